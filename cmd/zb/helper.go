@@ -4,14 +4,17 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math/big"
 	"math/rand"
 	"net/http"
+	urlparser "net/url"
 	"os"
 	"path"
+	"strconv"
 	"sync"
 	"time"
 
@@ -22,6 +25,7 @@ import (
 	"gopkg.in/resty.v1"
 
 	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
 	"zotregistry.io/zot/pkg/common"
 	testc "zotregistry.io/zot/pkg/test/common"
 )
@@ -74,33 +78,54 @@ func deleteTestRepo(repos []string, url string, client *resty.Client) error {
 		}
 
 		for _, tag := range tags.Tags {
-			var manifest ispec.Manifest
-
-			// first get tag manifest to get containing blobs
-			err := makeHTTPGetRequest(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, tag), &manifest, client)
-			if err != nil {
+			if err := deleteTestManifest(repo, tag, url, client); err != nil {
 				return err
 			}
+		}
+	}
 
-			// delete manifest so that we don't trigger BlobInUse error
-			err = makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, tag), client)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			// delete blobs
-			for _, blob := range manifest.Layers {
-				err := makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/blobs/%s", url, repo, blob.Digest.String()), client)
-				if err != nil {
-					return err
-				}
-			}
+// deleteTestManifest deletes the manifest and its blobs, the manifests of an index are deleted with it.
+func deleteTestManifest(repo, reference, url string, client *resty.Client) error {
+	var manifest struct {
+		Config    ispec.Descriptor   `json:"config"`
+		Layers    []ispec.Descriptor `json:"layers"`
+		Manifests []ispec.Descriptor `json:"manifests"`
+	}
 
-			// delete config blob
-			err = makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/blobs/%s", url, repo, manifest.Config.Digest.String()), client)
-			if err != nil {
-				return err
-			}
+	// first get the manifest to get containing blobs
+	err := makeHTTPGetRequest(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, reference), &manifest, client)
+	if err != nil {
+		return err
+	}
+
+	// delete manifest so that we don't trigger BlobInUse error
+	err = makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, reference), client)
+	if err != nil {
+		return err
+	}
+
+	for _, indexManifest := range manifest.Manifests {
+		if err := deleteTestManifest(repo, indexManifest.Digest.String(), url, client); err != nil {
+			return err
+		}
+	}
+
+	// delete blobs
+	for _, blob := range manifest.Layers {
+		err := makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/blobs/%s", url, repo, blob.Digest.String()), client)
+		if err != nil {
+			return err
+		}
+	}
+
+	// delete config blob
+	if manifest.Config.Digest != "" {
+		err = makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/blobs/%s", url, repo, manifest.Config.Digest.String()), client)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -947,6 +972,342 @@ func pushChunkAndCollect(workdir, url, trepo string, count int,
 	return repos
 }
 
+// platforms of the multi-arch images.
+var multiArchPlatforms = []ispec.Platform{ //nolint:gochecknoglobals // used only in this test
+	{OS: "linux", Architecture: "amd64"},
+	{OS: "linux", Architecture: "arm64", Variant: "v8"},
+	{OS: "linux", Architecture: "arm", Variant: "v7"},
+	{OS: "linux", Architecture: "s390x"},
+}
+
+const referrerArtifactType = "application/vnd.zot.zb.referrer.v1"
+
+// collectStats times the request and sends its stats record, the errors of the http client are connection failures.
+func collectStats(statsCh chan statsRecord, request func() (int, error)) {
+	start := time.Now()
+
+	statusCode, err := request()
+
+	record := statsRecord{
+		latency:    time.Since(start),
+		statusCode: statusCode,
+	}
+
+	if err != nil {
+		var urlErr *urlparser.Error
+
+		if errors.As(err, &urlErr) {
+			record.isConnFail = true
+		} else {
+			record.isErr = true
+		}
+	}
+
+	statsCh <- record
+}
+
+func checkStatusCode(resp *resty.Response, expected int) (int, error) {
+	if resp.StatusCode() != expected {
+		return resp.StatusCode(), fmt.Errorf("%w: Expected: %d, Got: %d, Body: '%s'", zerr.ErrBadHTTPStatusCode,
+			expected, resp.StatusCode(), string(resp.Body()))
+	}
+
+	return resp.StatusCode(), nil
+}
+
+func getTestRepoName(trepo string) (string, error) {
+	ruid, err := uuid.NewUUID()
+	if err != nil {
+		return "", err
+	}
+
+	if trepo != "" {
+		return trepo + "/" + ruid.String(), nil
+	}
+
+	return ruid.String(), nil
+}
+
+func getRandomLayers(count, size int) ([][]byte, error) {
+	layers := make([][]byte, count)
+
+	for idx := range layers {
+		layers[idx] = make([]byte, size)
+
+		if _, err := crand.Read(layers[idx]); err != nil {
+			return nil, err
+		}
+	}
+
+	return layers, nil
+}
+
+// pushBlob uploads the blob in a single request.
+func pushBlob(url, repo string, blob []byte, client *resty.Client) (godigest.Digest, int, error) {
+	digest := godigest.FromBytes(blob)
+
+	resp, err := client.R().Post(fmt.Sprintf("%s/v2/%s/blobs/uploads/", url, repo))
+	if err != nil {
+		return "", 0, err
+	}
+
+	if statusCode, err := checkStatusCode(resp, http.StatusAccepted); err != nil {
+		return "", statusCode, err
+	}
+
+	resp, err = client.R().
+		SetContentLength(true).
+		SetQueryParam("digest", digest.String()).
+		SetHeader("Content-Type", "application/octet-stream").
+		SetBody(blob).
+		Put(testc.Location(url, resp))
+	if err != nil {
+		return "", 0, err
+	}
+
+	statusCode, err := checkStatusCode(resp, http.StatusCreated)
+
+	return digest, statusCode, err
+}
+
+// pushManifest uploads the manifest, it is pushed by digest if the reference is empty.
+func pushManifest(url, repo, reference, mediaType string, manifest interface{}, client *resty.Client,
+) (ispec.Descriptor, int, error) {
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return ispec.Descriptor{}, 0, err
+	}
+
+	desc := ispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    godigest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+
+	if reference == "" {
+		reference = desc.Digest.String()
+	}
+
+	resp, err := client.R().
+		SetContentLength(true).
+		SetHeader("Content-Type", mediaType).
+		SetBody(content).
+		Put(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, reference))
+	if err != nil {
+		return ispec.Descriptor{}, 0, err
+	}
+
+	statusCode, err := checkStatusCode(resp, http.StatusCreated)
+
+	return desc, statusCode, err
+}
+
+// getManifestDescriptor returns the descriptor of the manifest pushed under the reference.
+func getManifestDescriptor(url, repo, reference string, client *resty.Client) (ispec.Descriptor, error) {
+	resp, err := client.R().
+		SetHeader("Accept", ispec.MediaTypeImageManifest).
+		Get(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, reference))
+	if err != nil {
+		return ispec.Descriptor{}, err
+	}
+
+	if _, err := checkStatusCode(resp, http.StatusOK); err != nil {
+		return ispec.Descriptor{}, err
+	}
+
+	return ispec.Descriptor{
+		MediaType: ispec.MediaTypeImageManifest,
+		Digest:    godigest.FromBytes(resp.Body()),
+		Size:      int64(len(resp.Body())),
+	}, nil
+}
+
+func getBlob(url, repo string, digest godigest.Digest, client *resty.Client) (int, error) {
+	resp, err := client.R().Get(fmt.Sprintf("%s/v2/%s/blobs/%s", url, repo, digest))
+	if err != nil {
+		return 0, err
+	}
+
+	return checkStatusCode(resp, http.StatusOK)
+}
+
+// pushMultiArchImage pushes an image manifest for each layer, with a platform each, and tags their index.
+func pushMultiArchImage(url, repo, tag string, layers [][]byte, client *resty.Client) (int, error) {
+	index := ispec.Index{
+		Versioned: imeta.Versioned{
+			SchemaVersion: defaultSchemaVersion,
+		},
+		MediaType: ispec.MediaTypeImageIndex,
+	}
+
+	for idx, layer := range layers {
+		platform := multiArchPlatforms[idx%len(multiArchPlatforms)]
+
+		layerDigest, statusCode, err := pushBlob(url, repo, layer, client)
+		if err != nil {
+			return statusCode, err
+		}
+
+		cblob, err := json.Marshal(ispec.Image{
+			Platform: platform,
+			RootFS: ispec.RootFS{
+				Type:    "layers",
+				DiffIDs: []godigest.Digest{layerDigest},
+			},
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		cdigest, statusCode, err := pushBlob(url, repo, cblob, client)
+		if err != nil {
+			return statusCode, err
+		}
+
+		manifest := ispec.Manifest{
+			Versioned: imeta.Versioned{
+				SchemaVersion: defaultSchemaVersion,
+			},
+			MediaType: ispec.MediaTypeImageManifest,
+			Config: ispec.Descriptor{
+				MediaType: ispec.MediaTypeImageConfig,
+				Digest:    cdigest,
+				Size:      int64(len(cblob)),
+			},
+			Layers: []ispec.Descriptor{
+				{
+					MediaType: ispec.MediaTypeImageLayer,
+					Digest:    layerDigest,
+					Size:      int64(len(layer)),
+				},
+			},
+		}
+
+		desc, statusCode, err := pushManifest(url, repo, "", ispec.MediaTypeImageManifest, manifest, client)
+		if err != nil {
+			return statusCode, err
+		}
+
+		desc.Platform = &platform
+		index.Manifests = append(index.Manifests, desc)
+	}
+
+	_, statusCode, err := pushManifest(url, repo, tag, ispec.MediaTypeImageIndex, index, client)
+
+	return statusCode, err
+}
+
+// pullMultiArchImage pulls the index and the image of one of its platforms, picked at random.
+func pullMultiArchImage(url, repo, tag string, client *resty.Client) (int, error) {
+	resp, err := client.R().
+		SetHeader("Accept", ispec.MediaTypeImageIndex).
+		Get(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, tag))
+	if err != nil {
+		return 0, err
+	}
+
+	if statusCode, err := checkStatusCode(resp, http.StatusOK); err != nil {
+		return statusCode, err
+	}
+
+	var index ispec.Index
+
+	if err := json.Unmarshal(resp.Body(), &index); err != nil {
+		return resp.StatusCode(), err
+	}
+
+	if len(index.Manifests) == 0 {
+		return resp.StatusCode(), zerr.ErrBadManifest
+	}
+
+	nBig, err := crand.Int(crand.Reader, big.NewInt(int64(len(index.Manifests))))
+	if err != nil {
+		return resp.StatusCode(), err
+	}
+
+	resp, err = client.R().
+		SetHeader("Accept", ispec.MediaTypeImageManifest).
+		Get(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, index.Manifests[nBig.Int64()].Digest))
+	if err != nil {
+		return 0, err
+	}
+
+	if statusCode, err := checkStatusCode(resp, http.StatusOK); err != nil {
+		return statusCode, err
+	}
+
+	var manifest ispec.Manifest
+
+	if err := json.Unmarshal(resp.Body(), &manifest); err != nil {
+		return resp.StatusCode(), err
+	}
+
+	statusCode, err := getBlob(url, repo, manifest.Config.Digest, client)
+	if err != nil {
+		return statusCode, err
+	}
+
+	for _, layer := range manifest.Layers {
+		statusCode, err = getBlob(url, repo, layer.Digest, client)
+		if err != nil {
+			return statusCode, err
+		}
+	}
+
+	return statusCode, nil
+}
+
+// pushReferrers pushes artifacts referring to the subject, they share the empty blob as config and layer.
+func pushReferrers(url, repo string, subject ispec.Descriptor, count int, client *resty.Client,
+) ([]godigest.Digest, error) {
+	if _, _, err := pushBlob(url, repo, ispec.DescriptorEmptyJSON.Data, client); err != nil {
+		return nil, err
+	}
+
+	referrers := make([]godigest.Digest, 0, count)
+
+	for idx := 0; idx < count; idx++ {
+		manifest := ispec.Manifest{
+			Versioned: imeta.Versioned{
+				SchemaVersion: defaultSchemaVersion,
+			},
+			MediaType:    ispec.MediaTypeImageManifest,
+			ArtifactType: referrerArtifactType,
+			Config:       ispec.DescriptorEmptyJSON,
+			Layers:       []ispec.Descriptor{ispec.DescriptorEmptyJSON},
+			Subject:      &subject,
+			Annotations:  map[string]string{"zb.referrer.index": strconv.Itoa(idx)},
+		}
+
+		desc, _, err := pushManifest(url, repo, "", ispec.MediaTypeImageManifest, manifest, client)
+		if err != nil {
+			return referrers, err
+		}
+
+		referrers = append(referrers, desc.Digest)
+	}
+
+	return referrers, nil
+}
+
+// deleteReferrers deletes the artifacts pushed by pushReferrers and their empty blob.
+func deleteReferrers(url, repo string, referrers []godigest.Digest, client *resty.Client) error {
+	for _, referrer := range referrers {
+		err := makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, referrer), client)
+		if err != nil {
+			return err
+		}
+	}
+
+	return makeHTTPDeleteRequest(fmt.Sprintf("%s/v2/%s/blobs/%s", url, repo, ispec.DescriptorEmptyJSON.Digest), client)
+}
+
+// triggerGC starts the garbage collection if the registry has the mgmt extension, the errors are ignored
+// since the registry also collects garbage periodically.
+func triggerGC(url string, client *resty.Client) {
+	_, _ = client.R().Post(url + constants.FullMgmtMaintenance + "/gc/run")
+}
+
 func getRandomSize(probabilityRange []float64) (int, int) {
 	var size int
 
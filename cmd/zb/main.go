@@ -14,7 +14,7 @@ import (
 func NewPerfRootCmd() *cobra.Command {
 	showVersion := false
 
-	var auth, workdir, repo, outFmt, srcIPs, srcCIDR string
+	var auth, workdir, repo, outFmt, outFile, workload, srcIPs, srcCIDR string
 
 	var concurrency, requests int
 
@@ -48,7 +48,7 @@ func NewPerfRootCmd() *cobra.Command {
 
 			requests = concurrency * (requests / concurrency)
 
-			Perf(workdir, url, auth, repo, concurrency, requests, outFmt, srcIPs, srcCIDR, skipCleanup)
+			Perf(workdir, url, auth, repo, concurrency, requests, outFmt, outFile, workload, srcIPs, srcCIDR, skipCleanup)
 		},
 	}
 
@@ -68,6 +68,10 @@ func NewPerfRootCmd() *cobra.Command {
 		"Number of requests to perform")
 	rootCmd.Flags().StringVarP(&outFmt, "output-format", "o", "",
 		"Output format of test results: stdout (default), json, ci-cd")
+	rootCmd.Flags().StringVar(&outFile, "output-file", "",
+		"File to write the json and ci-cd test results to (default \"<output-format>.json\")")
+	rootCmd.Flags().StringVarP(&workload, "workload", "w", "default",
+		"Tests to run: default, realistic (mixed pull/push ratios, multi-arch images, referrers, gc under load), all")
 	rootCmd.Flags().BoolVar(&skipCleanup, "skip-cleanup", false,
 		"Clean up pushed repos from remote registry after running benchmark (default true)")
 
@@ -2,6 +2,7 @@ package main //nolint:testpackage // separate binary
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -21,3 +22,35 @@ func TestIntegration(t *testing.T) {
 		So(cl.Execute(), ShouldBeNil)
 	})
 }
+
+func TestLatencyStats(t *testing.T) {
+	Convey("Percentiles are computed by nearest rank", t, func() {
+		So(percentile(nil, 50), ShouldEqual, 0)
+		So(mean(nil), ShouldEqual, 0)
+
+		latencies := make([]time.Duration, 0, 200)
+		for idx := 1; idx <= 200; idx++ {
+			latencies = append(latencies, time.Duration(idx)*time.Millisecond)
+		}
+
+		So(percentile(latencies, 50), ShouldEqual, 100*time.Millisecond)
+		So(percentile(latencies, 99), ShouldEqual, 198*time.Millisecond)
+		So(percentile(latencies, 99.9), ShouldEqual, 200*time.Millisecond)
+		So(percentile(latencies, 0), ShouldEqual, time.Millisecond)
+		So(percentile(latencies[:1], 99), ShouldEqual, time.Millisecond)
+		So(mean(latencies), ShouldEqual, 100500*time.Microsecond)
+	})
+
+	Convey("Workloads select the test suites", t, func() {
+		tests, err := getTestSuite("default")
+		So(err, ShouldBeNil)
+		So(tests, ShouldHaveLength, len(testSuite))
+
+		tests, err = getTestSuite("all")
+		So(err, ShouldBeNil)
+		So(tests, ShouldHaveLength, len(testSuite)+len(workloadSuite))
+
+		_, err = getTestSuite("unknown")
+		So(err, ShouldNotBeNil)
+	})
+}
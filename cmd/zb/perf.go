@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"log"
+	"math"
 	"math/big"
 	"net"
 	"net/http"
@@ -21,6 +22,8 @@ import (
 	godigest "github.com/opencontainers/go-digest"
 	"gopkg.in/resty.v1"
 
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/api/constants"
 )
 
@@ -36,6 +39,7 @@ const (
 	mediumBlob           = 10 * MiB
 	largeBlob            = 100 * MiB
 	cicdFmt              = "ci-cd"
+	jsonFmt              = "json"
 	secureProtocol       = "https"
 	httpKeepAlive        = 30 * time.Second
 	maxSourceIPs         = 1000
@@ -142,6 +146,41 @@ func newStatsSummary(name string) statsSummary {
 	return summary
 }
 
+// the percentiles of the latencies which are reported.
+var latencyPercentiles = []float64{50, 75, 90, 95, 99, 99.9} //nolint:gochecknoglobals // used only in this test
+
+// percentile returns the latency under which the percentage of the sorted latencies are, by nearest rank.
+func percentile(latencies []time.Duration, pct float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	idx := int(math.Ceil(pct/100*float64(len(latencies)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+
+	if idx >= len(latencies) {
+		idx = len(latencies) - 1
+	}
+
+	return latencies[idx]
+}
+
+func mean(latencies []time.Duration) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+
+	for _, latency := range latencies {
+		total += latency
+	}
+
+	return total / time.Duration(len(latencies))
+}
+
 type statsRecord struct {
 	latency    time.Duration
 	statusCode int
@@ -196,6 +235,27 @@ type cicdTestSummary struct {
 	Range string      `json:"range,omitempty"`
 }
 
+// jsonReport is the detailed report of a run, written with the json output format to track regressions.
+type jsonReport struct {
+	URL         string            `json:"url"`
+	Workload    string            `json:"workload"`
+	Concurrency int               `json:"concurrency"`
+	Requests    int               `json:"requests"`
+	Commit      string            `json:"commit,omitempty"`
+	StartedAt   time.Time         `json:"startedAt"`
+	Tests       []jsonTestSummary `json:"tests"`
+}
+
+type jsonTestSummary struct {
+	Name        string             `json:"name"`
+	Requests    int                `json:"requests"`
+	Failed      int                `json:"failed"`
+	DurationMs  float64            `json:"durationMs"`
+	RPS         float32            `json:"rps"`
+	StatusCodes map[string]int     `json:"statusCodes"`
+	LatencyMs   map[string]float64 `json:"latencyMs"`
+}
+
 type manifestStruct struct {
 	manifestHash       map[string]string
 	manifestBySizeHash map[int](map[string]string)
@@ -204,6 +264,13 @@ type manifestStruct struct {
 //nolint:gochecknoglobals // used only in this test
 var cicdSummary = []cicdTestSummary{}
 
+//nolint:gochecknoglobals // used only in this test
+var jsonSummary = []jsonTestSummary{}
+
+func toMilliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
 func printStats(requests int, summary *statsSummary, outFmt string) {
 	log.Printf("============\n")
 	log.Printf("Test name:\t%s", summary.name)
@@ -233,6 +300,10 @@ func printStats(requests int, summary *statsSummary, outFmt string) {
 		push := loadOrStore(&statusRequests, "Push", 0)
 		log.Printf("Push:\t%v", push)
 
+		if deletes, ok := statusRequests.Load("Delete"); ok {
+			log.Printf("Delete:\t%v", deletes)
+		}
+
 		log.Printf("\n")
 	}
 
@@ -243,10 +314,12 @@ func printStats(requests int, summary *statsSummary, outFmt string) {
 	log.Printf("\n")
 	log.Printf("min: %v", summary.min)
 	log.Printf("max: %v", summary.max)
-	log.Printf("%s:\t%v", "p50", summary.latencies[requests/2])
-	log.Printf("%s:\t%v", "p75", summary.latencies[requests*3/4])
-	log.Printf("%s:\t%v", "p90", summary.latencies[requests*9/10])
-	log.Printf("%s:\t%v", "p99", summary.latencies[requests*99/100])
+	log.Printf("mean: %v", mean(summary.latencies))
+
+	for _, pct := range latencyPercentiles {
+		log.Printf("p%v:\t%v", pct, percentile(summary.latencies, pct))
+	}
+
 	log.Printf("\n")
 
 	// ci/cd
@@ -260,6 +333,30 @@ func printStats(requests int, summary *statsSummary, outFmt string) {
 			},
 		)
 	}
+
+	if outFmt == jsonFmt {
+		latencyMs := map[string]float64{
+			"min":  toMilliseconds(summary.min),
+			"max":  toMilliseconds(summary.max),
+			"mean": toMilliseconds(mean(summary.latencies)),
+		}
+
+		for _, pct := range latencyPercentiles {
+			latencyMs[fmt.Sprintf("p%v", pct)] = toMilliseconds(percentile(summary.latencies, pct))
+		}
+
+		jsonSummary = append(jsonSummary,
+			jsonTestSummary{
+				Name:        summary.name,
+				Requests:    requests,
+				Failed:      summary.errors,
+				DurationMs:  toMilliseconds(summary.total),
+				RPS:         summary.rps,
+				StatusCodes: summary.statusHist,
+				LatencyMs:   latencyMs,
+			},
+		)
+	}
 }
 
 // test suites/funcs.
@@ -528,7 +625,206 @@ func MixedPullAndPush(
 		} else if idx == writeTestIdx {
 			repos = pushMonolithAndCollect(workdir, url, trepo, count, repos, config, client, statsCh)
 			current := loadOrStore(&statusRequests, "Push", 0)
+			statusRequests.Store("Push", current+1)
+		}
+	}
+
+	// clean up
+	if !skipCleanup {
+		err = deleteTestRepo(repos, url, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func PushMultiArch(
+	workdir, url, trepo string,
+	requests int,
+	config testConfig,
+	statsCh chan statsRecord,
+	client *resty.Client,
+	skipCleanup bool,
+) error {
+	repo, err := getTestRepoName(trepo)
+	if err != nil {
+		return err
+	}
+
+	for count := 0; count < requests; count++ {
+		// the layers are generated before timing the push
+		layers, err := getRandomLayers(len(multiArchPlatforms), config.size)
+		if err != nil {
+			return err
+		}
+
+		collectStats(statsCh, func() (int, error) {
+			return pushMultiArchImage(url, repo, fmt.Sprintf("tag%d", count), layers, client)
+		})
+	}
+
+	// clean up
+	if !skipCleanup {
+		err := deleteTestRepo([]string{repo}, url, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func PullMultiArch(
+	workdir, url, trepo string,
+	requests int,
+	config testConfig,
+	statsCh chan statsRecord,
+	client *resty.Client,
+	skipCleanup bool,
+) error {
+	repo, err := getTestRepoName(trepo)
+	if err != nil {
+		return err
+	}
+
+	layers, err := getRandomLayers(len(multiArchPlatforms), config.size)
+	if err != nil {
+		return err
+	}
+
+	const tag = "multiarch"
+
+	if _, err := pushMultiArchImage(url, repo, tag, layers, client); err != nil {
+		return err
+	}
+
+	for count := 0; count < requests; count++ {
+		collectStats(statsCh, func() (int, error) {
+			return pullMultiArchImage(url, repo, tag, client)
+		})
+	}
+
+	// clean up
+	if !skipCleanup {
+		err := deleteTestRepo([]string{repo}, url, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func GetReferrers(
+	workdir, url, trepo string,
+	requests int,
+	config testConfig,
+	statsCh chan statsRecord,
+	client *resty.Client,
+	skipCleanup bool,
+) error {
+	manifestHash, repos, err := pushMonolithImage(workdir, url, trepo, nil, config, client)
+	if err != nil {
+		return err
+	}
+
+	repo := repos[0]
+
+	subject, err := getManifestDescriptor(url, repo, manifestHash[repo], client)
+	if err != nil {
+		return err
+	}
+
+	referrers, err := pushReferrers(url, repo, subject, config.referrers, client)
+	if err != nil {
+		return err
+	}
+
+	for count := 0; count < requests; count++ {
+		collectStats(statsCh, func() (int, error) {
+			resp, err := client.R().Get(fmt.Sprintf("%s/v2/%s/referrers/%s", url, repo, subject.Digest))
+			if err != nil {
+				return 0, err
+			}
+
+			return checkStatusCode(resp, http.StatusOK)
+		})
+	}
+
+	// clean up
+	if !skipCleanup {
+		if err := deleteReferrers(url, repo, referrers, client); err != nil {
+			return err
+		}
+
+		if err := deleteTestRepo(repos, url, client); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PullWithGC pulls an image while other images are pushed and untagged, their blobs are left
+// for the garbage collection, which is started after each untag when the registry allows it.
+func PullWithGC(
+	workdir, url, trepo string,
+	requests int,
+	config testConfig,
+	statsCh chan statsRecord,
+	client *resty.Client,
+	skipCleanup bool,
+) error {
+	var repos []string
+
+	statusRequests = sync.Map{}
+
+	manifestHash, repos, err := pushMonolithImage(workdir, url, trepo, repos, config, client)
+	if err != nil {
+		return err
+	}
+
+	manifestItem := manifestStruct{
+		manifestHash: manifestHash,
+	}
+
+	for count := 0; count < requests; count++ {
+		idx := flipFunc(config.probabilityRange)
+
+		readTestIdx := 0
+		writeTestIdx := 1
+
+		if idx == readTestIdx {
+			repos = pullAndCollect(url, repos, manifestItem, config, client, statsCh)
+			current := loadOrStore(&statusRequests, "Pull", 0)
 			statusRequests.Store("Pull", current+1)
+		} else if idx == writeTestIdx {
+			collectStats(statsCh, func() (int, error) {
+				garbageHash, _, err := pushMonolithImage(workdir, url, trepo, nil, config, client)
+				if err != nil {
+					return 0, err
+				}
+
+				for repo, tag := range garbageHash {
+					resp, err := client.R().Delete(fmt.Sprintf("%s/v2/%s/manifests/%s", url, repo, tag))
+					if err != nil {
+						return 0, err
+					}
+
+					if statusCode, err := checkStatusCode(resp, http.StatusAccepted); err != nil {
+						return statusCode, err
+					}
+				}
+
+				return http.StatusAccepted, nil
+			})
+
+			triggerGC(url, client)
+
+			current := loadOrStore(&statusRequests, "Delete", 0)
+			statusRequests.Store("Delete", current+1)
 		}
 	}
 
@@ -550,6 +846,7 @@ type testConfig struct {
 	tfunc testFunc
 	// test-specific params
 	size                 int
+	referrers            int
 	probabilityRange     []float64
 	mixedSize, mixedType bool
 }
@@ -646,12 +943,92 @@ var testSuite = []testConfig{ //nolint:gochecknoglobals // used only in this tes
 	},
 }
 
+// workloadSuite mimics the production traffic: mixed pulls and pushes, multi-arch images, repositories
+// with many referrers and a garbage collection running under load.
+var workloadSuite = []testConfig{ //nolint:gochecknoglobals // used only in this test
+	{
+		name:             "Pull 90% and Push 10% Mixed 1MB",
+		tfunc:            MixedPullAndPush,
+		size:             smallBlob,
+		mixedType:        true,
+		probabilityRange: normalizeProbabilityRange([]float64{0.9, 0.1}),
+	},
+	{
+		name:             "Pull 50% and Push 50% Mixed 1MB",
+		tfunc:            MixedPullAndPush,
+		size:             smallBlob,
+		mixedType:        true,
+		probabilityRange: normalizeProbabilityRange([]float64{0.5, 0.5}),
+	},
+	{
+		name:             "Pull 10% and Push 90% Mixed 1MB",
+		tfunc:            MixedPullAndPush,
+		size:             smallBlob,
+		mixedType:        true,
+		probabilityRange: normalizeProbabilityRange([]float64{0.1, 0.9}),
+	},
+	{
+		name:  "Push Multi-Arch 4 Platforms 1MB",
+		tfunc: PushMultiArch,
+		size:  smallBlob,
+	},
+	{
+		name:  "Pull Multi-Arch 4 Platforms 1MB",
+		tfunc: PullMultiArch,
+		size:  smallBlob,
+	},
+	{
+		name:      "Get Referrers 10 Artifacts",
+		tfunc:     GetReferrers,
+		size:      smallBlob,
+		referrers: 10,
+	},
+	{
+		name:      "Get Referrers 100 Artifacts",
+		tfunc:     GetReferrers,
+		size:      smallBlob,
+		referrers: 100,
+	},
+	{
+		name:             "Pull 75% and Push-Delete 25% 1MB with GC",
+		tfunc:            PullWithGC,
+		size:             smallBlob,
+		mixedType:        true,
+		probabilityRange: normalizeProbabilityRange([]float64{0.75, 0.25}),
+	},
+}
+
+// getTestSuite returns the tests of the workload: "default", "realistic" or "all".
+func getTestSuite(workload string) ([]testConfig, error) {
+	switch workload {
+	case "", "default":
+		return testSuite, nil
+	case "realistic":
+		return workloadSuite, nil
+	case "all":
+		return append(append([]testConfig{}, testSuite...), workloadSuite...), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown workload %s", zerr.ErrInvalidArgs, workload)
+	}
+}
+
 func Perf(
 	workdir, url, auth, repo string,
 	concurrency int, requests int,
-	outFmt string, srcIPs string, srcCIDR string, skipCleanup bool,
+	outFmt string, outFile string, workload string, srcIPs string, srcCIDR string, skipCleanup bool,
 ) {
 	json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+	tests, err := getTestSuite(workload)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if outFile == "" {
+		outFile = fmt.Sprintf("%s.json", outFmt)
+	}
+
+	startedAt := time.Now()
 	// logging
 	log.SetFlags(0)
 	log.SetOutput(tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.TabIndent))
@@ -661,6 +1038,7 @@ func Perf(
 	log.Printf("\n")
 	log.Printf("Concurrency Level:\t%v", concurrency)
 	log.Printf("Total requests:\t%v", requests)
+	log.Printf("Workload:\t%v", workload)
 
 	if workdir == "" {
 		cwd, err := os.Getwd()
@@ -683,7 +1061,6 @@ func Perf(
 
 	log.Printf("Starting tests ...\n")
 
-	var err error
 	zbError := false
 
 	// get host ips from command line to make requests from
@@ -697,7 +1074,7 @@ func Perf(
 		}
 	}
 
-	for _, tconfig := range testSuite {
+	for _, tconfig := range tests {
 		statsCh := make(chan statsRecord, requests)
 
 		var wg sync.WaitGroup
@@ -757,7 +1134,26 @@ func Perf(
 			log.Fatal(err) // file closed on exit
 		}
 
-		if err := os.WriteFile(fmt.Sprintf("%s.json", outFmt), jsonOut, defaultFilePerms); err != nil {
+		if err := os.WriteFile(outFile, jsonOut, defaultFilePerms); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if outFmt == jsonFmt {
+		jsonOut, err := json.MarshalIndent(jsonReport{
+			URL:         url,
+			Workload:    workload,
+			Concurrency: concurrency,
+			Requests:    requests,
+			Commit:      config.Commit,
+			StartedAt:   startedAt,
+			Tests:       jsonSummary,
+		}, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := os.WriteFile(outFile, jsonOut, defaultFilePerms); err != nil {
 			log.Fatal(err)
 		}
 	}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"errors"
 	"os"
 
+	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/cli"
 )
 
 func main() {
 	if err := cli.NewCliRootCmd().Execute(); err != nil {
+		if errors.Is(err, zerr.ErrCVESeverityThreshold) {
+			os.Exit(cli.CVESeverityThresholdExitCode)
+		}
+
 		os.Exit(1)
 	}
 }
@@ -89,16 +89,24 @@ var (
 	ErrEmptyValue                     = errors.New("cache: empty value")
 	ErrEmptyRepoList                  = errors.New("search: no repository found")
 	ErrCVESearchDisabled              = errors.New("search: CVE search is disabled")
+	ErrLicenseSearchDisabled          = errors.New("search: license search is disabled, it requires CVE scanning")
+	ErrQueryNotAllowed                = errors.New("search: query is not in the persisted queries allow list")
+	ErrLicenseScanPending             = errors.New("search: the image wasn't scanned for licenses yet, retry later")
+	ErrFederationPeerFailed           = errors.New("search: federation peer query failed")
+	ErrFederationPeerInvalid          = errors.New("search: federation peers need a URL and a unique name other than \"local\"")
 	ErrCVEDBNotFound                  = errors.New("cve: CVE DB is not present")
+	ErrWebhookFailed                  = errors.New("cve: webhook notification failed")
 	ErrInvalidRepositoryName          = errors.New("repository: not a valid repository name")
 	ErrSyncMissingCatalog             = errors.New("sync: couldn't fetch upstream registry's catalog")
 	ErrMethodNotSupported             = errors.New("storage: method not supported")
 	ErrInvalidMetric                  = errors.New("metrics: invalid metric func")
+	ErrOTLPExportFailed               = errors.New("metrics: OTLP collector failed to accept the metrics")
 	ErrInjected                       = errors.New("test: injected failure")
 	ErrSyncInvalidUpstreamURL         = errors.New("sync: upstream url not found in sync config")
 	ErrRegistryNoContent              = errors.New("sync: could not find a Content that matches localRepo")
 	ErrSyncReferrerNotFound           = errors.New("sync: couldn't find upstream referrer")
 	ErrImageLintAnnotations           = errors.New("routes: lint checks failed")
+	ErrImageContainsSecrets           = errors.New("secrets: image contains secrets")
 	ErrParsingAuthHeader              = errors.New("auth: failed parsing authorization header")
 	ErrBadType                        = errors.New("core: invalid type")
 	ErrParsingHTTPHeader              = errors.New("routes: invalid HTTP header")
@@ -107,6 +115,9 @@ var (
 	ErrManifestConflict               = errors.New("manifest: multiple manifests found")
 	ErrManifestMetaNotFound           = errors.New("metadb: image metadata not found for given manifest reference")
 	ErrManifestDataNotFound           = errors.New("metadb: image data not found for given manifest digest")
+	ErrLayerIndexDisabled             = errors.New("metadb: layer index is not enabled")
+	ErrDownloadStatsDisabled          = errors.New("metadb: download statistics are not enabled")
+	ErrUserActivityDisabled           = errors.New("metadb: user activity is not enabled")
 	ErrIndexDataNotFount              = errors.New("metadb: index data not found for given digest")
 	ErrRepoMetaNotFound               = errors.New("metadb: repo metadata not found for given repo name")
 	ErrTagMetaNotFound                = errors.New("metadb: tag metadata not found for given repo and tag names")
@@ -129,6 +140,8 @@ var (
 	ErrUserSessionNotFound            = errors.New("userDB: user session for given ID not found")
 	ErrBucketDoesNotExist             = errors.New("DB: bucket does not exist")
 	ErrOpenIDProviderDoesNotExist     = errors.New("openID: provider does not exist in given config")
+	ErrDeviceFlowNotSupported         = errors.New("openID: provider doesn't support the device authorization flow")
+	ErrDeviceFlowFailed               = errors.New("openID: device authorization failed")
 	ErrHashKeyNotCreated              = errors.New("cookiestore: generated random hash key is nil, not continuing")
 	ErrFailedTypeAssertion            = errors.New("type assertion failed")
 	ErrInvalidOldUserStarredRepos     = errors.New("metadb: invalid old entry for user starred repos")
@@ -138,6 +151,7 @@ var (
 	ErrCouldNotMarshalBookmarkedRepos = errors.New("metadb: could not repack entry for user bookmarked repos")
 	ErrUserDataNotFound               = errors.New("metadb: user data not found for given user identifier")
 	ErrUserDataNotAllowed             = errors.New("metadb: user data operations are not allowed")
+	ErrBookmarkListNotFound           = errors.New("metadb: bookmark list not found")
 	ErrCouldNotPersistData            = errors.New("metadb: could not persist to db")
 	ErrSignConfigDirNotSet            = errors.New("signatures: signature config dir not set")
 	ErrBadManifestDigest              = errors.New("signatures: bad manifest digest")
@@ -150,8 +164,38 @@ var (
 	ErrInvalidTruststoreName          = errors.New("signatures: invalid truststore name")
 	ErrInvalidCertificateContent      = errors.New("signatures: invalid certificate content")
 	ErrInvalidPublicKeyContent        = errors.New("signatures: invalid public key content")
+	ErrInvalidTrustPolicy             = errors.New("signatures: invalid notation trust policy")
+	ErrImageNotTrusted                = errors.New("signatures: image doesn't have a trusted signature")
+	ErrInvalidSignaturePolicy         = errors.New("signatures: invalid signature policy")
+	ErrCosignKeylessNotConfigured     = errors.New("signatures: cosign keyless verification is not configured")
 	ErrInvalidStateCookie             = errors.New("auth: state cookie not present or differs from original state")
 	ErrSyncNoURLsLeft                 = errors.New("sync: no valid registry urls left after filtering local ones")
+	ErrSyncRepoQuarantined            = errors.New("sync: repo is quarantined after repeated sync failures")
+	ErrSyncTokenRequest               = errors.New("sync: unable to get a token from the upstream registry token server")
+	ErrSyncWebhookFailed              = errors.New("sync: webhook notification failed")
+	ErrSyncSignatureNotVerified       = errors.New("sync: image has no signature verified by the configured keys")
+	ErrSyncInvalidSignatureKey        = errors.New("sync: invalid signature verification key or certificate")
+	ErrSyncBlobDownload               = errors.New("sync: unable to download blob from the upstream registry")
+	ErrSyncInvalidMapping             = errors.New("sync: mapping source and destination must have the same wildcards")
+	ErrSyncInvalidTrigger             = errors.New("sync: invalid sync trigger, a repo is required")
+	ErrSyncTriggerNoRegistry          = errors.New("sync: no sync registry matches the trigger")
+	ErrSyncInvalidExclusion           = errors.New("sync: invalid exclusion pattern")
+	ErrEventSinkUnknownType           = errors.New("events: unknown event sink type")
+	ErrEventSinkFailed                = errors.New("events: event sink failed to accept the event")
+	ErrPluginNotLaunchedByZot         = errors.New("plugins: the plugin must be launched by zot")
+	ErrPluginHandshake                = errors.New("plugins: invalid handshake from the plugin")
+	ErrPluginStartTimeout             = errors.New("plugins: the plugin didn't start in time")
+	ErrPluginExited                   = errors.New("plugins: the plugin exited")
+	ErrPluginNotImplemented           = errors.New("plugins: method not implemented by the plugin")
+	ErrPluginInvalidRegistration      = errors.New("plugins: invalid registration of the plugin")
+	ErrPluginNotConfigured            = errors.New("plugins: the storage driver of the plugin isn't configured")
+	ErrPluginWriterNotFound           = errors.New("plugins: writer not found")
+	ErrRepoDeletionTokenInvalid       = errors.New("mgmt: invalid or expired repo deletion token")
+	ErrBadRetentionPolicy             = errors.New("retention: invalid retention policy")
+	ErrMaintenanceJobNotFound         = errors.New("maintenance: unknown or disabled maintenance job")
+	ErrMaintenanceJobRunning          = errors.New("maintenance: the maintenance job is already running")
+	ErrMaintenanceJobPaused           = errors.New("maintenance: the maintenance job is paused")
+	ErrScrubNoValidBlobCopy           = errors.New("scrub: no valid copy of the blob found in the repair sources")
 	ErrInvalidCLIParameter            = errors.New("cli: invalid parameter")
 	ErrGQLEndpointNotFound            = errors.New("cli: the server doesn't have a gql endpoint")
 	ErrGQLQueryNotSupported           = errors.New("cli: query is not supported or has different arguments")
@@ -162,4 +206,29 @@ var (
 	ErrInvalidOutputFormat            = errors.New("cli: invalid output format")
 	ErrFlagValueUnsupported           = errors.New("supported values ")
 	ErrUnknownSubcommand              = errors.New("cli: unknown subcommand")
+	ErrSBOMNotFound                   = errors.New("cli: no SBOM was found for the image")
+	ErrSBOMPackagesNotSupported       = errors.New("cli: the packages of the SBOM can't be listed")
+	ErrCVESeverityThreshold           = errors.New("cli: CVEs at or above the severity threshold were found")
+	ErrDeviceCodeExpired              = errors.New("cli: the device code expired before the login was completed")
+	ErrCredentialsHelper              = errors.New("cli: the credentials helper failed")
+	ErrUnsupportedSigningKey          = errors.New("cli: the signing key isn't supported")
+	ErrPlatformNotFound               = errors.New("cli: the image has no manifest for the platform")
+	ErrInvalidImageArchive            = errors.New("cli: the archive isn't an OCI layout or a docker archive")
+	ErrMetaDBLockTimeout              = errors.New("metadb: the lock of the writes wasn't held in time")
+	ErrReadOnlyTransaction            = errors.New("metadb: the transaction is read only")
+	ErrBadMetaDBBackup                = errors.New("metadb: invalid backup")
+	ErrMetaDBBackupNotSupported       = errors.New("metadb: backups aren't supported by this database")
+	ErrBadLogLevel                    = errors.New("log: invalid log level")
+	ErrUnknownLogComponent            = errors.New("log: unknown log component")
+	ErrEmptyIndex                     = errors.New("index: no manifests listed")
+	ErrDuplicateIndexManifest         = errors.New("index: manifest listed more than once")
+	ErrUnsupportedIndexManifest       = errors.New("index: only image manifests can be listed")
+	ErrUnknownManifestPlatform        = errors.New("index: the platform can't be derived from the image config")
+	ErrClusterMemberExists            = errors.New("cluster: a member with the same name is already running")
+	ErrClusterNotEnabled              = errors.New("cluster: zot isn't running in cluster mode")
+	ErrClusterLeaseUnavailable        = errors.New("cluster: the lease can't be taken, the write is denied")
+	ErrClusterLeaseLost               = errors.New("cluster: the lease was lost, the write is aborted")
+	ErrQuotaExceeded                  = errors.New("quota: the storage quota is exceeded")
+	ErrImmutableTag                   = errors.New("manifest: the tag is immutable")
+	ErrBadChunkRecipe                 = errors.New("blob: invalid recipe of a chunked blob")
 )
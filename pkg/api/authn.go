@@ -119,7 +119,7 @@ func (amw *AuthnMiddleware) basicAuthn(ctlr *Controller, userAc *reqCtx.UserAcce
 			var groups []string
 
 			if ctlr.Config.HTTP.AccessControl != nil {
-				ac := NewAccessController(ctlr.Config)
+				ac := NewAccessController(ctlr.Config, ctlr.Log)
 				groups = ac.getUserGroups(identity)
 			}
 
@@ -153,7 +153,7 @@ func (amw *AuthnMiddleware) basicAuthn(ctlr *Controller, userAc *reqCtx.UserAcce
 			var groups []string
 
 			if ctlr.Config.HTTP.AccessControl != nil {
-				ac := NewAccessController(ctlr.Config)
+				ac := NewAccessController(ctlr.Config, ctlr.Log)
 				groups = ac.getUserGroups(identity)
 			}
 
@@ -455,7 +455,7 @@ func bearerAuthHandler(ctlr *Controller) mux.MiddlewareFunc {
 
 				return
 			}
-			acCtrlr := NewAccessController(ctlr.Config)
+			acCtrlr := NewAccessController(ctlr.Config, ctlr.Log)
 			vars := mux.Vars(request)
 			name := vars["name"]
 
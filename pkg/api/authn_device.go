@@ -0,0 +1,337 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zitadel/oidc/pkg/client/rp"
+	"github.com/zitadel/oidc/pkg/oidc"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	zcommon "zotregistry.io/zot/pkg/common"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+)
+
+const (
+	deviceCodeGrantType        = "urn:ietf:params:oauth:grant-type:device_code"
+	deviceAuthorizationPending = "authorization_pending"
+	deviceSlowDown             = "slow_down"
+	deviceAPIKeyGeneratedBy    = "device"
+	oidcDiscoveryPath          = "/.well-known/openid-configuration"
+)
+
+// DeviceAuthorizationResponse is returned when starting the device authorization flow (RFC 8628),
+// the user has to open the verification URI and enter the user code, while the client polls for the token.
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"deviceCode"`
+	UserCode                string `json:"userCode"`
+	VerificationURI         string `json:"verificationURI"`
+	VerificationURIComplete string `json:"verificationURIComplete,omitempty"`
+	ExpiresIn               int    `json:"expiresIn"`
+	Interval                int    `json:"interval"`
+}
+
+type DeviceTokenPayload struct {
+	DeviceCode string `json:"deviceCode"`
+}
+
+// DeviceTokenResponse is returned while the user hasn't completed the authorization yet.
+type DeviceTokenResponse struct {
+	Status string `json:"status"`
+}
+
+// DeviceAPIKeyResponse holds the API key generated for the user once the device is authorized.
+type DeviceAPIKeyResponse struct {
+	generatedAPIKey
+	Username string `json:"username"`
+}
+
+type providerDeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURL         string `json:"verification_url"` // used by google instead of verification_uri
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type providerDeviceToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+}
+
+// DeviceAuthorization godoc
+// @Summary Start the openID device authorization flow
+// @Description Start the device authorization flow with an openID provider, for the clients without a browser
+// @Produce json
+// @Param   provider  query  string  true  "openID provider"
+// @Success 200 {object} api.DeviceAuthorizationResponse
+// @Failure 400 {string} string "bad request"
+// @Failure 501 {string} string "not implemented"
+// @Failure 500 {string} string "internal server error"
+// @Router /auth/device [post].
+func (rh *RouteHandler) DeviceAuthorization(resp http.ResponseWriter, req *http.Request) {
+	provider := req.URL.Query().Get("provider")
+
+	relyingParty, ok := rh.getDeviceFlowRelyingParty(provider)
+	if !ok {
+		resp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	endpoint, err := getDeviceAuthorizationEndpoint(relyingParty)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Str("provider", provider).Msg("unable to discover the device authorization endpoint")
+
+		if errors.Is(err, zerr.ErrDeviceFlowNotSupported) {
+			resp.WriteHeader(http.StatusNotImplemented)
+
+			return
+		}
+
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	form := deviceFlowForm(relyingParty)
+	form.Set("scope", strings.Join(relyingParty.OAuthConfig().Scopes, " "))
+
+	providerResp, err := relyingParty.HttpClient().PostForm(endpoint, form)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Str("provider", provider).Msg("unable to start the device authorization")
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	defer providerResp.Body.Close()
+
+	if providerResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(providerResp.Body)
+
+		rh.c.Log.Error().Err(zerr.ErrDeviceFlowFailed).Str("provider", provider).Int("status", providerResp.StatusCode).
+			Str("body", string(body)).Msg("unable to start the device authorization")
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	var authorization providerDeviceAuthorization
+
+	if err := json.NewDecoder(providerResp.Body).Decode(&authorization); err != nil {
+		rh.c.Log.Error().Err(err).Str("provider", provider).Msg("unable to decode the device authorization")
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	verificationURI := authorization.VerificationURI
+	if verificationURI == "" {
+		verificationURI = authorization.VerificationURL
+	}
+
+	zcommon.WriteJSON(resp, http.StatusOK, DeviceAuthorizationResponse{
+		DeviceCode:              authorization.DeviceCode,
+		UserCode:                authorization.UserCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: authorization.VerificationURIComplete,
+		ExpiresIn:               authorization.ExpiresIn,
+		Interval:                authorization.Interval,
+	})
+}
+
+// DeviceToken godoc
+// @Summary Poll the openID device authorization flow
+// @Description Poll the device authorization flow, once the user authorized the device an API key is created
+// @Description for them and returned, it is the only time the key is available
+// @Accept  json
+// @Produce json
+// @Param   provider  query  string              true  "openID provider"
+// @Param   code      body   DeviceTokenPayload  true  "device code"
+// @Success 201 {object} api.DeviceAPIKeyResponse
+// @Success 202 {object} api.DeviceTokenResponse
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /auth/device/token [post].
+func (rh *RouteHandler) DeviceToken(resp http.ResponseWriter, req *http.Request) {
+	provider := req.URL.Query().Get("provider")
+
+	relyingParty, ok := rh.getDeviceFlowRelyingParty(provider)
+	if !ok {
+		resp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var payload DeviceTokenPayload
+
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil || payload.DeviceCode == "" {
+		resp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	form := deviceFlowForm(relyingParty)
+	form.Set("grant_type", deviceCodeGrantType)
+	form.Set("device_code", payload.DeviceCode)
+
+	providerResp, err := relyingParty.HttpClient().PostForm(relyingParty.OAuthConfig().Endpoint.TokenURL, form)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Str("provider", provider).Msg("unable to get the device token")
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	defer providerResp.Body.Close()
+
+	var token providerDeviceToken
+
+	if err := json.NewDecoder(providerResp.Body).Decode(&token); err != nil {
+		rh.c.Log.Error().Err(err).Str("provider", provider).Msg("unable to decode the device token")
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if providerResp.StatusCode != http.StatusOK {
+		// the user didn't authorize the device yet, the client has to retry
+		if token.Error == deviceAuthorizationPending || token.Error == deviceSlowDown {
+			zcommon.WriteJSON(resp, http.StatusAccepted, DeviceTokenResponse{Status: token.Error})
+
+			return
+		}
+
+		rh.c.Log.Error().Err(zerr.ErrDeviceFlowFailed).Str("provider", provider).Str("error", token.Error).
+			Msg("device authorization was rejected")
+		resp.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	claims, err := rp.VerifyIDToken(req.Context(), token.IDToken, relyingParty.IDTokenVerifier())
+	if err != nil {
+		rh.c.Log.Error().Err(err).Str("provider", provider).Msg("invalid id token")
+		resp.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	info, err := rp.Userinfo(token.AccessToken, token.TokenType, claims.GetSubject(), relyingParty)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Str("provider", provider).Msg("unable to get the user info")
+		resp.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	email, groups := getUserInfoIdentity(info)
+	if email == "" {
+		rh.c.Log.Error().Msg("couldn't set user record for empty email value")
+		resp.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	userAc := reqCtx.NewUserAccessControl()
+	userAc.SetUsername(email)
+	userAc.AddGroups(groups)
+	userAc.SaveOnRequest(req)
+
+	if err := rh.c.MetaDB.SetUserGroups(req.Context(), groups); err != nil {
+		rh.c.Log.Error().Err(err).Str("identity", email).Msg("couldn't update the user profile")
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	apiKey, err := rh.addUserAPIKey(req, fmt.Sprintf("%s device login", provider), nil, time.Time{},
+		deviceAPIKeyGeneratedBy)
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rh.c.Log.Info().Str("identity", email).Str("provider", provider).Msg("device authorized")
+
+	zcommon.WriteJSON(resp, http.StatusCreated, DeviceAPIKeyResponse{generatedAPIKey: apiKey, Username: email})
+}
+
+func (rh *RouteHandler) getDeviceFlowRelyingParty(provider string) (rp.RelyingParty, bool) {
+	if !config.IsOpenIDSupported(provider) {
+		rh.c.Log.Error().Str("provider", provider).Msg("device authorization is only supported by the openID providers")
+
+		return nil, false
+	}
+
+	relyingParty, ok := rh.c.RelyingParties[provider]
+	if !ok {
+		rh.c.Log.Error().Str("provider", provider).Msg("unrecognized openid provider")
+
+		return nil, false
+	}
+
+	return relyingParty, true
+}
+
+// getDeviceAuthorizationEndpoint discovers the device authorization endpoint of the provider,
+// which isn't part of the endpoints known by the relying party.
+func getDeviceAuthorizationEndpoint(relyingParty rp.RelyingParty) (string, error) {
+	discoveryURL := strings.TrimSuffix(relyingParty.Issuer(), "/") + oidcDiscoveryPath
+
+	discoveryResp, err := relyingParty.HttpClient().Get(discoveryURL) //nolint: noctx
+	if err != nil {
+		return "", err
+	}
+	defer discoveryResp.Body.Close()
+
+	var discovery struct {
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	}
+
+	if err := json.NewDecoder(discoveryResp.Body).Decode(&discovery); err != nil {
+		return "", err
+	}
+
+	if discovery.DeviceAuthorizationEndpoint == "" {
+		return "", zerr.ErrDeviceFlowNotSupported
+	}
+
+	return discovery.DeviceAuthorizationEndpoint, nil
+}
+
+func deviceFlowForm(relyingParty rp.RelyingParty) url.Values {
+	form := url.Values{}
+	form.Set("client_id", relyingParty.OAuthConfig().ClientID)
+
+	if clientSecret := relyingParty.OAuthConfig().ClientSecret; clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+
+	return form
+}
+
+// getUserInfoIdentity returns the email and the groups of an openID user.
+func getUserInfoIdentity(info oidc.UserInfo) (string, []string) {
+	var groups []string
+
+	val, _ := info.GetClaim("groups").([]interface{})
+
+	for _, group := range val {
+		groups = append(groups, fmt.Sprint(group))
+	}
+
+	return info.GetEmail(), groups
+}
@@ -828,6 +828,149 @@ func TestAPIKeys(t *testing.T) {
 	})
 }
 
+func TestDeviceAuthorization(t *testing.T) {
+	Convey("Make a new controller with the device authorization flow", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		mockOIDCServer, err := test.MockOIDCRunWithDeviceFlow()
+		if err != nil {
+			panic(err)
+		}
+
+		defer func() {
+			err := mockOIDCServer.Shutdown()
+			if err != nil {
+				panic(err)
+			}
+		}()
+
+		// this provider doesn't support the device flow
+		mockOIDCServerNoDevice, err := test.MockOIDCRun()
+		if err != nil {
+			panic(err)
+		}
+
+		defer func() {
+			err := mockOIDCServerNoDevice.Shutdown()
+			if err != nil {
+				panic(err)
+			}
+		}()
+
+		mockOIDCConfig := mockOIDCServer.Config()
+		mockOIDCConfigNoDevice := mockOIDCServerNoDevice.Config()
+
+		conf.HTTP.Auth = &config.AuthConfig{
+			OpenID: &config.OpenIDConfig{
+				Providers: map[string]config.OpenIDProviderConfig{
+					"oidc": {
+						ClientID:     mockOIDCConfig.ClientID,
+						ClientSecret: mockOIDCConfig.ClientSecret,
+						Issuer:       mockOIDCConfig.Issuer,
+						Scopes:       []string{"openid", "email", "groups"},
+					},
+					"gitlab": {
+						ClientID:     mockOIDCConfigNoDevice.ClientID,
+						ClientSecret: mockOIDCConfigNoDevice.ClientSecret,
+						Issuer:       mockOIDCConfigNoDevice.Issuer,
+						Scopes:       []string{"openid", "email"},
+					},
+				},
+			},
+			APIKey: true,
+		}
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartServer()
+		defer cm.StopServer()
+		test.WaitTillServerReady(baseURL)
+
+		resp, err := resty.R().Post(baseURL + constants.DeviceAuthPath + "?provider=oidc")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		var authorization api.DeviceAuthorizationResponse
+		err = json.Unmarshal(resp.Body(), &authorization)
+		So(err, ShouldBeNil)
+		So(authorization.DeviceCode, ShouldNotBeEmpty)
+		So(authorization.UserCode, ShouldNotBeEmpty)
+		So(authorization.VerificationURI, ShouldStartWith, mockOIDCConfig.Issuer)
+		So(authorization.Interval, ShouldEqual, 1)
+
+		tokenURL := baseURL + constants.DeviceTokenPath + "?provider=oidc"
+
+		// the user didn't authorize the device yet
+		resp, err = resty.R().SetBody(api.DeviceTokenPayload{DeviceCode: authorization.DeviceCode}).Post(tokenURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		var status api.DeviceTokenResponse
+		err = json.Unmarshal(resp.Body(), &status)
+		So(err, ShouldBeNil)
+		So(status.Status, ShouldEqual, "authorization_pending")
+
+		resp, err = resty.R().SetBody(api.DeviceTokenPayload{DeviceCode: authorization.DeviceCode}).Post(tokenURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		var deviceAPIKey struct {
+			apiKeyResponse
+			Username string `json:"username"`
+		}
+		err = json.Unmarshal(resp.Body(), &deviceAPIKey)
+		So(err, ShouldBeNil)
+		So(deviceAPIKey.Username, ShouldEqual, mockoidc.DefaultUser().Email)
+		So(deviceAPIKey.APIKey, ShouldStartWith, constants.APIKeysPrefix)
+		So(deviceAPIKey.GeneratedBy, ShouldEqual, "device")
+
+		resp, err = resty.R().SetBasicAuth(deviceAPIKey.Username, deviceAPIKey.APIKey).Get(baseURL + "/v2/_catalog")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().SetBasicAuth(deviceAPIKey.Username, deviceAPIKey.APIKey).
+			Delete(baseURL + constants.APIKeyPath + "?id=" + deviceAPIKey.UUID)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().SetBasicAuth(deviceAPIKey.Username, deviceAPIKey.APIKey).Get(baseURL + "/v2/_catalog")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		Convey("invalid device code", func() {
+			resp, err = resty.R().SetBody(api.DeviceTokenPayload{DeviceCode: "invalid"}).Post(tokenURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBody("{}").Post(tokenURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("unsupported providers", func() {
+			resp, err = resty.R().Post(baseURL + constants.DeviceAuthPath + "?provider=gitlab")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotImplemented)
+
+			resp, err = resty.R().Post(baseURL + constants.DeviceAuthPath + "?provider=github")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBody(api.DeviceTokenPayload{DeviceCode: authorization.DeviceCode}).
+				Post(baseURL + constants.DeviceTokenPath + "?provider=unknown")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
 func TestAPIKeysOpenDBError(t *testing.T) {
 	Convey("Test API keys - unable to create database", t, func() {
 		conf := config.New()
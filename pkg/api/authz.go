@@ -26,17 +26,17 @@ type AccessController struct {
 	Log    log.Logger
 }
 
-func NewAccessController(conf *config.Config) *AccessController {
+func NewAccessController(conf *config.Config, log log.Logger) *AccessController {
 	if conf.HTTP.AccessControl == nil {
 		return &AccessController{
 			Config: &config.AccessControlConfig{},
-			Log:    log.NewLogger(conf.Log.Level, conf.Log.Output),
+			Log:    log,
 		}
 	}
 
 	return &AccessController{
 		Config: conf.HTTP.AccessControl,
-		Log:    log.NewLogger(conf.Log.Level, conf.Log.Output),
+		Log:    log,
 	}
 }
 
@@ -262,7 +262,7 @@ func BaseAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
 				return
 			}
 
-			aCtlr := NewAccessController(ctlr.Config)
+			aCtlr := NewAccessController(ctlr.Config, ctlr.Log)
 
 			// get access control context made in authn.go
 			userAc, err := reqCtx.UserAcFromContext(request.Context())
@@ -301,7 +301,7 @@ func DistSpecAuthzHandler(ctlr *Controller) mux.MiddlewareFunc {
 			resource := vars["name"]
 			reference, ok := vars["reference"]
 
-			acCtrlr := NewAccessController(ctlr.Config)
+			acCtrlr := NewAccessController(ctlr.Config, ctlr.Log)
 
 			// get userAc built in authn and previous authz middlewares
 			userAc, err := reqCtx.UserAcFromContext(request.Context())
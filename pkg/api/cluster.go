@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"zotregistry.io/zot/pkg/api/constants"
+)
+
+// forwardToUploadOwner sends the requests of an upload session to the member of the cluster owning it, the
+// chunks of a session are only in the storage of its member until the upload is finished. The forwarded
+// requests are marked with the constants.ClusterForwardedHeader so they're handled by the owner itself.
+func (rh *RouteHandler) forwardToUploadOwner(next http.HandlerFunc) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		if rh.c.Cluster == nil || request.Header.Get(constants.ClusterForwardedHeader) != "" {
+			next(response, request)
+
+			return
+		}
+
+		vars := mux.Vars(request)
+
+		owner, err := rh.c.Cluster.UploadOwner(vars["name"], vars["session_id"])
+		if err != nil {
+			// the session may still be local, it's handled here if the owner isn't known
+			rh.c.Log.Error().Err(err).Str("session_id", vars["session_id"]).
+				Msg("cluster: unable to get the owner of the upload session")
+		}
+
+		if owner == nil {
+			next(response, request)
+
+			return
+		}
+
+		target, err := url.Parse(owner.Address)
+		if err != nil || owner.Address == "" {
+			rh.c.Log.Error().Err(err).Str("member", owner.Name).Str("address", owner.Address).
+				Msg("cluster: invalid address of the member owning the upload session")
+			response.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		rh.c.Log.Debug().Str("member", owner.Name).Str("session_id", vars["session_id"]).
+			Msg("cluster: forwarding the request to the owner of the upload session")
+
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		director := proxy.Director
+		proxy.Director = func(request *http.Request) {
+			director(request)
+			request.Header.Set(constants.ClusterForwardedHeader, rh.c.Cluster.Info().Name)
+		}
+
+		proxy.ServeHTTP(response, request)
+	}
+}
+
+// claimUpload makes this member the owner of the upload session, the other members forward its requests here.
+func (rh *RouteHandler) claimUpload(repo, sessionID string) {
+	if rh.c.Cluster == nil {
+		return
+	}
+
+	if err := rh.c.Cluster.ClaimUpload(repo, sessionID); err != nil {
+		rh.c.Log.Error().Err(err).Str("repository", repo).Str("session_id", sessionID).
+			Msg("cluster: unable to claim the upload session")
+	}
+}
+
+// releaseUpload frees the finished or cancelled upload session.
+func (rh *RouteHandler) releaseUpload(repo, sessionID string) {
+	if rh.c.Cluster == nil {
+		return
+	}
+
+	if err := rh.c.Cluster.ReleaseUpload(repo, sessionID); err != nil {
+		rh.c.Log.Error().Err(err).Str("repository", repo).Str("session_id", sessionID).
+			Msg("cluster: unable to release the upload session")
+	}
+}
@@ -18,10 +18,17 @@ const (
 	LoginPath                    = "/auth/login"
 	LogoutPath                   = "/auth/logout"
 	APIKeyPath                   = "/auth/apikey" //nolint: gosec
+	DeviceAuthPath               = "/auth/device"
+	DeviceTokenPath              = "/auth/device/token" //nolint: gosec
+	UserActivityPath             = "/auth/activity"
+	ComposeIndexPath             = "/_zot/index"
+	ReadinessPath                = "/readyz"
 	SessionClientHeaderName      = "X-ZOT-API-CLIENT"
 	SessionClientHeaderValue     = "zot-ui"
+	ClusterForwardedHeader       = "X-Zot-Cluster-Forwarded"
 	APIKeysPrefix                = "zak_"
 	CallbackUIQueryParam         = "callback_ui"
+	CompressionQueryParam        = "compression"
 	APIKeyTimeFormat             = time.RFC3339
 	// authz permissions.
 	// method actions.
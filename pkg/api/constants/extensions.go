@@ -12,15 +12,59 @@ const (
 	ExtPrefix  = BasePrefix + "/ext"
 
 	// search extension.
-	ExtSearch        = "/search"
-	ExtSearchPrefix  = ExtPrefix + ExtSearch
-	FullSearchPrefix = RoutePrefix + ExtSearchPrefix
+	ExtSearch           = "/search"
+	ExtSearchPrefix     = ExtPrefix + ExtSearch
+	FullSearchPrefix    = RoutePrefix + ExtSearchPrefix
+	ExtSearchREST       = "/rest"
+	FullSearchREST      = FullSearchPrefix + ExtSearchREST
+	ExtSearchFederated  = "/federated"
+	FullSearchFederated = FullSearchPrefix + ExtSearchFederated
+	ExtSearchStats      = "/stats"
+	FullSearchStats     = FullSearchPrefix + ExtSearchStats
+
+	// repository READMEs, served by the search extension.
+	Readme     = "/readme"
+	ExtReadme  = ExtPrefix + Readme
+	FullReadme = RoutePrefix + ExtReadme
+
+	// sync extension.
+	SyncStatus     = "/sync/status"
+	ExtSyncStatus  = ExtPrefix + SyncStatus
+	FullSyncStatus = RoutePrefix + ExtSyncStatus
+
+	SyncTrigger     = "/sync/trigger"
+	ExtSyncTrigger  = ExtPrefix + SyncTrigger
+	FullSyncTrigger = RoutePrefix + ExtSyncTrigger
 
 	// mgmt extension.
 	Mgmt     = "/mgmt"
 	ExtMgmt  = ExtPrefix + Mgmt
 	FullMgmt = RoutePrefix + ExtMgmt
 
+	MgmtRepos     = "/mgmt/repos"
+	ExtMgmtRepos  = ExtPrefix + MgmtRepos
+	FullMgmtRepos = RoutePrefix + ExtMgmtRepos
+
+	MgmtRetention     = "/mgmt/retention"
+	ExtMgmtRetention  = ExtPrefix + MgmtRetention
+	FullMgmtRetention = RoutePrefix + ExtMgmtRetention
+
+	MgmtMaintenance     = "/mgmt/maintenance"
+	ExtMgmtMaintenance  = ExtPrefix + MgmtMaintenance
+	FullMgmtMaintenance = RoutePrefix + ExtMgmtMaintenance
+
+	MgmtMetaDB     = "/mgmt/metadb"
+	ExtMgmtMetaDB  = ExtPrefix + MgmtMetaDB
+	FullMgmtMetaDB = RoutePrefix + ExtMgmtMetaDB
+
+	MgmtLog     = "/mgmt/log"
+	ExtMgmtLog  = ExtPrefix + MgmtLog
+	FullMgmtLog = RoutePrefix + ExtMgmtLog
+
+	MgmtCluster     = "/mgmt/cluster"
+	ExtMgmtCluster  = ExtPrefix + MgmtCluster
+	FullMgmtCluster = RoutePrefix + ExtMgmtCluster
+
 	// signatures extension.
 	Notation     = "/notation"
 	ExtNotation  = ExtPrefix + Notation
@@ -33,4 +77,25 @@ const (
 	UserPrefs     = "/userprefs"
 	ExtUserPrefs  = ExtPrefix + UserPrefs
 	FullUserPrefs = RoutePrefix + ExtUserPrefs
+
+	UserPrefsExport     = UserPrefs + "/export"
+	ExtUserPrefsExport  = ExtPrefix + UserPrefsExport
+	FullUserPrefsExport = RoutePrefix + ExtUserPrefsExport
+
+	UserPrefsImport     = UserPrefs + "/import"
+	ExtUserPrefsImport  = ExtPrefix + UserPrefsImport
+	FullUserPrefsImport = RoutePrefix + ExtUserPrefsImport
+
+	UserPrefsAdmin     = UserPrefs + "/admin"
+	ExtUserPrefsAdmin  = ExtPrefix + UserPrefsAdmin
+	FullUserPrefsAdmin = RoutePrefix + ExtUserPrefsAdmin
+
+	UserPrefsMigrate     = UserPrefsAdmin + "/migrate"
+	ExtUserPrefsMigrate  = ExtPrefix + UserPrefsMigrate
+	FullUserPrefsMigrate = RoutePrefix + ExtUserPrefsMigrate
+
+	// plugins extension, the routes of a plugin are served under "<prefix>/<plugin name>".
+	Plugins     = "/plugins"
+	ExtPlugins  = ExtPrefix + Plugins
+	FullPlugins = RoutePrefix + ExtPlugins
 )
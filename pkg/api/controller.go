@@ -21,14 +21,25 @@ import (
 
 	"zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/cluster"
 	ext "zotregistry.io/zot/pkg/extensions"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/maintenance"
 	"zotregistry.io/zot/pkg/meta"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/plugins"
+	"zotregistry.io/zot/pkg/retention"
 	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage"
+	storageCommon "zotregistry.io/zot/pkg/storage/common"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	"zotregistry.io/zot/pkg/storage/immutability"
+	"zotregistry.io/zot/pkg/storage/refcount"
+	"zotregistry.io/zot/pkg/storage/transcode"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+	"zotregistry.io/zot/pkg/storage/usage"
 )
 
 const (
@@ -43,13 +54,26 @@ type Controller struct {
 	MetaDB          mTypes.MetaDB
 	StoreController storage.StoreController
 	Log             log.Logger
-	Audit           *log.Logger
+	Audit           *log.AuditLogger
+	Access          *log.AccessLogger
 	Server          *http.Server
 	Metrics         monitoring.MetricServer
 	CveInfo         ext.CveInfo
+	SearchCache     ext.SearchCache
+	SecretScanner   ext.SecretScanner
+	Transcoder      *transcode.Transcoder
+	EventRecorder   ext.EventRecorder
+	Retention       *retention.Manager
+	Maintenance     *maintenance.Manager
+	PluginManager   ext.PluginManager
 	SyncOnDemand    SyncOnDemand
+	SyncStatus      ext.SyncStatus
+	SyncTrigger     ext.SyncTrigger
 	RelyingParties  map[string]rp.RelyingParty
+	ExtensionRoutes ExtensionRoutes
 	CookieStore     sessions.Store
+	Cluster         *cluster.Member
+	RefCounter      *refcount.Counter
 	// runtime params
 	chosenPort int // kernel-chosen port
 }
@@ -57,18 +81,75 @@ type Controller struct {
 func NewController(config *config.Config) *Controller {
 	var controller Controller
 
-	logger := log.NewLogger(config.Log.Level, config.Log.Output)
+	if err := log.SetRedactionPatterns(config.Log.Redact); err != nil {
+		panic(err)
+	}
+
 	controller.Config = config
-	controller.Log = logger
+	controller.Log = newLogger(config.Log)
+
+	if err := log.SetLevels(log.Levels{Level: config.Log.Level, Components: config.Log.Components}); err != nil {
+		panic(err)
+	}
+
+	if auditLogConfig := config.Log.GetAuditLogConfig(); auditLogConfig != nil {
+		if auditLogConfig.Syslog != nil {
+			controller.Audit = log.NewSyslogAuditLogger(auditLogConfig.Syslog.Network,
+				auditLogConfig.Syslog.Address, auditLogConfig.Syslog.Tag)
+		} else {
+			controller.Audit = log.NewAuditLogger(auditLogConfig.Output, log.Rotation{
+				MaxSize:    auditLogConfig.MaxSize,
+				MaxBackups: auditLogConfig.MaxBackups,
+				MaxAge:     auditLogConfig.MaxAge,
+			})
+		}
+	}
 
-	if config.Log.Audit != "" {
-		audit := log.NewAuditLogger(config.Log.Level, config.Log.Audit)
-		controller.Audit = audit
+	if accessLogConfig := config.Log.AccessLog; accessLogConfig != nil {
+		controller.Access = log.NewAccessLogger(accessLogConfig.Output, log.Rotation{
+			MaxSize:    accessLogConfig.MaxSize,
+			Interval:   accessLogConfig.RotateInterval,
+			MaxBackups: accessLogConfig.MaxBackups,
+			MaxAge:     accessLogConfig.MaxAge,
+			Compress:   accessLogConfig.Compress,
+		})
 	}
 
 	return &controller
 }
 
+// newLogger returns the application logger, writing to syslog, journald or the output file.
+func newLogger(logConfig *config.LogConfig) log.Logger {
+	var logger log.Logger
+
+	switch {
+	case logConfig.Syslog != nil:
+		tag := logConfig.Syslog.Tag
+		if tag == "" {
+			tag = log.DefaultSyslogTag
+		}
+
+		writer, err := log.NewSyslogWriter(logConfig.Syslog.Network, logConfig.Syslog.Address, tag,
+			log.SyslogFacilityDaemon)
+		if err != nil {
+			panic(err)
+		}
+
+		logger = log.NewLoggerWithWriter(logConfig.Level, writer)
+	case logConfig.Journald:
+		writer, err := log.NewJournaldWriter(log.DefaultJournaldSocket, log.DefaultSyslogTag)
+		if err != nil {
+			panic(err)
+		}
+
+		logger = log.NewLoggerWithWriter(logConfig.Level, writer)
+	default:
+		logger = log.NewLogger(logConfig.Level, logConfig.Output)
+	}
+
+	return logger.WithSampling(logConfig.Sampling)
+}
+
 func DumpRuntimeParams(log log.Logger) {
 	var rLimit syscall.Rlimit
 
@@ -116,8 +197,12 @@ func (c *Controller) Run(reloadCtx context.Context) error {
 		handlers.RecoveryHandler(handlers.RecoveryLogger(c.Log),
 			handlers.PrintRecoveryStack(false)))
 
+	if c.Access != nil {
+		engine.Use(SessionAccessLogger(c.Access, c.Config.Log.AccessLog, c.Log))
+	}
+
 	if c.Audit != nil {
-		engine.Use(SessionAuditLogger(c.Audit))
+		engine.Use(SessionAuditLogger(c.Audit, c.Log))
 	}
 
 	c.Router = engine
@@ -225,47 +310,148 @@ func (c *Controller) Init(reloadCtx context.Context) error {
 
 	c.Metrics = monitoring.NewMetricsServer(enabled, c.Log)
 
+	// the minimal build enables the metrics when they are scraped by the exporter
+	if c.Config != nil && c.Config.Extensions != nil && c.Config.Extensions.Metrics != nil &&
+		c.Config.Extensions.Metrics.RepoLabels != nil {
+		monitoring.LimitRepoLabels(c.Metrics, c.Config.Extensions.Metrics.RepoLabels.Max)
+	}
+
+	// created once, the sync extension tracks the state of the registries of the current config
+	c.SyncStatus = ext.GetSyncStatus(c.Metrics)
+	c.SyncTrigger = ext.GetSyncTrigger(c.Log.Component(log.ComponentSync))
+
 	if err := c.InitImageStore(); err != nil { //nolint:contextcheck
 		return err
 	}
 
+	if err := c.InitCluster(); err != nil {
+		return err
+	}
+
+	// created before MetaDB which invalidates it on writes
+	c.SearchCache = ext.GetSearchCache(c.Config, c.Log.Component(log.ComponentSearch))
+
 	if err := c.InitMetaDB(reloadCtx); err != nil {
 		return err
 	}
 
 	c.InitCVEInfo()
 
+	c.SecretScanner = ext.GetSecretScanner(c.Config, c.StoreController, c.MetaDB, c.Log)
+
+	if c.Config.IsZstdTranscodeEnabled() {
+		c.Transcoder = transcode.NewTranscoder(c.Config, c.StoreController, c.MetaDB, c.Log)
+	}
+
+	c.EventRecorder = ext.GetEventRecorder(c.Config, c.Log)
+
+	var retentionNotifier retention.Notifier
+
+	if c.EventRecorder != nil {
+		retentionNotifier = c.EventRecorder
+	}
+
+	c.Retention = retention.NewManager(c.Config, c.StoreController, c.MetaDB, retentionNotifier, c.Log)
+
+	c.Maintenance = maintenance.NewManager(c.Log)
+
+	c.PluginManager = ext.GetPluginManager(c.Config, c.Log)
+
 	return nil
 }
 
 func (c *Controller) InitCVEInfo() {
 	// Enable CVE extension if extension config is provided
 	if c.Config != nil && c.Config.Extensions != nil {
-		c.CveInfo = ext.GetCVEInfo(c.Config, c.StoreController, c.MetaDB, c.Log)
+		c.CveInfo = ext.GetCVEInfo(c.Config, c.StoreController, c.MetaDB, c.Log.Component(log.ComponentSearch))
+	}
+}
+
+// InvalidateSearchCache drops cached search results, it's called when the access control config is reloaded.
+// MetaDB writes invalidate the cache on their own, see ext.WrapMetaDBWithSearchCache.
+func (c *Controller) InvalidateSearchCache() {
+	if c.SearchCache != nil {
+		c.SearchCache.Invalidate()
 	}
 }
 
 func (c *Controller) InitImageStore() error {
 	linter := ext.GetLinter(c.Config, c.Log)
 
-	storeController, err := storage.New(c.Config, linter, c.Metrics, c.Log)
+	storeController, err := storage.New(c.Config, linter, c.Metrics, c.Log.Component(log.ComponentStorage))
 	if err != nil {
 		return err
 	}
 
 	c.StoreController = storeController
 
+	if c.Config.Storage.Quotas != nil {
+		usage.NewQuotaEnforcer(c.Config.Storage.Quotas, c.StoreController,
+			c.Log.Component(log.ComponentStorage)).SetQuotaEnforcer(c.StoreController)
+	}
+
+	if c.Config.Storage.TagImmutability != nil {
+		immutability.NewPolicy(c.Config.Storage.TagImmutability).SetTagPolicy(c.StoreController)
+	}
+
+	if c.Config.Storage.IncrementalGC {
+		c.RefCounter, err = refcount.NewCounter(c.Config.Storage.RootDirectory, c.Log.Component(log.ComponentStorage))
+		if err != nil {
+			return err
+		}
+
+		c.RefCounter.SetReferenceCounter(c.StoreController)
+	}
+
+	return nil
+}
+
+// InitCluster joins the cluster of the zot instances sharing the storage, the writes in the repositories are
+// serialized across the members through the leases of the cluster.
+func (c *Controller) InitCluster() error {
+	if c.Config.Cluster == nil {
+		return nil
+	}
+
+	store, err := cluster.NewLeaseStore(c.Config.Cluster.Leases)
+	if err != nil {
+		return err
+	}
+
+	member, err := cluster.NewMember(c.Config.Cluster, store, c.Log)
+	if err != nil {
+		_ = store.Close()
+
+		return err
+	}
+
+	if err := member.Start(); err != nil {
+		_ = store.Close()
+
+		return err
+	}
+
+	c.Cluster = member
+
+	c.StoreController.DefaultStore.SetRepoLocker(member)
+
+	for _, substore := range c.StoreController.SubStore {
+		substore.SetRepoLocker(member)
+	}
+
 	return nil
 }
 
 func (c *Controller) InitMetaDB(reloadCtx context.Context) error {
 	// init metaDB if search is enabled or we need to store user profiles, api keys or signatures
 	if c.Config.IsSearchEnabled() || c.Config.IsBasicAuthnEnabled() || c.Config.IsImageTrustEnabled() {
-		driver, err := meta.New(c.Config.Storage.StorageConfig, c.Log) //nolint:contextcheck
+		driver, err := meta.NewFromConfig(c.Config.Storage, c.Log) //nolint:contextcheck
 		if err != nil {
 			return err
 		}
 
+		driver = meta.NewInstrumentedMetaDB(driver, c.Metrics, c.Config.Storage.MetaDBSlowThreshold, c.Log)
+
 		err = ext.SetupExtensions(c.Config, driver, c.Log) //nolint:contextcheck
 		if err != nil {
 			return err
@@ -281,7 +467,7 @@ func (c *Controller) InitMetaDB(reloadCtx context.Context) error {
 			return err
 		}
 
-		c.MetaDB = driver
+		c.MetaDB = ext.WrapMetaDBWithSearchCache(c.SearchCache, driver)
 	}
 
 	return nil
@@ -297,64 +483,177 @@ func (c *Controller) LoadNewConfig(reloadCtx context.Context, newConfig *config.
 	c.Config.Storage.GCDelay = newConfig.Storage.GCDelay
 	c.Config.Storage.GCReferrers = newConfig.Storage.GCReferrers
 
-	// reload background tasks
+	// reload the extensions which can be enabled and disabled at runtime, their routes are set up again below
 	if newConfig.Extensions != nil {
 		if c.Config.Extensions == nil {
 			c.Config.Extensions = &extconf.ExtensionConfig{}
 		}
 
-		// reload sync extension
-		c.Config.Extensions.Sync = newConfig.Extensions.Sync
-
-		// reload only if search is enabled and reloaded config has search extension (can't setup routes at this stage)
-		if c.Config.Extensions.Search != nil && *c.Config.Extensions.Search.Enable {
-			if newConfig.Extensions.Search != nil {
-				c.Config.Extensions.Search.CVE = newConfig.Extensions.Search.CVE
-			}
+		// search needs the metaDB, which is only opened at startup
+		if c.MetaDB == nil && newConfig.IsSearchEnabled() {
+			c.Log.Error().Msg("search can't be enabled at runtime without the metadb opened at startup, " +
+				"restart zot to enable it")
+		} else {
+			c.Config.Extensions.Search = newConfig.Extensions.Search
 		}
 
-		// reload scrub extension
+		c.Config.Extensions.Sync = newConfig.Extensions.Sync
 		c.Config.Extensions.Scrub = newConfig.Extensions.Scrub
+		c.Config.Extensions.UI = newConfig.Extensions.UI
+		c.Config.Extensions.Mgmt = newConfig.Extensions.Mgmt
 	} else {
 		c.Config.Extensions = nil
 	}
 
+	// reload log levels
+	if err := c.SetLogLevels(newConfig.Log); err != nil {
+		c.Log.Error().Err(err).Msg("unable to reload the log levels")
+	}
+
 	c.InitCVEInfo()
 
+	c.SetupExtensionRoutes()
+
+	c.InvalidateSearchCache()
+
 	c.StartBackgroundTasks(reloadCtx)
 
 	c.Log.Info().Interface("reloaded params", c.Config.Sanitize()).
 		Msg("loaded new configuration settings")
 }
 
+// SetLogLevels applies the global log level and the levels of the components of the config, the levels changed at
+// runtime are reset.
+func (c *Controller) SetLogLevels(logConfig *config.LogConfig) error {
+	if err := log.SetLevels(log.Levels{Level: logConfig.Level, Components: logConfig.Components}); err != nil {
+		return err
+	}
+
+	c.Config.Log.Level = logConfig.Level
+	c.Config.Log.Components = logConfig.Components
+
+	c.Log.Info().Interface("levels", log.GetLevels()).Msg("log levels changed")
+
+	return nil
+}
+
 func (c *Controller) Shutdown() {
 	ctx := context.Background()
 	_ = c.Server.Shutdown(ctx)
+
+	// the events of the last requests are published before exiting
+	if c.EventRecorder != nil {
+		c.EventRecorder.Close()
+	}
+
+	// the running hooks complete before the plugins are stopped
+	if c.PluginManager != nil {
+		c.PluginManager.Close()
+	}
+
+	// the tasks of the member are taken over by the other members
+	if c.Cluster != nil {
+		c.Cluster.Stop()
+	}
+
+	plugins.StopStorageDrivers()
+
+	if c.RefCounter != nil {
+		_ = c.RefCounter.Close()
+	}
+
+	if err := c.StoreController.Close(); err != nil {
+		c.Log.Error().Err(err).Msg("failed to close the cache databases")
+	}
 }
 
 func (c *Controller) StartBackgroundTasks(reloadCtx context.Context) {
 	taskScheduler := scheduler.NewScheduler(c.Config, c.Log)
+
+	// the members of the cluster share the periodic tasks
+	if c.Cluster != nil {
+		taskScheduler.SetTaskOwner(c.Cluster)
+	}
+
 	taskScheduler.RunScheduler(reloadCtx)
 
+	// the garbage collection, dedupe and scrub are tracked, and can be run or paused through the mgmt extension
+	c.Maintenance.Start(taskScheduler)
+	c.registerMaintenanceJobs()
+
 	// Enable running garbage-collect periodically for DefaultStore
 	if c.Config.Storage.GC {
-		c.StoreController.DefaultStore.RunGCPeriodically(c.Config.Storage.GCInterval, taskScheduler)
+		c.Maintenance.SubmitGenerator(maintenance.GC,
+			&storageCommon.GCTaskGenerator{ImgStore: c.StoreController.DefaultStore},
+			c.Config.Storage.GCInterval, scheduler.MediumPriority)
 	}
 
 	// Enable running dedupe blobs both ways (dedupe or restore deduped blobs)
-	c.StoreController.DefaultStore.RunDedupeBlobs(time.Duration(0), taskScheduler)
+	c.Maintenance.SubmitGenerator(maintenance.Dedupe, c.newDedupeTaskGenerator(c.StoreController.DefaultStore,
+		c.Config.Storage.Dedupe), time.Duration(0), scheduler.MediumPriority)
+
+	// Enable expiring the stale blob uploads periodically for DefaultStore
+	if c.Config.Storage.UploadSessionTTL > 0 {
+		submitUploadExpiry(taskScheduler, "upload-expiry", c.StoreController.DefaultStore,
+			c.Config.Storage.UploadSessionTTL)
+	}
+
+	if c.Config.Storage.UsageAlerts != nil {
+		var notifier usage.Notifier
+
+		if c.EventRecorder != nil {
+			notifier = c.EventRecorder
+		}
+
+		usage.NewChecker(c.Config, c.StoreController, c.Metrics, notifier, c.Log).RunPeriodically(taskScheduler)
+	}
+
+	if c.MetaDB != nil && c.Config.Storage.Reconcile != nil {
+		interval := c.Config.Storage.Reconcile.Interval
+		if interval == 0 {
+			interval = meta.DefaultReconcileInterval
+		}
+
+		c.Maintenance.SubmitGenerator(maintenance.Reconcile, meta.NewReconcileTaskGenerator(c.MetaDB,
+			c.StoreController, c.Config.Storage.Reconcile.DryRun, c.Metrics, c.Log), interval, scheduler.LowPriority)
+	}
+
+	if c.MetaDB != nil && c.Config.IsUserActivityEnabled() {
+		generator, interval := meta.NewUserActivityRetentionTaskGenerator(c.MetaDB,
+			c.Config.HTTP.Auth.Activity.Retention, c.Log)
+
+		taskScheduler.SubmitSharedGenerator("user-activity-retention", generator, interval, scheduler.LowPriority)
+	}
+
+	// the policies can be set through the mgmt extension even if the config doesn't have any
+	if c.Config.Storage.Retention != nil || c.Config.IsMgmtEnabled() {
+		c.Retention.RunPeriodically(taskScheduler)
+	}
 
 	// Enable extensions if extension config is provided for DefaultStore
 	if c.Config != nil && c.Config.Extensions != nil {
 		ext.EnableMetricsExtension(c.Config, c.Log, c.Config.Storage.RootDirectory)
-		ext.EnableSearchExtension(c.Config, c.StoreController, c.MetaDB, taskScheduler, c.CveInfo, c.Log)
+		ext.StartOTLPMetricsExporter(reloadCtx, c.Config, c.Log)
+		ext.EnableSearchExtension(c.Config, c.StoreController, c.MetaDB, taskScheduler, c.CveInfo,
+			c.Log.Component(log.ComponentSearch))
+		ext.EnableSecretScanningExtension(c.Config, c.SecretScanner, taskScheduler, c.Log)
 	}
 
+	c.Transcoder.SetTaskScheduler(taskScheduler)
+
 	if c.Config.Storage.SubPaths != nil {
 		for route, storageConfig := range c.Config.Storage.SubPaths {
 			// Enable running garbage-collect periodically for subImageStore
 			if storageConfig.GC {
-				c.StoreController.SubStore[route].RunGCPeriodically(storageConfig.GCInterval, taskScheduler)
+				c.Maintenance.SubmitGenerator(maintenance.GC,
+					&storageCommon.GCTaskGenerator{ImgStore: c.StoreController.SubStore[route]},
+					storageConfig.GCInterval, scheduler.MediumPriority)
+			}
+
+			// Enable expiring the stale blob uploads periodically for subImageStore
+			if storageConfig.UploadSessionTTL > 0 {
+				submitUploadExpiry(taskScheduler, "upload-expiry"+route, c.StoreController.SubStore[route],
+					storageConfig.UploadSessionTTL)
 			}
 
 			// Enable extensions if extension config is provided for subImageStore
@@ -365,24 +664,123 @@ func (c *Controller) StartBackgroundTasks(reloadCtx context.Context) {
 			// Enable running dedupe blobs both ways (dedupe or restore deduped blobs) for subpaths
 			substore := c.StoreController.SubStore[route]
 			if substore != nil {
-				substore.RunDedupeBlobs(time.Duration(0), taskScheduler)
+				c.Maintenance.SubmitGenerator(maintenance.Dedupe, c.newDedupeTaskGenerator(substore,
+					storageConfig.Dedupe), time.Duration(0), scheduler.MediumPriority)
 			}
 		}
 	}
 
 	if c.Config.Extensions != nil {
-		ext.EnableScrubExtension(c.Config, c.Log, c.StoreController, taskScheduler)
+		ext.EnableScrubExtension(c.Config, c.Log, c.StoreController, c.Maintenance,
+			ext.GetSyncBlobSource(c.SyncTrigger))
 		//nolint: contextcheck
-		syncOnDemand, err := ext.EnableSyncExtension(c.Config, c.MetaDB, c.StoreController, taskScheduler, c.Log)
+		syncOnDemand, err := ext.EnableSyncExtension(c.Config, c.MetaDB, c.StoreController, taskScheduler,
+			c.SyncStatus, c.SyncTrigger, c.Log.Component(log.ComponentSync))
 		if err != nil {
 			c.Log.Error().Err(err).Msg("unable to start sync extension")
 		}
 
 		c.SyncOnDemand = syncOnDemand
+	} else {
+		// the sync extension was disabled by the reloaded config
+		c.SyncOnDemand = nil
 	}
 
 	// we can later move enabling the other scheduled tasks inside the call below
-	ext.EnableScheduledTasks(c.Config, taskScheduler, c.MetaDB, c.Log) //nolint: contextcheck
+	ext.EnableScheduledTasks(c.Config, taskScheduler, c.MetaDB, c.EventRecorder, c.Log) //nolint: contextcheck
+
+	ext.EnablePluginTasks(c.Config, taskScheduler, c.PluginManager, c.Log)
+}
+
+// submitUploadExpiry expires the blob uploads of an image store not written for longer than the ttl, the members
+// of a cluster share the task. It runs every hour, or more often if the ttl is shorter.
+func submitUploadExpiry(taskScheduler *scheduler.Scheduler, name string, imgStore storageTypes.ImageStore,
+	ttl time.Duration,
+) {
+	interval := storageConstants.DefaultUploadExpiryInterval
+	if ttl < interval {
+		interval = ttl
+	}
+
+	taskScheduler.SubmitSharedGenerator(name, &storageCommon.UploadExpiryTaskGenerator{ImgStore: imgStore, TTL: ttl},
+		interval, scheduler.LowPriority)
+}
+
+// registerMaintenanceJobs enables the manual runs of the garbage collection, for the image stores having it
+// enabled, of the dedupe, which dedupes or restores the blobs of each image store depending on its config, and
+// of the reconciliation of the metadata with the storage.
+func (c *Controller) registerMaintenanceJobs() {
+	type maintainedStore struct {
+		imgStore storageTypes.ImageStore
+		gc       bool
+		dedupe   bool
+	}
+
+	stores := []maintainedStore{{c.StoreController.DefaultStore, c.Config.Storage.GC, c.Config.Storage.Dedupe}}
+
+	for route, storageConfig := range c.Config.Storage.SubPaths {
+		if substore := c.StoreController.SubStore[route]; substore != nil {
+			stores = append(stores, maintainedStore{substore, storageConfig.GC, storageConfig.Dedupe})
+		}
+	}
+
+	gcEnabled := false
+
+	for _, store := range stores {
+		gcEnabled = gcEnabled || store.gc
+	}
+
+	if gcEnabled {
+		c.Maintenance.Register(maintenance.GC, func() []scheduler.TaskGenerator {
+			generators := []scheduler.TaskGenerator{}
+
+			for _, store := range stores {
+				if !store.gc {
+					continue
+				}
+
+				imgStore := store.imgStore
+
+				// unlike the scheduled runs, the repos are collected without a random delay between them
+				generators = append(generators, maintenance.NewRepoTaskGenerator(imgStore,
+					func(repo string) scheduler.Task {
+						return storageCommon.NewGCTask(imgStore, repo)
+					}), &storageCommon.DedupeGCTaskGenerator{ImgStore: imgStore})
+			}
+
+			return generators
+		})
+	}
+
+	c.Maintenance.Register(maintenance.Dedupe, func() []scheduler.TaskGenerator {
+		generators := []scheduler.TaskGenerator{}
+
+		for _, store := range stores {
+			generators = append(generators, c.newDedupeTaskGenerator(store.imgStore, store.dedupe))
+		}
+
+		return generators
+	})
+
+	// the metadata can be reconciled with the storage even if it isn't done periodically
+	if c.MetaDB != nil {
+		dryRun := c.Config.Storage.Reconcile != nil && c.Config.Storage.Reconcile.DryRun
+
+		c.Maintenance.Register(maintenance.Reconcile, func() []scheduler.TaskGenerator {
+			return []scheduler.TaskGenerator{
+				meta.NewReconcileTaskGenerator(c.MetaDB, c.StoreController, dryRun, c.Metrics, c.Log),
+			}
+		})
+	}
+}
+
+func (c *Controller) newDedupeTaskGenerator(imgStore storageTypes.ImageStore, dedupe bool,
+) scheduler.TaskGenerator {
+	return &storageCommon.DedupeTaskGenerator{
+		ImgStore: imgStore,
+		Dedupe:   dedupe,
+		Log:      c.Log,
+	}
 }
 
 type SyncOnDemand interface {
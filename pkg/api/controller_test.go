@@ -58,6 +58,7 @@ import (
 	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/log"
 	"zotregistry.io/zot/pkg/meta"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
 	"zotregistry.io/zot/pkg/storage"
 	storageConstants "zotregistry.io/zot/pkg/storage/constants"
 	"zotregistry.io/zot/pkg/test"
@@ -6696,6 +6697,61 @@ func TestStorageCommit(t *testing.T) {
 	})
 }
 
+func TestStorageReadOnly(t *testing.T) {
+	Convey("Read-only image stores serve the pulls only", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		dir := t.TempDir()
+		ctlr := makeController(conf, dir)
+		ctlr.Config.Storage.ReadOnly = true
+		ctlr.Config.Storage.SubPaths = map[string]config.StorageConfig{
+			"/a": {RootDirectory: t.TempDir()},
+		}
+
+		img := CreateRandomImage()
+
+		err := test.WriteImageToFileSystem(img, "repo", "1.0", test.GetDefaultStoreController(dir, ctlr.Log))
+		So(err, ShouldBeNil)
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		resp, err := resty.R().Get(baseURL + "/v2/repo/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().Get(baseURL + "/v2/repo/blobs/" + img.ConfigDescriptor.Digest.String())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().Post(baseURL + "/v2/repo/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
+		So(string(resp.Body()), ShouldContainSubstring, "UNSUPPORTED")
+
+		resp, err = resty.R().SetHeader("Content-Type", ispec.MediaTypeImageManifest).
+			SetBody(img.ManifestDescriptor.Data).Put(baseURL + "/v2/repo/manifests/2.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
+
+		resp, err = resty.R().Delete(baseURL + "/v2/repo/manifests/" + img.ManifestDescriptor.Digest.String())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
+
+		resp, err = resty.R().Get(baseURL + "/v2/repo/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// the other stores are still writable
+		err = UploadImage(img, baseURL, "a/repo", "1.0")
+		So(err, ShouldBeNil)
+	})
+}
+
 func TestManifestImageIndex(t *testing.T) {
 	Convey("Make a new controller", t, func() {
 		port := test.GetFreePort()
@@ -7112,6 +7168,515 @@ func TestManifestImageIndex(t *testing.T) {
 	})
 }
 
+func TestAccessLog(t *testing.T) {
+	Convey("Make a new controller writing the access log", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("user", "user"))
+		defer os.Remove(htpasswdPath)
+
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{
+				Path: htpasswdPath,
+			},
+		}
+
+		accessPath := path.Join(t.TempDir(), "zot-access.log")
+		conf.Log.AccessLog = &config.AccessLogConfig{
+			Output:      accessPath,
+			Fields:      []string{"clientIP"},
+			ErrorFields: []string{"userAgent"},
+		}
+
+		ctlr := makeController(conf, t.TempDir())
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+
+		image := CreateRandomImage()
+
+		err := UploadImageWithBasicAuth(image, baseURL, "repo", "1.0", "user", "user")
+		So(err, ShouldBeNil)
+
+		resp, err := resty.R().SetBasicAuth("user", "user").Get(baseURL + "/v2/repo/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().SetHeader("User-Agent", "test-agent").Get(baseURL + "/v2/repo/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		cm.StopServer()
+
+		err = ctlr.Access.Close()
+		So(err, ShouldBeNil)
+
+		file, err := os.Open(accessPath)
+		So(err, ShouldBeNil)
+		defer file.Close()
+
+		var records []log.AccessRecord
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record log.AccessRecord
+
+			err = json.Unmarshal(scanner.Bytes(), &record)
+			So(err, ShouldBeNil)
+
+			records = append(records, record)
+		}
+
+		So(len(records), ShouldBeGreaterThan, 2)
+
+		pull := records[len(records)-2]
+		So(pull.Method, ShouldEqual, http.MethodGet)
+		So(pull.Path, ShouldEqual, "/v2/repo/manifests/1.0")
+		So(pull.Repo, ShouldEqual, "repo")
+		So(pull.Identity, ShouldEqual, "user")
+		So(pull.Status, ShouldEqual, http.StatusOK)
+		So(pull.Bytes, ShouldEqual, len(image.ManifestDescriptor.Data))
+		So(pull.Duration, ShouldBeGreaterThan, 0)
+		So(pull.ClientIP, ShouldNotBeEmpty)
+		So(pull.UserAgent, ShouldBeEmpty)
+
+		// the error fields are only added to the failed requests
+		denied := records[len(records)-1]
+		So(denied.Status, ShouldEqual, http.StatusUnauthorized)
+		So(denied.Identity, ShouldBeEmpty)
+		So(denied.ClientIP, ShouldNotBeEmpty)
+		So(denied.UserAgent, ShouldEqual, "test-agent")
+	})
+}
+
+func TestComposeIndex(t *testing.T) {
+	Convey("Compose an index from the manifests of a repo", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		ctlr := makeController(conf, t.TempDir())
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		amd64Image := CreateImageWith().RandomLayers(1, 10).ImageConfig(ispec.Image{
+			Platform: ispec.Platform{OS: "linux", Architecture: "amd64"},
+		}).Build()
+		arm64Image := CreateImageWith().RandomLayers(1, 10).ImageConfig(ispec.Image{
+			Platform: ispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		}).Build()
+		artifact := CreateImageWith().RandomLayers(1, 10).ArtifactConfig("application/vnd.test").Build()
+
+		for _, image := range []Image{amd64Image, arm64Image, artifact} {
+			err := UploadImage(image, baseURL, "repo", image.DigestStr())
+			So(err, ShouldBeNil)
+		}
+
+		composeURL := baseURL + "/v2/repo/_zot/index/"
+
+		Convey("The platforms are read from the configs", func() {
+			resp, err := resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{
+					{Digest: amd64Image.Digest()},
+					{Digest: arm64Image.Digest(), Annotations: map[string]string{"arch": "arm64"}},
+				},
+				Annotations: map[string]string{"built-by": "ci"},
+			}).Post(composeURL + "multi")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+			digest := resp.Header().Get(constants.DistContentDigestKey)
+			So(digest, ShouldNotBeEmpty)
+			So(resp.Header().Get("Location"), ShouldEqual, "/v2/repo/manifests/"+digest)
+
+			resp, err = resty.R().SetHeader("Accept", ispec.MediaTypeImageIndex).
+				Get(baseURL + "/v2/repo/manifests/multi")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("Content-Type"), ShouldEqual, ispec.MediaTypeImageIndex)
+			So(godigest.FromBytes(resp.Body()).String(), ShouldEqual, digest)
+
+			var index ispec.Index
+
+			err = json.Unmarshal(resp.Body(), &index)
+			So(err, ShouldBeNil)
+			So(index.Annotations["built-by"], ShouldEqual, "ci")
+			So(len(index.Manifests), ShouldEqual, 2)
+			So(index.Manifests[0].Digest, ShouldEqual, amd64Image.Digest())
+			So(index.Manifests[0].MediaType, ShouldEqual, ispec.MediaTypeImageManifest)
+			So(index.Manifests[0].Size, ShouldEqual, len(amd64Image.ManifestDescriptor.Data))
+			So(*index.Manifests[0].Platform, ShouldResemble, ispec.Platform{OS: "linux", Architecture: "amd64"})
+			So(*index.Manifests[1].Platform, ShouldResemble,
+				ispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+			So(index.Manifests[1].Annotations["arch"], ShouldEqual, "arm64")
+		})
+
+		Convey("The platform of a manifest without image config is given", func() {
+			resp, err := resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{{Digest: artifact.Digest()}},
+			}).Post(composeURL + "artifact")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{
+					{Digest: artifact.Digest(), Platform: &ispec.Platform{OS: "linux", Architecture: "s390x"}},
+				},
+			}).Post(composeURL + "artifact")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+		})
+
+		Convey("Get errors with bad requests", func() {
+			resp, err := resty.R().SetBody(`{"manifests":`).Post(composeURL + "bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{}).Post(composeURL + "bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{{Digest: amd64Image.Digest()}, {Digest: amd64Image.Digest()}},
+			}).Post(composeURL + "bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{{Digest: "sha256:bad"}},
+			}).Post(composeURL + "bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{{Digest: godigest.FromString("missing")}},
+			}).Post(composeURL + "bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{{Digest: amd64Image.Digest()}},
+			}).Post(baseURL + "/v2/missing/_zot/index/bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			// an index can't be listed in an index
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{{Digest: amd64Image.Digest()}},
+			}).Post(composeURL + "single")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+			resp, err = resty.R().SetBody(api.ComposeIndexRequest{
+				Manifests: []api.ComposeIndexManifest{
+					{Digest: godigest.Digest(resp.Header().Get(constants.DistContentDigestKey))},
+				},
+			}).Post(composeURL + "bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
+func TestReadiness(t *testing.T) {
+	Convey("The readiness is checked without credentials", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(getCredString("test", "test"))
+		defer os.Remove(htpasswdPath)
+
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{
+				Path: htpasswdPath,
+			},
+		}
+
+		ctlr := makeController(conf, t.TempDir())
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		resp, err := resty.R().Get(baseURL + constants.ReadinessPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		Convey("The cache database can't be reached", func() {
+			defaultStore := ctlr.StoreController.DefaultStore
+			defer func() { ctlr.StoreController.DefaultStore = defaultStore }()
+
+			ctlr.StoreController.DefaultStore = mocks.MockedImageStore{
+				PingFn: func() error {
+					return errors.ErrCacheRootBucket
+				},
+			}
+
+			resp, err := resty.R().Get(baseURL + constants.ReadinessPath)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusServiceUnavailable)
+		})
+	})
+}
+
+func TestCluster(t *testing.T) {
+	Convey("Upload sessions are handled by the member which created them", t, func() {
+		newClusterController := func(name, port string) *api.Controller {
+			conf := config.New()
+			conf.HTTP.Port = port
+			conf.Storage.Dedupe = false
+			conf.Cluster = &config.ClusterConfig{
+				Name:          name,
+				Address:       test.GetBaseURL(port),
+				Leases:        config.LeasesConfig{Driver: "memory", KeyPrefix: t.Name()},
+				LeaseDuration: time.Second,
+			}
+
+			// each member has its own storage, the forwarded requests are visible in the storage of their owner
+			return makeController(conf, t.TempDir())
+		}
+
+		port1 := test.GetFreePort()
+		baseURL1 := test.GetBaseURL(port1)
+		ctlr1 := newClusterController("zot-0", port1)
+
+		cm1 := test.NewControllerManager(ctlr1)
+		cm1.StartAndWait(port1)
+		defer cm1.StopServer()
+
+		port2 := test.GetFreePort()
+		baseURL2 := test.GetBaseURL(port2)
+		ctlr2 := newClusterController("zot-1", port2)
+
+		cm2 := test.NewControllerManager(ctlr2)
+		cm2.StartAndWait(port2)
+		defer cm2.StopServer()
+
+		So(ctlr1.Cluster, ShouldNotBeNil)
+		So(ctlr2.Cluster, ShouldNotBeNil)
+
+		members, err := ctlr2.Cluster.Members()
+		So(err, ShouldBeNil)
+		So(members, ShouldHaveLength, 2)
+
+		content := []byte("this is a blob uploaded through two members")
+		digest := godigest.FromBytes(content)
+
+		resp, err := resty.R().Post(baseURL1 + "/v2/repo/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		location := resp.Header().Get("Location")
+		So(location, ShouldNotBeEmpty)
+
+		resp, err = resty.R().
+			SetHeader("Content-Length", strconv.Itoa(len(content))).
+			SetHeader("Content-Range", fmt.Sprintf("%d-%d", 0, len(content)-1)).
+			SetHeader("Content-Type", "application/octet-stream").
+			SetBody(content).
+			Patch(baseURL2 + location)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		resp, err = resty.R().Get(baseURL2 + location)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNoContent)
+		So(resp.Header().Get("Range"), ShouldEqual, fmt.Sprintf("0-%d", len(content)-1))
+
+		resp, err = resty.R().SetQueryParam("digest", digest.String()).Put(baseURL2 + location)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		// the upload was finished by its owner
+		resp, err = resty.R().Head(baseURL1 + "/v2/repo/blobs/" + digest.String())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().Head(baseURL2 + "/v2/repo/blobs/" + digest.String())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		// the finished session isn't owned anymore, it's handled locally
+		resp, err = resty.R().Get(baseURL2 + location)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		// a cancelled session is released too
+		resp, err = resty.R().Post(baseURL2 + "/v2/repo/blobs/uploads/")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		location = resp.Header().Get("Location")
+
+		resp, err = resty.R().Delete(baseURL1 + location)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNoContent)
+
+		resp, err = resty.R().Delete(baseURL1 + location)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+	})
+
+	Convey("A member with the name of a running member can't join", t, func() {
+		newConfig := func(driver string) *config.Config {
+			conf := config.New()
+			conf.Cluster = &config.ClusterConfig{
+				Name:          "zot-0",
+				Address:       "http://127.0.0.1:8080",
+				Leases:        config.LeasesConfig{Driver: driver, KeyPrefix: t.Name()},
+				LeaseDuration: 200 * time.Millisecond,
+			}
+
+			return conf
+		}
+
+		ctlr1 := makeController(newConfig("memory"), t.TempDir())
+		So(ctlr1.Init(context.Background()), ShouldBeNil)
+
+		defer ctlr1.Cluster.Stop()
+
+		ctlr2 := makeController(newConfig("memory"), t.TempDir())
+		So(ctlr2.Init(context.Background()), ShouldEqual, errors.ErrClusterMemberExists)
+
+		ctlr3 := makeController(newConfig("etcd"), t.TempDir())
+		So(ctlr3.Init(context.Background()), ShouldEqual, errors.ErrBadConfig)
+	})
+}
+
+func TestUserActivity(t *testing.T) {
+	Convey("Make a new controller recording the user activity", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s",
+			test.GetCredString("admin", "admin"), test.GetCredString("user", "user")))
+		defer os.Remove(htpasswdPath)
+
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{
+				Path: htpasswdPath,
+			},
+			Activity: &config.UserActivityConfig{},
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				AuthorizationAllRepos: config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users: []string{"user"},
+							Actions: []string{
+								constants.ReadPermission,
+								constants.CreatePermission,
+								constants.DeletePermission,
+							},
+						},
+					},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{"admin"},
+				Actions: []string{constants.ReadPermission},
+			},
+		}
+
+		ctlr := makeController(conf, t.TempDir())
+
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "repo", "1.0", "user", "user")
+		So(err, ShouldBeNil)
+
+		resp, err := resty.R().SetBasicAuth("user", "user").Delete(baseURL + "/v2/repo/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		var activityResponse struct {
+			Activity []mTypes.UserActivity `json:"activity"`
+		}
+
+		resp, err = resty.R().SetBasicAuth("user", "user").Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &activityResponse)
+		So(err, ShouldBeNil)
+		So(len(activityResponse.Activity), ShouldEqual, 2)
+		So(activityResponse.Activity[0].Action, ShouldEqual, mTypes.UserActivityDelete)
+		So(activityResponse.Activity[1].Action, ShouldEqual, mTypes.UserActivityPush)
+		So(activityResponse.Activity[1].Repo, ShouldEqual, "repo")
+		So(activityResponse.Activity[1].Reference, ShouldEqual, "1.0")
+
+		resp, err = resty.R().SetBasicAuth("user", "user").SetQueryParam("limit", "1").
+			Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &activityResponse)
+		So(err, ShouldBeNil)
+		So(len(activityResponse.Activity), ShouldEqual, 1)
+
+		// only the admins can get the activity of the other users
+		resp, err = resty.R().SetBasicAuth("user", "user").SetQueryParam("user", "admin").
+			Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().SetBasicAuth("admin", "admin").SetQueryParam("user", "user").
+			Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &activityResponse)
+		So(err, ShouldBeNil)
+		So(len(activityResponse.Activity), ShouldEqual, 2)
+
+		resp, err = resty.R().SetBasicAuth("admin", "admin").Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &activityResponse)
+		So(err, ShouldBeNil)
+		So(activityResponse.Activity, ShouldBeEmpty)
+
+		// the time range is filtered
+		resp, err = resty.R().SetBasicAuth("user", "user").
+			SetQueryParam("to", time.Now().Add(-time.Hour).Format(time.RFC3339)).
+			Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &activityResponse)
+		So(err, ShouldBeNil)
+		So(activityResponse.Activity, ShouldBeEmpty)
+
+		resp, err = resty.R().SetBasicAuth("user", "user").SetQueryParam("from", "yesterday").
+			Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = resty.R().SetBasicAuth("user", "user").SetQueryParam("limit", "-1").
+			Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = resty.R().Get(baseURL + constants.UserActivityPath)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+	})
+}
+
 func TestManifestCollision(t *testing.T) {
 	Convey("Make a new controller", t, func() {
 		port := test.GetFreePort()
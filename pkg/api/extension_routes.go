@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gorilla/mux"
+
+	"zotregistry.io/zot/pkg/api/constants"
+	ext "zotregistry.io/zot/pkg/extensions"
+	"zotregistry.io/zot/pkg/log"
+)
+
+// ExtensionRoutes are the routes of the search, image trust, mgmt, sync, user preferences, plugins and UI
+// extensions. They are built again when the config is reloaded, so the extensions are enabled and disabled
+// without restarting.
+type ExtensionRoutes struct {
+	// the routes under /v2, behind the authentication and authorization of the registry
+	prefixed atomic.Pointer[mux.Router]
+	// the routes of the UI, matched after all the others
+	ui atomic.Pointer[mux.Router]
+}
+
+// MatchPrefixed tells if the request is one of the extension routes under /v2.
+func (er *ExtensionRoutes) MatchPrefixed(request *http.Request, _ *mux.RouteMatch) bool {
+	return matchRouter(er.prefixed.Load(), request)
+}
+
+// ServePrefixed handles the requests of the extension routes under /v2.
+func (er *ExtensionRoutes) ServePrefixed(response http.ResponseWriter, request *http.Request) {
+	serveRouter(er.prefixed.Load(), response, request)
+}
+
+// MatchUI tells if the request is one of the UI routes.
+func (er *ExtensionRoutes) MatchUI(request *http.Request, _ *mux.RouteMatch) bool {
+	return matchRouter(er.ui.Load(), request)
+}
+
+// ServeUI handles the requests of the UI routes.
+func (er *ExtensionRoutes) ServeUI(response http.ResponseWriter, request *http.Request) {
+	serveRouter(er.ui.Load(), response, request)
+}
+
+func matchRouter(router *mux.Router, request *http.Request) bool {
+	if router == nil {
+		return false
+	}
+
+	var match mux.RouteMatch
+
+	// the router answers the requests with a method it doesn't handle with 405
+	return router.Match(request, &match) || match.MatchErr == mux.ErrMethodMismatch
+}
+
+func serveRouter(router *mux.Router, response http.ResponseWriter, request *http.Request) {
+	// disabled between the match and now
+	if router == nil {
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	router.ServeHTTP(response, request)
+}
+
+// SetupExtensionRoutes builds the routes of the extensions enabled by the current config, they replace the
+// previous ones once they are all set up.
+func (c *Controller) SetupExtensionRoutes() {
+	prefixedRouter := mux.NewRouter()
+	prefixedRouter.UseEncodedPath()
+
+	extRouter := prefixedRouter.PathPrefix(constants.RoutePrefix).Subrouter()
+
+	// Preconditions for enabling the actual extension routes are part of extensions themselves
+	ext.SetupSearchRoutes(c.Config, extRouter, c.StoreController, c.MetaDB, c.CveInfo,
+		c.SearchCache, c.Log.Component(log.ComponentSearch))
+	ext.SetupImageTrustRoutes(c.Config, extRouter, c.MetaDB, c.Log)
+	ext.SetupMgmtRoutes(c.Config, extRouter, c.StoreController, c.MetaDB, c.EventRecorder,
+		c.Retention, c.Maintenance, c.Cluster, c.Log)
+	ext.SetupSyncRoutes(c.Config, extRouter, c.SyncStatus, c.SyncTrigger, c.Log.Component(log.ComponentSync))
+	ext.SetupUserPreferencesRoutes(c.Config, extRouter, c.MetaDB, c.Log)
+	ext.SetupPluginRoutes(c.Config, extRouter, c.PluginManager, c.Log)
+
+	uiRouter := mux.NewRouter()
+	uiRouter.UseEncodedPath()
+
+	ext.SetupUIRoutes(c.Config, uiRouter, c.Log)
+
+	c.ExtensionRoutes.prefixed.Store(prefixedRouter)
+	c.ExtensionRoutes.ui.Store(uiRouter)
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	apiErr "zotregistry.io/zot/pkg/api/errors"
+	zcommon "zotregistry.io/zot/pkg/common"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+// the manifest digests, platforms and annotations of an index fit in 1MiB.
+const maxComposeIndexSize = 1024 * 1024
+
+// ComposeIndexRequest lists the manifests of the index to build, they must already be in the repository.
+type ComposeIndexRequest struct {
+	Manifests   []ComposeIndexManifest `json:"manifests"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+}
+
+// ComposeIndexManifest is a manifest of the index, its platform is read from its config if not given.
+type ComposeIndexManifest struct {
+	Digest      godigest.Digest   `json:"digest"`
+	Platform    *ispec.Platform   `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ComposeIndex godoc
+// @Summary Compose an image index
+// @Description Build an image index from image manifests of the repository and store it under the reference,
+// @Description the platforms which aren't given are read from the image configs.
+// @Accept  json
+// @Produce json
+// @Param   name     			path    string     true        "repository name"
+// @Param   reference     path    string     true        "tag or digest of the index"
+// @Param   request  body    api.ComposeIndexRequest true "manifests of the index"
+// @Header  201 {object} constants.DistContentDigestKey
+// @Success 201 {string} string	"created"
+// @Failure 400 {string} string "bad request"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /v2/{name}/_zot/index/{reference} [post].
+func (rh *RouteHandler) ComposeIndex(response http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+	name := vars["name"]
+	reference := vars["reference"]
+
+	var indexRequest ComposeIndexRequest
+
+	decoder := json.NewDecoder(io.LimitReader(request.Body, maxComposeIndexSize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&indexRequest); err != nil {
+		e := apiErr.NewError(apiErr.MANIFEST_INVALID).AddDetail(map[string]string{"error": err.Error()})
+		zcommon.WriteJSON(response, http.StatusBadRequest, apiErr.NewErrorList(e))
+
+		return
+	}
+
+	index, err := composeIndex(rh.getImageStore(name), name, indexRequest)
+	if err != nil {
+		details := zerr.GetDetails(err)
+
+		switch {
+		case errors.Is(err, zerr.ErrRepoNotFound):
+			details["name"] = name
+			e := apiErr.NewError(apiErr.NAME_UNKNOWN).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		case errors.Is(err, zerr.ErrManifestNotFound):
+			e := apiErr.NewError(apiErr.MANIFEST_UNKNOWN).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		case errors.Is(err, zerr.ErrBlobNotFound):
+			e := apiErr.NewError(apiErr.BLOB_UNKNOWN).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusBadRequest, apiErr.NewErrorList(e))
+		case errors.Is(err, zerr.ErrEmptyIndex), errors.Is(err, zerr.ErrDuplicateIndexManifest),
+			errors.Is(err, zerr.ErrUnsupportedIndexManifest), errors.Is(err, zerr.ErrUnknownManifestPlatform),
+			errors.Is(err, zerr.ErrBadManifest):
+			details["reason"] = err.Error()
+			e := apiErr.NewError(apiErr.MANIFEST_INVALID).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusBadRequest, apiErr.NewErrorList(e))
+		default:
+			rh.c.Log.Error().Err(err).Str("repository", name).Str("reference", reference).
+				Msg("failed to compose the index")
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	body, err := json.Marshal(index)
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rh.putManifest(response, request, name, reference, ispec.MediaTypeImageIndex, body)
+}
+
+// composeIndex builds the index listing the image manifests of the repository.
+func composeIndex(imgStore storageTypes.ImageStore, repo string, indexRequest ComposeIndexRequest,
+) (ispec.Index, error) {
+	if len(indexRequest.Manifests) == 0 {
+		return ispec.Index{}, zerr.ErrEmptyIndex
+	}
+
+	index := ispec.Index{
+		MediaType:   ispec.MediaTypeImageIndex,
+		Manifests:   make([]ispec.Descriptor, 0, len(indexRequest.Manifests)),
+		Annotations: indexRequest.Annotations,
+	}
+	index.SchemaVersion = 2
+
+	listed := map[godigest.Digest]bool{}
+
+	for _, indexManifest := range indexRequest.Manifests {
+		if err := indexManifest.Digest.Validate(); err != nil {
+			return ispec.Index{}, zerr.NewError(zerr.ErrBadManifest).AddDetail("digest", indexManifest.Digest.String())
+		}
+
+		if listed[indexManifest.Digest] {
+			return ispec.Index{}, zerr.NewError(zerr.ErrDuplicateIndexManifest).
+				AddDetail("digest", indexManifest.Digest.String())
+		}
+
+		listed[indexManifest.Digest] = true
+
+		manifestBlob, _, mediaType, err := imgStore.GetImageManifest(repo, indexManifest.Digest.String())
+		if err != nil {
+			if errors.Is(err, zerr.ErrManifestNotFound) {
+				return ispec.Index{}, zerr.NewError(err).AddDetail("digest", indexManifest.Digest.String())
+			}
+
+			return ispec.Index{}, err
+		}
+
+		if mediaType != ispec.MediaTypeImageManifest {
+			return ispec.Index{}, zerr.NewError(zerr.ErrUnsupportedIndexManifest).
+				AddDetail("digest", indexManifest.Digest.String()).AddDetail("mediaType", mediaType)
+		}
+
+		platform := indexManifest.Platform
+		if platform == nil {
+			platform, err = getManifestPlatform(imgStore, repo, manifestBlob)
+			if err != nil {
+				return ispec.Index{}, zerr.NewError(err).AddDetail("digest", indexManifest.Digest.String())
+			}
+		}
+
+		index.Manifests = append(index.Manifests, ispec.Descriptor{
+			MediaType:   mediaType,
+			Digest:      indexManifest.Digest,
+			Size:        int64(len(manifestBlob)),
+			Platform:    platform,
+			Annotations: indexManifest.Annotations,
+		})
+	}
+
+	return index, nil
+}
+
+// getManifestPlatform reads the platform of the image from its config.
+func getManifestPlatform(imgStore storageTypes.ImageStore, repo string, manifestBlob []byte,
+) (*ispec.Platform, error) {
+	var manifest ispec.Manifest
+
+	if err := json.Unmarshal(manifestBlob, &manifest); err != nil {
+		return nil, zerr.ErrBadManifest
+	}
+
+	if manifest.Config.MediaType != ispec.MediaTypeImageConfig {
+		return nil, zerr.ErrUnknownManifestPlatform
+	}
+
+	var lockLatency time.Time
+
+	imgStore.RLockRepo(repo, &lockLatency)
+	configBlob, err := imgStore.GetBlobContent(repo, manifest.Config.Digest)
+	imgStore.RUnlockRepo(repo, &lockLatency)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var config ispec.Image
+
+	if err := json.Unmarshal(configBlob, &config); err != nil || config.OS == "" || config.Architecture == "" {
+		return nil, zerr.ErrUnknownManifestPlatform
+	}
+
+	return &ispec.Platform{
+		Architecture: config.Architecture,
+		OS:           config.OS,
+		OSVersion:    config.OSVersion,
+		OSFeatures:   config.OSFeatures,
+		Variant:      config.Variant,
+	}, nil
+}
@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"path"
@@ -39,6 +40,7 @@ import (
 	apiErr "zotregistry.io/zot/pkg/api/errors"
 	zcommon "zotregistry.io/zot/pkg/common"
 	gqlPlayground "zotregistry.io/zot/pkg/debug/gqlplayground"
+	"zotregistry.io/zot/pkg/debug/pprof"
 	debug "zotregistry.io/zot/pkg/debug/swagger"
 	ext "zotregistry.io/zot/pkg/extensions"
 	syncConstants "zotregistry.io/zot/pkg/extensions/sync/constants"
@@ -48,6 +50,7 @@ import (
 	zreg "zotregistry.io/zot/pkg/regexp"
 	reqCtx "zotregistry.io/zot/pkg/requestcontext"
 	storageCommon "zotregistry.io/zot/pkg/storage/common"
+	"zotregistry.io/zot/pkg/storage/transcode"
 	storageTypes "zotregistry.io/zot/pkg/storage/types"
 	"zotregistry.io/zot/pkg/test/inject"
 )
@@ -85,6 +88,12 @@ func (rh *RouteHandler) SetupRoutes() {
 		}
 	}
 
+	if rh.c.Config.IsOpenIDAuthEnabled() && rh.c.Config.IsAPIKeyEnabled() {
+		// device authorization flow, for the clients which can't receive the openID callback, like zli
+		rh.c.Router.HandleFunc(constants.DeviceAuthPath, rh.DeviceAuthorization).Methods(http.MethodPost)
+		rh.c.Router.HandleFunc(constants.DeviceTokenPath, rh.DeviceToken).Methods(http.MethodPost)
+	}
+
 	if rh.c.Config.IsAPIKeyEnabled() {
 		// enable api key management urls
 		apiKeyRouter := rh.c.Router.PathPrefix(constants.APIKeyPath).Subrouter()
@@ -99,6 +108,19 @@ func (rh *RouteHandler) SetupRoutes() {
 		apiKeyRouter.Methods(http.MethodDelete).HandlerFunc(rh.RevokeAPIKey)
 	}
 
+	if rh.c.Config.IsUserActivityEnabled() {
+		activityRouter := rh.c.Router.PathPrefix(constants.UserActivityPath).Subrouter()
+		activityRouter.Use(authHandler)
+		activityRouter.Use(BaseAuthzHandler(rh.c))
+		activityRouter.Use(zcommon.ACHeadersMiddleware(rh.c.Config, http.MethodGet, http.MethodOptions))
+		activityRouter.Use(zcommon.CORSHeadersMiddleware(rh.c.Config.HTTP.AllowOrigin))
+
+		activityRouter.Methods(http.MethodGet, http.MethodOptions).HandlerFunc(rh.GetUserActivity)
+	}
+
+	// the readiness is checked by the orchestrators, without credentials
+	rh.c.Router.HandleFunc(constants.ReadinessPath, rh.CheckReadiness).Methods(http.MethodGet)
+
 	/* on every route which may be used by UI we set OPTIONS as allowed METHOD
 	to enable preflight request from UI to backend */
 	if rh.c.Config.IsBasicAuthnEnabled() {
@@ -139,6 +161,9 @@ func (rh *RouteHandler) SetupRoutes() {
 			rh.UpdateManifest).Methods(http.MethodPut)
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/manifests/{reference}", zreg.NameRegexp.String()),
 			rh.DeleteManifest).Methods(http.MethodDelete)
+		// zot specific, builds an index from the manifests of the repo
+		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}%s/{reference}", zreg.NameRegexp.String(),
+			constants.ComposeIndexPath), rh.ComposeIndex).Methods(http.MethodPost)
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/blobs/{digest}", zreg.NameRegexp.String()),
 			rh.CheckBlob).Methods(http.MethodHead)
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/blobs/{digest}", zreg.NameRegexp.String()),
@@ -148,13 +173,13 @@ func (rh *RouteHandler) SetupRoutes() {
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/blobs/uploads/", zreg.NameRegexp.String()),
 			rh.CreateBlobUpload).Methods(http.MethodPost)
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/blobs/uploads/{session_id}", zreg.NameRegexp.String()),
-			rh.GetBlobUpload).Methods(http.MethodGet)
+			rh.forwardToUploadOwner(rh.GetBlobUpload)).Methods(http.MethodGet)
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/blobs/uploads/{session_id}", zreg.NameRegexp.String()),
-			rh.PatchBlobUpload).Methods(http.MethodPatch)
+			rh.forwardToUploadOwner(rh.PatchBlobUpload)).Methods(http.MethodPatch)
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/blobs/uploads/{session_id}", zreg.NameRegexp.String()),
-			rh.UpdateBlobUpload).Methods(http.MethodPut)
+			rh.forwardToUploadOwner(rh.UpdateBlobUpload)).Methods(http.MethodPut)
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/blobs/uploads/{session_id}", zreg.NameRegexp.String()),
-			rh.DeleteBlobUpload).Methods(http.MethodDelete)
+			rh.forwardToUploadOwner(rh.DeleteBlobUpload)).Methods(http.MethodDelete)
 		// support for OCI artifact references
 		prefixedDistSpecRouter.HandleFunc(fmt.Sprintf("/{name:%s}/referrers/{digest}", zreg.NameRegexp.String()),
 			getUIHeadersHandler(rh.c.Config, http.MethodGet, http.MethodOptions)(
@@ -178,16 +203,17 @@ func (rh *RouteHandler) SetupRoutes() {
 	debug.SetupSwaggerRoutes(rh.c.Config, rh.c.Router, authHandler, rh.c.Log)
 	// gql playground
 	gqlPlayground.SetupGQLPlaygroundRoutes(prefixedRouter, rh.c.StoreController, rh.c.Log)
+	// pprof, expvar and goroutine dump
+	pprof.SetupPprofRoutes(rh.c.Config, prefixedRouter, rh.c.Log)
 
 	// Preconditions for enabling the actual extension routes are part of extensions themselves
 	ext.SetupMetricsRoutes(rh.c.Config, rh.c.Router, authHandler, rh.c.Log, rh.c.Metrics)
-	ext.SetupSearchRoutes(rh.c.Config, prefixedRouter, rh.c.StoreController, rh.c.MetaDB, rh.c.CveInfo,
-		rh.c.Log)
-	ext.SetupImageTrustRoutes(rh.c.Config, prefixedRouter, rh.c.MetaDB, rh.c.Log)
-	ext.SetupMgmtRoutes(rh.c.Config, prefixedRouter, rh.c.Log)
-	ext.SetupUserPreferencesRoutes(rh.c.Config, prefixedRouter, rh.c.MetaDB, rh.c.Log)
+
+	// the other extension routes are built again when the config is reloaded
+	rh.c.SetupExtensionRoutes()
+	prefixedRouter.MatcherFunc(rh.c.ExtensionRoutes.MatchPrefixed).HandlerFunc(rh.c.ExtensionRoutes.ServePrefixed)
 	// last should always be UI because it will setup a http.FileServer and paths will be resolved by this FileServer.
-	ext.SetupUIRoutes(rh.c.Config, rh.c.Router, rh.c.Log)
+	rh.c.Router.MatcherFunc(rh.c.ExtensionRoutes.MatchUI).HandlerFunc(rh.c.ExtensionRoutes.ServeUI)
 }
 
 func getCORSHeadersHandler(allowOrigin string) func(http.HandlerFunc) http.HandlerFunc {
@@ -220,6 +246,24 @@ func getUIHeadersHandler(config *config.Config, allowedMethods ...string) func(h
 
 // Method handlers
 
+// CheckReadiness godoc
+// @Summary Check readiness
+// @Description Check if the cache databases of the storage can be reached
+// @Router 	/readyz [get]
+// @Success 200 {string} string	"ok"
+// @Failure 503 {string} string	"service unavailable".
+func (rh *RouteHandler) CheckReadiness(response http.ResponseWriter, request *http.Request) {
+	if err := rh.c.StoreController.Ping(); err != nil {
+		rh.c.Log.Error().Err(err).Msg("failed to reach the cache database")
+
+		response.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
 // CheckVersionSupport godoc
 // @Summary Check API support
 // @Description Check if this API version is supported
@@ -439,6 +483,8 @@ func (rh *RouteHandler) CheckManifest(response http.ResponseWriter, request *htt
 		return
 	}
 
+	content, digest = rh.getManifestVariant(request, name, reference, content, digest, mediaType)
+
 	response.Header().Set(constants.DistContentDigestKey, digest.String())
 	response.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
 	response.Header().Set("Content-Type", mediaType)
@@ -512,6 +558,27 @@ func (rh *RouteHandler) GetManifest(response http.ResponseWriter, request *http.
 		return
 	}
 
+	err = ext.CheckImageTrustEnforcement(rh.c.Config, rh.c.MetaDB, name, reference, digest, content, rh.c.Log)
+	if err != nil {
+		if errors.Is(err, zerr.ErrImageNotTrusted) {
+			if rh.c.EventRecorder != nil {
+				rh.c.EventRecorder.ImageDenied(name, reference, digest.String(), mediaType, getUsername(request),
+					err.Error())
+			}
+
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(map[string]string{
+				"name":      name,
+				"reference": reference,
+				"reason":    err.Error(),
+			})
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+		} else {
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
 	if rh.c.MetaDB != nil {
 		err := meta.OnGetManifest(name, reference, content, rh.c.StoreController, rh.c.MetaDB, rh.c.Log)
 		if err != nil {
@@ -519,14 +586,61 @@ func (rh *RouteHandler) GetManifest(response http.ResponseWriter, request *http.
 
 			return
 		}
+
+		if rh.c.Config.AreDownloadStatsEnabled() {
+			meta.OnDownload(name, reference, digest, content, getDownloadClient(request), rh.c.MetaDB, rh.c.Log)
+		}
 	}
 
+	content, digest = rh.getManifestVariant(request, name, reference, content, digest, mediaType)
+
 	response.Header().Set(constants.DistContentDigestKey, digest.String())
 	response.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
 	response.Header().Set("Content-Type", mediaType)
 	zcommon.WriteData(response, http.StatusOK, mediaType, content)
 }
 
+// getManifestVariant returns the copy of the manifest with the zstd layers to the clients accepting them, or asking
+// for it with the compression=zstd query parameter, when the image is pulled by tag. The manifests pulled by digest
+// are always the pushed ones. The digest of the copy isn't the digest of the pushed manifest, so its signatures
+// aren't found by tag.
+func (rh *RouteHandler) getManifestVariant(request *http.Request, name, reference string, content []byte,
+	digest godigest.Digest, mediaType string,
+) ([]byte, godigest.Digest) {
+	if rh.c.Transcoder == nil || mediaType != ispec.MediaTypeImageManifest {
+		return content, digest
+	}
+
+	if _, err := godigest.Parse(reference); err == nil {
+		return content, digest
+	}
+
+	if !acceptsMediaType(request, ispec.MediaTypeImageLayerZstd) &&
+		request.URL.Query().Get(constants.CompressionQueryParam) != transcode.CompressionZstd {
+		return content, digest
+	}
+
+	if variant, variantDigest, ok := rh.c.Transcoder.GetVariant(name, digest); ok {
+		return variant, variantDigest
+	}
+
+	return content, digest
+}
+
+func acceptsMediaType(request *http.Request, mediaType string) bool {
+	for _, accept := range request.Header.Values("Accept") {
+		for _, accepted := range strings.Split(accept, ",") {
+			accepted, _, _ = strings.Cut(accepted, ";")
+
+			if strings.TrimSpace(accepted) == mediaType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 type ImageIndex struct {
 	ispec.Index
 }
@@ -537,7 +651,7 @@ func getReferrers(ctx context.Context, routeHandler *RouteHandler,
 ) (ispec.Index, error) {
 	refs, err := imgStore.GetReferrers(name, digest, artifactTypes)
 	if err != nil || len(refs.Manifests) == 0 {
-		if isSyncOnDemandEnabled(*routeHandler.c) {
+		if isSyncOnDemandEnabled(routeHandler.c) {
 			routeHandler.c.Log.Info().Str("repository", name).Str("reference", digest.String()).
 				Msg("referrers not found, trying to get reference by syncing on demand")
 
@@ -635,6 +749,7 @@ func (rh *RouteHandler) GetReferrers(response http.ResponseWriter, request *http
 // @Header  201 {object} constants.DistContentDigestKey
 // @Success 201 {string} string	"created"
 // @Failure 400 {string} string "bad request"
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "not found"
 // @Failure 500 {string} string "internal server error"
 // @Router /v2/{name}/manifests/{reference} [put].
@@ -648,8 +763,6 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 		return
 	}
 
-	imgStore := rh.getImageStore(name)
-
 	reference, ok := vars["reference"]
 	if !ok || reference == "" {
 		err := apiErr.NewError(apiErr.MANIFEST_INVALID).AddDetail(map[string]string{"reference": reference})
@@ -676,6 +789,50 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 		return
 	}
 
+	rh.putManifest(response, request, name, reference, mediaType, body)
+}
+
+// putManifest stores a manifest, pushed or composed by zot, and notifies the extensions about it.
+func (rh *RouteHandler) putManifest(response http.ResponseWriter, request *http.Request,
+	name, reference, mediaType string, body []byte,
+) {
+	imgStore := rh.getImageStore(name)
+
+	if rh.isReadOnly(response, imgStore, name) {
+		return
+	}
+
+	if rh.c.SecretScanner != nil {
+		if err := rh.c.SecretScanner.VerifyManifest(name, mediaType, body, imgStore); err != nil {
+			details := zerr.GetDetails(err)
+			details["reference"] = reference
+			e := apiErr.NewError(apiErr.MANIFEST_INVALID).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusBadRequest, apiErr.NewErrorList(e))
+
+			return
+		}
+	}
+
+	if err := ext.CheckSignaturePolicy(rh.c.Config, rh.c.MetaDB, name, reference, body, rh.c.Log); err != nil {
+		if errors.Is(err, zerr.ErrImageNotTrusted) {
+			if rh.c.EventRecorder != nil {
+				rh.c.EventRecorder.ImageDenied(name, reference, godigest.FromBytes(body).String(), mediaType,
+					getUsername(request), err.Error())
+			}
+
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(map[string]string{
+				"name":      name,
+				"reference": reference,
+				"reason":    err.Error(),
+			})
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+		} else {
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
 	digest, subjectDigest, err := imgStore.PutImageManifest(name, reference, mediaType, body)
 	if err != nil {
 		details := zerr.GetDetails(err)
@@ -703,6 +860,14 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 			details["reference"] = reference
 			e := apiErr.NewError(apiErr.MANIFEST_INVALID).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusBadRequest, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details["name"] = name
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrImmutableTag) {
+			details["name"] = name
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			// could be syscall.EMFILE (Err:0x18 too many opened files), etc
 			rh.c.Log.Error().Err(err).Msg("unexpected error: performing cleanup")
@@ -731,6 +896,28 @@ func (rh *RouteHandler) UpdateManifest(response http.ResponseWriter, request *ht
 		}
 	}
 
+	subject := ispec.Descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(body))}
+
+	if rh.c.SecretScanner != nil {
+		rh.c.SecretScanner.OnManifestPushed(name, subject, body)
+	}
+
+	if rh.c.Transcoder != nil {
+		rh.c.Transcoder.OnManifestPushed(name, subject, body)
+	}
+
+	if rh.c.EventRecorder != nil {
+		rh.c.EventRecorder.ImageUpdated(name, reference, digest.String(), mediaType, getUsername(request))
+	}
+
+	if rh.c.PluginManager != nil {
+		rh.c.PluginManager.ImagePushed(name, reference, digest.String(), mediaType, body, getUsername(request))
+	}
+
+	meta.RecordUserActivity(request.Context(), rh.c.Config, rh.c.MetaDB, mTypes.UserActivity{
+		Action: mTypes.UserActivityPush, Repo: name, Reference: reference,
+	}, rh.c.Log)
+
 	if subjectDigest.String() != "" {
 		response.Header().Set(constants.SubjectDigestKey, subjectDigest.String())
 	}
@@ -761,6 +948,10 @@ func (rh *RouteHandler) DeleteManifest(response http.ResponseWriter, request *ht
 
 	imgStore := rh.getImageStore(name)
 
+	if rh.isReadOnly(response, imgStore, name) {
+		return
+	}
+
 	reference, ok := vars["reference"]
 	if !ok || reference == "" {
 		response.WriteHeader(http.StatusNotFound)
@@ -846,6 +1037,18 @@ func (rh *RouteHandler) DeleteManifest(response http.ResponseWriter, request *ht
 		}
 	}
 
+	if rh.c.EventRecorder != nil {
+		rh.c.EventRecorder.ImageDeleted(name, reference, manifestDigest.String(), mediaType, getUsername(request))
+	}
+
+	if rh.c.PluginManager != nil {
+		rh.c.PluginManager.ImageDeleted(name, reference, manifestDigest.String(), mediaType, getUsername(request))
+	}
+
+	meta.RecordUserActivity(request.Context(), rh.c.Config, rh.c.MetaDB, mTypes.UserActivity{
+		Action: mTypes.UserActivityDelete, Repo: name, Reference: reference,
+	}, rh.c.Log)
+
 	response.WriteHeader(http.StatusAccepted)
 }
 
@@ -896,6 +1099,10 @@ func (rh *RouteHandler) CheckBlob(response http.ResponseWriter, request *http.Re
 			details["digest"] = digest.String()
 			e := apiErr.NewError(apiErr.BLOB_UNKNOWN).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details["name"] = name
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			rh.c.Log.Error().Err(err).Msg("unexpected error")
 			response.WriteHeader(http.StatusInternalServerError)
@@ -1103,6 +1310,10 @@ func (rh *RouteHandler) DeleteBlob(response http.ResponseWriter, request *http.R
 
 	imgStore := rh.getImageStore(name)
 
+	if rh.isReadOnly(response, imgStore, name) {
+		return
+	}
+
 	err = imgStore.DeleteBlob(name, digest)
 	if err != nil {
 		details := zerr.GetDetails(err)
@@ -1143,6 +1354,7 @@ func (rh *RouteHandler) DeleteBlob(response http.ResponseWriter, request *http.R
 // @Header  202 {string} Location "/v2/{name}/blobs/uploads/{session_id}"
 // @Header  202 {string} Range "0-0"
 // @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "not found"
 // @Failure 500 {string} string "internal server error"
 // @Router /v2/{name}/blobs/uploads [post].
@@ -1158,6 +1370,10 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 
 	imgStore := rh.getImageStore(name)
 
+	if rh.isReadOnly(response, imgStore, name) {
+		return
+	}
+
 	// currently zot does not support cross-repository mounting, following dist-spec and returning 202
 	if mountDigests, ok := request.URL.Query()["mount"]; ok {
 		if len(mountDigests) != 1 {
@@ -1171,6 +1387,15 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 		// check blob looks for actual path (name+mountDigests[0]) first then look for cache and
 		// if found in cache, will do hard link and if fails we will start new upload.
 		_, _, err := imgStore.CheckBlob(name, mountDigest)
+		if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details := zerr.GetDetails(err)
+			details["name"] = name
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+
+			return
+		}
+
 		if err != nil {
 			upload, err := imgStore.NewBlobUpload(name)
 			if err != nil {
@@ -1187,6 +1412,8 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 				return
 			}
 
+			rh.claimUpload(name, upload)
+
 			response.Header().Set("Location", getBlobUploadSessionLocation(request.URL, upload))
 			response.Header().Set("Range", "0-0")
 			response.WriteHeader(http.StatusAccepted)
@@ -1247,6 +1474,15 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 		}
 
 		sessionID, size, err := imgStore.FullBlobUpload(name, request.Body, digest)
+		if errors.Is(err, zerr.ErrQuotaExceeded) {
+			details := zerr.GetDetails(err)
+			details["name"] = name
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
+
+			return
+		}
+
 		if err != nil {
 			rh.c.Log.Error().Err(err).Int64("actual", size).Int64("expected", contentLength).Msg("failed full upload")
 			response.WriteHeader(http.StatusInternalServerError)
@@ -1283,6 +1519,8 @@ func (rh *RouteHandler) CreateBlobUpload(response http.ResponseWriter, request *
 		return
 	}
 
+	rh.claimUpload(name, upload)
+
 	response.Header().Set("Location", getBlobUploadSessionLocation(request.URL, upload))
 	response.Header().Set("Range", "0-0")
 	response.WriteHeader(http.StatusAccepted)
@@ -1361,6 +1599,7 @@ func (rh *RouteHandler) GetBlobUpload(response http.ResponseWriter, request *htt
 // @Header  202 {string} Range "0-128"
 // @Header  200 {object} api.BlobUploadUUID
 // @Failure 400 {string} string "bad request"
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "not found"
 // @Failure 416 {string} string "range not satisfiable"
 // @Failure 500 {string} string "internal server error"
@@ -1377,6 +1616,10 @@ func (rh *RouteHandler) PatchBlobUpload(response http.ResponseWriter, request *h
 
 	imgStore := rh.getImageStore(name)
 
+	if rh.isReadOnly(response, imgStore, name) {
+		return
+	}
+
 	sessionID, ok := vars["session_id"]
 	if !ok || sessionID == "" {
 		response.WriteHeader(http.StatusNotFound)
@@ -1427,6 +1670,13 @@ func (rh *RouteHandler) PatchBlobUpload(response http.ResponseWriter, request *h
 			details["session_id"] = sessionID
 			e := apiErr.NewError(apiErr.BLOB_UPLOAD_UNKNOWN).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			// the upload can't be finished anyway
+			_ = imgStore.DeleteBlobUpload(name, sessionID)
+
+			details["name"] = name
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			// could be io.ErrUnexpectedEOF, syscall.EMFILE (Err:0x18 too many opened files), etc
 			rh.c.Log.Error().Err(err).Msg("unexpected error: removing .uploads/ files")
@@ -1441,6 +1691,9 @@ func (rh *RouteHandler) PatchBlobUpload(response http.ResponseWriter, request *h
 		return
 	}
 
+	// the ownership of the session is extended while it's used
+	rh.claimUpload(name, sessionID)
+
 	response.Header().Set("Location", getBlobUploadSessionLocation(request.URL, sessionID))
 	response.Header().Set("Range", fmt.Sprintf("0-%d", clen-1))
 	response.Header().Set("Content-Length", "0")
@@ -1459,6 +1712,7 @@ func (rh *RouteHandler) PatchBlobUpload(response http.ResponseWriter, request *h
 // @Success 201 {string} string	"created"
 // @Header  202 {string} Location "/v2/{name}/blobs/uploads/{digest}"
 // @Header  200 {object} constants.DistContentDigestKey
+// @Failure 403 {string} string "forbidden"
 // @Failure 404 {string} string "not found"
 // @Failure 500 {string} string "internal server error"
 // @Router /v2/{name}/blobs/uploads/{session_id} [put].
@@ -1474,6 +1728,10 @@ func (rh *RouteHandler) UpdateBlobUpload(response http.ResponseWriter, request *
 
 	imgStore := rh.getImageStore(name)
 
+	if rh.isReadOnly(response, imgStore, name) {
+		return
+	}
+
 	sessionID, ok := vars["session_id"]
 	if !ok || sessionID == "" {
 		response.WriteHeader(http.StatusNotFound)
@@ -1551,6 +1809,13 @@ func (rh *RouteHandler) UpdateBlobUpload(response http.ResponseWriter, request *
 				details["session_id"] = sessionID
 				e := apiErr.NewError(apiErr.BLOB_UPLOAD_UNKNOWN).AddDetail(details)
 				zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+			} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+				// the upload can't be finished anyway
+				_ = imgStore.DeleteBlobUpload(name, sessionID)
+
+				details["name"] = name
+				e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+				zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 			} else {
 				// could be io.ErrUnexpectedEOF, syscall.EMFILE (Err:0x18 too many opened files), etc
 				rh.c.Log.Error().Err(err).Msg("unexpected error: removing .uploads/ files")
@@ -1586,6 +1851,13 @@ finish:
 			details["session_id"] = sessionID
 			e := apiErr.NewError(apiErr.BLOB_UPLOAD_UNKNOWN).AddDetail(details)
 			zcommon.WriteJSON(response, http.StatusNotFound, apiErr.NewErrorList(e))
+		} else if errors.Is(err, zerr.ErrQuotaExceeded) {
+			// the upload can't be finished anyway
+			_ = imgStore.DeleteBlobUpload(name, sessionID)
+
+			details["name"] = name
+			e := apiErr.NewError(apiErr.DENIED).AddDetail(details)
+			zcommon.WriteJSON(response, http.StatusForbidden, apiErr.NewErrorList(e))
 		} else {
 			// could be io.ErrUnexpectedEOF, syscall.EMFILE (Err:0x18 too many opened files), etc
 			rh.c.Log.Error().Err(err).Msg("unexpected error: removing .uploads/ files")
@@ -1600,6 +1872,8 @@ finish:
 		return
 	}
 
+	rh.releaseUpload(name, sessionID)
+
 	response.Header().Set("Location", getBlobUploadLocation(request.URL, name, digest))
 	response.Header().Set("Content-Length", "0")
 	response.Header().Set(constants.DistContentDigestKey, digest.String())
@@ -1629,6 +1903,10 @@ func (rh *RouteHandler) DeleteBlobUpload(response http.ResponseWriter, request *
 
 	imgStore := rh.getImageStore(name)
 
+	if rh.isReadOnly(response, imgStore, name) {
+		return
+	}
+
 	sessionID, ok := vars["session_id"]
 	if !ok || sessionID == "" {
 		response.WriteHeader(http.StatusNotFound)
@@ -1654,6 +1932,8 @@ func (rh *RouteHandler) DeleteBlobUpload(response http.ResponseWriter, request *
 		return
 	}
 
+	rh.releaseUpload(name, sessionID)
+
 	response.WriteHeader(http.StatusNoContent)
 }
 
@@ -1893,6 +2173,18 @@ func WriteDataFromReader(response http.ResponseWriter, status int, length int64,
 	}
 }
 
+// isReadOnly rejects the pushes and deletes of the clients in the read-only image stores.
+func (rh *RouteHandler) isReadOnly(response http.ResponseWriter, imgStore storageTypes.ImageStore, name string) bool {
+	if !imgStore.IsReadOnly() {
+		return false
+	}
+
+	e := apiErr.NewError(apiErr.UNSUPPORTED).AddDetail(map[string]string{"name": name, "reason": "read only storage"})
+	zcommon.WriteJSON(response, http.StatusMethodNotAllowed, apiErr.NewErrorList(e))
+
+	return true
+}
+
 // will return image storage corresponding to subpath provided in config.
 func (rh *RouteHandler) getImageStore(name string) storageTypes.ImageStore {
 	return rh.c.StoreController.GetImageStore(name)
@@ -1902,7 +2194,7 @@ func (rh *RouteHandler) getImageStore(name string) storageTypes.ImageStore {
 func getImageManifest(ctx context.Context, routeHandler *RouteHandler, imgStore storageTypes.ImageStore, name,
 	reference string,
 ) ([]byte, godigest.Digest, string, error) {
-	syncEnabled := isSyncOnDemandEnabled(*routeHandler.c)
+	syncEnabled := isSyncOnDemandEnabled(routeHandler.c)
 
 	_, digestErr := godigest.Parse(reference)
 	if digestErr == nil {
@@ -1933,7 +2225,7 @@ func getOrasReferrers(ctx context.Context, routeHandler *RouteHandler,
 ) ([]artifactspec.Descriptor, error) {
 	refs, err := imgStore.GetOrasReferrers(name, digest, artifactType)
 	if err != nil {
-		if isSyncOnDemandEnabled(*routeHandler.c) {
+		if isSyncOnDemandEnabled(routeHandler.c) {
 			routeHandler.c.Log.Info().Str("repository", name).Str("reference", digest.String()).
 				Msg("artifact not found, trying to get artifact by syncing on demand")
 
@@ -2097,15 +2389,6 @@ func (rh *RouteHandler) CreateAPIKey(resp http.ResponseWriter, req *http.Request
 		return
 	}
 
-	apiKey, apiKeyID, err := GenerateAPIKey(guuid.DefaultGenerator, rh.c.Log)
-	if err != nil {
-		resp.WriteHeader(http.StatusInternalServerError)
-
-		return
-	}
-
-	hashedAPIKey := hashUUID(apiKey)
-
 	createdAt := time.Now()
 
 	// won't expire if no value provided
@@ -2126,36 +2409,16 @@ func (rh *RouteHandler) CreateAPIKey(resp http.ResponseWriter, req *http.Request
 		}
 	}
 
-	apiKeyDetails := &mTypes.APIKeyDetails{
-		CreatedAt:      createdAt,
-		ExpirationDate: expirationDate,
-		IsExpired:      false,
-		CreatorUA:      req.UserAgent(),
-		GeneratedBy:    "manual",
-		Label:          payload.Label,
-		Scopes:         payload.Scopes,
-		UUID:           apiKeyID,
-	}
-
-	err = rh.c.MetaDB.AddUserAPIKey(req.Context(), hashedAPIKey, apiKeyDetails)
+	createdAPIKey, err := rh.addUserAPIKey(req, payload.Label, payload.Scopes, expirationDate, "manual")
 	if err != nil {
-		rh.c.Log.Error().Err(err).Msg("error storing API key")
 		resp.WriteHeader(http.StatusInternalServerError)
 
 		return
 	}
 
-	apiKeyResponse := struct {
-		mTypes.APIKeyDetails
-		APIKey string `json:"apiKey"`
-	}{
-		APIKey:        fmt.Sprintf("%s%s", constants.APIKeysPrefix, apiKey),
-		APIKeyDetails: *apiKeyDetails,
-	}
-
 	json := jsoniter.ConfigCompatibleWithStandardLibrary
 
-	data, err := json.Marshal(apiKeyResponse)
+	data, err := json.Marshal(createdAPIKey)
 	if err != nil {
 		rh.c.Log.Error().Err(err).Msg("unable to marshal api key response")
 
@@ -2169,6 +2432,51 @@ func (rh *RouteHandler) CreateAPIKey(resp http.ResponseWriter, req *http.Request
 	_, _ = resp.Write(data)
 }
 
+type generatedAPIKey struct {
+	mTypes.APIKeyDetails
+	APIKey string `json:"apiKey"`
+}
+
+// addUserAPIKey generates a new API key for the user of the request, the key itself is returned
+// only once, only its hash is stored.
+func (rh *RouteHandler) addUserAPIKey(req *http.Request, label string, scopes []string,
+	expirationDate time.Time, generatedBy string,
+) (generatedAPIKey, error) {
+	apiKey, apiKeyID, err := GenerateAPIKey(guuid.DefaultGenerator, rh.c.Log)
+	if err != nil {
+		return generatedAPIKey{}, err
+	}
+
+	hashedAPIKey := hashUUID(apiKey)
+
+	apiKeyDetails := &mTypes.APIKeyDetails{
+		CreatedAt:      time.Now(),
+		ExpirationDate: expirationDate,
+		IsExpired:      false,
+		CreatorUA:      req.UserAgent(),
+		GeneratedBy:    generatedBy,
+		Label:          label,
+		Scopes:         scopes,
+		UUID:           apiKeyID,
+	}
+
+	err = rh.c.MetaDB.AddUserAPIKey(req.Context(), hashedAPIKey, apiKeyDetails)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Msg("error storing API key")
+
+		return generatedAPIKey{}, err
+	}
+
+	meta.RecordUserActivity(req.Context(), rh.c.Config, rh.c.MetaDB, mTypes.UserActivity{
+		Action: mTypes.UserActivityAPIKeyCreate, Details: label,
+	}, rh.c.Log)
+
+	return generatedAPIKey{
+		APIKey:        fmt.Sprintf("%s%s", constants.APIKeysPrefix, apiKey),
+		APIKeyDetails: *apiKeyDetails,
+	}, nil
+}
+
 // RevokeAPIKey godoc
 // @Summary Revokes one current user API key
 // @Description Revokes one current user API key based on given key ID
@@ -2198,9 +2506,125 @@ func (rh *RouteHandler) RevokeAPIKey(resp http.ResponseWriter, req *http.Request
 		return
 	}
 
+	meta.RecordUserActivity(req.Context(), rh.c.Config, rh.c.MetaDB, mTypes.UserActivity{
+		Action: mTypes.UserActivityAPIKeyRevoke, Details: keyID,
+	}, rh.c.Log)
+
 	resp.WriteHeader(http.StatusOK)
 }
 
+// defaultUserActivityRange is the time range of the returned activity if the request doesn't tell.
+const defaultUserActivityRange = 30 * 24 * time.Hour
+
+// GetUserActivity godoc
+// @Summary Get the activity of a user
+// @Description Get the pushes, deletes, stars and api keys of the current user, newest first, admins can get
+// @Description the activity of any user
+// @Accept  json
+// @Produce json
+// @Param   user   query  string  false  "user, defaults to the current user"
+// @Param   from   query  string  false  "RFC 3339 start of the time range, defaults to 30 days ago"
+// @Param   to     query  string  false  "RFC 3339 end of the time range, defaults to now"
+// @Param   limit  query  int     false  "maximum number of actions returned"
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /auth/activity  [get].
+func (rh *RouteHandler) GetUserActivity(resp http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodOptions {
+		return
+	}
+
+	userAc, err := reqCtx.UserAcFromContext(req.Context())
+	if err != nil || userAc == nil || userAc.IsAnonymous() {
+		resp.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	query := req.URL.Query()
+
+	user := userAc.GetUsername()
+	if queryUser := query.Get("user"); queryUser != "" && queryUser != user {
+		if !userAc.IsAdmin() {
+			resp.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		user = queryUser
+	}
+
+	to := time.Now()
+	from := to.Add(-defaultUserActivityRange)
+
+	if queryFrom := query.Get("from"); queryFrom != "" {
+		if from, err = time.Parse(time.RFC3339, queryFrom); err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	if queryTo := query.Get("to"); queryTo != "" {
+		if to, err = time.Parse(time.RFC3339, queryTo); err != nil {
+			resp.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	limit := 0
+
+	if queryLimit := query.Get("limit"); queryLimit != "" {
+		if limit, err = strconv.Atoi(queryLimit); err != nil || limit < 0 {
+			resp.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	if from.After(to) {
+		resp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	activity, err := rh.c.MetaDB.GetUserActivity(req.Context(), user, from, to)
+	if err != nil {
+		rh.c.Log.Error().Err(err).Str("user", user).Msg("error getting the user activity")
+
+		if errors.Is(err, zerr.ErrUserActivityDisabled) {
+			resp.WriteHeader(http.StatusNotImplemented)
+		} else {
+			resp.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	// the newest actions are the most interesting ones
+	for i, j := 0, len(activity)-1; i < j; i, j = i+1, j-1 {
+		activity[i], activity[j] = activity[j], activity[i]
+	}
+
+	if limit > 0 && len(activity) > limit {
+		activity = activity[:limit]
+	}
+
+	if activity == nil {
+		activity = []mTypes.UserActivity{}
+	}
+
+	zcommon.WriteJSON(resp, http.StatusOK, struct {
+		Activity []mTypes.UserActivity `json:"activity"`
+	}{
+		Activity: activity,
+	})
+}
+
 // GetBlobUploadSessionLocation returns actual blob location to start/resume uploading blobs.
 // e.g. /v2/<name>/blobs/uploads/<session-id>.
 func getBlobUploadSessionLocation(url *url.URL, sessionID string) string {
@@ -2229,7 +2653,7 @@ func getBlobUploadLocation(url *url.URL, name string, digest godigest.Digest) st
 	return url.String()
 }
 
-func isSyncOnDemandEnabled(ctlr Controller) bool {
+func isSyncOnDemandEnabled(ctlr *Controller) bool {
 	if ctlr.Config.IsSyncEnabled() &&
 		fmt.Sprintf("%v", ctlr.SyncOnDemand) != fmt.Sprintf("%v", nil) {
 		return true
@@ -2237,3 +2661,28 @@ func isSyncOnDemandEnabled(ctlr Controller) bool {
 
 	return false
 }
+
+// getDownloadClient identifies the client pulling an image in the download statistics, the user
+// if authenticated, the address of the client otherwise.
+func getDownloadClient(request *http.Request) string {
+	if username := getUsername(request); username != "" {
+		return username
+	}
+
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+
+	return host
+}
+
+// getUsername returns the authenticated user of a request, empty for anonymous requests.
+func getUsername(request *http.Request) string {
+	userAc, err := reqCtx.UserAcFromContext(request.Context())
+	if err != nil || userAc == nil {
+		return ""
+	}
+
+	return userAc.GetUsername()
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"encoding/base64"
 	"net/http"
 	"strconv"
@@ -10,8 +11,10 @@ import (
 	"github.com/didip/tollbooth/v6"
 	"github.com/gorilla/mux"
 
+	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
 	"zotregistry.io/zot/pkg/log"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
 )
 
 type statusWriter struct {
@@ -119,6 +122,7 @@ func SessionLogger(ctlr *Controller) mux.MiddlewareFunc {
 				monitoring.IncHTTPConnRequests(ctlr.Metrics, method, strconv.Itoa(statusCode))
 				monitoring.ObserveHTTPRepoLatency(ctlr.Metrics, path, latency)     // summary
 				monitoring.ObserveHTTPMethodLatency(ctlr.Metrics, method, latency) // histogram
+				monitoring.ObserveHTTPRepoTraffic(ctlr.Metrics, method, path, statusCode, bodySize)
 			}
 
 			log.Str("clientIP", clientIP).
@@ -133,52 +137,188 @@ func SessionLogger(ctlr *Controller) mux.MiddlewareFunc {
 	}
 }
 
-func SessionAuditLogger(audit *log.Logger) mux.MiddlewareFunc {
+// SessionAccessLogger writes one access log line per request, with the optional fields of the config.
+func SessionAccessLogger(access *log.AccessLogger, accessLogConfig *config.AccessLogConfig,
+	logger log.Logger,
+) mux.MiddlewareFunc {
+	fields := map[string]bool{}
+	for _, field := range accessLogConfig.Fields {
+		fields[field] = true
+	}
+
+	errorFields := map[string]bool{}
+	for _, field := range accessLogConfig.ErrorFields {
+		errorFields[field] = true
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
-			path := request.URL.Path
-			raw := request.URL.RawQuery
+			start := time.Now()
 
-			statusWr := statusWriter{ResponseWriter: response}
+			stwr := statusWriter{ResponseWriter: response}
 
 			// Process request
-			next.ServeHTTP(&statusWr, request)
+			next.ServeHTTP(&stwr, request)
 
-			clientIP := request.RemoteAddr
+			statusCode := stwr.status
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+
+			record := log.AccessRecord{
+				Time:     start,
+				Method:   request.Method,
+				Path:     request.URL.Path,
+				Repo:     mux.Vars(request)["name"],
+				Identity: getRequestIdentity(request),
+				Status:   statusCode,
+				Bytes:    stwr.length,
+				Duration: time.Since(start).Seconds(),
+			}
+
+			isError := statusCode >= http.StatusBadRequest
+
+			hasField := func(field string) bool {
+				return fields[field] || (isError && errorFields[field])
+			}
+
+			if hasField(log.AccessFieldClientIP) {
+				record.ClientIP = request.RemoteAddr
+			}
+
+			if hasField(log.AccessFieldUserAgent) {
+				record.UserAgent = request.UserAgent()
+			}
+
+			if hasField(log.AccessFieldQuery) {
+				record.Query = request.URL.RawQuery
+			}
+
+			if hasField(log.AccessFieldReferer) {
+				record.Referer = request.Referer()
+			}
+
+			if hasField(log.AccessFieldHeaders) {
+				// the credentials are masked by the logger
+				record.Headers = request.Header
+			}
+
+			if err := access.Log(record); err != nil {
+				logger.Error().Err(err).Str("method", request.Method).Str("path", request.URL.Path).
+					Msg("failed to write the access log")
+			}
+		})
+	}
+}
+
+// getRequestIdentity returns the name of the authenticated user, empty if anonymous.
+func getRequestIdentity(request *http.Request) string {
+	// saved on the request by the authentication
+	if userAc, err := reqCtx.UserAcFromContext(request.Context()); err == nil {
+		if username := userAc.GetUsername(); username != "" {
+			return username
+		}
+	}
+
+	return getBasicAuthUsername(request)
+}
+
+// auditResponseWriter holds the response of a mutating request until its audit record is written.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.body.Write(b)
+}
+
+// SessionAuditLogger writes the audit records of the successful mutating requests. The response is only sent
+// once the record is written, the request fails if it can't be.
+func SessionAuditLogger(audit *log.AuditLogger, logger log.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
 			method := request.Method
-			username := ""
 
-			for key, value := range request.Header {
-				if key == "Authorization" { // anonymize from logs
-					s := strings.SplitN(value[0], " ", 2) //nolint:gomnd
-					if len(s) == 2 && strings.EqualFold(s[0], "basic") {
-						b, err := base64.StdEncoding.DecodeString(s[1])
-						if err == nil {
-							pair := strings.SplitN(string(b), ":", 2) //nolint:gomnd
-							if len(pair) == 2 {                       //nolint:gomnd
-								username = pair[0]
-							}
-						}
-					}
-				}
+			if method != http.MethodPost && method != http.MethodPut &&
+				method != http.MethodPatch && method != http.MethodDelete {
+				next.ServeHTTP(response, request)
+
+				return
+			}
+
+			path := request.URL.Path
+			raw := request.URL.RawQuery
+
+			auditWr := auditResponseWriter{ResponseWriter: response}
+
+			// Process request
+			next.ServeHTTP(&auditWr, request)
+
+			statusCode := auditWr.status
+			if statusCode == 0 {
+				statusCode = http.StatusOK
 			}
 
-			statusCode := statusWr.status
 			if raw != "" {
 				path = path + "?" + raw
 			}
 
-			if (method == http.MethodPost || method == http.MethodPut ||
-				method == http.MethodPatch || method == http.MethodDelete) &&
-				(statusCode == http.StatusOK || statusCode == http.StatusCreated || statusCode == http.StatusAccepted) {
-				audit.Info().
-					Str("clientIP", clientIP).
-					Str("subject", username).
-					Str("action", method).
-					Str("object", path).
-					Int("status", statusCode).
-					Msg("HTTP API Audit")
+			if statusCode == http.StatusOK || statusCode == http.StatusCreated || statusCode == http.StatusAccepted {
+				err := audit.Log(log.AuditRecord{
+					ClientIP: request.RemoteAddr,
+					Subject:  getBasicAuthUsername(request),
+					Action:   method,
+					Object:   path,
+					Status:   statusCode,
+				})
+				if err != nil {
+					logger.Error().Err(err).Str("method", method).Str("path", path).
+						Msg("failed to write the audit record")
+
+					for key := range response.Header() {
+						response.Header().Del(key)
+					}
+
+					response.WriteHeader(http.StatusInternalServerError)
+
+					return
+				}
 			}
+
+			response.WriteHeader(statusCode)
+			_, _ = response.Write(auditWr.body.Bytes())
 		})
 	}
 }
+
+// getBasicAuthUsername returns the username of the basic auth header, empty if there isn't any.
+func getBasicAuthUsername(request *http.Request) string {
+	s := strings.SplitN(request.Header.Get("Authorization"), " ", 2) //nolint:gomnd
+	if len(s) != 2 || !strings.EqualFold(s[0], "basic") {            //nolint:gomnd
+		return ""
+	}
+
+	b, err := base64.StdEncoding.DecodeString(s[1])
+	if err != nil {
+		return ""
+	}
+
+	pair := strings.SplitN(string(b), ":", 2) //nolint:gomnd
+	if len(pair) != 2 {                       //nolint:gomnd
+		return ""
+	}
+
+	return pair[0]
+}
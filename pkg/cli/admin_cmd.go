@@ -0,0 +1,226 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+	"zotregistry.io/zot/pkg/maintenance"
+)
+
+func NewAdminCommand(searchService SearchService) *cobra.Command {
+	adminCmd := &cobra.Command{
+		Use:   "admin [command]",
+		Short: "Run and pause the maintenance jobs of the zot server",
+		Long: `Run and pause the garbage collection, scrub, dedupe and reconciliation of the metadata of the zot server,
+and follow their progress. The jobs are managed through the mgmt extension, only by the admins`,
+		RunE: ShowSuggestionsIfUnknownCommand,
+	}
+
+	adminCmd.SetUsageTemplate(adminCmd.UsageTemplate() + usageFooter)
+
+	adminCmd.PersistentFlags().String(cmdflags.URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	adminCmd.PersistentFlags().String(cmdflags.ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	adminCmd.PersistentFlags().StringP(cmdflags.UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	adminCmd.PersistentFlags().StringP(cmdflags.OutputFormatFlag, "f", "", "Specify output format [text/json/yaml]")
+	adminCmd.PersistentFlags().Bool(cmdflags.DebugFlag, false, "Show debug output")
+
+	adminCmd.AddCommand(NewAdminStatusCommand(searchService))
+	gcCmd := NewMaintenanceJobCommand(searchService, maintenance.GC, "the garbage collection")
+	gcCmd.AddCommand(NewGCPreviewCommand(searchService))
+
+	adminCmd.AddCommand(gcCmd)
+	adminCmd.AddCommand(NewMaintenanceJobCommand(searchService, maintenance.Scrub, "the scrub"))
+	adminCmd.AddCommand(NewMaintenanceJobCommand(searchService, maintenance.Dedupe,
+		"the dedupe, or the restore of the deduped blobs"))
+	adminCmd.AddCommand(NewMaintenanceJobCommand(searchService, maintenance.Reconcile,
+		"the reconciliation of the metadata with the storage"))
+	adminCmd.AddCommand(NewMetaDBCommand(searchService))
+
+	return adminCmd
+}
+
+func NewMetaDBCommand(searchService SearchService) *cobra.Command {
+	metaDBCmd := &cobra.Command{
+		Use:   "metadb [command]",
+		Short: "Back up and restore the metadata database of the zot server",
+		Long: `Back up and restore the stars, bookmarks, statistics and signatures state of the images, the image
+storage is backed up separately`,
+		RunE: ShowSuggestionsIfUnknownCommand,
+	}
+
+	backupCmd := &cobra.Command{
+		Use:   "backup [file]",
+		Short: "Save a backup of the metadata database",
+		Long: `Save a consistent snapshot of the metadata database to the file, the file is only written once the
+whole backup is received`,
+		Example: "  zli admin metadb backup metadb.backup.gz --config local",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return BackupMetaDB(searchConfig, args[0])
+		},
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore [file]",
+		Short: "Restore the metadata database from a backup",
+		Long: `Replace the metadata database of the server with the backup in the file, the current metadata is lost.
+The database is left unchanged if the backup is invalid`,
+		Example: `  # restore the backup without confirming it
+  zli admin metadb restore metadb.backup.gz --force --config local`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			force := defaultIfError(cmd.Flags().GetBool(cmdflags.ForceFlag))
+
+			return RestoreMetaDB(searchConfig, args[0], force, cmd.InOrStdin())
+		},
+	}
+
+	restoreCmd.Flags().Bool(cmdflags.ForceFlag, false, "Restore without asking for confirmation")
+
+	metaDBCmd.AddCommand(backupCmd)
+	metaDBCmd.AddCommand(restoreCmd)
+
+	return metaDBCmd
+}
+
+func NewGCPreviewCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Show what the garbage collection would remove",
+		Long: `Run the garbage collection without removing anything and show the manifests and blobs it would
+remove, and the space it would free, of all the repos or of a single one`,
+		Example: `  zli admin gc preview --config local
+  zli admin gc preview --repo alpine --format json --config local`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			repo := defaultIfError(cmd.Flags().GetString(cmdflags.RepoFlag))
+
+			return PreviewGC(searchConfig, repo)
+		},
+	}
+
+	cmd.Flags().String(cmdflags.RepoFlag, "", "Only preview the garbage collection of this repository")
+
+	return cmd
+}
+
+func NewAdminStatusCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of all the maintenance jobs",
+		Long:  `Show the status of the maintenance jobs enabled on the server, with the progress of their last run`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return ShowMaintenanceJobs(searchConfig, "")
+		},
+	}
+
+	return cmd
+}
+
+// NewMaintenanceJobCommand returns the command managing one of the maintenance jobs, its status is shown
+// if no subcommand is given.
+func NewMaintenanceJobCommand(searchService SearchService, job, description string) *cobra.Command {
+	jobCmd := &cobra.Command{
+		Use:   job + " [command]",
+		Short: fmt.Sprintf("Run, pause or show the status of %s", description),
+		Long:  fmt.Sprintf("Run, pause or show the status of %s", description),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return ShowMaintenanceJobs(searchConfig, job)
+		},
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: fmt.Sprintf("Start a run of %s", description),
+		Long: fmt.Sprintf(`Start a run of %s, unless it's paused or already running.
+With '--%s' the progress is printed until the run is finished`, description, cmdflags.WaitFlag),
+		Example: fmt.Sprintf("  zli admin %s run --wait --config local", job),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			wait := defaultIfError(cmd.Flags().GetBool(cmdflags.WaitFlag))
+
+			return RunMaintenanceJob(searchConfig, job, wait)
+		},
+	}
+
+	runCmd.Flags().Bool(cmdflags.WaitFlag, false, "Print the progress until the run is finished")
+
+	for _, action := range []struct {
+		name  string
+		short string
+		long  string
+	}{
+		{
+			name:  maintenanceActionPause,
+			short: "Pause " + description,
+			long: fmt.Sprintf(`Pause %s, no new task is started until it's resumed.
+The tasks already queued still run`, description),
+		},
+		{
+			name:  maintenanceActionResume,
+			short: "Resume " + description,
+			long:  fmt.Sprintf("Resume %s, an interrupted run continues where it stopped", description),
+		},
+	} {
+		action := action
+
+		jobCmd.AddCommand(&cobra.Command{
+			Use:   action.name,
+			Short: action.short,
+			Long:  action.long,
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+				if err != nil {
+					return err
+				}
+
+				return SetMaintenanceJobAction(searchConfig, job, action.name)
+			},
+		})
+	}
+
+	jobCmd.AddCommand(runCmd)
+
+	return jobCmd
+}
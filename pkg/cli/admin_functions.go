@@ -0,0 +1,344 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v2"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	"zotregistry.io/zot/pkg/maintenance"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	maintenanceActionRun    = "run"
+	maintenanceActionPause  = "pause"
+	maintenanceActionResume = "resume"
+
+	maintenanceTimeFormat = "2006-01-02 15:04:05"
+)
+
+// maintenanceWaitInterval is the interval the status of a job is polled at while waiting for its run to finish.
+var maintenanceWaitInterval = 2 * time.Second //nolint:gochecknoglobals // changed by the tests
+
+type maintenanceJobs struct {
+	Jobs []maintenance.Status `json:"jobs"`
+}
+
+type gcPreview struct {
+	Repos      []storageTypes.GCReport `json:"repos"`
+	BytesFreed int64                   `json:"bytesFreed"`
+}
+
+// PreviewGC prints what the garbage collection would remove from the repo, or from all the repos if it's empty.
+func PreviewGC(config searchConfig, repo string) error {
+	username, password := getUsernameAndPassword(config.user)
+
+	previewURL, err := combineServerAndEndpointURL(config.servURL, constants.FullMgmtMaintenance+"/gc/preview")
+	if err != nil {
+		return err
+	}
+
+	if repo != "" {
+		previewURL += "?" + url.Values{"repo": []string{repo}}.Encode()
+	}
+
+	preview := gcPreview{}
+
+	if _, err := makeGETRequest(context.Background(), previewURL, username, password, config.verifyTLS,
+		config.debug, &preview, config.resultWriter); err != nil {
+		return err
+	}
+
+	return printGCPreview(config, preview)
+}
+
+// ShowMaintenanceJobs prints the status of the job, or of all the jobs enabled on the server.
+func ShowMaintenanceJobs(config searchConfig, job string) error {
+	username, password := getUsernameAndPassword(config.user)
+
+	jobs, err := getMaintenanceJobs(context.Background(), config, username, password)
+	if err != nil {
+		return err
+	}
+
+	if job == "" {
+		return printMaintenanceJobs(config, jobs.Jobs)
+	}
+
+	status, err := findMaintenanceJob(jobs, job)
+	if err != nil {
+		return err
+	}
+
+	return printMaintenanceJobs(config, []maintenance.Status{status})
+}
+
+// RunMaintenanceJob starts a run of the job, printing its progress until it's finished if wait is set.
+func RunMaintenanceJob(config searchConfig, job string, wait bool) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	jobs, err := getMaintenanceJobs(ctx, config, username, password)
+	if err != nil {
+		return err
+	}
+
+	status, err := findMaintenanceJob(jobs, job)
+	if err != nil {
+		return err
+	}
+
+	// checked beforehand, the conflicts are only reported with a status code by the server
+	if status.Paused {
+		return fmt.Errorf("%w: %s, resume it first", zerr.ErrMaintenanceJobPaused, job)
+	}
+
+	if status.Running {
+		return fmt.Errorf("%w: %s", zerr.ErrMaintenanceJobRunning, job)
+	}
+
+	status, err = postMaintenanceJobAction(ctx, config, username, password, job, maintenanceActionRun)
+	if err != nil {
+		return err
+	}
+
+	if !wait {
+		return printMaintenanceJobs(config, []maintenance.Status{status})
+	}
+
+	isText := isDefaultOutputFormat(config.outputFormat)
+	progress := ""
+
+	for status.Running {
+		if isText {
+			if line := formatMaintenanceProgress(status); line != progress {
+				progress = line
+				fmt.Fprintln(config.resultWriter, line)
+			}
+		}
+
+		time.Sleep(maintenanceWaitInterval)
+
+		jobs, err = getMaintenanceJobs(ctx, config, username, password)
+		if err != nil {
+			return err
+		}
+
+		if status, err = findMaintenanceJob(jobs, job); err != nil {
+			return err
+		}
+	}
+
+	return printMaintenanceJobs(config, []maintenance.Status{status})
+}
+
+// SetMaintenanceJobAction pauses or resumes the job.
+func SetMaintenanceJobAction(config searchConfig, job, action string) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	jobs, err := getMaintenanceJobs(ctx, config, username, password)
+	if err != nil {
+		return err
+	}
+
+	if _, err := findMaintenanceJob(jobs, job); err != nil {
+		return err
+	}
+
+	status, err := postMaintenanceJobAction(ctx, config, username, password, job, action)
+	if err != nil {
+		return err
+	}
+
+	return printMaintenanceJobs(config, []maintenance.Status{status})
+}
+
+func getMaintenanceJobs(ctx context.Context, config searchConfig, username, password string,
+) (maintenanceJobs, error) {
+	jobsURL, err := combineServerAndEndpointURL(config.servURL, constants.FullMgmtMaintenance)
+	if err != nil {
+		return maintenanceJobs{}, err
+	}
+
+	jobs := maintenanceJobs{}
+
+	if _, err := makeGETRequest(ctx, jobsURL, username, password, config.verifyTLS, config.debug, &jobs,
+		config.resultWriter); err != nil {
+		return maintenanceJobs{}, err
+	}
+
+	return jobs, nil
+}
+
+func postMaintenanceJobAction(ctx context.Context, config searchConfig, username, password, job, action string,
+) (maintenance.Status, error) {
+	actionURL, err := combineServerAndEndpointURL(config.servURL,
+		fmt.Sprintf("%s/%s/%s", constants.FullMgmtMaintenance, job, action))
+	if err != nil {
+		return maintenance.Status{}, err
+	}
+
+	status := maintenance.Status{}
+
+	if err := makePOSTRequest(ctx, actionURL, username, password, nil, config.verifyTLS, config.debug, &status,
+		config.resultWriter); err != nil {
+		return maintenance.Status{}, err
+	}
+
+	return status, nil
+}
+
+func findMaintenanceJob(jobs maintenanceJobs, job string) (maintenance.Status, error) {
+	for _, status := range jobs.Jobs {
+		if status.Job == job {
+			return status, nil
+		}
+	}
+
+	return maintenance.Status{}, fmt.Errorf("%w: %s isn't enabled on the server", zerr.ErrMaintenanceJobNotFound,
+		job)
+}
+
+func getMaintenanceState(status maintenance.Status) string {
+	switch {
+	case status.Paused && status.Running:
+		// the queued tasks are still running
+		return "pausing"
+	case status.Paused:
+		return "paused"
+	case status.Running:
+		return "running"
+	default:
+		return "idle"
+	}
+}
+
+func formatMaintenanceProgress(status maintenance.Status) string {
+	return fmt.Sprintf("%s: %s, %d tasks done, %d failed, %d queued", status.Job, getMaintenanceState(status),
+		status.TasksDone, status.TasksFailed, status.TasksQueued)
+}
+
+func formatMaintenanceTime(timestamp *time.Time) string {
+	if timestamp == nil {
+		return "-"
+	}
+
+	return timestamp.Local().Format(maintenanceTimeFormat)
+}
+
+func isDefaultOutputFormat(format string) bool {
+	return format == "" || strings.ToLower(format) == defaultOutputFormat
+}
+
+func printMaintenanceJobs(config searchConfig, statuses []maintenance.Status) error {
+	switch strings.ToLower(config.outputFormat) {
+	case "", defaultOutputFormat:
+		table := getImageTableWriter(config.resultWriter)
+
+		table.SetHeader([]string{"JOB", "STATE", "TRIGGER", "STARTED", "FINISHED", "DONE", "FAILED", "QUEUED",
+			"LAST ERROR"})
+
+		for _, status := range statuses {
+			trigger := status.Trigger
+			if trigger == "" {
+				trigger = "-"
+			}
+
+			table.Append([]string{
+				status.Job,
+				getMaintenanceState(status),
+				trigger,
+				formatMaintenanceTime(status.StartedAt),
+				formatMaintenanceTime(status.FinishedAt),
+				strconv.Itoa(status.TasksDone),
+				strconv.Itoa(status.TasksFailed),
+				strconv.Itoa(status.TasksQueued),
+				status.LastError,
+			})
+		}
+
+		table.Render()
+	case jsonFormat:
+		// one job per line, like the other json outputs
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+		for _, status := range statuses {
+			body, err := json.Marshal(status)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(config.resultWriter, string(body))
+		}
+	case ymlFormat, yamlFormat:
+		for _, status := range statuses {
+			body, err := yaml.Marshal(status)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(config.resultWriter, "---\n"+string(body))
+		}
+	default:
+		return zerr.ErrInvalidOutputFormat
+	}
+
+	return nil
+}
+
+func printGCPreview(config searchConfig, preview gcPreview) error {
+	switch strings.ToLower(config.outputFormat) {
+	case "", defaultOutputFormat:
+		table := getImageTableWriter(config.resultWriter)
+
+		table.SetHeader([]string{"REPOSITORY", "MANIFESTS", "BLOBS", "SIZE", "REPO REMOVED"})
+
+		for _, report := range preview.Repos {
+			table.Append([]string{
+				report.Repo,
+				strconv.Itoa(len(report.Manifests)),
+				strconv.Itoa(len(report.Blobs)),
+				humanize.Bytes(uint64(report.BytesFreed)),
+				strconv.FormatBool(report.RepoRemoved),
+			})
+		}
+
+		table.Render()
+
+		fmt.Fprintf(config.resultWriter, "%d repositories, %s would be freed\n", len(preview.Repos),
+			humanize.Bytes(uint64(preview.BytesFreed)))
+	case jsonFormat:
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+		body, err := json.Marshal(preview)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(config.resultWriter, string(body))
+	case ymlFormat, yamlFormat:
+		body, err := yaml.Marshal(preview)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(config.resultWriter, "---\n"+string(body))
+	default:
+		return zerr.ErrInvalidOutputFormat
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+//go:build search && mgmt
+// +build search,mgmt
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/maintenance"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestAdminCommand(t *testing.T) {
+	Convey("zli admin", t, func() {
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("admin", "admin") + "\n" +
+			test.GetCredString("user", "user"))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.GC = true
+		conf.Storage.GCInterval = time.Hour
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{"user"}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{"admin"},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		for _, repo := range []string{"app", "other"} {
+			err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, repo, "1.0", "admin", "admin")
+			So(err, ShouldBeNil)
+		}
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"admintest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		maintenanceWaitInterval = 100 * time.Millisecond
+
+		runAdminCommand := func(user string, args ...string) (string, error) {
+			cmd := NewAdminCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(args, "--config", "admintest", "--user", user))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		out, err := runAdminCommand("admin:admin", "status")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "JOB")
+		So(out, ShouldContainSubstring, "LAST ERROR")
+		So(out, ShouldContainSubstring, "gc")
+		So(out, ShouldContainSubstring, "dedupe")
+
+		_, err = runAdminCommand("user:user", "status")
+		So(err, ShouldNotBeNil)
+
+		_, err = runAdminCommand("admin:admin", "status", "-f", "bad")
+		So(errors.Is(err, zerr.ErrInvalidOutputFormat), ShouldBeTrue)
+
+		// scrub isn't enabled in the config
+		_, err = runAdminCommand("admin:admin", "scrub", "run")
+		So(errors.Is(err, zerr.ErrMaintenanceJobNotFound), ShouldBeTrue)
+
+		out, err = runAdminCommand("admin:admin", "gc", "run", "--wait", "-f", "json")
+		So(err, ShouldBeNil)
+
+		status := maintenance.Status{}
+		err = json.Unmarshal([]byte(out), &status)
+		So(err, ShouldBeNil)
+		So(status.Job, ShouldEqual, maintenance.GC)
+		So(status.Running, ShouldBeFalse)
+		So(status.Trigger, ShouldEqual, maintenance.TriggerManual)
+		So(status.TasksDone, ShouldEqual, 2)
+		So(status.FinishedAt, ShouldNotBeNil)
+
+		out, err = runAdminCommand("admin:admin", "gc", "pause")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "paused")
+
+		_, err = runAdminCommand("admin:admin", "gc", "run")
+		So(errors.Is(err, zerr.ErrMaintenanceJobPaused), ShouldBeTrue)
+
+		out, err = runAdminCommand("admin:admin", "gc", "-f", "yaml")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "paused: true")
+
+		out, err = runAdminCommand("admin:admin", "gc", "resume")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "idle")
+
+		out, err = runAdminCommand("admin:admin", "gc", "run", "--wait")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "manual")
+		So(strings.Count(out, "JOB"), ShouldEqual, 1)
+
+		Convey("Preview the garbage collection", func() {
+			out, err := runAdminCommand("admin:admin", "gc", "preview")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "REPOSITORY")
+			So(out, ShouldContainSubstring, "0 repositories, 0 B would be freed")
+
+			// an orphan blob older than the gc delay
+			orphan := []byte("orphan blob")
+			orphanDigest := godigest.FromBytes(orphan)
+			orphanPath := path.Join(conf.Storage.RootDirectory, "app", "blobs", "sha256", orphanDigest.Encoded())
+
+			err = os.WriteFile(orphanPath, orphan, 0o600)
+			So(err, ShouldBeNil)
+
+			modTime := time.Now().Add(-2 * conf.Storage.GCDelay)
+			err = os.Chtimes(orphanPath, modTime, modTime)
+			So(err, ShouldBeNil)
+
+			out, err = runAdminCommand("admin:admin", "gc", "preview", "--repo", "app", "-f", "json")
+			So(err, ShouldBeNil)
+
+			preview := gcPreview{}
+			err = json.Unmarshal([]byte(out), &preview)
+			So(err, ShouldBeNil)
+			So(len(preview.Repos), ShouldEqual, 1)
+			So(preview.Repos[0].Repo, ShouldEqual, "app")
+			So(preview.Repos[0].Blobs[0].Digest, ShouldEqual, orphanDigest)
+			So(preview.BytesFreed, ShouldEqual, len(orphan))
+
+			out, err = runAdminCommand("admin:admin", "gc", "preview", "-f", "yaml")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "repo: app")
+
+			// the orphan blob is still there
+			_, err = os.Stat(orphanPath)
+			So(err, ShouldBeNil)
+
+			_, err = runAdminCommand("admin:admin", "gc", "preview", "--repo", "missing")
+			So(err, ShouldNotBeNil)
+
+			_, err = runAdminCommand("user:user", "gc", "preview")
+			So(err, ShouldNotBeNil)
+
+			_, err = runAdminCommand("admin:admin", "gc", "preview", "-f", "bad")
+			So(errors.Is(err, zerr.ErrInvalidOutputFormat), ShouldBeTrue)
+		})
+
+		Convey("Back up and restore the metaDB", func() {
+			backupPath := path.Join(t.TempDir(), "metadb.backup.gz")
+
+			err := ctlr.MetaDB.IncrementRepoStars("app")
+			So(err, ShouldBeNil)
+
+			_, err = runAdminCommand("user:user", "metadb", "backup", backupPath)
+			So(err, ShouldNotBeNil)
+			_, err = os.Stat(backupPath)
+			So(os.IsNotExist(err), ShouldBeTrue)
+
+			out, err := runAdminCommand("admin:admin", "metadb", "backup", backupPath)
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Saved the backup")
+			_, err = os.Stat(backupPath)
+			So(err, ShouldBeNil)
+
+			err = ctlr.MetaDB.IncrementRepoStars("app")
+			So(err, ShouldBeNil)
+
+			// the restore isn't confirmed
+			cmd := NewAdminCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetIn(strings.NewReader("n\n"))
+			cmd.SetArgs([]string{"metadb", "restore", backupPath, "--config", "admintest", "--user", "admin:admin"})
+			So(cmd.Execute(), ShouldBeNil)
+			So(buff.String(), ShouldContainSubstring, "Restore cancelled")
+
+			stars, err := ctlr.MetaDB.GetRepoStars("app")
+			So(err, ShouldBeNil)
+			So(stars, ShouldEqual, 2)
+
+			out, err = runAdminCommand("admin:admin", "metadb", "restore", backupPath, "--force")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Restored the metadata database")
+
+			stars, err = ctlr.MetaDB.GetRepoStars("app")
+			So(err, ShouldBeNil)
+			So(stars, ShouldEqual, 1)
+
+			_, err = runAdminCommand("admin:admin", "metadb", "restore", path.Join(t.TempDir(), "missing"), "--force")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
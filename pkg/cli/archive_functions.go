@@ -0,0 +1,361 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	jsoniter "github.com/json-iterator/go"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry/remote"
+
+	zerr "zotregistry.io/zot/errors"
+)
+
+const (
+	// ociIndexFile and dockerManifestFile tell the format of an archive.
+	ociIndexFile       = "index.json"
+	dockerManifestFile = "manifest.json"
+)
+
+// archiveImage is an image of a local archive, its content is read from source to be pushed to the server.
+type archiveImage struct {
+	name   string
+	source oras.ReadOnlyTarget
+	desc   ispec.Descriptor
+}
+
+/*
+ScanArchive lists the CVEs of the images of a local OCI layout or docker archive with the CVE DB of the server.
+Each image is pushed to the repo under a temporary tag, scanned, and its tag is deleted right after, the blobs are
+left to the garbage collection of the server.
+*/
+func ScanArchive(config searchConfig, archivePath, repo, searchedCveID string) error {
+	username, password := getUsernameAndPassword(config.user)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	images, err := loadArchiveImages(ctx, archivePath)
+	if err != nil {
+		return err
+	}
+
+	// the reports are about a single image
+	if len(images) > 1 && isCVEReportFormat(config.outputFormat) {
+		return fmt.Errorf("%w: %s is only supported for the archives of a single image", zerr.ErrInvalidOutputFormat,
+			config.outputFormat)
+	}
+
+	repository, err := newRemoteRepository(config, repo, username, password)
+	if err != nil {
+		return err
+	}
+
+	results := make([]imageCVEs, len(images))
+	progress := newProgressBar(config.spinner, len(images), "images")
+
+	for i, image := range images {
+		results[i].Image = image.name
+
+		cveList, err := scanArchiveImage(ctx, config, repository, image, searchedCveID)
+		if err != nil {
+			progress.finish()
+
+			return fmt.Errorf("%s: %w", image.name, err)
+		}
+
+		results[i].CVEs = filterCVEsBySeverity(cveList.Data.CVEListForImage.CVEList, config.severity)
+
+		progress.increment()
+	}
+
+	progress.finish()
+
+	writer := config.resultWriter
+
+	if config.outputFile != "" {
+		file, err := os.Create(config.outputFile)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		writer = file
+	}
+
+	// a single image is shown like the CVEs of an image of the registry
+	if len(results) == 1 {
+		cveList := &cveResult{Data: cveData{CVEListForImage: cveListForImage{CVEList: results[0].CVEs}}}
+
+		err = printCVEList(config, writer, results[0].Image, cveList)
+	} else {
+		err = printImagesCVEs(config, writer, results)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	allCVEs := []cve{}
+
+	for _, result := range results {
+		allCVEs = append(allCVEs, result.CVEs...)
+	}
+
+	return checkCVESeverityThreshold(allCVEs, config.failOn)
+}
+
+// scanArchiveImage pushes the image under a temporary tag to list its CVEs, the tag is deleted even if the scan fails.
+func scanArchiveImage(ctx context.Context, config searchConfig, repository *remote.Repository, image archiveImage,
+	searchedCveID string,
+) (*cveResult, error) {
+	username, password := getUsernameAndPassword(config.user)
+	repo := repository.Reference.Repository
+	tag := fmt.Sprintf("scan-%s-%d", image.desc.Digest.Encoded()[:12], time.Now().UnixNano())
+
+	desc, err := oras.Copy(ctx, image.source, image.desc.Digest.String(), repository, tag, oras.DefaultCopyOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	cveList, scanErr := getCVEListWithRetry(ctx, config, username, password,
+		fmt.Sprintf("%s@%s", repo, desc.Digest), searchedCveID)
+
+	url, err := combineServerAndEndpointURL(config.servURL, fmt.Sprintf("/v2/%s/manifests/%s", repo, tag))
+	if err == nil {
+		err = makeDELETERequest(ctx, url, username, password, config.verifyTLS, config.debug, config.resultWriter)
+	}
+
+	if scanErr != nil {
+		return nil, scanErr
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to delete the tag %s of %s: %w", tag, repo, err)
+	}
+
+	return cveList, nil
+}
+
+// loadArchiveImages returns the images of the archive, the OCI layouts are preferred to the docker archives since
+// the recent versions of docker write both.
+func loadArchiveImages(ctx context.Context, archivePath string) ([]archiveImage, error) {
+	index, isDockerArchive, err := readArchiveIndex(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case index != nil:
+		return loadOCIArchiveImages(ctx, archivePath, *index)
+	case isDockerArchive:
+		return loadDockerArchiveImages(archivePath)
+	default:
+		return nil, fmt.Errorf("%w: %s has neither %s nor %s", zerr.ErrInvalidImageArchive, archivePath,
+			ociIndexFile, dockerManifestFile)
+	}
+}
+
+// readArchiveIndex returns the index of an OCI layout archive, or whether it's a docker archive.
+func readArchiveIndex(archivePath string) (*ispec.Index, bool, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer file.Close()
+
+	var (
+		index           *ispec.Index
+		isDockerArchive bool
+		reader          = tar.NewReader(file)
+	)
+
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %s: %w", zerr.ErrInvalidImageArchive, archivePath, err)
+		}
+
+		switch path.Clean(header.Name) {
+		case ociIndexFile:
+			index = &ispec.Index{}
+
+			if err := jsoniter.NewDecoder(reader).Decode(index); err != nil {
+				return nil, false, fmt.Errorf("%w: %s: %w", zerr.ErrInvalidImageArchive, ociIndexFile, err)
+			}
+		case dockerManifestFile:
+			isDockerArchive = true
+		}
+	}
+
+	return index, isDockerArchive, nil
+}
+
+func loadOCIArchiveImages(ctx context.Context, archivePath string, index ispec.Index) ([]archiveImage, error) {
+	store, err := oci.NewFromTar(ctx, archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", zerr.ErrInvalidImageArchive, archivePath, err)
+	}
+
+	images := []archiveImage{}
+
+	for _, desc := range index.Manifests {
+		image := archiveImage{
+			name:   fmt.Sprintf("%s@%s", filepath.Base(archivePath), desc.Digest),
+			source: store,
+			desc:   desc,
+		}
+
+		if refName := desc.Annotations[ispec.AnnotationRefName]; refName != "" {
+			image.name = refName
+		}
+
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+func loadDockerArchiveImages(archivePath string) ([]archiveImage, error) {
+	opener := func() (io.ReadCloser, error) {
+		return os.Open(archivePath)
+	}
+
+	manifest, err := tarball.LoadManifest(opener)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", zerr.ErrInvalidImageArchive, archivePath, err)
+	}
+
+	images := []archiveImage{}
+
+	for _, descriptor := range manifest {
+		var tag *name.Tag
+
+		imageName := filepath.Base(archivePath)
+
+		// the images of an archive of several images are found by their tag
+		if len(descriptor.RepoTags) > 0 {
+			imageName = descriptor.RepoTags[0]
+
+			repoTag, err := name.NewTag(imageName)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", zerr.ErrInvalidImageArchive, imageName, err)
+			}
+
+			tag = &repoTag
+		}
+
+		image, err := tarball.Image(opener, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", zerr.ErrInvalidImageArchive, imageName, err)
+		}
+
+		source, err := newDockerArchiveImage(image)
+		if err != nil {
+			return nil, err
+		}
+
+		images = append(images, archiveImage{name: imageName, source: source, desc: source.desc})
+	}
+
+	return images, nil
+}
+
+/*
+dockerArchiveImage gives the content of an image of a docker archive to oras, the image is converted to an OCI
+image since the server only accepts the OCI manifests. The layers are compressed as they're read.
+*/
+type dockerArchiveImage struct {
+	image    v1.Image
+	manifest []byte
+	desc     ispec.Descriptor
+}
+
+func newDockerArchiveImage(image v1.Image) (*dockerArchiveImage, error) {
+	image = mutate.ConfigMediaType(mutate.MediaType(image, types.OCIManifestSchema1), types.OCIConfigJSON)
+
+	manifest, err := image.RawManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	return &dockerArchiveImage{
+		image:    image,
+		manifest: manifest,
+		desc: ispec.Descriptor{
+			MediaType: ispec.MediaTypeImageManifest,
+			Digest:    godigest.FromBytes(manifest),
+			Size:      int64(len(manifest)),
+		},
+	}, nil
+}
+
+func (archiveImage *dockerArchiveImage) Resolve(ctx context.Context, reference string) (ispec.Descriptor, error) {
+	return archiveImage.desc, nil
+}
+
+func (archiveImage *dockerArchiveImage) Exists(ctx context.Context, target ispec.Descriptor) (bool, error) {
+	content, err := archiveImage.Fetch(ctx, target)
+	if err != nil {
+		return false, nil //nolint: nilerr // the content isn't in the image
+	}
+
+	return true, content.Close()
+}
+
+func (archiveImage *dockerArchiveImage) Fetch(ctx context.Context, target ispec.Descriptor) (io.ReadCloser, error) {
+	if target.Digest == archiveImage.desc.Digest {
+		return io.NopCloser(bytes.NewReader(archiveImage.manifest)), nil
+	}
+
+	hash, err := v1.NewHash(target.Digest.String())
+	if err != nil {
+		return nil, err
+	}
+
+	configName, err := archiveImage.image.ConfigName()
+	if err != nil {
+		return nil, err
+	}
+
+	if hash == configName {
+		config, err := archiveImage.image.RawConfigFile()
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(bytes.NewReader(config)), nil
+	}
+
+	layer, err := archiveImage.image.LayerByDigest(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return layer.Compressed()
+}
@@ -3,12 +3,32 @@
 
 package cli
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
 
 func enableCli(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().Duration(cmdflags.TimeoutFlag, httpTimeout, "Timeout of the requests to the server")
+	rootCmd.PersistentFlags().Int(cmdflags.RetriesFlag, 0,
+		"Number of times the reads failing with a network error or an unavailable server are retried")
+	rootCmd.PersistentFlags().String(cmdflags.ProxyFlag, "",
+		"Proxy the requests are sent through, instead of the one of the HTTP_PROXY/HTTPS_PROXY variables")
+	rootCmd.PersistentFlags().String(cmdflags.NoProxyFlag, "",
+		"Comma separated hosts, and their subdomains, reached without the proxy")
+	rootCmd.PersistentFlags().String(cmdflags.ProfileFlag, "",
+		"Config of ~/.zot used when '--config' isn't given, instead of the one of the "+profileEnvVar+" variable")
+
 	rootCmd.AddCommand(NewConfigCommand())
 	rootCmd.AddCommand(NewImageCommand(NewSearchService()))
 	rootCmd.AddCommand(NewCVECommand(NewSearchService()))
 	rootCmd.AddCommand(NewRepoCommand(NewSearchService()))
 	rootCmd.AddCommand(NewSearchCommand(NewSearchService()))
+	rootCmd.AddCommand(NewTagCommand(NewSearchService()))
+	rootCmd.AddCommand(NewSBOMCommand(NewSearchService()))
+	rootCmd.AddCommand(NewLoginCommand(NewSearchService()))
+	rootCmd.AddCommand(NewLogoutCommand(NewSearchService()))
+	rootCmd.AddCommand(NewAdminCommand(NewSearchService()))
+	rootCmd.AddCommand(NewRegistryCommand(NewSearchService()))
 }
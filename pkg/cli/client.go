@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,6 +27,8 @@ import (
 var (
 	httpClientsMap = make(map[string]*http.Client) //nolint: gochecknoglobals
 	httpClientLock sync.Mutex                      //nolint: gochecknoglobals
+	// clientOptions are the settings of the HTTP clients, set from the flags and the config of the command.
+	clientOptions = httpClientOptions{timeout: httpTimeout} //nolint: gochecknoglobals
 )
 
 const (
@@ -35,8 +38,182 @@ const (
 	clientCertFilename = "client.cert"
 	clientKeyFilename  = "client.key"
 	caCertFilename     = "ca.crt"
+	maxRetryInterval   = 30 * time.Second
 )
 
+// retryInterval is the delay before the first retry of a request, doubled for each of the next ones.
+var retryInterval = time.Second //nolint: gochecknoglobals // changed by the tests
+
+type httpClientOptions struct {
+	timeout time.Duration
+	retries int
+	// proxy is used instead of the one of the environment, for the hosts which aren't in noProxy
+	proxy   *url.URL
+	noProxy []string
+	// certsDir has the CA certificate and the client certificate of the mutual TLS authentication
+	certsDir string
+}
+
+// setHTTPClientOptions changes the settings of the HTTP clients, the clients created with other ones are dropped.
+func setHTTPClientOptions(options httpClientOptions) {
+	httpClientLock.Lock()
+	defer httpClientLock.Unlock()
+
+	if reflect.DeepEqual(options, clientOptions) {
+		return
+	}
+
+	clientOptions = options
+	httpClientsMap = make(map[string]*http.Client)
+}
+
+func getHTTPClientOptions() httpClientOptions {
+	httpClientLock.Lock()
+	defer httpClientLock.Unlock()
+
+	return clientOptions
+}
+
+// waitTimeout is how long a result can take, with all the retries of its requests.
+func (options httpClientOptions) waitTimeout() time.Duration {
+	retries := time.Duration(options.retries)
+
+	return options.timeout*(retries+1) + maxRetryInterval*retries + 5*time.Second
+}
+
+func (options httpClientOptions) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if options.proxy == nil && len(options.noProxy) == 0 {
+		return http.ProxyFromEnvironment
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		host := req.URL.Hostname()
+
+		for _, noProxyHost := range options.noProxy {
+			noProxyHost = strings.TrimPrefix(noProxyHost, ".")
+
+			if noProxyHost == "*" || host == noProxyHost || strings.HasSuffix(host, "."+noProxyHost) {
+				return nil, nil //nolint: nilnil // no proxy
+			}
+		}
+
+		if options.proxy == nil {
+			return http.ProxyFromEnvironment(req)
+		}
+
+		return options.proxy, nil
+	}
+}
+
+// getHTTPClient returns the client of the host, it's created with the current options if there's none yet.
+func getHTTPClient(verifyTLS bool, host string) (*http.Client, error) {
+	httpClientLock.Lock()
+	defer httpClientLock.Unlock()
+
+	if httpClient, ok := httpClientsMap[host]; ok {
+		return httpClient, nil
+	}
+
+	httpClient, err := createHTTPClient(verifyTLS, host, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClientsMap[host] = httpClient
+
+	return httpClient, nil
+}
+
+func createHTTPClient(verifyTLS bool, host string, options httpClientOptions) (*http.Client, error) {
+	httpClient, err := common.CreateHTTPClient(verifyTLS, host, options.certsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient.Timeout = options.timeout
+
+	if transport, ok := httpClient.Transport.(*http.Transport); ok {
+		transport.Proxy = options.proxyFunc()
+	}
+
+	return httpClient, nil
+}
+
+/*
+sendHTTPRequest sends the request, retrying the reads failing with a network error or a status code telling
+the server is unavailable for now, as many times as the options allow, after an exponential backoff.
+*/
+func sendHTTPRequest(httpClient *http.Client, req *http.Request, debug bool, configWriter io.Writer,
+) (*http.Response, error) {
+	retries := getHTTPClientOptions().retries
+
+	// the other requests change the server, they may have been applied even if their response was lost
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		retries = 0
+	}
+
+	interval := retryInterval
+
+	for attempt := 0; ; attempt++ {
+		if debug {
+			fmt.Fprintln(configWriter, "[debug] ", req.Method, " ", req.URL, "[request header] ", req.Header)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err == nil && debug {
+			fmt.Fprintln(configWriter, "[debug] ", req.Method, req.URL, "[status] ",
+				resp.StatusCode, " ", "[response header] ", resp.Header)
+		}
+
+		if attempt >= retries || !isRetryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if debug {
+			fmt.Fprintf(configWriter, "[debug] retrying %s %s in %s [%d/%d]\n", req.Method, req.URL, interval,
+				attempt+1, retries)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		if interval *= 2; interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+
+		// the body was consumed by the previous attempt
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = body
+		}
+	}
+}
+
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
 func makeGETRequest(ctx context.Context, url, username, password string,
 	verifyTLS bool, debug bool, resultsPtr interface{}, configWriter io.Writer,
 ) (http.Header, error) {
@@ -63,6 +240,57 @@ func makeHEADRequest(ctx context.Context, url, username, password string, verify
 	return doHTTPRequest(req, verifyTLS, debug, nil, io.Discard)
 }
 
+func makePOSTRequest(ctx context.Context, url, username, password string, body interface{}, verifyTLS bool,
+	debug bool, resultsPtr interface{}, configWriter io.Writer,
+) error {
+	var reqBody io.Reader
+
+	contentType := "application/json"
+
+	// the readers are sent as they are, like the backups
+	if reader, ok := body.(io.Reader); ok {
+		reqBody = reader
+		contentType = "application/octet-stream"
+	} else if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reqBody = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(username, password)
+
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	_, err = doHTTPRequest(req, verifyTLS, debug, resultsPtr, configWriter)
+
+	return err
+}
+
+func makeDELETERequest(ctx context.Context, url, username, password string, verifyTLS bool,
+	debug bool, configWriter io.Writer,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(username, password)
+
+	_, err = doHTTPRequest(req, verifyTLS, debug, nil, configWriter)
+
+	return err
+}
+
 func makeGraphQLRequest(ctx context.Context, url, query, username,
 	password string, verifyTLS bool, debug bool, resultsPtr interface{}, configWriter io.Writer,
 ) error {
@@ -90,44 +318,19 @@ func makeGraphQLRequest(ctx context.Context, url, query, username,
 func doHTTPRequest(req *http.Request, verifyTLS bool, debug bool,
 	resultsPtr interface{}, configWriter io.Writer,
 ) (http.Header, error) {
-	var httpClient *http.Client
-
-	var err error
-
-	host := req.Host
-
-	httpClientLock.Lock()
-
-	if httpClientsMap[host] == nil {
-		httpClient, err = common.CreateHTTPClient(verifyTLS, host, "")
-		if err != nil {
-			return nil, err
-		}
-
-		httpClientsMap[host] = httpClient
-	} else {
-		httpClient = httpClientsMap[host]
-	}
-
-	httpClientLock.Unlock()
-
-	if debug {
-		fmt.Fprintln(configWriter, "[debug] ", req.Method, " ", req.URL, "[request header] ", req.Header)
-	}
-
-	resp, err := httpClient.Do(req)
+	httpClient, err := getHTTPClient(verifyTLS, req.Host)
 	if err != nil {
 		return nil, err
 	}
 
-	if debug {
-		fmt.Fprintln(configWriter, "[debug] ", req.Method, req.URL, "[status] ",
-			resp.StatusCode, " ", "[response header] ", resp.Header)
+	resp, err := sendHTTPRequest(httpClient, req, debug, configWriter)
+	if err != nil {
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && !isAcceptedStatus(req.Method, resp.StatusCode) {
 		if resp.StatusCode == http.StatusUnauthorized {
 			return nil, zerr.ErrUnauthorizedAccess
 		}
@@ -142,6 +345,25 @@ func doHTTPRequest(req *http.Request, verifyTLS bool, debug bool,
 		return resp.Header, nil
 	}
 
+	// the raw content is returned for the responses which aren't JSON documents, like blobs
+	if content, ok := resultsPtr.(*[]byte); ok {
+		*content, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return resp.Header, nil
+	}
+
+	// the large contents, like backups, are streamed instead
+	if writer, ok := resultsPtr.(io.Writer); ok {
+		if _, err := io.Copy(writer, resp.Body); err != nil {
+			return nil, err
+		}
+
+		return resp.Header, nil
+	}
+
 	if err := json.NewDecoder(resp.Body).Decode(resultsPtr); err != nil {
 		return nil, err
 	}
@@ -149,6 +371,12 @@ func doHTTPRequest(req *http.Request, verifyTLS bool, debug bool,
 	return resp.Header, nil
 }
 
+// isAcceptedStatus checks the status codes the creations and deletions are accepted with, besides 200.
+func isAcceptedStatus(method string, statusCode int) bool {
+	return (method == http.MethodPost && (statusCode == http.StatusCreated || statusCode == http.StatusAccepted)) ||
+		(method == http.MethodDelete && statusCode == http.StatusAccepted)
+}
+
 func validateURL(str string) error {
 	parsedURL, err := url.Parse(str)
 	if err != nil {
@@ -167,10 +395,11 @@ func validateURL(str string) error {
 }
 
 type requestsPool struct {
-	jobs     chan *httpJob
-	done     chan struct{}
-	wtgrp    *sync.WaitGroup
-	outputCh chan stringResult
+	jobs        chan *httpJob
+	done        chan struct{}
+	wtgrp       *sync.WaitGroup
+	outputCh    chan stringResult
+	concurrency int
 }
 
 type httpJob struct {
@@ -182,38 +411,50 @@ type httpJob struct {
 	config    searchConfig
 }
 
-const rateLimiterBuffer = 5000
+const (
+	requestsPoolBuffer = 5000
+	// defaultConcurrency is the number of requests sent in parallel, unless configured otherwise.
+	defaultConcurrency = 10
+)
 
-func newSmoothRateLimiter(wtgrp *sync.WaitGroup, opch chan stringResult) *requestsPool {
-	ch := make(chan *httpJob, rateLimiterBuffer)
+// newRequestsPool creates a pool of workers sending the requests of the jobs, concurrency of them at a time.
+func newRequestsPool(wtgrp *sync.WaitGroup, opch chan stringResult, concurrency int) *requestsPool {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
 
 	return &requestsPool{
-		jobs:     ch,
-		done:     make(chan struct{}),
-		wtgrp:    wtgrp,
-		outputCh: opch,
+		jobs:        make(chan *httpJob, requestsPoolBuffer),
+		done:        make(chan struct{}),
+		wtgrp:       wtgrp,
+		outputCh:    opch,
+		concurrency: concurrency,
 	}
 }
 
-// block every "rateLimit" time duration.
-const rateLimit = 100 * time.Millisecond
-
-func (p *requestsPool) startRateLimiter(ctx context.Context) {
-	p.wtgrp.Done()
-
-	throttle := time.NewTicker(rateLimit).C
-
-	for {
-		select {
-		case job := <-p.jobs:
-			go p.doJob(ctx, job)
-		case <-p.done:
-			return
-		}
-		<-throttle
+/*
+startWorkers starts the workers of the pool, they run until the pool is stopped. The jobs are still done once
+the context is cancelled, they return right away, their wait group would never be done otherwise.
+*/
+func (p *requestsPool) startWorkers(ctx context.Context) {
+	for i := 0; i < p.concurrency; i++ {
+		go func() {
+			for {
+				select {
+				case job := <-p.jobs:
+					p.doJob(ctx, job)
+				case <-p.done:
+					return
+				}
+			}
+		}()
 	}
 }
 
+func (p *requestsPool) stop() {
+	close(p.done)
+}
+
 func (p *requestsPool) doJob(ctx context.Context, job *httpJob) {
 	defer p.wtgrp.Done()
 
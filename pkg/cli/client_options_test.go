@@ -0,0 +1,177 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+)
+
+func TestHTTPClientOptions(t *testing.T) {
+	Convey("timeout, retries and proxy of the requests", t, func() {
+		defer setHTTPClientOptions(httpClientOptions{timeout: httpTimeout})
+
+		savedRetryInterval := retryInterval
+		retryInterval = 10 * time.Millisecond
+
+		defer func() { retryInterval = savedRetryInterval }()
+
+		var requests, failures atomic.Int32
+
+		delay := time.Duration(0)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			if failures.Load() > 0 {
+				failures.Add(-1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			time.Sleep(delay)
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name":"repo","tags":["1.0"]}`)
+		}))
+		defer server.Close()
+
+		runTagList := func(args ...string) (string, error) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append([]string{"tag", "list", "repo"}, args...))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		out, err := runTagList("--url", server.URL)
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "1.0")
+
+		Convey("retries", func() {
+			failures.Store(2)
+			requests.Store(0)
+
+			_, err := runTagList("--url", server.URL, "--retries", "1")
+			So(errors.Is(err, zerr.ErrBadHTTPStatusCode), ShouldBeTrue)
+			So(requests.Load(), ShouldEqual, 2)
+
+			failures.Store(2)
+			requests.Store(0)
+
+			out, err := runTagList("--url", server.URL, "--retries", "2")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "1.0")
+			So(requests.Load(), ShouldEqual, 3)
+
+			failures.Store(1)
+			requests.Store(0)
+
+			_, err = runTagList("--url", server.URL)
+			So(err, ShouldNotBeNil)
+			So(requests.Load(), ShouldEqual, 1)
+
+			_, err = runTagList("--url", server.URL, "--retries", "-1")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		})
+
+		Convey("timeout", func() {
+			delay = 500 * time.Millisecond
+
+			_, err := runTagList("--url", server.URL, "--timeout", "100ms")
+			So(err, ShouldNotBeNil)
+
+			_, err = runTagList("--url", server.URL, "--timeout", "0s")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+
+			out, err := runTagList("--url", server.URL, "--timeout", "5s")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "1.0")
+		})
+
+		Convey("proxy", func() {
+			var proxied atomic.Int32
+
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				proxied.Add(1)
+
+				// the requests sent to a proxy have the full URL of the registry
+				reverseProxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: r.URL.Scheme, Host: r.URL.Host})
+				reverseProxy.ServeHTTP(w, r)
+			}))
+			defer proxy.Close()
+
+			out, err := runTagList("--url", server.URL, "--proxy", proxy.URL)
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "1.0")
+			So(proxied.Load(), ShouldEqual, 1)
+
+			_, err = runTagList("--url", server.URL, "--proxy", proxy.URL, "--no-proxy", "example.com, 127.0.0.1")
+			So(err, ShouldBeNil)
+			So(proxied.Load(), ShouldEqual, 1)
+
+			_, err = runTagList("--url", server.URL, "--proxy", proxy.URL, "--no-proxy", "*")
+			So(err, ShouldBeNil)
+			So(proxied.Load(), ShouldEqual, 1)
+
+			_, err = runTagList("--url", server.URL, "--proxy", "proxy:3128")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		})
+
+		Convey("from the config", func() {
+			var proxied atomic.Int32
+
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				proxied.Add(1)
+
+				reverseProxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: r.URL.Scheme, Host: r.URL.Host})
+				reverseProxy.ServeHTTP(w, r)
+			}))
+			defer proxy.Close()
+
+			configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"clienttest","url":"%s",`+
+				`"showspinner":false,"retries":"2","timeout":"5s","proxy":"%s"}]}`, server.URL, proxy.URL))
+			defer os.Remove(configPath)
+
+			failures.Store(2)
+			requests.Store(0)
+
+			out, err := runTagList("--config", "clienttest")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "1.0")
+			So(requests.Load(), ShouldEqual, 3)
+			So(proxied.Load(), ShouldEqual, 3)
+
+			// the flags override the config
+			failures.Store(1)
+
+			_, err = runTagList("--config", "clienttest", "--retries", "0")
+			So(err, ShouldNotBeNil)
+
+			configPath = makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"clienttest","url":"%s",`+
+				`"showspinner":false,"timeout":"soon"}]}`, server.URL))
+			defer os.Remove(configPath)
+
+			_, err = runTagList("--config", "clienttest")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		})
+	})
+}
@@ -9,16 +9,50 @@ import (
 )
 
 const (
-	URLFlag          = "url"
-	ConfigFlag       = "config"
-	UserFlag         = "user"
-	OutputFormatFlag = "format"
-	FixedFlag        = "fixed"
-	VerboseFlag      = "verbose"
-	VersionFlag      = "version"
-	DebugFlag        = "debug"
-	SearchedCVEID    = "cve-id"
-	SortByFlag       = "sort-by"
+	URLFlag             = "url"
+	ConfigFlag          = "config"
+	UserFlag            = "user"
+	OutputFormatFlag    = "format"
+	FixedFlag           = "fixed"
+	VerboseFlag         = "verbose"
+	VersionFlag         = "version"
+	DebugFlag           = "debug"
+	SearchedCVEID       = "cve-id"
+	SortByFlag          = "sort-by"
+	FixStatusFlag       = "fix-status"
+	ManifestFlag        = "manifest"
+	ForceFlag           = "force"
+	DryRunFlag          = "dry-run"
+	SBOMFormatFlag      = "sbom-format"
+	OutputFileFlag      = "output"
+	PackagesFlag        = "packages"
+	SeverityFlag        = "severity"
+	FailOnFlag          = "fail-on"
+	UsernameFlag        = "username"
+	PasswordStdinFlag   = "password-stdin"
+	APIKeyFlag          = "api-key"
+	OIDCFlag            = "oidc"
+	WaitFlag            = "wait"
+	SignToolFlag        = "tool"
+	KeyFlag             = "key"
+	KeylessFlag         = "keyless"
+	RepoFlag            = "repo"
+	PlatformFlag        = "platform"
+	CVEsFlag            = "cves"
+	TimeoutFlag         = "timeout"
+	RetriesFlag         = "retries"
+	ProxyFlag           = "proxy"
+	NoProxyFlag         = "no-proxy"
+	FromFlag            = "from"
+	ToFlag              = "to"
+	LimitFlag           = "limit"
+	TemplateFlag        = "template"
+	ColumnsFlag         = "columns"
+	KeepLastFlag        = "keep-last"
+	MatchFlag           = "match"
+	OlderThanFlag       = "older-than"
+	NotPulledWithinFlag = "not-pulled-within"
+	ProfileFlag         = "profile"
 )
 
 const (
@@ -29,6 +63,31 @@ const (
 	SortBySeverity      = "severity"
 )
 
+const (
+	FixStatusAny         = "any"
+	FixStatusAvailable   = "available"
+	FixStatusPartial     = "partial"
+	FixStatusUnavailable = "unavailable"
+)
+
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+const (
+	SBOMFormatAny       = "any"
+	SBOMFormatSPDX      = "spdx"
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+const (
+	SignToolCosign   = "cosign"
+	SignToolNotation = "notation"
+)
+
 const stringType = "string"
 
 func ImageListSortOptions() []string {
@@ -63,6 +122,30 @@ func RepoListSortOptionsStr() string {
 	return strings.Join(RepoListSortOptions(), ", ")
 }
 
+func CVESeverityOptions() []string {
+	return []string{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+}
+
+func CVESeverityOptionsStr() string {
+	return strings.Join(CVESeverityOptions(), ", ")
+}
+
+func SBOMFormatOptions() []string {
+	return []string{SBOMFormatAny, SBOMFormatSPDX, SBOMFormatCycloneDX}
+}
+
+func SBOMFormatOptionsStr() string {
+	return strings.Join(SBOMFormatOptions(), ", ")
+}
+
+func SignToolOptions() []string {
+	return []string{SignToolCosign, SignToolNotation}
+}
+
+func SignToolOptionsStr() string {
+	return strings.Join(SignToolOptions(), ", ")
+}
+
 func Flag2SortCriteria(sortBy string) string {
 	switch sortBy {
 	case SortByRelevance:
@@ -80,6 +163,29 @@ func Flag2SortCriteria(sortBy string) string {
 	}
 }
 
+func CVEFixStatusOptions() []string {
+	return []string{FixStatusAny, FixStatusAvailable, FixStatusPartial, FixStatusUnavailable}
+}
+
+func CVEFixStatusOptionsStr() string {
+	return strings.Join(CVEFixStatusOptions(), ", ")
+}
+
+// Flag2FixStatus returns the value of the fixStatus graphQL argument matching the flag,
+// or an empty string if CVEs shouldn't be filtered by fix availability.
+func Flag2FixStatus(fixStatus string) string {
+	switch fixStatus {
+	case FixStatusAvailable:
+		return "AVAILABLE"
+	case FixStatusPartial:
+		return "PARTIAL"
+	case FixStatusUnavailable:
+		return "UNAVAILABLE"
+	default:
+		return ""
+	}
+}
+
 type CVEListSortFlag string
 
 func (e *CVEListSortFlag) String() string {
@@ -159,3 +265,85 @@ func (e *RepoListSortFlag) Set(val string) error {
 func (e *RepoListSortFlag) Type() string {
 	return stringType
 }
+
+type CVEFixStatusFlag string
+
+func (e *CVEFixStatusFlag) String() string {
+	return string(*e)
+}
+
+func (e *CVEFixStatusFlag) Set(val string) error {
+	if !common.Contains(CVEFixStatusOptions(), val) {
+		return fmt.Errorf("%w %s", zerr.ErrFlagValueUnsupported, CVEFixStatusOptionsStr())
+	}
+
+	*e = CVEFixStatusFlag(val)
+
+	return nil
+}
+
+func (e *CVEFixStatusFlag) Type() string {
+	return stringType
+}
+
+type CVESeverityFlag string
+
+func (e *CVESeverityFlag) String() string {
+	return string(*e)
+}
+
+func (e *CVESeverityFlag) Set(val string) error {
+	val = strings.ToLower(val)
+
+	if !common.Contains(CVESeverityOptions(), val) {
+		return fmt.Errorf("%w %s", zerr.ErrFlagValueUnsupported, CVESeverityOptionsStr())
+	}
+
+	*e = CVESeverityFlag(val)
+
+	return nil
+}
+
+func (e *CVESeverityFlag) Type() string {
+	return stringType
+}
+
+type SBOMFormatOptionFlag string
+
+func (e *SBOMFormatOptionFlag) String() string {
+	return string(*e)
+}
+
+func (e *SBOMFormatOptionFlag) Set(val string) error {
+	if !common.Contains(SBOMFormatOptions(), val) {
+		return fmt.Errorf("%w %s", zerr.ErrFlagValueUnsupported, SBOMFormatOptionsStr())
+	}
+
+	*e = SBOMFormatOptionFlag(val)
+
+	return nil
+}
+
+func (e *SBOMFormatOptionFlag) Type() string {
+	return stringType
+}
+
+type SignToolOptionFlag string
+
+func (e *SignToolOptionFlag) String() string {
+	return string(*e)
+}
+
+func (e *SignToolOptionFlag) Set(val string) error {
+	if !common.Contains(SignToolOptions(), val) {
+		return fmt.Errorf("%w %s", zerr.ErrFlagValueUnsupported, SignToolOptionsStr())
+	}
+
+	*e = SignToolOptionFlag(val)
+
+	return nil
+}
+
+func (e *SignToolOptionFlag) Type() string {
+	return stringType
+}
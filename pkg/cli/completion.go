@@ -0,0 +1,143 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/api/constants"
+)
+
+// completionTimeout bounds the requests made while completing, the shell waits for them.
+const completionTimeout = 5 * time.Second
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeRepos completes the first argument with the names of the repositories of the configured registry.
+func completeRepos(searchService SearchService) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return completeRepoFlag(searchService)(cmd, args, toComplete)
+	}
+}
+
+// completeRepoFlag completes the value of a flag with the names of the repositories of the configured registry.
+func completeRepoFlag(searchService SearchService) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+
+		config, err := getCompletionConfig(cmd, searchService)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		repos, err := getCompletionRepos(ctx, config, toComplete)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return repos, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+/*
+completeImages completes the first argument with the images of the configured registry: the repositories are
+completed first, followed by ':' for the tag to be completed next with the tags of the repository.
+*/
+func completeImages(searchService SearchService) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+
+		config, err := getCompletionConfig(cmd, searchService)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		repo, tagPrefix, hasTag := strings.Cut(toComplete, ":")
+		if !hasTag {
+			repos, err := getCompletionRepos(ctx, config, toComplete)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			for i := range repos {
+				repos[i] += ":"
+			}
+
+			// the tag is typed right after the ':'
+			return repos, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		}
+
+		username, password := getUsernameAndPassword(config.user)
+
+		tags, err := getTags(ctx, config, username, password, repo)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		images := []string{}
+
+		for _, tag := range tags {
+			if strings.HasPrefix(tag, tagPrefix) {
+				images = append(images, fmt.Sprintf("%s:%s", repo, tag))
+			}
+		}
+
+		return images, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func getCompletionConfig(cmd *cobra.Command, searchService SearchService) (searchConfig, error) {
+	config, err := GetSearchConfigFromFlags(cmd, searchService)
+	if err != nil {
+		return searchConfig{}, err
+	}
+
+	// anything else written to stdout would be taken as a completion
+	config.debug = false
+
+	return config, nil
+}
+
+func getCompletionRepos(ctx context.Context, config searchConfig, prefix string) ([]string, error) {
+	username, password := getUsernameAndPassword(config.user)
+
+	catalogURL, err := combineServerAndEndpointURL(config.servURL, fmt.Sprintf("%s%s",
+		constants.RoutePrefix, constants.ExtCatalogPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &catalogResponse{}
+
+	_, err = makeGETRequest(ctx, catalogURL, username, password, config.verifyTLS, config.debug, catalog,
+		config.resultWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := []string{}
+
+	for _, repo := range catalog.Repositories {
+		if strings.HasPrefix(repo, prefix) {
+			repos = append(repos, repo)
+		}
+	}
+
+	return repos, nil
+}
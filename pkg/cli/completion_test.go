@@ -0,0 +1,99 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestCompletion(t *testing.T) {
+	Convey("completion of the repos and tags", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		for _, image := range []string{"alpine:3.17", "alpine:3.18", "alpine:edge", "app:1.0"} {
+			repo, tag, _ := strings.Cut(image, ":")
+
+			err := UploadImage(CreateRandomImage(), baseURL, repo, tag)
+			So(err, ShouldBeNil)
+		}
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"comptest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		complete := func(args ...string) ([]string, string) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(bytes.NewBufferString(""))
+			cmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+			So(cmd.Execute(), ShouldBeNil)
+
+			// the completions are followed by the directive
+			lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+
+			return lines[:len(lines)-1], lines[len(lines)-1]
+		}
+
+		completions, directive := complete("image", "list", "--config", "comptest", "--repo", "")
+		So(completions, ShouldResemble, []string{"alpine", "app"})
+		So(directive, ShouldEqual, fmt.Sprintf(":%d", cobra.ShellCompDirectiveNoFileComp))
+
+		completions, _ = complete("tag", "list", "--config", "comptest", "al")
+		So(completions, ShouldResemble, []string{"alpine"})
+
+		completions, directive = complete("image", "cve", "--config", "comptest", "a")
+		So(completions, ShouldResemble, []string{"alpine:", "app:"})
+		So(directive, ShouldEqual,
+			fmt.Sprintf(":%d", cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace))
+
+		completions, _ = complete("tag", "rm", "--config", "comptest", "alpine:3")
+		So(completions, ShouldResemble, []string{"alpine:3.17", "alpine:3.18"})
+
+		// a single image is expected
+		completions, _ = complete("sbom", "list", "--config", "comptest", "alpine:3.17", "a")
+		So(completions, ShouldBeEmpty)
+
+		completions, directive = complete("image", "list", "--url", "http://127.0.0.1:1", "--repo", "")
+		So(completions, ShouldBeEmpty)
+		So(directive, ShouldEqual, fmt.Sprintf(":%d", cobra.ShellCompDirectiveError))
+
+		Convey("image list of a repo", func() {
+			cmd := NewImageCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs([]string{"list", "--repo", "alpine", "--config", "comptest"})
+			So(cmd.Execute(), ShouldBeNil)
+
+			out := buff.String()
+			So(out, ShouldContainSubstring, "alpine")
+			So(out, ShouldContainSubstring, "edge")
+			So(out, ShouldNotContainSubstring, "app")
+		})
+	})
+}
@@ -98,6 +98,7 @@ func NewConfigCommand() *cobra.Command {
 	configCmd.SetUsageTemplate(configCmd.UsageTemplate() + supportedOptions)
 	configCmd.AddCommand(NewConfigAddCommand())
 	configCmd.AddCommand(NewConfigRemoveCommand())
+	configCmd.AddCommand(NewConfigValidateServerCommand())
 
 	return configCmd
 }
@@ -162,6 +163,25 @@ func NewConfigRemoveCommand() *cobra.Command {
 	return configRemoveCmd
 }
 
+func NewConfigValidateServerCommand() *cobra.Command {
+	configValidateServerCmd := &cobra.Command{
+		Use:     "validate-server <file>",
+		Example: "  zli config validate-server /etc/zot/config.json",
+		Short:   "Validate a zot server config file",
+		Long: `Validate a zot server config file with the checks done by the server at startup, and the checks of the
+settings which don't work together, like dedupe without a cache. The errors and warnings are printed`,
+		Args: cobra.ExactArgs(oneArg),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ValidateServerConfig(args[0], cmd.OutOrStdout())
+		},
+	}
+
+	// Prevent parent template from overwriting default template
+	configValidateServerCmd.SetUsageTemplate(configValidateServerCmd.UsageTemplate())
+
+	return configValidateServerCmd
+}
+
 func getConfigMapFromFile(filePath string) ([]interface{}, error) {
 	file, err := os.OpenFile(filePath, os.O_RDONLY|os.O_CREATE, defaultConfigPerms)
 	if err != nil {
@@ -481,13 +501,28 @@ const (
   zli config --list
   zli config main url
   zli config main --list
-  zli config remove main`
+  zli config remove main
+  zli config validate-server /etc/zot/config.json
+  zli config main credentials env:ZOT_CREDENTIALS
+  ZLI_PROFILE=main zli image list`
 
 	supportedOptions = `
 Useful variables:
   url		zot server URL
   showspinner	show spinner while loading data [true/false]
   verify-tls	enable TLS certificate verification of the server [default: true]
+  credstore	docker credentials helper keeping the credentials of 'zli login' [default: the OS keychain]
+  concurrency	number of requests sent in parallel to the server [default: 10]
+  timeout	timeout of the requests to the server, like 30s [default: 5m]
+  retries	number of times the failed reads from the server are retried [default: 0]
+  proxy		proxy the requests are sent through, instead of the one of the HTTP(S)_PROXY variables
+  no-proxy	comma separated hosts, and their subdomains, reached without the proxy
+  credentials	"username:password" read from 'env:<variable>' or 'file:<path>' when '--user' isn't given
+  certs-dir	directory with the ca.crt, client.cert and client.key files of the mutual TLS authentication
+  output-format	output format used when '--format' isn't given [text/json/yaml]
+
+The configs are profiles, the one given by '--config', or else by '--profile' or the ZLI_PROFILE variable,
+is used by the other commands, and their flags override its values.
 `
 
 	nameKey = "_name"
@@ -497,6 +532,18 @@ Useful variables:
 	twoArgs   = 2
 	threeArgs = 3
 
-	showspinnerConfig = "showspinner"
-	verifyTLSConfig   = "verify-tls"
+	showspinnerConfig  = "showspinner"
+	verifyTLSConfig    = "verify-tls"
+	credstoreConfig    = "credstore"
+	concurrencyConfig  = "concurrency"
+	timeoutConfig      = "timeout"
+	retriesConfig      = "retries"
+	proxyConfig        = "proxy"
+	noProxyConfig      = "no-proxy"
+	credentialsConfig  = "credentials"
+	certsDirConfig     = "certs-dir"
+	outputFormatConfig = "output-format"
+
+	// profileEnvVar is the name of the config used when none is given with the flags.
+	profileEnvVar = "ZLI_PROFILE"
 )
@@ -5,8 +5,13 @@ package cli //nolint:testpackage
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"regexp"
 	"strings"
 	"testing"
@@ -465,3 +470,175 @@ func TestConfigCmdMain(t *testing.T) {
 		So(buff.String(), ShouldContainSubstring, "cli config name already added")
 	})
 }
+
+func TestConfigValidateServer(t *testing.T) {
+	Convey("Test validating a zot server config", t, func() {
+		rootDir := t.TempDir()
+
+		validateServerConfig := func(content string) (string, error) {
+			serverConfigPath := path.Join(t.TempDir(), "config.json")
+			err := os.WriteFile(serverConfigPath, []byte(content), 0o600)
+			So(err, ShouldBeNil)
+
+			cmd := NewConfigCommand()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs([]string{"validate-server", serverConfigPath})
+			err = cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		out, err := validateServerConfig(fmt.Sprintf(`{"storage":{"rootDirectory":"%s","gc":false,"gcInterval":"1h"},
+			"http":{"address":"127.0.0.1","port":"8080"},"log":{"level":"debug"}}`, rootDir))
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "warn: periodic garbage-collect interval specified without enabling "+
+			"garbage-collect, will be ignored")
+		So(out, ShouldContainSubstring, "is a valid zot config")
+
+		out, err = validateServerConfig(fmt.Sprintf(`{"storage":{"rootDirectory":"%s","dedup":true},
+			"http":{"address":"127.0.0.1","port":"8080"}}`, rootDir))
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+		So(out, ShouldContainSubstring, "error: unknown keys (keys: Storage.dedup)")
+		So(out, ShouldNotContainSubstring, "is a valid zot config")
+
+		out, err = validateServerConfig(fmt.Sprintf(`{"storage":{"rootDirectory":"%s"},
+			"http":{"address":"127.0.0.1","port":"99999"},"log":{"level":"verbose"}}`, rootDir))
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+		So(out, ShouldContainSubstring, "error: invalid port (port: 99999)")
+		// the checks after the server validation still run
+		So(out, ShouldContainSubstring, "error: invalid log level")
+
+		out, err = validateServerConfig(fmt.Sprintf(`{"storage":{"rootDirectory":"%s","dedupe":true,
+			"remoteCache":true,"subPaths":{"/a":{"rootDirectory":"%s","remoteCache":true,
+			"cacheDriver":{"name":"dynamodb","region":"us-east-2"}}}},
+			"http":{"address":"127.0.0.1","port":"8080","tls":{"cert":"server.cert"}}}`, rootDir, t.TempDir()))
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+		So(out, ShouldContainSubstring, "error: tls requires both a cert and a key")
+		So(out, ShouldContainSubstring, "error: dedupe is enabled with remoteCache but no cacheDriver is configured")
+		So(out, ShouldContainSubstring, "error: the dynamodb cacheDriver is missing parameters "+
+			"(missing: endpoint, cachetablename, subpath: /a)")
+
+		out, err = validateServerConfig(fmt.Sprintf(`{"storage":{"rootDirectory":"%s","dedupe":true,
+			"remoteCache":true,"cacheDriver":{"name":"postgres","tableprefix":"zot_"}},
+			"http":{"address":"127.0.0.1","port":"8080"}}`, rootDir))
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+		So(out, ShouldContainSubstring, "error: the postgres cacheDriver is missing parameters (missing: url)")
+
+		_, err = validateServerConfig(`{"storage":`)
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+
+		cmd := NewConfigCommand()
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"validate-server", path.Join(rootDir, "missing.json")})
+		err = cmd.Execute()
+		So(err, ShouldNotBeNil)
+		So(buff.String(), ShouldContainSubstring, "error while reading configuration")
+	})
+}
+
+func TestConfigProfiles(t *testing.T) {
+	Convey("zli config profiles", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name":"repo","tags":["1.0"]}`)
+		}))
+		defer server.Close()
+
+		credentialsPath := path.Join(t.TempDir(), "credentials")
+		So(os.WriteFile(credentialsPath, []byte("user:pass\n"), 0o600), ShouldBeNil)
+
+		t.Setenv("ZLI_TEST_CREDENTIALS", "user:pass")
+		t.Setenv(profileEnvVar, "")
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[
+			{"_name":"envprofile","url":"%s","showspinner":false,"credentials":"env:ZLI_TEST_CREDENTIALS",
+				"output-format":"json"},
+			{"_name":"fileprofile","url":"%s","showspinner":false,"credentials":"file:%s"},
+			{"_name":"badprofile","url":"%s","showspinner":false,"credentials":"pass"},
+			{"_name":"anonymous","url":"%s","showspinner":false}]}`,
+			server.URL, server.URL, credentialsPath, server.URL, server.URL))
+		defer os.Remove(configPath)
+
+		runTagList := func(args ...string) (string, error) {
+			cmd := NewCliRootCmd()
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append([]string{"tag", "list", "repo"}, args...))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		out, err := runTagList("--profile", "envprofile")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "1.0")
+
+		_, err = runTagList("--profile", "fileprofile")
+		So(err, ShouldBeNil)
+
+		_, err = runTagList("--profile", "badprofile")
+		So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+
+		_, err = runTagList("--profile", "anonymous")
+		So(err, ShouldNotBeNil)
+
+		// the flags override the profile
+		_, err = runTagList("--profile", "envprofile", "--user", "user:wrong")
+		So(err, ShouldNotBeNil)
+
+		_, err = runTagList("--profile", "envprofile", "--config", "anonymous")
+		So(err, ShouldNotBeNil)
+
+		_, err = runTagList()
+		So(errors.Is(err, zerr.ErrNoURLProvided), ShouldBeTrue)
+
+		t.Setenv(profileEnvVar, "fileprofile")
+
+		_, err = runTagList()
+		So(err, ShouldBeNil)
+
+		_, err = runTagList("--profile", "anonymous")
+		So(err, ShouldNotBeNil)
+
+		Convey("default output format and certificates", func() {
+			t.Setenv(profileEnvVar, "envprofile")
+
+			cmd := NewImageCommand(mockService{})
+			So(cmd.ParseFlags([]string{}), ShouldBeNil)
+
+			searchConfig, err := GetSearchConfigFromFlags(cmd, mockService{})
+			So(err, ShouldBeNil)
+			So(searchConfig.outputFormat, ShouldEqual, "json")
+			So(searchConfig.user, ShouldEqual, "user:pass")
+
+			So(cmd.ParseFlags([]string{"-f", "yaml"}), ShouldBeNil)
+
+			searchConfig, err = GetSearchConfigFromFlags(cmd, mockService{})
+			So(err, ShouldBeNil)
+			So(searchConfig.outputFormat, ShouldEqual, "yaml")
+
+			certsDir := t.TempDir()
+			So(setConfigValue(configPath, "envprofile", certsDirConfig, certsDir), ShouldBeNil)
+
+			options, err := getHTTPClientOptionsFromFlags(cmd)
+			So(err, ShouldBeNil)
+			So(options.certsDir, ShouldEqual, certsDir)
+
+			// the certificates are missing
+			_, err = createHTTPClient(true, "localhost", options)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -17,6 +17,8 @@ type HotReloader struct {
 	watcher  *fsnotify.Watcher
 	filePath string
 	ctlr     *api.Controller
+	// SIGHUP reloads the log levels of the config file
+	hupCh chan os.Signal
 }
 
 func NewHotReloader(ctlr *api.Controller, filePath string) (*HotReloader, error) {
@@ -30,6 +32,7 @@ func NewHotReloader(ctlr *api.Controller, filePath string) (*HotReloader, error)
 		watcher:  watcher,
 		filePath: filePath,
 		ctlr:     ctlr,
+		hupCh:    make(chan os.Signal, 1),
 	}
 
 	return hotReloader, nil
@@ -53,7 +56,9 @@ func signalHandler(ctlr *api.Controller, sigCh chan os.Signal, ctx context.Conte
 	}
 }
 
-func initShutDownRoutine(ctlr *api.Controller, ctx context.Context, cancel context.CancelFunc) {
+func initShutDownRoutine(ctlr *api.Controller, ctx context.Context, cancel context.CancelFunc,
+	hupCh chan os.Signal,
+) {
 	sigCh := make(chan os.Signal, 1)
 
 	go signalHandler(ctlr, sigCh, ctx, cancel)
@@ -61,8 +66,28 @@ func initShutDownRoutine(ctlr *api.Controller, ctx context.Context, cancel conte
 	// block all async signals to this server
 	signal.Ignore()
 
-	// handle SIGINT and SIGHUP.
-	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	// handle SIGTERM and SIGINT.
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+
+	// SIGHUP doesn't stop the server, it reloads the log levels.
+	signal.Notify(hupCh, syscall.SIGHUP)
+}
+
+// reloadLogLevels applies the log levels of the config file, the ones changed at runtime are reset.
+func (hr *HotReloader) reloadLogLevels() {
+	log.Info().Msg("received SIGHUP, reloading the log levels")
+
+	newConfig := config.New()
+
+	if err := LoadConfiguration(newConfig, hr.filePath); err != nil {
+		log.Error().Err(err).Msg("couldn't reload the log levels, invalid config")
+
+		return
+	}
+
+	if err := hr.ctlr.SetLogLevels(newConfig.Log); err != nil {
+		log.Error().Err(err).Msg("couldn't reload the log levels")
+	}
 }
 
 func (hr *HotReloader) Start() context.Context {
@@ -70,7 +95,13 @@ func (hr *HotReloader) Start() context.Context {
 
 	done := make(chan bool)
 
-	initShutDownRoutine(hr.ctlr, reloadCtx, cancelFunc)
+	initShutDownRoutine(hr.ctlr, reloadCtx, cancelFunc, hr.hupCh)
+
+	go func() {
+		for range hr.hupCh {
+			hr.reloadLogLevels()
+		}
+	}()
 
 	// run watcher
 	go func() {
@@ -99,7 +130,7 @@ func (hr *HotReloader) Start() context.Context {
 						reloadCtx, cancelFunc = context.WithCancel(context.Background())
 
 						// init shutdown routine
-						initShutDownRoutine(hr.ctlr, reloadCtx, cancelFunc)
+						initShutDownRoutine(hr.ctlr, reloadCtx, cancelFunc, hr.hupCh)
 
 						hr.ctlr.LoadNewConfig(reloadCtx, newConfig)
 					}
@@ -6,6 +6,8 @@ package cli_test
 import (
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -13,6 +15,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"golang.org/x/crypto/bcrypt"
 
+	"zotregistry.io/zot/pkg/api/constants"
 	"zotregistry.io/zot/pkg/cli"
 	"zotregistry.io/zot/pkg/test"
 )
@@ -413,6 +416,94 @@ func TestConfigReloader(t *testing.T) {
 		So(found, ShouldBeTrue)
 	})
 
+	Convey("reload extensions config", t, func(c C) {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		rootDir := t.TempDir()
+
+		logFile, err := os.CreateTemp("", "zot-log*.txt")
+		So(err, ShouldBeNil)
+
+		defer os.Remove(logFile.Name()) // clean up
+
+		configTemplate := `{
+				"distSpecVersion": "1.1.0-dev",
+				"storage": {
+					"rootDirectory": "%s"
+				},
+				"http": {
+					"address": "127.0.0.1",
+					"port": "%s"
+				},
+				"log": {
+					"level": "debug",
+					"output": "%s"
+				},
+				"extensions": {
+					"search": {
+						"enable": %t
+					}
+				}
+			}`
+
+		cfgfile, err := os.CreateTemp("", "zot-test*.json")
+		So(err, ShouldBeNil)
+
+		defer os.Remove(cfgfile.Name()) // clean up
+
+		_, err = cfgfile.WriteString(fmt.Sprintf(configTemplate, rootDir, port, logFile.Name(), true))
+		So(err, ShouldBeNil)
+
+		err = cfgfile.Close()
+		So(err, ShouldBeNil)
+
+		os.Args = []string{"cli_test", "serve", cfgfile.Name()}
+		go func() {
+			err = cli.NewServerRootCmd().Execute()
+			So(err, ShouldBeNil)
+		}()
+
+		test.WaitTillServerReady(baseURL)
+
+		searchURL := baseURL + constants.FullSearchPrefix + "?query=" +
+			url.QueryEscape(`{ImageList(repo:"zot-test"){Results{RepoName}}}`)
+
+		resp, err := http.Get(searchURL) //nolint:noctx
+		So(err, ShouldBeNil)
+		resp.Body.Close()
+		So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+		// disable search
+		err = os.WriteFile(cfgfile.Name(),
+			[]byte(fmt.Sprintf(configTemplate, rootDir, port, logFile.Name(), false)), 0o600)
+		So(err, ShouldBeNil)
+
+		// wait for config reload
+		time.Sleep(2 * time.Second)
+
+		resp, err = http.Get(searchURL) //nolint:noctx
+		So(err, ShouldBeNil)
+		resp.Body.Close()
+		So(resp.StatusCode, ShouldEqual, http.StatusNotFound)
+
+		// enable it again
+		err = os.WriteFile(cfgfile.Name(),
+			[]byte(fmt.Sprintf(configTemplate, rootDir, port, logFile.Name(), true)), 0o600)
+		So(err, ShouldBeNil)
+
+		// wait for config reload
+		time.Sleep(2 * time.Second)
+
+		resp, err = http.Get(searchURL) //nolint:noctx
+		So(err, ShouldBeNil)
+		resp.Body.Close()
+		So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+		data, err := os.ReadFile(logFile.Name())
+		So(err, ShouldBeNil)
+		So(string(data), ShouldContainSubstring, "loaded new configuration settings")
+	})
+
 	Convey("reload bad config", t, func(c C) {
 		port := test.GetFreePort()
 		baseURL := test.GetBaseURL(port)
@@ -0,0 +1,191 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+	"github.com/spf13/cobra"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+)
+
+const credentialsHelperPrefix = "docker-credential-"
+
+// credentialsStore keeps the zli credentials in a docker credentials helper, like the OS keychain,
+// so they are shared with docker and other tools logged in to the same registry.
+type credentialsStore struct {
+	helper  string
+	program client.ProgramFunc
+}
+
+func newCredentialsStore(helper string) credentialsStore {
+	return credentialsStore{
+		helper:  helper,
+		program: client.NewShellProgramFunc(credentialsHelperPrefix + helper),
+	}
+}
+
+// defaultCredentialsHelper returns the helper using the native keychain of the platform.
+func defaultCredentialsHelper() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "osxkeychain"
+	case "windows":
+		return "wincred"
+	default:
+		return "secretservice"
+	}
+}
+
+// getCredentialsStoreFromFlags returns the credentials helper set in the config, or the one
+// of the native keychain.
+func getCredentialsStoreFromFlags(cmd *cobra.Command) (credentialsStore, error) {
+	configName := getConfigName(cmd)
+	if configName == "" {
+		return newCredentialsStore(defaultCredentialsHelper()), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return credentialsStore{}, err
+	}
+
+	helper, err := getConfigValue(path.Join(home, "/.zot"), configName, credstoreConfig)
+	if err != nil {
+		return credentialsStore{}, err
+	}
+
+	if helper == "" {
+		helper = defaultCredentialsHelper()
+	}
+
+	return newCredentialsStore(helper), nil
+}
+
+// get returns the credentials stored for the server, or empty ones if there are none.
+func (store credentialsStore) get(serverURL string) (string, string, error) {
+	key, err := credentialsKey(serverURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	creds, err := client.Get(store.program, key)
+	if err != nil {
+		if credentials.IsErrCredentialsNotFound(err) {
+			return "", "", nil
+		}
+
+		return "", "", fmt.Errorf("%w: %s: %s", zerr.ErrCredentialsHelper, store.helper, err)
+	}
+
+	return creds.Username, creds.Secret, nil
+}
+
+func (store credentialsStore) store(serverURL, username, secret string) error {
+	key, err := credentialsKey(serverURL)
+	if err != nil {
+		return err
+	}
+
+	err = client.Store(store.program, &credentials.Credentials{
+		ServerURL: key,
+		Username:  username,
+		Secret:    secret,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s", zerr.ErrCredentialsHelper, store.helper, err)
+	}
+
+	return nil
+}
+
+func (store credentialsStore) erase(serverURL string) error {
+	key, err := credentialsKey(serverURL)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Erase(store.program, key); err != nil && !credentials.IsErrCredentialsNotFound(err) {
+		return fmt.Errorf("%w: %s: %s", zerr.ErrCredentialsHelper, store.helper, err)
+	}
+
+	return nil
+}
+
+// credentialsKey returns the key the credentials of the server are stored under, the host of the server
+// like docker does, so the credentials of `docker login` are used as well.
+func credentialsKey(serverURL string) (string, error) {
+	parsedURL, err := url.Parse(serverURL)
+	if err != nil {
+		return "", err
+	}
+
+	if parsedURL.Host == "" {
+		return "", fmt.Errorf("%w: %s", zerr.ErrInvalidURL, serverURL)
+	}
+
+	return parsedURL.Host + strings.TrimSuffix(parsedURL.Path, "/"), nil
+}
+
+// apiKeyCredentialsURL returns the URL the ID of the API key created at login is stored under,
+// the key is revoked at logout.
+func apiKeyCredentialsURL(serverURL string) string {
+	return strings.TrimSuffix(serverURL, "/") + constants.APIKeyPath
+}
+
+// getProfileUser returns the credentials referenced by the config in the "username:password" format, if any.
+func getProfileUser(cmd *cobra.Command) (string, error) {
+	reference, err := getFlagOrConfigValue(cmd, "", credentialsConfig)
+	if err != nil || reference == "" {
+		return "", err
+	}
+
+	kind, source, _ := strings.Cut(reference, ":")
+
+	switch kind {
+	case "env":
+		user := os.Getenv(source)
+		if user == "" {
+			return "", fmt.Errorf("%w: the %s variable of the credentials is empty", zerr.ErrInvalidCLIParameter,
+				source)
+		}
+
+		return user, nil
+	case "file":
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the credentials: %w", err)
+		}
+
+		return strings.TrimSpace(string(content)), nil
+	default:
+		return "", fmt.Errorf("%w: the %s must be 'env:<variable>' or 'file:<path>', not '%s'",
+			zerr.ErrInvalidCLIParameter, credentialsConfig, reference)
+	}
+}
+
+// getStoredUser returns the stored credentials of the server in the "username:password" format
+// of the user flag, or an empty string if there are none or the helper isn't available.
+func getStoredUser(cmd *cobra.Command, serverURL string) string {
+	store, err := getCredentialsStoreFromFlags(cmd)
+	if err != nil {
+		return ""
+	}
+
+	username, secret, err := store.get(serverURL)
+	if err != nil || (username == "" && secret == "") {
+		return ""
+	}
+
+	return username + ":" + secret
+}
@@ -4,6 +4,7 @@
 package cli //nolint:testpackage
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -16,17 +17,25 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	regTypes "github.com/google/go-containerregistry/pkg/v1/types"
 	godigest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
 
 	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/api"
 	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
 	zcommon "zotregistry.io/zot/pkg/common"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
@@ -158,7 +167,7 @@ func TestSearchCVECmd(t *testing.T) {
 		err := cmd.Execute()
 		space := regexp.MustCompile(`\s+`)
 		str := space.ReplaceAllString(buff.String(), " ")
-		So(strings.TrimSpace(str), ShouldEqual, "ID SEVERITY TITLE dummyCVEID HIGH Title of that CVE")
+		So(strings.TrimSpace(str), ShouldEqual, "ID SEVERITY FIX TITLE dummyCVEID HIGH AVAILABLE Title of that CVE")
 		So(err, ShouldBeNil)
 	})
 
@@ -224,7 +233,7 @@ func TestSearchCVECmd(t *testing.T) {
 		err := cveCmd.Execute()
 		space := regexp.MustCompile(`\s+`)
 		str := space.ReplaceAllString(buff.String(), " ")
-		So(strings.TrimSpace(str), ShouldEqual, "ID SEVERITY TITLE dummyCVEID HIGH Title of that CVE")
+		So(strings.TrimSpace(str), ShouldEqual, "ID SEVERITY FIX TITLE dummyCVEID HIGH AVAILABLE Title of that CVE")
 		So(err, ShouldBeNil)
 	})
 
@@ -241,7 +250,7 @@ func TestSearchCVECmd(t *testing.T) {
 		// Output is supposed to be in json lines format, keep all spaces as is for verification
 		So(buff.String(), ShouldEqual, `{"Tag":"dummyImageName:tag","CVEList":`+
 			`[{"Id":"dummyCVEID","Severity":"HIGH","Title":"Title of that CVE",`+
-			`"Description":"Description of the CVE","PackageList":[{"Name":"packagename",`+
+			`"Description":"Description of the CVE","FixStatus":"AVAILABLE","PackageList":[{"Name":"packagename",`+
 			`"InstalledVersion":"installedver","FixedVersion":"fixedver"}]}]}`+"\n")
 		So(err, ShouldBeNil)
 	})
@@ -259,7 +268,7 @@ func TestSearchCVECmd(t *testing.T) {
 		space := regexp.MustCompile(`\s+`)
 		str := space.ReplaceAllString(buff.String(), " ")
 		So(strings.TrimSpace(str), ShouldEqual, `--- tag: dummyImageName:tag cvelist: - id: dummyCVEID`+
-			` severity: HIGH title: Title of that CVE description: Description of the CVE packagelist: `+
+			` severity: HIGH title: Title of that CVE description: Description of the CVE fixstatus: AVAILABLE packagelist: `+
 			`- name: packagename installedversion: installedver fixedversion: fixedver`)
 		So(err, ShouldBeNil)
 	})
@@ -470,7 +479,7 @@ func TestNegativeServerResponse(t *testing.T) {
 
 		dir := t.TempDir()
 
-		imageStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), nil, nil)
 
 		storeController := storage.StoreController{
@@ -647,7 +656,7 @@ func TestServerCVEResponse(t *testing.T) {
 		str := space.ReplaceAllString(buff.String(), " ")
 		str = strings.TrimSpace(str)
 		So(err, ShouldBeNil)
-		So(str, ShouldContainSubstring, "ID SEVERITY TITLE")
+		So(str, ShouldContainSubstring, "ID SEVERITY FIX TITLE")
 		So(str, ShouldContainSubstring, "CVE")
 	})
 
@@ -665,7 +674,7 @@ func TestServerCVEResponse(t *testing.T) {
 		str := space.ReplaceAllString(buff.String(), " ")
 		str = strings.TrimSpace(str)
 		So(err, ShouldBeNil)
-		So(str, ShouldContainSubstring, "ID SEVERITY TITLE")
+		So(str, ShouldContainSubstring, "ID SEVERITY FIX TITLE")
 		So(str, ShouldContainSubstring, "CVE-C1")
 		So(str, ShouldNotContainSubstring, "CVE-2")
 	})
@@ -684,7 +693,7 @@ func TestServerCVEResponse(t *testing.T) {
 		str := space.ReplaceAllString(buff.String(), " ")
 		str = strings.TrimSpace(str)
 		So(err, ShouldBeNil)
-		So(str, ShouldContainSubstring, "ID SEVERITY TITLE")
+		So(str, ShouldContainSubstring, "ID SEVERITY FIX TITLE")
 		So(str, ShouldContainSubstring, "CVE-2")
 		So(str, ShouldNotContainSubstring, "CVE-1")
 	})
@@ -978,6 +987,9 @@ func TestCVESort(t *testing.T) {
 						ID:       "CVE-2023-2975",
 						Severity: "HIGH",
 						Title:    "AES-SIV cipher implementation contains a bug that can break",
+						PackageList: []cvemodel.Package{
+							{Name: "openssl", InstalledVersion: "3.0.8", FixedVersion: "3.0.10"},
+						},
 					},
 					"CVE-2023-3446": {
 						ID:       "CVE-2023-3446",
@@ -1018,12 +1030,12 @@ func TestCVESort(t *testing.T) {
 		str := space.ReplaceAllString(buff.String(), " ")
 		actual := strings.TrimSpace(str)
 		So(actual, ShouldResemble,
-			"ID SEVERITY TITLE "+
-				"CVE-2023-3446 CRITICAL Excessive time spent checking DH keys and par... "+
-				"CVE-2023-2975 HIGH AES-SIV cipher implementation contains a bug ... "+
-				"CVE-2023-2650 MEDIUM Possible DoS translating ASN.1 object identif... "+
-				"CVE-2023-3817 MEDIUM Excessive time spent checking DH q parameter ... "+
-				"CVE-2023-1255 LOW Input buffer over-read in AES-XTS implementat...")
+			"ID SEVERITY FIX TITLE "+
+				"CVE-2023-3446 CRITICAL UNAVAILABLE Excessive time spent checking DH keys and par... "+
+				"CVE-2023-2975 HIGH AVAILABLE AES-SIV cipher implementation contains a bug ... "+
+				"CVE-2023-2650 MEDIUM UNAVAILABLE Possible DoS translating ASN.1 object identif... "+
+				"CVE-2023-3817 MEDIUM UNAVAILABLE Excessive time spent checking DH q parameter ... "+
+				"CVE-2023-1255 LOW UNAVAILABLE Input buffer over-read in AES-XTS implementat...")
 
 		args = []string{"list", "repo:tag", "--sort-by", "alpha-asc", "--url", baseURL}
 		cmd = NewCVECommand(new(searchService))
@@ -1036,12 +1048,12 @@ func TestCVESort(t *testing.T) {
 		str = space.ReplaceAllString(buff.String(), " ")
 		actual = strings.TrimSpace(str)
 		So(actual, ShouldResemble,
-			"ID SEVERITY TITLE "+
-				"CVE-2023-1255 LOW Input buffer over-read in AES-XTS implementat... "+
-				"CVE-2023-2650 MEDIUM Possible DoS translating ASN.1 object identif... "+
-				"CVE-2023-2975 HIGH AES-SIV cipher implementation contains a bug ... "+
-				"CVE-2023-3446 CRITICAL Excessive time spent checking DH keys and par... "+
-				"CVE-2023-3817 MEDIUM Excessive time spent checking DH q parameter ...")
+			"ID SEVERITY FIX TITLE "+
+				"CVE-2023-1255 LOW UNAVAILABLE Input buffer over-read in AES-XTS implementat... "+
+				"CVE-2023-2650 MEDIUM UNAVAILABLE Possible DoS translating ASN.1 object identif... "+
+				"CVE-2023-2975 HIGH AVAILABLE AES-SIV cipher implementation contains a bug ... "+
+				"CVE-2023-3446 CRITICAL UNAVAILABLE Excessive time spent checking DH keys and par... "+
+				"CVE-2023-3817 MEDIUM UNAVAILABLE Excessive time spent checking DH q parameter ...")
 
 		args = []string{"list", "repo:tag", "--sort-by", "alpha-dsc", "--url", baseURL}
 		cmd = NewCVECommand(new(searchService))
@@ -1054,12 +1066,149 @@ func TestCVESort(t *testing.T) {
 		str = space.ReplaceAllString(buff.String(), " ")
 		actual = strings.TrimSpace(str)
 		So(actual, ShouldResemble,
-			"ID SEVERITY TITLE "+
-				"CVE-2023-3817 MEDIUM Excessive time spent checking DH q parameter ... "+
-				"CVE-2023-3446 CRITICAL Excessive time spent checking DH keys and par... "+
-				"CVE-2023-2975 HIGH AES-SIV cipher implementation contains a bug ... "+
-				"CVE-2023-2650 MEDIUM Possible DoS translating ASN.1 object identif... "+
-				"CVE-2023-1255 LOW Input buffer over-read in AES-XTS implementat...")
+			"ID SEVERITY FIX TITLE "+
+				"CVE-2023-3817 MEDIUM UNAVAILABLE Excessive time spent checking DH q parameter ... "+
+				"CVE-2023-3446 CRITICAL UNAVAILABLE Excessive time spent checking DH keys and par... "+
+				"CVE-2023-2975 HIGH AVAILABLE AES-SIV cipher implementation contains a bug ... "+
+				"CVE-2023-2650 MEDIUM UNAVAILABLE Possible DoS translating ASN.1 object identif... "+
+				"CVE-2023-1255 LOW UNAVAILABLE Input buffer over-read in AES-XTS implementat...")
+	})
+
+	Convey("test fix status filter", t, func() {
+		args := []string{"list", "repo:tag", "--fix-status", "available", "--url", baseURL}
+		cmd := NewCVECommand(new(searchService))
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		So(err, ShouldBeNil)
+		str := space.ReplaceAllString(buff.String(), " ")
+		actual := strings.TrimSpace(str)
+		So(actual, ShouldResemble,
+			"ID SEVERITY FIX TITLE "+
+				"CVE-2023-2975 HIGH AVAILABLE AES-SIV cipher implementation contains a bug ...")
+
+		args = []string{"list", "repo:tag", "--fix-status", "partial", "--url", baseURL}
+		cmd = NewCVECommand(new(searchService))
+		buff = bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs(args)
+		err = cmd.Execute()
+		So(err, ShouldBeNil)
+		So(strings.TrimSpace(buff.String()), ShouldEqual, "No CVEs found for image")
+
+		args = []string{"list", "repo:tag", "--fix-status", "unavailable", "--url", baseURL}
+		cmd = NewCVECommand(new(searchService))
+		buff = bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs(args)
+		err = cmd.Execute()
+		So(err, ShouldBeNil)
+		str = space.ReplaceAllString(buff.String(), " ")
+		So(str, ShouldNotContainSubstring, "CVE-2023-2975")
+		So(str, ShouldContainSubstring, "CVE-2023-1255 LOW UNAVAILABLE")
+	})
+
+	runCVECommand := func(args ...string) (string, error) {
+		cmd := NewCVECommand(new(searchService))
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs(append(args, "--url", baseURL))
+		err := cmd.Execute()
+
+		return buff.String(), err
+	}
+
+	Convey("test severity filter and threshold", t, func() {
+		out, err := runCVECommand("list", "repo:tag", "--severity", "HIGH")
+		So(err, ShouldBeNil)
+		str := space.ReplaceAllString(out, " ")
+		So(strings.TrimSpace(str), ShouldResemble,
+			"ID SEVERITY FIX TITLE "+
+				"CVE-2023-3446 CRITICAL UNAVAILABLE Excessive time spent checking DH keys and par... "+
+				"CVE-2023-2975 HIGH AVAILABLE AES-SIV cipher implementation contains a bug ...")
+
+		_, err = runCVECommand("list", "repo:tag", "--severity", "severe")
+		So(err, ShouldNotBeNil)
+
+		out, err = runCVECommand("list", "repo:tag", "--fail-on", "high")
+		So(errors.Is(err, zerr.ErrCVESeverityThreshold), ShouldBeTrue)
+		So(err.Error(), ShouldContainSubstring, "2 CVEs with a severity of high or higher")
+		So(out, ShouldContainSubstring, "CVE-2023-1255")
+
+		// the threshold only applies to the listed CVEs
+		_, err = runCVECommand("list", "repo:tag", "--fail-on", "medium", "--fix-status", "unavailable",
+			"--severity", "critical")
+		So(errors.Is(err, zerr.ErrCVESeverityThreshold), ShouldBeTrue)
+
+		_, err = runCVECommand("list", "repo:tag", "--fail-on", "critical", "--fix-status", "available")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("test report formats", t, func() {
+		out, err := runCVECommand("list", "repo:tag", "-f", "csv", "--severity", "high")
+		So(err, ShouldBeNil)
+		So(out, ShouldEqual, "ID,Severity,FixStatus,Title,Package,InstalledVersion,FixedVersion\n"+
+			"CVE-2023-3446,CRITICAL,UNAVAILABLE,Excessive time spent checking DH keys and parenthesis,,,\n"+
+			"CVE-2023-2975,HIGH,AVAILABLE,AES-SIV cipher implementation contains a bug that can break,"+
+			"openssl,3.0.8,3.0.10\n")
+
+		outputFile := path.Join(t.TempDir(), "cves.sarif")
+		out, err = runCVECommand("list", "repo:tag", "-f", "sarif", "--output", outputFile)
+		So(err, ShouldBeNil)
+		So(out, ShouldBeEmpty)
+
+		content, err := os.ReadFile(outputFile)
+		So(err, ShouldBeNil)
+
+		sarif := sarifReport{}
+		err = json.Unmarshal(content, &sarif)
+		So(err, ShouldBeNil)
+		So(sarif.Version, ShouldEqual, "2.1.0")
+		So(sarif.Runs, ShouldHaveLength, 1)
+		So(sarif.Runs[0].Results, ShouldHaveLength, 5)
+		So(sarif.Runs[0].Results[0].RuleID, ShouldEqual, "CVE-2023-3446")
+		So(sarif.Runs[0].Results[0].Level, ShouldEqual, "error")
+		So(sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI, ShouldEqual, "repo:tag")
+		So(sarif.Runs[0].Results[4].Level, ShouldEqual, "note")
+
+		out, err = runCVECommand("list", "repo:tag", "-f", "cyclonedx-vex", "--fix-status", "available")
+		So(err, ShouldBeNil)
+
+		vex := vexDocument{}
+		err = json.Unmarshal([]byte(out), &vex)
+		So(err, ShouldBeNil)
+		So(vex.BOMFormat, ShouldEqual, "CycloneDX")
+		So(vex.Metadata.Component.Name, ShouldEqual, "repo:tag")
+		So(vex.Components, ShouldResemble, []vexComponent{
+			{BOMRef: "openssl@3.0.8", Type: "library", Name: "openssl", Version: "3.0.8"},
+		})
+		So(vex.Vulnerabilities, ShouldHaveLength, 1)
+		So(vex.Vulnerabilities[0].ID, ShouldEqual, "CVE-2023-2975")
+		So(vex.Vulnerabilities[0].Ratings[0].Severity, ShouldEqual, "high")
+		So(vex.Vulnerabilities[0].Recommendation, ShouldEqual, "Upgrade openssl to 3.0.10")
+		So(vex.Vulnerabilities[0].Affects[0].Ref, ShouldEqual, "openssl@3.0.8")
+
+		// the reports are written even without CVEs
+		out, err = runCVECommand("list", "repo:tag", "-f", "csv", "--fix-status", "partial")
+		So(err, ShouldBeNil)
+		So(out, ShouldEqual, "ID,Severity,FixStatus,Title,Package,InstalledVersion,FixedVersion\n")
+
+		// the image subcommand supports them as well
+		cmd := NewImageCommand(new(searchService))
+		buff := bytes.NewBufferString("")
+		cmd.SetOut(buff)
+		cmd.SetErr(buff)
+		cmd.SetArgs([]string{"cve", "repo:tag", "-f", "csv", "--severity", "critical", "--fail-on", "critical",
+			"--url", baseURL})
+		err = cmd.Execute()
+		So(errors.Is(err, zerr.ErrCVESeverityThreshold), ShouldBeTrue)
+		So(buff.String(), ShouldContainSubstring, "CVE-2023-3446,CRITICAL")
+		So(buff.String(), ShouldNotContainSubstring, "CVE-2023-2975")
 	})
 }
 
@@ -1193,7 +1342,34 @@ func TestCVECommandGQL(t *testing.T) {
 			space := regexp.MustCompile(`\s+`)
 			str := space.ReplaceAllString(buff.String(), " ")
 			actual := strings.TrimSpace(str)
-			So(actual, ShouldContainSubstring, "dummyCVEID HIGH Title of that CVE")
+			So(actual, ShouldContainSubstring, "dummyCVEID HIGH AVAILABLE Title of that CVE")
+		})
+
+		Convey("image with fix status", func() {
+			args := []string{"list", "repo:tag", "--fix-status", "available", "--config", "cvetest"}
+			cmd := NewCVECommand(mockService{
+				getCveByImageGQLFn: func(ctx context.Context, config searchConfig, username, password,
+					imageName, searchedCVE string) (*cveResult, error,
+				) {
+					So(config.fixStatus, ShouldEqual, cmdflags.FixStatusAvailable)
+
+					return &cveResult{}, nil
+				},
+			})
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			So(err, ShouldBeNil)
+
+			args = []string{"list", "repo:tag", "--fix-status", "bad-status", "--config", "cvetest"}
+			cmd = NewCVECommand(mockService{})
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(args)
+			err = cmd.Execute()
+			So(err, ShouldNotBeNil)
 		})
 
 		Convey("image db download wait", func() {
@@ -1518,3 +1694,347 @@ func (service *mockServiceForRetry) getTagsForCVEGQL(ctx context.Context, config
 
 	return service.mockService.getTagsForCVEGQL(ctx, config, username, password, repo, cveID)
 }
+
+func TestCVEListAllImages(t *testing.T) {
+	Convey("the CVEs of all the images", t, func() {
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		const imageCount = 25
+
+		images := []zcommon.ImageSummary{}
+
+		for i := 0; i < imageCount; i++ {
+			images = append(images, zcommon.ImageSummary{RepoName: fmt.Sprintf("repo%02d", i), Tag: "1.0"})
+		}
+
+		var running, maxRunning int32
+
+		service := mockService{
+			getImagesGQLFn: func(ctx context.Context, config searchConfig, username, password, imageName string,
+			) (*zcommon.ImageListResponse, error) {
+				result := &zcommon.ImageListResponse{}
+				result.Results = images
+
+				return result, nil
+			},
+			getCveByImageGQLFn: func(ctx context.Context, config searchConfig, username, password,
+				imageName, searchedCVE string,
+			) (*cveResult, error) {
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+
+				for {
+					previous := atomic.LoadInt32(&maxRunning)
+					if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+						break
+					}
+				}
+
+				time.Sleep(10 * time.Millisecond)
+
+				if imageName == "repo13:1.0" {
+					return &cveResult{}, zerr.ErrInjected
+				}
+
+				cves := []cve{{ID: "CVE-LOW-" + imageName, Severity: "LOW"}}
+
+				if imageName == "repo07:1.0" {
+					cves = append(cves, cve{ID: "CVE-CRITICAL", Severity: "CRITICAL", Title: "critical"})
+				}
+
+				return &cveResult{Data: cveData{CVEListForImage: cveListForImage{CVEList: cves}}}, nil
+			},
+		}
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"cvealltest","url":"%s",`+
+			`"showspinner":true,"concurrency":"4"}]}`, baseURL))
+		defer os.Remove(configPath)
+
+		runCVECommand := func(args ...string) (string, string, error) {
+			cmd := NewCVECommand(service)
+			outBuff := bytes.NewBufferString("")
+			errBuff := bytes.NewBufferString("")
+			cmd.SetOut(outBuff)
+			cmd.SetErr(errBuff)
+			cmd.SetArgs(append(append([]string{"list"}, args...), "--config", "cvealltest"))
+			err := cmd.Execute()
+
+			return outBuff.String(), errBuff.String(), err
+		}
+
+		_, _, err := runCVECommand()
+		So(errors.Is(err, zerr.ErrInjected), ShouldBeTrue)
+
+		images = append(images[:13], images[14:]...)
+		maxRunning = 0
+
+		out, stderr, err := runCVECommand("--severity", "high", "--fail-on", "critical")
+		So(errors.Is(err, zerr.ErrCVESeverityThreshold), ShouldBeTrue)
+		So(out, ShouldContainSubstring, "repo07:1.0")
+		So(out, ShouldContainSubstring, "CVE-CRITICAL")
+		So(out, ShouldNotContainSubstring, "CVE-LOW")
+		So(stderr, ShouldContainSubstring, fmt.Sprintf("%d/%d images", imageCount-1, imageCount-1))
+		So(atomic.LoadInt32(&maxRunning), ShouldBeBetweenOrEqual, 2, 4)
+
+		out, _, err = runCVECommand("-f", "json")
+		So(err, ShouldBeNil)
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		So(lines, ShouldHaveLength, imageCount-1)
+
+		result := imageCVEs{}
+		So(json.Unmarshal([]byte(lines[0]), &result), ShouldBeNil)
+		So(result.Image, ShouldEqual, "repo00:1.0")
+		So(result.CVEs[0].ID, ShouldEqual, "CVE-LOW-repo00:1.0")
+
+		_, _, err = runCVECommand("-f", "sarif")
+		So(errors.Is(err, zerr.ErrInvalidOutputFormat), ShouldBeTrue)
+
+		images = nil
+
+		out, _, err = runCVECommand()
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "No images found")
+
+		Convey("with an invalid concurrency", func() {
+			configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"cvealltest","url":"%s",`+
+				`"concurrency":"none"}]}`, baseURL))
+			defer os.Remove(configPath)
+
+			_, _, err := runCVECommand()
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		})
+	})
+}
+
+func TestCVEScanArchive(t *testing.T) {
+	Convey("the CVEs of the images of an archive", t, func() {
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		scanned := []string{}
+
+		service := mockService{
+			getCveByImageGQLFn: func(ctx context.Context, config searchConfig, username, password,
+				imageName, searchedCVE string,
+			) (*cveResult, error) {
+				scanned = append(scanned, imageName)
+
+				// the image is in the registry while it's scanned
+				repo, digest, _ := strings.Cut(imageName, "@")
+
+				resp, err := resty.R().Head(fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, repo, digest))
+				if err != nil || resp.StatusCode() != http.StatusOK {
+					return &cveResult{}, zerr.ErrManifestNotFound
+				}
+
+				cves := []cve{
+					{ID: "CVE-LOW", Severity: "LOW"},
+					{ID: "CVE-CRITICAL", Severity: "CRITICAL", Title: "critical"},
+				}
+
+				return &cveResult{Data: cveData{CVEListForImage: cveListForImage{CVEList: cves}}}, nil
+			},
+		}
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"scanarchivetest","url":"%s",`+
+			`"showspinner":false}]}`, baseURL))
+		defer os.Remove(configPath)
+
+		runScanArchive := func(args ...string) (string, error) {
+			cmd := NewCVECommand(service)
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(append([]string{"scan-archive"}, args...), "--config", "scanarchivetest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		// the temporary tags are deleted after the scans
+		checkNoTags := func(repo string) {
+			resp, err := resty.R().Get(fmt.Sprintf("%s/v2/%s/tags/list", baseURL, repo))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			tags := struct {
+				Tags []string `json:"tags"`
+			}{}
+			So(json.Unmarshal(resp.Body(), &tags), ShouldBeNil)
+			So(tags.Tags, ShouldBeEmpty)
+		}
+
+		Convey("of an OCI layout", func() {
+			image := CreateRandomImage()
+			archivePath := path.Join(t.TempDir(), "image.tar")
+			So(writeOCIArchive(archivePath, image, "alpine:3.18"), ShouldBeNil)
+
+			out, err := runScanArchive(archivePath)
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "CVE-CRITICAL")
+			So(out, ShouldContainSubstring, "CVE-LOW")
+			So(scanned, ShouldResemble, []string{"zli/scan-archive@" + image.DigestStr()})
+			checkNoTags("zli/scan-archive")
+
+			out, err = runScanArchive(archivePath, "--repo", "scans", "--severity", "high", "--fail-on", "critical",
+				"-f", "json")
+			So(errors.Is(err, zerr.ErrCVESeverityThreshold), ShouldBeTrue)
+			So(out, ShouldContainSubstring, "CVE-CRITICAL")
+			So(out, ShouldNotContainSubstring, "CVE-LOW")
+			So(scanned[1], ShouldEqual, "scans@"+image.DigestStr())
+			checkNoTags("scans")
+
+			out, err = runScanArchive(archivePath, "-f", "sarif")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "alpine:3.18")
+
+			_, err = runScanArchive(archivePath, "--repo", "Invalid Repo")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("of a docker archive", func() {
+			first, err := random.Image(1024, 2)
+			So(err, ShouldBeNil)
+
+			second, err := random.Image(1024, 1)
+			So(err, ShouldBeNil)
+
+			firstTag, err := name.NewTag("app:1.0")
+			So(err, ShouldBeNil)
+
+			secondTag, err := name.NewTag("app:2.0")
+			So(err, ShouldBeNil)
+
+			archivePath := path.Join(t.TempDir(), "images.tar")
+			err = tarball.MultiWriteToFile(archivePath, map[name.Tag]v1.Image{firstTag: first, secondTag: second})
+			So(err, ShouldBeNil)
+
+			out, err := runScanArchive(archivePath)
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "app:1.0")
+			So(out, ShouldContainSubstring, "app:2.0")
+			So(scanned, ShouldHaveLength, 2)
+			checkNoTags("zli/scan-archive")
+
+			// the reports are about a single image
+			_, err = runScanArchive(archivePath, "-f", "csv")
+			So(errors.Is(err, zerr.ErrInvalidOutputFormat), ShouldBeTrue)
+		})
+
+		Convey("of an invalid archive", func() {
+			archivePath := path.Join(t.TempDir(), "invalid.tar")
+
+			So(os.WriteFile(archivePath, []byte("not an archive"), 0o600), ShouldBeNil)
+
+			_, err := runScanArchive(archivePath)
+			So(errors.Is(err, zerr.ErrInvalidImageArchive), ShouldBeTrue)
+
+			file, err := os.Create(archivePath)
+			So(err, ShouldBeNil)
+
+			writer := tar.NewWriter(file)
+			So(writer.WriteHeader(&tar.Header{Name: "README", Mode: 0o600}), ShouldBeNil)
+			So(writer.Close(), ShouldBeNil)
+			So(file.Close(), ShouldBeNil)
+
+			_, err = runScanArchive(archivePath)
+			So(errors.Is(err, zerr.ErrInvalidImageArchive), ShouldBeTrue)
+
+			_, err = runScanArchive(path.Join(t.TempDir(), "missing.tar"))
+			So(err, ShouldNotBeNil)
+			So(scanned, ShouldBeEmpty)
+		})
+	})
+}
+
+// writeOCIArchive writes the image to a tar archive of an OCI layout, under the ref name.
+func writeOCIArchive(archivePath string, image Image, refName string) error {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	writer := tar.NewWriter(file)
+
+	writeFile := func(name string, content []byte) error {
+		if err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))}); err != nil {
+			return err
+		}
+
+		_, err := writer.Write(content)
+
+		return err
+	}
+
+	configBlob, err := json.Marshal(image.Config)
+	if err != nil {
+		return err
+	}
+
+	manifestBlob, err := json.Marshal(image.Manifest)
+	if err != nil {
+		return err
+	}
+
+	index, err := json.Marshal(ispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ispec.MediaTypeImageIndex,
+		Manifests: []ispec.Descriptor{{
+			MediaType:   ispec.MediaTypeImageManifest,
+			Digest:      image.Digest(),
+			Size:        int64(len(manifestBlob)),
+			Annotations: map[string]string{ispec.AnnotationRefName: refName},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(ispec.ImageLayoutFile, []byte(`{"imageLayoutVersion":"1.0.0"}`)); err != nil {
+		return err
+	}
+
+	for _, blob := range append([][]byte{configBlob, manifestBlob}, image.Layers...) {
+		if err := writeFile(path.Join("blobs/sha256", godigest.FromBytes(blob).Encoded()), blob); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFile("index.json", index); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,376 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	zerr "zotregistry.io/zot/errors"
+	cvemodel "zotregistry.io/zot/pkg/extensions/search/cve/model"
+)
+
+// the formats the CVEs of an image can be exported in, besides the text/json/yaml ones.
+const (
+	sarifFormat        = "sarif"
+	csvFormat          = "csv"
+	cycloneDXVEXFormat = "cyclonedx-vex"
+
+	cveReportFormatsStr = sarifFormat + "/" + csvFormat + "/" + cycloneDXVEXFormat
+)
+
+const (
+	sarifVersion        = "2.1.0"
+	sarifSchema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	cycloneDXVersion    = "1.5"
+	cveReportToolName   = "zli"
+	cveReportToolURL    = "https://zotregistry.io"
+	sarifLevelError     = "error"
+	sarifLevelWarning   = "warning"
+	sarifLevelNote      = "note"
+	vexAnalysisInTriage = "in_triage"
+)
+
+type sarifReport struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	FullDescription      sarifMessage        `json:"fullDescription"`
+	DefaultConfiguration sarifRuleLevel      `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleLevel struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type vexDocument struct {
+	BOMFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	Version         int                `json:"version"`
+	Metadata        vexMetadata        `json:"metadata"`
+	Components      []vexComponent     `json:"components"`
+	Vulnerabilities []vexVulnerability `json:"vulnerabilities"`
+}
+
+type vexMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     []vexTool    `json:"tools"`
+	Component vexComponent `json:"component"`
+}
+
+type vexTool struct {
+	Name string `json:"name"`
+}
+
+type vexComponent struct {
+	BOMRef  string `json:"bom-ref"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type vexVulnerability struct {
+	ID             string      `json:"id"`
+	Description    string      `json:"description,omitempty"`
+	Recommendation string      `json:"recommendation,omitempty"`
+	Ratings        []vexRating `json:"ratings"`
+	Analysis       vexAnalysis `json:"analysis"`
+	Affects        []vexAffect `json:"affects"`
+}
+
+type vexRating struct {
+	Severity string `json:"severity"`
+}
+
+type vexAnalysis struct {
+	State string `json:"state"`
+}
+
+type vexAffect struct {
+	Ref string `json:"ref"`
+}
+
+func isCVEReportFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case sarifFormat, csvFormat, cycloneDXVEXFormat:
+		return true
+	default:
+		return false
+	}
+}
+
+// cveReport returns the CVEs of the image as a report in one of the sarif, csv or cyclonedx-vex formats.
+func cveReport(format, image string, cves []cve) (string, error) {
+	switch strings.ToLower(format) {
+	case sarifFormat:
+		return cveReportSARIF(image, cves)
+	case csvFormat:
+		return cveReportCSV(cves)
+	case cycloneDXVEXFormat:
+		return cveReportCycloneDXVEX(image, cves)
+	default:
+		return "", zerr.ErrInvalidOutputFormat
+	}
+}
+
+func cveReportSARIF(image string, cves []cve) (string, error) {
+	rules := []sarifRule{}
+	results := []sarifResult{}
+
+	for _, cve := range cves {
+		level := sarifLevel(cve.Severity)
+
+		rules = append(rules, sarifRule{
+			ID:                   cve.ID,
+			ShortDescription:     sarifMessage{Text: cve.Title},
+			FullDescription:      sarifMessage{Text: cve.Description},
+			DefaultConfiguration: sarifRuleLevel{Level: level},
+			Properties:           sarifRuleProperties{Tags: []string{"vulnerability", "security", cve.Severity}},
+		})
+
+		message := fmt.Sprintf("%s (%s): %s", cve.ID, cve.Severity, cve.Title)
+
+		for _, pkg := range cve.PackageList {
+			message += fmt.Sprintf("\nPackage: %s, installed version: %s, fixed version: %s",
+				pkg.Name, pkg.InstalledVersion, pkg.FixedVersion)
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  cve.ID,
+			Level:   level,
+			Message: sarifMessage{Text: message},
+			Locations: []sarifLocation{
+				{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: image}}},
+			},
+		})
+	}
+
+	report := sarifReport{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           cveReportToolName,
+				InformationURI: cveReportToolURL,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	body, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(body) + "\n", nil
+}
+
+// cveReportCSV returns a row for each package affected by a CVE, or a single row without a package
+// if the affected packages are unknown.
+func cveReportCSV(cves []cve) (string, error) {
+	var builder strings.Builder
+
+	writer := csv.NewWriter(&builder)
+
+	rows := [][]string{{"ID", "Severity", "FixStatus", "Title", "Package", "InstalledVersion", "FixedVersion"}}
+
+	for _, cve := range cves {
+		if len(cve.PackageList) == 0 {
+			rows = append(rows, []string{cve.ID, cve.Severity, cve.FixStatus, cve.Title, "", "", ""})
+
+			continue
+		}
+
+		for _, pkg := range cve.PackageList {
+			rows = append(rows, []string{cve.ID, cve.Severity, cve.FixStatus, cve.Title,
+				pkg.Name, pkg.InstalledVersion, pkg.FixedVersion})
+		}
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+// cveReportCycloneDXVEX returns the CVEs as the vulnerabilities of a CycloneDX VEX document, affecting
+// the packages of the image. The exploitability of the CVEs isn't known, so they are all marked as in triage.
+func cveReportCycloneDXVEX(image string, cves []cve) (string, error) {
+	imageComponent := vexComponent{BOMRef: image, Type: "container", Name: image}
+	components := []vexComponent{}
+	knownComponents := map[string]bool{}
+	vulnerabilities := []vexVulnerability{}
+
+	for _, cve := range cves {
+		affects := []vexAffect{}
+		upgrades := []string{}
+
+		for _, pkg := range cve.PackageList {
+			ref := pkg.Name + "@" + pkg.InstalledVersion
+
+			if !knownComponents[ref] {
+				knownComponents[ref] = true
+
+				components = append(components, vexComponent{
+					BOMRef:  ref,
+					Type:    "library",
+					Name:    pkg.Name,
+					Version: pkg.InstalledVersion,
+				})
+			}
+
+			affects = append(affects, vexAffect{Ref: ref})
+
+			if pkg.FixedVersion != "" {
+				upgrades = append(upgrades, fmt.Sprintf("%s to %s", pkg.Name, pkg.FixedVersion))
+			}
+		}
+
+		if len(affects) == 0 {
+			affects = append(affects, vexAffect{Ref: imageComponent.BOMRef})
+		}
+
+		recommendation := ""
+		if len(upgrades) > 0 {
+			recommendation = "Upgrade " + strings.Join(upgrades, ", ")
+		}
+
+		vulnerabilities = append(vulnerabilities, vexVulnerability{
+			ID:             cve.ID,
+			Description:    cve.Description,
+			Recommendation: recommendation,
+			Ratings:        []vexRating{{Severity: strings.ToLower(cve.Severity)}},
+			Analysis:       vexAnalysis{State: vexAnalysisInTriage},
+			Affects:        affects,
+		})
+	}
+
+	document := vexDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: cycloneDXVersion,
+		Version:     1,
+		Metadata: vexMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     []vexTool{{Name: cveReportToolName}},
+			Component: imageComponent,
+		},
+		Components:      components,
+		Vulnerabilities: vulnerabilities,
+	}
+
+	body, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(body) + "\n", nil
+}
+
+func sarifLevel(severity string) string {
+	switch cvemodel.SeverityValue(strings.ToUpper(severity)) {
+	case cvemodel.Critical, cvemodel.High:
+		return sarifLevelError
+	case cvemodel.Medium:
+		return sarifLevelWarning
+	default:
+		return sarifLevelNote
+	}
+}
+
+// filterCVEsBySeverity returns the CVEs with a severity at least as high as the given one.
+func filterCVEsBySeverity(cves []cve, minSeverity string) []cve {
+	if minSeverity == "" {
+		return cves
+	}
+
+	filtered := []cve{}
+
+	for _, cve := range cves {
+		if isSeverityAtLeast(cve.Severity, minSeverity) {
+			filtered = append(filtered, cve)
+		}
+	}
+
+	return filtered
+}
+
+// checkCVESeverityThreshold returns an error if any of the CVEs has a severity at least as high
+// as the threshold.
+func checkCVESeverityThreshold(cves []cve, threshold string) error {
+	if threshold == "" {
+		return nil
+	}
+
+	count := 0
+
+	for _, cve := range cves {
+		if isSeverityAtLeast(cve.Severity, threshold) {
+			count++
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%w: %d CVEs with a severity of %s or higher", zerr.ErrCVESeverityThreshold,
+			count, threshold)
+	}
+
+	return nil
+}
+
+func isSeverityAtLeast(severity, minSeverity string) bool {
+	return cvemodel.SeverityValue(strings.ToUpper(severity)) >= cvemodel.SeverityValue(strings.ToUpper(minSeverity))
+}
@@ -32,6 +32,9 @@ func NewCVECommand(searchService SearchService) *cobra.Command {
 	cvesCmd.AddCommand(NewCveForImageCommand(searchService))
 	cvesCmd.AddCommand(NewImagesByCVEIDCommand(searchService))
 	cvesCmd.AddCommand(NewFixedTagsCommand(searchService))
+	cvesCmd.AddCommand(NewCveScanArchiveCommand(searchService))
+
+	addOutputTemplateFlags(cvesCmd)
 
 	return cvesCmd
 }
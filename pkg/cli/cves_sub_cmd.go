@@ -16,19 +16,41 @@ import (
 
 const (
 	maxRetries = 20
+
+	// defaultScanArchiveRepo is the repository the images of the archives are pushed to for their scan.
+	defaultScanArchiveRepo = "zli/scan-archive"
 )
 
 func NewCveForImageCommand(searchService SearchService) *cobra.Command {
 	var (
-		searchedCVEID   string
-		cveListSortFlag = cmdflags.CVEListSortFlag(cmdflags.SortBySeverity)
+		searchedCVEID    string
+		cveListSortFlag  = cmdflags.CVEListSortFlag(cmdflags.SortBySeverity)
+		cveFixStatusFlag = cmdflags.CVEFixStatusFlag(cmdflags.FixStatusAny)
+		severityFlag     cmdflags.CVESeverityFlag
+		failOnFlag       cmdflags.CVESeverityFlag
 	)
 
 	cveForImageCmd := &cobra.Command{
 		Use:   "list [repo:tag]|[repo@digest]",
-		Short: "List CVEs by REPO:TAG or REPO@DIGEST",
-		Long:  `List CVEs by REPO:TAG or REPO@DIGEST`,
-		Args:  OneImageWithRefArg,
+		Short: "List CVEs by REPO:TAG or REPO@DIGEST, or of all the images",
+		Long: fmt.Sprintf(`List CVEs by REPO:TAG or REPO@DIGEST, or of all the images of the registry if no image
+is given, the images are then scanned in parallel as many at a time as the 'concurrency' of the config.
+Besides text/json/yaml, the CVEs of an image can be exported in the %s formats with '--%s'.
+With '--%s' zli exits with the code %d if CVEs of the given severity or higher are listed`,
+			cveReportFormatsStr, cmdflags.OutputFormatFlag, cmdflags.FailOnFlag, CVESeverityThresholdExitCode),
+		Example: `  # list the CVEs of an image
+  zli cve list alpine:3.18 --config local
+
+  # list the high and critical CVEs of all the images
+  zli cve list --severity high --config local`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return nil
+			}
+
+			return OneImageWithRefArg(cmd, args)
+		},
+		ValidArgsFunction: completeImages(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
@@ -40,6 +62,10 @@ func NewCveForImageCommand(searchService SearchService) *cobra.Command {
 				return fmt.Errorf("%w: '%s'", err, CVEListForImageQuery().Name)
 			}
 
+			if len(args) == 0 {
+				return SearchCVEsForAllImagesGQL(searchConfig, searchedCVEID)
+			}
+
 			image := args[0]
 
 			return SearchCVEForImageGQL(searchConfig, image, searchedCVEID)
@@ -49,10 +75,79 @@ func NewCveForImageCommand(searchService SearchService) *cobra.Command {
 	cveForImageCmd.Flags().StringVar(&searchedCVEID, cmdflags.SearchedCVEID, "", "Search for a specific CVE by name/id")
 	cveForImageCmd.Flags().Var(&cveListSortFlag, cmdflags.SortByFlag,
 		fmt.Sprintf("Options for sorting the output: [%s]", cmdflags.CVEListSortOptionsStr()))
+	cveForImageCmd.Flags().Var(&cveFixStatusFlag, cmdflags.FixStatusFlag,
+		fmt.Sprintf("Filter CVEs by the availability of a fix: [%s]", cmdflags.CVEFixStatusOptionsStr()))
+	cveForImageCmd.Flags().Var(&severityFlag, cmdflags.SeverityFlag,
+		fmt.Sprintf("Only list the CVEs with this severity or higher: [%s]", cmdflags.CVESeverityOptionsStr()))
+	cveForImageCmd.Flags().Var(&failOnFlag, cmdflags.FailOnFlag,
+		fmt.Sprintf("Exit with an error if CVEs with this severity or higher are listed: [%s]",
+			cmdflags.CVESeverityOptionsStr()))
+	cveForImageCmd.Flags().StringP(cmdflags.OutputFileFlag, "o", "",
+		"Write the CVEs to this file instead of the standard output")
 
 	return cveForImageCmd
 }
 
+func NewCveScanArchiveCommand(searchService SearchService) *cobra.Command {
+	var (
+		searchedCVEID    string
+		cveListSortFlag  = cmdflags.CVEListSortFlag(cmdflags.SortBySeverity)
+		cveFixStatusFlag = cmdflags.CVEFixStatusFlag(cmdflags.FixStatusAny)
+		severityFlag     cmdflags.CVESeverityFlag
+		failOnFlag       cmdflags.CVESeverityFlag
+	)
+
+	scanArchiveCmd := &cobra.Command{
+		Use:   "scan-archive [archive.tar]",
+		Short: "List CVEs of the images of a local archive with the CVE DB of the server",
+		Long: fmt.Sprintf(`List CVEs of the images of a local OCI layout or docker archive, as written by 'docker save',
+with the CVE DB of the server, so the vulnerabilities can be checked without a local DB.
+Each image is pushed to the repository given with '--%s' under a temporary tag, which is deleted once the image
+is scanned, the user needs to be allowed to push to and to delete from this repository.
+With '--%s' zli exits with the code %d if CVEs of the given severity or higher are listed`,
+			cmdflags.RepoFlag, cmdflags.FailOnFlag, CVESeverityThresholdExitCode),
+		Example: `  # list the CVEs of an image saved with docker
+  docker save alpine:3.18 -o alpine.tar
+  zli cve scan-archive alpine.tar --config local`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			err = CheckExtEndPointQuery(searchConfig, CVEListForImageQuery())
+			if err != nil {
+				return fmt.Errorf("%w: '%s'", err, CVEListForImageQuery().Name)
+			}
+
+			repo, err := cmd.Flags().GetString(cmdflags.RepoFlag)
+			if err != nil {
+				return err
+			}
+
+			return ScanArchive(searchConfig, args[0], repo, searchedCVEID)
+		},
+	}
+
+	scanArchiveCmd.Flags().String(cmdflags.RepoFlag, defaultScanArchiveRepo,
+		"The repository the images are pushed to for the scan")
+	scanArchiveCmd.Flags().StringVar(&searchedCVEID, cmdflags.SearchedCVEID, "", "Search for a specific CVE by name/id")
+	scanArchiveCmd.Flags().Var(&cveListSortFlag, cmdflags.SortByFlag,
+		fmt.Sprintf("Options for sorting the output: [%s]", cmdflags.CVEListSortOptionsStr()))
+	scanArchiveCmd.Flags().Var(&cveFixStatusFlag, cmdflags.FixStatusFlag,
+		fmt.Sprintf("Filter CVEs by the availability of a fix: [%s]", cmdflags.CVEFixStatusOptionsStr()))
+	scanArchiveCmd.Flags().Var(&severityFlag, cmdflags.SeverityFlag,
+		fmt.Sprintf("Only list the CVEs with this severity or higher: [%s]", cmdflags.CVESeverityOptionsStr()))
+	scanArchiveCmd.Flags().Var(&failOnFlag, cmdflags.FailOnFlag,
+		fmt.Sprintf("Exit with an error if CVEs with this severity or higher are listed: [%s]",
+			cmdflags.CVESeverityOptionsStr()))
+	scanArchiveCmd.Flags().StringP(cmdflags.OutputFileFlag, "o", "",
+		"Write the CVEs to this file instead of the standard output")
+
+	return scanArchiveCmd
+}
+
 func NewImagesByCVEIDCommand(searchService SearchService) *cobra.Command {
 	var (
 		repo              string
@@ -91,7 +186,8 @@ func NewImagesByCVEIDCommand(searchService SearchService) *cobra.Command {
 		},
 	}
 
-	imagesByCVEIDCmd.Flags().StringVar(&repo, "repo", "", "Search for a specific CVE by name/id")
+	imagesByCVEIDCmd.Flags().StringVar(&repo, cmdflags.RepoFlag, "", "Search for a specific CVE by name/id")
+	_ = imagesByCVEIDCmd.RegisterFlagCompletionFunc(cmdflags.RepoFlag, completeRepoFlag(searchService))
 	imagesByCVEIDCmd.Flags().Var(&imageListSortFlag, cmdflags.SortByFlag,
 		fmt.Sprintf("Options for sorting the output: [%s]", cmdflags.ImageListSortOptionsStr()))
 
@@ -118,6 +214,7 @@ func NewFixedTagsCommand(searchService SearchService) *cobra.Command {
 
 			return nil
 		},
+		ValidArgsFunction: completeRepos(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
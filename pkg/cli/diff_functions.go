@@ -0,0 +1,508 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	jsoniter "github.com/json-iterator/go"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gopkg.in/yaml.v2"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	zcommon "zotregistry.io/zot/pkg/common"
+)
+
+const (
+	diffAdded   = "+"
+	diffRemoved = "-"
+)
+
+// imageDiff is what changed between the manifests of two images, from the first to the second one.
+type imageDiff struct {
+	From          diffedImage    `json:"from"           yaml:"from"`
+	To            diffedImage    `json:"to"             yaml:"to"`
+	SizeDelta     int64          `json:"sizeDelta"      yaml:"sizedelta"`
+	AddedLayers   []layerChange  `json:"addedLayers"    yaml:"addedlayers"`
+	RemovedLayers []layerChange  `json:"removedLayers"  yaml:"removedlayers"`
+	CommonLayers  int            `json:"commonLayers"   yaml:"commonlayers"`
+	ConfigChanges []configChange `json:"configChanges"  yaml:"configchanges"`
+	CVEs          *cveChanges    `json:"cves,omitempty" yaml:"cves,omitempty"`
+}
+
+type diffedImage struct {
+	Image    string `json:"image"    yaml:"image"`
+	Digest   string `json:"digest"   yaml:"digest"`
+	Platform string `json:"platform" yaml:"platform"`
+	Size     int64  `json:"size"     yaml:"size"`
+}
+
+type layerChange struct {
+	Digest string `json:"digest" yaml:"digest"`
+	Size   int64  `json:"size"   yaml:"size"`
+}
+
+type configChange struct {
+	Field string `json:"field" yaml:"field"`
+	From  string `json:"from"  yaml:"from"`
+	To    string `json:"to"    yaml:"to"`
+}
+
+type cveChanges struct {
+	Added   []cveChange `json:"added"   yaml:"added"`
+	Removed []cveChange `json:"removed" yaml:"removed"`
+}
+
+type cveChange struct {
+	ID       string `json:"id"       yaml:"id"`
+	Severity string `json:"severity" yaml:"severity"`
+	Title    string `json:"title"    yaml:"title"`
+}
+
+// diffedManifest is the manifest of an image, with its config, picked for the platform if the image is an index.
+type diffedManifest struct {
+	repo     string
+	digest   string
+	platform string
+	manifest ispec.Manifest
+	config   ispec.Image
+}
+
+/*
+DiffImages prints what changed between two images: the layers added and removed, the size delta, the changes
+of the config and, if withCVEs is set, the CVEs fixed and introduced. The manifest of the platform is compared
+for the images which are indexes.
+*/
+func DiffImages(config searchConfig, fromImage, toImage, platform string, withCVEs bool) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	from, err := getDiffedManifest(ctx, config, username, password, fromImage, platform)
+	if err != nil {
+		return err
+	}
+
+	to, err := getDiffedManifest(ctx, config, username, password, toImage, platform)
+	if err != nil {
+		return err
+	}
+
+	diff := imageDiff{
+		From:          diffedImage{fromImage, from.digest, from.platform, getManifestSize(from.manifest)},
+		To:            diffedImage{toImage, to.digest, to.platform, getManifestSize(to.manifest)},
+		ConfigChanges: diffImageConfigs(from.config, to.config),
+	}
+
+	diff.SizeDelta = diff.To.Size - diff.From.Size
+	diff.AddedLayers, diff.RemovedLayers, diff.CommonLayers = diffLayers(from.manifest.Layers, to.manifest.Layers)
+
+	if withCVEs {
+		if err := checkDiffCVESupport(config); err != nil {
+			return err
+		}
+
+		diff.CVEs = &cveChanges{}
+
+		fromCVEs, err := getDiffedCVEs(ctx, config, username, password, from)
+		if err != nil {
+			return err
+		}
+
+		toCVEs, err := getDiffedCVEs(ctx, config, username, password, to)
+		if err != nil {
+			return err
+		}
+
+		diff.CVEs.Added = diffCVEs(toCVEs, fromCVEs)
+		diff.CVEs.Removed = diffCVEs(fromCVEs, toCVEs)
+	}
+
+	out, err := diff.string(config.outputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.resultWriter, out)
+
+	return nil
+}
+
+func getDiffedManifest(ctx context.Context, config searchConfig, username, password, image, platform string,
+) (diffedManifest, error) {
+	repo, ref, _, err := zcommon.GetRepoReference(image)
+	if err != nil {
+		return diffedManifest{}, err
+	}
+
+	manifestURL, err := combineServerAndEndpointURL(config.servURL, fmt.Sprintf("/v2/%s/manifests/%s", repo, ref))
+	if err != nil {
+		return diffedManifest{}, err
+	}
+
+	content := []byte{}
+
+	header, err := makeGETRequest(ctx, manifestURL, username, password, config.verifyTLS, config.debug, &content,
+		config.resultWriter)
+	if err != nil {
+		return diffedManifest{}, err
+	}
+
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	digest := header.Get(constants.DistContentDigestKey)
+
+	if header.Get("Content-Type") == ispec.MediaTypeImageIndex {
+		index := ispec.Index{}
+
+		if err := json.Unmarshal(content, &index); err != nil {
+			return diffedManifest{}, err
+		}
+
+		descriptor, err := findPlatformManifest(index, image, platform)
+		if err != nil {
+			return diffedManifest{}, err
+		}
+
+		return getDiffedManifest(ctx, config, username, password, fmt.Sprintf("%s@%s", repo, descriptor.Digest),
+			platform)
+	}
+
+	manifest := ispec.Manifest{}
+
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return diffedManifest{}, err
+	}
+
+	imageConfig, err := fetchConfig(ctx, repo, manifest.Config.Digest.String(), config, username, password)
+	if err != nil {
+		return diffedManifest{}, err
+	}
+
+	return diffedManifest{
+		repo:     repo,
+		digest:   digest,
+		platform: getConfigPlatform(imageConfig),
+		manifest: manifest,
+		config:   imageConfig,
+	}, nil
+}
+
+// findPlatformManifest picks the manifest of the platform in the index, the platform can be left out if the
+// index has a single manifest.
+func findPlatformManifest(index ispec.Index, image, platform string) (ispec.Descriptor, error) {
+	if platform == "" && len(index.Manifests) == 1 {
+		return index.Manifests[0], nil
+	}
+
+	platforms := make([]string, 0, len(index.Manifests))
+
+	for _, descriptor := range index.Manifests {
+		descriptorPlatform := ""
+
+		if descriptor.Platform != nil {
+			descriptorPlatform = getPlatformStr(zcommon.Platform{
+				Os:      descriptor.Platform.OS,
+				Arch:    descriptor.Platform.Architecture,
+				Variant: descriptor.Platform.Variant,
+			})
+		}
+
+		if platform != "" && descriptorPlatform == platform {
+			return descriptor, nil
+		}
+
+		platforms = append(platforms, descriptorPlatform)
+	}
+
+	return ispec.Descriptor{}, fmt.Errorf("%w: %s has the platforms %s, choose one of them with '--platform'",
+		zerr.ErrPlatformNotFound, image, strings.Join(platforms, ", "))
+}
+
+func getConfigPlatform(image ispec.Image) string {
+	return getPlatformStr(zcommon.Platform{Os: image.OS, Arch: image.Architecture, Variant: image.Variant})
+}
+
+func getManifestSize(manifest ispec.Manifest) int64 {
+	size := manifest.Config.Size
+
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+
+	return size
+}
+
+// diffLayers compares the layers by digest, a layer moved to another position isn't a change.
+func diffLayers(fromLayers, toLayers []ispec.Descriptor) ([]layerChange, []layerChange, int) {
+	fromDigests := map[string]bool{}
+	toDigests := map[string]bool{}
+
+	for _, layer := range fromLayers {
+		fromDigests[layer.Digest.String()] = true
+	}
+
+	for _, layer := range toLayers {
+		toDigests[layer.Digest.String()] = true
+	}
+
+	added := []layerChange{}
+	removed := []layerChange{}
+	common := 0
+
+	for _, layer := range toLayers {
+		if fromDigests[layer.Digest.String()] {
+			common++
+		} else {
+			added = append(added, layerChange{Digest: layer.Digest.String(), Size: layer.Size})
+		}
+	}
+
+	for _, layer := range fromLayers {
+		if !toDigests[layer.Digest.String()] {
+			removed = append(removed, layerChange{Digest: layer.Digest.String(), Size: layer.Size})
+		}
+	}
+
+	return added, removed, common
+}
+
+// diffImageConfigs compares the settings of the configs used to run the images, the labels and the
+// environment variables are compared one by one.
+func diffImageConfigs(from, to ispec.Image) []configChange {
+	changes := []configChange{}
+
+	addChange := func(field, fromValue, toValue string) {
+		if fromValue != toValue {
+			changes = append(changes, configChange{Field: field, From: fromValue, To: toValue})
+		}
+	}
+
+	formatTime := func(image ispec.Image) string {
+		if image.Created == nil {
+			return ""
+		}
+
+		return image.Created.UTC().Format(time.RFC3339)
+	}
+
+	addChange("created", formatTime(from), formatTime(to))
+	addChange("platform", getConfigPlatform(from), getConfigPlatform(to))
+	addChange("user", from.Config.User, to.Config.User)
+	addChange("workingDir", from.Config.WorkingDir, to.Config.WorkingDir)
+	addChange("entrypoint", strings.Join(from.Config.Entrypoint, " "), strings.Join(to.Config.Entrypoint, " "))
+	addChange("cmd", strings.Join(from.Config.Cmd, " "), strings.Join(to.Config.Cmd, " "))
+	addChange("stopSignal", from.Config.StopSignal, to.Config.StopSignal)
+	addChange("exposedPorts", strings.Join(getSortedKeys(from.Config.ExposedPorts), " "),
+		strings.Join(getSortedKeys(to.Config.ExposedPorts), " "))
+
+	fromEnv := map[string]string{}
+	toEnv := map[string]string{}
+
+	for _, variable := range from.Config.Env {
+		name, value, _ := strings.Cut(variable, "=")
+		fromEnv[name] = value
+	}
+
+	for _, variable := range to.Config.Env {
+		name, value, _ := strings.Cut(variable, "=")
+		toEnv[name] = value
+	}
+
+	for _, name := range getSortedKeys(fromEnv, toEnv) {
+		addChange("env:"+name, fromEnv[name], toEnv[name])
+	}
+
+	for _, name := range getSortedKeys(from.Config.Labels, to.Config.Labels) {
+		addChange("label:"+name, from.Config.Labels[name], to.Config.Labels[name])
+	}
+
+	return changes
+}
+
+func getSortedKeys[T any](maps ...map[string]T) []string {
+	keySet := map[string]bool{}
+
+	for _, keyMap := range maps {
+		for key := range keyMap {
+			keySet[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(keySet))
+
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func checkDiffCVESupport(config searchConfig) error {
+	if err := CheckExtEndPointQuery(config, CVEListForImageQuery()); err != nil {
+		return fmt.Errorf("%w: '%s'", err, CVEListForImageQuery().Name)
+	}
+
+	return nil
+}
+
+func getDiffedCVEs(ctx context.Context, config searchConfig, username, password string, manifest diffedManifest,
+) ([]cve, error) {
+	result, err := config.searchService.getCveByImageGQL(ctx, config, username, password,
+		fmt.Sprintf("%s@%s", manifest.repo, manifest.digest), "")
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data.CVEListForImage.CVEList, nil
+}
+
+// diffCVEs returns the CVEs of the first list which aren't in the second one.
+func diffCVEs(cves, otherCVEs []cve) []cveChange {
+	otherIDs := map[string]bool{}
+
+	for _, cve := range otherCVEs {
+		otherIDs[cve.ID] = true
+	}
+
+	changes := []cveChange{}
+
+	for _, cve := range cves {
+		if !otherIDs[cve.ID] {
+			changes = append(changes, cveChange{ID: cve.ID, Severity: cve.Severity, Title: cve.Title})
+		}
+	}
+
+	return changes
+}
+
+func (diff imageDiff) string(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", defaultOutputFormat:
+		return diff.stringPlainText(), nil
+	case jsonFormat:
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+		body, err := json.Marshal(diff)
+		if err != nil {
+			return "", err
+		}
+
+		return string(body) + "\n", nil
+	case ymlFormat, yamlFormat:
+		body, err := yaml.Marshal(diff)
+		if err != nil {
+			return "", err
+		}
+
+		return "---\n" + string(body), nil
+	default:
+		return "", zerr.ErrInvalidOutputFormat
+	}
+}
+
+func (diff imageDiff) stringPlainText() string {
+	var builder strings.Builder
+
+	table := getImageTableWriter(&builder)
+
+	table.SetHeader([]string{"", "IMAGE", "DIGEST", "PLATFORM", "SIZE"})
+
+	for _, image := range []struct {
+		change string
+		diffedImage
+	}{{diffRemoved, diff.From}, {diffAdded, diff.To}} {
+		table.Append([]string{image.change, image.Image, image.Digest, image.Platform,
+			humanize.Bytes(uint64(image.Size))})
+	}
+
+	table.Render()
+
+	sizeDelta := humanize.Bytes(uint64(absInt64(diff.SizeDelta)))
+	if diff.SizeDelta < 0 {
+		sizeDelta = diffRemoved + sizeDelta
+	} else {
+		sizeDelta = diffAdded + sizeDelta
+	}
+
+	fmt.Fprintf(&builder, "\nSize: %s\n", sizeDelta)
+	fmt.Fprintf(&builder, "Layers: %d added, %d removed, %d unchanged\n", len(diff.AddedLayers),
+		len(diff.RemovedLayers), diff.CommonLayers)
+
+	if len(diff.AddedLayers)+len(diff.RemovedLayers) > 0 {
+		writeDiffSection(&builder, []string{"", "LAYER", "SIZE"}, func(addRow func(...string)) {
+			for _, layer := range diff.RemovedLayers {
+				addRow(diffRemoved, layer.Digest, humanize.Bytes(uint64(layer.Size)))
+			}
+
+			for _, layer := range diff.AddedLayers {
+				addRow(diffAdded, layer.Digest, humanize.Bytes(uint64(layer.Size)))
+			}
+		})
+	}
+
+	fmt.Fprintf(&builder, "\nConfig: %d changes\n", len(diff.ConfigChanges))
+
+	if len(diff.ConfigChanges) > 0 {
+		writeDiffSection(&builder, []string{"FIELD", "FROM", "TO"}, func(addRow func(...string)) {
+			for _, change := range diff.ConfigChanges {
+				addRow(change.Field, valueOrDash(change.From), valueOrDash(change.To))
+			}
+		})
+	}
+
+	if diff.CVEs != nil {
+		fmt.Fprintf(&builder, "\nCVEs: %d added, %d removed\n", len(diff.CVEs.Added), len(diff.CVEs.Removed))
+
+		if len(diff.CVEs.Added)+len(diff.CVEs.Removed) > 0 {
+			writeDiffSection(&builder, []string{"", "ID", "SEVERITY", "TITLE"}, func(addRow func(...string)) {
+				for _, cve := range diff.CVEs.Removed {
+					addRow(diffRemoved, cve.ID, cve.Severity, cve.Title)
+				}
+
+				for _, cve := range diff.CVEs.Added {
+					addRow(diffAdded, cve.ID, cve.Severity, cve.Title)
+				}
+			})
+		}
+	}
+
+	return builder.String()
+}
+
+func writeDiffSection(writer io.Writer, header []string, appendRows func(addRow func(...string))) {
+	table := getImageTableWriter(writer)
+
+	table.SetHeader(header)
+
+	appendRows(func(row ...string) {
+		table.Append(row)
+	})
+
+	fmt.Fprintln(writer)
+	table.Render()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+func absInt64(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
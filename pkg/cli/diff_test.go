@@ -0,0 +1,170 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestImageDiffCommand(t *testing.T) {
+	Convey("zli image diff", t, func() {
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		sharedLayer := []byte("shared layer")
+		fromImage := CreateImageWith().
+			LayerBlobs([][]byte{sharedLayer, []byte("removed layer")}).
+			ImageConfig(ispec.Image{
+				Platform: ispec.Platform{OS: "linux", Architecture: "amd64"},
+				Config: ispec.ImageConfig{
+					Env:    []string{"PATH=/bin", "VERSION=1.0"},
+					Cmd:    []string{"/bin/app"},
+					Labels: map[string]string{"version": "1.0", "removed": "label"},
+				},
+			}).Build()
+		toImage := CreateImageWith().
+			LayerBlobs([][]byte{sharedLayer, []byte("added layer with more content")}).
+			ImageConfig(ispec.Image{
+				Platform: ispec.Platform{OS: "linux", Architecture: "amd64"},
+				Config: ispec.ImageConfig{
+					Env:    []string{"PATH=/bin", "VERSION=2.0"},
+					Cmd:    []string{"/bin/app"},
+					Labels: map[string]string{"version": "2.0"},
+				},
+			}).Build()
+
+		So(UploadImage(fromImage, baseURL, "app", "1.0"), ShouldBeNil)
+		So(UploadImage(toImage, baseURL, "app", "2.0"), ShouldBeNil)
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"difftest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		runDiffCommand := func(searchService SearchService, args ...string) (string, error) {
+			cmd := NewImageCommand(searchService)
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(append([]string{"diff"}, args...), "--config", "difftest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		out, err := runDiffCommand(new(searchService), "app:1.0", "app:2.0")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, fromImage.DigestStr())
+		So(out, ShouldContainSubstring, toImage.DigestStr())
+		So(out, ShouldContainSubstring, "Layers: 1 added, 1 removed, 1 unchanged")
+		So(out, ShouldContainSubstring, "Config: 3 changes")
+		So(out, ShouldContainSubstring, "label:removed")
+		So(out, ShouldNotContainSubstring, "CVEs")
+
+		out, err = runDiffCommand(new(searchService), "app:1.0", "app@"+toImage.DigestStr(), "-f", "json")
+		So(err, ShouldBeNil)
+
+		diff := imageDiff{}
+		So(json.Unmarshal([]byte(out), &diff), ShouldBeNil)
+		So(diff.From.Platform, ShouldEqual, "linux/amd64")
+		So(diff.SizeDelta, ShouldEqual, diff.To.Size-diff.From.Size)
+		So(diff.AddedLayers, ShouldResemble, []layerChange{{
+			Digest: toImage.Manifest.Layers[1].Digest.String(),
+			Size:   toImage.Manifest.Layers[1].Size,
+		}})
+		So(diff.RemovedLayers, ShouldHaveLength, 1)
+		So(diff.CommonLayers, ShouldEqual, 1)
+		So(diff.ConfigChanges, ShouldResemble, []configChange{
+			{Field: "env:VERSION", From: "1.0", To: "2.0"},
+			{Field: "label:removed", From: "label", To: ""},
+			{Field: "label:version", From: "1.0", To: "2.0"},
+		})
+		So(diff.CVEs, ShouldBeNil)
+
+		out, err = runDiffCommand(new(searchService), "app:1.0", "app:1.0", "-f", "yaml")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "sizedelta: 0")
+		So(out, ShouldContainSubstring, "configchanges: []")
+
+		_, err = runDiffCommand(new(searchService), "app:1.0", "app:2.0", "-f", "bad")
+		So(errors.Is(err, zerr.ErrInvalidOutputFormat), ShouldBeTrue)
+
+		_, err = runDiffCommand(new(searchService), "app", "app:2.0")
+		So(errors.Is(err, zerr.ErrInvalidRepoRefFormat), ShouldBeTrue)
+
+		_, err = runDiffCommand(new(searchService), "app:1.0", "app:3.0")
+		So(errors.Is(err, zerr.ErrBadHTTPStatusCode), ShouldBeTrue)
+
+		Convey("with the CVEs", func() {
+			cves := map[string][]cve{
+				"app@" + fromImage.DigestStr(): {{ID: "CVE-1", Severity: "HIGH"}, {ID: "CVE-2", Severity: "LOW"}},
+				"app@" + toImage.DigestStr():   {{ID: "CVE-2", Severity: "LOW"}, {ID: "CVE-3", Severity: "CRITICAL"}},
+			}
+
+			service := mockService{
+				getCveByImageGQLFn: func(ctx context.Context, config searchConfig, username, password,
+					imageName, searchedCVE string,
+				) (*cveResult, error) {
+					return &cveResult{Data: cveData{CVEListForImage: cveListForImage{CVEList: cves[imageName]}}}, nil
+				},
+			}
+
+			out, err := runDiffCommand(service, "app:1.0", "app:2.0", "--cves")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "CVEs: 1 added, 1 removed")
+			So(out, ShouldContainSubstring, "CVE-3")
+			So(out, ShouldNotContainSubstring, "CVE-2")
+		})
+
+		Convey("of multiarch images", func() {
+			multiarch := CreateRandomMultiarch()
+			So(UploadMultiarchImage(multiarch, baseURL, "multiarch", "latest"), ShouldBeNil)
+
+			// the platforms of the manifests aren't in the index
+			_, err := runDiffCommand(new(searchService), "multiarch:latest", "app:1.0")
+			So(errors.Is(err, zerr.ErrPlatformNotFound), ShouldBeTrue)
+
+			_, err = runDiffCommand(new(searchService), "multiarch:latest", "app:1.0", "--platform", "linux/amd64")
+			So(errors.Is(err, zerr.ErrPlatformNotFound), ShouldBeTrue)
+
+			single := CreateMultiarchWith().Images([]Image{toImage}).Build()
+			So(UploadMultiarchImage(single, baseURL, "single", "latest"), ShouldBeNil)
+
+			out, err := runDiffCommand(new(searchService), "single:latest", "app:2.0")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Layers: 0 added, 0 removed, 2 unchanged")
+			So(strings.Count(out, toImage.DigestStr()), ShouldEqual, 2)
+		})
+	})
+}
@@ -45,6 +45,12 @@ func NewImageCommand(searchService SearchService) *cobra.Command {
 	imageCmd.AddCommand(NewImageDerivedCommand(searchService))
 	imageCmd.AddCommand(NewImageDigestCommand(searchService))
 	imageCmd.AddCommand(NewImageNameCommand(searchService))
+	imageCmd.AddCommand(NewImageReferrersCommand(searchService))
+	imageCmd.AddCommand(NewImageSignCommand(searchService))
+	imageCmd.AddCommand(NewImageVerifyCommand(searchService))
+	imageCmd.AddCommand(NewImageDiffCommand(searchService))
+
+	addOutputTemplateFlags(imageCmd)
 
 	return imageCmd
 }
@@ -1741,7 +1741,7 @@ func TestImagesCommandGQL(t *testing.T) {
 			space := regexp.MustCompile(`\s+`)
 			str := space.ReplaceAllString(buff.String(), " ")
 			actual := strings.TrimSpace(str)
-			So(actual, ShouldContainSubstring, "dummyCVEID HIGH Title of that CVE")
+			So(actual, ShouldContainSubstring, "dummyCVEID HIGH AVAILABLE Title of that CVE")
 		})
 
 		Convey("CVE errors", func() {
@@ -2541,6 +2541,7 @@ func (service mockService) getCveByImageGQL(ctx context.Context, config searchCo
 					Description: "Description of the CVE",
 					Title:       "Title of that CVE",
 					Severity:    "HIGH",
+					FixStatus:   "AVAILABLE",
 					PackageList: []packageList{
 						{
 							Name:             "packagename",
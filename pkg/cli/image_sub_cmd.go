@@ -19,7 +19,7 @@ func NewImageListCommand(searchService SearchService) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all images",
-		Long:  "List all images",
+		Long:  fmt.Sprintf("List all images, or only the images of a repository with '--%s'", cmdflags.RepoFlag),
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
@@ -27,16 +27,26 @@ func NewImageListCommand(searchService SearchService) *cobra.Command {
 				return err
 			}
 
-			if err := CheckExtEndPointQuery(searchConfig, ImageListQuery()); err == nil {
+			repo := defaultIfError(cmd.Flags().GetString(cmdflags.RepoFlag))
+			isGQLSupported := CheckExtEndPointQuery(searchConfig, ImageListQuery()) == nil
+
+			switch {
+			case repo != "" && isGQLSupported:
+				return SearchImageByNameGQL(searchConfig, repo)
+			case repo != "":
+				return SearchImageByName(searchConfig, repo)
+			case isGQLSupported:
 				return SearchAllImagesGQL(searchConfig)
+			default:
+				return SearchAllImages(searchConfig)
 			}
-
-			return SearchAllImages(searchConfig)
 		},
 	}
 
 	cmd.Flags().Var(&imageListSortFlag, cmdflags.SortByFlag,
 		fmt.Sprintf("Options for sorting the output: [%s]", cmdflags.ImageListSortOptionsStr()))
+	cmd.Flags().String(cmdflags.RepoFlag, "", "List only the images of the repository")
+	_ = cmd.RegisterFlagCompletionFunc(cmdflags.RepoFlag, completeRepoFlag(searchService))
 
 	return cmd
 }
@@ -45,13 +55,19 @@ func NewImageCVEListCommand(searchService SearchService) *cobra.Command {
 	var (
 		searchedCVEID   string
 		cveListSortFlag = cmdflags.CVEListSortFlag(cmdflags.SortBySeverity)
+		severityFlag    cmdflags.CVESeverityFlag
+		failOnFlag      cmdflags.CVESeverityFlag
 	)
 
 	cmd := &cobra.Command{
 		Use:   "cve [repo]|[repo-name:tag]|[repo-name@digest]",
 		Short: "List all CVE's of the image",
-		Long:  "List all CVE's of the image",
-		Args:  OneImageWithRefArg,
+		Long: fmt.Sprintf(`List all CVE's of the image.
+Besides text/json/yaml, the CVEs can be exported in the %s formats with '--%s'.
+With '--%s' zli exits with the code %d if CVEs of the given severity or higher are listed`,
+			cveReportFormatsStr, cmdflags.OutputFormatFlag, cmdflags.FailOnFlag, CVESeverityThresholdExitCode),
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
@@ -71,6 +87,12 @@ func NewImageCVEListCommand(searchService SearchService) *cobra.Command {
 	cmd.Flags().StringVar(&searchedCVEID, cmdflags.SearchedCVEID, "", "Search for a specific CVE by name/id")
 	cmd.Flags().Var(&cveListSortFlag, cmdflags.SortByFlag,
 		fmt.Sprintf("Options for sorting the output: [%s]", cmdflags.CVEListSortOptionsStr()))
+	cmd.Flags().Var(&severityFlag, cmdflags.SeverityFlag,
+		fmt.Sprintf("Only list the CVEs with this severity or higher: [%s]", cmdflags.CVESeverityOptionsStr()))
+	cmd.Flags().Var(&failOnFlag, cmdflags.FailOnFlag,
+		fmt.Sprintf("Exit with an error if CVEs with this severity or higher are listed: [%s]",
+			cmdflags.CVESeverityOptionsStr()))
+	cmd.Flags().StringP(cmdflags.OutputFileFlag, "o", "", "Write the CVEs to this file instead of the standard output")
 
 	return cmd
 }
@@ -79,10 +101,11 @@ func NewImageDerivedCommand(searchService SearchService) *cobra.Command {
 	imageListSortFlag := cmdflags.ImageListSortFlag(cmdflags.SortByAlphabeticAsc)
 
 	cmd := &cobra.Command{
-		Use:   "derived [repo-name:tag]|[repo-name@digest]",
-		Short: "List images that are derived from given image",
-		Long:  "List images that are derived from given image",
-		Args:  OneImageWithRefArg,
+		Use:               "derived [repo-name:tag]|[repo-name@digest]",
+		Short:             "List images that are derived from given image",
+		Long:              "List images that are derived from given image",
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
@@ -107,10 +130,11 @@ func NewImageBaseCommand(searchService SearchService) *cobra.Command {
 	imageListSortFlag := cmdflags.ImageListSortFlag(cmdflags.SortByAlphabeticAsc)
 
 	cmd := &cobra.Command{
-		Use:   "base [repo-name:tag]|[repo-name@digest]",
-		Short: "List images that are base for the given image",
-		Long:  "List images that are base for the given image",
-		Args:  OneImageWithRefArg,
+		Use:               "base [repo-name:tag]|[repo-name@digest]",
+		Short:             "List images that are base for the given image",
+		Long:              "List images that are base for the given image",
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
@@ -181,6 +205,7 @@ func NewImageNameCommand(searchService SearchService) *cobra.Command {
 
 			return nil
 		},
+		ValidArgsFunction: completeImages(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
@@ -200,3 +225,144 @@ func NewImageNameCommand(searchService SearchService) *cobra.Command {
 
 	return cmd
 }
+
+func NewImageReferrersCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "referrers [repo:tag]|[repo@digest]",
+		Short: "Show the referrers graph of an image",
+		Long: `Show all the referrers of an image, like signatures, SBOMs and attestations, and the referrers
+of the referrers, as a tree with their artifact types, digests and sizes`,
+		Example: `  zli image referrers alpine:3.16 --config local
+  zli image referrers alpine:3.16 --config local -f json`,
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return ShowReferrersTree(searchConfig, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func NewImageSignCommand(searchService SearchService) *cobra.Command {
+	signToolFlag := cmdflags.SignToolOptionFlag(cmdflags.SignToolCosign)
+
+	cmd := &cobra.Command{
+		Use:   "sign [repo:tag]|[repo@digest]",
+		Short: "Sign an image with cosign or notation",
+		Long: fmt.Sprintf(`Sign an image and push the signature to the zot server, the digest of the image is signed.
+Cosign signs with the key file given with '--%s', its password is read from the COSIGN_PASSWORD environment
+variable or prompted, or keyless with '--%s' using the public sigstore instance.
+Notation signs with the key of its signing keys named with '--%s', or with its default key.
+The trust status of the signature is shown by 'zli image verify'`,
+			cmdflags.KeyFlag, cmdflags.KeylessFlag, cmdflags.KeyFlag),
+		Example: `  zli image sign alpine:3.16 --key cosign.key --config local
+  zli image sign alpine:3.16 --keyless --config local
+  zli image sign alpine:3.16 --tool notation --key release --config local`,
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			key := defaultIfError(cmd.Flags().GetString(cmdflags.KeyFlag))
+			keyless := defaultIfError(cmd.Flags().GetBool(cmdflags.KeylessFlag))
+
+			return SignImage(searchConfig, args[0], string(signToolFlag), key, keyless)
+		},
+	}
+
+	cmd.Flags().Var(&signToolFlag, cmdflags.SignToolFlag,
+		fmt.Sprintf("The tool used to sign the image: [%s]", cmdflags.SignToolOptionsStr()))
+	cmd.Flags().String(cmdflags.KeyFlag, "", "The cosign key file, or the name of the notation signing key")
+	cmd.Flags().Bool(cmdflags.KeylessFlag, false, "Sign keyless with cosign")
+
+	return cmd
+}
+
+func NewImageVerifyCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [repo:tag]",
+		Short: "Show whether the zot server trusts the signatures of an image",
+		Long: `Show the signatures of an image and whether they're trusted, as verified by the zot server with the
+keys and certificates uploaded to its imagetrust extension. zli exits with an error if none of the signatures
+is trusted, the signatures are never trusted if the extension is disabled`,
+		Example: `  zli image verify alpine:3.16 --config local
+  zli image verify alpine:3.16 --config local -f json`,
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return VerifyImage(searchConfig, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func NewImageDiffCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [repo:tag]|[repo@digest] [repo:tag]|[repo@digest]",
+		Short: "Show what changed between two images",
+		Long: fmt.Sprintf(`Show what changed from the first image to the second one: the layers added and removed, the size
+delta and the changes of the config, like the labels and the environment variables.
+The CVEs introduced and fixed are also compared with '--%s', if the CVE scanning is enabled on the server.
+The manifest of a platform is compared for the multiarch images, chosen with '--%s' as os/arch[/variant]`,
+			cmdflags.CVEsFlag, cmdflags.PlatformFlag),
+		Example: `  zli image diff alpine:3.17 alpine:3.18 --config local
+  zli image diff app:1.0 app:1.1 --platform linux/amd64 --cves --config local -f json`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			const argCount = 2
+
+			if err := cobra.ExactArgs(argCount)(cmd, args); err != nil {
+				return err
+			}
+
+			for _, image := range args {
+				if _, _, _, err := zcommon.GetRepoReference(image); err != nil {
+					return fmt.Errorf("%w: %s", err, image)
+				}
+			}
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string,
+		) ([]string, cobra.ShellCompDirective) {
+			const argCount = 2
+
+			if len(args) >= argCount {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			// both the images are completed the same way
+			return completeImages(searchService)(cmd, nil, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			platform := defaultIfError(cmd.Flags().GetString(cmdflags.PlatformFlag))
+			withCVEs := defaultIfError(cmd.Flags().GetBool(cmdflags.CVEsFlag))
+
+			return DiffImages(searchConfig, args[0], args[1], platform, withCVEs)
+		},
+	}
+
+	cmd.Flags().String(cmdflags.PlatformFlag, "", "The platform of the multiarch images to compare, as os/arch[/variant]")
+	cmd.Flags().Bool(cmdflags.CVEsFlag, false, "Compare the CVEs of the images")
+
+	return cmd
+}
@@ -0,0 +1,104 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+func NewLoginCommand(searchService SearchService) *cobra.Command {
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to a zot server",
+		Long: fmt.Sprintf(`Log in to a zot server, the credentials are kept by a docker credentials helper, the OS keychain
+by default, instead of being passed with each command. Another helper can be set with the '%s' config variable.
+
+The password is prompted for, or read from the standard input with '--%s'. With '--%s' an API key is
+created for the user and stored instead of the password. With '--%s' the user logs in with an openID provider
+of the server in a browser, possibly on another device, and an API key is stored as well.`,
+			credstoreConfig, cmdflags.PasswordStdinFlag, cmdflags.APIKeyFlag, cmdflags.OIDCFlag),
+		Example: `  # log in with a username and password
+  zli login --config local --username admin
+
+  # log in using an API key, created with the given password
+  echo $PASSWORD | zli login --config local --username admin --password-stdin --api-key
+
+  # log in with the "oidc" openID provider of the server
+  zli login --config local --oidc oidc`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			store, err := getCredentialsStoreFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			options := loginOptions{
+				username:      defaultIfError(cmd.Flags().GetString(cmdflags.UsernameFlag)),
+				passwordStdin: defaultIfError(cmd.Flags().GetBool(cmdflags.PasswordStdinFlag)),
+				apiKey:        defaultIfError(cmd.Flags().GetBool(cmdflags.APIKeyFlag)),
+				oidcProvider:  defaultIfError(cmd.Flags().GetString(cmdflags.OIDCFlag)),
+			}
+
+			if options.oidcProvider != "" && (options.username != "" || options.passwordStdin) {
+				return fmt.Errorf("%w: '--%s' can't be used with '--%s' or '--%s'", zerr.ErrInvalidFlagsCombination,
+					cmdflags.OIDCFlag, cmdflags.UsernameFlag, cmdflags.PasswordStdinFlag)
+			}
+
+			if options.passwordStdin && options.username == "" {
+				return fmt.Errorf("%w: '--%s' requires '--%s'", zerr.ErrInvalidFlagsCombination,
+					cmdflags.PasswordStdinFlag, cmdflags.UsernameFlag)
+			}
+
+			return Login(searchConfig, store, cmd.InOrStdin(), cmd.ErrOrStderr(), options)
+		},
+	}
+
+	loginCmd.Flags().String(cmdflags.URLFlag, "", "Specify zot server URL if config-name is not mentioned")
+	loginCmd.Flags().String(cmdflags.ConfigFlag, "", "Specify the registry configuration to use for connection")
+	loginCmd.Flags().Bool(cmdflags.DebugFlag, false, "Show debug output")
+	loginCmd.Flags().String(cmdflags.UsernameFlag, "", "Username, prompted for if not given")
+	loginCmd.Flags().Bool(cmdflags.PasswordStdinFlag, false, "Read the password from the standard input")
+	loginCmd.Flags().Bool(cmdflags.APIKeyFlag, false, "Store an API key created for the user instead of the password")
+	loginCmd.Flags().String(cmdflags.OIDCFlag, "", "Log in with this openID provider of the server")
+
+	return loginCmd
+}
+
+func NewLogoutCommand(searchService SearchService) *cobra.Command {
+	logoutCmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Log out from a zot server",
+		Long:  `Log out from a zot server, revoking the API key created at login and removing the stored credentials`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			store, err := getCredentialsStoreFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			return Logout(searchConfig, store, cmd.ErrOrStderr())
+		},
+	}
+
+	logoutCmd.Flags().String(cmdflags.URLFlag, "", "Specify zot server URL if config-name is not mentioned")
+	logoutCmd.Flags().String(cmdflags.ConfigFlag, "", "Specify the registry configuration to use for connection")
+	logoutCmd.Flags().Bool(cmdflags.DebugFlag, false, "Show debug output")
+
+	return logoutCmd
+}
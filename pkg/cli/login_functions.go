@@ -0,0 +1,280 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/constants"
+)
+
+const (
+	deviceSlowDown          = "slow_down"
+	deviceDefaultInterval   = 5 * time.Second
+	deviceSlowDownIncrement = 5 * time.Second
+)
+
+type loginOptions struct {
+	username      string
+	passwordStdin bool
+	apiKey        bool
+	oidcProvider  string
+}
+
+type loginAPIKey struct {
+	APIKey   string `json:"apiKey"`
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+}
+
+// Login checks the credentials of the user against the server and stores them, or the API key created
+// for the user.
+func Login(config searchConfig, store credentialsStore, input io.Reader, prompt io.Writer,
+	options loginOptions,
+) error {
+	ctx := context.Background()
+
+	if options.oidcProvider != "" {
+		apiKey, err := loginWithDeviceFlow(ctx, config, options.oidcProvider, prompt)
+		if err != nil {
+			return err
+		}
+
+		if err := storeLoginAPIKey(config, store, apiKey); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(config.resultWriter, "Login succeeded as %s\n", apiKey.Username)
+
+		return nil
+	}
+
+	username, password, err := readLoginCredentials(input, prompt, options)
+	if err != nil {
+		return err
+	}
+
+	v2URL, err := combineServerAndEndpointURL(config.servURL, "/v2/")
+	if err != nil {
+		return err
+	}
+
+	if _, err := makeGETRequest(ctx, v2URL, username, password, config.verifyTLS, config.debug, nil,
+		config.resultWriter); err != nil {
+		return err
+	}
+
+	if options.apiKey {
+		apiKey, err := createLoginAPIKey(ctx, config, username, password)
+		if err != nil {
+			return err
+		}
+
+		err = storeLoginAPIKey(config, store, apiKey)
+	} else {
+		err = store.store(config.servURL, username, password)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.resultWriter, "Login succeeded as %s\n", username)
+
+	return nil
+}
+
+// Logout revokes the API key created at login, if any, and removes the stored credentials.
+func Logout(config searchConfig, store credentialsStore, errWriter io.Writer) error {
+	username, secret, err := store.get(config.servURL)
+	if err != nil {
+		return err
+	}
+
+	if username == "" && secret == "" {
+		fmt.Fprintf(config.resultWriter, "Not logged in to %s\n", config.servURL)
+
+		return nil
+	}
+
+	apiKeyURL := apiKeyCredentialsURL(config.servURL)
+
+	_, apiKeyID, err := store.get(apiKeyURL)
+	if err != nil {
+		return err
+	}
+
+	if apiKeyID != "" {
+		revokeURL, err := combineServerAndEndpointURL(config.servURL,
+			constants.APIKeyPath+"?id="+url.QueryEscape(apiKeyID))
+		if err != nil {
+			return err
+		}
+
+		// the credentials are removed anyway, the key can still be revoked from the UI
+		if err := makeDELETERequest(context.Background(), revokeURL, username, secret, config.verifyTLS,
+			config.debug, config.resultWriter); err != nil {
+			fmt.Fprintf(errWriter, "unable to revoke the API key %s: %s\n", apiKeyID, err)
+		}
+
+		if err := store.erase(apiKeyURL); err != nil {
+			return err
+		}
+	}
+
+	if err := store.erase(config.servURL); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.resultWriter, "Removed the credentials of %s\n", config.servURL)
+
+	return nil
+}
+
+func readLoginCredentials(input io.Reader, prompt io.Writer, options loginOptions) (string, string, error) {
+	if options.passwordStdin {
+		password, err := io.ReadAll(input)
+		if err != nil {
+			return "", "", err
+		}
+
+		return options.username, strings.TrimRight(string(password), "\r\n"), nil
+	}
+
+	reader := bufio.NewReader(input)
+	username := options.username
+
+	if username == "" {
+		fmt.Fprint(prompt, "Username: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", "", err
+		}
+
+		username = strings.TrimSpace(line)
+	}
+
+	fmt.Fprint(prompt, "Password: ")
+
+	// don't echo the password when it's typed in a terminal
+	if file, ok := input.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		password, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(prompt)
+
+		return username, string(password), err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", "", err
+	}
+
+	return username, strings.TrimRight(line, "\r\n"), nil
+}
+
+func createLoginAPIKey(ctx context.Context, config searchConfig, username, password string) (loginAPIKey, error) {
+	apiKeyURL, err := combineServerAndEndpointURL(config.servURL, constants.APIKeyPath)
+	if err != nil {
+		return loginAPIKey{}, err
+	}
+
+	hostname, _ := os.Hostname()
+
+	payload := api.APIKeyPayload{Label: fmt.Sprintf("zli login on %s", hostname)}
+	apiKey := loginAPIKey{}
+
+	if err := makePOSTRequest(ctx, apiKeyURL, username, password, payload, config.verifyTLS, config.debug,
+		&apiKey, config.resultWriter); err != nil {
+		return loginAPIKey{}, err
+	}
+
+	apiKey.Username = username
+
+	return apiKey, nil
+}
+
+// loginWithDeviceFlow runs the device authorization flow of the openID provider, the user authorizes zli
+// in a browser while it waits for an API key to be created.
+func loginWithDeviceFlow(ctx context.Context, config searchConfig, provider string, prompt io.Writer,
+) (loginAPIKey, error) {
+	query := "?provider=" + url.QueryEscape(provider)
+
+	authorizationURL, err := combineServerAndEndpointURL(config.servURL, constants.DeviceAuthPath+query)
+	if err != nil {
+		return loginAPIKey{}, err
+	}
+
+	tokenURL, err := combineServerAndEndpointURL(config.servURL, constants.DeviceTokenPath+query)
+	if err != nil {
+		return loginAPIKey{}, err
+	}
+
+	authorization := api.DeviceAuthorizationResponse{}
+
+	if err := makePOSTRequest(ctx, authorizationURL, "", "", nil, config.verifyTLS, config.debug,
+		&authorization, config.resultWriter); err != nil {
+		return loginAPIKey{}, err
+	}
+
+	fmt.Fprintf(prompt, "To log in, open %s and enter the code %s\n", authorization.VerificationURI,
+		authorization.UserCode)
+
+	if authorization.VerificationURIComplete != "" {
+		fmt.Fprintf(prompt, "or open %s\n", authorization.VerificationURIComplete)
+	}
+
+	interval := time.Duration(authorization.Interval) * time.Second
+	if interval <= 0 {
+		interval = deviceDefaultInterval
+	}
+
+	deadline := time.Now().Add(time.Duration(authorization.ExpiresIn) * time.Second)
+
+	for {
+		time.Sleep(interval)
+
+		if time.Now().After(deadline) {
+			return loginAPIKey{}, zerr.ErrDeviceCodeExpired
+		}
+
+		result := struct {
+			api.DeviceTokenResponse
+			loginAPIKey
+		}{}
+
+		if err := makePOSTRequest(ctx, tokenURL, "", "", api.DeviceTokenPayload{DeviceCode: authorization.DeviceCode},
+			config.verifyTLS, config.debug, &result, config.resultWriter); err != nil {
+			return loginAPIKey{}, err
+		}
+
+		if result.Status == "" {
+			return result.loginAPIKey, nil
+		}
+
+		if result.Status == deviceSlowDown {
+			interval += deviceSlowDownIncrement
+		}
+	}
+}
+
+// storeLoginAPIKey stores the API key as the password of the user, and its ID to revoke it at logout.
+func storeLoginAPIKey(config searchConfig, store credentialsStore, apiKey loginAPIKey) error {
+	if err := store.store(config.servURL, apiKey.Username, apiKey.APIKey); err != nil {
+		return err
+	}
+
+	return store.store(apiKeyCredentialsURL(config.servURL), apiKey.Username, apiKey.UUID)
+}
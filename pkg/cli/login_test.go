@@ -0,0 +1,235 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/project-zot/mockoidc"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/cobra"
+	"gopkg.in/resty.v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+// a credentials helper keeping the credentials in files next to it.
+const testCredentialsHelper = `#!/bin/sh
+store="$(dirname "$0")/store"
+mkdir -p "$store"
+case "$1" in
+store)
+	creds=$(cat)
+	key=$(printf '%s' "$creds" | sed -n 's/.*"ServerURL":"\([^"]*\)".*/\1/p' | tr '/:' '__')
+	printf '%s' "$creds" > "$store/$key"
+	;;
+get)
+	key=$(cat | tr '/:' '__')
+	if [ ! -f "$store/$key" ]; then
+		echo "credentials not found in native keychain"
+		exit 1
+	fi
+	cat "$store/$key"
+	;;
+erase)
+	key=$(cat | tr '/:' '__')
+	rm -f "$store/$key"
+	;;
+esac
+`
+
+func TestLoginCommand(t *testing.T) {
+	Convey("zli login and logout", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		htpasswdPath := test.MakeHtpasswdFile()
+		defer os.Remove(htpasswdPath)
+
+		mockOIDCServer, err := test.MockOIDCRunWithDeviceFlow()
+		So(err, ShouldBeNil)
+
+		defer func() {
+			_ = mockOIDCServer.Shutdown()
+		}()
+
+		mockOIDCConfig := mockOIDCServer.Config()
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{
+				Path: htpasswdPath,
+			},
+			OpenID: &config.OpenIDConfig{
+				Providers: map[string]config.OpenIDProviderConfig{
+					"oidc": {
+						ClientID:     mockOIDCConfig.ClientID,
+						ClientSecret: mockOIDCConfig.ClientSecret,
+						Issuer:       mockOIDCConfig.Issuer,
+						Scopes:       []string{"openid", "email", "groups"},
+					},
+				},
+			},
+			APIKey: true,
+		}
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "repo", "1.0", "test", "test")
+		So(err, ShouldBeNil)
+
+		helperDir := t.TempDir()
+		err = os.WriteFile(path.Join(helperDir, credentialsHelperPrefix+"zlitest"), []byte(testCredentialsHelper),
+			0o755) //nolint:gosec
+		So(err, ShouldBeNil)
+
+		t.Setenv("PATH", helperDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		configPath := makeConfigFile(fmt.Sprintf(
+			`{"configs":[{"_name":"logintest","url":"%s","showspinner":false,"credstore":"zlitest"}]}`, baseURL))
+		defer os.Remove(configPath)
+
+		store := newCredentialsStore("zlitest")
+
+		runCommand := func(cmd *cobra.Command, input string, args ...string) (string, string, error) {
+			stdout := bytes.NewBufferString("")
+			stderr := bytes.NewBufferString("")
+			cmd.SetOut(stdout)
+			cmd.SetErr(stderr)
+			cmd.SetIn(strings.NewReader(input))
+			cmd.SetArgs(append(args, "--config", "logintest"))
+			err := cmd.Execute()
+
+			return stdout.String(), stderr.String(), err
+		}
+
+		listRepos := func() (string, error) {
+			out, _, err := runCommand(NewRepoCommand(new(searchService)), "", "list")
+
+			return out, err
+		}
+
+		_, err = listRepos()
+		So(err, ShouldNotBeNil)
+
+		Convey("with a username and password", func() {
+			out, stderr, err := runCommand(NewLoginCommand(new(searchService)), "test\ntest\n")
+			So(err, ShouldBeNil)
+			So(stderr, ShouldContainSubstring, "Username: ")
+			So(stderr, ShouldContainSubstring, "Password: ")
+			So(out, ShouldContainSubstring, "Login succeeded as test")
+
+			username, secret, err := store.get(baseURL)
+			So(err, ShouldBeNil)
+			So(username, ShouldEqual, "test")
+			So(secret, ShouldEqual, "test")
+
+			out, err = listRepos()
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "repo")
+
+			out, _, err = runCommand(NewLogoutCommand(new(searchService)), "")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Removed the credentials of "+baseURL)
+
+			_, err = listRepos()
+			So(err, ShouldNotBeNil)
+
+			out, _, err = runCommand(NewLogoutCommand(new(searchService)), "")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Not logged in to "+baseURL)
+
+			// wrong password
+			_, _, err = runCommand(NewLoginCommand(new(searchService)), "wrong\n", "--username", "test")
+			So(errors.Is(err, zerr.ErrUnauthorizedAccess), ShouldBeTrue)
+
+			username, secret, err = store.get(baseURL)
+			So(err, ShouldBeNil)
+			So(username, ShouldBeEmpty)
+			So(secret, ShouldBeEmpty)
+		})
+
+		Convey("with an API key", func() {
+			out, _, err := runCommand(NewLoginCommand(new(searchService)), "test\n",
+				"--username", "test", "--password-stdin", "--api-key")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Login succeeded as test")
+
+			username, apiKey, err := store.get(baseURL)
+			So(err, ShouldBeNil)
+			So(username, ShouldEqual, "test")
+			So(apiKey, ShouldStartWith, constants.APIKeysPrefix)
+
+			out, err = listRepos()
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "repo")
+
+			_, _, err = runCommand(NewLogoutCommand(new(searchService)), "")
+			So(err, ShouldBeNil)
+
+			// the API key is revoked
+			resp, err := resty.R().SetBasicAuth(username, apiKey).Get(baseURL + "/v2/_catalog")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, 401)
+
+			_, apiKeyID, err := store.get(apiKeyCredentialsURL(baseURL))
+			So(err, ShouldBeNil)
+			So(apiKeyID, ShouldBeEmpty)
+		})
+
+		Convey("with the openID device flow", func() {
+			out, stderr, err := runCommand(NewLoginCommand(new(searchService)), "", "--oidc", "oidc")
+			So(err, ShouldBeNil)
+			So(stderr, ShouldContainSubstring, "enter the code ZOT-1234")
+			So(out, ShouldContainSubstring, "Login succeeded as "+mockoidc.DefaultUser().Email)
+
+			username, apiKey, err := store.get(baseURL)
+			So(err, ShouldBeNil)
+			So(username, ShouldEqual, mockoidc.DefaultUser().Email)
+			So(apiKey, ShouldStartWith, constants.APIKeysPrefix)
+
+			out, err = listRepos()
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "repo")
+
+			_, _, err = runCommand(NewLogoutCommand(new(searchService)), "")
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetBasicAuth(username, apiKey).Get(baseURL + "/v2/_catalog")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, 401)
+
+			_, _, err = runCommand(NewLoginCommand(new(searchService)), "", "--oidc", "github")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("invalid flags", func() {
+			_, _, err := runCommand(NewLoginCommand(new(searchService)), "", "--oidc", "oidc", "--username", "test")
+			So(errors.Is(err, zerr.ErrInvalidFlagsCombination), ShouldBeTrue)
+
+			_, _, err = runCommand(NewLoginCommand(new(searchService)), "", "--password-stdin")
+			So(errors.Is(err, zerr.ErrInvalidFlagsCombination), ShouldBeTrue)
+
+			_, _, err = runCommand(NewLoginCommand(new(searchService)), "", "extra-arg")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
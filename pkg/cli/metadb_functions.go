@@ -0,0 +1,90 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"zotregistry.io/zot/pkg/api/constants"
+)
+
+// BackupMetaDB saves the backup of the metaDB of the server to the file, it's written to a temporary file first
+// so an interrupted backup doesn't replace a previous one.
+func BackupMetaDB(config searchConfig, path string) error {
+	username, password := getUsernameAndPassword(config.user)
+
+	backupURL, err := combineServerAndEndpointURL(config.servURL, constants.FullMgmtMetaDB+"/backup")
+	if err != nil {
+		return err
+	}
+
+	backupFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(backupFile.Name())
+
+	_, err = makeGETRequest(context.Background(), backupURL, username, password, config.verifyTLS, config.debug,
+		backupFile, config.resultWriter)
+	if err != nil {
+		backupFile.Close()
+
+		return err
+	}
+
+	if err := backupFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(backupFile.Name(), path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.resultWriter, "Saved the backup of the metadata database to %s\n", path)
+
+	return nil
+}
+
+// RestoreMetaDB replaces the metaDB of the server with the backup in the file, after it's confirmed by reading
+// "y" or "yes" from the input, unless it's forced.
+func RestoreMetaDB(config searchConfig, path string, force bool, input io.Reader) error {
+	username, password := getUsernameAndPassword(config.user)
+
+	restoreURL, err := combineServerAndEndpointURL(config.servURL, constants.FullMgmtMetaDB+"/restore")
+	if err != nil {
+		return err
+	}
+
+	backupFile, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer backupFile.Close()
+
+	if !force {
+		fmt.Fprintf(config.resultWriter, "Replace the metadata database of %s with %s? [y/N]: ", config.servURL,
+			path)
+
+		if !readConfirmation(input) {
+			fmt.Fprintln(config.resultWriter, "Restore cancelled")
+
+			return nil
+		}
+	}
+
+	if err := makePOSTRequest(context.Background(), restoreURL, username, password, backupFile,
+		config.verifyTLS, config.debug, nil, config.resultWriter); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.resultWriter, "Restored the metadata database from %s\n", path)
+
+	return nil
+}
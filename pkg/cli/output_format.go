@@ -0,0 +1,323 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/dustin/go-humanize"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spf13/cobra"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+// addOutputTemplateFlags adds the --template and --columns flags to the command and its subcommands, which render
+// the json output of the commands with a Go template, or as a table of some of its fields.
+func addOutputTemplateFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(cmdflags.TemplateFlag, "",
+		`Print each result with a Go template of the fields of its json output, e.g. '{{.repoName}}:{{.tag}}'`)
+	cmd.PersistentFlags().StringSlice(cmdflags.ColumnsFlag, nil,
+		"Print a table of these fields of the json output of the results, e.g. 'repoName,tag,digest'")
+
+	wrapOutputFormatting(cmd)
+}
+
+// wrapOutputFormatting sends the output of the commands to an outputFormatter if a template or columns are given,
+// it's flushed once the command is done, even if it failed after printing some results.
+func wrapOutputFormatting(cmd *cobra.Command) {
+	for _, subCmd := range cmd.Commands() {
+		wrapOutputFormatting(subCmd)
+	}
+
+	runE := cmd.RunE
+	if runE == nil {
+		return
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		formatter, err := newOutputFormatterFromFlags(cmd, out)
+		if err != nil {
+			return err
+		}
+
+		if formatter == nil {
+			return runE(cmd, args)
+		}
+
+		cmd.SetOut(formatter)
+		defer cmd.SetOut(out)
+
+		err = runE(cmd, args)
+
+		if flushErr := formatter.flush(); err == nil {
+			err = flushErr
+		}
+
+		return err
+	}
+}
+
+// isOutputFormatted returns whether the output is rendered with a template or columns, from the json format.
+func isOutputFormatted(cmd *cobra.Command) bool {
+	template := defaultIfError(cmd.Flags().GetString(cmdflags.TemplateFlag))
+	columns := defaultIfError(cmd.Flags().GetStringSlice(cmdflags.ColumnsFlag))
+
+	return template != "" || len(columns) > 0
+}
+
+// getOutputFormat returns the output format of the command, or of the config if it isn't given, json if the output
+// is rendered with a template or columns.
+func getOutputFormat(cmd *cobra.Command) (string, error) {
+	if !isOutputFormatted(cmd) {
+		return getFlagOrConfigValue(cmd, cmdflags.OutputFormatFlag, outputFormatConfig)
+	}
+
+	outputFormat := defaultIfError(cmd.Flags().GetString(cmdflags.OutputFormatFlag))
+
+	if outputFormat != "" && strings.ToLower(outputFormat) != jsonFormat {
+		return "", fmt.Errorf("%w: '--%s' and '--%s' render the json format, not %s", zerr.ErrInvalidFlagsCombination,
+			cmdflags.TemplateFlag, cmdflags.ColumnsFlag, outputFormat)
+	}
+
+	return jsonFormat, nil
+}
+
+/*
+outputFormatter renders the json documents written by the commands, one per line, with a template or as a table
+of some of their fields, the other lines like the warnings are written as they are. The fields are the keys of the
+json output, matched regardless of their case, the nested fields are separated by dots, e.g. 'manifests.0.digest'.
+*/
+type outputFormatter struct {
+	writer   io.Writer
+	template *template.Template
+	columns  []string
+	rows     [][]string
+	found    []bool
+	pending  []byte
+	// err is the first error of the writes, the commands don't check them
+	err error
+}
+
+func newOutputFormatterFromFlags(cmd *cobra.Command, writer io.Writer) (*outputFormatter, error) {
+	templateText := defaultIfError(cmd.Flags().GetString(cmdflags.TemplateFlag))
+	columns := defaultIfError(cmd.Flags().GetStringSlice(cmdflags.ColumnsFlag))
+
+	switch {
+	case templateText != "" && len(columns) > 0:
+		return nil, fmt.Errorf("%w: '--%s' or '--%s'", zerr.ErrInvalidFlagsCombination, cmdflags.TemplateFlag,
+			cmdflags.ColumnsFlag)
+	case templateText != "":
+		outputTemplate, err := template.New("output").Funcs(outputTemplateFuncs()).Parse(templateText)
+		if err != nil {
+			return nil, fmt.Errorf("%w: the template is invalid: %w", zerr.ErrInvalidCLIParameter, err)
+		}
+
+		return &outputFormatter{writer: writer, template: outputTemplate}, nil
+	case len(columns) > 0:
+		return &outputFormatter{writer: writer, columns: columns, found: make([]bool, len(columns))}, nil
+	default:
+		return nil, nil //nolint: nilnil // the output isn't formatted
+	}
+}
+
+func (formatter *outputFormatter) Write(data []byte) (int, error) {
+	formatter.pending = append(formatter.pending, data...)
+
+	for {
+		index := bytes.IndexByte(formatter.pending, '\n')
+		if index < 0 {
+			break
+		}
+
+		line := formatter.pending[:index+1]
+		formatter.pending = formatter.pending[index+1:]
+
+		if err := formatter.formatLine(line); err != nil {
+			if formatter.err == nil {
+				formatter.err = err
+			}
+
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+func (formatter *outputFormatter) formatLine(line []byte) error {
+	var document interface{}
+
+	decoder := jsoniter.ConfigCompatibleWithStandardLibrary.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&document); err != nil {
+		_, err := formatter.writer.Write(line)
+
+		return err
+	}
+
+	if formatter.template != nil {
+		var builder strings.Builder
+
+		if err := formatter.template.Execute(&builder, document); err != nil {
+			return fmt.Errorf("%w: %w", zerr.ErrInvalidCLIParameter, err)
+		}
+
+		_, err := fmt.Fprintln(formatter.writer, builder.String())
+
+		return err
+	}
+
+	row := make([]string, len(formatter.columns))
+
+	for i, column := range formatter.columns {
+		value, found := getOutputField(document, column)
+		if found {
+			formatter.found[i] = true
+			row[i] = formatOutputField(value)
+		}
+	}
+
+	formatter.rows = append(formatter.rows, row)
+
+	return nil
+}
+
+// flush writes the last line and the table of the columns, a column missing in all the results is reported.
+func (formatter *outputFormatter) flush() error {
+	if formatter.err != nil {
+		return formatter.err
+	}
+
+	if len(formatter.pending) > 0 {
+		line := formatter.pending
+		formatter.pending = nil
+
+		if err := formatter.formatLine(line); err != nil {
+			return err
+		}
+	}
+
+	if formatter.columns == nil || len(formatter.rows) == 0 {
+		return nil
+	}
+
+	for i, found := range formatter.found {
+		if !found {
+			return fmt.Errorf("%w: no result has the field '%s'", zerr.ErrInvalidCLIParameter, formatter.columns[i])
+		}
+	}
+
+	table := getImageTableWriter(formatter.writer)
+	header := make([]string, len(formatter.columns))
+
+	for i, column := range formatter.columns {
+		header[i] = strings.ToUpper(column)
+	}
+
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.AppendBulk(formatter.rows)
+	table.Render()
+
+	return nil
+}
+
+// getOutputField returns the field of the json document at the path, the keys are matched regardless of their case.
+func getOutputField(document interface{}, path string) (interface{}, bool) {
+	value := document
+
+	for _, key := range strings.Split(path, ".") {
+		switch typedValue := value.(type) {
+		case map[string]interface{}:
+			field, found := typedValue[key]
+
+			if !found {
+				for name, nameValue := range typedValue {
+					if strings.EqualFold(name, key) {
+						field, found = nameValue, true
+
+						break
+					}
+				}
+			}
+
+			if !found {
+				return nil, false
+			}
+
+			value = field
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(typedValue) {
+				return nil, false
+			}
+
+			value = typedValue[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+func formatOutputField(value interface{}) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typedValue
+	case jsoniter.Number:
+		return typedValue.String()
+	case bool:
+		return strconv.FormatBool(typedValue)
+	default:
+		body, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(typedValue)
+		if err != nil {
+			return fmt.Sprint(typedValue)
+		}
+
+		return string(body)
+	}
+}
+
+// outputTemplateFuncs are the functions of the templates, besides the builtin ones.
+func outputTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			body, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(value)
+
+			return string(body), err
+		},
+		"join": func(values []interface{}, separator string) string {
+			fields := make([]string, len(values))
+
+			for i, value := range values {
+				fields[i] = formatOutputField(value)
+			}
+
+			return strings.Join(fields, separator)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"bytes": func(value interface{}) (string, error) {
+			size, err := strconv.ParseUint(formatOutputField(value), 10, 64)
+			if err != nil {
+				return "", err
+			}
+
+			return humanize.Bytes(size), nil
+		},
+	}
+}
@@ -0,0 +1,119 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/cobra"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestOutputTemplate(t *testing.T) {
+	Convey("zli --template and --columns", t, func() {
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		image := CreateRandomImage()
+		So(UploadImage(image, baseURL, "app", "1.0"), ShouldBeNil)
+		So(UploadImage(CreateRandomImage(), baseURL, "other", "2.0"), ShouldBeNil)
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"templatetest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		run := func(cmd *cobra.Command, args ...string) (string, error) {
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(args, "--config", "templatetest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		Convey("template", func() {
+			out, err := run(NewImageCommand(new(searchService)), "list", "--template",
+				"{{.repoName}}:{{.tag}} {{upper .mediaType}}")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "app:1.0 APPLICATION/VND.OCI.IMAGE.MANIFEST.V1+JSON\n")
+			So(out, ShouldContainSubstring, "other:2.0 ")
+			So(strings.Count(out, "\n"), ShouldEqual, 2)
+
+			out, err = run(NewImageCommand(new(searchService)), "list", "--template",
+				`{{range .manifests}}{{.digest}} {{bytes .size}}{{end}}`)
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, image.DigestStr()+" ")
+			So(out, ShouldContainSubstring, " B\n")
+
+			out, err = run(NewSearchCommand(new(searchService)), "query", "app", "--template",
+				"{{.name}} {{len .platforms}} {{json .vendors}}")
+			So(err, ShouldBeNil)
+			So(out, ShouldStartWith, "app 1 ")
+
+			_, err = run(NewImageCommand(new(searchService)), "list", "--template", "{{.repoName")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+
+			_, err = run(NewImageCommand(new(searchService)), "list", "--template", "{{bytes .repoName}}")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		})
+
+		Convey("columns", func() {
+			out, err := run(NewImageCommand(new(searchService)), "list", "--columns", "repoName,TAG,manifests.0.digest")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "REPONAME")
+			So(out, ShouldContainSubstring, "MANIFESTS.0.DIGEST")
+			So(out, ShouldContainSubstring, image.DigestStr())
+			So(out, ShouldNotContainSubstring, "SIGNED")
+
+			out, err = run(NewImageCommand(new(searchService)), "name", "app:1.0", "--columns", "tag", "--columns",
+				"isSigned")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "1.0")
+			So(out, ShouldContainSubstring, "false")
+			So(out, ShouldNotContainSubstring, "2.0")
+
+			_, err = run(NewImageCommand(new(searchService)), "list", "--columns", "repoName,unknown")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		})
+
+		Convey("invalid flags", func() {
+			_, err := run(NewImageCommand(new(searchService)), "list", "--columns", "tag", "--template", "{{.tag}}")
+			So(errors.Is(err, zerr.ErrInvalidFlagsCombination), ShouldBeTrue)
+
+			_, err = run(NewImageCommand(new(searchService)), "list", "--columns", "tag", "-f", "yaml")
+			So(errors.Is(err, zerr.ErrInvalidFlagsCombination), ShouldBeTrue)
+
+			out, err := run(NewImageCommand(new(searchService)), "list", "--columns", "tag", "-f", "json")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "TAG")
+		})
+	})
+}
@@ -0,0 +1,185 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v2"
+
+	zerr "zotregistry.io/zot/errors"
+	zcommon "zotregistry.io/zot/pkg/common"
+)
+
+const (
+	treeBranch     = "├── "
+	treeLastBranch = "└── "
+	treeIndent     = "│   "
+	treeLastIndent = "    "
+)
+
+// referrerNode is a referrer of an image, with the referrers of the referrer itself, like the signature
+// of a SBOM.
+type referrerNode struct {
+	ArtifactType string          `json:"artifacttype" yaml:"artifacttype"`
+	MediaType    string          `json:"mediatype"    yaml:"mediatype"`
+	Digest       string          `json:"digest"       yaml:"digest"`
+	Size         int             `json:"size"         yaml:"size"`
+	Referrers    []*referrerNode `json:"referrers"    yaml:"referrers"`
+}
+
+// referrersTree is the referrers graph of an image.
+type referrersTree struct {
+	Repo      string          `json:"repo"      yaml:"repo"`
+	Reference string          `json:"reference" yaml:"reference"`
+	Digest    string          `json:"digest"    yaml:"digest"`
+	Referrers []*referrerNode `json:"referrers" yaml:"referrers"`
+}
+
+// ShowReferrersTree prints all the referrers of the image, and the referrers of the referrers, as a tree.
+func ShowReferrersTree(config searchConfig, subject string) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	repo, ref, refIsTag, err := zcommon.GetRepoReference(subject)
+	if err != nil {
+		return err
+	}
+
+	digest := ref
+
+	if refIsTag {
+		digest, err = fetchImageDigest(repo, ref, username, password, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	tree := referrersTree{Repo: repo, Reference: ref, Digest: digest}
+
+	// the graph is not supposed to have cycles, but a broken registry shouldn't make zli loop forever
+	visited := map[string]bool{digest: true}
+
+	tree.Referrers, err = getReferrerNodes(ctx, config, username, password, repo, digest, visited)
+	if err != nil {
+		return err
+	}
+
+	out, err := tree.string(config.outputFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.resultWriter, out)
+
+	return nil
+}
+
+func getReferrerNodes(ctx context.Context, config searchConfig, username, password, repo, digest string,
+	visited map[string]bool,
+) ([]*referrerNode, error) {
+	referrers, err := config.searchService.getReferrers(ctx, config, username, password, repo, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []*referrerNode{}
+
+	for _, referrer := range referrers {
+		if visited[referrer.Digest] {
+			continue
+		}
+
+		visited[referrer.Digest] = true
+
+		node := &referrerNode{
+			ArtifactType: referrer.ArtifactType,
+			MediaType:    referrer.MediaType,
+			Digest:       referrer.Digest,
+			Size:         referrer.Size,
+		}
+
+		node.Referrers, err = getReferrerNodes(ctx, config, username, password, repo, referrer.Digest, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+func (tree referrersTree) string(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", defaultOutputFormat:
+		return tree.stringPlainText(), nil
+	case jsonFormat:
+		return tree.stringJSON()
+	case ymlFormat, yamlFormat:
+		return tree.stringYAML()
+	default:
+		return "", zerr.ErrInvalidOutputFormat
+	}
+}
+
+func (tree referrersTree) stringPlainText() string {
+	var builder strings.Builder
+
+	if tree.Reference == tree.Digest {
+		fmt.Fprintf(&builder, "%s@%s\n", tree.Repo, tree.Digest)
+	} else {
+		fmt.Fprintf(&builder, "%s:%s %s\n", tree.Repo, tree.Reference, tree.Digest)
+	}
+
+	writeReferrerNodes(&builder, tree.Referrers, "")
+
+	return builder.String()
+}
+
+func writeReferrerNodes(writer io.Writer, nodes []*referrerNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := treeBranch, treeIndent
+
+		if i == len(nodes)-1 {
+			branch, indent = treeLastBranch, treeLastIndent
+		}
+
+		// the artifact type is optional, image manifests can be referrers too
+		artifactType := node.ArtifactType
+		if artifactType == "" {
+			artifactType = node.MediaType
+		}
+
+		fmt.Fprintf(writer, "%s%s%s %s %s\n", prefix, branch, artifactType, node.Digest,
+			humanize.Bytes(uint64(node.Size)))
+
+		writeReferrerNodes(writer, node.Referrers, prefix+indent)
+	}
+}
+
+func (tree referrersTree) stringJSON() (string, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+	body, err := json.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body) + "\n", nil
+}
+
+func (tree referrersTree) stringYAML() (string, error) {
+	body, err := yaml.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+
+	return "---\n" + string(body), nil
+}
@@ -0,0 +1,108 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestImageReferrersCommand(t *testing.T) {
+	Convey("image referrers", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		image := CreateRandomImage()
+		err := UploadImage(image, baseURL, "repo", "1.0")
+		So(err, ShouldBeNil)
+
+		sbom := CreateRandomImageWith().ArtifactType("application/spdx+json").Subject(image.DescriptorRef()).Build()
+		err = UploadImage(sbom, baseURL, "repo", sbom.DigestStr())
+		So(err, ShouldBeNil)
+
+		signature := CreateRandomImageWith().ArtifactType(TestFakeSignatureArtType).Subject(image.DescriptorRef()).Build()
+		err = UploadImage(signature, baseURL, "repo", signature.DigestStr())
+		So(err, ShouldBeNil)
+
+		// the signature of the SBOM
+		sbomSignature := CreateRandomImageWith().ArtifactType(TestFakeSignatureArtType).
+			Subject(sbom.DescriptorRef()).Build()
+		err = UploadImage(sbomSignature, baseURL, "repo", sbomSignature.DigestStr())
+		So(err, ShouldBeNil)
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"reftest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		runImageCommand := func(args ...string) (string, error) {
+			cmd := NewImageCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(args, "--config", "reftest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		out, err := runImageCommand("referrers", "repo:1.0")
+		So(err, ShouldBeNil)
+		So(out, ShouldStartWith, "repo:1.0 "+image.DigestStr()+"\n")
+		So(out, ShouldContainSubstring, "application/spdx+json "+sbom.DigestStr())
+		So(out, ShouldContainSubstring, TestFakeSignatureArtType+" "+signature.DigestStr())
+		So(out, ShouldContainSubstring, "   └── "+TestFakeSignatureArtType+" "+sbomSignature.DigestStr())
+
+		out, err = runImageCommand("referrers", "repo@"+image.DigestStr(), "-f", "json")
+		So(err, ShouldBeNil)
+
+		tree := referrersTree{}
+		err = json.Unmarshal([]byte(out), &tree)
+		So(err, ShouldBeNil)
+		So(tree.Digest, ShouldEqual, image.DigestStr())
+		So(tree.Referrers, ShouldHaveLength, 2)
+
+		for _, referrer := range tree.Referrers {
+			if referrer.Digest == sbom.DigestStr() {
+				So(referrer.Referrers, ShouldHaveLength, 1)
+				So(referrer.Referrers[0].Digest, ShouldEqual, sbomSignature.DigestStr())
+				So(referrer.Referrers[0].ArtifactType, ShouldEqual, TestFakeSignatureArtType)
+			} else {
+				So(referrer.Digest, ShouldEqual, signature.DigestStr())
+				So(referrer.Referrers, ShouldBeEmpty)
+			}
+		}
+
+		out, err = runImageCommand("referrers", "repo:1.0", "-f", "yaml")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "artifacttype: application/spdx+json")
+
+		_, err = runImageCommand("referrers", "repo:1.0", "-f", "bad")
+		So(err, ShouldNotBeNil)
+
+		_, err = runImageCommand("referrers", "repo:2.0")
+		So(err, ShouldNotBeNil)
+
+		_, err = runImageCommand("referrers", "repo")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,70 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+func NewRegistryCommand(searchService SearchService) *cobra.Command {
+	registryCmd := &cobra.Command{
+		Use:   "registry [command]",
+		Short: "Show the statistics of the zot registry",
+		Long:  `Show the statistics of the zot registry, for quick operational checks`,
+		RunE:  ShowSuggestionsIfUnknownCommand,
+	}
+
+	registryCmd.SetUsageTemplate(registryCmd.UsageTemplate() + usageFooter)
+
+	registryCmd.PersistentFlags().String(cmdflags.URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	registryCmd.PersistentFlags().String(cmdflags.ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	registryCmd.PersistentFlags().StringP(cmdflags.UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	registryCmd.PersistentFlags().StringP(cmdflags.OutputFormatFlag, "f", "", "Specify output format [text/json/yaml]")
+	registryCmd.PersistentFlags().Bool(cmdflags.DebugFlag, false, "Show debug output")
+
+	registryCmd.AddCommand(NewRegistryStatsCommand(searchService))
+
+	return registryCmd
+}
+
+func NewRegistryStatsCommand(searchService SearchService) *cobra.Command {
+	var (
+		from  string
+		to    string
+		limit int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show the number of repos and images, the storage usage and the downloads",
+		Long: `Show the number of repos and images, the storage they use and the storage saved by the dedupe, and the
+largest repos. If the download statistics are enabled on the server, the most downloaded repos and the downloads
+of every day of the period are shown too. Only the repos the user can read are counted`,
+		Example: `  # the statistics of the downloads of this month
+  zli registry stats --from 2023-10-01 --config local`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return ShowRegistryStats(searchConfig, from, to, limit)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, cmdflags.FromFlag, "",
+		"Start of the period of the downloads, YYYY-MM-DD or RFC 3339, 30 days ago by default")
+	cmd.Flags().StringVar(&to, cmdflags.ToFlag, "",
+		"End of the period of the downloads, YYYY-MM-DD or RFC 3339, now by default")
+	cmd.Flags().IntVar(&limit, cmdflags.LimitFlag, defaultRegistryStatsLimit,
+		"Number of largest and most downloaded repos shown")
+
+	return cmd
+}
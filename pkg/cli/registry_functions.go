@@ -0,0 +1,197 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v2"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+const (
+	defaultRegistryStatsLimit = 10
+
+	registryStatsDayFormat = "2006-01-02"
+)
+
+// registryStats are the statistics of the registry returned by the search extension, the downloads are only set
+// if the download statistics are enabled.
+type registryStats struct {
+	Repos         int                `json:"repos"                  yaml:"repos"`
+	Images        int                `json:"images"                 yaml:"images"`
+	StorageSize   int64              `json:"storageSize"            yaml:"storagesize"`
+	UniqueSize    int64              `json:"uniqueSize"             yaml:"uniquesize"`
+	Dedupe        bool               `json:"dedupe"                 yaml:"dedupe"`
+	DedupeSavings int64              `json:"dedupeSavings"          yaml:"dedupesavings"`
+	LargestRepos  []repoStorage      `json:"largestRepos"           yaml:"largestrepos"`
+	TopDownloads  []repoDownloads    `json:"topDownloads,omitempty" yaml:"topdownloads,omitempty"`
+	Downloads     *downloadsOverTime `json:"downloads,omitempty"    yaml:"downloads,omitempty"`
+}
+
+type repoStorage struct {
+	Repo   string `json:"repo"   yaml:"repo"`
+	Images int    `json:"images" yaml:"images"`
+	Size   int64  `json:"size"   yaml:"size"`
+}
+
+type repoDownloads struct {
+	Repo          string `json:"repo"          yaml:"repo"`
+	Count         int    `json:"count"         yaml:"count"`
+	UniqueClients int    `json:"uniqueClients" yaml:"uniqueclients"`
+}
+
+type downloadsOverTime struct {
+	Total         int              `json:"total"         yaml:"total"`
+	UniqueClients int              `json:"uniqueClients" yaml:"uniqueclients"`
+	Points        []downloadsPoint `json:"points"        yaml:"points"`
+}
+
+type downloadsPoint struct {
+	Day           string `json:"day"           yaml:"day"`
+	Count         int    `json:"count"         yaml:"count"`
+	UniqueClients int    `json:"uniqueClients" yaml:"uniqueclients"`
+}
+
+// ShowRegistryStats prints the statistics of the registry, with the downloads between from and to.
+func ShowRegistryStats(config searchConfig, from, to string, limit int) error {
+	username, password := getUsernameAndPassword(config.user)
+
+	if limit <= 0 {
+		return fmt.Errorf("%w: --%s must be a positive number", zerr.ErrInvalidCLIParameter, cmdflags.LimitFlag)
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+
+	for flag, value := range map[string]string{cmdflags.FromFlag: from, cmdflags.ToFlag: to} {
+		if value == "" {
+			continue
+		}
+
+		if !isRegistryStatsTime(value) {
+			return fmt.Errorf("%w: --%s must be a day like 2023-10-01 or an RFC 3339 time, not '%s'",
+				zerr.ErrInvalidCLIParameter, flag, value)
+		}
+
+		query.Set(flag, value)
+	}
+
+	statsURL, err := combineServerAndEndpointURL(config.servURL, constants.FullSearchStats)
+	if err != nil {
+		return err
+	}
+
+	stats := registryStats{}
+
+	if _, err := makeGETRequest(context.Background(), statsURL+"?"+query.Encode(), username, password,
+		config.verifyTLS, config.debug, &stats, config.resultWriter); err != nil {
+		return err
+	}
+
+	return printRegistryStats(config, stats)
+}
+
+func isRegistryStatsTime(value string) bool {
+	if _, err := time.Parse(registryStatsDayFormat, value); err == nil {
+		return true
+	}
+
+	_, err := time.Parse(time.RFC3339, value)
+
+	return err == nil
+}
+
+func printRegistryStats(config searchConfig, stats registryStats) error {
+	switch strings.ToLower(config.outputFormat) {
+	case "", defaultOutputFormat:
+		printRegistryStatsText(config.resultWriter, stats)
+	case jsonFormat:
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+		body, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(config.resultWriter, string(body))
+	case ymlFormat, yamlFormat:
+		body, err := yaml.Marshal(stats)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(config.resultWriter, "---\n"+string(body))
+	default:
+		return zerr.ErrInvalidOutputFormat
+	}
+
+	return nil
+}
+
+func printRegistryStatsText(writer io.Writer, stats registryStats) {
+	dedupeSavings := "disabled"
+	if stats.Dedupe {
+		dedupeSavings = humanize.Bytes(uint64(stats.DedupeSavings))
+	}
+
+	fmt.Fprintf(writer, "Repositories:   %d\n", stats.Repos)
+	fmt.Fprintf(writer, "Images:         %d\n", stats.Images)
+	fmt.Fprintf(writer, "Storage:        %s\n", humanize.Bytes(uint64(stats.StorageSize)))
+	fmt.Fprintf(writer, "Unique blobs:   %s\n", humanize.Bytes(uint64(stats.UniqueSize)))
+	fmt.Fprintf(writer, "Dedupe savings: %s\n", dedupeSavings)
+
+	if len(stats.LargestRepos) > 0 {
+		fmt.Fprintln(writer, "\nLargest repositories:")
+
+		table := getImageTableWriter(writer)
+		table.SetHeader([]string{"REPOSITORY", "IMAGES", "SIZE"})
+
+		for _, repo := range stats.LargestRepos {
+			table.Append([]string{repo.Repo, strconv.Itoa(repo.Images), humanize.Bytes(uint64(repo.Size))})
+		}
+
+		table.Render()
+	}
+
+	// the download statistics aren't enabled
+	if stats.Downloads == nil {
+		return
+	}
+
+	if len(stats.TopDownloads) > 0 {
+		fmt.Fprintln(writer, "\nMost downloaded repositories:")
+
+		table := getImageTableWriter(writer)
+		table.SetHeader([]string{"REPOSITORY", "DOWNLOADS", "CLIENTS"})
+
+		for _, repo := range stats.TopDownloads {
+			table.Append([]string{repo.Repo, strconv.Itoa(repo.Count), strconv.Itoa(repo.UniqueClients)})
+		}
+
+		table.Render()
+	}
+
+	fmt.Fprintf(writer, "\nDownloads: %d by %d clients\n", stats.Downloads.Total, stats.Downloads.UniqueClients)
+
+	table := getImageTableWriter(writer)
+	table.SetHeader([]string{"DAY", "DOWNLOADS", "CLIENTS"})
+
+	for _, point := range stats.Downloads.Points {
+		table.Append([]string{point.Day, strconv.Itoa(point.Count), strconv.Itoa(point.UniqueClients)})
+	}
+
+	table.Render()
+}
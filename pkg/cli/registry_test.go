@@ -0,0 +1,143 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestRegistryStatsCommand(t *testing.T) {
+	Convey("zli registry stats", t, func() {
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		withDownloadStats := false
+
+		runStatsCommand := func(args ...string) (string, error) {
+			cmd := NewRegistryCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(append([]string{"stats"}, args...), "--config", "statstest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		startServer := func() func() {
+			if withDownloadStats {
+				conf.Extensions.Search.DownloadStats = &extconf.DownloadStatsConfig{}
+			}
+
+			ctlr := api.NewController(conf)
+			cm := test.NewControllerManager(ctlr)
+
+			cm.StartAndWait(conf.HTTP.Port)
+
+			image := CreateRandomImage()
+			So(UploadImage(image, baseURL, "app", "1.0"), ShouldBeNil)
+			So(UploadImage(image, baseURL, "app-copy", "1.0"), ShouldBeNil)
+			So(UploadImage(CreateRandomImage(), baseURL, "other", "1.0"), ShouldBeNil)
+
+			for i := 0; i < 2; i++ {
+				resp, err := resty.R().Get(baseURL + "/v2/app/manifests/1.0")
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			}
+
+			return cm.StopServer
+		}
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"statstest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		Convey("without the download statistics", func() {
+			defer startServer()()
+
+			out, err := runStatsCommand()
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Repositories:   3")
+			So(out, ShouldContainSubstring, "Images:         3")
+			So(out, ShouldContainSubstring, "Largest repositories")
+			So(out, ShouldContainSubstring, "app-copy")
+			So(out, ShouldNotContainSubstring, "Downloads")
+
+			out, err = runStatsCommand("-f", "json", "--limit", "1")
+			So(err, ShouldBeNil)
+
+			stats := registryStats{}
+			So(json.Unmarshal([]byte(out), &stats), ShouldBeNil)
+			So(stats.Dedupe, ShouldBeTrue)
+			So(stats.DedupeSavings, ShouldBeGreaterThan, 0)
+			So(stats.StorageSize, ShouldEqual, stats.UniqueSize+stats.DedupeSavings)
+			So(stats.LargestRepos, ShouldHaveLength, 1)
+			So(stats.Downloads, ShouldBeNil)
+
+			out, err = runStatsCommand("-f", "yaml")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "repos: 3")
+			So(out, ShouldNotContainSubstring, "downloads")
+
+			_, err = runStatsCommand("-f", "bad")
+			So(errors.Is(err, zerr.ErrInvalidOutputFormat), ShouldBeTrue)
+
+			_, err = runStatsCommand("--limit", "0")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+
+			_, err = runStatsCommand("--from", "yesterday")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+
+			// the period is checked by the server
+			_, err = runStatsCommand("--from", "2030-01-01", "--to", "2029-01-01T00:00:00Z")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("with the download statistics", func() {
+			withDownloadStats = true
+			defer startServer()()
+
+			out, err := runStatsCommand()
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "Most downloaded repositories")
+			So(out, ShouldContainSubstring, "Downloads: 2 by 1 clients")
+
+			out, err = runStatsCommand("-f", "json", "--from", "2023-10-01")
+			So(err, ShouldBeNil)
+
+			stats := registryStats{}
+			So(json.Unmarshal([]byte(out), &stats), ShouldBeNil)
+			So(stats.TopDownloads, ShouldResemble, []repoDownloads{{Repo: "app", Count: 2, UniqueClients: 1}})
+			So(stats.Downloads.Total, ShouldEqual, 2)
+			So(stats.Downloads.Points[0].Day, ShouldEqual, "2023-10-01")
+
+			_, err = runStatsCommand("--from", "2030-01-01", "--to", "2029-01-01")
+			So(errors.Is(err, zerr.ErrBadHTTPStatusCode), ShouldBeTrue)
+		})
+	})
+}
@@ -14,8 +14,8 @@ const prefix = "Searching... "
 func NewRepoCommand(searchService SearchService) *cobra.Command {
 	repoCmd := &cobra.Command{
 		Use:   "repo [config-name]",
-		Short: "List all repositories",
-		Long:  `List all repositories`,
+		Short: "List and delete repositories",
+		Long:  `List and delete repositories`,
 		RunE:  ShowSuggestionsIfUnknownCommand,
 	}
 
@@ -30,6 +30,7 @@ func NewRepoCommand(searchService SearchService) *cobra.Command {
 	repoCmd.PersistentFlags().Bool(cmdflags.DebugFlag, false, "Show debug output")
 
 	repoCmd.AddCommand(NewListReposCommand(searchService))
+	repoCmd.AddCommand(NewRemoveRepoCommand(searchService))
 
 	return repoCmd
 }
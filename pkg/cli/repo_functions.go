@@ -0,0 +1,91 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"zotregistry.io/zot/pkg/api/constants"
+)
+
+type repoDeletionToken struct {
+	Repo    string              `json:"repo"`
+	Token   string              `json:"token"`
+	Summary repoDeletionSummary `json:"summary"`
+}
+
+type repoDeletionSummary struct {
+	Tags             []string `json:"tags"`
+	Referrers        int      `json:"referrers"`
+	Signatures       int      `json:"signatures"`
+	ReclaimableBytes int64    `json:"reclaimableBytes"`
+}
+
+// DeleteRepo deletes the repo with the mgmt extension, after printing what will be removed. The deletion is
+// confirmed by reading "y" or "yes" from the input, unless it's forced.
+func DeleteRepo(config searchConfig, repo string, dryRun, force bool, input io.Reader) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	repoURL, err := combineServerAndEndpointURL(config.servURL, fmt.Sprintf("%s/%s",
+		constants.FullMgmtRepos, repo))
+	if err != nil {
+		return err
+	}
+
+	// the token obtained to confirm the deletion comes with the summary of what will be removed
+	token := &repoDeletionToken{}
+
+	if err := makePOSTRequest(ctx, repoURL+"/deletion", username, password, nil, config.verifyTLS, config.debug,
+		token, config.resultWriter); err != nil {
+		return err
+	}
+
+	printRepoDeletionSummary(config.resultWriter, token)
+
+	if dryRun {
+		return nil
+	}
+
+	if !force {
+		fmt.Fprintf(config.resultWriter, "Delete the repository %s? [y/N]: ", repo)
+
+		if !readConfirmation(input) {
+			fmt.Fprintln(config.resultWriter, "Deletion cancelled")
+
+			return nil
+		}
+	}
+
+	if err := makeDELETERequest(ctx, repoURL+"?token="+url.QueryEscape(token.Token), username, password,
+		config.verifyTLS, config.debug, config.resultWriter); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.resultWriter, "Deleted the repository %s\n", repo)
+
+	return nil
+}
+
+func printRepoDeletionSummary(writer io.Writer, token *repoDeletionToken) {
+	summary := token.Summary
+
+	fmt.Fprintf(writer, "Repository:  %s\n", token.Repo)
+
+	if len(summary.Tags) > 0 {
+		fmt.Fprintf(writer, "Tags:        %d (%s)\n", len(summary.Tags), strings.Join(summary.Tags, ", "))
+	} else {
+		fmt.Fprintln(writer, "Tags:        0")
+	}
+
+	fmt.Fprintf(writer, "Referrers:   %d\n", summary.Referrers)
+	fmt.Fprintf(writer, "Signatures:  %d\n", summary.Signatures)
+	fmt.Fprintf(writer, "Reclaimable: %s\n", humanize.Bytes(uint64(summary.ReclaimableBytes)))
+}
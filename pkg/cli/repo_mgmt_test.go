@@ -0,0 +1,115 @@
+//go:build search && mgmt
+// +build search,mgmt
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestRemoveRepoCommand(t *testing.T) {
+	Convey("repo rm", t, func() {
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("admin", "admin") + "\n" +
+			test.GetCredString("user", "user"))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{"user"}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{"admin"},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		for _, tag := range []string{"1.0", "2.0"} {
+			err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "repo", tag, "admin", "admin")
+			So(err, ShouldBeNil)
+		}
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"repormtest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		runRepoCommand := func(input, user string, args ...string) (string, error) {
+			cmd := NewRepoCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetIn(strings.NewReader(input))
+			cmd.SetArgs(append(args, "--config", "repormtest", "--user", user))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		repoExists := func() bool {
+			resp, err := resty.R().SetBasicAuth("admin", "admin").Get(baseURL + "/v2/repo/tags/list")
+			So(err, ShouldBeNil)
+
+			return resp.StatusCode() == http.StatusOK
+		}
+
+		out, err := runRepoCommand("", "admin:admin", "rm", "repo", "--dry-run")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Repository:  repo")
+		So(out, ShouldContainSubstring, "Tags:        2 (1.0, 2.0)")
+		So(out, ShouldContainSubstring, "Referrers:   0")
+		So(out, ShouldNotContainSubstring, "Reclaimable: 0 B")
+		So(out, ShouldNotContainSubstring, "Delete the repository")
+		So(repoExists(), ShouldBeTrue)
+
+		out, err = runRepoCommand("n\n", "admin:admin", "rm", "repo")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Delete the repository repo? [y/N]")
+		So(out, ShouldContainSubstring, "Deletion cancelled")
+		So(repoExists(), ShouldBeTrue)
+
+		// only the admins can delete repos
+		_, err = runRepoCommand("y\n", "user:user", "rm", "repo")
+		So(err, ShouldNotBeNil)
+		So(repoExists(), ShouldBeTrue)
+
+		out, err = runRepoCommand("y\n", "admin:admin", "rm", "repo")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Deleted the repository repo")
+		So(repoExists(), ShouldBeFalse)
+
+		_, err = runRepoCommand("", "admin:admin", "rm", "repo", "--force")
+		So(err, ShouldNotBeNil)
+	})
+}
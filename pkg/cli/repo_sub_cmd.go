@@ -34,3 +34,35 @@ func NewListReposCommand(searchService SearchService) *cobra.Command {
 
 	return cmd
 }
+
+func NewRemoveRepoCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm [repo-name]",
+		Short: "Delete a repository",
+		Long: `Delete a repository with all its tags, referrers and blobs, and everything the registry stores about it.
+What will be removed is printed before asking for confirmation. Only the admins can delete repositories.`,
+		Example: `  # show what would be removed, without deleting anything
+  zli repo rm alpine --dry-run --config local
+
+  # delete the repository without confirming it
+  zli repo rm alpine --force --config local`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepos(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			dryRun := defaultIfError(cmd.Flags().GetBool(cmdflags.DryRunFlag))
+			force := defaultIfError(cmd.Flags().GetBool(cmdflags.ForceFlag))
+
+			return DeleteRepo(searchConfig, args[0], dryRun, force, cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().Bool(cmdflags.DryRunFlag, false, "Only show what would be removed")
+	cmd.Flags().Bool(cmdflags.ForceFlag, false, "Delete without asking for confirmation")
+
+	return cmd
+}
@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	glob "github.com/bmatcuk/doublestar/v4"
 	"github.com/mitchellh/mapstructure"
 	distspec "github.com/opencontainers/distribution-spec/specs-go"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,9 +27,17 @@ import (
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/api/constants"
 	"zotregistry.io/zot/pkg/cli/cmdflags"
+	"zotregistry.io/zot/pkg/common"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	eventsConstants "zotregistry.io/zot/pkg/extensions/events/constants"
+	lintConstants "zotregistry.io/zot/pkg/extensions/lint/constants"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
+	scrubConstants "zotregistry.io/zot/pkg/extensions/scrub/constants"
+	syncConstants "zotregistry.io/zot/pkg/extensions/sync/constants"
 	zlog "zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/retention"
+	"zotregistry.io/zot/pkg/storage"
 	storageConstants "zotregistry.io/zot/pkg/storage/constants"
 )
 
@@ -191,6 +203,10 @@ func NewServerRootCmd() *cobra.Command {
 	return rootCmd
 }
 
+// CVESeverityThresholdExitCode is the exit code of zli when CVEs above the severity threshold are found,
+// telling them apart from the other errors.
+const CVESeverityThresholdExitCode = 2
+
 // "zli" - client-side cli.
 func NewCliRootCmd() *cobra.Command {
 	showVersion := false
@@ -268,7 +284,8 @@ func validateCacheConfig(cfg *config.Config, log zlog.Logger) error {
 		}
 
 		// unsupported cache driver
-		if cfg.Storage.CacheDriver["name"] != storageConstants.DynamoDBDriverName {
+		if cfg.Storage.CacheDriver["name"] != storageConstants.DynamoDBDriverName &&
+			cfg.Storage.CacheDriver["name"] != storageConstants.PostgresDriverName {
 			log.Error().Err(zerr.ErrBadConfig).
 				Interface("cacheDriver", cfg.Storage.CacheDriver["name"]).Msg("unsupported cache driver")
 
@@ -301,7 +318,8 @@ func validateCacheConfig(cfg *config.Config, log zlog.Logger) error {
 			}
 
 			// unsupported cache driver
-			if subPath.CacheDriver["name"] != storageConstants.DynamoDBDriverName {
+			if subPath.CacheDriver["name"] != storageConstants.DynamoDBDriverName &&
+				subPath.CacheDriver["name"] != storageConstants.PostgresDriverName {
 				log.Error().Err(zerr.ErrBadConfig).Interface("cacheDriver", cfg.Storage.CacheDriver["name"]).
 					Msg("unsupported cache driver")
 
@@ -339,6 +357,43 @@ func validateExtensionsConfig(cfg *config.Config, log zlog.Logger) error {
 		}
 	}
 
+	if cfg.Extensions != nil && cfg.Extensions.UI != nil {
+		uiConfig := cfg.Extensions.UI
+
+		if uiConfig.PathPrefix != "" && (!strings.HasPrefix(uiConfig.PathPrefix, "/") ||
+			strings.HasPrefix(uiConfig.PathPrefix+"/", constants.RoutePrefix+"/")) {
+			log.Error().Err(zerr.ErrBadConfig).Str("pathPrefix", uiConfig.PathPrefix).
+				Msg("ui: the path prefix must start with / and can't be under the registry API routes")
+
+			return zerr.ErrBadConfig
+		}
+
+		for _, route := range uiConfig.Routes {
+			if !strings.HasPrefix(route, "/") {
+				log.Error().Err(zerr.ErrBadConfig).Str("route", route).Msg("ui: the routes must start with /")
+
+				return zerr.ErrBadConfig
+			}
+		}
+
+		if uiConfig.Directory != "" {
+			if _, err := os.Stat(path.Join(uiConfig.Directory, "index.html")); err != nil {
+				log.Error().Err(err).Str("directory", uiConfig.Directory).
+					Msg("ui: couldn't find the index.html of the UI bundle")
+
+				return zerr.ErrBadConfig
+			}
+		}
+
+		if uiConfig.APIBaseURL != "" {
+			if _, err := url.Parse(uiConfig.APIBaseURL); err != nil {
+				log.Error().Err(err).Str("apiBaseURL", uiConfig.APIBaseURL).Msg("ui: invalid API base URL")
+
+				return zerr.ErrBadConfig
+			}
+		}
+	}
+
 	//nolint:lll
 	if cfg.Storage.StorageDriver != nil && cfg.Extensions != nil && cfg.Extensions.Search != nil &&
 		cfg.Extensions.Search.Enable != nil && *cfg.Extensions.Search.Enable && cfg.Extensions.Search.CVE != nil {
@@ -357,6 +412,247 @@ func validateExtensionsConfig(cfg *config.Config, log zlog.Logger) error {
 		}
 	}
 
+	if cfg.Extensions != nil && cfg.Extensions.Search != nil && cfg.Extensions.Search.CVE != nil &&
+		cfg.Extensions.Search.CVE.Repositories != nil {
+		repositories := cfg.Extensions.Search.CVE.Repositories
+
+		for _, pattern := range append(append([]string{}, repositories.Include...), repositories.Exclude...) {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).Msg("CVE repository pattern could not be compiled")
+
+				return glob.ErrBadPattern
+			}
+		}
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Search != nil && cfg.Extensions.Search.Federation != nil {
+		peerNames := map[string]bool{}
+
+		for _, peer := range cfg.Extensions.Search.Federation.Peers {
+			if peer.Name == "" || peer.URL == "" || peer.Name == extconf.FederationLocalSource || peerNames[peer.Name] {
+				log.Error().Err(zerr.ErrFederationPeerInvalid).Str("peer", peer.Name).Msg("invalid federation peer")
+
+				return zerr.ErrFederationPeerInvalid
+			}
+
+			peerNames[peer.Name] = true
+		}
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Search != nil && cfg.Extensions.Search.DownloadStats != nil &&
+		cfg.Extensions.Search.DownloadStats.Retention < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("retention", cfg.Extensions.Search.DownloadStats.Retention).
+			Msg("download statistics retention can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Search != nil && cfg.Extensions.Search.DownloadStats != nil &&
+		cfg.Extensions.Search.DownloadStats.HourlyRetention < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("hourlyRetention", cfg.Extensions.Search.DownloadStats.HourlyRetention).
+			Msg("hourly download statistics retention can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Events != nil {
+		for _, sink := range cfg.Extensions.Events.Sinks {
+			if sink.Type != eventsConstants.HTTPSink && sink.Type != eventsConstants.NATSSink &&
+				sink.Type != eventsConstants.KafkaSink {
+				log.Error().Err(zerr.ErrBadConfig).Str("type", sink.Type).
+					Msg("events: unknown sink type, expected http, nats or kafka")
+
+				return zerr.ErrBadConfig
+			}
+
+			if sink.Address == "" {
+				log.Error().Err(zerr.ErrBadConfig).Str("type", sink.Type).Msg("events: sink address is required")
+
+				return zerr.ErrBadConfig
+			}
+
+			if sink.Format != "" && sink.Format != eventsConstants.JSONFormat &&
+				sink.Format != eventsConstants.CloudEventsFormat {
+				log.Error().Err(zerr.ErrBadConfig).Str("format", sink.Format).
+					Msg("events: unknown sink format, expected json or cloudevents")
+
+				return zerr.ErrBadConfig
+			}
+		}
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Plugins != nil {
+		pluginNames := map[string]bool{}
+
+		for _, plugin := range cfg.Extensions.Plugins.Plugins {
+			if plugin.Name == "" || strings.Contains(plugin.Name, "/") || pluginNames[plugin.Name] {
+				log.Error().Err(zerr.ErrBadConfig).Str("name", plugin.Name).
+					Msg("plugins: plugin names must be unique, non-empty and without slashes")
+
+				return zerr.ErrBadConfig
+			}
+
+			pluginNames[plugin.Name] = true
+
+			if plugin.Path == "" {
+				log.Error().Err(zerr.ErrBadConfig).Str("name", plugin.Name).Msg("plugins: plugin path is required")
+
+				return zerr.ErrBadConfig
+			}
+
+			if plugin.StartTimeout < 0 || plugin.Timeout < 0 {
+				log.Error().Err(zerr.ErrBadConfig).Str("name", plugin.Name).Msg("plugins: timeouts can't be negative")
+
+				return zerr.ErrBadConfig
+			}
+		}
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Metrics != nil && cfg.Extensions.Metrics.OTLP != nil {
+		if _, err := url.ParseRequestURI(cfg.Extensions.Metrics.OTLP.Endpoint); err != nil {
+			log.Error().Err(zerr.ErrBadConfig).Str("endpoint", cfg.Extensions.Metrics.OTLP.Endpoint).
+				Msg("metrics: invalid OTLP collector endpoint")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Metrics != nil && cfg.Extensions.Metrics.RepoLabels != nil &&
+		cfg.Extensions.Metrics.RepoLabels.Max < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Int("max", cfg.Extensions.Metrics.RepoLabels.Max).
+			Msg("metrics: the max number of repo labels can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Lint != nil {
+		for _, rule := range cfg.Extensions.Lint.Rules {
+			if rule.Mode != "" && rule.Mode != lintConstants.EnforceMode && rule.Mode != lintConstants.WarnMode {
+				log.Error().Err(zerr.ErrBadConfig).Str("mode", rule.Mode).
+					Msg("lint: unknown rule mode, expected enforce or warn")
+
+				return zerr.ErrBadConfig
+			}
+
+			for _, pattern := range append(append([]string{}, rule.Repos...), rule.ForbiddenBaseImages...) {
+				if ok := glob.ValidatePattern(pattern); !ok {
+					log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).Msg("lint rule pattern could not be compiled")
+
+					return glob.ErrBadPattern
+				}
+			}
+
+			for _, platform := range rule.RequiredPlatforms {
+				if parts := strings.Split(platform, "/"); len(parts) < 2 || len(parts) > 3 {
+					log.Error().Err(zerr.ErrBadConfig).Str("platform", platform).
+						Msg("lint: invalid required platform, expected os/arch[/variant]")
+
+					return zerr.ErrBadConfig
+				}
+			}
+		}
+
+		if policy := cfg.Extensions.Lint.Policy; policy != nil {
+			if len(policy.Paths) == 0 {
+				log.Error().Err(zerr.ErrBadConfig).Msg("lint: the paths of the Rego policies are required")
+
+				return zerr.ErrBadConfig
+			}
+
+			for _, policyPath := range policy.Paths {
+				if _, err := os.Stat(policyPath); err != nil {
+					log.Error().Err(err).Str("path", policyPath).Msg("lint: couldn't find the Rego policies")
+
+					return zerr.ErrBadConfig
+				}
+			}
+
+			if policy.Mode != "" && policy.Mode != lintConstants.EnforceMode && policy.Mode != lintConstants.WarnMode {
+				log.Error().Err(zerr.ErrBadConfig).Str("mode", policy.Mode).
+					Msg("lint: unknown policy mode, expected enforce or warn")
+
+				return zerr.ErrBadConfig
+			}
+		}
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Trust != nil {
+		trust := cfg.Extensions.Trust
+
+		if trust.NotationTrustPolicy != "" {
+			if !trust.Notation {
+				log.Error().Err(zerr.ErrBadConfig).
+					Msg("imagetrust: notation must be enabled to use a notation trust policy")
+
+				return zerr.ErrBadConfig
+			}
+
+			if _, err := os.Stat(trust.NotationTrustPolicy); err != nil {
+				log.Error().Err(err).Str("path", trust.NotationTrustPolicy).
+					Msg("imagetrust: couldn't find the notation trust policy")
+
+				return zerr.ErrBadConfig
+			}
+		}
+
+		if len(trust.EnforcedRepos) > 0 && !trust.Cosign && !trust.Notation {
+			log.Error().Err(zerr.ErrBadConfig).
+				Msg("imagetrust: cosign or notation must be enabled to enforce the image trust")
+
+			return zerr.ErrBadConfig
+		}
+
+		for _, pattern := range trust.EnforcedRepos {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).
+					Msg("imagetrust: enforced repos pattern could not be compiled")
+
+				return glob.ErrBadPattern
+			}
+		}
+
+		for _, policy := range trust.SignaturePolicies {
+			if err := validateSignaturePolicy(trust, policy, log); err != nil {
+				return err
+			}
+		}
+
+		if trust.CosignKeyless != nil {
+			if err := validateCosignKeylessConfig(trust, log); err != nil {
+				return err
+			}
+		}
+
+		if trust.VerificationInterval < 0 {
+			log.Error().Err(zerr.ErrBadConfig).Dur("verificationInterval", trust.VerificationInterval).
+				Msg("imagetrust: the interval of the signatures verification can't be negative")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	if cfg.Extensions != nil && cfg.Extensions.Scrub != nil && cfg.Extensions.Scrub.Repair != nil {
+		repair := cfg.Extensions.Scrub.Repair
+
+		for _, source := range repair.Sources {
+			switch source {
+			case scrubConstants.DedupeRepairSource, scrubConstants.SyncRepairSource:
+			case scrubConstants.ReplicaRepairSource:
+				if repair.ReplicaDirectory == "" {
+					log.Error().Err(zerr.ErrBadConfig).
+						Msg("scrub: replicaDirectory is required to repair blobs from the replica")
+
+					return zerr.ErrBadConfig
+				}
+			default:
+				log.Error().Err(zerr.ErrBadConfig).Str("source", source).
+					Msg("scrub: unknown repair source, expected dedupe, replica or sync")
+
+				return zerr.ErrBadConfig
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -369,6 +665,34 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 		return err
 	}
 
+	if err := validateUsageAlerts(config, log); err != nil {
+		return err
+	}
+
+	if err := validateQuotas(config, log); err != nil {
+		return err
+	}
+
+	if err := validateTagImmutability(config, log); err != nil {
+		return err
+	}
+
+	if err := validateRetention(config, log); err != nil {
+		return err
+	}
+
+	if err := validateMetaDB(config, log); err != nil {
+		return err
+	}
+
+	if err := validateReconcile(config, log); err != nil {
+		return err
+	}
+
+	if err := validateCluster(config, log); err != nil {
+		return err
+	}
+
 	if err := validateLDAP(config, log); err != nil {
 		return err
 	}
@@ -377,6 +701,34 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 		return err
 	}
 
+	if err := validateUserActivity(config, log); err != nil {
+		return err
+	}
+
+	if err := validateLogOutput(config, log); err != nil {
+		return err
+	}
+
+	if err := validateAuditLog(config, log); err != nil {
+		return err
+	}
+
+	if err := validateAccessLog(config, log); err != nil {
+		return err
+	}
+
+	if err := validateLogComponents(config, log); err != nil {
+		return err
+	}
+
+	if err := validateLogSampling(config, log); err != nil {
+		return err
+	}
+
+	if err := validateLogRedaction(config, log); err != nil {
+		return err
+	}
+
 	if err := validateSync(config, log); err != nil {
 		return err
 	}
@@ -402,9 +754,9 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 	}
 
 	if len(config.Storage.StorageDriver) != 0 {
-		// enforce s3 driver in case of using storage driver
-		if config.Storage.StorageDriver["name"] != storageConstants.S3StorageDriverName {
-			log.Error().Err(zerr.ErrBadConfig).Interface("cacheDriver", config.Storage.StorageDriver["name"]).
+		// enforce s3, azure or plugin driver in case of using storage driver
+		if err := validateStorageDriver(config.Storage.StorageDriver); err != nil {
+			log.Error().Err(err).Interface("storageDriver", config.Storage.StorageDriver["name"]).
 				Msg("unsupported storage driver")
 
 			return zerr.ErrBadConfig
@@ -418,15 +770,15 @@ func validateConfiguration(config *config.Config, log zlog.Logger) error {
 		}
 	}
 
-	// enforce s3 driver on subpaths in case of using storage driver
+	// enforce s3, azure or plugin driver on subpaths in case of using storage driver
 	if config.Storage.SubPaths != nil {
 		if len(config.Storage.SubPaths) > 0 {
 			subPaths := config.Storage.SubPaths
 
 			for route, storageConfig := range subPaths {
 				if len(storageConfig.StorageDriver) != 0 {
-					if storageConfig.StorageDriver["name"] != storageConstants.S3StorageDriverName {
-						log.Error().Err(zerr.ErrBadConfig).Str("subpath", route).Interface("storageDriver",
+					if err := validateStorageDriver(storageConfig.StorageDriver); err != nil {
+						log.Error().Err(err).Str("subpath", route).Interface("storageDriver",
 							storageConfig.StorageDriver["name"]).Msg("unsupported storage driver")
 
 						return zerr.ErrBadConfig
@@ -482,12 +834,14 @@ func validateOpenIDConfig(cfg *config.Config, log zlog.Logger) error {
 	return nil
 }
 
-func validateAuthzPolicies(config *config.Config, log zlog.Logger) error {
-	if (config.HTTP.Auth == nil || (config.HTTP.Auth.HTPasswd.Path == "" && config.HTTP.Auth.LDAP == nil &&
-		config.HTTP.Auth.OpenID == nil)) && !authzContainsOnlyAnonymousPolicy(config) {
-		log.Error().Err(zerr.ErrBadConfig).
-			Msg("access control config requires one of httpasswd, ldap or openid authentication " +
-				"or using only 'anonymousPolicy' policies")
+func validateUserActivity(config *config.Config, log zlog.Logger) error {
+	if config.HTTP.Auth == nil || config.HTTP.Auth.Activity == nil {
+		return nil
+	}
+
+	if config.HTTP.Auth.Activity.Retention < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("retention", config.HTTP.Auth.Activity.Retention).
+			Msg("user activity retention can't be negative")
 
 		return zerr.ErrBadConfig
 	}
@@ -495,59 +849,250 @@ func validateAuthzPolicies(config *config.Config, log zlog.Logger) error {
 	return nil
 }
 
-//nolint:gocyclo,cyclop,nestif
-func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log zlog.Logger) {
-	defaultVal := true
+func validateAuditLog(config *config.Config, log zlog.Logger) error {
+	auditLogConfig := config.Log.GetAuditLogConfig()
+	if auditLogConfig == nil {
+		return nil
+	}
 
-	if config.Extensions == nil && viperInstance.Get("extensions") != nil {
-		config.Extensions = &extconf.ExtensionConfig{}
+	if auditLogConfig.Output == "" && auditLogConfig.Syslog == nil {
+		log.Error().Err(zerr.ErrBadConfig).Msg("audit log requires an output file or syslog")
 
-		extMap := viperInstance.GetStringMap("extensions")
-		_, ok := extMap["metrics"]
+		return zerr.ErrBadConfig
+	}
 
-		if ok {
-			// we found a config like `"extensions": {"metrics": {}}`
-			// Note: In case metrics is not empty the config.Extensions will not be nil and we will not reach here
-			config.Extensions.Metrics = &extconf.MetricsConfig{}
-		}
+	if auditLogConfig.MaxSize < 0 || auditLogConfig.MaxBackups < 0 || auditLogConfig.MaxAge < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Int("maxSize", auditLogConfig.MaxSize).
+			Int("maxBackups", auditLogConfig.MaxBackups).Dur("maxAge", auditLogConfig.MaxAge).
+			Msg("audit log rotation settings can't be negative")
 
-		_, ok = extMap["search"]
-		if ok {
-			// we found a config like `"extensions": {"search": {}}`
-			// Note: In case search is not empty the config.Extensions will not be nil and we will not reach here
-			config.Extensions.Search = &extconf.SearchConfig{}
-		}
+		return zerr.ErrBadConfig
+	}
 
-		_, ok = extMap["scrub"]
-		if ok {
-			// we found a config like `"extensions": {"scrub:": {}}`
-			// Note: In case scrub is not empty the config.Extensions will not be nil and we will not reach here
-			config.Extensions.Scrub = &extconf.ScrubConfig{}
-		}
+	if auditLogConfig.Syslog != nil {
+		return validateSyslog(auditLogConfig.Syslog, log)
+	}
 
-		_, ok = extMap["trust"]
-		if ok {
-			// we found a config like `"extensions": {"trust:": {}}`
-			// Note: In case trust is not empty the config.Extensions will not be nil and we will not reach here
-			config.Extensions.Trust = &extconf.ImageTrustConfig{}
-		}
+	return nil
+}
 
-		_, ok = extMap["ui"]
-		if ok {
-			// we found a config like `"extensions": {"ui:": {}}`
-			// Note: In case UI is not empty the config.Extensions will not be nil and we will not reach here
-			config.Extensions.UI = &extconf.UIConfig{}
-		}
+func validateAccessLog(config *config.Config, log zlog.Logger) error {
+	accessLogConfig := config.Log.AccessLog
+	if accessLogConfig == nil {
+		return nil
 	}
 
-	if config.Extensions != nil {
-		if config.Extensions.Sync != nil {
-			if config.Extensions.Sync.Enable == nil {
-				config.Extensions.Sync.Enable = &defaultVal
-			}
+	if accessLogConfig.Output == "" {
+		log.Error().Err(zerr.ErrBadConfig).Msg("access log requires an output file")
 
-			for id, regCfg := range config.Extensions.Sync.Registries {
-				if regCfg.TLSVerify == nil {
+		return zerr.ErrBadConfig
+	}
+
+	if accessLogConfig.MaxSize < 0 || accessLogConfig.RotateInterval < 0 || accessLogConfig.MaxBackups < 0 ||
+		accessLogConfig.MaxAge < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Int("maxSize", accessLogConfig.MaxSize).
+			Dur("rotateInterval", accessLogConfig.RotateInterval).Int("maxBackups", accessLogConfig.MaxBackups).
+			Dur("maxAge", accessLogConfig.MaxAge).Msg("access log rotation settings can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	for _, fields := range [][]string{accessLogConfig.Fields, accessLogConfig.ErrorFields} {
+		for _, field := range fields {
+			if !common.Contains(zlog.AccessFields(), field) {
+				log.Error().Err(zerr.ErrBadConfig).Str("field", field).Strs("supported", zlog.AccessFields()).
+					Msg("unsupported access log field")
+
+				return zerr.ErrBadConfig
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateLogOutput(config *config.Config, log zlog.Logger) error {
+	outputs := 0
+
+	for _, isSet := range []bool{config.Log.Output != "", config.Log.Syslog != nil, config.Log.Journald} {
+		if isSet {
+			outputs++
+		}
+	}
+
+	if outputs > 1 {
+		log.Error().Err(zerr.ErrBadConfig).Msg("log output, syslog and journald can't be used together")
+
+		return zerr.ErrBadConfig
+	}
+
+	if config.Log.Syslog != nil {
+		return validateSyslog(config.Log.Syslog, log)
+	}
+
+	return nil
+}
+
+func validateLogRedaction(config *config.Config, log zlog.Logger) error {
+	for _, pattern := range config.Log.Redact {
+		if _, err := regexp.Compile(pattern); err != nil {
+			log.Error().Err(err).Str("pattern", pattern).Msg("log redaction pattern could not be compiled")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	return nil
+}
+
+func validateSyslog(syslogConfig *config.SyslogConfig, log zlog.Logger) error {
+	switch syslogConfig.Network {
+	case "", "unix":
+		return nil
+	case "tcp", "udp":
+		if syslogConfig.Address == "" {
+			log.Error().Err(zerr.ErrBadConfig).Str("network", syslogConfig.Network).
+				Msg("syslog network requires an address")
+
+			return zerr.ErrBadConfig
+		}
+
+		return nil
+	default:
+		log.Error().Err(zerr.ErrBadConfig).Str("network", syslogConfig.Network).
+			Msg("unsupported syslog network, only tcp, udp and unix are supported")
+
+		return zerr.ErrBadConfig
+	}
+}
+
+func validateLogComponents(config *config.Config, log zlog.Logger) error {
+	for component, level := range config.Log.Components {
+		if !common.Contains(zlog.Components(), component) {
+			log.Error().Err(zerr.ErrBadConfig).Str("component", component).Strs("supported", zlog.Components()).
+				Msg("log level of an unknown component")
+
+			return zerr.ErrBadConfig
+		}
+
+		if _, err := zerolog.ParseLevel(level); err != nil || level == "" {
+			log.Error().Err(zerr.ErrBadConfig).Str("component", component).Str("level", level).
+				Msg("invalid log level of the component")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	return nil
+}
+
+func validateLogSampling(config *config.Config, log zlog.Logger) error {
+	for levelName, levelSampling := range config.Log.Sampling {
+		if _, err := zerolog.ParseLevel(levelName); err != nil {
+			log.Error().Err(zerr.ErrBadConfig).Str("level", levelName).Msg("log sampling of an unknown level")
+
+			return zerr.ErrBadConfig
+		}
+
+		if levelSampling.Period < 0 || (levelSampling.Burst == 0) != (levelSampling.Period == 0) {
+			log.Error().Err(zerr.ErrBadConfig).Str("level", levelName).Uint32("burst", levelSampling.Burst).
+				Dur("period", levelSampling.Period).
+				Msg("log sampling requires both a burst and a positive period, or neither")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	return nil
+}
+
+func validateAuthzPolicies(config *config.Config, log zlog.Logger) error {
+	if (config.HTTP.Auth == nil || (config.HTTP.Auth.HTPasswd.Path == "" && config.HTTP.Auth.LDAP == nil &&
+		config.HTTP.Auth.OpenID == nil)) && !authzContainsOnlyAnonymousPolicy(config) {
+		log.Error().Err(zerr.ErrBadConfig).
+			Msg("access control config requires one of httpasswd, ldap or openid authentication " +
+				"or using only 'anonymousPolicy' policies")
+
+		return zerr.ErrBadConfig
+	}
+
+	return nil
+}
+
+//nolint:gocyclo,cyclop,nestif
+func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log zlog.Logger) {
+	defaultVal := true
+
+	if config.Extensions == nil && viperInstance.Get("extensions") != nil {
+		config.Extensions = &extconf.ExtensionConfig{}
+
+		extMap := viperInstance.GetStringMap("extensions")
+		_, ok := extMap["metrics"]
+
+		if ok {
+			// we found a config like `"extensions": {"metrics": {}}`
+			// Note: In case metrics is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.Metrics = &extconf.MetricsConfig{}
+		}
+
+		_, ok = extMap["search"]
+		if ok {
+			// we found a config like `"extensions": {"search": {}}`
+			// Note: In case search is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.Search = &extconf.SearchConfig{}
+		}
+
+		_, ok = extMap["scrub"]
+		if ok {
+			// we found a config like `"extensions": {"scrub:": {}}`
+			// Note: In case scrub is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.Scrub = &extconf.ScrubConfig{}
+		}
+
+		_, ok = extMap["trust"]
+		if ok {
+			// we found a config like `"extensions": {"trust:": {}}`
+			// Note: In case trust is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.Trust = &extconf.ImageTrustConfig{}
+		}
+
+		_, ok = extMap["secrets"]
+		if ok {
+			// we found a config like `"extensions": {"secrets:": {}}`
+			// Note: In case secrets is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.Secrets = &extconf.SecretsConfig{}
+		}
+
+		_, ok = extMap["events"]
+		if ok {
+			// we found a config like `"extensions": {"events:": {}}`
+			// Note: In case events is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.Events = &extconf.EventsConfig{}
+		}
+
+		_, ok = extMap["plugins"]
+		if ok {
+			// we found a config like `"extensions": {"plugins:": {}}`
+			// Note: In case plugins is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.Plugins = &extconf.PluginsConfig{}
+		}
+
+		_, ok = extMap["ui"]
+		if ok {
+			// we found a config like `"extensions": {"ui:": {}}`
+			// Note: In case UI is not empty the config.Extensions will not be nil and we will not reach here
+			config.Extensions.UI = &extconf.UIConfig{}
+		}
+	}
+
+	if config.Extensions != nil {
+		if config.Extensions.Sync != nil {
+			if config.Extensions.Sync.Enable == nil {
+				config.Extensions.Sync.Enable = &defaultVal
+			}
+
+			for id, regCfg := range config.Extensions.Sync.Registries {
+				if regCfg.TLSVerify == nil {
 					config.Extensions.Sync.Registries[id].TLSVerify = &defaultVal
 				}
 			}
@@ -585,6 +1130,10 @@ func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log z
 						Msg("Config: using default Trivy Java DB download URL.")
 					config.Extensions.Search.CVE.Trivy.JavaDBRepository = defaultJavaDBDownloadURL
 				}
+
+				if config.Extensions.Search.CVE.Rescan != nil && config.Extensions.Search.CVE.Rescan.Interval == 0 {
+					config.Extensions.Search.CVE.Rescan.Interval = config.Extensions.Search.CVE.UpdateInterval
+				}
 			}
 		}
 
@@ -596,6 +1145,10 @@ func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log z
 			if config.Extensions.Metrics.Prometheus == nil {
 				config.Extensions.Metrics.Prometheus = &extconf.PrometheusConfig{Path: constants.DefaultMetricsExtensionRoute}
 			}
+
+			if config.Extensions.Metrics.OTLP != nil && config.Extensions.Metrics.OTLP.Interval == 0 {
+				config.Extensions.Metrics.OTLP.Interval = time.Minute
+			}
 		}
 
 		if config.Extensions.Scrub != nil {
@@ -606,12 +1159,27 @@ func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log z
 			if config.Extensions.Scrub.Interval == 0 {
 				config.Extensions.Scrub.Interval = 24 * time.Hour //nolint: gomnd
 			}
+
+			if repair := config.Extensions.Scrub.Repair; repair != nil && len(repair.Sources) == 0 {
+				// local copies are tried before downloading the blobs
+				repair.Sources = []string{scrubConstants.DedupeRepairSource}
+
+				if repair.ReplicaDirectory != "" {
+					repair.Sources = append(repair.Sources, scrubConstants.ReplicaRepairSource)
+				}
+
+				repair.Sources = append(repair.Sources, scrubConstants.SyncRepairSource)
+			}
 		}
 
 		if config.Extensions.UI != nil {
 			if config.Extensions.UI.Enable == nil {
 				config.Extensions.UI.Enable = &defaultVal
 			}
+
+			if config.Extensions.UI.PathPrefix == "" {
+				config.Extensions.UI.PathPrefix = "/"
+			}
 		}
 
 		if config.Extensions.Trust != nil {
@@ -619,6 +1187,28 @@ func applyDefaultValues(config *config.Config, viperInstance *viper.Viper, log z
 				config.Extensions.Trust.Enable = &defaultVal
 			}
 		}
+
+		if config.Extensions.Secrets != nil {
+			if config.Extensions.Secrets.Enable == nil {
+				config.Extensions.Secrets.Enable = &defaultVal
+			}
+
+			if config.Extensions.Secrets.MaxFileSize <= 0 {
+				config.Extensions.Secrets.MaxFileSize = 1 << 20 //nolint: gomnd
+			}
+		}
+
+		if config.Extensions.Events != nil {
+			if config.Extensions.Events.Enable == nil {
+				config.Extensions.Events.Enable = &defaultVal
+			}
+		}
+
+		if config.Extensions.Plugins != nil {
+			if config.Extensions.Plugins.Enable == nil {
+				config.Extensions.Plugins.Enable = &defaultVal
+			}
+		}
 	}
 
 	if !config.Storage.GC {
@@ -724,6 +1314,19 @@ func updateDistSpecVersion(config *config.Config, log zlog.Logger) {
 }
 
 func LoadConfiguration(config *config.Config, configPath string) error {
+	viperInstance, metaData, err := readConfiguration(config, configPath, zlog.Logger{Logger: log.Logger})
+	if err != nil {
+		return err
+	}
+
+	log := zlog.NewLogger(config.Log.Level, config.Log.Output)
+
+	return checkConfiguration(config, viperInstance, metaData, log)
+}
+
+// readConfiguration reads the config file, the metadata tells which keys were set and which are unknown.
+func readConfiguration(config *config.Config, configPath string, log zlog.Logger,
+) (*viper.Viper, *mapstructure.Metadata, error) {
 	// Default is dot (.) but because we allow glob patterns in authz
 	// we need another key delimiter.
 	viperInstance := viper.NewWithOptions(viper.KeyDelimiter("::"))
@@ -733,18 +1336,23 @@ func LoadConfiguration(config *config.Config, configPath string) error {
 	if err := viperInstance.ReadInConfig(); err != nil {
 		log.Error().Err(err).Msg("error while reading configuration")
 
-		return err
+		return nil, nil, err
 	}
 
 	metaData := &mapstructure.Metadata{}
 	if err := viperInstance.Unmarshal(&config, metadataConfig(metaData)); err != nil {
 		log.Error().Err(err).Msg("error while unmarshaling new config")
 
-		return err
+		return nil, nil, err
 	}
 
-	log := zlog.NewLogger(config.Log.Level, config.Log.Output)
+	return viperInstance, metaData, nil
+}
 
+// checkConfiguration applies the default values to the config read from the file and validates it.
+func checkConfiguration(config *config.Config, viperInstance *viper.Viper, metaData *mapstructure.Metadata,
+	log zlog.Logger,
+) error {
 	if len(metaData.Keys) == 0 {
 		log.Error().Err(zerr.ErrBadConfig).Msg("config doesn't contain any key:value pair")
 
@@ -811,6 +1419,116 @@ func authzContainsOnlyAnonymousPolicy(cfg *config.Config) bool {
 	return anonymousPolicyPresent
 }
 
+func validateSignaturePolicy(trust *extconf.ImageTrustConfig, policy extconf.SignaturePolicy, log zlog.Logger,
+) error {
+	if len(policy.Repos) == 0 {
+		log.Error().Err(zerr.ErrInvalidSignaturePolicy).Msg("imagetrust: the signature policies require repos")
+
+		return zerr.ErrInvalidSignaturePolicy
+	}
+
+	for _, pattern := range policy.Repos {
+		if ok := glob.ValidatePattern(pattern); !ok {
+			log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).
+				Msg("imagetrust: signature policy repos pattern could not be compiled")
+
+			return glob.ErrBadPattern
+		}
+	}
+
+	if !trust.Cosign && !trust.Notation {
+		log.Error().Err(zerr.ErrInvalidSignaturePolicy).
+			Msg("imagetrust: cosign or notation must be enabled to use signature policies")
+
+		return zerr.ErrInvalidSignaturePolicy
+	}
+
+	for _, signatureType := range policy.SignatureTypes {
+		if (signatureType != common.CosignSignature || !trust.Cosign) &&
+			(signatureType != common.NotationSignature || !trust.Notation) {
+			log.Error().Err(zerr.ErrInvalidSignaturePolicy).Str("type", signatureType).
+				Msg("imagetrust: signature type of the signature policy is unknown or not enabled")
+
+			return zerr.ErrInvalidSignaturePolicy
+		}
+	}
+
+	return nil
+}
+
+func validateCosignKeylessConfig(trust *extconf.ImageTrustConfig, log zlog.Logger) error {
+	keyless := trust.CosignKeyless
+
+	if !trust.Cosign {
+		log.Error().Err(zerr.ErrBadConfig).
+			Msg("imagetrust: cosign must be enabled to use the cosign keyless verification")
+
+		return zerr.ErrBadConfig
+	}
+
+	if keyless.FulcioRoots == "" {
+		log.Error().Err(zerr.ErrBadConfig).
+			Msg("imagetrust: the Fulcio roots are required by the cosign keyless verification")
+
+		return zerr.ErrBadConfig
+	}
+
+	if keyless.RekorPublicKey == "" && keyless.RekorURL == "" {
+		log.Error().Err(zerr.ErrBadConfig).
+			Msg("imagetrust: the Rekor public key or URL is required by the cosign keyless verification")
+
+		return zerr.ErrBadConfig
+	}
+
+	if keyless.RekorURL != "" {
+		if _, err := url.ParseRequestURI(keyless.RekorURL); err != nil {
+			log.Error().Err(err).Str("url", keyless.RekorURL).Msg("imagetrust: invalid Rekor URL")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	for _, path := range []string{keyless.FulcioRoots, keyless.RekorPublicKey, keyless.CTLogPublicKey} {
+		if path == "" {
+			continue
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			log.Error().Err(err).Str("path", path).Msg("imagetrust: couldn't find the cosign keyless verification material")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	if len(keyless.Identities) == 0 {
+		log.Error().Err(zerr.ErrBadConfig).
+			Msg("imagetrust: at least one identity is required by the cosign keyless verification")
+
+		return zerr.ErrBadConfig
+	}
+
+	for _, identity := range keyless.Identities {
+		if (identity.Issuer == "" && identity.IssuerRegExp == "") ||
+			(identity.Subject == "" && identity.SubjectRegExp == "") {
+			log.Error().Err(zerr.ErrBadConfig).
+				Msg("imagetrust: the identities of the cosign keyless verification require an issuer and a subject")
+
+			return zerr.ErrBadConfig
+		}
+
+		for _, expression := range []string{identity.IssuerRegExp, identity.SubjectRegExp} {
+			if _, err := regexp.Compile(expression); err != nil {
+				log.Error().Err(err).Str("regexp", expression).
+					Msg("imagetrust: identity regular expression could not be compiled")
+
+				return zerr.ErrBadConfig
+			}
+		}
+	}
+
+	return nil
+}
+
 func validateLDAP(config *config.Config, log zlog.Logger) error {
 	// LDAP mandatory configuration
 	if config.HTTP.Auth != nil && config.HTTP.Auth.LDAP != nil {
@@ -850,6 +1568,24 @@ func validateHTTP(config *config.Config, log zlog.Logger) error {
 		}
 	}
 
+	if config.IsDebugEnabled() {
+		// the profiles and the goroutine dumps expose the internals of zot, they're only served to the admins
+		if !config.IsBasicAuthnEnabled() || config.HTTP.AccessControl == nil ||
+			(len(config.HTTP.AccessControl.AdminPolicy.Users) == 0 &&
+				len(config.HTTP.AccessControl.AdminPolicy.Groups) == 0) {
+			log.Error().Err(zerr.ErrBadConfig).
+				Msg("the debug endpoints require authentication and an admin policy with users or groups")
+
+			return zerr.ErrBadConfig
+		}
+
+		if config.HTTP.Debug.MutexProfileFraction < 0 || config.HTTP.Debug.BlockProfileRate < 0 {
+			log.Error().Err(zerr.ErrBadConfig).Msg("the debug mutex profile fraction and block profile rate can't be negative")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
 	return nil
 }
 
@@ -869,6 +1605,13 @@ func validateGC(config *config.Config, log zlog.Logger) error {
 		return zerr.ErrBadConfig
 	}
 
+	if config.Storage.UploadSessionTTL < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("ttl", config.Storage.UploadSessionTTL).
+			Msg("invalid upload session ttl specified")
+
+		return zerr.ErrBadConfig
+	}
+
 	if !config.Storage.GC {
 		if config.Storage.GCDelay != 0 {
 			log.Warn().Err(zerr.ErrBadConfig).
@@ -891,6 +1634,276 @@ func validateGC(config *config.Config, log zlog.Logger) error {
 
 			return zerr.ErrBadConfig
 		}
+
+		if subPath.UploadSessionTTL < 0 {
+			log.Error().Err(zerr.ErrBadConfig).
+				Str("subPath", name).
+				Interface("uploadSessionTTL", subPath.UploadSessionTTL).
+				Msg("invalid upload session ttl configuration - cannot be negative")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	return nil
+}
+
+func validateUsageAlerts(config *config.Config, log zlog.Logger) error {
+	usageAlerts := config.Storage.UsageAlerts
+	if usageAlerts == nil {
+		return nil
+	}
+
+	if usageAlerts.Interval < 0 || usageAlerts.MinFreeSpace < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("interval", usageAlerts.Interval).
+			Int64("minFreeSpace", usageAlerts.MinFreeSpace).
+			Msg("usage alerts interval and minimum free space can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	for _, threshold := range usageAlerts.Thresholds {
+		if threshold <= 0 || threshold > 100 {
+			log.Error().Err(zerr.ErrBadConfig).Int("threshold", threshold).
+				Msg("usage alerts thresholds must be percentages between 1 and 100")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	return validateRepoQuotas(usageAlerts.Quotas, log)
+}
+
+func validateQuotas(config *config.Config, log zlog.Logger) error {
+	quotas := config.Storage.Quotas
+	if quotas == nil {
+		return nil
+	}
+
+	if quotas.MaxSize < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Int64("maxSize", quotas.MaxSize).
+			Msg("storage quota max size can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	return validateRepoQuotas(quotas.Repos, log)
+}
+
+func validateTagImmutability(config *config.Config, log zlog.Logger) error {
+	if config.Storage.TagImmutability == nil {
+		return nil
+	}
+
+	for _, policy := range config.Storage.TagImmutability.Policies {
+		if len(policy.Repos) == 0 {
+			log.Error().Err(zerr.ErrBadConfig).Msg("tag immutability policy must match repos")
+
+			return zerr.ErrBadConfig
+		}
+
+		for _, pattern := range append(append([]string{}, policy.Repos...), policy.MutableTags...) {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).
+					Msg("tag immutability pattern could not be compiled")
+
+				return glob.ErrBadPattern
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRepoQuotas(quotas []config.RepoQuota, log zlog.Logger) error {
+	for _, quota := range quotas {
+		if quota.MaxSize <= 0 {
+			log.Error().Err(zerr.ErrBadConfig).Strs("repos", quota.Repos).Int64("maxSize", quota.MaxSize).
+				Msg("repo quota max size must be positive")
+
+			return zerr.ErrBadConfig
+		}
+
+		for _, pattern := range quota.Repos {
+			if ok := glob.ValidatePattern(pattern); !ok {
+				log.Error().Err(glob.ErrBadPattern).Str("pattern", pattern).Msg("repo quota pattern could not be compiled")
+
+				return glob.ErrBadPattern
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRetention(config *config.Config, log zlog.Logger) error {
+	if config.Storage.Retention == nil {
+		return nil
+	}
+
+	if config.Storage.Retention.Interval < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("interval", config.Storage.Retention.Interval).
+			Msg("retention interval can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	if err := retention.Validate(config.Storage.Retention.Policies); err != nil {
+		log.Error().Err(err).Msg("invalid retention policies")
+
+		return zerr.ErrBadConfig
+	}
+
+	return nil
+}
+
+func validateMetaDB(config *config.Config, log zlog.Logger) error {
+	if config.Storage.MetaDBSlowThreshold < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("metaDBSlowThreshold", config.Storage.MetaDBSlowThreshold).
+			Msg("metaDB slow threshold can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	metaDBConfig := config.Storage.MetaDB
+	if metaDBConfig == nil {
+		return nil
+	}
+
+	if metaDBConfig.Driver != "postgres" && metaDBConfig.Driver != "redis" {
+		log.Error().Err(zerr.ErrBadConfig).Str("driver", metaDBConfig.Driver).
+			Msg("unsupported metaDB driver, only postgres and redis are supported")
+
+		return zerr.ErrBadConfig
+	}
+
+	if metaDBConfig.URL == "" {
+		log.Error().Err(zerr.ErrBadConfig).Msg("metaDB url is required")
+
+		return zerr.ErrBadConfig
+	}
+
+	if metaDBConfig.MaxOpenConns < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Int("maxOpenConns", metaDBConfig.MaxOpenConns).
+			Msg("metaDB maxOpenConns can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	return nil
+}
+
+func validateReconcile(config *config.Config, log zlog.Logger) error {
+	if config.Storage.Reconcile != nil && config.Storage.Reconcile.Interval < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("interval", config.Storage.Reconcile.Interval).
+			Msg("reconcile interval can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	return nil
+}
+
+// validateStorageDriver checks the storage driver is supported and the plugin serving it is configured.
+func validateStorageDriver(storageDriver map[string]interface{}) error {
+	storeName := fmt.Sprintf("%v", storageDriver["name"])
+
+	if !storage.IsSupportedStorageDriver(storeName) {
+		return zerr.ErrBadConfig
+	}
+
+	if storeName == storageConstants.PluginStorageDriverName {
+		if _, err := storage.GetStoragePluginConfig(storageDriver); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateCluster checks the members share the state they coordinate on: the leases, the storage, the cache of
+// the deduped blobs and the metadb.
+func validateCluster(config *config.Config, log zlog.Logger) error {
+	clusterConfig := config.Cluster
+	if clusterConfig == nil {
+		return nil
+	}
+
+	switch clusterConfig.Leases.Driver {
+	case "redis":
+		if clusterConfig.Leases.URL == "" {
+			log.Error().Err(zerr.ErrBadConfig).Msg("cluster leases url is required")
+
+			return zerr.ErrBadConfig
+		}
+	case "memory":
+	default:
+		log.Error().Err(zerr.ErrBadConfig).Str("driver", clusterConfig.Leases.Driver).
+			Msg("unsupported cluster leases driver, only redis and memory are supported")
+
+		return zerr.ErrBadConfig
+	}
+
+	if address, err := url.Parse(clusterConfig.Address); err != nil ||
+		(address.Scheme != "http" && address.Scheme != "https") || address.Host == "" {
+		log.Error().Err(zerr.ErrBadConfig).Str("address", clusterConfig.Address).
+			Msg("cluster address must be the http or https url the other members reach this one at")
+
+		return zerr.ErrBadConfig
+	}
+
+	if clusterConfig.LeaseDuration < 0 {
+		log.Error().Err(zerr.ErrBadConfig).Dur("leaseDuration", clusterConfig.LeaseDuration).
+			Msg("cluster lease duration can't be negative")
+
+		return zerr.ErrBadConfig
+	}
+
+	// the members write in the same storage, and a local cache of the deduped blobs isn't updated by the writes
+	// of the other members
+	if len(config.Storage.StorageDriver) == 0 {
+		log.Error().Err(zerr.ErrBadConfig).Msg("cluster mode requires a shared storage driver, e.g. s3")
+
+		return zerr.ErrBadConfig
+	}
+
+	if config.Storage.Dedupe && !config.Storage.RemoteCache {
+		log.Error().Err(zerr.ErrBadConfig).
+			Msg("cluster mode requires a remote cache when dedupe is enabled, set remoteCache or disable dedupe")
+
+		return zerr.ErrBadConfig
+	}
+
+	// the references are counted by each member, the blobs pushed to the other members would be garbage collected
+	if config.Storage.IncrementalGC {
+		log.Error().Err(zerr.ErrBadConfig).Msg("cluster mode doesn't support the incremental gc, disable incrementalGC")
+
+		return zerr.ErrBadConfig
+	}
+
+	for route, storageConfig := range config.Storage.SubPaths {
+		if len(storageConfig.StorageDriver) == 0 {
+			log.Error().Err(zerr.ErrBadConfig).Str("route", route).
+				Msg("cluster mode requires a shared storage driver, e.g. s3")
+
+			return zerr.ErrBadConfig
+		}
+
+		if storageConfig.Dedupe && !storageConfig.RemoteCache {
+			log.Error().Err(zerr.ErrBadConfig).Str("route", route).
+				Msg("cluster mode requires a remote cache when dedupe is enabled, set remoteCache or disable dedupe")
+
+			return zerr.ErrBadConfig
+		}
+	}
+
+	// the metadb is shared if it's a database server or the remote cache
+	if (config.IsSearchEnabled() || config.IsBasicAuthnEnabled() || config.IsImageTrustEnabled()) &&
+		config.Storage.MetaDB == nil && !config.Storage.RemoteCache {
+		log.Error().Err(zerr.ErrBadConfig).
+			Msg("cluster mode requires a shared metaDB, set storage.metaDB or use a remote cache")
+
+		return zerr.ErrBadConfig
 	}
 
 	return nil
@@ -908,6 +1921,91 @@ func validateSync(config *config.Config, log zlog.Logger) error {
 				return zerr.ErrBadConfig
 			}
 
+			if regCfg.RetryBackoff != nil && *regCfg.RetryBackoff < 1 {
+				log.Error().Err(zerr.ErrBadConfig).Int("id", id).Float64("retryBackoff", *regCfg.RetryBackoff).
+					Msg("retryBackoff must be at least 1")
+
+				return zerr.ErrBadConfig
+			}
+
+			if regCfg.Quarantine != nil && (regCfg.Quarantine.Failures <= 0 || regCfg.Quarantine.Duration <= 0) {
+				log.Error().Err(zerr.ErrBadConfig).Int("id", id).Interface("quarantine", regCfg.Quarantine).
+					Msg("sync quarantine requires a positive number of failures and duration")
+
+				return zerr.ErrBadConfig
+			}
+
+			if !isValidCollisionPolicy(regCfg.CollisionPolicy) {
+				log.Error().Err(zerr.ErrBadConfig).Int("id", id).Str("collisionPolicy", regCfg.CollisionPolicy).
+					Msg("sync: unknown collision policy, expected overwrite, skip or keepBoth")
+
+				return zerr.ErrBadConfig
+			}
+
+			if regCfg.CollisionSuffix != "" && !isValidCollisionSuffix(regCfg.CollisionSuffix) {
+				log.Error().Err(zerr.ErrBadConfig).Int("id", id).Str("collisionSuffix", regCfg.CollisionSuffix).
+					Msg("sync: collision suffix can only contain letters, digits, '.', '_' and '-'")
+
+				return zerr.ErrBadConfig
+			}
+
+			if sigCfg := regCfg.SignatureVerification; sigCfg != nil &&
+				(len(sigCfg.CosignPublicKeys) == 0 && len(sigCfg.NotationCertificates) == 0 ||
+					len(sigCfg.NotationIdentities) > 0 && len(sigCfg.NotationCertificates) == 0) {
+				log.Error().Err(zerr.ErrBadConfig).Int("id", id).Interface("signatureVerification", sigCfg).
+					Msg("sync signature verification requires cosign public keys or notation certificates")
+
+				return zerr.ErrBadConfig
+			}
+
+			for _, mapping := range regCfg.Mappings {
+				if !isValidSyncMapping(mapping) {
+					log.Error().Err(zerr.ErrBadConfig).Int("id", id).Interface("mapping", mapping).
+						Msg("sync mapping: source and destination are required and must have the same wildcards")
+
+					return zerr.ErrBadConfig
+				}
+			}
+
+			if exclCfg := regCfg.Exclusions; exclCfg != nil {
+				for _, pattern := range exclCfg.Repos {
+					if !glob.ValidatePattern(strings.Trim(pattern, "/")) {
+						log.Error().Err(glob.ErrBadPattern).Int("id", id).Str("repo", pattern).
+							Msg("sync exclusions: repo pattern could not be compiled")
+
+						return zerr.ErrBadConfig
+					}
+				}
+
+				for _, pattern := range exclCfg.Tags {
+					if _, err := regexp.Compile(pattern); err != nil {
+						log.Error().Err(err).Int("id", id).Str("tag", pattern).
+							Msg("sync exclusions: tag regex could not be compiled")
+
+						return zerr.ErrBadConfig
+					}
+				}
+
+				if exclCfg.MaxImageSize < 0 {
+					log.Error().Err(zerr.ErrBadConfig).Int("id", id).Int64("maxImageSize", exclCfg.MaxImageSize).
+						Msg("sync exclusions: max image size can not be negative")
+
+					return zerr.ErrBadConfig
+				}
+			}
+
+			if regCfg.References != nil {
+				for _, referenceType := range regCfg.References.Types {
+					if referenceType != syncConstants.Cosign && referenceType != syncConstants.OCI &&
+						referenceType != syncConstants.Oras {
+						log.Error().Err(zerr.ErrBadConfig).Int("id", id).Str("type", referenceType).
+							Msg("sync references: unknown reference type")
+
+						return zerr.ErrBadConfig
+					}
+				}
+			}
+
 			if regCfg.Content != nil {
 				for _, content := range regCfg.Content {
 					ok := glob.ValidatePattern(content.Prefix)
@@ -917,13 +2015,39 @@ func validateSync(config *config.Config, log zlog.Logger) error {
 						return glob.ErrBadPattern
 					}
 
-					if content.StripPrefix && !strings.Contains(content.Prefix, "/*") && content.Destination == "/" {
+					if content.StripPrefix && !strings.Contains(content.Prefix, "/*") &&
+						(content.Destination == "/" || common.Contains(content.Destinations, "/")) {
 						log.Error().Err(zerr.ErrBadConfig).
 							Interface("sync content", content).
 							Msg("sync config: can not use stripPrefix true and destination '/' without using glob patterns in prefix")
 
 						return zerr.ErrBadConfig
 					}
+
+					if !isValidCollisionPolicy(content.CollisionPolicy) {
+						log.Error().Err(zerr.ErrBadConfig).Str("prefix", content.Prefix).
+							Str("collisionPolicy", content.CollisionPolicy).
+							Msg("sync content: unknown collision policy, expected overwrite, skip or keepBoth")
+
+						return zerr.ErrBadConfig
+					}
+
+					if content.Tags != nil && content.Tags.SemverConstraint != nil {
+						if _, err := semver.NewConstraint(*content.Tags.SemverConstraint); err != nil {
+							log.Error().Err(err).Str("semverConstraint", *content.Tags.SemverConstraint).
+								Msg("sync semver constraint could not be parsed")
+
+							return zerr.ErrBadConfig
+						}
+					}
+
+					if content.Priority != "" && content.Priority != syncConstants.LowPriority &&
+						content.Priority != syncConstants.MediumPriority && content.Priority != syncConstants.HighPriority {
+						log.Error().Err(zerr.ErrBadConfig).Str("prefix", content.Prefix).Str("priority", content.Priority).
+							Msg("sync content: unknown priority, expected low, medium or high")
+
+						return zerr.ErrBadConfig
+					}
 				}
 			}
 		}
@@ -931,3 +2055,24 @@ func validateSync(config *config.Config, log zlog.Logger) error {
 
 	return nil
 }
+
+// the suffix is appended to tags, so it can only contain tag characters.
+func isValidCollisionSuffix(suffix string) bool {
+	matched, _ := regexp.MatchString(`^[a-zA-Z0-9._-]+$`, suffix)
+
+	return matched
+}
+
+// the wildcards of a mapping source are replaced in order by the ones of its destination.
+func isValidSyncMapping(mapping syncconf.MappingConfig) bool {
+	wildcards := regexp.MustCompile(`\*\*|\*`)
+
+	return strings.Trim(mapping.Source, "/") != "" && strings.Trim(mapping.Destination, "/") != "" &&
+		strings.Join(wildcards.FindAllString(mapping.Source, -1), " ") ==
+			strings.Join(wildcards.FindAllString(mapping.Destination, -1), " ")
+}
+
+func isValidCollisionPolicy(policy string) bool {
+	return policy == "" || policy == syncConstants.CollisionOverwrite || policy == syncConstants.CollisionSkip ||
+		policy == syncConstants.CollisionKeepBoth
+}
@@ -0,0 +1,34 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+func NewSBOMCommand(searchService SearchService) *cobra.Command {
+	sbomCmd := &cobra.Command{
+		Use:   "sbom [command]",
+		Short: "List and download the SBOMs of an image",
+		Long:  `List and download the SBOMs attached to an image as referrers`,
+		RunE:  ShowSuggestionsIfUnknownCommand,
+	}
+
+	sbomCmd.SetUsageTemplate(sbomCmd.UsageTemplate() + usageFooter)
+
+	sbomCmd.PersistentFlags().String(cmdflags.URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	sbomCmd.PersistentFlags().String(cmdflags.ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	sbomCmd.PersistentFlags().StringP(cmdflags.UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	sbomCmd.PersistentFlags().Bool(cmdflags.DebugFlag, false, "Show debug output")
+
+	sbomCmd.AddCommand(NewSBOMListCommand(searchService))
+	sbomCmd.AddCommand(NewSBOMGetCommand(searchService))
+
+	return sbomCmd
+}
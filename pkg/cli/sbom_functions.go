@@ -0,0 +1,284 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"os"
+	"strings"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+	zcommon "zotregistry.io/zot/pkg/common"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+)
+
+// the media types used for the SBOM artifacts and their layers, mapped to their format.
+var sbomMediaTypes = map[string]string{ //nolint:gochecknoglobals
+	"application/spdx+json":          cmdflags.SBOMFormatSPDX,
+	"text/spdx":                      cmdflags.SBOMFormatSPDX,
+	"application/vnd.cyclonedx+json": cmdflags.SBOMFormatCycloneDX,
+	"application/vnd.cyclonedx+xml":  cmdflags.SBOMFormatCycloneDX,
+	"application/vnd.cyclonedx":      cmdflags.SBOMFormatCycloneDX,
+}
+
+const sbomJSONSuffix = "+json"
+
+type sbomPackage struct {
+	Name    string
+	Version string
+	License string
+}
+
+type spdxDocument struct {
+	Packages []struct {
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+	} `json:"packages"`
+}
+
+type cycloneDXDocument struct {
+	Components []struct {
+		Name     string `json:"name"`
+		Version  string `json:"version"`
+		Licenses []struct {
+			License struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"license"`
+			Expression string `json:"expression"`
+		} `json:"licenses"`
+	} `json:"components"`
+}
+
+func ListSBOMs(config searchConfig, subject string) error {
+	username, password := getUsernameAndPassword(config.user)
+
+	_, sboms, err := getSBOMReferrers(config, username, password, subject, cmdflags.SBOMFormatAny)
+	if err != nil {
+		return err
+	}
+
+	maxArtifactTypeLen := math.MinInt
+
+	for _, referrer := range sboms {
+		if maxArtifactTypeLen < len(referrer.ArtifactType) {
+			maxArtifactTypeLen = len(referrer.ArtifactType)
+		}
+	}
+
+	printReferrersTableHeader(config, config.resultWriter, maxArtifactTypeLen)
+
+	return printReferrersResult(config, sboms, maxArtifactTypeLen)
+}
+
+// GetSBOM downloads the first SBOM of the image in the given format, writing it to the output file or printing
+// the packages it lists.
+func GetSBOM(config searchConfig, subject, format, outputFile string, packages bool) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	repo, sboms, err := getSBOMReferrers(config, username, password, subject, format)
+	if err != nil {
+		return err
+	}
+
+	if len(sboms) == 0 {
+		return fmt.Errorf("%w: %s", zerr.ErrSBOMNotFound, subject)
+	}
+
+	manifestURL, err := combineServerAndEndpointURL(config.servURL,
+		fmt.Sprintf("/v2/%s/manifests/%s", repo, sboms[0].Digest))
+	if err != nil {
+		return err
+	}
+
+	manifest := ispec.Manifest{}
+
+	if _, err := makeGETRequest(ctx, manifestURL, username, password, config.verifyTLS, config.debug,
+		&manifest, config.resultWriter); err != nil {
+		return err
+	}
+
+	layer, err := getSBOMLayer(manifest, sboms[0].ArtifactType)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, subject)
+	}
+
+	blobURL, err := combineServerAndEndpointURL(config.servURL,
+		fmt.Sprintf("/v2/%s/blobs/%s", repo, layer.Digest))
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+
+	if _, err := makeGETRequest(ctx, blobURL, username, password, config.verifyTLS, config.debug,
+		&content, config.resultWriter); err != nil {
+		return err
+	}
+
+	if packages {
+		sbomPackages, err := parseSBOMPackages(layer.MediaType, content)
+		if err != nil {
+			return err
+		}
+
+		printSBOMPackages(config, sbomPackages)
+
+		return nil
+	}
+
+	if outputFile != "" {
+		return os.WriteFile(outputFile, content, storageConstants.DefaultFilePerms)
+	}
+
+	_, err = config.resultWriter.Write(content)
+
+	return err
+}
+
+// getSBOMReferrers returns the repo of the image, with its referrers which are SBOMs in the given format.
+func getSBOMReferrers(config searchConfig, username, password, subject, format string,
+) (string, referrersResult, error) {
+	repo, ref, refIsTag, err := zcommon.GetRepoReference(subject)
+	if err != nil {
+		return "", nil, err
+	}
+
+	digest := ref
+
+	if refIsTag {
+		digest, err = fetchImageDigest(repo, ref, username, password, config)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	referrers, err := config.searchService.getReferrers(context.Background(), config, username, password,
+		repo, digest)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sboms := referrersResult{}
+
+	for _, referrer := range referrers {
+		sbomFormat := getSBOMFormat(referrer.ArtifactType)
+		if sbomFormat == "" || (format != cmdflags.SBOMFormatAny && format != sbomFormat) {
+			continue
+		}
+
+		sboms = append(sboms, referrer)
+	}
+
+	return repo, sboms, nil
+}
+
+// getSBOMLayer returns the layer of the SBOM artifact holding the document. Artifacts with a single layer of
+// a generic media type are accepted, as long as the artifact type is the one of a SBOM.
+func getSBOMLayer(manifest ispec.Manifest, artifactType string) (ispec.Descriptor, error) {
+	for _, layer := range manifest.Layers {
+		if getSBOMFormat(layer.MediaType) != "" {
+			return layer, nil
+		}
+	}
+
+	if len(manifest.Layers) == 1 {
+		layer := manifest.Layers[0]
+		layer.MediaType = artifactType
+
+		return layer, nil
+	}
+
+	return ispec.Descriptor{}, zerr.ErrSBOMNotFound
+}
+
+// getSBOMFormat returns the format of the SBOM with the given media type, or an empty string
+// if it's not a SBOM.
+func getSBOMFormat(mediaType string) string {
+	// the media type may have parameters, like the spec version
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+
+	return sbomMediaTypes[mediaType]
+}
+
+func parseSBOMPackages(mediaType string, content []byte) ([]sbomPackage, error) {
+	parsed, _, _ := mime.ParseMediaType(mediaType)
+	if !strings.HasSuffix(parsed, sbomJSONSuffix) {
+		return nil, fmt.Errorf("%w: only the JSON documents are supported, got '%s'",
+			zerr.ErrSBOMPackagesNotSupported, mediaType)
+	}
+
+	packages := []sbomPackage{}
+
+	switch getSBOMFormat(mediaType) {
+	case cmdflags.SBOMFormatSPDX:
+		document := spdxDocument{}
+
+		if err := json.Unmarshal(content, &document); err != nil {
+			return nil, err
+		}
+
+		for _, pkg := range document.Packages {
+			license := pkg.LicenseConcluded
+			if license == "" || license == "NOASSERTION" {
+				license = pkg.LicenseDeclared
+			}
+
+			packages = append(packages, sbomPackage{Name: pkg.Name, Version: pkg.VersionInfo, License: license})
+		}
+	case cmdflags.SBOMFormatCycloneDX:
+		document := cycloneDXDocument{}
+
+		if err := json.Unmarshal(content, &document); err != nil {
+			return nil, err
+		}
+
+		for _, component := range document.Components {
+			licenses := []string{}
+
+			for _, license := range component.Licenses {
+				switch {
+				case license.Expression != "":
+					licenses = append(licenses, license.Expression)
+				case license.License.ID != "":
+					licenses = append(licenses, license.License.ID)
+				case license.License.Name != "":
+					licenses = append(licenses, license.License.Name)
+				}
+			}
+
+			packages = append(packages, sbomPackage{
+				Name:    component.Name,
+				Version: component.Version,
+				License: strings.Join(licenses, ", "),
+			})
+		}
+	}
+
+	return packages, nil
+}
+
+func printSBOMPackages(config searchConfig, packages []sbomPackage) {
+	table := getImageTableWriter(config.resultWriter)
+
+	table.SetHeader([]string{"NAME", "VERSION", "LICENSE"})
+
+	for _, pkg := range packages {
+		table.Append([]string{pkg.Name, pkg.Version, pkg.License})
+	}
+
+	table.Render()
+}
@@ -0,0 +1,70 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+func NewSBOMListCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list [repo:tag]|[repo@digest]",
+		Short:             "List the SBOMs of an image",
+		Long:              `List the SBOMs of an image, in the SPDX or CycloneDX formats, attached to it as referrers`,
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return ListSBOMs(searchConfig, args[0])
+		},
+	}
+
+	cmd.Flags().StringP(cmdflags.OutputFormatFlag, "f", "", "Specify output format [text/json/yaml]")
+
+	return cmd
+}
+
+func NewSBOMGetCommand(searchService SearchService) *cobra.Command {
+	sbomFormatFlag := cmdflags.SBOMFormatOptionFlag(cmdflags.SBOMFormatAny)
+
+	cmd := &cobra.Command{
+		Use:   "get [repo:tag]|[repo@digest]",
+		Short: "Download the SBOM of an image",
+		Long: fmt.Sprintf(`Download the SBOM of an image, the first SBOM referrer in the requested format is used.
+With '--%s' the packages listed in the SBOM are printed as a table instead`, cmdflags.PackagesFlag),
+		Example: `  # save the SPDX SBOM of an image
+  zli sbom get alpine:3.16 --sbom-format spdx --output alpine.spdx.json --config local
+
+  # print the packages of an image
+  zli sbom get alpine:3.16 --packages --config local`,
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			outputFile := defaultIfError(cmd.Flags().GetString(cmdflags.OutputFileFlag))
+			packages := defaultIfError(cmd.Flags().GetBool(cmdflags.PackagesFlag))
+
+			return GetSBOM(searchConfig, args[0], string(sbomFormatFlag), outputFile, packages)
+		},
+	}
+
+	cmd.Flags().Var(&sbomFormatFlag, cmdflags.SBOMFormatFlag,
+		fmt.Sprintf("Only use the SBOMs in this format: [%s]", cmdflags.SBOMFormatOptionsStr()))
+	cmd.Flags().StringP(cmdflags.OutputFileFlag, "o", "", "Write the SBOM to this file instead of the standard output")
+	cmd.Flags().Bool(cmdflags.PackagesFlag, false, "Print the packages listed in the SBOM as a table")
+
+	return cmd
+}
@@ -0,0 +1,129 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestSBOMCommand(t *testing.T) {
+	Convey("sbom", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		image := CreateRandomImage()
+		err := UploadImage(image, baseURL, "repo", "1.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(CreateRandomImage(), baseURL, "repo", "2.0")
+		So(err, ShouldBeNil)
+
+		spdx := []byte(`{"spdxVersion":"SPDX-2.3","packages":[` +
+			`{"name":"musl","versionInfo":"1.2.4-r2","licenseConcluded":"MIT"},` +
+			`{"name":"busybox","versionInfo":"1.36.1-r5","licenseConcluded":"NOASSERTION",` +
+			`"licenseDeclared":"GPL-2.0-only"}]}`)
+		cyclonedx := []byte(`<bom xmlns="http://cyclonedx.org/schema/bom/1.5"></bom>`)
+
+		for _, sbom := range []struct {
+			mediaType string
+			content   []byte
+		}{
+			{"application/spdx+json", spdx},
+			{"application/vnd.cyclonedx+xml", cyclonedx},
+		} {
+			artifact := CreateImageWith().Layers([]Layer{{
+				Blob:      sbom.content,
+				MediaType: sbom.mediaType,
+				Digest:    godigest.FromBytes(sbom.content),
+			}}).EmptyConfig().ArtifactType(sbom.mediaType).Subject(image.DescriptorRef()).Build()
+
+			err = UploadImage(artifact, baseURL, "repo", artifact.DigestStr())
+			So(err, ShouldBeNil)
+		}
+
+		// the other referrers aren't SBOMs
+		signature := CreateRandomImageWith().ArtifactType(TestFakeSignatureArtType).
+			Subject(image.DescriptorRef()).Build()
+		err = UploadImage(signature, baseURL, "repo", signature.DigestStr())
+		So(err, ShouldBeNil)
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"sbomtest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		runSBOMCommand := func(args ...string) (string, error) {
+			cmd := NewSBOMCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(args, "--config", "sbomtest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		out, err := runSBOMCommand("list", "repo:1.0")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "application/spdx+json")
+		So(out, ShouldContainSubstring, "application/vnd.cyclonedx+xml")
+		So(out, ShouldNotContainSubstring, TestFakeSignatureArtType)
+
+		out, err = runSBOMCommand("list", "repo:2.0")
+		So(err, ShouldBeNil)
+		So(out, ShouldNotContainSubstring, "application/spdx+json")
+
+		out, err = runSBOMCommand("get", "repo@"+image.DigestStr(), "--sbom-format", "spdx")
+		So(err, ShouldBeNil)
+		So(out, ShouldEqual, string(spdx))
+
+		outputFile := path.Join(t.TempDir(), "sbom.xml")
+		_, err = runSBOMCommand("get", "repo:1.0", "--sbom-format", "cyclonedx", "--output", outputFile)
+		So(err, ShouldBeNil)
+
+		content, err := os.ReadFile(outputFile)
+		So(err, ShouldBeNil)
+		So(content, ShouldResemble, cyclonedx)
+
+		out, err = runSBOMCommand("get", "repo:1.0", "--sbom-format", "spdx", "--packages")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "NAME")
+		So(out, ShouldContainSubstring, "musl     1.2.4-r2   MIT")
+		So(out, ShouldContainSubstring, "busybox  1.36.1-r5  GPL-2.0-only")
+
+		_, err = runSBOMCommand("get", "repo:1.0", "--sbom-format", "cyclonedx", "--packages")
+		So(errors.Is(err, zerr.ErrSBOMPackagesNotSupported), ShouldBeTrue)
+
+		_, err = runSBOMCommand("get", "repo:2.0")
+		So(errors.Is(err, zerr.ErrSBOMNotFound), ShouldBeTrue)
+
+		_, err = runSBOMCommand("get", "repo:1.0", "--sbom-format", "swid")
+		So(err, ShouldNotBeNil)
+
+		_, err = runSBOMCommand("list", "repo")
+		So(err, ShouldNotBeNil)
+	})
+}
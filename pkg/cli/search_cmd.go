@@ -32,5 +32,7 @@ func NewSearchCommand(searchService SearchService) *cobra.Command {
 	searchCmd.AddCommand(NewSearchQueryCommand(searchService))
 	searchCmd.AddCommand(NewSearchSubjectCommand(searchService))
 
+	addOutputTemplateFlags(searchCmd)
+
 	return searchCmd
 }
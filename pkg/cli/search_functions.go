@@ -6,12 +6,18 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v2"
+
 	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
 	zcommon "zotregistry.io/zot/pkg/common"
 )
 
@@ -213,6 +219,41 @@ func SearchCVEForImageGQL(config searchConfig, image, searchedCveID string) erro
 
 	defer cancel()
 
+	cveList, err := getCVEListWithRetry(ctx, config, username, password, image, searchedCveID)
+	if err != nil {
+		return err
+	}
+
+	cveList.Data.CVEListForImage.CVEList = filterCVEsBySeverity(cveList.Data.CVEListForImage.CVEList,
+		config.severity)
+
+	writer := config.resultWriter
+
+	if config.outputFile != "" {
+		file, err := os.Create(config.outputFile)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		writer = file
+	}
+
+	if err := printCVEList(config, writer, image, cveList); err != nil {
+		return err
+	}
+
+	return checkCVESeverityThreshold(cveList.Data.CVEListForImage.CVEList, config.failOn)
+}
+
+// getCVEListWithRetry lists the CVEs of the image, retrying while the CVE DB of the server isn't ready.
+func getCVEListWithRetry(ctx context.Context, config searchConfig, username, password, image, searchedCveID string,
+) (*cveResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
 	var cveList *cveResult
 
 	err := zcommon.RetryWithContext(ctx, func(attempt int, retryIn time.Duration) error {
@@ -232,12 +273,167 @@ func SearchCVEForImageGQL(config searchConfig, image, searchedCveID string) erro
 
 		return err
 	}, maxRetries, CveDBRetryInterval*time.Second)
+
+	return cveList, err
+}
+
+// imageCVEs is the CVEs of one of the images listed by SearchCVEsForAllImagesGQL.
+type imageCVEs struct {
+	Image string `json:"image" yaml:"image"`
+	CVEs  []cve  `json:"cves"  yaml:"cves"`
+}
+
+/*
+SearchCVEsForAllImagesGQL lists the CVEs of all the images of the registry. The images are scanned in parallel,
+with the concurrency of the config, and a progress bar is shown meanwhile.
+*/
+func SearchCVEsForAllImagesGQL(config searchConfig, searchedCveID string) error {
+	username, password := getUsernameAndPassword(config.user)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	// the reports are about a single image
+	if isCVEReportFormat(config.outputFormat) {
+		return fmt.Errorf("%w: %s is only supported for the CVEs of an image", zerr.ErrInvalidOutputFormat,
+			config.outputFormat)
+	}
+
+	// the sort criteria of the config is the one of the CVEs
+	imagesConfig := config
+	imagesConfig.sortBy = cmdflags.SortByAlphabeticAsc
+
+	imageList, err := config.searchService.getImagesGQL(ctx, imagesConfig, username, password, "")
+	if err != nil {
+		return err
+	}
+
+	results := make([]imageCVEs, len(imageList.Results))
+
+	for i, image := range imageList.Results {
+		results[i].Image = fmt.Sprintf("%s:%s", image.RepoName, image.Tag)
+	}
+
+	if len(results) == 0 {
+		fmt.Fprint(config.resultWriter, "No images found\n")
+
+		return nil
+	}
+
+	// the first scan waits for the CVE DB, the other ones don't need to
+	cveList, err := getCVEListWithRetry(ctx, config, username, password, results[0].Image, searchedCveID)
 	if err != nil {
 		return err
 	}
 
+	results[0].CVEs = filterCVEsBySeverity(cveList.Data.CVEListForImage.CVEList, config.severity)
+
+	progress := newProgressBar(config.spinner, len(results), "images")
+	progress.increment()
+
+	err = runConcurrently(ctx, len(results)-1, config.concurrency, func(ctx context.Context, index int) error {
+		result := &results[index+1]
+
+		cveList, err := config.searchService.getCveByImageGQL(ctx, config, username, password, result.Image,
+			searchedCveID)
+		if err != nil {
+			return err
+		}
+
+		result.CVEs = filterCVEsBySeverity(cveList.Data.CVEListForImage.CVEList, config.severity)
+
+		progress.increment()
+
+		return nil
+	})
+
+	progress.finish()
+
+	if err != nil {
+		return err
+	}
+
+	writer := config.resultWriter
+
+	if config.outputFile != "" {
+		file, err := os.Create(config.outputFile)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		writer = file
+	}
+
+	if err := printImagesCVEs(config, writer, results); err != nil {
+		return err
+	}
+
+	allCVEs := []cve{}
+
+	for _, result := range results {
+		allCVEs = append(allCVEs, result.CVEs...)
+	}
+
+	return checkCVESeverityThreshold(allCVEs, config.failOn)
+}
+
+func printImagesCVEs(config searchConfig, writer io.Writer, results []imageCVEs) error {
+	for i, result := range results {
+		switch strings.ToLower(config.outputFormat) {
+		case "", defaultOutputFormat:
+			if i > 0 {
+				fmt.Fprintln(writer)
+			}
+
+			fmt.Fprintln(writer, result.Image)
+
+			cveList := &cveResult{Data: cveData{CVEListForImage: cveListForImage{CVEList: result.CVEs}}}
+
+			if err := printCVEList(config, writer, result.Image, cveList); err != nil {
+				return err
+			}
+		case jsonFormat:
+			// one image per line, like the other json outputs
+			json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+			body, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(writer, string(body))
+		case ymlFormat, yamlFormat:
+			body, err := yaml.Marshal(result)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(writer, "---\n"+string(body))
+		default:
+			return zerr.ErrInvalidOutputFormat
+		}
+	}
+
+	return nil
+}
+
+func printCVEList(config searchConfig, writer io.Writer, image string, cveList *cveResult) error {
+	// the reports are written even if there are no CVEs, so they can be consumed by other tools
+	if isCVEReportFormat(config.outputFormat) {
+		out, err := cveReport(config.outputFormat, image, cveList.Data.CVEListForImage.CVEList)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(writer, out)
+
+		return nil
+	}
+
 	if len(cveList.Data.CVEListForImage.CVEList) == 0 {
-		fmt.Fprint(config.resultWriter, "No CVEs found for image\n")
+		fmt.Fprint(writer, "No CVEs found for image\n")
 
 		return nil
 	}
@@ -246,7 +442,7 @@ func SearchCVEForImageGQL(config searchConfig, image, searchedCveID string) erro
 
 	if config.outputFormat == defaultOutputFormat || config.outputFormat == "" {
 		printCVETableHeader(&builder)
-		fmt.Fprint(config.resultWriter, builder.String())
+		fmt.Fprint(writer, builder.String())
 	}
 
 	out, err := cveList.string(config.outputFormat)
@@ -254,7 +450,7 @@ func SearchCVEForImageGQL(config searchConfig, image, searchedCveID string) erro
 		return err
 	}
 
-	fmt.Fprint(config.resultWriter, out)
+	fmt.Fprint(writer, out)
 
 	return nil
 }
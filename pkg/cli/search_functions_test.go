@@ -338,6 +338,7 @@ func TestSearchCVEForImageGQL(t *testing.T) {
 									Description: "Description of the CVE",
 									Title:       "Title of that CVE",
 									Severity:    "HIGH",
+									FixStatus:   "AVAILABLE",
 									PackageList: []packageList{
 										{
 											Name:             "packagename",
@@ -358,7 +359,7 @@ func TestSearchCVEForImageGQL(t *testing.T) {
 		space := regexp.MustCompile(`\s+`)
 		str := space.ReplaceAllString(buff.String(), " ")
 		actual := strings.TrimSpace(str)
-		So(actual, ShouldContainSubstring, "dummyCVEID HIGH Title of that CVE")
+		So(actual, ShouldContainSubstring, "dummyCVEID HIGH AVAILABLE Title of that CVE")
 	})
 
 	Convey("SearchCVEForImageGQL", t, func() {
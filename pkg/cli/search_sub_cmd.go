@@ -24,7 +24,8 @@ func NewSearchSubjectCommand(searchService SearchService) *cobra.Command {
 		Example: `# For referrers search specify the referred subject using it's full digest or tag:
   zli search subject "repo@sha256:f9a0981..."
   zli search subject "repo:tag"`,
-		Args: OneImageWithRefArg,
+		Args:              OneImageWithRefArg,
+		ValidArgsFunction: completeImages(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
@@ -57,7 +58,8 @@ func NewSearchQueryCommand(searchService SearchService) *cobra.Command {
 	  
 # For image search specify the full repo name followed by the tag or a prefix of the tag.
   zli search query "test/repo:2.1."`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeImages(searchService),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
 			if err != nil {
@@ -0,0 +1,213 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/rs/zerolog"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	zlog "zotregistry.io/zot/pkg/log"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+)
+
+// the fields of the log entries which don't help fixing the config.
+var serverConfigIgnoredFields = map[string]bool{ //nolint:gochecknoglobals
+	zerolog.LevelFieldName:     true,
+	zerolog.MessageFieldName:   true,
+	zerolog.TimestampFieldName: true,
+	zerolog.CallerFieldName:    true,
+	"goroutine":                true,
+}
+
+type serverConfigProblem struct {
+	level   string
+	message string
+	details []string
+}
+
+func (problem serverConfigProblem) String() string {
+	if len(problem.details) == 0 {
+		return fmt.Sprintf("%s: %s", problem.level, problem.message)
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", problem.level, problem.message, strings.Join(problem.details, ", "))
+}
+
+/*
+ValidateServerConfig checks a zot server config file with the same validation the server does at startup,
+plus the checks of the settings which are accepted at startup but don't work together, like dedupe without
+a cache. The problems are printed to the writer, the config is invalid if any of them is an error.
+*/
+func ValidateServerConfig(configPath string, writer io.Writer) error {
+	logBuffer := &bytes.Buffer{}
+	logger := zlog.Logger{Logger: zerolog.New(logBuffer).Level(zerolog.WarnLevel)}
+
+	conf := config.New()
+
+	viperInstance, metaData, err := readConfiguration(conf, configPath, logger)
+	if err == nil {
+		// the server validation stops at its first error, the other checks still run to report all of them
+		_ = checkConfiguration(conf, viperInstance, metaData, logger)
+
+		checkServerConfigConsistency(conf, logger)
+	}
+
+	problems, err := parseServerConfigProblems(logBuffer)
+	if err != nil {
+		return err
+	}
+
+	isValid := true
+
+	for _, problem := range problems {
+		if problem.level == zerolog.LevelErrorValue {
+			isValid = false
+		}
+
+		fmt.Fprintln(writer, problem.String())
+	}
+
+	if !isValid {
+		return fmt.Errorf("%w: %s", zerr.ErrBadConfig, configPath)
+	}
+
+	fmt.Fprintf(writer, "%s is a valid zot config\n", configPath)
+
+	return nil
+}
+
+// parseServerConfigProblems turns the log entries of the validation into problems, the fields of an entry are
+// the details of its problem.
+func parseServerConfigProblems(logs io.Reader) ([]serverConfigProblem, error) {
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	problems := []serverConfigProblem{}
+	scanner := bufio.NewScanner(logs)
+
+	for scanner.Scan() {
+		entry := map[string]interface{}{}
+
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+
+		problem := serverConfigProblem{}
+		problem.level, _ = entry[zerolog.LevelFieldName].(string)
+		problem.message, _ = entry[zerolog.MessageFieldName].(string)
+
+		for field, value := range entry {
+			if serverConfigIgnoredFields[field] {
+				continue
+			}
+
+			// most of the checks fail with the same generic error, the message tells what's wrong
+			if field == zerolog.ErrorFieldName && value == zerr.ErrBadConfig.Error() {
+				continue
+			}
+
+			problem.details = append(problem.details, fmt.Sprintf("%s: %s", field, formatLogValue(value)))
+		}
+
+		sort.Strings(problem.details)
+
+		problems = append(problems, problem)
+	}
+
+	return problems, scanner.Err()
+}
+
+func formatLogValue(value interface{}) string {
+	if values, ok := value.([]interface{}); ok {
+		formattedValues := make([]string, 0, len(values))
+
+		for _, value := range values {
+			formattedValues = append(formattedValues, formatLogValue(value))
+		}
+
+		return strings.Join(formattedValues, ", ")
+	}
+
+	return fmt.Sprint(value)
+}
+
+// checkServerConfigConsistency logs the settings which are accepted by the server at startup, but which fail
+// later, or silently don't work.
+func checkServerConfigConsistency(conf *config.Config, log zlog.Logger) {
+	if _, err := zerolog.ParseLevel(conf.Log.Level); err != nil {
+		log.Error().Str("logLevel", conf.Log.Level).
+			Msg("invalid log level, use one of trace, debug, info, warn, error, fatal or panic")
+	}
+
+	if conf.HTTP.TLS != nil && (conf.HTTP.TLS.Cert == "") != (conf.HTTP.TLS.Key == "") {
+		log.Error().Msg("tls requires both a cert and a key")
+	}
+
+	checkStorageConsistency(conf.Storage.StorageConfig, "", log)
+
+	routes := make([]string, 0, len(conf.Storage.SubPaths))
+
+	for route := range conf.Storage.SubPaths {
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	for _, route := range routes {
+		checkStorageConsistency(conf.Storage.SubPaths[route], route, log)
+	}
+}
+
+func checkStorageConsistency(storageConfig config.StorageConfig, route string, log zlog.Logger) {
+	logError := func() *zerolog.Event {
+		event := log.Error()
+
+		if route != "" {
+			event = event.Str("subpath", route)
+		}
+
+		return event
+	}
+
+	if storageConfig.RootDirectory == "" {
+		logError().Msg("storage requires a rootDirectory")
+	}
+
+	if !storageConfig.RemoteCache {
+		return
+	}
+
+	// the server only rejects this combination with a remote storage driver, no cache is created either way
+	if storageConfig.Dedupe && storageConfig.CacheDriver == nil {
+		logError().Msg("dedupe is enabled with remoteCache but no cacheDriver is configured, there's no cache " +
+			"to dedupe the blobs with: configure a cacheDriver or set remoteCache to false")
+	}
+
+	if storageConfig.CacheDriver != nil && storageConfig.CacheDriver["name"] == storageConstants.DynamoDBDriverName {
+		missingParams := []string{}
+
+		for _, param := range []string{"endpoint", "region", "cachetablename"} {
+			if value, _ := storageConfig.CacheDriver[param].(string); value == "" {
+				missingParams = append(missingParams, param)
+			}
+		}
+
+		if len(missingParams) > 0 {
+			logError().Strs("missing", missingParams).Msg("the dynamodb cacheDriver is missing parameters")
+		}
+	}
+
+	if storageConfig.CacheDriver != nil && storageConfig.CacheDriver["name"] == storageConstants.PostgresDriverName {
+		if url, _ := storageConfig.CacheDriver["url"].(string); url == "" {
+			logError().Strs("missing", []string{"url"}).Msg("the postgres cacheDriver is missing parameters")
+		}
+	}
+}
@@ -70,10 +70,15 @@ type searchConfig struct {
 	user          string
 	outputFormat  string
 	sortBy        string
+	fixStatus     string
+	severity      string
+	failOn        string
+	outputFile    string
 	verifyTLS     bool
 	fixedFlag     bool
 	verbose       bool
 	debug         bool
+	concurrency   int
 	resultWriter  io.Writer
 	spinner       spinnerState
 }
@@ -248,6 +253,7 @@ func (service searchService) getImagesGQL(ctx context.Context, config searchConf
 					LastUpdated
 				}
 				LastUpdated
+				LastPulled
 				Size
 				IsSigned
 			}
@@ -303,15 +309,21 @@ func (service searchService) getImagesForDigestGQL(ctx context.Context, config s
 func (service searchService) getCveByImageGQL(ctx context.Context, config searchConfig, username, password,
 	imageName, searchedCVE string,
 ) (*cveResult, error) {
+	fixStatusArg := ""
+
+	if fixStatus := cmdflags.Flag2FixStatus(config.fixStatus); fixStatus != "" {
+		fixStatusArg = ", fixStatus: " + fixStatus
+	}
+
 	query := fmt.Sprintf(`
 	{ 
-		CVEListForImage (image:"%s", searchedCVE:"%s", requestedPage: {sortBy: %s}) { 
+		CVEListForImage (image:"%s", searchedCVE:"%s", requestedPage: {sortBy: %s}%s) { 
 			Tag CVEList { 
-				Id Title Severity Description 
+				Id Title Severity Description FixStatus
 				PackageList {Name InstalledVersion FixedVersion}
 			}
 		}
-	}`, imageName, searchedCVE, cmdflags.Flag2SortCriteria(config.sortBy))
+	}`, imageName, searchedCVE, cmdflags.Flag2SortCriteria(config.sortBy), fixStatusArg)
 	result := &cveResult{}
 
 	err := service.makeGraphQLQuery(ctx, config, username, password, query, result)
@@ -439,6 +451,7 @@ func (service searchService) getReferrers(ctx context.Context, config searchConf
 
 	for _, referrer := range referrerResp.Manifests {
 		referrersList = append(referrersList, common.Referrer{
+			MediaType:    referrer.MediaType,
 			ArtifactType: referrer.ArtifactType,
 			Digest:       referrer.Digest.String(),
 			Size:         int(referrer.Size),
@@ -455,14 +468,14 @@ func (service searchService) getImageByName(ctx context.Context, config searchCo
 	defer close(rch)
 
 	var localWg sync.WaitGroup
-	rlim := newSmoothRateLimiter(&localWg, rch)
 
-	localWg.Add(1)
+	pool := newRequestsPool(&localWg, rch, config.concurrency)
+	defer pool.stop()
 
-	go rlim.startRateLimiter(ctx)
+	pool.startWorkers(ctx)
 	localWg.Add(1)
 
-	go getImage(ctx, config, username, password, imageName, rch, &localWg, rlim)
+	go getImage(ctx, config, username, password, imageName, rch, &localWg, pool)
 
 	localWg.Wait()
 }
@@ -499,16 +512,15 @@ func (service searchService) getAllImages(ctx context.Context, config searchConf
 
 	var localWg sync.WaitGroup
 
-	rlim := newSmoothRateLimiter(&localWg, rch)
+	pool := newRequestsPool(&localWg, rch, config.concurrency)
+	defer pool.stop()
 
-	localWg.Add(1)
-
-	go rlim.startRateLimiter(ctx)
+	pool.startWorkers(ctx)
 
 	for _, repo := range catalog.Repositories {
 		localWg.Add(1)
 
-		go getImage(ctx, config, username, password, repo, rch, &localWg, rlim)
+		go getImage(ctx, config, username, password, repo, rch, &localWg, pool)
 	}
 
 	localWg.Wait()
@@ -627,15 +639,15 @@ func (service searchService) getImagesByDigest(ctx context.Context, config searc
 
 	var localWg sync.WaitGroup
 
-	rlim := newSmoothRateLimiter(&localWg, rch)
-	localWg.Add(1)
+	pool := newRequestsPool(&localWg, rch, config.concurrency)
+	defer pool.stop()
 
-	go rlim.startRateLimiter(ctx)
+	pool.startWorkers(ctx)
 
 	for _, image := range result.Results {
 		localWg.Add(1)
 
-		go addManifestCallToPool(ctx, config, rlim, username, password, image.RepoName, image.Tag, rch, &localWg)
+		go addManifestCallToPool(ctx, config, pool, username, password, image.RepoName, image.Tag, rch, &localWg)
 	}
 
 	localWg.Wait()
@@ -748,6 +760,7 @@ type cve struct {
 	Severity    string        `json:"Severity"`
 	Title       string        `json:"Title"`
 	Description string        `json:"Description"`
+	FixStatus   string        `json:"FixStatus"`
 	PackageList []packageList `json:"PackageList"`
 }
 
@@ -784,9 +797,11 @@ func (cve cveResult) stringPlainText() (string, error) {
 		id := ellipsize(c.ID, cveIDWidth, ellipsis)
 		title := ellipsize(c.Title, cveTitleWidth, ellipsis)
 		severity := ellipsize(c.Severity, cveSeverityWidth, ellipsis)
-		row := make([]string, 3) //nolint:gomnd
+		fixStatus := ellipsize(c.FixStatus, cveFixStatusWidth, ellipsis)
+		row := make([]string, 4) //nolint:gomnd
 		row[colCVEIDIndex] = id
 		row[colCVESeverityIndex] = severity
+		row[colCVEFixStatusIndex] = fixStatus
 		row[colCVETitleIndex] = title
 
 		table.Append(row)
@@ -1264,6 +1279,7 @@ func getCVETableWriter(writer io.Writer) *tablewriter.Table {
 	table.SetNoWhiteSpace(true)
 	table.SetColMinWidth(colCVEIDIndex, cveIDWidth)
 	table.SetColMinWidth(colCVESeverityIndex, cveSeverityWidth)
+	table.SetColMinWidth(colCVEFixStatusIndex, cveFixStatusWidth)
 	table.SetColMinWidth(colCVETitleIndex, cveTitleWidth)
 
 	return table
@@ -1362,13 +1378,15 @@ const (
 	layersWidth      = 8
 	ellipsis         = "..."
 
-	cveIDWidth       = 16
-	cveSeverityWidth = 8
-	cveTitleWidth    = 48
+	cveIDWidth        = 16
+	cveSeverityWidth  = 8
+	cveFixStatusWidth = 11
+	cveTitleWidth     = 48
 
-	colCVEIDIndex       = 0
-	colCVESeverityIndex = 1
-	colCVETitleIndex    = 2
+	colCVEIDIndex        = 0
+	colCVESeverityIndex  = 1
+	colCVEFixStatusIndex = 2
+	colCVETitleIndex     = 3
 
 	defaultOutputFormat = "text"
 )
@@ -0,0 +1,328 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	notconfig "github.com/notaryproject/notation-go/config"
+	notreg "github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/signer"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"gopkg.in/yaml.v2"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+	zcommon "zotregistry.io/zot/pkg/common"
+)
+
+// the OAuth2 client of the public sigstore instance, used for the keyless signatures.
+const sigstoreOIDCClientID = "sigstore"
+
+// imageTrust is the trust status of an image, as computed by the imagetrust extension of the server.
+type imageTrust struct {
+	Image      string                     `json:"image"      yaml:"image"`
+	Digest     string                     `json:"digest"     yaml:"digest"`
+	IsTrusted  bool                       `json:"isTrusted"  yaml:"istrusted"`
+	Signatures []zcommon.SignatureSummary `json:"signatures" yaml:"signatures"`
+}
+
+// basicKeychain gives the credentials of zli to cosign, instead of the ones of the docker config.
+type basicKeychain struct {
+	username string
+	password string
+}
+
+func (keychain basicKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return authn.FromConfig(authn.AuthConfig{Username: keychain.username, Password: keychain.password}), nil
+}
+
+/*
+SignImage signs the image with cosign or notation and pushes the signature to the server. Cosign signs with
+a key file, or keyless with the public sigstore instance, notation signs with a key of its signing keys.
+*/
+func SignImage(config searchConfig, image, tool, key string, keyless bool) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	switch {
+	case tool == cmdflags.SignToolNotation && keyless:
+		return fmt.Errorf("%w: notation doesn't support keyless signatures", zerr.ErrInvalidFlagsCombination)
+	case tool == cmdflags.SignToolCosign && keyless == (key != ""):
+		return fmt.Errorf("%w: cosign signs with either '--%s' or '--%s'", zerr.ErrInvalidFlagsCombination,
+			cmdflags.KeyFlag, cmdflags.KeylessFlag)
+	}
+
+	repo, ref, refIsTag, err := zcommon.GetRepoReference(image)
+	if err != nil {
+		return err
+	}
+
+	digest := ref
+
+	// the digest is signed, the tag could be moved in the meantime
+	if refIsTag {
+		digest, err = fetchImageDigest(repo, ref, username, password, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	serverURL, err := url.Parse(config.servURL)
+	if err != nil {
+		return err
+	}
+
+	reference := fmt.Sprintf("%s/%s@%s", serverURL.Host, repo, digest)
+	plainHTTP := serverURL.Scheme == "http"
+
+	if tool == cmdflags.SignToolNotation {
+		err = signWithNotation(ctx, config, reference, key, username, password)
+	} else {
+		err = signWithCosign(config, reference, key, keyless, username, password, plainHTTP)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.resultWriter, "signed %s@%s with %s\n", repo, digest, tool)
+
+	return nil
+}
+
+func signWithCosign(config searchConfig, reference, key string, keyless bool, username, password string,
+	plainHTTP bool,
+) error {
+	registryOpts := options.RegistryOptions{AllowInsecure: !config.verifyTLS, AllowHTTPRegistry: plainHTTP}
+
+	if username != "" {
+		registryOpts.Keychain = basicKeychain{username: username, password: password}
+	}
+
+	keyOpts := options.KeyOpts{KeyRef: key, PassFunc: generate.GetPass}
+
+	if keyless {
+		keyOpts = options.KeyOpts{
+			FulcioURL:    options.DefaultFulcioURL,
+			RekorURL:     options.DefaultRekorURL,
+			OIDCIssuer:   options.DefaultOIDCIssuerURL,
+			OIDCClientID: sigstoreOIDCClientID,
+		}
+	}
+
+	// the keyless signatures are verified with the transparency log
+	return sign.SignCmd(&options.RootOptions{Timeout: options.DefaultTimeout}, keyOpts,
+		options.SignOptions{Registry: registryOpts, Upload: true, TlogUpload: keyless}, []string{reference})
+}
+
+func signWithNotation(ctx context.Context, config searchConfig, reference, key, username, password string) error {
+	signingKeys, err := notconfig.LoadSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	var keySuite notconfig.KeySuite
+
+	if key == "" {
+		keySuite, err = signingKeys.GetDefault()
+	} else {
+		keySuite, err = signingKeys.Get(key)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if keySuite.X509KeyPair == nil {
+		return fmt.Errorf("%w: only the notation keys with a local key and certificate can be used, not %s",
+			zerr.ErrUnsupportedSigningKey, keySuite.Name)
+	}
+
+	notationSigner, err := signer.NewFromFiles(keySuite.X509KeyPair.KeyPath, keySuite.X509KeyPair.CertificatePath)
+	if err != nil {
+		return err
+	}
+
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return err
+	}
+
+	remoteRepo, err := newRemoteRepository(config, ref.Repository, username, password)
+	if err != nil {
+		return err
+	}
+
+	_, err = notation.Sign(ctx, notationSigner, notreg.NewRepository(remoteRepo), notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: ref.String(),
+	})
+
+	return err
+}
+
+// newRemoteRepository returns an oras client of the repository of the server, sending the requests with the HTTP
+// client and the credentials of zli.
+func newRemoteRepository(config searchConfig, repo, username, password string) (*remote.Repository, error) {
+	serverURL, err := url.Parse(config.servURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := registry.Reference{Registry: serverURL.Host, Repository: repo}
+	if err := ref.ValidateRepository(); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := createHTTPClient(config.verifyTLS, ref.Registry, getHTTPClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	authClient := &auth.Client{
+		Client:     httpClient,
+		Credential: auth.StaticCredential(ref.Registry, auth.Credential{Username: username, Password: password}),
+		Cache:      auth.NewCache(),
+	}
+
+	return &remote.Repository{Client: authClient, Reference: ref, PlainHTTP: serverURL.Scheme == "http"}, nil
+}
+
+// VerifyImage shows the signatures of the image and whether the server trusts them, the image is only trusted
+// if at least one of its signatures is.
+func VerifyImage(config searchConfig, image string) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	_, _, refIsTag, err := zcommon.GetRepoReference(image)
+	if err != nil {
+		return err
+	}
+
+	// the trust status is computed by the search extension for the tagged images
+	if !refIsTag {
+		return fmt.Errorf("%w: %s, the image must be referenced by tag", zerr.ErrInvalidRepoRefFormat, image)
+	}
+
+	query := fmt.Sprintf(`
+	{
+		Image(image: "%s") {
+			RepoName Tag Digest
+			SignatureInfo { Tool IsTrusted Author }
+		}
+	}`, image)
+
+	result := &zcommon.ImageSummaryResult{}
+
+	endPoint, err := combineServerAndEndpointURL(config.servURL, constants.FullSearchPrefix)
+	if err != nil {
+		return err
+	}
+
+	err = makeGraphQLRequest(ctx, endPoint, query, username, password, config.verifyTLS, config.debug, result,
+		config.resultWriter)
+
+	if errResult := checkResultGraphQLQuery(ctx, err, result.Errors); errResult != nil {
+		return errResult
+	}
+
+	summary := result.SingleImageSummary.ImageSummary
+	trust := imageTrust{
+		Image:      image,
+		Digest:     summary.Digest,
+		Signatures: summary.SignatureInfo,
+	}
+
+	if trust.Signatures == nil {
+		trust.Signatures = []zcommon.SignatureSummary{}
+	}
+
+	for _, signature := range trust.Signatures {
+		if signature.IsTrusted {
+			trust.IsTrusted = true
+		}
+	}
+
+	out, err := trust.string(config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(config.resultWriter, out)
+
+	if !trust.IsTrusted {
+		return fmt.Errorf("%w: %s", zerr.ErrImageNotTrusted, image)
+	}
+
+	return nil
+}
+
+func (trust imageTrust) string(config searchConfig) (string, error) {
+	switch strings.ToLower(config.outputFormat) {
+	case "", defaultOutputFormat:
+		return trust.stringPlainText(), nil
+	case jsonFormat:
+		json := jsoniter.ConfigCompatibleWithStandardLibrary
+
+		body, err := json.Marshal(trust)
+		if err != nil {
+			return "", err
+		}
+
+		return string(body) + "\n", nil
+	case ymlFormat, yamlFormat:
+		body, err := yaml.Marshal(trust)
+		if err != nil {
+			return "", err
+		}
+
+		return "---\n" + string(body), nil
+	default:
+		return "", zerr.ErrInvalidOutputFormat
+	}
+}
+
+func (trust imageTrust) stringPlainText() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%s %s\n", trust.Image, trust.Digest)
+
+	if len(trust.Signatures) == 0 {
+		fmt.Fprintln(&builder, "the image isn't signed")
+
+		return builder.String()
+	}
+
+	table := getImageTableWriter(&builder)
+
+	table.SetHeader([]string{"TOOL", "TRUSTED", "AUTHOR"})
+
+	for _, signature := range trust.Signatures {
+		author := signature.Author
+		if author == "" {
+			author = "-"
+		}
+
+		table.Append([]string{signature.Tool, strconv.FormatBool(signature.IsTrusted), author})
+	}
+
+	table.Render()
+
+	return builder.String()
+}
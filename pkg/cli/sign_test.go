@@ -0,0 +1,196 @@
+//go:build search && imagetrust
+// +build search,imagetrust
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestImageSignAndVerifyCommands(t *testing.T) {
+	Convey("image sign and verify", t, func() {
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+			Trust: &extconf.ImageTrustConfig{
+				BaseConfig: extconf.BaseConfig{Enable: &defaultVal},
+				Cosign:     true,
+				Notation:   true,
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		image := CreateRandomImage()
+		err := UploadImage(image, baseURL, "repo", "1.0")
+		So(err, ShouldBeNil)
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"signtest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		runImageCommand := func(args ...string) (string, error) {
+			cmd := NewImageCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetArgs(append(args, "--config", "signtest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		// the trust status is updated in the background once the signatures are pushed
+		waitForTrust := func(tool string) imageTrust {
+			trust := imageTrust{}
+
+			for i := 0; i < 30; i++ {
+				out, _ := runImageCommand("verify", "repo:1.0", "-f", "json")
+
+				// the error of an untrusted image follows the json
+				trust = imageTrust{}
+				err := json.Unmarshal([]byte(strings.SplitN(out, "\n", 2)[0]), &trust)
+				So(err, ShouldBeNil)
+
+				for _, signature := range trust.Signatures {
+					if signature.Tool == tool && signature.IsTrusted {
+						return trust
+					}
+				}
+
+				time.Sleep(time.Second)
+			}
+
+			return trust
+		}
+
+		out, err := runImageCommand("verify", "repo:1.0")
+		So(errors.Is(err, zerr.ErrImageNotTrusted), ShouldBeTrue)
+		So(out, ShouldContainSubstring, "the image isn't signed")
+
+		_, err = runImageCommand("verify", "repo@"+image.DigestStr())
+		So(errors.Is(err, zerr.ErrInvalidRepoRefFormat), ShouldBeTrue)
+
+		_, err = runImageCommand("sign", "repo:1.0")
+		So(errors.Is(err, zerr.ErrInvalidFlagsCombination), ShouldBeTrue)
+
+		_, err = runImageCommand("sign", "repo:1.0", "--tool", "notation", "--keyless")
+		So(errors.Is(err, zerr.ErrInvalidFlagsCombination), ShouldBeTrue)
+
+		_, err = runImageCommand("sign", "repo:1.0", "--tool", "gpg")
+		So(err, ShouldNotBeNil)
+
+		Convey("with cosign", func() {
+			keyDir := t.TempDir()
+			cwd, err := os.Getwd()
+			So(err, ShouldBeNil)
+
+			// the key pair is written to the current directory
+			So(os.Chdir(keyDir), ShouldBeNil)
+			t.Setenv("COSIGN_PASSWORD", "")
+			err = generate.GenerateKeyPairCmd(context.TODO(), "", "cosign", nil)
+			So(os.Chdir(cwd), ShouldBeNil)
+			So(err, ShouldBeNil)
+
+			// the signatures are verified when they're pushed
+			publicKey, err := os.ReadFile(path.Join(keyDir, "cosign.pub"))
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetHeader("Content-type", "application/octet-stream").
+				SetBody(publicKey).Post(baseURL + constants.FullCosign)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			out, err := runImageCommand("sign", "repo:1.0", "--key", path.Join(keyDir, "cosign.key"))
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "signed repo@"+image.DigestStr()+" with cosign")
+
+			trust := waitForTrust("cosign")
+			So(trust.IsTrusted, ShouldBeTrue)
+			So(trust.Digest, ShouldEqual, image.DigestStr())
+
+			out, err = runImageCommand("verify", "repo:1.0", "-f", "yaml")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "istrusted: true")
+		})
+
+		Convey("with notation", func() {
+			keyDir := t.TempDir()
+
+			test.NotationPathLock.Lock()
+			defer test.NotationPathLock.Unlock()
+
+			test.LoadNotationPath(keyDir)
+
+			err := test.GenerateNotationCerts(keyDir, "zli-sign-test")
+			So(err, ShouldBeNil)
+
+			// there's no default key
+			_, err = runImageCommand("sign", "repo:1.0", "--tool", "notation")
+			So(err, ShouldNotBeNil)
+
+			out, err := runImageCommand("sign", "repo:1.0", "--tool", "notation", "--key", "zli-sign-test")
+			So(err, ShouldBeNil)
+			So(out, ShouldContainSubstring, "signed repo@"+image.DigestStr()+" with notation")
+
+			// the certificate isn't known by the server
+			out, err = runImageCommand("verify", "repo:1.0")
+			So(errors.Is(err, zerr.ErrImageNotTrusted), ShouldBeTrue)
+			So(out, ShouldContainSubstring, "TOOL")
+			So(out, ShouldContainSubstring, "notation")
+			So(out, ShouldContainSubstring, "false")
+
+			certificate, err := os.ReadFile(path.Join(keyDir, "notation/localkeys/zli-sign-test.crt"))
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetHeader("Content-type", "application/octet-stream").
+				SetBody(certificate).Post(baseURL + constants.FullNotation)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			// signed again now the certificate is uploaded
+			_, err = runImageCommand("sign", "repo:1.0", "--tool", "notation", "--key", "zli-sign-test")
+			So(err, ShouldBeNil)
+
+			trust := waitForTrust("notation")
+			So(trust.IsTrusted, ShouldBeTrue)
+			So(trust.Signatures, ShouldHaveLength, 2)
+
+			for _, signature := range trust.Signatures {
+				So(signature.Author, ShouldNotBeEmpty)
+			}
+		})
+	})
+}
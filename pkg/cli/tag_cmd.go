@@ -0,0 +1,35 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+func NewTagCommand(searchService SearchService) *cobra.Command {
+	tagCmd := &cobra.Command{
+		Use:   "tag [command]",
+		Short: "List, delete and prune the tags of a repository",
+		Long:  `List, delete and prune the tags of a repository`,
+		RunE:  ShowSuggestionsIfUnknownCommand,
+	}
+
+	tagCmd.SetUsageTemplate(tagCmd.UsageTemplate() + usageFooter)
+
+	tagCmd.PersistentFlags().String(cmdflags.URLFlag, "",
+		"Specify zot server URL if config-name is not mentioned")
+	tagCmd.PersistentFlags().String(cmdflags.ConfigFlag, "",
+		"Specify the registry configuration to use for connection")
+	tagCmd.PersistentFlags().StringP(cmdflags.UserFlag, "u", "",
+		`User Credentials of zot server in "username:password" format`)
+	tagCmd.PersistentFlags().Bool(cmdflags.DebugFlag, false, "Show debug output")
+
+	tagCmd.AddCommand(NewTagListCommand(searchService))
+	tagCmd.AddCommand(NewTagRemoveCommand(searchService))
+	tagCmd.AddCommand(NewTagPruneCommand(searchService))
+
+	return tagCmd
+}
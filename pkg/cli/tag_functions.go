@@ -0,0 +1,376 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+func ListTags(config searchConfig, repo string) error {
+	username, password := getUsernameAndPassword(config.user)
+
+	tags, err := getTags(context.Background(), config, username, password, repo)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		fmt.Fprintln(config.resultWriter, tag)
+	}
+
+	return nil
+}
+
+// DeleteTag deletes the tag of the "repo:tag" image, or the manifest it points to with all its tags. The deletion
+// is confirmed by reading "y" or "yes" from the input, unless it's forced.
+func DeleteTag(config searchConfig, image string, deleteManifest, force bool, input io.Reader) error {
+	repo, tag, err := parseRepoTag(image)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	digest, err := fetchImageDigest(repo, tag, username, password, config)
+	if err != nil {
+		return err
+	}
+
+	reference := tag
+	description := fmt.Sprintf("the tag %s:%s", repo, tag)
+
+	if deleteManifest {
+		reference = digest
+		description = fmt.Sprintf("the manifest %s of %s", digest, repo)
+
+		if !force {
+			tags, err := getTagsOfDigest(ctx, config, username, password, repo, digest)
+			if err != nil {
+				return err
+			}
+
+			description += fmt.Sprintf(" and its tags: %s", strings.Join(tags, ", "))
+		}
+	}
+
+	if !force {
+		fmt.Fprintf(config.resultWriter, "Delete %s? [y/N]: ", description)
+
+		if !readConfirmation(input) {
+			fmt.Fprintln(config.resultWriter, "Deletion cancelled")
+
+			return nil
+		}
+	}
+
+	url, err := combineServerAndEndpointURL(config.servURL, fmt.Sprintf("/v2/%s/manifests/%s", repo, reference))
+	if err != nil {
+		return err
+	}
+
+	if err := makeDELETERequest(ctx, url, username, password, config.verifyTLS, config.debug,
+		config.resultWriter); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(config.resultWriter, "Deleted %s\n", description)
+
+	return nil
+}
+
+func parseRepoTag(image string) (string, string, error) {
+	// the repo may contain a registry port, the tag is after the last colon
+	sepIndex := strings.LastIndex(image, ":")
+	if sepIndex <= 0 || sepIndex == len(image)-1 || strings.Contains(image[sepIndex:], "/") ||
+		strings.Contains(image, "@") {
+		return "", "", fmt.Errorf("%w: expected an image in the 'repo:tag' format, got '%s'",
+			zerr.ErrInvalidCLIParameter, image)
+	}
+
+	return image[:sepIndex], image[sepIndex+1:], nil
+}
+
+func getTags(ctx context.Context, config searchConfig, username, password, repo string) ([]string, error) {
+	url, err := combineServerAndEndpointURL(config.servURL, fmt.Sprintf("/v2/%s/tags/list", repo))
+	if err != nil {
+		return nil, err
+	}
+
+	tagList := &tagListResp{}
+
+	_, err = makeGETRequest(ctx, url, username, password, config.verifyTLS, config.debug, tagList,
+		config.resultWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	return tagList.Tags, nil
+}
+
+// getTagsOfDigest returns the tags of the repo pointing to the manifest.
+func getTagsOfDigest(ctx context.Context, config searchConfig, username, password, repo, digest string,
+) ([]string, error) {
+	tags, err := getTags(ctx, config, username, password, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	digestTags := []string{}
+
+	for _, tag := range tags {
+		tagDigest, err := fetchImageDigest(repo, tag, username, password, config)
+		if err != nil {
+			return nil, err
+		}
+
+		if tagDigest == digest {
+			digestTags = append(digestTags, tag)
+		}
+	}
+
+	return digestTags, nil
+}
+
+func readConfirmation(input io.Reader) bool {
+	answer, _ := bufio.NewReader(input).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// tagPruneRules select the tags deleted by `zli tag prune`, like the retention policies of the server.
+type tagPruneRules struct {
+	// keepLast is the number of the most recent matching tags which are kept
+	keepLast int
+	// patterns are the glob patterns of the tags to delete, all of them if none is given
+	patterns []string
+	// olderThan is the age of the images from which their tags are deleted, any age if it's zero
+	olderThan time.Duration
+	// notPulledWithin keeps the tags whose image was pulled during this period, none if it's zero
+	notPulledWithin time.Duration
+}
+
+type prunedTag struct {
+	tag         string
+	lastUpdated time.Time
+	// lastPulled is zero if the image was never pulled
+	lastPulled time.Time
+	// known is false for the tags of the images the search extension doesn't know about
+	known  bool
+	delete bool
+}
+
+/*
+PruneTags deletes the tags of the repo matching the rules, after printing which tags are kept and deleted. The tags
+are listed with the distribution API, the time of their images and of their last pull are given by the search
+extension. The deletion
+is confirmed by reading "y" or "yes" from the input, unless it's forced.
+*/
+func PruneTags(config searchConfig, repo string, rules tagPruneRules, dryRun, force bool, input io.Reader) error {
+	ctx := context.Background()
+	username, password := getUsernameAndPassword(config.user)
+
+	tags, err := getTags(ctx, config, username, password, repo)
+	if err != nil {
+		return err
+	}
+
+	config.sortBy = cmdflags.SortByUpdateTime
+
+	images, err := config.searchService.getImagesGQL(ctx, config, username, password, repo)
+	if err != nil {
+		return err
+	}
+
+	lastUpdated := map[string]time.Time{}
+	lastPulled := map[string]time.Time{}
+
+	for _, image := range images.Results {
+		if image.RepoName != repo {
+			continue
+		}
+
+		lastUpdated[image.Tag] = image.LastUpdated
+
+		if image.LastPulled != nil {
+			lastPulled[image.Tag] = *image.LastPulled
+		}
+	}
+
+	prunedTags := selectTagsToPrune(tags, lastUpdated, lastPulled, rules, time.Now())
+	toDelete := printPrunedTags(config.resultWriter, prunedTags)
+
+	if len(toDelete) == 0 {
+		fmt.Fprintf(config.resultWriter, "No tag of %s to delete\n", repo)
+
+		return nil
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if !force {
+		fmt.Fprintf(config.resultWriter, "Delete %d tags of %s? [y/N]: ", len(toDelete), repo)
+
+		if !readConfirmation(input) {
+			fmt.Fprintln(config.resultWriter, "Deletion cancelled")
+
+			return nil
+		}
+	}
+
+	for _, tag := range toDelete {
+		url, err := combineServerAndEndpointURL(config.servURL, fmt.Sprintf("/v2/%s/manifests/%s", repo, tag))
+		if err != nil {
+			return err
+		}
+
+		if err := makeDELETERequest(ctx, url, username, password, config.verifyTLS, config.debug,
+			config.resultWriter); err != nil {
+			return fmt.Errorf("unable to delete the tag %s:%s: %w", repo, tag, err)
+		}
+
+		fmt.Fprintf(config.resultWriter, "Deleted the tag %s:%s\n", repo, tag)
+	}
+
+	return nil
+}
+
+// selectTagsToPrune returns the tags sorted from the most recent, with the ones to delete according to the rules.
+func selectTagsToPrune(tags []string, lastUpdated, lastPulled map[string]time.Time, rules tagPruneRules,
+	now time.Time,
+) []prunedTag {
+	prunedTags := make([]prunedTag, 0, len(tags))
+
+	for _, tag := range tags {
+		updated, known := lastUpdated[tag]
+
+		prunedTags = append(prunedTags, prunedTag{tag: tag, lastUpdated: updated, lastPulled: lastPulled[tag],
+			known: known})
+	}
+
+	// the tags of the unknown images are last, they're never deleted
+	sort.SliceStable(prunedTags, func(i, j int) bool {
+		if prunedTags[i].known != prunedTags[j].known {
+			return prunedTags[i].known
+		}
+
+		return prunedTags[i].lastUpdated.After(prunedTags[j].lastUpdated)
+	})
+
+	kept := 0
+
+	for i := range prunedTags {
+		prunedTag := &prunedTags[i]
+
+		if !prunedTag.known || !matchesTagPatterns(prunedTag.tag, rules.patterns) {
+			continue
+		}
+
+		if kept < rules.keepLast {
+			kept++
+
+			continue
+		}
+
+		if rules.olderThan > 0 && now.Sub(prunedTag.lastUpdated) < rules.olderThan {
+			continue
+		}
+
+		if rules.notPulledWithin > 0 && now.Sub(prunedTag.lastPulled) < rules.notPulledWithin {
+			continue
+		}
+
+		prunedTag.delete = true
+	}
+
+	return prunedTags
+}
+
+func matchesTagPatterns(tag string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, tag); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// printPrunedTags prints whether the tags are kept or deleted and returns the ones to delete.
+func printPrunedTags(writer io.Writer, prunedTags []prunedTag) []string {
+	toDelete := []string{}
+
+	table := getImageTableWriter(writer)
+	table.SetHeader([]string{"TAG", "LAST UPDATED", "LAST PULLED", "ACTION"})
+
+	for _, prunedTag := range prunedTags {
+		lastUpdated, lastPulled, action := "unknown", "unknown", "keep"
+
+		if prunedTag.known {
+			lastUpdated = prunedTag.lastUpdated.Format(time.RFC3339)
+			lastPulled = "never"
+		}
+
+		if !prunedTag.lastPulled.IsZero() {
+			lastPulled = prunedTag.lastPulled.Format(time.RFC3339)
+		}
+
+		if prunedTag.delete {
+			action = "delete"
+
+			toDelete = append(toDelete, prunedTag.tag)
+		}
+
+		table.Append([]string{prunedTag.tag, lastUpdated, lastPulled, action})
+	}
+
+	table.Render()
+
+	return toDelete
+}
+
+// parseAge parses a number of days like "30d", or a duration like "12h".
+func parseAge(age string) (time.Duration, error) {
+	var duration time.Duration
+
+	if days, found := strings.CutSuffix(age, "d"); found {
+		count, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number of days '%s': %w", days, err)
+		}
+
+		duration = time.Duration(count) * 24 * time.Hour
+	} else {
+		var err error
+
+		duration, err = time.ParseDuration(age)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("the age must be positive, not '%s'", age) //nolint: goerr113
+	}
+
+	return duration, nil
+}
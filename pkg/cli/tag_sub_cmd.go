@@ -0,0 +1,165 @@
+//go:build search
+// +build search
+
+package cli
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/spf13/cobra"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/cli/cmdflags"
+)
+
+func NewTagListCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list [repo-name]",
+		Short:             "List the tags of a repository",
+		Long:              "List the tags of a repository",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepos(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			return ListTags(searchConfig, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func NewTagRemoveCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm [repo-name:tag]",
+		Short: "Delete a tag of a repository",
+		Long: fmt.Sprintf(`Delete a tag of a repository, the manifest it points to is kept unless '--%s' is given,
+in which case the manifest is deleted with all its tags`, cmdflags.ManifestFlag),
+		Example: `  # delete a tag after confirming it
+  zli tag rm alpine:3.16 --config local
+
+  # delete the manifest of a tag and all its other tags, without confirming it
+  zli tag rm alpine:3.16 --manifest --force --config local`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeImages(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			deleteManifest := defaultIfError(cmd.Flags().GetBool(cmdflags.ManifestFlag))
+			force := defaultIfError(cmd.Flags().GetBool(cmdflags.ForceFlag))
+
+			return DeleteTag(searchConfig, args[0], deleteManifest, force, cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().Bool(cmdflags.ManifestFlag, false, "Delete the manifest the tag points to, with all its tags")
+	cmd.Flags().Bool(cmdflags.ForceFlag, false, "Delete without asking for confirmation")
+
+	return cmd
+}
+
+func NewTagPruneCommand(searchService SearchService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune [repo-name]",
+		Short: "Delete the tags of a repository matching retention rules",
+		Long: fmt.Sprintf(`Delete the tags of a repository matching retention rules, evaluated by zli against the tags
+of the repository. The tags matching '--%s', all of them if it isn't given, are sorted by the time of their images,
+the '--%s' most recent ones are kept and the other ones are deleted if they're older than '--%s' and their image
+wasn't pulled, by tag or by digest, within '--%s'. The tags of the images unknown to the search extension are kept.
+The manifests are left to the garbage collection.`,
+			cmdflags.MatchFlag, cmdflags.KeepLastFlag, cmdflags.OlderThanFlag, cmdflags.NotPulledWithinFlag),
+		Example: `  # show which pull request tags older than 30 days would be deleted, keeping the 5 most recent ones
+  zli tag prune app --match 'pr-*' --keep-last 5 --older-than 30d --dry-run --config local
+
+  # delete the tags which weren't pulled during the last 90 days
+  zli tag prune app --not-pulled-within 90d --config local
+
+  # delete all the tags but the 10 most recent ones, without confirming it
+  zli tag prune app --keep-last 10 --force --config local`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepos(searchService),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			searchConfig, err := GetSearchConfigFromFlags(cmd, searchService)
+			if err != nil {
+				return err
+			}
+
+			rules, err := getTagPruneRulesFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			dryRun := defaultIfError(cmd.Flags().GetBool(cmdflags.DryRunFlag))
+			force := defaultIfError(cmd.Flags().GetBool(cmdflags.ForceFlag))
+
+			return PruneTags(searchConfig, args[0], rules, dryRun, force, cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().Int(cmdflags.KeepLastFlag, 0, "Keep this number of the most recent matching tags")
+	cmd.Flags().StringSlice(cmdflags.MatchFlag, nil, "Only delete the tags matching these glob patterns, e.g. 'pr-*'")
+	cmd.Flags().String(cmdflags.OlderThanFlag, "",
+		"Only delete the tags of the images older than this age, in days like '30d' or as a duration like '12h'")
+	cmd.Flags().String(cmdflags.NotPulledWithinFlag, "",
+		"Only delete the tags of the images which weren't pulled during this period, in days like '90d' or as a duration")
+	cmd.Flags().Bool(cmdflags.DryRunFlag, false, "Only show which tags would be deleted")
+	cmd.Flags().Bool(cmdflags.ForceFlag, false, "Delete without asking for confirmation")
+
+	return cmd
+}
+
+func getTagPruneRulesFromFlags(cmd *cobra.Command) (tagPruneRules, error) {
+	flags := cmd.Flags()
+
+	rules := tagPruneRules{
+		keepLast: defaultIfError(flags.GetInt(cmdflags.KeepLastFlag)),
+		patterns: defaultIfError(flags.GetStringSlice(cmdflags.MatchFlag)),
+	}
+
+	if !flags.Changed(cmdflags.KeepLastFlag) && !flags.Changed(cmdflags.MatchFlag) &&
+		!flags.Changed(cmdflags.OlderThanFlag) && !flags.Changed(cmdflags.NotPulledWithinFlag) {
+		return tagPruneRules{}, fmt.Errorf("%w: at least one of '--%s', '--%s', '--%s' or '--%s' is needed to select "+
+			"the tags", zerr.ErrInvalidCLIParameter, cmdflags.KeepLastFlag, cmdflags.MatchFlag, cmdflags.OlderThanFlag,
+			cmdflags.NotPulledWithinFlag)
+	}
+
+	if rules.keepLast < 0 {
+		return tagPruneRules{}, fmt.Errorf("%w: '--%s' can't be negative", zerr.ErrInvalidCLIParameter,
+			cmdflags.KeepLastFlag)
+	}
+
+	for _, pattern := range rules.patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return tagPruneRules{}, fmt.Errorf("%w: invalid pattern '%s': %w", zerr.ErrInvalidCLIParameter, pattern, err)
+		}
+	}
+
+	if olderThan := defaultIfError(flags.GetString(cmdflags.OlderThanFlag)); olderThan != "" {
+		age, err := parseAge(olderThan)
+		if err != nil {
+			return tagPruneRules{}, fmt.Errorf("%w: '--%s': %w", zerr.ErrInvalidCLIParameter, cmdflags.OlderThanFlag,
+				err)
+		}
+
+		rules.olderThan = age
+	}
+
+	if notPulledWithin := defaultIfError(flags.GetString(cmdflags.NotPulledWithinFlag)); notPulledWithin != "" {
+		age, err := parseAge(notPulledWithin)
+		if err != nil {
+			return tagPruneRules{}, fmt.Errorf("%w: '--%s': %w", zerr.ErrInvalidCLIParameter,
+				cmdflags.NotPulledWithinFlag, err)
+		}
+
+		rules.notPulledWithin = age
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,226 @@
+//go:build search
+// +build search
+
+package cli //nolint:testpackage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestTagCommand(t *testing.T) {
+	Convey("tags", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		ctlr := api.NewController(conf)
+		ctlr.Config.Storage.RootDirectory = t.TempDir()
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		image := CreateRandomImage()
+
+		for _, tag := range []string{"1.0", "latest"} {
+			err := UploadImage(image, baseURL, "repo", tag)
+			So(err, ShouldBeNil)
+		}
+
+		err := UploadImage(CreateRandomImage(), baseURL, "repo", "2.0")
+		So(err, ShouldBeNil)
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"tagstest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		runTagCommand := func(input string, args ...string) (string, error) {
+			cmd := NewTagCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetIn(strings.NewReader(input))
+			cmd.SetArgs(append(args, "--config", "tagstest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		listTags := func() []string {
+			out, err := runTagCommand("", "list", "repo")
+			So(err, ShouldBeNil)
+
+			return strings.Fields(out)
+		}
+
+		So(listTags(), ShouldResemble, []string{"1.0", "2.0", "latest"})
+
+		// the tag is kept if the deletion isn't confirmed
+		out, err := runTagCommand("n\n", "rm", "repo:2.0")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Delete the tag repo:2.0? [y/N]")
+		So(out, ShouldContainSubstring, "Deletion cancelled")
+		So(listTags(), ShouldResemble, []string{"1.0", "2.0", "latest"})
+
+		out, err = runTagCommand("y\n", "rm", "repo:2.0")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Deleted the tag repo:2.0")
+		So(listTags(), ShouldResemble, []string{"1.0", "latest"})
+
+		// the other tags of the manifest are listed before deleting it
+		out, err = runTagCommand("yes\n", "rm", "repo:1.0", "--manifest")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "the manifest "+image.DigestStr()+" of repo and its tags: 1.0, latest")
+		So(out, ShouldContainSubstring, "Deleted the manifest")
+		So(listTags(), ShouldBeEmpty)
+
+		_, err = runTagCommand("", "rm", "repo:1.0", "--force")
+		So(err, ShouldNotBeNil)
+
+		for _, image := range []string{"repo", "repo:", ":tag", "repo@sha256:abc", "localhost:8080/repo"} {
+			_, err = runTagCommand("", "rm", image, "--force")
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		}
+
+		_, err = runTagCommand("", "list")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestTagPruneCommand(t *testing.T) {
+	Convey("zli tag prune", t, func() {
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+
+		cm.StartAndWait(conf.HTTP.Port)
+		defer cm.StopServer()
+
+		now := time.Now().UTC()
+
+		// the tags are created from 0 to 3 months ago
+		for i, tag := range []string{"1.0", "pr-3", "pr-2", "pr-1"} {
+			created := now.AddDate(0, -i, 0)
+			config := GetDefaultConfig()
+			config.Created = &created
+
+			image := CreateImageWith().RandomLayers(1, 10).ImageConfig(config).Build()
+			So(UploadImage(image, baseURL, "repo", tag), ShouldBeNil)
+		}
+
+		configPath := makeConfigFile(fmt.Sprintf(`{"configs":[{"_name":"prunetest","url":"%s","showspinner":false}]}`,
+			baseURL))
+		defer os.Remove(configPath)
+
+		runPruneCommand := func(input string, args ...string) (string, error) {
+			cmd := NewTagCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetErr(buff)
+			cmd.SetIn(strings.NewReader(input))
+			cmd.SetArgs(append(append([]string{"prune", "repo"}, args...), "--config", "prunetest"))
+			err := cmd.Execute()
+
+			return buff.String(), err
+		}
+
+		listTags := func() []string {
+			cmd := NewTagCommand(new(searchService))
+			buff := bytes.NewBufferString("")
+			cmd.SetOut(buff)
+			cmd.SetArgs([]string{"list", "repo", "--config", "prunetest"})
+			So(cmd.Execute(), ShouldBeNil)
+
+			return strings.Fields(buff.String())
+		}
+
+		out, err := runPruneCommand("", "--match", "pr-*", "--keep-last", "1", "--older-than", "45d", "--dry-run")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "pr-1")
+		So(strings.Count(out, "delete"), ShouldEqual, 2)
+		So(listTags(), ShouldResemble, []string{"1.0", "pr-1", "pr-2", "pr-3"})
+
+		out, err = runPruneCommand("n\n", "--match", "pr-*", "--keep-last", "1", "--older-than", "45d")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Delete 2 tags of repo? [y/N]")
+		So(out, ShouldContainSubstring, "Deletion cancelled")
+		So(listTags(), ShouldResemble, []string{"1.0", "pr-1", "pr-2", "pr-3"})
+
+		out, err = runPruneCommand("y\n", "--match", "pr-*", "--keep-last", "1", "--older-than", "45d")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Deleted the tag repo:pr-1")
+		So(out, ShouldContainSubstring, "Deleted the tag repo:pr-2")
+		So(listTags(), ShouldResemble, []string{"1.0", "pr-3"})
+
+		out, err = runPruneCommand("", "--older-than", "1000d", "--force")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "No tag of repo to delete")
+
+		out, err = runPruneCommand("", "--keep-last", "1", "--force")
+		So(err, ShouldBeNil)
+		So(out, ShouldContainSubstring, "Deleted the tag repo:pr-3")
+		So(listTags(), ShouldResemble, []string{"1.0"})
+
+		for _, args := range [][]string{
+			{},
+			{"--keep-last", "-1"},
+			{"--match", "pr-["},
+			{"--older-than", "soon"},
+			{"--older-than", "0d"},
+			{"--not-pulled-within", "soon"},
+		} {
+			_, err = runPruneCommand("", append(args, "--force")...)
+			So(errors.Is(err, zerr.ErrInvalidCLIParameter), ShouldBeTrue)
+		}
+
+		Convey("tags of unknown images are kept", func() {
+			lastUpdated := map[string]time.Time{"a": now.Add(-time.Hour), "b": now.Add(-2 * time.Hour)}
+
+			prunedTags := selectTagsToPrune([]string{"unknown", "b", "a"}, lastUpdated, nil, tagPruneRules{}, now)
+			So(prunedTags, ShouldHaveLength, 3)
+			So(prunedTags[0], ShouldResemble,
+				prunedTag{tag: "a", lastUpdated: lastUpdated["a"], known: true, delete: true})
+			So(prunedTags[1].delete, ShouldBeTrue)
+			So(prunedTags[2], ShouldResemble, prunedTag{tag: "unknown"})
+		})
+
+		Convey("tags of recently pulled images are kept", func() {
+			lastUpdated := map[string]time.Time{"a": now.AddDate(0, -2, 0), "b": now.AddDate(0, -1, 0)}
+			lastPulled := map[string]time.Time{"a": now.AddDate(0, 0, -1), "b": now.AddDate(0, 0, -60)}
+			rules := tagPruneRules{notPulledWithin: 30 * 24 * time.Hour}
+
+			prunedTags := selectTagsToPrune([]string{"a", "b"}, lastUpdated, lastPulled, rules, now)
+			So(prunedTags, ShouldHaveLength, 2)
+			So(prunedTags[0].tag, ShouldEqual, "b")
+			So(prunedTags[0].delete, ShouldBeTrue)
+			So(prunedTags[1].tag, ShouldEqual, "a")
+			So(prunedTags[1].delete, ShouldBeFalse)
+		})
+	})
+}
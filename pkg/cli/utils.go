@@ -7,8 +7,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -80,7 +82,7 @@ func collectResults(config searchConfig, wg *sync.WaitGroup, imageErr chan strin
 			foundResult = true
 
 			fmt.Fprint(config.resultWriter, result.StrValue)
-		case <-time.After(waitTimeout):
+		case <-time.After(getHTTPClientOptions().waitTimeout()):
 			config.spinner.stopSpinner()
 			cancel()
 
@@ -118,9 +120,113 @@ func (spinner *spinnerState) stopSpinner() {
 	}
 }
 
-const (
-	waitTimeout = httpTimeout + 5*time.Second
-)
+/*
+runConcurrently calls the function with the indexes of count items, with at most concurrency calls running at
+a time. The items not started yet are skipped after the first error, which is returned.
+*/
+func runConcurrently(ctx context.Context, count, concurrency int,
+	call func(ctx context.Context, index int) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var (
+		wtgrp    sync.WaitGroup
+		firstErr error
+		errOnce  sync.Once
+	)
+
+	indexes := make(chan int)
+
+	for i := 0; i < concurrency; i++ {
+		wtgrp.Add(1)
+
+		go func() {
+			defer wtgrp.Done()
+
+			for index := range indexes {
+				if err := call(ctx, index); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for index := 0; index < count && ctx.Err() == nil; index++ {
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+		}
+	}
+
+	close(indexes)
+	wtgrp.Wait()
+
+	return firstErr
+}
+
+const progressBarWidth = 30
+
+// progressBar shows the progress of the long operations, if the spinner is enabled.
+type progressBar struct {
+	writer  io.Writer
+	enabled bool
+	unit    string
+	total   int
+	done    int
+	lock    sync.Mutex
+}
+
+func newProgressBar(spinner spinnerState, total int, unit string) *progressBar {
+	bar := &progressBar{enabled: spinner.enabled, total: total, unit: unit}
+
+	if spinner.spinner != nil {
+		bar.writer = spinner.spinner.Writer
+	}
+
+	bar.enabled = bar.enabled && bar.writer != nil && total > 0
+	bar.render()
+
+	return bar
+}
+
+func (bar *progressBar) increment() {
+	bar.lock.Lock()
+	defer bar.lock.Unlock()
+
+	bar.done++
+	bar.render()
+}
+
+// finish clears the progress bar, for the results to be printed.
+func (bar *progressBar) finish() {
+	if bar.enabled {
+		fmt.Fprintf(bar.writer, "\r%s\r", strings.Repeat(" ", progressBarWidth+len(bar.status())+3))
+	}
+}
+
+func (bar *progressBar) render() {
+	if !bar.enabled {
+		return
+	}
+
+	filled := bar.done * progressBarWidth / bar.total
+
+	fmt.Fprintf(bar.writer, "\r[%s%s] %s", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled),
+		bar.status())
+}
+
+func (bar *progressBar) status() string {
+	return fmt.Sprintf("%d/%d %s", bar.done, bar.total, bar.unit)
+}
 
 type stringResult struct {
 	StrValue string
@@ -185,9 +291,10 @@ func printImageTableHeader(writer io.Writer, verbose bool, maxImageNameLen, maxT
 
 func printCVETableHeader(writer io.Writer) {
 	table := getCVETableWriter(writer)
-	row := make([]string, 3) //nolint:gomnd
+	row := make([]string, 4) //nolint:gomnd
 	row[colCVEIDIndex] = "ID"
 	row[colCVESeverityIndex] = "SEVERITY"
+	row[colCVEFixStatusIndex] = "FIX"
 	row[colCVETitleIndex] = "TITLE"
 
 	table.Append(row)
@@ -369,13 +476,46 @@ func GetSearchConfigFromFlags(cmd *cobra.Command, searchService SearchService) (
 		return searchConfig{}, err
 	}
 
+	concurrency, err := getConcurrencyConfig(cmd)
+	if err != nil {
+		return searchConfig{}, err
+	}
+
+	options, err := getHTTPClientOptionsFromFlags(cmd)
+	if err != nil {
+		return searchConfig{}, err
+	}
+
+	setHTTPClientOptions(options)
+
+	// the templates and the columns are rendered from the json output
+	outputFormat, err := getOutputFormat(cmd)
+	if err != nil {
+		return searchConfig{}, err
+	}
+
 	flags := cmd.Flags()
 	user := defaultIfError(flags.GetString(cmdflags.UserFlag))
 	fixed := defaultIfError(flags.GetBool(cmdflags.FixedFlag))
 	debug := defaultIfError(flags.GetBool(cmdflags.DebugFlag))
 	verbose := defaultIfError(flags.GetBool(cmdflags.VerboseFlag))
-	outputFormat := defaultIfError(flags.GetString(cmdflags.OutputFormatFlag))
 	sortBy := defaultIfError(flags.GetString(cmdflags.SortByFlag))
+	fixStatus := defaultIfError(flags.GetString(cmdflags.FixStatusFlag))
+	severity := defaultIfError(flags.GetString(cmdflags.SeverityFlag))
+	failOn := defaultIfError(flags.GetString(cmdflags.FailOnFlag))
+	outputFile := defaultIfError(flags.GetString(cmdflags.OutputFileFlag))
+
+	// the credentials referenced by the config, or else the ones stored by `zli login`, are used if none are given
+	if user == "" {
+		user, err = getProfileUser(cmd)
+		if err != nil {
+			return searchConfig{}, err
+		}
+	}
+
+	if user == "" {
+		user = getStoredUser(cmd, serverURL)
+	}
 
 	spin := spinner.New(spinner.CharSets[39], spinnerDuration, spinner.WithWriter(cmd.ErrOrStderr()))
 	spin.Prefix = prefix
@@ -390,6 +530,11 @@ func GetSearchConfigFromFlags(cmd *cobra.Command, searchService SearchService) (
 		verbose:       verbose,
 		debug:         debug,
 		sortBy:        sortBy,
+		fixStatus:     fixStatus,
+		severity:      severity,
+		failOn:        failOn,
+		outputFile:    outputFile,
+		concurrency:   concurrency,
 		spinner:       spinnerState{spin, isSpinner},
 		resultWriter:  cmd.OutOrStdout(),
 	}, nil
@@ -406,11 +551,11 @@ func defaultIfError[T any](out T, err error) T {
 }
 
 func GetCliConfigOptions(cmd *cobra.Command) (bool, bool, error) {
-	configName, err := cmd.Flags().GetString(cmdflags.ConfigFlag)
-	if err != nil {
+	if _, err := cmd.Flags().GetString(cmdflags.ConfigFlag); err != nil {
 		return false, false, err
 	}
 
+	configName := getConfigName(cmd)
 	if configName == "" {
 		return false, false, nil
 	}
@@ -435,20 +580,135 @@ func GetCliConfigOptions(cmd *cobra.Command) (bool, bool, error) {
 	return isSpinner, verifyTLS, nil
 }
 
+// getConcurrencyConfig returns the number of requests sent in parallel set in the config, if any.
+func getConcurrencyConfig(cmd *cobra.Command) (int, error) {
+	value, err := getFlagOrConfigValue(cmd, "", concurrencyConfig)
+	if err != nil || value == "" {
+		return defaultConcurrency, err
+	}
+
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency < 1 {
+		return 0, fmt.Errorf("%w: %s must be a positive number, not '%s'", zerr.ErrInvalidCLIParameter,
+			concurrencyConfig, value)
+	}
+
+	return concurrency, nil
+}
+
+// getHTTPClientOptionsFromFlags returns the settings of the HTTP clients, the flags override the config.
+func getHTTPClientOptionsFromFlags(cmd *cobra.Command) (httpClientOptions, error) {
+	options := httpClientOptions{timeout: httpTimeout}
+
+	timeout, err := getFlagOrConfigValue(cmd, cmdflags.TimeoutFlag, timeoutConfig)
+	if err != nil {
+		return httpClientOptions{}, err
+	}
+
+	if timeout != "" {
+		options.timeout, err = time.ParseDuration(timeout)
+		if err != nil || options.timeout <= 0 {
+			return httpClientOptions{}, fmt.Errorf("%w: the timeout must be a positive duration like 30s, not '%s'",
+				zerr.ErrInvalidCLIParameter, timeout)
+		}
+	}
+
+	retries, err := getFlagOrConfigValue(cmd, cmdflags.RetriesFlag, retriesConfig)
+	if err != nil {
+		return httpClientOptions{}, err
+	}
+
+	if retries != "" {
+		options.retries, err = strconv.Atoi(retries)
+		if err != nil || options.retries < 0 {
+			return httpClientOptions{}, fmt.Errorf("%w: the retries must be a number, not '%s'",
+				zerr.ErrInvalidCLIParameter, retries)
+		}
+	}
+
+	proxy, err := getFlagOrConfigValue(cmd, cmdflags.ProxyFlag, proxyConfig)
+	if err != nil {
+		return httpClientOptions{}, err
+	}
+
+	if proxy != "" {
+		options.proxy, err = url.Parse(proxy)
+		if err != nil || options.proxy.Scheme == "" || options.proxy.Host == "" {
+			return httpClientOptions{}, fmt.Errorf("%w: the proxy must be a URL like http://proxy:3128, not '%s'",
+				zerr.ErrInvalidCLIParameter, proxy)
+		}
+	}
+
+	options.certsDir, err = getFlagOrConfigValue(cmd, "", certsDirConfig)
+	if err != nil {
+		return httpClientOptions{}, err
+	}
+
+	noProxy, err := getFlagOrConfigValue(cmd, cmdflags.NoProxyFlag, noProxyConfig)
+	if err != nil {
+		return httpClientOptions{}, err
+	}
+
+	for _, host := range strings.Split(noProxy, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			options.noProxy = append(options.noProxy, host)
+		}
+	}
+
+	return options, nil
+}
+
+// getFlagOrConfigValue returns the value of the flag if it's given, or else the value of the key in the config.
+func getFlagOrConfigValue(cmd *cobra.Command, flagName, configKey string) (string, error) {
+	if flagName != "" && cmd.Flags().Changed(flagName) {
+		flag := cmd.Flags().Lookup(flagName)
+
+		return flag.Value.String(), nil
+	}
+
+	configName := getConfigName(cmd)
+	if configName == "" {
+		return "", nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return getConfigValue(path.Join(home, "/.zot"), configName, configKey)
+}
+
+/*
+getConfigName returns the name of the config of ~/.zot the command uses, the profile. It's given by '--config',
+or else by '--profile' or the ZLI_PROFILE variable, the flags of the command override the values of the profile.
+*/
+func getConfigName(cmd *cobra.Command) string {
+	if configName := defaultIfError(cmd.Flags().GetString(cmdflags.ConfigFlag)); configName != "" {
+		return configName
+	}
+
+	if profile := defaultIfError(cmd.Flags().GetString(cmdflags.ProfileFlag)); profile != "" {
+		return profile
+	}
+
+	return os.Getenv(profileEnvVar)
+}
+
 func GetServerURLFromFlags(cmd *cobra.Command) (string, error) {
 	serverURL, err := cmd.Flags().GetString(cmdflags.URLFlag)
 	if err == nil && serverURL != "" {
 		return serverURL, nil
 	}
 
-	configName, err := cmd.Flags().GetString(cmdflags.ConfigFlag)
-	if err != nil {
+	if _, err := cmd.Flags().GetString(cmdflags.ConfigFlag); err != nil {
 		return "", err
 	}
 
+	configName := getConfigName(cmd)
 	if configName == "" {
-		return "", fmt.Errorf("%w: specify either '--%s' or '--%s' flags", zerr.ErrNoURLProvided, cmdflags.URLFlag,
-			cmdflags.ConfigFlag)
+		return "", fmt.Errorf("%w: specify either '--%s' or '--%s' flags, or a profile with '--%s' or %s",
+			zerr.ErrNoURLProvided, cmdflags.URLFlag, cmdflags.ConfigFlag, cmdflags.ProfileFlag, profileEnvVar)
 	}
 
 	serverURL, err = ReadServerURLFromConfig(configName)
@@ -0,0 +1,492 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	DefaultLeaseDuration = 30 * time.Second
+	// an upload session is owned as long as it's used, each of its requests renews the lease
+	uploadLeaseDuration = time.Hour
+	storeTimeout        = 10 * time.Second
+	minRetryDelay       = 10 * time.Millisecond
+	maxRetryDelay       = 500 * time.Millisecond
+
+	memberKeyPrefix = "member/"
+	taskKeyPrefix   = "task/"
+	repoKeyPrefix   = "repo/"
+	uploadKeyPrefix = "upload/"
+	dedupeKey       = "dedupe"
+)
+
+// MemberInfo describes a member of the cluster.
+type MemberInfo struct {
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// MemberStatus is a member of the cluster with the background tasks it runs.
+type MemberStatus struct {
+	MemberInfo
+	Self  bool     `json:"self"`
+	Tasks []string `json:"tasks,omitempty"`
+}
+
+// Member is this zot instance in the cluster, it holds the leases of the repositories it writes, of its
+// upload sessions and of the background tasks it runs.
+type Member struct {
+	info          MemberInfo
+	infoJSON      string
+	store         LeaseStore
+	leaseDuration time.Duration
+	log           log.Logger
+
+	// the tasks the member wants to run, and whether it holds their leases
+	tasks map[string]bool
+	// the leases of the repositories being written by the member and of the dedupe
+	leases  map[string]*lease
+	lock    sync.Mutex
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// lease is held by the member as long as one of its image stores uses it, the image stores of the member share
+// the holder name of the member in the lease store.
+type lease struct {
+	users int
+	// false once the lease couldn't be renewed
+	held bool
+}
+
+// NewLeaseStore connects to the store of the leases of the config.
+func NewLeaseStore(leasesConfig config.LeasesConfig) (LeaseStore, error) {
+	keyPrefix := leasesConfig.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = DefaultKeyPrefix
+	}
+
+	switch leasesConfig.Driver {
+	case "memory":
+		return NewMemoryLeaseStore(keyPrefix), nil
+	case "redis":
+		client, err := GetRedisClient(leasesConfig.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewRedisLeaseStore(client, keyPrefix), nil
+	default:
+		return nil, zerr.ErrBadConfig
+	}
+}
+
+func NewMember(clusterConfig *config.ClusterConfig, store LeaseStore, log log.Logger) (*Member, error) {
+	name := clusterConfig.Name
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+
+		name = hostname
+	}
+
+	leaseDuration := clusterConfig.LeaseDuration
+	if leaseDuration == 0 {
+		leaseDuration = DefaultLeaseDuration
+	}
+
+	info := MemberInfo{
+		Name:      name,
+		Address:   strings.TrimSuffix(clusterConfig.Address, "/"),
+		StartedAt: time.Now().UTC(),
+	}
+
+	infoJSON, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Member{
+		info:          info,
+		infoJSON:      string(infoJSON),
+		store:         store,
+		leaseDuration: leaseDuration,
+		log:           log,
+		tasks:         map[string]bool{},
+		leases:        map[string]*lease{},
+	}, nil
+}
+
+func (member *Member) Info() MemberInfo {
+	return member.info
+}
+
+// Start joins the cluster, the membership and the leases of the tasks are renewed until the member is stopped.
+// The membership of a previous run of the member, e.g. after a crash, is taken over once it expires.
+func (member *Member) Start() error {
+	deadline := time.Now().Add(member.leaseDuration)
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		acquired, err := member.store.Acquire(ctx, memberKeyPrefix+member.info.Name, member.infoJSON,
+			member.leaseDuration)
+
+		cancel()
+
+		if err != nil {
+			return err
+		}
+
+		if acquired {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return zerr.ErrClusterMemberExists
+		}
+
+		time.Sleep(maxRetryDelay)
+	}
+
+	renewCtx, renewCancel := context.WithCancel(context.Background())
+
+	member.cancel = renewCancel
+	member.stopped = make(chan struct{})
+
+	go member.renewLeases(renewCtx)
+
+	member.log.Info().Str("member", member.info.Name).Str("address", member.info.Address).Msg("joined the cluster")
+
+	return nil
+}
+
+// Stop leaves the cluster, the tasks of the member are taken over by the other members.
+func (member *Member) Stop() {
+	if member.cancel == nil {
+		return
+	}
+
+	member.cancel()
+	<-member.stopped
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	member.lock.Lock()
+
+	for task := range member.tasks {
+		member.tasks[task] = false
+
+		_ = member.store.Release(ctx, taskKeyPrefix+task, member.info.Name)
+	}
+
+	member.lock.Unlock()
+
+	_ = member.store.Release(ctx, memberKeyPrefix+member.info.Name, member.infoJSON)
+	_ = member.store.Close()
+
+	member.log.Info().Str("member", member.info.Name).Msg("left the cluster")
+}
+
+func (member *Member) renewLeases(ctx context.Context) {
+	defer close(member.stopped)
+
+	ticker := time.NewTicker(member.leaseDuration / 3) //nolint:gomnd // renewed twice before expiring
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			member.renew(ctx)
+		}
+	}
+}
+
+func (member *Member) renew(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+
+	acquired, err := member.store.Acquire(ctx, memberKeyPrefix+member.info.Name, member.infoJSON, member.leaseDuration)
+	if err != nil || !acquired {
+		member.log.Error().Err(err).Str("member", member.info.Name).Msg("failed to renew the cluster membership")
+	}
+
+	// the leases are renewed under the lock so a lease released by UnlockRepo isn't taken again
+	member.lock.Lock()
+
+	for key, lease := range member.leases {
+		if !lease.held {
+			continue
+		}
+
+		// another member may take the lease once it expires, the writes in progress are aborted
+		acquired, err := member.store.Acquire(ctx, key, member.info.Name, member.leaseDuration)
+		if err != nil || !acquired {
+			member.log.Error().Err(err).Str("lease", key).Msg("failed to renew the lease, the writes are aborted")
+
+			lease.held = false
+		}
+	}
+
+	tasks := make([]string, 0, len(member.tasks))
+	for task := range member.tasks {
+		tasks = append(tasks, task)
+	}
+
+	member.lock.Unlock()
+
+	owned := make(map[string]bool, len(tasks))
+
+	for _, task := range tasks {
+		acquired, err := member.store.Acquire(ctx, taskKeyPrefix+task, member.info.Name, member.leaseDuration)
+		if err != nil {
+			member.log.Error().Err(err).Str("task", task).Msg("failed to renew the lease of the task")
+		}
+
+		// the task isn't run if the lease can't be renewed, another member may run it
+		owned[task] = acquired && err == nil
+	}
+
+	member.lock.Lock()
+	defer member.lock.Unlock()
+
+	for task, acquired := range owned {
+		if member.tasks[task] != acquired {
+			member.log.Info().Str("task", task).Bool("owned", acquired).Msg("the ownership of the task changed")
+		}
+
+		member.tasks[task] = acquired
+	}
+}
+
+// OwnsTask tells whether the member runs the background task, the leases of the tasks are taken when the
+// membership is renewed so the ownership of a new task is known after a renewal.
+func (member *Member) OwnsTask(name string) bool {
+	member.lock.Lock()
+	defer member.lock.Unlock()
+
+	owned, ok := member.tasks[name]
+	if !ok {
+		member.tasks[name] = false
+	}
+
+	return owned
+}
+
+// LockRepo waits for the lease of the repository, it's held until UnlockRepo so only one member writes
+// in the repository at a time. The write is denied if the leases can't be reached.
+func (member *Member) LockRepo(repo string) error {
+	return member.lockLease(repoKeyPrefix + repo)
+}
+
+// UnlockRepo releases the lease of the repository.
+func (member *Member) UnlockRepo(repo string) {
+	member.unlockLease(repoKeyPrefix + repo)
+}
+
+// CheckRepo returns an error if the lease of the repository, or of the dedupe, couldn't be renewed since it was
+// taken, another member may be writing in the repository.
+func (member *Member) CheckRepo(repo string) error {
+	member.lock.Lock()
+	defer member.lock.Unlock()
+
+	for _, key := range []string{repoKeyPrefix + repo, dedupeKey} {
+		if lease, ok := member.leases[key]; ok && !lease.held {
+			return zerr.ErrClusterLeaseLost
+		}
+	}
+
+	return nil
+}
+
+// LockDedupe waits for the lease of the deduped blobs, they're shared by the repositories of all the members.
+func (member *Member) LockDedupe() error {
+	return member.lockLease(dedupeKey)
+}
+
+// UnlockDedupe releases the lease of the deduped blobs.
+func (member *Member) UnlockDedupe() {
+	member.unlockLease(dedupeKey)
+}
+
+// lockLease takes the lease for the member, or shares it if another image store of the member already holds it.
+// The lease store is only called under the lock of the member, so a lease can't be released by its last user
+// while it's taken again.
+func (member *Member) lockLease(key string) error {
+	delay := minRetryDelay
+
+	for {
+		acquired, err := member.acquireLease(key)
+		if err != nil {
+			member.log.Error().Err(err).Str("lease", key).Msg("failed to acquire the lease, the write is denied")
+
+			return fmt.Errorf("%w: %s: %w", zerr.ErrClusterLeaseUnavailable, key, err)
+		}
+
+		if acquired {
+			return nil
+		}
+
+		time.Sleep(delay)
+
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+func (member *Member) acquireLease(key string) (bool, error) {
+	member.lock.Lock()
+	defer member.lock.Unlock()
+
+	// a lost lease is taken again once its users have aborted their writes
+	if lease, ok := member.leases[key]; ok {
+		if lease.held {
+			lease.users++
+		}
+
+		return lease.held, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	acquired, err := member.store.Acquire(ctx, key, member.info.Name, member.leaseDuration)
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	member.leases[key] = &lease{users: 1, held: true}
+
+	return true, nil
+}
+
+// unlockLease releases the lease once its last user is done.
+func (member *Member) unlockLease(key string) {
+	member.lock.Lock()
+	defer member.lock.Unlock()
+
+	lease, ok := member.leases[key]
+	if !ok {
+		return
+	}
+
+	if lease.users--; lease.users > 0 {
+		return
+	}
+
+	delete(member.leases, key)
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	if err := member.store.Release(ctx, key, member.info.Name); err != nil {
+		member.log.Error().Err(err).Str("lease", key).Msg("failed to release the lease")
+	}
+}
+
+// ClaimUpload makes the member the owner of the upload session, or extends its ownership.
+func (member *Member) ClaimUpload(repo, session string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	_, err := member.store.Acquire(ctx, uploadKeyPrefix+repo+"/"+session, member.info.Name, uploadLeaseDuration)
+
+	return err
+}
+
+// ReleaseUpload frees the upload session once it's finished or cancelled.
+func (member *Member) ReleaseUpload(repo, session string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	return member.store.Release(ctx, uploadKeyPrefix+repo+"/"+session, member.info.Name)
+}
+
+// UploadOwner returns the other member owning the upload session, nil if the member owns it or
+// if the owner left the cluster.
+func (member *Member) UploadOwner(repo, session string) (*MemberInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	owner, err := member.store.Holder(ctx, uploadKeyPrefix+repo+"/"+session)
+	if err != nil || owner == "" || owner == member.info.Name {
+		return nil, err
+	}
+
+	ownerInfo, err := member.store.Holder(ctx, memberKeyPrefix+owner)
+	if err != nil || ownerInfo == "" {
+		return nil, err
+	}
+
+	var info MemberInfo
+
+	if err := json.Unmarshal([]byte(ownerInfo), &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// Members returns the members of the cluster, sorted by name, with the tasks they run.
+func (member *Member) Members() ([]MemberStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	memberLeases, err := member.store.List(ctx, memberKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	taskLeases, err := member.store.List(ctx, taskKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := map[string][]string{}
+
+	for key, holder := range taskLeases {
+		tasks[holder] = append(tasks[holder], strings.TrimPrefix(key, taskKeyPrefix))
+	}
+
+	members := make([]MemberStatus, 0, len(memberLeases))
+
+	for _, memberInfo := range memberLeases {
+		var info MemberInfo
+
+		if err := json.Unmarshal([]byte(memberInfo), &info); err != nil {
+			member.log.Error().Err(err).Str("member", memberInfo).Msg("invalid member of the cluster")
+
+			continue
+		}
+
+		sort.Strings(tasks[info.Name])
+
+		members = append(members, MemberStatus{
+			MemberInfo: info,
+			Self:       info.Name == member.info.Name,
+			Tasks:      tasks[info.Name],
+		})
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+
+	return members, nil
+}
@@ -0,0 +1,372 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/cluster"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const leaseDuration = 300 * time.Millisecond
+
+var errLeaseStore = errors.New("lease store unreachable")
+
+func newMember(t *testing.T, name, keyPrefix string) *cluster.Member {
+	t.Helper()
+
+	clusterConfig := &config.ClusterConfig{
+		Name:          name,
+		Address:       "http://" + name + ":8080/",
+		Leases:        config.LeasesConfig{Driver: "memory", KeyPrefix: keyPrefix},
+		LeaseDuration: leaseDuration,
+	}
+
+	store, err := cluster.NewLeaseStore(clusterConfig.Leases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := cluster.NewMember(clusterConfig, store, log.NewLogger("debug", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return member
+}
+
+func TestLeaseStore(t *testing.T) {
+	Convey("Memory lease store", t, func() {
+		testLeaseStore(cluster.NewMemoryLeaseStore(t.Name()))
+	})
+
+	Convey("Unsupported lease store", t, func() {
+		_, err := cluster.NewLeaseStore(config.LeasesConfig{Driver: "etcd"})
+		So(err, ShouldEqual, zerr.ErrBadConfig)
+
+		_, err = cluster.NewLeaseStore(config.LeasesConfig{Driver: "redis", URL: "invalid://url"})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestRedisLeaseStore(t *testing.T) {
+	skipRedis(t)
+
+	Convey("Redis lease store", t, func() {
+		store, err := cluster.NewLeaseStore(config.LeasesConfig{
+			Driver:    "redis",
+			URL:       os.Getenv("REDIS_URL"),
+			KeyPrefix: "zot-" + t.Name(),
+		})
+		So(err, ShouldBeNil)
+
+		defer store.Close()
+
+		testLeaseStore(store)
+	})
+}
+
+func testLeaseStore(store cluster.LeaseStore) {
+	ctx := context.Background()
+
+	acquired, err := store.Acquire(ctx, "task/gc", "a", leaseDuration)
+	So(err, ShouldBeNil)
+	So(acquired, ShouldBeTrue)
+
+	// held by another holder
+	acquired, err = store.Acquire(ctx, "task/gc", "b", leaseDuration)
+	So(err, ShouldBeNil)
+	So(acquired, ShouldBeFalse)
+
+	// extended by its holder
+	acquired, err = store.Acquire(ctx, "task/gc", "a", leaseDuration)
+	So(err, ShouldBeNil)
+	So(acquired, ShouldBeTrue)
+
+	holder, err := store.Holder(ctx, "task/gc")
+	So(err, ShouldBeNil)
+	So(holder, ShouldEqual, "a")
+
+	acquired, err = store.Acquire(ctx, "repo/alpine", "b", leaseDuration)
+	So(err, ShouldBeNil)
+	So(acquired, ShouldBeTrue)
+
+	holders, err := store.List(ctx, "task/")
+	So(err, ShouldBeNil)
+	So(holders, ShouldResemble, map[string]string{"task/gc": "a"})
+
+	// only released by its holder
+	So(store.Release(ctx, "task/gc", "b"), ShouldBeNil)
+
+	holder, err = store.Holder(ctx, "task/gc")
+	So(err, ShouldBeNil)
+	So(holder, ShouldEqual, "a")
+
+	So(store.Release(ctx, "task/gc", "a"), ShouldBeNil)
+
+	holder, err = store.Holder(ctx, "task/gc")
+	So(err, ShouldBeNil)
+	So(holder, ShouldBeEmpty)
+
+	// taken by another holder once expired
+	time.Sleep(leaseDuration + 100*time.Millisecond)
+
+	holder, err = store.Holder(ctx, "repo/alpine")
+	So(err, ShouldBeNil)
+	So(holder, ShouldBeEmpty)
+
+	acquired, err = store.Acquire(ctx, "repo/alpine", "a", leaseDuration)
+	So(err, ShouldBeNil)
+	So(acquired, ShouldBeTrue)
+
+	So(store.Release(ctx, "repo/alpine", "a"), ShouldBeNil)
+}
+
+func TestMember(t *testing.T) {
+	Convey("Members with the same name", t, func() {
+		first := newMember(t, "zot-0", t.Name())
+		So(first.Start(), ShouldBeNil)
+
+		second := newMember(t, "zot-0", t.Name())
+		So(second.Start(), ShouldEqual, zerr.ErrClusterMemberExists)
+
+		// the membership is free once the first member left
+		first.Stop()
+
+		So(second.Start(), ShouldBeNil)
+		second.Stop()
+	})
+
+	Convey("Background tasks", t, func() {
+		first := newMember(t, "zot-0", t.Name())
+		So(first.Start(), ShouldBeNil)
+
+		second := newMember(t, "zot-1", t.Name())
+		So(second.Start(), ShouldBeNil)
+
+		defer second.Stop()
+
+		// the tasks are owned once the leases are renewed
+		So(first.OwnsTask("gc"), ShouldBeFalse)
+		time.Sleep(leaseDuration)
+		So(first.OwnsTask("gc"), ShouldBeTrue)
+
+		So(second.OwnsTask("gc"), ShouldBeFalse)
+		time.Sleep(leaseDuration)
+		So(second.OwnsTask("gc"), ShouldBeFalse)
+
+		members, err := second.Members()
+		So(err, ShouldBeNil)
+		So(members, ShouldHaveLength, 2)
+		So(members[0].Name, ShouldEqual, "zot-0")
+		So(members[0].Address, ShouldEqual, "http://zot-0:8080")
+		So(members[0].Self, ShouldBeFalse)
+		So(members[0].Tasks, ShouldResemble, []string{"gc"})
+		So(members[1].Name, ShouldEqual, "zot-1")
+		So(members[1].Self, ShouldBeTrue)
+		So(members[1].Tasks, ShouldBeEmpty)
+
+		// the tasks are taken over once the member left
+		first.Stop()
+		time.Sleep(leaseDuration)
+		So(second.OwnsTask("gc"), ShouldBeTrue)
+
+		members, err = second.Members()
+		So(err, ShouldBeNil)
+		So(members, ShouldHaveLength, 1)
+		So(members[0].Tasks, ShouldResemble, []string{"gc"})
+	})
+
+	Convey("Repository writes", t, func() {
+		first := newMember(t, "zot-0", t.Name())
+		So(first.Start(), ShouldBeNil)
+
+		defer first.Stop()
+
+		second := newMember(t, "zot-1", t.Name())
+		So(second.Start(), ShouldBeNil)
+
+		defer second.Stop()
+
+		So(first.LockRepo("alpine"), ShouldBeNil)
+
+		locked := make(chan struct{})
+
+		go func() {
+			_ = second.LockRepo("alpine")
+			close(locked)
+		}()
+
+		// the lease is renewed while the first member writes, longer than its duration
+		select {
+		case <-locked:
+			t.Fatal("the repository is written by two members")
+		case <-time.After(2 * leaseDuration):
+		}
+
+		// other repositories aren't blocked
+		So(second.LockRepo("busybox"), ShouldBeNil)
+		second.UnlockRepo("busybox")
+
+		first.UnlockRepo("alpine")
+
+		select {
+		case <-locked:
+		case <-time.After(time.Second):
+			t.Fatal("the repository isn't unlocked")
+		}
+
+		second.UnlockRepo("alpine")
+	})
+
+	Convey("Dedupe of the image stores of a member", t, func() {
+		first := newMember(t, "zot-0", t.Name())
+		So(first.Start(), ShouldBeNil)
+
+		defer first.Stop()
+
+		second := newMember(t, "zot-1", t.Name())
+		So(second.Start(), ShouldBeNil)
+
+		defer second.Stop()
+
+		// two image stores of the first member dedupe at the same time
+		So(first.LockDedupe(), ShouldBeNil)
+		So(first.LockDedupe(), ShouldBeNil)
+
+		locked := make(chan struct{})
+
+		go func() {
+			_ = second.LockDedupe()
+			close(locked)
+		}()
+
+		// the lease is kept until both image stores are done
+		first.UnlockDedupe()
+
+		select {
+		case <-locked:
+			t.Fatal("the blobs are deduped by two members")
+		case <-time.After(2 * leaseDuration):
+		}
+
+		first.UnlockDedupe()
+
+		select {
+		case <-locked:
+		case <-time.After(time.Second):
+			t.Fatal("the dedupe isn't unlocked")
+		}
+
+		second.UnlockDedupe()
+	})
+
+	Convey("Repository writes without the leases", t, func() {
+		store := &failingLeaseStore{LeaseStore: cluster.NewMemoryLeaseStore(t.Name())}
+
+		member, err := cluster.NewMember(&config.ClusterConfig{
+			Name:          "zot-0",
+			Leases:        config.LeasesConfig{Driver: "memory"},
+			LeaseDuration: leaseDuration,
+		}, store, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+		So(member.Start(), ShouldBeNil)
+
+		defer member.Stop()
+
+		So(member.LockRepo("alpine"), ShouldBeNil)
+		So(member.LockDedupe(), ShouldBeNil)
+		So(member.CheckRepo("alpine"), ShouldBeNil)
+
+		// the writes in progress are aborted once the leases can't be renewed
+		store.failing.Store(true)
+		time.Sleep(leaseDuration)
+
+		So(errors.Is(member.CheckRepo("alpine"), zerr.ErrClusterLeaseLost), ShouldBeTrue)
+		So(errors.Is(member.CheckRepo("busybox"), zerr.ErrClusterLeaseLost), ShouldBeTrue)
+
+		member.UnlockDedupe()
+		member.UnlockRepo("alpine")
+
+		// and the new writes are denied
+		So(errors.Is(member.LockRepo("alpine"), zerr.ErrClusterLeaseUnavailable), ShouldBeTrue)
+		So(errors.Is(member.LockDedupe(), zerr.ErrClusterLeaseUnavailable), ShouldBeTrue)
+
+		store.failing.Store(false)
+
+		So(member.LockRepo("alpine"), ShouldBeNil)
+		So(member.CheckRepo("alpine"), ShouldBeNil)
+		member.UnlockRepo("alpine")
+	})
+
+	Convey("Upload sessions", t, func() {
+		first := newMember(t, "zot-0", t.Name())
+		So(first.Start(), ShouldBeNil)
+
+		defer first.Stop()
+
+		second := newMember(t, "zot-1", t.Name())
+		So(second.Start(), ShouldBeNil)
+
+		defer second.Stop()
+
+		So(first.ClaimUpload("alpine", "session"), ShouldBeNil)
+
+		owner, err := first.UploadOwner("alpine", "session")
+		So(err, ShouldBeNil)
+		So(owner, ShouldBeNil)
+
+		owner, err = second.UploadOwner("alpine", "session")
+		So(err, ShouldBeNil)
+		So(owner, ShouldNotBeNil)
+		So(*owner, ShouldResemble, first.Info())
+
+		owner, err = second.UploadOwner("alpine", "unknown")
+		So(err, ShouldBeNil)
+		So(owner, ShouldBeNil)
+
+		So(first.ReleaseUpload("alpine", "session"), ShouldBeNil)
+
+		owner, err = second.UploadOwner("alpine", "session")
+		So(err, ShouldBeNil)
+		So(owner, ShouldBeNil)
+	})
+}
+
+// failingLeaseStore can't be reached while it's failing.
+type failingLeaseStore struct {
+	cluster.LeaseStore
+	failing atomic.Bool
+}
+
+func (store *failingLeaseStore) Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	if store.failing.Load() {
+		return false, errLeaseStore
+	}
+
+	return store.LeaseStore.Acquire(ctx, key, holder, ttl)
+}
+
+func (store *failingLeaseStore) Release(ctx context.Context, key, holder string) error {
+	if store.failing.Load() {
+		return errLeaseStore
+	}
+
+	return store.LeaseStore.Release(ctx, key, holder)
+}
+
+func skipRedis(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("REDIS_URL") == "" {
+		t.Skip("Skipping testing without a redis server")
+	}
+}
@@ -0,0 +1,114 @@
+package cluster
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LeaseStore keeps the leases shared by the members of a cluster, a lease is held by a single member until it
+// releases it or it expires.
+type LeaseStore interface {
+	// Acquire takes the lease for the holder, or extends it if the holder already has it.
+	// It returns false if the lease is held by another holder.
+	Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+	// Release frees the lease if it's held by the holder.
+	Release(ctx context.Context, key, holder string) error
+	// Holder returns the holder of the lease, it's empty if the lease is free.
+	Holder(ctx context.Context, key string) (string, error)
+	// List returns the holders of the leases whose keys start with the prefix.
+	List(ctx context.Context, prefix string) (map[string]string, error)
+	Close() error
+}
+
+type memoryLease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+// MemoryLeaseStore keeps the leases in the memory of the process, it's only shared by the members running
+// in the same process, e.g. in tests.
+type MemoryLeaseStore struct {
+	leases map[string]memoryLease
+	lock   sync.Mutex
+}
+
+//nolint:gochecknoglobals // the members of the same process share the memory stores of the same prefix
+var (
+	memoryStores     = map[string]*MemoryLeaseStore{}
+	memoryStoresLock sync.Mutex
+)
+
+// NewMemoryLeaseStore returns the memory store of the key prefix, the same one is returned for the same prefix.
+func NewMemoryLeaseStore(keyPrefix string) *MemoryLeaseStore {
+	memoryStoresLock.Lock()
+	defer memoryStoresLock.Unlock()
+
+	store, ok := memoryStores[keyPrefix]
+	if !ok {
+		store = &MemoryLeaseStore{leases: map[string]memoryLease{}}
+		memoryStores[keyPrefix] = store
+	}
+
+	return store
+}
+
+func (store *MemoryLeaseStore) Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	now := time.Now()
+
+	if lease, ok := store.leases[key]; ok && lease.holder != holder && lease.expiresAt.After(now) {
+		return false, nil
+	}
+
+	store.leases[key] = memoryLease{holder: holder, expiresAt: now.Add(ttl)}
+
+	return true, nil
+}
+
+func (store *MemoryLeaseStore) Release(ctx context.Context, key, holder string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if lease, ok := store.leases[key]; ok && lease.holder == holder {
+		delete(store.leases, key)
+	}
+
+	return nil
+}
+
+func (store *MemoryLeaseStore) Holder(ctx context.Context, key string) (string, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	lease, ok := store.leases[key]
+	if !ok || !lease.expiresAt.After(time.Now()) {
+		return "", nil
+	}
+
+	return lease.holder, nil
+}
+
+func (store *MemoryLeaseStore) List(ctx context.Context, prefix string) (map[string]string, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	now := time.Now()
+	holders := map[string]string{}
+
+	for key, lease := range store.leases {
+		if strings.HasPrefix(key, prefix) && lease.expiresAt.After(now) {
+			holders[key] = lease.holder
+		}
+	}
+
+	return holders, nil
+}
+
+// Close keeps the leases, the other members of the process still use them.
+func (store *MemoryLeaseStore) Close() error {
+	return nil
+}
@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+const DefaultKeyPrefix = "zot"
+
+// the lease is taken if it's free and extended if the holder already has it.
+//
+//nolint:gochecknoglobals
+var acquireScript = goredis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+if holder == false or holder == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`)
+
+//nolint:gochecknoglobals
+var releaseScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLeaseStore keeps the leases in redis as keys expiring with the leases.
+type RedisLeaseStore struct {
+	client    goredis.UniversalClient
+	keyPrefix string
+}
+
+func NewRedisLeaseStore(client goredis.UniversalClient, keyPrefix string) *RedisLeaseStore {
+	if keyPrefix == "" {
+		keyPrefix = DefaultKeyPrefix
+	}
+
+	return &RedisLeaseStore{
+		client:    client,
+		keyPrefix: keyPrefix + ":leases:",
+	}
+}
+
+func GetRedisClient(url string) (*goredis.Client, error) {
+	const pingTimeout = 10 * time.Second
+
+	options, err := goredis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	client := goredis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (store *RedisLeaseStore) Acquire(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	acquired, err := acquireScript.Run(ctx, store.client, []string{store.keyPrefix + key},
+		holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return acquired == 1, nil
+}
+
+func (store *RedisLeaseStore) Release(ctx context.Context, key, holder string) error {
+	return releaseScript.Run(ctx, store.client, []string{store.keyPrefix + key}, holder).Err()
+}
+
+func (store *RedisLeaseStore) Holder(ctx context.Context, key string) (string, error) {
+	holder, err := store.client.Get(ctx, store.keyPrefix+key).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", nil
+	}
+
+	return holder, err
+}
+
+func (store *RedisLeaseStore) List(ctx context.Context, prefix string) (map[string]string, error) {
+	holders := map[string]string{}
+
+	iter := store.client.Scan(ctx, 0, store.keyPrefix+prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+
+		// the lease may expire between the scan and the read
+		holder, err := store.client.Get(ctx, key).Result()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		holders[strings.TrimPrefix(key, store.keyPrefix)] = holder
+	}
+
+	return holders, iter.Err()
+}
+
+func (store *RedisLeaseStore) Close() error {
+	return store.client.Close()
+}
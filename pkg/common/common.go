@@ -20,9 +20,13 @@ const (
 	clientKeyFilename  = "client.key"
 	caCertFilename     = "ca.crt"
 
-	CosignSignature   = "cosign"
-	CosignSigKey      = "dev.cosignproject.cosign/signature"
-	NotationSignature = "notation"
+	CosignSignature = "cosign"
+	CosignSigKey    = "dev.cosignproject.cosign/signature"
+	// the certificate, its chain and the Rekor bundle of the cosign keyless signatures.
+	CosignCertificateKey = "dev.sigstore.cosign/certificate"
+	CosignChainKey       = "dev.sigstore.cosign/chain"
+	CosignBundleKey      = "dev.sigstore.cosign/bundle"
+	NotationSignature    = "notation"
 	// same value as github.com/notaryproject/notation-go/registry.ArtifactTypeNotation (assert by internal test).
 	// reason used: to reduce zot minimal binary size (otherwise adds oras.land/oras-go/v2 deps).
 	ArtifactTypeNotation = "application/vnd.cncf.notary.signature"
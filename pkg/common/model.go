@@ -12,6 +12,13 @@ type PageInfo struct {
 type RepoInfo struct {
 	Summary        RepoSummary
 	ImageSummaries []ImageSummary `json:"images"`
+	Readme         *RepoReadme    `json:"readme"`
+}
+
+type RepoReadme struct {
+	Content     string    `json:"content"`
+	LastUpdated time.Time `json:"lastUpdated"`
+	UpdatedBy   string    `json:"updatedBy"`
 }
 
 type RepoSummary struct {
@@ -24,6 +31,7 @@ type RepoSummary struct {
 	IsBookmarked  bool         `json:"isBookmarked"`
 	StarCount     int          `json:"starCount"`
 	DownloadCount int          `json:"downloadCount"`
+	LastPulled    *time.Time   `json:"lastPulled,omitempty"`
 	NewestImage   ImageSummary `json:"newestImage"`
 }
 
@@ -40,6 +48,7 @@ type ImageSummary struct {
 	Manifests       []ManifestSummary         `json:"manifests"`
 	Size            string                    `json:"size"`
 	DownloadCount   int                       `json:"downloadCount"`
+	LastPulled      *time.Time                `json:"lastPulled,omitempty"`
 	LastUpdated     time.Time                 `json:"lastUpdated"`
 	Description     string                    `json:"description"`
 	IsSigned        bool                      `json:"isSigned"`
@@ -63,6 +72,7 @@ type ManifestSummary struct {
 	Platform        Platform                  `json:"platform"`
 	IsSigned        bool                      `json:"isSigned"`
 	DownloadCount   int                       `json:"downloadCount"`
+	LastPulled      *time.Time                `json:"lastPulled,omitempty"`
 	Layers          []LayerSummary            `json:"layers"`
 	History         []LayerHistory            `json:"history"`
 	Vulnerabilities ImageVulnerabilitySummary `json:"vulnerabilities"`
@@ -3,4 +3,7 @@ package constants
 const (
 	Debug                 = "/_zot/debug"
 	GQLPlaygroundEndpoint = Debug + "/graphql-playground"
+	PprofEndpoint         = Debug + "/pprof"
+	ExpvarEndpoint        = Debug + "/vars"
+	GoroutinesEndpoint    = Debug + "/goroutines"
 )
@@ -0,0 +1,74 @@
+package pprof
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimePprof "runtime/pprof"
+
+	"github.com/gorilla/mux"
+
+	"zotregistry.io/zot/pkg/api/config"
+	zcommon "zotregistry.io/zot/pkg/common"
+	debugCst "zotregistry.io/zot/pkg/debug/constants"
+	"zotregistry.io/zot/pkg/log"
+)
+
+// the goroutine dump has the full stack traces of the goroutines, including what they're blocked on.
+const goroutineDumpDebugLevel = 2
+
+// SetupPprofRoutes exposes the pprof profiles, the expvar variables and a dump of the goroutines to the admins,
+// if enabled, so that a running zot can be profiled without rebuilding it.
+func SetupPprofRoutes(conf *config.Config, router *mux.Router, log log.Logger) {
+	if !conf.IsDebugEnabled() {
+		return
+	}
+
+	log.Info().Msg("setting up debug routes")
+
+	if conf.HTTP.Debug.MutexProfileFraction > 0 {
+		runtime.SetMutexProfileFraction(conf.HTTP.Debug.MutexProfileFraction)
+	}
+
+	if conf.HTTP.Debug.BlockProfileRate > 0 {
+		runtime.SetBlockProfileRate(conf.HTTP.Debug.BlockProfileRate)
+	}
+
+	adminsOnly := zcommon.AuthzOnlyAdminsMiddleware(conf)
+
+	pprofRouter := router.PathPrefix(debugCst.PprofEndpoint).Subrouter()
+	pprofRouter.Use(adminsOnly)
+	// the pprof index links to the profiles relatively to its path, which ends with a slash
+	pprofRouter.HandleFunc("/", pprof.Index).Methods(http.MethodGet)
+	pprofRouter.HandleFunc("/cmdline", pprof.Cmdline).Methods(http.MethodGet)
+	pprofRouter.HandleFunc("/profile", pprof.Profile).Methods(http.MethodGet)
+	pprofRouter.HandleFunc("/symbol", pprof.Symbol).Methods(http.MethodGet, http.MethodPost)
+	pprofRouter.HandleFunc("/trace", pprof.Trace).Methods(http.MethodGet)
+	pprofRouter.HandleFunc("/{profile}", HandleProfile).Methods(http.MethodGet)
+
+	router.Handle(debugCst.ExpvarEndpoint, adminsOnly(expvar.Handler())).Methods(http.MethodGet)
+	router.Handle(debugCst.GoroutinesEndpoint, adminsOnly(http.HandlerFunc(HandleGoroutineDump))).
+		Methods(http.MethodGet)
+}
+
+// HandleProfile serves a runtime profile by name, e.g. heap, allocs, goroutine, mutex or block.
+func HandleProfile(response http.ResponseWriter, request *http.Request) {
+	profile := mux.Vars(request)["profile"]
+
+	if runtimePprof.Lookup(profile) == nil {
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	pprof.Handler(profile).ServeHTTP(response, request)
+}
+
+// HandleGoroutineDump writes the stack traces of all the goroutines, as in a panic,
+// e.g. to find the goroutines waiting on a lock.
+func HandleGoroutineDump(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	_ = runtimePprof.Lookup("goroutine").WriteTo(response, goroutineDumpDebugLevel)
+}
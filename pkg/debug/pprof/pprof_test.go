@@ -0,0 +1,99 @@
+package pprof_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	debugCst "zotregistry.io/zot/pkg/debug/constants"
+	"zotregistry.io/zot/pkg/test"
+)
+
+func TestDebugRoutes(t *testing.T) {
+	Convey("Verify the debug routes are served only to the admins", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		debugURL := baseURL + constants.RoutePrefix
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("admin", "admin") + "\n" +
+			test.GetCredString("user", "user"))
+		defer os.Remove(htpasswdPath)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{},
+			AdminPolicy:  config.Policy{Users: []string{"admin"}, Actions: []string{"read"}},
+		}
+
+		Convey("Debug routes disabled by default", func() {
+			ctlr := api.NewController(conf)
+
+			ctlrManager := test.NewControllerManager(ctlr)
+			ctlrManager.StartAndWait(port)
+			defer ctlrManager.StopServer()
+
+			resp, err := resty.R().SetBasicAuth("admin", "admin").Get(debugURL + debugCst.PprofEndpoint + "/")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Debug routes enabled", func() {
+			conf.HTTP.Debug = &config.DebugConfig{Enable: true, MutexProfileFraction: 5, BlockProfileRate: 1000}
+
+			ctlr := api.NewController(conf)
+
+			ctlrManager := test.NewControllerManager(ctlr)
+			ctlrManager.StartAndWait(port)
+			defer ctlrManager.StopServer()
+
+			for _, endpoint := range []string{
+				debugCst.PprofEndpoint + "/", debugCst.PprofEndpoint + "/heap", debugCst.ExpvarEndpoint,
+				debugCst.GoroutinesEndpoint,
+			} {
+				resp, err := resty.R().Get(debugURL + endpoint)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+				resp, err = resty.R().SetBasicAuth("user", "user").Get(debugURL + endpoint)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+				resp, err = resty.R().SetBasicAuth("admin", "admin").Get(debugURL + endpoint)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			}
+
+			resp, err := resty.R().SetBasicAuth("admin", "admin").Get(debugURL + debugCst.PprofEndpoint + "/")
+			So(err, ShouldBeNil)
+			So(string(resp.Body()), ShouldContainSubstring, "mutex")
+
+			resp, err = resty.R().SetBasicAuth("admin", "admin").
+				Get(debugURL + debugCst.PprofEndpoint + "/mutex?debug=1")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(string(resp.Body()), ShouldContainSubstring, "--- mutex:")
+
+			resp, err = resty.R().SetBasicAuth("admin", "admin").Get(debugURL + debugCst.PprofEndpoint + "/unknown")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = resty.R().SetBasicAuth("admin", "admin").Get(debugURL + debugCst.ExpvarEndpoint)
+			So(err, ShouldBeNil)
+			So(string(resp.Body()), ShouldContainSubstring, "memstats")
+
+			resp, err = resty.R().SetBasicAuth("admin", "admin").Get(debugURL + debugCst.GoroutinesEndpoint)
+			So(err, ShouldBeNil)
+			So(string(resp.Body()), ShouldContainSubstring, "goroutine ")
+			So(resp.Header().Get("Content-Type"), ShouldStartWith, "text/plain")
+		})
+	})
+}
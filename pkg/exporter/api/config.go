@@ -3,10 +3,16 @@
 
 package api
 
+import (
+	"zotregistry.io/zot/pkg/log"
+)
+
 // We export below types in order for cli package to be able to read it from configuration file.
 type LogConfig struct {
 	Level  string
 	Output string
+	// limit the repeated lines of the levels, keyed by level name, not limited if not set
+	Sampling map[string]log.LevelSampling
 }
 
 type MetricsConfig struct {
@@ -13,7 +13,7 @@ type Controller struct {
 }
 
 func NewController(cfg *Config) *Controller {
-	logger := log.NewLogger(cfg.Exporter.Log.Level, cfg.Exporter.Log.Output)
+	logger := log.NewLogger(cfg.Exporter.Log.Level, cfg.Exporter.Log.Output).WithSampling(cfg.Exporter.Log.Sampling)
 
 	return &Controller{Config: cfg, Log: logger}
 }
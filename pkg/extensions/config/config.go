@@ -21,12 +21,86 @@ type ExtensionConfig struct {
 	Mgmt    *MgmtConfig
 	APIKey  *APIKeyConfig
 	Trust   *ImageTrustConfig
+	Secrets *SecretsConfig
+	Events  *EventsConfig
+	Plugins *PluginsConfig
 }
 
-type ImageTrustConfig struct {
+// PluginsConfig runs the out-of-tree extensions, plugin processes which zot talks to over gRPC.
+type PluginsConfig struct {
 	BaseConfig `mapstructure:",squash"`
-	Cosign     bool
-	Notation   bool
+	Plugins    []PluginConfig
+}
+
+type PluginConfig struct {
+	Name string            // unique name of the plugin, its routes are served under /v2/_zot/ext/plugins/<name>
+	Path string            // path of the plugin executable
+	Args []string          // arguments of the plugin executable
+	Env  map[string]string // environment variables added to the environment of zot
+	// how long to wait for the plugin process to start serving, default is 10 seconds
+	StartTimeout time.Duration
+	// how long to wait for the plugin to handle a request or a hook, default is 1 minute
+	Timeout time.Duration
+}
+
+// EventsConfig publishes the registry events, e.g. image pushes and deletions, to the sinks.
+type EventsConfig struct {
+	BaseConfig `mapstructure:",squash"`
+	Sinks      []EventSinkConfig
+}
+
+type EventSinkConfig struct {
+	Type    string // http, nats or kafka
+	Address string // URL of the http receiver, NATS server URL or comma separated Kafka brokers
+	// the events are published to "<channel>.<event type>", e.g. "zot.image.updated", default is "zot",
+	// http sinks post every event to the same URL
+	Channel string
+	Format  string            // json (default) or cloudevents
+	Headers map[string]string // http headers, e.g. an authorization header expected by the receiver
+	Timeout time.Duration     // how long to wait for the sink to accept an event, default is 30 seconds
+	// credentials of the NATS server or the Kafka brokers (SASL plain)
+	Credentials *EventSinkCredentials
+}
+
+type EventSinkCredentials struct {
+	Username string
+	Password string
+	Token    string // NATS only
+}
+
+type ImageTrustConfig struct {
+	BaseConfig          `mapstructure:",squash"`
+	Cosign              bool
+	Notation            bool
+	NotationTrustPolicy string   // path of a notation trust policy document, replacing the default one
+	EnforcedRepos       []string // images of these repos can't be pulled unless they have a trusted signature
+	CosignKeyless       *CosignKeylessConfig
+	SignaturePolicies   []SignaturePolicy
+	// how often the signatures are verified again with the current keys and certificates, default is 2 hours
+	VerificationInterval time.Duration
+}
+
+// SignaturePolicy requires a trusted signature on the images of some repos before they can be tagged or pulled.
+type SignaturePolicy struct {
+	Repos          []string // glob patterns of the repos
+	SignatureTypes []string // types of the accepted signatures, "cosign" or "notation", any by default
+}
+
+// CosignKeylessConfig verifies the cosign signatures made with short-lived certificates issued by Fulcio.
+type CosignKeylessConfig struct {
+	FulcioRoots    string // path of the PEM root and intermediate certificates of Fulcio
+	RekorPublicKey string // path of the PEM public key of Rekor, the bundles of the signatures are verified with it
+	RekorURL       string // Rekor instance in which the signatures without bundle are looked up
+	CTLogPublicKey string // path of the PEM public key of the certificate transparency log, the SCTs are verified with it
+	Identities     []CosignKeylessIdentity
+}
+
+// CosignKeylessIdentity is a trusted identity of the certificates, matched exactly or with regular expressions.
+type CosignKeylessIdentity struct {
+	Issuer        string
+	IssuerRegExp  string
+	Subject       string
+	SubjectRegExp string
 }
 
 type APIKeyConfig struct {
@@ -40,17 +114,113 @@ type MgmtConfig struct {
 type LintConfig struct {
 	BaseConfig           `mapstructure:",squash"`
 	MandatoryAnnotations []string
+	// checked on the images pushed to the repos matching the rules
+	Rules []LintRule
+	// Rego policies evaluated on the images pushed
+	Policy *LintPolicyConfig
+}
+
+// LintPolicyConfig evaluates the pushed manifests, their configs and referrers against Rego policies.
+type LintPolicyConfig struct {
+	Paths []string // Rego files, or directories of them
+	Query string   // returning the denial messages, default is "data.zot.admission.deny"
+	Mode  string   // "enforce" rejects the images denied by the policies, "warn" only logs them
+}
+
+// LintRule is a set of policies applied to the images pushed to the repos matching its patterns.
+type LintRule struct {
+	Repos               []string // glob patterns, default is all repos
+	Mode                string   // "enforce" rejects the images violating the rule, "warn" only logs them
+	RequiredLabels      []string // labels required in the image config
+	MaxLayers           int
+	MaxImageSize        int64    // sum of the config and layers sizes in bytes
+	ForbiddenBaseImages []string // glob patterns of the base image names, from the base.name annotation
+	RequiredPlatforms   []string // "os/arch[/variant]" required in the pushed indexes
+}
+
+type SecretsConfig struct {
+	BaseConfig `mapstructure:",squash"`
+	// reject pushed images in which high confidence secrets are found
+	RejectPush bool
+	// files bigger than this are not scanned, default is 1MiB
+	MaxFileSize int64
 }
 
 type SearchConfig struct {
 	BaseConfig `mapstructure:",squash"`
 	// CVE search
 	CVE *CVEConfig
+	// how long results of expensive queries (global search, base/derived images) are cached, disabled if not set
+	ResultCacheTTL time.Duration
+	// automatic persisted queries and allow list of the queries accepted by the GraphQL server
+	PersistedQueries *PersistedQueriesConfig
+	// peer registries the federated search queries are fanned out to
+	Federation *FederationConfig
+	// record the pulls of the images for the download statistics queries, disabled if not set
+	DownloadStats *DownloadStatsConfig
+}
+
+type DownloadStatsConfig struct {
+	Retention time.Duration // how long the daily statistics are kept, default is 90 days
+	// how long the hourly statistics are kept before the days are rolled up to daily totals, default is 7 days
+	HourlyRetention time.Duration
+}
+
+// FederationLocalSource is the name the results of the registry itself are attributed to in federated searches.
+const FederationLocalSource = "local"
+
+type FederationConfig struct {
+	Peers   []FederationPeerConfig
+	Timeout time.Duration // how long to wait for each peer, default is 10 seconds
+}
+
+type FederationPeerConfig struct {
+	Name    string            // the results of the peer are attributed to this name
+	URL     string            // base URL of the peer registry, e.g. "https://zot.eu.example.com"
+	Headers map[string]string // e.g. an authorization header for a service account of the peer
+	// send the Authorization header of the user instead of the configured one, so the peer applies its access
+	// control to the user, otherwise the results of the peer are filtered through the local access control
+	ForwardCredentials bool
+}
+
+type PersistedQueriesConfig struct {
+	// how many queries registered by clients through automatic persisted queries are kept, default is 100
+	CacheSize int
+	// path of a JSON file mapping the sha256 hashes of the persisted queries to the queries
+	AllowList string
+	// reject the queries which are not in the allow list, clients can't register new queries
+	AllowListOnly bool
 }
 
 type CVEConfig struct {
 	UpdateInterval time.Duration // should be 2 hours or more, if not specified default be kept as 24 hours
 	Trivy          *TrivyConfig
+	// notify the images newly affected by CVEs after each CVE DB update
+	Notifications *CVENotificationsConfig
+	// periodically rescan the stored images, so the scan results are refreshed before being queried
+	Rescan *CVERescanConfig
+	// repos whose images are scanned, every repo is scanned if not set
+	Repositories *CVERepositoriesConfig
+}
+
+type CVERepositoriesConfig struct {
+	Include []string // glob patterns of the repos to scan, every repo if empty
+	Exclude []string // glob patterns of the repos not to scan, takes precedence over Include
+}
+
+type CVERescanConfig struct {
+	Interval   time.Duration // default is the CVE DB update interval
+	PulledOnly bool          // only rescan the images pulled since the previous rescan
+}
+
+type CVENotificationsConfig struct {
+	Webhooks []WebhookConfig
+	Severity string // only CVEs of this severity or higher are notified, default is "HIGH"
+}
+
+type WebhookConfig struct {
+	URL     string
+	Headers map[string]string // e.g. an authorization header expected by the receiver
 }
 
 type TrivyConfig struct {
@@ -61,17 +231,47 @@ type TrivyConfig struct {
 type MetricsConfig struct {
 	BaseConfig `mapstructure:",squash"`
 	Prometheus *PrometheusConfig
+	OTLP       *OTLPConfig
+	RepoLabels *RepoLabelsConfig
 }
 
 type PrometheusConfig struct {
 	Path string // default is "/metrics"
 }
 
+// OTLPConfig pushes the metrics to an OpenTelemetry collector with OTLP/HTTP, in addition to the Prometheus endpoint.
+type OTLPConfig struct {
+	Endpoint string            // e.g. "http://otel-collector:4318/v1/metrics"
+	Interval time.Duration     // default is 1m
+	Headers  map[string]string // e.g. an authorization header expected by the collector
+	// added to the service.name, service.version, service.instance.id and zot.storage.root_directory attributes
+	ResourceAttributes map[string]string
+}
+
+// RepoLabelsConfig limits the cardinality of the per-repo traffic metrics.
+type RepoLabelsConfig struct {
+	// the most requested repos have their own label, the others are aggregated in the "other" label,
+	// default is 0 meaning every repo has its own label
+	Max int
+}
+
 type ScrubConfig struct {
 	BaseConfig `mapstructure:",squash"`
 	Interval   time.Duration
+	Repair     *ScrubRepairConfig
+}
+
+// ScrubRepairConfig enables replacing the corrupted blobs found by scrub with valid copies.
+type ScrubRepairConfig struct {
+	Sources          []string // tried in order, "dedupe", "replica" and "sync", default is all of them
+	ReplicaDirectory string   // root directory of a replica of the storage, with the same repos layout
 }
 
 type UIConfig struct {
 	BaseConfig `mapstructure:",squash"`
+	Directory  string            // serve an alternative UI bundle from this directory instead of the embedded one
+	PathPrefix string            // path the UI is served under, default is "/"
+	Routes     []string          // client side routes served the index.html, default is the routes of the zot UI
+	APIBaseURL string            // base URL of the zot API used by the UI, default is the zot instance serving it
+	Branding   map[string]string // custom values passed to the UI, e.g. the title or the logo of a distribution
 }
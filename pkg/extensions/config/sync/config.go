@@ -15,7 +15,16 @@ type Credentials struct {
 type Config struct {
 	Enable          *bool
 	CredentialsFile string
-	Registries      []RegistryConfig
+	// download rate limit of all the registries, in bytes per second, not limited if 0
+	MaxDownloadRate int64
+	// the sync events, e.g. tag collisions, are posted to these webhooks
+	Webhooks   []WebhookConfig
+	Registries []RegistryConfig
+}
+
+type WebhookConfig struct {
+	URL     string
+	Headers map[string]string // e.g. an authorization header expected by the receiver
 }
 
 type RegistryConfig struct {
@@ -27,17 +36,94 @@ type RegistryConfig struct {
 	CertDir      string
 	MaxRetries   *int
 	RetryDelay   *time.Duration
-	OnlySigned   *bool
+	// the retry delay is multiplied by RetryBackoff after each retry, up to MaxRetryDelay, constant if not set
+	RetryBackoff  *float64
+	MaxRetryDelay *time.Duration
+	Quarantine    *QuarantineConfig
+	OnlySigned    *bool
+	// only the images with a signature verified by these keys or certificates are synced
+	SignatureVerification *SignatureVerificationConfig
+	References            *ReferencesConfig
+	// download rate limit of this registry, in bytes per second, not limited if 0
+	MaxDownloadRate int64
+	// default collision policy of the content rules: overwrite (default), skip or keepBoth
+	CollisionPolicy string
+	// suffix of the tags the upstream images are synced as with the keepBoth collision policy, default is "-upstream"
+	CollisionSuffix string
+	// rewrite the upstream repos into local namespaces, instead of the destinations of the content rules
+	Mappings []MappingConfig
+	// the upstream repos, tags and images matching these rules are not synced, even if they match the content rules
+	Exclusions *ExclusionsConfig
+}
+
+// ExclusionsConfig denies the upstream repos, tags and images matching any of its rules,
+// they're evaluated before downloading the images.
+type ExclusionsConfig struct {
+	// glob patterns of the upstream repos, e.g. "library/nightly-*"
+	Repos []string
+	// regexes of the tags, e.g. "^nightly-.*"
+	Tags []string
+	// media types of the manifests, configs or layers, e.g. "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip"
+	MediaTypes []string
+	// images larger than this size in bytes, the sum of the manifests, configs and layers of every platform,
+	// not limited if 0
+	MaxImageSize int64
+}
+
+// MappingConfig maps the upstream repos matching Source to Destination, the wildcards ('*' for a path element,
+// '**' for any number of path elements) of Source are replaced in order by the ones of Destination,
+// e.g. docker.io/library/* to mirrors/dockerhub/*.
+type MappingConfig struct {
+	Source      string
+	Destination string
+}
+
+// QuarantineConfig skips the periodic sync of a repo for Duration after it failed Failures consecutive times.
+type QuarantineConfig struct {
+	Failures int
+	Duration time.Duration
+}
+
+// SignatureVerificationConfig holds the keys and identities the signatures of the upstream images are verified with,
+// an image is synced if one of its cosign or notation signatures is verified.
+type SignatureVerificationConfig struct {
+	// paths of the PEM encoded public keys verifying the cosign signatures
+	CosignPublicKeys []string
+	// paths of the PEM encoded CA certificates verifying the notation signatures
+	NotationCertificates []string
+	// trusted identities of the notation signers, e.g. "x509.subject: C=US, ST=WA, O=acme", any signer if empty
+	NotationIdentities []string
+}
+
+// ReferencesConfig selects the references (signatures, SBOMs, attestations and other artifacts)
+// synced along with the images, by default every reference is synced.
+type ReferencesConfig struct {
+	Enable *bool
+	// reference types to sync, e.g. CosignSignature, OCIReference, OrasReference, all of them if empty
+	Types []string
+	// only the OCI referrers with these artifact types are synced, all of them if empty
+	ArtifactTypes []string
 }
 
 type Content struct {
 	Prefix      string
 	Tags        *Tags
 	Destination string `mapstructure:",omitempty"`
-	StripPrefix bool
+	// the repos are also synced into these destinations (fan-out)
+	Destinations []string `mapstructure:",omitempty"`
+	StripPrefix  bool
+	// what to do when a synced tag already exists locally with a different image: overwrite, skip or keepBoth,
+	// e.g. when multiple upstreams are merged into the same local namespace, default is the registry policy
+	CollisionPolicy string
+	// the repos matching this rule are synced with their own polling interval instead of the registry one
+	PollInterval time.Duration
+	// priority of the periodic sync of the repos matching this rule: low, medium (default) or high
+	Priority string
 }
 
 type Tags struct {
 	Regex  *string
 	Semver *bool
+	// only sync the semver tags satisfying the constraint, e.g. ">=1.20 <2.0"
+	SemverConstraint *string
 }
@@ -0,0 +1,17 @@
+package constants
+
+// event sink types.
+const (
+	HTTPSink  = "http"
+	NATSSink  = "nats"
+	KafkaSink = "kafka"
+)
+
+// serialization formats of the events.
+const (
+	JSONFormat        = "json"
+	CloudEventsFormat = "cloudevents"
+)
+
+// the events are published to "<channel>.<event type>" by default.
+const DefaultChannel = "zot"
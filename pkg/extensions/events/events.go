@@ -0,0 +1,322 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/events/constants"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	ImageUpdatedEventType = "image.updated"
+	ImageDeletedEventType = "image.deleted"
+	RepoDeletedEventType  = "repo.deleted"
+	ImageDeniedEventType  = "image.denied"
+	// usage alerts, published when a repo crosses a threshold of its quota or a storage runs out of space
+	RepoQuotaThresholdEventType  = "repo.quota.threshold"
+	StorageFreeSpaceLowEventType = "storage.freespace.low"
+	// a signature became trusted or untrusted when it was verified again
+	SignatureStatusChangedEventType = "signature.status.changed"
+
+	defaultTimeout = 30 * time.Second
+	// the events recorded while this many events wait to be published are dropped
+	eventQueueSize = 1000
+
+	jsonContentType        = "application/json"
+	cloudEventsContentType = "application/cloudevents+json"
+	cloudEventsSpecVersion = "1.0"
+	// the CloudEvents types are the event types prefixed by the reverse DNS name of zot
+	cloudEventsTypePrefix = "io.zotregistry."
+)
+
+// Event is a change of the registry content, or an alert about its usage.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"` // the registry the event happened on
+	Repo      string    `json:"repo"`
+	Reference string    `json:"reference"` // the tag or digest the image was pushed or deleted by
+	Digest    string    `json:"digest"`
+	MediaType string    `json:"mediaType"`
+	User      string    `json:"user,omitempty"`   // the user who pushed or deleted the image, if authenticated
+	Reason    string    `json:"reason,omitempty"` // why the request was denied
+	// the usage alerts details, the repo size or free space in bytes compared to the quota or minimum free space
+	Usage     int64  `json:"usage,omitempty"`
+	Limit     int64  `json:"limit,omitempty"`
+	Threshold int    `json:"threshold,omitempty"` // the percentage of the quota crossed
+	Storage   string `json:"storage,omitempty"`   // the root directory running out of space
+	// the signature of the image whose status changed, its type, the digest of its manifest, its signer and status
+	SignatureType string `json:"signatureType,omitempty"`
+	Signature     string `json:"signature,omitempty"`
+	Signer        string `json:"signer,omitempty"`
+	Status        string `json:"status,omitempty"`
+}
+
+// cloudEvent is the structured mode JSON representation of an event, as defined by the CloudEvents spec.
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Time            time.Time `json:"time"`
+	Subject         string    `json:"subject"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Event     `json:"data"`
+}
+
+// Message is an event serialized for a sink.
+type Message struct {
+	Key         string // events with the same key, i.e. of the same repo, are kept in order by the kafka sinks
+	ContentType string
+	Body        []byte
+}
+
+/*
+Recorder publishes the registry events to the configured sinks, the events are queued and published
+in the background, so that they don't slow down the requests. An event is published to each sink once,
+the events a sink doesn't accept are logged and dropped.
+*/
+type Recorder struct {
+	sinks  []sinkPublisher
+	source string
+	queue  chan Event
+	done   chan struct{}
+	closed bool
+	lock   *sync.RWMutex
+	log    log.Logger
+}
+
+type sinkPublisher struct {
+	sink   Sink
+	config extconf.EventSinkConfig
+}
+
+// NewRecorder connects to the sinks, source identifies the registry in the events, e.g. its address.
+func NewRecorder(config *extconf.EventsConfig, source string, log log.Logger) (*Recorder, error) {
+	recorder := &Recorder{
+		source: source,
+		queue:  make(chan Event, eventQueueSize),
+		done:   make(chan struct{}),
+		lock:   &sync.RWMutex{},
+		log:    log,
+	}
+
+	for _, sinkConfig := range config.Sinks {
+		sink, err := NewSink(sinkConfig, log)
+		if err != nil {
+			for _, publisher := range recorder.sinks {
+				publisher.sink.Close()
+			}
+
+			return nil, err
+		}
+
+		recorder.sinks = append(recorder.sinks, sinkPublisher{sink: sink, config: sinkConfig})
+	}
+
+	go recorder.run()
+
+	return recorder, nil
+}
+
+func (recorder *Recorder) ImageUpdated(repo, reference, digest, mediaType, user string) {
+	recorder.record(ImageUpdatedEventType, repo, reference, digest, mediaType, user)
+}
+
+func (recorder *Recorder) ImageDeleted(repo, reference, digest, mediaType, user string) {
+	recorder.record(ImageDeletedEventType, repo, reference, digest, mediaType, user)
+}
+
+// RepoDeleted records the deletion of a whole repo with the mgmt extension.
+func (recorder *Recorder) RepoDeleted(repo, user string) {
+	recorder.record(RepoDeletedEventType, repo, "", "", "", user)
+}
+
+// ImageDenied records the violation of a policy by the push or pull of an image, for auditing.
+func (recorder *Recorder) ImageDenied(repo, reference, digest, mediaType, user, reason string) {
+	recorder.recordEvent(Event{
+		Type:      ImageDeniedEventType,
+		Repo:      repo,
+		Reference: reference,
+		Digest:    digest,
+		MediaType: mediaType,
+		User:      user,
+		Reason:    reason,
+	})
+}
+
+// RepoQuotaThresholdCrossed records a repo getting over a percentage of its quota.
+func (recorder *Recorder) RepoQuotaThresholdCrossed(repo string, size, quota int64, threshold int) {
+	recorder.recordEvent(Event{
+		Type:      RepoQuotaThresholdEventType,
+		Repo:      repo,
+		Usage:     size,
+		Limit:     quota,
+		Threshold: threshold,
+	})
+}
+
+// StorageFreeSpaceLow records the free space of a storage dropping below its minimum.
+func (recorder *Recorder) StorageFreeSpaceLow(rootDir string, free, minFree int64) {
+	recorder.recordEvent(Event{
+		Type:    StorageFreeSpaceLowEventType,
+		Usage:   free,
+		Limit:   minFree,
+		Storage: rootDir,
+	})
+}
+
+// SignatureStatusChanged records a signature which became trusted, untrusted or expired when it was verified
+// again, manifestDigest is the digest of the signed image.
+func (recorder *Recorder) SignatureStatusChanged(repo, manifestDigest, signatureType, signatureDigest, signer,
+	status string,
+) {
+	recorder.recordEvent(Event{
+		Type:          SignatureStatusChangedEventType,
+		Repo:          repo,
+		Digest:        manifestDigest,
+		SignatureType: signatureType,
+		Signature:     signatureDigest,
+		Signer:        signer,
+		Status:        status,
+	})
+}
+
+func (recorder *Recorder) record(eventType, repo, reference, digest, mediaType, user string) {
+	recorder.recordEvent(Event{
+		Type:      eventType,
+		Repo:      repo,
+		Reference: reference,
+		Digest:    digest,
+		MediaType: mediaType,
+		User:      user,
+	})
+}
+
+func (recorder *Recorder) recordEvent(event Event) {
+	event.ID = uuid.NewString()
+	event.Time = time.Now().UTC()
+	event.Source = recorder.source
+
+	recorder.lock.RLock()
+	defer recorder.lock.RUnlock()
+
+	if recorder.closed {
+		return
+	}
+
+	select {
+	case recorder.queue <- event:
+	default:
+		recorder.log.Warn().Str("type", event.Type).Str("repo", event.Repo).Str("reference", event.Reference).
+			Msg("events: too many events waiting to be published, dropping event")
+	}
+}
+
+func (recorder *Recorder) run() {
+	defer close(recorder.done)
+
+	for event := range recorder.queue {
+		for _, publisher := range recorder.sinks {
+			if err := recorder.publish(publisher, event); err != nil {
+				recorder.log.Error().Err(err).Str("sink", publisher.config.Type).
+					Str("address", publisher.config.Address).Str("type", event.Type).Str("repo", event.Repo).
+					Str("reference", event.Reference).Msg("events: unable to publish event")
+			}
+		}
+	}
+}
+
+func (recorder *Recorder) publish(publisher sinkPublisher, event Event) error {
+	message, err := EncodeEvent(event, publisher.config.Format)
+	if err != nil {
+		return err
+	}
+
+	timeout := publisher.config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return publisher.sink.Publish(ctx, GetTopic(publisher.config.Channel, event.Type), message)
+}
+
+// Close publishes the queued events and disconnects from the sinks, the events recorded afterwards are dropped.
+func (recorder *Recorder) Close() {
+	recorder.lock.Lock()
+
+	if recorder.closed {
+		recorder.lock.Unlock()
+
+		return
+	}
+
+	recorder.closed = true
+	close(recorder.queue)
+	recorder.lock.Unlock()
+
+	<-recorder.done
+
+	for _, publisher := range recorder.sinks {
+		if err := publisher.sink.Close(); err != nil {
+			recorder.log.Error().Err(err).Str("sink", publisher.config.Type).Msg("events: unable to close sink")
+		}
+	}
+}
+
+// GetTopic returns the topic (NATS subject or Kafka topic) events of a type are published to.
+func GetTopic(channel, eventType string) string {
+	if channel == "" {
+		channel = constants.DefaultChannel
+	}
+
+	return channel + "." + eventType
+}
+
+// EncodeEvent serializes an event as plain JSON or as a CloudEvent.
+func EncodeEvent(event Event, format string) (Message, error) {
+	message := Message{Key: event.Repo, ContentType: jsonContentType}
+
+	var (
+		body []byte
+		err  error
+	)
+
+	if format == constants.CloudEventsFormat {
+		message.ContentType = cloudEventsContentType
+
+		body, err = json.Marshal(cloudEvent{
+			SpecVersion:     cloudEventsSpecVersion,
+			ID:              event.ID,
+			Source:          event.Source,
+			Type:            cloudEventsTypePrefix + event.Type,
+			Time:            event.Time,
+			Subject:         event.Repo + "@" + event.Digest,
+			DataContentType: jsonContentType,
+			Data:            event,
+		})
+	} else {
+		body, err = json.Marshal(event)
+	}
+
+	if err != nil {
+		return Message{}, err
+	}
+
+	message.Body = body
+
+	return message, nil
+}
@@ -0,0 +1,390 @@
+//go:build events
+// +build events
+
+package events_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/events"
+	"zotregistry.io/zot/pkg/extensions/events/constants"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+type receivedEvent struct {
+	topic       string
+	contentType string
+	body        []byte
+}
+
+// eventReceiver is an http sink recording the events it receives.
+type eventReceiver struct {
+	server *httptest.Server
+	events chan receivedEvent
+}
+
+func newEventReceiver(status int) *eventReceiver {
+	receiver := &eventReceiver{events: make(chan receivedEvent, 10)}
+
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		receiver.events <- receivedEvent{
+			topic:       r.Header.Get("X-Zot-Event-Topic"),
+			contentType: r.Header.Get("Content-Type"),
+			body:        body,
+		}
+
+		w.WriteHeader(status)
+	}))
+
+	return receiver
+}
+
+func (receiver *eventReceiver) next() receivedEvent {
+	select {
+	case event := <-receiver.events:
+		return event
+	case <-time.After(10 * time.Second):
+		return receivedEvent{}
+	}
+}
+
+func TestEventsExtension(t *testing.T) {
+	Convey("Pushed and deleted images are published", t, func() {
+		receiver := newEventReceiver(http.StatusOK)
+		defer receiver.server.Close()
+
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		enable := true
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Events: &extconf.EventsConfig{
+				BaseConfig: extconf.BaseConfig{Enable: &enable},
+				Sinks: []extconf.EventSinkConfig{
+					{
+						Type:    constants.HTTPSink,
+						Address: receiver.server.URL,
+						Channel: "registry",
+						Format:  constants.CloudEventsFormat,
+					},
+				},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		image := CreateRandomImage()
+
+		err := UploadImage(image, baseURL, "repo", "tag")
+		So(err, ShouldBeNil)
+
+		received := receiver.next()
+		So(received.topic, ShouldEqual, "registry."+events.ImageUpdatedEventType)
+		So(received.contentType, ShouldEqual, "application/cloudevents+json")
+
+		var cloudEvent map[string]interface{}
+		err = json.Unmarshal(received.body, &cloudEvent)
+		So(err, ShouldBeNil)
+		So(cloudEvent["specversion"], ShouldEqual, "1.0")
+		So(cloudEvent["type"], ShouldEqual, "io.zotregistry.image.updated")
+		So(cloudEvent["subject"], ShouldEqual, "repo@"+image.DigestStr())
+
+		data, ok := cloudEvent["data"].(map[string]interface{})
+		So(ok, ShouldBeTrue)
+		So(data["repo"], ShouldEqual, "repo")
+		So(data["reference"], ShouldEqual, "tag")
+		So(data["digest"], ShouldEqual, image.DigestStr())
+
+		resp, err := resty.R().Delete(baseURL + "/v2/repo/manifests/" + image.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		received = receiver.next()
+		So(received.topic, ShouldEqual, "registry."+events.ImageDeletedEventType)
+
+		err = json.Unmarshal(received.body, &cloudEvent)
+		So(err, ShouldBeNil)
+		So(cloudEvent["type"], ShouldEqual, "io.zotregistry.image.deleted")
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	Convey("Test publishing events to sinks", t, func() {
+		logger := log.NewLogger("debug", "")
+
+		_, err := events.NewSink(extconf.EventSinkConfig{Type: "unknown"}, logger)
+		So(errors.Is(err, zerr.ErrEventSinkUnknownType), ShouldBeTrue)
+
+		_, err = events.NewRecorder(&extconf.EventsConfig{
+			Sinks: []extconf.EventSinkConfig{{Type: constants.HTTPSink}, {Type: "unknown"}},
+		}, "zot", logger)
+		So(errors.Is(err, zerr.ErrEventSinkUnknownType), ShouldBeTrue)
+
+		So(events.GetTopic("", events.ImageUpdatedEventType), ShouldEqual, "zot.image.updated")
+
+		message, err := events.EncodeEvent(events.Event{Type: events.ImageDeletedEventType, Repo: "repo"},
+			constants.JSONFormat)
+		So(err, ShouldBeNil)
+		So(message.Key, ShouldEqual, "repo")
+		So(message.ContentType, ShouldEqual, "application/json")
+
+		var event events.Event
+		err = json.Unmarshal(message.Body, &event)
+		So(err, ShouldBeNil)
+		So(event.Type, ShouldEqual, events.ImageDeletedEventType)
+
+		Convey("A failing sink doesn't prevent publishing to the others", func() {
+			failing := newEventReceiver(http.StatusInternalServerError)
+			defer failing.server.Close()
+
+			receiver := newEventReceiver(http.StatusNoContent)
+			defer receiver.server.Close()
+
+			recorder, err := events.NewRecorder(&extconf.EventsConfig{
+				Sinks: []extconf.EventSinkConfig{
+					{Type: constants.HTTPSink, Address: failing.server.URL},
+					{Type: constants.HTTPSink, Address: receiver.server.URL, Headers: map[string]string{"X-Key": "key"}},
+				},
+			}, "zot.example.com", logger)
+			So(err, ShouldBeNil)
+
+			recorder.ImageUpdated("repo", "1.0", "sha256:1234", "application/vnd.oci.image.manifest.v1+json", "alice")
+			recorder.Close()
+
+			// the events recorded after closing are dropped
+			recorder.ImageDeleted("repo", "1.0", "sha256:1234", "application/vnd.oci.image.manifest.v1+json", "")
+			recorder.Close()
+
+			So(failing.next().topic, ShouldEqual, "zot.image.updated")
+
+			received := receiver.next()
+			So(received.topic, ShouldEqual, "zot.image.updated")
+			So(received.contentType, ShouldEqual, "application/json")
+
+			err = json.Unmarshal(received.body, &event)
+			So(err, ShouldBeNil)
+			So(event.ID, ShouldNotBeEmpty)
+			So(event.Source, ShouldEqual, "zot.example.com")
+			So(event.User, ShouldEqual, "alice")
+			So(len(receiver.events), ShouldEqual, 0)
+		})
+
+		Convey("The denied images are recorded with the reason", func() {
+			receiver := newEventReceiver(http.StatusNoContent)
+			defer receiver.server.Close()
+
+			recorder, err := events.NewRecorder(&extconf.EventsConfig{
+				Sinks: []extconf.EventSinkConfig{{Type: constants.HTTPSink, Address: receiver.server.URL}},
+			}, "zot", logger)
+			So(err, ShouldBeNil)
+
+			recorder.ImageDenied("repo", "1.0", "sha256:1234", "application/vnd.oci.image.manifest.v1+json", "alice",
+				"unsigned")
+			recorder.Close()
+
+			received := receiver.next()
+			So(received.topic, ShouldEqual, "zot.image.denied")
+
+			err = json.Unmarshal(received.body, &event)
+			So(err, ShouldBeNil)
+			So(event.Type, ShouldEqual, events.ImageDeniedEventType)
+			So(event.Reference, ShouldEqual, "1.0")
+			So(event.User, ShouldEqual, "alice")
+			So(event.Reason, ShouldEqual, "unsigned")
+		})
+
+		Convey("The changes of the signatures status are recorded", func() {
+			receiver := newEventReceiver(http.StatusNoContent)
+			defer receiver.server.Close()
+
+			recorder, err := events.NewRecorder(&extconf.EventsConfig{
+				Sinks: []extconf.EventSinkConfig{{Type: constants.HTTPSink, Address: receiver.server.URL}},
+			}, "zot", logger)
+			So(err, ShouldBeNil)
+
+			recorder.SignatureStatusChanged("repo", "sha256:1234", "cosign", "sha256:5678", "alice", "trusted")
+			recorder.Close()
+
+			received := receiver.next()
+			So(received.topic, ShouldEqual, "zot.signature.status.changed")
+
+			err = json.Unmarshal(received.body, &event)
+			So(err, ShouldBeNil)
+			So(event.Type, ShouldEqual, events.SignatureStatusChangedEventType)
+			So(event.Digest, ShouldEqual, "sha256:1234")
+			So(event.SignatureType, ShouldEqual, "cosign")
+			So(event.Signature, ShouldEqual, "sha256:5678")
+			So(event.Signer, ShouldEqual, "alice")
+			So(event.Status, ShouldEqual, "trusted")
+		})
+
+		Convey("Events are published to NATS subjects", func() {
+			server := newFakeNATSServer(t)
+
+			recorder, err := events.NewRecorder(&extconf.EventsConfig{
+				Sinks: []extconf.EventSinkConfig{
+					{
+						Type:        constants.NATSSink,
+						Address:     server.url,
+						Format:      constants.CloudEventsFormat,
+						Credentials: &extconf.EventSinkCredentials{Token: "token"},
+					},
+				},
+			}, "zot", logger)
+			So(err, ShouldBeNil)
+
+			recorder.ImageUpdated("repo", "1.0", "sha256:1234", "application/vnd.oci.image.manifest.v1+json", "")
+			recorder.Close()
+
+			msg := server.next()
+			So(msg.subject, ShouldEqual, "zot.image.updated")
+			So(msg.headers, ShouldContainSubstring, "application/cloudevents+json")
+			So(msg.payload, ShouldContainSubstring, `"type":"io.zotregistry.image.updated"`)
+		})
+
+		Convey("Events which can't be written to Kafka are dropped", func() {
+			sink, err := events.NewSink(extconf.EventSinkConfig{
+				Type:        constants.KafkaSink,
+				Address:     "127.0.0.1:" + test.GetFreePort() + ", ",
+				Credentials: &extconf.EventSinkCredentials{Username: "user", Password: "pass"},
+			}, logger)
+			So(err, ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			err = sink.Publish(ctx, "zot.image.updated", message)
+			So(err, ShouldNotBeNil)
+			So(sink.Close(), ShouldBeNil)
+		})
+	})
+}
+
+type natsMessage struct {
+	subject string
+	headers string
+	payload string
+}
+
+// fakeNATSServer speaks enough of the NATS protocol to receive the published messages.
+type fakeNATSServer struct {
+	url      string
+	messages chan natsMessage
+}
+
+func newFakeNATSServer(t *testing.T) *fakeNATSServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeNATSServer{
+		url:      "nats://" + listener.Addr().String(),
+		messages: make(chan natsMessage, 10),
+	}
+
+	go func() {
+		var wg sync.WaitGroup
+
+		defer wg.Wait()
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				defer conn.Close()
+
+				server.serve(conn)
+			}()
+		}
+	}()
+
+	return server
+}
+
+func (server *fakeNATSServer) serve(conn net.Conn) {
+	_, _ = conn.Write([]byte(`INFO {"server_id":"fake","version":"2.10.0","headers":true,"max_payload":1048576}` +
+		"\r\n"))
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		case "HPUB":
+			// HPUB <subject> <header size> <total size>
+			headerSize, _ := strconv.Atoi(fields[len(fields)-2])
+			totalSize, _ := strconv.Atoi(fields[len(fields)-1])
+
+			content := make([]byte, totalSize+2)
+			if _, err := io.ReadFull(reader, content); err != nil {
+				return
+			}
+
+			server.messages <- natsMessage{
+				subject: fields[1],
+				headers: string(content[:headerSize]),
+				payload: string(content[headerSize:totalSize]),
+			}
+		}
+	}
+}
+
+func (server *fakeNATSServer) next() natsMessage {
+	select {
+	case msg := <-server.messages:
+		return msg
+	case <-time.After(10 * time.Second):
+		return natsMessage{}
+	}
+}
@@ -0,0 +1,200 @@
+//go:build events
+// +build events
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/events/constants"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	contentTypeHeader = "Content-Type"
+	// the kafka writer waits this long for more messages before sending a batch
+	kafkaBatchTimeout = 10 * time.Millisecond
+)
+
+// Sink publishes the serialized events to a message bus or a receiver.
+type Sink interface {
+	Publish(ctx context.Context, topic string, message Message) error
+	Close() error
+}
+
+// NewSink returns the sink of the config type.
+func NewSink(config extconf.EventSinkConfig, log log.Logger) (Sink, error) {
+	switch config.Type {
+	case constants.HTTPSink:
+		return NewHTTPSink(config), nil
+	case constants.NATSSink:
+		return NewNATSSink(config, log)
+	case constants.KafkaSink:
+		return NewKafkaSink(config), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", zerr.ErrEventSinkUnknownType, config.Type)
+	}
+}
+
+// HTTPSink posts every event to the same URL, with the topic in the X-Zot-Event-Topic header.
+type HTTPSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func NewHTTPSink(config extconf.EventSinkConfig) *HTTPSink {
+	return &HTTPSink{
+		url:     config.Address,
+		headers: config.Headers,
+		client:  &http.Client{},
+	}
+}
+
+func (sink *HTTPSink) Publish(ctx context.Context, topic string, message Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.url, bytes.NewReader(message.Body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(contentTypeHeader, message.ContentType)
+	req.Header.Set("X-Zot-Event-Topic", topic)
+
+	for name, value := range sink.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := sink.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned status code %d", zerr.ErrEventSinkFailed, sink.url, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (sink *HTTPSink) Close() error {
+	sink.client.CloseIdleConnections()
+
+	return nil
+}
+
+// NATSSink publishes the events to the subject of their topic, zot starts even if the server is unreachable,
+// the events are then buffered until it connects.
+type NATSSink struct {
+	conn *nats.Conn
+}
+
+func NewNATSSink(config extconf.EventSinkConfig, log log.Logger) (*NATSSink, error) {
+	options := []nats.Option{
+		nats.Name("zot"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(conn *nats.Conn, err error) {
+			log.Warn().Err(err).Str("address", conn.ConnectedUrlRedacted()).Msg("events: disconnected from NATS server")
+		}),
+	}
+
+	if config.Timeout > 0 {
+		options = append(options, nats.Timeout(config.Timeout))
+	}
+
+	if config.Credentials != nil {
+		if config.Credentials.Token != "" {
+			options = append(options, nats.Token(config.Credentials.Token))
+		} else {
+			options = append(options, nats.UserInfo(config.Credentials.Username, config.Credentials.Password))
+		}
+	}
+
+	conn, err := nats.Connect(config.Address, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSSink{conn: conn}, nil
+}
+
+func (sink *NATSSink) Publish(ctx context.Context, topic string, message Message) error {
+	msg := nats.NewMsg(topic)
+	msg.Data = message.Body
+	msg.Header.Set(contentTypeHeader, message.ContentType)
+
+	if err := sink.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	// the event is only published once the server received it
+	if sink.conn.IsConnected() {
+		return sink.conn.FlushWithContext(ctx)
+	}
+
+	return nil
+}
+
+func (sink *NATSSink) Close() error {
+	return sink.conn.Drain()
+}
+
+// KafkaSink writes the events to the topic of their type, keyed by repo, the topics must already exist.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(config extconf.EventSinkConfig) *KafkaSink {
+	brokers := []string{}
+
+	for _, broker := range strings.Split(config.Address, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+
+	transport := &kafka.Transport{}
+
+	if config.Credentials != nil && config.Credentials.Username != "" {
+		transport.SASL = plain.Mechanism{
+			Username: config.Credentials.Username,
+			Password: config.Credentials.Password,
+		}
+	}
+
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Balancer:     &kafka.Hash{},
+			BatchTimeout: kafkaBatchTimeout,
+			RequiredAcks: kafka.RequireOne,
+			Transport:    transport,
+		},
+	}
+}
+
+func (sink *KafkaSink) Publish(ctx context.Context, topic string, message Message) error {
+	return sink.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   topic,
+		Key:     []byte(message.Key),
+		Value:   message.Body,
+		Headers: []kafka.Header{{Key: contentTypeHeader, Value: []byte(message.ContentType)}},
+	})
+}
+
+func (sink *KafkaSink) Close() error {
+	return sink.writer.Close()
+}
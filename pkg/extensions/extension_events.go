@@ -0,0 +1,37 @@
+//go:build events
+// +build events
+
+package extensions
+
+import (
+	"net"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/events"
+	"zotregistry.io/zot/pkg/log"
+)
+
+type EventRecorder = *events.Recorder
+
+// GetEventRecorder returns the publisher of the registry events, nil if the events extension is disabled.
+func GetEventRecorder(config *config.Config, log log.Logger) EventRecorder {
+	if !config.IsEventsEnabled() {
+		return nil
+	}
+
+	source := net.JoinHostPort(config.HTTP.Address, config.HTTP.Port)
+	if config.HTTP.ExternalURL != "" {
+		source = config.HTTP.ExternalURL
+	}
+
+	recorder, err := events.NewRecorder(config.Extensions.Events, source, log)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to start events extension, the registry events are not published")
+
+		return nil
+	}
+
+	log.Info().Int("sinks", len(config.Extensions.Events.Sinks)).Msg("publishing registry events enabled")
+
+	return recorder
+}
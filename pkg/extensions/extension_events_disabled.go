@@ -0,0 +1,29 @@
+//go:build !events
+// +build !events
+
+package extensions
+
+import (
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+type EventRecorder interface {
+	ImageUpdated(repo, reference, digest, mediaType, user string)
+	ImageDeleted(repo, reference, digest, mediaType, user string)
+	RepoDeleted(repo, user string)
+	ImageDenied(repo, reference, digest, mediaType, user, reason string)
+	RepoQuotaThresholdCrossed(repo string, size, quota int64, threshold int)
+	StorageFreeSpaceLow(rootDir string, free, minFree int64)
+	SignatureStatusChanged(repo, manifestDigest, signatureType, signatureDigest, signer, status string)
+	Close()
+}
+
+func GetEventRecorder(config *config.Config, log log.Logger) EventRecorder {
+	if config.IsEventsEnabled() {
+		log.Warn().Msg("events extension is disabled because given zot binary doesn't " +
+			"include this feature please build a binary that does so")
+	}
+
+	return nil
+}
@@ -4,21 +4,30 @@
 package extensions
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	glob "github.com/bmatcuk/doublestar/v4"
 	"github.com/gorilla/mux"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/api/constants"
 	zcommon "zotregistry.io/zot/pkg/common"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/imagetrust"
 	"zotregistry.io/zot/pkg/log"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
 	"zotregistry.io/zot/pkg/scheduler"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
 )
 
 func IsBuiltWithImageTrustExtension() bool {
@@ -149,18 +158,30 @@ func (trust *ImageTrust) HandleNotationCertificateUpload(response http.ResponseW
 	response.WriteHeader(http.StatusOK)
 }
 
+// EnableImageTrustVerification periodically verifies again the signatures, the changes of their status are
+// published by the event recorder, if any.
 func EnableImageTrustVerification(conf *config.Config, taskScheduler *scheduler.Scheduler,
-	metaDB mTypes.MetaDB, log log.Logger,
+	metaDB mTypes.MetaDB, eventRecorder EventRecorder, log log.Logger,
 ) {
 	if !conf.IsImageTrustEnabled() {
 		return
 	}
 
-	generator := imagetrust.NewTaskGenerator(metaDB, log)
+	var notifier imagetrust.StatusNotifier
 
-	numberOfHours := 2
-	interval := time.Duration(numberOfHours) * time.Hour
-	taskScheduler.SubmitGenerator(generator, interval, scheduler.MediumPriority)
+	if eventRecorder != nil {
+		notifier = eventRecorder
+	}
+
+	generator := imagetrust.NewTaskGenerator(metaDB, notifier, log)
+
+	interval := conf.Extensions.Trust.VerificationInterval
+	if interval == 0 {
+		numberOfHours := 2
+		interval = time.Duration(numberOfHours) * time.Hour
+	}
+
+	taskScheduler.SubmitSharedGenerator("signature-verification", generator, interval, scheduler.MediumPriority)
 }
 
 func SetupImageTrustExtension(conf *config.Config, metaDB mTypes.MetaDB, log log.Logger) error {
@@ -168,11 +189,11 @@ func SetupImageTrustExtension(conf *config.Config, metaDB mTypes.MetaDB, log log
 		return nil
 	}
 
-	var imgTrustStore mTypes.ImageTrustStore
+	var imgTrustStore *imagetrust.ImageTrustStore
 
 	var err error
 
-	if conf.Storage.RemoteCache {
+	if conf.Storage.RemoteCache && conf.Storage.CacheDriver["name"] == storageConstants.DynamoDBDriverName {
 		endpoint, _ := conf.Storage.CacheDriver["endpoint"].(string)
 		region, _ := conf.Storage.CacheDriver["region"].(string)
 		imgTrustStore, err = imagetrust.NewAWSImageTrustStore(region, endpoint)
@@ -187,7 +208,178 @@ func SetupImageTrustExtension(conf *config.Config, metaDB mTypes.MetaDB, log log
 		}
 	}
 
+	if policyPath := conf.Extensions.Trust.NotationTrustPolicy; policyPath != "" {
+		trustPolicy, err := os.ReadFile(policyPath)
+		if err != nil {
+			return err
+		}
+
+		if err := imagetrust.SetNotationTrustPolicy(imgTrustStore.NotationStorage, trustPolicy); err != nil {
+			log.Error().Err(err).Str("path", policyPath).Msg("couldn't set the notation trust policy")
+
+			return err
+		}
+	}
+
+	if conf.IsCosignEnabled() && conf.Extensions.Trust.CosignKeyless != nil {
+		imgTrustStore.CosignKeyless, err = imagetrust.NewCosignKeylessVerifier(conf.Extensions.Trust.CosignKeyless)
+		if err != nil {
+			log.Error().Err(err).Msg("couldn't set up the cosign keyless verification")
+
+			return err
+		}
+	}
+
 	metaDB.SetImageTrustStore(imgTrustStore)
 
 	return nil
 }
+
+// CheckImageTrustEnforcement returns zerr.ErrImageNotTrusted if the pulled manifest belongs to a repo
+// in which the image trust is enforced and it doesn't have a trusted signature,
+// the signatures and the other referrers of the images can always be pulled.
+func CheckImageTrustEnforcement(conf *config.Config, metaDB mTypes.MetaDB, repo, reference string,
+	manifestDigest godigest.Digest, manifestContent []byte, log log.Logger,
+) error {
+	if !conf.IsImageTrustEnabled() || metaDB == nil {
+		return nil
+	}
+
+	requirements := getSignatureRequirements(conf.Extensions.Trust, repo, true)
+
+	return checkSignatureRequirements(metaDB, repo, reference, manifestDigest, manifestContent, requirements,
+		"pulled", true, log)
+}
+
+// CheckSignaturePolicy returns zerr.ErrImageNotTrusted if a manifest is pushed by tag in a repo with a signature policy
+// and it doesn't have a trusted signature of the accepted types, the manifests are pushed by digest and signed
+// before they're tagged.
+func CheckSignaturePolicy(conf *config.Config, metaDB mTypes.MetaDB, repo, reference string,
+	manifestContent []byte, log log.Logger,
+) error {
+	if !conf.IsImageTrustEnabled() || metaDB == nil {
+		return nil
+	}
+
+	if _, err := godigest.Parse(reference); err == nil {
+		return nil
+	}
+
+	requirements := getSignatureRequirements(conf.Extensions.Trust, repo, false)
+
+	return checkSignatureRequirements(metaDB, repo, reference, godigest.FromBytes(manifestContent), manifestContent,
+		requirements, "tagged", false, log)
+}
+
+// getSignatureRequirements returns the accepted signature types of each policy applying to a repo,
+// an empty list of types accepts any signature.
+func getSignatureRequirements(trust *extconf.ImageTrustConfig, repo string, pulled bool) [][]string {
+	requirements := [][]string{}
+
+	if pulled && isTrustEnforced(trust.EnforcedRepos, repo) {
+		requirements = append(requirements, []string{})
+	}
+
+	for _, policy := range trust.SignaturePolicies {
+		if isTrustEnforced(policy.Repos, repo) {
+			requirements = append(requirements, policy.SignatureTypes)
+		}
+	}
+
+	return requirements
+}
+
+func checkSignatureRequirements(metaDB mTypes.MetaDB, repo, reference string, manifestDigest godigest.Digest,
+	manifestContent []byte, requirements [][]string, action string, stored bool, log log.Logger,
+) error {
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	repoMeta, err := metaDB.GetRepoMeta(repo)
+	if err != nil && !errors.Is(err, zerr.ErrRepoMetaNotFound) {
+		log.Error().Err(err).Str("repository", repo).Msg("image trust: couldn't get the repo metadata")
+
+		return err
+	}
+
+	// the signatures and the other artifacts can't be run, the pulled ones must also be known as such by the metaDB
+	if !hasFilesystemLayers(manifestContent) &&
+		(!stored || isStoredSignatureOrReferrer(repoMeta, manifestDigest.String())) {
+		return nil
+	}
+
+	for _, signatureTypes := range requirements {
+		if imagetrust.HasTrustedSignature(repoMeta, manifestDigest.String(), signatureTypes) {
+			continue
+		}
+
+		signatureType := "trusted"
+		if len(signatureTypes) > 0 {
+			signatureType = "trusted " + strings.Join(signatureTypes, " or ")
+		}
+
+		log.Warn().Str("repository", repo).Str("reference", reference).Str("digest", manifestDigest.String()).
+			Msgf("image trust: image can't be %s without a %s signature", action, signatureType)
+
+		return fmt.Errorf("%w: the images of repo %s can't be %s without a %s signature, %s doesn't have one",
+			zerr.ErrImageNotTrusted, repo, action, signatureType, manifestDigest)
+	}
+
+	return nil
+}
+
+// hasFilesystemLayers returns false for the manifests without any layer of a container filesystem, such as the
+// signatures, attestations and SBOMs, the indexes and the manifests which can't be parsed are considered runnable.
+func hasFilesystemLayers(manifestContent []byte) bool {
+	var manifest struct {
+		Manifests []ispec.Descriptor `json:"manifests"`
+		Layers    []ispec.Descriptor `json:"layers"`
+	}
+
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil || manifest.Manifests != nil {
+		return true
+	}
+
+	for _, layer := range manifest.Layers {
+		if strings.HasPrefix(layer.MediaType, "application/vnd.oci.image.layer.") ||
+			strings.HasPrefix(layer.MediaType, "application/vnd.docker.image.rootfs.") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isStoredSignatureOrReferrer checks if the metaDB recorded the manifest as a signature or a referrer of an image.
+func isStoredSignatureOrReferrer(repoMeta mTypes.RepoMetadata, manifestDigest string) bool {
+	for _, manifestSignatures := range repoMeta.Signatures {
+		for _, signatures := range manifestSignatures {
+			for _, signature := range signatures {
+				if signature.SignatureManifestDigest == manifestDigest {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, referrers := range repoMeta.Referrers {
+		for _, referrer := range referrers {
+			if referrer.Digest == manifestDigest {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isTrustEnforced(patterns []string, repo string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := glob.Match(pattern, repo); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -5,6 +5,7 @@ package extensions
 
 import (
 	"github.com/gorilla/mux"
+	godigest "github.com/opencontainers/go-digest"
 
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/log"
@@ -22,7 +23,7 @@ func SetupImageTrustRoutes(config *config.Config, router *mux.Router, metaDB mTy
 }
 
 func EnableImageTrustVerification(config *config.Config, taskScheduler *scheduler.Scheduler,
-	metaDB mTypes.MetaDB, log log.Logger,
+	metaDB mTypes.MetaDB, eventRecorder EventRecorder, log log.Logger,
 ) {
 	log.Warn().Msg("skipping adding to the scheduler a generator for updating signatures validity because " +
 		"given binary doesn't include this feature, please build a binary that does so")
@@ -34,3 +35,15 @@ func SetupImageTrustExtension(conf *config.Config, metaDB mTypes.MetaDB, log log
 
 	return nil
 }
+
+func CheckImageTrustEnforcement(conf *config.Config, metaDB mTypes.MetaDB, repo, reference string,
+	manifestDigest godigest.Digest, manifestContent []byte, log log.Logger,
+) error {
+	return nil
+}
+
+func CheckSignaturePolicy(conf *config.Config, metaDB mTypes.MetaDB, repo, reference string,
+	manifestContent []byte, log log.Logger,
+) error {
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	guuid "github.com/gofrs/uuid"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
@@ -195,7 +196,7 @@ func RunSignatureUploadAndVerificationTests(t *testing.T, cacheDriverParams map[
 		writers := io.MultiWriter(os.Stdout, logFile)
 		logger.Logger = logger.Output(writers)
 
-		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false, false,
 			logger, monitoring.NewMetricsServer(false, logger), nil, nil)
 
 		storeController := storage.StoreController{
@@ -315,7 +316,7 @@ func RunSignatureUploadAndVerificationTests(t *testing.T, cacheDriverParams map[
 		writers := io.MultiWriter(os.Stdout, logFile)
 		logger.Logger = logger.Output(writers)
 
-		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false, false,
 			logger, monitoring.NewMetricsServer(false, logger), nil, nil)
 
 		storeController := storage.StoreController{
@@ -422,7 +423,7 @@ func RunSignatureUploadAndVerificationTests(t *testing.T, cacheDriverParams map[
 		writers := io.MultiWriter(os.Stdout, logFile)
 		logger.Logger = logger.Output(writers)
 
-		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false, false,
 			logger, monitoring.NewMetricsServer(false, logger), nil, nil)
 
 		storeController := storage.StoreController{
@@ -584,7 +585,7 @@ func RunSignatureUploadAndVerificationTests(t *testing.T, cacheDriverParams map[
 		writers := io.MultiWriter(os.Stdout, logFile)
 		logger.Logger = logger.Output(writers)
 
-		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false, false,
 			logger, monitoring.NewMetricsServer(false, logger), nil, nil)
 
 		storeController := storage.StoreController{
@@ -844,7 +845,7 @@ func RunSignatureUploadAndVerificationTests(t *testing.T, cacheDriverParams map[
 		writers := io.MultiWriter(os.Stdout, logFile)
 		logger.Logger = logger.Output(writers)
 
-		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(globalDir, false, false, 0, 0, false, false, false,
 			logger, monitoring.NewMetricsServer(false, logger), nil, nil)
 
 		storeController := storage.StoreController{
@@ -1006,3 +1007,222 @@ func skipIt(t *testing.T) {
 		t.Skip("Skipping testing without AWS mock server")
 	}
 }
+
+func TestImageTrustEnforcement(t *testing.T) {
+	Convey("Verify pulls of unverified images are denied in enforced repos", t, func() {
+		globalDir := t.TempDir()
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		certName := "enforcement"
+		defaultValue := true
+
+		// the certificates are trusted only for the "enforced" repo
+		trustPolicyPath := path.Join(t.TempDir(), "trustpolicy.json")
+		trustPolicy := `{
+			"version": "1.0",
+			"trustPolicies": [
+				{
+					"name": "enforced",
+					"registryScopes": [ "zot/enforced" ],
+					"signatureVerification": {
+						"level" : "strict"
+					},
+					"trustStores": ["ca:default"],
+					"trustedIdentities": [
+						"*"
+					]
+				}
+			]
+		}`
+		err := os.WriteFile(trustPolicyPath, []byte(trustPolicy), 0o600)
+		So(err, ShouldBeNil)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = globalDir
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Trust = &extconf.ImageTrustConfig{}
+		conf.Extensions.Trust.Enable = &defaultValue
+		conf.Extensions.Trust.Notation = defaultValue
+		conf.Extensions.Trust.NotationTrustPolicy = trustPolicyPath
+		conf.Extensions.Trust.EnforcedRepos = []string{"enforced*"}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		image := CreateRandomImage()
+
+		for _, repo := range []string{"enforced", "enforced-unscoped", "free"} {
+			err = UploadImage(image, baseURL, repo, "1.0")
+			So(err, ShouldBeNil)
+		}
+
+		resp, err := resty.R().Get(baseURL + "/v2/enforced/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+
+		resp, err = resty.R().Get(baseURL + "/v2/free/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// only the manifest downloads are denied
+		resp, err = resty.R().Head(baseURL + "/v2/enforced/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// the unsigned images aren't exempted by a signature tag or a subject
+		signatureTag := fmt.Sprintf("sha256-%s.sig", image.Digest().Encoded())
+
+		err = UploadImage(CreateRandomImage(), baseURL, "enforced", signatureTag)
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().Get(baseURL + "/v2/enforced/manifests/" + signatureTag)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		referrer := CreateImageWith().RandomLayers(1, 10).DefaultConfig().
+			Subject(image.DescriptorRef()).Build()
+
+		err = UploadImage(referrer, baseURL, "enforced", "referrer")
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().Get(baseURL + "/v2/enforced/manifests/referrer")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		rootDir := t.TempDir()
+
+		test.NotationPathLock.Lock()
+		defer test.NotationPathLock.Unlock()
+
+		test.LoadNotationPath(rootDir)
+
+		err = test.GenerateNotationCerts(rootDir, certName)
+		So(err, ShouldBeNil)
+
+		certificateContent, err := os.ReadFile(path.Join(rootDir, "notation/localkeys", fmt.Sprintf("%s.crt", certName)))
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().SetHeader("Content-type", "application/octet-stream").
+			SetBody(certificateContent).Post(baseURL + constants.FullNotation)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		for _, repo := range []string{"enforced", "enforced-unscoped"} {
+			err = test.SignWithNotation(certName, fmt.Sprintf("localhost:%s/%s:1.0", port, repo), rootDir)
+			So(err, ShouldBeNil)
+		}
+
+		resp, err = resty.R().Get(baseURL + "/v2/enforced/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// no trust policy applies to the repo, its signature isn't trusted
+		resp, err = resty.R().Get(baseURL + "/v2/enforced-unscoped/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+	})
+}
+
+func TestSignaturePolicy(t *testing.T) {
+	Convey("Verify images can't be tagged or pulled without a trusted signature in repos with a policy", t, func() {
+		globalDir := t.TempDir()
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		certName := "policy"
+		defaultValue := true
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = globalDir
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Trust = &extconf.ImageTrustConfig{}
+		conf.Extensions.Trust.Enable = &defaultValue
+		conf.Extensions.Trust.Cosign = defaultValue
+		conf.Extensions.Trust.Notation = defaultValue
+		conf.Extensions.Trust.SignaturePolicies = []extconf.SignaturePolicy{
+			{Repos: []string{"signed/**"}, SignatureTypes: []string{"notation"}},
+			{Repos: []string{"cosign-signed"}, SignatureTypes: []string{"cosign"}},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		image := CreateRandomImage()
+
+		err := UploadImage(image, baseURL, "free", "1.0")
+		So(err, ShouldBeNil)
+
+		// an unsigned image can't be tagged as a signature
+		signatureTag := fmt.Sprintf("sha256-%s.sig", image.Digest().Encoded())
+
+		resp, err := resty.R().SetHeader("Content-type", ispec.MediaTypeImageManifest).
+			SetBody(CreateRandomImage().ManifestDescriptor.Data).Put(baseURL + "/v2/signed/app/manifests/" + signatureTag)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		So(string(resp.Body()), ShouldContainSubstring, "can't be tagged")
+
+		// the unsigned images can't be tagged
+		for _, repo := range []string{"signed/app", "cosign-signed"} {
+			err = UploadImage(image, baseURL, repo, "1.0")
+			So(err, ShouldNotBeNil)
+
+			resp, err := resty.R().SetHeader("Content-type", ispec.MediaTypeImageManifest).
+				SetBody(image.ManifestDescriptor.Data).Put(baseURL + "/v2/" + repo + "/manifests/1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+			So(string(resp.Body()), ShouldContainSubstring, "DENIED")
+			So(string(resp.Body()), ShouldContainSubstring, "can't be tagged")
+
+			// but they can be pushed by digest to be signed
+			err = UploadImage(image, baseURL, repo, image.DigestStr())
+			So(err, ShouldBeNil)
+
+			resp, err = resty.R().Get(baseURL + "/v2/" + repo + "/manifests/" + image.DigestStr())
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+			So(string(resp.Body()), ShouldContainSubstring, "can't be pulled")
+		}
+
+		rootDir := t.TempDir()
+
+		test.NotationPathLock.Lock()
+		defer test.NotationPathLock.Unlock()
+
+		test.LoadNotationPath(rootDir)
+
+		err = test.GenerateNotationCerts(rootDir, certName)
+		So(err, ShouldBeNil)
+
+		certificateContent, err := os.ReadFile(path.Join(rootDir, "notation/localkeys", fmt.Sprintf("%s.crt", certName)))
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().SetHeader("Content-type", "application/octet-stream").
+			SetBody(certificateContent).Post(baseURL + constants.FullNotation)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		for _, repo := range []string{"signed/app", "cosign-signed"} {
+			err = test.SignWithNotation(certName, fmt.Sprintf("localhost:%s/%s@%s", port, repo, image.DigestStr()), rootDir)
+			So(err, ShouldBeNil)
+		}
+
+		err = UploadImage(image, baseURL, "signed/app", "1.0")
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().Get(baseURL + "/v2/signed/app/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// only the cosign signatures are accepted in this repo
+		err = UploadImage(image, baseURL, "cosign-signed", "1.0")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -4,6 +4,10 @@
 package extensions
 
 import (
+	"context"
+	"net"
+	"os"
+
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
@@ -36,3 +40,27 @@ func SetupMetricsRoutes(config *config.Config, router *mux.Router,
 		extRouter.Methods("GET").Handler(promhttp.Handler())
 	}
 }
+
+// StartOTLPMetricsExporter pushes the metrics to an OpenTelemetry collector until the context is done.
+func StartOTLPMetricsExporter(ctx context.Context, config *config.Config, log log.Logger) {
+	if !config.IsMetricsEnabled() || config.Extensions.Metrics.OTLP == nil {
+		return
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = config.HTTP.Address
+	}
+
+	exporter := monitoring.NewOTLPExporter(config.Extensions.Metrics.OTLP, map[string]string{
+		"service.name":               "zot",
+		"service.version":            config.ReleaseTag,
+		"service.instance.id":        net.JoinHostPort(hostname, config.HTTP.Port),
+		"zot.storage.root_directory": config.Storage.RootDirectory,
+	}, log)
+
+	log.Info().Str("endpoint", config.Extensions.Metrics.OTLP.Endpoint).
+		Dur("interval", config.Extensions.Metrics.OTLP.Interval).Msg("exporting metrics to OTLP collector")
+
+	go exporter.Run(ctx, config.Extensions.Metrics.OTLP.Interval)
+}
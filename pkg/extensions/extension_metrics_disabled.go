@@ -4,6 +4,7 @@
 package extensions
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -20,6 +21,14 @@ func EnableMetricsExtension(config *config.Config, log log.Logger, rootDir strin
 		"please build a binary that does so")
 }
 
+// StartOTLPMetricsExporter ...
+func StartOTLPMetricsExporter(ctx context.Context, config *config.Config, log log.Logger) {
+	if config.Extensions != nil && config.Extensions.Metrics != nil && config.Extensions.Metrics.OTLP != nil {
+		log.Warn().Msg("skipping exporting metrics to OTLP collector because given zot binary doesn't include " +
+			"this feature, please build a binary that does so")
+	}
+}
+
 // SetupMetricsRoutes ...
 func SetupMetricsRoutes(conf *config.Config, router *mux.Router,
 	authFunc mux.MiddlewareFunc, log log.Logger, metrics monitoring.MetricServer,
@@ -4,15 +4,43 @@
 package extensions
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	godigest "github.com/opencontainers/go-digest"
 
+	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/api/constants"
+	apiErr "zotregistry.io/zot/pkg/api/errors"
+	"zotregistry.io/zot/pkg/cluster"
 	zcommon "zotregistry.io/zot/pkg/common"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/maintenance"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	zreg "zotregistry.io/zot/pkg/regexp"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+	"zotregistry.io/zot/pkg/retention"
+	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	// a repo is deleted with a token obtained less than 5 minutes before.
+	repoDeletionTokenTTL = 5 * time.Minute
+	// the retention policies are small documents, the requests are limited to 1MB.
+	maxRetentionPoliciesSize = 1 << 20
+	// the log levels are tiny documents.
+	maxLogLevelsSize = 1 << 12
 )
 
 type HTPasswd struct {
@@ -80,7 +108,10 @@ func (auth Auth) MarshalJSON() ([]byte, error) {
 	return json.Marshal((localAuth)(auth))
 }
 
-func SetupMgmtRoutes(conf *config.Config, router *mux.Router, log log.Logger) {
+func SetupMgmtRoutes(conf *config.Config, router *mux.Router, storeController storage.StoreController,
+	metaDB mTypes.MetaDB, eventRecorder EventRecorder, retentionManager *retention.Manager,
+	maintenanceManager *maintenance.Manager, clusterMember *cluster.Member, log log.Logger,
+) {
 	if !conf.IsMgmtEnabled() {
 		log.Info().Msg("skip enabling the mgmt route as the config prerequisites are not met")
 
@@ -89,7 +120,94 @@ func SetupMgmtRoutes(conf *config.Config, router *mux.Router, log log.Logger) {
 
 	log.Info().Msg("setting up mgmt routes")
 
-	mgmt := Mgmt{Conf: conf, Log: log}
+	mgmt := Mgmt{
+		Conf:            conf,
+		StoreController: storeController,
+		MetaDB:          metaDB,
+		EventRecorder:   eventRecorder,
+		Retention:       retentionManager,
+		Maintenance:     maintenanceManager,
+		Cluster:         clusterMember,
+		Log:             log,
+		deletionTokens:  map[string]repoDeletionToken{},
+	}
+
+	// deleting a repo can't be undone, it's only available to the authenticated admins
+	if conf.IsBasicAuthnEnabled() {
+		repoMethods := zcommon.AllowedMethods(http.MethodPost, http.MethodDelete)
+
+		repoRouter := router.PathPrefix(constants.ExtMgmtRepos).Subrouter()
+		repoRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		repoRouter.Use(zcommon.AddExtensionSecurityHeaders())
+		repoRouter.Use(zcommon.ACHeadersMiddleware(conf, repoMethods...))
+		repoRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+		repoRouter.HandleFunc(fmt.Sprintf("/{name:%s}/deletion", zreg.NameRegexp.String()),
+			mgmt.HandleCreateRepoDeletionToken).Methods(http.MethodPost, http.MethodOptions)
+		repoRouter.HandleFunc(fmt.Sprintf("/{name:%s}", zreg.NameRegexp.String()),
+			mgmt.HandleDeleteRepo).Methods(http.MethodDelete, http.MethodOptions)
+
+		// the retention policies delete tags, they are only managed by the authenticated admins
+		retentionMethods := zcommon.AllowedMethods(http.MethodGet, http.MethodPut, http.MethodPost)
+
+		retentionRouter := router.PathPrefix(constants.ExtMgmtRetention).Subrouter()
+		retentionRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		retentionRouter.Use(zcommon.AddExtensionSecurityHeaders())
+		retentionRouter.Use(zcommon.ACHeadersMiddleware(conf, retentionMethods...))
+		retentionRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+		retentionRouter.HandleFunc("", mgmt.HandleGetRetentionPolicies).Methods(http.MethodGet, http.MethodOptions)
+		retentionRouter.HandleFunc("", mgmt.HandleSetRetentionPolicies).Methods(http.MethodPut)
+		retentionRouter.HandleFunc("/preview", mgmt.HandlePreviewRetentionPolicies).
+			Methods(http.MethodPost, http.MethodOptions)
+
+		// the garbage collection, scrub, dedupe and reconciliation are run and paused by the authenticated admins
+		maintenanceMethods := zcommon.AllowedMethods(http.MethodGet, http.MethodPost)
+
+		maintenanceRouter := router.PathPrefix(constants.ExtMgmtMaintenance).Subrouter()
+		maintenanceRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		maintenanceRouter.Use(zcommon.AddExtensionSecurityHeaders())
+		maintenanceRouter.Use(zcommon.ACHeadersMiddleware(conf, maintenanceMethods...))
+		maintenanceRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+		maintenanceRouter.HandleFunc("", mgmt.HandleGetMaintenanceJobs).Methods(http.MethodGet, http.MethodOptions)
+		maintenanceRouter.HandleFunc("/{job}", mgmt.HandleGetMaintenanceJob).Methods(http.MethodGet, http.MethodOptions)
+		maintenanceRouter.HandleFunc("/gc/preview", mgmt.HandlePreviewGC).Methods(http.MethodGet, http.MethodOptions)
+		maintenanceRouter.HandleFunc("/{job}/{action:run|pause|resume}", mgmt.HandleMaintenanceJobAction).
+			Methods(http.MethodPost, http.MethodOptions)
+
+		// the metadata of all the repos is read and replaced, only by the authenticated admins
+		metaDBMethods := zcommon.AllowedMethods(http.MethodGet, http.MethodPost)
+
+		metaDBRouter := router.PathPrefix(constants.ExtMgmtMetaDB).Subrouter()
+		metaDBRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		metaDBRouter.Use(zcommon.AddExtensionSecurityHeaders())
+		metaDBRouter.Use(zcommon.ACHeadersMiddleware(conf, metaDBMethods...))
+		metaDBRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+		metaDBRouter.HandleFunc("/backup", mgmt.HandleBackupMetaDB).Methods(http.MethodGet, http.MethodOptions)
+		metaDBRouter.HandleFunc("/restore", mgmt.HandleRestoreMetaDB).Methods(http.MethodPost, http.MethodOptions)
+
+		// the log levels are raised during incidents by the authenticated admins
+		logMethods := zcommon.AllowedMethods(http.MethodGet, http.MethodPut)
+
+		logRouter := router.PathPrefix(constants.ExtMgmtLog).Subrouter()
+		logRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		logRouter.Use(zcommon.AddExtensionSecurityHeaders())
+		logRouter.Use(zcommon.ACHeadersMiddleware(conf, logMethods...))
+		logRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+		logRouter.HandleFunc("", mgmt.HandleGetLogLevels).Methods(http.MethodGet, http.MethodOptions)
+		logRouter.HandleFunc("", mgmt.HandleSetLogLevels).Methods(http.MethodPut)
+
+		// the members of the cluster are listed to the authenticated admins
+		clusterMethods := zcommon.AllowedMethods(http.MethodGet)
+
+		clusterRouter := router.PathPrefix(constants.ExtMgmtCluster).Subrouter()
+		clusterRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		clusterRouter.Use(zcommon.AddExtensionSecurityHeaders())
+		clusterRouter.Use(zcommon.ACHeadersMiddleware(conf, clusterMethods...))
+		clusterRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+		clusterRouter.HandleFunc("", mgmt.HandleGetCluster).Methods(http.MethodGet, http.MethodOptions)
+	} else {
+		log.Info().Msg("skip enabling the mgmt repo deletion, retention, maintenance, metadb, log and cluster routes " +
+			"as authentication is not enabled")
+	}
 
 	// The endpoint for reading configuration should be available to all users
 	allowedMethods := zcommon.AllowedMethods(http.MethodGet)
@@ -104,8 +222,60 @@ func SetupMgmtRoutes(conf *config.Config, router *mux.Router, log log.Logger) {
 }
 
 type Mgmt struct {
-	Conf *config.Config
-	Log  log.Logger
+	Conf            *config.Config
+	StoreController storage.StoreController
+	MetaDB          mTypes.MetaDB
+	EventRecorder   EventRecorder
+	Retention       *retention.Manager
+	Maintenance     *maintenance.Manager
+	Cluster         *cluster.Member
+	Log             log.Logger
+
+	deletionTokens map[string]repoDeletionToken
+	tokensLock     sync.Mutex
+
+	// the levels restored when the ones changed for a while expire
+	levelsRestore *logLevelsRestore
+	levelsLock    sync.Mutex
+}
+
+type logLevelsRestore struct {
+	levels log.Levels
+	at     time.Time
+	timer  *time.Timer
+}
+
+type repoDeletionToken struct {
+	repo      string
+	expiresAt time.Time
+}
+
+// RepoDeletionToken confirms the deletion of a repo.
+type RepoDeletionToken struct {
+	Repo      string              `json:"repo"`
+	Token     string              `json:"token"`
+	ExpiresAt time.Time           `json:"expiresAt"`
+	Summary   RepoDeletionSummary `json:"summary"`
+}
+
+// RepoDeletionSummary describes what is removed with a repo.
+type RepoDeletionSummary struct {
+	Tags       []string `json:"tags"`
+	Referrers  int      `json:"referrers"`
+	Signatures int      `json:"signatures"`
+	// the size of the blobs no other repo of the same storage has, the shared blobs may be deduped
+	ReclaimableBytes int64 `json:"reclaimableBytes"`
+}
+
+// RetentionPolicies are the retention policies applied to the repos, the first policy matching a repo is used.
+type RetentionPolicies struct {
+	Policies []config.RetentionPolicy `json:"policies"`
+}
+
+// RetentionPreview lists the tags the retention policies would delete if they were applied now.
+type RetentionPreview struct {
+	Policies  []config.RetentionPolicy `json:"policies"`
+	Deletions []retention.Candidate    `json:"deletions"`
 }
 
 // mgmtHandler godoc
@@ -128,3 +298,844 @@ func (mgmt *Mgmt) HandleGetConfig(w http.ResponseWriter, r *http.Request) {
 
 	_, _ = w.Write(buf)
 }
+
+// RepoDeletionToken godoc
+// @Summary Get a token confirming the deletion of a repo
+// @Description Get a single use token, valid for 5 minutes, required to delete the repo
+// @Router  /v2/_zot/ext/mgmt/repos/{name}/deletion [post]
+// @Accept  json
+// @Produce json
+// @Param   name     path    string     true        "repository name"
+// @Success 201 {object}   extensions.RepoDeletionToken
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 404 {string}   string   "not found".
+func (mgmt *Mgmt) HandleCreateRepoDeletionToken(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	repo := mux.Vars(request)["name"]
+
+	imgStore := mgmt.StoreController.GetImageStore(repo)
+	if ok, err := imgStore.ValidateRepo(repo); !ok || err != nil {
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	summary, err := mgmt.getRepoDeletionSummary(repo)
+	if err != nil {
+		mgmt.Log.Error().Err(err).Str("repository", repo).Msg("mgmt: unable to get the summary of the repo deletion")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	token := RepoDeletionToken{
+		Repo:      repo,
+		Token:     uuid.NewString(),
+		ExpiresAt: time.Now().Add(repoDeletionTokenTTL).UTC(),
+		Summary:   summary,
+	}
+
+	mgmt.tokensLock.Lock()
+
+	// drop the expired tokens which were never used
+	for value, deletionToken := range mgmt.deletionTokens {
+		if time.Now().After(deletionToken.expiresAt) {
+			delete(mgmt.deletionTokens, value)
+		}
+	}
+
+	mgmt.deletionTokens[token.Token] = repoDeletionToken{repo: repo, expiresAt: token.ExpiresAt}
+
+	mgmt.tokensLock.Unlock()
+
+	zcommon.WriteJSON(response, http.StatusCreated, token)
+}
+
+// DeleteRepo godoc
+// @Summary Delete a repo
+// @Description Delete a repo with all its manifests, blobs, metadata and the stars and bookmarks of the users
+// @Router  /v2/_zot/ext/mgmt/repos/{name} [delete]
+// @Accept  json
+// @Produce json
+// @Param   name     path    string     true        "repository name"
+// @Param   token    query   string     true        "deletion token"
+// @Success 200 {string}   string   "ok"
+// @Failure 400 {string}   string   "bad request"
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 404 {string}   string   "not found"
+// @Failure 405 {string}   string   "method not allowed"
+// @Failure 500 {string}   string   "internal server error".
+func (mgmt *Mgmt) HandleDeleteRepo(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	repo := mux.Vars(request)["name"]
+
+	imgStore := mgmt.StoreController.GetImageStore(repo)
+
+	// the repos of the read-only stores aren't deleted, like their manifests and blobs
+	if imgStore.IsReadOnly() {
+		e := apiErr.NewError(apiErr.UNSUPPORTED).AddDetail(map[string]string{"name": repo, "reason": "read only storage"})
+		zcommon.WriteJSON(response, http.StatusMethodNotAllowed, apiErr.NewErrorList(e))
+
+		return
+	}
+
+	if !mgmt.useDeletionToken(repo, request.URL.Query().Get("token")) {
+		mgmt.Log.Info().Str("repository", repo).Msg("mgmt: repo deletion requested without a valid token")
+		zcommon.WriteJSON(response, http.StatusBadRequest,
+			map[string]string{"error": zerr.ErrRepoDeletionTokenInvalid.Error()})
+
+		return
+	}
+
+	if err := imgStore.DeleteRepo(repo); err != nil {
+		if errors.Is(err, zerr.ErrRepoNotFound) {
+			response.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		mgmt.Log.Error().Err(err).Str("repository", repo).Msg("mgmt: unable to delete the repo from storage")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if mgmt.MetaDB != nil {
+		if err := mgmt.MetaDB.DeleteRepoMeta(repo); err != nil {
+			mgmt.Log.Error().Err(err).Str("repository", repo).Msg("mgmt: unable to delete the repo metadata")
+			response.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	user := getMgmtUsername(request)
+
+	if mgmt.EventRecorder != nil {
+		mgmt.EventRecorder.RepoDeleted(repo, user)
+	}
+
+	mgmt.Log.Info().Str("repository", repo).Str("user", user).Msg("mgmt: repo deleted")
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// getRepoDeletionSummary lists the tags, referrers and signatures of the repo, and the size of its blobs which
+// no other repo has.
+func (mgmt *Mgmt) getRepoDeletionSummary(repo string) (RepoDeletionSummary, error) {
+	imgStore := mgmt.StoreController.GetImageStore(repo)
+	summary := RepoDeletionSummary{Tags: []string{}}
+
+	if mgmt.MetaDB != nil {
+		repoMeta, err := mgmt.MetaDB.GetRepoMeta(repo)
+		if err != nil && !errors.Is(err, zerr.ErrRepoMetaNotFound) {
+			return summary, err
+		}
+
+		for tag := range repoMeta.Tags {
+			summary.Tags = append(summary.Tags, tag)
+		}
+
+		for _, referrers := range repoMeta.Referrers {
+			summary.Referrers += len(referrers)
+		}
+
+		for _, manifestSignatures := range repoMeta.Signatures {
+			for _, signatures := range manifestSignatures {
+				summary.Signatures += len(signatures)
+			}
+		}
+	} else {
+		tags, err := imgStore.GetImageTags(repo)
+		if err != nil && !errors.Is(err, zerr.ErrRepoNotFound) {
+			return summary, err
+		}
+
+		summary.Tags = append(summary.Tags, tags...)
+	}
+
+	sort.Strings(summary.Tags)
+
+	repos, err := imgStore.GetRepositories()
+	if err != nil {
+		return summary, err
+	}
+
+	// the repos without blobs don't have a blobs directory, so the listing errors are ignored
+	sharedBlobs := map[godigest.Digest]bool{}
+
+	for _, otherRepo := range repos {
+		if otherRepo == repo {
+			continue
+		}
+
+		blobs, _ := imgStore.GetAllBlobs(otherRepo)
+		for _, blob := range blobs {
+			sharedBlobs[blob] = true
+		}
+	}
+
+	blobs, _ := imgStore.GetAllBlobs(repo)
+
+	for _, blob := range blobs {
+		if sharedBlobs[blob] {
+			continue
+		}
+
+		ok, size, _, err := imgStore.StatBlob(repo, blob)
+		if err != nil || !ok {
+			continue
+		}
+
+		summary.ReclaimableBytes += size
+	}
+
+	return summary, nil
+}
+
+// useDeletionToken checks the token confirms the deletion of the repo, a token is used only once.
+func (mgmt *Mgmt) useDeletionToken(repo, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	mgmt.tokensLock.Lock()
+	defer mgmt.tokensLock.Unlock()
+
+	deletionToken, ok := mgmt.deletionTokens[token]
+	if !ok || deletionToken.repo != repo {
+		return false
+	}
+
+	delete(mgmt.deletionTokens, token)
+
+	return time.Now().Before(deletionToken.expiresAt)
+}
+
+// GetRetentionPolicies godoc
+// @Summary Get the retention policies
+// @Description Get the retention policies currently applied to the repos
+// @Router  /v2/_zot/ext/mgmt/retention [get]
+// @Accept  json
+// @Produce json
+// @Success 200 {object}   extensions.RetentionPolicies
+// @Failure 401 {string}   string   "unauthorized".
+func (mgmt *Mgmt) HandleGetRetentionPolicies(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, RetentionPolicies{Policies: mgmt.Retention.Policies()})
+}
+
+// SetRetentionPolicies godoc
+// @Summary Set the retention policies
+// @Description Validate and store the retention policies, they replace the policies of the config.
+// @Description The response lists the tags the new policies would delete if they were applied now.
+// @Router  /v2/_zot/ext/mgmt/retention [put]
+// @Accept  json
+// @Produce json
+// @Param   policies body     extensions.RetentionPolicies true "retention policies"
+// @Success 200 {object}   extensions.RetentionPreview
+// @Failure 400 {string}   string   "bad request"
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 500 {string}   string   "internal server error".
+func (mgmt *Mgmt) HandleSetRetentionPolicies(response http.ResponseWriter, request *http.Request) {
+	policies, ok := mgmt.readRetentionPolicies(response, request)
+	if !ok {
+		return
+	}
+
+	if err := mgmt.Retention.SetPolicies(policies.Policies); err != nil {
+		if errors.Is(err, zerr.ErrBadRetentionPolicy) {
+			zcommon.WriteJSON(response, http.StatusBadRequest, map[string]string{"error": err.Error()})
+
+			return
+		}
+
+		mgmt.Log.Error().Err(err).Msg("mgmt: unable to store the retention policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	mgmt.Log.Info().Str("user", getMgmtUsername(request)).Msg("mgmt: retention policies updated")
+
+	mgmt.writeRetentionPreview(response, request, mgmt.Retention.Policies())
+}
+
+// PreviewRetentionPolicies godoc
+// @Summary Preview retention policies
+// @Description Validate the retention policies and list the tags they would delete, without storing them.
+// @Description The current policies are previewed if the request has no body.
+// @Router  /v2/_zot/ext/mgmt/retention/preview [post]
+// @Accept  json
+// @Produce json
+// @Param   policies body     extensions.RetentionPolicies false "retention policies"
+// @Success 200 {object}   extensions.RetentionPreview
+// @Failure 400 {string}   string   "bad request"
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 500 {string}   string   "internal server error".
+func (mgmt *Mgmt) HandlePreviewRetentionPolicies(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	if request.ContentLength == 0 {
+		mgmt.writeRetentionPreview(response, request, mgmt.Retention.Policies())
+
+		return
+	}
+
+	policies, ok := mgmt.readRetentionPolicies(response, request)
+	if !ok {
+		return
+	}
+
+	mgmt.writeRetentionPreview(response, request, policies.Policies)
+}
+
+// readRetentionPolicies decodes the policies of the request, it writes the error response if they can't be read.
+func (mgmt *Mgmt) readRetentionPolicies(response http.ResponseWriter, request *http.Request,
+) (RetentionPolicies, bool) {
+	policies := RetentionPolicies{}
+
+	body, err := io.ReadAll(http.MaxBytesReader(response, request.Body, maxRetentionPoliciesSize))
+	if err != nil {
+		response.WriteHeader(http.StatusRequestEntityTooLarge)
+
+		return policies, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// the misspelled fields would be ignored and the tags deleted by policies different from the expected ones
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&policies); err != nil {
+		zcommon.WriteJSON(response, http.StatusBadRequest,
+			map[string]string{"error": fmt.Sprintf("%s: %s", zerr.ErrBadRetentionPolicy, err)})
+
+		return policies, false
+	}
+
+	return policies, true
+}
+
+func (mgmt *Mgmt) writeRetentionPreview(response http.ResponseWriter, request *http.Request,
+	policies []config.RetentionPolicy,
+) {
+	deletions, err := mgmt.Retention.Preview(request.Context(), policies)
+	if err != nil {
+		if errors.Is(err, zerr.ErrBadRetentionPolicy) {
+			zcommon.WriteJSON(response, http.StatusBadRequest, map[string]string{"error": err.Error()})
+
+			return
+		}
+
+		mgmt.Log.Error().Err(err).Msg("mgmt: unable to preview the retention policies")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if policies == nil {
+		policies = []config.RetentionPolicy{}
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, RetentionPreview{Policies: policies, Deletions: deletions})
+}
+
+// MaintenanceJobs are the maintenance jobs enabled by the config.
+type MaintenanceJobs struct {
+	Jobs []maintenance.Status `json:"jobs"`
+}
+
+// GetMaintenanceJobs godoc
+// @Summary Get the maintenance jobs
+// @Description Get the status of the garbage collection, scrub, dedupe and reconciliation, those enabled by the config
+// @Router  /v2/_zot/ext/mgmt/maintenance [get]
+// @Produce json
+// @Success 200 {object}   extensions.MaintenanceJobs
+// @Failure 401 {string}   string   "unauthorized".
+func (mgmt *Mgmt) HandleGetMaintenanceJobs(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, MaintenanceJobs{Jobs: mgmt.Maintenance.Statuses()})
+}
+
+// GetMaintenanceJob godoc
+// @Summary Get a maintenance job
+// @Description Get the status of a maintenance job, with the progress of its current or last run
+// @Router  /v2/_zot/ext/mgmt/maintenance/{job} [get]
+// @Produce json
+// @Param   job      path     string  true  "gc, scrub, dedupe or reconcile"
+// @Success 200 {object}   maintenance.Status
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 404 {string}   string   "not found".
+func (mgmt *Mgmt) HandleGetMaintenanceJob(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	status, err := mgmt.Maintenance.Status(mux.Vars(request)["job"])
+	if err != nil {
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, status)
+}
+
+// MaintenanceJobAction godoc
+// @Summary Run, pause or resume a maintenance job
+// @Description Start a run of the job, or pause and resume its runs. Pausing a job stops it from starting new tasks,
+// @Description those already queued still run.
+// @Router  /v2/_zot/ext/mgmt/maintenance/{job}/{action} [post]
+// @Produce json
+// @Param   job      path     string  true  "gc, scrub, dedupe or reconcile"
+// @Param   action   path     string  true  "run, pause or resume"
+// @Success 200 {object}   maintenance.Status
+// @Success 202 {object}   maintenance.Status
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 404 {string}   string   "not found"
+// @Failure 409 {object}   maintenance.Status.
+func (mgmt *Mgmt) HandleMaintenanceJobAction(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	vars := mux.Vars(request)
+	job, action := vars["job"], vars["action"]
+
+	var (
+		status maintenance.Status
+		err    error
+	)
+
+	statusCode := http.StatusOK
+
+	switch action {
+	case "run":
+		status, err = mgmt.Maintenance.Run(job)
+		statusCode = http.StatusAccepted
+	case "pause":
+		status, err = mgmt.Maintenance.Pause(job)
+	default:
+		status, err = mgmt.Maintenance.Resume(job)
+	}
+
+	switch {
+	case errors.Is(err, zerr.ErrMaintenanceJobNotFound):
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	case errors.Is(err, zerr.ErrMaintenanceJobRunning), errors.Is(err, zerr.ErrMaintenanceJobPaused):
+		zcommon.WriteJSON(response, http.StatusConflict, status)
+
+		return
+	}
+
+	mgmt.Log.Info().Str("user", getMgmtUsername(request)).Str("job", job).Str("action", action).
+		Msg("mgmt: maintenance job updated")
+
+	zcommon.WriteJSON(response, statusCode, status)
+}
+
+// GCPreview lists what the garbage collection would remove from the repos, only the repos it would change are
+// listed.
+type GCPreview struct {
+	Repos      []storageTypes.GCReport `json:"repos"`
+	BytesFreed int64                   `json:"bytesFreed"`
+}
+
+// PreviewGC godoc
+// @Summary Preview the garbage collection
+// @Description Run the garbage collection without removing anything and list the manifests and blobs it would
+// @Description remove, of all the repos or of a single one.
+// @Router  /v2/_zot/ext/mgmt/maintenance/gc/preview [get]
+// @Produce json
+// @Param   repo     query    string  false "repository name"
+// @Success 200 {object}   extensions.GCPreview
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func (mgmt *Mgmt) HandlePreviewGC(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	preview := GCPreview{Repos: []storageTypes.GCReport{}}
+
+	addReport := func(imgStore storageTypes.ImageStore, repo string) error {
+		report, err := imgStore.RunGCRepoReport(repo, true)
+		if err != nil {
+			return err
+		}
+
+		if !report.IsEmpty() {
+			preview.Repos = append(preview.Repos, report)
+			preview.BytesFreed += report.BytesFreed
+		}
+
+		return nil
+	}
+
+	if repo := request.URL.Query().Get("repo"); repo != "" {
+		imgStore := mgmt.StoreController.GetImageStore(repo)
+		if ok, err := imgStore.ValidateRepo(repo); !ok || err != nil {
+			response.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if err := addReport(imgStore, repo); err != nil {
+			mgmt.Log.Error().Err(err).Str("repository", repo).Msg("mgmt: unable to preview the gc of the repo")
+			response.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		zcommon.WriteJSON(response, http.StatusOK, preview)
+
+		return
+	}
+
+	for _, imgStore := range mgmt.getImageStores() {
+		repos, err := imgStore.GetRepositories()
+		if err != nil {
+			mgmt.Log.Error().Err(err).Str("rootDir", imgStore.RootDir()).Msg("mgmt: unable to list the repos")
+			response.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		for _, repo := range repos {
+			if err := addReport(imgStore, repo); err != nil {
+				mgmt.Log.Error().Err(err).Str("repository", repo).Msg("mgmt: unable to preview the gc of the repo")
+				response.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+		}
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, preview)
+}
+
+// getImageStores returns the default image store then the sub stores sorted by their route.
+func (mgmt *Mgmt) getImageStores() []storageTypes.ImageStore {
+	imgStores := []storageTypes.ImageStore{mgmt.StoreController.DefaultStore}
+
+	routes := make([]string, 0, len(mgmt.StoreController.SubStore))
+	for route := range mgmt.StoreController.SubStore {
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	for _, route := range routes {
+		imgStores = append(imgStores, mgmt.StoreController.SubStore[route])
+	}
+
+	return imgStores
+}
+
+// countingWriter counts the bytes written, the status code of a response can't be changed once its body is
+// written.
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+}
+
+func (cw *countingWriter) Write(data []byte) (int, error) {
+	written, err := cw.writer.Write(data)
+	cw.written += int64(written)
+
+	return written, err
+}
+
+// BackupMetaDB godoc
+// @Summary Back up the metadata database
+// @Description Stream a consistent snapshot of the metadata database, with the stars, bookmarks, statistics and
+// @Description signatures state of the images. It's restored independently of the image storage.
+// @Router  /v2/_zot/ext/mgmt/metadb/backup [get]
+// @Produce application/gzip
+// @Success 200 {string}   string   "the backup"
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 500 {string}   string   "internal server error"
+// @Failure 501 {string}   string   "not implemented".
+func (mgmt *Mgmt) HandleBackupMetaDB(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/gzip")
+	response.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="metadb-%s.backup.gz"`,
+		time.Now().UTC().Format("20060102T150405Z")))
+
+	writer := &countingWriter{writer: response}
+
+	if err := mgmt.MetaDB.Backup(request.Context(), writer); err != nil {
+		mgmt.Log.Error().Err(err).Msg("mgmt: unable to back up the metadb")
+
+		// the client sees a truncated backup otherwise, which can't be restored
+		if writer.written == 0 {
+			response.Header().Del("Content-Disposition")
+			response.Header().Del("Content-Type")
+
+			if errors.Is(err, zerr.ErrMetaDBBackupNotSupported) {
+				response.WriteHeader(http.StatusNotImplemented)
+			} else {
+				response.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+
+		return
+	}
+
+	mgmt.Log.Info().Str("user", getMgmtUsername(request)).Int64("size", writer.written).
+		Msg("mgmt: metadb backed up")
+}
+
+// RestoreMetaDB godoc
+// @Summary Restore the metadata database
+// @Description Replace the metadata database with a backup, the database is left unchanged if the backup is invalid
+// @Router  /v2/_zot/ext/mgmt/metadb/restore [post]
+// @Accept  application/gzip
+// @Success 200 {string}   string   "ok"
+// @Failure 400 {string}   string   "bad request"
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 500 {string}   string   "internal server error"
+// @Failure 501 {string}   string   "not implemented".
+func (mgmt *Mgmt) HandleRestoreMetaDB(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	if err := mgmt.MetaDB.Restore(request.Context(), request.Body); err != nil {
+		switch {
+		case errors.Is(err, zerr.ErrBadMetaDBBackup):
+			zcommon.WriteJSON(response, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, zerr.ErrMetaDBBackupNotSupported):
+			response.WriteHeader(http.StatusNotImplemented)
+		default:
+			mgmt.Log.Error().Err(err).Msg("mgmt: unable to restore the metadb")
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	mgmt.Log.Info().Str("user", getMgmtUsername(request)).Msg("mgmt: metadb restored")
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// LogLevels are the log levels in use, the global one and the ones of the components which have their own.
+type LogLevels struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+	// when the levels changed for a while are restored, not set if the levels aren't restored
+	RestoreAt *time.Time `json:"restoreAt,omitempty"`
+}
+
+// LogLevelsChange changes the log levels at runtime.
+type LogLevelsChange struct {
+	// the global level, unchanged if empty
+	Level string `json:"level,omitempty"`
+	// the levels of the components, unchanged if not listed, an empty level gives the component the global level
+	Components map[string]string `json:"components,omitempty"`
+	// the previous levels are restored after it, e.g. "15m", the levels are kept if empty
+	Duration string `json:"duration,omitempty"`
+}
+
+// GetLogLevels godoc
+// @Summary Get the log levels
+// @Description Get the global log level and the levels of the components which have their own
+// @Router  /v2/_zot/ext/mgmt/log [get]
+// @Produce json
+// @Success 200 {object}   extensions.LogLevels
+// @Failure 401 {string}   string   "unauthorized".
+func (mgmt *Mgmt) HandleGetLogLevels(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	mgmt.levelsLock.Lock()
+	defer mgmt.levelsLock.Unlock()
+
+	zcommon.WriteJSON(response, http.StatusOK, mgmt.getLogLevels())
+}
+
+// SetLogLevels godoc
+// @Summary Set the log levels
+// @Description Change the global log level and the levels of the components (storage, sync, search) without
+// @Description restarting, for a while if a duration is given.
+// @Router  /v2/_zot/ext/mgmt/log [put]
+// @Accept  json
+// @Produce json
+// @Param   levels   body     extensions.LogLevelsChange true "log levels"
+// @Success 200 {object}   extensions.LogLevels
+// @Failure 400 {string}   string   "bad request"
+// @Failure 401 {string}   string   "unauthorized".
+func (mgmt *Mgmt) HandleSetLogLevels(response http.ResponseWriter, request *http.Request) {
+	var change LogLevelsChange
+
+	body, err := io.ReadAll(http.MaxBytesReader(response, request.Body, maxLogLevelsSize))
+	if err != nil {
+		response.WriteHeader(http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&change); err != nil {
+		zcommon.WriteJSON(response, http.StatusBadRequest, map[string]string{"error": err.Error()})
+
+		return
+	}
+
+	var duration time.Duration
+
+	if change.Duration != "" {
+		if duration, err = time.ParseDuration(change.Duration); err != nil || duration <= 0 {
+			zcommon.WriteJSON(response, http.StatusBadRequest,
+				map[string]string{"error": fmt.Sprintf("invalid duration %q", change.Duration)})
+
+			return
+		}
+	}
+
+	mgmt.levelsLock.Lock()
+	defer mgmt.levelsLock.Unlock()
+
+	previous := log.GetLevels()
+
+	levels := log.GetLevels()
+	if change.Level != "" {
+		levels.Level = change.Level
+	}
+
+	for component, level := range change.Components {
+		if level == "" {
+			delete(levels.Components, component)
+
+			continue
+		}
+
+		levels.Components[component] = level
+	}
+
+	if err := log.SetLevels(levels); err != nil {
+		zcommon.WriteJSON(response, http.StatusBadRequest, map[string]string{"error": err.Error()})
+
+		return
+	}
+
+	// the levels from before the first of the changes made for a while are the ones restored
+	if mgmt.levelsRestore != nil {
+		mgmt.levelsRestore.timer.Stop()
+		previous = mgmt.levelsRestore.levels
+		mgmt.levelsRestore = nil
+	}
+
+	if duration > 0 {
+		restore := &logLevelsRestore{levels: previous, at: time.Now().Add(duration)}
+
+		restore.timer = time.AfterFunc(duration, func() {
+			mgmt.levelsLock.Lock()
+			defer mgmt.levelsLock.Unlock()
+
+			// replaced by a later change
+			if mgmt.levelsRestore != restore {
+				return
+			}
+
+			mgmt.levelsRestore = nil
+
+			if err := log.SetLevels(restore.levels); err != nil {
+				mgmt.Log.Error().Err(err).Msg("mgmt: unable to restore the log levels")
+
+				return
+			}
+
+			mgmt.Log.Info().Interface("levels", restore.levels).Msg("mgmt: log levels restored")
+		})
+
+		mgmt.levelsRestore = restore
+	}
+
+	mgmt.Log.Info().Str("user", getMgmtUsername(request)).Interface("levels", levels).
+		Str("duration", change.Duration).Msg("mgmt: log levels changed")
+
+	zcommon.WriteJSON(response, http.StatusOK, mgmt.getLogLevels())
+}
+
+func (mgmt *Mgmt) getLogLevels() LogLevels {
+	levels := log.GetLevels()
+
+	logLevels := LogLevels{Level: levels.Level, Components: levels.Components}
+
+	if mgmt.levelsRestore != nil {
+		restoreAt := mgmt.levelsRestore.at
+		logLevels.RestoreAt = &restoreAt
+	}
+
+	return logLevels
+}
+
+func getMgmtUsername(request *http.Request) string {
+	if userAc, err := reqCtx.UserAcFromContext(request.Context()); err == nil && userAc != nil {
+		return userAc.GetUsername()
+	}
+
+	return ""
+}
+
+// ClusterStatus is this zot instance and the members of its cluster.
+type ClusterStatus struct {
+	Self    cluster.MemberInfo     `json:"self"`
+	Members []cluster.MemberStatus `json:"members"`
+}
+
+// GetCluster godoc
+// @Summary Get the members of the cluster
+// @Description Get the zot instances of the cluster with the background tasks each one runs
+// @Router  /v2/_zot/ext/mgmt/cluster [get]
+// @Produce json
+// @Success 200 {object}   extensions.ClusterStatus
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func (mgmt *Mgmt) HandleGetCluster(response http.ResponseWriter, request *http.Request) {
+	if request.Method == http.MethodOptions {
+		return
+	}
+
+	if mgmt.Cluster == nil {
+		zcommon.WriteJSON(response, http.StatusNotFound, map[string]string{"error": zerr.ErrClusterNotEnabled.Error()})
+
+		return
+	}
+
+	members, err := mgmt.Cluster.Members()
+	if err != nil {
+		mgmt.Log.Error().Err(err).Msg("mgmt: unable to list the members of the cluster")
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, ClusterStatus{Self: mgmt.Cluster.Info(), Members: members})
+}
@@ -7,14 +7,22 @@ import (
 	"github.com/gorilla/mux"
 
 	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/cluster"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/maintenance"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/retention"
+	"zotregistry.io/zot/pkg/storage"
 )
 
 func IsBuiltWithMGMTExtension() bool {
 	return false
 }
 
-func SetupMgmtRoutes(config *config.Config, router *mux.Router, log log.Logger) {
+func SetupMgmtRoutes(config *config.Config, router *mux.Router, storeController storage.StoreController,
+	metaDB mTypes.MetaDB, eventRecorder EventRecorder, retentionManager *retention.Manager,
+	maintenanceManager *maintenance.Manager, clusterMember *cluster.Member, log log.Logger,
+) {
 	log.Warn().Msg("skipping setting up mgmt routes because given zot binary doesn't include this feature," +
 		"please build a binary that does so")
 }
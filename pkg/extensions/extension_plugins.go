@@ -0,0 +1,50 @@
+//go:build plugins
+// +build plugins
+
+package extensions
+
+import (
+	"github.com/gorilla/mux"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/plugins"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+type PluginManager = *plugins.Manager
+
+// GetPluginManager starts the configured plugins, nil if the plugins extension is disabled.
+func GetPluginManager(config *config.Config, log log.Logger) PluginManager {
+	if !config.ArePluginsEnabled() {
+		return nil
+	}
+
+	manager := plugins.NewManager(config.Extensions.Plugins, log)
+
+	log.Info().Int("plugins", len(config.Extensions.Plugins.Plugins)).Msg("plugins enabled")
+
+	return manager
+}
+
+func SetupPluginRoutes(config *config.Config, router *mux.Router, manager PluginManager, log log.Logger) {
+	if manager == nil {
+		log.Info().Msg("skip enabling the plugin routes as the config prerequisites are not met")
+
+		return
+	}
+
+	log.Info().Msg("setting up plugin routes")
+
+	manager.SetupRoutes(config, router)
+}
+
+func EnablePluginTasks(config *config.Config, taskScheduler *scheduler.Scheduler, manager PluginManager,
+	log log.Logger,
+) {
+	if manager == nil {
+		return
+	}
+
+	manager.EnableTasks(taskScheduler)
+}
@@ -0,0 +1,35 @@
+//go:build !plugins
+// +build !plugins
+
+package extensions
+
+import (
+	"github.com/gorilla/mux"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+type PluginManager interface {
+	ImagePushed(repo, reference, digest, mediaType string, manifest []byte, user string)
+	ImageDeleted(repo, reference, digest, mediaType, user string)
+	Close()
+}
+
+func GetPluginManager(config *config.Config, log log.Logger) PluginManager {
+	if config.ArePluginsEnabled() {
+		log.Warn().Msg("plugins extension is disabled because given zot binary doesn't " +
+			"include this feature please build a binary that does so")
+	}
+
+	return nil
+}
+
+func SetupPluginRoutes(config *config.Config, router *mux.Router, manager PluginManager, log log.Logger) {
+}
+
+func EnablePluginTasks(config *config.Config, taskScheduler *scheduler.Scheduler, manager PluginManager,
+	log log.Logger,
+) {
+}
@@ -9,14 +9,16 @@ import (
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/extensions/scrub"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/maintenance"
 	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage"
 	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
 
 // EnableScrubExtension enables scrub extension.
+// The corrupted blobs of the synced repos can be repaired from blobSource, i.e. from their upstream registries.
 func EnableScrubExtension(config *config.Config, log log.Logger, storeController storage.StoreController,
-	sch *scheduler.Scheduler,
+	maintenanceManager *maintenance.Manager, blobSource storageTypes.BlobSource,
 ) {
 	if config.Extensions.Scrub != nil &&
 		*config.Extensions.Scrub.Enable {
@@ -28,27 +30,48 @@ func EnableScrubExtension(config *config.Config, log log.Logger, storeController
 			log.Warn().Msg("Scrub interval set to too-short interval < 2h, changing scrub duration to 2 hours and continuing.") //nolint:lll // gofumpt conflicts with lll
 		}
 
+		repairer := scrub.NewRepairer(config.Extensions.Scrub.Repair, blobSource, log)
+		imgStores := []storageTypes.ImageStore{}
+
 		// is local imagestore (because of umoci dependency which works only locally)
 		if config.Storage.StorageDriver == nil {
-			generator := &taskGenerator{
-				imgStore: storeController.DefaultStore,
-				log:      log,
-			}
-			sch.SubmitGenerator(generator, config.Extensions.Scrub.Interval, scheduler.LowPriority)
+			imgStores = append(imgStores, storeController.DefaultStore)
 		}
 
 		if config.Storage.SubPaths != nil {
 			for route := range config.Storage.SubPaths {
 				// is local imagestore (because of umoci dependency which works only locally)
 				if config.Storage.SubPaths[route].StorageDriver == nil {
-					generator := &taskGenerator{
-						imgStore: storeController.SubStore[route],
-						log:      log,
-					}
-					sch.SubmitGenerator(generator, config.Extensions.Scrub.Interval, scheduler.LowPriority)
+					imgStores = append(imgStores, storeController.SubStore[route])
 				}
 			}
 		}
+
+		for _, imgStore := range imgStores {
+			generator := &taskGenerator{
+				imgStore: imgStore,
+				repairer: repairer,
+				log:      log,
+			}
+
+			maintenanceManager.SubmitGenerator(maintenance.Scrub, generator, config.Extensions.Scrub.Interval,
+				scheduler.LowPriority)
+		}
+
+		// the manual runs scrub the same image stores
+		maintenanceManager.Register(maintenance.Scrub, func() []scheduler.TaskGenerator {
+			generators := []scheduler.TaskGenerator{}
+
+			for _, imgStore := range imgStores {
+				generators = append(generators, &taskGenerator{
+					imgStore: imgStore,
+					repairer: repairer,
+					log:      log,
+				})
+			}
+
+			return generators
+		})
 	} else {
 		log.Info().Msg("Scrub config not provided, skipping scrub")
 	}
@@ -56,6 +79,7 @@ func EnableScrubExtension(config *config.Config, log log.Logger, storeController
 
 type taskGenerator struct {
 	imgStore storageTypes.ImageStore
+	repairer *scrub.Repairer
 	log      log.Logger
 	lastRepo string
 	done     bool
@@ -75,7 +99,7 @@ func (gen *taskGenerator) Next() (scheduler.Task, error) {
 
 	gen.lastRepo = repo
 
-	return scrub.NewTask(gen.imgStore, repo, gen.log), nil
+	return scrub.NewTask(gen.imgStore, repo, gen.repairer, gen.log), nil
 }
 
 func (gen *taskGenerator) IsDone() bool {
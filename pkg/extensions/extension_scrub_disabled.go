@@ -6,13 +6,14 @@ package extensions
 import (
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/log"
-	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/maintenance"
 	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
 
 // EnableScrubExtension ...
 func EnableScrubExtension(config *config.Config, log log.Logger, storeController storage.StoreController,
-	sch *scheduler.Scheduler,
+	maintenanceManager *maintenance.Manager, blobSource storageTypes.BlobSource,
 ) {
 	log.Warn().Msg("skipping enabling scrub extension because given zot binary doesn't include this feature," +
 		"please build a binary that does so")
@@ -9,12 +9,17 @@ import (
 	"sync"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	gqlHandler "github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/gorilla/mux"
 
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/api/constants"
 	zcommon "zotregistry.io/zot/pkg/common"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/search"
 	cveinfo "zotregistry.io/zot/pkg/extensions/search/cve"
 	"zotregistry.io/zot/pkg/extensions/search/gql_generated"
@@ -25,10 +30,14 @@ import (
 )
 
 type (
-	CveInfo cveinfo.CveInfo
-	state   int
+	CveInfo     cveinfo.CveInfo
+	SearchCache = *search.ResultCache
+	state       int
 )
 
+// licenseScanInterval is the time between two rounds of background license scans.
+const licenseScanInterval = 10 * time.Minute
+
 const (
 	pending state = iota
 	running
@@ -49,7 +58,26 @@ func GetCVEInfo(config *config.Config, storeController storage.StoreController,
 	dbRepository := config.Extensions.Search.CVE.Trivy.DBRepository
 	javaDBRepository := config.Extensions.Search.CVE.Trivy.JavaDBRepository
 
-	return cveinfo.NewCVEInfo(storeController, metaDB, dbRepository, javaDBRepository, log)
+	cveInfo := cveinfo.NewCVEInfo(storeController, metaDB, dbRepository, javaDBRepository, log)
+
+	if config.Extensions.Search.CVE.Repositories != nil {
+		cveInfo.Scanner = cveinfo.NewRepoFilterScanner(cveInfo.Scanner, config.Extensions.Search.CVE.Repositories, log)
+	}
+
+	return cveInfo
+}
+
+func GetSearchCache(config *config.Config, log log.Logger) SearchCache {
+	if !config.IsSearchEnabled() {
+		return nil
+	}
+
+	return search.NewResultCache(config.Extensions.Search.ResultCacheTTL, log)
+}
+
+// WrapMetaDBWithSearchCache makes every MetaDB write changing search results invalidate the search cache.
+func WrapMetaDBWithSearchCache(searchCache SearchCache, metaDB mTypes.MetaDB) mTypes.MetaDB {
+	return searchCache.WrapMetaDB(metaDB)
 }
 
 func EnableSearchExtension(config *config.Config, storeController storage.StoreController,
@@ -58,21 +86,69 @@ func EnableSearchExtension(config *config.Config, storeController storage.StoreC
 	if config.Extensions.Search != nil && *config.Extensions.Search.Enable && config.Extensions.Search.CVE != nil {
 		updateInterval := config.Extensions.Search.CVE.UpdateInterval
 
-		downloadTrivyDB(updateInterval, taskScheduler, cveInfo, log)
+		var notifier *cveinfo.CVENotifier
+
+		if config.Extensions.Search.CVE.Notifications != nil {
+			notifier = cveinfo.NewCVENotifier(config.Extensions.Search.CVE.Notifications, metaDB, cveInfo, log)
+		}
+
+		downloadTrivyDB(updateInterval, taskScheduler, cveInfo, notifier, log)
+		scanLicenses(taskScheduler, metaDB, cveInfo, log)
+
+		if config.Extensions.Search.CVE.Rescan != nil {
+			rescanImages(config.Extensions.Search.CVE.Rescan, taskScheduler, metaDB, cveInfo, log)
+		}
 	} else {
 		log.Info().Msg("CVE config not provided, skipping CVE update")
 	}
+
+	if config.AreDownloadStatsEnabled() {
+		downloadStatsConfig := config.Extensions.Search.DownloadStats
+
+		generator, interval := search.NewDownloadStatsRetentionTaskGenerator(metaDB, downloadStatsConfig.Retention,
+			downloadStatsConfig.HourlyRetention, log)
+
+		log.Info().Msg("Submitting download statistics retention scheduler")
+		taskScheduler.SubmitSharedGenerator("download-stats-retention", generator, interval, scheduler.LowPriority)
+	}
 }
 
-func downloadTrivyDB(interval time.Duration, sch *scheduler.Scheduler, cveInfo CveInfo, log log.Logger) {
-	generator := NewTrivyTaskGenerator(interval, cveInfo, log)
+func downloadTrivyDB(interval time.Duration, sch *scheduler.Scheduler, cveInfo CveInfo,
+	notifier *cveinfo.CVENotifier, log log.Logger,
+) {
+	generator := NewTrivyTaskGenerator(interval, cveInfo, notifier, log)
 
 	log.Info().Msg("Submitting CVE DB update scheduler")
 	sch.SubmitGenerator(generator, interval, scheduler.HighPriority)
 }
 
-func NewTrivyTaskGenerator(interval time.Duration, cveInfo CveInfo, log log.Logger) *TrivyTaskGenerator {
-	generator := &TrivyTaskGenerator{interval, cveInfo, log, pending, 0, time.Now(), &sync.Mutex{}}
+// scanLicenses periodically scans the images for licenses in the background, the license queries
+// only read the results of these scans. Images already scanned are skipped, so the interval is short
+// to have the images pushed in the meantime scanned soon.
+func scanLicenses(sch *scheduler.Scheduler, metaDB mTypes.MetaDB, cveInfo CveInfo, log log.Logger) {
+	generator := cveinfo.NewLicenseScanTaskGenerator(metaDB, cveInfo, log)
+
+	log.Info().Msg("Submitting license scan scheduler")
+	sch.SubmitGenerator(generator, licenseScanInterval, scheduler.LowPriority)
+}
+
+// rescanImages periodically rescans the images for CVEs, the scan results are cached until the next
+// CVE DB update, rescanning them refreshes the results before they are queried.
+func rescanImages(rescanConfig *extconf.CVERescanConfig, sch *scheduler.Scheduler, metaDB mTypes.MetaDB,
+	cveInfo CveInfo, log log.Logger,
+) {
+	generator := cveinfo.NewRescanTaskGenerator(metaDB, cveInfo, rescanConfig.PulledOnly, log)
+
+	log.Info().Msg("Submitting CVE rescan scheduler")
+	sch.SubmitGenerator(generator, rescanConfig.Interval, scheduler.LowPriority)
+}
+
+// NewTrivyTaskGenerator returns a generator of CVE DB updates, the notifier is optional and
+// notifies the images newly affected by CVEs after each update.
+func NewTrivyTaskGenerator(interval time.Duration, cveInfo CveInfo, notifier *cveinfo.CVENotifier,
+	log log.Logger,
+) *TrivyTaskGenerator {
+	generator := &TrivyTaskGenerator{interval, cveInfo, notifier, log, pending, 0, time.Now(), &sync.Mutex{}}
 
 	return generator
 }
@@ -80,6 +156,7 @@ func NewTrivyTaskGenerator(interval time.Duration, cveInfo CveInfo, log log.Logg
 type TrivyTaskGenerator struct {
 	interval     time.Duration
 	cveInfo      CveInfo
+	notifier     *cveinfo.CVENotifier
 	log          log.Logger
 	status       state
 	waitTime     time.Duration
@@ -158,11 +235,18 @@ func (trivyT *trivyTask) DoWork(ctx context.Context) error {
 	trivyT.generator.lock.Unlock()
 	trivyT.log.Info().Str("DB update completed, next update scheduled after", trivyT.interval.String()).Msg("")
 
+	if trivyT.generator.notifier != nil {
+		// the DB is up to date, a failed notification doesn't fail the update
+		if err := trivyT.generator.notifier.Notify(ctx); err != nil {
+			trivyT.log.Error().Err(err).Msg("unable to notify images newly affected by CVEs")
+		}
+	}
+
 	return nil
 }
 
 func SetupSearchRoutes(conf *config.Config, router *mux.Router, storeController storage.StoreController,
-	metaDB mTypes.MetaDB, cveInfo CveInfo, log log.Logger,
+	metaDB mTypes.MetaDB, cveInfo CveInfo, searchCache SearchCache, log log.Logger,
 ) {
 	if !conf.IsSearchEnabled() {
 		log.Info().Msg("skip enabling the search route as the config prerequisites are not met")
@@ -172,7 +256,12 @@ func SetupSearchRoutes(conf *config.Config, router *mux.Router, storeController
 
 	log.Info().Msg("setting up search routes")
 
-	resConfig := search.GetResolverConfig(log, storeController, metaDB, cveInfo)
+	resConfig := search.GetResolverConfig(log, storeController, metaDB, cveInfo, searchCache)
+
+	persistedQueries, err := search.NewPersistedQueries(conf.Extensions.Search.PersistedQueries, log)
+	if err != nil {
+		panic(err)
+	}
 
 	allowedMethods := zcommon.AllowedMethods(http.MethodGet, http.MethodPost)
 
@@ -180,8 +269,64 @@ func SetupSearchRoutes(conf *config.Config, router *mux.Router, storeController
 	extRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
 	extRouter.Use(zcommon.ACHeadersMiddleware(conf, allowedMethods...))
 	extRouter.Use(zcommon.AddExtensionSecurityHeaders())
-	extRouter.Methods(allowedMethods...).
-		Handler(gqlHandler.NewDefaultServer(gql_generated.NewExecutableSchema(resConfig)))
+
+	schema := gql_generated.NewExecutableSchema(resConfig)
+
+	// REST wrappers over the most common queries, registered before the GraphQL handler which matches any path
+	restHandler := search.NewRESTHandler(schema, metaDB, log)
+	extRouter.HandleFunc(constants.ExtSearchREST+"/repos", restHandler.ListRepos).Methods(http.MethodGet)
+	extRouter.HandleFunc(constants.ExtSearchREST+"/images", restHandler.ListImages).Methods(http.MethodGet)
+	extRouter.HandleFunc(constants.ExtSearchREST+"/image", restHandler.GetImage).Methods(http.MethodGet)
+
+	if conf.Extensions.Search.Federation != nil {
+		federatedHandler := search.NewFederatedHandler(schema, conf.Extensions.Search.Federation,
+			persistedQueries, log)
+		extRouter.HandleFunc(constants.ExtSearchFederated, federatedHandler.Search).Methods(allowedMethods...)
+	}
+
+	var statsHandler *search.DownloadStatsHandler
+
+	if conf.AreDownloadStatsEnabled() {
+		statsHandler = search.NewDownloadStatsHandler(metaDB, log)
+		extRouter.HandleFunc(constants.ExtSearchStats+"/top", statsHandler.TopDownloads).Methods(http.MethodGet)
+		extRouter.HandleFunc(constants.ExtSearchStats+"/downloads", statsHandler.DownloadsOverTime).
+			Methods(http.MethodGet)
+		extRouter.HandleFunc(constants.ExtSearchStats+"/lastpulled", statsHandler.LastPulls).Methods(http.MethodGet)
+	}
+
+	registryStatsHandler := search.NewRegistryStatsHandler(metaDB, conf.Storage.Dedupe, statsHandler, log)
+	extRouter.HandleFunc(constants.ExtSearchStats, registryStatsHandler.RegistryStats).Methods(http.MethodGet)
+
+	extRouter.Methods(allowedMethods...).Handler(newGraphQLServer(schema, persistedQueries))
+
+	readmeMethods := zcommon.AllowedMethods(http.MethodGet, http.MethodPut, http.MethodDelete)
+
+	readmeRouter := router.PathPrefix(constants.ExtReadme).Subrouter()
+	readmeRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+	readmeRouter.Use(zcommon.ACHeadersMiddleware(conf, readmeMethods...))
+	readmeRouter.Use(zcommon.AddExtensionSecurityHeaders())
+	readmeRouter.Methods(readmeMethods...).Handler(search.NewReadmeHandler(metaDB, log))
 
 	log.Info().Msg("finished setting up search routes")
 }
+
+// newGraphQLServer is the gqlgen default server, using our persisted queries instead of the default APQ cache.
+func newGraphQLServer(schema graphql.ExecutableSchema, persistedQueries *search.PersistedQueries,
+) *gqlHandler.Server {
+	srv := gqlHandler.New(schema)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second, //nolint:gomnd
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New(1000)) //nolint:gomnd
+
+	srv.Use(extension.Introspection{})
+	srv.Use(persistedQueries)
+
+	return srv
+}
@@ -15,12 +15,24 @@ import (
 
 type CveInfo interface{}
 
+type SearchCache interface {
+	Invalidate()
+}
+
 func GetCVEInfo(config *config.Config, storeController storage.StoreController,
 	metaDB mTypes.MetaDB, log log.Logger,
 ) CveInfo {
 	return nil
 }
 
+func GetSearchCache(config *config.Config, log log.Logger) SearchCache {
+	return nil
+}
+
+func WrapMetaDBWithSearchCache(searchCache SearchCache, metaDB mTypes.MetaDB) mTypes.MetaDB {
+	return metaDB
+}
+
 func IsBuiltWithSearchExtension() bool {
 	return false
 }
@@ -35,7 +47,7 @@ func EnableSearchExtension(config *config.Config, storeController storage.StoreC
 
 // SetupSearchRoutes ...
 func SetupSearchRoutes(config *config.Config, router *mux.Router, storeController storage.StoreController,
-	metaDB mTypes.MetaDB, cveInfo CveInfo, log log.Logger,
+	metaDB mTypes.MetaDB, cveInfo CveInfo, searchCache SearchCache, log log.Logger,
 ) {
 	log.Warn().Msg("skipping setting up search routes because given zot binary doesn't include this feature," +
 		"please build a binary that does so")
@@ -58,7 +58,7 @@ func TestTrivyDBGenerator(t *testing.T) {
 		}
 
 		cveInfo := cveinfo.NewCVEInfo(storeController, metaDB, "ghcr.io/project-zot/trivy-db", "", logger)
-		generator := NewTrivyTaskGenerator(time.Minute, cveInfo, logger)
+		generator := NewTrivyTaskGenerator(time.Minute, cveInfo, nil, logger)
 
 		sch.SubmitGenerator(generator, 12000*time.Millisecond, scheduler.HighPriority)
 
@@ -0,0 +1,40 @@
+//go:build secrets
+// +build secrets
+
+package extensions
+
+import (
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/secrets"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/storage"
+)
+
+type SecretScanner = *secrets.Scanner
+
+func GetSecretScanner(config *config.Config, storeController storage.StoreController, metaDB mTypes.MetaDB,
+	log log.Logger,
+) SecretScanner {
+	if !config.IsSecretScanningEnabled() {
+		return nil
+	}
+
+	log.Info().Bool("rejectPush", config.Extensions.Secrets.RejectPush).Msg("secret scanning of pushed images enabled")
+
+	return secrets.NewScanner(config.Extensions.Secrets, storeController, metaDB, log)
+}
+
+// EnableSecretScanningExtension makes the scanner run the scans of pushed images through the scheduler.
+func EnableSecretScanningExtension(config *config.Config, secretScanner SecretScanner,
+	taskScheduler *scheduler.Scheduler, log log.Logger,
+) {
+	if !config.IsSecretScanningEnabled() || secretScanner == nil {
+		log.Info().Msg("secret scanning config not provided, skipping secret scanning")
+
+		return
+	}
+
+	secretScanner.SetTaskScheduler(taskScheduler)
+}
@@ -0,0 +1,40 @@
+//go:build !secrets
+// +build !secrets
+
+package extensions
+
+import (
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+type SecretScanner interface {
+	VerifyManifest(repo, mediaType string, body []byte, imgStore storageTypes.ImageStore) error
+	OnManifestPushed(repo string, subject ispec.Descriptor, body []byte)
+}
+
+func GetSecretScanner(config *config.Config, storeController storage.StoreController, metaDB mTypes.MetaDB,
+	log log.Logger,
+) SecretScanner {
+	if config.IsSecretScanningEnabled() {
+		log.Warn().Msg("secrets extension is disabled because given zot binary doesn't " +
+			"include this feature please build a binary that does so")
+	}
+
+	return nil
+}
+
+func EnableSecretScanningExtension(config *config.Config, secretScanner SecretScanner,
+	taskScheduler *scheduler.Scheduler, log log.Logger,
+) {
+	if config.IsSecretScanningEnabled() {
+		log.Warn().Msg("skipping secret scanning because given zot binary doesn't include this feature," +
+			"please build a binary that does so")
+	}
+}
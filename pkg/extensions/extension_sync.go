@@ -4,26 +4,72 @@
 package extensions
 
 import (
+	"io"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 
+	"github.com/gorilla/mux"
+
 	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	zcommon "zotregistry.io/zot/pkg/common"
 	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
 	"zotregistry.io/zot/pkg/extensions/sync"
+	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
 	"zotregistry.io/zot/pkg/log"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
 	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
 
+type SyncStatus = *sync.StatusTracker
+
+// GetSyncStatus returns the tracker of the sync state, it's kept across config reloads.
+func GetSyncStatus(metrics monitoring.MetricServer) SyncStatus {
+	return sync.NewStatusTracker(metrics)
+}
+
+type SyncTrigger = *sync.Trigger
+
+// GetSyncBlobSource returns the source of the blobs of the synced repos, i.e. their upstream registries.
+func GetSyncBlobSource(syncTrigger SyncTrigger) storageTypes.BlobSource {
+	if syncTrigger == nil {
+		return nil
+	}
+
+	return syncTrigger
+}
+
+// GetSyncTrigger returns the receiver of the upstream notifications, it's kept across config reloads.
+func GetSyncTrigger(log log.Logger) SyncTrigger {
+	return sync.NewTrigger(log)
+}
+
 func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
-	storeController storage.StoreController, sch *scheduler.Scheduler, log log.Logger,
+	storeController storage.StoreController, sch *scheduler.Scheduler, syncStatus SyncStatus, syncTrigger SyncTrigger,
+	log log.Logger,
 ) (*sync.BaseOnDemand, error) {
+	// the registries are added again by the reloaded config
+	if syncStatus != nil {
+		syncStatus.Reset()
+	}
+
+	if syncTrigger != nil {
+		syncTrigger.Reset()
+	}
+
 	if config.Extensions.Sync != nil && *config.Extensions.Sync.Enable {
 		onDemand := sync.NewOnDemand(log)
 
+		// the global download rate limit is shared by the registries
+		downloadLimiter := client.NewRateLimiter(config.Extensions.Sync.MaxDownloadRate)
+		events := sync.NewEventNotifier(config.Extensions.Sync.Webhooks, log)
+
 		for _, registryConfig := range config.Extensions.Sync.Registries {
 			registryConfig := registryConfig
 			if len(registryConfig.URLs) > 1 {
@@ -38,26 +84,34 @@ func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
 				return nil, zerr.ErrSyncNoURLsLeft
 			}
 
-			isPeriodical := len(registryConfig.Content) != 0 && registryConfig.PollInterval != 0
+			schedules := sync.GetContentSchedules(registryConfig)
+
+			isPeriodical := len(schedules) != 0
 			isOnDemand := registryConfig.OnDemand
 
 			if isPeriodical || isOnDemand {
 				service, err := sync.New(registryConfig, config.Extensions.Sync.CredentialsFile,
-					storeController, metaDB, log)
+					storeController, metaDB, downloadLimiter, syncStatus, events, log)
 				if err != nil {
 					return nil, err
 				}
 
 				if isPeriodical {
-					// add to task scheduler periodic sync
-					gen := sync.NewTaskGenerator(service, log)
-					sch.SubmitGenerator(gen, registryConfig.PollInterval, scheduler.MediumPriority)
+					// add to task scheduler periodic sync, a generator for each group of content rules
+					for _, schedule := range schedules {
+						gen := sync.NewTaskGenerator(service, schedule.ContentIDs, log)
+						sch.SubmitSharedGenerator("sync", gen, schedule.Interval, schedule.Priority)
+					}
 				}
 
 				if isOnDemand {
 					// onDemand services used in routes.go
 					onDemand.Add(service)
 				}
+
+				if syncTrigger != nil {
+					syncTrigger.Add(registryConfig.URLs, service)
+				}
 			}
 		}
 
@@ -69,6 +123,95 @@ func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
 	return nil, nil //nolint: nilnil
 }
 
+func SetupSyncRoutes(conf *config.Config, router *mux.Router, syncStatus SyncStatus, syncTrigger SyncTrigger,
+	log log.Logger,
+) {
+	if !conf.IsSyncEnabled() || syncStatus == nil || syncTrigger == nil {
+		log.Info().Msg("skip enabling the sync routes as the config prerequisites are not met")
+
+		return
+	}
+
+	log.Info().Msg("setting up sync routes")
+
+	allowedMethods := zcommon.AllowedMethods(http.MethodGet)
+
+	statusRouter := router.PathPrefix(constants.ExtSyncStatus).Subrouter()
+	statusRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+	statusRouter.Use(zcommon.AddExtensionSecurityHeaders())
+	statusRouter.Use(zcommon.ACHeadersMiddleware(conf, allowedMethods...))
+	// the sync state exposes the upstream registries, it's available only to admins
+	statusRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+	statusRouter.Methods(allowedMethods...).Handler(HandleSyncStatus(syncStatus))
+
+	triggerMethods := zcommon.AllowedMethods(http.MethodPost)
+
+	triggerRouter := router.PathPrefix(constants.ExtSyncTrigger).Subrouter()
+	triggerRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+	triggerRouter.Use(zcommon.AddExtensionSecurityHeaders())
+	triggerRouter.Use(zcommon.ACHeadersMiddleware(conf, triggerMethods...))
+	// the upstream registries and CIs notifying pushes authenticate as admins
+	triggerRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+	triggerRouter.Methods(triggerMethods...).Handler(HandleSyncTrigger(syncTrigger, log))
+
+	log.Info().Msg("finished setting up sync routes")
+}
+
+// SyncStatus godoc
+// @Summary Get the sync state of the upstream registries
+// @Description Get the last success, last error, images pending and bytes transferred of each synced registry and repo
+// @Router  /v2/_zot/ext/sync/status [get]
+// @Accept  json
+// @Produce json
+// @Success 200 {object}   sync.Status
+// @Failure 401 {string}   string   "unauthorized".
+func HandleSyncStatus(syncStatus SyncStatus) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		zcommon.WriteJSON(response, http.StatusOK, syncStatus.GetStatus())
+	})
+}
+
+// SyncTrigger godoc
+// @Summary Trigger the sync of upstream images
+// @Description Sync the upstream images right away instead of waiting for the next periodic sync, the body is
+// @Description either trigger requests or a docker distribution notification envelope whose push events are synced
+// @Router  /v2/_zot/ext/sync/trigger [post]
+// @Accept  json
+// @Produce json
+// @Param   requests  body  []sync.TriggerRequest  true  "images to sync, every tag of the repo if the tag is empty"
+// @Success 202 {string}   string   "accepted"
+// @Failure 400 {string}   string   "bad request"
+// @Failure 401 {string}   string   "unauthorized"
+// @Failure 404 {string}   string   "not found".
+func HandleSyncTrigger(syncTrigger SyncTrigger, log log.Logger) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			log.Error().Err(err).Msg("sync: unable to read trigger body")
+			response.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		requests, err := sync.ParseTriggerRequests(body)
+		if err != nil {
+			log.Info().Err(err).Msg("sync: invalid trigger body")
+			response.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if err := syncTrigger.Sync(requests); err != nil {
+			log.Info().Err(err).Msg("sync: unable to trigger sync")
+			response.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		response.WriteHeader(http.StatusAccepted)
+	})
+}
+
 func getLocalIPs() ([]string, error) {
 	var localIPs []string
 
@@ -4,20 +4,49 @@
 package extensions
 
 import (
+	"github.com/gorilla/mux"
+
 	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
 	"zotregistry.io/zot/pkg/extensions/sync"
 	"zotregistry.io/zot/pkg/log"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
 	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
 
+type SyncStatus interface{}
+
+func GetSyncStatus(metrics monitoring.MetricServer) SyncStatus {
+	return nil
+}
+
+type SyncTrigger interface{}
+
+func GetSyncTrigger(log log.Logger) SyncTrigger {
+	return nil
+}
+
+func GetSyncBlobSource(syncTrigger SyncTrigger) storageTypes.BlobSource {
+	return nil
+}
+
 // EnableSyncExtension ...
 func EnableSyncExtension(config *config.Config, metaDB mTypes.MetaDB,
-	storeController storage.StoreController, sch *scheduler.Scheduler, log log.Logger,
+	storeController storage.StoreController, sch *scheduler.Scheduler, syncStatus SyncStatus, syncTrigger SyncTrigger,
+	log log.Logger,
 ) (*sync.BaseOnDemand, error) {
 	log.Warn().Msg("skipping enabling sync extension because given zot binary doesn't include this feature," +
 		"please build a binary that does so")
 
 	return nil, nil //nolint: nilnil
 }
+
+// SetupSyncRoutes ...
+func SetupSyncRoutes(conf *config.Config, router *mux.Router, syncStatus SyncStatus, syncTrigger SyncTrigger,
+	log log.Logger,
+) {
+	log.Warn().Msg("skipping setting up sync routes because given zot binary doesn't include this feature," +
+		"please build a binary that does so")
+}
@@ -4,9 +4,13 @@
 package extensions
 
 import (
+	"bytes"
 	"embed"
+	"encoding/json"
 	"io/fs"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -21,20 +25,71 @@ import (
 //go:embed build/*
 var content embed.FS
 
+// uiContent is the UI bundle served when no directory is configured.
+var uiContent = getEmbeddedUIContent()
+
+// the client side routes of the zot UI, they're all served its index.html.
+var defaultUIRoutes = []string{"/login", "/home", "/explore", "/image"}
+
+// SetUIContent replaces the embedded UI bundle, e.g. by a distribution shipping its own UI,
+// it must be called before the routes are set up and the bundle must contain an index.html at its root.
+func SetUIContent(bundle fs.FS) {
+	uiContent = bundle
+}
+
+func getEmbeddedUIContent() fs.FS {
+	fsub, _ := fs.Sub(content, "build")
+
+	return fsub
+}
+
+// UIRuntimeConfig is the configuration given to the UI at runtime,
+// it's injected in its index.html as the window.__ZOT_UI_CONFIG__ object.
+type UIRuntimeConfig struct {
+	APIBaseURL string            `json:"apiBaseURL"`
+	PathPrefix string            `json:"pathPrefix"`
+	AuthModes  []string          `json:"authModes"`
+	Branding   map[string]string `json:"branding"`
+}
+
 type uiHandler struct {
-	log log.Logger
+	bundle        fs.FS
+	runtimeConfig []byte
+	log           log.Logger
 }
 
 func (uih uiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	buf, _ := content.ReadFile("build/index.html")
+	buf, err := fs.ReadFile(uih.bundle, "index.html")
+	if err != nil {
+		uih.log.Error().Err(err).Msg("unable to read index.html")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
 
-	_, err := w.Write(buf)
+	script := []byte("<script>window.__ZOT_UI_CONFIG__ = " + string(uih.runtimeConfig) + ";</script>")
+
+	// the config is set before the scripts of the UI are run
+	if bytes.Contains(buf, []byte("</head>")) {
+		buf = bytes.Replace(buf, []byte("</head>"), append(script, []byte("</head>")...), 1)
+	} else {
+		buf = append(script, buf...)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	_, err = w.Write(buf)
 	if err != nil {
 		uih.log.Error().Err(err).Msg("unable to serve index.html")
 	}
 }
 
-func addUISecurityHeaders(h http.Handler) http.HandlerFunc { //nolint:varnamelen
+func addUISecurityHeaders(h http.Handler, apiOrigin string) http.HandlerFunc { //nolint:varnamelen
+	connectSources := "connect-src 'self'"
+	if apiOrigin != "" {
+		connectSources += " " + apiOrigin
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		permissionsPolicy := "microphone=(), geolocation=(), battery=(), camera=(), autoplay=(), gyroscope=(), payment=()"
 		w.Header().Set("Permissions-Policy", permissionsPolicy)
@@ -46,7 +101,7 @@ func addUISecurityHeaders(h http.Handler) http.HandlerFunc { //nolint:varnamelen
 			"script-src 'self' 'unsafe-inline'",
 			"style-src 'self' 'unsafe-inline'",
 			"font-src 'self'",
-			"connect-src 'self'",
+			connectSources,
 			"img-src 'self'",
 			"manifest-src 'self'",
 			"base-uri 'self'",
@@ -57,6 +112,39 @@ func addUISecurityHeaders(h http.Handler) http.HandlerFunc { //nolint:varnamelen
 	}
 }
 
+func getUIRuntimeConfig(conf *config.Config, pathPrefix string) UIRuntimeConfig {
+	uiConfig := conf.Extensions.UI
+
+	runtimeConfig := UIRuntimeConfig{
+		APIBaseURL: uiConfig.APIBaseURL,
+		PathPrefix: pathPrefix,
+		AuthModes:  []string{},
+		Branding:   map[string]string{},
+	}
+
+	if conf.IsHtpasswdAuthEnabled() || conf.IsLdapAuthEnabled() {
+		runtimeConfig.AuthModes = append(runtimeConfig.AuthModes, "basic")
+	}
+
+	if conf.IsOpenIDAuthEnabled() {
+		runtimeConfig.AuthModes = append(runtimeConfig.AuthModes, "openid")
+	}
+
+	if conf.IsBearerAuthEnabled() {
+		runtimeConfig.AuthModes = append(runtimeConfig.AuthModes, "bearer")
+	}
+
+	if conf.IsMTLSAuthEnabled() {
+		runtimeConfig.AuthModes = append(runtimeConfig.AuthModes, "mtls")
+	}
+
+	for key, value := range uiConfig.Branding {
+		runtimeConfig.Branding[key] = value
+	}
+
+	return runtimeConfig
+}
+
 func SetupUIRoutes(conf *config.Config, router *mux.Router,
 	log log.Logger,
 ) {
@@ -68,8 +156,35 @@ func SetupUIRoutes(conf *config.Config, router *mux.Router,
 
 	log.Info().Msg("setting up ui routes")
 
-	fsub, _ := fs.Sub(content, "build")
-	uih := uiHandler{log: log}
+	uiConfig := conf.Extensions.UI
+
+	bundle := uiContent
+	if uiConfig.Directory != "" {
+		bundle = os.DirFS(uiConfig.Directory)
+	}
+
+	routes := uiConfig.Routes
+	if len(routes) == 0 {
+		routes = defaultUIRoutes
+	}
+
+	// the prefix of the routes, empty if the UI is served at the root
+	pathPrefix := strings.TrimSuffix(uiConfig.PathPrefix, "/")
+
+	runtimeConfig, err := json.Marshal(getUIRuntimeConfig(conf, pathPrefix+"/"))
+	if err != nil {
+		log.Error().Err(err).Msg("unable to encode the ui runtime config")
+
+		return
+	}
+
+	var apiOrigin string
+
+	if apiURL, err := url.Parse(uiConfig.APIBaseURL); err == nil && apiURL.Host != "" {
+		apiOrigin = apiURL.Scheme + "://" + apiURL.Host
+	}
+
+	uih := uiHandler{bundle: bundle, runtimeConfig: runtimeConfig, log: log}
 
 	// See https://go-review.googlesource.com/c/go/+/482635/2/src/net/http/fs.go
 	// See https://github.com/golang/go/issues/59469
@@ -79,16 +194,23 @@ func SetupUIRoutes(conf *config.Config, router *mux.Router,
 	// If we don't add this, all unmatched http methods on any urls would match the UI routes.
 	allowedMethods := zcommon.AllowedMethods(http.MethodGet)
 
-	router.PathPrefix("/login").Methods(allowedMethods...).
-		Handler(addUISecurityHeaders(uih))
-	router.PathPrefix("/home").Methods(allowedMethods...).
-		Handler(addUISecurityHeaders(uih))
-	router.PathPrefix("/explore").Methods(allowedMethods...).
-		Handler(addUISecurityHeaders(uih))
-	router.PathPrefix("/image").Methods(allowedMethods...).
-		Handler(addUISecurityHeaders(uih))
-	router.PathPrefix("/").Methods(allowedMethods...).
-		Handler(addUISecurityHeaders(http.FileServer(http.FS(fsub))))
-
-	log.Info().Msg("finished setting up ui routes")
+	for _, route := range routes {
+		router.PathPrefix(pathPrefix + route).Methods(allowedMethods...).
+			Handler(addUISecurityHeaders(uih, apiOrigin))
+	}
+
+	if pathPrefix != "" {
+		router.Path(pathPrefix).Methods(allowedMethods...).
+			Handler(http.RedirectHandler(pathPrefix+"/", http.StatusFound))
+	}
+
+	// the file server would serve the index.html without the runtime config
+	router.Path(pathPrefix + "/").Methods(allowedMethods...).
+		Handler(addUISecurityHeaders(uih, apiOrigin))
+	router.Path(pathPrefix + "/index.html").Methods(allowedMethods...).
+		Handler(addUISecurityHeaders(uih, apiOrigin))
+	router.PathPrefix(pathPrefix + "/").Methods(allowedMethods...).
+		Handler(addUISecurityHeaders(http.StripPrefix(pathPrefix, http.FileServer(http.FS(bundle))), apiOrigin))
+
+	log.Info().Str("pathPrefix", pathPrefix+"/").Msg("finished setting up ui routes")
 }
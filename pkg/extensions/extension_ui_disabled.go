@@ -4,6 +4,8 @@
 package extensions
 
 import (
+	"io/fs"
+
 	"github.com/gorilla/mux"
 
 	"zotregistry.io/zot/pkg/api/config"
@@ -16,3 +18,7 @@ func SetupUIRoutes(conf *config.Config, router *mux.Router,
 	log.Warn().Msg("skipping setting up ui routes because given zot binary doesn't include this feature," +
 		"please build a binary that does so")
 }
+
+// SetUIContent is a no-op, the given zot binary doesn't include the ui.
+func SetUIContent(bundle fs.FS) {
+}
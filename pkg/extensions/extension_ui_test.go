@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -50,7 +51,7 @@ func TestUIExtension(t *testing.T) {
 		ctlrManager := test.NewControllerManager(ctlr)
 		ctlrManager.StartAndWait(port)
 
-		found, err := test.ReadLogFileAndSearchString(logPath, "\"UI\":{\"Enable\":true}", 2*time.Minute)
+		found, err := test.ReadLogFileAndSearchString(logPath, "\"UI\":{\"Enable\":true,", 2*time.Minute)
 		So(found, ShouldBeTrue)
 		So(err, ShouldBeNil)
 
@@ -104,3 +105,72 @@ func TestUIExtension(t *testing.T) {
 		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
 	})
 }
+
+func TestUICustomBundle(t *testing.T) {
+	Convey("Verify the UI extension serves a custom bundle under a path prefix", t, func() {
+		conf := config.New()
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf.HTTP.Port = port
+
+		bundleDir := t.TempDir()
+		err := os.WriteFile(path.Join(bundleDir, "index.html"),
+			[]byte("<html><head><title>custom</title></head><body></body></html>"), 0o600)
+		So(err, ShouldBeNil)
+		err = os.WriteFile(path.Join(bundleDir, "app.js"), []byte("console.log('custom')"), 0o600)
+		So(err, ShouldBeNil)
+
+		defaultValue := true
+
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &defaultValue},
+		}
+		conf.Extensions.UI = &extconf.UIConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &defaultValue},
+			Directory:  bundleDir,
+			PathPrefix: "/registry-ui",
+			Routes:     []string{"/dashboard"},
+			APIBaseURL: "https://registry.example.com",
+			Branding:   map[string]string{"title": "Example Registry"},
+		}
+		conf.Storage.RootDirectory = t.TempDir()
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		for _, uiPath := range []string{"/registry-ui/", "/registry-ui/dashboard/repo"} {
+			resp, err := resty.R().Get(baseURL + uiPath)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(string(resp.Body()), ShouldStartWith, "<html><head><title>custom</title><script>window.__ZOT_UI_CONFIG__ = ")
+			So(string(resp.Body()), ShouldContainSubstring, `"apiBaseURL":"https://registry.example.com"`)
+			So(string(resp.Body()), ShouldContainSubstring, `"pathPrefix":"/registry-ui/"`)
+			So(string(resp.Body()), ShouldContainSubstring, `"branding":{"title":"Example Registry"}`)
+			So(resp.Header().Get("Content-Security-Policy"), ShouldContainSubstring,
+				"connect-src 'self' https://registry.example.com")
+		}
+
+		client := resty.New().SetRedirectPolicy(resty.NoRedirectPolicy())
+		resp, _ := client.R().Get(baseURL + "/registry-ui")
+		So(resp.StatusCode(), ShouldEqual, http.StatusFound)
+		So(resp.Header().Get("Location"), ShouldEqual, "/registry-ui/")
+
+		resp, err = resty.R().Get(baseURL + "/registry-ui/app.js")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(string(resp.Body()), ShouldEqual, "console.log('custom')")
+
+		// the routes of the zot UI aren't served by the custom bundle
+		resp, err = resty.R().Get(baseURL + "/registry-ui/home")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = resty.R().Get(baseURL + "/dashboard")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+	})
+}
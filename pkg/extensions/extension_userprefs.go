@@ -4,8 +4,11 @@
 package extensions
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"sort"
 
 	"github.com/gorilla/mux"
 
@@ -14,12 +17,16 @@ import (
 	"zotregistry.io/zot/pkg/api/constants"
 	zcommon "zotregistry.io/zot/pkg/common"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
 )
 
 const (
 	ToggleRepoBookmarkAction = "toggleBookmark"
 	ToggleRepoStarAction     = "toggleStar"
+	SetBookmarkListAction    = "setBookmarkList"
+	DeleteBookmarkListAction = "deleteBookmarkList"
 )
 
 func IsBuiltWithUserPrefsExtension() bool {
@@ -37,31 +44,65 @@ func SetupUserPreferencesRoutes(conf *config.Config, router *mux.Router,
 
 	log.Info().Msg("setting up user preferences routes")
 
+	// the export, import and admin routes are matched before the generic userprefs route
+	exportMethods := zcommon.AllowedMethods(http.MethodGet)
+
+	exportRouter := router.PathPrefix(constants.ExtUserPrefsExport).Subrouter()
+	exportRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+	exportRouter.Use(zcommon.AddExtensionSecurityHeaders())
+	exportRouter.Use(zcommon.ACHeadersMiddleware(conf, exportMethods...))
+	exportRouter.Methods(exportMethods...).Handler(HandleExportUserPrefs(metaDB, log))
+
+	importMethods := zcommon.AllowedMethods(http.MethodPut)
+
+	importRouter := router.PathPrefix(constants.ExtUserPrefsImport).Subrouter()
+	importRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+	importRouter.Use(zcommon.AddExtensionSecurityHeaders())
+	importRouter.Use(zcommon.ACHeadersMiddleware(conf, importMethods...))
+	importRouter.Methods(importMethods...).Handler(HandleImportUserPrefs(metaDB, log))
+
+	// managing the preferences of the other users is only available to the authenticated admins
+	if conf.IsBasicAuthnEnabled() {
+		adminMethods := zcommon.AllowedMethods(http.MethodGet, http.MethodPost, http.MethodDelete)
+
+		adminRouter := router.PathPrefix(constants.ExtUserPrefsAdmin).Subrouter()
+		adminRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		adminRouter.Use(zcommon.AddExtensionSecurityHeaders())
+		adminRouter.Use(zcommon.ACHeadersMiddleware(conf, adminMethods...))
+		adminRouter.Use(zcommon.AuthzOnlyAdminsMiddleware(conf))
+		adminRouter.Handle("/migrate", HandleMigrateUserPrefs(metaDB, log)).
+			Methods(http.MethodPost, http.MethodOptions)
+		adminRouter.Methods(http.MethodGet, http.MethodDelete, http.MethodOptions).
+			Handler(HandleAdminUserPrefs(metaDB, log))
+	}
+
 	allowedMethods := zcommon.AllowedMethods(http.MethodPut)
 
 	userPrefsRouter := router.PathPrefix(constants.ExtUserPrefs).Subrouter()
 	userPrefsRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
 	userPrefsRouter.Use(zcommon.AddExtensionSecurityHeaders())
 	userPrefsRouter.Use(zcommon.ACHeadersMiddleware(conf, allowedMethods...))
-	userPrefsRouter.Methods(allowedMethods...).Handler(HandleUserPrefs(metaDB, log))
+	userPrefsRouter.Methods(allowedMethods...).Handler(HandleUserPrefs(conf, metaDB, log))
 
 	log.Info().Msg("finished setting up user preferences routes")
 }
 
 // Repo preferences godoc
 // @Summary Add bookmarks/stars info
-// @Description Add bookmarks/stars info
+// @Description Add bookmarks/stars info, create/replace or delete a named bookmark list
 // @Router  /v2/_zot/ext/userprefs [put]
 // @Accept  json
 // @Produce json
-// @Param   action    query    string     true  "specify action" Enums(toggleBookmark, toggleStar)
-// @Param   repo      query    string     true  "repository name"
+// @Param   action    query    string     true  "specify action" Enums(toggleBookmark, toggleStar, setBookmarkList, deleteBookmarkList)
+// @Param   repo      query    string     false "repository name, for toggleBookmark and toggleStar"
+// @Param   name      query    string     false "bookmark list name, for setBookmarkList and deleteBookmarkList"
+// @Param   list      body     extensions.BookmarkListRequest false "repos and groups of the list, for setBookmarkList"
 // @Success 200 {string}   string   "ok"
 // @Failure 404 {string}   string   "not found"
 // @Failure 403 {string}   string   "forbidden"
 // @Failure 500 {string}   string   "internal server error"
 // @Failure 400 {string}   string   "bad request".
-func HandleUserPrefs(metaDB mTypes.MetaDB, log log.Logger) http.Handler {
+func HandleUserPrefs(conf *config.Config, metaDB mTypes.MetaDB, log log.Logger) http.Handler {
 	return http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
 		if !zcommon.QueryHasParams(req.URL.Query(), []string{"action"}) {
 			rsp.WriteHeader(http.StatusBadRequest)
@@ -77,7 +118,15 @@ func HandleUserPrefs(metaDB mTypes.MetaDB, log log.Logger) http.Handler {
 
 			return
 		case ToggleRepoStarAction:
-			PutStar(rsp, req, metaDB, log) //nolint:contextcheck
+			PutStar(rsp, req, conf, metaDB, log) //nolint:contextcheck
+
+			return
+		case SetBookmarkListAction:
+			PutBookmarkList(rsp, req, metaDB, log) //nolint:contextcheck
+
+			return
+		case DeleteBookmarkListAction:
+			DeleteBookmarkList(rsp, req, metaDB, log) //nolint:contextcheck
 
 			return
 		default:
@@ -88,7 +137,9 @@ func HandleUserPrefs(metaDB mTypes.MetaDB, log log.Logger) http.Handler {
 	})
 }
 
-func PutStar(rsp http.ResponseWriter, req *http.Request, metaDB mTypes.MetaDB, log log.Logger) {
+func PutStar(rsp http.ResponseWriter, req *http.Request, conf *config.Config, metaDB mTypes.MetaDB,
+	log log.Logger,
+) {
 	if !zcommon.QueryHasParams(req.URL.Query(), []string{"repo"}) {
 		rsp.WriteHeader(http.StatusBadRequest)
 
@@ -103,7 +154,7 @@ func PutStar(rsp http.ResponseWriter, req *http.Request, metaDB mTypes.MetaDB, l
 		return
 	}
 
-	_, err := metaDB.ToggleStarRepo(req.Context(), repo)
+	toggleState, err := metaDB.ToggleStarRepo(req.Context(), repo)
 	if err != nil {
 		if errors.Is(err, zerr.ErrRepoMetaNotFound) {
 			rsp.WriteHeader(http.StatusNotFound)
@@ -120,6 +171,16 @@ func PutStar(rsp http.ResponseWriter, req *http.Request, metaDB mTypes.MetaDB, l
 		return
 	}
 
+	switch toggleState {
+	case mTypes.Added:
+		meta.RecordUserActivity(req.Context(), conf, metaDB,
+			mTypes.UserActivity{Action: mTypes.UserActivityStar, Repo: repo}, log)
+	case mTypes.Removed:
+		meta.RecordUserActivity(req.Context(), conf, metaDB,
+			mTypes.UserActivity{Action: mTypes.UserActivityUnstar, Repo: repo}, log)
+	case mTypes.NotChanged:
+	}
+
 	rsp.WriteHeader(http.StatusOK)
 }
 
@@ -157,3 +218,487 @@ func PutBookmark(rsp http.ResponseWriter, req *http.Request, metaDB mTypes.MetaD
 
 	rsp.WriteHeader(http.StatusOK)
 }
+
+// BookmarkListRequest holds the repos of a named bookmark list, and the groups whose users can read it.
+type BookmarkListRequest struct {
+	Repos      []string `json:"repos"`
+	SharedWith []string `json:"sharedWith"`
+}
+
+func PutBookmarkList(rsp http.ResponseWriter, req *http.Request, metaDB mTypes.MetaDB, log log.Logger) {
+	name := req.URL.Query().Get("name")
+
+	if name == "" {
+		rsp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var listRequest BookmarkListRequest
+
+	if err := json.NewDecoder(req.Body).Decode(&listRequest); err != nil {
+		rsp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	err := metaDB.SetBookmarkList(req.Context(), mTypes.BookmarkList{
+		Name:       name,
+		Repos:      listRequest.Repos,
+		SharedWith: listRequest.SharedWith,
+	})
+	if err != nil {
+		if errors.Is(err, zerr.ErrUserDataNotAllowed) {
+			rsp.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		log.Error().Err(err).Str("name", name).Msg("unable to set bookmark list")
+
+		rsp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rsp.WriteHeader(http.StatusOK)
+}
+
+func DeleteBookmarkList(rsp http.ResponseWriter, req *http.Request, metaDB mTypes.MetaDB, log log.Logger) {
+	name := req.URL.Query().Get("name")
+
+	if name == "" {
+		rsp.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	err := metaDB.DeleteBookmarkList(req.Context(), name)
+	if err != nil {
+		if errors.Is(err, zerr.ErrBookmarkListNotFound) {
+			rsp.WriteHeader(http.StatusNotFound)
+
+			return
+		} else if errors.Is(err, zerr.ErrUserDataNotAllowed) {
+			rsp.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		log.Error().Err(err).Str("name", name).Msg("unable to delete bookmark list")
+
+		rsp.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	rsp.WriteHeader(http.StatusOK)
+}
+
+// UserPreferences are the stars, bookmarks and bookmark lists of a user, as they're exported and imported.
+type UserPreferences struct {
+	StarredRepos    []string           `json:"starredRepos"`
+	BookmarkedRepos []string           `json:"bookmarkedRepos"`
+	BookmarkLists   []UserBookmarkList `json:"bookmarkLists"`
+}
+
+type UserBookmarkList struct {
+	Name string `json:"name"`
+	BookmarkListRequest
+}
+
+// UserPrefsImportResult lists the repos which were not imported, because they don't exist anymore
+// or because the user can't read them.
+type UserPrefsImportResult struct {
+	SkippedRepos []string `json:"skippedRepos"`
+}
+
+const (
+	MergeImportMode   = "merge"
+	ReplaceImportMode = "replace"
+)
+
+// Export user preferences godoc
+// @Summary Export the preferences of the current user
+// @Description Export the starred and bookmarked repos and the bookmark lists of the current user
+// @Router  /v2/_zot/ext/userprefs/export [get]
+// @Produce json
+// @Success 200 {object}   extensions.UserPreferences
+// @Failure 403 {string}   string   "forbidden"
+// @Failure 500 {string}   string   "internal server error".
+func HandleExportUserPrefs(metaDB mTypes.MetaDB, log log.Logger) http.Handler {
+	return http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		userData, err := getUserData(req.Context(), metaDB) //nolint:contextcheck
+		if err != nil {
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		zcommon.WriteJSON(rsp, http.StatusOK, getUserPreferences(userData))
+	})
+}
+
+// Import user preferences godoc
+// @Summary Import the preferences of the current user
+// @Description Import stars, bookmarks and bookmark lists, merged with the current ones or replacing them
+// @Router  /v2/_zot/ext/userprefs/import [put]
+// @Accept  json
+// @Produce json
+// @Param   mode      query    string     false "merge (default) or replace the current preferences" Enums(merge, replace)
+// @Param   prefs     body     extensions.UserPreferences true "exported preferences"
+// @Success 200 {object}   extensions.UserPrefsImportResult
+// @Failure 400 {string}   string   "bad request"
+// @Failure 403 {string}   string   "forbidden"
+// @Failure 500 {string}   string   "internal server error".
+func HandleImportUserPrefs(metaDB mTypes.MetaDB, log log.Logger) http.Handler {
+	return http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		mode := req.URL.Query().Get("mode")
+		if mode != "" && mode != MergeImportMode && mode != ReplaceImportMode {
+			rsp.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		var prefs UserPreferences
+
+		if err := json.NewDecoder(req.Body).Decode(&prefs); err != nil {
+			rsp.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		for _, list := range prefs.BookmarkLists {
+			if list.Name == "" {
+				rsp.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		userData, err := getUserData(req.Context(), metaDB) //nolint:contextcheck
+		if err != nil {
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		skippedRepos, err := importUserPreferences(req.Context(), metaDB, userData, prefs, //nolint:contextcheck
+			mode == ReplaceImportMode)
+		if err != nil {
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		zcommon.WriteJSON(rsp, http.StatusOK, UserPrefsImportResult{SkippedRepos: skippedRepos})
+	})
+}
+
+// Manage user preferences godoc
+// @Summary Export or delete the preferences of a user
+// @Description Export the preferences of a user, or delete them, e.g. after the user is removed, admins only
+// @Router  /v2/_zot/ext/userprefs/admin [get]
+// @Router  /v2/_zot/ext/userprefs/admin [delete]
+// @Produce json
+// @Param   user      query    string     true  "name of the user"
+// @Success 200 {object}   extensions.UserPreferences
+// @Failure 400 {string}   string   "bad request"
+// @Failure 403 {string}   string   "forbidden"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func HandleAdminUserPrefs(metaDB mTypes.MetaDB, log log.Logger) http.Handler {
+	return http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		username := req.URL.Query().Get("user")
+		if username == "" {
+			rsp.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		ctx := getUserContext(req.Context(), username)
+
+		userData, err := metaDB.GetUserData(ctx)
+		if err != nil {
+			if errors.Is(err, zerr.ErrUserDataNotFound) {
+				rsp.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		if req.Method == http.MethodGet {
+			zcommon.WriteJSON(rsp, http.StatusOK, getUserPreferences(userData))
+
+			return
+		}
+
+		// the stars of the user are removed from the star counts of the repos
+		if _, err := importUserPreferences(ctx, metaDB, userData, UserPreferences{}, true); err != nil {
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		log.Info().Str("user", username).Msg("userprefs: deleted user preferences")
+
+		rsp.WriteHeader(http.StatusOK)
+	})
+}
+
+// Migrate user preferences godoc
+// @Summary Migrate the preferences of a user to another one
+// @Description Move the preferences of a user to another one, e.g. after the user is renamed, admins only
+// @Router  /v2/_zot/ext/userprefs/admin/migrate [post]
+// @Produce json
+// @Param   from      query    string     true  "name of the user whose preferences are moved"
+// @Param   to        query    string     true  "name of the user receiving the preferences"
+// @Success 200 {object}   extensions.UserPrefsImportResult
+// @Failure 400 {string}   string   "bad request"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func HandleMigrateUserPrefs(metaDB mTypes.MetaDB, log log.Logger) http.Handler {
+	return http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		from := req.URL.Query().Get("from")
+		to := req.URL.Query().Get("to")
+
+		if from == "" || to == "" || from == to {
+			rsp.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		fromCtx := getUserContext(req.Context(), from)
+		toCtx := getUserContext(req.Context(), to)
+
+		fromData, err := metaDB.GetUserData(fromCtx)
+		if err != nil {
+			if errors.Is(err, zerr.ErrUserDataNotFound) {
+				rsp.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		toData, err := getUserData(toCtx, metaDB)
+		if err != nil {
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		// the stars are added to the new user before they're removed from the old one
+		skippedRepos, err := importUserPreferences(toCtx, metaDB, toData, getUserPreferences(fromData), false)
+		if err != nil {
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		if _, err := importUserPreferences(fromCtx, metaDB, fromData, UserPreferences{}, true); err != nil {
+			writeUserPrefsError(rsp, err, log)
+
+			return
+		}
+
+		log.Info().Str("from", from).Str("to", to).Msg("userprefs: migrated user preferences")
+
+		zcommon.WriteJSON(rsp, http.StatusOK, UserPrefsImportResult{SkippedRepos: skippedRepos})
+	})
+}
+
+// getUserContext returns a context acting as the given user, with the rights of an admin,
+// the admins manage the preferences of the users regardless of the permissions of the users.
+func getUserContext(ctx context.Context, username string) context.Context {
+	userAc := reqCtx.NewUserAccessControl()
+	userAc.SetUsername(username)
+	userAc.SetIsAdmin(true)
+
+	return userAc.DeriveContext(ctx)
+}
+
+// getUserData returns the data of the user in the context, empty if the user has no data yet.
+func getUserData(ctx context.Context, metaDB mTypes.MetaDB) (mTypes.UserData, error) {
+	userData, err := metaDB.GetUserData(ctx)
+	if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+		return mTypes.UserData{}, err
+	}
+
+	return userData, nil
+}
+
+func getUserPreferences(userData mTypes.UserData) UserPreferences {
+	prefs := UserPreferences{
+		StarredRepos:    userData.StarredRepos,
+		BookmarkedRepos: userData.BookmarkedRepos,
+		BookmarkLists:   []UserBookmarkList{},
+	}
+
+	if prefs.StarredRepos == nil {
+		prefs.StarredRepos = []string{}
+	}
+
+	if prefs.BookmarkedRepos == nil {
+		prefs.BookmarkedRepos = []string{}
+	}
+
+	for _, list := range userData.BookmarkLists {
+		prefs.BookmarkLists = append(prefs.BookmarkLists, UserBookmarkList{
+			Name:                list.Name,
+			BookmarkListRequest: BookmarkListRequest{Repos: list.Repos, SharedWith: list.SharedWith},
+		})
+	}
+
+	sort.Slice(prefs.BookmarkLists, func(i, j int) bool {
+		return prefs.BookmarkLists[i].Name < prefs.BookmarkLists[j].Name
+	})
+
+	return prefs
+}
+
+/*
+importUserPreferences adds the preferences to the ones of the user in the context, replacing them if asked,
+the stars are toggled so that the star counts of the repos stay correct. The bookmark lists replace the existing
+lists with the same name. It returns the repos which don't exist or which the user can't read, they're skipped.
+*/
+func importUserPreferences(ctx context.Context, metaDB mTypes.MetaDB, userData mTypes.UserData,
+	prefs UserPreferences, replace bool,
+) ([]string, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	skippedRepos := []string{}
+
+	isSkipped := func(err error) bool {
+		return errors.Is(err, zerr.ErrRepoMetaNotFound) || errors.Is(err, zerr.ErrUserDataNotAllowed)
+	}
+
+	if replace {
+		for _, repo := range userData.StarredRepos {
+			if zcommon.Contains(prefs.StarredRepos, repo) {
+				continue
+			}
+
+			// the stars of the deleted repos are dropped below
+			if _, err := metaDB.ToggleStarRepo(ctx, repo); err != nil && !isSkipped(err) {
+				return nil, err
+			}
+		}
+	}
+
+	for _, repo := range prefs.StarredRepos {
+		if zcommon.Contains(userData.StarredRepos, repo) {
+			continue
+		}
+
+		if _, err := metaDB.ToggleStarRepo(ctx, repo); err != nil {
+			if !isSkipped(err) {
+				return nil, err
+			}
+
+			skippedRepos = append(skippedRepos, repo)
+		}
+	}
+
+	for _, repo := range prefs.BookmarkedRepos {
+		if zcommon.Contains(userData.BookmarkedRepos, repo) {
+			continue
+		}
+
+		if _, err := metaDB.ToggleBookmarkRepo(ctx, repo); err != nil {
+			if !isSkipped(err) {
+				return nil, err
+			}
+
+			skippedRepos = append(skippedRepos, repo)
+		}
+	}
+
+	for _, list := range prefs.BookmarkLists {
+		repos := []string{}
+
+		for _, repo := range list.Repos {
+			if !userAc.Can(constants.ReadPermission, repo) {
+				skippedRepos = append(skippedRepos, repo)
+
+				continue
+			}
+
+			repos = append(repos, repo)
+		}
+
+		if err := metaDB.SetBookmarkList(ctx, mTypes.BookmarkList{
+			Name:       list.Name,
+			Repos:      repos,
+			SharedWith: list.SharedWith,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if !replace {
+		return skippedRepos, nil
+	}
+
+	// the remaining stars and bookmarks, e.g. of deleted repos, and the lists which were not imported are dropped
+	userData, err = getUserData(ctx, metaDB)
+	if err != nil {
+		return nil, err
+	}
+
+	userData.StarredRepos = intersect(userData.StarredRepos, prefs.StarredRepos)
+	userData.BookmarkedRepos = intersect(userData.BookmarkedRepos, prefs.BookmarkedRepos)
+
+	for name := range userData.BookmarkLists {
+		if !containsBookmarkList(prefs.BookmarkLists, name) {
+			delete(userData.BookmarkLists, name)
+		}
+	}
+
+	return skippedRepos, metaDB.SetUserData(ctx, userData)
+}
+
+func intersect(repos, keptRepos []string) []string {
+	result := []string{}
+
+	for _, repo := range repos {
+		if zcommon.Contains(keptRepos, repo) {
+			result = append(result, repo)
+		}
+	}
+
+	return result
+}
+
+func containsBookmarkList(lists []UserBookmarkList, name string) bool {
+	for _, list := range lists {
+		if list.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeUserPrefsError(rsp http.ResponseWriter, err error, log log.Logger) {
+	switch {
+	case errors.Is(err, zerr.ErrUserDataNotAllowed):
+		rsp.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, zerr.ErrInvalidRequestParams):
+		rsp.WriteHeader(http.StatusBadRequest)
+	default:
+		log.Error().Err(err).Msg("userprefs: unable to manage user preferences")
+
+		rsp.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -64,13 +64,14 @@ func TestHandlers(t *testing.T) {
 	const UserprefsBaseURL = "http://127.0.0.1:8080/v2/_zot/ext/userprefs"
 
 	log := log.NewLogger("debug", "")
+	conf := config.New()
 	mockmetaDB := mocks.MetaDBMock{}
 
 	Convey("No repo in request", t, func() {
 		request := httptest.NewRequest(http.MethodGet, UserprefsBaseURL+"", strings.NewReader("My string"))
 		response := httptest.NewRecorder()
 
-		extensions.PutStar(response, request, mockmetaDB, log)
+		extensions.PutStar(response, request, conf, mockmetaDB, log)
 		res := response.Result()
 		So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
 		defer res.Body.Close()
@@ -85,7 +86,7 @@ func TestHandlers(t *testing.T) {
 		request := httptest.NewRequest(http.MethodGet, UserprefsBaseURL+"?repo=", strings.NewReader("My string"))
 		response := httptest.NewRecorder()
 
-		extensions.PutStar(response, request, mockmetaDB, log)
+		extensions.PutStar(response, request, conf, mockmetaDB, log)
 		res := response.Result()
 		So(res.StatusCode, ShouldEqual, http.StatusNotFound)
 		defer res.Body.Close()
@@ -116,7 +117,7 @@ func TestHandlers(t *testing.T) {
 			defer res.Body.Close()
 
 			response = httptest.NewRecorder()
-			extensions.PutStar(response, request, mockmetaDB, log)
+			extensions.PutStar(response, request, conf, mockmetaDB, log)
 			res = response.Result()
 			So(res.StatusCode, ShouldEqual, http.StatusNotFound)
 			defer res.Body.Close()
@@ -142,7 +143,7 @@ func TestHandlers(t *testing.T) {
 			defer res.Body.Close()
 
 			response = httptest.NewRecorder()
-			extensions.PutStar(response, request, mockmetaDB, log)
+			extensions.PutStar(response, request, conf, mockmetaDB, log)
 			res = response.Result()
 			So(res.StatusCode, ShouldEqual, http.StatusForbidden)
 			defer res.Body.Close()
@@ -167,7 +168,57 @@ func TestHandlers(t *testing.T) {
 			defer res.Body.Close()
 
 			response = httptest.NewRecorder()
-			extensions.PutStar(response, request, mockmetaDB, log)
+			extensions.PutStar(response, request, conf, mockmetaDB, log)
+			res = response.Result()
+			So(res.StatusCode, ShouldEqual, http.StatusInternalServerError)
+			defer res.Body.Close()
+		})
+	})
+
+	Convey("Bookmark lists different errors", t, func() {
+		Convey("ErrUserDataNotAllowed", func() {
+			mockmetaDB.SetBookmarkListFn = func(ctx context.Context, list mTypes.BookmarkList) error {
+				return zerr.ErrUserDataNotAllowed
+			}
+
+			mockmetaDB.DeleteBookmarkListFn = func(ctx context.Context, name string) error {
+				return zerr.ErrUserDataNotAllowed
+			}
+
+			request := httptest.NewRequest(http.MethodPut, UserprefsBaseURL+"?name=golden",
+				strings.NewReader(`{"repos": ["test"]}`))
+			response := httptest.NewRecorder()
+			extensions.PutBookmarkList(response, request, mockmetaDB, log)
+			res := response.Result()
+			So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+			defer res.Body.Close()
+
+			response = httptest.NewRecorder()
+			extensions.DeleteBookmarkList(response, request, mockmetaDB, log)
+			res = response.Result()
+			So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+			defer res.Body.Close()
+		})
+
+		Convey("ErrUnexpectedError", func() {
+			mockmetaDB.SetBookmarkListFn = func(ctx context.Context, list mTypes.BookmarkList) error {
+				return ErrTestError
+			}
+
+			mockmetaDB.DeleteBookmarkListFn = func(ctx context.Context, name string) error {
+				return ErrTestError
+			}
+
+			request := httptest.NewRequest(http.MethodPut, UserprefsBaseURL+"?name=golden",
+				strings.NewReader(`{"repos": ["test"]}`))
+			response := httptest.NewRecorder()
+			extensions.PutBookmarkList(response, request, mockmetaDB, log)
+			res := response.Result()
+			So(res.StatusCode, ShouldEqual, http.StatusInternalServerError)
+			defer res.Body.Close()
+
+			response = httptest.NewRecorder()
+			extensions.DeleteBookmarkList(response, request, mockmetaDB, log)
 			res = response.Result()
 			So(res.StatusCode, ShouldEqual, http.StatusInternalServerError)
 			defer res.Body.Close()
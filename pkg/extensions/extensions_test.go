@@ -4,14 +4,17 @@
 package extensions_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"testing"
 	"time"
 
+	godigest "github.com/opencontainers/go-digest"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/resty.v1"
 
@@ -21,7 +24,11 @@ import (
 	"zotregistry.io/zot/pkg/extensions"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
 	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/maintenance"
+	"zotregistry.io/zot/pkg/retention"
 	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
 )
 
 const (
@@ -932,3 +939,921 @@ func TestAllowedMethodsHeaderMgmt(t *testing.T) {
 		So(resp.StatusCode(), ShouldEqual, http.StatusNoContent)
 	})
 }
+
+func TestMgmtRepoDeletion(t *testing.T) {
+	Convey("Delete a repo with the mgmt extension", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+		simpleUser := "bob"
+		simpleUserPassword := "bobPassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n",
+			test.GetCredString(adminUser, adminPassword), test.GetCredString(simpleUser, simpleUserPassword)))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{simpleUser}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+		conf.Extensions.UI = &extconf.UIConfig{}
+		conf.Extensions.UI.Enable = &defaultVal
+
+		ctlr := api.NewController(conf)
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		// both repos have the same blobs, which are deduped
+		image := CreateRandomImage()
+
+		err := UploadImageWithBasicAuth(image, baseURL, "deleted", "1.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		err = UploadImageWithBasicAuth(image, baseURL, "kept", "1.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		// only the blobs of this image aren't shared with the other repo
+		uniqueImage := CreateRandomImage()
+
+		err = UploadImageWithBasicAuth(uniqueImage, baseURL, "deleted", "2.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+
+		resp, err := adminClient.Put(baseURL + constants.FullUserPrefs + "?repo=deleted&action=toggleStar")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		deletionURL := baseURL + constants.FullMgmtRepos + "/deleted"
+
+		// only the admins can delete repos
+		resp, err = resty.R().SetBasicAuth(simpleUser, simpleUserPassword).Post(deletionURL + "/deletion")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Post(deletionURL + "/deletion")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = adminClient.Post(baseURL + constants.FullMgmtRepos + "/inexistent/deletion")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		// a token is required
+		resp, err = adminClient.Delete(deletionURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = adminClient.Post(baseURL + constants.FullMgmtRepos + "/kept/deletion")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		var keptToken extensions.RepoDeletionToken
+		err = json.Unmarshal(resp.Body(), &keptToken)
+		So(err, ShouldBeNil)
+		So(keptToken.Summary.Tags, ShouldResemble, []string{"1.0"})
+		So(keptToken.Summary.ReclaimableBytes, ShouldEqual, 0)
+
+		// the token of another repo doesn't confirm the deletion
+		resp, err = adminClient.Delete(deletionURL + "?token=" + keptToken.Token)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = adminClient.Post(deletionURL + "/deletion")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		var token extensions.RepoDeletionToken
+		err = json.Unmarshal(resp.Body(), &token)
+		So(err, ShouldBeNil)
+		So(token.Repo, ShouldEqual, "deleted")
+		So(token.Token, ShouldNotBeEmpty)
+		So(token.ExpiresAt, ShouldHappenAfter, time.Now())
+		So(token.Summary.Tags, ShouldResemble, []string{"1.0", "2.0"})
+		So(token.Summary.Referrers, ShouldEqual, 0)
+
+		reclaimableBytes := uniqueImage.ManifestDescriptor.Size + uniqueImage.ConfigDescriptor.Size
+		for _, layer := range uniqueImage.Manifest.Layers {
+			reclaimableBytes += layer.Size
+		}
+
+		So(token.Summary.ReclaimableBytes, ShouldEqual, reclaimableBytes)
+
+		resp, err = adminClient.Delete(deletionURL + "?token=" + token.Token)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		// the token is used only once
+		resp, err = adminClient.Delete(deletionURL + "?token=" + token.Token)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		_, err = os.Stat(path.Join(conf.Storage.RootDirectory, "deleted"))
+		So(os.IsNotExist(err), ShouldBeTrue)
+
+		resp, err = adminClient.Get(baseURL + "/v2/deleted/tags/list")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		_, err = ctlr.MetaDB.GetRepoMeta("deleted")
+		So(err, ShouldNotBeNil)
+
+		// the blobs of the other repo are intact
+		for _, layer := range image.Manifest.Layers {
+			resp, err = adminClient.Get(baseURL + "/v2/kept/blobs/" + layer.Digest.String())
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(godigest.FromBytes(resp.Body()), ShouldEqual, layer.Digest)
+		}
+
+		resp, err = adminClient.Get(baseURL + "/v2/kept/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+
+	Convey("Don't delete the repos of a read-only store", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString(adminUser, adminPassword) + "\n")
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.ReadOnly = true
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+
+		ctlr := api.NewController(conf)
+
+		image := CreateRandomImage()
+
+		err := test.WriteImageToFileSystem(image, "repo", "1.0",
+			test.GetDefaultStoreController(conf.Storage.RootDirectory, ctlr.Log))
+		So(err, ShouldBeNil)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+		deletionURL := baseURL + constants.FullMgmtRepos + "/repo"
+
+		resp, err := adminClient.Post(deletionURL + "/deletion")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusCreated)
+
+		var token extensions.RepoDeletionToken
+		err = json.Unmarshal(resp.Body(), &token)
+		So(err, ShouldBeNil)
+
+		resp, err = adminClient.Delete(deletionURL + "?token=" + token.Token)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
+		So(string(resp.Body()), ShouldContainSubstring, "UNSUPPORTED")
+
+		_, err = os.Stat(path.Join(conf.Storage.RootDirectory, "repo"))
+		So(err, ShouldBeNil)
+
+		resp, err = adminClient.Get(baseURL + "/v2/repo/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+}
+
+func TestMgmtRetentionPolicies(t *testing.T) {
+	Convey("Manage the retention policies with the mgmt extension", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+		simpleUser := "bob"
+		simpleUserPassword := "bobPassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n",
+			test.GetCredString(adminUser, adminPassword), test.GetCredString(simpleUser, simpleUserPassword)))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		retentionURL := baseURL + constants.FullMgmtRetention
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.Retention = &config.RetentionConfig{
+			Policies: []config.RetentionPolicy{{Repos: []string{"**"}, KeepLastN: 10}},
+		}
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{simpleUser}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+
+		ctlr := api.NewController(conf)
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		for _, tag := range []string{"1.0", "latest", "2.0", "3.0"} {
+			err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "app", tag, adminUser, adminPassword)
+			So(err, ShouldBeNil)
+		}
+
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+
+		// only the admins can manage the policies
+		resp, err := resty.R().SetBasicAuth(simpleUser, simpleUserPassword).Get(retentionURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Get(retentionURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = adminClient.Get(retentionURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		var policies extensions.RetentionPolicies
+		err = json.Unmarshal(resp.Body(), &policies)
+		So(err, ShouldBeNil)
+		So(policies.Policies, ShouldResemble, conf.Storage.Retention.Policies)
+
+		for _, body := range []string{
+			`{`,
+			`{"policies": [{"repos": ["**"], "keepLast": 1}]}`,
+			`{"policies": [{"repos": ["["], "keepLastN": 1}]}`,
+			`{"policies": [{"repos": ["**"], "keepLastN": 1, "keepTags": ["("]}]}`,
+		} {
+			resp, err = adminClient.SetBody(body).Put(retentionURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+			So(string(resp.Body()), ShouldContainSubstring, "invalid retention policy")
+		}
+
+		body := `{"policies": [{"repos": ["app"], "keepLastN": 2, "keepTags": ["^latest$"]}]}`
+
+		// the preview doesn't change the policies
+		resp, err = adminClient.SetBody(body).Post(retentionURL + "/preview")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		var preview extensions.RetentionPreview
+		err = json.Unmarshal(resp.Body(), &preview)
+		So(err, ShouldBeNil)
+		So(len(preview.Deletions), ShouldEqual, 1)
+		So(preview.Deletions[0].Repo, ShouldEqual, "app")
+		So(preview.Deletions[0].Tag, ShouldEqual, "1.0")
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Post(retentionURL + "/preview")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		preview = extensions.RetentionPreview{}
+		err = json.Unmarshal(resp.Body(), &preview)
+		So(err, ShouldBeNil)
+		So(preview.Policies, ShouldResemble, conf.Storage.Retention.Policies)
+		So(preview.Deletions, ShouldBeEmpty)
+
+		resp, err = adminClient.SetBody(body).Put(retentionURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		preview = extensions.RetentionPreview{}
+		err = json.Unmarshal(resp.Body(), &preview)
+		So(err, ShouldBeNil)
+		So(len(preview.Deletions), ShouldEqual, 1)
+
+		resp, err = adminClient.Get(retentionURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &policies)
+		So(err, ShouldBeNil)
+		So(policies.Policies, ShouldResemble, []config.RetentionPolicy{
+			{Repos: []string{"app"}, KeepLastN: 2, KeepTags: []string{"^latest$"}},
+		})
+
+		_, err = os.Stat(path.Join(conf.Storage.RootDirectory, retention.PoliciesFile))
+		So(err, ShouldBeNil)
+
+		deleted, err := ctlr.Retention.Apply(context.Background())
+		So(err, ShouldBeNil)
+		So(len(deleted), ShouldEqual, 1)
+
+		resp, err = adminClient.Get(baseURL + "/v2/app/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		repoMeta, err := ctlr.MetaDB.GetRepoMeta("app")
+		So(err, ShouldBeNil)
+		So(repoMeta.Tags, ShouldNotContainKey, "1.0")
+		So(repoMeta.Tags, ShouldContainKey, "2.0")
+	})
+}
+
+func TestMgmtMaintenanceJobs(t *testing.T) {
+	Convey("Run and pause the maintenance jobs with the mgmt extension", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+		simpleUser := "bob"
+		simpleUserPassword := "bobPassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n",
+			test.GetCredString(adminUser, adminPassword), test.GetCredString(simpleUser, simpleUserPassword)))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		maintenanceURL := baseURL + constants.FullMgmtMaintenance
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.GC = true
+		conf.Storage.GCInterval = time.Hour
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{simpleUser}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+
+		ctlr := api.NewController(conf)
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		for _, repo := range []string{"app", "other"} {
+			err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, repo, "1.0", adminUser, adminPassword)
+			So(err, ShouldBeNil)
+		}
+
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+
+		getStatus := func(job string) maintenance.Status {
+			resp, err := adminClient.Get(maintenanceURL + "/" + job)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var status maintenance.Status
+			err = json.Unmarshal(resp.Body(), &status)
+			So(err, ShouldBeNil)
+
+			return status
+		}
+
+		waitForJob := func(job string) maintenance.Status {
+			for i := 0; i < 60; i++ {
+				if status := getStatus(job); !status.Running {
+					return status
+				}
+
+				time.Sleep(time.Second)
+			}
+
+			return getStatus(job)
+		}
+
+		// only the admins manage the jobs
+		resp, err := resty.R().SetBasicAuth(simpleUser, simpleUserPassword).Get(maintenanceURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Post(maintenanceURL + "/gc/run")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = adminClient.Get(maintenanceURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		var jobs extensions.MaintenanceJobs
+		err = json.Unmarshal(resp.Body(), &jobs)
+		So(err, ShouldBeNil)
+		So(len(jobs.Jobs), ShouldEqual, 3)
+		So(jobs.Jobs[0].Job, ShouldEqual, maintenance.Dedupe)
+		So(jobs.Jobs[1].Job, ShouldEqual, maintenance.GC)
+		So(jobs.Jobs[2].Job, ShouldEqual, maintenance.Reconcile)
+
+		// scrub isn't enabled
+		resp, err = adminClient.Get(maintenanceURL + "/scrub")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = adminClient.Post(maintenanceURL + "/scrub/run")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = adminClient.Post(maintenanceURL + "/gc/stop")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
+
+		So(waitForJob(maintenance.GC).Running, ShouldBeFalse)
+
+		resp, err = adminClient.Post(maintenanceURL + "/gc/pause")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(getStatus(maintenance.GC).Paused, ShouldBeTrue)
+
+		// a paused job isn't run
+		resp, err = adminClient.Post(maintenanceURL + "/gc/run")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusConflict)
+
+		resp, err = adminClient.Post(maintenanceURL + "/gc/resume")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = adminClient.Post(maintenanceURL + "/gc/run")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		var status maintenance.Status
+		err = json.Unmarshal(resp.Body(), &status)
+		So(err, ShouldBeNil)
+		So(status.Running, ShouldBeTrue)
+		So(status.Trigger, ShouldEqual, maintenance.TriggerManual)
+		So(status.StartedAt, ShouldNotBeNil)
+
+		status = waitForJob(maintenance.GC)
+		So(status.Running, ShouldBeFalse)
+		So(status.Trigger, ShouldEqual, maintenance.TriggerManual)
+		// a task for each repo, and one repairing the deduped blobs
+		So(status.TasksDone, ShouldEqual, 3)
+		So(status.TasksFailed, ShouldEqual, 0)
+		So(status.TasksQueued, ShouldEqual, 0)
+		So(status.FinishedAt, ShouldNotBeNil)
+	})
+}
+
+func TestMgmtGCPreview(t *testing.T) {
+	Convey("Preview the garbage collection with the mgmt extension", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+		simpleUser := "bob"
+		simpleUserPassword := "bobPassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n",
+			test.GetCredString(adminUser, adminPassword), test.GetCredString(simpleUser, simpleUserPassword)))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		previewURL := baseURL + constants.FullMgmtMaintenance + "/gc/preview"
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		// the gc isn't run by zot, only previewed
+		conf.Storage.GC = false
+		conf.Storage.GCDelay = time.Second
+		conf.Storage.UntaggedImageRetentionDelay = time.Second
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{simpleUser}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+
+		ctlr := api.NewController(conf)
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "app", "1.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		untagged := CreateRandomImage()
+		err = UploadImageWithBasicAuth(untagged, baseURL, "other", untagged.DigestStr(), adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		// sleep so the untagged image can be GC'ed
+		time.Sleep(time.Second)
+
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+
+		// only the admins preview the gc
+		resp, err := resty.R().SetBasicAuth(simpleUser, simpleUserPassword).Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = adminClient.Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		var preview extensions.GCPreview
+		err = json.Unmarshal(resp.Body(), &preview)
+		So(err, ShouldBeNil)
+		So(len(preview.Repos), ShouldEqual, 1)
+		So(preview.Repos[0].Repo, ShouldEqual, "other")
+		So(preview.Repos[0].RepoRemoved, ShouldBeTrue)
+		So(preview.Repos[0].Manifests[0].Digest, ShouldEqual, untagged.Digest())
+		So(preview.BytesFreed, ShouldEqual, preview.Repos[0].BytesFreed)
+		So(preview.BytesFreed, ShouldBeGreaterThan, 0)
+
+		// nothing was removed
+		resp, err = adminClient.Get(baseURL + "/v2/other/manifests/" + untagged.DigestStr())
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = adminClient.SetQueryParam("repo", "app").Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		preview = extensions.GCPreview{}
+		err = json.Unmarshal(resp.Body(), &preview)
+		So(err, ShouldBeNil)
+		So(preview.Repos, ShouldBeEmpty)
+		So(preview.BytesFreed, ShouldEqual, 0)
+
+		resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).SetQueryParam("repo", "missing").
+			Get(previewURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+	})
+}
+
+func TestMgmtMetaDBBackup(t *testing.T) {
+	Convey("Back up and restore the metaDB with the mgmt extension", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+		simpleUser := "bob"
+		simpleUserPassword := "bobPassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n",
+			test.GetCredString(adminUser, adminPassword), test.GetCredString(simpleUser, simpleUserPassword)))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		metaDBURL := baseURL + constants.FullMgmtMetaDB
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{simpleUser}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+
+		ctlr := api.NewController(conf)
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "app", "1.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		err = ctlr.MetaDB.IncrementRepoStars("app")
+		So(err, ShouldBeNil)
+
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+
+		// only the admins back up and restore the metaDB
+		resp, err := resty.R().SetBasicAuth(simpleUser, simpleUserPassword).Get(metaDBURL + "/backup")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Post(metaDBURL + "/restore")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = adminClient.Get(metaDBURL + "/backup")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(resp.Header().Get("Content-Type"), ShouldEqual, "application/gzip")
+		So(resp.Header().Get("Content-Disposition"), ShouldContainSubstring, "metadb-")
+
+		backup := resp.Body()
+
+		err = ctlr.MetaDB.IncrementRepoStars("app")
+		So(err, ShouldBeNil)
+
+		resp, err = adminClient.SetBody([]byte("not a backup")).Post(metaDBURL + "/restore")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		stars, err := ctlr.MetaDB.GetRepoStars("app")
+		So(err, ShouldBeNil)
+		So(stars, ShouldEqual, 2)
+
+		resp, err = adminClient.SetBody(backup).Post(metaDBURL + "/restore")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		stars, err = ctlr.MetaDB.GetRepoStars("app")
+		So(err, ShouldBeNil)
+		So(stars, ShouldEqual, 1)
+	})
+}
+
+func TestMgmtLogLevels(t *testing.T) {
+	Convey("Change the log levels with the mgmt extension", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+		simpleUser := "bob"
+		simpleUserPassword := "bobPassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n",
+			test.GetCredString(adminUser, adminPassword), test.GetCredString(simpleUser, simpleUserPassword)))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		logURL := baseURL + constants.FullMgmtLog
+
+		logFile := path.Join(t.TempDir(), "zot.log")
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{simpleUser}, Actions: []string{"read"}}},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+		conf.Log.Level = "info"
+		conf.Log.Output = logFile
+		conf.Log.Components = map[string]string{"sync": "warn"}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+
+		ctlr := api.NewController(conf)
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+
+		// only the admins change the log levels
+		resp, err := resty.R().SetBasicAuth(simpleUser, simpleUserPassword).Get(logURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().SetBody(`{"level":"debug"}`).Put(logURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		var levels extensions.LogLevels
+
+		resp, err = adminClient.Get(logURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &levels)
+		So(err, ShouldBeNil)
+		So(levels.Level, ShouldEqual, "info")
+		So(levels.Components, ShouldResemble, map[string]string{"sync": "warn"})
+		So(levels.RestoreAt, ShouldBeNil)
+
+		for _, body := range []string{
+			`{"level":"verbose"}`,
+			`{"components":{"metadb":"debug"}}`,
+			`{"level":"debug","duration":"-1m"}`,
+			`{"lvl":"debug"}`,
+		} {
+			resp, err = adminClient.SetBody(body).Put(logURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		}
+
+		// the debug logging of the storage is enabled for a while
+		resp, err = adminClient.SetBody(`{"components":{"storage":"debug","sync":""},"duration":"1s"}`).Put(logURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		levels = extensions.LogLevels{}
+
+		err = json.Unmarshal(resp.Body(), &levels)
+		So(err, ShouldBeNil)
+		So(levels.Level, ShouldEqual, "info")
+		So(levels.Components, ShouldResemble, map[string]string{"storage": "debug"})
+		So(levels.RestoreAt, ShouldNotBeNil)
+
+		storageLog := ctlr.Log.Component(log.ComponentStorage)
+		storageLog.Debug().Msg("storage debug line")
+		ctlr.Log.Debug().Msg("global debug line")
+
+		content, err := os.ReadFile(logFile)
+		So(err, ShouldBeNil)
+		So(string(content), ShouldContainSubstring, "storage debug line")
+		So(string(content), ShouldNotContainSubstring, "global debug line")
+
+		// the levels from before the change are restored
+		time.Sleep(2 * time.Second)
+
+		resp, err = adminClient.Get(logURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		levels = extensions.LogLevels{}
+
+		err = json.Unmarshal(resp.Body(), &levels)
+		So(err, ShouldBeNil)
+		So(levels.Level, ShouldEqual, "info")
+		So(levels.Components, ShouldResemble, map[string]string{"sync": "warn"})
+		So(levels.RestoreAt, ShouldBeNil)
+
+		resp, err = adminClient.SetBody(`{"level":"debug"}`).Put(logURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		ctlr.Log.Debug().Msg("another global debug line")
+
+		content, err = os.ReadFile(logFile)
+		So(err, ShouldBeNil)
+		So(string(content), ShouldContainSubstring, "another global debug line")
+
+		// the levels of the config are applied again, e.g. on SIGHUP
+		err = ctlr.SetLogLevels(conf.Log)
+		So(err, ShouldBeNil)
+		So(log.GetLevels(), ShouldResemble, log.Levels{Level: "info", Components: map[string]string{"sync": "warn"}})
+	})
+}
+
+func TestMgmtCluster(t *testing.T) {
+	Convey("List the members of the cluster with the mgmt extension", t, func() {
+		adminUser := "alice"
+		adminPassword := "alicePassword"
+		simpleUser := "bob"
+		simpleUserPassword := "bobPassword"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n",
+			test.GetCredString(adminUser, adminPassword), test.GetCredString(simpleUser, simpleUserPassword)))
+		defer os.Remove(htpasswdPath)
+
+		newConfig := func(port string) *config.Config {
+			defaultVal := true
+
+			conf := config.New()
+			conf.HTTP.Port = port
+			conf.Storage.RootDirectory = t.TempDir()
+			conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+			conf.HTTP.AccessControl = &config.AccessControlConfig{
+				Repositories: config.Repositories{
+					"**": config.PolicyGroup{
+						Policies: []config.Policy{{Users: []string{simpleUser}, Actions: []string{"read"}}},
+					},
+				},
+				AdminPolicy: config.Policy{
+					Users:   []string{adminUser},
+					Actions: []string{"read", "create", "update", "delete"},
+				},
+			}
+			conf.Extensions = &extconf.ExtensionConfig{}
+			conf.Extensions.Search = &extconf.SearchConfig{}
+			conf.Extensions.Search.Enable = &defaultVal
+			conf.Extensions.Search.CVE = nil
+
+			return conf
+		}
+
+		Convey("Without the cluster mode", func() {
+			port := test.GetFreePort()
+			baseURL := test.GetBaseURL(port)
+
+			ctlr := api.NewController(newConfig(port))
+			ctlrManager := test.NewControllerManager(ctlr)
+			ctlrManager.StartAndWait(port)
+			defer ctlrManager.StopServer()
+
+			resp, err := resty.R().SetBasicAuth(adminUser, adminPassword).Get(baseURL + constants.FullMgmtCluster)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("With the cluster mode", func() {
+			port := test.GetFreePort()
+			baseURL := test.GetBaseURL(port)
+
+			conf := newConfig(port)
+			conf.Cluster = &config.ClusterConfig{
+				Name:    "zot-0",
+				Address: baseURL,
+				Leases:  config.LeasesConfig{Driver: "memory", KeyPrefix: t.Name()},
+			}
+
+			ctlr := api.NewController(conf)
+			ctlrManager := test.NewControllerManager(ctlr)
+			ctlrManager.StartAndWait(port)
+			defer ctlrManager.StopServer()
+
+			// only the admins list the members
+			resp, err := resty.R().SetBasicAuth(simpleUser, simpleUserPassword).Get(baseURL + constants.FullMgmtCluster)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().Get(baseURL + constants.FullMgmtCluster)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth(adminUser, adminPassword).Get(baseURL + constants.FullMgmtCluster)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var status extensions.ClusterStatus
+
+			err = json.Unmarshal(resp.Body(), &status)
+			So(err, ShouldBeNil)
+			So(status.Self.Name, ShouldEqual, "zot-0")
+			So(status.Self.Address, ShouldEqual, baseURL)
+			So(status.Members, ShouldHaveLength, 1)
+			So(status.Members[0].Name, ShouldEqual, "zot-0")
+			So(status.Members[0].Self, ShouldBeTrue)
+		})
+	})
+}
@@ -51,9 +51,9 @@ func GetExtensions(config *config.Config) distext.ExtensionList {
 }
 
 func EnableScheduledTasks(conf *config.Config, taskScheduler *scheduler.Scheduler,
-	metaDB mTypes.MetaDB, log log.Logger,
+	metaDB mTypes.MetaDB, eventRecorder EventRecorder, log log.Logger,
 ) {
-	EnableImageTrustVerification(conf, taskScheduler, metaDB, log)
+	EnableImageTrustVerification(conf, taskScheduler, metaDB, eventRecorder, log)
 }
 
 func SetupExtensions(conf *config.Config, metaDB mTypes.MetaDB, log log.Logger) error {
@@ -0,0 +1,203 @@
+//go:build imagetrust
+// +build imagetrust
+
+package imagetrust
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	rekor "github.com/sigstore/rekor/pkg/client"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/tuf"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+)
+
+/*
+CosignKeylessVerifier verifies the cosign signatures made with short-lived certificates issued by Fulcio,
+the certificates must be issued by the configured Fulcio roots to one of the trusted identities
+and the signatures must be in the Rekor transparency log, which is checked offline with the bundles
+of the signatures or online by looking them up in Rekor. The verification material can be reloaded
+from the configured files, e.g. after the Fulcio roots or the Rekor key were rotated.
+*/
+type CosignKeylessVerifier struct {
+	config    *extconf.CosignKeylessConfig
+	checkOpts cosign.CheckOpts
+	lock      *sync.RWMutex
+}
+
+func NewCosignKeylessVerifier(config *extconf.CosignKeylessConfig) (*CosignKeylessVerifier, error) {
+	checkOpts, err := getCosignKeylessCheckOpts(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CosignKeylessVerifier{config: config, checkOpts: checkOpts, lock: &sync.RWMutex{}}, nil
+}
+
+// Reload reads the verification material of the config again, the previous one is kept if it can't be read.
+func (verifier *CosignKeylessVerifier) Reload() error {
+	checkOpts, err := getCosignKeylessCheckOpts(verifier.config)
+	if err != nil {
+		return err
+	}
+
+	verifier.lock.Lock()
+	verifier.checkOpts = checkOpts
+	verifier.lock.Unlock()
+
+	return nil
+}
+
+func getCosignKeylessCheckOpts(config *extconf.CosignKeylessConfig) (cosign.CheckOpts, error) {
+	checkOpts := cosign.CheckOpts{
+		ClaimVerifier: cosign.SimpleClaimVerifier,
+		RootCerts:     x509.NewCertPool(),
+		IgnoreSCT:     config.CTLogPublicKey == "",
+	}
+
+	content, err := os.ReadFile(config.FulcioRoots)
+	if err != nil {
+		return cosign.CheckOpts{}, err
+	}
+
+	certificates, err := cryptoutils.UnmarshalCertificatesFromPEM(content)
+	if err != nil {
+		return cosign.CheckOpts{}, err
+	}
+
+	for _, certificate := range certificates {
+		// the self-signed certificates are the roots, the others are intermediates
+		if certificate.CheckSignatureFrom(certificate) == nil {
+			checkOpts.RootCerts.AddCert(certificate)
+
+			continue
+		}
+
+		if checkOpts.IntermediateCerts == nil {
+			checkOpts.IntermediateCerts = x509.NewCertPool()
+		}
+
+		checkOpts.IntermediateCerts.AddCert(certificate)
+	}
+
+	if config.RekorPublicKey != "" {
+		rekorPublicKeys := cosign.NewTrustedTransparencyLogPubKeys()
+
+		if err := addTransparencyLogPublicKey(&rekorPublicKeys, config.RekorPublicKey); err != nil {
+			return cosign.CheckOpts{}, err
+		}
+
+		checkOpts.RekorPubKeys = &rekorPublicKeys
+	}
+
+	if config.RekorURL != "" {
+		checkOpts.RekorClient, err = rekor.GetRekorClient(config.RekorURL)
+		if err != nil {
+			return cosign.CheckOpts{}, err
+		}
+	} else {
+		checkOpts.Offline = true
+	}
+
+	if config.CTLogPublicKey != "" {
+		ctLogPublicKeys := cosign.NewTrustedTransparencyLogPubKeys()
+
+		if err := addTransparencyLogPublicKey(&ctLogPublicKeys, config.CTLogPublicKey); err != nil {
+			return cosign.CheckOpts{}, err
+		}
+
+		checkOpts.CTLogPubKeys = &ctLogPublicKeys
+	}
+
+	for _, identity := range config.Identities {
+		checkOpts.Identities = append(checkOpts.Identities, cosign.Identity{
+			Issuer:        identity.Issuer,
+			IssuerRegExp:  identity.IssuerRegExp,
+			Subject:       identity.Subject,
+			SubjectRegExp: identity.SubjectRegExp,
+		})
+	}
+
+	return checkOpts, nil
+}
+
+func addTransparencyLogPublicKey(publicKeys *cosign.TrustedTransparencyLogPubKeys, keyPath string) error {
+	content, err := os.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+
+	return publicKeys.AddTransparencyLogPubKey(content, tuf.Active)
+}
+
+// Verify verifies the signature of a manifest, it returns the identity of the certificate, e.g.
+// "user@example.com (https://accounts.google.com)".
+func (verifier *CosignKeylessVerifier) Verify(layerInfo mTypes.LayerInfo, manifestDigest godigest.Digest,
+) (string, bool, error) {
+	options := []static.Option{
+		static.WithCertChain([]byte(layerInfo.Certificate), []byte(layerInfo.CertificateChain)),
+	}
+
+	if layerInfo.Bundle != "" {
+		rekorBundle := &bundle.RekorBundle{}
+
+		if err := json.Unmarshal([]byte(layerInfo.Bundle), rekorBundle); err != nil {
+			return "", false, err
+		}
+
+		options = append(options, static.WithBundle(rekorBundle))
+	}
+
+	signature, err := static.NewSignature(layerInfo.LayerContent, layerInfo.SignatureKey, options...)
+	if err != nil {
+		return "", false, err
+	}
+
+	hash, err := v1.NewHash(manifestDigest.String())
+	if err != nil {
+		return "", false, err
+	}
+
+	// the options are copied, the verification sets the intermediate certificates of the signatures
+	verifier.lock.RLock()
+	checkOpts := verifier.checkOpts
+	verifier.lock.RUnlock()
+
+	if _, err := cosign.VerifyImageSignature(context.Background(), signature, hash, &checkOpts); err != nil {
+		return "", false, err
+	}
+
+	certificate, err := signature.Cert()
+	if err != nil {
+		return "", false, err
+	}
+
+	certExtensions := cosign.CertExtensions{Cert: certificate}
+
+	return fmt.Sprintf("%s (%s)", strings.Join(cryptoutils.GetSubjectAlternateNames(certificate), ", "),
+		certExtensions.GetIssuer()), true, nil
+}
+
+func (imgTrustStore *ImageTrustStore) VerifyCosignKeylessSignature(layerInfo mTypes.LayerInfo,
+	manifestDigest godigest.Digest,
+) (string, bool, error) {
+	if imgTrustStore.CosignKeyless == nil {
+		return "", false, zerr.ErrCosignKeylessNotConfigured
+	}
+
+	return imgTrustStore.CosignKeyless.Verify(layerInfo, manifestDigest)
+}
@@ -6,6 +6,7 @@ package imagetrust
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -34,6 +35,7 @@ const (
 type ImageTrustStore struct {
 	CosignStorage   publicKeyStorage
 	NotationStorage certificateStorage
+	CosignKeyless   *CosignKeylessVerifier
 }
 
 type SecretsManagerClient interface {
@@ -165,16 +167,54 @@ func (imgTrustStore *ImageTrustStore) VerifySignature(
 
 		return author, time.Time{}, isValid, err
 	case zcommon.NotationSignature:
-		return VerifyNotationSignature(imgTrustStore.NotationStorage, desc, manifestDigest.String(), rawSignature, sigKey)
+		return VerifyNotationSignature(imgTrustStore.NotationStorage, desc, repo+"@"+manifestDigest.String(),
+			rawSignature, sigKey)
 	default:
 		return "", time.Time{}, false, zerr.ErrInvalidSignatureType
 	}
 }
 
-func NewTaskGenerator(metaDB mTypes.MetaDB, log log.Logger) scheduler.TaskGenerator {
+// HasTrustedSignature checks if one of the signatures of a manifest was verified by the uploaded
+// public keys or certificates and its certificate didn't expire since, only the signatures of the given types
+// are checked, all of them if none is given.
+func HasTrustedSignature(repoMeta mTypes.RepoMetadata, manifestDigest string, signatureTypes []string) bool {
+	for signatureType, signatures := range repoMeta.Signatures[manifestDigest] {
+		if len(signatureTypes) > 0 && !zcommon.Contains(signatureTypes, signatureType) {
+			continue
+		}
+
+		for _, signature := range signatures {
+			for _, layer := range signature.LayersInfo {
+				if layer.Signer != "" && (layer.Date.IsZero() || time.Now().Before(layer.Date)) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// the statuses of the signatures, reported when they change after the signatures are verified again.
+const (
+	SignatureTrusted   = "trusted"
+	SignatureExpired   = "expired"
+	SignatureUntrusted = "untrusted"
+)
+
+// StatusNotifier is told about the signatures whose status changed when they were verified again, e.g. because
+// a public key or a certificate was uploaded, or a certificate expired, e.g. the events extension.
+type StatusNotifier interface {
+	SignatureStatusChanged(repo, manifestDigest, signatureType, signatureDigest, signer, status string)
+}
+
+// NewTaskGenerator returns a generator of the tasks verifying again the signatures of each repo with the current
+// public keys, certificates and keyless verification material, the notifier can be nil.
+func NewTaskGenerator(metaDB mTypes.MetaDB, notifier StatusNotifier, log log.Logger) scheduler.TaskGenerator {
 	return &sigValidityTaskGenerator{
 		repos:     []mTypes.RepoMetadata{},
 		metaDB:    metaDB,
+		notifier:  notifier,
 		repoIndex: -1,
 		log:       log,
 	}
@@ -183,6 +223,7 @@ func NewTaskGenerator(metaDB mTypes.MetaDB, log log.Logger) scheduler.TaskGenera
 type sigValidityTaskGenerator struct {
 	repos     []mTypes.RepoMetadata
 	metaDB    mTypes.MetaDB
+	notifier  StatusNotifier
 	repoIndex int
 	done      bool
 	log       log.Logger
@@ -190,6 +231,8 @@ type sigValidityTaskGenerator struct {
 
 func (gen *sigValidityTaskGenerator) Next() (scheduler.Task, error) {
 	if len(gen.repos) == 0 {
+		gen.reloadVerificationMaterial()
+
 		ctx := context.Background()
 
 		repos, err := gen.metaDB.GetMultipleRepoMeta(ctx, func(repoMeta mTypes.RepoMetadata) bool {
@@ -212,7 +255,20 @@ func (gen *sigValidityTaskGenerator) Next() (scheduler.Task, error) {
 		return nil, nil
 	}
 
-	return NewValidityTask(gen.metaDB, gen.repos[gen.repoIndex], gen.log), nil
+	return NewValidityTask(gen.metaDB, gen.repos[gen.repoIndex], gen.notifier, gen.log), nil
+}
+
+// reloadVerificationMaterial reads the files of the keyless verification again, the uploaded public keys
+// and certificates are always read when the signatures are verified.
+func (gen *sigValidityTaskGenerator) reloadVerificationMaterial() {
+	imgTrustStore, ok := gen.metaDB.ImageTrustStore().(*ImageTrustStore)
+	if !ok || imgTrustStore == nil || imgTrustStore.CosignKeyless == nil {
+		return
+	}
+
+	if err := imgTrustStore.CosignKeyless.Reload(); err != nil {
+		gen.log.Error().Err(err).Msg("couldn't reload the cosign keyless verification material, keeping the previous one")
+	}
 }
 
 func (gen *sigValidityTaskGenerator) IsDone() bool {
@@ -232,21 +288,46 @@ func (gen *sigValidityTaskGenerator) Reset() {
 }
 
 type validityTask struct {
-	metaDB mTypes.MetaDB
-	repo   mTypes.RepoMetadata
-	log    log.Logger
+	metaDB   mTypes.MetaDB
+	repo     mTypes.RepoMetadata
+	notifier StatusNotifier
+	log      log.Logger
 }
 
-func NewValidityTask(metaDB mTypes.MetaDB, repo mTypes.RepoMetadata, log log.Logger) *validityTask {
-	return &validityTask{metaDB, repo, log}
+func NewValidityTask(metaDB mTypes.MetaDB, repo mTypes.RepoMetadata, notifier StatusNotifier, log log.Logger,
+) *validityTask {
+	return &validityTask{metaDB, repo, notifier, log}
+}
+
+type signatureKey struct {
+	manifestDigest  string
+	signatureType   string
+	signatureDigest string
+}
+
+type signatureStatus struct {
+	signer string
+	status string
 }
 
 func (validityT *validityTask) DoWork(ctx context.Context) error {
 	validityT.log.Info().Msg("update signatures validity")
 
-	for signedManifest, sigs := range validityT.repo.Signatures {
+	// the statuses are compared to the ones of the latest metadata, the repo may have changed since it was listed
+	repoMeta, err := validityT.metaDB.GetRepoMeta(validityT.repo.Name)
+	if err != nil {
+		if errors.Is(err, zerr.ErrRepoMetaNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	statuses := getSignatureStatuses(repoMeta)
+
+	for signedManifest, sigs := range repoMeta.Signatures {
 		if len(sigs[zcommon.CosignSignature]) != 0 || len(sigs[zcommon.NotationSignature]) != 0 {
-			err := validityT.metaDB.UpdateSignaturesValidity(validityT.repo.Name, godigest.Digest(signedManifest))
+			err := validityT.metaDB.UpdateSignaturesValidity(repoMeta.Name, godigest.Digest(signedManifest))
 			if err != nil {
 				validityT.log.Info().Msg("error while verifying signatures")
 
@@ -255,7 +336,61 @@ func (validityT *validityTask) DoWork(ctx context.Context) error {
 		}
 	}
 
+	repoMeta, err = validityT.metaDB.GetRepoMeta(repoMeta.Name)
+	if err != nil {
+		return err
+	}
+
+	for key, status := range getSignatureStatuses(repoMeta) {
+		previousStatus, found := statuses[key]
+		if !found || previousStatus.status == status.status {
+			continue
+		}
+
+		validityT.log.Info().Str("repository", repoMeta.Name).Str("digest", key.manifestDigest).
+			Str("signatureType", key.signatureType).Str("signature", key.signatureDigest).
+			Str("previousStatus", previousStatus.status).Str("status", status.status).
+			Msg("signature status changed")
+
+		if validityT.notifier != nil {
+			validityT.notifier.SignatureStatusChanged(repoMeta.Name, key.manifestDigest, key.signatureType,
+				key.signatureDigest, status.signer, status.status)
+		}
+	}
+
 	validityT.log.Info().Msg("update signatures validity completed")
 
 	return nil
 }
+
+// getSignatureStatuses returns the status of each signature of the repo, a signature is trusted if one
+// of its layers was verified and its certificate didn't expire, like in HasTrustedSignature.
+func getSignatureStatuses(repoMeta mTypes.RepoMetadata) map[signatureKey]signatureStatus {
+	statuses := map[signatureKey]signatureStatus{}
+
+	for manifestDigest, manifestSignatures := range repoMeta.Signatures {
+		for signatureType, signatures := range manifestSignatures {
+			for _, signature := range signatures {
+				status := signatureStatus{status: SignatureUntrusted}
+
+				for _, layer := range signature.LayersInfo {
+					if layer.Signer == "" {
+						continue
+					}
+
+					if layer.Date.IsZero() || time.Now().Before(layer.Date) {
+						status = signatureStatus{signer: layer.Signer, status: SignatureTrusted}
+
+						break
+					}
+
+					status = signatureStatus{signer: layer.Signer, status: SignatureExpired}
+				}
+
+				statuses[signatureKey{manifestDigest, signatureType, signature.SignatureManifestDigest}] = status
+			}
+		}
+	}
+
+	return statuses
+}
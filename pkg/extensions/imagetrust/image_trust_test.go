@@ -5,7 +5,12 @@ package imagetrust_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +18,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +33,10 @@ import (
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/generate"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	cosigntest "github.com/sigstore/cosign/v2/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/resty.v1"
 
@@ -37,6 +47,8 @@ import (
 	zcommon "zotregistry.io/zot/pkg/common"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/imagetrust"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
 	"zotregistry.io/zot/pkg/test"
 	. "zotregistry.io/zot/pkg/test/image-utils"
 	"zotregistry.io/zot/pkg/test/mocks"
@@ -555,6 +567,356 @@ func TestCheckExpiryErr(t *testing.T) {
 	})
 }
 
+func TestCosignKeylessVerification(t *testing.T) {
+	Convey("Verify cosign keyless signatures", t, func() {
+		rootDir := t.TempDir()
+
+		rootCert, rootKey, err := cosigntest.GenerateRootCa()
+		So(err, ShouldBeNil)
+
+		leafCert, leafKey, err := cosigntest.GenerateLeafCert("user@example.com", "https://issuer.example.com",
+			rootCert, rootKey)
+		So(err, ShouldBeNil)
+
+		rekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		rootPEM, err := cryptoutils.MarshalCertificateToPEM(rootCert)
+		So(err, ShouldBeNil)
+
+		leafPEM, err := cryptoutils.MarshalCertificateToPEM(leafCert)
+		So(err, ShouldBeNil)
+
+		rekorPEM, err := cryptoutils.MarshalPublicKeyToPEM(rekorKey.Public())
+		So(err, ShouldBeNil)
+
+		fulcioRootsPath := path.Join(rootDir, "fulcio.pem")
+		err = os.WriteFile(fulcioRootsPath, rootPEM, 0o600)
+		So(err, ShouldBeNil)
+
+		rekorKeyPath := path.Join(rootDir, "rekor.pub")
+		err = os.WriteFile(rekorKeyPath, rekorPEM, 0o600)
+		So(err, ShouldBeNil)
+
+		manifestDigest := digest.FromString("manifest")
+
+		payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"localhost/repo"},`+
+			`"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+			manifestDigest))
+
+		payloadHash := sha256.Sum256(payload)
+
+		rawSignature, err := ecdsa.SignASN1(rand.Reader, leafKey, payloadHash[:])
+		So(err, ShouldBeNil)
+
+		signature := base64.StdEncoding.EncodeToString(rawSignature)
+
+		// the offline proof of the inclusion of the signature in Rekor
+		body := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"apiVersion":"0.0.1","kind":"hashedrekord",`+
+			`"spec":{"data":{"hash":{"algorithm":"sha256","value":"%s"}},`+
+			`"signature":{"content":"%s","publicKey":{"content":"%s"}}}}`,
+			hex.EncodeToString(payloadHash[:]), signature, base64.StdEncoding.EncodeToString(leafPEM))))
+
+		logID, err := cosign.GetTransparencyLogID(rekorKey.Public())
+		So(err, ShouldBeNil)
+
+		integratedTime := time.Now().Unix()
+
+		// the keys of the signed entry timestamp are sorted like in the canonical JSON
+		entry, err := json.Marshal(map[string]interface{}{
+			"body": body, "integratedTime": integratedTime, "logID": logID, "logIndex": 1,
+		})
+		So(err, ShouldBeNil)
+
+		entryHash := sha256.Sum256(entry)
+
+		signedEntryTimestamp, err := ecdsa.SignASN1(rand.Reader, rekorKey, entryHash[:])
+		So(err, ShouldBeNil)
+
+		rekorBundle, err := json.Marshal(bundle.RekorBundle{
+			SignedEntryTimestamp: signedEntryTimestamp,
+			Payload: bundle.RekorPayload{
+				Body: body, IntegratedTime: integratedTime, LogIndex: 1, LogID: logID,
+			},
+		})
+		So(err, ShouldBeNil)
+
+		layerInfo := mTypes.LayerInfo{
+			LayerContent: payload,
+			SignatureKey: signature,
+			Certificate:  string(leafPEM),
+			Bundle:       string(rekorBundle),
+		}
+
+		keylessConfig := &extconf.CosignKeylessConfig{
+			FulcioRoots:    fulcioRootsPath,
+			RekorPublicKey: rekorKeyPath,
+			Identities: []extconf.CosignKeylessIdentity{
+				{Issuer: "https://issuer.example.com", SubjectRegExp: ".*@example.com"},
+			},
+		}
+
+		verifier, err := imagetrust.NewCosignKeylessVerifier(keylessConfig)
+		So(err, ShouldBeNil)
+
+		imgTrustStore := &imagetrust.ImageTrustStore{CosignKeyless: verifier}
+
+		Convey("Trusted identity", func() {
+			author, isTrusted, err := imgTrustStore.VerifyCosignKeylessSignature(layerInfo, manifestDigest)
+			So(err, ShouldBeNil)
+			So(isTrusted, ShouldBeTrue)
+			So(author, ShouldEqual, "user@example.com (https://issuer.example.com)")
+		})
+
+		Convey("Untrusted identity", func() {
+			keylessConfig.Identities = []extconf.CosignKeylessIdentity{
+				{Issuer: "https://issuer.example.com", Subject: "admin@example.com"},
+			}
+
+			verifier, err := imagetrust.NewCosignKeylessVerifier(keylessConfig)
+			So(err, ShouldBeNil)
+
+			_, isTrusted, err := verifier.Verify(layerInfo, manifestDigest)
+			So(err, ShouldNotBeNil)
+			So(isTrusted, ShouldBeFalse)
+		})
+
+		Convey("Untrusted Fulcio root", func() {
+			otherRootCert, _, err := cosigntest.GenerateRootCa()
+			So(err, ShouldBeNil)
+
+			otherRootPEM, err := cryptoutils.MarshalCertificateToPEM(otherRootCert)
+			So(err, ShouldBeNil)
+
+			err = os.WriteFile(fulcioRootsPath, otherRootPEM, 0o600)
+			So(err, ShouldBeNil)
+
+			verifier, err := imagetrust.NewCosignKeylessVerifier(keylessConfig)
+			So(err, ShouldBeNil)
+
+			_, isTrusted, err := verifier.Verify(layerInfo, manifestDigest)
+			So(err, ShouldNotBeNil)
+			So(isTrusted, ShouldBeFalse)
+		})
+
+		Convey("Reload the verification material", func() {
+			otherRootCert, _, err := cosigntest.GenerateRootCa()
+			So(err, ShouldBeNil)
+
+			otherRootPEM, err := cryptoutils.MarshalCertificateToPEM(otherRootCert)
+			So(err, ShouldBeNil)
+
+			err = os.WriteFile(fulcioRootsPath, otherRootPEM, 0o600)
+			So(err, ShouldBeNil)
+
+			err = verifier.Reload()
+			So(err, ShouldBeNil)
+
+			_, isTrusted, err := verifier.Verify(layerInfo, manifestDigest)
+			So(err, ShouldNotBeNil)
+			So(isTrusted, ShouldBeFalse)
+
+			// the previous material is kept if the new one can't be read
+			err = os.WriteFile(fulcioRootsPath, rootPEM, 0o600)
+			So(err, ShouldBeNil)
+
+			err = verifier.Reload()
+			So(err, ShouldBeNil)
+
+			err = os.Remove(fulcioRootsPath)
+			So(err, ShouldBeNil)
+
+			err = verifier.Reload()
+			So(err, ShouldNotBeNil)
+
+			_, isTrusted, err = verifier.Verify(layerInfo, manifestDigest)
+			So(err, ShouldBeNil)
+			So(isTrusted, ShouldBeTrue)
+		})
+
+		Convey("Signature of another manifest", func() {
+			_, isTrusted, err := verifier.Verify(layerInfo, digest.FromString("other manifest"))
+			So(err, ShouldNotBeNil)
+			So(isTrusted, ShouldBeFalse)
+		})
+
+		Convey("Invalid Rekor bundle", func() {
+			layerInfo.Bundle = "{"
+
+			_, isTrusted, err := verifier.Verify(layerInfo, manifestDigest)
+			So(err, ShouldNotBeNil)
+			So(isTrusted, ShouldBeFalse)
+
+			otherRekorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			So(err, ShouldBeNil)
+
+			otherRekorPEM, err := cryptoutils.MarshalPublicKeyToPEM(otherRekorKey.Public())
+			So(err, ShouldBeNil)
+
+			err = os.WriteFile(rekorKeyPath, otherRekorPEM, 0o600)
+			So(err, ShouldBeNil)
+
+			verifier, err := imagetrust.NewCosignKeylessVerifier(keylessConfig)
+			So(err, ShouldBeNil)
+
+			layerInfo.Bundle = string(rekorBundle)
+
+			_, isTrusted, err = verifier.Verify(layerInfo, manifestDigest)
+			So(err, ShouldNotBeNil)
+			So(isTrusted, ShouldBeFalse)
+		})
+
+		Convey("No Rekor bundle offline", func() {
+			layerInfo.Bundle = ""
+
+			_, isTrusted, err := verifier.Verify(layerInfo, manifestDigest)
+			So(err, ShouldNotBeNil)
+			So(isTrusted, ShouldBeFalse)
+		})
+
+		Convey("Keyless verification not configured", func() {
+			imgTrustStore := &imagetrust.ImageTrustStore{}
+
+			_, isTrusted, err := imgTrustStore.VerifyCosignKeylessSignature(layerInfo, manifestDigest)
+			So(err, ShouldEqual, zerr.ErrCosignKeylessNotConfigured)
+			So(isTrusted, ShouldBeFalse)
+		})
+
+		Convey("Invalid verification material", func() {
+			keylessConfig.FulcioRoots = path.Join(rootDir, "missing.pem")
+
+			_, err := imagetrust.NewCosignKeylessVerifier(keylessConfig)
+			So(err, ShouldNotBeNil)
+
+			keylessConfig.FulcioRoots = fulcioRootsPath
+			keylessConfig.RekorPublicKey = fulcioRootsPath
+
+			_, err = imagetrust.NewCosignKeylessVerifier(keylessConfig)
+			So(err, ShouldNotBeNil)
+
+			keylessConfig.RekorPublicKey = rekorKeyPath
+			keylessConfig.CTLogPublicKey = path.Join(rootDir, "missing.pub")
+
+			_, err = imagetrust.NewCosignKeylessVerifier(keylessConfig)
+			So(err, ShouldNotBeNil)
+
+			keylessConfig.CTLogPublicKey = ""
+			keylessConfig.RekorURL = "https://rekor.example.com"
+
+			_, err = imagetrust.NewCosignKeylessVerifier(keylessConfig)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+type statusNotifier struct {
+	changes []string
+}
+
+func (notifier *statusNotifier) SignatureStatusChanged(repo, manifestDigest, signatureType, signatureDigest,
+	signer, status string,
+) {
+	notifier.changes = append(notifier.changes, strings.Join([]string{repo, manifestDigest, signatureType,
+		signatureDigest, signer, status}, " "))
+}
+
+func TestSignaturesValidityTask(t *testing.T) {
+	Convey("Verify the signatures again and report the changes of their status", t, func() {
+		manifestDigest := digest.FromString("manifest").String()
+		signatureDigest := digest.FromString("signature").String()
+
+		getRepoMeta := func(layerInfo mTypes.LayerInfo) mTypes.RepoMetadata {
+			return mTypes.RepoMetadata{
+				Name: "repo",
+				Signatures: map[string]mTypes.ManifestSignatures{
+					manifestDigest: {zcommon.CosignSignature: []mTypes.SignatureInfo{{
+						SignatureManifestDigest: signatureDigest,
+						LayersInfo:              []mTypes.LayerInfo{layerInfo},
+					}}},
+				},
+			}
+		}
+
+		storedRepoMeta := getRepoMeta(mTypes.LayerInfo{})
+		verifiedRepoMeta := getRepoMeta(mTypes.LayerInfo{Signer: "alice"})
+
+		metaDB := mocks.MetaDBMock{
+			GetMultipleRepoMetaFn: func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return []mTypes.RepoMetadata{storedRepoMeta}, nil
+			},
+			GetRepoMetaFn: func(repo string) (mTypes.RepoMetadata, error) {
+				return storedRepoMeta, nil
+			},
+			UpdateSignaturesValidityFn: func(repo string, manifestDigest digest.Digest) error {
+				storedRepoMeta = verifiedRepoMeta
+
+				return nil
+			},
+		}
+
+		notifier := &statusNotifier{}
+		generator := imagetrust.NewTaskGenerator(metaDB, notifier, log.NewLogger("debug", ""))
+
+		runTasks := func() {
+			generator.Reset()
+
+			for !generator.IsDone() {
+				task, err := generator.Next()
+				So(err, ShouldBeNil)
+
+				if task != nil {
+					So(task.DoWork(context.Background()), ShouldBeNil)
+				}
+			}
+		}
+
+		runTasks()
+		So(notifier.changes, ShouldResemble, []string{
+			"repo " + manifestDigest + " cosign " + signatureDigest + " alice trusted",
+		})
+
+		// the status doesn't change if the signature is verified the same way
+		runTasks()
+		So(notifier.changes, ShouldHaveLength, 1)
+
+		verifiedRepoMeta = getRepoMeta(mTypes.LayerInfo{Signer: "alice", Date: time.Now().Add(-time.Hour)})
+
+		runTasks()
+		So(notifier.changes, ShouldHaveLength, 2)
+		So(notifier.changes[1], ShouldEqual, "repo "+manifestDigest+" cosign "+signatureDigest+" alice expired")
+
+		verifiedRepoMeta = getRepoMeta(mTypes.LayerInfo{})
+
+		runTasks()
+		So(notifier.changes, ShouldHaveLength, 3)
+		So(notifier.changes[2], ShouldEqual, "repo "+manifestDigest+" cosign "+signatureDigest+"  untrusted")
+
+		Convey("Verification errors", func() {
+			metaDB.GetRepoMetaFn = func(repo string) (mTypes.RepoMetadata, error) {
+				return mTypes.RepoMetadata{}, zerr.ErrRepoMetaNotFound
+			}
+			task := imagetrust.NewValidityTask(metaDB, storedRepoMeta, nil, log.NewLogger("debug", ""))
+			So(task.DoWork(context.Background()), ShouldBeNil)
+
+			metaDB.GetRepoMetaFn = func(repo string) (mTypes.RepoMetadata, error) {
+				return mTypes.RepoMetadata{}, errUnexpectedError
+			}
+			task = imagetrust.NewValidityTask(metaDB, storedRepoMeta, nil, log.NewLogger("debug", ""))
+			So(task.DoWork(context.Background()), ShouldEqual, errUnexpectedError)
+
+			metaDB.GetRepoMetaFn = func(repo string) (mTypes.RepoMetadata, error) {
+				return storedRepoMeta, nil
+			}
+			metaDB.UpdateSignaturesValidityFn = func(repo string, manifestDigest digest.Digest) error {
+				return errUnexpectedError
+			}
+			task = imagetrust.NewValidityTask(metaDB, storedRepoMeta, nil, log.NewLogger("debug", ""))
+			So(task.DoWork(context.Background()), ShouldEqual, errUnexpectedError)
+		})
+	})
+}
+
 func TestLocalTrustStoreUploadErr(t *testing.T) {
 	Convey("certificate can't be stored", t, func() {
 		rootDir := t.TempDir()
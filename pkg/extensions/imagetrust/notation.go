@@ -37,6 +37,9 @@ import (
 const (
 	notationDirRelativePath = "_notation"
 	truststoreName          = "default"
+	// the registry of the artifact references matched against the registry scopes of the trust policies,
+	// e.g. "zot/repo" is the scope of the "repo" repository
+	NotationRegistryName = "zot"
 )
 
 type CertificateLocalStorage struct {
@@ -150,6 +153,21 @@ func InitTrustpolicyFile(notationStorage certificateStorage) error {
 	return notationStorage.InitTrustpolicy([]byte(trustPolicy))
 }
 
+// SetNotationTrustPolicy replaces the default trust policy document, which trusts all the uploaded certificates.
+func SetNotationTrustPolicy(notationStorage certificateStorage, trustPolicy []byte) error {
+	policyDocument := &trustpolicy.Document{}
+
+	if err := json.Unmarshal(trustPolicy, policyDocument); err != nil {
+		return fmt.Errorf("%w: %w", zerr.ErrInvalidTrustPolicy, err)
+	}
+
+	if err := policyDocument.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", zerr.ErrInvalidTrustPolicy, err)
+	}
+
+	return notationStorage.InitTrustpolicy(trustPolicy)
+}
+
 func (local *CertificateLocalStorage) InitTrustpolicy(trustpolicy []byte) error {
 	notationDir, err := local.GetNotationDirPath()
 	if err != nil {
@@ -392,16 +410,16 @@ func VerifyNotationSignature(
 
 	// Set VerifyOptions.
 	opts := notation.VerifierVerifyOptions{
-		// ArtifactReference is important to validate registry scope format
-		// If "registryScopes" field from trustpolicy.json file is not wildcard then "domain:80/repo@" should not be hardcoded
-		ArtifactReference:  "domain:80/repo@" + artifactReference,
+		// ArtifactReference is important to validate registry scope format,
+		// the trust policies are selected by matching "zot/{repo}" against their "registryScopes"
+		ArtifactReference:  NotationRegistryName + "/" + artifactReference,
 		SignatureMediaType: signatureMediaType,
 		PluginConfig:       map[string]string{},
 	}
 
 	// Verify the notation signature which should be associated with the artifactDescriptor.
 	outcome, err := verifier.Verify(ctx, artifactDescriptor, rawSignature, opts)
-	if outcome.EnvelopeContent != nil {
+	if outcome != nil && outcome.EnvelopeContent != nil {
 		author = outcome.EnvelopeContent.SignerInfo.CertificateChain[0].Subject.String()
 
 		if outcome.VerificationLevel == trustpolicy.LevelStrict && (err == nil ||
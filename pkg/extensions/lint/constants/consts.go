@@ -0,0 +1,10 @@
+package constants
+
+// what happens to the images violating a lint rule.
+const (
+	EnforceMode = "enforce" // the push is rejected
+	WarnMode    = "warn"    // the violations are only logged
+)
+
+// DefaultPolicyQuery is the rule of the Rego policies returning the reasons a pushed image is denied.
+const DefaultPolicyQuery = "data.zot.admission.deny"
@@ -18,14 +18,21 @@ import (
 
 type Linter struct {
 	config *config.LintConfig
+	policy *policyEvaluator
 	log    log.Logger
 }
 
 func NewLinter(config *config.LintConfig, log log.Logger) *Linter {
-	return &Linter{
+	linter := &Linter{
 		config: config,
 		log:    log,
 	}
+
+	if config != nil && config.Policy != nil {
+		linter.policy = newPolicyEvaluator(config.Policy, log)
+	}
+
+	return linter
 }
 
 func (linter *Linter) CheckMandatoryAnnotations(repo string, manifestDigest godigest.Digest,
@@ -111,10 +118,53 @@ func (linter *Linter) CheckMandatoryAnnotations(repo string, manifestDigest godi
 	return true, nil
 }
 
+// Lint checks the mandatory annotations and the rules of the image manifests, the rules of the image indexes,
+// then the Rego policies of both.
 func (linter *Linter) Lint(repo string, manifestDigest godigest.Digest,
 	imageStore storageTypes.ImageStore,
 ) (bool, error) {
-	return linter.CheckMandatoryAnnotations(repo, manifestDigest, imageStore)
+	if linter.config == nil || !*linter.config.Enable {
+		return true, nil
+	}
+
+	content, err := imageStore.GetBlobContent(repo, manifestDigest)
+	if err != nil {
+		linter.log.Error().Err(err).Msg("linter: unable to get image manifest")
+
+		return false, err
+	}
+
+	var manifest struct {
+		MediaType string             `json:"mediaType"`
+		Manifests []ispec.Descriptor `json:"manifests"`
+	}
+
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		linter.log.Error().Err(err).Msg("linter: couldn't unmarshal manifest JSON")
+
+		return false, err
+	}
+
+	if manifest.MediaType == ispec.MediaTypeImageIndex || manifest.Manifests != nil {
+		pass, err := linter.CheckIndexRules(repo, manifestDigest, imageStore)
+		if !pass {
+			return pass, err
+		}
+
+		return linter.CheckPolicies(repo, manifestDigest, imageStore)
+	}
+
+	pass, err := linter.CheckMandatoryAnnotations(repo, manifestDigest, imageStore)
+	if !pass {
+		return pass, err
+	}
+
+	pass, err = linter.CheckImageRules(repo, manifestDigest, imageStore)
+	if !pass {
+		return pass, err
+	}
+
+	return linter.CheckPolicies(repo, manifestDigest, imageStore)
 }
 
 func getMissingAnnotations(mandatoryAnnotationsMap map[string]bool) []string {
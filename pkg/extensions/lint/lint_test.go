@@ -5,6 +5,7 @@ package lint_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -16,6 +17,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"gopkg.in/resty.v1"
 
+	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/api"
 	"zotregistry.io/zot/pkg/api/config"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
@@ -492,7 +494,7 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		var index ispec.Index
 
 		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
-		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil)
 
 		indexContent, err := imgStore.GetIndexContent("zot-test")
@@ -524,7 +526,7 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		var index ispec.Index
 
 		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
-		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil)
 
 		indexContent, err := imgStore.GetIndexContent("zot-test")
@@ -594,7 +596,7 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		index.Manifests = append(index.Manifests, manifestDesc)
 
 		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
-		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil)
 
 		pass, err := linter.CheckMandatoryAnnotations("zot-test", digest, imgStore)
@@ -656,7 +658,7 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		index.Manifests = append(index.Manifests, manifestDesc)
 
 		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
-		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil)
 
 		pass, err := linter.CheckMandatoryAnnotations("zot-test", digest, imgStore)
@@ -720,7 +722,7 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		index.Manifests = append(index.Manifests, manifestDesc)
 
 		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
-		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil)
 
 		pass, err := linter.CheckMandatoryAnnotations("zot-test", digest, imgStore)
@@ -783,7 +785,7 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		index.Manifests = append(index.Manifests, manifestDesc)
 
 		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
-		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil)
 
 		err = os.Chmod(path.Join(dir, "zot-test", "blobs"), 0o000)
@@ -881,7 +883,7 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		index.Manifests = append(index.Manifests, manifestDesc)
 
 		linter := lint.NewLinter(lintConfig, log.NewLogger("debug", ""))
-		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false,
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), linter, nil)
 
 		err = os.Chmod(path.Join(dir, "zot-test", "blobs", "sha256", manifest.Config.Digest.Encoded()), 0o000)
@@ -903,3 +905,185 @@ func TestVerifyMandatoryAnnotationsFunction(t *testing.T) {
 		So(pass, ShouldBeTrue)
 	})
 }
+
+func TestLintRules(t *testing.T) {
+	Convey("Lint rules", t, func() {
+		enable := true
+		logger := log.NewLogger("debug", "")
+		dir := t.TempDir()
+		testStoreCtlr := test.GetDefaultStoreController(dir, logger)
+
+		image := CreateImageWith().RandomLayers(3, 10).ImageConfig(ispec.Image{
+			Platform: ispec.Platform{OS: "linux", Architecture: "amd64"},
+			Config:   ispec.ImageConfig{Labels: map[string]string{"maintainer": "zot"}},
+		}).Annotations(map[string]string{ispec.AnnotationBaseImageName: "docker.io/library/centos:7"}).Build()
+
+		err := test.WriteImageToFileSystem(image, "zot-test", "0.0.1", testStoreCtlr)
+		So(err, ShouldBeNil)
+
+		multiarch := CreateMultiarchWith().Images([]Image{image}).Build()
+
+		err = test.WriteMultiArchImageToFileSystem(multiarch, "zot-test", "multiarch", testStoreCtlr)
+		So(err, ShouldBeNil)
+
+		lintConfig := &extconf.LintConfig{BaseConfig: extconf.BaseConfig{Enable: &enable}}
+		linter := lint.NewLinter(lintConfig, logger)
+		imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
+			logger, monitoring.NewMetricsServer(false, logger), linter, nil)
+
+		Convey("Image compliant with the rules", func() {
+			lintConfig.Rules = []extconf.LintRule{{
+				RequiredLabels:      []string{"maintainer"},
+				MaxLayers:           3,
+				MaxImageSize:        1 << 20,
+				ForbiddenBaseImages: []string{"docker.io/library/alpine:*"},
+				RequiredPlatforms:   []string{"linux/amd64"},
+			}}
+
+			pass, err := linter.Lint("zot-test", image.Digest(), imgStore)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+
+			pass, err = linter.Lint("zot-test", multiarch.Digest(), imgStore)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+		})
+
+		Convey("Image violating enforced rules", func() {
+			lintConfig.Rules = []extconf.LintRule{
+				{Repos: []string{"zot-*"}, RequiredLabels: []string{"maintainer", "vendor"}},
+				{MaxLayers: 2, MaxImageSize: 10},
+				{ForbiddenBaseImages: []string{"docker.io/library/centos:*"}},
+			}
+
+			pass, err := linter.Lint("zot-test", image.Digest(), imgStore)
+			So(pass, ShouldBeFalse)
+			So(errors.Is(err, zerr.ErrImageLintAnnotations), ShouldBeTrue)
+
+			details := zerr.GetDetails(err)["ruleViolations"]
+			So(details, ShouldContainSubstring, "missing label vendor")
+			So(details, ShouldContainSubstring, "3 layers, more than 2")
+			So(details, ShouldContainSubstring, "more than 10")
+			So(details, ShouldContainSubstring, "forbidden base image docker.io/library/centos:7")
+			So(details, ShouldNotContainSubstring, "missing label maintainer")
+		})
+
+		Convey("Index missing required platforms", func() {
+			lintConfig.Rules = []extconf.LintRule{{RequiredPlatforms: []string{"linux/amd64", "linux/arm/v7"}}}
+
+			pass, err := linter.Lint("zot-test", multiarch.Digest(), imgStore)
+			So(pass, ShouldBeFalse)
+			So(zerr.GetDetails(err)["ruleViolations"], ShouldContainSubstring, "missing platform linux/arm/v7")
+			So(zerr.GetDetails(err)["ruleViolations"], ShouldNotContainSubstring, "missing platform linux/amd64")
+
+			// the image rules are not checked on indexes
+			lintConfig.Rules = []extconf.LintRule{{MaxLayers: 1}}
+
+			pass, err = linter.Lint("zot-test", multiarch.Digest(), imgStore)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+		})
+
+		Convey("Rules not matching the repo or in warn mode", func() {
+			lintConfig.Rules = []extconf.LintRule{
+				{Repos: []string{"other/**"}, MaxLayers: 1},
+				{Mode: "warn", RequiredLabels: []string{"vendor"}, RequiredPlatforms: []string{"linux/arm64"}},
+			}
+
+			pass, err := linter.Lint("zot-test", image.Digest(), imgStore)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+
+			pass, err = linter.Lint("zot-test", multiarch.Digest(), imgStore)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+		})
+	})
+}
+
+func TestLintPolicies(t *testing.T) {
+	Convey("Rego policies", t, func() {
+		enable := true
+		logger := log.NewLogger("debug", "")
+		dir := t.TempDir()
+		policyDir := t.TempDir()
+		testStoreCtlr := test.GetDefaultStoreController(dir, logger)
+
+		policy := `package zot.admission
+
+deny[msg] {
+	not input.config.config.Labels.maintainer
+	msg := "the maintainer label is required"
+}
+
+deny[msg] {
+	startswith(input.repo, "prod/")
+	count(input.manifest.layers) > 1
+	msg := sprintf("%s images must have a single layer", [input.repo])
+}
+`
+		err := os.WriteFile(path.Join(policyDir, "admission.rego"), []byte(policy), 0o600)
+		So(err, ShouldBeNil)
+
+		image := CreateImageWith().RandomLayers(2, 10).ImageConfig(ispec.Image{
+			Config: ispec.ImageConfig{Labels: map[string]string{"maintainer": "zot"}},
+		}).Build()
+		unlabeledImage := CreateImageWith().RandomLayers(1, 10).DefaultConfig().Build()
+
+		for _, repo := range []string{"dev/app", "prod/app"} {
+			err = test.WriteImageToFileSystem(image, repo, "labeled", testStoreCtlr)
+			So(err, ShouldBeNil)
+
+			err = test.WriteImageToFileSystem(unlabeledImage, repo, "unlabeled", testStoreCtlr)
+			So(err, ShouldBeNil)
+		}
+
+		lintConfig := &extconf.LintConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &enable},
+			Policy:     &extconf.LintPolicyConfig{Paths: []string{policyDir}},
+		}
+
+		Convey("Enforced policies", func() {
+			linter := lint.NewLinter(lintConfig, logger)
+			imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
+				logger, monitoring.NewMetricsServer(false, logger), linter, nil)
+
+			pass, err := linter.Lint("dev/app", image.Digest(), imgStore)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+
+			pass, err = linter.Lint("dev/app", unlabeledImage.Digest(), imgStore)
+			So(pass, ShouldBeFalse)
+			So(errors.Is(err, zerr.ErrImageLintAnnotations), ShouldBeTrue)
+			So(zerr.GetDetails(err)["policyDenials"], ShouldContainSubstring, "the maintainer label is required")
+
+			pass, err = linter.Lint("prod/app", image.Digest(), imgStore)
+			So(pass, ShouldBeFalse)
+			So(zerr.GetDetails(err)["policyDenials"], ShouldContainSubstring, "prod/app images must have a single layer")
+		})
+
+		Convey("Policies in warn mode", func() {
+			lintConfig.Policy.Mode = "warn"
+			linter := lint.NewLinter(lintConfig, logger)
+			imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
+				logger, monitoring.NewMetricsServer(false, logger), linter, nil)
+
+			pass, err := linter.Lint("prod/app", unlabeledImage.Digest(), imgStore)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+		})
+
+		Convey("Invalid policies reject every image", func() {
+			err := os.WriteFile(path.Join(policyDir, "invalid.rego"), []byte("package zot.admission\ndeny[msg] {"), 0o600)
+			So(err, ShouldBeNil)
+
+			linter := lint.NewLinter(lintConfig, logger)
+			imgStore := local.NewImageStore(dir, false, false, 0, 0, false, false, false,
+				logger, monitoring.NewMetricsServer(false, logger), linter, nil)
+
+			pass, err := linter.Lint("dev/app", image.Digest(), imgStore)
+			So(err, ShouldNotBeNil)
+			So(pass, ShouldBeFalse)
+		})
+	})
+}
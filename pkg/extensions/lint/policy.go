@@ -0,0 +1,183 @@
+//go:build lint
+// +build lint
+
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/lint/constants"
+	"zotregistry.io/zot/pkg/log"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+/*
+policyEvaluator evaluates the pushed manifests against Rego policies, their query returns the reasons
+an image is denied, e.g.
+
+	package zot.admission
+
+	deny[msg] {
+		not input.config.config.Labels.maintainer
+		msg := "the maintainer label is required"
+	}
+
+The input is the repo, digest and media type of the manifest, the manifest itself, its image config
+and the descriptors of its referrers.
+*/
+type policyEvaluator struct {
+	query rego.PreparedEvalQuery
+	mode  string
+	// the policies couldn't be loaded, every push is rejected instead of bypassing them
+	err error
+}
+
+// PolicyInput is the input document of the Rego policies.
+type PolicyInput struct {
+	Repo      string                 `json:"repo"`
+	Digest    string                 `json:"digest"`
+	MediaType string                 `json:"mediaType"`
+	Manifest  map[string]interface{} `json:"manifest"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Referrers []ispec.Descriptor     `json:"referrers"`
+}
+
+func newPolicyEvaluator(policyConfig *config.LintPolicyConfig, log log.Logger) *policyEvaluator {
+	query := policyConfig.Query
+	if query == "" {
+		query = constants.DefaultPolicyQuery
+	}
+
+	evaluator := &policyEvaluator{mode: policyConfig.Mode}
+
+	evaluator.query, evaluator.err = rego.New(
+		rego.Query(query),
+		rego.Load(policyConfig.Paths, nil),
+	).PrepareForEval(context.Background())
+	if evaluator.err != nil {
+		log.Error().Err(evaluator.err).Strs("paths", policyConfig.Paths).Msg("linter: couldn't load the Rego policies")
+	}
+
+	return evaluator
+}
+
+// CheckPolicies evaluates the Rego policies on a pushed manifest.
+func (linter *Linter) CheckPolicies(repo string, manifestDigest godigest.Digest,
+	imgStore storageTypes.ImageStore,
+) (bool, error) {
+	if linter.policy == nil {
+		return true, nil
+	}
+
+	if linter.policy.err != nil {
+		return false, linter.policy.err
+	}
+
+	input, err := getPolicyInput(repo, manifestDigest, imgStore)
+	if err != nil {
+		linter.log.Error().Err(err).Str("repository", repo).Str("digest", manifestDigest.String()).
+			Msg("linter: couldn't get the input of the Rego policies")
+
+		return false, err
+	}
+
+	results, err := linter.policy.query.Eval(context.Background(), rego.EvalInput(input))
+	if err != nil {
+		linter.log.Error().Err(err).Str("repository", repo).Str("digest", manifestDigest.String()).
+			Msg("linter: couldn't evaluate the Rego policies")
+
+		return false, err
+	}
+
+	denials := getDenials(results)
+	if len(denials) == 0 {
+		return true, nil
+	}
+
+	if linter.policy.mode == constants.WarnMode {
+		linter.log.Warn().Str("repository", repo).Str("digest", manifestDigest.String()).
+			Strs("denials", denials).Msg("linter: image denied by Rego policies")
+
+		return true, nil
+	}
+
+	msg := fmt.Sprintf("linter: image %s denied by the Rego policies: %s", manifestDigest, strings.Join(denials, ", "))
+	linter.log.Error().Str("repository", repo).Msg(msg)
+
+	return false, zerr.NewError(zerr.ErrImageLintAnnotations).AddDetail("policyDenials", msg)
+}
+
+func getPolicyInput(repo string, manifestDigest godigest.Digest, imgStore storageTypes.ImageStore,
+) (PolicyInput, error) {
+	input := PolicyInput{Repo: repo, Digest: manifestDigest.String(), Referrers: []ispec.Descriptor{}}
+
+	content, err := imgStore.GetBlobContent(repo, manifestDigest)
+	if err != nil {
+		return input, err
+	}
+
+	if err := json.Unmarshal(content, &input.Manifest); err != nil {
+		return input, err
+	}
+
+	var manifest ispec.Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return input, err
+	}
+
+	input.MediaType = manifest.MediaType
+
+	if manifest.Config.MediaType == ispec.MediaTypeImageConfig {
+		content, err := imgStore.GetBlobContent(repo, manifest.Config.Digest)
+		if err != nil {
+			return input, err
+		}
+
+		if err := json.Unmarshal(content, &input.Config); err != nil {
+			return input, err
+		}
+	}
+
+	referrers, err := imgStore.GetReferrers(repo, manifestDigest, nil)
+	if err == nil {
+		input.Referrers = append(input.Referrers, referrers.Manifests...)
+	}
+
+	return input, nil
+}
+
+// getDenials returns the messages of the query results, which are sets or arrays of messages.
+func getDenials(results rego.ResultSet) []string {
+	denials := []string{}
+
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			values, ok := expression.Value.([]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, value := range values {
+				if msg, ok := value.(string); ok {
+					denials = append(denials, msg)
+				} else {
+					denials = append(denials, fmt.Sprint(value))
+				}
+			}
+		}
+	}
+
+	sort.Strings(denials)
+
+	return denials
+}
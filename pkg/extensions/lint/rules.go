@@ -0,0 +1,275 @@
+//go:build lint
+// +build lint
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/lint/constants"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+// CheckImageRules checks an image manifest against the rules matching its repo.
+func (linter *Linter) CheckImageRules(repo string, manifestDigest godigest.Digest,
+	imgStore storageTypes.ImageStore,
+) (bool, error) {
+	rules := linter.getRepoRules(repo)
+	if len(rules) == 0 {
+		return true, nil
+	}
+
+	content, err := imgStore.GetBlobContent(repo, manifestDigest)
+	if err != nil {
+		linter.log.Error().Err(err).Msg("linter: unable to get image manifest")
+
+		return false, err
+	}
+
+	var manifest ispec.Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		linter.log.Error().Err(err).Msg("linter: couldn't unmarshal manifest JSON")
+
+		return false, err
+	}
+
+	// the config is only read if a rule requires labels
+	var imageConfig *ispec.Image
+
+	violations := map[*config.LintRule][]string{}
+
+	for _, rule := range rules {
+		if len(rule.RequiredLabels) > 0 && imageConfig == nil {
+			imageConfig = &ispec.Image{}
+
+			content, err := imgStore.GetBlobContent(repo, manifest.Config.Digest)
+			if err != nil {
+				linter.log.Error().Err(err).Msg("linter: couldn't get config JSON " + manifest.Config.Digest.String())
+
+				return false, err
+			}
+
+			if err := json.Unmarshal(content, imageConfig); err != nil {
+				linter.log.Error().Err(err).Msg("linter: couldn't unmarshal config JSON " + manifest.Config.Digest.String())
+
+				return false, err
+			}
+		}
+
+		violations[rule] = checkImageRule(rule, manifest, imageConfig)
+	}
+
+	return linter.report(repo, manifestDigest, rules, violations)
+}
+
+// CheckIndexRules checks an image index against the rules matching its repo.
+func (linter *Linter) CheckIndexRules(repo string, indexDigest godigest.Digest,
+	imgStore storageTypes.ImageStore,
+) (bool, error) {
+	rules := linter.getRepoRules(repo)
+	if len(rules) == 0 {
+		return true, nil
+	}
+
+	content, err := imgStore.GetBlobContent(repo, indexDigest)
+	if err != nil {
+		linter.log.Error().Err(err).Msg("linter: unable to get image index")
+
+		return false, err
+	}
+
+	var index ispec.Index
+	if err := json.Unmarshal(content, &index); err != nil {
+		linter.log.Error().Err(err).Msg("linter: couldn't unmarshal index JSON")
+
+		return false, err
+	}
+
+	platforms := []ispec.Platform{}
+
+	for _, manifest := range index.Manifests {
+		if manifest.Platform != nil {
+			platforms = append(platforms, *manifest.Platform)
+
+			continue
+		}
+
+		// the platform is optional in the index, it's also found in the image config
+		if manifest.MediaType == ispec.MediaTypeImageManifest {
+			platform, err := getManifestPlatform(repo, manifest.Digest, imgStore)
+			if err != nil {
+				linter.log.Error().Err(err).Str("digest", manifest.Digest.String()).
+					Msg("linter: couldn't get the platform of the index manifest")
+
+				return false, err
+			}
+
+			platforms = append(platforms, platform)
+		}
+	}
+
+	violations := map[*config.LintRule][]string{}
+
+	for _, rule := range rules {
+		for _, required := range rule.RequiredPlatforms {
+			if !hasPlatform(platforms, required) {
+				violations[rule] = append(violations[rule], "missing platform "+required)
+			}
+		}
+	}
+
+	return linter.report(repo, indexDigest, rules, violations)
+}
+
+// report logs the rule violations, the image is rejected if an enforced rule is violated.
+func (linter *Linter) report(repo string, digest godigest.Digest, rules []*config.LintRule,
+	violations map[*config.LintRule][]string,
+) (bool, error) {
+	enforced := []string{}
+
+	for _, rule := range rules {
+		if len(violations[rule]) == 0 {
+			continue
+		}
+
+		if rule.Mode == constants.WarnMode {
+			linter.log.Warn().Str("repository", repo).Str("digest", digest.String()).
+				Strs("violations", violations[rule]).Msg("linter: image violates lint rule")
+
+			continue
+		}
+
+		enforced = append(enforced, violations[rule]...)
+	}
+
+	if len(enforced) == 0 {
+		return true, nil
+	}
+
+	msg := fmt.Sprintf("linter: image %s violates the lint rules: %s", digest, strings.Join(enforced, ", "))
+	linter.log.Error().Str("repository", repo).Msg(msg)
+
+	return false, zerr.NewError(zerr.ErrImageLintAnnotations).AddDetail("ruleViolations", msg)
+}
+
+func (linter *Linter) getRepoRules(repo string) []*config.LintRule {
+	rules := []*config.LintRule{}
+
+	for idx := range linter.config.Rules {
+		rule := &linter.config.Rules[idx]
+
+		if len(rule.Repos) == 0 || linter.matchesAny(rule.Repos, repo) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func (linter *Linter) matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		matched, err := glob.Match(pattern, value)
+		if err != nil {
+			linter.log.Error().Err(err).Str("pattern", pattern).Msg("error while parsing glob pattern, skipping it...")
+
+			continue
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkImageRule(rule *config.LintRule, manifest ispec.Manifest, imageConfig *ispec.Image) []string {
+	violations := []string{}
+
+	for _, label := range rule.RequiredLabels {
+		if _, ok := imageConfig.Config.Labels[label]; !ok {
+			violations = append(violations, "missing label "+label)
+		}
+	}
+
+	if rule.MaxLayers > 0 && len(manifest.Layers) > rule.MaxLayers {
+		violations = append(violations, fmt.Sprintf("%d layers, more than %d", len(manifest.Layers), rule.MaxLayers))
+	}
+
+	if rule.MaxImageSize > 0 {
+		size := manifest.Config.Size
+
+		for _, layer := range manifest.Layers {
+			size += layer.Size
+		}
+
+		if size > rule.MaxImageSize {
+			violations = append(violations, fmt.Sprintf("%d bytes, more than %d", size, rule.MaxImageSize))
+		}
+	}
+
+	if baseImage := manifest.Annotations[ispec.AnnotationBaseImageName]; baseImage != "" {
+		for _, pattern := range rule.ForbiddenBaseImages {
+			if matched, _ := glob.Match(pattern, baseImage); matched {
+				violations = append(violations, "forbidden base image "+baseImage)
+
+				break
+			}
+		}
+	}
+
+	return violations
+}
+
+func getManifestPlatform(repo string, manifestDigest godigest.Digest, imgStore storageTypes.ImageStore,
+) (ispec.Platform, error) {
+	var manifest ispec.Manifest
+
+	content, err := imgStore.GetBlobContent(repo, manifestDigest)
+	if err != nil {
+		return ispec.Platform{}, err
+	}
+
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return ispec.Platform{}, err
+	}
+
+	var imageConfig ispec.Image
+
+	content, err = imgStore.GetBlobContent(repo, manifest.Config.Digest)
+	if err != nil {
+		return ispec.Platform{}, err
+	}
+
+	if err := json.Unmarshal(content, &imageConfig); err != nil {
+		return ispec.Platform{}, err
+	}
+
+	return imageConfig.Platform, nil
+}
+
+// hasPlatform checks if one of the platforms is the required "os/arch[/variant]" platform,
+// any variant matches a required platform without variant.
+func hasPlatform(platforms []ispec.Platform, required string) bool {
+	parts := strings.Split(required, "/")
+
+	for _, platform := range platforms {
+		if len(parts) < 2 || platform.OS != parts[0] || platform.Architecture != parts[1] { //nolint: gomnd
+			continue
+		}
+
+		if len(parts) == 2 || platform.Variant == parts[2] { //nolint: gomnd
+			return true
+		}
+	}
+
+	return false
+}
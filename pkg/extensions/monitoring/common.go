@@ -1,9 +1,20 @@
 package monitoring
 
 import (
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// the label of the repos which aren't among the most requested ones.
+	OtherReposLabel = "other"
+	// how often the most requested repos are ranked again.
+	repoLabelsRankingInterval = 10 * time.Minute
 )
 
 var re = regexp.MustCompile(`\/v2\/(.*?)\/(blobs|tags|manifests)\/(.*)$`)
@@ -16,6 +27,137 @@ type MetricServer interface {
 	IsEnabled() bool
 }
 
+/*
+RepoLabels caps the number of repo labels of the traffic metrics, only the most requested repos have
+their own label, the traffic of the other repos is counted with the "other" label.
+
+The repos are ranked by number of requests every 10 minutes, a repo which drops out of the top keeps
+its series, but its new traffic is counted in "other".
+*/
+type RepoLabels struct {
+	max         int
+	requests    map[string]uint64
+	top         map[string]bool
+	lastRanking time.Time
+	lock        sync.Mutex
+}
+
+func NewRepoLabels(max int) *RepoLabels {
+	return &RepoLabels{
+		max:         max,
+		requests:    map[string]uint64{},
+		top:         map[string]bool{},
+		lastRanking: time.Now(),
+	}
+}
+
+// Count counts a request to the repo, for ranking the repos.
+func (labels *RepoLabels) Count(repo string) {
+	if labels == nil || labels.max <= 0 {
+		return
+	}
+
+	labels.lock.Lock()
+	defer labels.lock.Unlock()
+
+	labels.requests[repo]++
+}
+
+// Get returns the label of the repo.
+func (labels *RepoLabels) Get(repo string) string {
+	if labels == nil || labels.max <= 0 {
+		return repo
+	}
+
+	labels.lock.Lock()
+	defer labels.lock.Unlock()
+
+	if time.Since(labels.lastRanking) > repoLabelsRankingInterval {
+		labels.rank()
+	}
+
+	if labels.top[repo] {
+		return repo
+	}
+
+	// until the top is full, the repos get a label on their first request
+	if len(labels.top) < labels.max {
+		labels.top[repo] = true
+
+		return repo
+	}
+
+	return OtherReposLabel
+}
+
+func (labels *RepoLabels) rank() {
+	repos := make([]string, 0, len(labels.requests))
+
+	for repo := range labels.requests {
+		repos = append(repos, repo)
+	}
+
+	sort.SliceStable(repos, func(i, j int) bool {
+		if labels.requests[repos[i]] != labels.requests[repos[j]] {
+			return labels.requests[repos[i]] > labels.requests[repos[j]]
+		}
+
+		return repos[i] < repos[j]
+	})
+
+	if len(repos) > labels.max {
+		repos = repos[:labels.max]
+	}
+
+	labels.top = map[string]bool{}
+
+	for _, repo := range repos {
+		labels.top[repo] = true
+	}
+
+	labels.lastRanking = time.Now()
+}
+
+// LimitRepoLabels caps the number of repo labels of the traffic metrics, 0 means no limit.
+func LimitRepoLabels(ms MetricServer, max int) {
+	if server, ok := ms.(*metricServer); ok && max > 0 {
+		server.repoLabels = NewRepoLabels(max)
+	}
+}
+
+func getRepoLabel(ms MetricServer, repo string) string {
+	server, ok := ms.(*metricServer)
+	if !ok {
+		return repo
+	}
+
+	return server.repoLabels.Get(repo)
+}
+
+func countRepoRequest(ms MetricServer, repo string) {
+	if server, ok := ms.(*metricServer); ok {
+		server.repoLabels.Count(repo)
+	}
+}
+
+// getRepoTraffic returns the repo of a request and the bytes it served, only the successful pulls of
+// blobs and manifests serve bytes, the requests failing with a client or server error count as errors.
+func getRepoTraffic(method, path string, statusCode, bodySize int) (string, int, bool, bool) {
+	match := re.FindStringSubmatch(path)
+	if len(match) < 2 { //nolint: gomnd
+		return "", 0, false, false
+	}
+
+	isError := statusCode >= http.StatusBadRequest
+
+	servedBytes := 0
+	if !isError && method == http.MethodGet && match[2] != "tags" {
+		servedBytes = bodySize
+	}
+
+	return match[1], servedBytes, isError, true
+}
+
 func getDirSize(path string) (int64, error) {
 	var size int64
 
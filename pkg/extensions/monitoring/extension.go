@@ -66,6 +66,22 @@ var (
 		},
 		[]string{"repo"},
 	)
+	repoServedBytes = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "repo_served_bytes_total",
+			Help:      "Total number of bytes of manifests and blobs served",
+		},
+		[]string{"repo"},
+	)
+	repoErrors = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "repo_errors_total",
+			Help:      "Total number of requests to a repo which failed",
+		},
+		[]string{"repo"},
+	)
 	serverInfo = promauto.NewGaugeVec( //nolint: gochecknoglobals
 		prometheus.GaugeOpts{
 			Namespace: metricsNamespace,
@@ -83,11 +99,134 @@ var (
 		},
 		[]string{"storageName", "lockType"},
 	)
+	syncErrors = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "sync_errors_total",
+			Help:      "Total number of errors while syncing a repo from an upstream registry",
+		},
+		[]string{"registry", "repo"},
+	)
+	syncImagesPending = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "sync_images_pending",
+			Help:      "Number of images of a repo waiting to be synced from an upstream registry",
+		},
+		[]string{"registry", "repo"},
+	)
+	syncTransferredBytes = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "sync_transferred_bytes",
+			Help:      "Bytes of image blobs downloaded while syncing a repo from an upstream registry",
+		},
+		[]string{"registry", "repo"},
+	)
+	syncLastSuccess = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "sync_last_success_timestamp_seconds",
+			Help:      "Time of the last successful sync of a repo from an upstream registry",
+		},
+		[]string{"registry", "repo"},
+	)
+	syncRateLimitRemaining = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "sync_ratelimit_remaining",
+			Help:      "Remaining requests quota reported by an upstream registry in its rate limit headers",
+		},
+		[]string{"registry"},
+	)
+	repoQuotaUsageRatio = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "repo_quota_usage_ratio",
+			Help:      "Storage used by a zot repo relative to its quota",
+		},
+		[]string{"repo"},
+	)
+	metaDBDrift = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "metadb_drift_total",
+			Help:      "Total number of repos whose metadata drifted from the storage, by kind of drift",
+		},
+		[]string{"kind"},
+	)
+	metaDBLatency = promauto.NewHistogramVec( //nolint: gochecknoglobals
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "metadb_operation_latency_seconds",
+			Help:      "Latency of the MetaDB operations",
+			Buckets:   GetMetaDBLatencyBuckets(),
+		},
+		[]string{"operation"},
+	)
+	metaDBErrors = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "metadb_operation_errors_total",
+			Help:      "Total number of MetaDB operations which failed",
+		},
+		[]string{"operation"},
+	)
+	storageFreeBytes = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "storage_free_bytes",
+			Help:      "Free space of the file system of a storage root directory",
+		},
+		[]string{"storageName"},
+	)
+	gcBlobsReaped = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "gc_blobs_reaped_total",
+			Help:      "Total number of blobs removed by the garbage collection of a repo",
+		},
+		[]string{"repo"},
+	)
+	gcManifestsReaped = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "gc_manifests_reaped_total",
+			Help:      "Total number of manifests removed by the garbage collection of a repo",
+		},
+		[]string{"repo"},
+	)
+	gcReclaimedBytes = promauto.NewCounterVec( //nolint: gochecknoglobals
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "gc_reclaimed_bytes_total",
+			Help:      "Total size of the manifests and blobs removed by the garbage collection of a repo",
+		},
+		[]string{"repo"},
+	)
+	gcDuration = promauto.NewHistogramVec( //nolint: gochecknoglobals
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "gc_duration_seconds",
+			Help:      "Duration of the garbage collection of a repo",
+			Buckets:   GetDefaultBuckets(),
+		},
+		[]string{"repo"},
+	)
+	gcLastSuccess = promauto.NewGaugeVec( //nolint: gochecknoglobals
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "gc_last_success_timestamp_seconds",
+			Help:      "Last time the garbage collection of a repo succeeded",
+		},
+		[]string{"repo"},
+	)
 )
 
 type metricServer struct {
-	enabled bool
-	log     log.Logger
+	enabled    bool
+	repoLabels *RepoLabels
+	log        log.Logger
 }
 
 func GetDefaultBuckets() []float64 {
@@ -98,6 +237,10 @@ func GetStorageLatencyBuckets() []float64 {
 	return []float64{.001, .01, 0.1, 1, 5, 10, 15, 30, 60}
 }
 
+func GetMetaDBLatencyBuckets() []float64 {
+	return []float64{.0005, .001, .005, .01, .05, .1, .5, 1, 5}
+}
+
 func NewMetricsServer(enabled bool, log log.Logger) MetricServer {
 	return &metricServer{
 		enabled: enabled,
@@ -149,13 +292,33 @@ func ObserveHTTPRepoLatency(ms MetricServer, path string, latency time.Duration)
 		match := re.FindStringSubmatch(path)
 
 		if len(match) > 1 {
-			httpRepoLatency.WithLabelValues(match[1]).Observe(latency.Seconds())
+			httpRepoLatency.WithLabelValues(getRepoLabel(ms, match[1])).Observe(latency.Seconds())
 		} else {
 			httpRepoLatency.WithLabelValues("N/A").Observe(latency.Seconds())
 		}
 	})
 }
 
+func ObserveHTTPRepoTraffic(ms MetricServer, method, path string, statusCode, bodySize int) {
+	ms.SendMetric(func() {
+		repo, servedBytes, isError, ok := getRepoTraffic(method, path, statusCode, bodySize)
+		if !ok {
+			return
+		}
+
+		countRepoRequest(ms, repo)
+		label := getRepoLabel(ms, repo)
+
+		if servedBytes > 0 {
+			repoServedBytes.WithLabelValues(label).Add(float64(servedBytes))
+		}
+
+		if isError {
+			repoErrors.WithLabelValues(label).Inc()
+		}
+	})
+}
+
 func ObserveHTTPMethodLatency(ms MetricServer, method string, latency time.Duration) {
 	ms.SendMetric(func() {
 		httpMethodLatency.WithLabelValues(method).Observe(latency.Seconds())
@@ -164,7 +327,7 @@ func ObserveHTTPMethodLatency(ms MetricServer, method string, latency time.Durat
 
 func IncDownloadCounter(ms MetricServer, repo string) {
 	ms.SendMetric(func() {
-		downloadCounter.WithLabelValues(repo).Inc()
+		downloadCounter.WithLabelValues(getRepoLabel(ms, repo)).Inc()
 	})
 }
 
@@ -181,7 +344,7 @@ func SetStorageUsage(ms MetricServer, rootDir, repo string) {
 
 func IncUploadCounter(ms MetricServer, repo string) {
 	ms.SendMetric(func() {
-		uploadCounter.WithLabelValues(repo).Inc()
+		uploadCounter.WithLabelValues(getRepoLabel(ms, repo)).Inc()
 	})
 }
 
@@ -196,3 +359,93 @@ func ObserveStorageLockLatency(ms MetricServer, latency time.Duration, storageNa
 		storageLockLatency.WithLabelValues(storageName, lockType).Observe(latency.Seconds())
 	})
 }
+
+func IncSyncErrors(ms MetricServer, registry, repo string) {
+	ms.SendMetric(func() {
+		syncErrors.WithLabelValues(registry, repo).Inc()
+	})
+}
+
+func SetSyncImagesPending(ms MetricServer, registry, repo string, pending int) {
+	ms.SendMetric(func() {
+		syncImagesPending.WithLabelValues(registry, repo).Set(float64(pending))
+	})
+}
+
+func SetSyncTransferredBytes(ms MetricServer, registry, repo string, transferred int64) {
+	ms.SendMetric(func() {
+		syncTransferredBytes.WithLabelValues(registry, repo).Set(float64(transferred))
+	})
+}
+
+func SetSyncLastSuccess(ms MetricServer, registry, repo string, lastSuccess time.Time) {
+	ms.SendMetric(func() {
+		syncLastSuccess.WithLabelValues(registry, repo).Set(float64(lastSuccess.Unix()))
+	})
+}
+
+func SetSyncRateLimitRemaining(ms MetricServer, registry string, remaining int64) {
+	ms.SendMetric(func() {
+		syncRateLimitRemaining.WithLabelValues(registry).Set(float64(remaining))
+	})
+}
+
+func SetRepoQuotaUsage(ms MetricServer, repo string, ratio float64) {
+	ms.SendMetric(func() {
+		repoQuotaUsageRatio.WithLabelValues(repo).Set(ratio)
+	})
+}
+
+func SetStorageFreeBytes(ms MetricServer, storageName string, free int64) {
+	ms.SendMetric(func() {
+		storageFreeBytes.WithLabelValues(storageName).Set(float64(free))
+	})
+}
+
+func IncMetaDBDrift(ms MetricServer, kind string) {
+	ms.SendMetric(func() {
+		metaDBDrift.WithLabelValues(kind).Inc()
+	})
+}
+
+func ObserveMetaDBLatency(ms MetricServer, operation string, latency time.Duration) {
+	ms.SendMetric(func() {
+		metaDBLatency.WithLabelValues(operation).Observe(latency.Seconds())
+	})
+}
+
+func IncMetaDBErrors(ms MetricServer, operation string) {
+	ms.SendMetric(func() {
+		metaDBErrors.WithLabelValues(operation).Inc()
+	})
+}
+
+func IncGCBlobsReaped(ms MetricServer, repo string, count int) {
+	ms.SendMetric(func() {
+		gcBlobsReaped.WithLabelValues(getRepoLabel(ms, repo)).Add(float64(count))
+	})
+}
+
+func IncGCManifestsReaped(ms MetricServer, repo string, count int) {
+	ms.SendMetric(func() {
+		gcManifestsReaped.WithLabelValues(getRepoLabel(ms, repo)).Add(float64(count))
+	})
+}
+
+func IncGCReclaimedBytes(ms MetricServer, repo string, size int64) {
+	ms.SendMetric(func() {
+		gcReclaimedBytes.WithLabelValues(getRepoLabel(ms, repo)).Add(float64(size))
+	})
+}
+
+func ObserveGCDuration(ms MetricServer, repo string, duration time.Duration) {
+	ms.SendMetric(func() {
+		gcDuration.WithLabelValues(getRepoLabel(ms, repo)).Observe(duration.Seconds())
+	})
+}
+
+func SetGCLastSuccess(ms MetricServer, repo string, lastSuccess time.Time) {
+	ms.SendMetric(func() {
+		gcLastSuccess.WithLabelValues(getRepoLabel(ms, repo)).Set(float64(lastSuccess.Unix()))
+	})
+}
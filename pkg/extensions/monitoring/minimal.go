@@ -18,17 +18,34 @@ import (
 const (
 	metricsNamespace = "zot"
 	// Counters.
-	httpConnRequests = metricsNamespace + ".http.requests"
-	repoDownloads    = metricsNamespace + ".repo.downloads"
-	repoUploads      = metricsNamespace + ".repo.uploads"
+	httpConnRequests  = metricsNamespace + ".http.requests"
+	repoDownloads     = metricsNamespace + ".repo.downloads"
+	repoUploads       = metricsNamespace + ".repo.uploads"
+	repoServedBytes   = metricsNamespace + ".repo.served.bytes"
+	repoErrors        = metricsNamespace + ".repo.errors"
+	syncErrors        = metricsNamespace + ".sync.errors"
+	metaDBDrift       = metricsNamespace + ".metadb.drift"
+	metaDBErrors      = metricsNamespace + ".metadb.operation.errors"
+	gcBlobsReaped     = metricsNamespace + ".gc.blobs.reaped"
+	gcManifestsReaped = metricsNamespace + ".gc.manifests.reaped"
+	gcReclaimedBytes  = metricsNamespace + ".gc.reclaimed.bytes"
 	// Gauge.
-	repoStorageBytes = metricsNamespace + ".repo.storage.bytes"
-	serverInfo       = metricsNamespace + ".info"
+	repoStorageBytes      = metricsNamespace + ".repo.storage.bytes"
+	serverInfo            = metricsNamespace + ".info"
+	syncImagesPending     = metricsNamespace + ".sync.images.pending"
+	syncTransferredBytes  = metricsNamespace + ".sync.transferred.bytes"
+	syncLastSuccessSecond = metricsNamespace + ".sync.last.success.timestamp.seconds"
+	syncRateLimitRemain   = metricsNamespace + ".sync.ratelimit.remaining"
+	repoQuotaUsageRatio   = metricsNamespace + ".repo.quota.usage.ratio"
+	storageFreeBytes      = metricsNamespace + ".storage.free.bytes"
+	gcLastSuccessSecond   = metricsNamespace + ".gc.last.success.timestamp.seconds"
 	// Summary.
 	httpRepoLatencySeconds = metricsNamespace + ".http.repo.latency.seconds"
 	// Histogram.
 	httpMethodLatencySeconds  = metricsNamespace + ".http.method.latency.seconds"
 	storageLockLatencySeconds = metricsNamespace + ".storage.lock.latency.seconds"
+	metaDBLatencySeconds      = metricsNamespace + ".metadb.operation.latency.seconds"
+	gcDurationSeconds         = metricsNamespace + ".gc.duration.seconds"
 
 	metricsScrapeTimeout       = 2 * time.Minute
 	metricsScrapeCheckInterval = 30 * time.Second
@@ -41,6 +58,7 @@ type metricServer struct {
 	cache      *MetricsInfo
 	cacheChan  chan *MetricsInfo
 	bucketsF2S map[float64]string // float64 to string conversion of buckets label
+	repoLabels *RepoLabels
 	log        log.Logger
 	lock       *sync.RWMutex
 }
@@ -53,7 +71,7 @@ type MetricsInfo struct {
 }
 
 // CounterValue stores info about a metric that is incremented over time,
-// such as the number of requests to an HTTP endpoint.
+// such as the number of requests to an HTTP endpoint, the count sent is the increment (1 if not set).
 type CounterValue struct {
 	Name        string
 	Count       int
@@ -97,6 +115,10 @@ func GetStorageLatencyBuckets() []float64 {
 	return []float64{.001, .01, 0.1, 1, 5, 10, 15, 30, 60, math.MaxFloat64}
 }
 
+func GetMetaDBLatencyBuckets() []float64 {
+	return []float64{.0005, .001, .005, .01, .05, .1, .5, 1, 5, math.MaxFloat64}
+}
+
 // implements the MetricServer interface.
 func (ms *metricServer) SendMetric(metric interface{}) {
 	ms.lock.RLock()
@@ -188,7 +210,10 @@ func NewMetricsServer(enabled bool, log log.Logger) MetricServer {
 	// convert to a map for returning easily the string corresponding to a bucket
 	bucketsFloat2String := map[float64]string{}
 
-	for _, fvalue := range append(GetDefaultBuckets(), GetStorageLatencyBuckets()...) {
+	allBuckets := append(GetDefaultBuckets(), GetStorageLatencyBuckets()...)
+	allBuckets = append(allBuckets, GetMetaDBLatencyBuckets()...)
+
+	for _, fvalue := range allBuckets {
 		if fvalue == math.MaxFloat64 {
 			bucketsFloat2String[fvalue] = "+Inf"
 		} else {
@@ -215,16 +240,31 @@ func NewMetricsServer(enabled bool, log log.Logger) MetricServer {
 // contains a map with key=CounterName and value=CounterLabels.
 func GetCounters() map[string][]string {
 	return map[string][]string{
-		httpConnRequests: {"method", "code"},
-		repoDownloads:    {"repo"},
-		repoUploads:      {"repo"},
+		httpConnRequests:  {"method", "code"},
+		repoDownloads:     {"repo"},
+		repoUploads:       {"repo"},
+		repoServedBytes:   {"repo"},
+		repoErrors:        {"repo"},
+		syncErrors:        {"registry", "repo"},
+		metaDBDrift:       {"kind"},
+		metaDBErrors:      {"operation"},
+		gcBlobsReaped:     {"repo"},
+		gcManifestsReaped: {"repo"},
+		gcReclaimedBytes:  {"repo"},
 	}
 }
 
 func GetGauges() map[string][]string {
 	return map[string][]string{
-		repoStorageBytes: {"repo"},
-		serverInfo:       {"commit", "binaryType", "goVersion", "version"},
+		repoStorageBytes:      {"repo"},
+		serverInfo:            {"commit", "binaryType", "goVersion", "version"},
+		syncImagesPending:     {"registry", "repo"},
+		syncTransferredBytes:  {"registry", "repo"},
+		syncLastSuccessSecond: {"registry", "repo"},
+		syncRateLimitRemain:   {"registry"},
+		repoQuotaUsageRatio:   {"repo"},
+		storageFreeBytes:      {"storageName"},
+		gcLastSuccessSecond:   {"repo"},
 	}
 }
 
@@ -238,6 +278,8 @@ func GetHistograms() map[string][]string {
 	return map[string][]string{
 		httpMethodLatencySeconds:  {"method"},
 		storageLockLatencySeconds: {"storageName", "lockType"},
+		metaDBLatencySeconds:      {"operation"},
+		gcDurationSeconds:         {"repo"},
 	}
 }
 
@@ -319,13 +361,18 @@ func (ms *metricServer) CounterInc(cv *CounterValue) {
 		return
 	}
 
+	increment := cv.Count
+	if increment == 0 {
+		increment = 1
+	}
+
 	index, ok := findCounterValueIndex(ms.cache.Counters, cv.Name, cv.LabelValues)
 	if !ok {
 		// cv not found in cache: add it
-		cv.Count = 1
+		cv.Count = increment
 		ms.cache.Counters = append(ms.cache.Counters, cv)
 	} else {
-		ms.cache.Counters[index].Count++
+		ms.cache.Counters[index].Count += increment
 	}
 }
 
@@ -441,7 +488,7 @@ func ObserveHTTPRepoLatency(ms MetricServer, path string, latency time.Duration)
 	match := re.FindStringSubmatch(path)
 
 	if len(match) > 1 {
-		lvs = []string{match[1]}
+		lvs = []string{getRepoLabel(ms, match[1])}
 	} else {
 		lvs = []string{"N/A"}
 	}
@@ -455,6 +502,33 @@ func ObserveHTTPRepoLatency(ms MetricServer, path string, latency time.Duration)
 	ms.SendMetric(sv)
 }
 
+func ObserveHTTPRepoTraffic(ms MetricServer, method, path string, statusCode, bodySize int) {
+	repo, servedBytes, isError, ok := getRepoTraffic(method, path, statusCode, bodySize)
+	if !ok {
+		return
+	}
+
+	countRepoRequest(ms, repo)
+	label := getRepoLabel(ms, repo)
+
+	if servedBytes > 0 {
+		ms.SendMetric(CounterValue{
+			Name:        repoServedBytes,
+			Count:       servedBytes,
+			LabelNames:  []string{"repo"},
+			LabelValues: []string{label},
+		})
+	}
+
+	if isError {
+		ms.SendMetric(CounterValue{
+			Name:        repoErrors,
+			LabelNames:  []string{"repo"},
+			LabelValues: []string{label},
+		})
+	}
+}
+
 func ObserveHTTPMethodLatency(ms MetricServer, method string, latency time.Duration) {
 	h := HistogramValue{
 		Name:        httpMethodLatencySeconds,
@@ -469,7 +543,7 @@ func IncDownloadCounter(ms MetricServer, repo string) {
 	dCounter := CounterValue{
 		Name:        repoDownloads,
 		LabelNames:  []string{"repo"},
-		LabelValues: []string{repo},
+		LabelValues: []string{getRepoLabel(ms, repo)},
 	}
 	ms.SendMetric(dCounter)
 }
@@ -478,7 +552,7 @@ func IncUploadCounter(ms MetricServer, repo string) {
 	uCounter := CounterValue{
 		Name:        repoUploads,
 		LabelNames:  []string{"repo"},
-		LabelValues: []string{repo},
+		LabelValues: []string{getRepoLabel(ms, repo)},
 	}
 	ms.SendMetric(uCounter)
 }
@@ -521,6 +595,153 @@ func ObserveStorageLockLatency(ms MetricServer, latency time.Duration, storageNa
 	ms.SendMetric(h)
 }
 
+func IncSyncErrors(ms MetricServer, registry, repo string) {
+	errCounter := CounterValue{
+		Name:        syncErrors,
+		LabelNames:  []string{"registry", "repo"},
+		LabelValues: []string{registry, repo},
+	}
+	ms.SendMetric(errCounter)
+}
+
+func SetSyncImagesPending(ms MetricServer, registry, repo string, pending int) {
+	gauge := GaugeValue{
+		Name:        syncImagesPending,
+		Value:       float64(pending),
+		LabelNames:  []string{"registry", "repo"},
+		LabelValues: []string{registry, repo},
+	}
+	ms.SendMetric(gauge)
+}
+
+func SetSyncTransferredBytes(ms MetricServer, registry, repo string, transferred int64) {
+	gauge := GaugeValue{
+		Name:        syncTransferredBytes,
+		Value:       float64(transferred),
+		LabelNames:  []string{"registry", "repo"},
+		LabelValues: []string{registry, repo},
+	}
+	ms.SendMetric(gauge)
+}
+
+func SetSyncLastSuccess(ms MetricServer, registry, repo string, lastSuccess time.Time) {
+	gauge := GaugeValue{
+		Name:        syncLastSuccessSecond,
+		Value:       float64(lastSuccess.Unix()),
+		LabelNames:  []string{"registry", "repo"},
+		LabelValues: []string{registry, repo},
+	}
+	ms.SendMetric(gauge)
+}
+
+func SetSyncRateLimitRemaining(ms MetricServer, registry string, remaining int64) {
+	gauge := GaugeValue{
+		Name:        syncRateLimitRemain,
+		Value:       float64(remaining),
+		LabelNames:  []string{"registry"},
+		LabelValues: []string{registry},
+	}
+	ms.SendMetric(gauge)
+}
+
+func SetRepoQuotaUsage(ms MetricServer, repo string, ratio float64) {
+	gauge := GaugeValue{
+		Name:        repoQuotaUsageRatio,
+		Value:       ratio,
+		LabelNames:  []string{"repo"},
+		LabelValues: []string{repo},
+	}
+	ms.SendMetric(gauge)
+}
+
+func SetStorageFreeBytes(ms MetricServer, storageName string, free int64) {
+	gauge := GaugeValue{
+		Name:        storageFreeBytes,
+		Value:       float64(free),
+		LabelNames:  []string{"storageName"},
+		LabelValues: []string{storageName},
+	}
+	ms.SendMetric(gauge)
+}
+
+func IncMetaDBDrift(ms MetricServer, kind string) {
+	driftCounter := CounterValue{
+		Name:        metaDBDrift,
+		LabelNames:  []string{"kind"},
+		LabelValues: []string{kind},
+	}
+	ms.SendMetric(driftCounter)
+}
+
+func ObserveMetaDBLatency(ms MetricServer, operation string, latency time.Duration) {
+	h := HistogramValue{
+		Name:        metaDBLatencySeconds,
+		Sum:         latency.Seconds(), // convenient temporary store for Histogram latency value
+		LabelNames:  []string{"operation"},
+		LabelValues: []string{operation},
+	}
+	ms.SendMetric(h)
+}
+
+func IncMetaDBErrors(ms MetricServer, operation string) {
+	errCounter := CounterValue{
+		Name:        metaDBErrors,
+		LabelNames:  []string{"operation"},
+		LabelValues: []string{operation},
+	}
+	ms.SendMetric(errCounter)
+}
+
+func IncGCBlobsReaped(ms MetricServer, repo string, count int) {
+	reaped := CounterValue{
+		Name:        gcBlobsReaped,
+		Count:       count,
+		LabelNames:  []string{"repo"},
+		LabelValues: []string{getRepoLabel(ms, repo)},
+	}
+	ms.SendMetric(reaped)
+}
+
+func IncGCManifestsReaped(ms MetricServer, repo string, count int) {
+	reaped := CounterValue{
+		Name:        gcManifestsReaped,
+		Count:       count,
+		LabelNames:  []string{"repo"},
+		LabelValues: []string{getRepoLabel(ms, repo)},
+	}
+	ms.SendMetric(reaped)
+}
+
+func IncGCReclaimedBytes(ms MetricServer, repo string, size int64) {
+	reclaimed := CounterValue{
+		Name:        gcReclaimedBytes,
+		Count:       int(size),
+		LabelNames:  []string{"repo"},
+		LabelValues: []string{getRepoLabel(ms, repo)},
+	}
+	ms.SendMetric(reclaimed)
+}
+
+func ObserveGCDuration(ms MetricServer, repo string, duration time.Duration) {
+	h := HistogramValue{
+		Name:        gcDurationSeconds,
+		Sum:         duration.Seconds(), // convenient temporary store for Histogram latency value
+		LabelNames:  []string{"repo"},
+		LabelValues: []string{getRepoLabel(ms, repo)},
+	}
+	ms.SendMetric(h)
+}
+
+func SetGCLastSuccess(ms MetricServer, repo string, lastSuccess time.Time) {
+	gauge := GaugeValue{
+		Name:        gcLastSuccessSecond,
+		Value:       float64(lastSuccess.Unix()),
+		LabelNames:  []string{"repo"},
+		LabelValues: []string{getRepoLabel(ms, repo)},
+	}
+	ms.SendMetric(gauge)
+}
+
 func GetMaxIdleScrapeInterval() time.Duration {
 	return metricsScrapeTimeout + metricsScrapeCheckInterval
 }
@@ -529,6 +750,8 @@ func GetBuckets(metricName string) []float64 {
 	switch metricName {
 	case storageLockLatencySeconds:
 		return GetStorageLatencyBuckets()
+	case metaDBLatencySeconds:
+		return GetMetaDBLatencyBuckets()
 	default:
 		return GetDefaultBuckets()
 	}
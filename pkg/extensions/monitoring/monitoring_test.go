@@ -4,7 +4,12 @@
 package monitoring_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -62,6 +67,8 @@ func TestExtensionMetrics(t *testing.T) {
 		monitoring.SetStorageUsage(ctlr.Metrics, rootDir, "alpine")
 
 		monitoring.ObserveStorageLockLatency(ctlr.Metrics, time.Millisecond, rootDir, "RWLock")
+		monitoring.ObserveMetaDBLatency(ctlr.Metrics, "GetRepoMeta", time.Millisecond)
+		monitoring.IncMetaDBErrors(ctlr.Metrics, "GetRepoMeta")
 
 		resp, err := resty.R().Get(baseURL + "/metrics")
 		So(err, ShouldBeNil)
@@ -75,6 +82,8 @@ func TestExtensionMetrics(t *testing.T) {
 		So(respStr, ShouldContainSubstring, "zot_storage_lock_latency_seconds_bucket")
 		So(respStr, ShouldContainSubstring, "zot_storage_lock_latency_seconds_sum")
 		So(respStr, ShouldContainSubstring, "zot_storage_lock_latency_seconds_bucket")
+		So(respStr, ShouldContainSubstring, "zot_metadb_operation_latency_seconds_bucket{operation=\"GetRepoMeta\"")
+		So(respStr, ShouldContainSubstring, "zot_metadb_operation_errors_total{operation=\"GetRepoMeta\"} 1")
 	})
 	Convey("Make a new controller with disabled metrics extension", t, func() {
 		port := test.GetFreePort()
@@ -102,3 +111,227 @@ func TestExtensionMetrics(t *testing.T) {
 		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
 	})
 }
+
+func TestGCMetrics(t *testing.T) {
+	Convey("The garbage collection of the repos is measured", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.GCDelay = time.Millisecond
+		conf.Storage.UntaggedImageRetentionDelay = time.Millisecond
+		conf.Extensions = &extconf.ExtensionConfig{}
+		enabled := true
+		conf.Extensions.Metrics = &extconf.MetricsConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &enabled},
+			Prometheus: &extconf.PrometheusConfig{Path: "/metrics"},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		imgStore := ctlr.StoreController.DefaultStore
+
+		err := test.WriteImageToFileSystem(CreateRandomImage(), "gc-repo", "tagged", ctlr.StoreController)
+		So(err, ShouldBeNil)
+
+		untagged := CreateRandomImage()
+		err = test.WriteImageToFileSystem(untagged, "gc-repo", untagged.DigestStr(), ctlr.StoreController)
+		So(err, ShouldBeNil)
+
+		time.Sleep(10 * time.Millisecond)
+
+		// a dry run isn't counted
+		_, err = imgStore.RunGCRepoReport("gc-repo", true)
+		So(err, ShouldBeNil)
+
+		report, err := imgStore.RunGCRepoReport("gc-repo", false)
+		So(err, ShouldBeNil)
+		So(report.Manifests, ShouldHaveLength, 1)
+		So(report.Blobs, ShouldNotBeEmpty)
+
+		resp, err := resty.R().Get(baseURL + "/metrics")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		respStr := string(resp.Body())
+		So(respStr, ShouldContainSubstring, "zot_gc_manifests_reaped_total{repo=\"gc-repo\"} 1\n")
+		So(respStr, ShouldContainSubstring,
+			fmt.Sprintf("zot_gc_blobs_reaped_total{repo=\"gc-repo\"} %d\n", len(report.Blobs)))
+		So(respStr, ShouldContainSubstring,
+			fmt.Sprintf("zot_gc_reclaimed_bytes_total{repo=\"gc-repo\"} %d\n", report.BytesFreed))
+		So(respStr, ShouldContainSubstring, "zot_gc_duration_seconds_count{repo=\"gc-repo\"} 1\n")
+		So(respStr, ShouldContainSubstring, "zot_gc_last_success_timestamp_seconds{repo=\"gc-repo\"}")
+	})
+}
+
+func TestRepoTrafficMetrics(t *testing.T) {
+	Convey("Make a new controller with limited repo labels", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{}
+		enabled := true
+		conf.Extensions.Metrics = &extconf.MetricsConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &enabled},
+			Prometheus: &extconf.PrometheusConfig{Path: "/metrics"},
+			RepoLabels: &extconf.RepoLabelsConfig{Max: 1},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		monitoring.ObserveHTTPRepoTraffic(ctlr.Metrics, http.MethodGet, "/v2/top-repo/blobs/sha256:1234", http.StatusOK, 100)
+		monitoring.ObserveHTTPRepoTraffic(ctlr.Metrics, http.MethodGet, "/v2/top-repo/manifests/latest", http.StatusOK, 10)
+		monitoring.ObserveHTTPRepoTraffic(ctlr.Metrics, http.MethodHead, "/v2/top-repo/manifests/latest", http.StatusOK, 0)
+		monitoring.ObserveHTTPRepoTraffic(ctlr.Metrics, http.MethodGet, "/v2/failing-repo/manifests/latest",
+			http.StatusNotFound, 10)
+		monitoring.ObserveHTTPRepoTraffic(ctlr.Metrics, http.MethodGet, "/v2/other-repo/blobs/sha256:1234", http.StatusOK, 50)
+		monitoring.ObserveHTTPRepoTraffic(ctlr.Metrics, http.MethodGet, "/v2/_catalog", http.StatusOK, 50)
+		monitoring.IncDownloadCounter(ctlr.Metrics, "top-repo")
+		monitoring.IncDownloadCounter(ctlr.Metrics, "other-repo")
+
+		resp, err := resty.R().Get(baseURL + "/metrics")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		respStr := string(resp.Body())
+		So(respStr, ShouldContainSubstring, "zot_repo_served_bytes_total{repo=\"top-repo\"} 110")
+		So(respStr, ShouldContainSubstring, "zot_repo_served_bytes_total{repo=\"other\"} 50")
+		So(respStr, ShouldContainSubstring, "zot_repo_errors_total{repo=\"other\"} 1")
+		So(respStr, ShouldContainSubstring, "zot_repo_downloads_total{repo=\"top-repo\"} 1")
+		So(respStr, ShouldContainSubstring, "zot_repo_downloads_total{repo=\"other\"} 1")
+		So(respStr, ShouldNotContainSubstring, "repo=\"failing-repo\"")
+		So(respStr, ShouldNotContainSubstring, "repo=\"other-repo\"")
+	})
+}
+
+func TestOTLPExporter(t *testing.T) {
+	Convey("Export the metrics to an OTLP collector", t, func() {
+		requests := make(chan []byte, 10)
+
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			body, _ := io.ReadAll(r.Body)
+
+			select {
+			case requests <- body:
+			default:
+			}
+		}))
+		defer collector.Close()
+
+		port := test.GetFreePort()
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{}
+		enabled := true
+		conf.Extensions.Metrics = &extconf.MetricsConfig{
+			BaseConfig: extconf.BaseConfig{Enable: &enabled},
+			Prometheus: &extconf.PrometheusConfig{Path: "/metrics"},
+			OTLP: &extconf.OTLPConfig{
+				Endpoint:           collector.URL + "/v1/metrics",
+				Interval:           100 * time.Millisecond,
+				Headers:            map[string]string{"Authorization": "Bearer token"},
+				ResourceAttributes: map[string]string{"deployment.environment": "test"},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		monitoring.IncDownloadCounter(ctlr.Metrics, "otlp-repo")
+
+		// the first exports may have happened before the download
+		var body []byte
+
+		for body == nil {
+			select {
+			case body = <-requests:
+				if !strings.Contains(string(body), "otlp-repo") {
+					body = nil
+				}
+			case <-time.After(10 * time.Second):
+				body = []byte{}
+			}
+		}
+
+		So(body, ShouldNotBeEmpty)
+
+		var request struct {
+			ResourceMetrics []struct {
+				Resource struct {
+					Attributes []struct {
+						Key   string
+						Value struct{ StringValue string }
+					}
+				}
+				ScopeMetrics []struct {
+					Metrics []struct {
+						Name string
+						Sum  *struct {
+							IsMonotonic bool
+							DataPoints  []struct {
+								Attributes []struct {
+									Key   string
+									Value struct{ StringValue string }
+								}
+								AsDouble float64
+							}
+						}
+					}
+				}
+			}
+		}
+
+		err := json.Unmarshal(body, &request)
+		So(err, ShouldBeNil)
+		So(request.ResourceMetrics, ShouldHaveLength, 1)
+
+		attributes := map[string]string{}
+		for _, attribute := range request.ResourceMetrics[0].Resource.Attributes {
+			attributes[attribute.Key] = attribute.Value.StringValue
+		}
+
+		So(attributes["service.name"], ShouldEqual, "zot")
+		So(attributes["deployment.environment"], ShouldEqual, "test")
+		So(attributes["zot.storage.root_directory"], ShouldEqual, conf.Storage.RootDirectory)
+
+		found := false
+
+		for _, metric := range request.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+			if metric.Name != "zot_repo_downloads_total" {
+				continue
+			}
+
+			So(metric.Sum, ShouldNotBeNil)
+			So(metric.Sum.IsMonotonic, ShouldBeTrue)
+
+			for _, dataPoint := range metric.Sum.DataPoints {
+				if dataPoint.Attributes[0].Value.StringValue == "otlp-repo" {
+					found = true
+
+					So(dataPoint.AsDouble, ShouldEqual, 1)
+				}
+			}
+		}
+
+		So(found, ShouldBeTrue)
+	})
+}
@@ -0,0 +1,350 @@
+//go:build metrics
+// +build metrics
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	otlpScopeName = "zotregistry.io/zot"
+	// AGGREGATION_TEMPORALITY_CUMULATIVE, the prometheus counters are never reset.
+	otlpCumulativeTemporality = 2
+)
+
+/*
+OTLPExporter periodically pushes the metrics of the prometheus registry to an OpenTelemetry collector,
+encoded as OTLP/HTTP JSON, the counters are exported as cumulative sums, the gauges as gauges and
+the histograms and summaries as such.
+*/
+type OTLPExporter struct {
+	endpoint   string
+	headers    map[string]string
+	resource   otlpResource
+	gatherer   prometheus.Gatherer
+	client     *http.Client
+	startNanos string
+	log        log.Logger
+}
+
+// NewOTLPExporter returns an exporter of the metrics, with the resource attributes identifying the zot instance.
+func NewOTLPExporter(config *extconf.OTLPConfig, resourceAttributes map[string]string, log log.Logger,
+) *OTLPExporter {
+	attributes := map[string]string{}
+
+	for name, value := range resourceAttributes {
+		attributes[name] = value
+	}
+
+	for name, value := range config.ResourceAttributes {
+		attributes[name] = value
+	}
+
+	return &OTLPExporter{
+		endpoint:   config.Endpoint,
+		headers:    config.Headers,
+		resource:   otlpResource{Attributes: getOTLPAttributes(attributes)},
+		gatherer:   prometheus.DefaultGatherer,
+		client:     &http.Client{Timeout: time.Minute},
+		startNanos: getOTLPTime(time.Now()),
+		log:        log,
+	}
+}
+
+// Run exports the metrics every interval until the context is done.
+func (exporter *OTLPExporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			exporter.client.CloseIdleConnections()
+
+			return
+		case <-ticker.C:
+			if err := exporter.Export(ctx); err != nil && ctx.Err() == nil {
+				exporter.log.Error().Err(err).Str("endpoint", exporter.endpoint).Msg("metrics: failed to export to OTLP collector")
+			}
+		}
+	}
+}
+
+// Export pushes the current value of the metrics.
+func (exporter *OTLPExporter) Export(ctx context.Context) error {
+	families, err := exporter.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(exporter.getRequest(families, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exporter.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range exporter.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := exporter.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned status code %d", zerr.ErrOTLPExportFailed, exporter.endpoint, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (exporter *OTLPExporter) getRequest(families []*dto.MetricFamily, now time.Time) otlpRequest {
+	metrics := []otlpMetric{}
+	nowNanos := getOTLPTime(now)
+
+	for _, family := range families {
+		metric := otlpMetric{Name: family.GetName(), Description: family.GetHelp()}
+
+		switch family.GetType() { //nolint: exhaustive // untyped metrics are not used
+		case dto.MetricType_COUNTER:
+			metric.Sum = &otlpSum{AggregationTemporality: otlpCumulativeTemporality, IsMonotonic: true}
+
+			for _, promMetric := range family.GetMetric() {
+				metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpNumberDataPoint{
+					Attributes:        getOTLPLabels(promMetric.GetLabel()),
+					StartTimeUnixNano: exporter.startNanos,
+					TimeUnixNano:      nowNanos,
+					AsDouble:          promMetric.GetCounter().GetValue(),
+				})
+			}
+		case dto.MetricType_GAUGE:
+			metric.Gauge = &otlpGauge{}
+
+			for _, promMetric := range family.GetMetric() {
+				metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpNumberDataPoint{
+					Attributes:   getOTLPLabels(promMetric.GetLabel()),
+					TimeUnixNano: nowNanos,
+					AsDouble:     promMetric.GetGauge().GetValue(),
+				})
+			}
+		case dto.MetricType_HISTOGRAM:
+			metric.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulativeTemporality}
+
+			for _, promMetric := range family.GetMetric() {
+				histogram := promMetric.GetHistogram()
+				dataPoint := otlpHistogramDataPoint{
+					Attributes:        getOTLPLabels(promMetric.GetLabel()),
+					StartTimeUnixNano: exporter.startNanos,
+					TimeUnixNano:      nowNanos,
+					Count:             strconv.FormatUint(histogram.GetSampleCount(), 10),
+					Sum:               histogram.GetSampleSum(),
+					BucketCounts:      []string{},
+					ExplicitBounds:    []float64{},
+				}
+
+				// the prometheus buckets are cumulative, the OTLP ones aren't and end with the +Inf bucket
+				var previousCount uint64
+
+				for _, bucket := range histogram.GetBucket() {
+					dataPoint.ExplicitBounds = append(dataPoint.ExplicitBounds, bucket.GetUpperBound())
+					dataPoint.BucketCounts = append(dataPoint.BucketCounts,
+						strconv.FormatUint(bucket.GetCumulativeCount()-previousCount, 10))
+					previousCount = bucket.GetCumulativeCount()
+				}
+
+				dataPoint.BucketCounts = append(dataPoint.BucketCounts,
+					strconv.FormatUint(histogram.GetSampleCount()-previousCount, 10))
+
+				metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, dataPoint)
+			}
+		case dto.MetricType_SUMMARY:
+			metric.Summary = &otlpSummary{}
+
+			for _, promMetric := range family.GetMetric() {
+				summary := promMetric.GetSummary()
+				dataPoint := otlpSummaryDataPoint{
+					Attributes:        getOTLPLabels(promMetric.GetLabel()),
+					StartTimeUnixNano: exporter.startNanos,
+					TimeUnixNano:      nowNanos,
+					Count:             strconv.FormatUint(summary.GetSampleCount(), 10),
+					Sum:               summary.GetSampleSum(),
+					QuantileValues:    []otlpQuantileValue{},
+				}
+
+				for _, quantile := range summary.GetQuantile() {
+					dataPoint.QuantileValues = append(dataPoint.QuantileValues, otlpQuantileValue{
+						Quantile: quantile.GetQuantile(),
+						Value:    quantile.GetValue(),
+					})
+				}
+
+				metric.Summary.DataPoints = append(metric.Summary.DataPoints, dataPoint)
+			}
+		default:
+			continue
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	return otlpRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource: exporter.resource,
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:   otlpScope{Name: otlpScopeName},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
+
+func getOTLPLabels(labels []*dto.LabelPair) []otlpAttribute {
+	attributes := []otlpAttribute{}
+
+	for _, label := range labels {
+		attributes = append(attributes, otlpAttribute{
+			Key:   label.GetName(),
+			Value: otlpValue{StringValue: label.GetValue()},
+		})
+	}
+
+	return attributes
+}
+
+func getOTLPAttributes(attributes map[string]string) []otlpAttribute {
+	names := make([]string, 0, len(attributes))
+
+	for name := range attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	otlpAttributes := []otlpAttribute{}
+
+	for _, name := range names {
+		otlpAttributes = append(otlpAttributes, otlpAttribute{Key: name, Value: otlpValue{StringValue: attributes[name]}})
+	}
+
+	return otlpAttributes
+}
+
+// the 64 bits integers are encoded as strings in the JSON encoding of OTLP.
+func getOTLPTime(instant time.Time) string {
+	return strconv.FormatInt(instant.UnixNano(), 10)
+}
+
+// the JSON encoding of the OTLP ExportMetricsServiceRequest.
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+	Summary     *otlpSummary   `json:"summary,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsDouble          float64         `json:"asDouble"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                      `json:"aggregationTemporality"`
+}
+
+type otlpHistogramDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+type otlpSummary struct {
+	DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+}
+
+type otlpSummaryDataPoint struct {
+	Attributes        []otlpAttribute     `json:"attributes"`
+	StartTimeUnixNano string              `json:"startTimeUnixNano"`
+	TimeUnixNano      string              `json:"timeUnixNano"`
+	Count             string              `json:"count"`
+	Sum               float64             `json:"sum"`
+	QuantileValues    []otlpQuantileValue `json:"quantileValues"`
+}
+
+type otlpQuantileValue struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
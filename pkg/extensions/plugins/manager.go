@@ -0,0 +1,336 @@
+//go:build plugins
+// +build plugins
+
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	zcommon "zotregistry.io/zot/pkg/common"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+	zplugins "zotregistry.io/zot/pkg/plugins"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+const (
+	DefaultTimeout = time.Minute
+	// the bodies of the requests are passed to the plugins in a single message.
+	maxRequestBodySize = 32 << 20
+)
+
+// Manager runs the configured plugins and calls them for their routes, hooks and tasks.
+type Manager struct {
+	plugins []*plugin
+	hooks   sync.WaitGroup
+	log     log.Logger
+}
+
+type plugin struct {
+	client       *zplugins.Client
+	registration *zplugins.Registration
+	timeout      time.Duration
+}
+
+// NewManager starts the plugins, those which fail to start or to register are logged and skipped.
+func NewManager(config *extconf.PluginsConfig, log log.Logger) *Manager {
+	manager := &Manager{log: log}
+
+	for _, pluginConfig := range config.Plugins {
+		timeout := pluginConfig.Timeout
+		if timeout <= 0 {
+			timeout = DefaultTimeout
+		}
+
+		client, err := zplugins.NewClient(pluginConfig, log)
+		if err != nil {
+			log.Error().Err(err).Str("plugin", pluginConfig.Name).Msg("plugins: unable to start plugin, skipping it")
+
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		registration, err := client.Register(ctx)
+
+		cancel()
+
+		if err == nil {
+			err = validateRegistration(registration)
+		}
+
+		if err != nil {
+			log.Error().Err(err).Str("plugin", pluginConfig.Name).Msg("plugins: unable to register plugin, skipping it")
+
+			client.Close()
+
+			continue
+		}
+
+		log.Info().Str("plugin", pluginConfig.Name).Int("routes", len(registration.Routes)).
+			Strs("hooks", registration.Hooks).Int("tasks", len(registration.Tasks)).Msg("plugins: plugin registered")
+
+		manager.plugins = append(manager.plugins, &plugin{
+			client:       client,
+			registration: registration,
+			timeout:      timeout,
+		})
+	}
+
+	return manager
+}
+
+func validateRegistration(registration *zplugins.Registration) error {
+	for _, route := range registration.Routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("%w: route path %q must start with /", zerr.ErrPluginInvalidRegistration, route.Path)
+		}
+	}
+
+	for _, hook := range registration.Hooks {
+		if hook != zplugins.PushHook && hook != zplugins.DeleteHook {
+			return fmt.Errorf("%w: unknown hook %q, expected push or delete", zerr.ErrPluginInvalidRegistration, hook)
+		}
+	}
+
+	for _, task := range registration.Tasks {
+		interval, err := time.ParseDuration(task.Interval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("%w: invalid interval %q of task %s", zerr.ErrPluginInvalidRegistration,
+				task.Interval, task.Name)
+		}
+	}
+
+	return nil
+}
+
+// SetupRoutes serves the routes of the plugins under /v2/_zot/ext/plugins/<plugin name>.
+func (manager *Manager) SetupRoutes(conf *config.Config, router *mux.Router) {
+	for _, plugin := range manager.plugins {
+		if len(plugin.registration.Routes) == 0 {
+			continue
+		}
+
+		prefix := constants.ExtPlugins + "/" + plugin.client.Name()
+
+		pluginRouter := router.PathPrefix(prefix).Subrouter()
+		pluginRouter.Use(zcommon.CORSHeadersMiddleware(conf.HTTP.AllowOrigin))
+		pluginRouter.Use(zcommon.AddExtensionSecurityHeaders())
+
+		for _, route := range plugin.registration.Routes {
+			var handler http.Handler = manager.handleHTTP(plugin, constants.RoutePrefix+prefix)
+
+			if route.AdminOnly {
+				handler = zcommon.AuthzOnlyAdminsMiddleware(conf)(handler)
+			}
+
+			methods := route.Methods
+			if len(methods) == 0 {
+				methods = []string{http.MethodGet}
+			}
+
+			pluginRouter.Handle(route.Path, handler).Methods(methods...)
+		}
+	}
+}
+
+// handleHTTP passes the requests to the plugin, without the credentials of the user.
+func (manager *Manager) handleHTTP(plugin *plugin, prefix string) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(response, request.Body, maxRequestBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				response.WriteHeader(http.StatusRequestEntityTooLarge)
+
+				return
+			}
+
+			response.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		headers := request.Header.Clone()
+		headers.Del("Authorization")
+		headers.Del("Cookie")
+
+		pluginRequest := &zplugins.HTTPRequest{
+			Method:  request.Method,
+			Path:    strings.TrimPrefix(request.URL.Path, prefix),
+			Query:   request.URL.RawQuery,
+			Headers: headers,
+			Body:    body,
+		}
+
+		if userAc, err := reqCtx.UserAcFromContext(request.Context()); err == nil && userAc != nil {
+			pluginRequest.User = userAc.GetUsername()
+			pluginRequest.Groups = userAc.GetGroups()
+		}
+
+		ctx, cancel := context.WithTimeout(request.Context(), plugin.timeout)
+		defer cancel()
+
+		pluginResponse, err := plugin.client.HandleHTTP(ctx, pluginRequest)
+		if err != nil {
+			manager.log.Error().Err(err).Str("plugin", plugin.client.Name()).Str("path", request.URL.Path).
+				Msg("plugins: plugin failed to handle request")
+
+			response.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		for name, values := range pluginResponse.Headers {
+			for _, value := range values {
+				response.Header().Add(name, value)
+			}
+		}
+
+		status := pluginResponse.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		response.WriteHeader(status)
+		_, _ = response.Write(pluginResponse.Body)
+	})
+}
+
+// ImagePushed calls the push hooks of the plugins, without waiting for them.
+func (manager *Manager) ImagePushed(repo, reference, digest, mediaType string, manifest []byte, user string) {
+	manager.runHooks(zplugins.PushHook, &zplugins.ImageEvent{
+		Repo:      repo,
+		Reference: reference,
+		Digest:    digest,
+		MediaType: mediaType,
+		Manifest:  manifest,
+		User:      user,
+	})
+}
+
+// ImageDeleted calls the delete hooks of the plugins, without waiting for them.
+func (manager *Manager) ImageDeleted(repo, reference, digest, mediaType, user string) {
+	manager.runHooks(zplugins.DeleteHook, &zplugins.ImageEvent{
+		Repo:      repo,
+		Reference: reference,
+		Digest:    digest,
+		MediaType: mediaType,
+		User:      user,
+	})
+}
+
+func (manager *Manager) runHooks(hook string, event *zplugins.ImageEvent) {
+	for _, hookPlugin := range manager.plugins {
+		if !zcommon.Contains(hookPlugin.registration.Hooks, hook) {
+			continue
+		}
+
+		manager.hooks.Add(1)
+
+		go func(plugin *plugin) {
+			defer manager.hooks.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), plugin.timeout)
+			defer cancel()
+
+			var err error
+
+			if hook == zplugins.PushHook {
+				err = plugin.client.OnImagePushed(ctx, event)
+			} else {
+				err = plugin.client.OnImageDeleted(ctx, event)
+			}
+
+			if err != nil {
+				manager.log.Error().Err(err).Str("plugin", plugin.client.Name()).Str("hook", hook).
+					Str("repo", event.Repo).Str("reference", event.Reference).Msg("plugins: plugin hook failed")
+			}
+		}(hookPlugin)
+	}
+}
+
+// EnableTasks submits the tasks of the plugins to the scheduler, each one runs once per interval.
+func (manager *Manager) EnableTasks(taskScheduler *scheduler.Scheduler) {
+	for _, plugin := range manager.plugins {
+		for _, task := range plugin.registration.Tasks {
+			// the intervals are validated on registration
+			interval, _ := time.ParseDuration(task.Interval)
+
+			taskScheduler.SubmitSharedGenerator("plugin/"+plugin.client.Name()+"/"+task.Name,
+				&taskGenerator{plugin: plugin, name: task.Name, log: manager.log}, interval, scheduler.LowPriority)
+		}
+	}
+}
+
+// Close waits for the running hooks and stops the plugins.
+func (manager *Manager) Close() {
+	manager.hooks.Wait()
+
+	for _, plugin := range manager.plugins {
+		plugin.client.Close()
+	}
+}
+
+// taskGenerator generates a single run of a task of a plugin per interval.
+type taskGenerator struct {
+	plugin    *plugin
+	name      string
+	generated bool
+	done      bool
+	log       log.Logger
+}
+
+func (gen *taskGenerator) Next() (scheduler.Task, error) {
+	if gen.generated {
+		gen.done = true
+
+		return nil, nil //nolint: nilnil
+	}
+
+	gen.generated = true
+
+	return &pluginTask{plugin: gen.plugin, name: gen.name, log: gen.log}, nil
+}
+
+func (gen *taskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *taskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *taskGenerator) Reset() {
+	gen.generated = false
+	gen.done = false
+}
+
+type pluginTask struct {
+	plugin *plugin
+	name   string
+	log    log.Logger
+}
+
+func (task *pluginTask) DoWork(ctx context.Context) error {
+	if err := task.plugin.client.RunTask(ctx, &zplugins.TaskRequest{Name: task.name}); err != nil {
+		task.log.Error().Err(err).Str("plugin", task.plugin.client.Name()).Str("task", task.name).
+			Msg("plugins: plugin task failed")
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+//go:build plugins
+// +build plugins
+
+package plugins_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/plugins"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+const (
+	helperEnv       = "ZOT_TEST_PLUGIN"
+	helperOutputEnv = "ZOT_TEST_PLUGIN_OUTPUT"
+)
+
+// recorderPlugin writes a file in the output directory for every hook or task it runs.
+type recorderPlugin struct {
+	plugins.UnimplementedPlugin
+	output string
+}
+
+func (recorderPlugin) Register(ctx context.Context) (*plugins.Registration, error) {
+	return &plugins.Registration{
+		Routes: []plugins.Route{
+			{Path: "/hello"},
+			{Path: "/items/{id}", Methods: []string{http.MethodPost}},
+			{Path: "/admin", AdminOnly: true},
+		},
+		Hooks: []string{plugins.PushHook, plugins.DeleteHook},
+		Tasks: []plugins.Task{{Name: "report", Interval: "1h"}},
+	}, nil
+}
+
+func (recorderPlugin) HandleHTTP(ctx context.Context, request *plugins.HTTPRequest) (*plugins.HTTPResponse, error) {
+	return &plugins.HTTPResponse{
+		Headers: map[string][]string{"Content-Type": {"text/plain"}},
+		Body: []byte(fmt.Sprintf("%s %s %s %s authorization=%t", request.Method, request.Path, request.User,
+			request.Body, request.Headers["Authorization"] != nil)),
+	}, nil
+}
+
+func (plugin recorderPlugin) OnImagePushed(ctx context.Context, event *plugins.ImageEvent) error {
+	return plugin.record(fmt.Sprintf("push-%s-%s", event.Repo, event.Reference))
+}
+
+func (plugin recorderPlugin) OnImageDeleted(ctx context.Context, event *plugins.ImageEvent) error {
+	return plugin.record(fmt.Sprintf("delete-%s-%s", event.Repo, event.Reference))
+}
+
+func (plugin recorderPlugin) RunTask(ctx context.Context, request *plugins.TaskRequest) error {
+	return plugin.record("task-" + request.Name)
+}
+
+func (plugin recorderPlugin) record(name string) error {
+	return os.WriteFile(path.Join(plugin.output, name), []byte{}, 0o600)
+}
+
+// TestHelperPlugin is the plugin process started by the tests, it's skipped when the test binary isn't a plugin.
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv(helperEnv) == "" {
+		return
+	}
+
+	if err := plugins.Serve(recorderPlugin{output: os.Getenv(helperOutputEnv)}); err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func waitForFile(filePath string) bool {
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(filePath); err == nil {
+			return true
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return false
+}
+
+func TestPluginManager(t *testing.T) {
+	Convey("Plugins extend zot with routes, hooks and tasks", t, func() {
+		output := t.TempDir()
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+		pluginURL := baseURL + constants.FullPlugins + "/recorder"
+
+		htpasswdPath := test.MakeHtpasswdFileFromString(test.GetCredString("admin", "admin") + "\n" +
+			test.GetCredString("user", "user"))
+		defer os.Remove(htpasswdPath)
+
+		defaultVal := true
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					DefaultPolicy: []string{"read", "create", "update", "delete"},
+				},
+			},
+			AdminPolicy: config.Policy{Users: []string{"admin"}, Actions: []string{"read"}},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{
+			Plugins: &extconf.PluginsConfig{
+				BaseConfig: extconf.BaseConfig{Enable: &defaultVal},
+				Plugins: []extconf.PluginConfig{
+					{
+						Name: "recorder",
+						Path: os.Args[0],
+						Args: []string{"-test.run=^TestHelperPlugin$"},
+						Env:  map[string]string{helperEnv: "1", helperOutputEnv: output},
+					},
+					{
+						// plugins which fail to start are skipped
+						Name: "missing",
+						Path: "/nonexistent/plugin",
+					},
+				},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := test.NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		Convey("The routes of the plugin", func() {
+			resp, err := resty.R().Get(pluginURL + "/hello")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			resp, err = resty.R().SetBasicAuth("user", "user").Get(pluginURL + "/hello")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("Content-Type"), ShouldEqual, "text/plain")
+			// the credentials of the users aren't passed to the plugins
+			So(string(resp.Body()), ShouldEqual, "GET /hello user  authorization=false")
+
+			resp, err = resty.R().SetBasicAuth("user", "user").SetBody("content").Post(pluginURL + "/items/1")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(string(resp.Body()), ShouldEqual, "POST /items/1 user content authorization=false")
+
+			resp, err = resty.R().SetBasicAuth("user", "user").Get(pluginURL + "/items/1")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
+
+			resp, err = resty.R().SetBasicAuth("user", "user").Get(pluginURL + "/admin")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = resty.R().SetBasicAuth("admin", "admin").Get(pluginURL + "/admin")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			resp, err = resty.R().SetBasicAuth("admin", "admin").Get(baseURL + constants.FullPlugins + "/missing/hello")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("The hooks and tasks of the plugin", func() {
+			err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "repo", "1.0", "user", "user")
+			So(err, ShouldBeNil)
+			So(waitForFile(path.Join(output, "push-repo-1.0")), ShouldBeTrue)
+
+			resp, err := resty.R().SetBasicAuth("user", "user").Delete(baseURL + "/v2/repo/manifests/1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+			So(waitForFile(path.Join(output, "delete-repo-1.0")), ShouldBeTrue)
+
+			So(waitForFile(path.Join(output, "task-report")), ShouldBeTrue)
+		})
+	})
+}
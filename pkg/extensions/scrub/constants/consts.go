@@ -0,0 +1,8 @@
+package constants
+
+// sources the corrupted blobs are repaired from.
+const (
+	DedupeRepairSource  = "dedupe"  // a valid copy of the blob in another repo
+	ReplicaRepairSource = "replica" // the blob in the replica directory
+	SyncRepairSource    = "sync"    // the blob in the upstream registry the repo is synced from
+)
@@ -0,0 +1,199 @@
+//go:build scrub
+// +build scrub
+
+package scrub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/scrub/constants"
+	"zotregistry.io/zot/pkg/log"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+/*
+Repairer replaces the corrupted blobs found by scrub with the first valid copy found in its sources:
+another repo of the same store having the blob (dedupe), the replica directory, or the upstream registry
+the repo is synced from.
+*/
+type Repairer struct {
+	sources    []string
+	replicaDir string
+	blobSource storageTypes.BlobSource
+	log        log.Logger
+}
+
+// NewRepairer returns nil if repairing is not configured, blobSource is nil if sync is not enabled.
+func NewRepairer(config *extconf.ScrubRepairConfig, blobSource storageTypes.BlobSource, log log.Logger) *Repairer {
+	if config == nil {
+		return nil
+	}
+
+	return &Repairer{
+		sources:    config.Sources,
+		replicaDir: config.ReplicaDirectory,
+		blobSource: blobSource,
+		log:        log,
+	}
+}
+
+// RepairBlob replaces a corrupted blob of a repo, it returns the source the valid copy was taken from.
+func (repairer *Repairer) RepairBlob(ctx context.Context, imgStore storageTypes.ImageStore, repo string,
+	digest godigest.Digest,
+) (string, error) {
+	if err := digest.Validate(); err != nil {
+		return "", err
+	}
+
+	for _, source := range repairer.sources {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		err := repairer.repairFrom(ctx, source, imgStore, repo, digest)
+		if err == nil {
+			repairer.log.Info().Str("repo", repo).Str("blob", digest.String()).Str("source", source).
+				Msg("scrub: repaired corrupted blob")
+
+			return source, nil
+		}
+
+		repairer.log.Debug().Err(err).Str("repo", repo).Str("blob", digest.String()).Str("source", source).
+			Msg("scrub: unable to repair corrupted blob from source")
+	}
+
+	return "", zerr.ErrScrubNoValidBlobCopy
+}
+
+func (repairer *Repairer) repairFrom(ctx context.Context, source string, imgStore storageTypes.ImageStore,
+	repo string, digest godigest.Digest,
+) error {
+	switch source {
+	case constants.DedupeRepairSource:
+		return repairer.repairFromDuplicate(imgStore, repo, digest)
+	case constants.ReplicaRepairSource:
+		blobFile, err := os.Open(getBlobPath(repairer.replicaDir, repo, digest))
+		if err != nil {
+			return err
+		}
+
+		defer blobFile.Close()
+
+		return replaceBlob(imgStore, repo, digest, blobFile)
+	case constants.SyncRepairSource:
+		if repairer.blobSource == nil {
+			return zerr.ErrScrubNoValidBlobCopy
+		}
+
+		blobReader, err := repairer.blobSource.GetBlob(ctx, repo, digest)
+		if err != nil {
+			return err
+		}
+
+		defer blobReader.Close()
+
+		return replaceBlob(imgStore, repo, digest, blobReader)
+	default:
+		return fmt.Errorf("%w: unknown repair source %s", zerr.ErrScrubNoValidBlobCopy, source)
+	}
+}
+
+// repairFromDuplicate copies the blob from another repo of the store, the hard links of a deduped blob
+// share its corrupted content, so they're skipped.
+func (repairer *Repairer) repairFromDuplicate(imgStore storageTypes.ImageStore, repo string,
+	digest godigest.Digest,
+) error {
+	repos, err := imgStore.GetRepositories()
+	if err != nil {
+		return err
+	}
+
+	corruptedInfo, _ := os.Stat(getBlobPath(imgStore.RootDir(), repo, digest))
+
+	for _, otherRepo := range repos {
+		if otherRepo == repo {
+			continue
+		}
+
+		duplicatePath := getBlobPath(imgStore.RootDir(), otherRepo, digest)
+
+		duplicateInfo, err := os.Stat(duplicatePath)
+		if err != nil || (corruptedInfo != nil && os.SameFile(corruptedInfo, duplicateInfo)) {
+			continue
+		}
+
+		duplicateFile, err := os.Open(duplicatePath)
+		if err != nil {
+			continue
+		}
+
+		err = replaceBlob(imgStore, repo, digest, duplicateFile)
+		duplicateFile.Close()
+
+		if err == nil {
+			return nil
+		}
+	}
+
+	return zerr.ErrScrubNoValidBlobCopy
+}
+
+// replaceBlob verifies the content of a copy of the blob, then replaces the blob with it.
+func replaceBlob(imgStore storageTypes.ImageStore, repo string, digest godigest.Digest, content io.Reader) error {
+	uploadDir := path.Join(imgStore.RootDir(), repo, storageConstants.BlobUploadDir)
+	if err := os.MkdirAll(uploadDir, storageConstants.DefaultDirPerms); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(uploadDir, "repair-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	digester := digest.Algorithm().Digester()
+
+	_, err = io.Copy(io.MultiWriter(tmpFile, digester.Hash()), content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if digester.Digest() != digest {
+		return zerr.ErrBadBlobDigest
+	}
+
+	blobPath := getBlobPath(imgStore.RootDir(), repo, digest)
+
+	var lockLatency time.Time
+
+	if err := imgStore.LockRepo(repo, &lockLatency); err != nil {
+		return err
+	}
+	defer imgStore.UnlockRepo(repo, &lockLatency)
+
+	if err := os.MkdirAll(path.Dir(blobPath), storageConstants.DefaultDirPerms); err != nil {
+		return err
+	}
+
+	// renaming keeps the other hard links of a deduped blob, they're repaired when their repo is scrubbed
+	return os.Rename(tmpFile.Name(), blobPath)
+}
+
+func getBlobPath(rootDir, repo string, digest godigest.Digest) string {
+	return path.Join(rootDir, repo, "blobs", digest.Algorithm().String(), digest.Encoded())
+}
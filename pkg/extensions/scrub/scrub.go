@@ -8,17 +8,29 @@ import (
 	"fmt"
 	"path"
 
+	godigest "github.com/opencontainers/go-digest"
+
 	"zotregistry.io/zot/pkg/log"
 	"zotregistry.io/zot/pkg/storage"
 	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
 
-// Scrub Extension for repo...
-func RunScrubRepo(ctx context.Context, imgStore storageTypes.ImageStore, repo string, log log.Logger) error {
+// Scrub Extension for repo, the corrupted blobs are repaired if repairer is not nil.
+func RunScrubRepo(ctx context.Context, imgStore storageTypes.ImageStore, repo string, repairer *Repairer,
+	log log.Logger,
+) error {
 	execMsg := fmt.Sprintf("executing scrub to check manifest/blob integrity for %s", path.Join(imgStore.RootDir(), repo))
 	log.Info().Msg(execMsg)
 
-	results, err := storage.CheckRepo(ctx, repo, imgStore)
+	var repairBlob storage.BlobRepairer
+
+	if repairer != nil {
+		repairBlob = func(ctx context.Context, repo string, digest godigest.Digest) (string, error) {
+			return repairer.RepairBlob(ctx, imgStore, repo, digest)
+		}
+	}
+
+	results, err := storage.CheckAndRepairRepo(ctx, repo, imgStore, repairBlob)
 	if err != nil {
 		errMessage := fmt.Sprintf("error while running scrub for %s", path.Join(imgStore.RootDir(), repo))
 		log.Error().Err(err).Msg(errMessage)
@@ -28,18 +40,30 @@ func RunScrubRepo(ctx context.Context, imgStore storageTypes.ImageStore, repo st
 	}
 
 	for _, result := range results {
-		if result.Status == "ok" {
+		switch {
+		case result.RepairStatus == storage.RepairStatusRepaired:
+			log.Info().
+				Str("image", result.ImageName).
+				Str("tag", result.Tag).
+				Str("status", result.Status).
+				Str("repairStatus", result.RepairStatus).
+				Str("repairSource", result.RepairSource).
+				Msg("scrub: blobs/manifest repaired")
+		case result.Status == storage.ScrubStatusOK:
 			log.Info().
 				Str("image", result.ImageName).
 				Str("tag", result.Tag).
 				Str("status", result.Status).
 				Msg("scrub: blobs/manifest ok")
-		} else {
+		default:
 			log.Warn().
 				Str("image", result.ImageName).
 				Str("tag", result.Tag).
 				Str("status", result.Status).
 				Str("error", result.Error).
+				Str("affectedBlob", result.AffectedBlob).
+				Str("repairStatus", result.RepairStatus).
+				Str("repairError", result.RepairError).
 				Msg("scrub: blobs/manifest affected")
 		}
 	}
@@ -52,13 +76,14 @@ func RunScrubRepo(ctx context.Context, imgStore storageTypes.ImageStore, repo st
 type Task struct {
 	imgStore storageTypes.ImageStore
 	repo     string
+	repairer *Repairer
 	log      log.Logger
 }
 
-func NewTask(imgStore storageTypes.ImageStore, repo string, log log.Logger) *Task {
-	return &Task{imgStore, repo, log}
+func NewTask(imgStore storageTypes.ImageStore, repo string, repairer *Repairer, log log.Logger) *Task {
+	return &Task{imgStore, repo, repairer, log}
 }
 
 func (scrubT *Task) DoWork(ctx context.Context) error {
-	return RunScrubRepo(ctx, scrubT.imgStore, scrubT.repo, scrubT.log) //nolint: contextcheck
+	return RunScrubRepo(ctx, scrubT.imgStore, scrubT.repo, scrubT.repairer, scrubT.log) //nolint: contextcheck
 }
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	godigest "github.com/opencontainers/go-digest"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"zotregistry.io/zot/pkg/api"
@@ -201,7 +202,7 @@ func TestRunScrubRepo(t *testing.T) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, 1*time.Second, 1*time.Second, true,
-			true, log, metrics, nil, cacheDriver)
+			true, false, log, metrics, nil, cacheDriver)
 
 		srcStorageCtlr := test.GetDefaultStoreController(dir, log)
 		image := CreateDefaultVulnerableImage()
@@ -209,7 +210,7 @@ func TestRunScrubRepo(t *testing.T) {
 		err = test.WriteImageToFileSystem(image, repoName, "0.0.1", srcStorageCtlr)
 		So(err, ShouldBeNil)
 
-		err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, log)
+		err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, nil, log)
 		So(err, ShouldBeNil)
 
 		data, err := os.ReadFile(logFile.Name())
@@ -237,7 +238,7 @@ func TestRunScrubRepo(t *testing.T) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, 1*time.Second, 1*time.Second, true,
-			true, log, metrics, nil, cacheDriver)
+			true, false, log, metrics, nil, cacheDriver)
 
 		srcStorageCtlr := test.GetDefaultStoreController(dir, log)
 		image := CreateDefaultVulnerableImage()
@@ -252,7 +253,7 @@ func TestRunScrubRepo(t *testing.T) {
 			panic(err)
 		}
 
-		err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, log)
+		err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, nil, log)
 		So(err, ShouldBeNil)
 
 		data, err := os.ReadFile(logFile.Name())
@@ -260,6 +261,86 @@ func TestRunScrubRepo(t *testing.T) {
 		So(string(data), ShouldContainSubstring, "scrub: blobs/manifest affected")
 	})
 
+	Convey("Corrupted blobs repaired", t, func(c C) {
+		logFile, err := os.CreateTemp("", "zot-log*.txt")
+		So(err, ShouldBeNil)
+
+		defer os.Remove(logFile.Name()) // clean up
+
+		dir := t.TempDir()
+		replicaDir := t.TempDir()
+		log := log.NewLogger("debug", logFile.Name())
+		metrics := monitoring.NewMetricsServer(false, log)
+		imgStore := local.NewImageStore(dir, true, false, 1*time.Second, 1*time.Second, false,
+			false, false, log, metrics, nil, nil)
+
+		image := CreateRandomImage()
+		layerDigest := image.Manifest.Layers[0].Digest
+		layerPath := path.Join(dir, repoName, "blobs/sha256", layerDigest.Encoded())
+
+		err = test.WriteImageToFileSystem(image, repoName, "0.0.1", test.GetDefaultStoreController(dir, log))
+		So(err, ShouldBeNil)
+
+		err = test.WriteImageToFileSystem(image, repoName, "0.0.1", test.GetDefaultStoreController(replicaDir, log))
+		So(err, ShouldBeNil)
+
+		err = test.WriteImageToFileSystem(image, "duplicate", "0.0.1", test.GetDefaultStoreController(dir, log))
+		So(err, ShouldBeNil)
+
+		Convey("From a duplicate in another repo", func() {
+			So(os.WriteFile(layerPath, []byte("corrupted"), 0o600), ShouldBeNil)
+
+			repairer := scrub.NewRepairer(&extconf.ScrubRepairConfig{Sources: []string{"dedupe"}}, nil, log)
+
+			err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, repairer, log)
+			So(err, ShouldBeNil)
+
+			data, err := os.ReadFile(logFile.Name())
+			So(err, ShouldBeNil)
+			So(string(data), ShouldContainSubstring, "scrub: blobs/manifest repaired")
+			So(string(data), ShouldContainSubstring, `"repairSource":"dedupe"`)
+
+			blob, err := os.ReadFile(layerPath)
+			So(err, ShouldBeNil)
+			So(godigest.FromBytes(blob), ShouldEqual, layerDigest)
+		})
+
+		Convey("From the replica", func() {
+			So(os.Remove(layerPath), ShouldBeNil)
+			So(os.Remove(path.Join(dir, "duplicate", "blobs/sha256", layerDigest.Encoded())), ShouldBeNil)
+
+			repairer := scrub.NewRepairer(&extconf.ScrubRepairConfig{
+				Sources:          []string{"dedupe", "replica"},
+				ReplicaDirectory: replicaDir,
+			}, nil, log)
+
+			err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, repairer, log)
+			So(err, ShouldBeNil)
+
+			data, err := os.ReadFile(logFile.Name())
+			So(err, ShouldBeNil)
+			So(string(data), ShouldContainSubstring, `"repairSource":"replica"`)
+
+			blob, err := os.ReadFile(layerPath)
+			So(err, ShouldBeNil)
+			So(godigest.FromBytes(blob), ShouldEqual, layerDigest)
+		})
+
+		Convey("No valid copy found", func() {
+			So(os.WriteFile(layerPath, []byte("corrupted"), 0o600), ShouldBeNil)
+
+			repairer := scrub.NewRepairer(&extconf.ScrubRepairConfig{Sources: []string{"sync"}}, nil, log)
+
+			err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, repairer, log)
+			So(err, ShouldBeNil)
+
+			data, err := os.ReadFile(logFile.Name())
+			So(err, ShouldBeNil)
+			So(string(data), ShouldContainSubstring, "scrub: blobs/manifest affected")
+			So(string(data), ShouldContainSubstring, `"repairStatus":"failed"`)
+		})
+	})
+
 	Convey("CheckRepo error - not enough permissions to access root directory", t, func(c C) {
 		logFile, err := os.CreateTemp("", "zot-log*.txt")
 		So(err, ShouldBeNil)
@@ -279,7 +360,7 @@ func TestRunScrubRepo(t *testing.T) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, 1*time.Second,
-			1*time.Second, true, true, log, metrics, nil, cacheDriver,
+			1*time.Second, true, true, false, log, metrics, nil, cacheDriver,
 		)
 
 		srcStorageCtlr := test.GetDefaultStoreController(dir, log)
@@ -290,7 +371,7 @@ func TestRunScrubRepo(t *testing.T) {
 
 		So(os.Chmod(path.Join(dir, repoName), 0o000), ShouldBeNil)
 
-		err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, log)
+		err = scrub.RunScrubRepo(context.Background(), imgStore, repoName, nil, log)
 		So(err, ShouldNotBeNil)
 
 		data, err := os.ReadFile(logFile.Name())
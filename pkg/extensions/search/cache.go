@@ -0,0 +1,269 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	godigest "github.com/opencontainers/go-digest"
+
+	"zotregistry.io/zot/pkg/api/constants"
+	"zotregistry.io/zot/pkg/extensions/search/convert"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+)
+
+const resultCacheSize = 1024
+
+// ResultCache keeps the results of expensive resolvers for a short amount of time so that
+// clients polling the same queries don't walk MetaDB on every request.
+// A nil *ResultCache is valid and caches nothing.
+type ResultCache struct {
+	cache *expirable.LRU[string, any]
+	log   log.Logger
+}
+
+func NewResultCache(ttl time.Duration, log log.Logger) *ResultCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &ResultCache{
+		cache: expirable.NewLRU[string, any](resultCacheSize, nil, ttl),
+		log:   log,
+	}
+}
+
+func (rc *ResultCache) Get(key string) (any, bool) {
+	if rc == nil || key == "" {
+		return nil, false
+	}
+
+	return rc.cache.Get(key)
+}
+
+func (rc *ResultCache) Add(key string, value any) {
+	if rc == nil || key == "" {
+		return
+	}
+
+	rc.cache.Add(key, value)
+}
+
+// Invalidate drops all cached results, it's called on every MetaDB write changing search results,
+// see WrapMetaDB.
+func (rc *ResultCache) Invalidate() {
+	if rc == nil {
+		return
+	}
+
+	rc.cache.Purge()
+}
+
+// Key builds a key unique to the resolver, its arguments, the requesting user with the access
+// resolved for the request (admin, groups and readable repositories, which may differ between logins
+// of the same user) and the requested fields (the fields decide which parts of the result are computed).
+// An empty key is returned if caching is disabled or the request can't be identified.
+func (rc *ResultCache) Key(ctx context.Context, resolver string, args ...any) string {
+	if rc == nil {
+		return ""
+	}
+
+	argsBlob, err := json.Marshal(args)
+	if err != nil {
+		rc.log.Debug().Err(err).Str("resolver", resolver).Msg("unable to compute result cache key")
+
+		return ""
+	}
+
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil || userAc == nil {
+		return ""
+	}
+
+	groups := append([]string{}, userAc.GetGroups()...)
+	sort.Strings(groups)
+
+	accessBlob, err := json.Marshal(userAccess{
+		Username:     userAc.GetUsername(),
+		IsAdmin:      userAc.IsAdmin(),
+		Groups:       groups,
+		ReadPatterns: userAc.GetGlobPatterns(constants.ReadPermission),
+	})
+	if err != nil {
+		return ""
+	}
+
+	preloads := []string{}
+
+	for field := range convert.GetPreloads(ctx) {
+		preloads = append(preloads, field)
+	}
+
+	sort.Strings(preloads)
+
+	return strings.Join([]string{resolver, string(argsBlob), string(accessBlob), strings.Join(preloads, ",")}, "|")
+}
+
+type userAccess struct {
+	Username     string
+	IsAdmin      bool
+	Groups       []string
+	ReadPatterns map[string]bool
+}
+
+// getCachedResult returns the result cached under key, or computes and caches it on a miss.
+func getCachedResult[T any](rc *ResultCache, key string, compute func() (T, error)) (T, error) {
+	if cached, ok := rc.Get(key); ok {
+		if result, ok := cached.(T); ok {
+			return result, nil
+		}
+	}
+
+	result, err := compute()
+	if err == nil {
+		rc.Add(key, result)
+	}
+
+	return result, err
+}
+
+// WrapMetaDB returns a MetaDB invalidating the cache on every write changing search results, no matter
+// who makes it (pushes, deletes, sync, signatures, READMEs, stars, bookmarks and user data).
+// Image download counters are not tracked, so they may lag behind by up to the cache TTL. Neither are the download
+// statistics, the user activity and the API keys, which aren't part of the search results.
+func (rc *ResultCache) WrapMetaDB(metaDB mTypes.MetaDB) mTypes.MetaDB {
+	if rc == nil || metaDB == nil {
+		return metaDB
+	}
+
+	return &invalidatingMetaDB{MetaDB: metaDB, cache: rc}
+}
+
+type invalidatingMetaDB struct {
+	mTypes.MetaDB
+	cache *ResultCache
+}
+
+func (db *invalidatingMetaDB) invalidate(err error) error {
+	db.cache.Invalidate()
+
+	return err
+}
+
+func (db *invalidatingMetaDB) IncrementRepoStars(repo string) error {
+	return db.invalidate(db.MetaDB.IncrementRepoStars(repo))
+}
+
+func (db *invalidatingMetaDB) DecrementRepoStars(repo string) error {
+	return db.invalidate(db.MetaDB.DecrementRepoStars(repo))
+}
+
+func (db *invalidatingMetaDB) SetRepoReadme(repo string, readme *mTypes.RepoReadme) error {
+	return db.invalidate(db.MetaDB.SetRepoReadme(repo, readme))
+}
+
+func (db *invalidatingMetaDB) SetRepoReference(repo string, reference string, manifestDigest godigest.Digest,
+	mediaType string,
+) error {
+	return db.invalidate(db.MetaDB.SetRepoReference(repo, reference, manifestDigest, mediaType))
+}
+
+func (db *invalidatingMetaDB) DeleteRepoTag(repo string, tag string) error {
+	return db.invalidate(db.MetaDB.DeleteRepoTag(repo, tag))
+}
+
+func (db *invalidatingMetaDB) DeleteRepoMeta(repo string) error {
+	return db.invalidate(db.MetaDB.DeleteRepoMeta(repo))
+}
+
+func (db *invalidatingMetaDB) SetRepoMeta(repo string, repoMeta mTypes.RepoMetadata) error {
+	return db.invalidate(db.MetaDB.SetRepoMeta(repo, repoMeta))
+}
+
+func (db *invalidatingMetaDB) SetManifestData(manifestDigest godigest.Digest, md mTypes.ManifestData) error {
+	return db.invalidate(db.MetaDB.SetManifestData(manifestDigest, md))
+}
+
+func (db *invalidatingMetaDB) SetManifestMeta(repo string, manifestDigest godigest.Digest,
+	mm mTypes.ManifestMetadata,
+) error {
+	return db.invalidate(db.MetaDB.SetManifestMeta(repo, manifestDigest, mm))
+}
+
+func (db *invalidatingMetaDB) SetIndexData(digest godigest.Digest, indexData mTypes.IndexData) error {
+	return db.invalidate(db.MetaDB.SetIndexData(digest, indexData))
+}
+
+func (db *invalidatingMetaDB) SetReferrer(repo string, referredDigest godigest.Digest,
+	referrer mTypes.ReferrerInfo,
+) error {
+	return db.invalidate(db.MetaDB.SetReferrer(repo, referredDigest, referrer))
+}
+
+func (db *invalidatingMetaDB) DeleteReferrer(repo string, referredDigest godigest.Digest,
+	referrerDigest godigest.Digest,
+) error {
+	return db.invalidate(db.MetaDB.DeleteReferrer(repo, referredDigest, referrerDigest))
+}
+
+func (db *invalidatingMetaDB) AddManifestSignature(repo string, signedManifestDigest godigest.Digest,
+	sm mTypes.SignatureMetadata,
+) error {
+	return db.invalidate(db.MetaDB.AddManifestSignature(repo, signedManifestDigest, sm))
+}
+
+func (db *invalidatingMetaDB) DeleteSignature(repo string, signedManifestDigest godigest.Digest,
+	sm mTypes.SignatureMetadata,
+) error {
+	return db.invalidate(db.MetaDB.DeleteSignature(repo, signedManifestDigest, sm))
+}
+
+func (db *invalidatingMetaDB) UpdateSignaturesValidity(repo string, manifestDigest godigest.Digest) error {
+	return db.invalidate(db.MetaDB.UpdateSignaturesValidity(repo, manifestDigest))
+}
+
+func (db *invalidatingMetaDB) ToggleStarRepo(ctx context.Context, reponame string) (mTypes.ToggleState, error) {
+	toggleState, err := db.MetaDB.ToggleStarRepo(ctx, reponame)
+
+	return toggleState, db.invalidate(err)
+}
+
+func (db *invalidatingMetaDB) ToggleBookmarkRepo(ctx context.Context, reponame string) (mTypes.ToggleState, error) {
+	toggleState, err := db.MetaDB.ToggleBookmarkRepo(ctx, reponame)
+
+	return toggleState, db.invalidate(err)
+}
+
+func (db *invalidatingMetaDB) SetBookmarkList(ctx context.Context, list mTypes.BookmarkList) error {
+	return db.invalidate(db.MetaDB.SetBookmarkList(ctx, list))
+}
+
+func (db *invalidatingMetaDB) DeleteBookmarkList(ctx context.Context, name string) error {
+	return db.invalidate(db.MetaDB.DeleteBookmarkList(ctx, name))
+}
+
+func (db *invalidatingMetaDB) SetUserData(ctx context.Context, userData mTypes.UserData) error {
+	return db.invalidate(db.MetaDB.SetUserData(ctx, userData))
+}
+
+func (db *invalidatingMetaDB) DeleteUserData(ctx context.Context) error {
+	return db.invalidate(db.MetaDB.DeleteUserData(ctx))
+}
+
+func (db *invalidatingMetaDB) SetUserGroups(ctx context.Context, groups []string) error {
+	return db.invalidate(db.MetaDB.SetUserGroups(ctx, groups))
+}
+
+func (db *invalidatingMetaDB) PatchDB() error {
+	return db.invalidate(db.MetaDB.PatchDB())
+}
+
+func (db *invalidatingMetaDB) Restore(ctx context.Context, reader io.Reader) error {
+	return db.invalidate(db.MetaDB.Restore(ctx, reader))
+}
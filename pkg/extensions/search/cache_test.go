@@ -0,0 +1,207 @@
+package search //nolint
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/extensions/search/gql_generated"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+	"zotregistry.io/zot/pkg/storage"
+	"zotregistry.io/zot/pkg/test/mocks"
+)
+
+func TestResultCache(t *testing.T) {
+	Convey("Result cache", t, func() {
+		log := log.NewLogger("debug", "")
+		ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter,
+			graphql.DefaultRecover)
+
+		searchCalls := 0
+
+		mockMetaDB := mocks.MetaDBMock{
+			SearchReposFn: func(ctx context.Context, searchText string,
+			) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+				searchCalls++
+
+				return []mTypes.RepoMetadata{}, map[string]mTypes.ManifestMetadata{}, map[string]mTypes.IndexData{}, nil
+			},
+		}
+
+		Convey("Disabled cache", func() {
+			So(NewResultCache(0, log), ShouldBeNil)
+
+			var resultCache *ResultCache
+
+			So(resultCache.Key(ctx, "GlobalSearch", "repo"), ShouldBeEmpty)
+			resultCache.Add("key", 1)
+			_, found := resultCache.Get("key")
+			So(found, ShouldBeFalse)
+			So(resultCache.Invalidate, ShouldNotPanic)
+
+			resolver := queryResolver{NewResolver(log, storage.StoreController{}, mockMetaDB, mocks.CveInfoMock{})}
+
+			_, err := resolver.GlobalSearch(ctx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			_, err = resolver.GlobalSearch(ctx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			So(searchCalls, ShouldEqual, 2)
+		})
+
+		Convey("Enabled cache", func() {
+			resultCache := NewResultCache(time.Hour, log)
+			So(resultCache, ShouldNotBeNil)
+
+			resolverConfig := NewResolver(log, storage.StoreController{}, mockMetaDB, mocks.CveInfoMock{})
+			resolverConfig.resultCache = resultCache
+			resolver := queryResolver{resolverConfig}
+
+			_, err := resolver.GlobalSearch(ctx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			_, err = resolver.GlobalSearch(ctx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			So(searchCalls, ShouldEqual, 1)
+
+			// different arguments
+			_, err = resolver.GlobalSearch(ctx, "other", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			So(searchCalls, ShouldEqual, 2)
+
+			// different user
+			userAc := reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user")
+			userCtx := context.WithValue(ctx, reqCtx.GetContextKey(), *userAc)
+
+			_, err = resolver.GlobalSearch(userCtx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			So(searchCalls, ShouldEqual, 3)
+
+			resultCache.Invalidate()
+
+			_, err = resolver.GlobalSearch(ctx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			So(searchCalls, ShouldEqual, 4)
+		})
+
+		Convey("Errors are not cached", func() {
+			resolverConfig := NewResolver(log, storage.StoreController{}, mocks.MetaDBMock{}, mocks.CveInfoMock{})
+			resolverConfig.resultCache = NewResultCache(time.Hour, log)
+			resolver := queryResolver{resolverConfig}
+
+			_, err := resolver.BaseImageList(ctx, "repo", nil, &gql_generated.PageInput{})
+			So(err, ShouldNotBeNil)
+
+			_, found := resolverConfig.resultCache.Get(
+				resolverConfig.resultCache.Key(ctx, "BaseImageList", "repo", nil, &gql_generated.PageInput{}))
+			So(found, ShouldBeFalse)
+		})
+
+		Convey("Same user with different access", func() {
+			resultCache := NewResultCache(time.Hour, log)
+
+			userAc := reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user")
+			userAc.SetGlobPatterns("read", map[string]bool{"repo1": true})
+			key := resultCache.Key(context.WithValue(ctx, reqCtx.GetContextKey(), *userAc), "GlobalSearch", "repo")
+
+			userAc = reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user")
+			userAc.SetGlobPatterns("read", map[string]bool{"repo1": true, "repo2": true})
+			So(resultCache.Key(context.WithValue(ctx, reqCtx.GetContextKey(), *userAc), "GlobalSearch", "repo"),
+				ShouldNotEqual, key)
+
+			userAc = reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user")
+			userAc.SetGlobPatterns("read", map[string]bool{"repo1": true})
+			userAc.AddGroups([]string{"group"})
+			So(resultCache.Key(context.WithValue(ctx, reqCtx.GetContextKey(), *userAc), "GlobalSearch", "repo"),
+				ShouldNotEqual, key)
+		})
+
+		Convey("MetaDB writes invalidate the cache", func() {
+			var nilCache *ResultCache
+
+			So(nilCache.WrapMetaDB(mockMetaDB), ShouldHaveSameTypeAs, mockMetaDB)
+
+			resultCache := NewResultCache(time.Hour, log)
+			metaDB := resultCache.WrapMetaDB(mockMetaDB)
+
+			writes := []func() error{
+				func() error { return metaDB.IncrementRepoStars("repo") },
+				func() error { return metaDB.DecrementRepoStars("repo") },
+				func() error { return metaDB.SetRepoReadme("repo", &mTypes.RepoReadme{Content: "# repo"}) },
+				func() error { return metaDB.SetRepoReference("repo", "tag", "digest", "type") },
+				func() error { return metaDB.DeleteRepoTag("repo", "tag") },
+				func() error { return metaDB.DeleteRepoMeta("repo") },
+				func() error { return metaDB.SetRepoMeta("repo", mTypes.RepoMetadata{}) },
+				func() error { return metaDB.SetManifestData("digest", mTypes.ManifestData{}) },
+				func() error { return metaDB.SetManifestMeta("repo", "digest", mTypes.ManifestMetadata{}) },
+				func() error { return metaDB.SetIndexData("digest", mTypes.IndexData{}) },
+				func() error { return metaDB.SetReferrer("repo", "digest", mTypes.ReferrerInfo{}) },
+				func() error { return metaDB.DeleteReferrer("repo", "digest", "referrer") },
+				func() error { return metaDB.AddManifestSignature("repo", "digest", mTypes.SignatureMetadata{}) },
+				func() error { return metaDB.DeleteSignature("repo", "digest", mTypes.SignatureMetadata{}) },
+				func() error { return metaDB.UpdateSignaturesValidity("repo", "digest") },
+				func() error { _, err := metaDB.ToggleStarRepo(ctx, "repo"); return err },     //nolint: nlreturn
+				func() error { _, err := metaDB.ToggleBookmarkRepo(ctx, "repo"); return err }, //nolint: nlreturn
+				func() error { return metaDB.SetBookmarkList(ctx, mTypes.BookmarkList{Name: "list"}) },
+				func() error { return metaDB.DeleteBookmarkList(ctx, "list") },
+				func() error { return metaDB.SetUserData(ctx, mTypes.UserData{}) },
+				func() error { return metaDB.DeleteUserData(ctx) },
+				func() error { return metaDB.SetUserGroups(ctx, []string{"group"}) },
+				func() error { return metaDB.PatchDB() },
+			}
+
+			for _, write := range writes {
+				resultCache.Add("key", 1)
+
+				So(write(), ShouldBeNil)
+
+				_, found := resultCache.Get("key")
+				So(found, ShouldBeFalse)
+			}
+
+			// reads don't invalidate
+			resultCache.Add("key", 1)
+			_, err := metaDB.GetRepoMeta("repo")
+			So(err, ShouldBeNil)
+
+			_, found := resultCache.Get("key")
+			So(found, ShouldBeTrue)
+		})
+
+		Convey("The results cached before a README update are recomputed", func() {
+			resultCache := NewResultCache(time.Hour, log)
+			metaDB := resultCache.WrapMetaDB(mockMetaDB)
+
+			resolverConfig := NewResolver(log, storage.StoreController{}, metaDB, mocks.CveInfoMock{})
+			resolverConfig.resultCache = resultCache
+			resolver := queryResolver{resolverConfig}
+
+			_, err := resolver.GlobalSearch(ctx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			So(searchCalls, ShouldEqual, 1)
+
+			err = metaDB.SetRepoReadme("repo", &mTypes.RepoReadme{Content: "# repo"})
+			So(err, ShouldBeNil)
+
+			_, err = resolver.GlobalSearch(ctx, "repo", &gql_generated.Filter{}, &gql_generated.PageInput{})
+			So(err, ShouldBeNil)
+			So(searchCalls, ShouldEqual, 2)
+		})
+
+		Convey("Bad user access control in context", func() {
+			resultCache := NewResultCache(time.Hour, log)
+
+			var invalid struct{}
+
+			badCtx := context.WithValue(ctx, reqCtx.GetContextKey(), invalid)
+			So(resultCache.Key(badCtx, "GlobalSearch", "repo"), ShouldBeEmpty)
+		})
+	})
+}
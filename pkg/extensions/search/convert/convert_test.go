@@ -1073,3 +1073,54 @@ func TestDownloadCount(t *testing.T) {
 		So(*repoSummary.NewestImage.DownloadCount, ShouldEqual, 100)
 	})
 }
+
+func TestLastPulled(t *testing.T) {
+	Convey("last pulls of the images and of the repo", t, func() {
+		lastPulled := time.Date(2023, 1, 1, 1, 1, 1, 0, time.UTC)
+		pulledImage, neverPulledImage := CreateRandomImage(), CreateRandomImage()
+
+		repoMeta, manifestMetaMap, indexDataMap := test.GetMetadataForRepos(
+			test.Repo{
+				Name: "repo",
+				Images: []test.RepoImage{
+					{
+						Image:      pulledImage,
+						Tag:        "pulled",
+						Statistics: mTypes.DescriptorStatistics{DownloadCount: 1, LastPullTimestamp: lastPulled},
+					},
+					{
+						Image: neverPulledImage,
+						Tag:   "never-pulled",
+					},
+				},
+			},
+		)
+
+		repoSummary := convert.RepoMeta2RepoSummary(context.Background(), repoMeta[0], manifestMetaMap, indexDataMap,
+			convert.SkipQGLField{}, nil)
+		So(*repoSummary.LastPulled, ShouldEqual, lastPulled)
+
+		imageSummary, _, err := convert.ImageManifest2ImageSummary(context.Background(), "repo", "pulled",
+			pulledImage.Digest(), true, repoMeta[0], manifestMetaMap[pulledImage.DigestStr()], nil)
+		So(err, ShouldBeNil)
+		So(*imageSummary.LastPulled, ShouldEqual, lastPulled)
+		So(*imageSummary.Manifests[0].LastPulled, ShouldEqual, lastPulled)
+
+		// the pulls by tag count for the image of the tag
+		tagLastPulled := lastPulled.Add(time.Hour)
+		repoMeta[0].TagStatistics = map[string]mTypes.DescriptorStatistics{
+			"pulled": {DownloadCount: 1, LastPullTimestamp: tagLastPulled},
+		}
+
+		imageSummary, _, err = convert.ImageManifest2ImageSummary(context.Background(), "repo", "pulled",
+			pulledImage.Digest(), true, repoMeta[0], manifestMetaMap[pulledImage.DigestStr()], nil)
+		So(err, ShouldBeNil)
+		So(*imageSummary.LastPulled, ShouldEqual, tagLastPulled)
+
+		imageSummary, _, err = convert.ImageManifest2ImageSummary(context.Background(), "repo", "never-pulled",
+			neverPulledImage.Digest(), true, repoMeta[0], manifestMetaMap[neverPulledImage.DigestStr()], nil)
+		So(err, ShouldBeNil)
+		So(imageSummary.LastPulled, ShouldBeNil)
+		So(imageSummary.Manifests[0].LastPulled, ShouldBeNil)
+	})
+}
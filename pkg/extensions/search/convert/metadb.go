@@ -131,6 +131,7 @@ func RepoMeta2RepoSummary(ctx context.Context, repoMeta mTypes.RepoMetadata,
 		Vendors:       repoVendors,
 		NewestImage:   lastUpdatedImageSummary,
 		DownloadCount: &repoDownloadCount,
+		LastPulled:    getLastPulled(mcommon.GetRepoLastPull(repoMeta)),
 		StarCount:     &repoStarCount,
 		IsBookmarked:  &repoIsUserBookMarked,
 		IsStarred:     &repoIsUserStarred,
@@ -284,6 +285,7 @@ func ImageIndex2ImageSummary(ctx context.Context, repo, tag string, indexDigest
 		SignatureInfo: signaturesInfo,
 		Size:          &indexSize,
 		DownloadCount: &totalDownloadCount,
+		LastPulled:    getLastPulled(mcommon.GetImageLastPull(repoMeta, tag, indexDigestStr)),
 		Description:   &annotations.Description,
 		Title:         &annotations.Title,
 		Documentation: &annotations.Documentation,
@@ -419,6 +421,7 @@ func ImageManifest2ImageSummary(ctx context.Context, repo, tag string, digest go
 				SignatureInfo: signaturesInfo,
 				Platform:      &platform,
 				DownloadCount: &downloadCount,
+				LastPulled:    getLastPulled(repoMeta.Statistics[manifestDigest].LastPullTimestamp),
 				Layers:        getLayersSummaries(manifestContent),
 				History:       historyEntries,
 				Vulnerabilities: &gql_generated.ImageVulnerabilitySummary{
@@ -434,6 +437,7 @@ func ImageManifest2ImageSummary(ctx context.Context, repo, tag string, digest go
 		SignatureInfo: signaturesInfo,
 		Size:          &imageSize,
 		DownloadCount: &downloadCount,
+		LastPulled:    getLastPulled(mcommon.GetImageLastPull(repoMeta, tag, manifestDigest)),
 		Description:   &annotations.Description,
 		Title:         &annotations.Title,
 		Documentation: &annotations.Documentation,
@@ -452,6 +456,15 @@ func ImageManifest2ImageSummary(ctx context.Context, repo, tag string, digest go
 	return &imageSummary, imageBlobsMap, nil
 }
 
+// getLastPulled returns nil instead of the zero time of the images which were never pulled.
+func getLastPulled(lastPull time.Time) *time.Time {
+	if lastPull.IsZero() {
+		return nil
+	}
+
+	return &lastPull
+}
+
 func getReferrers(referrersInfo []mTypes.ReferrerInfo) []*gql_generated.Referrer {
 	referrers := make([]*gql_generated.Referrer, 0, len(referrersInfo))
 
@@ -564,6 +577,7 @@ func ImageManifest2ManifestSummary(ctx context.Context, repo, tag string, descri
 		Size:          &imageSize,
 		Platform:      &platform,
 		DownloadCount: &downloadCount,
+		LastPulled:    getLastPulled(repoMeta.Statistics[manifestDigestStr].LastPullTimestamp),
 		Layers:        getLayersSummaries(manifestContent),
 		History:       historyEntries,
 		IsSigned:      &isSigned,
@@ -769,6 +783,7 @@ func RepoMeta2ExpandedRepoInfo(ctx context.Context, repoMeta mTypes.RepoMetadata
 		Vendors:       repoVendors,
 		NewestImage:   lastUpdatedImageSummary,
 		DownloadCount: &repoDownloadCount,
+		LastPulled:    getLastPulled(mcommon.GetRepoLastPull(repoMeta)),
 		StarCount:     &repoStarCount,
 		IsBookmarked:  &isBookmarked,
 		IsStarred:     &isStarred,
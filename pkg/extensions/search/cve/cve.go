@@ -20,16 +20,23 @@ import (
 type CveInfo interface {
 	GetImageListForCVE(repo, cveID string) ([]cvemodel.TagInfo, error)
 	GetImageListWithCVEFixed(repo, cveID string) ([]cvemodel.TagInfo, error)
-	GetCVEListForImage(repo, tag string, searchedCVE string, pageinput cvemodel.PageInput,
+	GetCVEListForImage(repo, tag string, searchedCVE string, fixStatus cvemodel.FixStatus, pageinput cvemodel.PageInput,
 	) ([]cvemodel.CVE, zcommon.PageInfo, error)
 	GetCVESummaryForImage(repo, ref string) (cvemodel.ImageCVESummary, error)
 	GetCVESummaryForImageMedia(repo, digest, mediaType string) (cvemodel.ImageCVESummary, error)
+	GetLicensesForImage(repo, ref string) ([]cvemodel.License, error)
+	GetImageListForLicense(repo string, licenses []string) ([]cvemodel.TagInfo, error)
+	ScanImageLicenses(repo, ref string) error
+	RescanImage(repo, ref string) error
 	CompareSeverities(severity1, severity2 string) int
 	UpdateDB() error
 }
 
 type Scanner interface {
 	ScanImage(image string) (map[string]cvemodel.CVE, error)
+	RescanImage(image string) (map[string]cvemodel.CVE, error)
+	ScanImageLicenses(image string) ([]cvemodel.License, error)
+	GetCachedImageLicenses(image string) ([]cvemodel.License, error)
 	IsImageFormatScannable(repo, ref string) (bool, error)
 	IsImageMediaScannable(repo, digestStr, mediaType string) (bool, error)
 	CompareSeverities(severity1, severity2 string) int
@@ -103,6 +110,102 @@ func (cveinfo BaseCveInfo) GetImageListForCVE(repo, cveID string) ([]cvemodel.Ta
 	return imgList, nil
 }
 
+// GetLicensesForImage returns the licenses found by the background license scans,
+// ErrLicenseScanPending is returned if the image wasn't scanned yet.
+func (cveinfo BaseCveInfo) GetLicensesForImage(repo, ref string) ([]cvemodel.License, error) {
+	isValidImage, err := cveinfo.Scanner.IsImageFormatScannable(repo, ref)
+	if !isValidImage {
+		return []cvemodel.License{}, err
+	}
+
+	return cveinfo.Scanner.GetCachedImageLicenses(zcommon.GetFullImageName(repo, ref))
+}
+
+// ScanImageLicenses scans the image for licenses, the results are then returned by the license queries.
+func (cveinfo BaseCveInfo) ScanImageLicenses(repo, ref string) error {
+	isValidImage, err := cveinfo.Scanner.IsImageFormatScannable(repo, ref)
+	if !isValidImage {
+		return err
+	}
+
+	_, err = cveinfo.Scanner.ScanImageLicenses(zcommon.GetFullImageName(repo, ref))
+
+	return err
+}
+
+// RescanImage scans the image again and refreshes the cached result, which can be outdated
+// if the CVE DB changed since the image was scanned.
+func (cveinfo BaseCveInfo) RescanImage(repo, ref string) error {
+	isValidImage, err := cveinfo.Scanner.IsImageFormatScannable(repo, ref)
+	if !isValidImage {
+		return err
+	}
+
+	_, err = cveinfo.Scanner.RescanImage(zcommon.GetFullImageName(repo, ref))
+
+	return err
+}
+
+// GetImageListForLicense returns the tags of the repo containing at least a package or file
+// under one of the given licenses, a license matches if its name contains the searched text.
+// Only the images already scanned by the background license scans are returned.
+func (cveinfo BaseCveInfo) GetImageListForLicense(repo string, licenses []string) ([]cvemodel.TagInfo, error) {
+	imgList := make([]cvemodel.TagInfo, 0)
+
+	repoMeta, err := cveinfo.MetaDB.GetRepoMeta(repo)
+	if err != nil {
+		cveinfo.Log.Error().Err(err).Str("repository", repo).Strs("licenses", licenses).
+			Msg("unable to get list of tags from repo")
+
+		return imgList, err
+	}
+
+	for tag, descriptor := range repoMeta.Tags {
+		switch descriptor.MediaType {
+		case ispec.MediaTypeImageManifest, ispec.MediaTypeImageIndex:
+			isScanableImage, err := cveinfo.Scanner.IsImageFormatScannable(repo, descriptor.Digest)
+			if !isScanableImage || err != nil {
+				cveinfo.Log.Info().Str("image", repo+":"+tag).Err(err).Msg("image is not scanable")
+
+				continue
+			}
+
+			imageLicenses, err := cveinfo.Scanner.GetCachedImageLicenses(zcommon.GetFullImageName(repo, tag))
+			if err != nil {
+				cveinfo.Log.Debug().Str("image", repo+":"+tag).Err(err).Msg("image licenses not available")
+
+				continue
+			}
+
+			if containsLicense(imageLicenses, licenses) {
+				imgList = append(imgList, cvemodel.TagInfo{
+					Tag: tag,
+					Descriptor: cvemodel.Descriptor{
+						Digest:    godigest.Digest(descriptor.Digest),
+						MediaType: descriptor.MediaType,
+					},
+				})
+			}
+		default:
+			cveinfo.Log.Error().Str("mediaType", descriptor.MediaType).Msg("media type not supported for scanning")
+		}
+	}
+
+	return imgList, nil
+}
+
+func containsLicense(imageLicenses []cvemodel.License, searchedLicenses []string) bool {
+	for _, license := range imageLicenses {
+		for _, searchedLicense := range searchedLicenses {
+			if strings.Contains(strings.ToUpper(license.Name), strings.ToUpper(searchedLicense)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (cveinfo BaseCveInfo) GetImageListWithCVEFixed(repo, cveID string) ([]cvemodel.TagInfo, error) {
 	repoMeta, err := cveinfo.MetaDB.GetRepoMeta(repo)
 	if err != nil {
@@ -319,10 +422,16 @@ func getConfigAndDigest(metaDB mTypes.MetaDB, manifestDigestStr string) (ispec.I
 	return configContent, manifestDigest, err
 }
 
-func filterCVEList(cveMap map[string]cvemodel.CVE, searchedCVE string, pageFinder *CvePageFinder) {
+func filterCVEList(cveMap map[string]cvemodel.CVE, searchedCVE string, fixStatus cvemodel.FixStatus,
+	pageFinder *CvePageFinder,
+) {
 	searchedCVE = strings.ToUpper(searchedCVE)
 
 	for _, cve := range cveMap {
+		if fixStatus != "" && cve.FixStatus() != fixStatus {
+			continue
+		}
+
 		if strings.Contains(strings.ToUpper(cve.Title), searchedCVE) ||
 			strings.Contains(strings.ToUpper(cve.ID), searchedCVE) {
 			pageFinder.Add(cve)
@@ -330,7 +439,11 @@ func filterCVEList(cveMap map[string]cvemodel.CVE, searchedCVE string, pageFinde
 	}
 }
 
-func (cveinfo BaseCveInfo) GetCVEListForImage(repo, ref string, searchedCVE string, pageInput cvemodel.PageInput) (
+// GetCVEListForImage returns a page of the CVEs affecting the image, an empty fixStatus returns CVEs
+// regardless of the availability of a fix.
+func (cveinfo BaseCveInfo) GetCVEListForImage(repo, ref string, searchedCVE string, fixStatus cvemodel.FixStatus,
+	pageInput cvemodel.PageInput,
+) (
 	[]cvemodel.CVE,
 	zcommon.PageInfo,
 	error,
@@ -352,7 +465,7 @@ func (cveinfo BaseCveInfo) GetCVEListForImage(repo, ref string, searchedCVE stri
 		return []cvemodel.CVE{}, zcommon.PageInfo{}, err
 	}
 
-	filterCVEList(cveMap, searchedCVE, pageFinder)
+	filterCVEList(cveMap, searchedCVE, fixStatus, pageFinder)
 
 	cveList, pageInfo := pageFinder.Page()
 
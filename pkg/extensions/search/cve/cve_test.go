@@ -5,9 +5,12 @@
 package cveinfo_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path"
@@ -36,6 +39,7 @@ import (
 	"zotregistry.io/zot/pkg/meta"
 	"zotregistry.io/zot/pkg/meta/boltdb"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage"
 	storageConstants "zotregistry.io/zot/pkg/storage/constants"
 	"zotregistry.io/zot/pkg/storage/local"
@@ -324,7 +328,7 @@ func TestImageFormat(t *testing.T) {
 
 		metrics := monitoring.NewMetricsServer(false, log)
 		defaultStore := local.NewImageStore(imgDir, false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 		storeController := storage.StoreController{DefaultStore: defaultStore}
 
 		params := boltdb.DBParameters{
@@ -1256,14 +1260,14 @@ func TestCVEStruct(t *testing.T) {
 		}
 
 		// Image is found
-		cveList, pageInfo, err := cveInfo.GetCVEListForImage("repo1", "0.1.0", "", pageInput)
+		cveList, pageInfo, err := cveInfo.GetCVEListForImage("repo1", "0.1.0", "", "", pageInput)
 		So(err, ShouldBeNil)
 		So(len(cveList), ShouldEqual, 1)
 		So(cveList[0].ID, ShouldEqual, "CVE1")
 		So(pageInfo.ItemCount, ShouldEqual, 1)
 		So(pageInfo.TotalCount, ShouldEqual, 1)
 
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "1.0.0", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "1.0.0", "", "", pageInput)
 		So(err, ShouldBeNil)
 		So(len(cveList), ShouldEqual, 3)
 		So(cveList[0].ID, ShouldEqual, "CVE2")
@@ -1272,7 +1276,7 @@ func TestCVEStruct(t *testing.T) {
 		So(pageInfo.ItemCount, ShouldEqual, 3)
 		So(pageInfo.TotalCount, ShouldEqual, 3)
 
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "1.0.1", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "1.0.1", "", "", pageInput)
 		So(err, ShouldBeNil)
 		So(len(cveList), ShouldEqual, 2)
 		So(cveList[0].ID, ShouldEqual, "CVE1")
@@ -1280,42 +1284,42 @@ func TestCVEStruct(t *testing.T) {
 		So(pageInfo.ItemCount, ShouldEqual, 2)
 		So(pageInfo.TotalCount, ShouldEqual, 2)
 
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "1.1.0", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "1.1.0", "", "", pageInput)
 		So(err, ShouldBeNil)
 		So(len(cveList), ShouldEqual, 1)
 		So(cveList[0].ID, ShouldEqual, "CVE3")
 		So(pageInfo.ItemCount, ShouldEqual, 1)
 		So(pageInfo.TotalCount, ShouldEqual, 1)
 
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo6", "1.0.0", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo6", "1.0.0", "", "", pageInput)
 		So(err, ShouldBeNil)
 		So(len(cveList), ShouldEqual, 0)
 		So(pageInfo.ItemCount, ShouldEqual, 0)
 		So(pageInfo.TotalCount, ShouldEqual, 0)
 
 		// Image is not scannable
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo2", "1.0.0", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo2", "1.0.0", "", "", pageInput)
 		So(err, ShouldEqual, zerr.ErrScanNotSupported)
 		So(len(cveList), ShouldEqual, 0)
 		So(pageInfo.ItemCount, ShouldEqual, 0)
 		So(pageInfo.TotalCount, ShouldEqual, 0)
 
 		// Tag is not found
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo3", "1.0.0", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo3", "1.0.0", "", "", pageInput)
 		So(err, ShouldEqual, zerr.ErrTagMetaNotFound)
 		So(len(cveList), ShouldEqual, 0)
 		So(pageInfo.ItemCount, ShouldEqual, 0)
 		So(pageInfo.TotalCount, ShouldEqual, 0)
 
 		// Manifest is not found
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo5", "nonexitent-manifest", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo5", "nonexitent-manifest", "", "", pageInput)
 		So(err, ShouldEqual, zerr.ErrManifestDataNotFound)
 		So(len(cveList), ShouldEqual, 0)
 		So(pageInfo.ItemCount, ShouldEqual, 0)
 		So(pageInfo.TotalCount, ShouldEqual, 0)
 
 		// Repo is not found
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo100", "1.0.0", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo100", "1.0.0", "", "", pageInput)
 		So(err, ShouldEqual, zerr.ErrRepoMetaNotFound)
 		So(len(cveList), ShouldEqual, 0)
 		So(pageInfo.ItemCount, ShouldEqual, 0)
@@ -1435,7 +1439,7 @@ func TestCVEStruct(t *testing.T) {
 		So(cveSummary.Count, ShouldEqual, 0)
 		So(cveSummary.MaxSeverity, ShouldEqual, "")
 
-		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "0.1.0", "", pageInput)
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo1", "0.1.0", "", "", pageInput)
 		So(err, ShouldNotBeNil)
 		So(cveList, ShouldBeEmpty)
 		So(pageInfo.ItemCount, ShouldEqual, 0)
@@ -1826,3 +1830,676 @@ func TestGetCVESummaryForImageMediaErrors(t *testing.T) {
 		})
 	})
 }
+
+func TestGetCVEListForImageFixFilter(t *testing.T) {
+	Convey("Filter CVEs by fix availability", t, func() {
+		storeController := storage.StoreController{}
+		storeController.DefaultStore = mocks.MockedImageStore{}
+
+		log := log.NewLogger("debug", "")
+
+		cveInfo := cveinfo.NewCVEInfo(storeController, mocks.MetaDBMock{}, "", "", log)
+		cveInfo.Scanner = mocks.CveScannerMock{
+			IsImageFormatScannableFn: func(repo, ref string) (bool, error) {
+				return true, nil
+			},
+			ScanImageFn: func(image string) (map[string]cvemodel.CVE, error) {
+				return map[string]cvemodel.CVE{
+					"CVE1": {
+						ID:       "CVE1",
+						Severity: "HIGH",
+						PackageList: []cvemodel.Package{
+							{Name: "pkg1", InstalledVersion: "1.0.0", FixedVersion: "1.0.1"},
+						},
+					},
+					"CVE2": {
+						ID:       "CVE2",
+						Severity: "MEDIUM",
+						PackageList: []cvemodel.Package{
+							{Name: "pkg1", InstalledVersion: "1.0.0", FixedVersion: "1.0.1"},
+							{Name: "pkg2", InstalledVersion: "2.0.0"},
+						},
+					},
+					"CVE3": {
+						ID:       "CVE3",
+						Severity: "LOW",
+						PackageList: []cvemodel.Package{
+							{Name: "pkg3", InstalledVersion: "3.0.0", FixedVersion: cvemodel.FixedVersionNotSpecified},
+						},
+					},
+				}, nil
+			},
+		}
+
+		pageInput := cvemodel.PageInput{SortBy: cveinfo.SeverityDsc}
+
+		cveList, pageInfo, err := cveInfo.GetCVEListForImage("repo", "tag", "", "", pageInput)
+		So(err, ShouldBeNil)
+		So(pageInfo.TotalCount, ShouldEqual, 3)
+		So(cveList[0].HasFix(), ShouldBeTrue)
+		So(cveList[0].FixStatus(), ShouldEqual, cvemodel.FixStatusAvailable)
+		So(cveList[1].HasFix(), ShouldBeFalse)
+		So(cveList[1].FixStatus(), ShouldEqual, cvemodel.FixStatusPartial)
+		So(cveList[2].HasFix(), ShouldBeFalse)
+		So(cveList[2].FixStatus(), ShouldEqual, cvemodel.FixStatusUnavailable)
+
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo", "tag", "", cvemodel.FixStatusAvailable, pageInput)
+		So(err, ShouldBeNil)
+		So(pageInfo.TotalCount, ShouldEqual, 1)
+		So(cveList[0].ID, ShouldEqual, "CVE1")
+
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo", "tag", "", cvemodel.FixStatusPartial, pageInput)
+		So(err, ShouldBeNil)
+		So(pageInfo.TotalCount, ShouldEqual, 1)
+		So(cveList[0].ID, ShouldEqual, "CVE2")
+
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo", "tag", "", cvemodel.FixStatusUnavailable, pageInput)
+		So(err, ShouldBeNil)
+		So(pageInfo.TotalCount, ShouldEqual, 1)
+		So(cveList[0].ID, ShouldEqual, "CVE3")
+
+		cveList, pageInfo, err = cveInfo.GetCVEListForImage("repo", "tag", "CVE1", cvemodel.FixStatusUnavailable,
+			pageInput)
+		So(err, ShouldBeNil)
+		So(pageInfo.TotalCount, ShouldEqual, 0)
+		So(cveList, ShouldBeEmpty)
+
+		So(cvemodel.CVE{ID: "CVE4"}.HasFix(), ShouldBeFalse)
+		So(cvemodel.CVE{ID: "CVE4"}.FixStatus(), ShouldEqual, cvemodel.FixStatusUnavailable)
+	})
+}
+
+func TestGetUpgradeAdvice(t *testing.T) {
+	Convey("Upgrade advice for packages affected by CVEs", t, func() {
+		So(cveinfo.GetUpgradeAdvice(nil), ShouldBeEmpty)
+
+		advice := cveinfo.GetUpgradeAdvice([]cvemodel.CVE{{
+			ID: "CVE1",
+			PackageList: []cvemodel.Package{
+				{Name: "libc", InstalledVersion: "2.28-151.el8", FixedVersion: "2.28-164.el8_5"},
+			},
+		}})
+		So(advice, ShouldHaveLength, 1)
+		So(advice[0].RecommendedVersion, ShouldEqual, "2.28-164.el8_5")
+
+		cveList := []cvemodel.CVE{
+			{
+				ID: "CVE2",
+				PackageList: []cvemodel.Package{
+					{Name: "pkg1", InstalledVersion: "1.0.0", FixedVersion: "1.2.0"},
+					{Name: "pkg2", InstalledVersion: "2.0.0"},
+				},
+			},
+			{
+				ID: "CVE1",
+				PackageList: []cvemodel.Package{
+					{Name: "pkg1", InstalledVersion: "1.0.0", FixedVersion: "1.10.0"},
+					{Name: "libc", InstalledVersion: "2.28-151.el8", FixedVersion: "2.28-189.el8_6"},
+				},
+			},
+			{
+				ID: "CVE3",
+				PackageList: []cvemodel.Package{
+					{Name: "pkg1", InstalledVersion: "1.0.0", FixedVersion: "1.2.0"},
+					{Name: "libc", InstalledVersion: "2.28-151.el8", FixedVersion: "2.28-164.el8_5"},
+					{Name: "pkg3", InstalledVersion: "3.0.0", FixedVersion: cvemodel.FixedVersionNotSpecified},
+				},
+			},
+		}
+
+		advice = cveinfo.GetUpgradeAdvice(cveList)
+		So(advice, ShouldResemble, []cvemodel.PackageUpgrade{
+			{
+				Name:               "libc",
+				InstalledVersion:   "2.28-151.el8",
+				RecommendedVersion: "",
+				FixedVersions:      []string{"2.28-164.el8_5", "2.28-189.el8_6"},
+				CVEIDs:             []string{"CVE1", "CVE3"},
+			},
+			{
+				Name:               "pkg1",
+				InstalledVersion:   "1.0.0",
+				RecommendedVersion: "1.10.0",
+				FixedVersions:      []string{"1.2.0", "1.10.0"},
+				CVEIDs:             []string{"CVE1", "CVE2", "CVE3"},
+			},
+		})
+	})
+}
+
+func TestGetImageListForLicense(t *testing.T) {
+	Convey("Search images by license", t, func() {
+		storeController := storage.StoreController{}
+		storeController.DefaultStore = mocks.MockedImageStore{}
+
+		log := log.NewLogger("debug", "")
+
+		metaDB := mocks.MetaDBMock{
+			GetRepoMetaFn: func(repo string) (mTypes.RepoMetadata, error) {
+				return mTypes.RepoMetadata{
+					Name: repo,
+					Tags: map[string]mTypes.Descriptor{
+						"agpl":    {Digest: godigest.FromString("agpl").String(), MediaType: ispec.MediaTypeImageManifest},
+						"mit":     {Digest: godigest.FromString("mit").String(), MediaType: ispec.MediaTypeImageManifest},
+						"broken":  {Digest: godigest.FromString("broken").String(), MediaType: ispec.MediaTypeImageManifest},
+						"unknown": {Digest: godigest.FromString("unknown").String(), MediaType: "unknown"},
+					},
+				}, nil
+			},
+		}
+
+		cveInfo := cveinfo.NewCVEInfo(storeController, metaDB, "", "", log)
+		cveInfo.Scanner = mocks.CveScannerMock{
+			IsImageFormatScannableFn: func(repo, ref string) (bool, error) {
+				return true, nil
+			},
+			GetCachedImageLicensesFn: func(image string) ([]cvemodel.License, error) {
+				switch image {
+				case "repo:agpl":
+					return []cvemodel.License{{Name: "AGPL-3.0", PackageName: "pkg1"}}, nil
+				case "repo:mit":
+					return []cvemodel.License{{Name: "MIT", FilePath: "LICENSE"}}, nil
+				default:
+					return []cvemodel.License{}, zerr.ErrLicenseScanPending
+				}
+			},
+			ScanImageLicensesFn: func(image string) ([]cvemodel.License, error) {
+				if image == "repo:broken" {
+					return nil, zerr.ErrScanNotSupported
+				}
+
+				return []cvemodel.License{}, nil
+			},
+		}
+
+		licenses, err := cveInfo.GetLicensesForImage("repo", "agpl")
+		So(err, ShouldBeNil)
+		So(licenses, ShouldResemble, []cvemodel.License{{Name: "AGPL-3.0", PackageName: "pkg1"}})
+
+		_, err = cveInfo.GetLicensesForImage("repo", "broken")
+		So(err, ShouldEqual, zerr.ErrLicenseScanPending)
+
+		So(cveInfo.ScanImageLicenses("repo", "mit"), ShouldBeNil)
+		So(cveInfo.ScanImageLicenses("repo", "broken"), ShouldNotBeNil)
+
+		tagsInfo, err := cveInfo.GetImageListForLicense("repo", []string{"agpl"})
+		So(err, ShouldBeNil)
+		So(len(tagsInfo), ShouldEqual, 1)
+		So(tagsInfo[0].Tag, ShouldEqual, "agpl")
+
+		tagsInfo, err = cveInfo.GetImageListForLicense("repo", []string{"GPL", "MIT"})
+		So(err, ShouldBeNil)
+		So(len(tagsInfo), ShouldEqual, 2)
+
+		tagsInfo, err = cveInfo.GetImageListForLicense("repo", []string{"Apache-2.0"})
+		So(err, ShouldBeNil)
+		So(tagsInfo, ShouldBeEmpty)
+
+		metaDB.GetRepoMetaFn = func(repo string) (mTypes.RepoMetadata, error) {
+			return mTypes.RepoMetadata{}, zerr.ErrRepoMetaNotFound
+		}
+		cveInfo.MetaDB = metaDB
+
+		_, err = cveInfo.GetImageListForLicense("repo", []string{"MIT"})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestLicenseScanTaskGenerator(t *testing.T) {
+	Convey("Scan the images of every repo for licenses", t, func() {
+		log := log.NewLogger("debug", "")
+
+		metaDB := mocks.MetaDBMock{
+			GetMultipleRepoMetaFn: func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return []mTypes.RepoMetadata{
+					{
+						Name: "repo1",
+						Tags: map[string]mTypes.Descriptor{
+							"image": {Digest: godigest.FromString("image").String(), MediaType: ispec.MediaTypeImageManifest},
+							"index": {Digest: godigest.FromString("index").String(), MediaType: ispec.MediaTypeImageIndex},
+						},
+					},
+					{
+						Name: "repo2",
+						Tags: map[string]mTypes.Descriptor{
+							"broken":  {Digest: godigest.FromString("broken").String(), MediaType: ispec.MediaTypeImageManifest},
+							"unknown": {Digest: godigest.FromString("unknown").String(), MediaType: "unknown"},
+						},
+					},
+				}, nil
+			},
+		}
+
+		scannedImages := []string{}
+
+		cveInfo := mocks.CveInfoMock{
+			ScanImageLicensesFn: func(repo, reference string) error {
+				scannedImages = append(scannedImages, repo+":"+reference)
+
+				if reference == "broken" {
+					return zerr.ErrScanNotSupported
+				}
+
+				return nil
+			},
+		}
+
+		generator := cveinfo.NewLicenseScanTaskGenerator(metaDB, cveInfo, log)
+		So(generator.IsReady(), ShouldBeTrue)
+
+		for !generator.IsDone() {
+			task, err := generator.Next()
+			So(err, ShouldBeNil)
+
+			if task != nil {
+				So(task.DoWork(context.Background()), ShouldBeNil)
+			}
+		}
+
+		So(scannedImages, ShouldHaveLength, 3)
+		So(scannedImages, ShouldContain, "repo1:image")
+		So(scannedImages, ShouldContain, "repo1:index")
+		So(scannedImages, ShouldContain, "repo2:broken")
+
+		generator.Reset()
+		So(generator.IsDone(), ShouldBeFalse)
+
+		Convey("Listing the repos fails", func() {
+			metaDB.GetMultipleRepoMetaFn = func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return nil, zerr.ErrRepoMetaNotFound
+			}
+
+			generator := cveinfo.NewLicenseScanTaskGenerator(metaDB, cveInfo, log)
+
+			_, err := generator.Next()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The task is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			task := cveinfo.NewLicenseScanTask(cveInfo, mTypes.RepoMetadata{
+				Name: "repo",
+				Tags: map[string]mTypes.Descriptor{
+					"image": {Digest: godigest.FromString("image").String(), MediaType: ispec.MediaTypeImageManifest},
+				},
+			}, log)
+
+			So(task.DoWork(ctx), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCVENotifier(t *testing.T) {
+	Convey("Notify the images newly affected by CVEs", t, func() {
+		log := log.NewLogger("debug", "")
+
+		imageDigest := godigest.FromString("image").String()
+		otherDigest := godigest.FromString("other").String()
+
+		metaDB := mocks.MetaDBMock{
+			GetMultipleRepoMetaFn: func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return []mTypes.RepoMetadata{
+					{
+						Name: "repo1",
+						Tags: map[string]mTypes.Descriptor{
+							"1.0":    {Digest: imageDigest, MediaType: ispec.MediaTypeImageManifest},
+							"latest": {Digest: imageDigest, MediaType: ispec.MediaTypeImageManifest},
+							"sig":    {Digest: otherDigest, MediaType: "unknown"},
+						},
+					},
+					{
+						Name: "repo2",
+						Tags: map[string]mTypes.Descriptor{
+							"broken": {Digest: otherDigest, MediaType: ispec.MediaTypeImageManifest},
+						},
+					},
+				}, nil
+			},
+		}
+
+		cves := []cvemodel.CVE{
+			{ID: "CVE-1", Severity: "CRITICAL", Title: "cve 1"},
+			{ID: "CVE-2", Severity: "LOW", Title: "cve 2"},
+		}
+
+		cveInfo := mocks.CveInfoMock{
+			GetCVEListForImageFn: func(repo, reference, searchedCVE string, fixStatus cvemodel.FixStatus,
+				pageInput cvemodel.PageInput,
+			) ([]cvemodel.CVE, zcommon.PageInfo, error) {
+				if repo == "repo2" {
+					return nil, zcommon.PageInfo{}, zerr.ErrScanNotSupported
+				}
+
+				return cves, zcommon.PageInfo{}, nil
+			},
+			CompareSeveritiesFn: func(severity1, severity2 string) int {
+				return trivy.Scanner{}.CompareSeverities(severity1, severity2)
+			},
+		}
+
+		notifications := []cveinfo.CVENotification{}
+		authHeaders := []string{}
+
+		server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			notification := cveinfo.CVENotification{}
+
+			if err := json.NewDecoder(request.Body).Decode(&notification); err != nil {
+				response.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			notifications = append(notifications, notification)
+			authHeaders = append(authHeaders, request.Header.Get("Authorization"))
+		}))
+		defer server.Close()
+
+		config := &extconf.CVENotificationsConfig{
+			Webhooks: []extconf.WebhookConfig{{URL: server.URL, Headers: map[string]string{"Authorization": "token"}}},
+		}
+
+		notifier := cveinfo.NewCVENotifier(config, metaDB, cveInfo, log)
+		ctx := context.Background()
+
+		// the first update only records the affected images
+		So(notifier.Notify(ctx), ShouldBeNil)
+		So(notifications, ShouldBeEmpty)
+
+		// nothing changed
+		So(notifier.Notify(ctx), ShouldBeNil)
+		So(notifications, ShouldBeEmpty)
+
+		// new CVEs, only the ones above the severity threshold are notified
+		cves = append(cves,
+			cvemodel.CVE{ID: "CVE-3", Severity: "HIGH", Title: "cve 3"},
+			cvemodel.CVE{ID: "CVE-4", Severity: "MEDIUM", Title: "cve 4"},
+		)
+
+		So(notifier.Notify(ctx), ShouldBeNil)
+		So(notifications, ShouldHaveLength, 1)
+		So(authHeaders[0], ShouldEqual, "token")
+		So(notifications[0].Severity, ShouldEqual, "HIGH")
+		So(notifications[0].Images, ShouldHaveLength, 1)
+		So(notifications[0].Images[0].Repo, ShouldEqual, "repo1")
+		So(notifications[0].Images[0].Digest, ShouldEqual, imageDigest)
+		So(notifications[0].Images[0].Tags, ShouldResemble, []string{"1.0", "latest"})
+		So(notifications[0].Images[0].CVEs, ShouldResemble, []cveinfo.NotifiedCVE{
+			{ID: "CVE-3", Severity: "HIGH", Title: "cve 3"},
+		})
+
+		// the same CVEs are not notified twice
+		So(notifier.Notify(ctx), ShouldBeNil)
+		So(notifications, ShouldHaveLength, 1)
+
+		Convey("Lower severity threshold", func() {
+			config.Severity = "MEDIUM"
+			notifier := cveinfo.NewCVENotifier(config, metaDB, cveInfo, log)
+
+			So(notifier.Notify(ctx), ShouldBeNil)
+
+			cves = append(cves, cvemodel.CVE{ID: "CVE-5", Severity: "MEDIUM", Title: "cve 5"})
+
+			So(notifier.Notify(ctx), ShouldBeNil)
+			So(notifications, ShouldHaveLength, 2)
+			So(notifications[1].Images[0].CVEs, ShouldHaveLength, 1)
+			So(notifications[1].Images[0].CVEs[0].ID, ShouldEqual, "CVE-5")
+		})
+
+		Convey("The webhook fails", func() {
+			failingServer := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+				response.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer failingServer.Close()
+
+			config.Webhooks = append([]extconf.WebhookConfig{{URL: failingServer.URL}}, config.Webhooks...)
+			notifier := cveinfo.NewCVENotifier(config, metaDB, cveInfo, log)
+
+			So(notifier.Notify(ctx), ShouldBeNil)
+
+			cves = append(cves, cvemodel.CVE{ID: "CVE-5", Severity: "CRITICAL", Title: "cve 5"})
+
+			// the other webhooks are still notified
+			So(notifier.Notify(ctx), ShouldWrap, zerr.ErrWebhookFailed)
+			So(notifications, ShouldHaveLength, 2)
+		})
+
+		Convey("Listing the repos fails", func() {
+			metaDB.GetMultipleRepoMetaFn = func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return nil, zerr.ErrRepoMetaNotFound
+			}
+
+			notifier := cveinfo.NewCVENotifier(config, metaDB, cveInfo, log)
+			So(notifier.Notify(ctx), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestRescanTaskGenerator(t *testing.T) {
+	Convey("Rescan the images of every repo for CVEs", t, func() {
+		log := log.NewLogger("debug", "")
+
+		imageDigest := godigest.FromString("image").String()
+		indexDigest := godigest.FromString("index").String()
+		downloadCount := 0
+
+		metaDB := mocks.MetaDBMock{
+			GetMultipleRepoMetaFn: func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return []mTypes.RepoMetadata{
+					{
+						Name: "repo1",
+						Tags: map[string]mTypes.Descriptor{
+							"image": {Digest: imageDigest, MediaType: ispec.MediaTypeImageManifest},
+							"index": {Digest: indexDigest, MediaType: ispec.MediaTypeImageIndex},
+							"sig":   {Digest: godigest.FromString("sig").String(), MediaType: "unknown"},
+						},
+						Statistics: map[string]mTypes.DescriptorStatistics{
+							imageDigest: {DownloadCount: downloadCount},
+						},
+					},
+					{
+						Name: "repo2",
+						Tags: map[string]mTypes.Descriptor{
+							"broken": {Digest: imageDigest, MediaType: ispec.MediaTypeImageManifest},
+						},
+					},
+				}, nil
+			},
+		}
+
+		rescannedImages := []string{}
+
+		cveInfo := mocks.CveInfoMock{
+			RescanImageFn: func(repo, reference string) error {
+				rescannedImages = append(rescannedImages, repo+":"+reference)
+
+				if reference == "broken" {
+					return zerr.ErrScanNotSupported
+				}
+
+				return nil
+			},
+		}
+
+		runGenerator := func(generator scheduler.TaskGenerator) {
+			for !generator.IsDone() {
+				task, err := generator.Next()
+				So(err, ShouldBeNil)
+
+				if task != nil {
+					So(task.DoWork(context.Background()), ShouldBeNil)
+				}
+			}
+
+			generator.Reset()
+		}
+
+		Convey("All images", func() {
+			generator := cveinfo.NewRescanTaskGenerator(metaDB, cveInfo, false, log)
+			So(generator.IsReady(), ShouldBeTrue)
+
+			runGenerator(generator)
+			So(rescannedImages, ShouldHaveLength, 3)
+			So(rescannedImages, ShouldContain, "repo1:image")
+			So(rescannedImages, ShouldContain, "repo1:index")
+			So(rescannedImages, ShouldContain, "repo2:broken")
+
+			runGenerator(generator)
+			So(rescannedImages, ShouldHaveLength, 6)
+		})
+
+		Convey("Only pulled images", func() {
+			generator := cveinfo.NewRescanTaskGenerator(metaDB, cveInfo, true, log)
+
+			// the first round rescans every image
+			runGenerator(generator)
+			So(rescannedImages, ShouldHaveLength, 3)
+
+			// nothing was pulled
+			runGenerator(generator)
+			So(rescannedImages, ShouldHaveLength, 3)
+
+			downloadCount = 1
+
+			runGenerator(generator)
+			So(rescannedImages, ShouldHaveLength, 4)
+			So(rescannedImages[3], ShouldEqual, "repo1:image")
+
+			runGenerator(generator)
+			So(rescannedImages, ShouldHaveLength, 4)
+		})
+
+		Convey("Listing the repos fails", func() {
+			metaDB.GetMultipleRepoMetaFn = func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return nil, zerr.ErrRepoMetaNotFound
+			}
+
+			generator := cveinfo.NewRescanTaskGenerator(metaDB, cveInfo, false, log)
+
+			_, err := generator.Next()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The task is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			task := cveinfo.NewRescanTask(cveInfo, "repo1", []string{"image"}, log)
+			So(task.DoWork(ctx), ShouldNotBeNil)
+			So(rescannedImages, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Rescan an image", t, func() {
+		rescanned := false
+
+		cveInfo := cveinfo.BaseCveInfo{
+			Log: log.NewLogger("debug", ""),
+			Scanner: mocks.CveScannerMock{
+				IsImageFormatScannableFn: func(repo, reference string) (bool, error) {
+					return reference != "unscannable", nil
+				},
+				RescanImageFn: func(image string) (map[string]cvemodel.CVE, error) {
+					rescanned = true
+
+					return map[string]cvemodel.CVE{}, nil
+				},
+			},
+		}
+
+		So(cveInfo.RescanImage("repo", "unscannable"), ShouldBeNil)
+		So(rescanned, ShouldBeFalse)
+
+		So(cveInfo.RescanImage("repo", "tag"), ShouldBeNil)
+		So(rescanned, ShouldBeTrue)
+	})
+}
+
+func TestRepoFilterScanner(t *testing.T) {
+	Convey("Only the images of the configured repos are scanned", t, func() {
+		scanner := mocks.CveScannerMock{
+			IsImageFormatScannableFn: func(repo, reference string) (bool, error) {
+				return true, nil
+			},
+			IsImageMediaScannableFn: func(repo, digest, mediaType string) (bool, error) {
+				return true, nil
+			},
+		}
+
+		log := log.NewLogger("debug", "")
+
+		isScanned := func(scanner cveinfo.Scanner, repo string) bool {
+			byRef, err := scanner.IsImageFormatScannable(repo, "tag")
+			So(err, ShouldBeNil)
+
+			byMedia, err := scanner.IsImageMediaScannable(repo, "digest", ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+			So(byMedia, ShouldEqual, byRef)
+
+			return byRef
+		}
+
+		Convey("Include", func() {
+			filter := cveinfo.NewRepoFilterScanner(scanner, &extconf.CVERepositoriesConfig{
+				Include: []string{"prod/**", "base"},
+			}, log)
+
+			So(isScanned(filter, "prod/app"), ShouldBeTrue)
+			So(isScanned(filter, "prod/team/app"), ShouldBeTrue)
+			So(isScanned(filter, "base"), ShouldBeTrue)
+			So(isScanned(filter, "models/llm"), ShouldBeFalse)
+		})
+
+		Convey("Exclude", func() {
+			filter := cveinfo.NewRepoFilterScanner(scanner, &extconf.CVERepositoriesConfig{
+				Exclude: []string{"models/**", "[bad"},
+			}, log)
+
+			So(isScanned(filter, "prod/app"), ShouldBeTrue)
+			So(isScanned(filter, "models/llm"), ShouldBeFalse)
+		})
+
+		Convey("Exclude takes precedence", func() {
+			filter := cveinfo.NewRepoFilterScanner(scanner, &extconf.CVERepositoriesConfig{
+				Include: []string{"**"},
+				Exclude: []string{"models/**"},
+			}, log)
+
+			So(isScanned(filter, "prod/app"), ShouldBeTrue)
+			So(isScanned(filter, "models/llm"), ShouldBeFalse)
+		})
+
+		Convey("CVE queries skip the excluded repos", func() {
+			scanned := false
+			scanner.ScanImageFn = func(image string) (map[string]cvemodel.CVE, error) {
+				scanned = true
+
+				return map[string]cvemodel.CVE{"CVE-1": {ID: "CVE-1", Severity: "HIGH"}}, nil
+			}
+
+			cveInfo := cveinfo.BaseCveInfo{
+				Log: log,
+				Scanner: cveinfo.NewRepoFilterScanner(scanner, &extconf.CVERepositoriesConfig{
+					Exclude: []string{"models/**"},
+				}, log),
+			}
+
+			cves, _, err := cveInfo.GetCVEListForImage("models/llm", "tag", "", "", cvemodel.PageInput{})
+			So(err, ShouldBeNil)
+			So(cves, ShouldBeEmpty)
+			So(scanned, ShouldBeFalse)
+
+			cves, _, err = cveInfo.GetCVEListForImage("prod/app", "tag", "", "", cvemodel.PageInput{})
+			So(err, ShouldBeNil)
+			So(cves, ShouldHaveLength, 1)
+			So(scanned, ShouldBeTrue)
+		})
+	})
+}
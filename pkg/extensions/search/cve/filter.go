@@ -0,0 +1,68 @@
+package cveinfo
+
+import (
+	glob "github.com/bmatcuk/doublestar/v4"
+
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+// repoFilterScanner only scans the images of the repos matching the repositories config, the images of
+// the other repos are reported as not scannable, like images of unsupported formats.
+// Every scan (queries, background scans and notifications) checks if the image is scannable first.
+type repoFilterScanner struct {
+	Scanner
+	include []string
+	exclude []string
+	log     log.Logger
+}
+
+func NewRepoFilterScanner(scanner Scanner, config *extconf.CVERepositoriesConfig, log log.Logger) Scanner {
+	return repoFilterScanner{
+		Scanner: scanner,
+		include: config.Include,
+		exclude: config.Exclude,
+		log:     log,
+	}
+}
+
+func (scanner repoFilterScanner) IsImageFormatScannable(repo, ref string) (bool, error) {
+	if !scanner.isRepoScanned(repo) {
+		return false, nil
+	}
+
+	return scanner.Scanner.IsImageFormatScannable(repo, ref)
+}
+
+func (scanner repoFilterScanner) IsImageMediaScannable(repo, digestStr, mediaType string) (bool, error) {
+	if !scanner.isRepoScanned(repo) {
+		return false, nil
+	}
+
+	return scanner.Scanner.IsImageMediaScannable(repo, digestStr, mediaType)
+}
+
+func (scanner repoFilterScanner) isRepoScanned(repo string) bool {
+	if scanner.matchesAny(scanner.exclude, repo) {
+		return false
+	}
+
+	return len(scanner.include) == 0 || scanner.matchesAny(scanner.include, repo)
+}
+
+func (scanner repoFilterScanner) matchesAny(patterns []string, repo string) bool {
+	for _, pattern := range patterns {
+		matched, err := glob.Match(pattern, repo)
+		if err != nil {
+			scanner.log.Error().Err(err).Str("pattern", pattern).Msg("error while parsing glob pattern, skipping it...")
+
+			continue
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package cveinfo
+
+import (
+	"context"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+// NewLicenseScanTaskGenerator returns a generator of tasks scanning the images of every repo for licenses,
+// so that the license queries only read the results of previous scans. Images already scanned are skipped.
+func NewLicenseScanTaskGenerator(metaDB mTypes.MetaDB, cveInfo CveInfo, log log.Logger) scheduler.TaskGenerator {
+	return &licenseScanTaskGenerator{
+		repos:     []mTypes.RepoMetadata{},
+		metaDB:    metaDB,
+		cveInfo:   cveInfo,
+		repoIndex: -1,
+		log:       log,
+	}
+}
+
+type licenseScanTaskGenerator struct {
+	repos     []mTypes.RepoMetadata
+	metaDB    mTypes.MetaDB
+	cveInfo   CveInfo
+	repoIndex int
+	done      bool
+	log       log.Logger
+}
+
+func (gen *licenseScanTaskGenerator) Next() (scheduler.Task, error) {
+	if len(gen.repos) == 0 {
+		ctx := context.Background()
+
+		repos, err := gen.metaDB.GetMultipleRepoMeta(ctx, func(repoMeta mTypes.RepoMetadata) bool {
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		gen.repos = repos
+	}
+
+	gen.repoIndex++
+
+	if gen.repoIndex >= len(gen.repos) {
+		gen.done = true
+
+		gen.log.Info().Msg("finished generating tasks for scanning images for licenses")
+
+		return nil, nil
+	}
+
+	return NewLicenseScanTask(gen.cveInfo, gen.repos[gen.repoIndex], gen.log), nil
+}
+
+func (gen *licenseScanTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *licenseScanTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *licenseScanTaskGenerator) Reset() {
+	gen.done = false
+	gen.repoIndex = -1
+	gen.repos = []mTypes.RepoMetadata{}
+
+	gen.log.Info().Msg("finished resetting task generator for scanning images for licenses")
+}
+
+type licenseScanTask struct {
+	cveInfo CveInfo
+	repo    mTypes.RepoMetadata
+	log     log.Logger
+}
+
+func NewLicenseScanTask(cveInfo CveInfo, repo mTypes.RepoMetadata, log log.Logger) *licenseScanTask {
+	return &licenseScanTask{cveInfo, repo, log}
+}
+
+// DoWork scans the tagged images of the repo, a failed scan doesn't stop the scans of the other images.
+func (scanTask *licenseScanTask) DoWork(ctx context.Context) error {
+	for tag, descriptor := range scanTask.repo.Tags {
+		if descriptor.MediaType != ispec.MediaTypeImageManifest && descriptor.MediaType != ispec.MediaTypeImageIndex {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := scanTask.cveInfo.ScanImageLicenses(scanTask.repo.Name, tag)
+		if err != nil {
+			scanTask.log.Error().Err(err).Str("image", scanTask.repo.Name+":"+tag).
+				Msg("unable to scan image for licenses")
+		}
+	}
+
+	return nil
+}
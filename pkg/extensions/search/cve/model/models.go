@@ -20,6 +20,46 @@ type CVE struct {
 	PackageList []Package `json:"PackageList"`
 }
 
+// FixedVersionNotSpecified is reported by scanners for packages without a version fixing the CVE.
+const FixedVersionNotSpecified = "Not Specified"
+
+// FixStatus tells if a CVE can be remediated by upgrading the packages it affects.
+type FixStatus string
+
+const (
+	// every affected package has a version fixing the CVE.
+	FixStatusAvailable FixStatus = "AVAILABLE"
+	// only some of the affected packages have a version fixing the CVE.
+	FixStatusPartial FixStatus = "PARTIAL"
+	// none of the affected packages has a version fixing the CVE.
+	FixStatusUnavailable FixStatus = "UNAVAILABLE"
+)
+
+func (cve CVE) FixStatus() FixStatus {
+	fixedPackages := 0
+
+	for _, pkg := range cve.PackageList {
+		if pkg.HasFix() {
+			fixedPackages++
+		}
+	}
+
+	switch {
+	case fixedPackages == 0:
+		return FixStatusUnavailable
+	case fixedPackages < len(cve.PackageList):
+		return FixStatusPartial
+	default:
+		return FixStatusAvailable
+	}
+}
+
+// HasFix returns true if every package affected by the CVE has a version in which the CVE is fixed,
+// CVEs fixed only in some of the affected packages are not considered fixed.
+func (cve CVE) HasFix() bool {
+	return cve.FixStatus() == FixStatusAvailable
+}
+
 //nolint:tagliatelle // graphQL schema
 type Package struct {
 	Name             string `json:"Name"`
@@ -27,6 +67,34 @@ type Package struct {
 	FixedVersion     string `json:"FixedVersion"`
 }
 
+// HasFix returns true if there is a version of the package in which the CVE is fixed.
+func (pkg Package) HasFix() bool {
+	return pkg.FixedVersion != "" && pkg.FixedVersion != FixedVersionNotSpecified
+}
+
+// PackageUpgrade is the advice for upgrading a package affected by CVEs which have a fix.
+type PackageUpgrade struct {
+	Name             string
+	InstalledVersion string
+	// the version fixing all the fixable CVEs of the package, empty if the fixed versions can't be compared
+	RecommendedVersion string
+	// the minimum versions fixing each of the CVEs
+	FixedVersions []string
+	// the CVEs fixed by the upgrade
+	CVEIDs []string
+}
+
+// License is a license detected in an image, either declared by a package or found in a license file.
+//
+//nolint:tagliatelle // graphQL schema
+type License struct {
+	Name        string `json:"Name"`
+	Category    string `json:"Category"`
+	Severity    string `json:"Severity"`
+	PackageName string `json:"PackageName"`
+	FilePath    string `json:"FilePath"`
+}
+
 const (
 	None = iota
 	Low
@@ -0,0 +1,236 @@
+package cveinfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	cvemodel "zotregistry.io/zot/pkg/extensions/search/cve/model"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+)
+
+const (
+	defaultNotificationSeverity = "HIGH"
+	webhookTimeout              = 30 * time.Second
+)
+
+// CVENotification is the payload posted to the webhooks, it lists the images newly affected by CVEs.
+type CVENotification struct {
+	Time     time.Time       `json:"time"`
+	Severity string          `json:"severity"`
+	Images   []AffectedImage `json:"images"`
+}
+
+type AffectedImage struct {
+	Repo   string        `json:"repo"`
+	Digest string        `json:"digest"`
+	Tags   []string      `json:"tags"`
+	CVEs   []NotifiedCVE `json:"cves"`
+}
+
+type NotifiedCVE struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+}
+
+// CVENotifier diffs the CVEs affecting the stored images against the previous CVE DB update,
+// and posts the images newly affected by CVEs to the configured webhooks.
+// The first diff only records the affected images, and so do the diffs for images pushed since the previous one,
+// only the CVEs found by a CVE DB update in images already scanned with the previous CVE DB are notified.
+type CVENotifier struct {
+	webhooks   []extconf.WebhookConfig
+	severity   string
+	metaDB     mTypes.MetaDB
+	cveInfo    CveInfo
+	httpClient *http.Client
+	// CVE ids affecting each image (repo@digest) after the previous CVE DB update, nil before the first one
+	affected map[string]map[string]bool
+	log      log.Logger
+}
+
+func NewCVENotifier(config *extconf.CVENotificationsConfig, metaDB mTypes.MetaDB, cveInfo CveInfo,
+	log log.Logger,
+) *CVENotifier {
+	severity := config.Severity
+	if severity == "" {
+		severity = defaultNotificationSeverity
+	}
+
+	return &CVENotifier{
+		webhooks:   config.Webhooks,
+		severity:   severity,
+		metaDB:     metaDB,
+		cveInfo:    cveInfo,
+		httpClient: &http.Client{Timeout: webhookTimeout},
+		log:        log,
+	}
+}
+
+// Notify is called after each CVE DB update, it scans the stored images and notifies the newly affected ones.
+func (notifier *CVENotifier) Notify(ctx context.Context) error {
+	images, affected, err := notifier.diff(ctx)
+	if err != nil {
+		return err
+	}
+
+	notifier.affected = affected
+
+	if len(images) == 0 {
+		notifier.log.Info().Msg("no image is newly affected by CVEs")
+
+		return nil
+	}
+
+	notification := CVENotification{
+		Time:     time.Now(),
+		Severity: notifier.severity,
+		Images:   images,
+	}
+
+	notifier.log.Info().Int("images", len(images)).Msg("notifying images newly affected by CVEs")
+
+	var lastErr error
+
+	// a failing webhook doesn't prevent notifying the others
+	for _, webhook := range notifier.webhooks {
+		if err := notifier.post(ctx, webhook, notification); err != nil {
+			notifier.log.Error().Err(err).Str("url", webhook.URL).Msg("unable to send CVE notification")
+
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// diff returns the images newly affected by CVEs, and the CVEs affecting each image.
+func (notifier *CVENotifier) diff(ctx context.Context) ([]AffectedImage, map[string]map[string]bool, error) {
+	repos, err := notifier.metaDB.GetMultipleRepoMeta(ctx, func(repoMeta mTypes.RepoMetadata) bool {
+		return true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	affected := map[string]map[string]bool{}
+	newlyAffected := map[string]*AffectedImage{}
+
+	for _, repoMeta := range repos {
+		for tag, descriptor := range repoMeta.Tags {
+			if descriptor.MediaType != ispec.MediaTypeImageManifest && descriptor.MediaType != ispec.MediaTypeImageIndex {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+
+			image := repoMeta.Name + "@" + descriptor.Digest
+
+			if affectedImage, ok := newlyAffected[image]; ok {
+				affectedImage.Tags = append(affectedImage.Tags, tag)
+
+				continue
+			}
+
+			if _, ok := affected[image]; ok {
+				continue
+			}
+
+			cveList, _, err := notifier.cveInfo.GetCVEListForImage(repoMeta.Name, tag, "", "", cvemodel.PageInput{})
+			if err != nil {
+				notifier.log.Info().Err(err).Str("image", repoMeta.Name+":"+tag).Msg("unable to scan image for CVEs")
+
+				continue
+			}
+
+			cveIDs := map[string]bool{}
+			newCVEs := []NotifiedCVE{}
+
+			for _, cve := range cveList {
+				if notifier.cveInfo.CompareSeverities(cve.Severity, notifier.severity) > 0 {
+					continue
+				}
+
+				cveIDs[cve.ID] = true
+
+				previousCVEs, scanned := notifier.affected[image]
+				if scanned && !previousCVEs[cve.ID] {
+					newCVEs = append(newCVEs, NotifiedCVE{ID: cve.ID, Severity: cve.Severity, Title: cve.Title})
+				}
+			}
+
+			affected[image] = cveIDs
+
+			if len(newCVEs) > 0 {
+				newlyAffected[image] = &AffectedImage{
+					Repo:   repoMeta.Name,
+					Digest: descriptor.Digest,
+					Tags:   []string{tag},
+					CVEs:   newCVEs,
+				}
+			}
+		}
+	}
+
+	images := make([]AffectedImage, 0, len(newlyAffected))
+
+	for _, affectedImage := range newlyAffected {
+		sort.Strings(affectedImage.Tags)
+
+		images = append(images, *affectedImage)
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Repo != images[j].Repo {
+			return images[i].Repo < images[j].Repo
+		}
+
+		return images[i].Digest < images[j].Digest
+	})
+
+	return images, affected, nil
+}
+
+func (notifier *CVENotifier) post(ctx context.Context, webhook extconf.WebhookConfig,
+	notification CVENotification,
+) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := notifier.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned status code %d", zerr.ErrWebhookFailed, webhook.URL, resp.StatusCode)
+	}
+
+	return nil
+}
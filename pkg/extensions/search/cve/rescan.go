@@ -0,0 +1,141 @@
+package cveinfo
+
+import (
+	"context"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+// NewRescanTaskGenerator returns a generator of tasks rescanning the images of every repo for CVEs,
+// refreshing the scan results before they are queried. With pulledOnly only the images pulled since
+// the previous round of rescans are rescanned, the first round rescans every image.
+func NewRescanTaskGenerator(metaDB mTypes.MetaDB, cveInfo CveInfo, pulledOnly bool,
+	log log.Logger,
+) scheduler.TaskGenerator {
+	return &rescanTaskGenerator{
+		repos:      []mTypes.RepoMetadata{},
+		metaDB:     metaDB,
+		cveInfo:    cveInfo,
+		pulledOnly: pulledOnly,
+		repoIndex:  -1,
+		log:        log,
+	}
+}
+
+type rescanTaskGenerator struct {
+	repos      []mTypes.RepoMetadata
+	metaDB     mTypes.MetaDB
+	cveInfo    CveInfo
+	pulledOnly bool
+	// download count of each image (repo@digest) at the previous round of rescans, nil before the first one
+	downloadCounts map[string]int
+	// download counts of the current round of rescans
+	newDownloadCounts map[string]int
+	repoIndex         int
+	done              bool
+	log               log.Logger
+}
+
+func (gen *rescanTaskGenerator) Next() (scheduler.Task, error) {
+	if len(gen.repos) == 0 {
+		ctx := context.Background()
+
+		repos, err := gen.metaDB.GetMultipleRepoMeta(ctx, func(repoMeta mTypes.RepoMetadata) bool {
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		gen.repos = repos
+		gen.newDownloadCounts = map[string]int{}
+	}
+
+	gen.repoIndex++
+
+	if gen.repoIndex >= len(gen.repos) {
+		gen.done = true
+		gen.downloadCounts = gen.newDownloadCounts
+
+		gen.log.Info().Msg("finished generating tasks for rescanning images for CVEs")
+
+		return nil, nil
+	}
+
+	repoMeta := gen.repos[gen.repoIndex]
+	tags := []string{}
+	digests := map[string]bool{}
+
+	for tag, descriptor := range repoMeta.Tags {
+		if descriptor.MediaType != ispec.MediaTypeImageManifest && descriptor.MediaType != ispec.MediaTypeImageIndex {
+			continue
+		}
+
+		image := repoMeta.Name + "@" + descriptor.Digest
+		downloadCount := repoMeta.Statistics[descriptor.Digest].DownloadCount
+
+		gen.newDownloadCounts[image] = downloadCount
+
+		// the tags of an image share its scan results
+		if digests[descriptor.Digest] {
+			continue
+		}
+
+		digests[descriptor.Digest] = true
+
+		// images pushed since the previous round are missing from the download counts, and have 0 downloads
+		if gen.pulledOnly && gen.downloadCounts != nil && gen.downloadCounts[image] == downloadCount {
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return NewRescanTask(gen.cveInfo, repoMeta.Name, tags, gen.log), nil
+}
+
+func (gen *rescanTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *rescanTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *rescanTaskGenerator) Reset() {
+	gen.done = false
+	gen.repoIndex = -1
+	gen.repos = []mTypes.RepoMetadata{}
+
+	gen.log.Info().Msg("finished resetting task generator for rescanning images for CVEs")
+}
+
+type rescanTask struct {
+	cveInfo CveInfo
+	repo    string
+	tags    []string
+	log     log.Logger
+}
+
+func NewRescanTask(cveInfo CveInfo, repo string, tags []string, log log.Logger) *rescanTask {
+	return &rescanTask{cveInfo, repo, tags, log}
+}
+
+// DoWork rescans the images, a failed scan doesn't stop the scans of the other images.
+func (scanTask *rescanTask) DoWork(ctx context.Context) error {
+	for _, tag := range scanTask.tags {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := scanTask.cveInfo.RescanImage(scanTask.repo, tag); err != nil {
+			scanTask.log.Error().Err(err).Str("image", scanTask.repo+":"+tag).Msg("unable to rescan image for CVEs")
+		}
+	}
+
+	return nil
+}
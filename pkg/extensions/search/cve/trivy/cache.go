@@ -34,3 +34,27 @@ func (cveCache *CveCache) Get(image string) map[string]cvemodel.CVE {
 func (cveCache *CveCache) Purge() {
 	cveCache.cache.Purge()
 }
+
+type LicenseCache struct {
+	cache *lru.Cache[string, []cvemodel.License]
+	log   log.Logger
+}
+
+func NewLicenseCache(size int, log log.Logger) *LicenseCache {
+	cache, _ := lru.New[string, []cvemodel.License](size)
+
+	return &LicenseCache{cache: cache, log: log}
+}
+
+func (licenseCache *LicenseCache) Add(image string, licenses []cvemodel.License) {
+	licenseCache.cache.Add(image, licenses)
+}
+
+func (licenseCache *LicenseCache) Get(image string) []cvemodel.License {
+	licenses, ok := licenseCache.cache.Get(image)
+	if !ok {
+		return nil
+	}
+
+	return licenses
+}
@@ -12,9 +12,11 @@ import (
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/commands/artifact"
 	"github.com/aquasecurity/trivy/pkg/commands/operation"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 	fanalTypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/javadb"
+	"github.com/aquasecurity/trivy/pkg/licensing"
 	"github.com/aquasecurity/trivy/pkg/types"
 	regTypes "github.com/google/go-containerregistry/pkg/v1/types"
 	godigest "github.com/opencontainers/go-digest"
@@ -30,6 +32,9 @@ import (
 	"zotregistry.io/zot/pkg/storage"
 )
 
+// licenseCacheDir is the directory, under the trivy cache directory, of the trivy cache used by license scans.
+const licenseCacheDir = "license"
+
 // getNewScanOptions sets trivy configuration values for our scans and returns them as
 // a trivy Options structure.
 func getNewScanOptions(dir, dbRepository, javaDBRepository string) *flag.Options {
@@ -65,6 +70,25 @@ func getNewScanOptions(dir, dbRepository, javaDBRepository string) *flag.Options
 	return &scanOptions
 }
 
+// getLicenseScanOptions changes the scan options so that trivy only detects the licenses
+// of the packages installed in the image, classified using the default trivy license categories.
+func getLicenseScanOptions(opts flag.Options) flag.Options {
+	opts.ScanOptions.Scanners = types.Scanners{types.LicenseScanner}
+	opts.LicenseOptions = flag.LicenseOptions{
+		LicenseConfidenceLevel: 0.9, //nolint:gomnd
+		LicenseCategories: map[fanalTypes.LicenseCategory][]string{
+			fanalTypes.CategoryForbidden:    licensing.ForbiddenLicenses,
+			fanalTypes.CategoryRestricted:   licensing.RestrictedLicenses,
+			fanalTypes.CategoryReciprocal:   licensing.ReciprocalLicenses,
+			fanalTypes.CategoryNotice:       licensing.NoticeLicenses,
+			fanalTypes.CategoryPermissive:   licensing.PermissiveLicenses,
+			fanalTypes.CategoryUnencumbered: licensing.UnencumberedLicenses,
+		},
+	}
+
+	return opts
+}
+
 type cveTrivyController struct {
 	DefaultCveConfig *flag.Options
 	SubCveConfig     map[string]*flag.Options
@@ -76,7 +100,9 @@ type Scanner struct {
 	storeController  storage.StoreController
 	log              log.Logger
 	dbLock           *sync.Mutex
+	licenseLock      *sync.Mutex
 	cache            *CveCache
+	licenseCache     *LicenseCache
 	dbRepository     string
 	javaDBRepository string
 }
@@ -118,7 +144,9 @@ func NewScanner(storeController storage.StoreController,
 		cveController:    cveController,
 		storeController:  storeController,
 		dbLock:           &sync.Mutex{},
-		cache:            NewCveCache(10000, log), //nolint:gomnd
+		licenseLock:      &sync.Mutex{},
+		cache:            NewCveCache(10000, log),     //nolint:gomnd
+		licenseCache:     NewLicenseCache(10000, log), //nolint:gomnd
 		dbRepository:     dbRepository,
 		javaDBRepository: javaDBRepository,
 	}
@@ -170,6 +198,35 @@ func (scanner Scanner) runTrivy(opts flag.Options) (types.Report, error) {
 	}
 	defer runner.Close(ctx)
 
+	return scanWithRunner(ctx, runner, opts)
+}
+
+// runTrivyLicenses runs a license only scan. Such scans don't need the trivy DB so they don't wait for
+// the DB updates, and they use their own trivy cache so they don't wait for the vulnerability scans either.
+func (scanner Scanner) runTrivyLicenses(opts flag.Options) (types.Report, error) {
+	ctx := context.Background()
+
+	scanner.licenseLock.Lock()
+	defer scanner.licenseLock.Unlock()
+
+	licenseTrivyCache, err := cache.NewFSCache(path.Join(opts.CacheDir, licenseCacheDir))
+	if err != nil {
+		return types.Report{}, err
+	}
+
+	// the runner closes the cache
+	runner, err := artifact.NewRunner(ctx, opts, artifact.WithCacheClient(licenseTrivyCache))
+	if err != nil {
+		licenseTrivyCache.Close()
+
+		return types.Report{}, err
+	}
+	defer runner.Close(ctx)
+
+	return scanWithRunner(ctx, runner, opts)
+}
+
+func scanWithRunner(ctx context.Context, runner artifact.Runner, opts flag.Options) (types.Report, error) {
 	report, err := runner.ScanImage(ctx, opts)
 	if err != nil {
 		return types.Report{}, err
@@ -303,6 +360,18 @@ func (scanner Scanner) isIndexScanable(digestStr string) (bool, error) {
 }
 
 func (scanner Scanner) ScanImage(image string) (map[string]cvemodel.CVE, error) {
+	return scanner.scanImage(image, scanner.scanManifest)
+}
+
+// RescanImage scans the image even if it was already scanned, and replaces the cached result,
+// the queries keep returning the previous result until the scan completes.
+func (scanner Scanner) RescanImage(image string) (map[string]cvemodel.CVE, error) {
+	return scanner.scanImage(image, scanner.rescanManifest)
+}
+
+func (scanner Scanner) scanImage(image string,
+	scanManifest func(repo, digest string) (map[string]cvemodel.CVE, error),
+) (map[string]cvemodel.CVE, error) {
 	var (
 		originalImageInput = image
 		digest             string
@@ -337,9 +406,9 @@ func (scanner Scanner) ScanImage(image string) (map[string]cvemodel.CVE, error)
 
 	switch mediaType {
 	case ispec.MediaTypeImageIndex:
-		cveIDMap, err = scanner.scanIndex(repo, digest)
+		cveIDMap, err = scanner.scanIndex(repo, digest, scanManifest)
 	default:
-		cveIDMap, err = scanner.scanManifest(repo, digest)
+		cveIDMap, err = scanManifest(repo, digest)
 	}
 
 	if err != nil {
@@ -356,6 +425,10 @@ func (scanner Scanner) scanManifest(repo, digest string) (map[string]cvemodel.CV
 		return cachedMap, nil
 	}
 
+	return scanner.rescanManifest(repo, digest)
+}
+
+func (scanner Scanner) rescanManifest(repo, digest string) (map[string]cvemodel.CVE, error) {
 	cveidMap := map[string]cvemodel.CVE{}
 	image := repo + "@" + digest
 
@@ -378,7 +451,7 @@ func (scanner Scanner) scanManifest(repo, digest string) (map[string]cvemodel.CV
 			if vulnerability.FixedVersion != "" {
 				fixedVersion = vulnerability.FixedVersion
 			} else {
-				fixedVersion = "Not Specified"
+				fixedVersion = cvemodel.FixedVersionNotSpecified
 			}
 
 			_, ok := cveidMap[vulnerability.VulnerabilityID]
@@ -427,7 +500,9 @@ func (scanner Scanner) scanManifest(repo, digest string) (map[string]cvemodel.CV
 	return cveidMap, nil
 }
 
-func (scanner Scanner) scanIndex(repo, digest string) (map[string]cvemodel.CVE, error) {
+func (scanner Scanner) scanIndex(repo, digest string,
+	scanManifest func(repo, digest string) (map[string]cvemodel.CVE, error),
+) (map[string]cvemodel.CVE, error) {
 	indexData, err := scanner.metaDB.GetIndexData(godigest.Digest(digest))
 	if err != nil {
 		return map[string]cvemodel.CVE{}, err
@@ -444,7 +519,7 @@ func (scanner Scanner) scanIndex(repo, digest string) (map[string]cvemodel.CVE,
 
 	for _, manifest := range indexContent.Manifests {
 		if isScannable, err := scanner.isManifestScanable(manifest.Digest.String()); isScannable && err == nil {
-			manifestCveIDMap, err := scanner.scanManifest(repo, manifest.Digest.String())
+			manifestCveIDMap, err := scanManifest(repo, manifest.Digest.String())
 			if err != nil {
 				return nil, err
 			}
@@ -458,6 +533,131 @@ func (scanner Scanner) scanIndex(repo, digest string) (map[string]cvemodel.CVE,
 	return indexCveIDMap, nil
 }
 
+// ScanImageLicenses returns the licenses of the packages and license files found in the image.
+// For multiarch images the licenses found in all the scannable manifests are returned.
+func (scanner Scanner) ScanImageLicenses(image string) ([]cvemodel.License, error) {
+	licenses, err := scanner.getImageLicenses(image, scanner.scanManifestLicenses)
+	if err != nil {
+		scanner.log.Error().Err(err).Str("image", image).Msg("unable to scan image for licenses")
+	}
+
+	return licenses, err
+}
+
+// GetCachedImageLicenses returns the licenses of an image which was already scanned, without scanning it.
+// ErrLicenseScanPending is returned if the image, or one of the manifests of a multiarch image, wasn't scanned yet.
+func (scanner Scanner) GetCachedImageLicenses(image string) ([]cvemodel.License, error) {
+	return scanner.getImageLicenses(image, scanner.getCachedManifestLicenses)
+}
+
+func (scanner Scanner) getImageLicenses(image string,
+	getManifestLicenses func(repo, digest string) ([]cvemodel.License, error),
+) ([]cvemodel.License, error) {
+	repo, ref, isTag := zcommon.GetImageDirAndReference(image)
+
+	var (
+		digest    = ref
+		mediaType string
+	)
+
+	if isTag {
+		imgDescriptor, err := mcommon.GetImageDescriptor(scanner.metaDB, repo, ref)
+		if err != nil {
+			return []cvemodel.License{}, err
+		}
+
+		digest = imgDescriptor.Digest
+		mediaType = imgDescriptor.MediaType
+	} else {
+		var found bool
+
+		found, mediaType = mcommon.FindMediaTypeForDigest(scanner.metaDB, godigest.Digest(ref))
+		if !found {
+			return []cvemodel.License{}, zerr.ErrManifestNotFound
+		}
+	}
+
+	if mediaType != ispec.MediaTypeImageIndex {
+		return getManifestLicenses(repo, digest)
+	}
+
+	indexData, err := scanner.metaDB.GetIndexData(godigest.Digest(digest))
+	if err != nil {
+		return []cvemodel.License{}, err
+	}
+
+	var indexContent ispec.Index
+
+	err = json.Unmarshal(indexData.IndexBlob, &indexContent)
+	if err != nil {
+		return []cvemodel.License{}, err
+	}
+
+	indexLicenses := []cvemodel.License{}
+	seen := map[cvemodel.License]bool{}
+
+	for _, manifest := range indexContent.Manifests {
+		if isScannable, err := scanner.isManifestScanable(manifest.Digest.String()); !isScannable || err != nil {
+			continue
+		}
+
+		licenses, err := getManifestLicenses(repo, manifest.Digest.String())
+		if err != nil {
+			return []cvemodel.License{}, err
+		}
+
+		for _, license := range licenses {
+			if !seen[license] {
+				seen[license] = true
+
+				indexLicenses = append(indexLicenses, license)
+			}
+		}
+	}
+
+	return indexLicenses, nil
+}
+
+func (scanner Scanner) getCachedManifestLicenses(repo, digest string) ([]cvemodel.License, error) {
+	if cachedLicenses := scanner.licenseCache.Get(digest); cachedLicenses != nil {
+		return cachedLicenses, nil
+	}
+
+	return []cvemodel.License{}, zerr.ErrLicenseScanPending
+}
+
+func (scanner Scanner) scanManifestLicenses(repo, digest string) ([]cvemodel.License, error) {
+	if cachedLicenses := scanner.licenseCache.Get(digest); cachedLicenses != nil {
+		return cachedLicenses, nil
+	}
+
+	licenses := []cvemodel.License{}
+	image := repo + "@" + digest
+
+	opts := getLicenseScanOptions(scanner.getTrivyOptions(image))
+
+	report, err := scanner.runTrivyLicenses(opts)
+	if err != nil {
+		return licenses, err
+	}
+
+	for _, result := range report.Results {
+		for _, detectedLicense := range result.Licenses {
+			licenses = append(licenses, cvemodel.License{
+				Name:        detectedLicense.Name,
+				Category:    string(detectedLicense.Category),
+				Severity:    detectedLicense.Severity,
+				PackageName: detectedLicense.PkgName,
+				FilePath:    detectedLicense.FilePath,
+			})
+		}
+	}
+
+	scanner.licenseCache.Add(digest, licenses)
+
+	return licenses, nil
+}
+
 // UpdateDB downloads the Trivy DB / Cache under the store root directory.
 func (scanner Scanner) UpdateDB() error {
 	// We need a lock as using multiple substores each with it's own DB
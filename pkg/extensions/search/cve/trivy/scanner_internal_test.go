@@ -4,7 +4,9 @@
 package trivy
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"os"
 	"path"
@@ -76,13 +78,13 @@ func TestMultipleStoragePath(t *testing.T) {
 		// Create ImageStore
 
 		firstStore := local.NewImageStore(firstRootDir, false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 		secondStore := local.NewImageStore(secondRootDir, false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 		thirdStore := local.NewImageStore(thirdRootDir, false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 		storeController := storage.StoreController{}
 
@@ -191,7 +193,7 @@ func TestTrivyLibraryErrors(t *testing.T) {
 
 		// Create ImageStore
 		store := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 		storeController := storage.StoreController{}
 		storeController.DefaultStore = store
@@ -409,7 +411,7 @@ func TestImageScannable(t *testing.T) {
 	metrics := monitoring.NewMetricsServer(false, log)
 
 	store := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+		storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 	storeController := storage.StoreController{}
 	storeController.DefaultStore = store
@@ -476,7 +478,7 @@ func TestDefaultTrivyDBUrl(t *testing.T) {
 
 		// Create ImageStore
 		store := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 		storeController := storage.StoreController{}
 		storeController.DefaultStore = store
@@ -552,7 +554,7 @@ func TestScanIndexErrors(t *testing.T) {
 
 			scanner := NewScanner(storeController, metaDB, "", "", log)
 
-			_, err := scanner.scanIndex("repo", "digest")
+			_, err := scanner.scanIndex("repo", "digest", scanner.scanManifest)
 			So(err, ShouldNotBeNil)
 		})
 
@@ -565,7 +567,7 @@ func TestScanIndexErrors(t *testing.T) {
 
 			scanner := NewScanner(storeController, metaDB, "", "", log)
 
-			_, err := scanner.scanIndex("repo", "digest")
+			_, err := scanner.scanIndex("repo", "digest", scanner.scanManifest)
 			So(err, ShouldNotBeNil)
 		})
 	})
@@ -651,3 +653,208 @@ func TestIsIndexScanableErrors(t *testing.T) {
 		})
 	})
 }
+
+func TestScanImageLicenses(t *testing.T) {
+	Convey("Scan images for licenses", t, func() {
+		rootDir := t.TempDir()
+
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		store := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
+
+		storeController := storage.StoreController{}
+		storeController.DefaultStore = store
+
+		image := createImageWithLicenses()
+		err := test.WriteImageToFileSystem(image, "repo", "image", storeController)
+		So(err, ShouldBeNil)
+
+		multiarch := CreateMultiarchWith().Images([]Image{createImageWithLicenses(), CreateRandomImage()}).Build()
+		err = test.WriteMultiArchImageToFileSystem(multiarch, "repo", "index", storeController)
+		So(err, ShouldBeNil)
+
+		boltDriver, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: rootDir})
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDriver, log)
+		So(err, ShouldBeNil)
+
+		err = meta.ParseStorage(metaDB, storeController, log)
+		So(err, ShouldBeNil)
+
+		// the trivy DB is not downloaded, license scans don't need it
+		scanner := NewScanner(storeController, metaDB, "", "", log)
+
+		Convey("Scan a manifest", func() {
+			_, err := scanner.GetCachedImageLicenses("repo:image")
+			So(err, ShouldEqual, zerr.ErrLicenseScanPending)
+
+			licenses, err := scanner.ScanImageLicenses("repo:image")
+			So(err, ShouldBeNil)
+			So(licenses, ShouldHaveLength, 1)
+			So(licenses[0].Name, ShouldEqual, "MIT")
+			So(licenses[0].PackageName, ShouldEqual, "musl")
+
+			cachedLicenses, err := scanner.GetCachedImageLicenses("repo:image")
+			So(err, ShouldBeNil)
+			So(cachedLicenses, ShouldResemble, licenses)
+
+			cachedLicenses, err = scanner.GetCachedImageLicenses("repo@" + image.DigestStr())
+			So(err, ShouldBeNil)
+			So(cachedLicenses, ShouldResemble, licenses)
+
+			// the license scans use their own trivy cache
+			_, err = os.Stat(path.Join(rootDir, "_trivy", licenseCacheDir))
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Scan an index", func() {
+			_, err := scanner.GetCachedImageLicenses("repo:index")
+			So(err, ShouldEqual, zerr.ErrLicenseScanPending)
+
+			// one of the manifests of the index is scanned
+			_, err = scanner.ScanImageLicenses("repo@" + multiarch.Images[0].DigestStr())
+			So(err, ShouldBeNil)
+
+			_, err = scanner.GetCachedImageLicenses("repo:index")
+			So(err, ShouldEqual, zerr.ErrLicenseScanPending)
+
+			licenses, err := scanner.ScanImageLicenses("repo:index")
+			So(err, ShouldBeNil)
+			So(licenses, ShouldHaveLength, 1)
+
+			cachedLicenses, err := scanner.GetCachedImageLicenses("repo@" + multiarch.DigestStr())
+			So(err, ShouldBeNil)
+			So(cachedLicenses, ShouldResemble, licenses)
+		})
+
+		Convey("Scan results are cached", func() {
+			digest := godigest.FromString("not-stored")
+			cachedLicenses := []model.License{{Name: "MIT", PackageName: "pkg"}}
+
+			scanner.licenseCache.Add(digest.String(), cachedLicenses)
+
+			licenses, err := scanner.scanManifestLicenses("repo", digest.String())
+			So(err, ShouldBeNil)
+			So(licenses, ShouldResemble, cachedLicenses)
+		})
+
+		Convey("Scan fails", func() {
+			digest := godigest.FromString("not-stored")
+
+			_, err := scanner.scanManifestLicenses("repo", digest.String())
+			So(err, ShouldNotBeNil)
+
+			// failed scans are not cached
+			_, err = scanner.getCachedManifestLicenses("repo", digest.String())
+			So(err, ShouldEqual, zerr.ErrLicenseScanPending)
+
+			_, err = scanner.ScanImageLicenses("repo:missing")
+			So(err, ShouldNotBeNil)
+
+			_, err = scanner.ScanImageLicenses("repo@" + digest.String())
+			So(err, ShouldEqual, zerr.ErrManifestNotFound)
+		})
+
+		Convey("Scan fails for a manifest of an index", func() {
+			metaDB := mocks.MetaDBMock{
+				GetRepoMetaFn: func(repo string) (mTypes.RepoMetadata, error) {
+					return mTypes.RepoMetadata{
+						Tags: map[string]mTypes.Descriptor{
+							"index": {Digest: multiarch.DigestStr(), MediaType: ispec.MediaTypeImageIndex},
+						},
+					}, nil
+				},
+				GetIndexDataFn: func(indexDigest godigest.Digest) (mTypes.IndexData, error) {
+					return multiarch.IndexData(), nil
+				},
+				GetManifestDataFn: func(manifestDigest godigest.Digest) (mTypes.ManifestData, error) {
+					return mTypes.ManifestData{ManifestBlob: []byte("{}")}, nil
+				},
+			}
+
+			// the manifests are not in the image store
+			scanner := NewScanner(storage.StoreController{DefaultStore: local.NewImageStore(t.TempDir(), false, false,
+				storageConstants.DefaultGCDelay, storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log,
+				metrics, nil, nil)}, metaDB, "", "", log)
+
+			_, err := scanner.ScanImageLicenses("repo:index")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Index errors", func() {
+			metaDB := mocks.MetaDBMock{
+				GetRepoMetaFn: func(repo string) (mTypes.RepoMetadata, error) {
+					return mTypes.RepoMetadata{
+						Tags: map[string]mTypes.Descriptor{
+							"index": {Digest: multiarch.DigestStr(), MediaType: ispec.MediaTypeImageIndex},
+						},
+					}, nil
+				},
+				GetIndexDataFn: func(indexDigest godigest.Digest) (mTypes.IndexData, error) {
+					return mTypes.IndexData{}, zerr.ErrManifestDataNotFound
+				},
+			}
+
+			scanner := NewScanner(storeController, metaDB, "", "", log)
+
+			_, err := scanner.ScanImageLicenses("repo:index")
+			So(err, ShouldNotBeNil)
+
+			metaDB.GetIndexDataFn = func(indexDigest godigest.Digest) (mTypes.IndexData, error) {
+				return mTypes.IndexData{IndexBlob: []byte("bad index")}, nil
+			}
+
+			scanner = NewScanner(storeController, metaDB, "", "", log)
+
+			_, err = scanner.GetCachedImageLicenses("repo:index")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+// createImageWithLicenses returns an alpine based image with a single package installed, licensed under MIT.
+func createImageWithLicenses() Image {
+	files := map[string]string{
+		"etc/alpine-release":   "3.17.0\n",
+		"lib/apk/db/installed": "P:musl\nV:1.2.3-r4\nA:x86_64\nL:MIT\no:musl\n\n",
+	}
+
+	var tarBuffer bytes.Buffer
+
+	tarWriter := tar.NewWriter(&tarBuffer)
+
+	for _, name := range []string{"etc/alpine-release", "lib/apk/db/installed"} {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644, //nolint:gomnd
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		})
+		So(err, ShouldBeNil)
+
+		_, err = tarWriter.Write([]byte(files[name]))
+		So(err, ShouldBeNil)
+	}
+
+	So(tarWriter.Close(), ShouldBeNil)
+
+	var layerBuffer bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&layerBuffer)
+	_, err := gzipWriter.Write(tarBuffer.Bytes())
+	So(err, ShouldBeNil)
+	So(gzipWriter.Close(), ShouldBeNil)
+
+	return CreateImageWith().
+		LayerBlobs([][]byte{layerBuffer.Bytes()}).
+		ImageConfig(ispec.Image{
+			Platform: ispec.Platform{Architecture: "amd64", OS: "linux"},
+			RootFS: ispec.RootFS{
+				Type:    "layers",
+				DiffIDs: []godigest.Digest{godigest.FromBytes(tarBuffer.Bytes())},
+			},
+		}).Build()
+}
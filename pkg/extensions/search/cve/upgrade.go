@@ -0,0 +1,104 @@
+package cveinfo
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver"
+
+	cvemodel "zotregistry.io/zot/pkg/extensions/search/cve/model"
+)
+
+// GetUpgradeAdvice groups the fixable CVEs by the package installed in the image and returns, for each package,
+// the versions fixing them. Packages affected only by CVEs without a fix are not part of the advice.
+func GetUpgradeAdvice(cveList []cvemodel.CVE) []cvemodel.PackageUpgrade {
+	type packageKey struct {
+		name             string
+		installedVersion string
+	}
+
+	upgrades := map[packageKey]*cvemodel.PackageUpgrade{}
+
+	for _, cve := range cveList {
+		for _, pkg := range cve.PackageList {
+			if !pkg.HasFix() {
+				continue
+			}
+
+			key := packageKey{name: pkg.Name, installedVersion: pkg.InstalledVersion}
+
+			upgrade, ok := upgrades[key]
+			if !ok {
+				upgrade = &cvemodel.PackageUpgrade{
+					Name:             pkg.Name,
+					InstalledVersion: pkg.InstalledVersion,
+					FixedVersions:    []string{},
+					CVEIDs:           []string{},
+				}
+
+				upgrades[key] = upgrade
+			}
+
+			upgrade.FixedVersions = appendUnique(upgrade.FixedVersions, pkg.FixedVersion)
+			upgrade.CVEIDs = appendUnique(upgrade.CVEIDs, cve.ID)
+		}
+	}
+
+	advice := make([]cvemodel.PackageUpgrade, 0, len(upgrades))
+
+	for _, upgrade := range upgrades {
+		sort.Strings(upgrade.CVEIDs)
+		upgrade.RecommendedVersion = sortVersions(upgrade.FixedVersions)
+
+		advice = append(advice, *upgrade)
+	}
+
+	sort.Slice(advice, func(i, j int) bool {
+		if advice[i].Name != advice[j].Name {
+			return advice[i].Name < advice[j].Name
+		}
+
+		return advice[i].InstalledVersion < advice[j].InstalledVersion
+	})
+
+	return advice
+}
+
+// sortVersions sorts the versions ascending and returns the highest one. Versions using schemes which
+// can't be compared (distro specific revisions, lists of versions from several release branches)
+// are sorted alphabetically and no version is returned, unless there's only one.
+func sortVersions(versions []string) string {
+	if len(versions) == 1 {
+		return versions[0]
+	}
+
+	semvers := make([]*semver.Version, 0, len(versions))
+
+	for _, version := range versions {
+		semVersion, err := semver.NewVersion(version)
+		if err != nil {
+			sort.Strings(versions)
+
+			return ""
+		}
+
+		semvers = append(semvers, semVersion)
+	}
+
+	sort.Sort(semver.Collection(semvers))
+
+	for i, semVersion := range semvers {
+		versions[i] = semVersion.Original()
+	}
+
+	return versions[len(versions)-1]
+}
+
+func appendUnique(list []string, item string) []string {
+	for _, existing := range list {
+		if existing == item {
+			return list
+		}
+	}
+
+	return append(list, item)
+}
@@ -0,0 +1,598 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	zcommon "zotregistry.io/zot/pkg/common"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+)
+
+const (
+	defaultFederationTimeout = 10 * time.Second
+	// the results are attributed to the registry they come from with this field
+	registryField = "Registry"
+	pageField     = "Page"
+)
+
+// repoFields are the fields naming the repo of the objects found in the results of the peers, they're filtered
+// through the local access control of the user.
+var repoFields = map[string]string{ //nolint:gochecknoglobals
+	"ImageSummary":  "RepoName",
+	"RepoSummary":   "Name",
+	"DownloadCount": "Repo",
+}
+
+// FederatedRequest is a GraphQL request, the same request is run by the registry and by each peer.
+type FederatedRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// FederatedResponse holds the merged results of the registry and of its peers,
+// the errors of each registry are reported without failing the whole query.
+type FederatedResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []FederatedError       `json:"errors,omitempty"`
+}
+
+type FederatedError struct {
+	Registry string `json:"registry"`
+	Message  string `json:"message"`
+}
+
+type peerResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// FederatedHandler fans the search queries out to the peer registries and merges their results with the local ones.
+// The lists found in the results are concatenated, each element having a "Registry" field naming its registry,
+// and the page counts are summed. Other results, e.g. the summary of a single image, are taken from the first
+// registry returning them, the registry itself first and then the peers in the configured order.
+//
+// The peers forwarding the credentials of the user apply their own access control. The results of the other peers,
+// queried with their configured headers, are filtered through the local access control of the user: the images and
+// repos of the repos the user can't read locally are dropped, along with the ones whose repo isn't selected by the
+// query, and so are the results of the queries on such repos. Their page counts only count the objects left.
+type FederatedHandler struct {
+	executor   *executor.Executor
+	peers      []extconf.FederationPeerConfig
+	httpClient *http.Client
+	log        log.Logger
+}
+
+// NewFederatedHandler runs the local queries with the same persisted queries as the search route, so the queries
+// which aren't allowed by the search route aren't run by the federated search either.
+func NewFederatedHandler(schema graphql.ExecutableSchema, config *extconf.FederationConfig,
+	persistedQueries *PersistedQueries, log log.Logger,
+) *FederatedHandler {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = defaultFederationTimeout
+	}
+
+	exec := executor.New(schema)
+	exec.Use(extension.Introspection{})
+
+	if persistedQueries != nil {
+		exec.Use(persistedQueries)
+	}
+
+	return &FederatedHandler{
+		executor:   exec,
+		peers:      config.Peers,
+		httpClient: &http.Client{Timeout: timeout},
+		log:        log,
+	}
+}
+
+// Search godoc
+// @Summary Federated search
+// @Description Run a search GraphQL query on the registry and its federation peers, and merge the results
+// @Router  /v2/_zot/ext/search/federated [post]
+// @Accept  json
+// @Produce json
+// @Param   request   body     search.FederatedRequest true "GraphQL request"
+// @Success 200 {object}   search.FederatedResponse
+// @Failure 400 {string}   string   "bad request".
+func (fh *FederatedHandler) Search(response http.ResponseWriter, request *http.Request) {
+	fedRequest, err := getFederatedRequest(request)
+	if err != nil || (fedRequest.Query == "" && fedRequest.Extensions == nil) {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	// the query is validated locally, so that invalid queries are not sent to the peers
+	localData, localErrs, operationCtx, ok := fh.runLocalQuery(response, request, &fedRequest)
+	if !ok {
+		return
+	}
+
+	userAc, err := reqCtx.UserAcFromContext(request.Context())
+	if err != nil {
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	fedResponse := FederatedResponse{Data: map[string]interface{}{}, Errors: []FederatedError{}}
+
+	fh.merge(&fedResponse, extconf.FederationLocalSource, localData, localErrs)
+
+	peerResponses := make([]peerResponse, len(fh.peers))
+	peerErrs := make([]error, len(fh.peers))
+
+	var wg sync.WaitGroup
+
+	for i, peer := range fh.peers {
+		wg.Add(1)
+
+		go func(i int, peer extconf.FederationPeerConfig) {
+			defer wg.Done()
+
+			peerResponses[i], peerErrs[i] = fh.queryPeer(request, peer, fedRequest)
+		}(i, peer)
+	}
+
+	wg.Wait()
+
+	for i, peer := range fh.peers {
+		if peerErrs[i] != nil {
+			fh.log.Error().Err(peerErrs[i]).Str("peer", peer.Name).Msg("federated search: unable to query peer")
+
+			fedResponse.Errors = append(fedResponse.Errors, FederatedError{Registry: peer.Name, Message: peerErrs[i].Error()})
+
+			continue
+		}
+
+		errMessages := make([]string, 0, len(peerResponses[i].Errors))
+
+		for _, peerErr := range peerResponses[i].Errors {
+			errMessages = append(errMessages, peerErr.Message)
+		}
+
+		data := peerResponses[i].Data
+
+		// the admins, or all the users if the access control isn't enabled, can read all the repos
+		if !peer.ForwardCredentials && !userAc.IsAdmin() {
+			data = fh.filterPeerData(operationCtx, userAc, peer.Name, data)
+		}
+
+		fh.merge(&fedResponse, peer.Name, data, errMessages)
+	}
+
+	if len(fedResponse.Errors) == 0 {
+		fedResponse.Errors = nil
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, fedResponse)
+}
+
+// runLocalQuery writes a bad request response if the query is invalid or isn't allowed, the query of a persisted
+// query sent as a hash is set in the request sent to the peers.
+func (fh *FederatedHandler) runLocalQuery(response http.ResponseWriter, request *http.Request,
+	fedRequest *FederatedRequest,
+) (json.RawMessage, []string, *graphql.OperationContext, bool) {
+	ctx := graphql.StartOperationTrace(request.Context())
+
+	rawParams := &graphql.RawParams{
+		Query:         fedRequest.Query,
+		OperationName: fedRequest.OperationName,
+		Variables:     fedRequest.Variables,
+		Extensions:    fedRequest.Extensions,
+		Headers:       request.Header,
+	}
+	rawParams.ReadTime.Start = graphql.Now()
+	rawParams.ReadTime.End = graphql.Now()
+
+	operationCtx, gqlErrs := fh.executor.CreateOperationContext(ctx, rawParams)
+	if gqlErrs != nil {
+		zcommon.WriteJSON(response, http.StatusBadRequest, map[string]interface{}{"errors": gqlErrs})
+
+		return nil, nil, nil, false
+	}
+
+	fedRequest.Query = rawParams.Query
+
+	responses, ctx := fh.executor.DispatchOperation(ctx, operationCtx)
+	result := responses(ctx)
+
+	errMessages := make([]string, 0, len(result.Errors))
+
+	for _, gqlErr := range result.Errors {
+		errMessages = append(errMessages, gqlErr.Message)
+	}
+
+	return result.Data, errMessages, operationCtx, true
+}
+
+// queryPeer sends the query with the configured headers of the peer, the Authorization header of the user replaces
+// the configured one if the peer forwards the credentials.
+func (fh *FederatedHandler) queryPeer(request *http.Request, peer extconf.FederationPeerConfig,
+	fedRequest FederatedRequest,
+) (peerResponse, error) {
+	var result peerResponse
+
+	body, err := json.Marshal(fedRequest)
+	if err != nil {
+		return result, err
+	}
+
+	peerURL := strings.TrimSuffix(peer.URL, "/") + constants.FullSearchPrefix
+
+	req, err := http.NewRequestWithContext(request.Context(), http.MethodPost, peerURL, bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range peer.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if peer.ForwardCredentials {
+		req.Header.Del("Authorization")
+
+		if authorization := request.Header.Get("Authorization"); authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+	}
+
+	resp, err := fh.httpClient.Do(req)
+	if err != nil {
+		return result, err
+	}
+
+	defer resp.Body.Close()
+
+	// GraphQL errors are returned with a 422 status code, along with the error messages
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnprocessableEntity {
+		return result, fmt.Errorf("%w: %s returned status code %d", zerr.ErrFederationPeerFailed, peer.Name,
+			resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("%w: %s returned an invalid response: %w", zerr.ErrFederationPeerFailed, peer.Name, err)
+	}
+
+	return result, nil
+}
+
+// filterPeerData drops the results of a peer the user can't read locally.
+func (fh *FederatedHandler) filterPeerData(operationCtx *graphql.OperationContext,
+	userAc *reqCtx.UserAccessControl, registry string, data json.RawMessage,
+) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// the invalid results are reported by merge
+		return data
+	}
+
+	for _, field := range graphql.CollectFields(operationCtx, operationCtx.Operation.SelectionSet,
+		[]string{"Query"}) {
+		if _, ok := fields[field.Alias]; !ok {
+			continue
+		}
+
+		if !canReadArguments(userAc, field.ArgumentMap(operationCtx.Variables)) {
+			delete(fields, field.Alias)
+
+			continue
+		}
+
+		value, ok := filterValue(operationCtx, userAc, field, fields[field.Alias])
+		if !ok {
+			delete(fields, field.Alias)
+
+			continue
+		}
+
+		fields[field.Alias] = value
+	}
+
+	filtered, err := json.Marshal(fields)
+	if err != nil {
+		fh.log.Error().Err(err).Str("registry", registry).Msg("federated search: unable to encode query result")
+
+		return nil
+	}
+
+	return filtered
+}
+
+// filterValue returns false if the value is an object of a repo the user can't read, the lists only keep the
+// objects the user can read and the page counts of the filtered lists are recomputed.
+func filterValue(operationCtx *graphql.OperationContext, userAc *reqCtx.UserAccessControl,
+	field graphql.CollectedField, value interface{},
+) (interface{}, bool) {
+	switch typedValue := value.(type) {
+	case []interface{}:
+		filtered := make([]interface{}, 0, len(typedValue))
+
+		for _, elem := range typedValue {
+			if elem, ok := filterValue(operationCtx, userAc, field, elem); ok {
+				filtered = append(filtered, elem)
+			}
+		}
+
+		return filtered, true
+	case map[string]interface{}:
+		if field.Definition == nil {
+			break
+		}
+
+		typeName := field.Definition.Type.Name()
+		subFields := graphql.CollectFields(operationCtx, field.Selections, []string{typeName})
+
+		if repoField, ok := repoFields[typeName]; ok && !canReadObject(userAc, subFields, repoField, typedValue) {
+			return nil, false
+		}
+
+		for _, subField := range subFields {
+			if subValue, ok := filterValue(operationCtx, userAc, subField, typedValue[subField.Alias]); ok {
+				typedValue[subField.Alias] = subValue
+			} else {
+				typedValue[subField.Alias] = nil
+			}
+		}
+
+		countPage(operationCtx, subFields, typedValue)
+	}
+
+	return value, true
+}
+
+// countPage sets the page counts of a paginated result to the number of objects left in its filtered lists. The
+// objects of the other pages of the peer can't be checked, so they aren't counted either, their count would tell
+// how many objects the user can't read.
+func countPage(operationCtx *graphql.OperationContext, subFields []graphql.CollectedField,
+	result map[string]interface{},
+) {
+	var page *graphql.CollectedField
+
+	filtered, count := false, 0
+
+	for idx, subField := range subFields {
+		if subField.Name == pageField {
+			page = &subFields[idx]
+
+			continue
+		}
+
+		if subField.Definition == nil || subField.Definition.Type.Elem == nil {
+			continue
+		}
+
+		if _, ok := repoFields[subField.Definition.Type.Name()]; !ok {
+			continue
+		}
+
+		filtered = true
+
+		if list, ok := result[subField.Alias].([]interface{}); ok {
+			count += len(list)
+		}
+	}
+
+	if page == nil || !filtered {
+		return
+	}
+
+	pageCounts, ok := result[page.Alias].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	// the counts are summed with the ones of the other registries as decoded JSON numbers
+	for _, countField := range graphql.CollectFields(operationCtx, page.Selections, []string{"PageInfo"}) {
+		if countField.Name == "TotalCount" || countField.Name == "ItemCount" {
+			pageCounts[countField.Alias] = float64(count)
+		}
+	}
+}
+
+// canReadObject checks the repo of an object, the objects whose repo isn't selected can't be checked.
+func canReadObject(userAc *reqCtx.UserAccessControl, subFields []graphql.CollectedField, repoField string,
+	object map[string]interface{},
+) bool {
+	for _, subField := range subFields {
+		if subField.Name != repoField {
+			continue
+		}
+
+		repo, ok := object[subField.Alias].(string)
+
+		return ok && userAc.Can(constants.ReadPermission, repo)
+	}
+
+	return false
+}
+
+// canReadArguments checks the repo and the image arguments of the queries, e.g. ImageList(repo) or Image(image).
+func canReadArguments(userAc *reqCtx.UserAccessControl, arguments map[string]interface{}) bool {
+	if repo, ok := arguments["repo"].(string); ok && !userAc.Can(constants.ReadPermission, repo) {
+		return false
+	}
+
+	if image, ok := arguments["image"].(string); ok {
+		repo, _, _ := strings.Cut(image, "@")
+		repo, _, _ = strings.Cut(repo, ":")
+
+		if !userAc.Can(constants.ReadPermission, repo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (fh *FederatedHandler) merge(fedResponse *FederatedResponse, registry string, data json.RawMessage,
+	errMessages []string,
+) {
+	for _, errMessage := range errMessages {
+		fedResponse.Errors = append(fedResponse.Errors, FederatedError{Registry: registry, Message: errMessage})
+	}
+
+	if len(data) == 0 {
+		return
+	}
+
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		fh.log.Error().Err(err).Str("registry", registry).Msg("federated search: unable to decode query result")
+
+		fedResponse.Errors = append(fedResponse.Errors, FederatedError{Registry: registry, Message: err.Error()})
+
+		return
+	}
+
+	for field, value := range fields {
+		fedResponse.Data[field] = mergeResult(fedResponse.Data[field], value, registry)
+	}
+}
+
+// mergeResult merges the result of a registry into the results of the previous registries.
+func mergeResult(merged, result interface{}, registry string) interface{} {
+	if merged == nil {
+		return withRegistry(result, registry)
+	}
+
+	switch mergedValue := merged.(type) {
+	case []interface{}:
+		if resultList, ok := result.([]interface{}); ok {
+			return append(mergedValue, withRegistry(resultList, registry).([]interface{})...)
+		}
+	case map[string]interface{}:
+		resultMap, ok := result.(map[string]interface{})
+		if !ok || !hasList(mergedValue) {
+			break
+		}
+
+		// paginated results, e.g. {Page, Results} or the {Page, Repos, Images, Layers} of the global search
+		for key, value := range resultMap {
+			switch {
+			case key == pageField:
+				mergedValue[key] = sumPages(mergedValue[key], value)
+			case mergedValue[key] == nil:
+				mergedValue[key] = withRegistry(value, registry)
+			default:
+				if mergedList, ok := mergedValue[key].([]interface{}); ok {
+					mergedValue[key] = mergeResult(mergedList, value, registry)
+				}
+			}
+		}
+	}
+
+	return merged
+}
+
+// withRegistry attributes the objects of a result to their registry.
+func withRegistry(result interface{}, registry string) interface{} {
+	switch value := result.(type) {
+	case []interface{}:
+		for _, elem := range value {
+			withRegistry(elem, registry)
+		}
+	case map[string]interface{}:
+		if !hasList(value) {
+			value[registryField] = registry
+
+			break
+		}
+
+		for key, elem := range value {
+			if _, ok := elem.([]interface{}); ok {
+				withRegistry(value[key], registry)
+			}
+		}
+	}
+
+	return result
+}
+
+func sumPages(merged, page interface{}) interface{} {
+	mergedPage, ok := merged.(map[string]interface{})
+	if !ok {
+		return page
+	}
+
+	pageCounts, ok := page.(map[string]interface{})
+	if !ok {
+		return merged
+	}
+
+	for key, value := range pageCounts {
+		count, ok := value.(float64)
+		if !ok {
+			continue
+		}
+
+		mergedCount, _ := mergedPage[key].(float64)
+		mergedPage[key] = mergedCount + count
+	}
+
+	return mergedPage
+}
+
+func hasList(value map[string]interface{}) bool {
+	for _, elem := range value {
+		if _, ok := elem.([]interface{}); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getFederatedRequest(request *http.Request) (FederatedRequest, error) {
+	fedRequest := FederatedRequest{}
+
+	if request.Method == http.MethodPost {
+		err := json.NewDecoder(request.Body).Decode(&fedRequest)
+
+		return fedRequest, err
+	}
+
+	fedRequest.Query = request.URL.Query().Get("query")
+	fedRequest.OperationName = request.URL.Query().Get("operationName")
+
+	if variables := request.URL.Query().Get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &fedRequest.Variables); err != nil {
+			return fedRequest, err
+		}
+	}
+
+	if extensions := request.URL.Query().Get("extensions"); extensions != "" {
+		if err := json.Unmarshal([]byte(extensions), &fedRequest.Extensions); err != nil {
+			return fedRequest, err
+		}
+	}
+
+	return fedRequest, nil
+}
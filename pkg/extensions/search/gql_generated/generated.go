@@ -48,8 +48,17 @@ type ComplexityRoot struct {
 		Value func(childComplexity int) int
 	}
 
+	BookmarkList struct {
+		Name       func(childComplexity int) int
+		Owner      func(childComplexity int) int
+		Repos      func(childComplexity int) int
+		SharedWith func(childComplexity int) int
+	}
+
 	CVE struct {
 		Description func(childComplexity int) int
+		FixStatus   func(childComplexity int) int
+		HasFix      func(childComplexity int) int
 		ID          func(childComplexity int) int
 		PackageList func(childComplexity int) int
 		Severity    func(childComplexity int) int
@@ -57,9 +66,29 @@ type ComplexityRoot struct {
 	}
 
 	CVEResultForImage struct {
-		CVEList func(childComplexity int) int
-		Page    func(childComplexity int) int
-		Tag     func(childComplexity int) int
+		CVEList       func(childComplexity int) int
+		Page          func(childComplexity int) int
+		Tag           func(childComplexity int) int
+		UpgradeAdvice func(childComplexity int) int
+	}
+
+	DownloadCount struct {
+		Count         func(childComplexity int) int
+		Reference     func(childComplexity int) int
+		Repo          func(childComplexity int) int
+		UniqueClients func(childComplexity int) int
+	}
+
+	DownloadsOverTime struct {
+		Points        func(childComplexity int) int
+		Total         func(childComplexity int) int
+		UniqueClients func(childComplexity int) int
+	}
+
+	DownloadsPoint struct {
+		Count         func(childComplexity int) int
+		Day           func(childComplexity int) int
+		UniqueClients func(childComplexity int) int
 	}
 
 	GlobalSearchResult struct {
@@ -85,6 +114,7 @@ type ComplexityRoot struct {
 		DownloadCount   func(childComplexity int) int
 		IsSigned        func(childComplexity int) int
 		Labels          func(childComplexity int) int
+		LastPulled      func(childComplexity int) int
 		LastUpdated     func(childComplexity int) int
 		Licenses        func(childComplexity int) int
 		Manifests       func(childComplexity int) int
@@ -115,6 +145,19 @@ type ComplexityRoot struct {
 		Size   func(childComplexity int) int
 	}
 
+	License struct {
+		Category    func(childComplexity int) int
+		FilePath    func(childComplexity int) int
+		Name        func(childComplexity int) int
+		PackageName func(childComplexity int) int
+		Severity    func(childComplexity int) int
+	}
+
+	LicenseResultForImage struct {
+		LicenseList func(childComplexity int) int
+		Tag         func(childComplexity int) int
+	}
+
 	ManifestSummary struct {
 		ArtifactType    func(childComplexity int) int
 		ConfigDigest    func(childComplexity int) int
@@ -122,6 +165,7 @@ type ComplexityRoot struct {
 		DownloadCount   func(childComplexity int) int
 		History         func(childComplexity int) int
 		IsSigned        func(childComplexity int) int
+		LastPulled      func(childComplexity int) int
 		LastUpdated     func(childComplexity int) int
 		Layers          func(childComplexity int) int
 		Platform        func(childComplexity int) int
@@ -133,10 +177,19 @@ type ComplexityRoot struct {
 
 	PackageInfo struct {
 		FixedVersion     func(childComplexity int) int
+		HasFix           func(childComplexity int) int
 		InstalledVersion func(childComplexity int) int
 		Name             func(childComplexity int) int
 	}
 
+	PackageUpgrade struct {
+		CVEIds             func(childComplexity int) int
+		FixedVersions      func(childComplexity int) int
+		InstalledVersion   func(childComplexity int) int
+		Name               func(childComplexity int) int
+		RecommendedVersion func(childComplexity int) int
+	}
+
 	PageInfo struct {
 		ItemCount  func(childComplexity int) int
 		TotalCount func(childComplexity int) int
@@ -159,19 +212,26 @@ type ComplexityRoot struct {
 
 	Query struct {
 		BaseImageList           func(childComplexity int, image string, digest *string, requestedPage *PageInput) int
+		BookmarkListRepos       func(childComplexity int, name string, owner *string, requestedPage *PageInput) int
+		BookmarkLists           func(childComplexity int) int
 		BookmarkedRepos         func(childComplexity int, requestedPage *PageInput) int
-		CVEListForImage         func(childComplexity int, image string, requestedPage *PageInput, searchedCve *string) int
+		CVEListForImage         func(childComplexity int, image string, requestedPage *PageInput, searchedCve *string, fixStatus *FixStatus) int
 		DerivedImageList        func(childComplexity int, image string, digest *string, requestedPage *PageInput) int
+		DownloadsOverTime       func(childComplexity int, repo *string, reference *string, from *time.Time, to *time.Time) int
 		ExpandedRepoInfo        func(childComplexity int, repo string) int
 		GlobalSearch            func(childComplexity int, query string, filter *Filter, requestedPage *PageInput) int
 		Image                   func(childComplexity int, image string) int
 		ImageList               func(childComplexity int, repo string, requestedPage *PageInput) int
 		ImageListForCve         func(childComplexity int, id string, filter *Filter, requestedPage *PageInput) int
 		ImageListForDigest      func(childComplexity int, id string, requestedPage *PageInput) int
+		ImageListForLicense     func(childComplexity int, licenses []string, filter *Filter, requestedPage *PageInput) int
 		ImageListWithCVEFixed   func(childComplexity int, id string, image string, filter *Filter, requestedPage *PageInput) int
+		LicenseListForImage     func(childComplexity int, image string) int
 		Referrers               func(childComplexity int, repo string, digest string, typeArg []string) int
 		RepoListWithNewestImage func(childComplexity int, requestedPage *PageInput) int
+		SecretListForImage      func(childComplexity int, image string) int
 		StarredRepos            func(childComplexity int, requestedPage *PageInput) int
+		TopDownloads            func(childComplexity int, groupBy *DownloadsGroupBy, repo *string, from *time.Time, to *time.Time, limit *int) int
 	}
 
 	Referrer struct {
@@ -184,13 +244,21 @@ type ComplexityRoot struct {
 
 	RepoInfo struct {
 		Images  func(childComplexity int) int
+		Readme  func(childComplexity int) int
 		Summary func(childComplexity int) int
 	}
 
+	RepoReadme struct {
+		Content     func(childComplexity int) int
+		LastUpdated func(childComplexity int) int
+		UpdatedBy   func(childComplexity int) int
+	}
+
 	RepoSummary struct {
 		DownloadCount func(childComplexity int) int
 		IsBookmarked  func(childComplexity int) int
 		IsStarred     func(childComplexity int) int
+		LastPulled    func(childComplexity int) int
 		LastUpdated   func(childComplexity int) int
 		Name          func(childComplexity int) int
 		NewestImage   func(childComplexity int) int
@@ -201,6 +269,22 @@ type ComplexityRoot struct {
 		Vendors       func(childComplexity int) int
 	}
 
+	Secret struct {
+		Confidence  func(childComplexity int) int
+		Description func(childComplexity int) int
+		FilePath    func(childComplexity int) int
+		LayerDigest func(childComplexity int) int
+		Line        func(childComplexity int) int
+		Match       func(childComplexity int) int
+		RuleID      func(childComplexity int) int
+	}
+
+	SecretResultForImage struct {
+		Digest     func(childComplexity int) int
+		SecretList func(childComplexity int) int
+		Tag        func(childComplexity int) int
+	}
+
 	SignatureSummary struct {
 		Author    func(childComplexity int) int
 		IsTrusted func(childComplexity int) int
@@ -209,9 +293,12 @@ type ComplexityRoot struct {
 }
 
 type QueryResolver interface {
-	CVEListForImage(ctx context.Context, image string, requestedPage *PageInput, searchedCve *string) (*CVEResultForImage, error)
+	CVEListForImage(ctx context.Context, image string, requestedPage *PageInput, searchedCve *string, fixStatus *FixStatus) (*CVEResultForImage, error)
 	ImageListForCve(ctx context.Context, id string, filter *Filter, requestedPage *PageInput) (*PaginatedImagesResult, error)
 	ImageListWithCVEFixed(ctx context.Context, id string, image string, filter *Filter, requestedPage *PageInput) (*PaginatedImagesResult, error)
+	LicenseListForImage(ctx context.Context, image string) (*LicenseResultForImage, error)
+	ImageListForLicense(ctx context.Context, licenses []string, filter *Filter, requestedPage *PageInput) (*PaginatedImagesResult, error)
+	SecretListForImage(ctx context.Context, image string) (*SecretResultForImage, error)
 	ImageListForDigest(ctx context.Context, id string, requestedPage *PageInput) (*PaginatedImagesResult, error)
 	RepoListWithNewestImage(ctx context.Context, requestedPage *PageInput) (*PaginatedReposResult, error)
 	ImageList(ctx context.Context, repo string, requestedPage *PageInput) (*PaginatedImagesResult, error)
@@ -223,6 +310,10 @@ type QueryResolver interface {
 	Referrers(ctx context.Context, repo string, digest string, typeArg []string) ([]*Referrer, error)
 	StarredRepos(ctx context.Context, requestedPage *PageInput) (*PaginatedReposResult, error)
 	BookmarkedRepos(ctx context.Context, requestedPage *PageInput) (*PaginatedReposResult, error)
+	BookmarkLists(ctx context.Context) ([]*BookmarkList, error)
+	BookmarkListRepos(ctx context.Context, name string, owner *string, requestedPage *PageInput) (*PaginatedReposResult, error)
+	TopDownloads(ctx context.Context, groupBy *DownloadsGroupBy, repo *string, from *time.Time, to *time.Time, limit *int) ([]*DownloadCount, error)
+	DownloadsOverTime(ctx context.Context, repo *string, reference *string, from *time.Time, to *time.Time) (*DownloadsOverTime, error)
 }
 
 type executableSchema struct {
@@ -254,6 +345,34 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Annotation.Value(childComplexity), true
 
+	case "BookmarkList.Name":
+		if e.complexity.BookmarkList.Name == nil {
+			break
+		}
+
+		return e.complexity.BookmarkList.Name(childComplexity), true
+
+	case "BookmarkList.Owner":
+		if e.complexity.BookmarkList.Owner == nil {
+			break
+		}
+
+		return e.complexity.BookmarkList.Owner(childComplexity), true
+
+	case "BookmarkList.Repos":
+		if e.complexity.BookmarkList.Repos == nil {
+			break
+		}
+
+		return e.complexity.BookmarkList.Repos(childComplexity), true
+
+	case "BookmarkList.SharedWith":
+		if e.complexity.BookmarkList.SharedWith == nil {
+			break
+		}
+
+		return e.complexity.BookmarkList.SharedWith(childComplexity), true
+
 	case "CVE.Description":
 		if e.complexity.CVE.Description == nil {
 			break
@@ -261,6 +380,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.CVE.Description(childComplexity), true
 
+	case "CVE.FixStatus":
+		if e.complexity.CVE.FixStatus == nil {
+			break
+		}
+
+		return e.complexity.CVE.FixStatus(childComplexity), true
+
+	case "CVE.HasFix":
+		if e.complexity.CVE.HasFix == nil {
+			break
+		}
+
+		return e.complexity.CVE.HasFix(childComplexity), true
+
 	case "CVE.Id":
 		if e.complexity.CVE.ID == nil {
 			break
@@ -310,6 +443,83 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.CVEResultForImage.Tag(childComplexity), true
 
+	case "CVEResultForImage.UpgradeAdvice":
+		if e.complexity.CVEResultForImage.UpgradeAdvice == nil {
+			break
+		}
+
+		return e.complexity.CVEResultForImage.UpgradeAdvice(childComplexity), true
+
+	case "DownloadCount.Count":
+		if e.complexity.DownloadCount.Count == nil {
+			break
+		}
+
+		return e.complexity.DownloadCount.Count(childComplexity), true
+
+	case "DownloadCount.Reference":
+		if e.complexity.DownloadCount.Reference == nil {
+			break
+		}
+
+		return e.complexity.DownloadCount.Reference(childComplexity), true
+
+	case "DownloadCount.Repo":
+		if e.complexity.DownloadCount.Repo == nil {
+			break
+		}
+
+		return e.complexity.DownloadCount.Repo(childComplexity), true
+
+	case "DownloadCount.UniqueClients":
+		if e.complexity.DownloadCount.UniqueClients == nil {
+			break
+		}
+
+		return e.complexity.DownloadCount.UniqueClients(childComplexity), true
+
+	case "DownloadsOverTime.Points":
+		if e.complexity.DownloadsOverTime.Points == nil {
+			break
+		}
+
+		return e.complexity.DownloadsOverTime.Points(childComplexity), true
+
+	case "DownloadsOverTime.Total":
+		if e.complexity.DownloadsOverTime.Total == nil {
+			break
+		}
+
+		return e.complexity.DownloadsOverTime.Total(childComplexity), true
+
+	case "DownloadsOverTime.UniqueClients":
+		if e.complexity.DownloadsOverTime.UniqueClients == nil {
+			break
+		}
+
+		return e.complexity.DownloadsOverTime.UniqueClients(childComplexity), true
+
+	case "DownloadsPoint.Count":
+		if e.complexity.DownloadsPoint.Count == nil {
+			break
+		}
+
+		return e.complexity.DownloadsPoint.Count(childComplexity), true
+
+	case "DownloadsPoint.Day":
+		if e.complexity.DownloadsPoint.Day == nil {
+			break
+		}
+
+		return e.complexity.DownloadsPoint.Day(childComplexity), true
+
+	case "DownloadsPoint.UniqueClients":
+		if e.complexity.DownloadsPoint.UniqueClients == nil {
+			break
+		}
+
+		return e.complexity.DownloadsPoint.UniqueClients(childComplexity), true
+
 	case "GlobalSearchResult.Images":
 		if e.complexity.GlobalSearchResult.Images == nil {
 			break
@@ -422,6 +632,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.ImageSummary.Labels(childComplexity), true
 
+	case "ImageSummary.LastPulled":
+		if e.complexity.ImageSummary.LastPulled == nil {
+			break
+		}
+
+		return e.complexity.ImageSummary.LastPulled(childComplexity), true
+
 	case "ImageSummary.LastUpdated":
 		if e.complexity.ImageSummary.LastUpdated == nil {
 			break
@@ -555,6 +772,55 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.LayerSummary.Size(childComplexity), true
 
+	case "License.Category":
+		if e.complexity.License.Category == nil {
+			break
+		}
+
+		return e.complexity.License.Category(childComplexity), true
+
+	case "License.FilePath":
+		if e.complexity.License.FilePath == nil {
+			break
+		}
+
+		return e.complexity.License.FilePath(childComplexity), true
+
+	case "License.Name":
+		if e.complexity.License.Name == nil {
+			break
+		}
+
+		return e.complexity.License.Name(childComplexity), true
+
+	case "License.PackageName":
+		if e.complexity.License.PackageName == nil {
+			break
+		}
+
+		return e.complexity.License.PackageName(childComplexity), true
+
+	case "License.Severity":
+		if e.complexity.License.Severity == nil {
+			break
+		}
+
+		return e.complexity.License.Severity(childComplexity), true
+
+	case "LicenseResultForImage.LicenseList":
+		if e.complexity.LicenseResultForImage.LicenseList == nil {
+			break
+		}
+
+		return e.complexity.LicenseResultForImage.LicenseList(childComplexity), true
+
+	case "LicenseResultForImage.Tag":
+		if e.complexity.LicenseResultForImage.Tag == nil {
+			break
+		}
+
+		return e.complexity.LicenseResultForImage.Tag(childComplexity), true
+
 	case "ManifestSummary.ArtifactType":
 		if e.complexity.ManifestSummary.ArtifactType == nil {
 			break
@@ -597,6 +863,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.ManifestSummary.IsSigned(childComplexity), true
 
+	case "ManifestSummary.LastPulled":
+		if e.complexity.ManifestSummary.LastPulled == nil {
+			break
+		}
+
+		return e.complexity.ManifestSummary.LastPulled(childComplexity), true
+
 	case "ManifestSummary.LastUpdated":
 		if e.complexity.ManifestSummary.LastUpdated == nil {
 			break
@@ -653,6 +926,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PackageInfo.FixedVersion(childComplexity), true
 
+	case "PackageInfo.HasFix":
+		if e.complexity.PackageInfo.HasFix == nil {
+			break
+		}
+
+		return e.complexity.PackageInfo.HasFix(childComplexity), true
+
 	case "PackageInfo.InstalledVersion":
 		if e.complexity.PackageInfo.InstalledVersion == nil {
 			break
@@ -667,6 +947,41 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PackageInfo.Name(childComplexity), true
 
+	case "PackageUpgrade.CVEIds":
+		if e.complexity.PackageUpgrade.CVEIds == nil {
+			break
+		}
+
+		return e.complexity.PackageUpgrade.CVEIds(childComplexity), true
+
+	case "PackageUpgrade.FixedVersions":
+		if e.complexity.PackageUpgrade.FixedVersions == nil {
+			break
+		}
+
+		return e.complexity.PackageUpgrade.FixedVersions(childComplexity), true
+
+	case "PackageUpgrade.InstalledVersion":
+		if e.complexity.PackageUpgrade.InstalledVersion == nil {
+			break
+		}
+
+		return e.complexity.PackageUpgrade.InstalledVersion(childComplexity), true
+
+	case "PackageUpgrade.Name":
+		if e.complexity.PackageUpgrade.Name == nil {
+			break
+		}
+
+		return e.complexity.PackageUpgrade.Name(childComplexity), true
+
+	case "PackageUpgrade.RecommendedVersion":
+		if e.complexity.PackageUpgrade.RecommendedVersion == nil {
+			break
+		}
+
+		return e.complexity.PackageUpgrade.RecommendedVersion(childComplexity), true
+
 	case "PageInfo.ItemCount":
 		if e.complexity.PageInfo.ItemCount == nil {
 			break
@@ -735,6 +1050,25 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.BaseImageList(childComplexity, args["image"].(string), args["digest"].(*string), args["requestedPage"].(*PageInput)), true
 
+	case "Query.BookmarkListRepos":
+		if e.complexity.Query.BookmarkListRepos == nil {
+			break
+		}
+
+		args, err := ec.field_Query_BookmarkListRepos_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.BookmarkListRepos(childComplexity, args["name"].(string), args["owner"].(*string), args["requestedPage"].(*PageInput)), true
+
+	case "Query.BookmarkLists":
+		if e.complexity.Query.BookmarkLists == nil {
+			break
+		}
+
+		return e.complexity.Query.BookmarkLists(childComplexity), true
+
 	case "Query.BookmarkedRepos":
 		if e.complexity.Query.BookmarkedRepos == nil {
 			break
@@ -757,7 +1091,7 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 			return 0, false
 		}
 
-		return e.complexity.Query.CVEListForImage(childComplexity, args["image"].(string), args["requestedPage"].(*PageInput), args["searchedCVE"].(*string)), true
+		return e.complexity.Query.CVEListForImage(childComplexity, args["image"].(string), args["requestedPage"].(*PageInput), args["searchedCVE"].(*string), args["fixStatus"].(*FixStatus)), true
 
 	case "Query.DerivedImageList":
 		if e.complexity.Query.DerivedImageList == nil {
@@ -771,6 +1105,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.DerivedImageList(childComplexity, args["image"].(string), args["digest"].(*string), args["requestedPage"].(*PageInput)), true
 
+	case "Query.DownloadsOverTime":
+		if e.complexity.Query.DownloadsOverTime == nil {
+			break
+		}
+
+		args, err := ec.field_Query_DownloadsOverTime_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.DownloadsOverTime(childComplexity, args["repo"].(*string), args["reference"].(*string), args["from"].(*time.Time), args["to"].(*time.Time)), true
+
 	case "Query.ExpandedRepoInfo":
 		if e.complexity.Query.ExpandedRepoInfo == nil {
 			break
@@ -843,6 +1189,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.ImageListForDigest(childComplexity, args["id"].(string), args["requestedPage"].(*PageInput)), true
 
+	case "Query.ImageListForLicense":
+		if e.complexity.Query.ImageListForLicense == nil {
+			break
+		}
+
+		args, err := ec.field_Query_ImageListForLicense_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.ImageListForLicense(childComplexity, args["licenses"].([]string), args["filter"].(*Filter), args["requestedPage"].(*PageInput)), true
+
 	case "Query.ImageListWithCVEFixed":
 		if e.complexity.Query.ImageListWithCVEFixed == nil {
 			break
@@ -855,6 +1213,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.ImageListWithCVEFixed(childComplexity, args["id"].(string), args["image"].(string), args["filter"].(*Filter), args["requestedPage"].(*PageInput)), true
 
+	case "Query.LicenseListForImage":
+		if e.complexity.Query.LicenseListForImage == nil {
+			break
+		}
+
+		args, err := ec.field_Query_LicenseListForImage_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.LicenseListForImage(childComplexity, args["image"].(string)), true
+
 	case "Query.Referrers":
 		if e.complexity.Query.Referrers == nil {
 			break
@@ -879,6 +1249,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.RepoListWithNewestImage(childComplexity, args["requestedPage"].(*PageInput)), true
 
+	case "Query.SecretListForImage":
+		if e.complexity.Query.SecretListForImage == nil {
+			break
+		}
+
+		args, err := ec.field_Query_SecretListForImage_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.SecretListForImage(childComplexity, args["image"].(string)), true
+
 	case "Query.StarredRepos":
 		if e.complexity.Query.StarredRepos == nil {
 			break
@@ -891,6 +1273,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.StarredRepos(childComplexity, args["requestedPage"].(*PageInput)), true
 
+	case "Query.TopDownloads":
+		if e.complexity.Query.TopDownloads == nil {
+			break
+		}
+
+		args, err := ec.field_Query_TopDownloads_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.TopDownloads(childComplexity, args["groupBy"].(*DownloadsGroupBy), args["repo"].(*string), args["from"].(*time.Time), args["to"].(*time.Time), args["limit"].(*int)), true
+
 	case "Referrer.Annotations":
 		if e.complexity.Referrer.Annotations == nil {
 			break
@@ -933,6 +1327,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.RepoInfo.Images(childComplexity), true
 
+	case "RepoInfo.Readme":
+		if e.complexity.RepoInfo.Readme == nil {
+			break
+		}
+
+		return e.complexity.RepoInfo.Readme(childComplexity), true
+
 	case "RepoInfo.Summary":
 		if e.complexity.RepoInfo.Summary == nil {
 			break
@@ -940,6 +1341,27 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.RepoInfo.Summary(childComplexity), true
 
+	case "RepoReadme.Content":
+		if e.complexity.RepoReadme.Content == nil {
+			break
+		}
+
+		return e.complexity.RepoReadme.Content(childComplexity), true
+
+	case "RepoReadme.LastUpdated":
+		if e.complexity.RepoReadme.LastUpdated == nil {
+			break
+		}
+
+		return e.complexity.RepoReadme.LastUpdated(childComplexity), true
+
+	case "RepoReadme.UpdatedBy":
+		if e.complexity.RepoReadme.UpdatedBy == nil {
+			break
+		}
+
+		return e.complexity.RepoReadme.UpdatedBy(childComplexity), true
+
 	case "RepoSummary.DownloadCount":
 		if e.complexity.RepoSummary.DownloadCount == nil {
 			break
@@ -961,6 +1383,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.RepoSummary.IsStarred(childComplexity), true
 
+	case "RepoSummary.LastPulled":
+		if e.complexity.RepoSummary.LastPulled == nil {
+			break
+		}
+
+		return e.complexity.RepoSummary.LastPulled(childComplexity), true
+
 	case "RepoSummary.LastUpdated":
 		if e.complexity.RepoSummary.LastUpdated == nil {
 			break
@@ -1017,51 +1446,121 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.RepoSummary.Vendors(childComplexity), true
 
-	case "SignatureSummary.Author":
-		if e.complexity.SignatureSummary.Author == nil {
+	case "Secret.Confidence":
+		if e.complexity.Secret.Confidence == nil {
 			break
 		}
 
-		return e.complexity.SignatureSummary.Author(childComplexity), true
+		return e.complexity.Secret.Confidence(childComplexity), true
 
-	case "SignatureSummary.IsTrusted":
-		if e.complexity.SignatureSummary.IsTrusted == nil {
+	case "Secret.Description":
+		if e.complexity.Secret.Description == nil {
 			break
 		}
 
-		return e.complexity.SignatureSummary.IsTrusted(childComplexity), true
+		return e.complexity.Secret.Description(childComplexity), true
 
-	case "SignatureSummary.Tool":
-		if e.complexity.SignatureSummary.Tool == nil {
+	case "Secret.FilePath":
+		if e.complexity.Secret.FilePath == nil {
 			break
 		}
 
-		return e.complexity.SignatureSummary.Tool(childComplexity), true
+		return e.complexity.Secret.FilePath(childComplexity), true
 
-	}
-	return 0, false
-}
+	case "Secret.LayerDigest":
+		if e.complexity.Secret.LayerDigest == nil {
+			break
+		}
 
-func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
-	rc := graphql.GetOperationContext(ctx)
-	ec := executionContext{rc, e, 0, 0, make(chan graphql.DeferredResult)}
-	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
-		ec.unmarshalInputFilter,
-		ec.unmarshalInputPageInput,
-	)
-	first := true
+		return e.complexity.Secret.LayerDigest(childComplexity), true
 
-	switch rc.Operation.Operation {
-	case ast.Query:
-		return func(ctx context.Context) *graphql.Response {
-			var response graphql.Response
-			var data graphql.Marshaler
-			if first {
-				first = false
-				ctx = graphql.WithUnmarshalerMap(ctx, inputUnmarshalMap)
-				data = ec._Query(ctx, rc.Operation.SelectionSet)
-			} else {
-				if atomic.LoadInt32(&ec.pendingDeferred) > 0 {
+	case "Secret.Line":
+		if e.complexity.Secret.Line == nil {
+			break
+		}
+
+		return e.complexity.Secret.Line(childComplexity), true
+
+	case "Secret.Match":
+		if e.complexity.Secret.Match == nil {
+			break
+		}
+
+		return e.complexity.Secret.Match(childComplexity), true
+
+	case "Secret.RuleID":
+		if e.complexity.Secret.RuleID == nil {
+			break
+		}
+
+		return e.complexity.Secret.RuleID(childComplexity), true
+
+	case "SecretResultForImage.Digest":
+		if e.complexity.SecretResultForImage.Digest == nil {
+			break
+		}
+
+		return e.complexity.SecretResultForImage.Digest(childComplexity), true
+
+	case "SecretResultForImage.SecretList":
+		if e.complexity.SecretResultForImage.SecretList == nil {
+			break
+		}
+
+		return e.complexity.SecretResultForImage.SecretList(childComplexity), true
+
+	case "SecretResultForImage.Tag":
+		if e.complexity.SecretResultForImage.Tag == nil {
+			break
+		}
+
+		return e.complexity.SecretResultForImage.Tag(childComplexity), true
+
+	case "SignatureSummary.Author":
+		if e.complexity.SignatureSummary.Author == nil {
+			break
+		}
+
+		return e.complexity.SignatureSummary.Author(childComplexity), true
+
+	case "SignatureSummary.IsTrusted":
+		if e.complexity.SignatureSummary.IsTrusted == nil {
+			break
+		}
+
+		return e.complexity.SignatureSummary.IsTrusted(childComplexity), true
+
+	case "SignatureSummary.Tool":
+		if e.complexity.SignatureSummary.Tool == nil {
+			break
+		}
+
+		return e.complexity.SignatureSummary.Tool(childComplexity), true
+
+	}
+	return 0, false
+}
+
+func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
+	rc := graphql.GetOperationContext(ctx)
+	ec := executionContext{rc, e, 0, 0, make(chan graphql.DeferredResult)}
+	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
+		ec.unmarshalInputFilter,
+		ec.unmarshalInputPageInput,
+	)
+	first := true
+
+	switch rc.Operation.Operation {
+	case ast.Query:
+		return func(ctx context.Context) *graphql.Response {
+			var response graphql.Response
+			var data graphql.Marshaler
+			if first {
+				first = false
+				ctx = graphql.WithUnmarshalerMap(ctx, inputUnmarshalMap)
+				data = ec._Query(ctx, rc.Operation.SelectionSet)
+			} else {
+				if atomic.LoadInt32(&ec.pendingDeferred) > 0 {
 					result := <-ec.deferredResults
 					atomic.AddInt32(&ec.pendingDeferred, -1)
 					data = result.Result
@@ -1158,6 +1657,10 @@ type CVEResultForImage {
     The CVE pagination information, see PageInfo object for more details
     """
     Page: PageInfo
+    """
+    How to upgrade the packages affected by the CVEs (on all pages) having a fix, one entry per package
+    """
+    UpgradeAdvice: [PackageUpgrade]
 }
 
 """
@@ -1185,6 +1688,16 @@ type CVE {
     Information on the packages in which the CVE was found
     """
     PackageList: [PackageInfo]
+    """
+    True if every package affected by the CVE has a version in which the CVE is fixed,
+    false if the CVE is fixed only in some of the affected packages or in none of them
+    """
+    HasFix: Boolean
+    """
+    If the CVE can be fixed by upgrading all (AVAILABLE), some (PARTIAL) or none (UNAVAILABLE)
+    of the affected packages
+    """
+    FixStatus: FixStatus
 }
 
 """
@@ -1203,6 +1716,129 @@ type PackageInfo {
     Minimum version of the package in which the CVE is fixed
     """
     FixedVersion: String
+    """
+    True if there is a version of the package in which the CVE is fixed
+    """
+    HasFix: Boolean
+}
+
+"""
+Upgrade advice for a package affected by CVEs which have a fix
+"""
+type PackageUpgrade {
+    """
+    Name of the package
+    """
+    Name: String
+    """
+    Current version of the package
+    """
+    InstalledVersion: String
+    """
+    The version fixing all the fixable CVEs affecting the package, empty if several fixed versions
+    can't be compared (e.g. distribution specific versioning), in which case see FixedVersions
+    """
+    RecommendedVersion: String
+    """
+    The minimum versions in which each of the CVEs is fixed, sorted ascending when they can be compared
+    """
+    FixedVersions: [String]
+    """
+    IDs of the CVEs fixed by upgrading the package
+    """
+    CVEIds: [String]
+}
+
+"""
+Contains the tag of the image and the list of licenses found in the image
+"""
+type LicenseResultForImage {
+    """
+    Tag of the scanned image
+    """
+    Tag: String
+    """
+    List of licenses declared by the packages installed in the image or found in license files
+    """
+    LicenseList: [License]
+}
+
+"""
+Contains details about a license found in an image
+"""
+type License {
+    """
+    Name of the license, typically an SPDX identifier such as "AGPL-3.0"
+    """
+    Name: String
+    """
+    Category of the license, one of "forbidden", "restricted", "reciprocal", "notice", "permissive", "unencumbered", "unknown"
+    """
+    Category: String
+    """
+    Severity derived from the license category, one of "UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"
+    """
+    Severity: String
+    """
+    Name of the package declaring the license, empty if the license was found in a file
+    """
+    PackageName: String
+    """
+    Path of the file in which the license was found, empty if the license was declared by a package
+    """
+    FilePath: String
+}
+
+"""
+Contains the tag and digest of the image and the secrets found in its layers
+"""
+type SecretResultForImage {
+    """
+    Tag of the scanned image
+    """
+    Tag: String
+    """
+    Digest of the scanned image manifest
+    """
+    Digest: String
+    """
+    List of secrets found when the image was pushed
+    """
+    SecretList: [Secret]
+}
+
+"""
+Contains details about a secret found in an image layer, the secret itself is redacted
+"""
+type Secret {
+    """
+    ID of the detection rule, for example "aws-access-key-id" or "private-key"
+    """
+    RuleID: String
+    """
+    Human readable description of the kind of secret
+    """
+    Description: String
+    """
+    Confidence of the detection, one of "HIGH", "MEDIUM"
+    """
+    Confidence: String
+    """
+    Digest of the layer containing the file
+    """
+    LayerDigest: String
+    """
+    Path of the file in which the secret was found
+    """
+    FilePath: String
+    """
+    Line of the file in which the secret was found
+    """
+    Line: Int
+    """
+    Redacted value of the secret
+    """
+    Match: String
 }
 
 """
@@ -1217,6 +1853,28 @@ type RepoInfo {
     Details about the repository itself
     """
     Summary: RepoSummary
+    """
+    Markdown documentation of the repository, null if it has no README
+    """
+    Readme: RepoReadme
+}
+
+"""
+Markdown documentation of a repository, set by its users and shown next to its tags
+"""
+type RepoReadme {
+    """
+    Content of the README, in markdown
+    """
+    Content: String
+    """
+    Timestamp of the last update of the README
+    """
+    LastUpdated: Time
+    """
+    User who last updated the README
+    """
+    UpdatedBy: String
 }
 
 """
@@ -1276,6 +1934,10 @@ type ImageSummary {
     """
     DownloadCount: Int
     """
+    Timestamp of the last pull of this image, by its tag or by its digest, null if it was never pulled
+    """
+    LastPulled: Time
+    """
     Timestamp of the last modification done to the image (from config or the last updated layer)
     """
     LastUpdated: Time
@@ -1366,6 +2028,10 @@ type ManifestSummary {
     """
     DownloadCount: Int
     """
+    Timestamp of the last pull of this manifest, null if it was never pulled
+    """
+    LastPulled: Time
+    """
     List of layers matching the search criteria
     NOTE: the actual search logic for layers is not implemented at the moment
     """
@@ -1436,6 +2102,10 @@ type RepoSummary {
     """
     DownloadCount: Int
     """
+    Timestamp of the last pull of an image of this repository, null if none was pulled
+    """
+    LastPulled: Time
+    """
     Number of stars attributed to this repository by users
     """
     StarCount: Int
@@ -1584,6 +2254,100 @@ type SignatureSummary {
     Author: String
 }
 
+"""
+Number of downloads of a repo, tag or digest
+"""
+type DownloadCount {
+    """
+    Repository name
+    """
+    Repo: String
+    """
+    Tag or digest, empty when the downloads are grouped by repo
+    """
+    Reference: String
+    """
+    Number of downloads
+    """
+    Count: Int
+    """
+    Number of distinct clients which made the downloads
+    """
+    UniqueClients: Int
+}
+
+"""
+Number of downloads during a day
+"""
+type DownloadsPoint {
+    """
+    Day in the YYYY-MM-DD format, in UTC
+    """
+    Day: String
+    """
+    Number of downloads
+    """
+    Count: Int
+    """
+    Number of distinct clients which made the downloads
+    """
+    UniqueClients: Int
+}
+
+"""
+Downloads during a period, day by day
+"""
+type DownloadsOverTime {
+    """
+    Number of downloads during the period
+    """
+    Total: Int
+    """
+    Number of distinct clients which made the downloads during the period
+    """
+    UniqueClients: Int
+    """
+    Downloads of every day of the period, including the days without downloads
+    """
+    Points: [DownloadsPoint!]!
+}
+
+"""
+How the downloads are counted
+"""
+enum DownloadsGroupBy {
+    """
+    Downloads of every image of the repository
+    """
+    REPO
+    """
+    Downloads made by tag
+    """
+    TAG
+    """
+    Downloads of each manifest, made by tag or by digest
+    """
+    DIGEST
+}
+
+"""
+Availability of a fix for a CVE across the packages it affects
+"""
+enum FixStatus {
+    """
+    Every affected package has a version in which the CVE is fixed
+    """
+    AVAILABLE
+    """
+    Only some of the affected packages have a version in which the CVE is fixed
+    """
+    PARTIAL
+    """
+    None of the affected packages has a version in which the CVE is fixed
+    """
+    UNAVAILABLE
+}
+
 """
 All sort criteria usable with pagination, some of these criteria applies only
 to certain queries. For example sort by severity is available for CVEs but not
@@ -1662,6 +2426,28 @@ input PageInput {
     sortBy: SortCriteria
 }
 
+"""
+A named list of repos bookmarked by a user, shared read-only with the users of some groups
+"""
+type BookmarkList {
+    """
+    Name of the list, unique among the lists of its owner
+    """
+    Name: String
+    """
+    The user who created the list, only the owner can modify it
+    """
+    Owner: String
+    """
+    The repos of the list which can be read by the current user
+    """
+    Repos: [String!]
+    """
+    The groups whose users can read the list
+    """
+    SharedWith: [String!]
+}
+
 """
 Paginated list of RepoSummary objects
 """
@@ -1734,6 +2520,8 @@ type Query {
         requestedPage: PageInput
         "Search term for specific CVE by title/id"
         searchedCVE: String
+        "Only return CVEs with the given fix availability, see CVE.FixStatus"
+        fixStatus: FixStatus
     ): CVEResultForImage!
 
     """
@@ -1763,6 +2551,34 @@ type Query {
         requestedPage: PageInput
     ): PaginatedImagesResult!
 
+    """
+    Returns the list of licenses found in the image specified in the argument
+    """
+    LicenseListForImage(
+        "Image name in format ` + "`" + `repository:tag` + "`" + ` or ` + "`" + `repository@digest` + "`" + `"
+        image: String!
+    ): LicenseResultForImage!
+
+    """
+    Returns a list of images containing packages or files under any of the specified licenses
+    """
+    ImageListForLicense(
+        "Licenses to search for, a license matches if its name contains one of these values (case insensitive)"
+        licenses: [String!]!,
+        "Filter to apply before returning the results"
+        filter: Filter,
+        "Sets the parameters of the requested page"
+        requestedPage: PageInput
+    ): PaginatedImagesResult!
+
+    """
+    Returns the secrets found in the image specified in the argument when it was pushed
+    """
+    SecretListForImage(
+        "Image name in format ` + "`" + `repository:tag` + "`" + ` or ` + "`" + `repository@digest` + "`" + `"
+        image: String!
+    ): SecretResultForImage!
+
     """
     Returns a list of images which contain the specified digest 
     """
@@ -1874,9 +2690,56 @@ type Query {
         "Sets the parameters of the requested page (how many to include and offset)"
         requestedPage: PageInput
     ): PaginatedReposResult!
-}
-`, BuiltIn: false},
-}
+
+    """
+    Receive the bookmark lists of the current user, and the ones shared with the groups of the current user
+    """
+    BookmarkLists: [BookmarkList!]!
+
+    """
+    Receive RepoSummaries of the repos of a bookmark list
+    """
+    BookmarkListRepos(
+        "Name of the bookmark list"
+        name: String!,
+        "Owner of the bookmark list, the current user if not specified"
+        owner: String,
+        "Sets the parameters of the requested page (how many to include and offset)"
+        requestedPage: PageInput
+    ): PaginatedReposResult!
+
+    """
+    Returns the most downloaded repos, tags or digests, among the repos the user can read
+    """
+    TopDownloads(
+        "How the downloads are counted, by repository if not specified"
+        groupBy: DownloadsGroupBy,
+        "Only count the downloads of this repository"
+        repo: String,
+        "Start of the period, 30 days before its end if not specified"
+        from: Time,
+        "End of the period, now if not specified"
+        to: Time,
+        "Maximum number of results, 10 if not specified"
+        limit: Int
+    ): [DownloadCount!]!
+
+    """
+    Returns the downloads of every day of a period, of the repos the user can read
+    """
+    DownloadsOverTime(
+        "Only count the downloads of this repository"
+        repo: String,
+        "Only count the downloads of this tag or digest of the repository"
+        reference: String,
+        "Start of the period, 30 days before its end if not specified"
+        from: Time,
+        "End of the period, now if not specified"
+        to: Time
+    ): DownloadsOverTime!
+}
+`, BuiltIn: false},
+}
 var parsedSchema = gqlparser.MustLoadSchema(sources...)
 
 // endregion ************************** generated!.gotpl **************************
@@ -1916,6 +2779,39 @@ func (ec *executionContext) field_Query_BaseImageList_args(ctx context.Context,
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_BookmarkListRepos_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["name"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("name"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["name"] = arg0
+	var arg1 *string
+	if tmp, ok := rawArgs["owner"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("owner"))
+		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["owner"] = arg1
+	var arg2 *PageInput
+	if tmp, ok := rawArgs["requestedPage"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("requestedPage"))
+		arg2, err = ec.unmarshalOPageInput2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["requestedPage"] = arg2
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_BookmarkedRepos_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1961,6 +2857,15 @@ func (ec *executionContext) field_Query_CVEListForImage_args(ctx context.Context
 		}
 	}
 	args["searchedCVE"] = arg2
+	var arg3 *FixStatus
+	if tmp, ok := rawArgs["fixStatus"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fixStatus"))
+		arg3, err = ec.unmarshalOFixStatus2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐFixStatus(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["fixStatus"] = arg3
 	return args, nil
 }
 
@@ -1997,6 +2902,48 @@ func (ec *executionContext) field_Query_DerivedImageList_args(ctx context.Contex
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_DownloadsOverTime_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := rawArgs["repo"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("repo"))
+		arg0, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["repo"] = arg0
+	var arg1 *string
+	if tmp, ok := rawArgs["reference"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("reference"))
+		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["reference"] = arg1
+	var arg2 *time.Time
+	if tmp, ok := rawArgs["from"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("from"))
+		arg2, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["from"] = arg2
+	var arg3 *time.Time
+	if tmp, ok := rawArgs["to"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("to"))
+		arg3, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["to"] = arg3
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_ExpandedRepoInfo_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -2102,6 +3049,39 @@ func (ec *executionContext) field_Query_ImageListForDigest_args(ctx context.Cont
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_ImageListForLicense_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 []string
+	if tmp, ok := rawArgs["licenses"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("licenses"))
+		arg0, err = ec.unmarshalNString2ᚕstringᚄ(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["licenses"] = arg0
+	var arg1 *Filter
+	if tmp, ok := rawArgs["filter"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("filter"))
+		arg1, err = ec.unmarshalOFilter2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐFilter(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["filter"] = arg1
+	var arg2 *PageInput
+	if tmp, ok := rawArgs["requestedPage"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("requestedPage"))
+		arg2, err = ec.unmarshalOPageInput2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["requestedPage"] = arg2
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_ImageListWithCVEFixed_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -2183,6 +3163,21 @@ func (ec *executionContext) field_Query_Image_args(ctx context.Context, rawArgs
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_LicenseListForImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["image"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("image"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["image"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_Referrers_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -2231,6 +3226,21 @@ func (ec *executionContext) field_Query_RepoListWithNewestImage_args(ctx context
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_SecretListForImage_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["image"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("image"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["image"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_StarredRepos_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -2246,6 +3256,57 @@ func (ec *executionContext) field_Query_StarredRepos_args(ctx context.Context, r
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_TopDownloads_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *DownloadsGroupBy
+	if tmp, ok := rawArgs["groupBy"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("groupBy"))
+		arg0, err = ec.unmarshalODownloadsGroupBy2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsGroupBy(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["groupBy"] = arg0
+	var arg1 *string
+	if tmp, ok := rawArgs["repo"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("repo"))
+		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["repo"] = arg1
+	var arg2 *time.Time
+	if tmp, ok := rawArgs["from"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("from"))
+		arg2, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["from"] = arg2
+	var arg3 *time.Time
+	if tmp, ok := rawArgs["to"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("to"))
+		arg3, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["to"] = arg3
+	var arg4 *int
+	if tmp, ok := rawArgs["limit"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("limit"))
+		arg4, err = ec.unmarshalOInt2ᚖint(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["limit"] = arg4
+	return args, nil
+}
+
 func (ec *executionContext) field_Query___type_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -2381,8 +3442,8 @@ func (ec *executionContext) fieldContext_Annotation_Value(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _CVE_Id(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVE_Id(ctx, field)
+func (ec *executionContext) _BookmarkList_Name(ctx context.Context, field graphql.CollectedField, obj *BookmarkList) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_BookmarkList_Name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2395,7 +3456,7 @@ func (ec *executionContext) _CVE_Id(ctx context.Context, field graphql.Collected
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ID, nil
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2409,9 +3470,9 @@ func (ec *executionContext) _CVE_Id(ctx context.Context, field graphql.Collected
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVE_Id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_BookmarkList_Name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "CVE",
+		Object:     "BookmarkList",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2422,8 +3483,8 @@ func (ec *executionContext) fieldContext_CVE_Id(ctx context.Context, field graph
 	return fc, nil
 }
 
-func (ec *executionContext) _CVE_Title(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVE_Title(ctx, field)
+func (ec *executionContext) _BookmarkList_Owner(ctx context.Context, field graphql.CollectedField, obj *BookmarkList) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_BookmarkList_Owner(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2436,7 +3497,7 @@ func (ec *executionContext) _CVE_Title(ctx context.Context, field graphql.Collec
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Title, nil
+		return obj.Owner, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2450,9 +3511,9 @@ func (ec *executionContext) _CVE_Title(ctx context.Context, field graphql.Collec
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVE_Title(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_BookmarkList_Owner(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "CVE",
+		Object:     "BookmarkList",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2463,8 +3524,8 @@ func (ec *executionContext) fieldContext_CVE_Title(ctx context.Context, field gr
 	return fc, nil
 }
 
-func (ec *executionContext) _CVE_Description(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVE_Description(ctx, field)
+func (ec *executionContext) _BookmarkList_Repos(ctx context.Context, field graphql.CollectedField, obj *BookmarkList) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_BookmarkList_Repos(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2477,7 +3538,7 @@ func (ec *executionContext) _CVE_Description(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Description, nil
+		return obj.Repos, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2486,14 +3547,14 @@ func (ec *executionContext) _CVE_Description(ctx context.Context, field graphql.
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.([]string)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOString2ᚕstringᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVE_Description(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_BookmarkList_Repos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "CVE",
+		Object:     "BookmarkList",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2504,8 +3565,8 @@ func (ec *executionContext) fieldContext_CVE_Description(ctx context.Context, fi
 	return fc, nil
 }
 
-func (ec *executionContext) _CVE_Severity(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVE_Severity(ctx, field)
+func (ec *executionContext) _BookmarkList_SharedWith(ctx context.Context, field graphql.CollectedField, obj *BookmarkList) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_BookmarkList_SharedWith(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2518,7 +3579,7 @@ func (ec *executionContext) _CVE_Severity(ctx context.Context, field graphql.Col
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Severity, nil
+		return obj.SharedWith, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2527,14 +3588,14 @@ func (ec *executionContext) _CVE_Severity(ctx context.Context, field graphql.Col
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.([]string)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOString2ᚕstringᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVE_Severity(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_BookmarkList_SharedWith(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "CVE",
+		Object:     "BookmarkList",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2545,8 +3606,8 @@ func (ec *executionContext) fieldContext_CVE_Severity(ctx context.Context, field
 	return fc, nil
 }
 
-func (ec *executionContext) _CVE_PackageList(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVE_PackageList(ctx, field)
+func (ec *executionContext) _CVE_Id(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVE_Id(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2559,7 +3620,7 @@ func (ec *executionContext) _CVE_PackageList(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.PackageList, nil
+		return obj.ID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2568,34 +3629,26 @@ func (ec *executionContext) _CVE_PackageList(ctx context.Context, field graphql.
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*PackageInfo)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOPackageInfo2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageInfo(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVE_PackageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVE_Id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "CVE",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Name":
-				return ec.fieldContext_PackageInfo_Name(ctx, field)
-			case "InstalledVersion":
-				return ec.fieldContext_PackageInfo_InstalledVersion(ctx, field)
-			case "FixedVersion":
-				return ec.fieldContext_PackageInfo_FixedVersion(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PackageInfo", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _CVEResultForImage_Tag(ctx context.Context, field graphql.CollectedField, obj *CVEResultForImage) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVEResultForImage_Tag(ctx, field)
+func (ec *executionContext) _CVE_Title(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVE_Title(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2608,7 +3661,7 @@ func (ec *executionContext) _CVEResultForImage_Tag(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Tag, nil
+		return obj.Title, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2622,9 +3675,9 @@ func (ec *executionContext) _CVEResultForImage_Tag(ctx context.Context, field gr
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVEResultForImage_Tag(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVE_Title(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "CVEResultForImage",
+		Object:     "CVE",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2635,8 +3688,8 @@ func (ec *executionContext) fieldContext_CVEResultForImage_Tag(ctx context.Conte
 	return fc, nil
 }
 
-func (ec *executionContext) _CVEResultForImage_CVEList(ctx context.Context, field graphql.CollectedField, obj *CVEResultForImage) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVEResultForImage_CVEList(ctx, field)
+func (ec *executionContext) _CVE_Description(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVE_Description(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2649,7 +3702,7 @@ func (ec *executionContext) _CVEResultForImage_CVEList(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.CVEList, nil
+		return obj.Description, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2658,38 +3711,26 @@ func (ec *executionContext) _CVEResultForImage_CVEList(ctx context.Context, fiel
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*Cve)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOCVE2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCve(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVEResultForImage_CVEList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVE_Description(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "CVEResultForImage",
+		Object:     "CVE",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Id":
-				return ec.fieldContext_CVE_Id(ctx, field)
-			case "Title":
-				return ec.fieldContext_CVE_Title(ctx, field)
-			case "Description":
-				return ec.fieldContext_CVE_Description(ctx, field)
-			case "Severity":
-				return ec.fieldContext_CVE_Severity(ctx, field)
-			case "PackageList":
-				return ec.fieldContext_CVE_PackageList(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type CVE", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _CVEResultForImage_Page(ctx context.Context, field graphql.CollectedField, obj *CVEResultForImage) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_CVEResultForImage_Page(ctx, field)
+func (ec *executionContext) _CVE_Severity(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVE_Severity(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2702,7 +3743,7 @@ func (ec *executionContext) _CVEResultForImage_Page(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Page, nil
+		return obj.Severity, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2711,32 +3752,26 @@ func (ec *executionContext) _CVEResultForImage_Page(ctx context.Context, field g
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*PageInfo)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_CVEResultForImage_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVE_Severity(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "CVEResultForImage",
+		Object:     "CVE",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "TotalCount":
-				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
-			case "ItemCount":
-				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _GlobalSearchResult_Page(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_GlobalSearchResult_Page(ctx, field)
+func (ec *executionContext) _CVE_PackageList(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVE_PackageList(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2749,7 +3784,7 @@ func (ec *executionContext) _GlobalSearchResult_Page(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Page, nil
+		return obj.PackageList, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2758,32 +3793,36 @@ func (ec *executionContext) _GlobalSearchResult_Page(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*PageInfo)
+	res := resTmp.([]*PackageInfo)
 	fc.Result = res
-	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+	return ec.marshalOPackageInfo2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageInfo(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_GlobalSearchResult_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVE_PackageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "GlobalSearchResult",
+		Object:     "CVE",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "TotalCount":
-				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
-			case "ItemCount":
-				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
+			case "Name":
+				return ec.fieldContext_PackageInfo_Name(ctx, field)
+			case "InstalledVersion":
+				return ec.fieldContext_PackageInfo_InstalledVersion(ctx, field)
+			case "FixedVersion":
+				return ec.fieldContext_PackageInfo_FixedVersion(ctx, field)
+			case "HasFix":
+				return ec.fieldContext_PackageInfo_HasFix(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type PackageInfo", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _GlobalSearchResult_Images(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_GlobalSearchResult_Images(ctx, field)
+func (ec *executionContext) _CVE_HasFix(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVE_HasFix(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2796,7 +3835,7 @@ func (ec *executionContext) _GlobalSearchResult_Images(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Images, nil
+		return obj.HasFix, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2805,68 +3844,26 @@ func (ec *executionContext) _GlobalSearchResult_Images(ctx context.Context, fiel
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*ImageSummary)
+	res := resTmp.(*bool)
 	fc.Result = res
-	return ec.marshalOImageSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_GlobalSearchResult_Images(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVE_HasFix(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "GlobalSearchResult",
+		Object:     "CVE",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "RepoName":
-				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
-			case "Tag":
-				return ec.fieldContext_ImageSummary_Tag(ctx, field)
-			case "Digest":
-				return ec.fieldContext_ImageSummary_Digest(ctx, field)
-			case "MediaType":
-				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
-			case "Manifests":
-				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
-			case "Size":
-				return ec.fieldContext_ImageSummary_Size(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
-			case "Description":
-				return ec.fieldContext_ImageSummary_Description(ctx, field)
-			case "IsSigned":
-				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
-			case "SignatureInfo":
-				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
-			case "Licenses":
-				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
-			case "Labels":
-				return ec.fieldContext_ImageSummary_Labels(ctx, field)
-			case "Title":
-				return ec.fieldContext_ImageSummary_Title(ctx, field)
-			case "Source":
-				return ec.fieldContext_ImageSummary_Source(ctx, field)
-			case "Documentation":
-				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
-			case "Vendor":
-				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
-			case "Authors":
-				return ec.fieldContext_ImageSummary_Authors(ctx, field)
-			case "Vulnerabilities":
-				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
-			case "Referrers":
-				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _GlobalSearchResult_Repos(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_GlobalSearchResult_Repos(ctx, field)
+func (ec *executionContext) _CVE_FixStatus(ctx context.Context, field graphql.CollectedField, obj *Cve) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVE_FixStatus(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2879,7 +3876,7 @@ func (ec *executionContext) _GlobalSearchResult_Repos(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Repos, nil
+		return obj.FixStatus, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2888,50 +3885,26 @@ func (ec *executionContext) _GlobalSearchResult_Repos(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*RepoSummary)
+	res := resTmp.(*FixStatus)
 	fc.Result = res
-	return ec.marshalORepoSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoSummary(ctx, field.Selections, res)
+	return ec.marshalOFixStatus2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐFixStatus(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_GlobalSearchResult_Repos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVE_FixStatus(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "GlobalSearchResult",
+		Object:     "CVE",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Name":
-				return ec.fieldContext_RepoSummary_Name(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
-			case "Size":
-				return ec.fieldContext_RepoSummary_Size(ctx, field)
-			case "Platforms":
-				return ec.fieldContext_RepoSummary_Platforms(ctx, field)
-			case "Vendors":
-				return ec.fieldContext_RepoSummary_Vendors(ctx, field)
-			case "NewestImage":
-				return ec.fieldContext_RepoSummary_NewestImage(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
-			case "StarCount":
-				return ec.fieldContext_RepoSummary_StarCount(ctx, field)
-			case "IsBookmarked":
-				return ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
-			case "IsStarred":
-				return ec.fieldContext_RepoSummary_IsStarred(ctx, field)
-			case "Rank":
-				return ec.fieldContext_RepoSummary_Rank(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RepoSummary", field.Name)
+			return nil, errors.New("field of type FixStatus does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _GlobalSearchResult_Layers(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_GlobalSearchResult_Layers(ctx, field)
+func (ec *executionContext) _CVEResultForImage_Tag(ctx context.Context, field graphql.CollectedField, obj *CVEResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVEResultForImage_Tag(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2944,7 +3917,7 @@ func (ec *executionContext) _GlobalSearchResult_Layers(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Layers, nil
+		return obj.Tag, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2953,32 +3926,26 @@ func (ec *executionContext) _GlobalSearchResult_Layers(ctx context.Context, fiel
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*LayerSummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOLayerSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerSummary(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_GlobalSearchResult_Layers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVEResultForImage_Tag(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "GlobalSearchResult",
+		Object:     "CVEResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Size":
-				return ec.fieldContext_LayerSummary_Size(ctx, field)
-			case "Digest":
-				return ec.fieldContext_LayerSummary_Digest(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LayerSummary", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _HistoryDescription_Created(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_HistoryDescription_Created(ctx, field)
+func (ec *executionContext) _CVEResultForImage_CVEList(ctx context.Context, field graphql.CollectedField, obj *CVEResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVEResultForImage_CVEList(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2991,7 +3958,7 @@ func (ec *executionContext) _HistoryDescription_Created(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Created, nil
+		return obj.CVEList, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3000,26 +3967,42 @@ func (ec *executionContext) _HistoryDescription_Created(ctx context.Context, fie
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.([]*Cve)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOCVE2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCve(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_HistoryDescription_Created(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVEResultForImage_CVEList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "HistoryDescription",
+		Object:     "CVEResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			switch field.Name {
+			case "Id":
+				return ec.fieldContext_CVE_Id(ctx, field)
+			case "Title":
+				return ec.fieldContext_CVE_Title(ctx, field)
+			case "Description":
+				return ec.fieldContext_CVE_Description(ctx, field)
+			case "Severity":
+				return ec.fieldContext_CVE_Severity(ctx, field)
+			case "PackageList":
+				return ec.fieldContext_CVE_PackageList(ctx, field)
+			case "HasFix":
+				return ec.fieldContext_CVE_HasFix(ctx, field)
+			case "FixStatus":
+				return ec.fieldContext_CVE_FixStatus(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type CVE", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _HistoryDescription_CreatedBy(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_HistoryDescription_CreatedBy(ctx, field)
+func (ec *executionContext) _CVEResultForImage_Page(ctx context.Context, field graphql.CollectedField, obj *CVEResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVEResultForImage_Page(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3032,7 +4015,7 @@ func (ec *executionContext) _HistoryDescription_CreatedBy(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.CreatedBy, nil
+		return obj.Page, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3041,26 +4024,32 @@ func (ec *executionContext) _HistoryDescription_CreatedBy(ctx context.Context, f
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*PageInfo)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_HistoryDescription_CreatedBy(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVEResultForImage_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "HistoryDescription",
+		Object:     "CVEResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "TotalCount":
+				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
+			case "ItemCount":
+				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _HistoryDescription_Author(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_HistoryDescription_Author(ctx, field)
+func (ec *executionContext) _CVEResultForImage_UpgradeAdvice(ctx context.Context, field graphql.CollectedField, obj *CVEResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_CVEResultForImage_UpgradeAdvice(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3073,7 +4062,7 @@ func (ec *executionContext) _HistoryDescription_Author(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Author, nil
+		return obj.UpgradeAdvice, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3082,26 +4071,38 @@ func (ec *executionContext) _HistoryDescription_Author(ctx context.Context, fiel
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.([]*PackageUpgrade)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOPackageUpgrade2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageUpgrade(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_HistoryDescription_Author(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_CVEResultForImage_UpgradeAdvice(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "HistoryDescription",
+		Object:     "CVEResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "Name":
+				return ec.fieldContext_PackageUpgrade_Name(ctx, field)
+			case "InstalledVersion":
+				return ec.fieldContext_PackageUpgrade_InstalledVersion(ctx, field)
+			case "RecommendedVersion":
+				return ec.fieldContext_PackageUpgrade_RecommendedVersion(ctx, field)
+			case "FixedVersions":
+				return ec.fieldContext_PackageUpgrade_FixedVersions(ctx, field)
+			case "CVEIds":
+				return ec.fieldContext_PackageUpgrade_CVEIds(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PackageUpgrade", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _HistoryDescription_Comment(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_HistoryDescription_Comment(ctx, field)
+func (ec *executionContext) _DownloadCount_Repo(ctx context.Context, field graphql.CollectedField, obj *DownloadCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadCount_Repo(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3114,7 +4115,7 @@ func (ec *executionContext) _HistoryDescription_Comment(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Comment, nil
+		return obj.Repo, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3128,9 +4129,9 @@ func (ec *executionContext) _HistoryDescription_Comment(ctx context.Context, fie
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_HistoryDescription_Comment(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadCount_Repo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "HistoryDescription",
+		Object:     "DownloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3141,8 +4142,8 @@ func (ec *executionContext) fieldContext_HistoryDescription_Comment(ctx context.
 	return fc, nil
 }
 
-func (ec *executionContext) _HistoryDescription_EmptyLayer(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_HistoryDescription_EmptyLayer(ctx, field)
+func (ec *executionContext) _DownloadCount_Reference(ctx context.Context, field graphql.CollectedField, obj *DownloadCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadCount_Reference(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3155,7 +4156,7 @@ func (ec *executionContext) _HistoryDescription_EmptyLayer(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EmptyLayer, nil
+		return obj.Reference, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3164,26 +4165,26 @@ func (ec *executionContext) _HistoryDescription_EmptyLayer(ctx context.Context,
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_HistoryDescription_EmptyLayer(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadCount_Reference(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "HistoryDescription",
+		Object:     "DownloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_RepoName(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_RepoName(ctx, field)
+func (ec *executionContext) _DownloadCount_Count(ctx context.Context, field graphql.CollectedField, obj *DownloadCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadCount_Count(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3196,7 +4197,7 @@ func (ec *executionContext) _ImageSummary_RepoName(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.RepoName, nil
+		return obj.Count, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3205,26 +4206,26 @@ func (ec *executionContext) _ImageSummary_RepoName(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_RepoName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadCount_Count(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Tag(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Tag(ctx, field)
+func (ec *executionContext) _DownloadCount_UniqueClients(ctx context.Context, field graphql.CollectedField, obj *DownloadCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadCount_UniqueClients(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3237,7 +4238,7 @@ func (ec *executionContext) _ImageSummary_Tag(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Tag, nil
+		return obj.UniqueClients, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3246,26 +4247,26 @@ func (ec *executionContext) _ImageSummary_Tag(ctx context.Context, field graphql
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Tag(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadCount_UniqueClients(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Digest(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Digest(ctx, field)
+func (ec *executionContext) _DownloadsOverTime_Total(ctx context.Context, field graphql.CollectedField, obj *DownloadsOverTime) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadsOverTime_Total(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3278,7 +4279,7 @@ func (ec *executionContext) _ImageSummary_Digest(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Digest, nil
+		return obj.Total, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3287,26 +4288,26 @@ func (ec *executionContext) _ImageSummary_Digest(ctx context.Context, field grap
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadsOverTime_Total(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadsOverTime",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_MediaType(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_MediaType(ctx, field)
+func (ec *executionContext) _DownloadsOverTime_UniqueClients(ctx context.Context, field graphql.CollectedField, obj *DownloadsOverTime) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadsOverTime_UniqueClients(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3319,7 +4320,7 @@ func (ec *executionContext) _ImageSummary_MediaType(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.MediaType, nil
+		return obj.UniqueClients, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3328,26 +4329,26 @@ func (ec *executionContext) _ImageSummary_MediaType(ctx context.Context, field g
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_MediaType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadsOverTime_UniqueClients(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadsOverTime",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Manifests(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Manifests(ctx, field)
+func (ec *executionContext) _DownloadsOverTime_Points(ctx context.Context, field graphql.CollectedField, obj *DownloadsOverTime) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadsOverTime_Points(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3360,63 +4361,46 @@ func (ec *executionContext) _ImageSummary_Manifests(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Manifests, nil
+		return obj.Points, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.([]*ManifestSummary)
+	res := resTmp.([]*DownloadsPoint)
 	fc.Result = res
-	return ec.marshalOManifestSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐManifestSummary(ctx, field.Selections, res)
+	return ec.marshalNDownloadsPoint2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsPointᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Manifests(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadsOverTime_Points(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadsOverTime",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Digest":
-				return ec.fieldContext_ManifestSummary_Digest(ctx, field)
-			case "ConfigDigest":
-				return ec.fieldContext_ManifestSummary_ConfigDigest(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_ManifestSummary_LastUpdated(ctx, field)
-			case "Size":
-				return ec.fieldContext_ManifestSummary_Size(ctx, field)
-			case "IsSigned":
-				return ec.fieldContext_ManifestSummary_IsSigned(ctx, field)
-			case "SignatureInfo":
-				return ec.fieldContext_ManifestSummary_SignatureInfo(ctx, field)
-			case "Platform":
-				return ec.fieldContext_ManifestSummary_Platform(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_ManifestSummary_DownloadCount(ctx, field)
-			case "Layers":
-				return ec.fieldContext_ManifestSummary_Layers(ctx, field)
-			case "History":
-				return ec.fieldContext_ManifestSummary_History(ctx, field)
-			case "Vulnerabilities":
-				return ec.fieldContext_ManifestSummary_Vulnerabilities(ctx, field)
-			case "Referrers":
-				return ec.fieldContext_ManifestSummary_Referrers(ctx, field)
-			case "ArtifactType":
-				return ec.fieldContext_ManifestSummary_ArtifactType(ctx, field)
+			case "Day":
+				return ec.fieldContext_DownloadsPoint_Day(ctx, field)
+			case "Count":
+				return ec.fieldContext_DownloadsPoint_Count(ctx, field)
+			case "UniqueClients":
+				return ec.fieldContext_DownloadsPoint_UniqueClients(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type ManifestSummary", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type DownloadsPoint", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Size(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Size(ctx, field)
+func (ec *executionContext) _DownloadsPoint_Day(ctx context.Context, field graphql.CollectedField, obj *DownloadsPoint) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadsPoint_Day(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3429,7 +4413,7 @@ func (ec *executionContext) _ImageSummary_Size(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Size, nil
+		return obj.Day, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3443,9 +4427,9 @@ func (ec *executionContext) _ImageSummary_Size(ctx context.Context, field graphq
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadsPoint_Day(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadsPoint",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3456,8 +4440,8 @@ func (ec *executionContext) fieldContext_ImageSummary_Size(ctx context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
+func (ec *executionContext) _DownloadsPoint_Count(ctx context.Context, field graphql.CollectedField, obj *DownloadsPoint) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadsPoint_Count(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3470,7 +4454,7 @@ func (ec *executionContext) _ImageSummary_DownloadCount(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DownloadCount, nil
+		return obj.Count, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3484,9 +4468,9 @@ func (ec *executionContext) _ImageSummary_DownloadCount(ctx context.Context, fie
 	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_DownloadCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadsPoint_Count(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadsPoint",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3497,8 +4481,8 @@ func (ec *executionContext) fieldContext_ImageSummary_DownloadCount(ctx context.
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_LastUpdated(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
+func (ec *executionContext) _DownloadsPoint_UniqueClients(ctx context.Context, field graphql.CollectedField, obj *DownloadsPoint) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_DownloadsPoint_UniqueClients(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3511,7 +4495,7 @@ func (ec *executionContext) _ImageSummary_LastUpdated(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.LastUpdated, nil
+		return obj.UniqueClients, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3520,26 +4504,26 @@ func (ec *executionContext) _ImageSummary_LastUpdated(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_LastUpdated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_DownloadsPoint_UniqueClients(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "DownloadsPoint",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Description(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Description(ctx, field)
+func (ec *executionContext) _GlobalSearchResult_Page(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_GlobalSearchResult_Page(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3552,7 +4536,7 @@ func (ec *executionContext) _ImageSummary_Description(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Description, nil
+		return obj.Page, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3561,26 +4545,32 @@ func (ec *executionContext) _ImageSummary_Description(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*PageInfo)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Description(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_GlobalSearchResult_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "GlobalSearchResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "TotalCount":
+				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
+			case "ItemCount":
+				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_IsSigned(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_IsSigned(ctx, field)
+func (ec *executionContext) _GlobalSearchResult_Images(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_GlobalSearchResult_Images(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3593,7 +4583,7 @@ func (ec *executionContext) _ImageSummary_IsSigned(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsSigned, nil
+		return obj.Images, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3602,26 +4592,70 @@ func (ec *executionContext) _ImageSummary_IsSigned(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.([]*ImageSummary)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalOImageSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_IsSigned(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_GlobalSearchResult_Images(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "GlobalSearchResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			switch field.Name {
+			case "RepoName":
+				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
+			case "Tag":
+				return ec.fieldContext_ImageSummary_Tag(ctx, field)
+			case "Digest":
+				return ec.fieldContext_ImageSummary_Digest(ctx, field)
+			case "MediaType":
+				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
+			case "Manifests":
+				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
+			case "Size":
+				return ec.fieldContext_ImageSummary_Size(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_ImageSummary_LastPulled(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
+			case "Description":
+				return ec.fieldContext_ImageSummary_Description(ctx, field)
+			case "IsSigned":
+				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
+			case "SignatureInfo":
+				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
+			case "Licenses":
+				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
+			case "Labels":
+				return ec.fieldContext_ImageSummary_Labels(ctx, field)
+			case "Title":
+				return ec.fieldContext_ImageSummary_Title(ctx, field)
+			case "Source":
+				return ec.fieldContext_ImageSummary_Source(ctx, field)
+			case "Documentation":
+				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
+			case "Vendor":
+				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
+			case "Authors":
+				return ec.fieldContext_ImageSummary_Authors(ctx, field)
+			case "Vulnerabilities":
+				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
+			case "Referrers":
+				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
+func (ec *executionContext) _GlobalSearchResult_Repos(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_GlobalSearchResult_Repos(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3634,7 +4668,7 @@ func (ec *executionContext) _ImageSummary_SignatureInfo(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.SignatureInfo, nil
+		return obj.Repos, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3643,34 +4677,52 @@ func (ec *executionContext) _ImageSummary_SignatureInfo(ctx context.Context, fie
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*SignatureSummary)
+	res := resTmp.([]*RepoSummary)
 	fc.Result = res
-	return ec.marshalOSignatureSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSignatureSummary(ctx, field.Selections, res)
+	return ec.marshalORepoSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_GlobalSearchResult_Repos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "GlobalSearchResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Tool":
-				return ec.fieldContext_SignatureSummary_Tool(ctx, field)
-			case "IsTrusted":
-				return ec.fieldContext_SignatureSummary_IsTrusted(ctx, field)
-			case "Author":
-				return ec.fieldContext_SignatureSummary_Author(ctx, field)
+			case "Name":
+				return ec.fieldContext_RepoSummary_Name(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
+			case "Size":
+				return ec.fieldContext_RepoSummary_Size(ctx, field)
+			case "Platforms":
+				return ec.fieldContext_RepoSummary_Platforms(ctx, field)
+			case "Vendors":
+				return ec.fieldContext_RepoSummary_Vendors(ctx, field)
+			case "NewestImage":
+				return ec.fieldContext_RepoSummary_NewestImage(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_RepoSummary_LastPulled(ctx, field)
+			case "StarCount":
+				return ec.fieldContext_RepoSummary_StarCount(ctx, field)
+			case "IsBookmarked":
+				return ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
+			case "IsStarred":
+				return ec.fieldContext_RepoSummary_IsStarred(ctx, field)
+			case "Rank":
+				return ec.fieldContext_RepoSummary_Rank(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type SignatureSummary", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type RepoSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Licenses(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Licenses(ctx, field)
+func (ec *executionContext) _GlobalSearchResult_Layers(ctx context.Context, field graphql.CollectedField, obj *GlobalSearchResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_GlobalSearchResult_Layers(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3683,7 +4735,7 @@ func (ec *executionContext) _ImageSummary_Licenses(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Licenses, nil
+		return obj.Layers, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3692,26 +4744,32 @@ func (ec *executionContext) _ImageSummary_Licenses(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.([]*LayerSummary)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOLayerSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Licenses(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_GlobalSearchResult_Layers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "GlobalSearchResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "Size":
+				return ec.fieldContext_LayerSummary_Size(ctx, field)
+			case "Digest":
+				return ec.fieldContext_LayerSummary_Digest(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LayerSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Labels(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Labels(ctx, field)
+func (ec *executionContext) _HistoryDescription_Created(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_HistoryDescription_Created(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3724,7 +4782,7 @@ func (ec *executionContext) _ImageSummary_Labels(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Labels, nil
+		return obj.Created, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3733,26 +4791,26 @@ func (ec *executionContext) _ImageSummary_Labels(ctx context.Context, field grap
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Labels(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HistoryDescription_Created(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "HistoryDescription",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Title(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Title(ctx, field)
+func (ec *executionContext) _HistoryDescription_CreatedBy(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_HistoryDescription_CreatedBy(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3765,7 +4823,7 @@ func (ec *executionContext) _ImageSummary_Title(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Title, nil
+		return obj.CreatedBy, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3779,9 +4837,9 @@ func (ec *executionContext) _ImageSummary_Title(ctx context.Context, field graph
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Title(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HistoryDescription_CreatedBy(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "HistoryDescription",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3792,8 +4850,8 @@ func (ec *executionContext) fieldContext_ImageSummary_Title(ctx context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Source(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Source(ctx, field)
+func (ec *executionContext) _HistoryDescription_Author(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_HistoryDescription_Author(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3806,7 +4864,7 @@ func (ec *executionContext) _ImageSummary_Source(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Source, nil
+		return obj.Author, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3820,9 +4878,9 @@ func (ec *executionContext) _ImageSummary_Source(ctx context.Context, field grap
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Source(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HistoryDescription_Author(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "HistoryDescription",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3833,8 +4891,8 @@ func (ec *executionContext) fieldContext_ImageSummary_Source(ctx context.Context
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Documentation(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Documentation(ctx, field)
+func (ec *executionContext) _HistoryDescription_Comment(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_HistoryDescription_Comment(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3847,7 +4905,7 @@ func (ec *executionContext) _ImageSummary_Documentation(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Documentation, nil
+		return obj.Comment, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3861,9 +4919,9 @@ func (ec *executionContext) _ImageSummary_Documentation(ctx context.Context, fie
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Documentation(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HistoryDescription_Comment(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "HistoryDescription",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -3874,8 +4932,8 @@ func (ec *executionContext) fieldContext_ImageSummary_Documentation(ctx context.
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Vendor(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Vendor(ctx, field)
+func (ec *executionContext) _HistoryDescription_EmptyLayer(ctx context.Context, field graphql.CollectedField, obj *HistoryDescription) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_HistoryDescription_EmptyLayer(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3888,7 +4946,7 @@ func (ec *executionContext) _ImageSummary_Vendor(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Vendor, nil
+		return obj.EmptyLayer, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3897,26 +4955,26 @@ func (ec *executionContext) _ImageSummary_Vendor(ctx context.Context, field grap
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*bool)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Vendor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_HistoryDescription_EmptyLayer(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageSummary",
+		Object:     "HistoryDescription",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Authors(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Authors(ctx, field)
+func (ec *executionContext) _ImageSummary_RepoName(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_RepoName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3929,7 +4987,7 @@ func (ec *executionContext) _ImageSummary_Authors(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Authors, nil
+		return obj.RepoName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3943,7 +5001,7 @@ func (ec *executionContext) _ImageSummary_Authors(ctx context.Context, field gra
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Authors(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_RepoName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "ImageSummary",
 		Field:      field,
@@ -3956,8 +5014,8 @@ func (ec *executionContext) fieldContext_ImageSummary_Authors(ctx context.Contex
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
+func (ec *executionContext) _ImageSummary_Tag(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Tag(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3970,7 +5028,7 @@ func (ec *executionContext) _ImageSummary_Vulnerabilities(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Vulnerabilities, nil
+		return obj.Tag, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3979,32 +5037,26 @@ func (ec *executionContext) _ImageSummary_Vulnerabilities(ctx context.Context, f
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*ImageVulnerabilitySummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOImageVulnerabilitySummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageVulnerabilitySummary(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Tag(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "MaxSeverity":
-				return ec.fieldContext_ImageVulnerabilitySummary_MaxSeverity(ctx, field)
-			case "Count":
-				return ec.fieldContext_ImageVulnerabilitySummary_Count(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type ImageVulnerabilitySummary", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageSummary_Referrers(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageSummary_Referrers(ctx, field)
+func (ec *executionContext) _ImageSummary_Digest(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Digest(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4017,7 +5069,7 @@ func (ec *executionContext) _ImageSummary_Referrers(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Referrers, nil
+		return obj.Digest, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4026,38 +5078,26 @@ func (ec *executionContext) _ImageSummary_Referrers(ctx context.Context, field g
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*Referrer)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOReferrer2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐReferrer(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageSummary_Referrers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "MediaType":
-				return ec.fieldContext_Referrer_MediaType(ctx, field)
-			case "ArtifactType":
-				return ec.fieldContext_Referrer_ArtifactType(ctx, field)
-			case "Size":
-				return ec.fieldContext_Referrer_Size(ctx, field)
-			case "Digest":
-				return ec.fieldContext_Referrer_Digest(ctx, field)
-			case "Annotations":
-				return ec.fieldContext_Referrer_Annotations(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Referrer", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageVulnerabilitySummary_MaxSeverity(ctx context.Context, field graphql.CollectedField, obj *ImageVulnerabilitySummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageVulnerabilitySummary_MaxSeverity(ctx, field)
+func (ec *executionContext) _ImageSummary_MediaType(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_MediaType(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4070,7 +5110,7 @@ func (ec *executionContext) _ImageVulnerabilitySummary_MaxSeverity(ctx context.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.MaxSeverity, nil
+		return obj.MediaType, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4084,9 +5124,9 @@ func (ec *executionContext) _ImageVulnerabilitySummary_MaxSeverity(ctx context.C
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageVulnerabilitySummary_MaxSeverity(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_MediaType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageVulnerabilitySummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -4097,8 +5137,8 @@ func (ec *executionContext) fieldContext_ImageVulnerabilitySummary_MaxSeverity(c
 	return fc, nil
 }
 
-func (ec *executionContext) _ImageVulnerabilitySummary_Count(ctx context.Context, field graphql.CollectedField, obj *ImageVulnerabilitySummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ImageVulnerabilitySummary_Count(ctx, field)
+func (ec *executionContext) _ImageSummary_Manifests(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Manifests(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4111,7 +5151,7 @@ func (ec *executionContext) _ImageVulnerabilitySummary_Count(ctx context.Context
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Count, nil
+		return obj.Manifests, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4120,26 +5160,56 @@ func (ec *executionContext) _ImageVulnerabilitySummary_Count(ctx context.Context
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*int)
+	res := resTmp.([]*ManifestSummary)
 	fc.Result = res
-	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+	return ec.marshalOManifestSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐManifestSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ImageVulnerabilitySummary_Count(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Manifests(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ImageVulnerabilitySummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "Digest":
+				return ec.fieldContext_ManifestSummary_Digest(ctx, field)
+			case "ConfigDigest":
+				return ec.fieldContext_ManifestSummary_ConfigDigest(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_ManifestSummary_LastUpdated(ctx, field)
+			case "Size":
+				return ec.fieldContext_ManifestSummary_Size(ctx, field)
+			case "IsSigned":
+				return ec.fieldContext_ManifestSummary_IsSigned(ctx, field)
+			case "SignatureInfo":
+				return ec.fieldContext_ManifestSummary_SignatureInfo(ctx, field)
+			case "Platform":
+				return ec.fieldContext_ManifestSummary_Platform(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_ManifestSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_ManifestSummary_LastPulled(ctx, field)
+			case "Layers":
+				return ec.fieldContext_ManifestSummary_Layers(ctx, field)
+			case "History":
+				return ec.fieldContext_ManifestSummary_History(ctx, field)
+			case "Vulnerabilities":
+				return ec.fieldContext_ManifestSummary_Vulnerabilities(ctx, field)
+			case "Referrers":
+				return ec.fieldContext_ManifestSummary_Referrers(ctx, field)
+			case "ArtifactType":
+				return ec.fieldContext_ManifestSummary_ArtifactType(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ManifestSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LayerHistory_Layer(ctx context.Context, field graphql.CollectedField, obj *LayerHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LayerHistory_Layer(ctx, field)
+func (ec *executionContext) _ImageSummary_Size(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Size(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4152,7 +5222,7 @@ func (ec *executionContext) _LayerHistory_Layer(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Layer, nil
+		return obj.Size, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4161,32 +5231,26 @@ func (ec *executionContext) _LayerHistory_Layer(ctx context.Context, field graph
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*LayerSummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOLayerSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerSummary(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LayerHistory_Layer(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LayerHistory",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Size":
-				return ec.fieldContext_LayerSummary_Size(ctx, field)
-			case "Digest":
-				return ec.fieldContext_LayerSummary_Digest(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LayerSummary", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LayerHistory_HistoryDescription(ctx context.Context, field graphql.CollectedField, obj *LayerHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LayerHistory_HistoryDescription(ctx, field)
+func (ec *executionContext) _ImageSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4199,7 +5263,7 @@ func (ec *executionContext) _LayerHistory_HistoryDescription(ctx context.Context
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.HistoryDescription, nil
+		return obj.DownloadCount, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4208,38 +5272,26 @@ func (ec *executionContext) _LayerHistory_HistoryDescription(ctx context.Context
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*HistoryDescription)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOHistoryDescription2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐHistoryDescription(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LayerHistory_HistoryDescription(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_DownloadCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LayerHistory",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Created":
-				return ec.fieldContext_HistoryDescription_Created(ctx, field)
-			case "CreatedBy":
-				return ec.fieldContext_HistoryDescription_CreatedBy(ctx, field)
-			case "Author":
-				return ec.fieldContext_HistoryDescription_Author(ctx, field)
-			case "Comment":
-				return ec.fieldContext_HistoryDescription_Comment(ctx, field)
-			case "EmptyLayer":
-				return ec.fieldContext_HistoryDescription_EmptyLayer(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type HistoryDescription", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LayerSummary_Size(ctx context.Context, field graphql.CollectedField, obj *LayerSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LayerSummary_Size(ctx, field)
+func (ec *executionContext) _ImageSummary_LastPulled(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_LastPulled(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4252,7 +5304,7 @@ func (ec *executionContext) _LayerSummary_Size(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Size, nil
+		return obj.LastPulled, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4261,26 +5313,26 @@ func (ec *executionContext) _LayerSummary_Size(ctx context.Context, field graphq
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LayerSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_LastPulled(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LayerSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LayerSummary_Digest(ctx context.Context, field graphql.CollectedField, obj *LayerSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LayerSummary_Digest(ctx, field)
+func (ec *executionContext) _ImageSummary_LastUpdated(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4293,7 +5345,7 @@ func (ec *executionContext) _LayerSummary_Digest(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Digest, nil
+		return obj.LastUpdated, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4302,26 +5354,26 @@ func (ec *executionContext) _LayerSummary_Digest(ctx context.Context, field grap
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LayerSummary_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_LastUpdated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LayerSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_Digest(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_Digest(ctx, field)
+func (ec *executionContext) _ImageSummary_Description(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Description(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4334,7 +5386,7 @@ func (ec *executionContext) _ManifestSummary_Digest(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Digest, nil
+		return obj.Description, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4348,9 +5400,9 @@ func (ec *executionContext) _ManifestSummary_Digest(ctx context.Context, field g
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Description(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -4361,8 +5413,8 @@ func (ec *executionContext) fieldContext_ManifestSummary_Digest(ctx context.Cont
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_ConfigDigest(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_ConfigDigest(ctx, field)
+func (ec *executionContext) _ImageSummary_IsSigned(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_IsSigned(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4375,7 +5427,7 @@ func (ec *executionContext) _ManifestSummary_ConfigDigest(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ConfigDigest, nil
+		return obj.IsSigned, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4384,26 +5436,26 @@ func (ec *executionContext) _ManifestSummary_ConfigDigest(ctx context.Context, f
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*bool)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_ConfigDigest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_IsSigned(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_LastUpdated(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_LastUpdated(ctx, field)
+func (ec *executionContext) _ImageSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4416,7 +5468,7 @@ func (ec *executionContext) _ManifestSummary_LastUpdated(ctx context.Context, fi
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.LastUpdated, nil
+		return obj.SignatureInfo, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4425,26 +5477,34 @@ func (ec *executionContext) _ManifestSummary_LastUpdated(ctx context.Context, fi
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.([]*SignatureSummary)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOSignatureSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSignatureSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_LastUpdated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			switch field.Name {
+			case "Tool":
+				return ec.fieldContext_SignatureSummary_Tool(ctx, field)
+			case "IsTrusted":
+				return ec.fieldContext_SignatureSummary_IsTrusted(ctx, field)
+			case "Author":
+				return ec.fieldContext_SignatureSummary_Author(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SignatureSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_Size(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_Size(ctx, field)
+func (ec *executionContext) _ImageSummary_Licenses(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Licenses(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4457,7 +5517,7 @@ func (ec *executionContext) _ManifestSummary_Size(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Size, nil
+		return obj.Licenses, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4471,9 +5531,9 @@ func (ec *executionContext) _ManifestSummary_Size(ctx context.Context, field gra
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Licenses(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -4484,8 +5544,8 @@ func (ec *executionContext) fieldContext_ManifestSummary_Size(ctx context.Contex
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_IsSigned(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_IsSigned(ctx, field)
+func (ec *executionContext) _ImageSummary_Labels(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Labels(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4498,7 +5558,7 @@ func (ec *executionContext) _ManifestSummary_IsSigned(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsSigned, nil
+		return obj.Labels, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4507,26 +5567,26 @@ func (ec *executionContext) _ManifestSummary_IsSigned(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_IsSigned(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Labels(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_SignatureInfo(ctx, field)
+func (ec *executionContext) _ImageSummary_Title(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Title(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4539,7 +5599,7 @@ func (ec *executionContext) _ManifestSummary_SignatureInfo(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.SignatureInfo, nil
+		return obj.Title, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4548,34 +5608,26 @@ func (ec *executionContext) _ManifestSummary_SignatureInfo(ctx context.Context,
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*SignatureSummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOSignatureSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSignatureSummary(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Title(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Tool":
-				return ec.fieldContext_SignatureSummary_Tool(ctx, field)
-			case "IsTrusted":
-				return ec.fieldContext_SignatureSummary_IsTrusted(ctx, field)
-			case "Author":
-				return ec.fieldContext_SignatureSummary_Author(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type SignatureSummary", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_Platform(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_Platform(ctx, field)
+func (ec *executionContext) _ImageSummary_Source(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Source(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4588,7 +5640,7 @@ func (ec *executionContext) _ManifestSummary_Platform(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Platform, nil
+		return obj.Source, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4597,32 +5649,26 @@ func (ec *executionContext) _ManifestSummary_Platform(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*Platform)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOPlatform2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPlatform(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_Platform(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Source(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Os":
-				return ec.fieldContext_Platform_Os(ctx, field)
-			case "Arch":
-				return ec.fieldContext_Platform_Arch(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Platform", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_DownloadCount(ctx, field)
+func (ec *executionContext) _ImageSummary_Documentation(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Documentation(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4635,7 +5681,7 @@ func (ec *executionContext) _ManifestSummary_DownloadCount(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DownloadCount, nil
+		return obj.Documentation, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4644,26 +5690,26 @@ func (ec *executionContext) _ManifestSummary_DownloadCount(ctx context.Context,
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_DownloadCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Documentation(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_Layers(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_Layers(ctx, field)
+func (ec *executionContext) _ImageSummary_Vendor(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Vendor(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4676,7 +5722,7 @@ func (ec *executionContext) _ManifestSummary_Layers(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Layers, nil
+		return obj.Vendor, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4685,32 +5731,26 @@ func (ec *executionContext) _ManifestSummary_Layers(ctx context.Context, field g
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*LayerSummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOLayerSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerSummary(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_Layers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Vendor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Size":
-				return ec.fieldContext_LayerSummary_Size(ctx, field)
-			case "Digest":
-				return ec.fieldContext_LayerSummary_Digest(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LayerSummary", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_History(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_History(ctx, field)
+func (ec *executionContext) _ImageSummary_Authors(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Authors(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4723,7 +5763,7 @@ func (ec *executionContext) _ManifestSummary_History(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.History, nil
+		return obj.Authors, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4732,32 +5772,26 @@ func (ec *executionContext) _ManifestSummary_History(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*LayerHistory)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOLayerHistory2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerHistory(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_History(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Authors(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Layer":
-				return ec.fieldContext_LayerHistory_Layer(ctx, field)
-			case "HistoryDescription":
-				return ec.fieldContext_LayerHistory_HistoryDescription(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LayerHistory", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_Vulnerabilities(ctx, field)
+func (ec *executionContext) _ImageSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4784,9 +5818,9 @@ func (ec *executionContext) _ManifestSummary_Vulnerabilities(ctx context.Context
 	return ec.marshalOImageVulnerabilitySummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageVulnerabilitySummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -4803,8 +5837,8 @@ func (ec *executionContext) fieldContext_ManifestSummary_Vulnerabilities(ctx con
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_Referrers(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_Referrers(ctx, field)
+func (ec *executionContext) _ImageSummary_Referrers(ctx context.Context, field graphql.CollectedField, obj *ImageSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageSummary_Referrers(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4831,9 +5865,9 @@ func (ec *executionContext) _ManifestSummary_Referrers(ctx context.Context, fiel
 	return ec.marshalOReferrer2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐReferrer(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_Referrers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageSummary_Referrers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -4856,8 +5890,8 @@ func (ec *executionContext) fieldContext_ManifestSummary_Referrers(ctx context.C
 	return fc, nil
 }
 
-func (ec *executionContext) _ManifestSummary_ArtifactType(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_ManifestSummary_ArtifactType(ctx, field)
+func (ec *executionContext) _ImageVulnerabilitySummary_MaxSeverity(ctx context.Context, field graphql.CollectedField, obj *ImageVulnerabilitySummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageVulnerabilitySummary_MaxSeverity(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4870,7 +5904,7 @@ func (ec *executionContext) _ManifestSummary_ArtifactType(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ArtifactType, nil
+		return obj.MaxSeverity, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4884,9 +5918,9 @@ func (ec *executionContext) _ManifestSummary_ArtifactType(ctx context.Context, f
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_ManifestSummary_ArtifactType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageVulnerabilitySummary_MaxSeverity(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "ManifestSummary",
+		Object:     "ImageVulnerabilitySummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -4897,8 +5931,8 @@ func (ec *executionContext) fieldContext_ManifestSummary_ArtifactType(ctx contex
 	return fc, nil
 }
 
-func (ec *executionContext) _PackageInfo_Name(ctx context.Context, field graphql.CollectedField, obj *PackageInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PackageInfo_Name(ctx, field)
+func (ec *executionContext) _ImageVulnerabilitySummary_Count(ctx context.Context, field graphql.CollectedField, obj *ImageVulnerabilitySummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ImageVulnerabilitySummary_Count(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4911,7 +5945,7 @@ func (ec *executionContext) _PackageInfo_Name(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.Count, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4920,26 +5954,26 @@ func (ec *executionContext) _PackageInfo_Name(ctx context.Context, field graphql
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PackageInfo_Name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ImageVulnerabilitySummary_Count(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PackageInfo",
+		Object:     "ImageVulnerabilitySummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PackageInfo_InstalledVersion(ctx context.Context, field graphql.CollectedField, obj *PackageInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PackageInfo_InstalledVersion(ctx, field)
+func (ec *executionContext) _LayerHistory_Layer(ctx context.Context, field graphql.CollectedField, obj *LayerHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LayerHistory_Layer(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4952,7 +5986,7 @@ func (ec *executionContext) _PackageInfo_InstalledVersion(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.InstalledVersion, nil
+		return obj.Layer, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4961,26 +5995,32 @@ func (ec *executionContext) _PackageInfo_InstalledVersion(ctx context.Context, f
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*LayerSummary)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOLayerSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PackageInfo_InstalledVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LayerHistory_Layer(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PackageInfo",
+		Object:     "LayerHistory",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "Size":
+				return ec.fieldContext_LayerSummary_Size(ctx, field)
+			case "Digest":
+				return ec.fieldContext_LayerSummary_Digest(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LayerSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PackageInfo_FixedVersion(ctx context.Context, field graphql.CollectedField, obj *PackageInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PackageInfo_FixedVersion(ctx, field)
+func (ec *executionContext) _LayerHistory_HistoryDescription(ctx context.Context, field graphql.CollectedField, obj *LayerHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LayerHistory_HistoryDescription(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4993,7 +6033,7 @@ func (ec *executionContext) _PackageInfo_FixedVersion(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FixedVersion, nil
+		return obj.HistoryDescription, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5002,26 +6042,38 @@ func (ec *executionContext) _PackageInfo_FixedVersion(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*HistoryDescription)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOHistoryDescription2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐHistoryDescription(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PackageInfo_FixedVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LayerHistory_HistoryDescription(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PackageInfo",
+		Object:     "LayerHistory",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "Created":
+				return ec.fieldContext_HistoryDescription_Created(ctx, field)
+			case "CreatedBy":
+				return ec.fieldContext_HistoryDescription_CreatedBy(ctx, field)
+			case "Author":
+				return ec.fieldContext_HistoryDescription_Author(ctx, field)
+			case "Comment":
+				return ec.fieldContext_HistoryDescription_Comment(ctx, field)
+			case "EmptyLayer":
+				return ec.fieldContext_HistoryDescription_EmptyLayer(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type HistoryDescription", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_TotalCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PageInfo_TotalCount(ctx, field)
+func (ec *executionContext) _LayerSummary_Size(ctx context.Context, field graphql.CollectedField, obj *LayerSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LayerSummary_Size(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5034,39 +6086,36 @@ func (ec *executionContext) _PageInfo_TotalCount(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.TotalCount, nil
+		return obj.Size, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_TotalCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LayerSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "LayerSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PageInfo_ItemCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PageInfo_ItemCount(ctx, field)
-	if err != nil {
+func (ec *executionContext) _LayerSummary_Digest(ctx context.Context, field graphql.CollectedField, obj *LayerSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LayerSummary_Digest(ctx, field)
+	if err != nil {
 		return graphql.Null
 	}
 	ctx = graphql.WithFieldContext(ctx, fc)
@@ -5078,38 +6127,35 @@ func (ec *executionContext) _PageInfo_ItemCount(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ItemCount, nil
+		return obj.Digest, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PageInfo_ItemCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LayerSummary_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PageInfo",
+		Object:     "LayerSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+func (ec *executionContext) _License_Name(ctx context.Context, field graphql.CollectedField, obj *License) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_License_Name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5122,7 +6168,7 @@ func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Page, nil
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5131,32 +6177,26 @@ func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, fie
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*PageInfo)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PaginatedImagesResult_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_License_Name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PaginatedImagesResult",
+		Object:     "License",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "TotalCount":
-				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
-			case "ItemCount":
-				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PaginatedImagesResult_Results(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+func (ec *executionContext) _License_Category(ctx context.Context, field graphql.CollectedField, obj *License) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_License_Category(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5169,80 +6209,35 @@ func (ec *executionContext) _PaginatedImagesResult_Results(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Results, nil
+		return obj.Category, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*ImageSummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNImageSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummaryᚄ(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PaginatedImagesResult_Results(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_License_Category(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PaginatedImagesResult",
+		Object:     "License",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "RepoName":
-				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
-			case "Tag":
-				return ec.fieldContext_ImageSummary_Tag(ctx, field)
-			case "Digest":
-				return ec.fieldContext_ImageSummary_Digest(ctx, field)
-			case "MediaType":
-				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
-			case "Manifests":
-				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
-			case "Size":
-				return ec.fieldContext_ImageSummary_Size(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
-			case "Description":
-				return ec.fieldContext_ImageSummary_Description(ctx, field)
-			case "IsSigned":
-				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
-			case "SignatureInfo":
-				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
-			case "Licenses":
-				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
-			case "Labels":
-				return ec.fieldContext_ImageSummary_Labels(ctx, field)
-			case "Title":
-				return ec.fieldContext_ImageSummary_Title(ctx, field)
-			case "Source":
-				return ec.fieldContext_ImageSummary_Source(ctx, field)
-			case "Documentation":
-				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
-			case "Vendor":
-				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
-			case "Authors":
-				return ec.fieldContext_ImageSummary_Authors(ctx, field)
-			case "Vulnerabilities":
-				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
-			case "Referrers":
-				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PaginatedReposResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedReposResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+func (ec *executionContext) _License_Severity(ctx context.Context, field graphql.CollectedField, obj *License) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_License_Severity(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5255,7 +6250,7 @@ func (ec *executionContext) _PaginatedReposResult_Page(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Page, nil
+		return obj.Severity, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5264,32 +6259,26 @@ func (ec *executionContext) _PaginatedReposResult_Page(ctx context.Context, fiel
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*PageInfo)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PaginatedReposResult_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_License_Severity(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PaginatedReposResult",
+		Object:     "License",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "TotalCount":
-				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
-			case "ItemCount":
-				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _PaginatedReposResult_Results(ctx context.Context, field graphql.CollectedField, obj *PaginatedReposResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_PaginatedReposResult_Results(ctx, field)
+func (ec *executionContext) _License_PackageName(ctx context.Context, field graphql.CollectedField, obj *License) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_License_PackageName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5302,62 +6291,35 @@ func (ec *executionContext) _PaginatedReposResult_Results(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Results, nil
+		return obj.PackageName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*RepoSummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNRepoSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoSummaryᚄ(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_PaginatedReposResult_Results(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_License_PackageName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "PaginatedReposResult",
+		Object:     "License",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Name":
-				return ec.fieldContext_RepoSummary_Name(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
-			case "Size":
-				return ec.fieldContext_RepoSummary_Size(ctx, field)
-			case "Platforms":
-				return ec.fieldContext_RepoSummary_Platforms(ctx, field)
-			case "Vendors":
-				return ec.fieldContext_RepoSummary_Vendors(ctx, field)
-			case "NewestImage":
-				return ec.fieldContext_RepoSummary_NewestImage(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
-			case "StarCount":
-				return ec.fieldContext_RepoSummary_StarCount(ctx, field)
-			case "IsBookmarked":
-				return ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
-			case "IsStarred":
-				return ec.fieldContext_RepoSummary_IsStarred(ctx, field)
-			case "Rank":
-				return ec.fieldContext_RepoSummary_Rank(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RepoSummary", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Platform_Os(ctx context.Context, field graphql.CollectedField, obj *Platform) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Platform_Os(ctx, field)
+func (ec *executionContext) _License_FilePath(ctx context.Context, field graphql.CollectedField, obj *License) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_License_FilePath(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5370,7 +6332,7 @@ func (ec *executionContext) _Platform_Os(ctx context.Context, field graphql.Coll
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Os, nil
+		return obj.FilePath, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5384,9 +6346,9 @@ func (ec *executionContext) _Platform_Os(ctx context.Context, field graphql.Coll
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Platform_Os(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_License_FilePath(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Platform",
+		Object:     "License",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -5397,8 +6359,8 @@ func (ec *executionContext) fieldContext_Platform_Os(ctx context.Context, field
 	return fc, nil
 }
 
-func (ec *executionContext) _Platform_Arch(ctx context.Context, field graphql.CollectedField, obj *Platform) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Platform_Arch(ctx, field)
+func (ec *executionContext) _LicenseResultForImage_Tag(ctx context.Context, field graphql.CollectedField, obj *LicenseResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LicenseResultForImage_Tag(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5411,7 +6373,7 @@ func (ec *executionContext) _Platform_Arch(ctx context.Context, field graphql.Co
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Arch, nil
+		return obj.Tag, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5425,9 +6387,9 @@ func (ec *executionContext) _Platform_Arch(ctx context.Context, field graphql.Co
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Platform_Arch(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LicenseResultForImage_Tag(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Platform",
+		Object:     "LicenseResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -5438,8 +6400,8 @@ func (ec *executionContext) fieldContext_Platform_Arch(ctx context.Context, fiel
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_CVEListForImage(ctx, field)
+func (ec *executionContext) _LicenseResultForImage_LicenseList(ctx context.Context, field graphql.CollectedField, obj *LicenseResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LicenseResultForImage_LicenseList(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5452,57 +6414,47 @@ func (ec *executionContext) _Query_CVEListForImage(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().CVEListForImage(rctx, fc.Args["image"].(string), fc.Args["requestedPage"].(*PageInput), fc.Args["searchedCVE"].(*string))
+		return obj.LicenseList, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*CVEResultForImage)
+	res := resTmp.([]*License)
 	fc.Result = res
-	return ec.marshalNCVEResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx, field.Selections, res)
+	return ec.marshalOLicense2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLicense(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LicenseResultForImage_LicenseList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "LicenseResultForImage",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Tag":
-				return ec.fieldContext_CVEResultForImage_Tag(ctx, field)
-			case "CVEList":
-				return ec.fieldContext_CVEResultForImage_CVEList(ctx, field)
-			case "Page":
-				return ec.fieldContext_CVEResultForImage_Page(ctx, field)
+			case "Name":
+				return ec.fieldContext_License_Name(ctx, field)
+			case "Category":
+				return ec.fieldContext_License_Category(ctx, field)
+			case "Severity":
+				return ec.fieldContext_License_Severity(ctx, field)
+			case "PackageName":
+				return ec.fieldContext_License_PackageName(ctx, field)
+			case "FilePath":
+				return ec.fieldContext_License_FilePath(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type CVEResultForImage", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type License", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_CVEListForImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_ImageListForCVE(ctx, field)
+func (ec *executionContext) _ManifestSummary_Digest(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_Digest(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5515,55 +6467,35 @@ func (ec *executionContext) _Query_ImageListForCVE(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().ImageListForCve(rctx, fc.Args["id"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+		return obj.Digest, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedImagesResult)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_ImageListForCVE_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_ImageListWithCVEFixed(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_ImageListWithCVEFixed(ctx, field)
+func (ec *executionContext) _ManifestSummary_ConfigDigest(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_ConfigDigest(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5576,55 +6508,35 @@ func (ec *executionContext) _Query_ImageListWithCVEFixed(ctx context.Context, fi
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().ImageListWithCVEFixed(rctx, fc.Args["id"].(string), fc.Args["image"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+		return obj.ConfigDigest, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedImagesResult)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_ImageListWithCVEFixed(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_ConfigDigest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_ImageListWithCVEFixed_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_ImageListForDigest(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_ImageListForDigest(ctx, field)
+func (ec *executionContext) _ManifestSummary_LastUpdated(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_LastUpdated(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5637,55 +6549,35 @@ func (ec *executionContext) _Query_ImageListForDigest(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().ImageListForDigest(rctx, fc.Args["id"].(string), fc.Args["requestedPage"].(*PageInput))
+		return obj.LastUpdated, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedImagesResult)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_ImageListForDigest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_LastUpdated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_ImageListForDigest_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_RepoListWithNewestImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_RepoListWithNewestImage(ctx, field)
+func (ec *executionContext) _ManifestSummary_Size(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_Size(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5698,55 +6590,35 @@ func (ec *executionContext) _Query_RepoListWithNewestImage(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().RepoListWithNewestImage(rctx, fc.Args["requestedPage"].(*PageInput))
+		return obj.Size, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedReposResult)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNPaginatedReposResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedReposResult(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_RepoListWithNewestImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedReposResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedReposResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedReposResult", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_RepoListWithNewestImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_ImageList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_ImageList(ctx, field)
+func (ec *executionContext) _ManifestSummary_IsSigned(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_IsSigned(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5759,55 +6631,35 @@ func (ec *executionContext) _Query_ImageList(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().ImageList(rctx, fc.Args["repo"].(string), fc.Args["requestedPage"].(*PageInput))
+		return obj.IsSigned, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedImagesResult)
+	res := resTmp.(*bool)
 	fc.Result = res
-	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_ImageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_IsSigned(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_ImageList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_ExpandedRepoInfo(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_ExpandedRepoInfo(ctx, field)
+func (ec *executionContext) _ManifestSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_SignatureInfo(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5820,55 +6672,43 @@ func (ec *executionContext) _Query_ExpandedRepoInfo(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().ExpandedRepoInfo(rctx, fc.Args["repo"].(string))
+		return obj.SignatureInfo, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*RepoInfo)
+	res := resTmp.([]*SignatureSummary)
 	fc.Result = res
-	return ec.marshalNRepoInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoInfo(ctx, field.Selections, res)
+	return ec.marshalOSignatureSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSignatureSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_ExpandedRepoInfo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_SignatureInfo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Images":
-				return ec.fieldContext_RepoInfo_Images(ctx, field)
-			case "Summary":
-				return ec.fieldContext_RepoInfo_Summary(ctx, field)
+			case "Tool":
+				return ec.fieldContext_SignatureSummary_Tool(ctx, field)
+			case "IsTrusted":
+				return ec.fieldContext_SignatureSummary_IsTrusted(ctx, field)
+			case "Author":
+				return ec.fieldContext_SignatureSummary_Author(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type RepoInfo", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type SignatureSummary", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_ExpandedRepoInfo_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_GlobalSearch(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_GlobalSearch(ctx, field)
+func (ec *executionContext) _ManifestSummary_Platform(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_Platform(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5881,59 +6721,41 @@ func (ec *executionContext) _Query_GlobalSearch(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().GlobalSearch(rctx, fc.Args["query"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+		return obj.Platform, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*GlobalSearchResult)
+	res := resTmp.(*Platform)
 	fc.Result = res
-	return ec.marshalNGlobalSearchResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐGlobalSearchResult(ctx, field.Selections, res)
+	return ec.marshalOPlatform2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPlatform(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_GlobalSearch(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_Platform(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Page":
-				return ec.fieldContext_GlobalSearchResult_Page(ctx, field)
-			case "Images":
-				return ec.fieldContext_GlobalSearchResult_Images(ctx, field)
-			case "Repos":
-				return ec.fieldContext_GlobalSearchResult_Repos(ctx, field)
-			case "Layers":
-				return ec.fieldContext_GlobalSearchResult_Layers(ctx, field)
+			case "Os":
+				return ec.fieldContext_Platform_Os(ctx, field)
+			case "Arch":
+				return ec.fieldContext_Platform_Arch(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type GlobalSearchResult", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Platform", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_GlobalSearch_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_DerivedImageList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_DerivedImageList(ctx, field)
+func (ec *executionContext) _ManifestSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_DownloadCount(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5946,55 +6768,2731 @@ func (ec *executionContext) _Query_DerivedImageList(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().DerivedImageList(rctx, fc.Args["image"].(string), fc.Args["digest"].(*string), fc.Args["requestedPage"].(*PageInput))
+		return obj.DownloadCount, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedImagesResult)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_DerivedImageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ManifestSummary_DownloadCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "ManifestSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
-	defer func() {
+	return fc, nil
+}
+
+func (ec *executionContext) _ManifestSummary_LastPulled(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_LastPulled(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.LastPulled, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*time.Time)
+	fc.Result = res
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ManifestSummary_LastPulled(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ManifestSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ManifestSummary_Layers(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_Layers(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Layers, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*LayerSummary)
+	fc.Result = res
+	return ec.marshalOLayerSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerSummary(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ManifestSummary_Layers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ManifestSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Size":
+				return ec.fieldContext_LayerSummary_Size(ctx, field)
+			case "Digest":
+				return ec.fieldContext_LayerSummary_Digest(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LayerSummary", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ManifestSummary_History(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_History(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.History, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*LayerHistory)
+	fc.Result = res
+	return ec.marshalOLayerHistory2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLayerHistory(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ManifestSummary_History(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ManifestSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Layer":
+				return ec.fieldContext_LayerHistory_Layer(ctx, field)
+			case "HistoryDescription":
+				return ec.fieldContext_LayerHistory_HistoryDescription(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LayerHistory", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ManifestSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_Vulnerabilities(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Vulnerabilities, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*ImageVulnerabilitySummary)
+	fc.Result = res
+	return ec.marshalOImageVulnerabilitySummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageVulnerabilitySummary(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ManifestSummary_Vulnerabilities(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ManifestSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "MaxSeverity":
+				return ec.fieldContext_ImageVulnerabilitySummary_MaxSeverity(ctx, field)
+			case "Count":
+				return ec.fieldContext_ImageVulnerabilitySummary_Count(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ImageVulnerabilitySummary", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ManifestSummary_Referrers(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_Referrers(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Referrers, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*Referrer)
+	fc.Result = res
+	return ec.marshalOReferrer2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐReferrer(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ManifestSummary_Referrers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ManifestSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "MediaType":
+				return ec.fieldContext_Referrer_MediaType(ctx, field)
+			case "ArtifactType":
+				return ec.fieldContext_Referrer_ArtifactType(ctx, field)
+			case "Size":
+				return ec.fieldContext_Referrer_Size(ctx, field)
+			case "Digest":
+				return ec.fieldContext_Referrer_Digest(ctx, field)
+			case "Annotations":
+				return ec.fieldContext_Referrer_Annotations(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Referrer", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ManifestSummary_ArtifactType(ctx context.Context, field graphql.CollectedField, obj *ManifestSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ManifestSummary_ArtifactType(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ArtifactType, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ManifestSummary_ArtifactType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ManifestSummary",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageInfo_Name(ctx context.Context, field graphql.CollectedField, obj *PackageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageInfo_Name(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageInfo_Name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageInfo_InstalledVersion(ctx context.Context, field graphql.CollectedField, obj *PackageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageInfo_InstalledVersion(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.InstalledVersion, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageInfo_InstalledVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageInfo_FixedVersion(ctx context.Context, field graphql.CollectedField, obj *PackageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageInfo_FixedVersion(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.FixedVersion, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageInfo_FixedVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageInfo_HasFix(ctx context.Context, field graphql.CollectedField, obj *PackageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageInfo_HasFix(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HasFix, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*bool)
+	fc.Result = res
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageInfo_HasFix(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageUpgrade_Name(ctx context.Context, field graphql.CollectedField, obj *PackageUpgrade) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageUpgrade_Name(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageUpgrade_Name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageUpgrade",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageUpgrade_InstalledVersion(ctx context.Context, field graphql.CollectedField, obj *PackageUpgrade) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageUpgrade_InstalledVersion(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.InstalledVersion, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageUpgrade_InstalledVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageUpgrade",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageUpgrade_RecommendedVersion(ctx context.Context, field graphql.CollectedField, obj *PackageUpgrade) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageUpgrade_RecommendedVersion(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.RecommendedVersion, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageUpgrade_RecommendedVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageUpgrade",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageUpgrade_FixedVersions(ctx context.Context, field graphql.CollectedField, obj *PackageUpgrade) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageUpgrade_FixedVersions(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.FixedVersions, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*string)
+	fc.Result = res
+	return ec.marshalOString2ᚕᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageUpgrade_FixedVersions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageUpgrade",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PackageUpgrade_CVEIds(ctx context.Context, field graphql.CollectedField, obj *PackageUpgrade) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PackageUpgrade_CVEIds(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.CVEIds, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*string)
+	fc.Result = res
+	return ec.marshalOString2ᚕᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PackageUpgrade_CVEIds(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PackageUpgrade",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_TotalCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_TotalCount(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.TotalCount, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_TotalCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_ItemCount(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_ItemCount(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ItemCount, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_ItemCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PaginatedImagesResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Page, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*PageInfo)
+	fc.Result = res
+	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PaginatedImagesResult_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PaginatedImagesResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "TotalCount":
+				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
+			case "ItemCount":
+				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PaginatedImagesResult_Results(ctx context.Context, field graphql.CollectedField, obj *PaginatedImagesResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Results, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*ImageSummary)
+	fc.Result = res
+	return ec.marshalNImageSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummaryᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PaginatedImagesResult_Results(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PaginatedImagesResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "RepoName":
+				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
+			case "Tag":
+				return ec.fieldContext_ImageSummary_Tag(ctx, field)
+			case "Digest":
+				return ec.fieldContext_ImageSummary_Digest(ctx, field)
+			case "MediaType":
+				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
+			case "Manifests":
+				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
+			case "Size":
+				return ec.fieldContext_ImageSummary_Size(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_ImageSummary_LastPulled(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
+			case "Description":
+				return ec.fieldContext_ImageSummary_Description(ctx, field)
+			case "IsSigned":
+				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
+			case "SignatureInfo":
+				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
+			case "Licenses":
+				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
+			case "Labels":
+				return ec.fieldContext_ImageSummary_Labels(ctx, field)
+			case "Title":
+				return ec.fieldContext_ImageSummary_Title(ctx, field)
+			case "Source":
+				return ec.fieldContext_ImageSummary_Source(ctx, field)
+			case "Documentation":
+				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
+			case "Vendor":
+				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
+			case "Authors":
+				return ec.fieldContext_ImageSummary_Authors(ctx, field)
+			case "Vulnerabilities":
+				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
+			case "Referrers":
+				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PaginatedReposResult_Page(ctx context.Context, field graphql.CollectedField, obj *PaginatedReposResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Page, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*PageInfo)
+	fc.Result = res
+	return ec.marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PaginatedReposResult_Page(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PaginatedReposResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "TotalCount":
+				return ec.fieldContext_PageInfo_TotalCount(ctx, field)
+			case "ItemCount":
+				return ec.fieldContext_PageInfo_ItemCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PaginatedReposResult_Results(ctx context.Context, field graphql.CollectedField, obj *PaginatedReposResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PaginatedReposResult_Results(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Results, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*RepoSummary)
+	fc.Result = res
+	return ec.marshalNRepoSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoSummaryᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PaginatedReposResult_Results(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PaginatedReposResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Name":
+				return ec.fieldContext_RepoSummary_Name(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
+			case "Size":
+				return ec.fieldContext_RepoSummary_Size(ctx, field)
+			case "Platforms":
+				return ec.fieldContext_RepoSummary_Platforms(ctx, field)
+			case "Vendors":
+				return ec.fieldContext_RepoSummary_Vendors(ctx, field)
+			case "NewestImage":
+				return ec.fieldContext_RepoSummary_NewestImage(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_RepoSummary_LastPulled(ctx, field)
+			case "StarCount":
+				return ec.fieldContext_RepoSummary_StarCount(ctx, field)
+			case "IsBookmarked":
+				return ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
+			case "IsStarred":
+				return ec.fieldContext_RepoSummary_IsStarred(ctx, field)
+			case "Rank":
+				return ec.fieldContext_RepoSummary_Rank(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RepoSummary", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Platform_Os(ctx context.Context, field graphql.CollectedField, obj *Platform) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Platform_Os(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Os, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Platform_Os(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Platform",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Platform_Arch(ctx context.Context, field graphql.CollectedField, obj *Platform) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Platform_Arch(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Arch, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Platform_Arch(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Platform",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_CVEListForImage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().CVEListForImage(rctx, fc.Args["image"].(string), fc.Args["requestedPage"].(*PageInput), fc.Args["searchedCVE"].(*string), fc.Args["fixStatus"].(*FixStatus))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*CVEResultForImage)
+	fc.Result = res
+	return ec.marshalNCVEResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_CVEListForImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Tag":
+				return ec.fieldContext_CVEResultForImage_Tag(ctx, field)
+			case "CVEList":
+				return ec.fieldContext_CVEResultForImage_CVEList(ctx, field)
+			case "Page":
+				return ec.fieldContext_CVEResultForImage_Page(ctx, field)
+			case "UpgradeAdvice":
+				return ec.fieldContext_CVEResultForImage_UpgradeAdvice(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type CVEResultForImage", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_CVEListForImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ImageListForCVE(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().ImageListForCve(rctx, fc.Args["id"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedImagesResult)
+	fc.Result = res
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_ImageListForCVE(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_ImageListForCVE_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_ImageListWithCVEFixed(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ImageListWithCVEFixed(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().ImageListWithCVEFixed(rctx, fc.Args["id"].(string), fc.Args["image"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedImagesResult)
+	fc.Result = res
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_ImageListWithCVEFixed(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_ImageListWithCVEFixed_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_LicenseListForImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_LicenseListForImage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().LicenseListForImage(rctx, fc.Args["image"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*LicenseResultForImage)
+	fc.Result = res
+	return ec.marshalNLicenseResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLicenseResultForImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_LicenseListForImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Tag":
+				return ec.fieldContext_LicenseResultForImage_Tag(ctx, field)
+			case "LicenseList":
+				return ec.fieldContext_LicenseResultForImage_LicenseList(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LicenseResultForImage", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_LicenseListForImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_ImageListForLicense(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ImageListForLicense(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().ImageListForLicense(rctx, fc.Args["licenses"].([]string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedImagesResult)
+	fc.Result = res
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_ImageListForLicense(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_ImageListForLicense_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_SecretListForImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_SecretListForImage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().SecretListForImage(rctx, fc.Args["image"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*SecretResultForImage)
+	fc.Result = res
+	return ec.marshalNSecretResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSecretResultForImage(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_SecretListForImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Tag":
+				return ec.fieldContext_SecretResultForImage_Tag(ctx, field)
+			case "Digest":
+				return ec.fieldContext_SecretResultForImage_Digest(ctx, field)
+			case "SecretList":
+				return ec.fieldContext_SecretResultForImage_SecretList(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SecretResultForImage", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_SecretListForImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_ImageListForDigest(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ImageListForDigest(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().ImageListForDigest(rctx, fc.Args["id"].(string), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedImagesResult)
+	fc.Result = res
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_ImageListForDigest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_ImageListForDigest_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_RepoListWithNewestImage(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_RepoListWithNewestImage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().RepoListWithNewestImage(rctx, fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedReposResult)
+	fc.Result = res
+	return ec.marshalNPaginatedReposResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedReposResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_RepoListWithNewestImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedReposResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedReposResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_RepoListWithNewestImage_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_ImageList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ImageList(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().ImageList(rctx, fc.Args["repo"].(string), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedImagesResult)
+	fc.Result = res
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_ImageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_ImageList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_ExpandedRepoInfo(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_ExpandedRepoInfo(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().ExpandedRepoInfo(rctx, fc.Args["repo"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*RepoInfo)
+	fc.Result = res
+	return ec.marshalNRepoInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoInfo(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_ExpandedRepoInfo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Images":
+				return ec.fieldContext_RepoInfo_Images(ctx, field)
+			case "Summary":
+				return ec.fieldContext_RepoInfo_Summary(ctx, field)
+			case "Readme":
+				return ec.fieldContext_RepoInfo_Readme(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RepoInfo", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_ExpandedRepoInfo_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_GlobalSearch(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_GlobalSearch(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().GlobalSearch(rctx, fc.Args["query"].(string), fc.Args["filter"].(*Filter), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*GlobalSearchResult)
+	fc.Result = res
+	return ec.marshalNGlobalSearchResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐGlobalSearchResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_GlobalSearch(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_GlobalSearchResult_Page(ctx, field)
+			case "Images":
+				return ec.fieldContext_GlobalSearchResult_Images(ctx, field)
+			case "Repos":
+				return ec.fieldContext_GlobalSearchResult_Repos(ctx, field)
+			case "Layers":
+				return ec.fieldContext_GlobalSearchResult_Layers(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type GlobalSearchResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_GlobalSearch_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_DerivedImageList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_DerivedImageList(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().DerivedImageList(rctx, fc.Args["image"].(string), fc.Args["digest"].(*string), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedImagesResult)
+	fc.Result = res
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_DerivedImageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_DerivedImageList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_BaseImageList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_BaseImageList(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().BaseImageList(rctx, fc.Args["image"].(string), fc.Args["digest"].(*string), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedImagesResult)
+	fc.Result = res
+	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_BaseImageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_BaseImageList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_Image(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_Image(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Image(rctx, fc.Args["image"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*ImageSummary)
+	fc.Result = res
+	return ec.marshalNImageSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_Image(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "RepoName":
+				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
+			case "Tag":
+				return ec.fieldContext_ImageSummary_Tag(ctx, field)
+			case "Digest":
+				return ec.fieldContext_ImageSummary_Digest(ctx, field)
+			case "MediaType":
+				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
+			case "Manifests":
+				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
+			case "Size":
+				return ec.fieldContext_ImageSummary_Size(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_ImageSummary_LastPulled(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
+			case "Description":
+				return ec.fieldContext_ImageSummary_Description(ctx, field)
+			case "IsSigned":
+				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
+			case "SignatureInfo":
+				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
+			case "Licenses":
+				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
+			case "Labels":
+				return ec.fieldContext_ImageSummary_Labels(ctx, field)
+			case "Title":
+				return ec.fieldContext_ImageSummary_Title(ctx, field)
+			case "Source":
+				return ec.fieldContext_ImageSummary_Source(ctx, field)
+			case "Documentation":
+				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
+			case "Vendor":
+				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
+			case "Authors":
+				return ec.fieldContext_ImageSummary_Authors(ctx, field)
+			case "Vulnerabilities":
+				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
+			case "Referrers":
+				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_Image_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_Referrers(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_Referrers(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Referrers(rctx, fc.Args["repo"].(string), fc.Args["digest"].(string), fc.Args["type"].([]string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*Referrer)
+	fc.Result = res
+	return ec.marshalNReferrer2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐReferrer(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_Referrers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "MediaType":
+				return ec.fieldContext_Referrer_MediaType(ctx, field)
+			case "ArtifactType":
+				return ec.fieldContext_Referrer_ArtifactType(ctx, field)
+			case "Size":
+				return ec.fieldContext_Referrer_Size(ctx, field)
+			case "Digest":
+				return ec.fieldContext_Referrer_Digest(ctx, field)
+			case "Annotations":
+				return ec.fieldContext_Referrer_Annotations(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Referrer", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_Referrers_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_StarredRepos(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_StarredRepos(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().StarredRepos(rctx, fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedReposResult)
+	fc.Result = res
+	return ec.marshalNPaginatedReposResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedReposResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_StarredRepos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedReposResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedReposResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_StarredRepos_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_BookmarkedRepos(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_BookmarkedRepos(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().BookmarkedRepos(rctx, fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedReposResult)
+	fc.Result = res
+	return ec.marshalNPaginatedReposResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedReposResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_BookmarkedRepos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedReposResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedReposResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_BookmarkedRepos_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_BookmarkLists(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_BookmarkLists(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().BookmarkLists(rctx)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*BookmarkList)
+	fc.Result = res
+	return ec.marshalNBookmarkList2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐBookmarkListᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_BookmarkLists(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Name":
+				return ec.fieldContext_BookmarkList_Name(ctx, field)
+			case "Owner":
+				return ec.fieldContext_BookmarkList_Owner(ctx, field)
+			case "Repos":
+				return ec.fieldContext_BookmarkList_Repos(ctx, field)
+			case "SharedWith":
+				return ec.fieldContext_BookmarkList_SharedWith(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type BookmarkList", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_BookmarkListRepos(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_BookmarkListRepos(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().BookmarkListRepos(rctx, fc.Args["name"].(string), fc.Args["owner"].(*string), fc.Args["requestedPage"].(*PageInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PaginatedReposResult)
+	fc.Result = res
+	return ec.marshalNPaginatedReposResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedReposResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_BookmarkListRepos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Page":
+				return ec.fieldContext_PaginatedReposResult_Page(ctx, field)
+			case "Results":
+				return ec.fieldContext_PaginatedReposResult_Results(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PaginatedReposResult", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_BookmarkListRepos_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_TopDownloads(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_TopDownloads(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().TopDownloads(rctx, fc.Args["groupBy"].(*DownloadsGroupBy), fc.Args["repo"].(*string), fc.Args["from"].(*time.Time), fc.Args["to"].(*time.Time), fc.Args["limit"].(*int))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*DownloadCount)
+	fc.Result = res
+	return ec.marshalNDownloadCount2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadCountᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_TopDownloads(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Repo":
+				return ec.fieldContext_DownloadCount_Repo(ctx, field)
+			case "Reference":
+				return ec.fieldContext_DownloadCount_Reference(ctx, field)
+			case "Count":
+				return ec.fieldContext_DownloadCount_Count(ctx, field)
+			case "UniqueClients":
+				return ec.fieldContext_DownloadCount_UniqueClients(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type DownloadCount", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_TopDownloads_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_DownloadsOverTime(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_DownloadsOverTime(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().DownloadsOverTime(rctx, fc.Args["repo"].(*string), fc.Args["reference"].(*string), fc.Args["from"].(*time.Time), fc.Args["to"].(*time.Time))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*DownloadsOverTime)
+	fc.Result = res
+	return ec.marshalNDownloadsOverTime2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsOverTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_DownloadsOverTime(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Total":
+				return ec.fieldContext_DownloadsOverTime_Total(ctx, field)
+			case "UniqueClients":
+				return ec.fieldContext_DownloadsOverTime_UniqueClients(ctx, field)
+			case "Points":
+				return ec.fieldContext_DownloadsOverTime_Points(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type DownloadsOverTime", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_DownloadsOverTime_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query___type(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.introspectType(fc.Args["name"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
+	fc.Result = res
+	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "kind":
+				return ec.fieldContext___Type_kind(ctx, field)
+			case "name":
+				return ec.fieldContext___Type_name(ctx, field)
+			case "description":
+				return ec.fieldContext___Type_description(ctx, field)
+			case "fields":
+				return ec.fieldContext___Type_fields(ctx, field)
+			case "interfaces":
+				return ec.fieldContext___Type_interfaces(ctx, field)
+			case "possibleTypes":
+				return ec.fieldContext___Type_possibleTypes(ctx, field)
+			case "enumValues":
+				return ec.fieldContext___Type_enumValues(ctx, field)
+			case "inputFields":
+				return ec.fieldContext___Type_inputFields(ctx, field)
+			case "ofType":
+				return ec.fieldContext___Type_ofType(ctx, field)
+			case "specifiedByURL":
+				return ec.fieldContext___Type_specifiedByURL(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
+		},
+	}
+	defer func() {
 		if r := recover(); r != nil {
 			err = ec.Recover(ctx, r)
 			ec.Error(ctx, err)
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_DerivedImageList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return fc, err
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_BaseImageList(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_BaseImageList(ctx, field)
+func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query___schema(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.introspectSchema()
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Schema)
+	fc.Result = res
+	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query___schema(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "description":
+				return ec.fieldContext___Schema_description(ctx, field)
+			case "types":
+				return ec.fieldContext___Schema_types(ctx, field)
+			case "queryType":
+				return ec.fieldContext___Schema_queryType(ctx, field)
+			case "mutationType":
+				return ec.fieldContext___Schema_mutationType(ctx, field)
+			case "subscriptionType":
+				return ec.fieldContext___Schema_subscriptionType(ctx, field)
+			case "directives":
+				return ec.fieldContext___Schema_directives(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Referrer_MediaType(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Referrer_MediaType(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.MediaType, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Referrer_MediaType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Referrer",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Referrer_ArtifactType(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Referrer_ArtifactType(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ArtifactType, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Referrer_ArtifactType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Referrer",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Referrer_Size(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Referrer_Size(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Size, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Referrer_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Referrer",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Referrer_Digest(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Referrer_Digest(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Digest, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Referrer_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Referrer",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Referrer_Annotations(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Referrer_Annotations(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6007,7 +9505,7 @@ func (ec *executionContext) _Query_BaseImageList(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().BaseImageList(rctx, fc.Args["image"].(string), fc.Args["digest"].(*string), fc.Args["requestedPage"].(*PageInput))
+		return obj.Annotations, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6019,43 +9517,32 @@ func (ec *executionContext) _Query_BaseImageList(ctx context.Context, field grap
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedImagesResult)
+	res := resTmp.([]*Annotation)
 	fc.Result = res
-	return ec.marshalNPaginatedImagesResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx, field.Selections, res)
+	return ec.marshalNAnnotation2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐAnnotation(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_BaseImageList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Referrer_Annotations(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "Referrer",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedImagesResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedImagesResult_Results(ctx, field)
+			case "Key":
+				return ec.fieldContext_Annotation_Key(ctx, field)
+			case "Value":
+				return ec.fieldContext_Annotation_Value(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedImagesResult", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Annotation", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_BaseImageList_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_Image(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_Image(ctx, field)
+func (ec *executionContext) _RepoInfo_Images(ctx context.Context, field graphql.CollectedField, obj *RepoInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoInfo_Images(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6068,29 +9555,26 @@ func (ec *executionContext) _Query_Image(ctx context.Context, field graphql.Coll
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Image(rctx, fc.Args["image"].(string))
+		return obj.Images, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*ImageSummary)
+	res := resTmp.([]*ImageSummary)
 	fc.Result = res
-	return ec.marshalNImageSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
+	return ec.marshalOImageSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_Image(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoInfo_Images(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RepoInfo",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "RepoName":
@@ -6107,6 +9591,8 @@ func (ec *executionContext) fieldContext_Query_Image(ctx context.Context, field
 				return ec.fieldContext_ImageSummary_Size(ctx, field)
 			case "DownloadCount":
 				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_ImageSummary_LastPulled(ctx, field)
 			case "LastUpdated":
 				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
 			case "Description":
@@ -6137,22 +9623,78 @@ func (ec *executionContext) fieldContext_Query_Image(ctx context.Context, field
 			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
 		},
 	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoInfo_Summary(ctx context.Context, field graphql.CollectedField, obj *RepoInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoInfo_Summary(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
 	defer func() {
 		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
 		}
 	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_Image_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Summary, nil
+	})
+	if err != nil {
 		ec.Error(ctx, err)
-		return fc, err
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*RepoSummary)
+	fc.Result = res
+	return ec.marshalORepoSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoSummary(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoInfo_Summary(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "Name":
+				return ec.fieldContext_RepoSummary_Name(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
+			case "Size":
+				return ec.fieldContext_RepoSummary_Size(ctx, field)
+			case "Platforms":
+				return ec.fieldContext_RepoSummary_Platforms(ctx, field)
+			case "Vendors":
+				return ec.fieldContext_RepoSummary_Vendors(ctx, field)
+			case "NewestImage":
+				return ec.fieldContext_RepoSummary_NewestImage(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_RepoSummary_LastPulled(ctx, field)
+			case "StarCount":
+				return ec.fieldContext_RepoSummary_StarCount(ctx, field)
+			case "IsBookmarked":
+				return ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
+			case "IsStarred":
+				return ec.fieldContext_RepoSummary_IsStarred(ctx, field)
+			case "Rank":
+				return ec.fieldContext_RepoSummary_Rank(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RepoSummary", field.Name)
+		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_Referrers(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_Referrers(ctx, field)
+func (ec *executionContext) _RepoInfo_Readme(ctx context.Context, field graphql.CollectedField, obj *RepoInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoInfo_Readme(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6165,61 +9707,166 @@ func (ec *executionContext) _Query_Referrers(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Referrers(rctx, fc.Args["repo"].(string), fc.Args["digest"].(string), fc.Args["type"].([]string))
+		return obj.Readme, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*Referrer)
+	res := resTmp.(*RepoReadme)
 	fc.Result = res
-	return ec.marshalNReferrer2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐReferrer(ctx, field.Selections, res)
+	return ec.marshalORepoReadme2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoReadme(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_Referrers(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoInfo_Readme(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RepoInfo",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "MediaType":
-				return ec.fieldContext_Referrer_MediaType(ctx, field)
-			case "ArtifactType":
-				return ec.fieldContext_Referrer_ArtifactType(ctx, field)
-			case "Size":
-				return ec.fieldContext_Referrer_Size(ctx, field)
-			case "Digest":
-				return ec.fieldContext_Referrer_Digest(ctx, field)
-			case "Annotations":
-				return ec.fieldContext_Referrer_Annotations(ctx, field)
+			case "Content":
+				return ec.fieldContext_RepoReadme_Content(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_RepoReadme_LastUpdated(ctx, field)
+			case "UpdatedBy":
+				return ec.fieldContext_RepoReadme_UpdatedBy(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Referrer", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type RepoReadme", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoReadme_Content(ctx context.Context, field graphql.CollectedField, obj *RepoReadme) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoReadme_Content(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Content, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoReadme_Content(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoReadme",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoReadme_LastUpdated(ctx context.Context, field graphql.CollectedField, obj *RepoReadme) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoReadme_LastUpdated(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.LastUpdated, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*time.Time)
+	fc.Result = res
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoReadme_LastUpdated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoReadme",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepoReadme_UpdatedBy(ctx context.Context, field graphql.CollectedField, obj *RepoReadme) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoReadme_UpdatedBy(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UpdatedBy, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepoReadme_UpdatedBy(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepoReadme",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_Referrers_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_StarredRepos(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_StarredRepos(ctx, field)
+func (ec *executionContext) _RepoSummary_Name(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_Name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6232,55 +9879,35 @@ func (ec *executionContext) _Query_StarredRepos(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().StarredRepos(rctx, fc.Args["requestedPage"].(*PageInput))
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedReposResult)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNPaginatedReposResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedReposResult(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_StarredRepos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_Name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RepoSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedReposResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedReposResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedReposResult", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_StarredRepos_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_BookmarkedRepos(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_BookmarkedRepos(ctx, field)
+func (ec *executionContext) _RepoSummary_LastUpdated(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6293,55 +9920,35 @@ func (ec *executionContext) _Query_BookmarkedRepos(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().BookmarkedRepos(rctx, fc.Args["requestedPage"].(*PageInput))
+		return obj.LastUpdated, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*PaginatedReposResult)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNPaginatedReposResult2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedReposResult(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_BookmarkedRepos(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_LastUpdated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RepoSummary",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Page":
-				return ec.fieldContext_PaginatedReposResult_Page(ctx, field)
-			case "Results":
-				return ec.fieldContext_PaginatedReposResult_Results(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type PaginatedReposResult", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_BookmarkedRepos_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query___type(ctx, field)
+func (ec *executionContext) _RepoSummary_Size(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_Size(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6354,7 +9961,7 @@ func (ec *executionContext) _Query___type(ctx context.Context, field graphql.Col
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectType(fc.Args["name"].(string))
+		return obj.Size, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6363,59 +9970,26 @@ func (ec *executionContext) _Query___type(ctx context.Context, field graphql.Col
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RepoSummary",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "kind":
-				return ec.fieldContext___Type_kind(ctx, field)
-			case "name":
-				return ec.fieldContext___Type_name(ctx, field)
-			case "description":
-				return ec.fieldContext___Type_description(ctx, field)
-			case "fields":
-				return ec.fieldContext___Type_fields(ctx, field)
-			case "interfaces":
-				return ec.fieldContext___Type_interfaces(ctx, field)
-			case "possibleTypes":
-				return ec.fieldContext___Type_possibleTypes(ctx, field)
-			case "enumValues":
-				return ec.fieldContext___Type_enumValues(ctx, field)
-			case "inputFields":
-				return ec.fieldContext___Type_inputFields(ctx, field)
-			case "ofType":
-				return ec.fieldContext___Type_ofType(ctx, field)
-			case "specifiedByURL":
-				return ec.fieldContext___Type_specifiedByURL(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return fc, err
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query___schema(ctx, field)
+func (ec *executionContext) _RepoSummary_Platforms(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_Platforms(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6428,7 +10002,7 @@ func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectSchema()
+		return obj.Platforms, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6437,40 +10011,32 @@ func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.C
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Schema)
+	res := resTmp.([]*Platform)
 	fc.Result = res
-	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
+	return ec.marshalOPlatform2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPlatform(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query___schema(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_Platforms(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RepoSummary",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "description":
-				return ec.fieldContext___Schema_description(ctx, field)
-			case "types":
-				return ec.fieldContext___Schema_types(ctx, field)
-			case "queryType":
-				return ec.fieldContext___Schema_queryType(ctx, field)
-			case "mutationType":
-				return ec.fieldContext___Schema_mutationType(ctx, field)
-			case "subscriptionType":
-				return ec.fieldContext___Schema_subscriptionType(ctx, field)
-			case "directives":
-				return ec.fieldContext___Schema_directives(ctx, field)
+			case "Os":
+				return ec.fieldContext_Platform_Os(ctx, field)
+			case "Arch":
+				return ec.fieldContext_Platform_Arch(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Platform", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Referrer_MediaType(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Referrer_MediaType(ctx, field)
+func (ec *executionContext) _RepoSummary_Vendors(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_Vendors(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6483,7 +10049,7 @@ func (ec *executionContext) _Referrer_MediaType(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.MediaType, nil
+		return obj.Vendors, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6492,14 +10058,14 @@ func (ec *executionContext) _Referrer_MediaType(ctx context.Context, field graph
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.([]*string)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOString2ᚕᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Referrer_MediaType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_Vendors(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Referrer",
+		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -6510,8 +10076,8 @@ func (ec *executionContext) fieldContext_Referrer_MediaType(ctx context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _Referrer_ArtifactType(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Referrer_ArtifactType(ctx, field)
+func (ec *executionContext) _RepoSummary_NewestImage(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_NewestImage(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6524,7 +10090,7 @@ func (ec *executionContext) _Referrer_ArtifactType(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ArtifactType, nil
+		return obj.NewestImage, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6533,26 +10099,70 @@ func (ec *executionContext) _Referrer_ArtifactType(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*ImageSummary)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOImageSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Referrer_ArtifactType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_NewestImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Referrer",
+		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "RepoName":
+				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
+			case "Tag":
+				return ec.fieldContext_ImageSummary_Tag(ctx, field)
+			case "Digest":
+				return ec.fieldContext_ImageSummary_Digest(ctx, field)
+			case "MediaType":
+				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
+			case "Manifests":
+				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
+			case "Size":
+				return ec.fieldContext_ImageSummary_Size(ctx, field)
+			case "DownloadCount":
+				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
+			case "LastPulled":
+				return ec.fieldContext_ImageSummary_LastPulled(ctx, field)
+			case "LastUpdated":
+				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
+			case "Description":
+				return ec.fieldContext_ImageSummary_Description(ctx, field)
+			case "IsSigned":
+				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
+			case "SignatureInfo":
+				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
+			case "Licenses":
+				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
+			case "Labels":
+				return ec.fieldContext_ImageSummary_Labels(ctx, field)
+			case "Title":
+				return ec.fieldContext_ImageSummary_Title(ctx, field)
+			case "Source":
+				return ec.fieldContext_ImageSummary_Source(ctx, field)
+			case "Documentation":
+				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
+			case "Vendor":
+				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
+			case "Authors":
+				return ec.fieldContext_ImageSummary_Authors(ctx, field)
+			case "Vulnerabilities":
+				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
+			case "Referrers":
+				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
 		},
 	}
 	return fc, nil
 }
-
-func (ec *executionContext) _Referrer_Size(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Referrer_Size(ctx, field)
+
+func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6565,7 +10175,7 @@ func (ec *executionContext) _Referrer_Size(ctx context.Context, field graphql.Co
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Size, nil
+		return obj.DownloadCount, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6579,9 +10189,9 @@ func (ec *executionContext) _Referrer_Size(ctx context.Context, field graphql.Co
 	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Referrer_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_DownloadCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Referrer",
+		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -6592,8 +10202,8 @@ func (ec *executionContext) fieldContext_Referrer_Size(ctx context.Context, fiel
 	return fc, nil
 }
 
-func (ec *executionContext) _Referrer_Digest(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Referrer_Digest(ctx, field)
+func (ec *executionContext) _RepoSummary_LastPulled(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_LastPulled(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6606,7 +10216,7 @@ func (ec *executionContext) _Referrer_Digest(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Digest, nil
+		return obj.LastPulled, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6615,26 +10225,26 @@ func (ec *executionContext) _Referrer_Digest(ctx context.Context, field graphql.
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Referrer_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_LastPulled(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Referrer",
+		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Referrer_Annotations(ctx context.Context, field graphql.CollectedField, obj *Referrer) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Referrer_Annotations(ctx, field)
+func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_StarCount(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6647,44 +10257,35 @@ func (ec *executionContext) _Referrer_Annotations(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Annotations, nil
+		return obj.StarCount, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*Annotation)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalNAnnotation2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐAnnotation(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Referrer_Annotations(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_StarCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Referrer",
+		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Key":
-				return ec.fieldContext_Annotation_Key(ctx, field)
-			case "Value":
-				return ec.fieldContext_Annotation_Value(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Annotation", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoInfo_Images(ctx context.Context, field graphql.CollectedField, obj *RepoInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoInfo_Images(ctx, field)
+func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6697,7 +10298,7 @@ func (ec *executionContext) _RepoInfo_Images(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Images, nil
+		return obj.IsBookmarked, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6706,68 +10307,26 @@ func (ec *executionContext) _RepoInfo_Images(ctx context.Context, field graphql.
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*ImageSummary)
+	res := resTmp.(*bool)
 	fc.Result = res
-	return ec.marshalOImageSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoInfo_Images(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_IsBookmarked(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoInfo",
+		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "RepoName":
-				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
-			case "Tag":
-				return ec.fieldContext_ImageSummary_Tag(ctx, field)
-			case "Digest":
-				return ec.fieldContext_ImageSummary_Digest(ctx, field)
-			case "MediaType":
-				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
-			case "Manifests":
-				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
-			case "Size":
-				return ec.fieldContext_ImageSummary_Size(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
-			case "Description":
-				return ec.fieldContext_ImageSummary_Description(ctx, field)
-			case "IsSigned":
-				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
-			case "SignatureInfo":
-				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
-			case "Licenses":
-				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
-			case "Labels":
-				return ec.fieldContext_ImageSummary_Labels(ctx, field)
-			case "Title":
-				return ec.fieldContext_ImageSummary_Title(ctx, field)
-			case "Source":
-				return ec.fieldContext_ImageSummary_Source(ctx, field)
-			case "Documentation":
-				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
-			case "Vendor":
-				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
-			case "Authors":
-				return ec.fieldContext_ImageSummary_Authors(ctx, field)
-			case "Vulnerabilities":
-				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
-			case "Referrers":
-				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoInfo_Summary(ctx context.Context, field graphql.CollectedField, obj *RepoInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoInfo_Summary(ctx, field)
+func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_IsStarred(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6780,7 +10339,7 @@ func (ec *executionContext) _RepoInfo_Summary(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Summary, nil
+		return obj.IsStarred, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6789,50 +10348,26 @@ func (ec *executionContext) _RepoInfo_Summary(ctx context.Context, field graphql
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*RepoSummary)
+	res := resTmp.(*bool)
 	fc.Result = res
-	return ec.marshalORepoSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoSummary(ctx, field.Selections, res)
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoInfo_Summary(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_IsStarred(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoInfo",
+		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Name":
-				return ec.fieldContext_RepoSummary_Name(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
-			case "Size":
-				return ec.fieldContext_RepoSummary_Size(ctx, field)
-			case "Platforms":
-				return ec.fieldContext_RepoSummary_Platforms(ctx, field)
-			case "Vendors":
-				return ec.fieldContext_RepoSummary_Vendors(ctx, field)
-			case "NewestImage":
-				return ec.fieldContext_RepoSummary_NewestImage(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
-			case "StarCount":
-				return ec.fieldContext_RepoSummary_StarCount(ctx, field)
-			case "IsBookmarked":
-				return ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
-			case "IsStarred":
-				return ec.fieldContext_RepoSummary_IsStarred(ctx, field)
-			case "Rank":
-				return ec.fieldContext_RepoSummary_Rank(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RepoSummary", field.Name)
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_Name(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_Name(ctx, field)
+func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepoSummary_Rank(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6845,7 +10380,7 @@ func (ec *executionContext) _RepoSummary_Name(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.Rank, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6854,26 +10389,26 @@ func (ec *executionContext) _RepoSummary_Name(ctx context.Context, field graphql
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_Name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepoSummary_Rank(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RepoSummary",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_LastUpdated(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_LastUpdated(ctx, field)
+func (ec *executionContext) _Secret_RuleID(ctx context.Context, field graphql.CollectedField, obj *Secret) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Secret_RuleID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6886,7 +10421,7 @@ func (ec *executionContext) _RepoSummary_LastUpdated(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.LastUpdated, nil
+		return obj.RuleID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6895,26 +10430,26 @@ func (ec *executionContext) _RepoSummary_LastUpdated(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_LastUpdated(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Secret_RuleID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "Secret",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_Size(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_Size(ctx, field)
+func (ec *executionContext) _Secret_Description(ctx context.Context, field graphql.CollectedField, obj *Secret) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Secret_Description(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6927,7 +10462,7 @@ func (ec *executionContext) _RepoSummary_Size(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Size, nil
+		return obj.Description, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6941,9 +10476,9 @@ func (ec *executionContext) _RepoSummary_Size(ctx context.Context, field graphql
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_Size(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Secret_Description(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "Secret",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -6954,8 +10489,8 @@ func (ec *executionContext) fieldContext_RepoSummary_Size(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_Platforms(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_Platforms(ctx, field)
+func (ec *executionContext) _Secret_Confidence(ctx context.Context, field graphql.CollectedField, obj *Secret) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Secret_Confidence(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6968,7 +10503,7 @@ func (ec *executionContext) _RepoSummary_Platforms(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Platforms, nil
+		return obj.Confidence, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6977,32 +10512,26 @@ func (ec *executionContext) _RepoSummary_Platforms(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*Platform)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOPlatform2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPlatform(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_Platforms(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Secret_Confidence(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "Secret",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "Os":
-				return ec.fieldContext_Platform_Os(ctx, field)
-			case "Arch":
-				return ec.fieldContext_Platform_Arch(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Platform", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_Vendors(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_Vendors(ctx, field)
+func (ec *executionContext) _Secret_LayerDigest(ctx context.Context, field graphql.CollectedField, obj *Secret) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Secret_LayerDigest(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7015,7 +10544,7 @@ func (ec *executionContext) _RepoSummary_Vendors(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Vendors, nil
+		return obj.LayerDigest, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7024,14 +10553,14 @@ func (ec *executionContext) _RepoSummary_Vendors(ctx context.Context, field grap
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOString2ᚕᚖstring(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_Vendors(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Secret_LayerDigest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "Secret",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7042,8 +10571,8 @@ func (ec *executionContext) fieldContext_RepoSummary_Vendors(ctx context.Context
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_NewestImage(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_NewestImage(ctx, field)
+func (ec *executionContext) _Secret_FilePath(ctx context.Context, field graphql.CollectedField, obj *Secret) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Secret_FilePath(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7056,7 +10585,7 @@ func (ec *executionContext) _RepoSummary_NewestImage(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.NewestImage, nil
+		return obj.FilePath, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7065,68 +10594,26 @@ func (ec *executionContext) _RepoSummary_NewestImage(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*ImageSummary)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOImageSummary2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐImageSummary(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_NewestImage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Secret_FilePath(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "Secret",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
-		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "RepoName":
-				return ec.fieldContext_ImageSummary_RepoName(ctx, field)
-			case "Tag":
-				return ec.fieldContext_ImageSummary_Tag(ctx, field)
-			case "Digest":
-				return ec.fieldContext_ImageSummary_Digest(ctx, field)
-			case "MediaType":
-				return ec.fieldContext_ImageSummary_MediaType(ctx, field)
-			case "Manifests":
-				return ec.fieldContext_ImageSummary_Manifests(ctx, field)
-			case "Size":
-				return ec.fieldContext_ImageSummary_Size(ctx, field)
-			case "DownloadCount":
-				return ec.fieldContext_ImageSummary_DownloadCount(ctx, field)
-			case "LastUpdated":
-				return ec.fieldContext_ImageSummary_LastUpdated(ctx, field)
-			case "Description":
-				return ec.fieldContext_ImageSummary_Description(ctx, field)
-			case "IsSigned":
-				return ec.fieldContext_ImageSummary_IsSigned(ctx, field)
-			case "SignatureInfo":
-				return ec.fieldContext_ImageSummary_SignatureInfo(ctx, field)
-			case "Licenses":
-				return ec.fieldContext_ImageSummary_Licenses(ctx, field)
-			case "Labels":
-				return ec.fieldContext_ImageSummary_Labels(ctx, field)
-			case "Title":
-				return ec.fieldContext_ImageSummary_Title(ctx, field)
-			case "Source":
-				return ec.fieldContext_ImageSummary_Source(ctx, field)
-			case "Documentation":
-				return ec.fieldContext_ImageSummary_Documentation(ctx, field)
-			case "Vendor":
-				return ec.fieldContext_ImageSummary_Vendor(ctx, field)
-			case "Authors":
-				return ec.fieldContext_ImageSummary_Authors(ctx, field)
-			case "Vulnerabilities":
-				return ec.fieldContext_ImageSummary_Vulnerabilities(ctx, field)
-			case "Referrers":
-				return ec.fieldContext_ImageSummary_Referrers(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type ImageSummary", field.Name)
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_DownloadCount(ctx, field)
+func (ec *executionContext) _Secret_Line(ctx context.Context, field graphql.CollectedField, obj *Secret) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Secret_Line(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7139,7 +10626,7 @@ func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DownloadCount, nil
+		return obj.Line, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7153,9 +10640,9 @@ func (ec *executionContext) _RepoSummary_DownloadCount(ctx context.Context, fiel
 	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_DownloadCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Secret_Line(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "Secret",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7166,8 +10653,8 @@ func (ec *executionContext) fieldContext_RepoSummary_DownloadCount(ctx context.C
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_StarCount(ctx, field)
+func (ec *executionContext) _Secret_Match(ctx context.Context, field graphql.CollectedField, obj *Secret) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Secret_Match(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7180,7 +10667,7 @@ func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.StarCount, nil
+		return obj.Match, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7189,26 +10676,26 @@ func (ec *executionContext) _RepoSummary_StarCount(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*int)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_StarCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Secret_Match(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "Secret",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_IsBookmarked(ctx, field)
+func (ec *executionContext) _SecretResultForImage_Tag(ctx context.Context, field graphql.CollectedField, obj *SecretResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SecretResultForImage_Tag(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7221,7 +10708,7 @@ func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsBookmarked, nil
+		return obj.Tag, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7230,26 +10717,26 @@ func (ec *executionContext) _RepoSummary_IsBookmarked(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_IsBookmarked(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SecretResultForImage_Tag(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SecretResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_IsStarred(ctx, field)
+func (ec *executionContext) _SecretResultForImage_Digest(ctx context.Context, field graphql.CollectedField, obj *SecretResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SecretResultForImage_Digest(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7262,7 +10749,7 @@ func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsStarred, nil
+		return obj.Digest, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7271,26 +10758,26 @@ func (ec *executionContext) _RepoSummary_IsStarred(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_IsStarred(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SecretResultForImage_Digest(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SecretResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql.CollectedField, obj *RepoSummary) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RepoSummary_Rank(ctx, field)
+func (ec *executionContext) _SecretResultForImage_SecretList(ctx context.Context, field graphql.CollectedField, obj *SecretResultForImage) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SecretResultForImage_SecretList(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7303,7 +10790,7 @@ func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Rank, nil
+		return obj.SecretList, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7312,19 +10799,35 @@ func (ec *executionContext) _RepoSummary_Rank(ctx context.Context, field graphql
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*int)
+	res := resTmp.([]*Secret)
 	fc.Result = res
-	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+	return ec.marshalOSecret2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSecret(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepoSummary_Rank(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_SecretResultForImage_SecretList(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RepoSummary",
+		Object:     "SecretResultForImage",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "RuleID":
+				return ec.fieldContext_Secret_RuleID(ctx, field)
+			case "Description":
+				return ec.fieldContext_Secret_Description(ctx, field)
+			case "Confidence":
+				return ec.fieldContext_Secret_Confidence(ctx, field)
+			case "LayerDigest":
+				return ec.fieldContext_Secret_LayerDigest(ctx, field)
+			case "FilePath":
+				return ec.fieldContext_Secret_FilePath(ctx, field)
+			case "Line":
+				return ec.fieldContext_Secret_Line(ctx, field)
+			case "Match":
+				return ec.fieldContext_Secret_Match(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Secret", field.Name)
 		},
 	}
 	return fc, nil
@@ -9384,6 +12887,48 @@ func (ec *executionContext) _Annotation(ctx context.Context, sel ast.SelectionSe
 	return out
 }
 
+var bookmarkListImplementors = []string{"BookmarkList"}
+
+func (ec *executionContext) _BookmarkList(ctx context.Context, sel ast.SelectionSet, obj *BookmarkList) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, bookmarkListImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("BookmarkList")
+		case "Name":
+			out.Values[i] = ec._BookmarkList_Name(ctx, field, obj)
+		case "Owner":
+			out.Values[i] = ec._BookmarkList_Owner(ctx, field, obj)
+		case "Repos":
+			out.Values[i] = ec._BookmarkList_Repos(ctx, field, obj)
+		case "SharedWith":
+			out.Values[i] = ec._BookmarkList_SharedWith(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var cVEImplementors = []string{"CVE"}
 
 func (ec *executionContext) _CVE(ctx context.Context, sel ast.SelectionSet, obj *Cve) graphql.Marshaler {
@@ -9405,6 +12950,10 @@ func (ec *executionContext) _CVE(ctx context.Context, sel ast.SelectionSet, obj
 			out.Values[i] = ec._CVE_Severity(ctx, field, obj)
 		case "PackageList":
 			out.Values[i] = ec._CVE_PackageList(ctx, field, obj)
+		case "HasFix":
+			out.Values[i] = ec._CVE_HasFix(ctx, field, obj)
+		case "FixStatus":
+			out.Values[i] = ec._CVE_FixStatus(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -9445,6 +12994,133 @@ func (ec *executionContext) _CVEResultForImage(ctx context.Context, sel ast.Sele
 			out.Values[i] = ec._CVEResultForImage_CVEList(ctx, field, obj)
 		case "Page":
 			out.Values[i] = ec._CVEResultForImage_Page(ctx, field, obj)
+		case "UpgradeAdvice":
+			out.Values[i] = ec._CVEResultForImage_UpgradeAdvice(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var downloadCountImplementors = []string{"DownloadCount"}
+
+func (ec *executionContext) _DownloadCount(ctx context.Context, sel ast.SelectionSet, obj *DownloadCount) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, downloadCountImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("DownloadCount")
+		case "Repo":
+			out.Values[i] = ec._DownloadCount_Repo(ctx, field, obj)
+		case "Reference":
+			out.Values[i] = ec._DownloadCount_Reference(ctx, field, obj)
+		case "Count":
+			out.Values[i] = ec._DownloadCount_Count(ctx, field, obj)
+		case "UniqueClients":
+			out.Values[i] = ec._DownloadCount_UniqueClients(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var downloadsOverTimeImplementors = []string{"DownloadsOverTime"}
+
+func (ec *executionContext) _DownloadsOverTime(ctx context.Context, sel ast.SelectionSet, obj *DownloadsOverTime) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, downloadsOverTimeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("DownloadsOverTime")
+		case "Total":
+			out.Values[i] = ec._DownloadsOverTime_Total(ctx, field, obj)
+		case "UniqueClients":
+			out.Values[i] = ec._DownloadsOverTime_UniqueClients(ctx, field, obj)
+		case "Points":
+			out.Values[i] = ec._DownloadsOverTime_Points(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var downloadsPointImplementors = []string{"DownloadsPoint"}
+
+func (ec *executionContext) _DownloadsPoint(ctx context.Context, sel ast.SelectionSet, obj *DownloadsPoint) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, downloadsPointImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("DownloadsPoint")
+		case "Day":
+			out.Values[i] = ec._DownloadsPoint_Day(ctx, field, obj)
+		case "Count":
+			out.Values[i] = ec._DownloadsPoint_Count(ctx, field, obj)
+		case "UniqueClients":
+			out.Values[i] = ec._DownloadsPoint_UniqueClients(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -9579,6 +13255,8 @@ func (ec *executionContext) _ImageSummary(ctx context.Context, sel ast.Selection
 			out.Values[i] = ec._ImageSummary_Size(ctx, field, obj)
 		case "DownloadCount":
 			out.Values[i] = ec._ImageSummary_DownloadCount(ctx, field, obj)
+		case "LastPulled":
+			out.Values[i] = ec._ImageSummary_LastPulled(ctx, field, obj)
 		case "LastUpdated":
 			out.Values[i] = ec._ImageSummary_LastUpdated(ctx, field, obj)
 		case "Description":
@@ -9628,21 +13306,97 @@ func (ec *executionContext) _ImageSummary(ctx context.Context, sel ast.Selection
 	return out
 }
 
-var imageVulnerabilitySummaryImplementors = []string{"ImageVulnerabilitySummary"}
+var imageVulnerabilitySummaryImplementors = []string{"ImageVulnerabilitySummary"}
+
+func (ec *executionContext) _ImageVulnerabilitySummary(ctx context.Context, sel ast.SelectionSet, obj *ImageVulnerabilitySummary) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, imageVulnerabilitySummaryImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ImageVulnerabilitySummary")
+		case "MaxSeverity":
+			out.Values[i] = ec._ImageVulnerabilitySummary_MaxSeverity(ctx, field, obj)
+		case "Count":
+			out.Values[i] = ec._ImageVulnerabilitySummary_Count(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var layerHistoryImplementors = []string{"LayerHistory"}
+
+func (ec *executionContext) _LayerHistory(ctx context.Context, sel ast.SelectionSet, obj *LayerHistory) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, layerHistoryImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LayerHistory")
+		case "Layer":
+			out.Values[i] = ec._LayerHistory_Layer(ctx, field, obj)
+		case "HistoryDescription":
+			out.Values[i] = ec._LayerHistory_HistoryDescription(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var layerSummaryImplementors = []string{"LayerSummary"}
 
-func (ec *executionContext) _ImageVulnerabilitySummary(ctx context.Context, sel ast.SelectionSet, obj *ImageVulnerabilitySummary) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, imageVulnerabilitySummaryImplementors)
+func (ec *executionContext) _LayerSummary(ctx context.Context, sel ast.SelectionSet, obj *LayerSummary) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, layerSummaryImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("ImageVulnerabilitySummary")
-		case "MaxSeverity":
-			out.Values[i] = ec._ImageVulnerabilitySummary_MaxSeverity(ctx, field, obj)
-		case "Count":
-			out.Values[i] = ec._ImageVulnerabilitySummary_Count(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("LayerSummary")
+		case "Size":
+			out.Values[i] = ec._LayerSummary_Size(ctx, field, obj)
+		case "Digest":
+			out.Values[i] = ec._LayerSummary_Digest(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -9666,21 +13420,27 @@ func (ec *executionContext) _ImageVulnerabilitySummary(ctx context.Context, sel
 	return out
 }
 
-var layerHistoryImplementors = []string{"LayerHistory"}
+var licenseImplementors = []string{"License"}
 
-func (ec *executionContext) _LayerHistory(ctx context.Context, sel ast.SelectionSet, obj *LayerHistory) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, layerHistoryImplementors)
+func (ec *executionContext) _License(ctx context.Context, sel ast.SelectionSet, obj *License) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, licenseImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("LayerHistory")
-		case "Layer":
-			out.Values[i] = ec._LayerHistory_Layer(ctx, field, obj)
-		case "HistoryDescription":
-			out.Values[i] = ec._LayerHistory_HistoryDescription(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("License")
+		case "Name":
+			out.Values[i] = ec._License_Name(ctx, field, obj)
+		case "Category":
+			out.Values[i] = ec._License_Category(ctx, field, obj)
+		case "Severity":
+			out.Values[i] = ec._License_Severity(ctx, field, obj)
+		case "PackageName":
+			out.Values[i] = ec._License_PackageName(ctx, field, obj)
+		case "FilePath":
+			out.Values[i] = ec._License_FilePath(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -9704,21 +13464,21 @@ func (ec *executionContext) _LayerHistory(ctx context.Context, sel ast.Selection
 	return out
 }
 
-var layerSummaryImplementors = []string{"LayerSummary"}
+var licenseResultForImageImplementors = []string{"LicenseResultForImage"}
 
-func (ec *executionContext) _LayerSummary(ctx context.Context, sel ast.SelectionSet, obj *LayerSummary) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, layerSummaryImplementors)
+func (ec *executionContext) _LicenseResultForImage(ctx context.Context, sel ast.SelectionSet, obj *LicenseResultForImage) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, licenseResultForImageImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("LayerSummary")
-		case "Size":
-			out.Values[i] = ec._LayerSummary_Size(ctx, field, obj)
-		case "Digest":
-			out.Values[i] = ec._LayerSummary_Digest(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("LicenseResultForImage")
+		case "Tag":
+			out.Values[i] = ec._LicenseResultForImage_Tag(ctx, field, obj)
+		case "LicenseList":
+			out.Values[i] = ec._LicenseResultForImage_LicenseList(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -9769,6 +13529,8 @@ func (ec *executionContext) _ManifestSummary(ctx context.Context, sel ast.Select
 			out.Values[i] = ec._ManifestSummary_Platform(ctx, field, obj)
 		case "DownloadCount":
 			out.Values[i] = ec._ManifestSummary_DownloadCount(ctx, field, obj)
+		case "LastPulled":
+			out.Values[i] = ec._ManifestSummary_LastPulled(ctx, field, obj)
 		case "Layers":
 			out.Values[i] = ec._ManifestSummary_Layers(ctx, field, obj)
 		case "History":
@@ -9819,6 +13581,52 @@ func (ec *executionContext) _PackageInfo(ctx context.Context, sel ast.SelectionS
 			out.Values[i] = ec._PackageInfo_InstalledVersion(ctx, field, obj)
 		case "FixedVersion":
 			out.Values[i] = ec._PackageInfo_FixedVersion(ctx, field, obj)
+		case "HasFix":
+			out.Values[i] = ec._PackageInfo_HasFix(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var packageUpgradeImplementors = []string{"PackageUpgrade"}
+
+func (ec *executionContext) _PackageUpgrade(ctx context.Context, sel ast.SelectionSet, obj *PackageUpgrade) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, packageUpgradeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PackageUpgrade")
+		case "Name":
+			out.Values[i] = ec._PackageUpgrade_Name(ctx, field, obj)
+		case "InstalledVersion":
+			out.Values[i] = ec._PackageUpgrade_InstalledVersion(ctx, field, obj)
+		case "RecommendedVersion":
+			out.Values[i] = ec._PackageUpgrade_RecommendedVersion(ctx, field, obj)
+		case "FixedVersions":
+			out.Values[i] = ec._PackageUpgrade_FixedVersions(ctx, field, obj)
+		case "CVEIds":
+			out.Values[i] = ec._PackageUpgrade_CVEIds(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -10090,6 +13898,72 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "LicenseListForImage":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_LicenseListForImage(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "ImageListForLicense":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_ImageListForLicense(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "SecretListForImage":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_SecretListForImage(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "ImageListForDigest":
 			field := field
@@ -10332,6 +14206,94 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "BookmarkLists":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_BookmarkLists(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "BookmarkListRepos":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_BookmarkListRepos(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "TopDownloads":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_TopDownloads(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "DownloadsOverTime":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_DownloadsOverTime(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "__type":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
@@ -10374,20 +14336,60 @@ func (ec *executionContext) _Referrer(ctx context.Context, sel ast.SelectionSet,
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Referrer")
-		case "MediaType":
-			out.Values[i] = ec._Referrer_MediaType(ctx, field, obj)
-		case "ArtifactType":
-			out.Values[i] = ec._Referrer_ArtifactType(ctx, field, obj)
-		case "Size":
-			out.Values[i] = ec._Referrer_Size(ctx, field, obj)
-		case "Digest":
-			out.Values[i] = ec._Referrer_Digest(ctx, field, obj)
-		case "Annotations":
-			out.Values[i] = ec._Referrer_Annotations(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				out.Invalids++
-			}
+			out.Values[i] = graphql.MarshalString("Referrer")
+		case "MediaType":
+			out.Values[i] = ec._Referrer_MediaType(ctx, field, obj)
+		case "ArtifactType":
+			out.Values[i] = ec._Referrer_ArtifactType(ctx, field, obj)
+		case "Size":
+			out.Values[i] = ec._Referrer_Size(ctx, field, obj)
+		case "Digest":
+			out.Values[i] = ec._Referrer_Digest(ctx, field, obj)
+		case "Annotations":
+			out.Values[i] = ec._Referrer_Annotations(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var repoInfoImplementors = []string{"RepoInfo"}
+
+func (ec *executionContext) _RepoInfo(ctx context.Context, sel ast.SelectionSet, obj *RepoInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, repoInfoImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("RepoInfo")
+		case "Images":
+			out.Values[i] = ec._RepoInfo_Images(ctx, field, obj)
+		case "Summary":
+			out.Values[i] = ec._RepoInfo_Summary(ctx, field, obj)
+		case "Readme":
+			out.Values[i] = ec._RepoInfo_Readme(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -10411,21 +14413,23 @@ func (ec *executionContext) _Referrer(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var repoInfoImplementors = []string{"RepoInfo"}
+var repoReadmeImplementors = []string{"RepoReadme"}
 
-func (ec *executionContext) _RepoInfo(ctx context.Context, sel ast.SelectionSet, obj *RepoInfo) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, repoInfoImplementors)
+func (ec *executionContext) _RepoReadme(ctx context.Context, sel ast.SelectionSet, obj *RepoReadme) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, repoReadmeImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("RepoInfo")
-		case "Images":
-			out.Values[i] = ec._RepoInfo_Images(ctx, field, obj)
-		case "Summary":
-			out.Values[i] = ec._RepoInfo_Summary(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("RepoReadme")
+		case "Content":
+			out.Values[i] = ec._RepoReadme_Content(ctx, field, obj)
+		case "LastUpdated":
+			out.Values[i] = ec._RepoReadme_LastUpdated(ctx, field, obj)
+		case "UpdatedBy":
+			out.Values[i] = ec._RepoReadme_UpdatedBy(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -10474,6 +14478,8 @@ func (ec *executionContext) _RepoSummary(ctx context.Context, sel ast.SelectionS
 			out.Values[i] = ec._RepoSummary_NewestImage(ctx, field, obj)
 		case "DownloadCount":
 			out.Values[i] = ec._RepoSummary_DownloadCount(ctx, field, obj)
+		case "LastPulled":
+			out.Values[i] = ec._RepoSummary_LastPulled(ctx, field, obj)
 		case "StarCount":
 			out.Values[i] = ec._RepoSummary_StarCount(ctx, field, obj)
 		case "IsBookmarked":
@@ -10505,6 +14511,94 @@ func (ec *executionContext) _RepoSummary(ctx context.Context, sel ast.SelectionS
 	return out
 }
 
+var secretImplementors = []string{"Secret"}
+
+func (ec *executionContext) _Secret(ctx context.Context, sel ast.SelectionSet, obj *Secret) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, secretImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Secret")
+		case "RuleID":
+			out.Values[i] = ec._Secret_RuleID(ctx, field, obj)
+		case "Description":
+			out.Values[i] = ec._Secret_Description(ctx, field, obj)
+		case "Confidence":
+			out.Values[i] = ec._Secret_Confidence(ctx, field, obj)
+		case "LayerDigest":
+			out.Values[i] = ec._Secret_LayerDigest(ctx, field, obj)
+		case "FilePath":
+			out.Values[i] = ec._Secret_FilePath(ctx, field, obj)
+		case "Line":
+			out.Values[i] = ec._Secret_Line(ctx, field, obj)
+		case "Match":
+			out.Values[i] = ec._Secret_Match(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var secretResultForImageImplementors = []string{"SecretResultForImage"}
+
+func (ec *executionContext) _SecretResultForImage(ctx context.Context, sel ast.SelectionSet, obj *SecretResultForImage) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, secretResultForImageImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("SecretResultForImage")
+		case "Tag":
+			out.Values[i] = ec._SecretResultForImage_Tag(ctx, field, obj)
+		case "Digest":
+			out.Values[i] = ec._SecretResultForImage_Digest(ctx, field, obj)
+		case "SecretList":
+			out.Values[i] = ec._SecretResultForImage_SecretList(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var signatureSummaryImplementors = []string{"SignatureSummary"}
 
 func (ec *executionContext) _SignatureSummary(ctx context.Context, sel ast.SelectionSet, obj *SignatureSummary) graphql.Marshaler {
@@ -10864,14 +14958,203 @@ func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, o
 		})
 	}
 
-	return out
+	return out
+}
+
+// endregion **************************** object.gotpl ****************************
+
+// region    ***************************** type.gotpl *****************************
+
+func (ec *executionContext) marshalNAnnotation2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐAnnotation(ctx context.Context, sel ast.SelectionSet, v []*Annotation) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOAnnotation2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐAnnotation(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (ec *executionContext) marshalNBookmarkList2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐBookmarkListᚄ(ctx context.Context, sel ast.SelectionSet, v []*BookmarkList) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNBookmarkList2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐBookmarkList(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNBookmarkList2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐBookmarkList(ctx context.Context, sel ast.SelectionSet, v *BookmarkList) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._BookmarkList(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNBoolean2bool(ctx context.Context, v interface{}) (bool, error) {
+	res, err := graphql.UnmarshalBoolean(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.SelectionSet, v bool) graphql.Marshaler {
+	res := graphql.MarshalBoolean(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) marshalNCVEResultForImage2zotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx context.Context, sel ast.SelectionSet, v CVEResultForImage) graphql.Marshaler {
+	return ec._CVEResultForImage(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNCVEResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx context.Context, sel ast.SelectionSet, v *CVEResultForImage) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._CVEResultForImage(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNDownloadCount2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadCountᚄ(ctx context.Context, sel ast.SelectionSet, v []*DownloadCount) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNDownloadCount2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadCount(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNDownloadCount2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadCount(ctx context.Context, sel ast.SelectionSet, v *DownloadCount) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._DownloadCount(ctx, sel, v)
 }
 
-// endregion **************************** object.gotpl ****************************
+func (ec *executionContext) marshalNDownloadsOverTime2zotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsOverTime(ctx context.Context, sel ast.SelectionSet, v DownloadsOverTime) graphql.Marshaler {
+	return ec._DownloadsOverTime(ctx, sel, &v)
+}
 
-// region    ***************************** type.gotpl *****************************
+func (ec *executionContext) marshalNDownloadsOverTime2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsOverTime(ctx context.Context, sel ast.SelectionSet, v *DownloadsOverTime) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._DownloadsOverTime(ctx, sel, v)
+}
 
-func (ec *executionContext) marshalNAnnotation2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐAnnotation(ctx context.Context, sel ast.SelectionSet, v []*Annotation) graphql.Marshaler {
+func (ec *executionContext) marshalNDownloadsPoint2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsPointᚄ(ctx context.Context, sel ast.SelectionSet, v []*DownloadsPoint) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -10895,7 +15178,7 @@ func (ec *executionContext) marshalNAnnotation2ᚕᚖzotregistryᚗioᚋzotᚋpk
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalOAnnotation2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐAnnotation(ctx, sel, v[i])
+			ret[i] = ec.marshalNDownloadsPoint2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsPoint(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -10906,36 +15189,23 @@ func (ec *executionContext) marshalNAnnotation2ᚕᚖzotregistryᚗioᚋzotᚋpk
 	}
 	wg.Wait()
 
-	return ret
-}
-
-func (ec *executionContext) unmarshalNBoolean2bool(ctx context.Context, v interface{}) (bool, error) {
-	res, err := graphql.UnmarshalBoolean(v)
-	return res, graphql.ErrorOnPath(ctx, err)
-}
-
-func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.SelectionSet, v bool) graphql.Marshaler {
-	res := graphql.MarshalBoolean(v)
-	if res == graphql.Null {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
 		}
 	}
-	return res
-}
 
-func (ec *executionContext) marshalNCVEResultForImage2zotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx context.Context, sel ast.SelectionSet, v CVEResultForImage) graphql.Marshaler {
-	return ec._CVEResultForImage(ctx, sel, &v)
+	return ret
 }
 
-func (ec *executionContext) marshalNCVEResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐCVEResultForImage(ctx context.Context, sel ast.SelectionSet, v *CVEResultForImage) graphql.Marshaler {
+func (ec *executionContext) marshalNDownloadsPoint2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsPoint(ctx context.Context, sel ast.SelectionSet, v *DownloadsPoint) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
 		}
 		return graphql.Null
 	}
-	return ec._CVEResultForImage(ctx, sel, v)
+	return ec._DownloadsPoint(ctx, sel, v)
 }
 
 func (ec *executionContext) marshalNGlobalSearchResult2zotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐGlobalSearchResult(ctx context.Context, sel ast.SelectionSet, v GlobalSearchResult) graphql.Marshaler {
@@ -11025,6 +15295,20 @@ func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.Selecti
 	return res
 }
 
+func (ec *executionContext) marshalNLicenseResultForImage2zotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLicenseResultForImage(ctx context.Context, sel ast.SelectionSet, v LicenseResultForImage) graphql.Marshaler {
+	return ec._LicenseResultForImage(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNLicenseResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLicenseResultForImage(ctx context.Context, sel ast.SelectionSet, v *LicenseResultForImage) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._LicenseResultForImage(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNPaginatedImagesResult2zotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPaginatedImagesResult(ctx context.Context, sel ast.SelectionSet, v PaginatedImagesResult) graphql.Marshaler {
 	return ec._PaginatedImagesResult(ctx, sel, &v)
 }
@@ -11159,6 +15443,20 @@ func (ec *executionContext) marshalNRepoSummary2ᚖzotregistryᚗioᚋzotᚋpkg
 	return ec._RepoSummary(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNSecretResultForImage2zotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSecretResultForImage(ctx context.Context, sel ast.SelectionSet, v SecretResultForImage) graphql.Marshaler {
+	return ec._SecretResultForImage(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSecretResultForImage2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSecretResultForImage(ctx context.Context, sel ast.SelectionSet, v *SecretResultForImage) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._SecretResultForImage(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNString2string(ctx context.Context, v interface{}) (string, error) {
 	res, err := graphql.UnmarshalString(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -11174,6 +15472,38 @@ func (ec *executionContext) marshalNString2string(ctx context.Context, sel ast.S
 	return res
 }
 
+func (ec *executionContext) unmarshalNString2ᚕstringᚄ(ctx context.Context, v interface{}) ([]string, error) {
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]string, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNString2string(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalNString2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNString2string(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) marshalN__Directive2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐDirective(ctx context.Context, sel ast.SelectionSet, v introspection.Directive) graphql.Marshaler {
 	return ec.___Directive(ctx, sel, &v)
 }
@@ -11508,6 +15838,22 @@ func (ec *executionContext) marshalOCVE2ᚖzotregistryᚗioᚋzotᚋpkgᚋextens
 	return ec._CVE(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalODownloadsGroupBy2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsGroupBy(ctx context.Context, v interface{}) (*DownloadsGroupBy, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(DownloadsGroupBy)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalODownloadsGroupBy2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐDownloadsGroupBy(ctx context.Context, sel ast.SelectionSet, v *DownloadsGroupBy) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) unmarshalOFilter2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐFilter(ctx context.Context, v interface{}) (*Filter, error) {
 	if v == nil {
 		return nil, nil
@@ -11516,6 +15862,22 @@ func (ec *executionContext) unmarshalOFilter2ᚖzotregistryᚗioᚋzotᚋpkgᚋe
 	return &res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalOFixStatus2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐFixStatus(ctx context.Context, v interface{}) (*FixStatus, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(FixStatus)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOFixStatus2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐFixStatus(ctx context.Context, sel ast.SelectionSet, v *FixStatus) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) marshalOHistoryDescription2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐHistoryDescription(ctx context.Context, sel ast.SelectionSet, v *HistoryDescription) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -11690,6 +16052,54 @@ func (ec *executionContext) marshalOLayerSummary2ᚖzotregistryᚗioᚋzotᚋpkg
 	return ec._LayerSummary(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOLicense2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLicense(ctx context.Context, sel ast.SelectionSet, v []*License) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOLicense2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLicense(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (ec *executionContext) marshalOLicense2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐLicense(ctx context.Context, sel ast.SelectionSet, v *License) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._License(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalOManifestSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐManifestSummary(ctx context.Context, sel ast.SelectionSet, v []*ManifestSummary) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -11786,6 +16196,54 @@ func (ec *executionContext) marshalOPackageInfo2ᚖzotregistryᚗioᚋzotᚋpkg
 	return ec._PackageInfo(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOPackageUpgrade2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageUpgrade(ctx context.Context, sel ast.SelectionSet, v []*PackageUpgrade) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOPackageUpgrade2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageUpgrade(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (ec *executionContext) marshalOPackageUpgrade2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPackageUpgrade(ctx context.Context, sel ast.SelectionSet, v *PackageUpgrade) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._PackageUpgrade(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalOPageInfo2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v *PageInfo) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -11897,6 +16355,13 @@ func (ec *executionContext) marshalOReferrer2ᚖzotregistryᚗioᚋzotᚋpkgᚋe
 	return ec._Referrer(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalORepoReadme2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoReadme(ctx context.Context, sel ast.SelectionSet, v *RepoReadme) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._RepoReadme(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalORepoSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐRepoSummary(ctx context.Context, sel ast.SelectionSet, v []*RepoSummary) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -11945,6 +16410,54 @@ func (ec *executionContext) marshalORepoSummary2ᚖzotregistryᚗioᚋzotᚋpkg
 	return ec._RepoSummary(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOSecret2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSecret(ctx context.Context, sel ast.SelectionSet, v []*Secret) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOSecret2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSecret(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (ec *executionContext) marshalOSecret2ᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSecret(ctx context.Context, sel ast.SelectionSet, v *Secret) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Secret(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalOSignatureSummary2ᚕᚖzotregistryᚗioᚋzotᚋpkgᚋextensionsᚋsearchᚋgql_generatedᚐSignatureSummary(ctx context.Context, sel ast.SelectionSet, v []*SignatureSummary) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -18,6 +18,18 @@ type Annotation struct {
 	Value *string `json:"Value,omitempty"`
 }
 
+// A named list of repos bookmarked by a user, shared read-only with the users of some groups
+type BookmarkList struct {
+	// Name of the list, unique among the lists of its owner
+	Name *string `json:"Name,omitempty"`
+	// The user who created the list, only the owner can modify it
+	Owner *string `json:"Owner,omitempty"`
+	// The repos of the list which can be read by the current user
+	Repos []string `json:"Repos,omitempty"`
+	// The groups whose users can read the list
+	SharedWith []string `json:"SharedWith,omitempty"`
+}
+
 // Contains various details about the CVE (Common Vulnerabilities and Exposures)
 // and a list of PackageInfo about the affected packages
 type Cve struct {
@@ -31,6 +43,12 @@ type Cve struct {
 	Severity *string `json:"Severity,omitempty"`
 	// Information on the packages in which the CVE was found
 	PackageList []*PackageInfo `json:"PackageList,omitempty"`
+	// True if every package affected by the CVE has a version in which the CVE is fixed,
+	// false if the CVE is fixed only in some of the affected packages or in none of them
+	HasFix *bool `json:"HasFix,omitempty"`
+	// If the CVE can be fixed by upgrading all (AVAILABLE), some (PARTIAL) or none (UNAVAILABLE)
+	// of the affected packages
+	FixStatus *FixStatus `json:"FixStatus,omitempty"`
 }
 
 // Contains the tag of the image and a list of CVEs
@@ -41,6 +59,40 @@ type CVEResultForImage struct {
 	CVEList []*Cve `json:"CVEList,omitempty"`
 	// The CVE pagination information, see PageInfo object for more details
 	Page *PageInfo `json:"Page,omitempty"`
+	// How to upgrade the packages affected by the CVEs (on all pages) having a fix, one entry per package
+	UpgradeAdvice []*PackageUpgrade `json:"UpgradeAdvice,omitempty"`
+}
+
+// Number of downloads of a repo, tag or digest
+type DownloadCount struct {
+	// Repository name
+	Repo *string `json:"Repo,omitempty"`
+	// Tag or digest, empty when the downloads are grouped by repo
+	Reference *string `json:"Reference,omitempty"`
+	// Number of downloads
+	Count *int `json:"Count,omitempty"`
+	// Number of distinct clients which made the downloads
+	UniqueClients *int `json:"UniqueClients,omitempty"`
+}
+
+// Downloads during a period, day by day
+type DownloadsOverTime struct {
+	// Number of downloads during the period
+	Total *int `json:"Total,omitempty"`
+	// Number of distinct clients which made the downloads during the period
+	UniqueClients *int `json:"UniqueClients,omitempty"`
+	// Downloads of every day of the period, including the days without downloads
+	Points []*DownloadsPoint `json:"Points"`
+}
+
+// Number of downloads during a day
+type DownloadsPoint struct {
+	// Day in the YYYY-MM-DD format, in UTC
+	Day *string `json:"Day,omitempty"`
+	// Number of downloads
+	Count *int `json:"Count,omitempty"`
+	// Number of distinct clients which made the downloads
+	UniqueClients *int `json:"UniqueClients,omitempty"`
 }
 
 // Apply various types of filters to the queries made for repositories and images
@@ -105,6 +157,8 @@ type ImageSummary struct {
 	Size *string `json:"Size,omitempty"`
 	// Number of downloads of the manifest of this image
 	DownloadCount *int `json:"DownloadCount,omitempty"`
+	// Timestamp of the last pull of this image, by its tag or by its digest, null if it was never pulled
+	LastPulled *time.Time `json:"LastPulled,omitempty"`
 	// Timestamp of the last modification done to the image (from config or the last updated layer)
 	LastUpdated *time.Time `json:"LastUpdated,omitempty"`
 	// Human-readable description of the software packaged in the image
@@ -158,6 +212,28 @@ type LayerSummary struct {
 	Digest *string `json:"Digest,omitempty"`
 }
 
+// Contains details about a license found in an image
+type License struct {
+	// Name of the license, typically an SPDX identifier such as "AGPL-3.0"
+	Name *string `json:"Name,omitempty"`
+	// Category of the license, one of "forbidden", "restricted", "reciprocal", "notice", "permissive", "unencumbered", "unknown"
+	Category *string `json:"Category,omitempty"`
+	// Severity derived from the license category, one of "UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"
+	Severity *string `json:"Severity,omitempty"`
+	// Name of the package declaring the license, empty if the license was found in a file
+	PackageName *string `json:"PackageName,omitempty"`
+	// Path of the file in which the license was found, empty if the license was declared by a package
+	FilePath *string `json:"FilePath,omitempty"`
+}
+
+// Contains the tag of the image and the list of licenses found in the image
+type LicenseResultForImage struct {
+	// Tag of the scanned image
+	Tag *string `json:"Tag,omitempty"`
+	// List of licenses declared by the packages installed in the image or found in license files
+	LicenseList []*License `json:"LicenseList,omitempty"`
+}
+
 // Details about a specific version of an image for a certain operating system and architecture.
 type ManifestSummary struct {
 	// Digest of the manifest file associated with this image
@@ -176,6 +252,8 @@ type ManifestSummary struct {
 	Platform *Platform `json:"Platform,omitempty"`
 	// Total number of image manifest downloads from this repository
 	DownloadCount *int `json:"DownloadCount,omitempty"`
+	// Timestamp of the last pull of this manifest, null if it was never pulled
+	LastPulled *time.Time `json:"LastPulled,omitempty"`
 	// List of layers matching the search criteria
 	// NOTE: the actual search logic for layers is not implemented at the moment
 	Layers []*LayerSummary `json:"Layers,omitempty"`
@@ -197,6 +275,23 @@ type PackageInfo struct {
 	InstalledVersion *string `json:"InstalledVersion,omitempty"`
 	// Minimum version of the package in which the CVE is fixed
 	FixedVersion *string `json:"FixedVersion,omitempty"`
+	// True if there is a version of the package in which the CVE is fixed
+	HasFix *bool `json:"HasFix,omitempty"`
+}
+
+// Upgrade advice for a package affected by CVEs which have a fix
+type PackageUpgrade struct {
+	// Name of the package
+	Name *string `json:"Name,omitempty"`
+	// Current version of the package
+	InstalledVersion *string `json:"InstalledVersion,omitempty"`
+	// The version fixing all the fixable CVEs affecting the package, empty if several fixed versions
+	// can't be compared (e.g. distribution specific versioning), in which case see FixedVersions
+	RecommendedVersion *string `json:"RecommendedVersion,omitempty"`
+	// The minimum versions in which each of the CVEs is fixed, sorted ascending when they can be compared
+	FixedVersions []*string `json:"FixedVersions,omitempty"`
+	// IDs of the CVEs fixed by upgrading the package
+	CVEIds []*string `json:"CVEIds,omitempty"`
 }
 
 // Information on current page returned by the API
@@ -268,6 +363,18 @@ type RepoInfo struct {
 	Images []*ImageSummary `json:"Images,omitempty"`
 	// Details about the repository itself
 	Summary *RepoSummary `json:"Summary,omitempty"`
+	// Markdown documentation of the repository, null if it has no README
+	Readme *RepoReadme `json:"Readme,omitempty"`
+}
+
+// Markdown documentation of a repository, set by its users and shown next to its tags
+type RepoReadme struct {
+	// Content of the README, in markdown
+	Content *string `json:"Content,omitempty"`
+	// Timestamp of the last update of the README
+	LastUpdated *time.Time `json:"LastUpdated,omitempty"`
+	// User who last updated the README
+	UpdatedBy *string `json:"UpdatedBy,omitempty"`
 }
 
 // Details of a specific repo, it is used by queries returning a list of repos
@@ -287,6 +394,8 @@ type RepoSummary struct {
 	NewestImage *ImageSummary `json:"NewestImage,omitempty"`
 	// Total number of image manifest downloads from this repository
 	DownloadCount *int `json:"DownloadCount,omitempty"`
+	// Timestamp of the last pull of an image of this repository, null if none was pulled
+	LastPulled *time.Time `json:"LastPulled,omitempty"`
 	// Number of stars attributed to this repository by users
 	StarCount *int `json:"StarCount,omitempty"`
 	// True if the repository is bookmarked by the current user, false otherwise
@@ -297,6 +406,34 @@ type RepoSummary struct {
 	Rank *int `json:"Rank,omitempty"`
 }
 
+// Contains details about a secret found in an image layer, the secret itself is redacted
+type Secret struct {
+	// ID of the detection rule, for example "aws-access-key-id" or "private-key"
+	RuleID *string `json:"RuleID,omitempty"`
+	// Human readable description of the kind of secret
+	Description *string `json:"Description,omitempty"`
+	// Confidence of the detection, one of "HIGH", "MEDIUM"
+	Confidence *string `json:"Confidence,omitempty"`
+	// Digest of the layer containing the file
+	LayerDigest *string `json:"LayerDigest,omitempty"`
+	// Path of the file in which the secret was found
+	FilePath *string `json:"FilePath,omitempty"`
+	// Line of the file in which the secret was found
+	Line *int `json:"Line,omitempty"`
+	// Redacted value of the secret
+	Match *string `json:"Match,omitempty"`
+}
+
+// Contains the tag and digest of the image and the secrets found in its layers
+type SecretResultForImage struct {
+	// Tag of the scanned image
+	Tag *string `json:"Tag,omitempty"`
+	// Digest of the scanned image manifest
+	Digest *string `json:"Digest,omitempty"`
+	// List of secrets found when the image was pushed
+	SecretList []*Secret `json:"SecretList,omitempty"`
+}
+
 // Contains details about the signature
 type SignatureSummary struct {
 	// Tool is the tool used for signing image
@@ -307,6 +444,100 @@ type SignatureSummary struct {
 	Author *string `json:"Author,omitempty"`
 }
 
+// How the downloads are counted
+type DownloadsGroupBy string
+
+const (
+	// Downloads of every image of the repository
+	DownloadsGroupByRepo DownloadsGroupBy = "REPO"
+	// Downloads made by tag
+	DownloadsGroupByTag DownloadsGroupBy = "TAG"
+	// Downloads of each manifest, made by tag or by digest
+	DownloadsGroupByDigest DownloadsGroupBy = "DIGEST"
+)
+
+var AllDownloadsGroupBy = []DownloadsGroupBy{
+	DownloadsGroupByRepo,
+	DownloadsGroupByTag,
+	DownloadsGroupByDigest,
+}
+
+func (e DownloadsGroupBy) IsValid() bool {
+	switch e {
+	case DownloadsGroupByRepo, DownloadsGroupByTag, DownloadsGroupByDigest:
+		return true
+	}
+	return false
+}
+
+func (e DownloadsGroupBy) String() string {
+	return string(e)
+}
+
+func (e *DownloadsGroupBy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DownloadsGroupBy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DownloadsGroupBy", str)
+	}
+	return nil
+}
+
+func (e DownloadsGroupBy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Availability of a fix for a CVE across the packages it affects
+type FixStatus string
+
+const (
+	// Every affected package has a version in which the CVE is fixed
+	FixStatusAvailable FixStatus = "AVAILABLE"
+	// Only some of the affected packages have a version in which the CVE is fixed
+	FixStatusPartial FixStatus = "PARTIAL"
+	// None of the affected packages has a version in which the CVE is fixed
+	FixStatusUnavailable FixStatus = "UNAVAILABLE"
+)
+
+var AllFixStatus = []FixStatus{
+	FixStatusAvailable,
+	FixStatusPartial,
+	FixStatusUnavailable,
+}
+
+func (e FixStatus) IsValid() bool {
+	switch e {
+	case FixStatusAvailable, FixStatusPartial, FixStatusUnavailable:
+		return true
+	}
+	return false
+}
+
+func (e FixStatus) String() string {
+	return string(e)
+}
+
+func (e *FixStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FixStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FixStatus", str)
+	}
+	return nil
+}
+
+func (e FixStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // All sort criteria usable with pagination, some of these criteria applies only
 // to certain queries. For example sort by severity is available for CVEs but not
 // for repositories
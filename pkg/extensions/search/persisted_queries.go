@@ -0,0 +1,198 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/mitchellh/mapstructure"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	defaultPersistedQueriesCacheSize = 100
+	// same error and code as the gqlgen APQ extension, clients react to it by sending the full query.
+	errPersistedQueryNotFound     = "PersistedQueryNotFound"
+	errPersistedQueryNotFoundCode = "PERSISTED_QUERY_NOT_FOUND"
+	errQueryNotAllowedCode        = "QUERY_NOT_ALLOWED"
+)
+
+// PersistedQueries implements automatic persisted queries (APQ): clients send the sha256 hash of a
+// query instead of the query, and only send the full query if the server doesn't know the hash yet.
+// Queries can also be persisted ahead of time with an allow list, and the server can be locked down
+// to only run the queries of the allow list.
+// See https://github.com/apollographql/apollo-link-persisted-queries
+type PersistedQueries struct {
+	cache         *lru.Cache[string, string]
+	allowList     map[string]string
+	allowListOnly bool
+	log           log.Logger
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = &PersistedQueries{}
+
+// NewPersistedQueries loads the allow list of the config, a nil config enables APQ with the default cache size.
+func NewPersistedQueries(config *extconf.PersistedQueriesConfig, log log.Logger) (*PersistedQueries, error) {
+	cacheSize := defaultPersistedQueriesCacheSize
+	allowList := map[string]string{}
+	allowListOnly := false
+
+	if config != nil {
+		if config.CacheSize > 0 {
+			cacheSize = config.CacheSize
+		}
+
+		if config.AllowList != "" {
+			var err error
+
+			allowList, err = loadAllowList(config.AllowList)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		allowListOnly = config.AllowListOnly
+	}
+
+	cache, err := lru.New[string, string](cacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Int("allowed queries", len(allowList)).Bool("allow list only", allowListOnly).
+		Msg("persisted queries enabled")
+
+	return &PersistedQueries{
+		cache:         cache,
+		allowList:     allowList,
+		allowListOnly: allowListOnly,
+		log:           log,
+	}, nil
+}
+
+func loadAllowList(path string) (map[string]string, error) {
+	allowListBlob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	allowList := map[string]string{}
+
+	if err := json.Unmarshal(allowListBlob, &allowList); err != nil {
+		return nil, fmt.Errorf("%w: invalid persisted queries allow list %s: %w", zerr.ErrBadConfig, path, err)
+	}
+
+	for hash, query := range allowList {
+		if computeQueryHash(query) != hash {
+			return nil, fmt.Errorf("%w: persisted query hash %s doesn't match its query", zerr.ErrBadConfig, hash)
+		}
+	}
+
+	return allowList, nil
+}
+
+func (pq *PersistedQueries) ExtensionName() string {
+	return "PersistedQueries"
+}
+
+func (pq *PersistedQueries) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (pq *PersistedQueries) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams,
+) *gqlerror.Error {
+	var extension struct {
+		Sha256  string `mapstructure:"sha256Hash"`
+		Version int64  `mapstructure:"version"`
+	}
+
+	if rawParams.Extensions["persistedQuery"] != nil {
+		if err := mapstructure.Decode(rawParams.Extensions["persistedQuery"], &extension); err != nil {
+			return gqlerror.Errorf("invalid APQ extension data")
+		}
+
+		if extension.Version != 1 {
+			return gqlerror.Errorf("unsupported APQ version")
+		}
+	}
+
+	if pq.allowListOnly {
+		return pq.getAllowedQuery(rawParams, extension.Sha256)
+	}
+
+	if extension.Sha256 == "" {
+		return nil
+	}
+
+	if rawParams.Query == "" {
+		// client sent the hash without the query, get it from the persisted queries
+		query, ok := pq.getPersistedQuery(extension.Sha256)
+		if !ok {
+			gqlErr := gqlerror.Errorf(errPersistedQueryNotFound)
+			errcode.Set(gqlErr, errPersistedQueryNotFoundCode)
+
+			return gqlErr
+		}
+
+		rawParams.Query = query
+
+		return nil
+	}
+
+	// client sent the hash with the query, verify and store it
+	if computeQueryHash(rawParams.Query) != extension.Sha256 {
+		return gqlerror.Errorf("provided APQ hash does not match query")
+	}
+
+	pq.cache.Add(extension.Sha256, rawParams.Query)
+
+	return nil
+}
+
+// getAllowedQuery only lets through the queries of the allow list, sent either as a hash or in full.
+func (pq *PersistedQueries) getAllowedQuery(rawParams *graphql.RawParams, hash string) *gqlerror.Error {
+	if rawParams.Query != "" {
+		hash = computeQueryHash(rawParams.Query)
+	}
+
+	query, ok := pq.allowList[hash]
+	if !ok {
+		pq.log.Info().Str("hash", hash).Msg("rejected query not in the persisted queries allow list")
+
+		gqlErr := gqlerror.Errorf(zerr.ErrQueryNotAllowed.Error())
+		errcode.Set(gqlErr, errQueryNotAllowedCode)
+
+		return gqlErr
+	}
+
+	rawParams.Query = query
+
+	return nil
+}
+
+func (pq *PersistedQueries) getPersistedQuery(hash string) (string, bool) {
+	if query, ok := pq.allowList[hash]; ok {
+		return query, true
+	}
+
+	return pq.cache.Get(hash)
+}
+
+func computeQueryHash(query string) string {
+	hash := sha256.Sum256([]byte(query))
+
+	return hex.EncodeToString(hash[:])
+}
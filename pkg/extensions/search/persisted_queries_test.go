@@ -0,0 +1,143 @@
+package search //nolint
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+func TestPersistedQueries(t *testing.T) {
+	Convey("Persisted queries", t, func() {
+		log := log.NewLogger("debug", "")
+		ctx := context.Background()
+
+		allowedQuery := "{ RepoListWithNewestImage { Results { Name } } }"
+		otherQuery := "{ ImageList(repo: \"repo\") { Results { Tag } } }"
+
+		allowListPath := path.Join(t.TempDir(), "allowlist.json")
+
+		allowListBlob, err := json.Marshal(map[string]string{computeQueryHash(allowedQuery): allowedQuery})
+		So(err, ShouldBeNil)
+
+		err = os.WriteFile(allowListPath, allowListBlob, 0o600)
+		So(err, ShouldBeNil)
+
+		persistedQuery := func(hash string) map[string]interface{} {
+			return map[string]interface{}{
+				"persistedQuery": map[string]interface{}{"sha256Hash": hash, "version": 1},
+			}
+		}
+
+		Convey("Automatic persisted queries", func() {
+			persistedQueries, err := NewPersistedQueries(nil, log)
+			So(err, ShouldBeNil)
+
+			// queries without the extension are not changed
+			rawParams := &graphql.RawParams{Query: otherQuery}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldBeNil)
+			So(rawParams.Query, ShouldEqual, otherQuery)
+
+			// the hash is not known yet
+			rawParams = &graphql.RawParams{Extensions: persistedQuery(computeQueryHash(otherQuery))}
+			gqlErr := persistedQueries.MutateOperationParameters(ctx, rawParams)
+			So(gqlErr, ShouldNotBeNil)
+			So(gqlErr.Message, ShouldEqual, errPersistedQueryNotFound)
+			So(gqlErr.Extensions["code"], ShouldEqual, errPersistedQueryNotFoundCode)
+
+			// the client sends the query with its hash
+			rawParams = &graphql.RawParams{Query: otherQuery, Extensions: persistedQuery(computeQueryHash(otherQuery))}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldBeNil)
+
+			rawParams = &graphql.RawParams{Extensions: persistedQuery(computeQueryHash(otherQuery))}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldBeNil)
+			So(rawParams.Query, ShouldEqual, otherQuery)
+
+			// the hash doesn't match the query
+			rawParams = &graphql.RawParams{Query: otherQuery, Extensions: persistedQuery(computeQueryHash(allowedQuery))}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldNotBeNil)
+
+			rawParams = &graphql.RawParams{Extensions: map[string]interface{}{
+				"persistedQuery": map[string]interface{}{"sha256Hash": computeQueryHash(otherQuery), "version": 2},
+			}}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldNotBeNil)
+
+			rawParams = &graphql.RawParams{Extensions: map[string]interface{}{"persistedQuery": "bad"}}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldNotBeNil)
+		})
+
+		Convey("Allow list", func() {
+			persistedQueries, err := NewPersistedQueries(&extconf.PersistedQueriesConfig{
+				CacheSize: 10,
+				AllowList: allowListPath,
+			}, log)
+			So(err, ShouldBeNil)
+
+			// queries of the allow list are known without being sent first
+			rawParams := &graphql.RawParams{Extensions: persistedQuery(computeQueryHash(allowedQuery))}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldBeNil)
+			So(rawParams.Query, ShouldEqual, allowedQuery)
+
+			// other queries are still accepted
+			rawParams = &graphql.RawParams{Query: otherQuery}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldBeNil)
+		})
+
+		Convey("Allow list only", func() {
+			persistedQueries, err := NewPersistedQueries(&extconf.PersistedQueriesConfig{
+				AllowList:     allowListPath,
+				AllowListOnly: true,
+			}, log)
+			So(err, ShouldBeNil)
+
+			rawParams := &graphql.RawParams{Extensions: persistedQuery(computeQueryHash(allowedQuery))}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldBeNil)
+			So(rawParams.Query, ShouldEqual, allowedQuery)
+
+			rawParams = &graphql.RawParams{Query: allowedQuery}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldBeNil)
+
+			rawParams = &graphql.RawParams{Query: otherQuery}
+			gqlErr := persistedQueries.MutateOperationParameters(ctx, rawParams)
+			So(gqlErr, ShouldNotBeNil)
+			So(gqlErr.Message, ShouldEqual, zerr.ErrQueryNotAllowed.Error())
+
+			// clients can't register new queries
+			rawParams = &graphql.RawParams{Query: otherQuery, Extensions: persistedQuery(computeQueryHash(otherQuery))}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldNotBeNil)
+
+			rawParams = &graphql.RawParams{Extensions: persistedQuery(computeQueryHash(otherQuery))}
+			So(persistedQueries.MutateOperationParameters(ctx, rawParams), ShouldNotBeNil)
+		})
+
+		Convey("Bad allow lists", func() {
+			_, err := NewPersistedQueries(&extconf.PersistedQueriesConfig{
+				AllowList: path.Join(t.TempDir(), "missing.json"),
+			}, log)
+			So(err, ShouldNotBeNil)
+
+			err = os.WriteFile(allowListPath, []byte("bad"), 0o600)
+			So(err, ShouldBeNil)
+
+			_, err = NewPersistedQueries(&extconf.PersistedQueriesConfig{AllowList: allowListPath}, log)
+			So(err, ShouldWrap, zerr.ErrBadConfig)
+
+			allowListBlob, err := json.Marshal(map[string]string{computeQueryHash(allowedQuery): otherQuery})
+			So(err, ShouldBeNil)
+
+			err = os.WriteFile(allowListPath, allowListBlob, 0o600)
+			So(err, ShouldBeNil)
+
+			_, err = NewPersistedQueries(&extconf.PersistedQueriesConfig{AllowList: allowListPath}, log)
+			So(err, ShouldWrap, zerr.ErrBadConfig)
+		})
+	})
+}
@@ -0,0 +1,212 @@
+package search
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	apiErr "zotregistry.io/zot/pkg/api/errors"
+	zcommon "zotregistry.io/zot/pkg/common"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+)
+
+// MaxReadmeSize is the maximum size of a README, the READMEs are part of the repo metadata.
+const MaxReadmeSize = 64 << 10
+
+// ReadmeHandler serves the markdown READMEs of the repos, stored in MetaDB next to the stars and statistics.
+type ReadmeHandler struct {
+	metaDB mTypes.MetaDB
+	log    log.Logger
+}
+
+func NewReadmeHandler(metaDB mTypes.MetaDB, log log.Logger) *ReadmeHandler {
+	return &ReadmeHandler{
+		metaDB: metaDB,
+		log:    log,
+	}
+}
+
+func (rh *ReadmeHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	repo := request.URL.Query().Get("repo")
+	if repo == "" {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	switch request.Method {
+	case http.MethodGet:
+		rh.GetReadme(response, request, repo)
+	case http.MethodPut:
+		rh.SetReadme(response, request, repo)
+	case http.MethodDelete:
+		rh.DeleteReadme(response, request, repo)
+	default:
+		response.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// GetReadme godoc
+// @Summary Get the README of a repository
+// @Description Get the markdown README of a repository
+// @Router  /v2/_zot/ext/readme [get]
+// @Produce text/markdown
+// @Param   repo      query    string     true  "repository name"
+// @Success 200 {string}   string   "the README"
+// @Failure 400 {string}   string   "bad request"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func (rh *ReadmeHandler) GetReadme(response http.ResponseWriter, request *http.Request, repo string) {
+	repoMeta, ok := rh.getRepoMeta(response, request, repo, constants.ReadPermission)
+	if !ok {
+		return
+	}
+
+	if repoMeta.Readme == nil {
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	response.Header().Set("Last-Modified", repoMeta.Readme.LastUpdated.UTC().Format(http.TimeFormat))
+	zcommon.WriteData(response, http.StatusOK, "text/markdown; charset=utf-8", []byte(repoMeta.Readme.Content))
+}
+
+// SetReadme godoc
+// @Summary Set the README of a repository
+// @Description Create or replace the markdown README of a repository, requires the update permission on it
+// @Router  /v2/_zot/ext/readme [put]
+// @Accept  text/markdown
+// @Param   repo      query    string     true  "repository name"
+// @Param   readme    body     string     true  "the README"
+// @Success 204 {string}   string   "no content"
+// @Failure 400 {string}   string   "bad request"
+// @Failure 403 {string}   string   "forbidden"
+// @Failure 404 {string}   string   "not found"
+// @Failure 413 {string}   string   "request entity too large"
+// @Failure 500 {string}   string   "internal server error".
+func (rh *ReadmeHandler) SetReadme(response http.ResponseWriter, request *http.Request, repo string) {
+	if _, ok := rh.getRepoMeta(response, request, repo, constants.UpdatePermission); !ok {
+		return
+	}
+
+	content, err := io.ReadAll(http.MaxBytesReader(response, request.Body, MaxReadmeSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.WriteHeader(http.StatusRequestEntityTooLarge)
+
+			return
+		}
+
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if len(content) == 0 || !utf8.Valid(content) {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	readme := &mTypes.RepoReadme{
+		Content:     string(content),
+		LastUpdated: time.Now().UTC(),
+	}
+
+	if userAc, err := reqCtx.UserAcFromContext(request.Context()); err == nil {
+		readme.UpdatedBy = userAc.GetUsername()
+	}
+
+	if err := rh.metaDB.SetRepoReadme(repo, readme); err != nil {
+		rh.writeError(response, repo, err)
+
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteReadme godoc
+// @Summary Delete the README of a repository
+// @Description Delete the markdown README of a repository, requires the update permission on it
+// @Router  /v2/_zot/ext/readme [delete]
+// @Param   repo      query    string     true  "repository name"
+// @Success 204 {string}   string   "no content"
+// @Failure 400 {string}   string   "bad request"
+// @Failure 403 {string}   string   "forbidden"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func (rh *ReadmeHandler) DeleteReadme(response http.ResponseWriter, request *http.Request, repo string) {
+	repoMeta, ok := rh.getRepoMeta(response, request, repo, constants.UpdatePermission)
+	if !ok {
+		return
+	}
+
+	if repoMeta.Readme == nil {
+		response.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	if err := rh.metaDB.SetRepoReadme(repo, nil); err != nil {
+		rh.writeError(response, repo, err)
+
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+// getRepoMeta writes a not found response if the repo doesn't exist or the user can't read it,
+// and a forbidden response if the user can read it but doesn't have the requested permission.
+func (rh *ReadmeHandler) getRepoMeta(response http.ResponseWriter, request *http.Request, repo, action string,
+) (mTypes.RepoMetadata, bool) {
+	if ok, err := reqCtx.RepoIsUserAvailable(request.Context(), repo); !ok || err != nil {
+		writeRepoNotFound(response, repo)
+
+		return mTypes.RepoMetadata{}, false
+	}
+
+	if action != constants.ReadPermission {
+		userAc, err := reqCtx.UserAcFromContext(request.Context())
+		if err != nil || !userAc.Can(action, repo) {
+			response.WriteHeader(http.StatusForbidden)
+
+			return mTypes.RepoMetadata{}, false
+		}
+	}
+
+	repoMeta, err := rh.metaDB.GetRepoMeta(repo)
+	if err != nil {
+		rh.writeError(response, repo, err)
+
+		return mTypes.RepoMetadata{}, false
+	}
+
+	return repoMeta, true
+}
+
+func (rh *ReadmeHandler) writeError(response http.ResponseWriter, repo string, err error) {
+	if errors.Is(err, zerr.ErrRepoMetaNotFound) {
+		writeRepoNotFound(response, repo)
+
+		return
+	}
+
+	rh.log.Error().Err(err).Str("repository", repo).Msg("readme: unable to access the README of the repo")
+
+	response.WriteHeader(http.StatusInternalServerError)
+}
+
+func writeRepoNotFound(response http.ResponseWriter, repo string) {
+	zcommon.WriteJSON(response, http.StatusNotFound,
+		apiErr.NewErrorList(apiErr.NewError(apiErr.NAME_UNKNOWN).AddDetail(map[string]string{"name": repo})))
+}
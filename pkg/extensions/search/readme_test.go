@@ -0,0 +1,177 @@
+//go:build search
+// +build search
+
+package search_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	"zotregistry.io/zot/pkg/common"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/search"
+	. "zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestRepoReadme(t *testing.T) {
+	Convey("Test the READMEs of the repos", t, func() {
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		readmeURL := baseURL + constants.FullReadme
+		defaultVal := true
+
+		htpasswdPath := MakeHtpasswdFileFromString(GetCredString("writer", "writer") + "\n" +
+			GetCredString("reader", "reader"))
+		defer os.Remove(htpasswdPath)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{Users: []string{"writer"}, Actions: []string{"read", "create", "update"}},
+						{Users: []string{"reader"}, Actions: []string{"read"}},
+					},
+				},
+				"private": config.PolicyGroup{
+					Policies: []config.Policy{
+						{Users: []string{"writer"}, Actions: []string{"read", "create", "update"}},
+					},
+				},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "repo", "1.0", "writer", "writer")
+		So(err, ShouldBeNil)
+
+		err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "private", "1.0", "writer", "writer")
+		So(err, ShouldBeNil)
+
+		readme := "# repo\n\nRun it with `docker run repo:1.0`.\n"
+
+		resp, err := resty.R().SetBasicAuth("reader", "reader").Get(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = resty.R().SetBasicAuth("reader", "reader").Get(readmeURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		// the update permission is required to change the README
+		resp, err = resty.R().SetBasicAuth("reader", "reader").SetBody(readme).Put(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().Get(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").SetBody("").Put(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").SetBody([]byte{0xff, 0xfe}).Put(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").
+			SetBody(strings.Repeat("a", search.MaxReadmeSize+1)).Put(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusRequestEntityTooLarge)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").SetBody(readme).Put(readmeURL + "?repo=missing")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").SetBody(readme).Put(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNoContent)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").SetBody(readme).Put(readmeURL + "?repo=private")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNoContent)
+
+		resp, err = resty.R().SetBasicAuth("reader", "reader").Get(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(resp.Header().Get("Content-Type"), ShouldEqual, "text/markdown; charset=utf-8")
+		So(resp.Header().Get("Last-Modified"), ShouldNotBeEmpty)
+		So(string(resp.Body()), ShouldEqual, readme)
+
+		// the users who can't read the repo can't read its README
+		resp, err = resty.R().SetBasicAuth("reader", "reader").Get(readmeURL + "?repo=private")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		query := `{ ExpandedRepoInfo(repo:"repo") { Summary { Name } Readme { Content LastUpdated UpdatedBy } } }`
+
+		resp, err = resty.R().SetBasicAuth("reader", "reader").
+			Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(query))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		var repoInfoResp common.ExpandedRepoInfoResp
+
+		err = json.Unmarshal(resp.Body(), &repoInfoResp)
+		So(err, ShouldBeNil)
+		So(repoInfoResp.Errors, ShouldBeEmpty)
+		So(repoInfoResp.RepoInfo.Readme, ShouldNotBeNil)
+		So(repoInfoResp.RepoInfo.Readme.Content, ShouldEqual, readme)
+		So(repoInfoResp.RepoInfo.Readme.UpdatedBy, ShouldEqual, "writer")
+		So(repoInfoResp.RepoInfo.Readme.LastUpdated.IsZero(), ShouldBeFalse)
+
+		// the README isn't removed when the tags change
+		err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "repo", "2.0", "writer", "writer")
+		So(err, ShouldBeNil)
+
+		resp, err = resty.R().SetBasicAuth("reader", "reader").Get(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = resty.R().SetBasicAuth("reader", "reader").Delete(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").Delete(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNoContent)
+
+		resp, err = resty.R().SetBasicAuth("writer", "writer").Delete(readmeURL + "?repo=repo")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = resty.R().SetBasicAuth("reader", "reader").
+			Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(query))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		repoInfoResp = common.ExpandedRepoInfoResp{}
+
+		err = json.Unmarshal(resp.Body(), &repoInfoResp)
+		So(err, ShouldBeNil)
+		So(repoInfoResp.RepoInfo.Readme, ShouldBeNil)
+	})
+}
@@ -24,6 +24,7 @@ import (
 	cvemodel "zotregistry.io/zot/pkg/extensions/search/cve/model"
 	"zotregistry.io/zot/pkg/extensions/search/gql_generated"
 	"zotregistry.io/zot/pkg/extensions/search/pagination"
+	"zotregistry.io/zot/pkg/extensions/secrets"
 	"zotregistry.io/zot/pkg/log"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
 	reqCtx "zotregistry.io/zot/pkg/requestcontext"
@@ -41,17 +42,19 @@ type Resolver struct {
 	cveInfo         cveinfo.CveInfo
 	metaDB          mTypes.MetaDB
 	storeController storage.StoreController
+	resultCache     *ResultCache
 	log             log.Logger
 }
 
 // GetResolverConfig ...
 func GetResolverConfig(log log.Logger, storeController storage.StoreController,
-	metaDB mTypes.MetaDB, cveInfo cveinfo.CveInfo,
+	metaDB mTypes.MetaDB, cveInfo cveinfo.CveInfo, resultCache *ResultCache,
 ) gql_generated.Config {
 	resConfig := &Resolver{
 		cveInfo:         cveInfo,
 		metaDB:          metaDB,
 		storeController: storeController,
+		resultCache:     resultCache,
 		log:             log,
 	}
 
@@ -281,11 +284,12 @@ func getImageSummary(ctx context.Context, repo, tag string, digest *string, meta
 }
 
 func getCVEListForImage(
-	ctx context.Context, //nolint:unparam // may be used in the future to filter by permissions
+	ctx context.Context,
 	image string,
 	cveInfo cveinfo.CveInfo,
 	requestedPage *gql_generated.PageInput,
 	searchedCVE string,
+	fixStatus *gql_generated.FixStatus,
 	log log.Logger, //nolint:unparam // may be used by devs for debugging
 ) (*gql_generated.CVEResultForImage, error) {
 	if requestedPage == nil {
@@ -306,7 +310,9 @@ func getCVEListForImage(
 		return &gql_generated.CVEResultForImage{}, gqlerror.Errorf("no reference provided")
 	}
 
-	cveList, pageInfo, err := cveInfo.GetCVEListForImage(repo, ref, searchedCVE, pageInput)
+	cveFixStatus := cvemodel.FixStatus(safeDereferencing(fixStatus, ""))
+
+	cveList, pageInfo, err := cveInfo.GetCVEListForImage(repo, ref, searchedCVE, cveFixStatus, pageInput)
 	if err != nil {
 		return &gql_generated.CVEResultForImage{}, err
 	}
@@ -318,17 +324,21 @@ func getCVEListForImage(
 		desc := cveDetail.Description
 		title := cveDetail.Title
 		severity := cveDetail.Severity
+		cveHasFix := cveDetail.HasFix()
+		cveFixStatus := gql_generated.FixStatus(cveDetail.FixStatus())
 
 		pkgList := make([]*gql_generated.PackageInfo, 0)
 
 		for _, pkg := range cveDetail.PackageList {
 			pkg := pkg
+			pkgHasFix := pkg.HasFix()
 
 			pkgList = append(pkgList,
 				&gql_generated.PackageInfo{
 					Name:             &pkg.Name,
 					InstalledVersion: &pkg.InstalledVersion,
 					FixedVersion:     &pkg.FixedVersion,
+					HasFix:           &pkgHasFix,
 				},
 			)
 		}
@@ -340,10 +350,24 @@ func getCVEListForImage(
 				Description: &desc,
 				Severity:    &severity,
 				PackageList: pkgList,
+				HasFix:      &cveHasFix,
+				FixStatus:   &cveFixStatus,
 			},
 		)
 	}
 
+	var upgradeAdvice []*gql_generated.PackageUpgrade
+
+	// the advice covers all the CVEs matching the filters, not only the requested page
+	if !canSkipField(convert.GetPreloads(ctx), "UpgradeAdvice") {
+		allCVEs, _, err := cveInfo.GetCVEListForImage(repo, ref, searchedCVE, cveFixStatus, cvemodel.PageInput{})
+		if err != nil {
+			return &gql_generated.CVEResultForImage{}, err
+		}
+
+		upgradeAdvice = getUpgradeAdvice(allCVEs)
+	}
+
 	return &gql_generated.CVEResultForImage{
 		Tag:     &ref,
 		CVEList: cveids,
@@ -351,9 +375,40 @@ func getCVEListForImage(
 			TotalCount: pageInfo.TotalCount,
 			ItemCount:  pageInfo.ItemCount,
 		},
+		UpgradeAdvice: upgradeAdvice,
 	}, nil
 }
 
+func getUpgradeAdvice(cveList []cvemodel.CVE) []*gql_generated.PackageUpgrade {
+	upgradeAdvice := []*gql_generated.PackageUpgrade{}
+
+	for _, upgrade := range cveinfo.GetUpgradeAdvice(cveList) {
+		upgrade := upgrade
+
+		fixedVersions := make([]*string, 0, len(upgrade.FixedVersions))
+
+		for i := range upgrade.FixedVersions {
+			fixedVersions = append(fixedVersions, &upgrade.FixedVersions[i])
+		}
+
+		cveIDs := make([]*string, 0, len(upgrade.CVEIDs))
+
+		for i := range upgrade.CVEIDs {
+			cveIDs = append(cveIDs, &upgrade.CVEIDs[i])
+		}
+
+		upgradeAdvice = append(upgradeAdvice, &gql_generated.PackageUpgrade{
+			Name:               &upgrade.Name,
+			InstalledVersion:   &upgrade.InstalledVersion,
+			RecommendedVersion: &upgrade.RecommendedVersion,
+			FixedVersions:      fixedVersions,
+			CVEIds:             cveIDs,
+		})
+	}
+
+	return upgradeAdvice
+}
+
 func FilterByTagInfo(tagsInfo []cvemodel.TagInfo) mTypes.FilterFunc {
 	return func(repoMeta mTypes.RepoMetadata, manifestMeta mTypes.ManifestMetadata) bool {
 		manifestDigest := godigest.FromBytes(manifestMeta.ManifestBlob).String()
@@ -488,6 +543,233 @@ func getImageListForCVE(
 	}, nil
 }
 
+func getLicenseListForImage(
+	image string,
+	cveInfo cveinfo.CveInfo,
+	log log.Logger,
+) (*gql_generated.LicenseResultForImage, error) {
+	repo, ref, _ := zcommon.GetImageDirAndReference(image)
+
+	if ref == "" {
+		return &gql_generated.LicenseResultForImage{}, gqlerror.Errorf("no reference provided")
+	}
+
+	licenses, err := cveInfo.GetLicensesForImage(repo, ref)
+	if err != nil {
+		log.Error().Err(err).Str("image", image).Msg("unable to get licenses for image")
+
+		return &gql_generated.LicenseResultForImage{}, err
+	}
+
+	licenseList := make([]*gql_generated.License, 0, len(licenses))
+
+	for _, license := range licenses {
+		license := license
+
+		licenseList = append(licenseList, &gql_generated.License{
+			Name:        &license.Name,
+			Category:    &license.Category,
+			Severity:    &license.Severity,
+			PackageName: &license.PackageName,
+			FilePath:    &license.FilePath,
+		})
+	}
+
+	return &gql_generated.LicenseResultForImage{
+		Tag:         &ref,
+		LicenseList: licenseList,
+	}, nil
+}
+
+func getImageListForLicense(
+	ctx context.Context,
+	licenses []string,
+	cveInfo cveinfo.CveInfo,
+	filter *gql_generated.Filter,
+	requestedPage *gql_generated.PageInput,
+	metaDB mTypes.MetaDB,
+	log log.Logger,
+) (*gql_generated.PaginatedImagesResult, error) {
+	if len(licenses) == 0 {
+		return &gql_generated.PaginatedImagesResult{}, gqlerror.Errorf("no license provided")
+	}
+
+	// Like for CVEs, the images are scanned before filtering the results so the DB isn't kept locked
+	reposMeta, err := metaDB.GetMultipleRepoMeta(ctx, func(repoMeta mTypes.RepoMetadata) bool { return true })
+	if err != nil {
+		return &gql_generated.PaginatedImagesResult{}, err
+	}
+
+	matchingImages := []cvemodel.TagInfo{}
+
+	for _, repoMeta := range reposMeta {
+		repo := repoMeta.Name
+
+		log.Info().Str("repository", repo).Strs("licenses", licenses).Msg("extracting list of tags with licenses")
+
+		tagsInfo, err := cveInfo.GetImageListForLicense(repo, licenses)
+		if err != nil {
+			log.Error().Str("repository", repo).Strs("licenses", licenses).Err(err).
+				Msg("error getting image list for licenses from repo")
+
+			return &gql_generated.PaginatedImagesResult{}, err
+		}
+
+		matchingImages = append(matchingImages, tagsInfo...)
+	}
+
+	skip := convert.SkipQGLField{
+		Vulnerabilities: canSkipField(convert.GetPreloads(ctx), "Results.Vulnerabilities"),
+	}
+
+	if requestedPage == nil {
+		requestedPage = &gql_generated.PageInput{}
+	}
+
+	localFilter := mTypes.Filter{}
+	if filter != nil {
+		localFilter = mTypes.Filter{
+			Os:            filter.Os,
+			Arch:          filter.Arch,
+			HasToBeSigned: filter.HasToBeSigned,
+			IsBookmarked:  filter.IsBookmarked,
+			IsStarred:     filter.IsStarred,
+		}
+	}
+
+	pageInput := pagination.PageInput{
+		Limit:  safeDereferencing(requestedPage.Limit, 0),
+		Offset: safeDereferencing(requestedPage.Offset, 0),
+		SortBy: pagination.SortCriteria(
+			safeDereferencing(requestedPage.SortBy, gql_generated.SortCriteriaUpdateTime),
+		),
+	}
+
+	reposMeta, manifestMetaMap, indexDataMap, err := metaDB.FilterTags(ctx, FilterByTagInfo(matchingImages))
+	if err != nil {
+		return &gql_generated.PaginatedImagesResult{}, err
+	}
+
+	imageSummaries, pageInfo, err := convert.PaginatedRepoMeta2ImageSummaries(ctx, reposMeta, manifestMetaMap,
+		indexDataMap, skip, cveInfo, localFilter, pageInput)
+	if err != nil {
+		return &gql_generated.PaginatedImagesResult{}, err
+	}
+
+	return &gql_generated.PaginatedImagesResult{
+		Results: imageSummaries,
+		Page: &gql_generated.PageInfo{
+			TotalCount: pageInfo.TotalCount,
+			ItemCount:  pageInfo.ItemCount,
+		},
+	}, nil
+}
+
+// getSecretListForImage returns the findings of the secret scan reports pushed as referrers of the image.
+func getSecretListForImage(
+	ctx context.Context,
+	image string,
+	metaDB mTypes.MetaDB,
+	storeController storage.StoreController,
+	log log.Logger,
+) (*gql_generated.SecretResultForImage, error) {
+	repo, ref, isTag := zcommon.GetImageDirAndReference(image)
+
+	if ref == "" {
+		return &gql_generated.SecretResultForImage{}, gqlerror.Errorf("no reference provided")
+	}
+
+	if ok, err := reqCtx.RepoIsUserAvailable(ctx, repo); !ok || err != nil {
+		log.Info().Err(err).Str("repository", repo).Bool("availability", ok).Msg("resolver: repo user availability")
+
+		return &gql_generated.SecretResultForImage{}, nil //nolint:nilerr // don't give details to a potential attacker
+	}
+
+	result := &gql_generated.SecretResultForImage{SecretList: []*gql_generated.Secret{}}
+	digest := ref
+
+	if isTag {
+		repoMeta, err := metaDB.GetUserRepoMeta(ctx, repo)
+		if err != nil {
+			return &gql_generated.SecretResultForImage{}, err
+		}
+
+		descriptor, ok := repoMeta.Tags[ref]
+		if !ok {
+			return &gql_generated.SecretResultForImage{}, zerr.ErrTagMetaNotFound
+		}
+
+		digest = descriptor.Digest
+		result.Tag = &ref
+	}
+
+	result.Digest = &digest
+
+	referrers, err := metaDB.GetReferrersInfo(repo, godigest.Digest(digest), []string{secrets.ArtifactType})
+	if err != nil {
+		log.Error().Err(err).Str("image", image).Msg("unable to get secret scan reports of image")
+
+		return &gql_generated.SecretResultForImage{}, err
+	}
+
+	imgStore := storeController.GetImageStore(repo)
+	seen := map[secrets.Finding]bool{}
+
+	for _, referrer := range referrers {
+		manifestBlob, _, _, err := imgStore.GetImageManifest(repo, referrer.Digest)
+		if err != nil {
+			log.Error().Err(err).Str("image", image).Str("report", referrer.Digest).
+				Msg("unable to get secret scan report manifest")
+
+			return &gql_generated.SecretResultForImage{}, err
+		}
+
+		var manifest ispec.Manifest
+
+		if err := json.Unmarshal(manifestBlob, &manifest); err != nil {
+			return &gql_generated.SecretResultForImage{}, err
+		}
+
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != secrets.ReportMediaType {
+				continue
+			}
+
+			reportBlob, err := imgStore.GetBlobContent(repo, layer.Digest)
+			if err != nil {
+				return &gql_generated.SecretResultForImage{}, err
+			}
+
+			var report secrets.Report
+
+			if err := json.Unmarshal(reportBlob, &report); err != nil {
+				return &gql_generated.SecretResultForImage{}, err
+			}
+
+			for _, finding := range report.Findings {
+				if seen[finding] {
+					continue
+				}
+
+				seen[finding] = true
+				finding := finding
+
+				result.SecretList = append(result.SecretList, &gql_generated.Secret{
+					RuleID:      &finding.RuleID,
+					Description: &finding.Description,
+					Confidence:  &finding.Confidence,
+					LayerDigest: &finding.LayerDigest,
+					FilePath:    &finding.FilePath,
+					Line:        &finding.Line,
+					Match:       &finding.Match,
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
+
 func getImageListWithCVEFixed(
 	ctx context.Context,
 	cveID string,
@@ -626,6 +908,63 @@ func getBookmarkedRepos(
 	return getFilteredPaginatedRepos(ctx, cveInfo, filterFn, log, requestedPage, metaDB)
 }
 
+func getBookmarkLists(ctx context.Context, metaDB mTypes.MetaDB) ([]*gql_generated.BookmarkList, error) {
+	lists, err := metaDB.GetBookmarkLists(ctx)
+	if err != nil {
+		return []*gql_generated.BookmarkList{}, err
+	}
+
+	results := make([]*gql_generated.BookmarkList, 0, len(lists))
+
+	for i := range lists {
+		results = append(results, &gql_generated.BookmarkList{
+			Name:       &lists[i].Name,
+			Owner:      &lists[i].Owner,
+			Repos:      lists[i].Repos,
+			SharedWith: lists[i].SharedWith,
+		})
+	}
+
+	return results, nil
+}
+
+func getBookmarkListRepos(
+	ctx context.Context,
+	cveInfo cveinfo.CveInfo,
+	log log.Logger, //nolint:unparam // may be used by devs for debugging
+	name string,
+	owner *string,
+	requestedPage *gql_generated.PageInput,
+	metaDB mTypes.MetaDB,
+) (*gql_generated.PaginatedReposResult, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return &gql_generated.PaginatedReposResult{}, err
+	}
+
+	listOwner := safeDereferencing(owner, userAc.GetUsername())
+
+	// only the lists readable by the user are returned, with the repos readable by the user
+	lists, err := metaDB.GetBookmarkLists(ctx)
+	if err != nil {
+		return &gql_generated.PaginatedReposResult{}, err
+	}
+
+	for _, list := range lists {
+		if list.Name != name || list.Owner != listOwner {
+			continue
+		}
+
+		filterFn := func(repoMeta mTypes.RepoMetadata) bool {
+			return zcommon.Contains(list.Repos, repoMeta.Name)
+		}
+
+		return getFilteredPaginatedRepos(ctx, cveInfo, filterFn, log, requestedPage, metaDB)
+	}
+
+	return &gql_generated.PaginatedReposResult{}, zerr.ErrBookmarkListNotFound
+}
+
 func getStarredRepos(
 	ctx context.Context,
 	cveInfo cveinfo.CveInfo,
@@ -818,8 +1157,9 @@ func derivedImageList(ctx context.Context, image string, digest *string, metaDB
 		return &gql_generated.PaginatedImagesResult{}, err
 	}
 
-	// we need all available tags
-	reposMeta, manifestMetaMap, indexDataMap, err := metaDB.FilterTags(ctx, filterDerivedImages(searchedImage))
+	// a derived image has all the layers of the image, so it has the first one
+	reposMeta, manifestMetaMap, indexDataMap, err := filterTagsByLayers(ctx, metaDB, searchedImage, true,
+		filterDerivedImages(searchedImage))
 	if err != nil {
 		return &gql_generated.PaginatedImagesResult{}, err
 	}
@@ -839,6 +1179,43 @@ func derivedImageList(ctx context.Context, image string, digest *string, metaDB
 	}, nil
 }
 
+// filterTagsByLayers only filters the images having layers of the searched image, looked up in the layer index,
+// instead of every image. With firstLayerOnly only the images having the first layer of the image are filtered.
+// Every image is filtered if the MetaDB has no layer index.
+func filterTagsByLayers(ctx context.Context, metaDB mTypes.MetaDB, image *gql_generated.ImageSummary,
+	firstLayerOnly bool, filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	manifestDigests := []godigest.Digest{}
+	foundManifests := map[godigest.Digest]bool{}
+
+	for _, manifest := range image.Manifests {
+		layers := manifest.Layers
+		if firstLayerOnly && len(layers) > 0 {
+			layers = layers[:1]
+		}
+
+		for _, layer := range layers {
+			layerManifests, err := metaDB.GetManifestsWithLayer(godigest.Digest(*layer.Digest))
+			if err != nil {
+				if errors.Is(err, zerr.ErrLayerIndexDisabled) {
+					return metaDB.FilterTags(ctx, filterFunc)
+				}
+
+				return nil, nil, nil, err
+			}
+
+			for _, manifestDigest := range layerManifests {
+				if !foundManifests[manifestDigest] {
+					foundManifests[manifestDigest] = true
+					manifestDigests = append(manifestDigests, manifestDigest)
+				}
+			}
+		}
+	}
+
+	return metaDB.FilterTagsByManifests(ctx, manifestDigests, filterFunc)
+}
+
 func filterDerivedImages(image *gql_generated.ImageSummary) mTypes.FilterFunc {
 	return func(repoMeta mTypes.RepoMetadata, manifestMeta mTypes.ManifestMetadata) bool {
 		var addImageToList bool
@@ -920,8 +1297,9 @@ func baseImageList(ctx context.Context, image string, digest *string, metaDB mTy
 		return &gql_generated.PaginatedImagesResult{}, err
 	}
 
-	// we need all available tags
-	reposMeta, manifestMetaMap, indexDataMap, err := metaDB.FilterTags(ctx, filterBaseImages(searchedImage))
+	// a base image only has layers of the image
+	reposMeta, manifestMetaMap, indexDataMap, err := filterTagsByLayers(ctx, metaDB, searchedImage, false,
+		filterBaseImages(searchedImage))
 	if err != nil {
 		return &gql_generated.PaginatedImagesResult{}, err
 	}
@@ -1217,7 +1595,17 @@ func expandedRepoInfo(ctx context.Context, repo string, metaDB mTypes.MetaDB, cv
 
 	sort.Sort(dateSortedImages)
 
-	return &gql_generated.RepoInfo{Summary: repoSummary, Images: dateSortedImages}, nil
+	repoInfo := &gql_generated.RepoInfo{Summary: repoSummary, Images: dateSortedImages}
+
+	if repoMeta.Readme != nil {
+		repoInfo.Readme = &gql_generated.RepoReadme{
+			Content:     &repoMeta.Readme.Content,
+			LastUpdated: &repoMeta.Readme.LastUpdated,
+			UpdatedBy:   &repoMeta.Readme.UpdatedBy,
+		}
+	}
+
+	return repoInfo, nil
 }
 
 type timeSlice []*gql_generated.ImageSummary
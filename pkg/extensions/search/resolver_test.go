@@ -19,6 +19,7 @@ import (
 	cveinfo "zotregistry.io/zot/pkg/extensions/search/cve"
 	cvemodel "zotregistry.io/zot/pkg/extensions/search/cve/model"
 	"zotregistry.io/zot/pkg/extensions/search/gql_generated"
+	"zotregistry.io/zot/pkg/extensions/secrets"
 	"zotregistry.io/zot/pkg/log"
 	"zotregistry.io/zot/pkg/meta/boltdb"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
@@ -2150,10 +2151,10 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 			dig := godigest.FromString("dig")
 			repoWithDigestRef := fmt.Sprintf("repo@%s", dig)
 
-			_, err := getCVEListForImage(responseContext, repoWithDigestRef, cveInfo, pageInput, "", log)
+			_, err := getCVEListForImage(responseContext, repoWithDigestRef, cveInfo, pageInput, "", nil, log)
 			So(err, ShouldBeNil)
 
-			cveResult, err := getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "", log)
+			cveResult, err := getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "", nil, log)
 			So(err, ShouldBeNil)
 			So(*cveResult.Tag, ShouldEqual, "1.0.0")
 
@@ -2165,7 +2166,7 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 			}
 
 			// test searching CVE by id in results
-			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "CVE3", log)
+			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "CVE3", nil, log)
 			So(err, ShouldBeNil)
 			So(*cveResult.Tag, ShouldEqual, "1.0.0")
 
@@ -2177,13 +2178,13 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 			}
 
 			// test searching CVE by id in results - no matches
-			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "CVE100", log)
+			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "CVE100", nil, log)
 			So(err, ShouldBeNil)
 			So(*cveResult.Tag, ShouldEqual, "1.0.0")
 			So(len(cveResult.CVEList), ShouldEqual, 0)
 
 			// test searching CVE by id in results - partial name
-			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "VE3", log)
+			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "VE3", nil, log)
 			So(err, ShouldBeNil)
 			So(*cveResult.Tag, ShouldEqual, "1.0.0")
 
@@ -2195,7 +2196,7 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 			}
 
 			// test searching CVE by title in results
-			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "Title CVE", log)
+			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.0", cveInfo, pageInput, "Title CVE", nil, log)
 			So(err, ShouldBeNil)
 			So(*cveResult.Tag, ShouldEqual, "1.0.0")
 
@@ -2206,7 +2207,7 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 				So(expectedCves, ShouldContain, *cve.ID)
 			}
 
-			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.1", cveInfo, pageInput, "", log)
+			cveResult, err = getCVEListForImage(responseContext, "repo1:1.0.1", cveInfo, pageInput, "", nil, log)
 			So(err, ShouldBeNil)
 			So(*cveResult.Tag, ShouldEqual, "1.0.1")
 
@@ -2217,7 +2218,7 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 				So(expectedCves, ShouldContain, *cve.ID)
 			}
 
-			cveResult, err = getCVEListForImage(responseContext, "repo1:1.1.0", cveInfo, pageInput, "", log)
+			cveResult, err = getCVEListForImage(responseContext, "repo1:1.1.0", cveInfo, pageInput, "", nil, log)
 			So(err, ShouldBeNil)
 			So(*cveResult.Tag, ShouldEqual, "1.1.0")
 
@@ -2237,7 +2238,7 @@ func TestCVEResolvers(t *testing.T) { //nolint:gocyclo
 			responseContext := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter,
 				graphql.DefaultRecover)
 
-			_, err = getCVEListForImage(responseContext, "repo1:1.1.0", cveInfo, pageInput, "", log)
+			_, err = getCVEListForImage(responseContext, "repo1:1.1.0", cveInfo, pageInput, "", nil, log)
 			So(err, ShouldNotBeNil)
 		})
 	})
@@ -3651,3 +3652,241 @@ func ref[T any](val T) *T {
 
 	return &ref
 }
+
+func TestLicenseResolvers(t *testing.T) {
+	Convey("License resolvers", t, func() {
+		log := log.NewLogger("debug", "")
+		ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter,
+			graphql.DefaultRecover)
+
+		cveInfo := mocks.CveInfoMock{
+			GetLicensesForImageFn: func(repo, reference string) ([]cvemodel.License, error) {
+				return []cvemodel.License{
+					{Name: "AGPL-3.0", Category: "forbidden", Severity: "CRITICAL", PackageName: "pkg1"},
+					{Name: "MIT", Category: "notice", Severity: "LOW", FilePath: "LICENSE"},
+				}, nil
+			},
+			GetImageListForLicenseFn: func(repo string, licenses []string) ([]cvemodel.TagInfo, error) {
+				if repo != "repo1" {
+					return []cvemodel.TagInfo{}, nil
+				}
+
+				return []cvemodel.TagInfo{
+					{
+						Tag: "1.0.0",
+						Descriptor: cvemodel.Descriptor{
+							Digest:    godigest.FromString("digest"),
+							MediaType: ispec.MediaTypeImageManifest,
+						},
+					},
+				}, nil
+			},
+		}
+
+		Convey("getLicenseListForImage", func() {
+			result, err := getLicenseListForImage("repo1:1.0.0", cveInfo, log)
+			So(err, ShouldBeNil)
+			So(*result.Tag, ShouldEqual, "1.0.0")
+			So(len(result.LicenseList), ShouldEqual, 2)
+			So(*result.LicenseList[0].Name, ShouldEqual, "AGPL-3.0")
+			So(*result.LicenseList[0].PackageName, ShouldEqual, "pkg1")
+			So(*result.LicenseList[1].FilePath, ShouldEqual, "LICENSE")
+
+			_, err = getLicenseListForImage("repo1", cveInfo, log)
+			So(err, ShouldNotBeNil)
+
+			cveInfo.GetLicensesForImageFn = func(repo, reference string) ([]cvemodel.License, error) {
+				return nil, ErrTestError
+			}
+
+			_, err = getLicenseListForImage("repo1:1.0.0", cveInfo, log)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("getImageListForLicense", func() {
+			metaDB := mocks.MetaDBMock{
+				GetMultipleRepoMetaFn: func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+				) ([]mTypes.RepoMetadata, error) {
+					return []mTypes.RepoMetadata{{Name: "repo1"}, {Name: "repo2"}}, nil
+				},
+				FilterTagsFn: func(ctx context.Context, filterFunc mTypes.FilterFunc,
+				) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+					return []mTypes.RepoMetadata{}, map[string]mTypes.ManifestMetadata{},
+						map[string]mTypes.IndexData{}, nil
+				},
+			}
+
+			_, err := getImageListForLicense(ctx, []string{"AGPL"}, cveInfo, nil, nil, metaDB, log)
+			So(err, ShouldBeNil)
+
+			_, err = getImageListForLicense(ctx, []string{}, cveInfo, nil, nil, metaDB, log)
+			So(err, ShouldNotBeNil)
+
+			cveInfo.GetImageListForLicenseFn = func(repo string, licenses []string) ([]cvemodel.TagInfo, error) {
+				return nil, ErrTestError
+			}
+
+			_, err = getImageListForLicense(ctx, []string{"AGPL"}, cveInfo, nil, nil, metaDB, log)
+			So(err, ShouldNotBeNil)
+
+			metaDB.GetMultipleRepoMetaFn = func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+			) ([]mTypes.RepoMetadata, error) {
+				return nil, ErrTestError
+			}
+
+			_, err = getImageListForLicense(ctx, []string{"AGPL"}, cveInfo, nil, nil, metaDB, log)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("license search disabled", func() {
+			resolver := queryResolver{NewResolver(log, storage.StoreController{}, mocks.MetaDBMock{}, nil)}
+
+			_, err := resolver.LicenseListForImage(ctx, "repo1:1.0.0")
+			So(err, ShouldNotBeNil)
+
+			_, err = resolver.ImageListForLicense(ctx, []string{"AGPL"}, nil, nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSecretListForImage(t *testing.T) {
+	Convey("getSecretListForImage", t, func() {
+		log := log.NewLogger("debug", "")
+		ctx := context.Background()
+
+		imageDigest := godigest.FromString("image")
+		reportDigest := godigest.FromString("report")
+
+		report := secrets.Report{
+			Findings: []secrets.Finding{
+				{RuleID: "private-key", Confidence: secrets.ConfidenceHigh, FilePath: "id_rsa", Line: 1, Match: "----****"},
+			},
+		}
+
+		reportBlob, err := json.Marshal(report)
+		So(err, ShouldBeNil)
+
+		reportManifestBlob, err := json.Marshal(ispec.Manifest{
+			ArtifactType: secrets.ArtifactType,
+			Layers: []ispec.Descriptor{
+				{MediaType: ispec.MediaTypeImageLayerGzip, Digest: godigest.FromString("other")},
+				{MediaType: secrets.ReportMediaType, Digest: godigest.FromBytes(reportBlob)},
+			},
+		})
+		So(err, ShouldBeNil)
+
+		metaDB := mocks.MetaDBMock{
+			GetUserRepoMetaFn: func(ctx context.Context, repo string) (mTypes.RepoMetadata, error) {
+				return mTypes.RepoMetadata{
+					Name: repo,
+					Tags: map[string]mTypes.Descriptor{
+						"tag": {Digest: imageDigest.String(), MediaType: ispec.MediaTypeImageManifest},
+					},
+				}, nil
+			},
+			GetReferrersInfoFn: func(repo string, referredDigest godigest.Digest, artifactTypes []string,
+			) ([]mTypes.ReferrerInfo, error) {
+				if referredDigest != imageDigest {
+					return []mTypes.ReferrerInfo{}, nil
+				}
+
+				// the same report pushed twice is only listed once
+				return []mTypes.ReferrerInfo{{Digest: reportDigest.String()}, {Digest: reportDigest.String()}}, nil
+			},
+		}
+
+		imgStore := mocks.MockedImageStore{
+			GetImageManifestFn: func(repo, reference string) ([]byte, godigest.Digest, string, error) {
+				return reportManifestBlob, reportDigest, ispec.MediaTypeImageManifest, nil
+			},
+			GetBlobContentFn: func(repo string, digest godigest.Digest) ([]byte, error) {
+				return reportBlob, nil
+			},
+		}
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		result, err := getSecretListForImage(ctx, "repo:tag", metaDB, storeController, log)
+		So(err, ShouldBeNil)
+		So(*result.Tag, ShouldEqual, "tag")
+		So(*result.Digest, ShouldEqual, imageDigest.String())
+		So(len(result.SecretList), ShouldEqual, 1)
+		So(*result.SecretList[0].RuleID, ShouldEqual, "private-key")
+		So(*result.SecretList[0].Line, ShouldEqual, 1)
+
+		result, err = getSecretListForImage(ctx, "repo@"+imageDigest.String(), metaDB, storeController, log)
+		So(err, ShouldBeNil)
+		So(result.Tag, ShouldBeNil)
+		So(len(result.SecretList), ShouldEqual, 1)
+
+		result, err = getSecretListForImage(ctx, "repo@"+godigest.FromString("clean").String(), metaDB, storeController, log)
+		So(err, ShouldBeNil)
+		So(result.SecretList, ShouldBeEmpty)
+
+		Convey("Repos the user can't read are hidden", func() {
+			userAc := reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user")
+			userAc.SetGlobPatterns("read", map[string]bool{"other": true})
+			userCtx := context.WithValue(ctx, reqCtx.GetContextKey(), *userAc)
+
+			result, err := getSecretListForImage(userCtx, "repo:tag", metaDB, storeController, log)
+			So(err, ShouldBeNil)
+			So(result.SecretList, ShouldBeEmpty)
+		})
+
+		Convey("Errors", func() {
+			_, err := getSecretListForImage(ctx, "repo", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+
+			_, err = getSecretListForImage(ctx, "repo:missing", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+
+			imgStore.GetBlobContentFn = func(repo string, digest godigest.Digest) ([]byte, error) {
+				return []byte("{"), nil
+			}
+			storeController.DefaultStore = imgStore
+
+			_, err = getSecretListForImage(ctx, "repo:tag", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+
+			imgStore.GetBlobContentFn = func(repo string, digest godigest.Digest) ([]byte, error) {
+				return nil, ErrTestError
+			}
+			storeController.DefaultStore = imgStore
+
+			_, err = getSecretListForImage(ctx, "repo:tag", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+
+			imgStore.GetImageManifestFn = func(repo, reference string) ([]byte, godigest.Digest, string, error) {
+				return []byte("{"), reportDigest, ispec.MediaTypeImageManifest, nil
+			}
+			storeController.DefaultStore = imgStore
+
+			_, err = getSecretListForImage(ctx, "repo:tag", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+
+			imgStore.GetImageManifestFn = func(repo, reference string) ([]byte, godigest.Digest, string, error) {
+				return nil, "", "", ErrTestError
+			}
+			storeController.DefaultStore = imgStore
+
+			_, err = getSecretListForImage(ctx, "repo:tag", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+
+			metaDB.GetReferrersInfoFn = func(repo string, referredDigest godigest.Digest, artifactTypes []string,
+			) ([]mTypes.ReferrerInfo, error) {
+				return nil, ErrTestError
+			}
+
+			_, err = getSecretListForImage(ctx, "repo:tag", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+
+			metaDB.GetUserRepoMetaFn = func(ctx context.Context, repo string) (mTypes.RepoMetadata, error) {
+				return mTypes.RepoMetadata{}, ErrTestError
+			}
+
+			_, err = getSecretListForImage(ctx, "repo:tag", metaDB, storeController, log)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,268 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	zerr "zotregistry.io/zot/errors"
+	apiErr "zotregistry.io/zot/pkg/api/errors"
+	zcommon "zotregistry.io/zot/pkg/common"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+)
+
+const (
+	vulnerabilitiesFields = `Vulnerabilities { MaxSeverity Count }`
+	imageFields           = `RepoName Tag Digest MediaType Size DownloadCount LastUpdated Description IsSigned
+		Licenses Labels Title Source Documentation Vendor Authors ` + vulnerabilitiesFields + `
+		Manifests { Digest ConfigDigest LastUpdated Size IsSigned DownloadCount ArtifactType
+			Platform { Os Arch } ` + vulnerabilitiesFields + ` }`
+
+	repoListQuery = `query($requestedPage: PageInput) {
+		RepoListWithNewestImage(requestedPage: $requestedPage) {
+			Page { TotalCount ItemCount }
+			Results { Name LastUpdated Size Platforms { Os Arch } Vendors DownloadCount StarCount IsBookmarked IsStarred
+				NewestImage { Tag Digest MediaType LastUpdated Size ` + vulnerabilitiesFields + ` } }
+		}
+	}`
+	imageListQuery = `query($repo: String!, $requestedPage: PageInput) {
+		ImageList(repo: $repo, requestedPage: $requestedPage) {
+			Page { TotalCount ItemCount }
+			Results { ` + imageFields + ` }
+		}
+	}`
+	imageQuery = `query($image: String!) {
+		Image(image: $image) { ` + imageFields + ` }
+	}`
+)
+
+// RESTHandler serves plain REST wrappers over the most common search queries, for scripts and
+// integrations which can't easily use GraphQL. The queries are run by the GraphQL executor,
+// so the results, and the access control, are the same as for the GraphQL queries.
+type RESTHandler struct {
+	executor *executor.Executor
+	metaDB   mTypes.MetaDB
+	log      log.Logger
+}
+
+func NewRESTHandler(schema graphql.ExecutableSchema, metaDB mTypes.MetaDB, log log.Logger) *RESTHandler {
+	return &RESTHandler{
+		executor: executor.New(schema),
+		metaDB:   metaDB,
+		log:      log,
+	}
+}
+
+// ListRepos godoc
+// @Summary List repositories
+// @Description List the repositories with their metadata and newest image, wraps RepoListWithNewestImage
+// @Router  /v2/_zot/ext/search/rest/repos [get]
+// @Produce json
+// @Param   limit     query    int        false "maximum number of results"
+// @Param   offset    query    int        false "number of results to skip"
+// @Param   sortBy    query    string     false "sort criteria" Enums(RELEVANCE, UPDATE_TIME, ALPHABETIC_ASC, ALPHABETIC_DSC, STARS, DOWNLOADS)
+// @Success 200 {object}   gql_generated.PaginatedReposResult
+// @Failure 400 {string}   string   "bad request"
+// @Failure 500 {string}   string   "internal server error".
+func (rh *RESTHandler) ListRepos(response http.ResponseWriter, request *http.Request) {
+	requestedPage, err := getRequestedPage(request)
+	if err != nil {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	rh.runQuery(response, request, repoListQuery, "RepoListWithNewestImage", map[string]interface{}{
+		"requestedPage": requestedPage,
+	})
+}
+
+// ListImages godoc
+// @Summary List the images of a repository
+// @Description List the images of a repository with their sizes and CVE counts, wraps ImageList
+// @Router  /v2/_zot/ext/search/rest/images [get]
+// @Produce json
+// @Param   repo      query    string     true  "repository name"
+// @Param   limit     query    int        false "maximum number of results"
+// @Param   offset    query    int        false "number of results to skip"
+// @Param   sortBy    query    string     false "sort criteria" Enums(UPDATE_TIME, ALPHABETIC_ASC, ALPHABETIC_DSC, SEVERITY, DOWNLOADS)
+// @Success 200 {object}   gql_generated.PaginatedImagesResult
+// @Failure 400 {string}   string   "bad request"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func (rh *RESTHandler) ListImages(response http.ResponseWriter, request *http.Request) {
+	repo := request.URL.Query().Get("repo")
+	if repo == "" {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	requestedPage, err := getRequestedPage(request)
+	if err != nil {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if _, ok := rh.getRepoMeta(request.Context(), response, repo); !ok {
+		return
+	}
+
+	rh.runQuery(response, request, imageListQuery, "ImageList", map[string]interface{}{
+		"repo":          repo,
+		"requestedPage": requestedPage,
+	})
+}
+
+// GetImage godoc
+// @Summary Get an image
+// @Description Get the summary of a tagged image, wraps Image
+// @Router  /v2/_zot/ext/search/rest/image [get]
+// @Produce json
+// @Param   repo      query    string     true  "repository name"
+// @Param   tag       query    string     true  "image tag"
+// @Success 200 {object}   gql_generated.ImageSummary
+// @Failure 400 {string}   string   "bad request"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func (rh *RESTHandler) GetImage(response http.ResponseWriter, request *http.Request) {
+	repo := request.URL.Query().Get("repo")
+	tag := request.URL.Query().Get("tag")
+
+	if repo == "" || tag == "" {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	repoMeta, ok := rh.getRepoMeta(request.Context(), response, repo)
+	if !ok {
+		return
+	}
+
+	if _, ok := repoMeta.Tags[tag]; !ok {
+		zcommon.WriteJSON(response, http.StatusNotFound,
+			apiErr.NewErrorList(apiErr.NewError(apiErr.MANIFEST_UNKNOWN).AddDetail(map[string]string{"tag": tag})))
+
+		return
+	}
+
+	rh.runQuery(response, request, imageQuery, "Image", map[string]interface{}{
+		"image": repo + ":" + tag,
+	})
+}
+
+// getRepoMeta writes a not found response if the repo doesn't exist or the user can't read it.
+func (rh *RESTHandler) getRepoMeta(ctx context.Context, response http.ResponseWriter, repo string,
+) (mTypes.RepoMetadata, bool) {
+	if ok, err := reqCtx.RepoIsUserAvailable(ctx, repo); !ok || err != nil {
+		zcommon.WriteJSON(response, http.StatusNotFound,
+			apiErr.NewErrorList(apiErr.NewError(apiErr.NAME_UNKNOWN).AddDetail(map[string]string{"name": repo})))
+
+		return mTypes.RepoMetadata{}, false
+	}
+
+	repoMeta, err := rh.metaDB.GetRepoMeta(repo)
+	if err != nil {
+		if errors.Is(err, zerr.ErrRepoMetaNotFound) {
+			zcommon.WriteJSON(response, http.StatusNotFound,
+				apiErr.NewErrorList(apiErr.NewError(apiErr.NAME_UNKNOWN).AddDetail(map[string]string{"name": repo})))
+		} else {
+			rh.log.Error().Err(err).Str("repository", repo).Msg("search rest: unable to get repo metadata")
+
+			response.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return mTypes.RepoMetadata{}, false
+	}
+
+	return repoMeta, true
+}
+
+// runQuery runs the GraphQL query and writes the result of the given query field, without the GraphQL envelope.
+func (rh *RESTHandler) runQuery(response http.ResponseWriter, request *http.Request, query, field string,
+	variables map[string]interface{},
+) {
+	ctx := graphql.StartOperationTrace(request.Context())
+
+	rawParams := &graphql.RawParams{
+		Query:     query,
+		Variables: variables,
+		Headers:   request.Header,
+	}
+	rawParams.ReadTime.Start = graphql.Now()
+	rawParams.ReadTime.End = graphql.Now()
+
+	// the queries are fixed, the validation can only fail because of the parameters, e.g. an unknown sort criteria
+	operationCtx, gqlErrs := rh.executor.CreateOperationContext(ctx, rawParams)
+	if gqlErrs != nil {
+		rh.writeErrors(response, http.StatusBadRequest, gqlErrs)
+
+		return
+	}
+
+	responses, ctx := rh.executor.DispatchOperation(ctx, operationCtx)
+	result := responses(ctx)
+
+	if len(result.Errors) > 0 {
+		rh.writeErrors(response, http.StatusInternalServerError, result.Errors)
+
+		return
+	}
+
+	var data map[string]json.RawMessage
+
+	if err := json.Unmarshal(result.Data, &data); err != nil {
+		rh.log.Error().Err(err).Str("query", field).Msg("search rest: unable to decode query result")
+
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteData(response, http.StatusOK, "application/json", data[field])
+}
+
+func (rh *RESTHandler) writeErrors(response http.ResponseWriter, status int, gqlErrs gqlerror.List) {
+	rh.log.Error().Err(gqlErrs).Msg("search rest: query failed")
+
+	zcommon.WriteJSON(response, status, map[string]interface{}{"errors": gqlErrs})
+}
+
+func getRequestedPage(request *http.Request) (map[string]interface{}, error) {
+	requestedPage := map[string]interface{}{}
+
+	for _, param := range []string{"limit", "offset"} {
+		value := request.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+
+		intValue, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if intValue < 0 {
+			return nil, zerr.ErrInvalidRequestParams
+		}
+
+		requestedPage[param] = intValue
+	}
+
+	if sortBy := request.URL.Query().Get("sortBy"); sortBy != "" {
+		requestedPage["sortBy"] = strings.ToUpper(sortBy)
+	}
+
+	return requestedPage, nil
+}
@@ -6,6 +6,7 @@ package search
 
 import (
 	"context"
+	"time"
 
 	"github.com/vektah/gqlparser/v2/gqlerror"
 	zerr "zotregistry.io/zot/errors"
@@ -14,16 +15,16 @@ import (
 )
 
 // CVEListForImage is the resolver for the CVEListForImage field.
-func (r *queryResolver) CVEListForImage(ctx context.Context, image string, requestedPage *gql_generated.PageInput, searchedCve *string) (*gql_generated.CVEResultForImage, error) {
+func (r *queryResolver) CVEListForImage(ctx context.Context, image string, requestedPage *gql_generated.PageInput, searchedCve *string, fixStatus *gql_generated.FixStatus) (*gql_generated.CVEResultForImage, error) {
 	if r.cveInfo == nil {
 		return &gql_generated.CVEResultForImage{}, zerr.ErrCVESearchDisabled
 	}
 
 	if searchedCve == nil {
-		return getCVEListForImage(ctx, image, r.cveInfo, requestedPage, "", r.log)
+		return getCVEListForImage(ctx, image, r.cveInfo, requestedPage, "", fixStatus, r.log)
 	}
 
-	return getCVEListForImage(ctx, image, r.cveInfo, requestedPage, *searchedCve, r.log)
+	return getCVEListForImage(ctx, image, r.cveInfo, requestedPage, *searchedCve, fixStatus, r.log)
 }
 
 // ImageListForCve is the resolver for the ImageListForCVE field.
@@ -48,6 +49,31 @@ func (r *queryResolver) ImageListWithCVEFixed(ctx context.Context, id string, im
 	return getImageListWithCVEFixed(ctx, id, image, r.cveInfo, filter, requestedPage, r.metaDB, r.log)
 }
 
+// LicenseListForImage is the resolver for the LicenseListForImage field.
+func (r *queryResolver) LicenseListForImage(ctx context.Context, image string) (*gql_generated.LicenseResultForImage, error) {
+	if r.cveInfo == nil {
+		return &gql_generated.LicenseResultForImage{}, zerr.ErrLicenseSearchDisabled
+	}
+
+	return getLicenseListForImage(image, r.cveInfo, r.log)
+}
+
+// ImageListForLicense is the resolver for the ImageListForLicense field.
+func (r *queryResolver) ImageListForLicense(ctx context.Context, licenses []string, filter *gql_generated.Filter, requestedPage *gql_generated.PageInput) (*gql_generated.PaginatedImagesResult, error) {
+	if r.cveInfo == nil {
+		return &gql_generated.PaginatedImagesResult{}, zerr.ErrLicenseSearchDisabled
+	}
+
+	filter = cleanFilter(filter)
+
+	return getImageListForLicense(ctx, licenses, r.cveInfo, filter, requestedPage, r.metaDB, r.log)
+}
+
+// SecretListForImage is the resolver for the SecretListForImage field.
+func (r *queryResolver) SecretListForImage(ctx context.Context, image string) (*gql_generated.SecretResultForImage, error) {
+	return getSecretListForImage(ctx, image, r.metaDB, r.storeController, r.log)
+}
+
 // ImageListForDigest is the resolver for the ImageListForDigest field.
 func (r *queryResolver) ImageListForDigest(ctx context.Context, id string, requestedPage *gql_generated.PageInput) (*gql_generated.PaginatedImagesResult, error) {
 	r.log.Info().Msg("extracting repositories")
@@ -101,28 +127,37 @@ func (r *queryResolver) GlobalSearch(ctx context.Context, query string, filter *
 	query = cleanQuery(query)
 	filter = cleanFilter(filter)
 
-	paginatedReposResult, images, layers, err := globalSearch(ctx, query, r.metaDB, filter, requestedPage, r.cveInfo, r.log)
+	key := r.resultCache.Key(ctx, "GlobalSearch", query, filter, requestedPage)
+
+	return getCachedResult(r.resultCache, key, func() (*gql_generated.GlobalSearchResult, error) {
+		paginatedReposResult, images, layers, err := globalSearch(ctx, query, r.metaDB, filter, requestedPage,
+			r.cveInfo, r.log)
 
-	return &gql_generated.GlobalSearchResult{
-		Page:   paginatedReposResult.Page,
-		Images: images,
-		Repos:  paginatedReposResult.Results,
-		Layers: layers,
-	}, err
+		return &gql_generated.GlobalSearchResult{
+			Page:   paginatedReposResult.Page,
+			Images: images,
+			Repos:  paginatedReposResult.Results,
+			Layers: layers,
+		}, err
+	})
 }
 
 // DependencyListForImage is the resolver for the DependencyListForImage field.
 func (r *queryResolver) DerivedImageList(ctx context.Context, image string, digest *string, requestedPage *gql_generated.PageInput) (*gql_generated.PaginatedImagesResult, error) {
-	derivedList, err := derivedImageList(ctx, image, digest, r.metaDB, requestedPage, r.cveInfo, r.log)
+	key := r.resultCache.Key(ctx, "DerivedImageList", image, digest, requestedPage)
 
-	return derivedList, err
+	return getCachedResult(r.resultCache, key, func() (*gql_generated.PaginatedImagesResult, error) {
+		return derivedImageList(ctx, image, digest, r.metaDB, requestedPage, r.cveInfo, r.log)
+	})
 }
 
 // BaseImageList is the resolver for the BaseImageList field.
 func (r *queryResolver) BaseImageList(ctx context.Context, image string, digest *string, requestedPage *gql_generated.PageInput) (*gql_generated.PaginatedImagesResult, error) {
-	imageList, err := baseImageList(ctx, image, digest, r.metaDB, requestedPage, r.cveInfo, r.log)
+	key := r.resultCache.Key(ctx, "BaseImageList", image, digest, requestedPage)
 
-	return imageList, err
+	return getCachedResult(r.resultCache, key, func() (*gql_generated.PaginatedImagesResult, error) {
+		return baseImageList(ctx, image, digest, r.metaDB, requestedPage, r.cveInfo, r.log)
+	})
 }
 
 // Image is the resolver for the Image field.
@@ -158,6 +193,26 @@ func (r *queryResolver) BookmarkedRepos(ctx context.Context, requestedPage *gql_
 	return getBookmarkedRepos(ctx, r.cveInfo, r.log, requestedPage, r.metaDB)
 }
 
+// BookmarkLists is the resolver for the BookmarkLists field.
+func (r *queryResolver) BookmarkLists(ctx context.Context) ([]*gql_generated.BookmarkList, error) {
+	return getBookmarkLists(ctx, r.metaDB)
+}
+
+// BookmarkListRepos is the resolver for the BookmarkListRepos field.
+func (r *queryResolver) BookmarkListRepos(ctx context.Context, name string, owner *string, requestedPage *gql_generated.PageInput) (*gql_generated.PaginatedReposResult, error) {
+	return getBookmarkListRepos(ctx, r.cveInfo, r.log, name, owner, requestedPage, r.metaDB)
+}
+
+// TopDownloads is the resolver for the TopDownloads field.
+func (r *queryResolver) TopDownloads(ctx context.Context, groupBy *gql_generated.DownloadsGroupBy, repo *string, from *time.Time, to *time.Time, limit *int) ([]*gql_generated.DownloadCount, error) {
+	return getTopDownloads(ctx, groupBy, repo, from, to, limit, r.metaDB)
+}
+
+// DownloadsOverTime is the resolver for the DownloadsOverTime field.
+func (r *queryResolver) DownloadsOverTime(ctx context.Context, repo *string, reference *string, from *time.Time, to *time.Time) (*gql_generated.DownloadsOverTime, error) {
+	return getDownloadsOverTime(ctx, repo, reference, from, to, r.metaDB)
+}
+
 // Query returns gql_generated.QueryResolver implementation.
 func (r *Resolver) Query() gql_generated.QueryResolver { return &queryResolver{r} }
 
@@ -5,6 +5,9 @@ package search_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -35,6 +38,7 @@ import (
 	zcommon "zotregistry.io/zot/pkg/common"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/extensions/search"
 	cveinfo "zotregistry.io/zot/pkg/extensions/search/cve"
 	cvemodel "zotregistry.io/zot/pkg/extensions/search/cve/model"
 	"zotregistry.io/zot/pkg/log"
@@ -720,7 +724,9 @@ func TestRepoListWithNewestImage(t *testing.T) {
 		defer ctlr.Shutdown()
 
 		substring := "{\"Search\":{\"Enable\":true,\"CVE\":{\"UpdateInterval\":3600000000000," +
-			"\"Trivy\":{\"DBRepository\":\"ghcr.io/project-zot/trivy-db\",\"JavaDBRepository\":\"\"}}}"
+			"\"Trivy\":{\"DBRepository\":\"ghcr.io/project-zot/trivy-db\",\"JavaDBRepository\":\"\"}," +
+			"\"Notifications\":null,\"Rescan\":null,\"Repositories\":null}," +
+			"\"ResultCacheTTL\":0,\"PersistedQueries\":null,\"Federation\":null}"
 		found, err := readFileAndSearchString(logPath, substring, 2*time.Minute)
 		So(found, ShouldBeTrue)
 		So(err, ShouldBeNil)
@@ -1186,7 +1192,7 @@ func TestExpandedRepoInfo(t *testing.T) {
 
 		ctlr := api.NewController(conf)
 
-		imageStore := local.NewImageStore(tempDir, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(tempDir, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), nil, nil)
 
 		storeController := storage.StoreController{
@@ -1315,7 +1321,7 @@ func TestExpandedRepoInfo(t *testing.T) {
 		log := log.NewLogger("debug", "")
 		metrics := monitoring.NewMetricsServer(false, log)
 		testStorage := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 		resp, err := resty.R().Get(baseURL + "/v2/")
 		So(resp, ShouldNotBeNil)
@@ -1660,7 +1666,7 @@ func TestExpandedRepoInfo(t *testing.T) {
 		conf.Extensions.Search.CVE = nil
 		ctlr := api.NewController(conf)
 
-		imageStore := local.NewImageStore(conf.Storage.RootDirectory, false, false, 0, 0, false, false,
+		imageStore := local.NewImageStore(conf.Storage.RootDirectory, false, false, 0, 0, false, false, false,
 			log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), nil, nil)
 
 		storeController := storage.StoreController{
@@ -3419,7 +3425,9 @@ func TestGlobalSearch(t *testing.T) {
 
 		// Wait for trivy db to download
 		substring := "{\"Search\":{\"Enable\":true,\"CVE\":{\"UpdateInterval\":3600000000000," +
-			"\"Trivy\":{\"DBRepository\":\"ghcr.io/project-zot/trivy-db\",\"JavaDBRepository\":\"\"}}}"
+			"\"Trivy\":{\"DBRepository\":\"ghcr.io/project-zot/trivy-db\",\"JavaDBRepository\":\"\"}," +
+			"\"Notifications\":null,\"Rescan\":null,\"Repositories\":null}," +
+			"\"ResultCacheTTL\":0,\"PersistedQueries\":null,\"Federation\":null}"
 		found, err := readFileAndSearchString(logPath, substring, 2*time.Minute)
 		So(found, ShouldBeTrue)
 		So(err, ShouldBeNil)
@@ -3811,6 +3819,782 @@ func TestGlobalSearchFiltering(t *testing.T) {
 	})
 }
 
+func TestCVEListForImageFixStatus(t *testing.T) {
+	Convey("CVE list with fix status and upgrade advice", t, func() {
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+
+		defaultVal := true
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctx := context.Background()
+
+		if err := ctlr.Init(ctx); err != nil {
+			panic(err)
+		}
+
+		cves := []cvemodel.CVE{
+			{
+				ID:       "CVE1",
+				Severity: "HIGH",
+				PackageList: []cvemodel.Package{
+					{Name: "pkg1", InstalledVersion: "1.0.0", FixedVersion: "1.0.1"},
+				},
+			},
+			{
+				ID:       "CVE2",
+				Severity: "MEDIUM",
+				PackageList: []cvemodel.Package{
+					{Name: "pkg1", InstalledVersion: "1.0.0", FixedVersion: "1.2.0"},
+					{Name: "pkg2", InstalledVersion: "2.0.0", FixedVersion: cvemodel.FixedVersionNotSpecified},
+				},
+			},
+		}
+
+		ctlr.CveInfo = mocks.CveInfoMock{
+			GetCVEListForImageFn: func(repo, reference, searchedCVE string, fixStatus cvemodel.FixStatus,
+				pageInput cvemodel.PageInput,
+			) ([]cvemodel.CVE, zcommon.PageInfo, error) {
+				filtered := []cvemodel.CVE{}
+
+				for _, cve := range cves {
+					if fixStatus == "" || cve.FixStatus() == fixStatus {
+						filtered = append(filtered, cve)
+					}
+				}
+
+				return filtered, zcommon.PageInfo{TotalCount: len(filtered), ItemCount: len(filtered)}, nil
+			},
+		}
+
+		go func() {
+			if err := ctlr.Run(ctx); !errors.Is(err, http.ErrServerClosed) {
+				panic(err)
+			}
+		}()
+
+		defer ctlr.Shutdown()
+
+		WaitTillServerReady(baseURL)
+
+		type cveListResp struct {
+			Data struct {
+				CVEListForImage struct {
+					CVEList []struct {
+						ID          string `json:"Id"`
+						HasFix      bool   `json:"HasFix"`
+						FixStatus   string `json:"FixStatus"`
+						PackageList []struct {
+							Name   string `json:"Name"`
+							HasFix bool   `json:"HasFix"`
+						} `json:"PackageList"`
+					} `json:"CVEList"`
+					UpgradeAdvice []struct {
+						Name               string   `json:"Name"`
+						RecommendedVersion string   `json:"RecommendedVersion"`
+						FixedVersions      []string `json:"FixedVersions"`
+						CVEIds             []string `json:"CVEIds"`
+					} `json:"UpgradeAdvice"`
+				} `json:"CVEListForImage"`
+			} `json:"data"`
+		}
+
+		query := `{
+			CVEListForImage(image:"repo:tag", requestedPage:{limit:1, sortBy:SEVERITY}) {
+				CVEList { Id HasFix FixStatus PackageList { Name HasFix } }
+				UpgradeAdvice { Name RecommendedVersion FixedVersions CVEIds }
+			}
+		}`
+
+		resp, err := resty.R().Get(baseURL + graphqlQueryPrefix + "?query=" + url.QueryEscape(query))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, 200)
+
+		responseStruct := &cveListResp{}
+		err = json.Unmarshal(resp.Body(), responseStruct)
+		So(err, ShouldBeNil)
+
+		result := responseStruct.Data.CVEListForImage
+		So(result.CVEList, ShouldHaveLength, 2)
+		So(result.CVEList[0].HasFix, ShouldBeTrue)
+		So(result.CVEList[0].FixStatus, ShouldEqual, "AVAILABLE")
+		So(result.CVEList[1].HasFix, ShouldBeFalse)
+		So(result.CVEList[1].FixStatus, ShouldEqual, "PARTIAL")
+		So(result.CVEList[1].PackageList[0].HasFix, ShouldBeTrue)
+		So(result.CVEList[1].PackageList[1].HasFix, ShouldBeFalse)
+
+		So(result.UpgradeAdvice, ShouldHaveLength, 1)
+		So(result.UpgradeAdvice[0].Name, ShouldEqual, "pkg1")
+		So(result.UpgradeAdvice[0].RecommendedVersion, ShouldEqual, "1.2.0")
+		So(result.UpgradeAdvice[0].FixedVersions, ShouldResemble, []string{"1.0.1", "1.2.0"})
+		So(result.UpgradeAdvice[0].CVEIds, ShouldResemble, []string{"CVE1", "CVE2"})
+
+		query = `{
+			CVEListForImage(image:"repo:tag", fixStatus:PARTIAL) {
+				CVEList { Id }
+				UpgradeAdvice { Name RecommendedVersion }
+			}
+		}`
+
+		resp, err = resty.R().Get(baseURL + graphqlQueryPrefix + "?query=" + url.QueryEscape(query))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, 200)
+
+		responseStruct = &cveListResp{}
+		err = json.Unmarshal(resp.Body(), responseStruct)
+		So(err, ShouldBeNil)
+
+		result = responseStruct.Data.CVEListForImage
+		So(result.CVEList, ShouldHaveLength, 1)
+		So(result.CVEList[0].ID, ShouldEqual, "CVE2")
+		So(result.UpgradeAdvice, ShouldHaveLength, 1)
+		So(result.UpgradeAdvice[0].RecommendedVersion, ShouldEqual, "1.2.0")
+	})
+}
+
+func TestGlobalSearchResultCache(t *testing.T) {
+	Convey("Cached global search results are invalidated by pushes", t, func() {
+		dir := t.TempDir()
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = dir
+
+		defaultVal := true
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{
+				BaseConfig:     extconf.BaseConfig{Enable: &defaultVal},
+				ResultCacheTTL: time.Hour,
+			},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		getTags := func() []string {
+			query := `{
+				GlobalSearch(query:"repo:") {
+					Images {
+						Tag
+					}
+				}
+			}`
+
+			resp, err := resty.R().Get(baseURL + graphqlQueryPrefix + "?query=" + url.QueryEscape(query))
+			So(resp, ShouldNotBeNil)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, 200)
+
+			responseStruct := &zcommon.GlobalSearchResultResp{}
+
+			err = json.Unmarshal(resp.Body(), responseStruct)
+			So(err, ShouldBeNil)
+
+			tags := []string{}
+			for _, image := range responseStruct.Images {
+				tags = append(tags, image.Tag)
+			}
+
+			return tags
+		}
+
+		config, layers, manifest, err := GetRandomImageComponents(100)
+		So(err, ShouldBeNil)
+
+		err = UploadImage(Image{Config: config, Layers: layers, Manifest: manifest}, baseURL, "repo", "1.0")
+		So(err, ShouldBeNil)
+
+		So(getTags(), ShouldResemble, []string{"1.0"})
+		So(getTags(), ShouldResemble, []string{"1.0"})
+
+		config, layers, manifest, err = GetRandomImageComponents(100)
+		So(err, ShouldBeNil)
+
+		err = UploadImage(Image{Config: config, Layers: layers, Manifest: manifest}, baseURL, "repo", "2.0")
+		So(err, ShouldBeNil)
+
+		So(getTags(), ShouldContain, "2.0")
+		So(len(getTags()), ShouldEqual, 2)
+	})
+}
+
+func TestPersistedQueriesAllowList(t *testing.T) {
+	Convey("Only the queries of the allow list are accepted", t, func() {
+		dir := t.TempDir()
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = dir
+
+		allowedQuery := `{ GlobalSearch(query:"repo") { Repos { Name } } }`
+		allowedQueryHash := sha256.Sum256([]byte(allowedQuery))
+		allowedQueryHashStr := hex.EncodeToString(allowedQueryHash[:])
+
+		allowListPath := path.Join(t.TempDir(), "allowlist.json")
+
+		allowListBlob, err := json.Marshal(map[string]string{allowedQueryHashStr: allowedQuery})
+		So(err, ShouldBeNil)
+
+		err = os.WriteFile(allowListPath, allowListBlob, 0o600)
+		So(err, ShouldBeNil)
+
+		defaultVal := true
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{
+				BaseConfig: extconf.BaseConfig{Enable: &defaultVal},
+				PersistedQueries: &extconf.PersistedQueriesConfig{
+					AllowList:     allowListPath,
+					AllowListOnly: true,
+				},
+				Federation: &extconf.FederationConfig{},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		err = UploadImage(CreateRandomImage(), baseURL, "repo", "1.0")
+		So(err, ShouldBeNil)
+
+		// the client only sends the hash of the query
+		extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + allowedQueryHashStr + `"}}`
+
+		resp, err := resty.R().Get(baseURL + graphqlQueryPrefix + "?extensions=" + url.QueryEscape(extensions))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, 200)
+
+		responseStruct := &zcommon.GlobalSearchResultResp{}
+
+		err = json.Unmarshal(resp.Body(), responseStruct)
+		So(err, ShouldBeNil)
+		So(responseStruct.Errors, ShouldBeEmpty)
+		So(responseStruct.Repos, ShouldHaveLength, 1)
+		So(responseStruct.Repos[0].Name, ShouldEqual, "repo")
+
+		// the full query is also accepted
+		resp, err = resty.R().Get(baseURL + graphqlQueryPrefix + "?query=" + url.QueryEscape(allowedQuery))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, 200)
+		So(string(resp.Body()), ShouldNotContainSubstring, zerr.ErrQueryNotAllowed.Error())
+
+		otherQuery := `{ GlobalSearch(query:"repo") { Images { Tag } } }`
+
+		resp, err = resty.R().Get(baseURL + graphqlQueryPrefix + "?query=" + url.QueryEscape(otherQuery))
+		So(err, ShouldBeNil)
+		So(string(resp.Body()), ShouldContainSubstring, zerr.ErrQueryNotAllowed.Error())
+
+		// the federated search runs the same queries
+		federatedURL := baseURL + constants.FullSearchFederated
+
+		resp, err = resty.R().SetBody(search.FederatedRequest{Query: otherQuery}).Post(federatedURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		So(string(resp.Body()), ShouldContainSubstring, zerr.ErrQueryNotAllowed.Error())
+
+		resp, err = resty.R().Get(federatedURL + "?extensions=" + url.QueryEscape(extensions))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(string(resp.Body()), ShouldContainSubstring, `"Name":"repo"`)
+	})
+}
+
+func TestSearchRESTEndpoints(t *testing.T) {
+	Convey("REST wrappers over the search queries", t, func() {
+		dir := t.TempDir()
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = dir
+		defaultVal := true
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		image := CreateRandomImage()
+
+		err := UploadImage(image, baseURL, "repo1", "1.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(CreateRandomImage(), baseURL, "repo1", "2.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(CreateRandomImage(), baseURL, "repo2", "1.0")
+		So(err, ShouldBeNil)
+
+		restURL := baseURL + constants.FullSearchREST
+
+		Convey("List repos", func() {
+			resp, err := resty.R().Get(restURL + "/repos")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(resp.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			result := zcommon.PaginatedReposResult{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+			So(result.Results, ShouldHaveLength, 2)
+			So(result.Page.TotalCount, ShouldEqual, 2)
+
+			resp, err = resty.R().Get(restURL + "/repos?limit=1&offset=1&sortBy=alphabetic_asc")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			result = zcommon.PaginatedReposResult{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+			So(result.Results, ShouldHaveLength, 1)
+			So(result.Results[0].Name, ShouldEqual, "repo2")
+
+			resp, err = resty.R().Get(restURL + "/repos?limit=bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().Get(restURL + "/repos?offset=-1")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().Get(restURL + "/repos?sortBy=bad")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("List images", func() {
+			resp, err := resty.R().Get(restURL + "/images?repo=repo1&sortBy=ALPHABETIC_ASC")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			result := zcommon.PaginatedImagesResult{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+			So(result.Results, ShouldHaveLength, 2)
+			So(result.Results[0].Tag, ShouldEqual, "1.0")
+			So(result.Results[0].Size, ShouldNotBeEmpty)
+			So(result.Results[1].Tag, ShouldEqual, "2.0")
+
+			resp, err = resty.R().Get(restURL + "/images")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().Get(restURL + "/images?repo=missing")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Get image", func() {
+			resp, err := resty.R().Get(restURL + "/image?repo=repo1&tag=1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			result := zcommon.ImageSummary{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+			So(result.RepoName, ShouldEqual, "repo1")
+			So(result.Tag, ShouldEqual, "1.0")
+			So(result.Digest, ShouldEqual, image.DigestStr())
+
+			resp, err = resty.R().Get(restURL + "/image?repo=repo1")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().Get(restURL + "/image?repo=repo1&tag=missing")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = resty.R().Get(restURL + "/image?repo=missing&tag=1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
+
+func TestFederatedSearch(t *testing.T) {
+	Convey("Federated search across peer registries", t, func() {
+		defaultVal := true
+
+		peerPort := GetFreePort()
+		peerURL := GetBaseURL(peerPort)
+		peerConf := config.New()
+		peerConf.HTTP.Port = peerPort
+		peerConf.Storage.RootDirectory = t.TempDir()
+		peerConf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		peerCtlrManager := NewControllerManager(api.NewController(peerConf))
+		peerCtlrManager.StartAndWait(peerPort)
+		defer peerCtlrManager.StopServer()
+
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{
+				BaseConfig: extconf.BaseConfig{Enable: &defaultVal},
+				Federation: &extconf.FederationConfig{
+					Peers: []extconf.FederationPeerConfig{
+						{Name: "eu", URL: peerURL},
+						{Name: "unreachable", URL: GetBaseURL(GetFreePort())},
+					},
+				},
+			},
+		}
+
+		ctlrManager := NewControllerManager(api.NewController(conf))
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		err := UploadImage(CreateRandomImage(), baseURL, "repo1", "1.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(CreateRandomImage(), peerURL, "repo2", "1.0")
+		So(err, ShouldBeNil)
+
+		err = UploadImage(CreateRandomImage(), peerURL, "repo3", "1.0")
+		So(err, ShouldBeNil)
+
+		federatedURL := baseURL + constants.FullSearchFederated
+
+		type federatedRepoList struct {
+			Data struct {
+				RepoListWithNewestImage struct {
+					Page    zcommon.PageInfo
+					Results []struct {
+						Name     string
+						Registry string
+					}
+				}
+			}
+			Errors []search.FederatedError
+		}
+
+		Convey("Paginated results are merged", func() {
+			resp, err := resty.R().SetBody(search.FederatedRequest{
+				Query: `{ RepoListWithNewestImage(requestedPage: {sortBy: ALPHABETIC_ASC}) {
+					Page { TotalCount ItemCount } Results { Name } } }`,
+			}).Post(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			result := federatedRepoList{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+
+			repos := result.Data.RepoListWithNewestImage
+			So(repos.Page.TotalCount, ShouldEqual, 3)
+			So(repos.Page.ItemCount, ShouldEqual, 3)
+			So(repos.Results, ShouldHaveLength, 3)
+			So(repos.Results[0].Name, ShouldEqual, "repo1")
+			So(repos.Results[0].Registry, ShouldEqual, extconf.FederationLocalSource)
+			So(repos.Results[1].Name, ShouldEqual, "repo2")
+			So(repos.Results[1].Registry, ShouldEqual, "eu")
+			So(repos.Results[2].Name, ShouldEqual, "repo3")
+			So(repos.Results[2].Registry, ShouldEqual, "eu")
+
+			So(result.Errors, ShouldHaveLength, 1)
+			So(result.Errors[0].Registry, ShouldEqual, "unreachable")
+
+			resp, err = resty.R().SetQueryParams(map[string]string{
+				"query":     `query($repo: String!) { ImageList(repo: $repo) { Page { TotalCount } Results { Tag } } }`,
+				"variables": `{"repo": "repo2"}`,
+			}).Get(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var imageList struct {
+				Data struct {
+					ImageList struct {
+						Page    zcommon.PageInfo
+						Results []struct {
+							Tag      string
+							Registry string
+						}
+					}
+				}
+			}
+
+			err = json.Unmarshal(resp.Body(), &imageList)
+			So(err, ShouldBeNil)
+			So(imageList.Data.ImageList.Page.TotalCount, ShouldEqual, 1)
+			So(imageList.Data.ImageList.Results, ShouldHaveLength, 1)
+			So(imageList.Data.ImageList.Results[0].Registry, ShouldEqual, "eu")
+		})
+
+		Convey("Single results are taken from the first registry returning them", func() {
+			resp, err := resty.R().SetBody(search.FederatedRequest{
+				Query: `{ Image(image: "repo3:1.0") { RepoName Tag } }`,
+			}).Post(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var image struct {
+				Data struct {
+					Image struct {
+						RepoName string
+						Registry string
+					}
+				}
+				Errors []search.FederatedError
+			}
+
+			err = json.Unmarshal(resp.Body(), &image)
+			So(err, ShouldBeNil)
+			So(image.Data.Image.RepoName, ShouldEqual, "repo3")
+			So(image.Data.Image.Registry, ShouldEqual, "eu")
+
+			registries := []string{}
+			for _, fedErr := range image.Errors {
+				registries = append(registries, fedErr.Registry)
+			}
+
+			// the image is not found locally
+			So(registries, ShouldContain, extconf.FederationLocalSource)
+			So(registries, ShouldContain, "unreachable")
+		})
+
+		Convey("Invalid queries", func() {
+			resp, err := resty.R().SetBody(search.FederatedRequest{Query: `{ UnknownQuery }`}).Post(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().Get(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = resty.R().SetBody("not json").Post(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
+func TestFederatedSearchAccessControl(t *testing.T) {
+	Convey("Federated search results are restricted to the repos the user can read", t, func() {
+		defaultVal := true
+
+		adminUser, adminPassword := "alice", "alicePassword"
+		serviceUser, servicePassword := "service", "servicePassword"
+		restrictedUser, restrictedPassword := "bob", "bobPassword"
+
+		htpasswdPath := MakeHtpasswdFileFromString(GetCredString(adminUser, adminPassword) + "\n" +
+			GetCredString(serviceUser, servicePassword) + "\n" + GetCredString(restrictedUser, restrictedPassword))
+		defer os.Remove(htpasswdPath)
+
+		// the service account reads all the repos, the restricted user only the public ones
+		accessControl := &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{{Users: []string{serviceUser}, Actions: []string{"read"}}},
+				},
+				"public/**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{Users: []string{serviceUser, restrictedUser}, Actions: []string{"read"}},
+					},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update", "delete"},
+			},
+		}
+
+		peerPort := GetFreePort()
+		peerURL := GetBaseURL(peerPort)
+		peerConf := config.New()
+		peerConf.HTTP.Port = peerPort
+		peerConf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		peerConf.HTTP.AccessControl = accessControl
+		peerConf.Storage.RootDirectory = t.TempDir()
+		peerConf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{BaseConfig: extconf.BaseConfig{Enable: &defaultVal}},
+		}
+
+		peerCtlrManager := NewControllerManager(api.NewController(peerConf))
+		peerCtlrManager.StartAndWait(peerPort)
+		defer peerCtlrManager.StopServer()
+
+		serviceCredentials := base64.StdEncoding.EncodeToString([]byte(serviceUser + ":" + servicePassword))
+
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = accessControl
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{
+				BaseConfig: extconf.BaseConfig{Enable: &defaultVal},
+				Federation: &extconf.FederationConfig{
+					Peers: []extconf.FederationPeerConfig{
+						{
+							Name:    "service",
+							URL:     peerURL,
+							Headers: map[string]string{"Authorization": "Basic " + serviceCredentials},
+						},
+						{
+							Name:               "forwarded",
+							URL:                peerURL,
+							Headers:            map[string]string{"Authorization": "Basic " + serviceCredentials},
+							ForwardCredentials: true,
+						},
+					},
+				},
+			},
+		}
+
+		ctlrManager := NewControllerManager(api.NewController(conf))
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		err := UploadImageWithBasicAuth(CreateRandomImage(), peerURL, "public/repo", "1.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		err = UploadImageWithBasicAuth(CreateRandomImage(), peerURL, "private/repo", "1.0", adminUser, adminPassword)
+		So(err, ShouldBeNil)
+
+		federatedURL := baseURL + constants.FullSearchFederated
+
+		getRepos := func(user, password string) map[string][]string {
+			resp, err := resty.R().SetBasicAuth(user, password).SetBody(search.FederatedRequest{
+				Query: `{ RepoListWithNewestImage { Results { Name NewestImage { Tag } } } }`,
+			}).Post(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var result struct {
+				Data struct {
+					RepoListWithNewestImage struct {
+						Results []struct {
+							Name     string
+							Registry string
+						}
+					}
+				}
+			}
+
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+
+			repos := map[string][]string{}
+			for _, repo := range result.Data.RepoListWithNewestImage.Results {
+				repos[repo.Registry] = append(repos[repo.Registry], repo.Name)
+			}
+
+			return repos
+		}
+
+		// the admins get all the results of the peers
+		repos := getRepos(adminUser, adminPassword)
+		So(repos["service"], ShouldHaveLength, 2)
+		So(repos["forwarded"], ShouldHaveLength, 2)
+
+		// the restricted user doesn't get the repos it can't read, through the service account or its own credentials
+		repos = getRepos(restrictedUser, restrictedPassword)
+		So(repos["service"], ShouldResemble, []string{"public/repo"})
+		So(repos["forwarded"], ShouldResemble, []string{"public/repo"})
+
+		// nor their count in the pages of the results
+		getPage := func(requestedPage string) (int, int) {
+			resp, err := resty.R().SetBasicAuth(restrictedUser, restrictedPassword).SetBody(search.FederatedRequest{
+				Query: `{ RepoListWithNewestImage` + requestedPage + ` { Page { TotalCount ItemCount } Results { Name } } }`,
+			}).Post(federatedURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var result struct {
+				Data struct {
+					RepoListWithNewestImage struct {
+						Page struct {
+							TotalCount int
+							ItemCount  int
+						}
+					}
+				}
+			}
+
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+
+			return result.Data.RepoListWithNewestImage.Page.TotalCount,
+				result.Data.RepoListWithNewestImage.Page.ItemCount
+		}
+
+		totalCount, itemCount := getPage("")
+		So(totalCount, ShouldEqual, 2)
+		So(itemCount, ShouldEqual, 2)
+
+		// the first page of the service account only has the private repo
+		totalCount, itemCount = getPage(`(requestedPage: {limit: 1, offset: 0, sortBy: ALPHABETIC_ASC})`)
+		So(totalCount, ShouldEqual, 1)
+		So(itemCount, ShouldEqual, 1)
+
+		// the queries on a repo the user can't read return nothing from the peers queried with the service account
+		resp, err := resty.R().SetBasicAuth(restrictedUser, restrictedPassword).SetBody(search.FederatedRequest{
+			Query: `{ ImageList(repo: "private/repo") { Results { Tag } } Image(image: "private/repo:1.0") { Tag } }`,
+		}).Post(federatedURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		So(string(resp.Body()), ShouldNotContainSubstring, `"Registry":"service"`)
+
+		// the images whose repo isn't selected can't be checked
+		resp, err = resty.R().SetBasicAuth(restrictedUser, restrictedPassword).SetBody(search.FederatedRequest{
+			Query: `{ GlobalSearch(query: "repo") { Images { Tag } Repos { Name } } }`,
+		}).Post(federatedURL)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(string(resp.Body()), ShouldNotContainSubstring, "private/repo")
+
+		var globalSearch struct {
+			Data struct {
+				GlobalSearch struct {
+					Images []struct {
+						Tag      string
+						Registry string
+					}
+					Repos []struct {
+						Name     string
+						Registry string
+					}
+				}
+			}
+		}
+
+		err = json.Unmarshal(resp.Body(), &globalSearch)
+		So(err, ShouldBeNil)
+
+		for _, image := range globalSearch.Data.GlobalSearch.Images {
+			So(image.Registry, ShouldNotEqual, "service")
+		}
+
+		So(globalSearch.Data.GlobalSearch.Repos, ShouldNotBeEmpty)
+	})
+}
+
 func TestGlobalSearchWithInvalidInput(t *testing.T) {
 	Convey("Global search with invalid input", t, func() {
 		dir := t.TempDir()
@@ -5406,7 +6190,7 @@ func TestMetaDBWhenDeletingImages(t *testing.T) {
 			log := log.NewLogger("debug", "")
 			metrics := monitoring.NewMetricsServer(false, log)
 			storage := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-				storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+				storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 			indexBlob, err := storage.GetIndexContent(repo)
 			So(err, ShouldBeNil)
@@ -5483,7 +6267,7 @@ func TestMetaDBWhenDeletingImages(t *testing.T) {
 			log := log.NewLogger("debug", "")
 			metrics := monitoring.NewMetricsServer(false, log)
 			storage := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-				storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil)
+				storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
 
 			indexBlob, err := storage.GetIndexContent(repo)
 			So(err, ShouldBeNil)
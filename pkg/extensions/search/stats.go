@@ -0,0 +1,860 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	zerr "zotregistry.io/zot/errors"
+	zcommon "zotregistry.io/zot/pkg/common"
+	"zotregistry.io/zot/pkg/extensions/search/gql_generated"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta/common"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+// how the downloads are counted by the top downloads queries.
+const (
+	DownloadsByRepo   = "repo"
+	DownloadsByTag    = "tag"
+	DownloadsByDigest = "digest"
+)
+
+// the intervals of the points of the downloads over time queries.
+const (
+	DownloadsIntervalDay  = "day"
+	DownloadsIntervalHour = "hour"
+)
+
+const (
+	// DefaultDownloadStatsPeriod is the period of the download statistics queries which don't specify a start.
+	DefaultDownloadStatsPeriod = 30 * 24 * time.Hour
+	// DefaultDownloadStatsRetention is how long the daily download statistics are kept if not configured.
+	DefaultDownloadStatsRetention = 90 * 24 * time.Hour
+	// DefaultHourlyDownloadStatsRetention is how long the hourly download statistics are kept if not configured.
+	DefaultHourlyDownloadStatsRetention = 7 * 24 * time.Hour
+	DefaultTopDownloadsLimit            = 10
+
+	downloadStatsRetentionInterval = 24 * time.Hour
+)
+
+// DownloadCount is the number of downloads of a repo, tag or digest.
+type DownloadCount struct {
+	Repo          string `json:"repo"`
+	Reference     string `json:"reference,omitempty"` // the tag or digest, empty when grouped by repo
+	Count         int    `json:"count"`
+	UniqueClients int    `json:"uniqueClients"`
+}
+
+// DownloadsPoint is the number of downloads during a day.
+type DownloadsPoint struct {
+	Day           string `json:"day"`
+	Count         int    `json:"count"`
+	UniqueClients int    `json:"uniqueClients"`
+}
+
+// DownloadsOverTime are the downloads during a period, day by day including the days without downloads.
+type DownloadsOverTime struct {
+	Total         int              `json:"total"`
+	UniqueClients int              `json:"uniqueClients"`
+	Points        []DownloadsPoint `json:"points"`
+}
+
+// HourlyDownloadsPoint is the number of downloads during an hour.
+type HourlyDownloadsPoint struct {
+	Hour  string `json:"hour"` // the start of the hour, RFC 3339 in UTC
+	Count int    `json:"count"`
+}
+
+// HourlyDownloadsOverTime are the downloads during a period, hour by hour including the hours without downloads,
+// except the hours of the days rolled up which only have daily statistics.
+type HourlyDownloadsOverTime struct {
+	Total  int                    `json:"total"`
+	Points []HourlyDownloadsPoint `json:"points"`
+}
+
+// LastPull is the last pull of a tag or digest of a repo, kept as long as the repo.
+type LastPull struct {
+	Reference     string    `json:"reference"`
+	DownloadCount int       `json:"downloadCount"`
+	LastPulled    time.Time `json:"lastPulled"`
+}
+
+type clientSet map[string]struct{}
+
+func (set clientSet) add(clients []string) {
+	for _, client := range clients {
+		set[client] = struct{}{}
+	}
+}
+
+// GetTopDownloads returns the most downloaded repos, tags or digests, sorted by decreasing number of downloads.
+// Every download is counted by digest, only the downloads made by tag are counted by tag.
+func GetTopDownloads(stats []mTypes.DailyDownloads, groupBy string, limit int) []DownloadCount {
+	type countKey struct {
+		repo      string
+		reference string
+	}
+
+	counts := map[countKey]int{}
+	clients := map[countKey]clientSet{}
+
+	add := func(key countKey, downloads mTypes.ReferenceDownloads) {
+		counts[key] += downloads.Count
+
+		if clients[key] == nil {
+			clients[key] = clientSet{}
+		}
+
+		clients[key].add(downloads.Clients)
+	}
+
+	for _, dailyStats := range stats {
+		references := dailyStats.Digests
+		if groupBy == DownloadsByTag {
+			references = dailyStats.Tags
+		}
+
+		for reference, downloads := range references {
+			key := countKey{repo: dailyStats.Repo, reference: reference}
+			if groupBy == DownloadsByRepo {
+				key.reference = ""
+			}
+
+			add(key, downloads)
+		}
+	}
+
+	topDownloads := make([]DownloadCount, 0, len(counts))
+
+	for key, count := range counts {
+		topDownloads = append(topDownloads, DownloadCount{
+			Repo:          key.repo,
+			Reference:     key.reference,
+			Count:         count,
+			UniqueClients: len(clients[key]),
+		})
+	}
+
+	sort.Slice(topDownloads, func(i, j int) bool {
+		if topDownloads[i].Count != topDownloads[j].Count {
+			return topDownloads[i].Count > topDownloads[j].Count
+		}
+
+		if topDownloads[i].Repo != topDownloads[j].Repo {
+			return topDownloads[i].Repo < topDownloads[j].Repo
+		}
+
+		return topDownloads[i].Reference < topDownloads[j].Reference
+	})
+
+	if limit > 0 && len(topDownloads) > limit {
+		topDownloads = topDownloads[:limit]
+	}
+
+	return topDownloads
+}
+
+// GetDownloadsOverTime returns the downloads of every day between 'from' and 'to', of a tag or digest
+// if 'reference' is not empty.
+func GetDownloadsOverTime(stats []mTypes.DailyDownloads, reference string, from, to time.Time) DownloadsOverTime {
+	dayCounts := map[string]int{}
+	dayClients := map[string]clientSet{}
+	allClients := clientSet{}
+
+	for _, dailyStats := range stats {
+		if dayClients[dailyStats.Day] == nil {
+			dayClients[dailyStats.Day] = clientSet{}
+		}
+
+		for digest, downloads := range dailyStats.Digests {
+			if reference != "" && digest != reference {
+				continue
+			}
+
+			dayCounts[dailyStats.Day] += downloads.Count
+			dayClients[dailyStats.Day].add(downloads.Clients)
+			allClients.add(downloads.Clients)
+		}
+
+		if reference == "" || common.ReferenceIsDigest(reference) {
+			continue
+		}
+
+		if downloads, ok := dailyStats.Tags[reference]; ok {
+			dayCounts[dailyStats.Day] += downloads.Count
+			dayClients[dailyStats.Day].add(downloads.Clients)
+			allClients.add(downloads.Clients)
+		}
+	}
+
+	downloadsOverTime := DownloadsOverTime{
+		UniqueClients: len(allClients),
+		Points:        []DownloadsPoint{},
+	}
+
+	lastDay := to.UTC().Format(mTypes.DownloadStatsDayFormat)
+
+	for day := from.UTC(); day.Format(mTypes.DownloadStatsDayFormat) <= lastDay; day = day.AddDate(0, 0, 1) {
+		dayString := day.Format(mTypes.DownloadStatsDayFormat)
+
+		downloadsOverTime.Total += dayCounts[dayString]
+		downloadsOverTime.Points = append(downloadsOverTime.Points, DownloadsPoint{
+			Day:           dayString,
+			Count:         dayCounts[dayString],
+			UniqueClients: len(dayClients[dayString]),
+		})
+	}
+
+	return downloadsOverTime
+}
+
+// GetHourlyDownloadsOverTime returns the downloads of every hour between 'from' and 'to'.
+func GetHourlyDownloadsOverTime(stats []mTypes.DailyDownloads, from, to time.Time) HourlyDownloadsOverTime {
+	dayHours := map[string][]int{}
+	rolledUpDays := map[string]bool{}
+
+	for _, dailyStats := range stats {
+		if len(dailyStats.Hours) != mTypes.DownloadStatsHours {
+			rolledUpDays[dailyStats.Day] = true
+
+			continue
+		}
+
+		if dayHours[dailyStats.Day] == nil {
+			dayHours[dailyStats.Day] = make([]int, mTypes.DownloadStatsHours)
+		}
+
+		for hour, count := range dailyStats.Hours {
+			dayHours[dailyStats.Day][hour] += count
+		}
+	}
+
+	downloadsOverTime := HourlyDownloadsOverTime{
+		Points: []HourlyDownloadsPoint{},
+	}
+
+	for hour := from.UTC().Truncate(time.Hour); !hour.After(to); hour = hour.Add(time.Hour) {
+		day := hour.Format(mTypes.DownloadStatsDayFormat)
+
+		if rolledUpDays[day] {
+			continue
+		}
+
+		count := 0
+		if hours := dayHours[day]; hours != nil {
+			count = hours[hour.Hour()]
+		}
+
+		downloadsOverTime.Total += count
+		downloadsOverTime.Points = append(downloadsOverTime.Points, HourlyDownloadsPoint{
+			Hour:  hour.Format(time.RFC3339),
+			Count: count,
+		})
+	}
+
+	return downloadsOverTime
+}
+
+// GetLastPulls returns the last pulls of the tags and digests of a repo, the tags first, each sorted by name.
+func GetLastPulls(repoMeta mTypes.RepoMetadata) []LastPull {
+	lastPulls := []LastPull{}
+
+	for _, statistics := range []map[string]mTypes.DescriptorStatistics{repoMeta.TagStatistics, repoMeta.Statistics} {
+		references := make([]string, 0, len(statistics))
+
+		for reference, referenceStatistics := range statistics {
+			if !referenceStatistics.LastPullTimestamp.IsZero() {
+				references = append(references, reference)
+			}
+		}
+
+		sort.Strings(references)
+
+		for _, reference := range references {
+			lastPulls = append(lastPulls, LastPull{
+				Reference:     reference,
+				DownloadCount: statistics[reference].DownloadCount,
+				LastPulled:    statistics[reference].LastPullTimestamp,
+			})
+		}
+	}
+
+	return lastPulls
+}
+
+// GetDownloadStatsPeriod returns the period of a download statistics query, the last
+// DefaultDownloadStatsPeriod by default.
+func GetDownloadStatsPeriod(from, to *time.Time) (time.Time, time.Time) {
+	periodEnd := time.Now()
+	if to != nil {
+		periodEnd = *to
+	}
+
+	periodStart := periodEnd.Add(-DefaultDownloadStatsPeriod)
+	if from != nil {
+		periodStart = *from
+	}
+
+	return periodStart, periodEnd
+}
+
+func getTopDownloads(ctx context.Context, groupBy *gql_generated.DownloadsGroupBy, repo *string,
+	from, to *time.Time, limit *int, metaDB mTypes.MetaDB,
+) ([]*gql_generated.DownloadCount, error) {
+	countBy := DownloadsByRepo
+
+	if groupBy != nil {
+		switch *groupBy {
+		case gql_generated.DownloadsGroupByTag:
+			countBy = DownloadsByTag
+		case gql_generated.DownloadsGroupByDigest:
+			countBy = DownloadsByDigest
+		}
+	}
+
+	maxResults := DefaultTopDownloadsLimit
+	if limit != nil {
+		if *limit <= 0 {
+			return []*gql_generated.DownloadCount{}, gqlerror.Errorf("the limit must be positive")
+		}
+
+		maxResults = *limit
+	}
+
+	periodStart, periodEnd := GetDownloadStatsPeriod(from, to)
+
+	stats, err := metaDB.GetDownloadStats(ctx, safeDereferencing(repo, ""), periodStart, periodEnd)
+	if err != nil {
+		return []*gql_generated.DownloadCount{}, err
+	}
+
+	topDownloads := []*gql_generated.DownloadCount{}
+
+	for _, downloads := range GetTopDownloads(stats, countBy, maxResults) {
+		downloads := downloads
+
+		topDownloads = append(topDownloads, &gql_generated.DownloadCount{
+			Repo:          &downloads.Repo,
+			Reference:     &downloads.Reference,
+			Count:         &downloads.Count,
+			UniqueClients: &downloads.UniqueClients,
+		})
+	}
+
+	return topDownloads, nil
+}
+
+func getDownloadsOverTime(ctx context.Context, repo, reference *string, from, to *time.Time, metaDB mTypes.MetaDB,
+) (*gql_generated.DownloadsOverTime, error) {
+	if safeDereferencing(reference, "") != "" && safeDereferencing(repo, "") == "" {
+		return &gql_generated.DownloadsOverTime{}, gqlerror.Errorf("the repo of the reference is required")
+	}
+
+	periodStart, periodEnd := GetDownloadStatsPeriod(from, to)
+
+	stats, err := metaDB.GetDownloadStats(ctx, safeDereferencing(repo, ""), periodStart, periodEnd)
+	if err != nil {
+		return &gql_generated.DownloadsOverTime{}, err
+	}
+
+	downloadsOverTime := GetDownloadsOverTime(stats, safeDereferencing(reference, ""), periodStart, periodEnd)
+
+	result := &gql_generated.DownloadsOverTime{
+		Total:         &downloadsOverTime.Total,
+		UniqueClients: &downloadsOverTime.UniqueClients,
+		Points:        []*gql_generated.DownloadsPoint{},
+	}
+
+	for _, point := range downloadsOverTime.Points {
+		point := point
+
+		result.Points = append(result.Points, &gql_generated.DownloadsPoint{
+			Day:           &point.Day,
+			Count:         &point.Count,
+			UniqueClients: &point.UniqueClients,
+		})
+	}
+
+	return result, nil
+}
+
+// DownloadStatsHandler serves the download statistics, only counting the downloads of the repos the user can read.
+type DownloadStatsHandler struct {
+	metaDB mTypes.MetaDB
+	log    log.Logger
+}
+
+func NewDownloadStatsHandler(metaDB mTypes.MetaDB, log log.Logger) *DownloadStatsHandler {
+	return &DownloadStatsHandler{
+		metaDB: metaDB,
+		log:    log,
+	}
+}
+
+// TopDownloads godoc
+// @Summary Get the most downloaded images
+// @Description Get the most downloaded repos, tags or digests during a period
+// @Router  /v2/_zot/ext/search/stats/top [get]
+// @Produce json
+// @Param   groupBy   query    string     false "repo (default), tag or digest"
+// @Param   repo      query    string     false "only count the downloads of this repository"
+// @Param   from      query    string     false "start of the period, RFC 3339 or YYYY-MM-DD, 30 days ago by default"
+// @Param   to        query    string     false "end of the period, RFC 3339 or YYYY-MM-DD, now by default"
+// @Param   limit     query    int        false "maximum number of results, 10 by default"
+// @Success 200 {array}    search.DownloadCount
+// @Failure 400 {string}   string   "bad request"
+// @Failure 500 {string}   string   "internal server error".
+func (dsh *DownloadStatsHandler) TopDownloads(response http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	groupBy := query.Get("groupBy")
+	if groupBy == "" {
+		groupBy = DownloadsByRepo
+	}
+
+	if groupBy != DownloadsByRepo && groupBy != DownloadsByTag && groupBy != DownloadsByDigest {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	limit := DefaultTopDownloadsLimit
+
+	if limitQuery := query.Get("limit"); limitQuery != "" {
+		var err error
+
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit <= 0 {
+			response.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	stats, _, _, ok := dsh.getDownloadStats(response, request)
+	if !ok {
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, GetTopDownloads(stats, groupBy, limit))
+}
+
+// DownloadsOverTime godoc
+// @Summary Get the downloads over time
+// @Description Get the downloads of every day or hour of a period, of all the repos, a repository or a tag or digest
+// @Description of a repository, the hourly downloads are only kept for the last days and not by tag or digest
+// @Router  /v2/_zot/ext/search/stats/downloads [get]
+// @Produce json
+// @Param   repo      query    string     false "only count the downloads of this repository"
+// @Param   reference query    string     false "only count the downloads of this tag or digest, requires repo"
+// @Param   from      query    string     false "start of the period, RFC 3339 or YYYY-MM-DD, 30 days ago by default"
+// @Param   to        query    string     false "end of the period, RFC 3339 or YYYY-MM-DD, now by default"
+// @Param   interval  query    string     false "day (default) or hour"
+// @Success 200 {object}   search.DownloadsOverTime
+// @Success 200 {object}   search.HourlyDownloadsOverTime
+// @Failure 400 {string}   string   "bad request"
+// @Failure 500 {string}   string   "internal server error".
+func (dsh *DownloadStatsHandler) DownloadsOverTime(response http.ResponseWriter, request *http.Request) {
+	reference := request.URL.Query().Get("reference")
+	if reference != "" && request.URL.Query().Get("repo") == "" {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	interval := request.URL.Query().Get("interval")
+	if interval == "" {
+		interval = DownloadsIntervalDay
+	}
+
+	if (interval != DownloadsIntervalDay && interval != DownloadsIntervalHour) ||
+		(interval == DownloadsIntervalHour && reference != "") {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	stats, from, to, ok := dsh.getDownloadStats(response, request)
+	if !ok {
+		return
+	}
+
+	if interval == DownloadsIntervalHour {
+		zcommon.WriteJSON(response, http.StatusOK, GetHourlyDownloadsOverTime(stats, from, to))
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, GetDownloadsOverTime(stats, reference, from, to))
+}
+
+// LastPulls godoc
+// @Summary Get the last pulls of the images of a repository
+// @Description Get when the tags and digests of a repository were last pulled and how many times, since they
+// @Description were pushed
+// @Router  /v2/_zot/ext/search/stats/lastpulled [get]
+// @Produce json
+// @Param   repo      query    string     true  "repository name"
+// @Success 200 {array}    search.LastPull
+// @Failure 400 {string}   string   "bad request"
+// @Failure 404 {string}   string   "not found"
+// @Failure 500 {string}   string   "internal server error".
+func (dsh *DownloadStatsHandler) LastPulls(response http.ResponseWriter, request *http.Request) {
+	repo := request.URL.Query().Get("repo")
+	if repo == "" {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if ok, err := reqCtx.RepoIsUserAvailable(request.Context(), repo); !ok || err != nil {
+		writeRepoNotFound(response, repo)
+
+		return
+	}
+
+	repoMeta, err := dsh.metaDB.GetRepoMeta(repo)
+	if err != nil {
+		if errors.Is(err, zerr.ErrRepoMetaNotFound) {
+			writeRepoNotFound(response, repo)
+
+			return
+		}
+
+		dsh.log.Error().Err(err).Str("repository", repo).Msg("stats: unable to get the repo metadata")
+
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, GetLastPulls(repoMeta))
+}
+
+func (dsh *DownloadStatsHandler) getDownloadStats(response http.ResponseWriter, request *http.Request,
+) ([]mTypes.DailyDownloads, time.Time, time.Time, bool) {
+	from, errFrom := parseDownloadStatsTime(request.URL.Query().Get("from"))
+	to, errTo := parseDownloadStatsTime(request.URL.Query().Get("to"))
+
+	periodStart, periodEnd := GetDownloadStatsPeriod(from, to)
+
+	if errFrom != nil || errTo != nil || periodStart.After(periodEnd) {
+		response.WriteHeader(http.StatusBadRequest)
+
+		return nil, periodStart, periodEnd, false
+	}
+
+	stats, err := dsh.metaDB.GetDownloadStats(request.Context(), request.URL.Query().Get("repo"),
+		periodStart, periodEnd)
+	if err != nil {
+		dsh.log.Error().Err(err).Msg("stats: unable to get the download statistics")
+
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return nil, periodStart, periodEnd, false
+	}
+
+	return stats, periodStart, periodEnd, true
+}
+
+// parseDownloadStatsTime accepts RFC 3339 times and days, returning nil if the time isn't set.
+func parseDownloadStatsTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil //nolint: nilnil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		parsed, err = time.Parse(mTypes.DownloadStatsDayFormat, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &parsed, nil
+}
+
+// RepoStorage is the storage used by the images of a repo, the blobs shared by its images counted once.
+type RepoStorage struct {
+	Repo   string `json:"repo"`
+	Images int    `json:"images"`
+	Size   int64  `json:"size"`
+}
+
+// RegistryStats are the statistics of the repos the user can read. StorageSize is the sum of the sizes of the repos,
+// UniqueSize counts the blobs shared by repos once, so the dedupe saves their difference if it's enabled.
+type RegistryStats struct {
+	Repos         int                `json:"repos"`
+	Images        int                `json:"images"`
+	StorageSize   int64              `json:"storageSize"`
+	UniqueSize    int64              `json:"uniqueSize"`
+	Dedupe        bool               `json:"dedupe"`
+	DedupeSavings int64              `json:"dedupeSavings"`
+	LargestRepos  []RepoStorage      `json:"largestRepos"`
+	TopDownloads  []DownloadCount    `json:"topDownloads,omitempty"`
+	Downloads     *DownloadsOverTime `json:"downloads,omitempty"`
+}
+
+// GetRegistryStats returns the storage statistics of the tagged images of the repos, with the 'limit' largest repos.
+func GetRegistryStats(ctx context.Context, metaDB mTypes.MetaDB, dedupe bool, limit int) (RegistryStats, error) {
+	reposMeta, err := metaDB.GetMultipleRepoMeta(ctx, func(repoMeta mTypes.RepoMetadata) bool { return true })
+	if err != nil {
+		return RegistryStats{}, err
+	}
+
+	stats := RegistryStats{Repos: len(reposMeta), Dedupe: dedupe, LargestRepos: []RepoStorage{}}
+	registryBlobs := map[string]int64{}
+
+	for _, repoMeta := range reposMeta {
+		repoBlobs := map[string]int64{}
+
+		for _, descriptor := range repoMeta.Tags {
+			if err := addImageBlobs(metaDB, descriptor, repoBlobs); err != nil {
+				return RegistryStats{}, err
+			}
+		}
+
+		repoStorage := RepoStorage{Repo: repoMeta.Name, Images: len(repoMeta.Tags)}
+
+		for digest, size := range repoBlobs {
+			repoStorage.Size += size
+			registryBlobs[digest] = size
+		}
+
+		stats.Images += repoStorage.Images
+		stats.StorageSize += repoStorage.Size
+		stats.LargestRepos = append(stats.LargestRepos, repoStorage)
+	}
+
+	for _, size := range registryBlobs {
+		stats.UniqueSize += size
+	}
+
+	if dedupe {
+		stats.DedupeSavings = stats.StorageSize - stats.UniqueSize
+	}
+
+	sort.Slice(stats.LargestRepos, func(i, j int) bool {
+		if stats.LargestRepos[i].Size != stats.LargestRepos[j].Size {
+			return stats.LargestRepos[i].Size > stats.LargestRepos[j].Size
+		}
+
+		return stats.LargestRepos[i].Repo < stats.LargestRepos[j].Repo
+	})
+
+	if limit > 0 && len(stats.LargestRepos) > limit {
+		stats.LargestRepos = stats.LargestRepos[:limit]
+	}
+
+	return stats, nil
+}
+
+// addImageBlobs adds the manifests, configs and layers of the image or of the images of the index to the blobs.
+func addImageBlobs(metaDB mTypes.MetaDB, descriptor mTypes.Descriptor, blobs map[string]int64) error {
+	switch descriptor.MediaType {
+	case ispec.MediaTypeImageManifest:
+		manifestData, err := metaDB.GetManifestData(godigest.Digest(descriptor.Digest))
+		if err != nil {
+			return err
+		}
+
+		var manifest ispec.Manifest
+
+		if err := json.Unmarshal(manifestData.ManifestBlob, &manifest); err != nil {
+			return err
+		}
+
+		blobs[descriptor.Digest] = int64(len(manifestData.ManifestBlob))
+		blobs[manifest.Config.Digest.String()] = manifest.Config.Size
+
+		for _, layer := range manifest.Layers {
+			blobs[layer.Digest.String()] = layer.Size
+		}
+	case ispec.MediaTypeImageIndex:
+		indexData, err := metaDB.GetIndexData(godigest.Digest(descriptor.Digest))
+		if err != nil {
+			return err
+		}
+
+		var index ispec.Index
+
+		if err := json.Unmarshal(indexData.IndexBlob, &index); err != nil {
+			return err
+		}
+
+		blobs[descriptor.Digest] = int64(len(indexData.IndexBlob))
+
+		for _, manifest := range index.Manifests {
+			err := addImageBlobs(metaDB, mTypes.Descriptor{Digest: manifest.Digest.String(), MediaType: manifest.MediaType},
+				blobs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegistryStatsHandler serves the statistics of the registry, only counting the repos the user can read.
+type RegistryStatsHandler struct {
+	metaDB        mTypes.MetaDB
+	dedupe        bool
+	downloadStats *DownloadStatsHandler
+	log           log.Logger
+}
+
+// NewRegistryStatsHandler returns the handler of the registry statistics, which include the download statistics
+// if downloadStats is set.
+func NewRegistryStatsHandler(metaDB mTypes.MetaDB, dedupe bool, downloadStats *DownloadStatsHandler,
+	log log.Logger,
+) *RegistryStatsHandler {
+	return &RegistryStatsHandler{
+		metaDB:        metaDB,
+		dedupe:        dedupe,
+		downloadStats: downloadStats,
+		log:           log,
+	}
+}
+
+// RegistryStats godoc
+// @Summary Get the statistics of the registry
+// @Description Get the number of repos and images, the storage used and saved by the dedupe, the largest repos,
+// @Description and if enabled the most downloaded repos and the downloads over time
+// @Router  /v2/_zot/ext/search/stats [get]
+// @Produce json
+// @Param   from      query    string     false "start of the downloads period, RFC 3339 or YYYY-MM-DD, 30 days ago by default"
+// @Param   to        query    string     false "end of the downloads period, RFC 3339 or YYYY-MM-DD, now by default"
+// @Param   limit     query    int        false "maximum number of largest and most downloaded repos, 10 by default"
+// @Success 200 {object}   search.RegistryStats
+// @Failure 400 {string}   string   "bad request"
+// @Failure 500 {string}   string   "internal server error".
+func (rsh *RegistryStatsHandler) RegistryStats(response http.ResponseWriter, request *http.Request) {
+	limit := DefaultTopDownloadsLimit
+
+	if limitQuery := request.URL.Query().Get("limit"); limitQuery != "" {
+		var err error
+
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit <= 0 {
+			response.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	stats, err := GetRegistryStats(request.Context(), rsh.metaDB, rsh.dedupe, limit)
+	if err != nil {
+		rsh.log.Error().Err(err).Msg("stats: unable to get the registry statistics")
+
+		response.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if rsh.downloadStats != nil {
+		downloadStats, from, to, ok := rsh.downloadStats.getDownloadStats(response, request)
+		if !ok {
+			return
+		}
+
+		downloads := GetDownloadsOverTime(downloadStats, "", from, to)
+
+		stats.TopDownloads = GetTopDownloads(downloadStats, DownloadsByRepo, limit)
+		stats.Downloads = &downloads
+	}
+
+	zcommon.WriteJSON(response, http.StatusOK, stats)
+}
+
+// NewDownloadStatsRetentionTaskGenerator returns a generator of tasks deleting the daily download statistics
+// older than the retention and rolling up the hourly ones older than the hourly retention, once a day.
+func NewDownloadStatsRetentionTaskGenerator(metaDB mTypes.MetaDB, retention, hourlyRetention time.Duration,
+	log log.Logger,
+) (scheduler.TaskGenerator, time.Duration) {
+	if retention == 0 {
+		retention = DefaultDownloadStatsRetention
+	}
+
+	if hourlyRetention == 0 {
+		hourlyRetention = DefaultHourlyDownloadStatsRetention
+	}
+
+	return &downloadStatsRetentionTaskGenerator{
+		metaDB:          metaDB,
+		retention:       retention,
+		hourlyRetention: hourlyRetention,
+		log:             log,
+	}, downloadStatsRetentionInterval
+}
+
+type downloadStatsRetentionTaskGenerator struct {
+	metaDB          mTypes.MetaDB
+	retention       time.Duration
+	hourlyRetention time.Duration
+	generated       bool
+	done            bool
+	log             log.Logger
+}
+
+func (gen *downloadStatsRetentionTaskGenerator) Next() (scheduler.Task, error) {
+	if gen.generated {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.generated = true
+
+	now := time.Now()
+
+	return &downloadStatsRetentionTask{gen.metaDB, now.Add(-gen.retention), now.Add(-gen.hourlyRetention), gen.log},
+		nil
+}
+
+func (gen *downloadStatsRetentionTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *downloadStatsRetentionTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *downloadStatsRetentionTaskGenerator) Reset() {
+	gen.generated = false
+	gen.done = false
+}
+
+type downloadStatsRetentionTask struct {
+	metaDB       mTypes.MetaDB
+	before       time.Time
+	hourlyBefore time.Time
+	log          log.Logger
+}
+
+func (task *downloadStatsRetentionTask) DoWork(ctx context.Context) error {
+	if err := task.metaDB.DeleteDownloadStats(task.before); err != nil {
+		task.log.Error().Err(err).Msg("stats: unable to delete the expired download statistics")
+
+		return err
+	}
+
+	if err := task.metaDB.RollupDownloadStats(task.hourlyBefore); err != nil {
+		task.log.Error().Err(err).Msg("stats: unable to roll up the hourly download statistics")
+
+		return err
+	}
+
+	return nil
+}
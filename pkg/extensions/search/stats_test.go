@@ -0,0 +1,319 @@
+//go:build search
+// +build search
+
+package search_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/extensions/search"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	. "zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestDownloadStats(t *testing.T) {
+	Convey("Test the download statistics", t, func() {
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		statsURL := baseURL + constants.FullSearchStats
+		defaultVal := true
+
+		htpasswdPath := MakeHtpasswdFileFromString(GetCredString("writer", "writer") + "\n" +
+			GetCredString("reader", "reader"))
+		defer os.Remove(htpasswdPath)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Auth = &config.AuthConfig{HTPasswd: config.AuthHTPasswd{Path: htpasswdPath}}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{Users: []string{"writer"}, Actions: []string{"read", "create", "update"}},
+						{Users: []string{"reader"}, Actions: []string{"read"}},
+					},
+				},
+				"private": config.PolicyGroup{
+					Policies: []config.Policy{
+						{Users: []string{"writer"}, Actions: []string{"read", "create", "update"}},
+					},
+				},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{
+			Search: &extconf.SearchConfig{
+				BaseConfig:    extconf.BaseConfig{Enable: &defaultVal},
+				DownloadStats: &extconf.DownloadStatsConfig{},
+			},
+		}
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		image := CreateRandomImage()
+
+		err := UploadImageWithBasicAuth(image, baseURL, "repo", "1.0", "writer", "writer")
+		So(err, ShouldBeNil)
+
+		err = UploadImageWithBasicAuth(CreateRandomImage(), baseURL, "private", "1.0", "writer", "writer")
+		So(err, ShouldBeNil)
+
+		pull := func(user, repo, reference string) {
+			resp, err := resty.R().SetBasicAuth(user, user).Get(baseURL + "/v2/" + repo + "/manifests/" + reference)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		}
+
+		pull("reader", "repo", "1.0")
+		pull("reader", "repo", "1.0")
+		pull("writer", "repo", image.DigestStr())
+		pull("writer", "private", "1.0")
+
+		// the HEAD requests aren't counted
+		resp, err := resty.R().SetBasicAuth("reader", "reader").Head(baseURL + "/v2/repo/manifests/1.0")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		Convey("Top downloads", func() {
+			var topDownloads []search.DownloadCount
+
+			resp, err := resty.R().SetBasicAuth("writer", "writer").Get(statsURL + "/top")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &topDownloads)
+			So(err, ShouldBeNil)
+			So(topDownloads, ShouldResemble, []search.DownloadCount{
+				{Repo: "repo", Count: 3, UniqueClients: 2},
+				{Repo: "private", Count: 1, UniqueClients: 1},
+			})
+
+			// the downloads of the repos the user can't read aren't returned
+			resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/top?groupBy=tag")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &topDownloads)
+			So(err, ShouldBeNil)
+			So(topDownloads, ShouldResemble, []search.DownloadCount{
+				{Repo: "repo", Reference: "1.0", Count: 2, UniqueClients: 1},
+			})
+
+			resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/top?groupBy=digest&limit=1")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &topDownloads)
+			So(err, ShouldBeNil)
+			So(topDownloads, ShouldResemble, []search.DownloadCount{
+				{Repo: "repo", Reference: image.DigestStr(), Count: 3, UniqueClients: 2},
+			})
+
+			for _, query := range []string{"groupBy=user", "limit=0", "from=yesterday", "from=2030-01-01&to=2029-01-01"} {
+				resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/top?" + query)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+			}
+
+			resp, err = resty.R().Get(statsURL + "/top")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Downloads over time", func() {
+			var downloadsOverTime search.DownloadsOverTime
+
+			today := time.Now().UTC().Format(mTypes.DownloadStatsDayFormat)
+			from := time.Now().UTC().AddDate(0, 0, -2).Format(mTypes.DownloadStatsDayFormat)
+
+			resp, err := resty.R().SetBasicAuth("reader", "reader").
+				Get(statsURL + "/downloads?repo=repo&from=" + from)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &downloadsOverTime)
+			So(err, ShouldBeNil)
+			So(downloadsOverTime.Total, ShouldEqual, 3)
+			So(downloadsOverTime.UniqueClients, ShouldEqual, 2)
+			So(len(downloadsOverTime.Points), ShouldEqual, 3)
+			So(downloadsOverTime.Points[0].Count, ShouldEqual, 0)
+			So(downloadsOverTime.Points[2], ShouldResemble, search.DownloadsPoint{
+				Day: today, Count: 3, UniqueClients: 2,
+			})
+
+			resp, err = resty.R().SetBasicAuth("reader", "reader").
+				Get(statsURL + "/downloads?repo=repo&reference=1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &downloadsOverTime)
+			So(err, ShouldBeNil)
+			So(downloadsOverTime.Total, ShouldEqual, 2)
+			So(downloadsOverTime.UniqueClients, ShouldEqual, 1)
+
+			resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/downloads?repo=private")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &downloadsOverTime)
+			So(err, ShouldBeNil)
+			So(downloadsOverTime.Total, ShouldEqual, 0)
+
+			resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/downloads?reference=1.0")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Hourly downloads over time", func() {
+			var downloadsOverTime search.HourlyDownloadsOverTime
+
+			now := time.Now().UTC()
+			from := now.Add(-2 * time.Hour).Format(time.RFC3339)
+
+			resp, err := resty.R().SetBasicAuth("reader", "reader").
+				Get(statsURL + "/downloads?interval=hour&repo=repo&from=" + url.QueryEscape(from))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &downloadsOverTime)
+			So(err, ShouldBeNil)
+			So(downloadsOverTime.Total, ShouldEqual, 3)
+			So(len(downloadsOverTime.Points), ShouldEqual, 3)
+			So(downloadsOverTime.Points[2].Hour, ShouldEqual, now.Truncate(time.Hour).Format(time.RFC3339))
+
+			for _, query := range []string{"interval=minute", "interval=hour&repo=repo&reference=1.0"} {
+				resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/downloads?" + query)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+			}
+		})
+
+		Convey("Last pulls", func() {
+			var lastPulls []search.LastPull
+
+			resp, err := resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/lastpulled?repo=repo")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &lastPulls)
+			So(err, ShouldBeNil)
+			So(len(lastPulls), ShouldEqual, 2)
+			So(lastPulls[0].Reference, ShouldEqual, "1.0")
+			So(lastPulls[0].DownloadCount, ShouldEqual, 2)
+			So(lastPulls[1].Reference, ShouldEqual, image.DigestStr())
+			So(lastPulls[1].DownloadCount, ShouldEqual, 3)
+			So(lastPulls[1].LastPulled.After(lastPulls[0].LastPulled), ShouldBeTrue)
+
+			resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/lastpulled?repo=private")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "/lastpulled")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Registry statistics", func() {
+			var stats search.RegistryStats
+
+			imageSize := image.ManifestDescriptor.Size + image.Manifest.Config.Size
+			for _, layer := range image.Manifest.Layers {
+				imageSize += layer.Size
+			}
+
+			// the blobs of the copy are deduped
+			err := UploadImageWithBasicAuth(image, baseURL, "copy", "1.0", "writer", "writer")
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetBasicAuth("writer", "writer").Get(statsURL)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &stats)
+			So(err, ShouldBeNil)
+			So(stats.Repos, ShouldEqual, 3)
+			So(stats.Images, ShouldEqual, 3)
+			So(stats.Dedupe, ShouldBeTrue)
+			So(stats.DedupeSavings, ShouldEqual, imageSize)
+			So(stats.StorageSize-stats.UniqueSize, ShouldEqual, imageSize)
+			So(stats.LargestRepos, ShouldHaveLength, 3)
+			So(stats.TopDownloads, ShouldResemble, []search.DownloadCount{
+				{Repo: "repo", Count: 3, UniqueClients: 2},
+				{Repo: "private", Count: 1, UniqueClients: 1},
+			})
+			So(stats.Downloads.Total, ShouldEqual, 4)
+
+			// the repos the user can't read aren't counted
+			resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "?limit=1")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			err = json.Unmarshal(resp.Body(), &stats)
+			So(err, ShouldBeNil)
+			So(stats.Repos, ShouldEqual, 2)
+			So(stats.StorageSize, ShouldEqual, 2*imageSize)
+			So(stats.UniqueSize, ShouldEqual, imageSize)
+			So(stats.LargestRepos, ShouldResemble, []search.RepoStorage{{Repo: "copy", Images: 1, Size: imageSize}})
+			So(stats.TopDownloads, ShouldResemble, []search.DownloadCount{{Repo: "repo", Count: 3, UniqueClients: 2}})
+
+			for _, query := range []string{"limit=0", "from=yesterday"} {
+				resp, err = resty.R().SetBasicAuth("reader", "reader").Get(statsURL + "?" + query)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+			}
+		})
+
+		Convey("GraphQL queries", func() {
+			query := `{
+				TopDownloads(groupBy: TAG, limit: 5) { Repo Reference Count UniqueClients }
+				DownloadsOverTime(repo: "repo") { Total UniqueClients Points { Day Count } }
+			}`
+
+			resp, err := resty.R().SetBasicAuth("writer", "writer").
+				Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(query))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			var result struct {
+				Data struct {
+					TopDownloads      []search.DownloadCount
+					DownloadsOverTime struct {
+						Total         int
+						UniqueClients int
+						Points        []search.DownloadsPoint
+					}
+				}
+				Errors []interface{}
+			}
+
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+			So(result.Errors, ShouldBeEmpty)
+			So(result.Data.TopDownloads, ShouldResemble, []search.DownloadCount{
+				{Repo: "repo", Reference: "1.0", Count: 2, UniqueClients: 1},
+				{Repo: "private", Reference: "1.0", Count: 1, UniqueClients: 1},
+			})
+			So(result.Data.DownloadsOverTime.Total, ShouldEqual, 3)
+			So(result.Data.DownloadsOverTime.UniqueClients, ShouldEqual, 2)
+			// the last 30 days by default
+			So(len(result.Data.DownloadsOverTime.Points), ShouldEqual, 31)
+		})
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/api/constants"
 	"zotregistry.io/zot/pkg/common"
+	"zotregistry.io/zot/pkg/extensions"
 	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
 	"zotregistry.io/zot/pkg/log"
@@ -544,7 +545,7 @@ func TestChangingRepoState(t *testing.T) {
 	}
 
 	// ------ Create the test repos
-	defaultStore := local.NewImageStore(conf.Storage.RootDirectory, false, false, 0, 0, false, false,
+	defaultStore := local.NewImageStore(conf.Storage.RootDirectory, false, false, 0, 0, false, false, false,
 		log.NewLogger("debug", ""), monitoring.NewMetricsServer(false, log.NewLogger("debug", "")), nil, nil)
 
 	err = WriteImageToFileSystem(img, accesibleRepo, "tag", storage.StoreController{
@@ -1009,6 +1010,207 @@ func TestExpandedRepoInfoWithUserPrefs(t *testing.T) {
 	})
 }
 
+func TestBookmarkLists(t *testing.T) {
+	Convey("Named bookmark lists shared with groups", t, func() {
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		defaultVal := true
+
+		ownerUser, ownerPassword := "owner", "owner123"
+		readerUser, readerPassword := "reader", "reader123"
+		otherUser, otherPassword := "other", "other123"
+
+		htpasswdPath := MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n%s\n\n",
+			getCredString(ownerUser, ownerPassword), getCredString(readerUser, readerPassword),
+			getCredString(otherUser, otherPassword)))
+		defer os.Remove(htpasswdPath)
+
+		conf := config.New()
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{
+				Path: htpasswdPath,
+			},
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Groups: config.Groups{
+				"readers": config.Group{Users: []string{readerUser}},
+			},
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{ownerUser, readerUser, otherUser},
+							Actions: []string{"read"},
+						},
+					},
+				},
+				"private": config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{ownerUser},
+							Actions: []string{"read"},
+						},
+					},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{ownerUser},
+				Actions: []string{"read", "create", "update"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+		conf.Extensions.UI = &extconf.UIConfig{}
+		conf.Extensions.UI.Enable = &defaultVal
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		for _, repo := range []string{"alpine", "ubuntu", "private"} {
+			err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, repo, "1.0", ownerUser, ownerPassword)
+			So(err, ShouldBeNil)
+		}
+
+		userprefsBaseURL := baseURL + constants.FullUserPrefs
+		listsQuery := `{ BookmarkLists { Name Owner Repos SharedWith } }`
+		listReposQuery := `{ BookmarkListRepos(name: "golden", owner: "owner") { Page { TotalCount } Results { Name } } }`
+
+		type bookmarkListsResponse struct {
+			Data struct {
+				BookmarkLists []struct {
+					Name       string
+					Owner      string
+					Repos      []string
+					SharedWith []string
+				}
+			}
+		}
+
+		type bookmarkListReposResponse struct {
+			Data struct {
+				BookmarkListRepos common.PaginatedReposResult
+			}
+			Errors []interface{}
+		}
+
+		ownerClient := resty.R().SetBasicAuth(ownerUser, ownerPassword)
+
+		resp, err := ownerClient.SetBody(`{"repos": ["alpine", "private"], "sharedWith": ["readers"]}`).
+			Put(userprefsBaseURL + "?action=setBookmarkList&name=golden")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		Convey("The owner and the readers of a list can query it", func() {
+			resp, err := ownerClient.Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(listsQuery))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			lists := bookmarkListsResponse{}
+			err = json.Unmarshal(resp.Body(), &lists)
+			So(err, ShouldBeNil)
+			So(lists.Data.BookmarkLists, ShouldHaveLength, 1)
+			So(lists.Data.BookmarkLists[0].Name, ShouldEqual, "golden")
+			So(lists.Data.BookmarkLists[0].Owner, ShouldEqual, ownerUser)
+			So(lists.Data.BookmarkLists[0].Repos, ShouldResemble, []string{"alpine", "private"})
+			So(lists.Data.BookmarkLists[0].SharedWith, ShouldResemble, []string{"readers"})
+
+			readerClient := resty.R().SetBasicAuth(readerUser, readerPassword)
+
+			resp, err = readerClient.Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(listsQuery))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			lists = bookmarkListsResponse{}
+			err = json.Unmarshal(resp.Body(), &lists)
+			So(err, ShouldBeNil)
+			So(lists.Data.BookmarkLists, ShouldHaveLength, 1)
+			// the reader can't read the private repo
+			So(lists.Data.BookmarkLists[0].Repos, ShouldResemble, []string{"alpine"})
+
+			resp, err = readerClient.Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(listReposQuery))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			listRepos := bookmarkListReposResponse{}
+			err = json.Unmarshal(resp.Body(), &listRepos)
+			So(err, ShouldBeNil)
+			So(listRepos.Data.BookmarkListRepos.Page.TotalCount, ShouldEqual, 1)
+			So(listRepos.Data.BookmarkListRepos.Results[0].Name, ShouldEqual, "alpine")
+
+			// shared lists are read-only
+			resp, err = readerClient.Put(userprefsBaseURL + "?action=deleteBookmarkList&name=golden")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Users outside of the groups can't query a list", func() {
+			otherClient := resty.R().SetBasicAuth(otherUser, otherPassword)
+
+			resp, err := otherClient.Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(listsQuery))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			lists := bookmarkListsResponse{}
+			err = json.Unmarshal(resp.Body(), &lists)
+			So(err, ShouldBeNil)
+			So(lists.Data.BookmarkLists, ShouldBeEmpty)
+
+			resp, err = otherClient.Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(listReposQuery))
+			So(err, ShouldBeNil)
+
+			listRepos := bookmarkListReposResponse{}
+			err = json.Unmarshal(resp.Body(), &listRepos)
+			So(err, ShouldBeNil)
+			So(listRepos.Errors, ShouldNotBeEmpty)
+
+			// users can't add the repos they can't read to their lists
+			resp, err = otherClient.SetBody(`{"repos": ["private"]}`).
+				Put(userprefsBaseURL + "?action=setBookmarkList&name=mine")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("The owner can delete a list", func() {
+			resp, err := ownerClient.Put(userprefsBaseURL + "?action=deleteBookmarkList&name=golden")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			resp, err = ownerClient.Get(baseURL + constants.FullSearchPrefix + "?query=" + url.QueryEscape(listsQuery))
+			So(err, ShouldBeNil)
+
+			lists := bookmarkListsResponse{}
+			err = json.Unmarshal(resp.Body(), &lists)
+			So(err, ShouldBeNil)
+			So(lists.Data.BookmarkLists, ShouldBeEmpty)
+
+			resp, err = ownerClient.Put(userprefsBaseURL + "?action=deleteBookmarkList&name=golden")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Invalid requests", func() {
+			resp, err := ownerClient.SetBody(`{"repos": ["alpine"]}`).Put(userprefsBaseURL + "?action=setBookmarkList")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = ownerClient.SetBody(`not json`).Put(userprefsBaseURL + "?action=setBookmarkList&name=golden")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = ownerClient.Put(userprefsBaseURL + "?action=deleteBookmarkList")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
 func PutRepoStarURL(repo string) string {
 	return fmt.Sprintf("?repo=%s&action=toggleStar", repo)
 }
@@ -1017,6 +1219,238 @@ func PutRepoBookmarkURL(repo string) string {
 	return fmt.Sprintf("?repo=%s&action=toggleBookmark", repo)
 }
 
+func TestUserPrefsExportImport(t *testing.T) {
+	Convey("Export, import and migrate the user preferences", t, func() {
+		port := GetFreePort()
+		baseURL := GetBaseURL(port)
+		defaultVal := true
+
+		adminUser, adminPassword := "admin", "admin123"
+		aliceUser, alicePassword := "alice", "alice123"
+		bobUser, bobPassword := "bob", "bob123"
+
+		htpasswdPath := MakeHtpasswdFileFromString(fmt.Sprintf("%s\n%s\n%s\n\n",
+			getCredString(adminUser, adminPassword), getCredString(aliceUser, alicePassword),
+			getCredString(bobUser, bobPassword)))
+		defer os.Remove(htpasswdPath)
+
+		conf := config.New()
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.HTTP.Port = port
+		conf.HTTP.Auth = &config.AuthConfig{
+			HTPasswd: config.AuthHTPasswd{
+				Path: htpasswdPath,
+			},
+		}
+		conf.HTTP.AccessControl = &config.AccessControlConfig{
+			Repositories: config.Repositories{
+				"**": config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{aliceUser, bobUser},
+							Actions: []string{"read"},
+						},
+					},
+				},
+				"private": config.PolicyGroup{
+					Policies: []config.Policy{
+						{
+							Users:   []string{aliceUser},
+							Actions: []string{"read"},
+						},
+					},
+				},
+			},
+			AdminPolicy: config.Policy{
+				Users:   []string{adminUser},
+				Actions: []string{"read", "create", "update"},
+			},
+		}
+		conf.Extensions = &extconf.ExtensionConfig{}
+		conf.Extensions.Search = &extconf.SearchConfig{}
+		conf.Extensions.Search.Enable = &defaultVal
+		conf.Extensions.Search.CVE = nil
+		conf.Extensions.UI = &extconf.UIConfig{}
+		conf.Extensions.UI.Enable = &defaultVal
+
+		ctlr := api.NewController(conf)
+
+		ctlrManager := NewControllerManager(ctlr)
+		ctlrManager.StartAndWait(port)
+		defer ctlrManager.StopServer()
+
+		for _, repo := range []string{"alpine", "ubuntu", "private"} {
+			err := UploadImageWithBasicAuth(CreateRandomImage(), baseURL, repo, "1.0", adminUser, adminPassword)
+			So(err, ShouldBeNil)
+		}
+
+		userprefsBaseURL := baseURL + constants.FullUserPrefs
+		aliceClient := resty.R().SetBasicAuth(aliceUser, alicePassword)
+		bobClient := resty.R().SetBasicAuth(bobUser, bobPassword)
+		adminClient := resty.R().SetBasicAuth(adminUser, adminPassword)
+
+		getStars := func(repo string) int {
+			stars, err := ctlr.MetaDB.GetRepoStars(repo)
+			So(err, ShouldBeNil)
+
+			return stars
+		}
+
+		exportPrefs := func(client *resty.Request, url string) extensions.UserPreferences {
+			resp, err := client.Get(url)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			prefs := extensions.UserPreferences{}
+			err = json.Unmarshal(resp.Body(), &prefs)
+			So(err, ShouldBeNil)
+
+			return prefs
+		}
+
+		resp, err := aliceClient.Put(userprefsBaseURL + "?action=toggleStar&repo=alpine")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = aliceClient.Put(userprefsBaseURL + "?action=toggleStar&repo=private")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = aliceClient.Put(userprefsBaseURL + "?action=toggleBookmark&repo=ubuntu")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		resp, err = aliceClient.SetBody(`{"repos": ["alpine", "private"], "sharedWith": ["readers"]}`).
+			Put(userprefsBaseURL + "?action=setBookmarkList&name=golden")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		alicePrefs := exportPrefs(aliceClient, baseURL+constants.FullUserPrefsExport)
+		So(alicePrefs.StarredRepos, ShouldResemble, []string{"alpine", "private"})
+		So(alicePrefs.BookmarkedRepos, ShouldResemble, []string{"ubuntu"})
+		So(alicePrefs.BookmarkLists, ShouldHaveLength, 1)
+		So(alicePrefs.BookmarkLists[0].Name, ShouldEqual, "golden")
+		So(alicePrefs.BookmarkLists[0].Repos, ShouldResemble, []string{"alpine", "private"})
+		So(alicePrefs.BookmarkLists[0].SharedWith, ShouldResemble, []string{"readers"})
+
+		Convey("The users import their preferences", func() {
+			resp, err := resty.R().Get(baseURL + constants.FullUserPrefsExport)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusUnauthorized)
+
+			bobPrefs := exportPrefs(bobClient, baseURL+constants.FullUserPrefsExport)
+			So(bobPrefs.StarredRepos, ShouldBeEmpty)
+			So(bobPrefs.BookmarkLists, ShouldBeEmpty)
+
+			// the repos which don't exist or which bob can't read are skipped
+			alicePrefs.StarredRepos = append(alicePrefs.StarredRepos, "missing")
+
+			body, err := json.Marshal(alicePrefs)
+			So(err, ShouldBeNil)
+
+			resp, err = bobClient.SetBody(body).Put(baseURL + constants.FullUserPrefsImport)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			result := extensions.UserPrefsImportResult{}
+			err = json.Unmarshal(resp.Body(), &result)
+			So(err, ShouldBeNil)
+			So(result.SkippedRepos, ShouldResemble, []string{"private", "missing", "private"})
+
+			bobPrefs = exportPrefs(bobClient, baseURL+constants.FullUserPrefsExport)
+			So(bobPrefs.StarredRepos, ShouldResemble, []string{"alpine"})
+			So(bobPrefs.BookmarkedRepos, ShouldResemble, []string{"ubuntu"})
+			So(bobPrefs.BookmarkLists, ShouldHaveLength, 1)
+			So(bobPrefs.BookmarkLists[0].Repos, ShouldResemble, []string{"alpine"})
+			So(getStars("alpine"), ShouldEqual, 2)
+
+			// importing again doesn't change anything
+			resp, err = bobClient.SetBody(body).Put(baseURL + constants.FullUserPrefsImport + "?mode=merge")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+			So(getStars("alpine"), ShouldEqual, 2)
+
+			resp, err = bobClient.SetBody(`{"starredRepos": ["ubuntu"]}`).
+				Put(baseURL + constants.FullUserPrefsImport + "?mode=replace")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			bobPrefs = exportPrefs(bobClient, baseURL+constants.FullUserPrefsExport)
+			So(bobPrefs.StarredRepos, ShouldResemble, []string{"ubuntu"})
+			So(bobPrefs.BookmarkedRepos, ShouldBeEmpty)
+			So(bobPrefs.BookmarkLists, ShouldBeEmpty)
+			So(getStars("alpine"), ShouldEqual, 1)
+			So(getStars("ubuntu"), ShouldEqual, 1)
+
+			resp, err = bobClient.SetBody(`{}`).Put(baseURL + constants.FullUserPrefsImport + "?mode=overwrite")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = bobClient.SetBody(`not json`).Put(baseURL + constants.FullUserPrefsImport)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			resp, err = bobClient.SetBody(`{"bookmarkLists": [{"repos": ["alpine"]}]}`).
+				Put(baseURL + constants.FullUserPrefsImport)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("The admins manage the preferences of the users", func() {
+			resp, err := bobClient.Get(baseURL + constants.FullUserPrefsAdmin + "?user=alice")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			resp, err = bobClient.Post(baseURL + constants.FullUserPrefsMigrate + "?from=alice&to=bob")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusForbidden)
+
+			So(exportPrefs(adminClient, baseURL+constants.FullUserPrefsAdmin+"?user=alice"), ShouldResemble, alicePrefs)
+
+			resp, err = adminClient.Get(baseURL + constants.FullUserPrefsAdmin + "?user=nobody")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = adminClient.Get(baseURL + constants.FullUserPrefsAdmin)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			// alice is renamed to carol
+			resp, err = adminClient.Post(baseURL + constants.FullUserPrefsMigrate + "?from=alice&to=carol")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			So(exportPrefs(adminClient, baseURL+constants.FullUserPrefsAdmin+"?user=carol"), ShouldResemble, alicePrefs)
+
+			alicePrefs = exportPrefs(adminClient, baseURL+constants.FullUserPrefsAdmin+"?user=alice")
+			So(alicePrefs.StarredRepos, ShouldBeEmpty)
+			So(alicePrefs.BookmarkedRepos, ShouldBeEmpty)
+			So(alicePrefs.BookmarkLists, ShouldBeEmpty)
+			So(getStars("alpine"), ShouldEqual, 1)
+			So(getStars("private"), ShouldEqual, 1)
+
+			resp, err = adminClient.Post(baseURL + constants.FullUserPrefsMigrate + "?from=nobody&to=carol")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			resp, err = adminClient.Post(baseURL + constants.FullUserPrefsMigrate + "?from=carol&to=carol")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+			// carol is removed
+			resp, err = adminClient.Delete(baseURL + constants.FullUserPrefsAdmin + "?user=carol")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+			carolPrefs := exportPrefs(adminClient, baseURL+constants.FullUserPrefsAdmin+"?user=carol")
+			So(carolPrefs.StarredRepos, ShouldBeEmpty)
+			So(carolPrefs.BookmarkLists, ShouldBeEmpty)
+			So(getStars("alpine"), ShouldEqual, 0)
+			So(getStars("private"), ShouldEqual, 0)
+		})
+	})
+}
+
 func getCredString(username, password string) string {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), 10)
 	if err != nil {
@@ -0,0 +1,29 @@
+package secrets
+
+const (
+	// ArtifactType is the artifact type of the referrers holding the secret scan results of an image.
+	ArtifactType = "application/vnd.zot.secrets.report.v1"
+	// ReportMediaType is the media type of the layer holding the JSON encoded Report.
+	ReportMediaType = "application/vnd.zot.secrets.report.v1+json"
+)
+
+const (
+	ConfidenceHigh   = "HIGH"
+	ConfidenceMedium = "MEDIUM"
+)
+
+// Report is the content of the secret scan referrer of an image.
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Finding is a secret found in a file of an image layer, the matched text is redacted.
+type Finding struct {
+	RuleID      string `json:"ruleID"`
+	Description string `json:"description"`
+	Confidence  string `json:"confidence"`
+	LayerDigest string `json:"layerDigest"`
+	FilePath    string `json:"filePath"`
+	Line        int    `json:"line"`
+	Match       string `json:"match"`
+}
@@ -0,0 +1,59 @@
+//go:build secrets
+// +build secrets
+
+package secrets
+
+import "regexp"
+
+type rule struct {
+	ID          string
+	Description string
+	Confidence  string
+	Regex       *regexp.Regexp
+}
+
+//nolint:lll
+var rules = []rule{
+	{
+		ID:          "aws-access-key-id",
+		Description: "AWS access key ID",
+		Confidence:  ConfidenceHigh,
+		Regex:       regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`),
+	},
+	{
+		ID:          "aws-secret-access-key",
+		Description: "AWS secret access key",
+		Confidence:  ConfidenceHigh,
+		Regex:       regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`),
+	},
+	{
+		ID:          "private-key",
+		Description: "private key",
+		Confidence:  ConfidenceHigh,
+		Regex:       regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		ID:          "github-token",
+		Description: "GitHub token",
+		Confidence:  ConfidenceHigh,
+		Regex:       regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{82}\b`),
+	},
+	{
+		ID:          "gitlab-token",
+		Description: "GitLab personal access token",
+		Confidence:  ConfidenceHigh,
+		Regex:       regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20}\b`),
+	},
+	{
+		ID:          "slack-token",
+		Description: "Slack token",
+		Confidence:  ConfidenceHigh,
+		Regex:       regexp.MustCompile(`\bxox[baprs]-[0-9A-Za-z-]{10,48}\b`),
+	},
+	{
+		ID:          "generic-secret",
+		Description: "hardcoded password, token or API key",
+		Confidence:  ConfidenceMedium,
+		Regex:       regexp.MustCompile(`(?i)\b(?:password|passwd|secret|token|api_?key)["']?\s*[:=]\s*["'][^"'\s]{8,}["']`),
+	},
+}
@@ -0,0 +1,377 @@
+//go:build secrets
+// +build secrets
+
+package secrets
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	regTypes "github.com/google/go-containerregistry/pkg/v1/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	// only the beginning of a file is checked for NUL bytes to decide if it's a binary.
+	binarySniffLen = 512
+	// used when the config is built in code and doesn't go through the defaults of the config loader.
+	defaultMaxFileSize = 1 << 20
+	// findings of manifests verified before being stored, waiting for their report to be pushed.
+	verifiedManifestsCacheSize = 100
+)
+
+type Scanner struct {
+	config            *config.SecretsConfig
+	maxFileSize       int64
+	storeController   storage.StoreController
+	metaDB            mTypes.MetaDB
+	taskScheduler     atomic.Pointer[scheduler.Scheduler]
+	verifiedManifests *lru.Cache[godigest.Digest, []Finding]
+	log               log.Logger
+}
+
+func NewScanner(config *config.SecretsConfig, storeController storage.StoreController, metaDB mTypes.MetaDB,
+	log log.Logger,
+) *Scanner {
+	maxFileSize := config.MaxFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+
+	verifiedManifests, _ := lru.New[godigest.Digest, []Finding](verifiedManifestsCacheSize)
+
+	return &Scanner{
+		config:            config,
+		maxFileSize:       maxFileSize,
+		storeController:   storeController,
+		metaDB:            metaDB,
+		verifiedManifests: verifiedManifests,
+		log:               log,
+	}
+}
+
+// SetTaskScheduler sets the scheduler running the scans of pushed images when pushes are not blocked by the scan.
+func (scanner *Scanner) SetTaskScheduler(taskScheduler *scheduler.Scheduler) {
+	if scanner == nil {
+		return
+	}
+
+	scanner.taskScheduler.Store(taskScheduler)
+}
+
+func (scanner *Scanner) isEnabled() bool {
+	return scanner != nil && scanner.config != nil && scanner.config.Enable != nil && *scanner.config.Enable
+}
+
+// VerifyManifest is called before a pushed manifest is stored. If RejectPush is set the image is scanned
+// right away and ErrImageContainsSecrets is returned if high confidence secrets are found, otherwise
+// the scan is deferred until the manifest is stored, see OnManifestPushed.
+func (scanner *Scanner) VerifyManifest(repo, mediaType string, body []byte, imgStore storageTypes.ImageStore,
+) error {
+	if !scanner.isEnabled() || !scanner.config.RejectPush {
+		return nil
+	}
+
+	findings := scanner.ScanManifest(repo, mediaType, body, imgStore)
+
+	for _, finding := range findings {
+		if finding.Confidence == ConfidenceHigh {
+			return zerr.NewError(zerr.ErrImageContainsSecrets).
+				AddDetail("secret", fmt.Sprintf("%s in %s:%d of layer %s", finding.Description,
+					finding.FilePath, finding.Line, finding.LayerDigest))
+		}
+	}
+
+	scanner.verifiedManifests.Add(godigest.FromBytes(body), findings)
+
+	return nil
+}
+
+// OnManifestPushed stores the report of a pushed image. Images already verified before being stored
+// get their report right away, the others are scanned in the background.
+func (scanner *Scanner) OnManifestPushed(repo string, subject ispec.Descriptor, body []byte) {
+	if !scanner.isEnabled() {
+		return
+	}
+
+	if findings, ok := scanner.verifiedManifests.Get(subject.Digest); ok {
+		scanner.verifiedManifests.Remove(subject.Digest)
+
+		if err := scanner.StoreReport(repo, subject, findings); err != nil {
+			scanner.log.Error().Err(err).Str("repository", repo).Str("digest", subject.Digest.String()).
+				Msg("secrets: couldn't store secret scan report")
+		}
+
+		return
+	}
+
+	task := &scanTask{scanner: scanner, repo: repo, subject: subject, body: body}
+
+	if taskScheduler := scanner.taskScheduler.Load(); taskScheduler != nil {
+		taskScheduler.SubmitTask(task, scheduler.MediumPriority)
+
+		return
+	}
+
+	go func() {
+		_ = task.DoWork(context.Background())
+	}()
+}
+
+type scanTask struct {
+	scanner *Scanner
+	repo    string
+	subject ispec.Descriptor
+	body    []byte
+}
+
+func (task *scanTask) DoWork(ctx context.Context) error {
+	imgStore := task.scanner.storeController.GetImageStore(task.repo)
+	findings := task.scanner.ScanManifest(task.repo, task.subject.MediaType, task.body, imgStore)
+
+	err := task.scanner.StoreReport(task.repo, task.subject, findings)
+	if err != nil {
+		task.scanner.log.Error().Err(err).Str("repository", task.repo).Str("digest", task.subject.Digest.String()).
+			Msg("secrets: couldn't store secret scan report")
+	}
+
+	return err
+}
+
+// ScanManifest looks for secrets in the files of the layers referenced by an image manifest.
+// Errors reading the layers are only logged, those layers are skipped.
+func (scanner *Scanner) ScanManifest(repo, mediaType string, body []byte, imgStore storageTypes.ImageStore,
+) []Finding {
+	findings := []Finding{}
+
+	if !scanner.isEnabled() || !isImageManifest(mediaType) {
+		return findings
+	}
+
+	var manifest ispec.Manifest
+
+	// invalid manifests are rejected later by the image store
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return findings
+	}
+
+	// artifacts such as signatures, SBOMs or our own reports are not container images
+	if manifest.ArtifactType != "" || !isImageConfig(manifest.Config.MediaType) {
+		return findings
+	}
+
+	for _, layer := range manifest.Layers {
+		layerFindings, err := scanner.scanLayer(repo, layer, imgStore)
+		if err != nil {
+			scanner.log.Warn().Err(err).Str("repository", repo).Str("layer", layer.Digest.String()).
+				Msg("secrets: unable to scan layer")
+		}
+
+		findings = append(findings, layerFindings...)
+	}
+
+	if len(findings) > 0 {
+		scanner.log.Warn().Str("repository", repo).Int("findings", len(findings)).
+			Msg("secrets: found secrets in pushed image")
+	}
+
+	return findings
+}
+
+func isImageManifest(mediaType string) bool {
+	return mediaType == ispec.MediaTypeImageManifest || mediaType == string(regTypes.DockerManifestSchema2)
+}
+
+func isImageConfig(mediaType string) bool {
+	return mediaType == ispec.MediaTypeImageConfig || mediaType == string(regTypes.DockerConfigJSON)
+}
+
+func (scanner *Scanner) scanLayer(repo string, layer ispec.Descriptor, imgStore storageTypes.ImageStore,
+) ([]Finding, error) {
+	findings := []Finding{}
+
+	blob, _, err := imgStore.GetBlob(repo, layer.Digest, layer.MediaType)
+	if err != nil {
+		return findings, err
+	}
+	defer blob.Close()
+
+	bufferedBlob := bufio.NewReader(blob)
+
+	var layerReader io.Reader = bufferedBlob
+
+	// layers are either gzip compressed or plain tar archives, anything else is skipped
+	magic, err := bufferedBlob.Peek(2) //nolint: gomnd
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(layerReader)
+		if err != nil {
+			return findings, err
+		}
+		defer gzipReader.Close()
+
+		layerReader = gzipReader
+	}
+
+	tarReader := tar.NewReader(layerReader)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return findings, err
+		}
+
+		if header.Typeflag != tar.TypeReg || header.Size > scanner.maxFileSize {
+			continue
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return findings, err
+		}
+
+		for _, finding := range scanContent(content) {
+			finding.LayerDigest = layer.Digest.String()
+			finding.FilePath = header.Name
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, nil
+}
+
+func scanContent(content []byte) []Finding {
+	findings := []Finding{}
+
+	sniffLen := binarySniffLen
+	if len(content) < sniffLen {
+		sniffLen = len(content)
+	}
+
+	if bytes.IndexByte(content[:sniffLen], 0) != -1 {
+		return findings
+	}
+
+	for idx, line := range bytes.Split(content, []byte("\n")) {
+		for _, rule := range rules {
+			match := rule.Regex.Find(line)
+			if match == nil {
+				continue
+			}
+
+			findings = append(findings, Finding{
+				RuleID:      rule.ID,
+				Description: rule.Description,
+				Confidence:  rule.Confidence,
+				Line:        idx + 1,
+				Match:       redact(string(match)),
+			})
+		}
+	}
+
+	return findings
+}
+
+// redact keeps just enough of the secret to help finding it.
+func redact(secret string) string {
+	const visibleLen = 4
+
+	if len(secret) <= 2*visibleLen {
+		return "****"
+	}
+
+	return secret[:visibleLen] + "****"
+}
+
+// StoreReport pushes the findings of an image as a referrer of that image so they can be listed
+// by clients and by the search extension. Nothing is pushed for images without findings.
+func (scanner *Scanner) StoreReport(repo string, subject ispec.Descriptor, findings []Finding) error {
+	if !scanner.isEnabled() || len(findings) == 0 {
+		return nil
+	}
+
+	imgStore := scanner.storeController.GetImageStore(repo)
+
+	reportBlob, err := json.Marshal(Report{Findings: findings})
+	if err != nil {
+		return err
+	}
+
+	reportDigest := godigest.FromBytes(reportBlob)
+
+	if _, _, err := imgStore.FullBlobUpload(repo, bytes.NewReader(reportBlob), reportDigest); err != nil {
+		return err
+	}
+
+	emptyConfig := ispec.DescriptorEmptyJSON
+
+	if _, _, err := imgStore.FullBlobUpload(repo, bytes.NewReader(emptyConfig.Data), emptyConfig.Digest); err != nil {
+		return err
+	}
+
+	emptyConfig.Data = nil
+
+	manifest := ispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2}, //nolint: gomnd
+		MediaType:    ispec.MediaTypeImageManifest,
+		ArtifactType: ArtifactType,
+		Config:       emptyConfig,
+		Layers: []ispec.Descriptor{
+			{
+				MediaType: ReportMediaType,
+				Digest:    reportDigest,
+				Size:      int64(len(reportBlob)),
+			},
+		},
+		Subject: &subject,
+	}
+
+	manifestBlob, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	manifestDigest := godigest.FromBytes(manifestBlob)
+
+	_, _, err = imgStore.PutImageManifest(repo, manifestDigest.String(), ispec.MediaTypeImageManifest, manifestBlob)
+	if err != nil {
+		return err
+	}
+
+	if scanner.metaDB != nil {
+		err = meta.OnUpdateManifest(repo, manifestDigest.String(), ispec.MediaTypeImageManifest, manifestDigest,
+			manifestBlob, scanner.storeController, scanner.metaDB, scanner.log)
+		if err != nil {
+			return err
+		}
+	}
+
+	scanner.log.Info().Str("repository", repo).Str("subject", subject.Digest.String()).
+		Str("report", manifestDigest.String()).Msg("secrets: stored secret scan report")
+
+	return nil
+}
@@ -7,3 +7,19 @@ const (
 	OCI               = "OCIReference"
 	SyncBlobUploadDir = ".sync"
 )
+
+// priorities of the periodic sync of a content rule.
+const (
+	LowPriority    = "low"
+	MediumPriority = "medium"
+	HighPriority   = "high"
+)
+
+// policies applied when a synced tag already exists locally with a different image.
+const (
+	CollisionOverwrite = "overwrite"
+	CollisionSkip      = "skip"
+	// the upstream image is synced as the tag with a suffix, keeping the local image
+	CollisionKeepBoth      = "keepBoth"
+	DefaultCollisionSuffix = "-upstream"
+)
@@ -7,7 +7,7 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/Masterminds/semver"
+	"github.com/Masterminds/semver/v3"
 	glob "github.com/bmatcuk/doublestar/v4"
 
 	"zotregistry.io/zot/pkg/common"
@@ -24,7 +24,8 @@ eg: "content": [
 		"stripPrefix": true
 		"tags": {
 			"regex": "4.*",
-			"semver": true
+			"semver": true,
+			"semverConstraint": ">=4.2 <5.0"
 		}
 	}
 ]
@@ -32,11 +33,13 @@ eg: "content": [
 
 type ContentManager struct {
 	contents []syncconf.Content
-	log      log.Logger
+	// the mapped repos are synced into their mapping destination instead of the content rules destinations
+	mapper NamespaceMapper
+	log    log.Logger
 }
 
-func NewContentManager(contents []syncconf.Content, log log.Logger) ContentManager {
-	return ContentManager{contents: contents, log: log}
+func NewContentManager(contents []syncconf.Content, mapper NamespaceMapper, log log.Logger) ContentManager {
+	return ContentManager{contents: contents, mapper: mapper, log: log}
 }
 
 /*
@@ -49,7 +52,14 @@ func (cm ContentManager) MatchesContent(repo string) bool {
 	return content != nil
 }
 
-// FilterTags filters a repo tags based on content config rules (semver, regex).
+// MatchesContentIDs returns whether the first content config rule matching a repo is one of contentIDs.
+func (cm ContentManager) MatchesContentIDs(repo string, contentIDs []int) bool {
+	contentID := cm.getContentIDByUpstreamRepo(repo)
+
+	return contentID != -1 && common.Contains(contentIDs, contentID)
+}
+
+// FilterTags filters a repo tags based on content config rules (semver, semver constraint, regex).
 func (cm ContentManager) FilterTags(repo string, tags []string) ([]string, error) {
 	content := cm.getContentByLocalRepo(repo)
 
@@ -66,6 +76,13 @@ func (cm ContentManager) FilterTags(repo string, tags []string) ([]string, error
 		if content.Tags.Semver != nil && *content.Tags.Semver {
 			tags = filterTagsBySemver(tags, cm.log)
 		}
+
+		if content.Tags.SemverConstraint != nil {
+			tags, err = filterTagsBySemverConstraint(tags, *content.Tags.SemverConstraint, cm.log)
+			if err != nil {
+				return []string{}, err
+			}
+		}
 	}
 
 	return tags, nil
@@ -81,26 +98,105 @@ func (cm ContentManager) GetRepoDestination(repo string) string {
 		return ""
 	}
 
+	if localRepo, ok := cm.mapper.Map(repo); ok {
+		return localRepo
+	}
+
 	return getRepoDestination(repo, *content)
 }
 
+/*
+GetRepoDestinations applies content destination config rules and returns the local repos a repo is synced into,
+the destination first and then the fan-out destinations.
+- used by periodically sync.
+*/
+func (cm ContentManager) GetRepoDestinations(repo string) []string {
+	content := cm.getContentByUpstreamRepo(repo)
+	if content == nil {
+		return []string{}
+	}
+
+	if localRepo, ok := cm.mapper.Map(repo); ok {
+		return []string{localRepo}
+	}
+
+	localRepos := []string{}
+
+	for _, destination := range getDestinations(*content) {
+		destContent := *content
+		destContent.Destination = destination
+
+		localRepo := getRepoDestination(repo, destContent)
+		if !common.Contains(localRepos, localRepo) {
+			localRepos = append(localRepos, localRepo)
+		}
+	}
+
+	return localRepos
+}
+
 /*
 GetRepoSource is the inverse function of GetRepoDestination, needed in on demand to find out
-the remote name of a repo given a local repo.
+the remote name of a repo given a local repo, which can be mapped or in any of the destinations of a content rule.
 - used by on demand sync.
 */
 func (cm ContentManager) GetRepoSource(repo string) string {
-	content := cm.getContentByLocalRepo(repo)
+	if remoteRepo, ok := cm.mapper.Unmap(repo); ok {
+		if len(cm.contents) == 0 || cm.MatchesContent(remoteRepo) {
+			return remoteRepo
+		}
+
+		return ""
+	}
+
+	var remoteRepo string
+
+	if len(cm.contents) == 0 {
+		// without content rules, the repos which are not mapped are synced as is
+		remoteRepo = strings.Trim(repo, "/")
+	} else {
+		content, destination := cm.getContentAndDestinationByLocalRepo(repo)
+		if content == nil {
+			return ""
+		}
+
+		destContent := *content
+		destContent.Destination = destination
+
+		remoteRepo = getRepoSource(repo, destContent)
+	}
+
+	// the mapped upstream repos are only synced into their mapping destination
+	if _, ok := cm.mapper.Map(remoteRepo); ok {
+		return ""
+	}
+
+	return remoteRepo
+}
+
+// GetCollisionPolicy returns the policy applied when a local tag synced from an upstream repo
+// already exists with a different image, empty if the content rule doesn't set it.
+func (cm ContentManager) GetCollisionPolicy(repo string) string {
+	content := cm.getContentByUpstreamRepo(repo)
 	if content == nil {
 		return ""
 	}
 
-	return getRepoSource(repo, *content)
+	return content.CollisionPolicy
 }
 
 // utilies functions.
 func (cm ContentManager) getContentByUpstreamRepo(repo string) *syncconf.Content {
-	for _, content := range cm.contents {
+	contentID := cm.getContentIDByUpstreamRepo(repo)
+	if contentID == -1 {
+		return nil
+	}
+
+	return &cm.contents[contentID]
+}
+
+func (cm ContentManager) getContentIDByUpstreamRepo(repo string) int {
+	for cID, content := range cm.contents {
 		var prefix string
 		// handle prefixes starting with '/'
 		if strings.HasPrefix(content.Prefix, "/") {
@@ -119,50 +215,68 @@ func (cm ContentManager) getContentByUpstreamRepo(repo string) *syncconf.Content
 		}
 
 		if matched {
-			return &content
+			return cID
 		}
 	}
 
-	return nil
+	return -1
 }
 
 func (cm ContentManager) getContentByLocalRepo(repo string) *syncconf.Content {
-	contentID := -1
+	content, _ := cm.getContentAndDestinationByLocalRepo(repo)
+
+	return content
+}
+
+// getContentAndDestinationByLocalRepo returns the content rule of a local repo and the destination it belongs to.
+func (cm ContentManager) getContentAndDestinationByLocalRepo(repo string) (*syncconf.Content, string) {
 	repo = strings.Trim(repo, "/")
 
 	for cID, content := range cm.contents {
 		// make sure prefix ends in "/" to extract the meta characters
 		prefix := strings.Trim(content.Prefix, "/") + "/"
-		destination := strings.Trim(content.Destination, "/")
 
-		var patternSlice []string
+		for _, contentDestination := range getDestinations(content) {
+			destination := strings.Trim(contentDestination, "/")
 
-		if content.StripPrefix {
-			_, metaCharacters := glob.SplitPattern(prefix)
-			patternSlice = append(patternSlice, destination, metaCharacters)
-		} else {
-			patternSlice = append(patternSlice, destination, prefix)
-		}
+			var patternSlice []string
 
-		pattern := strings.Trim(strings.Join(patternSlice, "/"), "/")
+			if content.StripPrefix {
+				_, metaCharacters := glob.SplitPattern(prefix)
+				patternSlice = append(patternSlice, destination, metaCharacters)
+			} else {
+				patternSlice = append(patternSlice, destination, prefix)
+			}
 
-		matched, err := glob.Match(pattern, repo)
-		if err != nil {
-			continue
-		}
+			pattern := strings.Trim(strings.Join(patternSlice, "/"), "/")
 
-		if matched {
-			contentID = cID
+			matched, err := glob.Match(pattern, repo)
+			if err != nil {
+				continue
+			}
 
-			break
+			if matched {
+				return &cm.contents[cID], contentDestination
+			}
 		}
 	}
 
-	if contentID == -1 {
-		return nil
+	return nil, ""
+}
+
+// getDestinations returns the destination and the fan-out destinations of a content rule.
+func getDestinations(content syncconf.Content) []string {
+	if len(content.Destinations) == 0 {
+		return []string{content.Destination}
 	}
 
-	return &cm.contents[contentID]
+	destinations := []string{}
+
+	if content.Destination != "" {
+		destinations = append(destinations, content.Destination)
+	}
+
+	return append(destinations, content.Destinations...)
 }
 
 func getRepoSource(localRepo string, content syncconf.Content) string {
@@ -255,3 +369,30 @@ func filterTagsBySemver(tags []string, log log.Logger) []string {
 
 	return filteredTags
 }
+
+// filterTagsBySemverConstraint filters tags by checking if they are semver versions satisfying the constraint.
+func filterTagsBySemverConstraint(tags []string, constraint string, log log.Logger) ([]string, error) {
+	filteredTags := []string{}
+
+	log.Info().Str("constraint", constraint).Msg("filtering tags using semver constraint")
+
+	semverConstraint, err := semver.NewConstraint(constraint)
+	if err != nil {
+		log.Error().Err(err).Str("constraint", constraint).Msg("couldn't parse semver constraint")
+
+		return filteredTags, err
+	}
+
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+
+		if semverConstraint.Check(version) {
+			filteredTags = append(filteredTags, tag)
+		}
+	}
+
+	return filteredTags, nil
+}
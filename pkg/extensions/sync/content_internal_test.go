@@ -5,11 +5,13 @@ package sync
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
 	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/scheduler"
 )
 
 func TestContentManager(t *testing.T) {
@@ -67,7 +69,7 @@ func TestContentManager(t *testing.T) {
 
 	Convey("Test GetRepoDestination()", t, func() {
 		for _, test := range testCases {
-			cm := NewContentManager([]syncconf.Content{test.content}, log.Logger{})
+			cm := NewContentManager([]syncconf.Content{test.content}, NamespaceMapper{}, log.Logger{})
 			actualResult := cm.GetRepoDestination(test.expected)
 			So(actualResult, ShouldEqual, test.repo)
 		}
@@ -76,7 +78,7 @@ func TestContentManager(t *testing.T) {
 	// this is the inverse function of getRepoDestination()
 	Convey("Test GetRepoSource()", t, func() {
 		for _, test := range testCases {
-			cm := NewContentManager([]syncconf.Content{test.content}, log.Logger{})
+			cm := NewContentManager([]syncconf.Content{test.content}, NamespaceMapper{}, log.Logger{})
 			actualResult := cm.GetRepoSource(test.repo)
 			So(actualResult, ShouldEqual, test.expected)
 		}
@@ -84,7 +86,7 @@ func TestContentManager(t *testing.T) {
 
 	Convey("Test MatchesContent() error", t, func() {
 		content := syncconf.Content{Prefix: "[repo%^&"}
-		cm := NewContentManager([]syncconf.Content{content}, log.Logger{})
+		cm := NewContentManager([]syncconf.Content{content}, NamespaceMapper{}, log.Logger{})
 		So(cm.MatchesContent("repo"), ShouldEqual, false)
 	})
 }
@@ -147,7 +149,7 @@ func TestGetContentByLocalRepo(t *testing.T) {
 
 	Convey("Test getContentByLocalRepo()", t, func() {
 		for _, test := range testCases {
-			cm := NewContentManager(test.content, log.Logger{})
+			cm := NewContentManager(test.content, NamespaceMapper{}, log.Logger{})
 			actualResult := cm.getContentByLocalRepo(test.repo)
 			if test.expected == -1 {
 				var tnil *syncconf.Content = nil
@@ -160,7 +162,7 @@ func TestGetContentByLocalRepo(t *testing.T) {
 
 	Convey("Test getContentByLocalRepo() error", t, func() {
 		content := syncconf.Content{Prefix: "[repo%^&"}
-		cm := NewContentManager([]syncconf.Content{content}, log.Logger{})
+		cm := NewContentManager([]syncconf.Content{content}, NamespaceMapper{}, log.Logger{})
 		So(cm.getContentByLocalRepo("repo"), ShouldBeNil)
 	})
 }
@@ -170,6 +172,8 @@ func TestFilterTags(t *testing.T) {
 	badRegex := "[*"
 	semverFalse := false
 	semverTrue := true
+	semverConstraint := ">=1.20 <2.0"
+	badSemverConstraint := "not-a-constraint"
 	testCases := []struct {
 		tags         []string
 		repo         string
@@ -213,6 +217,24 @@ func TestFilterTags(t *testing.T) {
 			filteredTags: []string{"v1.0.1"},
 			err:          false,
 		},
+		{
+			repo: "golang",
+			content: []syncconf.Content{
+				{Prefix: "golang", Tags: &syncconf.Tags{SemverConstraint: &semverConstraint}},
+			},
+			tags:         []string{"latest", "1.19.5", "1.20", "v1.21.3", "1.21-alpine", "1.22.0-rc.1", "2.0.0"},
+			filteredTags: []string{"1.20", "v1.21.3"},
+			err:          false,
+		},
+		{
+			repo: "golang",
+			content: []syncconf.Content{
+				{Prefix: "golang", Tags: &syncconf.Tags{Regex: &allTagsRegex, SemverConstraint: &badSemverConstraint}},
+			},
+			tags:         []string{"1.20"},
+			filteredTags: []string{},
+			err:          true,
+		},
 		{
 			repo: "repo",
 			content: []syncconf.Content{
@@ -236,7 +258,7 @@ func TestFilterTags(t *testing.T) {
 
 	Convey("Test FilterTags()", t, func() {
 		for _, test := range testCases {
-			cm := NewContentManager(test.content, log.NewLogger("debug", ""))
+			cm := NewContentManager(test.content, NamespaceMapper{}, log.NewLogger("debug", ""))
 			actualResult, err := cm.FilterTags(test.repo, test.tags)
 			So(actualResult, ShouldResemble, test.filteredTags)
 			if test.err {
@@ -247,3 +269,160 @@ func TestFilterTags(t *testing.T) {
 		}
 	})
 }
+
+func TestContentSchedules(t *testing.T) {
+	Convey("Test GetContentSchedules()", t, func() {
+		content := []syncconf.Content{
+			{Prefix: "library/alpine", PollInterval: time.Hour, Priority: "high"},
+			{Prefix: "internal/**"},
+			{Prefix: "mirrors/**", PollInterval: 24 * time.Hour, Priority: "low"},
+			{Prefix: "other/**", PollInterval: 2 * time.Hour},
+		}
+
+		schedules := GetContentSchedules(syncconf.RegistryConfig{Content: content, PollInterval: 6 * time.Hour})
+		So(schedules, ShouldResemble, []ContentSchedule{
+			{Interval: 6 * time.Hour, Priority: scheduler.MediumPriority, ContentIDs: []int{1}},
+			{Interval: time.Hour, Priority: scheduler.HighPriority, ContentIDs: []int{0}},
+			{Interval: 24 * time.Hour, Priority: scheduler.LowPriority, ContentIDs: []int{2}},
+			{Interval: 2 * time.Hour, Priority: scheduler.MediumPriority, ContentIDs: []int{3}},
+		})
+
+		// the rules without their own pollInterval are not synced periodically without a registry pollInterval
+		schedules = GetContentSchedules(syncconf.RegistryConfig{Content: content})
+		So(len(schedules), ShouldEqual, 3)
+		So(schedules[0].ContentIDs, ShouldResemble, []int{0})
+
+		schedules = GetContentSchedules(syncconf.RegistryConfig{PollInterval: 6 * time.Hour})
+		So(schedules, ShouldBeEmpty)
+	})
+
+	Convey("Test MatchesContentIDs()", t, func() {
+		content := []syncconf.Content{
+			{Prefix: "library/alpine"},
+			{Prefix: "library/**"},
+		}
+
+		cm := NewContentManager(content, NamespaceMapper{}, log.NewLogger("debug", ""))
+
+		// a repo belongs to the first rule matching it
+		So(cm.MatchesContentIDs("library/alpine", []int{0}), ShouldBeTrue)
+		So(cm.MatchesContentIDs("library/alpine", []int{1}), ShouldBeFalse)
+		So(cm.MatchesContentIDs("library/busybox", []int{1}), ShouldBeTrue)
+		So(cm.MatchesContentIDs("library/busybox", []int{0}), ShouldBeFalse)
+		So(cm.MatchesContentIDs("other/busybox", []int{0, 1}), ShouldBeFalse)
+	})
+}
+
+func TestContentDestinations(t *testing.T) {
+	Convey("Test fan-out destinations", t, func() {
+		content := []syncconf.Content{
+			{Prefix: "library/**", Destinations: []string{"/mirrors/dockerhub", "/cache"}, CollisionPolicy: "skip"},
+			{Prefix: "internal/**", Destination: "/internal", Destinations: []string{"/internal-copy"}, StripPrefix: true},
+			{Prefix: "other"},
+		}
+
+		cm := NewContentManager(content, NamespaceMapper{}, log.NewLogger("debug", ""))
+
+		So(cm.GetRepoDestinations("library/alpine"), ShouldResemble,
+			[]string{"mirrors/dockerhub/library/alpine", "cache/library/alpine"})
+		So(cm.GetRepoDestinations("internal/app"), ShouldResemble, []string{"internal/app", "internal-copy/app"})
+		So(cm.GetRepoDestinations("other"), ShouldResemble, []string{"other"})
+		So(cm.GetRepoDestinations("unknown"), ShouldBeEmpty)
+
+		// each destination maps back to the upstream repo
+		So(cm.GetRepoSource("mirrors/dockerhub/library/alpine"), ShouldEqual, "library/alpine")
+		So(cm.GetRepoSource("cache/library/alpine"), ShouldEqual, "library/alpine")
+		So(cm.GetRepoSource("internal-copy/app"), ShouldEqual, "internal/app")
+		So(cm.GetRepoSource("library/alpine"), ShouldBeEmpty)
+
+		So(cm.GetCollisionPolicy("library/alpine"), ShouldEqual, "skip")
+		So(cm.GetCollisionPolicy("internal/app"), ShouldBeEmpty)
+	})
+}
+
+func TestNamespaceMapper(t *testing.T) {
+	Convey("Test mapping upstream repos into local namespaces", t, func() {
+		mapper := NewNamespaceMapper([]syncconf.MappingConfig{
+			{Source: "docker.io/library/*", Destination: "mirrors/dockerhub/*"},
+			{Source: "quay.io/**", Destination: "mirrors/quay/**"},
+			{Source: "/team/*/app/**", Destination: "/apps/*/**"},
+			// invalid rules are skipped
+			{Source: "invalid/*", Destination: "invalid"},
+			{Source: "", Destination: "invalid"},
+		}, []string{"https://docker.io"}, log.NewLogger("debug", ""))
+
+		localRepo, ok := mapper.Map("library/alpine")
+		So(ok, ShouldBeTrue)
+		So(localRepo, ShouldEqual, "mirrors/dockerhub/alpine")
+
+		localRepo, ok = mapper.Map("team/ops/app/api/v2")
+		So(ok, ShouldBeTrue)
+		So(localRepo, ShouldEqual, "apps/ops/api/v2")
+
+		// '*' only matches a path element and the quay.io rule is for another registry
+		for _, repo := range []string{"library/nested/alpine", "quay.io/prometheus", "prometheus/node", "invalid/repo"} {
+			_, ok = mapper.Map(repo)
+			So(ok, ShouldBeFalse)
+		}
+
+		remoteRepo, ok := mapper.Unmap("mirrors/dockerhub/alpine")
+		So(ok, ShouldBeTrue)
+		So(remoteRepo, ShouldEqual, "library/alpine")
+
+		remoteRepo, ok = mapper.Unmap("/apps/ops/api/v2")
+		So(ok, ShouldBeTrue)
+		So(remoteRepo, ShouldEqual, "team/ops/app/api/v2")
+
+		_, ok = mapper.Unmap("mirrors/quay/prometheus/node")
+		So(ok, ShouldBeFalse)
+
+		_, ok = mapper.Unmap("library/alpine")
+		So(ok, ShouldBeFalse)
+
+		// the registry URL can include a namespace
+		mapper = NewNamespaceMapper([]syncconf.MappingConfig{
+			{Source: "docker.io/library/*", Destination: "mirrors/dockerhub/*"},
+		}, []string{"https://docker.io/library"}, log.NewLogger("debug", ""))
+
+		localRepo, ok = mapper.Map("alpine")
+		So(ok, ShouldBeTrue)
+		So(localRepo, ShouldEqual, "mirrors/dockerhub/alpine")
+
+		remoteRepo, ok = mapper.Unmap("mirrors/dockerhub/alpine")
+		So(ok, ShouldBeTrue)
+		So(remoteRepo, ShouldEqual, "alpine")
+	})
+
+	Convey("Test mappings with content rules", t, func() {
+		mapper := NewNamespaceMapper([]syncconf.MappingConfig{
+			{Source: "library/*", Destination: "mirrors/dockerhub/*"},
+		}, []string{"https://docker.io"}, log.NewLogger("debug", ""))
+
+		content := []syncconf.Content{
+			{Prefix: "library/**", Destination: "/ignored"},
+			{Prefix: "other/**", Destination: "/local"},
+		}
+
+		cm := NewContentManager(content, mapper, log.NewLogger("debug", ""))
+
+		// the mappings take precedence over the content rules destinations
+		So(cm.GetRepoDestinations("library/alpine"), ShouldResemble, []string{"mirrors/dockerhub/alpine"})
+		So(cm.GetRepoDestination("library/alpine"), ShouldEqual, "mirrors/dockerhub/alpine")
+		So(cm.GetRepoDestinations("other/app"), ShouldResemble, []string{"local/other/app"})
+
+		So(cm.GetRepoSource("mirrors/dockerhub/alpine"), ShouldEqual, "library/alpine")
+		So(cm.GetRepoSource("local/other/app"), ShouldEqual, "other/app")
+		// the mapped repos are not synced into the content rules destinations
+		So(cm.GetRepoSource("ignored/library/alpine"), ShouldBeEmpty)
+
+		// the mapped repos are still filtered by the content rules
+		cm = NewContentManager([]syncconf.Content{{Prefix: "other/**"}}, mapper, log.NewLogger("debug", ""))
+		So(cm.GetRepoSource("mirrors/dockerhub/alpine"), ShouldBeEmpty)
+
+		// without content rules, the repos which are not mapped are synced as is
+		cm = NewContentManager(nil, mapper, log.NewLogger("debug", ""))
+		So(cm.GetRepoSource("mirrors/dockerhub/alpine"), ShouldEqual, "library/alpine")
+		So(cm.GetRepoSource("other/app"), ShouldEqual, "other/app")
+		So(cm.GetRepoSource("library/alpine"), ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,133 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	zerr "zotregistry.io/zot/errors"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	TagCollisionEventType = "tagCollision"
+	webhookTimeout        = 30 * time.Second
+)
+
+// TagCollisionEvent is posted to the sync webhooks when a synced tag already exists locally with a different image.
+type TagCollisionEvent struct {
+	Type           string    `json:"type"`
+	Time           time.Time `json:"time"`
+	Registry       string    `json:"registry"`
+	UpstreamRepo   string    `json:"upstreamRepo"`
+	Repo           string    `json:"repo"`
+	Tag            string    `json:"tag"`
+	LocalDigest    string    `json:"localDigest"`
+	UpstreamDigest string    `json:"upstreamDigest"`
+	Policy         string    `json:"policy"`
+	// the tag the upstream image is synced as with the keepBoth policy
+	SyncedTag string `json:"syncedTag,omitempty"`
+}
+
+// EventNotifier logs the sync events and posts them to the webhooks, it's shared by the services of every registry.
+// A collision is only notified once, until either the local or the upstream image changes.
+type EventNotifier struct {
+	webhooks   []syncconf.WebhookConfig
+	httpClient *http.Client
+	// local and upstream digests of the notified collisions, by registry and local image
+	notified map[string]string
+	lock     *sync.Mutex
+	log      log.Logger
+}
+
+func NewEventNotifier(webhooks []syncconf.WebhookConfig, log log.Logger) *EventNotifier {
+	return &EventNotifier{
+		webhooks:   webhooks,
+		httpClient: &http.Client{Timeout: webhookTimeout},
+		notified:   map[string]string{},
+		lock:       &sync.Mutex{},
+		log:        log,
+	}
+}
+
+// notifyCollision posts the event in the background, so that the sync doesn't wait on the webhooks,
+// a nil notifier notifies nothing.
+func (notifier *EventNotifier) notifyCollision(event TagCollisionEvent) {
+	if notifier == nil {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s:%s", event.Registry, event.Repo, event.Tag)
+	digests := event.LocalDigest + "/" + event.UpstreamDigest
+
+	notifier.lock.Lock()
+
+	if notifier.notified[key] == digests {
+		notifier.lock.Unlock()
+
+		return
+	}
+
+	notifier.notified[key] = digests
+	notifier.lock.Unlock()
+
+	notifier.log.Warn().Str("registry", event.Registry).Str("repo", event.Repo).Str("tag", event.Tag).
+		Str("localDigest", event.LocalDigest).Str("upstreamDigest", event.UpstreamDigest).
+		Str("policy", event.Policy).Str("syncedTag", event.SyncedTag).
+		Msg("sync: local tag already holds a different image than upstream")
+
+	if len(notifier.webhooks) == 0 {
+		return
+	}
+
+	event.Type = TagCollisionEventType
+	event.Time = time.Now()
+
+	go func() {
+		// a failing webhook doesn't prevent notifying the others
+		for _, webhook := range notifier.webhooks {
+			if err := notifier.post(context.Background(), webhook, event); err != nil {
+				notifier.log.Error().Err(err).Str("url", webhook.URL).Msg("sync: unable to send event")
+			}
+		}
+	}()
+}
+
+func (notifier *EventNotifier) post(ctx context.Context, webhook syncconf.WebhookConfig, event interface{}) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := notifier.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned status code %d", zerr.ErrSyncWebhookFailed, webhook.URL, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	"github.com/containers/image/v5/manifest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/common"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
+)
+
+// Exclusions denies the upstream repos, tags and images matching the exclusion rules of a registry,
+// a nil Exclusions denies nothing.
+type Exclusions struct {
+	repos        []string
+	tags         []*regexp.Regexp
+	mediaTypes   []string
+	maxImageSize int64
+}
+
+// NewExclusions returns nil if nothing is excluded.
+func NewExclusions(config *syncconf.ExclusionsConfig) (*Exclusions, error) {
+	if config == nil {
+		return nil, nil //nolint: nilnil
+	}
+
+	exclusions := &Exclusions{
+		mediaTypes:   config.MediaTypes,
+		maxImageSize: config.MaxImageSize,
+	}
+
+	for _, pattern := range config.Repos {
+		pattern = strings.Trim(pattern, "/")
+		if !glob.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("%w: %s", zerr.ErrSyncInvalidExclusion, pattern)
+		}
+
+		exclusions.repos = append(exclusions.repos, pattern)
+	}
+
+	for _, pattern := range config.Tags {
+		tagRegex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", zerr.ErrSyncInvalidExclusion, pattern, err.Error())
+		}
+
+		exclusions.tags = append(exclusions.tags, tagRegex)
+	}
+
+	return exclusions, nil
+}
+
+// ExcludesRepo returns whether the upstream repo is not synced.
+func (exclusions *Exclusions) ExcludesRepo(repo string) bool {
+	if exclusions == nil {
+		return false
+	}
+
+	repo = strings.Trim(repo, "/")
+
+	for _, pattern := range exclusions.repos {
+		if matched, _ := glob.Match(pattern, repo); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExcludesTag returns whether the upstream tag is not synced, digests are never excluded.
+func (exclusions *Exclusions) ExcludesTag(tag string) bool {
+	if exclusions == nil {
+		return false
+	}
+
+	for _, tagRegex := range exclusions.tags {
+		if tagRegex.MatchString(tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterTags returns the tags which are not excluded.
+func (exclusions *Exclusions) FilterTags(tags []string) []string {
+	if exclusions == nil || len(exclusions.tags) == 0 {
+		return tags
+	}
+
+	filteredTags := []string{}
+
+	for _, tag := range tags {
+		if !exclusions.ExcludesTag(tag) {
+			filteredTags = append(filteredTags, tag)
+		}
+	}
+
+	return filteredTags
+}
+
+// CheckImage returns ErrSyncImageFilteredOut if the upstream image has an excluded media type or is too large,
+// the manifests of a multi-platform image are fetched to get the size of each platform.
+func (exclusions *Exclusions) CheckImage(ctx context.Context, httpClient *client.Client, repo, mediaType string,
+	manifestContent []byte,
+) error {
+	if exclusions == nil || (len(exclusions.mediaTypes) == 0 && exclusions.maxImageSize == 0) {
+		return nil
+	}
+
+	size, err := exclusions.getImageSize(ctx, httpClient, repo, mediaType, manifestContent)
+	if err != nil {
+		return err
+	}
+
+	if exclusions.maxImageSize > 0 && size > exclusions.maxImageSize {
+		return fmt.Errorf("%w: image size %d is larger than %d", zerr.ErrSyncImageFilteredOut, size,
+			exclusions.maxImageSize)
+	}
+
+	return nil
+}
+
+// getImageSize returns the size of the manifests, configs and layers of an image, it returns
+// ErrSyncImageFilteredOut if any of them has an excluded media type.
+func (exclusions *Exclusions) getImageSize(ctx context.Context, httpClient *client.Client, repo, mediaType string,
+	manifestContent []byte,
+) (int64, error) {
+	if common.Contains(exclusions.mediaTypes, mediaType) {
+		return 0, fmt.Errorf("%w: media type %s is excluded", zerr.ErrSyncImageFilteredOut, mediaType)
+	}
+
+	size := int64(len(manifestContent))
+
+	switch mediaType {
+	case ispec.MediaTypeImageIndex, manifest.DockerV2ListMediaType:
+		var index ispec.Index
+
+		if err := json.Unmarshal(manifestContent, &index); err != nil {
+			return 0, err
+		}
+
+		for _, desc := range index.Manifests {
+			var childManifest json.RawMessage
+
+			content, _, _, err := httpClient.MakeGetRequest(ctx, &childManifest, desc.MediaType,
+				"v2", repo, "manifests", desc.Digest.String())
+			if err != nil {
+				return 0, err
+			}
+
+			manifestSize, err := exclusions.getImageSize(ctx, httpClient, repo, desc.MediaType, content)
+			if err != nil {
+				return 0, err
+			}
+
+			size += manifestSize
+		}
+	default:
+		var imageManifest ispec.Manifest
+
+		if err := json.Unmarshal(manifestContent, &imageManifest); err != nil {
+			return 0, err
+		}
+
+		for _, desc := range append([]ispec.Descriptor{imageManifest.Config}, imageManifest.Layers...) {
+			if common.Contains(exclusions.mediaTypes, desc.MediaType) {
+				return 0, fmt.Errorf("%w: media type %s is excluded", zerr.ErrSyncImageFilteredOut, desc.MediaType)
+			}
+
+			size += desc.Size
+		}
+	}
+
+	return size, nil
+}
@@ -0,0 +1,341 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	// lifetime of the tokens returned without expires_in, see the distribution token spec.
+	defaultTokenLifetime = 60 * time.Second
+	// the tokens are refreshed when they are about to expire, so that they don't expire mid-request.
+	maxTokenRefreshMargin = 30 * time.Second
+	// the requests rate limited by the upstream registry are retried after the delay it asks for,
+	// unless the delay is longer than maxRetryAfter.
+	maxRateLimitRetries    = 3
+	defaultRetryAfter      = 2 * time.Second
+	maxRetryAfter          = time.Minute
+	rateLimitRemainingName = "RateLimit-Remaining"
+)
+
+// bearerToken is a token of the upstream registry token server, see
+// https://distribution.github.io/distribution/spec/auth/token/
+type bearerToken struct {
+	Token       string    `json:"token"`
+	AccessToken string    `json:"access_token"` //nolint: tagliatelle
+	ExpiresIn   int       `json:"expires_in"`   //nolint: tagliatelle
+	IssuedAt    time.Time `json:"issued_at"`    //nolint: tagliatelle
+	// the token is refreshed from the challenge it was issued for
+	challenge map[string]string
+	refreshAt time.Time
+}
+
+// authTransport authenticates the requests to upstream registries using bearer tokens, which are refreshed
+// before they expire, it also waits for the rate limited requests to be allowed again.
+// The requests are sent with basic auth until a bearer challenge is received.
+type authTransport struct {
+	transport http.RoundTripper
+	username  string
+	password  string
+	// tokens by repo, the scope of the tokens is at most a repo
+	tokens map[string]*bearerToken
+	lock   *sync.Mutex
+	// called with the remaining requests quota reported by the upstream registry
+	onRateLimit func(remaining int64)
+	log         log.Logger
+}
+
+func newAuthTransport(transport http.RoundTripper, config Config, log log.Logger) *authTransport {
+	return &authTransport{
+		transport:   transport,
+		username:    config.Username,
+		password:    config.Password,
+		tokens:      map[string]*bearerToken{},
+		lock:        &sync.Mutex{},
+		onRateLimit: config.OnRateLimit,
+		log:         log,
+	}
+}
+
+func (at *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := getTokenKey(req.URL)
+
+	token, err := at.getToken(req, key)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := at.roundTrip(req, token)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate"))
+	if !ok {
+		return resp, nil
+	}
+
+	// the token expired or the request needs a token with another scope
+	token, err = at.fetchToken(req, key, challenge)
+	if err != nil {
+		at.log.Error().Err(err).Str("realm", challenge["realm"]).Str("scope", challenge["scope"]).
+			Msg("sync: failed to get token from upstream registry")
+
+		return resp, nil
+	}
+
+	resp.Body.Close()
+
+	return at.roundTrip(req, token)
+}
+
+// roundTrip sends the request, retrying it while the upstream registry rate limits it.
+func (at *authTransport) roundTrip(req *http.Request, token *bearerToken) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		authReq := req.Clone(req.Context())
+
+		if token != nil {
+			authReq.Header.Set("Authorization", "Bearer "+token.get())
+		}
+
+		resp, err := at.transport.RoundTrip(authReq)
+		if err != nil {
+			return resp, err
+		}
+
+		at.observeRateLimit(resp)
+
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= maxRateLimitRetries {
+			return resp, nil
+		}
+
+		delay := parseRetryAfter(resp.Header.Get("Retry-After"), defaultRetryAfter<<attempt)
+		if delay > maxRetryAfter {
+			return resp, nil
+		}
+
+		at.log.Warn().Str("url", req.URL.String()).Str("delay", delay.String()).
+			Msg("sync: rate limited by upstream registry, retrying")
+
+		resp.Body.Close()
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// getToken returns the token of a repo, refreshing it if it's about to expire, nil if no token is needed.
+func (at *authTransport) getToken(req *http.Request, key string) (*bearerToken, error) {
+	at.lock.Lock()
+	token, ok := at.tokens[key]
+	at.lock.Unlock()
+
+	if !ok {
+		return nil, nil //nolint: nilnil
+	}
+
+	if time.Now().Before(token.refreshAt) {
+		return token, nil
+	}
+
+	return at.fetchToken(req, key, token.challenge)
+}
+
+func (at *authTransport) fetchToken(req *http.Request, key string, challenge map[string]string,
+) (*bearerToken, error) {
+	realm, err := url.Parse(challenge["realm"])
+	if err != nil {
+		return nil, err
+	}
+
+	query := realm.Query()
+
+	if service, ok := challenge["service"]; ok {
+		query.Set("service", service)
+	}
+
+	if scope, ok := challenge["scope"]; ok {
+		query.Set("scope", scope)
+	}
+
+	realm.RawQuery = query.Encode()
+
+	tokenReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if at.username != "" && at.password != "" {
+		tokenReq.SetBasicAuth(at.username, at.password)
+	}
+
+	resp, err := at.transport.RoundTrip(tokenReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: token server returned status code %d", zerr.ErrSyncTokenRequest, resp.StatusCode)
+	}
+
+	token := &bearerToken{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return nil, err
+	}
+
+	token.challenge = challenge
+	token.refreshAt = getTokenRefreshTime(token)
+
+	at.lock.Lock()
+	at.tokens[key] = token
+	at.lock.Unlock()
+
+	return token, nil
+}
+
+func (at *authTransport) observeRateLimit(resp *http.Response) {
+	if at.onRateLimit == nil {
+		return
+	}
+
+	remaining, ok := parseRateLimitRemaining(resp.Header)
+	if ok {
+		at.onRateLimit(remaining)
+	}
+}
+
+func (token *bearerToken) get() string {
+	if token.Token != "" {
+		return token.Token
+	}
+
+	return token.AccessToken
+}
+
+// getTokenRefreshTime returns when the token should be refreshed, shortly before it expires.
+func getTokenRefreshTime(token *bearerToken) time.Time {
+	issuedAt := token.IssuedAt
+	if issuedAt.IsZero() || issuedAt.After(time.Now()) {
+		issuedAt = time.Now()
+	}
+
+	lifetime := time.Duration(token.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = defaultTokenLifetime
+	}
+
+	margin := lifetime / 5
+	if margin > maxTokenRefreshMargin {
+		margin = maxTokenRefreshMargin
+	}
+
+	return issuedAt.Add(lifetime - margin)
+}
+
+// getTokenKey returns the repo a request is about, or its path for the requests which are not about a repo.
+func getTokenKey(reqURL *url.URL) string {
+	path := reqURL.Path
+
+	for _, route := range []string{"/manifests/", "/blobs/", "/tags/", "/referrers/", "/_oras/"} {
+		if idx := strings.LastIndex(path, route); idx != -1 {
+			return strings.TrimPrefix(path[:idx], "/v2/")
+		}
+	}
+
+	return path
+}
+
+// parseBearerChallenge parses the parameters of a WWW-Authenticate bearer challenge,
+// e.g. Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull".
+func parseBearerChallenge(header string) (map[string]string, bool) {
+	scheme, params, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "bearer") {
+		return nil, false
+	}
+
+	challenge := map[string]string{}
+
+	for params != "" {
+		var name, value string
+
+		name, params, found = strings.Cut(strings.TrimLeft(params, " ,"), "=")
+		if !found {
+			break
+		}
+
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+
+		challenge[strings.ToLower(strings.TrimSpace(name))] = value
+	}
+
+	if challenge["realm"] == "" {
+		return nil, false
+	}
+
+	return challenge, true
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, in seconds or a HTTP date, or defaultDelay.
+func parseRetryAfter(header string, defaultDelay time.Duration) time.Duration {
+	if header == "" {
+		return defaultDelay
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+
+		return 0
+	}
+
+	return defaultDelay
+}
+
+// parseRateLimitRemaining returns the remaining requests quota of the RateLimit-Remaining header,
+// e.g. "76;w=21600" on Docker Hub, or of the X-RateLimit-Remaining header.
+func parseRateLimitRemaining(header http.Header) (int64, bool) {
+	value := header.Get(rateLimitRemainingName)
+	if value == "" {
+		value = header.Get("X-" + rateLimitRemainingName)
+	}
+
+	if value == "" {
+		return 0, false
+	}
+
+	value, _, _ = strings.Cut(value, ";")
+
+	remaining, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return remaining, true
+}
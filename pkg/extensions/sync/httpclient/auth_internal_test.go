@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/log"
+)
+
+func TestAuthTransport(t *testing.T) {
+	Convey("Test bearer token auth and rate limits", t, func() {
+		var tokensIssued, rateLimited atomic.Int32
+
+		var server *httptest.Server
+
+		server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/token":
+				user, pass, ok := req.BasicAuth()
+				if !ok || user != "user" || pass != "pass" || req.URL.Query().Get("scope") != "repository:repo:pull" {
+					resp.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				// the token expires right away, so that it's refreshed before each request
+				token := tokensIssued.Add(1)
+				fmt.Fprintf(resp, `{"token":"token%d","expires_in":1}`, token)
+			case "/v2/repo/tags/list":
+				if req.Header.Get("Authorization") != fmt.Sprintf("Bearer token%d", tokensIssued.Load()) {
+					resp.Header().Set("WWW-Authenticate",
+						fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:repo:pull"`, server.URL))
+					resp.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				if rateLimited.Add(1) == 1 {
+					resp.Header().Set("Retry-After", "1")
+					resp.WriteHeader(http.StatusTooManyRequests)
+
+					return
+				}
+
+				resp.Header().Set("RateLimit-Remaining", "76;w=21600")
+				fmt.Fprint(resp, `{"name":"repo","tags":["latest"]}`)
+			default:
+				resp.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var remaining atomic.Int64
+
+		client, err := New(Config{
+			URL:         server.URL,
+			Username:    "user",
+			Password:    "pass",
+			TLSVerify:   false,
+			OnRateLimit: func(quota int64) { remaining.Store(quota) },
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		var tags struct {
+			Tags []string `json:"tags"`
+		}
+
+		_, _, statusCode, err := client.MakeGetRequest(context.Background(), &tags, "application/json",
+			"v2", "repo", "tags", "list")
+		So(err, ShouldBeNil)
+		So(statusCode, ShouldEqual, http.StatusOK)
+		So(tags.Tags, ShouldResemble, []string{"latest"})
+		So(tokensIssued.Load(), ShouldEqual, 1)
+		So(rateLimited.Load(), ShouldEqual, 2)
+		So(remaining.Load(), ShouldEqual, 76)
+
+		// the token is refreshed before it expires, without waiting for a challenge
+		_, _, statusCode, err = client.MakeGetRequest(context.Background(), &tags, "application/json",
+			"v2", "repo", "tags", "list")
+		So(err, ShouldBeNil)
+		So(statusCode, ShouldEqual, http.StatusOK)
+		So(tokensIssued.Load(), ShouldEqual, 2)
+		So(rateLimited.Load(), ShouldEqual, 3)
+	})
+
+	Convey("Test parsing auth and rate limit headers", t, func() {
+		challenge, ok := parseBearerChallenge(
+			`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull"`)
+		So(ok, ShouldBeTrue)
+		So(challenge, ShouldResemble, map[string]string{
+			"realm":   "https://auth.docker.io/token",
+			"service": "registry.docker.io",
+			"scope":   "repository:library/alpine:pull",
+		})
+
+		_, ok = parseBearerChallenge(`Basic realm="zot"`)
+		So(ok, ShouldBeFalse)
+
+		_, ok = parseBearerChallenge(`Bearer service="registry.docker.io"`)
+		So(ok, ShouldBeFalse)
+
+		So(parseRetryAfter("", time.Second), ShouldEqual, time.Second)
+		So(parseRetryAfter("10", time.Second), ShouldEqual, 10*time.Second)
+		So(parseRetryAfter("soon", time.Second), ShouldEqual, time.Second)
+		So(parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), time.Second), ShouldEqual, 0)
+
+		header := http.Header{}
+		_, ok = parseRateLimitRemaining(header)
+		So(ok, ShouldBeFalse)
+
+		header.Set("X-RateLimit-Remaining", "42")
+		remaining, ok := parseRateLimitRemaining(header)
+		So(ok, ShouldBeTrue)
+		So(remaining, ShouldEqual, 42)
+
+		reqURL, err := url.Parse("https://registry.io/v2/library/alpine/manifests/latest")
+		So(err, ShouldBeNil)
+		So(getTokenKey(reqURL), ShouldEqual, "library/alpine")
+
+		reqURL, err = url.Parse("https://registry.io/v2/_catalog")
+		So(err, ShouldBeNil)
+		So(getTokenKey(reqURL), ShouldEqual, "/v2/_catalog")
+	})
+}
@@ -2,11 +2,18 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 
+	godigest "github.com/opencontainers/go-digest"
+	"golang.org/x/time/rate"
+
+	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/common"
 	"zotregistry.io/zot/pkg/log"
 )
@@ -17,6 +24,10 @@ type Config struct {
 	Password  string
 	CertDir   string
 	TLSVerify bool
+	// the responses are read at the rates of these limiters
+	RateLimiters []*rate.Limiter
+	// called with the remaining requests quota reported by the upstream registry rate limit headers
+	OnRateLimit func(remaining int64)
 }
 
 type Client struct {
@@ -66,6 +77,12 @@ func (httpClient *Client) SetConfig(config Config) error {
 		return err
 	}
 
+	if len(config.RateLimiters) > 0 {
+		client.Transport = throttledTransport{transport: client.Transport, limiters: config.RateLimiters}
+	}
+
+	client.Transport = newAuthTransport(client.Transport, config, httpClient.log)
+
 	httpClient.client = client
 	httpClient.config = &config
 
@@ -130,3 +147,60 @@ func (httpClient *Client) MakeGetRequest(ctx context.Context, resultPtr interfac
 
 	return body, mediaType, statusCode, err
 }
+
+// GetBlob returns the content of an upstream blob from offset, using a range request, and the offset it starts at,
+// which is 0 if the upstream registry doesn't support range requests.
+func (httpClient *Client) GetBlob(ctx context.Context, repo string, digest godigest.Digest, offset int64,
+) (io.ReadCloser, int64, error) {
+	httpClient.lock.RLock()
+	blobURL := httpClient.url.JoinPath("v2", repo, "blobs", digest.String())
+	client := httpClient.client
+	config := httpClient.config
+	httpClient.lock.RUnlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, blobURL.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if config.Username != "" && config.Password != "" {
+		req.SetBasicAuth(config.Username, config.Password)
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && getContentRangeStart(resp.Header) == offset:
+		return resp.Body, offset, nil
+	case resp.StatusCode == http.StatusOK:
+		return resp.Body, 0, nil
+	default:
+		resp.Body.Close()
+
+		return nil, 0, fmt.Errorf("%w: %s returned status code %d", zerr.ErrSyncBlobDownload, blobURL, resp.StatusCode)
+	}
+}
+
+// getContentRangeStart returns the start of a Content-Range header, e.g. "bytes 100-199/200", -1 if it's invalid.
+func getContentRangeStart(header http.Header) int64 {
+	contentRange, found := strings.CutPrefix(header.Get("Content-Range"), "bytes ")
+	if !found {
+		return -1
+	}
+
+	start, _, _ := strings.Cut(contentRange, "-")
+
+	offset, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+
+	return offset
+}
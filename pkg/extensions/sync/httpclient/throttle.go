@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// the largest chunk read at once from a throttled reader, so that the transfers are smooth.
+const maxRateLimitBurst = 1024 * 1024
+
+// NewRateLimiter returns a limiter of the download rate, in bytes per second, nil if the rate is not limited.
+func NewRateLimiter(bytesPerSecond int64) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	burst := bytesPerSecond
+	if burst > maxRateLimitBurst {
+		burst = maxRateLimitBurst
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(burst))
+}
+
+type throttledReader struct {
+	ctx      context.Context //nolint: containedctx
+	reader   io.ReadCloser
+	limiters []*rate.Limiter
+}
+
+// NewThrottledReader returns a reader waiting on each of the limiters, so that reading doesn't exceed their rates.
+// A limiter can be shared by multiple readers, e.g. the global limit of the sync extension.
+func NewThrottledReader(ctx context.Context, reader io.ReadCloser, limiters ...*rate.Limiter) io.ReadCloser {
+	activeLimiters := []*rate.Limiter{}
+
+	for _, limiter := range limiters {
+		if limiter != nil {
+			activeLimiters = append(activeLimiters, limiter)
+		}
+	}
+
+	if len(activeLimiters) == 0 {
+		return reader
+	}
+
+	return &throttledReader{ctx: ctx, reader: reader, limiters: activeLimiters}
+}
+
+func (tr *throttledReader) Read(buf []byte) (int, error) {
+	for _, limiter := range tr.limiters {
+		if len(buf) > limiter.Burst() {
+			buf = buf[:limiter.Burst()]
+		}
+	}
+
+	n, err := tr.reader.Read(buf)
+	if n > 0 {
+		for _, limiter := range tr.limiters {
+			if waitErr := limiter.WaitN(tr.ctx, n); waitErr != nil {
+				return n, waitErr
+			}
+		}
+	}
+
+	return n, err
+}
+
+func (tr *throttledReader) Close() error {
+	return tr.reader.Close()
+}
+
+type throttledTransport struct {
+	transport http.RoundTripper
+	limiters  []*rate.Limiter
+}
+
+func (tt throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := tt.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Body = NewThrottledReader(req.Context(), resp.Body, tt.limiters...)
+
+	return resp, nil
+}
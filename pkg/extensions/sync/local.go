@@ -49,20 +49,15 @@ func NewLocalRegistry(storeController storage.StoreController, metaDB mTypes.Met
 
 func (registry *LocalRegistry) CanSkipImage(repo, tag string, imageDigest digest.Digest) (bool, error) {
 	// check image already synced
-	imageStore := registry.storeController.GetImageStore(repo)
-
-	_, localImageManifestDigest, _, err := imageStore.GetImageManifest(repo, tag)
+	localImageManifestDigest, err := registry.GetImageDigest(repo, tag)
 	if err != nil {
-		if errors.Is(err, zerr.ErrRepoNotFound) || errors.Is(err, zerr.ErrManifestNotFound) {
-			return false, nil
-		}
-
-		registry.log.Error().Str("errorType", common.TypeOf(err)).Str("repo", repo).Str("reference", tag).
-			Err(err).Msg("couldn't get local image manifest")
-
 		return false, err
 	}
 
+	if localImageManifestDigest == "" {
+		return false, nil
+	}
+
 	if localImageManifestDigest != imageDigest {
 		registry.log.Info().Str("repo", repo).Str("reference", tag).
 			Str("localDigest", localImageManifestDigest.String()).
@@ -75,6 +70,24 @@ func (registry *LocalRegistry) CanSkipImage(repo, tag string, imageDigest digest
 	return true, nil
 }
 
+func (registry *LocalRegistry) GetImageDigest(repo, reference string) (digest.Digest, error) {
+	imageStore := registry.storeController.GetImageStore(repo)
+
+	_, manifestDigest, _, err := imageStore.GetImageManifest(repo, reference)
+	if err != nil {
+		if errors.Is(err, zerr.ErrRepoNotFound) || errors.Is(err, zerr.ErrManifestNotFound) {
+			return "", nil
+		}
+
+		registry.log.Error().Str("errorType", common.TypeOf(err)).Str("repo", repo).Str("reference", reference).
+			Err(err).Msg("couldn't get local image manifest")
+
+		return "", err
+	}
+
+	return manifestDigest, nil
+}
+
 func (registry *LocalRegistry) GetContext() *types.SystemContext {
 	return registry.tempStorage.GetContext()
 }
@@ -85,8 +98,6 @@ func (registry *LocalRegistry) GetImageReference(repo, reference string) (types.
 
 // finalize a syncing image.
 func (registry *LocalRegistry) CommitImage(imageReference types.ImageReference, repo, reference string) error {
-	imageStore := registry.storeController.GetImageStore(repo)
-
 	tempImageStore := getImageStoreFromImageReference(imageReference, repo, reference)
 
 	defer os.RemoveAll(tempImageStore.RootDir())
@@ -94,13 +105,31 @@ func (registry *LocalRegistry) CommitImage(imageReference types.ImageReference,
 	registry.log.Info().Str("syncTempDir", path.Join(tempImageStore.RootDir(), repo)).Str("reference", reference).
 		Msg("pushing synced local image to local registry")
 
+	return registry.copyImage(tempImageStore, repo, reference, repo, reference)
+}
+
+// CopyImage copies an image already synced into a local repo to another local repo or tag, so that the images
+// synced to multiple destinations are only downloaded once.
+func (registry *LocalRegistry) CopyImage(srcRepo, srcReference, destRepo, destReference string) error {
+	registry.log.Info().Str("image", fmt.Sprintf("%s:%s", srcRepo, srcReference)).
+		Str("destination", fmt.Sprintf("%s:%s", destRepo, destReference)).Msg("copying synced image")
+
+	return registry.copyImage(registry.storeController.GetImageStore(srcRepo), srcRepo, srcReference,
+		destRepo, destReference)
+}
+
+func (registry *LocalRegistry) copyImage(srcImageStore storageTypes.ImageStore, srcRepo, srcReference,
+	repo, reference string,
+) error {
+	imageStore := registry.storeController.GetImageStore(repo)
+
 	var lockLatency time.Time
 
-	manifestBlob, manifestDigest, mediaType, err := tempImageStore.GetImageManifest(repo, reference)
+	manifestBlob, manifestDigest, mediaType, err := srcImageStore.GetImageManifest(srcRepo, srcReference)
 	if err != nil {
 		registry.log.Error().Str("errorType", common.TypeOf(err)).
-			Err(err).Str("dir", path.Join(tempImageStore.RootDir(), repo)).Str("repo", repo).Str("reference", reference).
-			Msg("couldn't find synced manifest in temporary sync dir")
+			Err(err).Str("dir", path.Join(srcImageStore.RootDir(), srcRepo)).Str("repo", srcRepo).
+			Str("reference", srcReference).Msg("couldn't find synced manifest")
 
 		return err
 	}
@@ -108,7 +137,7 @@ func (registry *LocalRegistry) CommitImage(imageReference types.ImageReference,
 	// is image manifest
 	switch mediaType {
 	case ispec.MediaTypeImageManifest:
-		if err := registry.copyManifest(repo, manifestBlob, reference, tempImageStore); err != nil {
+		if err := registry.copyManifest(srcRepo, repo, manifestBlob, reference, srcImageStore); err != nil {
 			if errors.Is(err, zerr.ErrImageLintAnnotations) {
 				registry.log.Error().Str("errorType", common.TypeOf(err)).
 					Err(err).Msg("couldn't upload manifest because of missing annotations")
@@ -124,27 +153,27 @@ func (registry *LocalRegistry) CommitImage(imageReference types.ImageReference,
 
 		if err := json.Unmarshal(manifestBlob, &indexManifest); err != nil {
 			registry.log.Error().Str("errorType", common.TypeOf(err)).
-				Err(err).Str("dir", path.Join(tempImageStore.RootDir(), repo)).
+				Err(err).Str("dir", path.Join(srcImageStore.RootDir(), srcRepo)).
 				Msg("invalid JSON")
 
 			return err
 		}
 
 		for _, manifest := range indexManifest.Manifests {
-			tempImageStore.RLock(&lockLatency)
-			manifestBuf, err := tempImageStore.GetBlobContent(repo, manifest.Digest)
-			tempImageStore.RUnlock(&lockLatency)
+			srcImageStore.RLockRepo(srcRepo, &lockLatency)
+			manifestBuf, err := srcImageStore.GetBlobContent(srcRepo, manifest.Digest)
+			srcImageStore.RUnlockRepo(srcRepo, &lockLatency)
 
 			if err != nil {
 				registry.log.Error().Str("errorType", common.TypeOf(err)).
-					Err(err).Str("dir", path.Join(tempImageStore.RootDir(), repo)).Str("digest", manifest.Digest.String()).
+					Err(err).Str("dir", path.Join(srcImageStore.RootDir(), srcRepo)).Str("digest", manifest.Digest.String()).
 					Msg("couldn't find manifest which is part of an image index")
 
 				return err
 			}
 
-			if err := registry.copyManifest(repo, manifestBuf, manifest.Digest.String(),
-				tempImageStore); err != nil {
+			if err := registry.copyManifest(srcRepo, repo, manifestBuf, manifest.Digest.String(),
+				srcImageStore); err != nil {
 				if errors.Is(err, zerr.ErrImageLintAnnotations) {
 					registry.log.Error().Str("errorType", common.TypeOf(err)).
 						Err(err).Msg("couldn't upload manifest because of missing annotations")
@@ -180,8 +209,8 @@ func (registry *LocalRegistry) CommitImage(imageReference types.ImageReference,
 	return nil
 }
 
-func (registry *LocalRegistry) copyManifest(repo string, manifestContent []byte, reference string,
-	tempImageStore storageTypes.ImageStore,
+func (registry *LocalRegistry) copyManifest(srcRepo, repo string, manifestContent []byte, reference string,
+	srcImageStore storageTypes.ImageStore,
 ) error {
 	imageStore := registry.storeController.GetImageStore(repo)
 
@@ -191,7 +220,7 @@ func (registry *LocalRegistry) copyManifest(repo string, manifestContent []byte,
 
 	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
 		registry.log.Error().Str("errorType", common.TypeOf(err)).
-			Err(err).Str("dir", path.Join(tempImageStore.RootDir(), repo)).
+			Err(err).Str("dir", path.Join(srcImageStore.RootDir(), srcRepo)).
 			Msg("invalid JSON")
 
 		return err
@@ -202,13 +231,13 @@ func (registry *LocalRegistry) copyManifest(repo string, manifestContent []byte,
 			continue
 		}
 
-		err = registry.copyBlob(repo, blob.Digest, blob.MediaType, tempImageStore)
+		err = registry.copyBlob(srcRepo, repo, blob.Digest, blob.MediaType, srcImageStore)
 		if err != nil {
 			return err
 		}
 	}
 
-	err = registry.copyBlob(repo, manifest.Config.Digest, manifest.Config.MediaType, tempImageStore)
+	err = registry.copyBlob(srcRepo, repo, manifest.Config.Digest, manifest.Config.MediaType, srcImageStore)
 	if err != nil {
 		return err
 	}
@@ -239,8 +268,8 @@ func (registry *LocalRegistry) copyManifest(repo string, manifestContent []byte,
 }
 
 // Copy a blob from one image store to another image store.
-func (registry *LocalRegistry) copyBlob(repo string, blobDigest digest.Digest, blobMediaType string,
-	tempImageStore storageTypes.ImageStore,
+func (registry *LocalRegistry) copyBlob(srcRepo, repo string, blobDigest digest.Digest, blobMediaType string,
+	srcImageStore storageTypes.ImageStore,
 ) error {
 	imageStore := registry.storeController.GetImageStore(repo)
 	if found, _, _ := imageStore.CheckBlob(repo, blobDigest); found {
@@ -248,10 +277,10 @@ func (registry *LocalRegistry) copyBlob(repo string, blobDigest digest.Digest, b
 		return nil
 	}
 
-	blobReadCloser, _, err := tempImageStore.GetBlob(repo, blobDigest, blobMediaType)
+	blobReadCloser, _, err := srcImageStore.GetBlob(srcRepo, blobDigest, blobMediaType)
 	if err != nil {
 		registry.log.Error().Str("errorType", common.TypeOf(err)).Err(err).
-			Str("dir", path.Join(tempImageStore.RootDir(), repo)).
+			Str("dir", path.Join(srcImageStore.RootDir(), srcRepo)).
 			Str("blob digest", blobDigest.String()).Str("media type", blobMediaType).
 			Msg("couldn't read blob")
 
@@ -283,7 +312,7 @@ func getImageStoreFromImageReference(imageReference types.ImageReference, repo,
 
 	tempImageStore := local.NewImageStore(tempRootDir, false, false,
 		storageConstants.DefaultGCDelay, storageConstants.DefaultUntaggedImgeRetentionDelay,
-		false, false, log.Logger{}, metrics, nil, nil)
+		false, false, false, log.Logger{}, metrics, nil, nil)
 
 	return tempImageStore
 }
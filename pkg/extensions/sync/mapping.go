@@ -0,0 +1,218 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"regexp"
+	"strings"
+
+	zerr "zotregistry.io/zot/errors"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	"zotregistry.io/zot/pkg/log"
+)
+
+/* NamespaceMapper rewrites the upstream repos into local namespaces, and the local repos back into upstream repos
+for on demand sync, using templated mapping rules, the wildcards of the source are replaced in order by the ones
+of the destination, '*' matches a path element and '**' matches any number of path elements
+eg: "mappings": [
+	{
+		"source": "docker.io/library/*",
+		"destination": "mirrors/dockerhub/*"
+	}
+]
+The source can be prefixed with the upstream registry, without the transport, to only map the repos of this registry.
+*/
+
+type NamespaceMapper struct {
+	rules []mappingRule
+	// the registry URLs, without the transport, prefixing the host qualified sources
+	upstreams []string
+}
+
+type mappingRule struct {
+	source      *mappingTemplate
+	destination *mappingTemplate
+	// the source is prefixed with an upstream registry
+	qualified bool
+}
+
+// mappingTemplate is a repo path with wildcards, which are captured when matching a repo.
+type mappingTemplate struct {
+	regexp *regexp.Regexp
+	// the path between the wildcards
+	parts     []string
+	wildcards []string
+}
+
+func NewNamespaceMapper(mappings []syncconf.MappingConfig, urls []string, log log.Logger) NamespaceMapper {
+	mapper := NamespaceMapper{}
+
+	for _, url := range urls {
+		mapper.upstreams = append(mapper.upstreams, strings.Trim(StripRegistryTransport(url), "/"))
+	}
+
+	for _, mapping := range mappings {
+		rule, err := newMappingRule(mapping)
+		if err != nil {
+			log.Error().Err(err).Str("source", mapping.Source).Str("destination", mapping.Destination).
+				Msg("invalid sync mapping rule, skipping it...")
+
+			continue
+		}
+
+		mapper.rules = append(mapper.rules, rule)
+	}
+
+	return mapper
+}
+
+func newMappingRule(mapping syncconf.MappingConfig) (mappingRule, error) {
+	source := parseMappingTemplate(mapping.Source)
+	destination := parseMappingTemplate(mapping.Destination)
+
+	if strings.Trim(mapping.Source, "/") == "" || strings.Trim(mapping.Destination, "/") == "" ||
+		strings.Join(source.wildcards, " ") != strings.Join(destination.wildcards, " ") {
+		return mappingRule{}, zerr.ErrSyncInvalidMapping
+	}
+
+	return mappingRule{
+		source:      source,
+		destination: destination,
+		qualified:   isHostQualified(strings.Trim(mapping.Source, "/")),
+	}, nil
+}
+
+func parseMappingTemplate(template string) *mappingTemplate {
+	tmpl := &mappingTemplate{}
+
+	var pattern, part strings.Builder
+
+	template = strings.Trim(template, "/")
+
+	for idx := 0; idx < len(template); idx++ {
+		if template[idx] != '*' {
+			part.WriteByte(template[idx])
+
+			continue
+		}
+
+		wildcard := "*"
+		if idx+1 < len(template) && template[idx+1] == '*' {
+			wildcard = "**"
+			idx++
+		}
+
+		tmpl.parts = append(tmpl.parts, part.String())
+		tmpl.wildcards = append(tmpl.wildcards, wildcard)
+		pattern.WriteString(regexp.QuoteMeta(part.String()))
+
+		if wildcard == "**" {
+			pattern.WriteString("(.+)")
+		} else {
+			pattern.WriteString("([^/]+)")
+		}
+
+		part.Reset()
+	}
+
+	tmpl.parts = append(tmpl.parts, part.String())
+	pattern.WriteString(regexp.QuoteMeta(part.String()))
+
+	tmpl.regexp = regexp.MustCompile("^" + pattern.String() + "$")
+
+	return tmpl
+}
+
+// match returns the values of the wildcards if the repo matches the template.
+func (tmpl *mappingTemplate) match(repo string) ([]string, bool) {
+	matches := tmpl.regexp.FindStringSubmatch(repo)
+	if matches == nil {
+		return nil, false
+	}
+
+	return matches[1:], true
+}
+
+// render returns the repo path with the wildcards replaced by values.
+func (tmpl *mappingTemplate) render(values []string) string {
+	var repo strings.Builder
+
+	for idx, value := range values {
+		repo.WriteString(tmpl.parts[idx])
+		repo.WriteString(value)
+	}
+
+	repo.WriteString(tmpl.parts[len(tmpl.parts)-1])
+
+	return repo.String()
+}
+
+// Map returns the local repo of an upstream repo, false if no mapping rule matches it.
+func (mapper NamespaceMapper) Map(repo string) (string, bool) {
+	repo = strings.Trim(repo, "/")
+
+	for _, rule := range mapper.rules {
+		upstreamRepos := []string{repo}
+		if rule.qualified {
+			upstreamRepos = mapper.getQualifiedRepos(repo)
+		}
+
+		for _, upstreamRepo := range upstreamRepos {
+			if values, ok := rule.source.match(upstreamRepo); ok {
+				return rule.destination.render(values), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Unmap returns the upstream repo of a local repo, false if it's not mapped from this registry.
+func (mapper NamespaceMapper) Unmap(localRepo string) (string, bool) {
+	localRepo = strings.Trim(localRepo, "/")
+
+	for _, rule := range mapper.rules {
+		values, ok := rule.destination.match(localRepo)
+		if !ok {
+			continue
+		}
+
+		source := rule.source.render(values)
+		if !rule.qualified {
+			return source, true
+		}
+
+		// the source may be qualified by another registry
+		for _, upstream := range mapper.upstreams {
+			if repo, found := strings.CutPrefix(source, upstream+"/"); found && repo != "" {
+				return repo, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// HasRules returns whether any mapping rule is configured.
+func (mapper NamespaceMapper) HasRules() bool {
+	return len(mapper.rules) > 0
+}
+
+// getQualifiedRepos returns the repo prefixed by each registry URL.
+func (mapper NamespaceMapper) getQualifiedRepos(repo string) []string {
+	repos := []string{}
+
+	for _, upstream := range mapper.upstreams {
+		repos = append(repos, upstream+"/"+repo)
+	}
+
+	return repos
+}
+
+// isHostQualified returns whether the first path element of a repo is a registry host, eg: docker.io/library/alpine.
+func isHostQualified(repo string) bool {
+	host, _, found := strings.Cut(repo, "/")
+
+	return found && (strings.ContainsAny(host, ".:") || host == "localhost")
+}
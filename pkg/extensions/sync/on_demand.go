@@ -9,8 +9,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/containers/common/pkg/retry"
-
 	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/common"
 	"zotregistry.io/zot/pkg/log"
@@ -135,9 +133,9 @@ func (onDemand *BaseOnDemand) syncImage(ctx context.Context, repo, reference str
 				continue
 			}
 
-			retryOptions := service.GetRetryOptions()
+			retryPolicy := service.GetRetryPolicy()
 
-			if retryOptions.MaxRetry > 0 {
+			if retryPolicy.MaxRetries > 0 {
 				// retry in background
 				go func(service Service) {
 					// remove image after syncing
@@ -150,14 +148,14 @@ func (onDemand *BaseOnDemand) syncImage(ctx context.Context, repo, reference str
 					onDemand.log.Info().Str("repo", repo).Str(reference, "reference").Str("err", err.Error()).
 						Msg("sync routine: starting routine to copy image, because of error")
 
-					time.Sleep(retryOptions.Delay)
+					time.Sleep(retryPolicy.GetDelay(0))
 
 					// retrying in background, can't use the same context which should be cancelled by now.
-					if err = retry.RetryIfNecessary(context.Background(), func() error {
+					if err = retryPolicy.RetryIfNecessary(context.Background(), func() error {
 						err := service.SyncImage(context.Background(), repo, reference)
 
 						return err
-					}, retryOptions); err != nil {
+					}, onDemand.log); err != nil {
 						onDemand.log.Error().Str("errorType", common.TypeOf(err)).Str("repo", repo).Str("reference", reference).
 							Err(err).Msg("sync routine: error while copying image")
 					}
@@ -212,6 +212,10 @@ func getCosignSBOMTagFromSubjectDigest(digestStr string) string {
 	return strings.Replace(digestStr, ":", "-", 1) + "." + remote.SBOMTagSuffix
 }
 
+func getCosignAttestationTagFromSubjectDigest(digestStr string) string {
+	return strings.Replace(digestStr, ":", "-", 1) + "." + remote.AttestationTagSuffix
+}
+
 func getCosignTagsFromSubjectDigest(digestStr string) []string {
 	var cosignTags []string
 
@@ -219,14 +223,17 @@ func getCosignTagsFromSubjectDigest(digestStr string) []string {
 	cosignTags = append(cosignTags, getCosignSignatureTagFromSubjectDigest(digestStr))
 	// sbom tag
 	cosignTags = append(cosignTags, getCosignSBOMTagFromSubjectDigest(digestStr))
+	// attestation tag
+	cosignTags = append(cosignTags, getCosignAttestationTagFromSubjectDigest(digestStr))
 
 	return cosignTags
 }
 
-// this function will check if tag is a cosign tag (signature or sbom).
+// this function will check if tag is a cosign tag (signature, sbom or attestation).
 func IsCosignTag(tag string) bool {
 	if strings.HasPrefix(tag, "sha256-") &&
-		(strings.HasSuffix(tag, remote.SignatureTagSuffix) || strings.HasSuffix(tag, remote.SBOMTagSuffix)) {
+		(strings.HasSuffix(tag, remote.SignatureTagSuffix) || strings.HasSuffix(tag, remote.SBOMTagSuffix) ||
+			strings.HasSuffix(tag, remote.AttestationTagSuffix)) {
 		return true
 	}
 
@@ -28,16 +28,19 @@ type OciReferences struct {
 	client          *client.Client
 	storeController storage.StoreController
 	metaDB          mTypes.MetaDB
-	log             log.Logger
+	// only the referrers with these artifact types are synced, all of them if empty
+	artifactTypes []string
+	log           log.Logger
 }
 
 func NewOciReferences(httpClient *client.Client, storeController storage.StoreController,
-	metaDB mTypes.MetaDB, log log.Logger,
+	metaDB mTypes.MetaDB, artifactTypes []string, log log.Logger,
 ) OciReferences {
 	return OciReferences{
 		client:          httpClient,
 		storeController: storeController,
 		metaDB:          metaDB,
+		artifactTypes:   artifactTypes,
 		log:             log,
 	}
 }
@@ -66,7 +69,7 @@ func (ref OciReferences) canSkipReferences(localRepo, subjectDigestStr string, i
 
 	// check oci references already synced
 	if len(index.Manifests) > 0 {
-		localRefs, err := imageStore.GetReferrers(localRepo, digest, nil)
+		localRefs, err := imageStore.GetReferrers(localRepo, digest, ref.artifactTypes)
 		if err != nil {
 			if errors.Is(err, zerr.ErrManifestNotFound) {
 				return false, nil
@@ -103,6 +106,8 @@ func (ref OciReferences) SyncReferences(ctx context.Context, localRepo, remoteRe
 		return refsDigests, err
 	}
 
+	index.Manifests = filterReferrersByArtifactType(index.Manifests, ref.artifactTypes)
+
 	skipOCIRefs, err := ref.canSkipReferences(localRepo, subjectDigestStr, index)
 	if err != nil {
 		ref.log.Error().Err(err).Str("repository", localRepo).Str("subject", subjectDigestStr).
@@ -14,7 +14,10 @@ import (
 	artifactspec "github.com/oras-project/artifacts-spec/specs-go/v1"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
 
+	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/common"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	"zotregistry.io/zot/pkg/extensions/sync/constants"
 	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
 	"zotregistry.io/zot/pkg/log"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
@@ -33,21 +36,51 @@ type Reference interface {
 
 type References struct {
 	referenceList []Reference
-	log           log.Logger
+	// reference types to sync, all of them if nil
+	syncedTypes map[string]bool
+	log         log.Logger
 }
 
+// NewReferences returns the references synced according to the registry config, nil config meaning all of them.
 func NewReferences(httpClient *client.Client, storeController storage.StoreController,
-	metaDB mTypes.MetaDB, log log.Logger,
+	metaDB mTypes.MetaDB, config *syncconf.ReferencesConfig, log log.Logger,
 ) References {
 	refs := References{log: log}
 
+	var artifactTypes []string
+
+	if config != nil {
+		refs.syncedTypes = map[string]bool{}
+
+		if config.Enable == nil || *config.Enable {
+			for _, referenceType := range GetReferenceTypes() {
+				if len(config.Types) == 0 || common.Contains(config.Types, referenceType) {
+					refs.syncedTypes[referenceType] = true
+				}
+			}
+		}
+
+		artifactTypes = config.ArtifactTypes
+	}
+
 	refs.referenceList = append(refs.referenceList, NewCosignReference(httpClient, storeController, metaDB, log))
-	refs.referenceList = append(refs.referenceList, NewOciReferences(httpClient, storeController, metaDB, log))
+	refs.referenceList = append(refs.referenceList, NewOciReferences(httpClient, storeController, metaDB,
+		artifactTypes, log))
 	refs.referenceList = append(refs.referenceList, NewORASReferences(httpClient, storeController, metaDB, log))
 
 	return refs
 }
 
+// GetReferenceTypes returns the names of the reference types which can be synced.
+func GetReferenceTypes() []string {
+	return []string{constants.Cosign, constants.OCI, constants.Oras}
+}
+
+func (refs References) isSynced(referenceType string) bool {
+	return refs.syncedTypes == nil || refs.syncedTypes[referenceType]
+}
+
+// IsSigned checks every reference type, even the ones which are not synced.
 func (refs References) IsSigned(ctx context.Context, upstreamRepo, subjectDigestStr string) bool {
 	for _, ref := range refs.referenceList {
 		ok := ref.IsSigned(ctx, upstreamRepo, subjectDigestStr)
@@ -77,6 +110,10 @@ func (refs References) syncAll(ctx context.Context, localRepo, upstreamRepo,
 
 	// for each reference type(cosign/oci/oras reference)
 	for _, ref := range refs.referenceList {
+		if !refs.isSynced(ref.Name()) {
+			continue
+		}
+
 		syncedRefsDigests, err = ref.SyncReferences(ctx, localRepo, upstreamRepo, subjectDigestStr)
 		if err != nil {
 			refs.log.Error().Err(err).
@@ -104,6 +141,14 @@ func (refs References) SyncReference(ctx context.Context, localRepo, upstreamRep
 
 	var syncedRefsDigests []godigest.Digest
 
+	if !refs.isSynced(referenceType) {
+		refs.log.Info().Str("reference type", referenceType).
+			Str("image", fmt.Sprintf("%s:%s", upstreamRepo, subjectDigestStr)).
+			Msg("will not sync image referrer, filtered out by references config")
+
+		return zerr.ErrSyncReferrerNotFound
+	}
+
 	for _, ref := range refs.referenceList {
 		if ref.Name() == referenceType {
 			syncedRefsDigests, err = ref.SyncReferences(ctx, localRepo, upstreamRepo, subjectDigestStr)
@@ -215,3 +260,19 @@ func getNotationManifestsFromOCIRefs(ociRefs ispec.Index) []ispec.Descriptor {
 
 	return notaryManifests
 }
+
+func filterReferrersByArtifactType(referrers []ispec.Descriptor, artifactTypes []string) []ispec.Descriptor {
+	if len(artifactTypes) == 0 {
+		return referrers
+	}
+
+	filtered := []ispec.Descriptor{}
+
+	for _, referrer := range referrers {
+		if common.Contains(artifactTypes, referrer.ArtifactType) {
+			filtered = append(filtered, referrer)
+		}
+	}
+
+	return filtered
+}
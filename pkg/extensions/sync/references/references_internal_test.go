@@ -14,6 +14,8 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	zerr "zotregistry.io/zot/errors"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	"zotregistry.io/zot/pkg/extensions/sync/constants"
 	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
 	"zotregistry.io/zot/pkg/log"
 	"zotregistry.io/zot/pkg/storage"
@@ -74,7 +76,7 @@ func TestOci(t *testing.T) {
 			GetReferrersFn: func(repo string, digest godigest.Digest, artifactTypes []string) (ispec.Index, error) {
 				return ispec.Index{}, zerr.ErrManifestNotFound
 			},
-		}}, nil, log.NewLogger("debug", ""))
+		}}, nil, nil, log.NewLogger("debug", ""))
 
 		ok := oci.IsSigned(context.Background(), "repo", "")
 		So(ok, ShouldBeFalse)
@@ -392,3 +394,82 @@ func TestCompareArtifactRefs(t *testing.T) {
 		}
 	})
 }
+
+func TestReferencesConfig(t *testing.T) {
+	Convey("Test references config", t, func() {
+		cfg := client.Config{
+			URL:       "url",
+			TLSVerify: false,
+		}
+
+		client, err := client.New(cfg, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		Convey("all references are synced by default", func() {
+			refs := NewReferences(client, storage.StoreController{}, nil, nil, log.NewLogger("debug", ""))
+
+			for _, referenceType := range GetReferenceTypes() {
+				So(refs.isSynced(referenceType), ShouldBeTrue)
+			}
+		})
+
+		Convey("only the configured reference types are synced", func() {
+			refs := NewReferences(client, storage.StoreController{}, nil, &syncconf.ReferencesConfig{
+				Types: []string{constants.Cosign, constants.OCI},
+			}, log.NewLogger("debug", ""))
+
+			So(refs.isSynced(constants.Cosign), ShouldBeTrue)
+			So(refs.isSynced(constants.OCI), ShouldBeTrue)
+			So(refs.isSynced(constants.Oras), ShouldBeFalse)
+
+			err := refs.SyncReference(context.Background(), "repo", "repo", "digest", constants.Oras)
+			So(errors.Is(err, zerr.ErrSyncReferrerNotFound), ShouldBeTrue)
+		})
+
+		Convey("no reference is synced when disabled", func() {
+			enable := false
+
+			refs := NewReferences(client, storage.StoreController{}, nil, &syncconf.ReferencesConfig{
+				Enable: &enable,
+			}, log.NewLogger("debug", ""))
+
+			for _, referenceType := range GetReferenceTypes() {
+				So(refs.isSynced(referenceType), ShouldBeFalse)
+			}
+
+			err := refs.SyncAll(context.Background(), "repo", "repo", "digest")
+			So(err, ShouldBeNil)
+		})
+	})
+
+	Convey("Test filtering referrers by artifact type", t, func() {
+		referrers := []ispec.Descriptor{
+			{Digest: "digest1", ArtifactType: "application/vnd.cncf.notary.signature"},
+			{Digest: "digest2", ArtifactType: "application/spdx+json"},
+			{Digest: "digest3", ArtifactType: "application/vnd.example.other"},
+		}
+
+		So(filterReferrersByArtifactType(referrers, nil), ShouldResemble, referrers)
+
+		filtered := filterReferrersByArtifactType(referrers, []string{
+			"application/vnd.cncf.notary.signature", "application/spdx+json",
+		})
+		So(len(filtered), ShouldEqual, 2)
+		So(filtered[0].Digest, ShouldEqual, godigest.Digest("digest1"))
+		So(filtered[1].Digest, ShouldEqual, godigest.Digest("digest2"))
+	})
+
+	Convey("Test cosign tags", t, func() {
+		digest := "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+		cosignTags := getCosignTagsFromSubjectDigest(digest)
+		So(len(cosignTags), ShouldEqual, 3)
+
+		for _, cosignTag := range cosignTags {
+			So(IsCosignTag(cosignTag), ShouldBeTrue)
+		}
+
+		So(cosignTags[2], ShouldEndWith, ".att")
+		So(IsCosignTag("latest"), ShouldBeFalse)
+	})
+}
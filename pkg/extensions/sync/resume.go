@@ -0,0 +1,272 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/containers/image/v5/types"
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/sync/constants"
+	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
+	"zotregistry.io/zot/pkg/log"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+// the partial downloads of a repo are kept under its sync dir, across the sync sessions.
+const partialBlobsDir = "partial"
+
+var errPartialBlobInUse = errors.New("sync: partial blob is being downloaded by another sync")
+
+// the partial downloads being written, a partial download is only resumed by one sync at a time.
+var partialBlobsInUse = struct { //nolint: gochecknoglobals
+	paths map[string]bool
+	lock  sync.Mutex
+}{paths: map[string]bool{}}
+
+// blobFetcher fetches the blobs of an upstream image synced into a local repo.
+type blobFetcher struct {
+	client     *client.Client
+	imageStore storageTypes.ImageStore
+	localRepo  string
+	remoteRepo string
+	// counts the bytes downloaded from the upstream registry, nil if they're not counted
+	count func(transferred int64)
+	log   log.Logger
+}
+
+// resumableImageReference is an upstream image whose blobs are not downloaded if they're already in the local
+// storage, e.g. in another repo with dedupe enabled. The other blobs are downloaded into partial files, so that
+// an interrupted download is resumed with a range request by the next sync instead of starting from zero.
+type resumableImageReference struct {
+	types.ImageReference
+	blobs *blobFetcher
+}
+
+func newResumableImageReference(imageReference types.ImageReference, blobs *blobFetcher) types.ImageReference {
+	return resumableImageReference{ImageReference: imageReference, blobs: blobs}
+}
+
+func (ref resumableImageReference) NewImageSource(ctx context.Context, sys *types.SystemContext,
+) (types.ImageSource, error) {
+	imageSource, err := ref.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+
+	return resumableImageSource{ImageSource: imageSource, blobs: ref.blobs}, nil
+}
+
+type resumableImageSource struct {
+	types.ImageSource
+	blobs *blobFetcher
+}
+
+func (src resumableImageSource) Reference() types.ImageReference {
+	return newResumableImageReference(src.ImageSource.Reference(), src.blobs)
+}
+
+func (src resumableImageSource) GetBlob(ctx context.Context, blobInfo types.BlobInfo, cache types.BlobInfoCache,
+) (io.ReadCloser, int64, error) {
+	if blobInfo.Digest == "" {
+		return src.ImageSource.GetBlob(ctx, blobInfo, cache)
+	}
+
+	if reader, size, ok := src.blobs.getLocalBlob(blobInfo); ok {
+		return reader, size, nil
+	}
+
+	reader, err := src.blobs.download(ctx, blobInfo.Digest, blobInfo.Size)
+	if err != nil {
+		if !errors.Is(err, errPartialBlobInUse) {
+			return nil, -1, err
+		}
+
+		// the blob is being downloaded by another sync
+		return src.ImageSource.GetBlob(ctx, blobInfo, cache)
+	}
+
+	return reader, blobInfo.Size, nil
+}
+
+// getLocalBlob returns the blob from the local storage, it's found in the other repos using the dedupe cache.
+func (blobs *blobFetcher) getLocalBlob(blobInfo types.BlobInfo) (io.ReadCloser, int64, bool) {
+	ok, _, err := blobs.imageStore.CheckBlob(blobs.localRepo, blobInfo.Digest)
+	if err != nil || !ok {
+		return nil, -1, false
+	}
+
+	reader, size, err := blobs.imageStore.GetBlob(blobs.localRepo, blobInfo.Digest, blobInfo.MediaType)
+	if err != nil {
+		return nil, -1, false
+	}
+
+	blobs.log.Debug().Str("repo", blobs.localRepo).Str("digest", blobInfo.Digest.String()).
+		Msg("sync: blob already in local storage, skipping download")
+
+	return reader, size, true
+}
+
+// download returns the blob read from its partial file, then from the upstream registry from where the partial
+// file ends, the downloaded content is appended to the partial file until the blob is fully read.
+func (blobs *blobFetcher) download(ctx context.Context, digest godigest.Digest, size int64) (io.ReadCloser, error) {
+	partialDir := path.Join(blobs.imageStore.RootDir(), blobs.localRepo, constants.SyncBlobUploadDir, partialBlobsDir)
+	if err := os.MkdirAll(partialDir, storageConstants.DefaultDirPerms); err != nil {
+		return nil, err
+	}
+
+	partialPath := path.Join(partialDir, fmt.Sprintf("%s-%s", digest.Algorithm(), digest.Encoded()))
+
+	partialBlobsInUse.lock.Lock()
+
+	if partialBlobsInUse.paths[partialPath] {
+		partialBlobsInUse.lock.Unlock()
+
+		return nil, errPartialBlobInUse
+	}
+
+	partialBlobsInUse.paths[partialPath] = true
+	partialBlobsInUse.lock.Unlock()
+
+	reader, err := blobs.openPartialBlob(ctx, digest, size, partialPath)
+	if err != nil {
+		releasePartialBlob(partialPath)
+
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func (blobs *blobFetcher) openPartialBlob(ctx context.Context, digest godigest.Digest, size int64,
+	partialPath string,
+) (io.ReadCloser, error) {
+	file, err := os.OpenFile(partialPath, os.O_CREATE|os.O_RDWR, storageConstants.DefaultFilePerms)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	offset := fileInfo.Size()
+
+	var (
+		body  io.ReadCloser
+		start int64
+	)
+
+	if size > 0 && offset == size {
+		// the download completed but the blob wasn't fully read, nothing left to download
+		body, start = io.NopCloser(bytes.NewReader(nil)), offset
+	} else {
+		if size > 0 && offset > size {
+			offset = 0
+		}
+
+		body, start, err = blobs.client.GetBlob(ctx, blobs.remoteRepo, digest, offset)
+		if err != nil && offset > 0 {
+			// the partial file can't be resumed, e.g. the range is not satisfiable
+			body, start, err = blobs.client.GetBlob(ctx, blobs.remoteRepo, digest, 0)
+		}
+
+		if err != nil {
+			file.Close()
+
+			return nil, err
+		}
+	}
+
+	if start < fileInfo.Size() {
+		// the download starts again, e.g. the upstream registry doesn't support range requests
+		if err := file.Truncate(start); err != nil {
+			body.Close()
+			file.Close()
+
+			return nil, err
+		}
+	}
+
+	if start > 0 {
+		blobs.log.Info().Str("repo", blobs.remoteRepo).Str("digest", digest.String()).Int64("offset", start).
+			Msg("sync: resuming interrupted blob download")
+	}
+
+	var upstreamReader io.ReadCloser = body
+	if blobs.count != nil {
+		upstreamReader = &countedReader{reader: body, count: blobs.count}
+	}
+
+	verifier := digest.Verifier()
+	reader := io.TeeReader(io.MultiReader(
+		io.NewSectionReader(file, 0, start),
+		io.TeeReader(upstreamReader, io.NewOffsetWriter(file, start)),
+	), verifier)
+
+	return &partialBlobReader{
+		reader:   reader,
+		upstream: upstreamReader,
+		file:     file,
+		verifier: verifier,
+		path:     partialPath,
+	}, nil
+}
+
+// partialBlobReader reads a blob being downloaded into its partial file, the partial file is removed once the blob
+// is fully read, or if its content doesn't match its digest.
+type partialBlobReader struct {
+	reader   io.Reader
+	upstream io.ReadCloser
+	file     *os.File
+	verifier godigest.Verifier
+	path     string
+	done     bool
+}
+
+func (pbr *partialBlobReader) Read(buf []byte) (int, error) {
+	n, err := pbr.reader.Read(buf)
+	if errors.Is(err, io.EOF) {
+		pbr.done = true
+
+		if !pbr.verifier.Verified() {
+			return n, zerr.ErrBadBlobDigest
+		}
+	}
+
+	return n, err
+}
+
+func (pbr *partialBlobReader) Close() error {
+	err := pbr.upstream.Close()
+
+	pbr.file.Close()
+
+	if pbr.done {
+		os.Remove(pbr.path)
+	}
+
+	releasePartialBlob(pbr.path)
+
+	return err
+}
+
+func releasePartialBlob(partialPath string) {
+	partialBlobsInUse.lock.Lock()
+	delete(partialBlobsInUse.paths, partialPath)
+	partialBlobsInUse.lock.Unlock()
+}
@@ -0,0 +1,171 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/containers/common/pkg/retry"
+
+	zerr "zotregistry.io/zot/errors"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	"zotregistry.io/zot/pkg/log"
+)
+
+// RetryPolicy retries the sync operations failing with temporary errors, the delay between retries is
+// multiplied by Backoff after each retry, up to MaxDelay if set.
+type RetryPolicy struct {
+	MaxRetries int
+	Delay      time.Duration
+	Backoff    float64
+	MaxDelay   time.Duration
+}
+
+func NewRetryPolicy(opts syncconf.RegistryConfig) *RetryPolicy {
+	policy := &RetryPolicy{}
+
+	if opts.MaxRetries != nil {
+		policy.MaxRetries = *opts.MaxRetries
+
+		if opts.RetryDelay != nil {
+			policy.Delay = *opts.RetryDelay
+		}
+
+		if opts.RetryBackoff != nil {
+			policy.Backoff = *opts.RetryBackoff
+		}
+
+		if opts.MaxRetryDelay != nil {
+			policy.MaxDelay = *opts.MaxRetryDelay
+		}
+	}
+
+	return policy
+}
+
+// GetDelay returns the delay before a retry, attempt starting from 0 for the first retry.
+func (policy *RetryPolicy) GetDelay(attempt int) time.Duration {
+	delay := policy.Delay
+
+	if policy.Backoff > 1 {
+		for i := 0; i < attempt; i++ {
+			delay = time.Duration(float64(delay) * policy.Backoff)
+
+			if policy.MaxDelay != 0 && delay >= policy.MaxDelay {
+				break
+			}
+		}
+	}
+
+	if policy.MaxDelay != 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	return delay
+}
+
+// RetryIfNecessary runs the operation, retrying it while it fails with errors worth retrying.
+func (policy *RetryPolicy) RetryIfNecessary(ctx context.Context, operation func() error, log log.Logger) error {
+	err := operation()
+
+	for attempt := 0; err != nil && retry.IsErrorRetryable(err) && attempt < policy.MaxRetries; attempt++ {
+		delay := policy.GetDelay(attempt)
+
+		log.Warn().Err(err).Str("delay", delay.String()).Int("attempt", attempt+1).Int("maxRetries", policy.MaxRetries).
+			Msg("sync: operation failed, retrying")
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+
+		err = operation()
+	}
+
+	return err
+}
+
+// quarantine skips the periodic sync of the repos which failed to sync too many consecutive times,
+// so that the other repos are synced without waiting on their retries.
+type quarantine struct {
+	failures int
+	duration time.Duration
+	repos    map[string]*quarantinedRepo
+	lock     *sync.Mutex
+	log      log.Logger
+}
+
+type quarantinedRepo struct {
+	failures int
+	until    time.Time
+}
+
+// newQuarantine returns nil if quarantining is not configured, a nil quarantine never skips a repo.
+func newQuarantine(config *syncconf.QuarantineConfig, log log.Logger) *quarantine {
+	if config == nil || config.Failures <= 0 || config.Duration <= 0 {
+		return nil
+	}
+
+	return &quarantine{
+		failures: config.Failures,
+		duration: config.Duration,
+		repos:    map[string]*quarantinedRepo{},
+		lock:     &sync.Mutex{},
+		log:      log,
+	}
+}
+
+// check returns an error if the repo is quarantined.
+func (q *quarantine) check(repo string) error {
+	if q == nil {
+		return nil
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if repoState, ok := q.repos[repo]; ok && time.Now().Before(repoState.until) {
+		q.log.Info().Str("repo", repo).Time("until", repoState.until).
+			Msg("sync: skipping repo, quarantined after repeated sync failures")
+
+		return zerr.ErrSyncRepoQuarantined
+	}
+
+	return nil
+}
+
+// record counts the consecutive failures of a repo, the repo is released after a successful sync.
+func (q *quarantine) record(repo string, err error) {
+	if q == nil {
+		return
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if err == nil {
+		delete(q.repos, repo)
+
+		return
+	}
+
+	repoState, ok := q.repos[repo]
+	if !ok {
+		repoState = &quarantinedRepo{}
+		q.repos[repo] = repoState
+	}
+
+	repoState.failures++
+
+	if repoState.failures >= q.failures {
+		repoState.failures = 0
+		repoState.until = time.Now().Add(q.duration)
+
+		q.log.Warn().Err(err).Str("repo", repo).Time("until", repoState.until).
+			Msg("sync: quarantining repo after repeated sync failures")
+	}
+}
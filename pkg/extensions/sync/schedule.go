@@ -0,0 +1,63 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"time"
+
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	syncConstants "zotregistry.io/zot/pkg/extensions/sync/constants"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+// ContentSchedule is the periodic sync of the repos matching some content rules (indexes in the registry config).
+type ContentSchedule struct {
+	Interval   time.Duration
+	Priority   scheduler.Priority
+	ContentIDs []int
+}
+
+// GetContentSchedules groups the content rules of a registry by polling interval: the rules without their own
+// pollInterval are synced together with the registry pollInterval, the other ones are synced separately.
+func GetContentSchedules(opts syncconf.RegistryConfig) []ContentSchedule {
+	schedules := []ContentSchedule{}
+
+	defaultSchedule := ContentSchedule{
+		Interval:   opts.PollInterval,
+		Priority:   scheduler.MediumPriority,
+		ContentIDs: []int{},
+	}
+
+	for contentID, content := range opts.Content {
+		if content.PollInterval == 0 {
+			defaultSchedule.ContentIDs = append(defaultSchedule.ContentIDs, contentID)
+
+			continue
+		}
+
+		schedules = append(schedules, ContentSchedule{
+			Interval:   content.PollInterval,
+			Priority:   GetPriority(content.Priority),
+			ContentIDs: []int{contentID},
+		})
+	}
+
+	if defaultSchedule.Interval != 0 && len(defaultSchedule.ContentIDs) > 0 {
+		schedules = append([]ContentSchedule{defaultSchedule}, schedules...)
+	}
+
+	return schedules
+}
+
+// GetPriority returns the scheduler priority of a content rule, medium if not set.
+func GetPriority(priority string) scheduler.Priority {
+	switch priority {
+	case syncConstants.LowPriority:
+		return scheduler.LowPriority
+	case syncConstants.HighPriority:
+		return scheduler.HighPriority
+	default:
+		return scheduler.MediumPriority
+	}
+}
@@ -7,14 +7,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
-	"github.com/containers/common/pkg/retry"
 	"github.com/containers/image/v5/copy"
 	"github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/time/rate"
 
 	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/common"
 	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	syncConstants "zotregistry.io/zot/pkg/extensions/sync/constants"
 	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
 	"zotregistry.io/zot/pkg/extensions/sync/references"
 	"zotregistry.io/zot/pkg/log"
@@ -27,24 +30,49 @@ type BaseService struct {
 	credentials     syncconf.CredentialsFile
 	remote          Remote
 	local           Local
-	retryOptions    *retry.RetryOptions
+	retryPolicy     *RetryPolicy
+	quarantine      *quarantine
 	contentManager  ContentManager
 	storeController storage.StoreController
 	metaDB          mTypes.MetaDB
 	repositories    []string
 	references      references.References
 	client          *client.Client
-	log             log.Logger
+	// the downloads from the upstream registry are throttled by the global and the registry rate limiters
+	rateLimiters []*rate.Limiter
+	// nil if the sync state is not tracked
+	status *registryStatus
+	// nil if the upstream signatures are not verified
+	signatures *SignatureVerifier
+	// nil if nothing is excluded
+	exclusions *Exclusions
+	events     *EventNotifier
+	log        log.Logger
 }
 
+// New returns the sync service of a registry, downloadLimiter is shared by the services of every registry
+// and nil if the global download rate is not limited, the sync state is tracked by statusTracker if not nil.
+// The sync events are sent by events, which is shared by the services of every registry, if not nil.
 func New(opts syncconf.RegistryConfig, credentialsFilepath string,
-	storeController storage.StoreController, metadb mTypes.MetaDB, log log.Logger,
+	storeController storage.StoreController, metadb mTypes.MetaDB, downloadLimiter *rate.Limiter,
+	statusTracker *StatusTracker, events *EventNotifier, log log.Logger,
 ) (Service, error) {
 	service := &BaseService{}
 
 	service.config = opts
 	service.log = log
 	service.metaDB = metadb
+	service.events = events
+
+	if statusTracker != nil {
+		service.status = statusTracker.addRegistry(opts.URLs)
+	}
+
+	for _, limiter := range []*rate.Limiter{downloadLimiter, client.NewRateLimiter(opts.MaxDownloadRate)} {
+		if limiter != nil {
+			service.rateLimiters = append(service.rateLimiters, limiter)
+		}
+	}
 
 	var err error
 
@@ -59,20 +87,26 @@ func New(opts syncconf.RegistryConfig, credentialsFilepath string,
 
 	service.credentials = credentialsFile
 
-	service.contentManager = NewContentManager(opts.Content, log)
+	service.contentManager = NewContentManager(opts.Content, NewNamespaceMapper(opts.Mappings, opts.URLs, log), log)
 	service.local = NewLocalRegistry(storeController, metadb, log)
 
-	retryOptions := &retry.RetryOptions{}
+	service.retryPolicy = NewRetryPolicy(opts)
+	service.quarantine = newQuarantine(opts.Quarantine, log)
+	service.storeController = storeController
 
-	if opts.MaxRetries != nil {
-		retryOptions.MaxRetry = *opts.MaxRetries
-		if opts.RetryDelay != nil {
-			retryOptions.Delay = *opts.RetryDelay
-		}
+	service.signatures, err = NewSignatureVerifier(opts.SignatureVerification, log)
+	if err != nil {
+		log.Error().Err(err).Msg("couldn't load the sync signature verification keys")
+
+		return nil, err
 	}
 
-	service.retryOptions = retryOptions
-	service.storeController = storeController
+	service.exclusions, err = NewExclusions(opts.Exclusions)
+	if err != nil {
+		log.Error().Err(err).Msg("couldn't load the sync exclusion rules")
+
+		return nil, err
+	}
 
 	err = service.SetNextAvailableClient()
 	if err != nil {
@@ -83,6 +117,7 @@ func New(opts syncconf.RegistryConfig, credentialsFilepath string,
 		service.client,
 		service.storeController,
 		service.metaDB,
+		service.config.References,
 		service.log,
 	)
 
@@ -109,11 +144,13 @@ func (service *BaseService) SetNextAvailableClient() error {
 		}
 
 		options := client.Config{
-			URL:       url,
-			Username:  credentials.Username,
-			Password:  credentials.Password,
-			TLSVerify: tlsVerify,
-			CertDir:   service.config.CertDir,
+			URL:          url,
+			Username:     credentials.Username,
+			Password:     credentials.Password,
+			TLSVerify:    tlsVerify,
+			CertDir:      service.config.CertDir,
+			RateLimiters: service.rateLimiters,
+			OnRateLimit:  service.status.setRateLimitRemaining,
 		}
 
 		var err error
@@ -142,8 +179,8 @@ func (service *BaseService) SetNextAvailableClient() error {
 	return nil
 }
 
-func (service *BaseService) GetRetryOptions() *retry.Options {
-	return service.retryOptions
+func (service *BaseService) GetRetryPolicy() *RetryPolicy {
+	return service.retryPolicy
 }
 
 func (service *BaseService) getNextRepoFromCatalog(lastRepo string) string {
@@ -174,15 +211,15 @@ func (service *BaseService) getNextRepoFromCatalog(lastRepo string) string {
 	return nextRepo
 }
 
-func (service *BaseService) GetNextRepo(lastRepo string) (string, error) {
+func (service *BaseService) GetNextRepo(lastRepo string, contentIDs []int) (string, error) {
 	var err error
 
 	if len(service.repositories) == 0 {
-		if err = retry.RetryIfNecessary(context.Background(), func() error {
+		if err = service.retryPolicy.RetryIfNecessary(context.Background(), func() error {
 			service.repositories, err = service.remote.GetRepositories(context.Background())
 
 			return err
-		}, service.retryOptions); err != nil {
+		}, service.log); err != nil {
 			service.log.Error().Str("errorType", common.TypeOf(err)).Str("remote registry", service.client.GetConfig().URL).
 				Err(err).Msg("error while getting repositories from remote registry")
 
@@ -198,7 +235,8 @@ func (service *BaseService) GetNextRepo(lastRepo string) (string, error) {
 			break
 		}
 
-		matches = service.contentManager.MatchesContent(lastRepo)
+		matches = service.contentManager.MatchesContentIDs(lastRepo, contentIDs) &&
+			!service.exclusions.ExcludesRepo(lastRepo)
 	}
 
 	return lastRepo, nil
@@ -212,7 +250,7 @@ func (service *BaseService) SyncReference(ctx context.Context, repo string,
 
 	remoteURL := service.client.GetConfig().URL
 
-	if len(service.config.Content) > 0 {
+	if len(service.config.Content) > 0 || len(service.config.Mappings) > 0 {
 		remoteRepo = service.contentManager.GetRepoSource(repo)
 		if remoteRepo == "" {
 			service.log.Info().Str("remote", remoteURL).Str("repo", repo).Str("subject", subjectDigestStr).
@@ -222,6 +260,13 @@ func (service *BaseService) SyncReference(ctx context.Context, repo string,
 		}
 	}
 
+	if service.exclusions.ExcludesRepo(remoteRepo) {
+		service.log.Info().Str("remote", remoteURL).Str("repo", repo).Str("subject", subjectDigestStr).
+			Str("reference type", referenceType).Msg("will not sync reference for image, repo is excluded")
+
+		return zerr.ErrSyncImageFilteredOut
+	}
+
 	service.log.Info().Str("remote", remoteURL).Str("repo", repo).Str("subject", subjectDigestStr).
 		Str("reference type", referenceType).Msg("sync: syncing reference for image")
 
@@ -234,7 +279,7 @@ func (service *BaseService) SyncImage(ctx context.Context, repo, reference strin
 
 	remoteURL := service.client.GetConfig().URL
 
-	if len(service.config.Content) > 0 {
+	if len(service.config.Content) > 0 || len(service.config.Mappings) > 0 {
 		remoteRepo = service.contentManager.GetRepoSource(repo)
 		if remoteRepo == "" {
 			service.log.Info().Str("remote", remoteURL).Str("repo", repo).Str("reference", reference).
@@ -247,21 +292,126 @@ func (service *BaseService) SyncImage(ctx context.Context, repo, reference strin
 	service.log.Info().Str("remote", remoteURL).Str("repo", repo).Str("reference", reference).
 		Msg("sync: syncing image")
 
-	manifestDigest, err := service.syncTag(ctx, repo, remoteRepo, reference)
+	return service.syncImage(ctx, []string{repo}, remoteRepo, reference)
+}
+
+// GetUpstreamBlob returns the content of a blob of a local repo from the upstream repo it's synced from.
+func (service *BaseService) GetUpstreamBlob(ctx context.Context, repo string, blobDigest digest.Digest,
+) (io.ReadCloser, error) {
+	remoteRepo := repo
+
+	if len(service.config.Content) > 0 || len(service.config.Mappings) > 0 {
+		remoteRepo = service.contentManager.GetRepoSource(repo)
+		if remoteRepo == "" {
+			return nil, zerr.ErrSyncImageFilteredOut
+		}
+	}
+
+	if service.exclusions.ExcludesRepo(remoteRepo) {
+		return nil, zerr.ErrSyncImageFilteredOut
+	}
+
+	blobReader, _, err := service.client.GetBlob(ctx, remoteRepo, blobDigest, 0)
+
+	return blobReader, err
+}
+
+// SyncUpstreamImage syncs an upstream image, or every tag of an upstream repo if tag is empty,
+// into the local repos it's synced into by the content and mapping rules.
+func (service *BaseService) SyncUpstreamImage(ctx context.Context, remoteRepo, tag string) error {
+	localRepos := service.getLocalRepos(remoteRepo)
+	if len(localRepos) == 0 || service.exclusions.ExcludesRepo(remoteRepo) {
+		service.log.Info().Str("remote", service.client.GetConfig().URL).Str("repo", remoteRepo).Str("tag", tag).
+			Msg("will not sync triggered image, filtered out by content or excluded")
+
+		return zerr.ErrSyncImageFilteredOut
+	}
+
+	if tag == "" {
+		return service.SyncRepo(ctx, remoteRepo)
+	}
+
+	tags, err := service.contentManager.FilterTags(remoteRepo, []string{tag})
 	if err != nil {
 		return err
 	}
 
-	err = service.references.SyncAll(ctx, repo, remoteRepo, manifestDigest.String())
-	if err != nil && !errors.Is(err, zerr.ErrSyncReferrerNotFound) {
+	if len(tags) == 0 {
+		return zerr.ErrSyncImageFilteredOut
+	}
+
+	service.log.Info().Str("remote", service.client.GetConfig().URL).Str("repo", remoteRepo).Str("tag", tag).
+		Msg("sync: syncing triggered image")
+
+	return service.retryPolicy.RetryIfNecessary(ctx, func() error {
+		return service.syncImage(ctx, localRepos, remoteRepo, tag)
+	}, service.log)
+}
+
+// getLocalRepos returns the local repos an upstream repo is synced into, without content rules
+// the repos which are not mapped are synced as is.
+func (service *BaseService) getLocalRepos(remoteRepo string) []string {
+	if len(service.config.Content) > 0 {
+		return service.contentManager.GetRepoDestinations(remoteRepo)
+	}
+
+	if localRepo, ok := service.contentManager.mapper.Map(remoteRepo); ok {
+		return []string{localRepo}
+	}
+
+	return []string{remoteRepo}
+}
+
+// syncImage syncs an upstream image and its references into the local repos.
+func (service *BaseService) syncImage(ctx context.Context, localRepos []string, remoteRepo, reference string,
+) error {
+	manifestDigest, syncedRepos, err := service.syncTag(ctx, localRepos, remoteRepo, reference)
+	if err != nil {
+		// images missing upstream or skipped are not sync failures
+		if !errors.Is(err, zerr.ErrManifestNotFound) && !errors.Is(err, zerr.ErrSyncImageNotSigned) &&
+			!errors.Is(err, zerr.ErrMediaTypeNotSupported) && !errors.Is(err, zerr.ErrSyncImageFilteredOut) {
+			service.status.failure(remoteRepo, err)
+		}
+
 		return err
 	}
 
+	for _, localRepo := range syncedRepos {
+		err = service.references.SyncAll(ctx, localRepo, remoteRepo, manifestDigest.String())
+		if err != nil && !errors.Is(err, zerr.ErrSyncReferrerNotFound) {
+			service.status.failure(remoteRepo, err)
+
+			return err
+		}
+	}
+
+	service.status.success(remoteRepo)
+
 	return nil
 }
 
 // sync repo periodically.
 func (service *BaseService) SyncRepo(ctx context.Context, repo string) error {
+	if err := service.quarantine.check(repo); err != nil {
+		return err
+	}
+
+	err := service.syncRepo(ctx, repo)
+	if err != nil {
+		service.status.failure(repo, err)
+	} else {
+		service.status.success(repo)
+	}
+
+	// a cancelled sync is not a failure of the repo
+	if !errors.Is(err, context.Canceled) {
+		service.quarantine.record(repo, err)
+	}
+
+	return err
+}
+
+func (service *BaseService) syncRepo(ctx context.Context, repo string) error {
 	service.log.Info().Str("repo", repo).Str("registry", service.client.GetConfig().URL).
 		Msg("sync: syncing repo")
 
@@ -269,11 +419,11 @@ func (service *BaseService) SyncRepo(ctx context.Context, repo string) error {
 
 	var tags []string
 
-	if err = retry.RetryIfNecessary(ctx, func() error {
+	if err = service.retryPolicy.RetryIfNecessary(ctx, func() error {
 		tags, err = service.remote.GetRepoTags(repo)
 
 		return err
-	}, service.retryOptions); err != nil {
+	}, service.log); err != nil {
 		service.log.Error().Str("errorType", common.TypeOf(err)).Str("repo", repo).
 			Err(err).Msg("error while getting tags for repo")
 
@@ -286,10 +436,22 @@ func (service *BaseService) SyncRepo(ctx context.Context, repo string) error {
 		return err
 	}
 
+	tags = service.exclusions.FilterTags(tags)
+
 	service.log.Info().Str("repo", repo).Msgf("sync: syncing tags %v", tags)
 
-	// apply content.destination rule
-	localRepo := service.contentManager.GetRepoDestination(repo)
+	pending := 0
+
+	for _, tag := range tags {
+		if !references.IsCosignTag(tag) {
+			pending++
+		}
+	}
+
+	service.status.setPending(repo, pending)
+
+	// apply content.destination, content.destinations and mapping rules
+	localRepos := service.getLocalRepos(repo)
 
 	for _, tag := range tags {
 		select {
@@ -304,13 +466,18 @@ func (service *BaseService) SyncRepo(ctx context.Context, repo string) error {
 
 		var manifestDigest digest.Digest
 
-		if err = retry.RetryIfNecessary(ctx, func() error {
-			manifestDigest, err = service.syncTag(ctx, localRepo, repo, tag)
+		var syncedRepos []string
+
+		if err = service.retryPolicy.RetryIfNecessary(ctx, func() error {
+			manifestDigest, syncedRepos, err = service.syncTag(ctx, localRepos, repo, tag)
 
 			return err
-		}, service.retryOptions); err != nil {
-			if errors.Is(err, zerr.ErrSyncImageNotSigned) || errors.Is(err, zerr.ErrMediaTypeNotSupported) {
-				// skip unsigned images or unsupported image mediatype
+		}, service.log); err != nil {
+			if errors.Is(err, zerr.ErrSyncImageNotSigned) || errors.Is(err, zerr.ErrMediaTypeNotSupported) ||
+				errors.Is(err, zerr.ErrSyncImageFilteredOut) {
+				// skip unsigned, excluded images or unsupported image mediatype
+				service.status.imageDone(repo)
+
 				continue
 			}
 
@@ -320,21 +487,23 @@ func (service *BaseService) SyncRepo(ctx context.Context, repo string) error {
 			return err
 		}
 
-		if manifestDigest != "" {
-			if err = retry.RetryIfNecessary(ctx, func() error {
+		for _, localRepo := range syncedRepos {
+			if err = service.retryPolicy.RetryIfNecessary(ctx, func() error {
 				err = service.references.SyncAll(ctx, localRepo, repo, manifestDigest.String())
 				if errors.Is(err, zerr.ErrSyncReferrerNotFound) {
 					return nil
 				}
 
 				return err
-			}, service.retryOptions); err != nil {
+			}, service.log); err != nil {
 				service.log.Error().Str("errorType", common.TypeOf(err)).Str("repo", repo).
 					Err(err).Msg("error while syncing tags for repo")
 
 				return err
 			}
 		}
+
+		service.status.imageDone(repo)
 	}
 
 	service.log.Info().Str("repo", repo).Msg("sync: finished syncing repo")
@@ -342,12 +511,24 @@ func (service *BaseService) SyncRepo(ctx context.Context, repo string) error {
 	return nil
 }
 
-func (service *BaseService) syncTag(ctx context.Context, localRepo, remoteRepo, tag string) (digest.Digest, error) {
+// syncTag syncs an upstream image into the local repos, the image is downloaded once and copied into the other
+// local repos, it returns the local repos holding the upstream image.
+func (service *BaseService) syncTag(ctx context.Context, localRepos []string, remoteRepo, tag string,
+) (digest.Digest, []string, error) {
+	// the excluded repos and tags are skipped before any request to the upstream registry
+	_, isDigest := parseReference(tag)
+
+	if service.exclusions.ExcludesRepo(remoteRepo) || (!isDigest && service.exclusions.ExcludesTag(tag)) {
+		service.log.Info().Str("repo", remoteRepo).Str("reference", tag).Msg("skipping excluded image")
+
+		return "", nil, zerr.ErrSyncImageFilteredOut
+	}
+
 	copyOptions := getCopyOptions(service.remote.GetContext(), service.local.GetContext())
 
 	policyContext, err := getPolicyContext(service.log)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	defer func() {
@@ -359,19 +540,27 @@ func (service *BaseService) syncTag(ctx context.Context, localRepo, remoteRepo,
 		service.log.Error().Err(err).Str("errortype", common.TypeOf(err)).
 			Str("repo", remoteRepo).Str("reference", tag).Msg("couldn't get a remote image reference")
 
-		return "", err
+		return "", nil, err
 	}
 
-	_, mediaType, manifestDigest, err := service.remote.GetManifestContent(remoteImageRef)
+	manifestContent, mediaType, manifestDigest, err := service.remote.GetManifestContent(remoteImageRef)
 	if err != nil {
 		service.log.Error().Err(err).Str("repo", remoteRepo).Str("reference", tag).
 			Msg("couldn't get upstream image manifest details")
 
-		return "", err
+		return "", nil, err
 	}
 
 	if !isSupportedMediaType(mediaType) {
-		return "", zerr.ErrMediaTypeNotSupported
+		return "", nil, zerr.ErrMediaTypeNotSupported
+	}
+
+	if err := service.exclusions.CheckImage(ctx, service.client, remoteRepo, mediaType, manifestContent); err != nil {
+		// skip the images with excluded media types or too large, before downloading them
+		service.log.Info().Err(err).Str("image", remoteImageRef.DockerReference().String()).
+			Msg("skipping excluded image")
+
+		return "", nil, err
 	}
 
 	if service.config.OnlySigned != nil && *service.config.OnlySigned && !references.IsCosignTag(tag) {
@@ -381,53 +570,186 @@ func (service *BaseService) syncTag(ctx context.Context, localRepo, remoteRepo,
 			service.log.Info().Str("image", remoteImageRef.DockerReference().String()).
 				Msg("skipping image without mandatory signature")
 
-			return "", zerr.ErrSyncImageNotSigned
+			return "", nil, zerr.ErrSyncImageNotSigned
 		}
 	}
 
-	skipImage, err := service.local.CanSkipImage(localRepo, tag, manifestDigest)
-	if err != nil {
-		service.log.Error().Err(err).Str("errortype", common.TypeOf(err)).
-			Str("repo", localRepo).Str("reference", tag).
-			Msg("couldn't check if the local image can be skipped")
+	if service.signatures != nil && !references.IsCosignTag(tag) {
+		subject := ispec.Descriptor{MediaType: mediaType, Digest: manifestDigest, Size: int64(len(manifestContent))}
+
+		if err := service.signatures.Verify(ctx, service.client, remoteRepo, subject); err != nil {
+			// skip the images which are not signed by the configured keys
+			service.log.Info().Err(err).Str("image", remoteImageRef.DockerReference().String()).
+				Msg("skipping image without a signature verified by the configured keys")
+
+			return "", nil, zerr.ErrSyncImageNotSigned
+		}
 	}
 
-	if !skipImage {
-		localImageRef, err := service.local.GetImageReference(localRepo, tag)
+	syncedRepos := []string{}
+	// the local repos and tags the upstream image is synced into
+	targets := []syncTarget{}
+
+	for _, localRepo := range localRepos {
+		localTag, ok := service.getLocalTag(localRepo, remoteRepo, tag, manifestDigest)
+		if !ok {
+			continue
+		}
+
+		skipImage, err := service.local.CanSkipImage(localRepo, localTag, manifestDigest)
 		if err != nil {
 			service.log.Error().Err(err).Str("errortype", common.TypeOf(err)).
-				Str("repo", localRepo).Str("reference", tag).Msg("couldn't get a local image reference")
+				Str("repo", localRepo).Str("reference", localTag).
+				Msg("couldn't check if the local image can be skipped")
+		}
 
-			return "", err
+		if skipImage {
+			service.log.Info().Str("image", remoteImageRef.DockerReference().String()).
+				Str("local image", fmt.Sprintf("%s:%s", localRepo, localTag)).
+				Msg("skipping image because it's already synced")
+
+			syncedRepos = append(syncedRepos, localRepo)
+
+			continue
+		}
+
+		targets = append(targets, syncTarget{repo: localRepo, tag: localTag})
+	}
+
+	if len(targets) > 0 {
+		localRepo, localTag := targets[0].repo, targets[0].tag
+
+		localImageRef, err := service.local.GetImageReference(localRepo, localTag)
+		if err != nil {
+			service.log.Error().Err(err).Str("errortype", common.TypeOf(err)).
+				Str("repo", localRepo).Str("reference", localTag).Msg("couldn't get a local image reference")
+
+			return "", nil, err
 		}
 
 		service.log.Info().Str("remote image", remoteImageRef.DockerReference().String()).
-			Str("local image", fmt.Sprintf("%s:%s", localRepo, tag)).Msg("syncing image")
+			Str("local image", fmt.Sprintf("%s:%s", localRepo, localTag)).Msg("syncing image")
+
+		var count func(transferred int64)
+		if service.status != nil {
+			count = func(transferred int64) {
+				service.status.addTransferredBytes(remoteRepo, transferred)
+			}
+		}
+
+		// the blobs of the upstream image are counted and throttled while being downloaded
+		srcImageRef := newThrottledImageReference(remoteImageRef, service.rateLimiters)
+		if count != nil {
+			srcImageRef = newCountedImageReference(srcImageRef, count)
+		}
 
-		_, err = copy.Image(ctx, policyContext, localImageRef, remoteImageRef, &copyOptions)
+		// the blobs already stored locally are not downloaded and the interrupted downloads are resumed
+		srcImageRef = newResumableImageReference(srcImageRef, &blobFetcher{
+			client:     service.client,
+			imageStore: service.storeController.GetImageStore(localRepo),
+			localRepo:  localRepo,
+			remoteRepo: remoteRepo,
+			count:      count,
+			log:        service.log,
+		})
+
+		_, err = copy.Image(ctx, policyContext, localImageRef, srcImageRef, &copyOptions)
 		if err != nil {
 			service.log.Error().Err(err).Str("errortype", common.TypeOf(err)).
 				Str("remote image", remoteImageRef.DockerReference().String()).
-				Str("local image", fmt.Sprintf("%s:%s", localRepo, tag)).Msg("coulnd't sync image")
+				Str("local image", fmt.Sprintf("%s:%s", localRepo, localTag)).Msg("coulnd't sync image")
 
-			return "", err
+			return "", nil, err
 		}
 
-		err = service.local.CommitImage(localImageRef, localRepo, tag)
+		err = service.local.CommitImage(localImageRef, localRepo, localTag)
 		if err != nil {
 			service.log.Error().Err(err).Str("errortype", common.TypeOf(err)).
-				Str("repo", localRepo).Str("reference", tag).Msg("couldn't commit image to local image store")
+				Str("repo", localRepo).Str("reference", localTag).Msg("couldn't commit image to local image store")
 
-			return "", err
+			return "", nil, err
+		}
+
+		syncedRepos = append(syncedRepos, localRepo)
+
+		// copy the image to the other destinations
+		for _, target := range targets[1:] {
+			if err := service.local.CopyImage(localRepo, localTag, target.repo, target.tag); err != nil {
+				service.log.Error().Err(err).Str("errortype", common.TypeOf(err)).
+					Str("repo", target.repo).Str("reference", target.tag).Msg("couldn't copy synced image")
+
+				return "", nil, err
+			}
+
+			if !common.Contains(syncedRepos, target.repo) {
+				syncedRepos = append(syncedRepos, target.repo)
+			}
 		}
-	} else {
-		service.log.Info().Str("image", remoteImageRef.DockerReference().String()).
-			Msg("skipping image because it's already synced")
 	}
 
 	service.log.Info().Str("image", remoteImageRef.DockerReference().String()).Msg("sync: finished syncing image")
 
-	return manifestDigest, nil
+	return manifestDigest, syncedRepos, nil
+}
+
+type syncTarget struct {
+	repo string
+	tag  string
+}
+
+// getLocalTag returns the tag an upstream image is synced as in a local repo, which depends on the collision policy
+// if the local tag holds a different image, false if the image is not synced into the local repo.
+func (service *BaseService) getLocalTag(localRepo, remoteRepo, tag string, manifestDigest digest.Digest,
+) (string, bool) {
+	localDigest, err := service.local.GetImageDigest(localRepo, tag)
+	if err != nil || localDigest == "" || localDigest == manifestDigest {
+		return tag, true
+	}
+
+	event := TagCollisionEvent{
+		Registry:       service.client.GetConfig().URL,
+		UpstreamRepo:   remoteRepo,
+		Repo:           localRepo,
+		Tag:            tag,
+		LocalDigest:    localDigest.String(),
+		UpstreamDigest: manifestDigest.String(),
+		Policy:         service.getCollisionPolicy(remoteRepo),
+	}
+
+	switch event.Policy {
+	case syncConstants.CollisionSkip:
+		service.events.notifyCollision(event)
+
+		return "", false
+	case syncConstants.CollisionKeepBoth:
+		suffix := service.config.CollisionSuffix
+		if suffix == "" {
+			suffix = syncConstants.DefaultCollisionSuffix
+		}
+
+		event.SyncedTag = tag + suffix
+		service.events.notifyCollision(event)
+
+		return event.SyncedTag, true
+	default:
+		service.events.notifyCollision(event)
+
+		return tag, true
+	}
+}
+
+// getCollisionPolicy returns the collision policy of the content rule matching an upstream repo,
+// or the registry one if the rule doesn't set it.
+func (service *BaseService) getCollisionPolicy(remoteRepo string) string {
+	if policy := service.contentManager.GetCollisionPolicy(remoteRepo); policy != "" {
+		return policy
+	}
+
+	if service.config.CollisionPolicy != "" {
+		return service.config.CollisionPolicy
+	}
+
+	return syncConstants.CollisionOverwrite
 }
 
 func (service *BaseService) ResetCatalog() {
@@ -439,5 +761,8 @@ func (service *BaseService) ResetCatalog() {
 func (service *BaseService) SetNextAvailableURL() error {
 	service.log.Info().Msg("getting available client")
 
-	return service.SetNextAvailableClient()
+	err := service.SetNextAvailableClient()
+	service.status.failure("", err)
+
+	return err
 }
@@ -0,0 +1,264 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	sigstoreSigs "github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/common"
+	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
+	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const notationTrustStore = "sync"
+
+// SignatureVerifier only admits the upstream images having a cosign or notation signature verified by
+// the configured public keys and certificates.
+type SignatureVerifier struct {
+	cosignVerifiers []sigstoreSigs.Verifier
+	// nil if no notation certificate is configured
+	notationVerifier notation.Verifier
+	log              log.Logger
+}
+
+// notationCertificates is the notation trust store of the configured certificates.
+type notationCertificates []*x509.Certificate
+
+func (certificates notationCertificates) GetCertificates(ctx context.Context, storeType truststore.Type,
+	namedStore string,
+) ([]*x509.Certificate, error) {
+	return certificates, nil
+}
+
+// NewSignatureVerifier loads the keys and certificates of the config, it returns nil if signatures are not verified.
+func NewSignatureVerifier(config *syncconf.SignatureVerificationConfig, log log.Logger,
+) (*SignatureVerifier, error) {
+	if config == nil {
+		return nil, nil //nolint: nilnil
+	}
+
+	sigVerifier := &SignatureVerifier{log: log}
+
+	for _, keyPath := range config.CosignPublicKeys {
+		keyContent, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, err := cryptoutils.UnmarshalPEMToPublicKey(keyContent)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", zerr.ErrSyncInvalidSignatureKey, keyPath, err.Error())
+		}
+
+		cosignVerifier, err := sigstoreSigs.LoadVerifier(publicKey, crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", zerr.ErrSyncInvalidSignatureKey, keyPath, err.Error())
+		}
+
+		sigVerifier.cosignVerifiers = append(sigVerifier.cosignVerifiers, cosignVerifier)
+	}
+
+	certificates := notationCertificates{}
+
+	for _, certPath := range config.NotationCertificates {
+		certContent, err := os.ReadFile(certPath)
+		if err != nil {
+			return nil, err
+		}
+
+		certs, err := cryptoutils.UnmarshalCertificatesFromPEM(certContent)
+		if err != nil || len(certs) == 0 {
+			return nil, fmt.Errorf("%w: %s", zerr.ErrSyncInvalidSignatureKey, certPath)
+		}
+
+		certificates = append(certificates, certs...)
+	}
+
+	if len(certificates) > 0 {
+		identities := config.NotationIdentities
+		if len(identities) == 0 {
+			identities = []string{"*"}
+		}
+
+		policy := &trustpolicy.Document{
+			Version: "1.0",
+			TrustPolicies: []trustpolicy.TrustPolicy{
+				{
+					Name:                  "sync",
+					RegistryScopes:        []string{"*"},
+					SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: trustpolicy.LevelStrict.Name},
+					TrustStores:           []string{string(truststore.TypeCA) + ":" + notationTrustStore},
+					TrustedIdentities:     identities,
+				},
+			},
+		}
+
+		// signatures requiring notation plugins are not verified
+		notationVerifier, err := verifier.New(policy, certificates, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		sigVerifier.notationVerifier = notationVerifier
+	}
+
+	return sigVerifier, nil
+}
+
+// Verify returns nil if one of the upstream signatures of the image is verified.
+func (sigVerifier *SignatureVerifier) Verify(ctx context.Context, httpClient *client.Client, repo string,
+	subject ispec.Descriptor,
+) error {
+	if len(sigVerifier.cosignVerifiers) > 0 {
+		err := sigVerifier.verifyCosignSignature(ctx, httpClient, repo, subject)
+		if err == nil {
+			return nil
+		}
+
+		sigVerifier.log.Debug().Err(err).Str("repo", repo).Str("digest", subject.Digest.String()).
+			Msg("sync: couldn't verify cosign signature")
+	}
+
+	if sigVerifier.notationVerifier != nil {
+		err := sigVerifier.verifyNotationSignature(ctx, httpClient, repo, subject)
+		if err == nil {
+			return nil
+		}
+
+		sigVerifier.log.Debug().Err(err).Str("repo", repo).Str("digest", subject.Digest.String()).
+			Msg("sync: couldn't verify notation signature")
+	}
+
+	return zerr.ErrSyncSignatureNotVerified
+}
+
+func (sigVerifier *SignatureVerifier) verifyCosignSignature(ctx context.Context, httpClient *client.Client,
+	repo string, subject ispec.Descriptor,
+) error {
+	var sigManifest ispec.Manifest
+
+	sigTag := strings.Replace(subject.Digest.String(), ":", "-", 1) + "." + remote.SignatureTagSuffix
+
+	_, _, _, err := httpClient.MakeGetRequest(ctx, &sigManifest, ispec.MediaTypeImageManifest,
+		"v2", repo, "manifests", sigTag)
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range sigManifest.Layers {
+		signature, err := base64.StdEncoding.DecodeString(layer.Annotations[common.CosignSigKey])
+		if err != nil || len(signature) == 0 {
+			continue
+		}
+
+		sigPayload, err := getBlob(ctx, httpClient, repo, layer.Digest)
+		if err != nil {
+			return err
+		}
+
+		for _, cosignVerifier := range sigVerifier.cosignVerifiers {
+			if cosignVerifier.VerifySignature(bytes.NewReader(signature), bytes.NewReader(sigPayload)) != nil {
+				continue
+			}
+
+			// the signature is only valid for the image whose digest it signs
+			var simpleSigning payload.SimpleContainerImage
+			if err := json.Unmarshal(sigPayload, &simpleSigning); err != nil {
+				continue
+			}
+
+			if simpleSigning.Critical.Image.DockerManifestDigest == subject.Digest.String() {
+				return nil
+			}
+		}
+	}
+
+	return zerr.ErrSyncSignatureNotVerified
+}
+
+func (sigVerifier *SignatureVerifier) verifyNotationSignature(ctx context.Context, httpClient *client.Client,
+	repo string, subject ispec.Descriptor,
+) error {
+	var referrers ispec.Index
+
+	_, _, _, err := httpClient.MakeGetRequest(ctx, &referrers, ispec.MediaTypeImageIndex,
+		"v2", repo, "referrers", subject.Digest.String())
+	if err != nil {
+		return err
+	}
+
+	artifactReference := fmt.Sprintf("%s/%s@%s", httpClient.GetHostname(), repo, subject.Digest)
+
+	for _, referrer := range referrers.Manifests {
+		if referrer.ArtifactType != common.ArtifactTypeNotation {
+			continue
+		}
+
+		var sigManifest ispec.Manifest
+
+		_, _, _, err := httpClient.MakeGetRequest(ctx, &sigManifest, ispec.MediaTypeImageManifest,
+			"v2", repo, "manifests", referrer.Digest.String())
+		if err != nil || len(sigManifest.Layers) == 0 {
+			continue
+		}
+
+		envelope, err := getBlob(ctx, httpClient, repo, sigManifest.Layers[0].Digest)
+		if err != nil {
+			return err
+		}
+
+		_, err = sigVerifier.notationVerifier.Verify(ctx, subject, envelope, notation.VerifierVerifyOptions{
+			ArtifactReference:  artifactReference,
+			SignatureMediaType: sigManifest.Layers[0].MediaType,
+			PluginConfig:       map[string]string{},
+		})
+		if err == nil {
+			return nil
+		}
+
+		sigVerifier.log.Debug().Err(err).Str("repo", repo).Str("signature", referrer.Digest.String()).
+			Msg("sync: notation signature not verified")
+	}
+
+	return zerr.ErrSyncSignatureNotVerified
+}
+
+// getBlob returns the content of an upstream blob, checking its digest.
+func getBlob(ctx context.Context, httpClient *client.Client, repo string, digest godigest.Digest) ([]byte, error) {
+	var resultPtr interface{}
+
+	// the blobs are not necessarily json, only the status code tells if the request failed
+	body, _, statusCode, err := httpClient.MakeGetRequest(ctx, resultPtr, "", "v2", repo, "blobs", digest.String())
+	if err != nil && statusCode != http.StatusOK {
+		return nil, err
+	}
+
+	if godigest.FromBytes(body) != digest {
+		return nil, fmt.Errorf("%w: %s", zerr.ErrBadBlobDigest, digest)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,298 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/types"
+
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+)
+
+// Status is the sync state of every upstream registry.
+type Status struct {
+	Registries []RegistryStatus `json:"registries"`
+}
+
+// RegistryStatus is the sync state of an upstream registry, the errors which are not specific to a repo,
+// e.g. the registry being unreachable, are only reported at the registry level.
+type RegistryStatus struct {
+	URLs             []string   `json:"urls"`
+	LastSuccess      *time.Time `json:"lastSuccess,omitempty"`
+	LastError        string     `json:"lastError,omitempty"`
+	LastErrorTime    *time.Time `json:"lastErrorTime,omitempty"`
+	ImagesPending    int        `json:"imagesPending"`
+	BytesTransferred int64      `json:"bytesTransferred"`
+	// remaining requests quota reported by the rate limit headers of the registry, if it sends them
+	RateLimitRemaining *int64       `json:"rateLimitRemaining,omitempty"`
+	Repos              []RepoStatus `json:"repos"`
+}
+
+// RepoStatus is the sync state of an upstream repo, BytesTransferred only counts the image blobs.
+type RepoStatus struct {
+	Repo             string     `json:"repo"`
+	LastSuccess      *time.Time `json:"lastSuccess,omitempty"`
+	LastError        string     `json:"lastError,omitempty"`
+	LastErrorTime    *time.Time `json:"lastErrorTime,omitempty"`
+	ImagesPending    int        `json:"imagesPending"`
+	BytesTransferred int64      `json:"bytesTransferred"`
+}
+
+// StatusTracker keeps the sync state of the upstream registries, and reports it as metrics.
+type StatusTracker struct {
+	registries []*registryStatus
+	metrics    monitoring.MetricServer
+	lock       *sync.RWMutex
+}
+
+func NewStatusTracker(metrics monitoring.MetricServer) *StatusTracker {
+	return &StatusTracker{
+		registries: []*registryStatus{},
+		metrics:    metrics,
+		lock:       &sync.RWMutex{},
+	}
+}
+
+// Reset forgets the registries, it's called when the sync config is reloaded.
+func (tracker *StatusTracker) Reset() {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	tracker.registries = []*registryStatus{}
+}
+
+func (tracker *StatusTracker) addRegistry(urls []string) *registryStatus {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	name := ""
+	if len(urls) > 0 {
+		name = urls[0]
+	}
+
+	registry := &registryStatus{
+		name:    name,
+		status:  RegistryStatus{URLs: urls},
+		repos:   map[string]*RepoStatus{},
+		metrics: tracker.metrics,
+		lock:    tracker.lock,
+	}
+
+	tracker.registries = append(tracker.registries, registry)
+
+	return registry
+}
+
+// GetStatus returns a snapshot of the sync state, the repos of each registry are sorted by name.
+func (tracker *StatusTracker) GetStatus() Status {
+	tracker.lock.RLock()
+	defer tracker.lock.RUnlock()
+
+	status := Status{Registries: make([]RegistryStatus, 0, len(tracker.registries))}
+
+	for _, registry := range tracker.registries {
+		registryStatus := registry.status
+		registryStatus.Repos = make([]RepoStatus, 0, len(registry.repos))
+
+		for _, repoStatus := range registry.repos {
+			registryStatus.ImagesPending += repoStatus.ImagesPending
+			registryStatus.BytesTransferred += repoStatus.BytesTransferred
+
+			registryStatus.Repos = append(registryStatus.Repos, *repoStatus)
+		}
+
+		sort.Slice(registryStatus.Repos, func(i, j int) bool {
+			return registryStatus.Repos[i].Repo < registryStatus.Repos[j].Repo
+		})
+
+		status.Registries = append(status.Registries, registryStatus)
+	}
+
+	return status
+}
+
+// registryStatus records the sync state of a registry, a nil registryStatus records nothing.
+type registryStatus struct {
+	name    string
+	status  RegistryStatus
+	repos   map[string]*RepoStatus
+	metrics monitoring.MetricServer
+	lock    *sync.RWMutex
+}
+
+// getRepo must be called with the lock held.
+func (registry *registryStatus) getRepo(repo string) *RepoStatus {
+	repoStatus, ok := registry.repos[repo]
+	if !ok {
+		repoStatus = &RepoStatus{Repo: repo}
+		registry.repos[repo] = repoStatus
+	}
+
+	return repoStatus
+}
+
+func (registry *registryStatus) setPending(repo string, pending int) {
+	if registry == nil {
+		return
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	registry.getRepo(repo).ImagesPending = pending
+
+	monitoring.SetSyncImagesPending(registry.metrics, registry.name, repo, pending)
+}
+
+// imageDone is called once an image of a repo is synced or skipped.
+func (registry *registryStatus) imageDone(repo string) {
+	if registry == nil {
+		return
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	repoStatus := registry.getRepo(repo)
+	if repoStatus.ImagesPending > 0 {
+		repoStatus.ImagesPending--
+	}
+
+	monitoring.SetSyncImagesPending(registry.metrics, registry.name, repo, repoStatus.ImagesPending)
+}
+
+func (registry *registryStatus) addTransferredBytes(repo string, transferred int64) {
+	if registry == nil || transferred == 0 {
+		return
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	repoStatus := registry.getRepo(repo)
+	repoStatus.BytesTransferred += transferred
+
+	monitoring.SetSyncTransferredBytes(registry.metrics, registry.name, repo, repoStatus.BytesTransferred)
+}
+
+func (registry *registryStatus) success(repo string) {
+	if registry == nil {
+		return
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	now := time.Now()
+
+	repoStatus := registry.getRepo(repo)
+	repoStatus.LastSuccess = &now
+	repoStatus.ImagesPending = 0
+	registry.status.LastSuccess = &now
+
+	monitoring.SetSyncLastSuccess(registry.metrics, registry.name, repo, now)
+	monitoring.SetSyncImagesPending(registry.metrics, registry.name, repo, 0)
+}
+
+func (registry *registryStatus) setRateLimitRemaining(remaining int64) {
+	if registry == nil {
+		return
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	registry.status.RateLimitRemaining = &remaining
+
+	monitoring.SetSyncRateLimitRemaining(registry.metrics, registry.name, remaining)
+}
+
+// failure records the error at the registry level if repo is empty.
+func (registry *registryStatus) failure(repo string, err error) {
+	if registry == nil || err == nil {
+		return
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	now := time.Now()
+
+	registry.status.LastError = err.Error()
+	registry.status.LastErrorTime = &now
+
+	if repo != "" {
+		repoStatus := registry.getRepo(repo)
+		repoStatus.LastError = err.Error()
+		repoStatus.LastErrorTime = &now
+	}
+
+	monitoring.IncSyncErrors(registry.metrics, registry.name, repo)
+}
+
+// countedImageReference is an upstream image whose downloaded blob bytes are counted.
+type countedImageReference struct {
+	types.ImageReference
+	count func(transferred int64)
+}
+
+func newCountedImageReference(imageReference types.ImageReference, count func(transferred int64),
+) types.ImageReference {
+	return countedImageReference{ImageReference: imageReference, count: count}
+}
+
+func (ref countedImageReference) NewImageSource(ctx context.Context, sys *types.SystemContext,
+) (types.ImageSource, error) {
+	imageSource, err := ref.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+
+	return countedImageSource{ImageSource: imageSource, count: ref.count}, nil
+}
+
+type countedImageSource struct {
+	types.ImageSource
+	count func(transferred int64)
+}
+
+func (src countedImageSource) Reference() types.ImageReference {
+	return newCountedImageReference(src.ImageSource.Reference(), src.count)
+}
+
+func (src countedImageSource) GetBlob(ctx context.Context, blobInfo types.BlobInfo, cache types.BlobInfoCache,
+) (io.ReadCloser, int64, error) {
+	reader, size, err := src.ImageSource.GetBlob(ctx, blobInfo, cache)
+	if err != nil {
+		return reader, size, err
+	}
+
+	return &countedReader{reader: reader, count: src.count}, size, nil
+}
+
+// countedReader reports the bytes read once closed, so that the status is not locked for each read.
+type countedReader struct {
+	reader io.ReadCloser
+	read   int64
+	count  func(transferred int64)
+}
+
+func (cr *countedReader) Read(buf []byte) (int, error) {
+	n, err := cr.reader.Read(buf)
+	cr.read += int64(n)
+
+	return n, err
+}
+
+func (cr *countedReader) Close() error {
+	cr.count(cr.read)
+	cr.read = 0
+
+	return cr.reader.Close()
+}
@@ -5,8 +5,8 @@ package sync
 
 import (
 	"context"
+	"io"
 
-	"github.com/containers/common/pkg/retry"
 	"github.com/containers/image/v5/types"
 	"github.com/opencontainers/go-digest"
 
@@ -20,12 +20,17 @@ import (
 
 // Sync general functionalities, one service per registry config.
 type Service interface {
-	// Get next repo from remote /v2/_catalog, will return empty string when there is no repo left.
-	GetNextRepo(lastRepo string) (string, error) // used by task scheduler
+	// Get next repo from remote /v2/_catalog matching one of the content rules (indexes in the registry config),
+	// will return empty string when there is no repo left.
+	GetNextRepo(lastRepo string, contentIDs []int) (string, error) // used by task scheduler
 	// Sync a repo with all of its tags and references (signatures, artifacts, sboms) into ImageStore.
 	SyncRepo(ctx context.Context, repo string) error // used by periodically sync
 	// Sync an image (repo:tag || repo:digest) into ImageStore.
 	SyncImage(ctx context.Context, repo, reference string) error // used by sync on demand
+	// Sync an upstream image (repo:tag), or all the tags of an upstream repo, into its local repos.
+	SyncUpstreamImage(ctx context.Context, remoteRepo, tag string) error // used by triggered sync
+	// Get the content of a blob of a local repo from its upstream repo.
+	GetUpstreamBlob(ctx context.Context, repo string, blobDigest digest.Digest) (io.ReadCloser, error) // used by scrub
 	// Sync a single reference for an image.
 	SyncReference(ctx context.Context, repo string, subjectDigestStr string,
 		referenceType string) error // used by sync on demand
@@ -33,8 +38,8 @@ type Service interface {
 	ResetCatalog() // used by scheduler to empty out the catalog after a sync periodically roundtrip finishes
 	// Sync supports multiple urls per registry, before a sync repo/image/ref 'ping' each url.
 	SetNextAvailableURL() error // used by all sync methods
-	// Returns retry options from registry config.
-	GetRetryOptions() *retry.Options // used by sync on demand to retry in background
+	// Returns retry policy from registry config.
+	GetRetryPolicy() *RetryPolicy // used by sync on demand to retry in background
 }
 
 // Local and remote registries must implement this interface.
@@ -69,23 +74,30 @@ type Local interface {
 	Registry
 	// Check if an image is already synced
 	CanSkipImage(repo, tag string, imageDigest digest.Digest) (bool, error)
+	// Get the digest of a local image, empty if the image doesn't exist
+	GetImageDigest(repo, reference string) (digest.Digest, error)
 	// CommitImage moves a synced repo/ref from temporary oci layout to ImageStore
 	CommitImage(imageReference types.ImageReference, repo, tag string) error
+	// CopyImage copies a synced repo/ref to another repo/ref of ImageStore
+	CopyImage(srcRepo, srcReference, destRepo, destReference string) error
 }
 
 type TaskGenerator struct {
-	Service  Service
-	lastRepo string
-	done     bool
-	log      log.Logger
+	Service Service
+	// the generator only syncs the repos matching these content rules
+	contentIDs []int
+	lastRepo   string
+	done       bool
+	log        log.Logger
 }
 
-func NewTaskGenerator(service Service, log log.Logger) *TaskGenerator {
+func NewTaskGenerator(service Service, contentIDs []int, log log.Logger) *TaskGenerator {
 	return &TaskGenerator{
-		Service:  service,
-		done:     false,
-		lastRepo: "",
-		log:      log,
+		Service:    service,
+		contentIDs: contentIDs,
+		done:       false,
+		lastRepo:   "",
+		log:        log,
 	}
 }
 
@@ -94,7 +106,7 @@ func (gen *TaskGenerator) Next() (scheduler.Task, error) {
 		return nil, err
 	}
 
-	repo, err := gen.Service.GetNextRepo(gen.lastRepo)
+	repo, err := gen.Service.GetNextRepo(gen.lastRepo, gen.contentIDs)
 	if err != nil {
 		return nil, err
 	}
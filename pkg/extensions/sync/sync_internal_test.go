@@ -6,11 +6,27 @@ package sync
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	dockerManifest "github.com/containers/image/v5/manifest"
 	"github.com/containers/image/v5/oci/layout"
@@ -18,13 +34,17 @@ import (
 	godigest "github.com/opencontainers/go-digest"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/rs/zerolog"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	sigstoreSigs "github.com/sigstore/sigstore/pkg/signature"
 	. "github.com/smartystreets/goconvey/convey"
 
 	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/common"
 	"zotregistry.io/zot/pkg/extensions/config"
 	syncconf "zotregistry.io/zot/pkg/extensions/config/sync"
 	"zotregistry.io/zot/pkg/extensions/lint"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
+	syncConstants "zotregistry.io/zot/pkg/extensions/sync/constants"
 	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
 	"zotregistry.io/zot/pkg/log"
 	"zotregistry.io/zot/pkg/storage"
@@ -70,7 +90,7 @@ func TestInjectSyncUtils(t *testing.T) {
 		log := log.Logger{Logger: zerolog.New(os.Stdout)}
 		metrics := monitoring.NewMetricsServer(false, log)
 		imageStore := local.NewImageStore(t.TempDir(), false, false, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, nil,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil,
 		)
 		injected = inject.InjectFailure(0)
 
@@ -163,7 +183,7 @@ func TestService(t *testing.T) {
 			URLs: []string{"http://localhost"},
 		}
 
-		service, err := New(conf, "", storage.StoreController{}, mocks.MetaDBMock{}, log.Logger{})
+		service, err := New(conf, "", storage.StoreController{}, mocks.MetaDBMock{}, nil, nil, nil, log.Logger{})
 		So(err, ShouldBeNil)
 
 		err = service.SyncRepo(context.Background(), "repo")
@@ -171,6 +191,811 @@ func TestService(t *testing.T) {
 	})
 }
 
+func TestDownloadRateLimits(t *testing.T) {
+	Convey("no limiter if the download rate is not limited", t, func() {
+		So(client.NewRateLimiter(0), ShouldBeNil)
+
+		reader := io.NopCloser(bytes.NewReader([]byte("blob")))
+		So(client.NewThrottledReader(context.Background(), reader, nil), ShouldEqual, reader)
+
+		imageRef, err := layout.NewReference("dir", "image")
+		So(err, ShouldBeNil)
+		So(newThrottledImageReference(imageRef, nil), ShouldEqual, imageRef)
+	})
+
+	Convey("downloads are throttled by the global and the registry limiters", t, func() {
+		globalLimiter := client.NewRateLimiter(8 * 1024)
+
+		conf := syncconf.RegistryConfig{
+			URLs:            []string{"http://localhost"},
+			MaxDownloadRate: 4 * 1024,
+		}
+
+		service, err := New(conf, "", storage.StoreController{}, mocks.MetaDBMock{}, globalLimiter, nil, nil, log.Logger{})
+		So(err, ShouldBeNil)
+
+		baseService, ok := service.(*BaseService)
+		So(ok, ShouldBeTrue)
+		So(len(baseService.rateLimiters), ShouldEqual, 2)
+
+		imageRef, err := layout.NewReference("dir", "image")
+		So(err, ShouldBeNil)
+
+		_, ok = newThrottledImageReference(imageRef, baseService.rateLimiters).(throttledImageReference)
+		So(ok, ShouldBeTrue)
+
+		// the first 4KB are read at once, the next 8KB at the rate of the registry limiter
+		blob := make([]byte, 12*1024)
+		reader := client.NewThrottledReader(context.Background(), io.NopCloser(bytes.NewReader(blob)),
+			baseService.rateLimiters...)
+
+		start := time.Now()
+
+		buf, err := io.ReadAll(reader)
+		So(err, ShouldBeNil)
+		So(len(buf), ShouldEqual, len(blob))
+		So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 1500*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		reader = client.NewThrottledReader(ctx, io.NopCloser(bytes.NewReader(blob)), baseService.rateLimiters...)
+		_, err = io.ReadAll(reader)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	Convey("Test retry delays", t, func() {
+		maxRetries := 5
+		delay := time.Second
+		backoff := 2.0
+		maxDelay := 5 * time.Second
+
+		policy := NewRetryPolicy(syncconf.RegistryConfig{MaxRetries: &maxRetries, RetryDelay: &delay})
+		So(policy.GetDelay(0), ShouldEqual, time.Second)
+		So(policy.GetDelay(3), ShouldEqual, time.Second)
+
+		policy = NewRetryPolicy(syncconf.RegistryConfig{
+			MaxRetries: &maxRetries, RetryDelay: &delay, RetryBackoff: &backoff, MaxRetryDelay: &maxDelay,
+		})
+		So(policy.MaxRetries, ShouldEqual, 5)
+		So(policy.GetDelay(0), ShouldEqual, time.Second)
+		So(policy.GetDelay(1), ShouldEqual, 2*time.Second)
+		So(policy.GetDelay(2), ShouldEqual, 4*time.Second)
+		So(policy.GetDelay(3), ShouldEqual, 5*time.Second)
+		So(policy.GetDelay(100), ShouldEqual, 5*time.Second)
+
+		// no retries without maxRetries
+		policy = NewRetryPolicy(syncconf.RegistryConfig{RetryDelay: &delay})
+		So(policy.MaxRetries, ShouldEqual, 0)
+	})
+
+	Convey("Test retrying operations", t, func() {
+		maxRetries := 2
+		delay := time.Millisecond
+		policy := NewRetryPolicy(syncconf.RegistryConfig{MaxRetries: &maxRetries, RetryDelay: &delay})
+
+		attempts := 0
+		err := policy.RetryIfNecessary(context.Background(), func() error {
+			attempts++
+
+			return syscall.ECONNREFUSED
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldEqual, syscall.ECONNREFUSED)
+		So(attempts, ShouldEqual, 3)
+
+		// errors which are not worth retrying are returned at once
+		attempts = 0
+		err = policy.RetryIfNecessary(context.Background(), func() error {
+			attempts++
+
+			return ErrTestError
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldEqual, ErrTestError)
+		So(attempts, ShouldEqual, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		attempts = 0
+		err = policy.RetryIfNecessary(ctx, func() error {
+			attempts++
+
+			return syscall.ECONNREFUSED
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldEqual, syscall.ECONNREFUSED)
+		So(attempts, ShouldEqual, 1)
+	})
+}
+
+func TestQuarantine(t *testing.T) {
+	Convey("Test quarantining failing repos", t, func() {
+		So(newQuarantine(nil, log.NewLogger("debug", "")), ShouldBeNil)
+		So(newQuarantine(&syncconf.QuarantineConfig{Failures: 2}, log.NewLogger("debug", "")), ShouldBeNil)
+
+		var noQuarantine *quarantine
+		noQuarantine.record("repo", ErrTestError)
+		So(noQuarantine.check("repo"), ShouldBeNil)
+
+		repoQuarantine := newQuarantine(&syncconf.QuarantineConfig{Failures: 2, Duration: time.Hour},
+			log.NewLogger("debug", ""))
+
+		repoQuarantine.record("repo", ErrTestError)
+		So(repoQuarantine.check("repo"), ShouldBeNil)
+
+		// released after a successful sync
+		repoQuarantine.record("repo", nil)
+		repoQuarantine.record("repo", ErrTestError)
+		So(repoQuarantine.check("repo"), ShouldBeNil)
+
+		repoQuarantine.record("repo", ErrTestError)
+		So(repoQuarantine.check("repo"), ShouldEqual, zerr.ErrSyncRepoQuarantined)
+		So(repoQuarantine.check("other-repo"), ShouldBeNil)
+
+		Convey("the quarantined repo is not synced", func() {
+			service, err := New(syncconf.RegistryConfig{
+				URLs:       []string{"http://localhost"},
+				Quarantine: &syncconf.QuarantineConfig{Failures: 1, Duration: time.Hour},
+			}, "", storage.StoreController{}, mocks.MetaDBMock{}, nil, nil, nil, log.NewLogger("debug", ""))
+			So(err, ShouldBeNil)
+
+			err = service.SyncRepo(context.Background(), "repo")
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, zerr.ErrSyncRepoQuarantined), ShouldBeFalse)
+
+			err = service.SyncRepo(context.Background(), "repo")
+			So(errors.Is(err, zerr.ErrSyncRepoQuarantined), ShouldBeTrue)
+		})
+	})
+}
+
+type collisionLocalRegistry struct {
+	Local
+	digests map[string]godigest.Digest
+}
+
+func (registry collisionLocalRegistry) GetImageDigest(repo, reference string) (godigest.Digest, error) {
+	return registry.digests[repo+":"+reference], nil
+}
+
+func TestTagCollisions(t *testing.T) {
+	Convey("Test tag collision policies and events", t, func() {
+		events := make(chan TagCollisionEvent, 2)
+
+		server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			var event TagCollisionEvent
+
+			if req.Header.Get("Authorization") != "token" || json.NewDecoder(req.Body).Decode(&event) != nil {
+				resp.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			events <- event
+		}))
+		defer server.Close()
+
+		notifier := NewEventNotifier([]syncconf.WebhookConfig{
+			{URL: server.URL, Headers: map[string]string{"Authorization": "token"}},
+		}, log.NewLogger("debug", ""))
+
+		service, err := New(syncconf.RegistryConfig{
+			URLs:            []string{"http://localhost"},
+			CollisionPolicy: "keepBoth",
+			Content: []syncconf.Content{
+				{Prefix: "skip/**", CollisionPolicy: "skip"},
+				{Prefix: "**"},
+			},
+		}, "", storage.StoreController{}, mocks.MetaDBMock{}, nil, nil, notifier, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		localDigest := godigest.FromString("local")
+		upstreamDigest := godigest.FromString("upstream")
+
+		baseService, ok := service.(*BaseService)
+		So(ok, ShouldBeTrue)
+
+		baseService.local = collisionLocalRegistry{digests: map[string]godigest.Digest{
+			"repo:1.0":      localDigest,
+			"skip/repo:1.0": localDigest,
+		}}
+
+		So(baseService.getCollisionPolicy("skip/repo"), ShouldEqual, "skip")
+		So(baseService.getCollisionPolicy("repo"), ShouldEqual, "keepBoth")
+
+		// no collision
+		tag, ok := baseService.getLocalTag("repo", "repo", "2.0", upstreamDigest)
+		So(ok, ShouldBeTrue)
+		So(tag, ShouldEqual, "2.0")
+
+		tag, ok = baseService.getLocalTag("repo", "repo", "1.0", localDigest)
+		So(ok, ShouldBeTrue)
+		So(tag, ShouldEqual, "1.0")
+
+		tag, ok = baseService.getLocalTag("repo", "repo", "1.0", upstreamDigest)
+		So(ok, ShouldBeTrue)
+		So(tag, ShouldEqual, "1.0-upstream")
+
+		event := <-events
+		So(event.Type, ShouldEqual, TagCollisionEventType)
+		So(event.Repo, ShouldEqual, "repo")
+		So(event.Tag, ShouldEqual, "1.0")
+		So(event.LocalDigest, ShouldEqual, localDigest.String())
+		So(event.UpstreamDigest, ShouldEqual, upstreamDigest.String())
+		So(event.Policy, ShouldEqual, "keepBoth")
+		So(event.SyncedTag, ShouldEqual, "1.0-upstream")
+
+		// the same collision is only notified once
+		_, _ = baseService.getLocalTag("repo", "repo", "1.0", upstreamDigest)
+
+		_, ok = baseService.getLocalTag("skip/repo", "skip/repo", "1.0", upstreamDigest)
+		So(ok, ShouldBeFalse)
+
+		event = <-events
+		So(event.Repo, ShouldEqual, "skip/repo")
+		So(event.Policy, ShouldEqual, "skip")
+		So(event.SyncedTag, ShouldBeEmpty)
+		So(events, ShouldBeEmpty)
+
+		var noNotifier *EventNotifier
+		So(func() { noNotifier.notifyCollision(event) }, ShouldNotPanic)
+
+		err = notifier.post(context.Background(), syncconf.WebhookConfig{URL: server.URL}, event)
+		So(errors.Is(err, zerr.ErrSyncWebhookFailed), ShouldBeTrue)
+	})
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	Convey("Test verifying upstream signatures", t, func() {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		signer, err := sigstoreSigs.LoadSigner(privateKey, crypto.SHA256)
+		So(err, ShouldBeNil)
+
+		publicKey, err := cryptoutils.MarshalPublicKeyToPEM(privateKey.Public())
+		So(err, ShouldBeNil)
+
+		keyPath := path.Join(t.TempDir(), "cosign.pub")
+		So(os.WriteFile(keyPath, publicKey, 0o600), ShouldBeNil)
+
+		signedDigest := godigest.FromString("signed")
+		sigPayload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"repo"},`+
+			`"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"}}`, signedDigest))
+
+		signature, err := signer.SignMessage(bytes.NewReader(sigPayload))
+		So(err, ShouldBeNil)
+
+		payloadDigest := godigest.FromBytes(sigPayload)
+		sigManifest, err := json.Marshal(ispec.Manifest{
+			MediaType: ispec.MediaTypeImageManifest,
+			Layers: []ispec.Descriptor{{
+				MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+				Digest:      payloadDigest,
+				Size:        int64(len(sigPayload)),
+				Annotations: map[string]string{common.CosignSigKey: base64.StdEncoding.EncodeToString(signature)},
+			}},
+		})
+		So(err, ShouldBeNil)
+
+		// the signature of the signed image is also served as the signature of another image
+		server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/v2/repo/manifests/" + strings.Replace(signedDigest.String(), ":", "-", 1) + ".sig",
+				"/v2/repo/manifests/" + strings.Replace(godigest.FromString("other").String(), ":", "-", 1) + ".sig":
+				_, _ = resp.Write(sigManifest)
+			case "/v2/repo/blobs/" + payloadDigest.String():
+				_, _ = resp.Write(sigPayload)
+			default:
+				resp.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		httpClient, err := client.New(client.Config{URL: server.URL}, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		sigVerifier, err := NewSignatureVerifier(nil, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+		So(sigVerifier, ShouldBeNil)
+
+		_, err = NewSignatureVerifier(&syncconf.SignatureVerificationConfig{
+			CosignPublicKeys: []string{path.Join(t.TempDir(), "missing.pub")},
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldNotBeNil)
+
+		_, err = NewSignatureVerifier(&syncconf.SignatureVerificationConfig{
+			NotationCertificates: []string{keyPath},
+		}, log.NewLogger("debug", ""))
+		So(errors.Is(err, zerr.ErrSyncInvalidSignatureKey), ShouldBeTrue)
+
+		sigVerifier, err = NewSignatureVerifier(&syncconf.SignatureVerificationConfig{
+			CosignPublicKeys: []string{keyPath},
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		err = sigVerifier.Verify(context.Background(), httpClient, "repo", ispec.Descriptor{Digest: signedDigest})
+		So(err, ShouldBeNil)
+
+		err = sigVerifier.Verify(context.Background(), httpClient, "repo",
+			ispec.Descriptor{Digest: godigest.FromString("other")})
+		So(errors.Is(err, zerr.ErrSyncSignatureNotVerified), ShouldBeTrue)
+
+		err = sigVerifier.Verify(context.Background(), httpClient, "repo",
+			ispec.Descriptor{Digest: godigest.FromString("unsigned")})
+		So(errors.Is(err, zerr.ErrSyncSignatureNotVerified), ShouldBeTrue)
+
+		// no notation signature
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{Organization: []string{"acme"}},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		}
+
+		certificate, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+		So(err, ShouldBeNil)
+
+		certPath := path.Join(t.TempDir(), "notation.crt")
+		So(os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), 0o600),
+			ShouldBeNil)
+
+		notationVerifier, err := NewSignatureVerifier(&syncconf.SignatureVerificationConfig{
+			NotationCertificates: []string{certPath},
+			NotationIdentities:   []string{"x509.subject: C=US, ST=WA, O=acme"},
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		err = notationVerifier.Verify(context.Background(), httpClient, "repo", ispec.Descriptor{Digest: signedDigest})
+		So(errors.Is(err, zerr.ErrSyncSignatureNotVerified), ShouldBeTrue)
+
+		// signed by another key
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+
+		otherPublicKey, err := cryptoutils.MarshalPublicKeyToPEM(otherKey.Public())
+		So(err, ShouldBeNil)
+
+		So(os.WriteFile(keyPath, otherPublicKey, 0o600), ShouldBeNil)
+
+		sigVerifier, err = NewSignatureVerifier(&syncconf.SignatureVerificationConfig{
+			CosignPublicKeys: []string{keyPath},
+		}, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		err = sigVerifier.Verify(context.Background(), httpClient, "repo", ispec.Descriptor{Digest: signedDigest})
+		So(errors.Is(err, zerr.ErrSyncSignatureNotVerified), ShouldBeTrue)
+	})
+}
+
+func TestResumableBlobs(t *testing.T) {
+	Convey("Test resuming blob downloads and reusing local blobs", t, func() {
+		dir := t.TempDir()
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imageStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
+
+		content := []byte("this is a blob being downloaded in multiple attempts")
+		blobDigest := godigest.FromBytes(content)
+
+		var ranges []string
+
+		supportsRanges := true
+
+		server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != "/v2/upstream/blobs/"+blobDigest.String() {
+				resp.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			ranges = append(ranges, req.Header.Get("Range"))
+
+			if !supportsRanges {
+				_, _ = resp.Write(content)
+
+				return
+			}
+
+			http.ServeContent(resp, req, "", time.Time{}, bytes.NewReader(content))
+		}))
+		defer server.Close()
+
+		httpClient, err := client.New(client.Config{URL: server.URL}, log)
+		So(err, ShouldBeNil)
+
+		var transferred int64
+
+		blobs := &blobFetcher{
+			client:     httpClient,
+			imageStore: imageStore,
+			localRepo:  "repo",
+			remoteRepo: "upstream",
+			count:      func(n int64) { transferred += n },
+			log:        log,
+		}
+
+		partialPath := path.Join(dir, "repo", syncConstants.SyncBlobUploadDir, partialBlobsDir,
+			"sha256-"+blobDigest.Encoded())
+
+		readBlob := func() ([]byte, error) {
+			reader, err := blobs.download(context.Background(), blobDigest, int64(len(content)))
+			if err != nil {
+				return nil, err
+			}
+
+			defer reader.Close()
+
+			return io.ReadAll(reader)
+		}
+
+		// the first attempt was interrupted after 10 bytes
+		So(os.MkdirAll(path.Dir(partialPath), 0o700), ShouldBeNil)
+		So(os.WriteFile(partialPath, content[:10], 0o600), ShouldBeNil)
+
+		buf, err := readBlob()
+		So(err, ShouldBeNil)
+		So(buf, ShouldResemble, content)
+		So(ranges, ShouldResemble, []string{"bytes=10-"})
+		So(transferred, ShouldEqual, len(content)-10)
+
+		_, err = os.Stat(partialPath)
+		So(os.IsNotExist(err), ShouldBeTrue)
+
+		Convey("the download starts again if the upstream doesn't support range requests", func() {
+			supportsRanges = false
+
+			So(os.WriteFile(partialPath, content[:10], 0o600), ShouldBeNil)
+
+			buf, err := readBlob()
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, content)
+
+			_, err = os.Stat(partialPath)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("a corrupted partial download is discarded", func() {
+			So(os.WriteFile(partialPath, []byte("corrupted"), 0o600), ShouldBeNil)
+
+			_, err := readBlob()
+			So(errors.Is(err, zerr.ErrBadBlobDigest), ShouldBeTrue)
+
+			_, err = os.Stat(partialPath)
+			So(os.IsNotExist(err), ShouldBeTrue)
+
+			buf, err := readBlob()
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, content)
+		})
+
+		Convey("a partial download is only resumed by one sync at a time", func() {
+			reader, err := blobs.download(context.Background(), blobDigest, int64(len(content)))
+			So(err, ShouldBeNil)
+
+			_, err = blobs.download(context.Background(), blobDigest, int64(len(content)))
+			So(errors.Is(err, errPartialBlobInUse), ShouldBeTrue)
+
+			So(reader.Close(), ShouldBeNil)
+
+			// the partial file is kept, the blob wasn't fully read
+			_, err = os.Stat(partialPath)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("blobs already in local storage are not downloaded", func() {
+			_, _, err := imageStore.FullBlobUpload("other-repo", bytes.NewReader(content), blobDigest)
+			So(err, ShouldBeNil)
+
+			reader, size, ok := blobs.getLocalBlob(types.BlobInfo{Digest: blobDigest})
+			So(ok, ShouldBeTrue)
+			So(size, ShouldEqual, len(content))
+
+			buf, err := io.ReadAll(reader)
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, content)
+			So(reader.Close(), ShouldBeNil)
+
+			_, _, ok = blobs.getLocalBlob(types.BlobInfo{Digest: godigest.FromString("missing")})
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func TestExclusions(t *testing.T) {
+	Convey("Test sync exclusion rules", t, func() {
+		exclusions, err := NewExclusions(nil)
+		So(err, ShouldBeNil)
+		So(exclusions, ShouldBeNil)
+		So(exclusions.ExcludesRepo("repo"), ShouldBeFalse)
+		So(exclusions.ExcludesTag("nightly"), ShouldBeFalse)
+		So(exclusions.FilterTags([]string{"1.0"}), ShouldResemble, []string{"1.0"})
+		So(exclusions.CheckImage(context.Background(), nil, "repo", ispec.MediaTypeImageManifest, nil), ShouldBeNil)
+
+		_, err = NewExclusions(&syncconf.ExclusionsConfig{Repos: []string{"repo[a-"}})
+		So(errors.Is(err, zerr.ErrSyncInvalidExclusion), ShouldBeTrue)
+
+		_, err = NewExclusions(&syncconf.ExclusionsConfig{Tags: []string{"nightly-("}})
+		So(errors.Is(err, zerr.ErrSyncInvalidExclusion), ShouldBeTrue)
+
+		exclusions, err = NewExclusions(&syncconf.ExclusionsConfig{
+			Repos: []string{"/nightly/**", "library/*-dev"},
+			Tags:  []string{"^nightly-", "-rc[0-9]+$"},
+		})
+		So(err, ShouldBeNil)
+		So(exclusions.ExcludesRepo("nightly/app"), ShouldBeTrue)
+		So(exclusions.ExcludesRepo("library/app-dev"), ShouldBeTrue)
+		So(exclusions.ExcludesRepo("library/app"), ShouldBeFalse)
+		So(exclusions.FilterTags([]string{"1.0", "nightly-20231010", "2.0-rc1", "latest"}),
+			ShouldResemble, []string{"1.0", "latest"})
+	})
+
+	Convey("Test excluding upstream images by media type and size", t, func() {
+		layer := ispec.Descriptor{MediaType: ispec.MediaTypeImageLayerGzip, Digest: godigest.FromString("layer"), Size: 1000}
+		foreignLayer := ispec.Descriptor{
+			MediaType: "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip",
+			Digest:    godigest.FromString("foreign"),
+			Size:      10,
+		}
+		config := ispec.Descriptor{MediaType: ispec.MediaTypeImageConfig, Digest: godigest.FromString("config"), Size: 100}
+
+		manifestContent, err := json.Marshal(ispec.Manifest{
+			MediaType: ispec.MediaTypeImageManifest,
+			Config:    config,
+			Layers:    []ispec.Descriptor{layer},
+		})
+		So(err, ShouldBeNil)
+
+		foreignManifestContent, err := json.Marshal(ispec.Manifest{
+			MediaType: ispec.MediaTypeImageManifest,
+			Config:    config,
+			Layers:    []ispec.Descriptor{foreignLayer},
+		})
+		So(err, ShouldBeNil)
+
+		manifests := map[godigest.Digest][]byte{
+			godigest.FromBytes(manifestContent):        manifestContent,
+			godigest.FromBytes(foreignManifestContent): foreignManifestContent,
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			content, ok := manifests[godigest.Digest(path.Base(r.URL.Path))]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", ispec.MediaTypeImageManifest)
+			_, _ = w.Write(content)
+		}))
+		defer server.Close()
+
+		httpClient, err := client.New(client.Config{URL: server.URL}, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		getIndexContent := func(manifestContents ...[]byte) []byte {
+			index := ispec.Index{MediaType: ispec.MediaTypeImageIndex}
+
+			for _, content := range manifestContents {
+				index.Manifests = append(index.Manifests, ispec.Descriptor{
+					MediaType: ispec.MediaTypeImageManifest,
+					Digest:    godigest.FromBytes(content),
+					Size:      int64(len(content)),
+				})
+			}
+
+			content, err := json.Marshal(index)
+			So(err, ShouldBeNil)
+
+			return content
+		}
+
+		ctx := context.Background()
+		imageSize := int64(len(manifestContent)) + config.Size + layer.Size
+
+		exclusions, err := NewExclusions(&syncconf.ExclusionsConfig{
+			MediaTypes:   []string{foreignLayer.MediaType},
+			MaxImageSize: imageSize,
+		})
+		So(err, ShouldBeNil)
+
+		So(exclusions.CheckImage(ctx, httpClient, "repo", ispec.MediaTypeImageManifest, manifestContent), ShouldBeNil)
+
+		err = exclusions.CheckImage(ctx, httpClient, "repo", ispec.MediaTypeImageManifest, foreignManifestContent)
+		So(errors.Is(err, zerr.ErrSyncImageFilteredOut), ShouldBeTrue)
+
+		// the size of a multi-platform image is the size of every platform
+		indexContent := getIndexContent(manifestContent)
+		err = exclusions.CheckImage(ctx, httpClient, "repo", ispec.MediaTypeImageIndex, indexContent)
+		So(errors.Is(err, zerr.ErrSyncImageFilteredOut), ShouldBeTrue)
+
+		exclusions.maxImageSize = imageSize + int64(len(indexContent))
+		So(exclusions.CheckImage(ctx, httpClient, "repo", ispec.MediaTypeImageIndex, indexContent), ShouldBeNil)
+
+		exclusions.maxImageSize = 0
+
+		err = exclusions.CheckImage(ctx, httpClient, "repo", ispec.MediaTypeImageIndex,
+			getIndexContent(manifestContent, foreignManifestContent))
+		So(errors.Is(err, zerr.ErrSyncImageFilteredOut), ShouldBeTrue)
+
+		// the platform manifests must be found upstream
+		err = exclusions.CheckImage(ctx, httpClient, "repo", ispec.MediaTypeImageIndex,
+			getIndexContent([]byte("missing")))
+		So(err, ShouldNotBeNil)
+		So(errors.Is(err, zerr.ErrSyncImageFilteredOut), ShouldBeFalse)
+
+		exclusions.mediaTypes = []string{ispec.MediaTypeImageIndex}
+
+		err = exclusions.CheckImage(ctx, httpClient, "repo", ispec.MediaTypeImageIndex, indexContent)
+		So(errors.Is(err, zerr.ErrSyncImageFilteredOut), ShouldBeTrue)
+	})
+}
+
+// triggerService records the images it's triggered to sync.
+type triggerService struct {
+	Service
+	err    error
+	synced chan string
+}
+
+func (service triggerService) SetNextAvailableURL() error {
+	return nil
+}
+
+func (service triggerService) SyncUpstreamImage(ctx context.Context, remoteRepo, tag string) error {
+	service.synced <- remoteRepo + ":" + tag
+
+	return service.err
+}
+
+func TestTrigger(t *testing.T) {
+	Convey("Test parsing sync triggers", t, func() {
+		requests, err := ParseTriggerRequests([]byte(`{"repo": "/repo1/", "tag": "1.0"}`))
+		So(err, ShouldBeNil)
+		So(requests, ShouldResemble, []TriggerRequest{{Repo: "repo1", Tag: "1.0"}})
+
+		requests, err = ParseTriggerRequests([]byte(`[{"registry": "docker.io", "repo": "repo1"}, {"repo": "repo2"}]`))
+		So(err, ShouldBeNil)
+		So(requests, ShouldResemble, []TriggerRequest{{Registry: "docker.io", Repo: "repo1"}, {Repo: "repo2"}})
+
+		requests, err = ParseTriggerRequests([]byte(`{"events": [
+			{"action": "push", "target": {"repository": "repo1", "tag": "1.0"}},
+			{"action": "pull", "target": {"repository": "repo2", "tag": "1.0"}},
+			{"action": "push", "target": {"repository": "repo3"}}
+		]}`))
+		So(err, ShouldBeNil)
+		So(requests, ShouldResemble, []TriggerRequest{{Repo: "repo1", Tag: "1.0"}, {Repo: "repo3"}})
+
+		_, err = ParseTriggerRequests([]byte(`{"tag": "1.0"}`))
+		So(err, ShouldEqual, zerr.ErrSyncInvalidTrigger)
+
+		_, err = ParseTriggerRequests([]byte(`not json`))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Test triggering the sync of upstream images", t, func() {
+		trigger := NewTrigger(log.NewLogger("debug", ""))
+
+		filteredOut := triggerService{err: zerr.ErrSyncImageFilteredOut, synced: make(chan string, 1)}
+		syncing := triggerService{synced: make(chan string, 1)}
+
+		trigger.Add([]string{"https://registry1:5000"}, filteredOut)
+		trigger.Add([]string{"http://registry2", "registry3"}, syncing)
+
+		err := trigger.Sync([]TriggerRequest{{Repo: "repo", Tag: "1.0"}})
+		So(err, ShouldBeNil)
+
+		// the image is synced from the next registry if the first one filters it out
+		So(<-filteredOut.synced, ShouldEqual, "repo:1.0")
+		So(<-syncing.synced, ShouldEqual, "repo:1.0")
+
+		err = trigger.Sync([]TriggerRequest{{Registry: "https://registry3", Repo: "repo"}})
+		So(err, ShouldBeNil)
+		So(<-syncing.synced, ShouldEqual, "repo:")
+
+		err = trigger.Sync([]TriggerRequest{{Repo: "repo"}, {Registry: "registry4", Repo: "repo"}})
+		So(err, ShouldEqual, zerr.ErrSyncTriggerNoRegistry)
+
+		// an image already syncing is not triggered again
+		trigger.inFlight[TriggerRequest{Registry: "registry2", Repo: "repo", Tag: "2.0"}] = true
+
+		err = trigger.Sync([]TriggerRequest{{Registry: "registry2", Repo: "repo", Tag: "2.0"}})
+		So(err, ShouldBeNil)
+		So(len(syncing.synced), ShouldEqual, 0)
+
+		trigger.Reset()
+
+		err = trigger.Sync([]TriggerRequest{{Repo: "repo"}})
+		So(err, ShouldEqual, zerr.ErrSyncTriggerNoRegistry)
+	})
+}
+
+func TestStatusTracker(t *testing.T) {
+	Convey("Test tracking the sync state", t, func() {
+		tracker := NewStatusTracker(monitoring.NewMetricsServer(false, log.NewLogger("debug", "")))
+
+		registry := tracker.addRegistry([]string{"http://registry1", "http://registry2"})
+		_ = tracker.addRegistry([]string{"http://registry3"})
+
+		registry.setPending("repo2", 3)
+		registry.imageDone("repo2")
+		registry.addTransferredBytes("repo2", 1024)
+		registry.failure("repo2", ErrTestError)
+
+		registry.setPending("repo1", 1)
+		registry.addTransferredBytes("repo1", 512)
+		registry.imageDone("repo1")
+		registry.imageDone("repo1")
+		registry.success("repo1")
+
+		registry.failure("", ErrTestError)
+		registry.failure("repo1", nil)
+
+		status := tracker.GetStatus()
+		So(len(status.Registries), ShouldEqual, 2)
+
+		regStatus := status.Registries[0]
+		So(regStatus.URLs, ShouldResemble, []string{"http://registry1", "http://registry2"})
+		So(regStatus.LastSuccess, ShouldNotBeNil)
+		So(regStatus.LastError, ShouldEqual, ErrTestError.Error())
+		So(regStatus.ImagesPending, ShouldEqual, 2)
+		So(regStatus.BytesTransferred, ShouldEqual, 1536)
+		So(len(regStatus.Repos), ShouldEqual, 2)
+
+		So(regStatus.Repos[0].Repo, ShouldEqual, "repo1")
+		So(regStatus.Repos[0].LastSuccess, ShouldNotBeNil)
+		So(regStatus.Repos[0].LastError, ShouldBeEmpty)
+		So(regStatus.Repos[0].ImagesPending, ShouldEqual, 0)
+		So(regStatus.Repos[0].BytesTransferred, ShouldEqual, 512)
+
+		So(regStatus.Repos[1].Repo, ShouldEqual, "repo2")
+		So(regStatus.Repos[1].LastSuccess, ShouldBeNil)
+		So(regStatus.Repos[1].LastError, ShouldEqual, ErrTestError.Error())
+		So(regStatus.Repos[1].LastErrorTime, ShouldNotBeNil)
+		So(regStatus.Repos[1].ImagesPending, ShouldEqual, 2)
+		So(regStatus.Repos[1].BytesTransferred, ShouldEqual, 1024)
+
+		So(status.Registries[1].URLs, ShouldResemble, []string{"http://registry3"})
+		So(status.Registries[1].Repos, ShouldBeEmpty)
+
+		// a nil registry status records nothing
+		var noStatus *registryStatus
+		noStatus.setPending("repo", 1)
+		noStatus.imageDone("repo")
+		noStatus.addTransferredBytes("repo", 1)
+		noStatus.success("repo")
+		noStatus.failure("repo", ErrTestError)
+
+		// bytes are counted when the blob is closed
+		counted := int64(0)
+		reader := &countedReader{
+			reader: io.NopCloser(bytes.NewReader([]byte("blob"))),
+			count:  func(transferred int64) { counted += transferred },
+		}
+
+		_, err := io.ReadAll(reader)
+		So(err, ShouldBeNil)
+		So(counted, ShouldEqual, 0)
+		So(reader.Close(), ShouldBeNil)
+		So(counted, ShouldEqual, 4)
+
+		tracker.Reset()
+		So(tracker.GetStatus().Registries, ShouldBeEmpty)
+	})
+}
+
 func TestLocalRegistry(t *testing.T) {
 	Convey("make StoreController", t, func() {
 		dir := t.TempDir()
@@ -184,7 +1009,7 @@ func TestLocalRegistry(t *testing.T) {
 		}, log)
 
 		syncImgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 		repoName := "repo"
 
 		registry := NewLocalRegistry(storage.StoreController{DefaultStore: syncImgStore}, nil, log)
@@ -282,6 +1107,21 @@ func TestLocalRegistry(t *testing.T) {
 
 			err = registry.CommitImage(imageReference, repoName, "1.0")
 			So(err, ShouldBeNil)
+
+			// fan out the synced image to another repo
+			err = registry.CopyImage(repoName, "1.0", "fanout/"+repoName, "1.0")
+			So(err, ShouldBeNil)
+
+			imageDigest, err := registry.GetImageDigest("fanout/"+repoName, "1.0")
+			So(err, ShouldBeNil)
+			So(imageDigest, ShouldEqual, indexDigest)
+
+			imageDigest, err = registry.GetImageDigest("fanout/"+repoName, "2.0")
+			So(err, ShouldBeNil)
+			So(imageDigest, ShouldBeEmpty)
+
+			err = registry.CopyImage(repoName, "2.0", "fanout/"+repoName, "2.0")
+			So(err, ShouldNotBeNil)
 		})
 
 		Convey("trigger GetImageManifest error in CommitImage()", func() {
@@ -302,7 +1142,7 @@ func TestLocalRegistry(t *testing.T) {
 			}, log)
 
 			syncImgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, linter, cacheDriver)
+				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, linter, cacheDriver)
 			repoName := "repo"
 
 			registry := NewLocalRegistry(storage.StoreController{DefaultStore: syncImgStore}, nil, log)
@@ -440,6 +1280,13 @@ func TestLocalRegistry(t *testing.T) {
 
 				err = registry.CommitImage(imageReference, repoName, "2.0")
 				So(err, ShouldBeNil)
+
+				err = registry.CopyImage(repoName, "2.0", "fanout/"+repoName, "2.0")
+				So(err, ShouldBeNil)
+
+				ok, err = registry.CanSkipImage("fanout/"+repoName, "2.0", digest)
+				So(ok, ShouldBeTrue)
+				So(err, ShouldBeNil)
 			})
 		})
 	})
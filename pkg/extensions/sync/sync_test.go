@@ -2649,6 +2649,110 @@ func TestBadURL(t *testing.T) {
 	})
 }
 
+func TestSyncStatus(t *testing.T) {
+	Convey("Verify sync status of an unreachable registry", t, func() {
+		tlsVerify := false
+		upstreamURL := test.GetBaseURL(test.GetFreePort())
+
+		syncRegistryConfig := syncconf.RegistryConfig{
+			URLs:      []string{upstreamURL},
+			TLSVerify: &tlsVerify,
+			OnDemand:  true,
+		}
+
+		defaultVal := true
+		syncConfig := &syncconf.Config{
+			Enable:     &defaultVal,
+			Registries: []syncconf.RegistryConfig{syncRegistryConfig},
+		}
+
+		dctlr, destBaseURL, _, destClient := makeDownstreamServer(t, false, syncConfig)
+
+		dcm := test.NewControllerManager(dctlr)
+		dcm.StartAndWait(dctlr.Config.HTTP.Port)
+		defer dcm.StopServer()
+
+		resp, err := destClient.R().Get(destBaseURL + constants.FullSyncStatus)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		var status sync.Status
+		err = json.Unmarshal(resp.Body(), &status)
+		So(err, ShouldBeNil)
+		So(len(status.Registries), ShouldEqual, 1)
+		So(status.Registries[0].URLs, ShouldResemble, []string{upstreamURL})
+		So(status.Registries[0].LastSuccess, ShouldBeNil)
+		So(status.Registries[0].Repos, ShouldBeEmpty)
+
+		resp, err = destClient.R().Get(destBaseURL + "/v2/" + testImage + "/manifests/" + testImageTag)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = destClient.R().Get(destBaseURL + constants.FullSyncStatus)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+		err = json.Unmarshal(resp.Body(), &status)
+		So(err, ShouldBeNil)
+		So(len(status.Registries), ShouldEqual, 1)
+		So(status.Registries[0].LastError, ShouldNotBeEmpty)
+		So(status.Registries[0].LastErrorTime, ShouldNotBeNil)
+		So(len(status.Registries[0].Repos), ShouldEqual, 1)
+		So(status.Registries[0].Repos[0].Repo, ShouldEqual, testImage)
+		So(status.Registries[0].Repos[0].LastError, ShouldNotBeEmpty)
+		So(status.Registries[0].Repos[0].LastSuccess, ShouldBeNil)
+	})
+}
+
+func TestSyncTrigger(t *testing.T) {
+	Convey("Verify triggering the sync of upstream images", t, func() {
+		tlsVerify := false
+		upstreamURL := test.GetBaseURL(test.GetFreePort())
+
+		syncRegistryConfig := syncconf.RegistryConfig{
+			URLs:      []string{upstreamURL},
+			TLSVerify: &tlsVerify,
+			OnDemand:  true,
+		}
+
+		defaultVal := true
+		syncConfig := &syncconf.Config{
+			Enable:     &defaultVal,
+			Registries: []syncconf.RegistryConfig{syncRegistryConfig},
+		}
+
+		dctlr, destBaseURL, _, destClient := makeDownstreamServer(t, false, syncConfig)
+
+		dcm := test.NewControllerManager(dctlr)
+		dcm.StartAndWait(dctlr.Config.HTTP.Port)
+		defer dcm.StopServer()
+
+		resp, err := destClient.R().SetBody(`{"repo": "` + testImage + `", "tag": "` + testImageTag + `"}`).
+			Post(destBaseURL + constants.FullSyncTrigger)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		resp, err = destClient.R().SetBody(`{"events": [{"action": "push", "target": {"repository": "` +
+			testImage + `", "tag": "` + testImageTag + `"}}]}`).Post(destBaseURL + constants.FullSyncTrigger)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+
+		resp, err = destClient.R().SetBody(`{"registry": "other-registry:5000", "repo": "` + testImage + `"}`).
+			Post(destBaseURL + constants.FullSyncTrigger)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+		resp, err = destClient.R().SetBody(`{"tag": "` + testImageTag + `"}`).
+			Post(destBaseURL + constants.FullSyncTrigger)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusBadRequest)
+
+		resp, err = destClient.R().Get(destBaseURL + constants.FullSyncTrigger)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusMethodNotAllowed)
+	})
+}
+
 func TestNoImagesByRegex(t *testing.T) {
 	Convey("Verify sync with no images on source based on regex", t, func() {
 		updateDuration, _ := time.ParseDuration("1h")
@@ -0,0 +1,58 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"context"
+	"io"
+
+	"github.com/containers/image/v5/types"
+	"golang.org/x/time/rate"
+
+	client "zotregistry.io/zot/pkg/extensions/sync/httpclient"
+)
+
+// throttledImageReference is an upstream image whose blobs are downloaded at the rates of the limiters.
+type throttledImageReference struct {
+	types.ImageReference
+	limiters []*rate.Limiter
+}
+
+func newThrottledImageReference(imageReference types.ImageReference, limiters []*rate.Limiter,
+) types.ImageReference {
+	if len(limiters) == 0 {
+		return imageReference
+	}
+
+	return throttledImageReference{ImageReference: imageReference, limiters: limiters}
+}
+
+func (ref throttledImageReference) NewImageSource(ctx context.Context, sys *types.SystemContext,
+) (types.ImageSource, error) {
+	imageSource, err := ref.ImageReference.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+
+	return throttledImageSource{ImageSource: imageSource, limiters: ref.limiters}, nil
+}
+
+type throttledImageSource struct {
+	types.ImageSource
+	limiters []*rate.Limiter
+}
+
+func (src throttledImageSource) Reference() types.ImageReference {
+	return newThrottledImageReference(src.ImageSource.Reference(), src.limiters)
+}
+
+func (src throttledImageSource) GetBlob(ctx context.Context, blobInfo types.BlobInfo, cache types.BlobInfoCache,
+) (io.ReadCloser, int64, error) {
+	reader, size, err := src.ImageSource.GetBlob(ctx, blobInfo, cache)
+	if err != nil {
+		return reader, size, err
+	}
+
+	return client.NewThrottledReader(ctx, reader, src.limiters...), size, nil
+}
@@ -0,0 +1,239 @@
+//go:build sync
+// +build sync
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/common"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const triggerPushAction = "push"
+
+// TriggerRequest asks to sync an upstream image, or every tag of an upstream repo if the tag is empty,
+// from the registries whose URL matches the registry, or from any registry if it's empty.
+type TriggerRequest struct {
+	Registry string `json:"registry,omitempty"`
+	Repo     string `json:"repo"`
+	Tag      string `json:"tag,omitempty"`
+}
+
+// notificationEnvelope is the body of the docker distribution registry notifications.
+type notificationEnvelope struct {
+	Events []struct {
+		Action string `json:"action"`
+		Target struct {
+			Repository string `json:"repository"`
+			Tag        string `json:"tag"`
+		} `json:"target"`
+	} `json:"events"`
+}
+
+/*
+Trigger syncs the images pushed upstream as soon as it's notified, instead of waiting for the next periodic sync,
+it's kept across config reloads and holds the services of every registry, which also makes it the source
+of the upstream blobs.
+A triggered image is only synced once at a time, the triggers received while it's syncing are dropped.
+*/
+type Trigger struct {
+	registries []triggerRegistry
+	inFlight   map[TriggerRequest]bool
+	lock       *sync.Mutex
+	log        log.Logger
+}
+
+type triggerRegistry struct {
+	urls    []string
+	service Service
+}
+
+func NewTrigger(log log.Logger) *Trigger {
+	return &Trigger{
+		registries: []triggerRegistry{},
+		inFlight:   map[TriggerRequest]bool{},
+		lock:       &sync.Mutex{},
+		log:        log,
+	}
+}
+
+// Reset forgets the registries, it's called when the sync config is reloaded.
+func (trigger *Trigger) Reset() {
+	trigger.lock.Lock()
+	defer trigger.lock.Unlock()
+
+	trigger.registries = []triggerRegistry{}
+}
+
+func (trigger *Trigger) Add(urls []string, service Service) {
+	trigger.lock.Lock()
+	defer trigger.lock.Unlock()
+
+	trigger.registries = append(trigger.registries, triggerRegistry{urls: urls, service: service})
+}
+
+// ParseTriggerRequests reads either a list of trigger requests, a single one,
+// or a docker distribution notification envelope whose push events are triggered.
+func ParseTriggerRequests(body []byte) ([]TriggerRequest, error) {
+	var envelope notificationEnvelope
+
+	if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Events) > 0 {
+		requests := []TriggerRequest{}
+
+		for _, event := range envelope.Events {
+			// deletes and pulls are not synced
+			if event.Action != triggerPushAction || event.Target.Repository == "" {
+				continue
+			}
+
+			requests = append(requests, TriggerRequest{Repo: event.Target.Repository, Tag: event.Target.Tag})
+		}
+
+		return requests, nil
+	}
+
+	requests := []TriggerRequest{}
+
+	if err := json.Unmarshal(body, &requests); err != nil {
+		var request TriggerRequest
+
+		if err := json.Unmarshal(body, &request); err != nil {
+			return nil, err
+		}
+
+		requests = []TriggerRequest{request}
+	}
+
+	for idx := range requests {
+		requests[idx].Repo = strings.Trim(requests[idx].Repo, "/")
+		if requests[idx].Repo == "" {
+			return nil, zerr.ErrSyncInvalidTrigger
+		}
+	}
+
+	return requests, nil
+}
+
+// Sync starts syncing the requested images in the background, it returns ErrSyncTriggerNoRegistry
+// without syncing anything if a request doesn't match any registry.
+func (trigger *Trigger) Sync(requests []TriggerRequest) error {
+	services := make([][]Service, len(requests))
+
+	for idx, request := range requests {
+		services[idx] = trigger.getServices(request.Registry)
+		if len(services[idx]) == 0 {
+			return zerr.ErrSyncTriggerNoRegistry
+		}
+	}
+
+	for idx, request := range requests {
+		trigger.lock.Lock()
+
+		if trigger.inFlight[request] {
+			trigger.lock.Unlock()
+
+			trigger.log.Info().Str("repo", request.Repo).Str("tag", request.Tag).
+				Msg("sync: triggered image is already syncing")
+
+			continue
+		}
+
+		trigger.inFlight[request] = true
+		trigger.lock.Unlock()
+
+		// the request context is cancelled once the trigger is accepted
+		go trigger.sync(context.Background(), request, services[idx])
+	}
+
+	return nil
+}
+
+func (trigger *Trigger) sync(ctx context.Context, request TriggerRequest, services []Service) {
+	defer func() {
+		trigger.lock.Lock()
+		delete(trigger.inFlight, request)
+		trigger.lock.Unlock()
+	}()
+
+	var err error
+
+	// the image is synced from the first registry having it
+	for _, service := range services {
+		if err = service.SetNextAvailableURL(); err != nil {
+			continue
+		}
+
+		err = service.SyncUpstreamImage(ctx, request.Repo, request.Tag)
+		if err == nil {
+			trigger.log.Info().Str("repo", request.Repo).Str("tag", request.Tag).
+				Msg("sync: finished syncing triggered image")
+
+			return
+		}
+
+		if !errors.Is(err, zerr.ErrManifestNotFound) && !errors.Is(err, zerr.ErrSyncImageFilteredOut) {
+			trigger.log.Error().Str("errorType", common.TypeOf(err)).Str("repo", request.Repo).
+				Str("tag", request.Tag).Err(err).Msg("sync: error while syncing triggered image")
+		}
+	}
+
+	trigger.log.Info().Err(err).Str("repo", request.Repo).Str("tag", request.Tag).
+		Msg("sync: triggered image not synced by any registry")
+}
+
+// GetBlob returns the content of a blob of a local repo from the first upstream registry it's synced from
+// which has it, it's used by scrub to repair the corrupted blobs.
+func (trigger *Trigger) GetBlob(ctx context.Context, repo string, blobDigest godigest.Digest) (io.ReadCloser, error) {
+	err := zerr.ErrSyncTriggerNoRegistry
+
+	for _, service := range trigger.getServices("") {
+		if err = service.SetNextAvailableURL(); err != nil {
+			continue
+		}
+
+		var blobReader io.ReadCloser
+
+		blobReader, err = service.GetUpstreamBlob(ctx, repo, blobDigest)
+		if err == nil {
+			return blobReader, nil
+		}
+	}
+
+	return nil, err
+}
+
+// getServices returns the services of the registries matching the registry URL, or of every registry if it's empty.
+func (trigger *Trigger) getServices(registry string) []Service {
+	trigger.lock.Lock()
+	defer trigger.lock.Unlock()
+
+	services := []Service{}
+	registry = strings.Trim(StripRegistryTransport(registry), "/")
+
+	for _, triggerRegistry := range trigger.registries {
+		if registry == "" {
+			services = append(services, triggerRegistry.service)
+
+			continue
+		}
+
+		for _, url := range triggerRegistry.urls {
+			if strings.Trim(StripRegistryTransport(url), "/") == registry {
+				services = append(services, triggerRegistry.service)
+
+				break
+			}
+		}
+	}
+
+	return services
+}
@@ -0,0 +1,89 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// The optional fields of the access log lines.
+const (
+	AccessFieldClientIP  = "clientIP"
+	AccessFieldUserAgent = "userAgent"
+	AccessFieldQuery     = "query"
+	AccessFieldReferer   = "referer"
+	AccessFieldHeaders   = "headers"
+)
+
+// AccessFields returns the names of the optional fields of the access log lines.
+func AccessFields() []string {
+	return []string{
+		AccessFieldClientIP, AccessFieldUserAgent, AccessFieldQuery, AccessFieldReferer, AccessFieldHeaders,
+	}
+}
+
+// AccessRecord is one line of the access log, one per request. The optional fields are left out when empty.
+type AccessRecord struct {
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Repo     string    `json:"repo,omitempty"`
+	Identity string    `json:"identity,omitempty"`
+	Status   int       `json:"status"`
+	Bytes    int       `json:"bytes"`
+	// in seconds
+	Duration  float64             `json:"duration"`
+	ClientIP  string              `json:"clientIP,omitempty"` //nolint:tagliatelle // keep IP
+	UserAgent string              `json:"userAgent,omitempty"`
+	Query     string              `json:"query,omitempty"`
+	Referer   string              `json:"referer,omitempty"`
+	Headers   map[string][]string `json:"headers,omitempty"`
+}
+
+// AccessLogger writes one line per request, independently of the application log, to a file rotated on its
+// own so no external logrotate is needed.
+type AccessLogger struct {
+	lock   sync.Mutex
+	writer io.WriteCloser
+}
+
+// NewAccessLogger returns an access logger writing to the file, rotated as told.
+func NewAccessLogger(output string, rotation Rotation) *AccessLogger {
+	// unlike the audit log the lines aren't synced, the access log mustn't slow down the requests
+	file, err := newRotatingFile(output, rotation, false)
+	if err != nil {
+		panic(err)
+	}
+
+	return &AccessLogger{writer: file}
+}
+
+// Log writes the record.
+func (al *AccessLogger) Log(record AccessRecord) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	line = append(Redact(line), '\n')
+
+	al.lock.Lock()
+	defer al.lock.Unlock()
+
+	_, err = al.writer.Write(line)
+
+	return err
+}
+
+// Close closes the access log file, once its rotated files are compressed.
+func (al *AccessLogger) Close() error {
+	al.lock.Lock()
+	defer al.lock.Unlock()
+
+	return al.writer.Close()
+}
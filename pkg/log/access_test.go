@@ -0,0 +1,127 @@
+package log_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/log"
+)
+
+func TestAccessLogger(t *testing.T) {
+	Convey("Write the access log lines to a file", t, func() {
+		accessPath := path.Join(t.TempDir(), "zot-access.log")
+
+		access := log.NewAccessLogger(accessPath, log.Rotation{})
+		defer access.Close()
+
+		err := access.Log(log.AccessRecord{
+			Method: "GET", Path: "/v2/alpine/manifests/3.18", Repo: "alpine", Identity: "user",
+			Status: 200, Bytes: 1024, Duration: 0.5,
+			Headers: map[string][]string{"Authorization": {"Basic dXNlcjpwYXNz"}},
+		})
+		So(err, ShouldBeNil)
+
+		err = access.Log(log.AccessRecord{Method: "GET", Path: "/v2/", Status: 401})
+		So(err, ShouldBeNil)
+
+		content, err := os.ReadFile(accessPath)
+		So(err, ShouldBeNil)
+
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		So(len(lines), ShouldEqual, 2)
+
+		var record map[string]interface{}
+
+		err = json.Unmarshal([]byte(lines[0]), &record)
+		So(err, ShouldBeNil)
+		So(record, ShouldContainKey, "time")
+		So(record["method"], ShouldEqual, "GET")
+		So(record["path"], ShouldEqual, "/v2/alpine/manifests/3.18")
+		So(record["repo"], ShouldEqual, "alpine")
+		So(record["identity"], ShouldEqual, "user")
+		So(record["status"], ShouldEqual, 200)
+		So(record["bytes"], ShouldEqual, 1024)
+		So(record["duration"], ShouldEqual, 0.5)
+		So(lines[0], ShouldContainSubstring, `"Authorization":["******"]`)
+
+		// the empty fields are left out
+		record = map[string]interface{}{}
+
+		err = json.Unmarshal([]byte(lines[1]), &record)
+		So(err, ShouldBeNil)
+		So(record["status"], ShouldEqual, 401)
+		So(record, ShouldNotContainKey, "repo")
+		So(record, ShouldNotContainKey, "identity")
+		So(record, ShouldNotContainKey, "clientIP")
+		So(record, ShouldNotContainKey, "headers")
+	})
+
+	Convey("Rotate the access log file on time and compress the rotated files", t, func() {
+		dir := t.TempDir()
+		accessPath := path.Join(dir, "zot-access.log")
+
+		access := log.NewAccessLogger(accessPath, log.Rotation{
+			Interval: 50 * time.Millisecond, MaxBackups: 2, Compress: true,
+		})
+
+		for i := 0; i < 4; i++ {
+			err := access.Log(log.AccessRecord{Method: "GET", Path: "/v2/", Status: 200})
+			So(err, ShouldBeNil)
+
+			time.Sleep(60 * time.Millisecond)
+		}
+
+		// waits for the compression
+		err := access.Close()
+		So(err, ShouldBeNil)
+
+		backups, err := filepath.Glob(accessPath + ".*")
+		So(err, ShouldBeNil)
+		So(len(backups), ShouldEqual, 2)
+
+		for _, backup := range backups {
+			So(backup, ShouldEndWith, ".gz")
+
+			file, err := os.Open(backup)
+			So(err, ShouldBeNil)
+
+			gzReader, err := gzip.NewReader(file)
+			So(err, ShouldBeNil)
+
+			content, err := io.ReadAll(gzReader)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldContainSubstring, `"path":"/v2/"`)
+
+			file.Close()
+		}
+
+		// no compression leftovers
+		entries, err := os.ReadDir(dir)
+		So(err, ShouldBeNil)
+		So(len(entries), ShouldEqual, 3)
+	})
+
+	Convey("Get error when writing to a closed access log", t, func() {
+		access := log.NewAccessLogger(path.Join(t.TempDir(), "zot-access.log"), log.Rotation{})
+
+		err := access.Close()
+		So(err, ShouldBeNil)
+
+		err = access.Log(log.AccessRecord{Method: "GET", Status: 200})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Get error with an output which can't be written", t, func() {
+		So(func() { _ = log.NewAccessLogger(path.Join(t.TempDir(), "missing", "zot-access.log"), log.Rotation{}) },
+			ShouldPanic)
+	})
+}
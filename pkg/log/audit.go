@@ -0,0 +1,95 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// AuditMessage is the message of every audit record.
+	AuditMessage = "HTTP API Audit"
+
+	// DefaultAuditSyslogTag is the syslog tag of the audit records if the config doesn't tell.
+	DefaultAuditSyslogTag = "zot-audit"
+
+	auditLevel = "info"
+)
+
+// AuditRecord is one line of the audit log. The field names are stable, the consumers of the audit log can
+// rely on them, new fields are only ever added.
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Level    string    `json:"level"`
+	ClientIP string    `json:"clientIP"` //nolint:tagliatelle // keep IP
+	Subject  string    `json:"subject"`
+	Action   string    `json:"action"`
+	Object   string    `json:"object"`
+	Status   int       `json:"status"`
+	Message  string    `json:"message"`
+}
+
+// AuditLogger writes the audit records, independently of the application log: it has its own output, format
+// and rotation, and isn't filtered by the log level.
+type AuditLogger struct {
+	lock   sync.Mutex
+	writer io.WriteCloser
+}
+
+// NewAuditLogger returns an audit logger writing to the file, rotated as told.
+func NewAuditLogger(output string, rotation Rotation) *AuditLogger {
+	file, err := newRotatingFile(output, rotation, true)
+	if err != nil {
+		panic(err)
+	}
+
+	return &AuditLogger{writer: file}
+}
+
+// NewSyslogAuditLogger returns an audit logger sending the records to syslog with the auth facility, the local
+// syslog daemon is used if the network is empty.
+func NewSyslogAuditLogger(network, address, tag string) *AuditLogger {
+	if tag == "" {
+		tag = DefaultAuditSyslogTag
+	}
+
+	writer, err := NewSyslogWriter(network, address, tag, SyslogFacilityAuth)
+	if err != nil {
+		panic(err)
+	}
+
+	return &AuditLogger{writer: writer}
+}
+
+// Log writes the record, it is written when Log returns.
+func (al *AuditLogger) Log(record AuditRecord) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	record.Level = auditLevel
+	record.Message = AuditMessage
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	line = append(Redact(line), '\n')
+
+	al.lock.Lock()
+	defer al.lock.Unlock()
+
+	_, err = al.writer.Write(line)
+
+	return err
+}
+
+// Close closes the output of the audit log.
+func (al *AuditLogger) Close() error {
+	al.lock.Lock()
+	defer al.lock.Unlock()
+
+	return al.writer.Close()
+}
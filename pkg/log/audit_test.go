@@ -0,0 +1,115 @@
+package log_test
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/log"
+)
+
+func TestAuditLogger(t *testing.T) {
+	Convey("Write the audit records to a file", t, func() {
+		auditPath := path.Join(t.TempDir(), "zot-audit.log")
+
+		audit := log.NewAuditLogger(auditPath, log.Rotation{})
+		defer audit.Close()
+
+		err := audit.Log(log.AuditRecord{Subject: "user", Action: "PUT", Object: "/v2/repo/manifests/1.0", Status: 201})
+		So(err, ShouldBeNil)
+
+		content, err := os.ReadFile(auditPath)
+		So(err, ShouldBeNil)
+
+		var record map[string]interface{}
+
+		err = json.Unmarshal(content, &record)
+		So(err, ShouldBeNil)
+		So(record, ShouldContainKey, "time")
+		So(record, ShouldContainKey, "clientIP")
+		So(record["level"], ShouldEqual, "info")
+		So(record["subject"], ShouldEqual, "user")
+		So(record["action"], ShouldEqual, "PUT")
+		So(record["object"], ShouldEqual, "/v2/repo/manifests/1.0")
+		So(record["status"], ShouldEqual, 201)
+		So(record["message"], ShouldEqual, log.AuditMessage)
+	})
+
+	Convey("Rotate the audit log file", t, func() {
+		dir := t.TempDir()
+		auditPath := path.Join(dir, "zot-audit.log")
+
+		// an old backup, deleted when the file is opened
+		oldBackup := auditPath + "." + time.Now().Add(-48*time.Hour).UTC().Format("2006-01-02T15-04-05.000")
+		err := os.WriteFile(oldBackup, []byte("old"), 0o600)
+		So(err, ShouldBeNil)
+
+		audit := log.NewAuditLogger(auditPath, log.Rotation{MaxSize: 1, MaxBackups: 1, MaxAge: 24 * time.Hour})
+		defer audit.Close()
+
+		_, err = os.Stat(oldBackup)
+		So(os.IsNotExist(err), ShouldBeTrue)
+
+		bigObject := strings.Repeat("a", 600*1024)
+
+		for i := 0; i < 3; i++ {
+			err = audit.Log(log.AuditRecord{Action: "PUT", Object: bigObject, Status: 201})
+			So(err, ShouldBeNil)
+
+			// the backups are named after the time of the rotation
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// every record fills more than half of the file, only the last backup is kept
+		backups, err := filepath.Glob(auditPath + ".*")
+		So(err, ShouldBeNil)
+		So(len(backups), ShouldEqual, 1)
+
+		info, err := os.Stat(auditPath)
+		So(err, ShouldBeNil)
+		So(info.Size(), ShouldBeLessThan, 1024*1024)
+	})
+
+	Convey("Send the audit records to syslog", t, func() {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		audit := log.NewSyslogAuditLogger("udp", conn.LocalAddr().String(), "")
+		defer audit.Close()
+
+		err = audit.Log(log.AuditRecord{Subject: "user", Action: "DELETE", Object: "/v2/repo/manifests/1.0", Status: 202})
+		So(err, ShouldBeNil)
+
+		buf := make([]byte, 4096)
+
+		err = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		So(err, ShouldBeNil)
+
+		n, _, err := conn.ReadFrom(buf)
+		So(err, ShouldBeNil)
+		So(string(buf[:n]), ShouldContainSubstring, log.DefaultAuditSyslogTag)
+		So(string(buf[:n]), ShouldContainSubstring, `"action":"DELETE"`)
+	})
+
+	Convey("Get error with an unknown syslog network", t, func() {
+		So(func() { _ = log.NewSyslogAuditLogger("unknown", "127.0.0.1:514", "") }, ShouldPanic)
+	})
+
+	Convey("Get error when writing to a closed audit log", t, func() {
+		audit := log.NewAuditLogger(path.Join(t.TempDir(), "zot-audit.log"), log.Rotation{})
+
+		err := audit.Close()
+		So(err, ShouldBeNil)
+
+		err = audit.Log(log.AuditRecord{Action: "PUT", Status: 201})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultJournaldSocket is the socket of the native protocol of systemd-journald.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldWriter sends every line to systemd-journald, the fields of the line are sent as journal fields:
+// the message as MESSAGE, the level as PRIORITY and the other ones with their names in upper case.
+type JournaldWriter struct {
+	identifier string
+	lock       sync.Mutex
+	conn       net.Conn
+}
+
+// NewJournaldWriter returns a writer sending the lines to the journald socket, with the identifier as
+// SYSLOG_IDENTIFIER.
+func NewJournaldWriter(socket, identifier string) (*JournaldWriter, error) {
+	if socket == "" {
+		socket = DefaultJournaldSocket
+	}
+
+	conn, err := net.Dial("unixgram", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournaldWriter{identifier: identifier, conn: conn}, nil
+}
+
+// Write sends the line with the info priority.
+func (jw *JournaldWriter) Write(line []byte) (int, error) {
+	return jw.WriteLevel(zerolog.NoLevel, line)
+}
+
+// WriteLevel sends the line with the priority of the level.
+func (jw *JournaldWriter) WriteLevel(level zerolog.Level, line []byte) (int, error) {
+	fields := map[string]interface{}{}
+
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	// the lines which aren't json are sent as they are
+	if err := decoder.Decode(&fields); err != nil {
+		fields = map[string]interface{}{zerolog.MessageFieldName: string(bytes.TrimRight(line, "\n"))}
+	}
+
+	var buf bytes.Buffer
+
+	appendJournalField(&buf, "PRIORITY", strconv.Itoa(syslogSeverity(level)))
+	appendJournalField(&buf, "SYSLOG_IDENTIFIER", jw.identifier)
+
+	if msg, ok := fields[zerolog.MessageFieldName]; ok {
+		appendJournalField(&buf, "MESSAGE", journalValue(msg))
+	}
+
+	// the order of the fields is kept stable
+	keys := make([]string, 0, len(fields))
+
+	for key := range fields {
+		if key != zerolog.MessageFieldName && key != zerolog.LevelFieldName {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		appendJournalField(&buf, journalFieldName(key), journalValue(fields[key]))
+	}
+
+	jw.lock.Lock()
+	defer jw.lock.Unlock()
+
+	if _, err := jw.conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(line), nil
+}
+
+func (jw *JournaldWriter) Close() error {
+	return jw.conn.Close()
+}
+
+// appendJournalField appends the field in the journald native format, the values with new lines are
+// prefixed with their length.
+func appendJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+
+		return
+	}
+
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName returns the field name in upper case, with only letters, digits and underscores and not
+// starting with an underscore, which is reserved for the fields set by journald.
+func journalFieldName(name string) string {
+	fieldName := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	fieldName = strings.TrimLeft(fieldName, "_")
+
+	if fieldName == "" || (fieldName[0] >= '0' && fieldName[0] <= '9') {
+		fieldName = "ZOT_" + fieldName
+	}
+
+	return fieldName
+}
+
+func journalValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,203 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+
+	zerr "zotregistry.io/zot/errors"
+)
+
+// The components whose log level can be set apart from the global one.
+const (
+	ComponentStorage = "storage"
+	ComponentSync    = "sync"
+	ComponentSearch  = "search"
+)
+
+// Components returns the names of the components whose log level can be set apart from the global one.
+func Components() []string {
+	return []string{ComponentStorage, ComponentSync, ComponentSearch}
+}
+
+// Levels are the log levels in use, the global one and the ones of the components which have their own.
+type Levels struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// levelState is replaced as a whole when a level changes, the loggers read it without locking.
+type levelState struct {
+	global     zerolog.Level
+	components map[string]zerolog.Level
+}
+
+//nolint:gochecknoglobals
+var (
+	currentLevels atomic.Pointer[levelState]
+	// the writes are serialized, the reads don't wait
+	levelsLock sync.Mutex
+)
+
+//nolint:gochecknoinits
+func init() {
+	currentLevels.Store(&levelState{global: zerolog.GlobalLevel(), components: map[string]zerolog.Level{}})
+}
+
+// SetLevel changes the global log level at runtime, the components which have their own level keep it.
+func SetLevel(level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	setGlobalLevel(lvl)
+
+	return nil
+}
+
+func setGlobalLevel(lvl zerolog.Level) {
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+
+	state := currentLevels.Load()
+
+	storeLevels(&levelState{global: lvl, components: state.components})
+}
+
+// SetComponentLevel changes the log level of the component at runtime, the component gets back the global level
+// if the level is empty.
+func SetComponentLevel(component, level string) error {
+	if !isComponent(component) {
+		return fmt.Errorf("%w: %s", zerr.ErrUnknownLogComponent, component)
+	}
+
+	var lvl zerolog.Level
+
+	if level != "" {
+		var err error
+
+		if lvl, err = parseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+
+	state := currentLevels.Load()
+
+	components := make(map[string]zerolog.Level, len(state.components))
+	for name, componentLevel := range state.components {
+		components[name] = componentLevel
+	}
+
+	if level == "" {
+		delete(components, component)
+	} else {
+		components[component] = lvl
+	}
+
+	storeLevels(&levelState{global: state.global, components: components})
+
+	return nil
+}
+
+// SetLevels replaces the global log level and the levels of the components, the components which aren't listed
+// get back the global level.
+func SetLevels(levels Levels) error {
+	global, err := parseLevel(levels.Level)
+	if err != nil {
+		return err
+	}
+
+	components := make(map[string]zerolog.Level, len(levels.Components))
+
+	for component, level := range levels.Components {
+		if !isComponent(component) {
+			return fmt.Errorf("%w: %s", zerr.ErrUnknownLogComponent, component)
+		}
+
+		if components[component], err = parseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+
+	storeLevels(&levelState{global: global, components: components})
+
+	return nil
+}
+
+// GetLevels returns the log levels in use.
+func GetLevels() Levels {
+	state := currentLevels.Load()
+
+	levels := Levels{Level: state.global.String(), Components: map[string]string{}}
+
+	for component, level := range state.components {
+		levels.Components[component] = level.String()
+	}
+
+	return levels
+}
+
+// Component returns a logger for the component, its lines are tagged with the component and filtered by its level.
+func (l Logger) Component(component string) Logger {
+	logger := l.Logger.With().Str("component", component).Logger()
+
+	return Logger{Logger: logger.Sample(componentLevelSampler{component: component})}
+}
+
+// storeLevels stores the levels, zerolog's global level is the lowest one so it doesn't filter out the lines of
+// the components logging more than the others.
+func storeLevels(state *levelState) {
+	lowest := state.global
+
+	for _, level := range state.components {
+		if level < lowest {
+			lowest = level
+		}
+	}
+
+	zerolog.SetGlobalLevel(lowest)
+	currentLevels.Store(state)
+}
+
+func parseLevel(level string) (zerolog.Level, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil || level == "" {
+		return lvl, fmt.Errorf("%w: %q", zerr.ErrBadLogLevel, level)
+	}
+
+	return lvl, nil
+}
+
+func isComponent(component string) bool {
+	for _, name := range Components() {
+		if name == component {
+			return true
+		}
+	}
+
+	return false
+}
+
+// componentLevelSampler filters the lines by the level of the component, the global level if it has none.
+type componentLevelSampler struct {
+	component string
+}
+
+func (s componentLevelSampler) Sample(lvl zerolog.Level) bool {
+	state := currentLevels.Load()
+
+	if componentLevel, ok := state.components[s.component]; ok {
+		return lvl >= componentLevel
+	}
+
+	return lvl >= state.global
+}
@@ -0,0 +1,97 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/log"
+)
+
+func TestLevels(t *testing.T) {
+	Convey("Change the log levels at runtime", t, func() {
+		var buf bytes.Buffer
+
+		logger := log.NewLoggerWithWriter("info", &buf)
+		storageLog := logger.Component(log.ComponentStorage)
+		syncLog := logger.Component(log.ComponentSync)
+
+		defer func() { _ = log.SetLevels(log.Levels{Level: "debug"}) }()
+
+		logger.Debug().Msg("global debug")
+		storageLog.Debug().Msg("storage debug")
+		So(buf.String(), ShouldBeEmpty)
+
+		// only the storage logs more
+		err := log.SetComponentLevel(log.ComponentStorage, "debug")
+		So(err, ShouldBeNil)
+
+		logger.Debug().Msg("global debug")
+		syncLog.Debug().Msg("sync debug")
+		storageLog.Debug().Msg("storage debug")
+		So(buf.String(), ShouldNotContainSubstring, "global debug")
+		So(buf.String(), ShouldNotContainSubstring, "sync debug")
+		So(buf.String(), ShouldContainSubstring, `"component":"storage"`)
+		So(buf.String(), ShouldContainSubstring, "storage debug")
+
+		// the sync logs less
+		err = log.SetComponentLevel(log.ComponentSync, "error")
+		So(err, ShouldBeNil)
+
+		err = log.SetLevel("debug")
+		So(err, ShouldBeNil)
+
+		buf.Reset()
+
+		logger.Debug().Msg("global debug")
+		syncLog.Warn().Msg("sync warn")
+		So(buf.String(), ShouldContainSubstring, "global debug")
+		So(buf.String(), ShouldNotContainSubstring, "sync warn")
+
+		So(log.GetLevels(), ShouldResemble, log.Levels{
+			Level:      "debug",
+			Components: map[string]string{log.ComponentStorage: "debug", log.ComponentSync: "error"},
+		})
+
+		// an empty level gives the component the global level back
+		err = log.SetComponentLevel(log.ComponentSync, "")
+		So(err, ShouldBeNil)
+
+		buf.Reset()
+
+		syncLog.Warn().Msg("sync warn")
+		So(buf.String(), ShouldContainSubstring, "sync warn")
+
+		err = log.SetLevels(log.Levels{Level: "warn"})
+		So(err, ShouldBeNil)
+		So(log.GetLevels(), ShouldResemble, log.Levels{Level: "warn", Components: map[string]string{}})
+
+		buf.Reset()
+
+		storageLog.Info().Msg("storage info")
+		So(buf.String(), ShouldBeEmpty)
+	})
+
+	Convey("Get error with an invalid level or an unknown component", t, func() {
+		err := log.SetLevel("verbose")
+		So(errors.Is(err, zerr.ErrBadLogLevel), ShouldBeTrue)
+
+		err = log.SetLevel("")
+		So(errors.Is(err, zerr.ErrBadLogLevel), ShouldBeTrue)
+
+		err = log.SetComponentLevel("metadb", "debug")
+		So(errors.Is(err, zerr.ErrUnknownLogComponent), ShouldBeTrue)
+
+		err = log.SetComponentLevel(log.ComponentSearch, "verbose")
+		So(errors.Is(err, zerr.ErrBadLogLevel), ShouldBeTrue)
+
+		err = log.SetLevels(log.Levels{Level: "info", Components: map[string]string{"metadb": "debug"}})
+		So(errors.Is(err, zerr.ErrUnknownLogComponent), ShouldBeTrue)
+
+		err = log.SetLevels(log.Levels{Level: "info", Components: map[string]string{log.ComponentSearch: ""}})
+		So(errors.Is(err, zerr.ErrBadLogLevel), ShouldBeTrue)
+	})
+}
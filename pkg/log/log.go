@@ -1,6 +1,7 @@
 package log
 
 import (
+	"io"
 	"os"
 	"runtime"
 	"strconv"
@@ -26,33 +27,21 @@ func (l Logger) Println(v ...interface{}) {
 }
 
 func NewLogger(level, output string) Logger {
-	loggerSetTimeFormat.Do(func() {
-		zerolog.TimeFieldFormat = time.RFC3339Nano
-	})
+	if output == "" {
+		return NewLoggerWithWriter(level, os.Stdout)
+	}
 
-	lvl, err := zerolog.ParseLevel(level)
+	file, err := os.OpenFile(output, os.O_APPEND|os.O_WRONLY|os.O_CREATE, defaultPerms)
 	if err != nil {
 		panic(err)
 	}
 
-	zerolog.SetGlobalLevel(lvl)
-
-	var log zerolog.Logger
-
-	if output == "" {
-		log = zerolog.New(os.Stdout)
-	} else {
-		file, err := os.OpenFile(output, os.O_APPEND|os.O_WRONLY|os.O_CREATE, defaultPerms)
-		if err != nil {
-			panic(err)
-		}
-		log = zerolog.New(file)
-	}
-
-	return Logger{Logger: log.Hook(goroutineHook{}).With().Caller().Timestamp().Logger()}
+	return NewLoggerWithWriter(level, file)
 }
 
-func NewAuditLogger(level, audit string) *Logger {
+// NewLoggerWithWriter returns a logger writing to the writer, e.g. a SyslogWriter or a JournaldWriter. The
+// sensitive data is masked, see Redact.
+func NewLoggerWithWriter(level string, writer io.Writer) Logger {
 	loggerSetTimeFormat.Do(func() {
 		zerolog.TimeFieldFormat = time.RFC3339Nano
 	})
@@ -62,18 +51,12 @@ func NewAuditLogger(level, audit string) *Logger {
 		panic(err)
 	}
 
-	zerolog.SetGlobalLevel(lvl)
+	// the components which have their own level keep it
+	setGlobalLevel(lvl)
 
-	var auditLog zerolog.Logger
+	log := zerolog.New(newRedactingWriter(writer)).Sample(componentLevelSampler{})
 
-	auditFile, err := os.OpenFile(audit, os.O_APPEND|os.O_WRONLY|os.O_CREATE, defaultPerms)
-	if err != nil {
-		panic(err)
-	}
-
-	auditLog = zerolog.New(auditFile)
-
-	return &Logger{Logger: auditLog.With().Timestamp().Logger()}
+	return Logger{Logger: log.Hook(goroutineHook{}).With().Caller().Timestamp().Logger()}
 }
 
 // GoroutineID adds goroutine-id to logs to help debug concurrency issues.
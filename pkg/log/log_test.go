@@ -75,6 +75,38 @@ func TestAuditLogMessages(t *testing.T) {
 		ctlrManager.StartAndWait(port)
 		defer ctlrManager.StopServer()
 
+		Convey("The audit record is written before the response", func() {
+			path := "/v2/" + AuthorizedNamespace + "/blobs/uploads/"
+			resp, err := resty.R().SetBasicAuth(username, passphrase).Post(baseURL + path)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusAccepted)
+			So(resp.Header().Get("Location"), ShouldNotBeEmpty)
+
+			byteValue, err := os.ReadFile(auditPath)
+			So(err, ShouldBeNil)
+
+			var auditLog AuditLog
+			err = json.Unmarshal(byteValue, &auditLog)
+			So(err, ShouldBeNil)
+			So(auditLog.Object, ShouldEqual, path)
+		})
+
+		Convey("The requests fail if the audit record can't be written", func() {
+			err := ctlr.Audit.Close()
+			So(err, ShouldBeNil)
+
+			resp, err := resty.R().SetBasicAuth(username, passphrase).
+				Post(baseURL + "/v2/" + AuthorizedNamespace + "/blobs/uploads/")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusInternalServerError)
+			So(resp.Header().Get("Location"), ShouldBeEmpty)
+
+			// the requests which aren't audited still succeed
+			resp, err = resty.R().SetBasicAuth(username, passphrase).Get(baseURL + "/v2/")
+			So(err, ShouldBeNil)
+			So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		})
+
 		Convey("Open auditLog file", func() {
 			auditFile, err := os.Open(auditPath)
 			if err != nil {
@@ -292,7 +324,8 @@ func TestAuditLogMessages(t *testing.T) {
 
 func TestLogErrors(t *testing.T) {
 	Convey("Get error with unknown log level", t, func() {
-		So(func() { _ = log.NewLogger("invalid", "test.out") }, ShouldPanic)
+		logPath := path.Join(t.TempDir(), "test.out")
+		So(func() { _ = log.NewLogger("invalid", logPath) }, ShouldPanic)
 	})
 
 	Convey("Get error when opening log file", t, func() {
@@ -307,17 +340,13 @@ func TestLogErrors(t *testing.T) {
 }
 
 func TestNewAuditLogger(t *testing.T) {
-	Convey("Get error with unknown audit log level", t, func() {
-		So(func() { _ = log.NewAuditLogger("invalid", "test.out") }, ShouldPanic)
-	})
-
 	Convey("Get error when opening audit file", t, func() {
 		dir := t.TempDir()
 		logPath := path.Join(dir, "logFile")
 		err := os.WriteFile(logPath, []byte{}, 0o000)
 		So(err, ShouldBeNil)
 		So(func() {
-			_ = log.NewAuditLogger(zerolog.DebugLevel.String(), logPath)
+			_ = log.NewAuditLogger(logPath, log.Rotation{})
 		}, ShouldPanic)
 	})
 }
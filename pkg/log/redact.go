@@ -0,0 +1,138 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// RedactedValue replaces the sensitive data in the log.
+const RedactedValue = "******"
+
+// the capture groups of the patterns are masked, the whole match if there isn't any group.
+//
+//nolint:gochecknoglobals,lll
+var defaultRedactionPatterns = []*regexp.Regexp{
+	// basic auth credentials and bearer tokens
+	regexp.MustCompile(`(?i)\b(?:basic|bearer)\s+([A-Za-z0-9\-._~+/]+=*)`),
+	// the headers carrying credentials, e.g. in the request dumps
+	regexp.MustCompile(`(?i)"(?:authorization|proxy-authorization|cookie|set-cookie|x-registry-auth|x-amz-security-token)"\s*:\s*\[?\s*"((?:[^"\\]|\\.)*)"`),
+	// the fields named like passwords, tokens, secrets and keys
+	regexp.MustCompile(`(?i)"[a-z0-9_\-]*(?:password|passwd|passphrase|secret|token|apikey|secretkey|accesskey)"\s*:\s*"((?:[^"\\]|\\.)*)"`),
+	// zot api keys
+	regexp.MustCompile(`\bzak_([0-9a-fA-F]{32})\b`),
+	// the signatures and credentials of the presigned urls of S3, GCS and Azure
+	regexp.MustCompile(`(?i)(?:[?&]|\\u0026)(?:x-amz-signature|x-amz-credential|x-amz-security-token|x-goog-signature|x-goog-credential|signature|sig|awsaccesskeyid)=([^&"\s\\]*)`),
+	// the passwords in the urls
+	regexp.MustCompile(`://[^/\s:@"]+:([^/\s@"]+)@`),
+}
+
+//nolint:gochecknoglobals
+var extraRedactionPatterns atomic.Pointer[[]*regexp.Regexp]
+
+// SetRedactionPatterns sets the regular expressions of the sensitive data masked in every log, on top of the
+// default ones: the authorization headers, tokens, passwords and presigned urls.
+func SetRedactionPatterns(patterns []string) error {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+
+		compiled = append(compiled, regex)
+	}
+
+	extraRedactionPatterns.Store(&compiled)
+
+	return nil
+}
+
+// Redact masks the sensitive data of the line.
+func Redact(line []byte) []byte {
+	for _, pattern := range defaultRedactionPatterns {
+		line = maskMatches(pattern, line)
+	}
+
+	if extraPatterns := extraRedactionPatterns.Load(); extraPatterns != nil {
+		for _, pattern := range *extraPatterns {
+			line = maskMatches(pattern, line)
+		}
+	}
+
+	return line
+}
+
+func maskMatches(pattern *regexp.Regexp, line []byte) []byte {
+	matches := pattern.FindAllSubmatchIndex(line, -1)
+	if matches == nil {
+		return line
+	}
+
+	var buf bytes.Buffer
+
+	last := 0
+
+	for _, match := range matches {
+		spans := [][]int{match[:2]}
+
+		if pattern.NumSubexp() > 0 {
+			spans = spans[:0]
+
+			for group := 1; group <= pattern.NumSubexp(); group++ {
+				if match[2*group] >= 0 {
+					spans = append(spans, match[2*group:2*group+2])
+				}
+			}
+		}
+
+		for _, span := range spans {
+			if span[0] < last {
+				continue
+			}
+
+			buf.Write(line[last:span[0]])
+			buf.WriteString(RedactedValue)
+
+			last = span[1]
+		}
+	}
+
+	buf.Write(line[last:])
+
+	return buf.Bytes()
+}
+
+// redactingWriter masks the sensitive data of the lines before writing them.
+type redactingWriter struct {
+	writer io.Writer
+}
+
+func newRedactingWriter(writer io.Writer) redactingWriter {
+	return redactingWriter{writer: writer}
+}
+
+func (rw redactingWriter) Write(line []byte) (int, error) {
+	return rw.WriteLevel(zerolog.NoLevel, line)
+}
+
+// WriteLevel passes the level on to the writers which use it, like the syslog and journald ones.
+func (rw redactingWriter) WriteLevel(level zerolog.Level, line []byte) (int, error) {
+	var err error
+
+	if levelWriter, ok := rw.writer.(zerolog.LevelWriter); ok {
+		_, err = levelWriter.WriteLevel(level, Redact(line))
+	} else {
+		_, err = rw.writer.Write(Redact(line))
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(line), nil
+}
@@ -0,0 +1,229 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFmt     = "2006-01-02T15-04-05.000"
+	backupSeparator   = "."
+	compressedBackup  = ".gz"
+	bytesInMegabyte   = 1024 * 1024
+	compressedTmpFile = ".tmp"
+	hiddenFilePrefix  = "."
+)
+
+// Rotation tells when a log file is rotated and how long the rotated files are kept.
+type Rotation struct {
+	// rotate the file when it gets bigger than MaxSize megabytes, not rotated if 0
+	MaxSize int
+	// rotate the file every Interval, not rotated on time if 0
+	Interval time.Duration
+	// the number of rotated files kept, all are kept if 0
+	MaxBackups int
+	// delete the rotated files older than MaxAge, kept if 0
+	MaxAge time.Duration
+	// gzip the rotated files
+	Compress bool
+}
+
+// rotatingFile is a file renamed to a timestamped backup when it gets too big or too old, the backups are
+// compressed if told and the old ones are deleted.
+type rotatingFile struct {
+	path        string
+	rotation    Rotation
+	file        *os.File
+	size        int64
+	openedAt    time.Time
+	syncWrites  bool
+	compressing sync.WaitGroup
+}
+
+// newRotatingFile opens the file, every write is synced to the disk if syncWrites is set.
+func newRotatingFile(path string, rotation Rotation, syncWrites bool) (*rotatingFile, error) {
+	rotFile := &rotatingFile{path: path, rotation: rotation, syncWrites: syncWrites}
+
+	if err := rotFile.open(); err != nil {
+		return nil, err
+	}
+
+	rotFile.prune()
+
+	return rotFile, nil
+}
+
+func (rf *rotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, defaultPerms)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return err
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = time.Now()
+
+	return nil
+}
+
+// Write writes the line, rotating the file first if it would get too big or is too old.
+func (rf *rotatingFile) Write(line []byte) (int, error) {
+	if rf.shouldRotate(len(line)) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(line)
+	rf.size += int64(n)
+
+	if err != nil || !rf.syncWrites {
+		return n, err
+	}
+
+	return n, rf.file.Sync()
+}
+
+func (rf *rotatingFile) shouldRotate(length int) bool {
+	if rf.size == 0 {
+		return false
+	}
+
+	maxSize := int64(rf.rotation.MaxSize) * bytesInMegabyte
+	if maxSize > 0 && rf.size+int64(length) > maxSize {
+		return true
+	}
+
+	return rf.rotation.Interval > 0 && time.Since(rf.openedAt) >= rf.rotation.Interval
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+
+	backup := rf.path + backupSeparator + time.Now().UTC().Format(backupTimeFmt)
+	if err := os.Rename(rf.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate the log %s: %w", rf.path, err)
+	}
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	if !rf.rotation.Compress {
+		rf.prune()
+
+		return nil
+	}
+
+	// compressing a big file takes a while, the writes don't wait for it
+	rf.compressing.Add(1)
+
+	go func() {
+		defer rf.compressing.Done()
+
+		_ = compressFile(backup)
+
+		rf.prune()
+	}()
+
+	return nil
+}
+
+// prune deletes the backups beyond the max number of backups and the ones older than the max age.
+func (rf *rotatingFile) prune() {
+	if rf.rotation.MaxBackups == 0 && rf.rotation.MaxAge == 0 {
+		return
+	}
+
+	backups, err := filepath.Glob(rf.path + backupSeparator + "*")
+	if err != nil {
+		return
+	}
+
+	// the names end with the rotation time, the newest are last
+	sort.Strings(backups)
+
+	for idx, backup := range backups {
+		if rf.rotation.MaxBackups > 0 && idx < len(backups)-rf.rotation.MaxBackups {
+			_ = os.Remove(backup)
+
+			continue
+		}
+
+		if rf.rotation.MaxAge > 0 {
+			rotatedAt, err := time.Parse(backupTimeFmt, strings.TrimSuffix(
+				strings.TrimPrefix(backup, rf.path+backupSeparator), compressedBackup))
+			if err == nil && time.Since(rotatedAt) > rf.rotation.MaxAge {
+				_ = os.Remove(backup)
+			}
+		}
+	}
+}
+
+// Close closes the file once the backups are compressed.
+func (rf *rotatingFile) Close() error {
+	rf.compressing.Wait()
+
+	return rf.file.Close()
+}
+
+// compressFile gzips the file to file.gz and deletes it, it is kept if it can't be compressed.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// hidden, not matched by the backups glob until it is complete
+	tmpPath := filepath.Join(filepath.Dir(path),
+		hiddenFilePrefix+filepath.Base(path)+compressedBackup+compressedTmpFile)
+
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultPerms)
+	if err != nil {
+		return err
+	}
+
+	gzWriter := gzip.NewWriter(dst)
+
+	_, err = io.Copy(gzWriter, src)
+	if err == nil {
+		err = gzWriter.Close()
+	}
+
+	if err == nil {
+		err = dst.Sync()
+	}
+
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpPath, path+compressedBackup)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpPath)
+
+		return err
+	}
+
+	return os.Remove(path)
+}
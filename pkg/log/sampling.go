@@ -0,0 +1,113 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// the messages are tracked until there are too many of them, e.g. because they aren't constant strings.
+	maxSampledMessages = 10000
+
+	droppedFieldName = "dropped"
+)
+
+// LevelSampling limits the lines logged with a level.
+type LevelSampling struct {
+	// log at most Burst lines with the same message every Period, not limited if 0
+	Burst  uint32
+	Period time.Duration
+	// log only one line of every Every, all are logged if 0
+	Every uint32
+}
+
+// WithSampling returns a logger limiting the lines of the levels, keyed by level name. The first line logged
+// after some were dropped has the number of lines with the same message dropped in the previous period.
+func (l Logger) WithSampling(sampling map[string]LevelSampling) Logger {
+	if len(sampling) == 0 {
+		return l
+	}
+
+	hook := samplingHook{levels: map[zerolog.Level]*levelSampler{}}
+
+	for levelName, levelSampling := range sampling {
+		level, err := zerolog.ParseLevel(levelName)
+		if err != nil {
+			panic(err)
+		}
+
+		hook.levels[level] = &levelSampler{config: levelSampling, windows: map[string]*samplingWindow{}}
+	}
+
+	return Logger{Logger: l.Logger.Hook(hook)}
+}
+
+type samplingHook struct {
+	levels map[zerolog.Level]*levelSampler
+}
+
+func (h samplingHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	sampler, ok := h.levels[level]
+	if !ok {
+		return
+	}
+
+	if !sampler.sample(e, msg) {
+		e.Discard()
+	}
+}
+
+type levelSampler struct {
+	config  LevelSampling
+	counter uint32
+	lock    sync.Mutex
+	windows map[string]*samplingWindow
+}
+
+type samplingWindow struct {
+	start   time.Time
+	count   uint32
+	dropped uint32
+}
+
+func (ls *levelSampler) sample(e *zerolog.Event, msg string) bool {
+	if ls.config.Every > 1 && atomic.AddUint32(&ls.counter, 1)%ls.config.Every != 1 {
+		return false
+	}
+
+	if ls.config.Burst == 0 || ls.config.Period == 0 {
+		return true
+	}
+
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+
+	now := time.Now()
+
+	window, ok := ls.windows[msg]
+	if !ok || now.Sub(window.start) >= ls.config.Period {
+		if !ok && len(ls.windows) >= maxSampledMessages {
+			ls.windows = map[string]*samplingWindow{}
+		}
+
+		if ok && window.dropped > 0 {
+			e.Uint32(droppedFieldName, window.dropped)
+		}
+
+		window = &samplingWindow{start: now}
+		ls.windows[msg] = window
+	}
+
+	if window.count >= ls.config.Burst {
+		window.dropped++
+
+		return false
+	}
+
+	window.count++
+
+	return true
+}
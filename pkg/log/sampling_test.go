@@ -0,0 +1,81 @@
+package log_test
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/log"
+)
+
+func TestLogSampling(t *testing.T) {
+	Convey("Limit the repeated lines", t, func() {
+		logFile := path.Join(t.TempDir(), "zot.log")
+
+		logger := log.NewLogger("debug", logFile).WithSampling(map[string]log.LevelSampling{
+			"debug": {Burst: 2, Period: 200 * time.Millisecond},
+		})
+
+		for i := 0; i < 5; i++ {
+			logger.Debug().Msg("blob path found")
+			logger.Debug().Msg("blob path not found")
+			logger.Info().Msg("not sampled")
+		}
+
+		content, err := os.ReadFile(logFile)
+		So(err, ShouldBeNil)
+		So(strings.Count(string(content), `"message":"blob path found"`), ShouldEqual, 2)
+		So(strings.Count(string(content), `"message":"blob path not found"`), ShouldEqual, 2)
+		So(strings.Count(string(content), `"message":"not sampled"`), ShouldEqual, 5)
+
+		// the next period tells how many lines were dropped
+		time.Sleep(250 * time.Millisecond)
+
+		logger.Debug().Msg("blob path found")
+
+		content, err = os.ReadFile(logFile)
+		So(err, ShouldBeNil)
+		So(strings.Count(string(content), `"message":"blob path found"`), ShouldEqual, 3)
+		So(string(content), ShouldContainSubstring, `"dropped":3`)
+	})
+
+	Convey("Log one line of every few", t, func() {
+		logFile := path.Join(t.TempDir(), "zot.log")
+
+		logger := log.NewLogger("debug", logFile).WithSampling(map[string]log.LevelSampling{
+			"error": {Every: 3},
+		})
+
+		for i := 0; i < 7; i++ {
+			logger.Error().Msg("failed")
+		}
+
+		content, err := os.ReadFile(logFile)
+		So(err, ShouldBeNil)
+		So(strings.Count(string(content), `"message":"failed"`), ShouldEqual, 3)
+	})
+
+	Convey("The loggers aren't sampled by default", t, func() {
+		logFile := path.Join(t.TempDir(), "zot.log")
+
+		logger := log.NewLogger("debug", logFile).WithSampling(nil)
+
+		for i := 0; i < 5; i++ {
+			logger.Debug().Msg("blob path found")
+		}
+
+		content, err := os.ReadFile(logFile)
+		So(err, ShouldBeNil)
+		So(strings.Count(string(content), `"message":"blob path found"`), ShouldEqual, 5)
+	})
+
+	Convey("Get error with an unknown level", t, func() {
+		So(func() {
+			_ = log.NewLogger("debug", "").WithSampling(map[string]log.LevelSampling{"unknown": {Every: 2}})
+		}, ShouldPanic)
+	})
+}
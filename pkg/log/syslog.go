@@ -0,0 +1,188 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultSyslogAddress is the socket of the local syslog daemon.
+	DefaultSyslogAddress = "/dev/log"
+
+	// DefaultSyslogTag is the app name of the application log messages, and their journald identifier.
+	DefaultSyslogTag = "zot"
+
+	// SyslogFacilityDaemon and SyslogFacilityAuth are the facilities of the application and the audit logs.
+	SyslogFacilityDaemon = 3
+	SyslogFacilityAuth   = 4
+
+	syslogVersion    = 1
+	syslogNilValue   = "-"
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// SyslogWriter sends every line to syslog as an RFC 5424 message, octet counted over TCP and one per datagram
+// over UDP and unix sockets.
+type SyslogWriter struct {
+	network  string
+	address  string
+	tag      string
+	facility int
+	hostname string
+	lock     sync.Mutex
+	conn     net.Conn
+	closed   bool
+}
+
+// NewSyslogWriter returns a writer sending the lines to the syslog server, the network is "tcp", "udp" or "unix",
+// the local syslog daemon is used if it's empty.
+func NewSyslogWriter(network, address, tag string, facility int) (*SyslogWriter, error) {
+	if network == "" {
+		network = "unix"
+	}
+
+	if address == "" && network == "unix" {
+		address = DefaultSyslogAddress
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = syslogNilValue
+	}
+
+	writer := &SyslogWriter{
+		network:  network,
+		address:  address,
+		tag:      tag,
+		facility: facility,
+		hostname: hostname,
+	}
+
+	if err := writer.connect(); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (sw *SyslogWriter) connect() error {
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	switch sw.network {
+	case "unix":
+		// the local daemons listen on datagram sockets, some on stream ones
+		conn, err = net.Dial("unixgram", sw.address)
+		if err != nil {
+			conn, err = net.Dial("unix", sw.address)
+		}
+	case "tcp", "udp":
+		conn, err = net.Dial(sw.network, sw.address)
+	default:
+		err = fmt.Errorf("unsupported syslog network %s: %w", sw.network, net.UnknownNetworkError(sw.network))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sw.conn = conn
+
+	return nil
+}
+
+// Write sends the line with the info severity.
+func (sw *SyslogWriter) Write(line []byte) (int, error) {
+	return sw.WriteLevel(zerolog.NoLevel, line)
+}
+
+// WriteLevel sends the line with the severity of the level, reconnecting once if the connection was lost.
+func (sw *SyslogWriter) WriteLevel(level zerolog.Level, line []byte) (int, error) {
+	msg := sw.format(level, bytes.TrimRight(line, "\n"))
+
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+
+	if sw.closed {
+		return 0, os.ErrClosed
+	}
+
+	if sw.conn != nil {
+		if _, err := sw.conn.Write(msg); err == nil {
+			return len(line), nil
+		}
+
+		sw.conn.Close()
+		sw.conn = nil
+	}
+
+	if err := sw.connect(); err != nil {
+		return 0, err
+	}
+
+	if _, err := sw.conn.Write(msg); err != nil {
+		return 0, err
+	}
+
+	return len(line), nil
+}
+
+// format returns the RFC 5424 message, prefixed with its length over TCP (RFC 6587 octet counting).
+func (sw *SyslogWriter) format(level zerolog.Level, line []byte) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "<%d>%d %s %s %s %d %s %s ", sw.facility*8+syslogSeverity(level), syslogVersion,
+		time.Now().Format(syslogTimeFormat), sw.hostname, sw.tag, os.Getpid(), syslogNilValue, syslogNilValue)
+	buf.Write(line)
+
+	if sw.network != "tcp" {
+		return buf.Bytes()
+	}
+
+	return append([]byte(strconv.Itoa(buf.Len())+" "), buf.Bytes()...)
+}
+
+func (sw *SyslogWriter) Close() error {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+
+	sw.closed = true
+
+	if sw.conn == nil {
+		return nil
+	}
+
+	err := sw.conn.Close()
+	sw.conn = nil
+
+	return err
+}
+
+//nolint:gomnd // the RFC 5424 severities
+func syslogSeverity(level zerolog.Level) int {
+	switch level {
+	case zerolog.PanicLevel:
+		return 0
+	case zerolog.FatalLevel:
+		return 2
+	case zerolog.ErrorLevel:
+		return 3
+	case zerolog.WarnLevel:
+		return 4
+	case zerolog.DebugLevel, zerolog.TraceLevel:
+		return 7
+	case zerolog.InfoLevel, zerolog.NoLevel, zerolog.Disabled:
+		return 6
+	default:
+		return 6
+	}
+}
@@ -0,0 +1,213 @@
+package log_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/log"
+)
+
+func TestSyslogWriter(t *testing.T) {
+	Convey("Send the log to syslog over UDP", t, func() {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		writer, err := log.NewSyslogWriter("udp", conn.LocalAddr().String(), log.DefaultSyslogTag,
+			log.SyslogFacilityDaemon)
+		So(err, ShouldBeNil)
+		defer writer.Close()
+
+		logger := log.NewLoggerWithWriter("debug", writer)
+		logger.Error().Str("repository", "alpine").Msg("failed to sync")
+
+		msg := readPacket(conn)
+		// daemon facility, error severity
+		So(msg, ShouldStartWith, "<27>1 ")
+		So(msg, ShouldContainSubstring, " zot ")
+		So(msg, ShouldContainSubstring, `"repository":"alpine"`)
+		So(msg, ShouldContainSubstring, `"message":"failed to sync"`)
+		So(msg, ShouldNotEndWith, "\n")
+	})
+
+	Convey("Send the log to syslog over TCP", t, func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		defer listener.Close()
+
+		received := make(chan string, 1)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+
+			size, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+
+			msg := make([]byte, size)
+
+			if _, err := reader.Read(msg); err == nil {
+				received <- string(msg)
+			}
+		}()
+
+		writer, err := log.NewSyslogWriter("tcp", listener.Addr().String(), "tag", log.SyslogFacilityDaemon)
+		So(err, ShouldBeNil)
+		defer writer.Close()
+
+		logger := log.NewLoggerWithWriter("debug", writer)
+		logger.Debug().Msg("blob path found")
+
+		select {
+		case msg := <-received:
+			// daemon facility, debug severity, octet counted
+			So(msg, ShouldStartWith, "<31>1 ")
+			So(msg, ShouldContainSubstring, " tag ")
+			So(msg, ShouldContainSubstring, `"message":"blob path found"`)
+		case <-time.After(5 * time.Second):
+			So("no message received", ShouldBeEmpty)
+		}
+	})
+
+	Convey("Send the log to a unix socket", t, func() {
+		socket := path.Join(t.TempDir(), "syslog.sock")
+
+		conn, err := net.ListenPacket("unixgram", socket)
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		writer, err := log.NewSyslogWriter("", socket, log.DefaultSyslogTag, log.SyslogFacilityDaemon)
+		So(err, ShouldBeNil)
+
+		logger := log.NewLoggerWithWriter("debug", writer)
+		logger.Warn().Msg("disk almost full")
+
+		So(readPacket(conn), ShouldStartWith, "<28>1 ")
+
+		err = writer.Close()
+		So(err, ShouldBeNil)
+
+		_, err = writer.Write([]byte("closed"))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Get error with an unknown network or an unreachable server", t, func() {
+		_, err := log.NewSyslogWriter("http", "127.0.0.1:514", log.DefaultSyslogTag, log.SyslogFacilityDaemon)
+		So(err, ShouldNotBeNil)
+
+		_, err = log.NewSyslogWriter("unix", path.Join(t.TempDir(), "missing.sock"), log.DefaultSyslogTag,
+			log.SyslogFacilityDaemon)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestJournaldWriter(t *testing.T) {
+	Convey("Send the log to journald", t, func() {
+		socket := path.Join(t.TempDir(), "journal.sock")
+
+		conn, err := net.ListenPacket("unixgram", socket)
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		writer, err := log.NewJournaldWriter(socket, log.DefaultSyslogTag)
+		So(err, ShouldBeNil)
+		defer writer.Close()
+
+		logger := log.NewLoggerWithWriter("debug", writer)
+		logger.Error().Str("repository", "alpine").Int("count", 2).Str("error", "first\nsecond").
+			Msg("failed to sync")
+
+		fields := readJournalFields(readPacket(conn))
+		So(fields["PRIORITY"], ShouldEqual, "3")
+		So(fields["SYSLOG_IDENTIFIER"], ShouldEqual, "zot")
+		So(fields["MESSAGE"], ShouldEqual, "failed to sync")
+		So(fields["REPOSITORY"], ShouldEqual, "alpine")
+		So(fields["COUNT"], ShouldEqual, "2")
+		So(fields["ERROR"], ShouldEqual, "first\nsecond")
+		So(fields, ShouldContainKey, "TIME")
+		So(fields, ShouldContainKey, "CALLER")
+		So(fields, ShouldNotContainKey, "LEVEL")
+
+		// the lines which aren't json are sent as messages
+		_, err = writer.Write([]byte("not json\n"))
+		So(err, ShouldBeNil)
+
+		fields = readJournalFields(readPacket(conn))
+		So(fields["PRIORITY"], ShouldEqual, "6")
+		So(fields["MESSAGE"], ShouldEqual, "not json")
+	})
+
+	Convey("Get error when journald isn't running", t, func() {
+		_, err := log.NewJournaldWriter(path.Join(t.TempDir(), "missing.sock"), log.DefaultSyslogTag)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func readPacket(conn net.PacketConn) string {
+	buf := make([]byte, 65536)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		return ""
+	}
+
+	return string(buf[:n])
+}
+
+// readJournalFields parses the journald native protocol.
+func readJournalFields(packet string) map[string]string {
+	fields := map[string]string{}
+	reader := bufio.NewReader(bytes.NewBufferString(packet))
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fields
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+
+		if name, value, ok := strings.Cut(line, "="); ok {
+			fields[name] = value
+
+			continue
+		}
+
+		var size uint64
+
+		if err := binary.Read(reader, binary.LittleEndian, &size); err != nil {
+			return fields
+		}
+
+		value := make([]byte, size+1)
+
+		if _, err := reader.Read(value); err != nil {
+			return fields
+		}
+
+		fields[line] = string(value[:size])
+	}
+}
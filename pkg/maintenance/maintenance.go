@@ -0,0 +1,395 @@
+// Package maintenance tracks the storage maintenance jobs run by the scheduler, the garbage collection, scrub,
+// dedupe and the reconciliation of the metadata, so they can be triggered, paused and followed by the admins
+// through the mgmt extension.
+package maintenance
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/scheduler"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	GC        = "gc"
+	Scrub     = "scrub"
+	Dedupe    = "dedupe"
+	Reconcile = "reconcile"
+
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+)
+
+// GeneratorsFunc returns the task generators running a job once, usually one for each image store.
+type GeneratorsFunc func() []scheduler.TaskGenerator
+
+// Status is the state of a job, with the progress of its current run, or of the last one if it isn't running.
+type Status struct {
+	Job         string     `json:"job"`
+	Paused      bool       `json:"paused"`
+	Running     bool       `json:"running"`
+	Trigger     string     `json:"trigger,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+	TasksDone   int        `json:"tasksDone"`
+	TasksFailed int        `json:"tasksFailed"`
+	TasksQueued int        `json:"tasksQueued"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+type job struct {
+	generators GeneratorsFunc
+	status     Status
+	// the generators in the middle of a run
+	active int
+}
+
+/*
+Manager keeps the state of the maintenance jobs. The generators of a job are wrapped to count the tasks they
+generate and run, and they don't generate tasks while the job is paused. Pausing a job doesn't stop the tasks
+already queued, and the jobs are resumed when zot restarts.
+*/
+type Manager struct {
+	jobs      map[string]*job
+	paused    map[string]bool
+	scheduler *scheduler.Scheduler
+	lock      *sync.Mutex
+	log       log.Logger
+}
+
+func NewManager(log log.Logger) *Manager {
+	return &Manager{
+		jobs:   map[string]*job{},
+		paused: map[string]bool{},
+		lock:   &sync.Mutex{},
+		log:    log,
+	}
+}
+
+// Start sets the scheduler the jobs run with. The jobs are registered again with each scheduler, e.g. when
+// the config is reloaded, only their paused state is kept.
+func (manager *Manager) Start(sch *scheduler.Scheduler) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	manager.scheduler = sch
+	manager.jobs = map[string]*job{}
+}
+
+// Register enables the job, the generators are used for its manual runs.
+func (manager *Manager) Register(name string, generators GeneratorsFunc) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	manager.getJob(name).generators = generators
+}
+
+// SubmitGenerator submits a generator of the job to the scheduler, its runs are the scheduled runs of the job.
+func (manager *Manager) SubmitGenerator(name string, generator scheduler.TaskGenerator, interval time.Duration,
+	priority scheduler.Priority,
+) {
+	manager.lock.Lock()
+	sch := manager.scheduler
+	trackedGen := &trackedGenerator{manager: manager, job: manager.getJob(name), name: name, generator: generator}
+	manager.lock.Unlock()
+
+	// the scheduled runs are shared by the zot instances using the same storage
+	sch.SubmitSharedGenerator(name, trackedGen, interval, priority)
+}
+
+// Run starts a manual run of the job, unless it's paused or already running.
+func (manager *Manager) Run(name string) (Status, error) {
+	manager.lock.Lock()
+
+	runJob, ok := manager.jobs[name]
+	if !ok || runJob.generators == nil {
+		manager.lock.Unlock()
+
+		return Status{}, zerr.ErrMaintenanceJobNotFound
+	}
+
+	if manager.paused[name] {
+		manager.lock.Unlock()
+
+		return manager.getStatus(name, runJob), zerr.ErrMaintenanceJobPaused
+	}
+
+	if runJob.status.Running {
+		manager.lock.Unlock()
+
+		return manager.getStatus(name, runJob), zerr.ErrMaintenanceJobRunning
+	}
+
+	generators := runJob.generators()
+	trackedGens := make([]*trackedGenerator, 0, len(generators))
+
+	// all the generators are started before submitting any, the run only ends once all of them are done
+	for _, generator := range generators {
+		manager.startGenerator(runJob, TriggerManual)
+
+		trackedGens = append(trackedGens, &trackedGenerator{
+			manager:   manager,
+			job:       runJob,
+			name:      name,
+			generator: generator,
+			started:   true,
+		})
+	}
+
+	if len(generators) == 0 {
+		manager.startGenerator(runJob, TriggerManual)
+		manager.finishGenerator(runJob)
+	}
+
+	sch := manager.scheduler
+	status := manager.getStatus(name, runJob)
+
+	// the scheduler calls the generators with its own lock held, it's not taken with the lock of the manager
+	manager.lock.Unlock()
+
+	for _, generator := range trackedGens {
+		// a one time run, the generator is dropped by the scheduler once it's done
+		sch.SubmitGenerator(generator, time.Duration(0), scheduler.MediumPriority)
+	}
+
+	manager.log.Info().Str("job", name).Msg("maintenance: manual run started")
+
+	return status, nil
+}
+
+// Pause stops the job from generating new tasks, the tasks already queued still run.
+func (manager *Manager) Pause(name string) (Status, error) {
+	return manager.setPaused(name, true)
+}
+
+// Resume lets the job generate tasks again, an interrupted run continues where it stopped.
+func (manager *Manager) Resume(name string) (Status, error) {
+	return manager.setPaused(name, false)
+}
+
+func (manager *Manager) Status(name string) (Status, error) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	statusJob, ok := manager.jobs[name]
+	if !ok {
+		return Status{}, zerr.ErrMaintenanceJobNotFound
+	}
+
+	return manager.getStatus(name, statusJob), nil
+}
+
+// Statuses returns the status of all the enabled jobs, sorted by name.
+func (manager *Manager) Statuses() []Status {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	statuses := make([]Status, 0, len(manager.jobs))
+
+	for name, statusJob := range manager.jobs {
+		statuses = append(statuses, manager.getStatus(name, statusJob))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Job < statuses[j].Job
+	})
+
+	return statuses
+}
+
+func (manager *Manager) setPaused(name string, paused bool) (Status, error) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	pausedJob, ok := manager.jobs[name]
+	if !ok {
+		return Status{}, zerr.ErrMaintenanceJobNotFound
+	}
+
+	manager.paused[name] = paused
+
+	manager.log.Info().Str("job", name).Bool("paused", paused).Msg("maintenance: job paused state changed")
+
+	return manager.getStatus(name, pausedJob), nil
+}
+
+func (manager *Manager) isPaused(name string) bool {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	return manager.paused[name]
+}
+
+// getJob returns the job, registering it if needed, the lock of the manager is held by the caller.
+func (manager *Manager) getJob(name string) *job {
+	registeredJob, ok := manager.jobs[name]
+	if !ok {
+		registeredJob = &job{status: Status{Job: name}}
+		manager.jobs[name] = registeredJob
+	}
+
+	return registeredJob
+}
+
+func (manager *Manager) getStatus(name string, statusJob *job) Status {
+	status := statusJob.status
+	status.Paused = manager.paused[name]
+
+	return status
+}
+
+// startGenerator starts a new run of the job if none of its generators is running yet.
+func (manager *Manager) startGenerator(runJob *job, trigger string) {
+	if runJob.active == 0 && runJob.status.TasksQueued == 0 {
+		now := time.Now()
+
+		runJob.status = Status{
+			Job:       runJob.status.Job,
+			Running:   true,
+			Trigger:   trigger,
+			StartedAt: &now,
+		}
+	}
+
+	runJob.active++
+}
+
+func (manager *Manager) finishGenerator(runJob *job) {
+	runJob.active--
+
+	manager.checkFinished(runJob)
+}
+
+func (manager *Manager) checkFinished(runJob *job) {
+	if runJob.status.Running && runJob.active == 0 && runJob.status.TasksQueued == 0 {
+		now := time.Now()
+
+		runJob.status.Running = false
+		runJob.status.FinishedAt = &now
+	}
+}
+
+type trackedGenerator struct {
+	manager   *Manager
+	job       *job
+	name      string
+	generator scheduler.TaskGenerator
+	started   bool
+}
+
+func (gen *trackedGenerator) Next() (scheduler.Task, error) {
+	gen.manager.lock.Lock()
+
+	if !gen.started {
+		gen.started = true
+		gen.manager.startGenerator(gen.job, TriggerScheduled)
+	}
+
+	gen.manager.lock.Unlock()
+
+	task, err := gen.generator.Next()
+	// the scheduler drops the task generated along with the end of the run
+	if err != nil || task == nil || gen.generator.IsDone() {
+		return task, err
+	}
+
+	gen.manager.lock.Lock()
+	gen.job.status.TasksQueued++
+	gen.manager.lock.Unlock()
+
+	return &trackedTask{manager: gen.manager, job: gen.job, task: task}, nil
+}
+
+func (gen *trackedGenerator) IsDone() bool {
+	return gen.generator.IsDone()
+}
+
+func (gen *trackedGenerator) IsReady() bool {
+	return !gen.manager.isPaused(gen.name) && gen.generator.IsReady()
+}
+
+func (gen *trackedGenerator) Reset() {
+	gen.generator.Reset()
+
+	gen.manager.lock.Lock()
+	defer gen.manager.lock.Unlock()
+
+	if gen.started {
+		gen.started = false
+		gen.manager.finishGenerator(gen.job)
+	}
+}
+
+type trackedTask struct {
+	manager *Manager
+	job     *job
+	task    scheduler.Task
+}
+
+func (task *trackedTask) DoWork(ctx context.Context) error {
+	err := task.task.DoWork(ctx)
+
+	task.manager.lock.Lock()
+	defer task.manager.lock.Unlock()
+
+	task.job.status.TasksQueued--
+
+	if err != nil {
+		task.job.status.TasksFailed++
+		task.job.status.LastError = err.Error()
+	} else {
+		task.job.status.TasksDone++
+	}
+
+	task.manager.checkFinished(task.job)
+
+	return err
+}
+
+// NewRepoTaskGenerator returns a generator of a task for each repo of the image store, one after the other.
+func NewRepoTaskGenerator(imgStore storageTypes.ImageStore, newTask func(repo string) scheduler.Task,
+) scheduler.TaskGenerator {
+	return &repoTaskGenerator{imgStore: imgStore, newTask: newTask}
+}
+
+type repoTaskGenerator struct {
+	imgStore storageTypes.ImageStore
+	newTask  func(repo string) scheduler.Task
+	lastRepo string
+	done     bool
+}
+
+func (gen *repoTaskGenerator) Next() (scheduler.Task, error) {
+	repo, err := gen.imgStore.GetNextRepository(gen.lastRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if repo == "" {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.lastRepo = repo
+
+	return gen.newTask(repo), nil
+}
+
+func (gen *repoTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *repoTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *repoTaskGenerator) Reset() {
+	gen.lastRepo = ""
+	gen.done = false
+}
@@ -0,0 +1,191 @@
+package maintenance_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/maintenance"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+var errTaskFailed = errors.New("task failed")
+
+type countingGenerator struct {
+	tasks     int
+	generated int
+	failed    bool
+	done      bool
+	runs      *atomic.Int32
+}
+
+func (gen *countingGenerator) Next() (scheduler.Task, error) {
+	if gen.generated == gen.tasks {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.generated++
+
+	return &countingTask{failed: gen.failed && gen.generated == 1, runs: gen.runs}, nil
+}
+
+func (gen *countingGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *countingGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *countingGenerator) Reset() {
+	gen.generated = 0
+	gen.done = false
+}
+
+type countingTask struct {
+	failed bool
+	runs   *atomic.Int32
+}
+
+func (task *countingTask) DoWork(ctx context.Context) error {
+	task.runs.Add(1)
+
+	if task.failed {
+		return errTaskFailed
+	}
+
+	return nil
+}
+
+func waitForJob(manager *maintenance.Manager, job string) maintenance.Status {
+	for i := 0; i < 30; i++ {
+		status, err := manager.Status(job)
+		So(err, ShouldBeNil)
+
+		if !status.Running {
+			return status
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	status, _ := manager.Status(job)
+
+	return status
+}
+
+func TestMaintenanceManager(t *testing.T) {
+	Convey("Track the runs of the maintenance jobs", t, func() {
+		logger := log.NewLogger("debug", "")
+		ctx, cancel := context.WithCancel(context.Background())
+
+		defer cancel()
+
+		sch := scheduler.NewScheduler(config.New(), logger)
+		sch.RunScheduler(ctx)
+
+		manager := maintenance.NewManager(logger)
+		manager.Start(sch)
+
+		runs := &atomic.Int32{}
+
+		manager.Register(maintenance.GC, func() []scheduler.TaskGenerator {
+			return []scheduler.TaskGenerator{
+				&countingGenerator{tasks: 2, runs: runs},
+				&countingGenerator{tasks: 1, failed: true, runs: runs},
+			}
+		})
+
+		_, err := manager.Status(maintenance.Scrub)
+		So(errors.Is(err, zerr.ErrMaintenanceJobNotFound), ShouldBeTrue)
+
+		_, err = manager.Run(maintenance.Scrub)
+		So(errors.Is(err, zerr.ErrMaintenanceJobNotFound), ShouldBeTrue)
+
+		status, err := manager.Run(maintenance.GC)
+		So(err, ShouldBeNil)
+		So(status.Running, ShouldBeTrue)
+		So(status.Trigger, ShouldEqual, maintenance.TriggerManual)
+
+		_, err = manager.Run(maintenance.GC)
+		So(errors.Is(err, zerr.ErrMaintenanceJobRunning), ShouldBeTrue)
+
+		status = waitForJob(manager, maintenance.GC)
+		So(status.Running, ShouldBeFalse)
+		So(status.TasksDone, ShouldEqual, 2)
+		So(status.TasksFailed, ShouldEqual, 1)
+		So(status.LastError, ShouldEqual, errTaskFailed.Error())
+		So(status.FinishedAt, ShouldNotBeNil)
+		So(runs.Load(), ShouldEqual, 3)
+
+		Convey("A paused job doesn't generate tasks", func() {
+			status, err := manager.Pause(maintenance.GC)
+			So(err, ShouldBeNil)
+			So(status.Paused, ShouldBeTrue)
+
+			_, err = manager.Run(maintenance.GC)
+			So(errors.Is(err, zerr.ErrMaintenanceJobPaused), ShouldBeTrue)
+
+			runs.Store(0)
+
+			manager.SubmitGenerator(maintenance.GC, &countingGenerator{tasks: 1, runs: runs}, time.Hour,
+				scheduler.MediumPriority)
+
+			time.Sleep(6 * time.Second)
+			So(runs.Load(), ShouldEqual, 0)
+
+			status, err = manager.Resume(maintenance.GC)
+			So(err, ShouldBeNil)
+			So(status.Paused, ShouldBeFalse)
+
+			for i := 0; i < 30 && runs.Load() == 0; i++ {
+				time.Sleep(time.Second)
+			}
+
+			status = waitForJob(manager, maintenance.GC)
+			So(runs.Load(), ShouldEqual, 1)
+			So(status.Trigger, ShouldEqual, maintenance.TriggerScheduled)
+			So(status.TasksDone, ShouldEqual, 1)
+			So(status.TasksFailed, ShouldEqual, 0)
+
+			statuses := manager.Statuses()
+			So(len(statuses), ShouldEqual, 1)
+			So(statuses[0].Job, ShouldEqual, maintenance.GC)
+		})
+
+		Convey("The jobs are registered again with a new scheduler, the paused state is kept", func() {
+			_, err := manager.Pause(maintenance.GC)
+			So(err, ShouldBeNil)
+
+			manager.Start(sch)
+
+			_, err = manager.Status(maintenance.GC)
+			So(errors.Is(err, zerr.ErrMaintenanceJobNotFound), ShouldBeTrue)
+
+			manager.Register(maintenance.GC, func() []scheduler.TaskGenerator { return nil })
+
+			status, err := manager.Status(maintenance.GC)
+			So(err, ShouldBeNil)
+			So(status.Paused, ShouldBeTrue)
+			So(status.StartedAt, ShouldBeNil)
+
+			_, err = manager.Resume(maintenance.GC)
+			So(err, ShouldBeNil)
+
+			// nothing to run
+			status, err = manager.Run(maintenance.GC)
+			So(err, ShouldBeNil)
+			So(status.Running, ShouldBeFalse)
+			So(status.FinishedAt, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package meta
+
+import (
+	"context"
+	"time"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+	"zotregistry.io/zot/pkg/scheduler"
+)
+
+const (
+	// DefaultUserActivityRetention is how long the actions of the users are kept if the config doesn't tell.
+	DefaultUserActivityRetention = 90 * 24 * time.Hour
+
+	userActivityRetentionInterval = 24 * time.Hour
+)
+
+// RecordUserActivity adds the action to the activity of the authenticated user of the request, if the activity
+// is enabled. The actions of the anonymous users aren't recorded, and the action is already done, so a failure to
+// record it is only logged.
+func RecordUserActivity(ctx context.Context, conf *config.Config, metaDB mTypes.MetaDB,
+	activity mTypes.UserActivity, log log.Logger,
+) {
+	if metaDB == nil || !conf.IsUserActivityEnabled() {
+		return
+	}
+
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil || userAc == nil || userAc.IsAnonymous() {
+		return
+	}
+
+	activity.User = userAc.GetUsername()
+	activity.Timestamp = time.Now()
+
+	if err := metaDB.RecordUserActivity(activity); err != nil {
+		log.Error().Err(err).Str("user", activity.User).Str("action", activity.Action).
+			Msg("failed to record the user activity")
+	}
+}
+
+// NewUserActivityRetentionTaskGenerator returns a generator deleting the actions older than the retention every
+// day, with the interval it should be submitted with.
+func NewUserActivityRetentionTaskGenerator(metaDB mTypes.MetaDB, retention time.Duration, log log.Logger,
+) (scheduler.TaskGenerator, time.Duration) {
+	if retention == 0 {
+		retention = DefaultUserActivityRetention
+	}
+
+	return &userActivityRetentionTaskGenerator{
+		metaDB:    metaDB,
+		retention: retention,
+		log:       log,
+	}, userActivityRetentionInterval
+}
+
+type userActivityRetentionTaskGenerator struct {
+	metaDB    mTypes.MetaDB
+	retention time.Duration
+	generated bool
+	done      bool
+	log       log.Logger
+}
+
+func (gen *userActivityRetentionTaskGenerator) Next() (scheduler.Task, error) {
+	if gen.generated {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.generated = true
+
+	return &userActivityRetentionTask{gen.metaDB, time.Now().Add(-gen.retention), gen.log}, nil
+}
+
+func (gen *userActivityRetentionTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *userActivityRetentionTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *userActivityRetentionTaskGenerator) Reset() {
+	gen.generated = false
+	gen.done = false
+}
+
+type userActivityRetentionTask struct {
+	metaDB mTypes.MetaDB
+	before time.Time
+	log    log.Logger
+}
+
+func (task *userActivityRetentionTask) DoWork(ctx context.Context) error {
+	if err := task.metaDB.DeleteUserActivity(task.before); err != nil {
+		task.log.Error().Err(err).Msg("failed to delete the expired user activity")
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package boltdb
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.etcd.io/bbolt"
+
+	"zotregistry.io/zot/pkg/meta/common"
+	"zotregistry.io/zot/pkg/meta/version"
+)
+
+// Backup writes the buckets as they are in a single read transaction, the writes aren't blocked meanwhile.
+func (bdw *BoltDB) Backup(ctx context.Context, writer io.Writer) error {
+	return bdw.DB.View(func(tx *bbolt.Tx) error {
+		dbVersion := string(tx.Bucket([]byte(VersionBucket)).Get([]byte(version.DBVersionKey)))
+
+		backupWriter, err := common.NewBackupWriter(writer, dbVersion)
+		if err != nil {
+			return err
+		}
+
+		for _, bucketName := range common.GetBackupBuckets() {
+			buck := tx.Bucket([]byte(bucketName))
+			if buck == nil {
+				continue
+			}
+
+			err := buck.ForEach(func(key, value []byte) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				return backupWriter.Write(bucketName, key, value)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return backupWriter.Close()
+	})
+}
+
+// Restore replaces the buckets in a single transaction, they are left unchanged if the backup can't be read.
+func (bdw *BoltDB) Restore(ctx context.Context, reader io.Reader) error {
+	backupReader, err := common.NewBackupReader(reader, common.GetBackupBuckets())
+	if err != nil {
+		return err
+	}
+
+	err = bdw.DB.Update(func(tx *bbolt.Tx) error {
+		for _, bucketName := range common.GetBackupBuckets() {
+			if err := tx.DeleteBucket([]byte(bucketName)); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+				return err
+			}
+
+			if _, err := tx.CreateBucket([]byte(bucketName)); err != nil {
+				return err
+			}
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			entry, err := backupReader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Bucket([]byte(entry.Bucket)).Put([]byte(entry.Key), entry.Value); err != nil {
+				return err
+			}
+		}
+
+		// the version of the header is the one the data is patched from
+		return tx.Bucket([]byte(VersionBucket)).Put([]byte(version.DBVersionKey),
+			[]byte(backupReader.Header.DBVersion))
+	})
+	if err != nil {
+		return err
+	}
+
+	return bdw.PatchDB()
+}
@@ -66,6 +66,21 @@ func New(boltDB *bbolt.DB, log log.Logger) (*BoltDB, error) {
 			return err
 		}
 
+		_, err = transaction.CreateBucketIfNotExists([]byte(LayerIndexBucket))
+		if err != nil {
+			return err
+		}
+
+		_, err = transaction.CreateBucketIfNotExists([]byte(DownloadStatsBucket))
+		if err != nil {
+			return err
+		}
+
+		_, err = transaction.CreateBucketIfNotExists([]byte(UserActivityBucket))
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -102,12 +117,66 @@ func (bdw *BoltDB) SetManifestData(manifestDigest godigest.Digest, manifestData
 			return fmt.Errorf("metadb: error while setting manifest data with for digest %s %w", manifestDigest, err)
 		}
 
-		return nil
+		return indexManifestLayers(tx.Bucket([]byte(LayerIndexBucket)), manifestDigest, manifestData.ManifestBlob)
 	})
 
 	return err
 }
 
+// indexManifestLayers adds the manifest to the layer index entries of its layers.
+func indexManifestLayers(layerIndexBuck *bbolt.Bucket, manifestDigest godigest.Digest, manifestBlob []byte) error {
+	for _, layerDigest := range common.GetManifestLayers(manifestBlob) {
+		manifestDigests := []string{}
+
+		if manifestDigestsBlob := layerIndexBuck.Get([]byte(layerDigest)); len(manifestDigestsBlob) > 0 {
+			err := json.Unmarshal(manifestDigestsBlob, &manifestDigests)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling layer index for digest %s %w", layerDigest, err)
+			}
+		}
+
+		if zcommon.Contains(manifestDigests, manifestDigest.String()) {
+			continue
+		}
+
+		manifestDigests = append(manifestDigests, manifestDigest.String())
+
+		manifestDigestsBlob, err := json.Marshal(manifestDigests)
+		if err != nil {
+			return err
+		}
+
+		err = layerIndexBuck.Put([]byte(layerDigest), manifestDigestsBlob)
+		if err != nil {
+			return fmt.Errorf("metadb: error while setting layer index for digest %s %w", layerDigest, err)
+		}
+	}
+
+	return nil
+}
+
+func (bdw *BoltDB) GetManifestsWithLayer(layerDigest godigest.Digest) ([]godigest.Digest, error) {
+	manifestDigests := []godigest.Digest{}
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(LayerIndexBucket))
+
+		manifestDigestsBlob := buck.Get([]byte(layerDigest))
+		if len(manifestDigestsBlob) == 0 {
+			return nil
+		}
+
+		err := json.Unmarshal(manifestDigestsBlob, &manifestDigests)
+		if err != nil {
+			return fmt.Errorf("metadb: error while unmashaling layer index for digest %s %w", layerDigest, err)
+		}
+
+		return nil
+	})
+
+	return manifestDigests, err
+}
+
 func (bdw *BoltDB) GetManifestData(manifestDigest godigest.Digest) (mTypes.ManifestData, error) {
 	var manifestData mTypes.ManifestData
 
@@ -554,6 +623,7 @@ func (bdw *BoltDB) DeleteRepoTag(repo string, tag string) error {
 		}
 
 		delete(repoMeta.Tags, tag)
+		delete(repoMeta.TagStatistics, tag)
 
 		repoMetaBlob, err = json.Marshal(repoMeta)
 		if err != nil {
@@ -566,6 +636,49 @@ func (bdw *BoltDB) DeleteRepoTag(repo string, tag string) error {
 	return err
 }
 
+func (bdw *BoltDB) DeleteRepoMeta(repo string) error {
+	return bdw.DB.Update(func(tx *bbolt.Tx) error {
+		repoBuck := tx.Bucket([]byte(RepoMetadataBucket))
+
+		if err := repoBuck.Delete([]byte(repo)); err != nil {
+			return err
+		}
+
+		userBuck := tx.Bucket([]byte(UserDataBucket))
+		if userBuck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		changedUserData := map[string]mTypes.UserData{}
+
+		err := userBuck.ForEach(func(userid, userDataBlob []byte) error {
+			var userData mTypes.UserData
+
+			if err := json.Unmarshal(userDataBlob, &userData); err != nil {
+				return err
+			}
+
+			if common.RemoveRepoFromUserData(&userData, repo) {
+				changedUserData[string(userid)] = userData
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// the bucket can't be modified while iterating it
+		for userid, userData := range changedUserData {
+			if err := bdw.setUserData(userid, tx, userData); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func (bdw *BoltDB) IncrementRepoStars(repo string) error {
 	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
 		buck := tx.Bucket([]byte(RepoMetadataBucket))
@@ -653,6 +766,35 @@ func (bdw *BoltDB) GetRepoStars(repo string) (int, error) {
 	return stars, err
 }
 
+func (bdw *BoltDB) SetRepoReadme(repo string, readme *mTypes.RepoReadme) error {
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(RepoMetadataBucket))
+
+		repoMetaBlob := buck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		repoMeta.Readme = readme
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
 func (bdw *BoltDB) GetMultipleRepoMeta(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
 ) ([]mTypes.RepoMetadata, error) {
 	foundRepos := []mTypes.RepoMetadata{}
@@ -701,29 +843,244 @@ func (bdw *BoltDB) IncrementImageDownloads(repo string, reference string) error
 			return err
 		}
 
-		manifestDigest := reference
+		if err := common.AddImageDownload(&repoMeta, reference, time.Now()); err != nil {
+			return err
+		}
 
-		if !common.ReferenceIsDigest(reference) {
-			// search digest for tag
-			descriptor, found := repoMeta.Tags[reference]
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
 
-			if !found {
-				return zerr.ErrManifestMetaNotFound
+	return err
+}
+
+// RecordDownload adds the pull to the statistics of its day and repo.
+func (bdw *BoltDB) RecordDownload(record mTypes.DownloadRecord) error {
+	day := record.Timestamp.UTC().Format(mTypes.DownloadStatsDayFormat)
+	key := []byte(day + "/" + record.Repo)
+
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(DownloadStatsBucket))
+
+		stats := mTypes.DailyDownloads{Repo: record.Repo, Day: day}
+
+		if statsBlob := buck.Get(key); len(statsBlob) > 0 {
+			err := json.Unmarshal(statsBlob, &stats)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling download stats %s %w", key, err)
 			}
+		}
+
+		common.AddDownload(&stats, record)
 
-			manifestDigest = descriptor.Digest
+		statsBlob, err := json.Marshal(stats)
+		if err != nil {
+			return err
 		}
 
-		manifestStatistics := repoMeta.Statistics[manifestDigest]
-		manifestStatistics.DownloadCount++
-		repoMeta.Statistics[manifestDigest] = manifestStatistics
+		return buck.Put(key, statsBlob)
+	})
 
-		repoMetaBlob, err = json.Marshal(repoMeta)
+	return err
+}
+
+func (bdw *BoltDB) GetDownloadStats(ctx context.Context, repo string, from, to time.Time,
+) ([]mTypes.DailyDownloads, error) {
+	fromDay := from.UTC().Format(mTypes.DownloadStatsDayFormat)
+	toDay := to.UTC().Format(mTypes.DownloadStatsDayFormat)
+	foundStats := []mTypes.DailyDownloads{}
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket([]byte(DownloadStatsBucket)).Cursor()
+
+		for key, statsBlob := cursor.Seek([]byte(fromDay)); key != nil; key, statsBlob = cursor.Next() {
+			day, statsRepo, _ := strings.Cut(string(key), "/")
+			if day > toDay {
+				break
+			}
+
+			if repo != "" && statsRepo != repo {
+				continue
+			}
+
+			if ok, err := reqCtx.RepoIsUserAvailable(ctx, statsRepo); !ok || err != nil {
+				continue
+			}
+
+			var stats mTypes.DailyDownloads
+
+			err := json.Unmarshal(statsBlob, &stats)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling download stats %s %w", key, err)
+			}
+
+			foundStats = append(foundStats, stats)
+		}
+
+		return nil
+	})
+
+	return foundStats, err
+}
+
+func (bdw *BoltDB) DeleteDownloadStats(before time.Time) error {
+	beforeDay := before.UTC().Format(mTypes.DownloadStatsDayFormat)
+
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(DownloadStatsBucket))
+
+		// the keys are collected first, deleting while iterating skips keys
+		oldKeys := [][]byte{}
+
+		cursor := buck.Cursor()
+
+		for key, _ := cursor.First(); key != nil && string(key) < beforeDay; key, _ = cursor.Next() {
+			oldKeys = append(oldKeys, key)
+		}
+
+		for _, key := range oldKeys {
+			if err := buck.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (bdw *BoltDB) RollupDownloadStats(before time.Time) error {
+	beforeDay := before.UTC().Format(mTypes.DownloadStatsDayFormat)
+
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(DownloadStatsBucket))
+
+		// the stats are collected first, writing while iterating skips keys
+		rolledUpStats := map[string][]byte{}
+
+		cursor := buck.Cursor()
+
+		for key, statsBlob := cursor.First(); key != nil && string(key) < beforeDay; key, statsBlob = cursor.Next() {
+			var stats mTypes.DailyDownloads
+
+			if err := json.Unmarshal(statsBlob, &stats); err != nil {
+				return fmt.Errorf("metadb: error while unmashaling download stats %s %w", key, err)
+			}
+
+			if stats.Hours == nil {
+				continue
+			}
+
+			stats.Hours = nil
+
+			statsBlob, err := json.Marshal(stats)
+			if err != nil {
+				return err
+			}
+
+			rolledUpStats[string(key)] = statsBlob
+		}
+
+		for key, statsBlob := range rolledUpStats {
+			if err := buck.Put([]byte(key), statsBlob); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// RecordUserActivity adds the action to the activity history of its user.
+func (bdw *BoltDB) RecordUserActivity(activity mTypes.UserActivity) error {
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(UserActivityBucket))
+
+		key := common.GetUserActivityKey(activity.User, activity.Timestamp)
+
+		for buck.Get([]byte(key)) != nil {
+			activity.Timestamp = activity.Timestamp.Add(time.Nanosecond)
+			key = common.GetUserActivityKey(activity.User, activity.Timestamp)
+		}
+
+		activityBlob, err := json.Marshal(activity)
 		if err != nil {
 			return err
 		}
 
-		return buck.Put([]byte(repo), repoMetaBlob)
+		return buck.Put([]byte(key), activityBlob)
+	})
+
+	return err
+}
+
+func (bdw *BoltDB) GetUserActivity(ctx context.Context, user string, from, to time.Time,
+) ([]mTypes.UserActivity, error) {
+	prefix := common.GetUserActivityPrefix(user)
+	fromKey := common.GetUserActivityKey(user, from)
+	toKey := common.GetUserActivityKey(user, to)
+	foundActivity := []mTypes.UserActivity{}
+
+	err := bdw.DB.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket([]byte(UserActivityBucket)).Cursor()
+
+		for key, activityBlob := cursor.Seek([]byte(fromKey)); key != nil; key, activityBlob = cursor.Next() {
+			if !strings.HasPrefix(string(key), prefix) || string(key) > toKey {
+				break
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var activity mTypes.UserActivity
+
+			err := json.Unmarshal(activityBlob, &activity)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling user activity %s %w", key, err)
+			}
+
+			foundActivity = append(foundActivity, activity)
+		}
+
+		return nil
+	})
+
+	return foundActivity, err
+}
+
+func (bdw *BoltDB) DeleteUserActivity(before time.Time) error {
+	err := bdw.DB.Update(func(tx *bbolt.Tx) error {
+		buck := tx.Bucket([]byte(UserActivityBucket))
+
+		// the keys are collected first, deleting while iterating skips keys
+		oldKeys := [][]byte{}
+
+		err := buck.ForEach(func(key, _ []byte) error {
+			if common.IsUserActivityKeyBefore(string(key), before) {
+				oldKeys = append(oldKeys, key)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range oldKeys {
+			if err := buck.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
 	})
 
 	return err
@@ -794,8 +1151,18 @@ func (bdw *BoltDB) UpdateSignaturesValidity(repo string, manifestDigest godigest
 				layersInfo := []mTypes.LayerInfo{}
 
 				for _, layerInfo := range sigInfo.LayersInfo {
-					author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent,
-						layerInfo.SignatureKey, manifestDigest, blob, repo)
+					var (
+						author    string
+						date      time.Time
+						isTrusted bool
+					)
+
+					if sigType == zcommon.CosignSignature && layerInfo.Certificate != "" {
+						author, isTrusted, _ = imgTrustStore.VerifyCosignKeylessSignature(layerInfo, manifestDigest)
+					} else {
+						author, date, isTrusted, _ = imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent,
+							layerInfo.SignatureKey, manifestDigest, blob, repo)
+					}
 
 					if isTrusted {
 						layerInfo.Signer = author
@@ -1137,6 +1504,20 @@ func NewManifestMetadata(manifestDigest string, repoMeta mTypes.RepoMetadata,
 
 func (bdw *BoltDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFunc,
 ) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	return bdw.filterTags(ctx, nil, filterFunc)
+}
+
+func (bdw *BoltDB) FilterTagsByManifests(ctx context.Context, manifestDigests []godigest.Digest,
+	filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	return bdw.filterTags(ctx, common.GetDigestSet(manifestDigests), filterFunc)
+}
+
+// filterTags only reads the data of the manifests in the set, or of every manifest if the set is nil.
+func (bdw *BoltDB) filterTags(ctx context.Context, manifestSet map[string]bool, filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
 ) {
 	var (
 		foundRepos          = make([]mTypes.RepoMetadata, 0)
@@ -1178,6 +1559,10 @@ func (bdw *BoltDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFunc,
 				case ispec.MediaTypeImageManifest:
 					manifestDigest := descriptor.Digest
 
+					if manifestSet != nil && !manifestSet[manifestDigest] {
+						continue
+					}
+
 					manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest, manifestMetadataMap, manifestBuck)
 					if err != nil {
 						return fmt.Errorf("metadb: error while unmashaling manifest metadata for digest %s %w", manifestDigest, err)
@@ -1207,6 +1592,10 @@ func (bdw *BoltDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFunc,
 					for _, manifest := range indexContent.Manifests {
 						manifestDigest := manifest.Digest.String()
 
+						if manifestSet != nil && !manifestSet[manifestDigest] {
+							continue
+						}
+
 						manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest, manifestMetadataMap, manifestBuck)
 						if err != nil {
 							return fmt.Errorf("metadb: error while getting manifest data for digest %s %w", manifestDigest, err)
@@ -1545,6 +1934,115 @@ func (bdw *BoltDB) GetBookmarkedRepos(ctx context.Context) ([]string, error) {
 	return userData.BookmarkedRepos, err
 }
 
+func (bdw *BoltDB) SetBookmarkList(ctx context.Context, list mTypes.BookmarkList) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	list, err = common.NewBookmarkList(userAc, list)
+	if err != nil {
+		return err
+	}
+
+	userid := userAc.GetUsername()
+
+	return bdw.DB.Update(func(transaction *bbolt.Tx) error {
+		var userData mTypes.UserData
+
+		err := bdw.getUserData(userid, transaction, &userData)
+		if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+			return err
+		}
+
+		if userData.BookmarkLists == nil {
+			userData.BookmarkLists = map[string]mTypes.BookmarkList{}
+		}
+
+		userData.BookmarkLists[list.Name] = list
+
+		return bdw.setUserData(userid, transaction, userData)
+	})
+}
+
+func (bdw *BoltDB) DeleteBookmarkList(ctx context.Context, name string) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	return bdw.DB.Update(func(transaction *bbolt.Tx) error {
+		var userData mTypes.UserData
+
+		err := bdw.getUserData(userid, transaction, &userData)
+		if err != nil {
+			if errors.Is(err, zerr.ErrUserDataNotFound) {
+				return zerr.ErrBookmarkListNotFound
+			}
+
+			return err
+		}
+
+		if _, ok := userData.BookmarkLists[name]; !ok {
+			return zerr.ErrBookmarkListNotFound
+		}
+
+		delete(userData.BookmarkLists, name)
+
+		return bdw.setUserData(userid, transaction, userData)
+	})
+}
+
+func (bdw *BoltDB) GetBookmarkLists(ctx context.Context) ([]mTypes.BookmarkList, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return []mTypes.BookmarkList{}, err
+	}
+
+	if userAc.IsAnonymous() {
+		return []mTypes.BookmarkList{}, nil
+	}
+
+	lists := []mTypes.BookmarkList{}
+
+	// the lists shared with the groups of the user are stored in the user data of their owners
+	err = bdw.DB.View(func(transaction *bbolt.Tx) error {
+		buck := transaction.Bucket([]byte(UserDataBucket))
+		if buck == nil {
+			return zerr.ErrBucketDoesNotExist
+		}
+
+		return buck.ForEach(func(userid, userDataBlob []byte) error {
+			var userData mTypes.UserData
+
+			if err := json.Unmarshal(userDataBlob, &userData); err != nil {
+				return err
+			}
+
+			for _, list := range userData.BookmarkLists {
+				lists = append(lists, list)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return []mTypes.BookmarkList{}, err
+	}
+
+	return common.GetReadableBookmarkLists(userAc, lists), nil
+}
+
 func (bdw *BoltDB) PatchDB() error {
 	var DBVersion string
 
@@ -32,6 +32,11 @@ func (its imgTrustStore) VerifySignature(
 	return "", time.Time{}, false, nil
 }
 
+func (its imgTrustStore) VerifyCosignKeylessSignature(layerInfo mTypes.LayerInfo, manifestDigest digest.Digest,
+) (string, bool, error) {
+	return "", false, nil
+}
+
 func TestWrapperErrors(t *testing.T) {
 	Convey("Errors", t, func() {
 		tmpDir := t.TempDir()
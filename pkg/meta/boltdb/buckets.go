@@ -2,10 +2,13 @@ package boltdb
 
 // MetadataDB.
 const (
-	ManifestDataBucket = "ManifestData"
-	IndexDataBucket    = "IndexData"
-	RepoMetadataBucket = "RepoMetadata"
-	UserDataBucket     = "UserData"
-	VersionBucket      = "Version"
-	UserAPIKeysBucket  = "UserAPIKeys"
+	ManifestDataBucket  = "ManifestData"
+	IndexDataBucket     = "IndexData"
+	RepoMetadataBucket  = "RepoMetadata"
+	UserDataBucket      = "UserData"
+	VersionBucket       = "Version"
+	UserAPIKeysBucket   = "UserAPIKeys"
+	LayerIndexBucket    = "LayerIndex"
+	DownloadStatsBucket = "DownloadStats"
+	UserActivityBucket  = "UserActivity"
 )
@@ -0,0 +1,144 @@
+package common
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/meta/version"
+)
+
+// BackupFormat identifies the backups of the metaDB, which are gzipped json lines, a BackupHeader followed by
+// the BackupEntry of every key.
+const BackupFormat = "zot-metadb-backup"
+
+// the buckets of the backups, named like the boltdb buckets, the other databases map them to their tables.
+const (
+	BackupManifestDataBucket  = "ManifestData"
+	BackupIndexDataBucket     = "IndexData"
+	BackupRepoMetadataBucket  = "RepoMetadata"
+	BackupUserDataBucket      = "UserData"
+	BackupVersionBucket       = "Version"
+	BackupUserAPIKeysBucket   = "UserAPIKeys"
+	BackupLayerIndexBucket    = "LayerIndex"
+	BackupDownloadStatsBucket = "DownloadStats"
+	BackupUserActivityBucket  = "UserActivity"
+)
+
+type BackupHeader struct {
+	Format    string    `json:"format"`
+	DBVersion string    `json:"dbVersion"`
+	Created   time.Time `json:"created"`
+}
+
+type BackupEntry struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+	Value  []byte `json:"value"`
+}
+
+// BackupWriter writes the entries of a backup, the backup is only complete once it's closed.
+type BackupWriter struct {
+	gzipWriter *gzip.Writer
+	encoder    *json.Encoder
+}
+
+func NewBackupWriter(writer io.Writer, dbVersion string) (*BackupWriter, error) {
+	gzipWriter := gzip.NewWriter(writer)
+
+	backupWriter := &BackupWriter{
+		gzipWriter: gzipWriter,
+		encoder:    json.NewEncoder(gzipWriter),
+	}
+
+	err := backupWriter.encoder.Encode(BackupHeader{
+		Format:    BackupFormat,
+		DBVersion: dbVersion,
+		Created:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backupWriter, nil
+}
+
+func (bw *BackupWriter) Write(bucket string, key, value []byte) error {
+	return bw.encoder.Encode(BackupEntry{Bucket: bucket, Key: string(key), Value: value})
+}
+
+func (bw *BackupWriter) Close() error {
+	return bw.gzipWriter.Close()
+}
+
+// BackupReader reads the entries of a backup, after checking it's a backup of a version of the database this
+// version of zot can patch.
+type BackupReader struct {
+	Header  BackupHeader
+	decoder *json.Decoder
+	buckets map[string]bool
+}
+
+// NewBackupReader returns a reader of the backup, its entries must be in one of the buckets.
+func NewBackupReader(reader io.Reader, buckets []string) (*BackupReader, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", zerr.ErrBadMetaDBBackup, err)
+	}
+
+	backupReader := &BackupReader{
+		decoder: json.NewDecoder(gzipReader),
+		buckets: map[string]bool{},
+	}
+
+	for _, bucket := range buckets {
+		backupReader.buckets[bucket] = true
+	}
+
+	if err := backupReader.decoder.Decode(&backupReader.Header); err != nil {
+		return nil, fmt.Errorf("%w: %w", zerr.ErrBadMetaDBBackup, err)
+	}
+
+	if backupReader.Header.Format != BackupFormat {
+		return nil, fmt.Errorf("%w: unknown format %q", zerr.ErrBadMetaDBBackup, backupReader.Header.Format)
+	}
+
+	if version.GetVersionIndex(backupReader.Header.DBVersion) == -1 {
+		return nil, fmt.Errorf("%w: unknown database version %q", zerr.ErrBadMetaDBBackup,
+			backupReader.Header.DBVersion)
+	}
+
+	return backupReader, nil
+}
+
+// Next returns the next entry of the backup, io.EOF once all of them were read.
+func (br *BackupReader) Next() (BackupEntry, error) {
+	var entry BackupEntry
+
+	if err := br.decoder.Decode(&entry); err != nil {
+		if errors.Is(err, io.EOF) {
+			return entry, io.EOF
+		}
+
+		return entry, fmt.Errorf("%w: %w", zerr.ErrBadMetaDBBackup, err)
+	}
+
+	if !br.buckets[entry.Bucket] {
+		return entry, fmt.Errorf("%w: unknown bucket %q", zerr.ErrBadMetaDBBackup, entry.Bucket)
+	}
+
+	return entry, nil
+}
+
+// GetBackupBuckets returns the buckets of the backups.
+func GetBackupBuckets() []string {
+	return []string{
+		BackupVersionBucket, BackupManifestDataBucket, BackupIndexDataBucket, BackupRepoMetadataBucket,
+		BackupUserDataBucket, BackupUserAPIKeysBucket, BackupLayerIndexBucket, BackupDownloadStatsBucket,
+		BackupUserActivityBucket,
+	}
+}
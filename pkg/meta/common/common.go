@@ -3,6 +3,8 @@ package common
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,7 +12,10 @@ import (
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	zcommon "zotregistry.io/zot/pkg/common"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
 )
 
 func UpdateManifestMeta(repoMeta mTypes.RepoMetadata, manifestDigest godigest.Digest,
@@ -344,3 +349,260 @@ func InitializeImageConfig(blob []byte) ispec.Image {
 
 	return configContent
 }
+
+// GetManifestLayers returns the layer digests of the manifest, none if the blob isn't a valid manifest.
+func GetManifestLayers(manifestBlob []byte) []godigest.Digest {
+	var manifestContent ispec.Manifest
+
+	err := json.Unmarshal(manifestBlob, &manifestContent)
+	if err != nil {
+		return []godigest.Digest{}
+	}
+
+	layers := make([]godigest.Digest, 0, len(manifestContent.Layers))
+
+	for _, layer := range manifestContent.Layers {
+		layers = append(layers, layer.Digest)
+	}
+
+	return layers
+}
+
+func GetDigestSet(digests []godigest.Digest) map[string]bool {
+	digestSet := make(map[string]bool, len(digests))
+
+	for _, digest := range digests {
+		digestSet[digest.String()] = true
+	}
+
+	return digestSet
+}
+
+// NewBookmarkList validates a bookmark list of the user, the user can only add the repos it can read.
+func NewBookmarkList(userAc *reqCtx.UserAccessControl, list mTypes.BookmarkList) (mTypes.BookmarkList, error) {
+	if list.Name == "" {
+		return mTypes.BookmarkList{}, zerr.ErrInvalidRequestParams
+	}
+
+	repos := []string{}
+
+	for _, repo := range list.Repos {
+		if !userAc.Can(constants.ReadPermission, repo) {
+			return mTypes.BookmarkList{}, zerr.ErrUserDataNotAllowed
+		}
+
+		if !zcommon.Contains(repos, repo) {
+			repos = append(repos, repo)
+		}
+	}
+
+	return mTypes.BookmarkList{
+		Name:       list.Name,
+		Owner:      userAc.GetUsername(),
+		Repos:      repos,
+		SharedWith: list.SharedWith,
+	}, nil
+}
+
+// GetReadableBookmarkLists returns the lists owned by the user or shared with one of its groups, sorted by owner
+// and name. The repos the user can't read are removed from the lists.
+func GetReadableBookmarkLists(userAc *reqCtx.UserAccessControl, lists []mTypes.BookmarkList,
+) []mTypes.BookmarkList {
+	readableLists := []mTypes.BookmarkList{}
+
+	for _, list := range lists {
+		if list.Owner != userAc.GetUsername() && !isSharedWithGroups(list, userAc.GetGroups()) {
+			continue
+		}
+
+		repos := []string{}
+
+		for _, repo := range list.Repos {
+			if userAc.Can(constants.ReadPermission, repo) {
+				repos = append(repos, repo)
+			}
+		}
+
+		list.Repos = repos
+		readableLists = append(readableLists, list)
+	}
+
+	sort.Slice(readableLists, func(i, j int) bool {
+		if readableLists[i].Owner != readableLists[j].Owner {
+			return readableLists[i].Owner < readableLists[j].Owner
+		}
+
+		return readableLists[i].Name < readableLists[j].Name
+	})
+
+	return readableLists
+}
+
+// RemoveRepoFromUserData removes a deleted repo from the stars, bookmarks and bookmark lists of the user,
+// it returns true if the user data changed.
+func RemoveRepoFromUserData(userData *mTypes.UserData, repo string) bool {
+	changed := false
+
+	removeRepo := func(repos []string) []string {
+		kept := []string{}
+
+		for _, listedRepo := range repos {
+			if listedRepo == repo {
+				changed = true
+
+				continue
+			}
+
+			kept = append(kept, listedRepo)
+		}
+
+		return kept
+	}
+
+	userData.StarredRepos = removeRepo(userData.StarredRepos)
+	userData.BookmarkedRepos = removeRepo(userData.BookmarkedRepos)
+
+	for name, list := range userData.BookmarkLists {
+		list.Repos = removeRepo(list.Repos)
+		userData.BookmarkLists[name] = list
+	}
+
+	return changed
+}
+
+// AddImageDownload adds a pull of the manifest of the reference, a tag or a digest, to the statistics of the repo.
+func AddImageDownload(repoMeta *mTypes.RepoMetadata, reference string, timestamp time.Time) error {
+	manifestDigest := reference
+
+	if !ReferenceIsDigest(reference) {
+		// search digest for tag
+		descriptor, found := repoMeta.Tags[reference]
+
+		if !found {
+			return zerr.ErrManifestMetaNotFound
+		}
+
+		manifestDigest = descriptor.Digest
+
+		if repoMeta.TagStatistics == nil {
+			repoMeta.TagStatistics = map[string]mTypes.DescriptorStatistics{}
+		}
+
+		repoMeta.TagStatistics[reference] = addDescriptorDownload(repoMeta.TagStatistics[reference], timestamp)
+	}
+
+	if repoMeta.Statistics == nil {
+		repoMeta.Statistics = map[string]mTypes.DescriptorStatistics{}
+	}
+
+	repoMeta.Statistics[manifestDigest] = addDescriptorDownload(repoMeta.Statistics[manifestDigest], timestamp)
+
+	return nil
+}
+
+// GetImageLastPull returns the time of the last pull of an image, by its tag, if it has one, or by its digest,
+// zero if it was never pulled.
+func GetImageLastPull(repoMeta mTypes.RepoMetadata, tag, digest string) time.Time {
+	lastPull := repoMeta.Statistics[digest].LastPullTimestamp
+
+	if tag != "" && repoMeta.TagStatistics[tag].LastPullTimestamp.After(lastPull) {
+		lastPull = repoMeta.TagStatistics[tag].LastPullTimestamp
+	}
+
+	return lastPull
+}
+
+// GetRepoLastPull returns the time of the last pull of an image of the repo, zero if none was pulled.
+func GetRepoLastPull(repoMeta mTypes.RepoMetadata) time.Time {
+	lastPull := time.Time{}
+
+	for _, statistics := range repoMeta.Statistics {
+		if statistics.LastPullTimestamp.After(lastPull) {
+			lastPull = statistics.LastPullTimestamp
+		}
+	}
+
+	return lastPull
+}
+
+func addDescriptorDownload(statistics mTypes.DescriptorStatistics, timestamp time.Time) mTypes.DescriptorStatistics {
+	statistics.DownloadCount++
+
+	if timestamp.After(statistics.LastPullTimestamp) {
+		statistics.LastPullTimestamp = timestamp
+	}
+
+	return statistics
+}
+
+// AddDownload adds a pull to the daily download statistics of its repo, which must be of the day of the pull.
+// The hourly counts start with the first pull of the day, the days rolled up don't get them back.
+func AddDownload(stats *mTypes.DailyDownloads, record mTypes.DownloadRecord) {
+	if len(stats.Digests) == 0 && stats.Hours == nil {
+		stats.Hours = make([]int, mTypes.DownloadStatsHours)
+	}
+
+	if stats.Digests == nil {
+		stats.Digests = map[string]mTypes.ReferenceDownloads{}
+	}
+
+	if stats.Tags == nil {
+		stats.Tags = map[string]mTypes.ReferenceDownloads{}
+	}
+
+	stats.Digests[record.Digest] = addReferenceDownload(stats.Digests[record.Digest], record)
+
+	if !ReferenceIsDigest(record.Reference) {
+		stats.Tags[record.Reference] = addReferenceDownload(stats.Tags[record.Reference], record)
+	}
+
+	if len(stats.Hours) == mTypes.DownloadStatsHours {
+		stats.Hours[record.Timestamp.UTC().Hour()]++
+	}
+}
+
+func addReferenceDownload(downloads mTypes.ReferenceDownloads, record mTypes.DownloadRecord,
+) mTypes.ReferenceDownloads {
+	downloads.Count++
+
+	if record.Client != "" && !zcommon.Contains(downloads.Clients, record.Client) {
+		downloads.Clients = append(downloads.Clients, record.Client)
+	}
+
+	if record.Timestamp.After(downloads.LastPulled) {
+		downloads.LastPulled = record.Timestamp
+	}
+
+	return downloads
+}
+
+// userActivityTimeFormat has a fixed width, so the keys of the activity of a user are sorted by time.
+const userActivityTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// GetUserActivityPrefix returns the prefix of the keys of the activity of a user, the user is escaped so it's
+// not the prefix of the keys of another user.
+func GetUserActivityPrefix(user string) string {
+	return url.PathEscape(user) + "/"
+}
+
+// GetUserActivityKey returns the key of an action of a user, its actions are sorted by time.
+func GetUserActivityKey(user string, timestamp time.Time) string {
+	return GetUserActivityPrefix(user) + timestamp.UTC().Format(userActivityTimeFormat)
+}
+
+// IsUserActivityKeyBefore checks if the key is the one of an action before 'before', whatever its user.
+func IsUserActivityKeyBefore(key string, before time.Time) bool {
+	_, timestamp, _ := strings.Cut(key, "/")
+
+	return timestamp < before.UTC().Format(userActivityTimeFormat)
+}
+
+func isSharedWithGroups(list mTypes.BookmarkList, groups []string) bool {
+	for _, group := range list.SharedWith {
+		if zcommon.Contains(groups, group) {
+			return true
+		}
+	}
+
+	return false
+}
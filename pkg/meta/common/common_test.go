@@ -1,7 +1,11 @@
 package common_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,8 +13,10 @@ import (
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	. "github.com/smartystreets/goconvey/convey"
 
+	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/meta/common"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/meta/version"
 	"zotregistry.io/zot/pkg/test/mocks"
 )
 
@@ -258,3 +264,65 @@ func TestFetchDataForRepos(t *testing.T) {
 		})
 	})
 }
+
+func TestBackupReader(t *testing.T) {
+	Convey("The backups are read back", t, func() {
+		backup := bytes.Buffer{}
+
+		backupWriter, err := common.NewBackupWriter(&backup, version.CurrentVersion)
+		So(err, ShouldBeNil)
+
+		err = backupWriter.Write(common.BackupRepoMetadataBucket, []byte("repo"), []byte("{}"))
+		So(err, ShouldBeNil)
+
+		err = backupWriter.Close()
+		So(err, ShouldBeNil)
+
+		backupReader, err := common.NewBackupReader(bytes.NewReader(backup.Bytes()), common.GetBackupBuckets())
+		So(err, ShouldBeNil)
+		So(backupReader.Header.DBVersion, ShouldEqual, version.CurrentVersion)
+
+		entry, err := backupReader.Next()
+		So(err, ShouldBeNil)
+		So(entry.Bucket, ShouldEqual, common.BackupRepoMetadataBucket)
+		So(entry.Key, ShouldEqual, "repo")
+		So(string(entry.Value), ShouldEqual, "{}")
+
+		_, err = backupReader.Next()
+		So(err, ShouldEqual, io.EOF)
+
+		Convey("Unknown buckets are rejected", func() {
+			backupReader, err := common.NewBackupReader(bytes.NewReader(backup.Bytes()),
+				[]string{common.BackupUserDataBucket})
+			So(err, ShouldBeNil)
+
+			_, err = backupReader.Next()
+			So(errors.Is(err, zerr.ErrBadMetaDBBackup), ShouldBeTrue)
+		})
+	})
+
+	Convey("The backups of unknown versions are rejected", t, func() {
+		backup := bytes.Buffer{}
+
+		backupWriter, err := common.NewBackupWriter(&backup, "V0")
+		So(err, ShouldBeNil)
+		So(backupWriter.Close(), ShouldBeNil)
+
+		_, err = common.NewBackupReader(&backup, common.GetBackupBuckets())
+		So(errors.Is(err, zerr.ErrBadMetaDBBackup), ShouldBeTrue)
+	})
+
+	Convey("The files which aren't backups are rejected", t, func() {
+		_, err := common.NewBackupReader(strings.NewReader("not a backup"), common.GetBackupBuckets())
+		So(errors.Is(err, zerr.ErrBadMetaDBBackup), ShouldBeTrue)
+
+		gzipped := bytes.Buffer{}
+		gzipWriter := gzip.NewWriter(&gzipped)
+		_, err = gzipWriter.Write([]byte(`{"format":"other"}`))
+		So(err, ShouldBeNil)
+		So(gzipWriter.Close(), ShouldBeNil)
+
+		_, err = common.NewBackupReader(&gzipped, common.GetBackupBuckets())
+		So(errors.Is(err, zerr.ErrBadMetaDBBackup), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,18 @@
+package dynamodb
+
+import (
+	"context"
+	"io"
+
+	zerr "zotregistry.io/zot/errors"
+)
+
+// Backup isn't supported, the tables are backed up by DynamoDB, e.g. with the point in time recovery.
+func (dwr *DynamoDB) Backup(ctx context.Context, writer io.Writer) error {
+	return zerr.ErrMetaDBBackupNotSupported
+}
+
+// Restore isn't supported, the tables are restored by DynamoDB.
+func (dwr *DynamoDB) Restore(ctx context.Context, reader io.Reader) error {
+	return zerr.ErrMetaDBBackupNotSupported
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -28,32 +29,38 @@ import (
 var errMetaDB = errors.New("metadb: error while constructing manifest meta")
 
 type DynamoDB struct {
-	Client                *dynamodb.Client
-	APIKeyTablename       string
-	RepoMetaTablename     string
-	IndexDataTablename    string
-	ManifestDataTablename string
-	UserDataTablename     string
-	VersionTablename      string
-	Patches               []func(client *dynamodb.Client, tableNames map[string]string) error
-	imgTrustStore         mTypes.ImageTrustStore
-	Log                   log.Logger
+	Client                 *dynamodb.Client
+	APIKeyTablename        string
+	RepoMetaTablename      string
+	IndexDataTablename     string
+	ManifestDataTablename  string
+	UserDataTablename      string
+	VersionTablename       string
+	LayerIndexTablename    string // optional, derived and base image queries scan every image without it
+	DownloadStatsTablename string // optional, the download statistics aren't recorded without it
+	UserActivityTablename  string // optional, the activity of the users isn't recorded without it
+	Patches                []func(client *dynamodb.Client, tableNames map[string]string) error
+	imgTrustStore          mTypes.ImageTrustStore
+	Log                    log.Logger
 }
 
 func New(
 	client *dynamodb.Client, params DBDriverParameters, log log.Logger,
 ) (*DynamoDB, error) {
 	dynamoWrapper := DynamoDB{
-		Client:                client,
-		RepoMetaTablename:     params.RepoMetaTablename,
-		ManifestDataTablename: params.ManifestDataTablename,
-		IndexDataTablename:    params.IndexDataTablename,
-		VersionTablename:      params.VersionTablename,
-		UserDataTablename:     params.UserDataTablename,
-		APIKeyTablename:       params.APIKeyTablename,
-		Patches:               version.GetDynamoDBPatches(),
-		imgTrustStore:         nil,
-		Log:                   log,
+		Client:                 client,
+		RepoMetaTablename:      params.RepoMetaTablename,
+		ManifestDataTablename:  params.ManifestDataTablename,
+		IndexDataTablename:     params.IndexDataTablename,
+		VersionTablename:       params.VersionTablename,
+		UserDataTablename:      params.UserDataTablename,
+		APIKeyTablename:        params.APIKeyTablename,
+		LayerIndexTablename:    params.LayerIndexTablename,
+		DownloadStatsTablename: params.DownloadStatsTablename,
+		UserActivityTablename:  params.UserActivityTablename,
+		Patches:                version.GetDynamoDBPatches(),
+		imgTrustStore:          nil,
+		Log:                    log,
 	}
 
 	err := dynamoWrapper.createVersionTable()
@@ -86,6 +93,27 @@ func New(
 		return nil, err
 	}
 
+	if dynamoWrapper.LayerIndexTablename != "" {
+		err = dynamoWrapper.createLayerIndexTable()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dynamoWrapper.DownloadStatsTablename != "" {
+		err = dynamoWrapper.createDownloadStatsTable()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dynamoWrapper.UserActivityTablename != "" {
+		err = dynamoWrapper.createUserActivityTable()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Using the Config value, create the DynamoDB client
 	return &dynamoWrapper, nil
 }
@@ -119,8 +147,76 @@ func (dwr *DynamoDB) SetManifestData(manifestDigest godigest.Digest, manifestDat
 		TableName:        aws.String(dwr.ManifestDataTablename),
 		UpdateExpression: aws.String("SET #MD = :ManifestData"),
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return dwr.indexManifestLayers(manifestDigest, manifestData.ManifestBlob)
+}
+
+// indexManifestLayers adds the manifest to the layer index entries of its layers.
+func (dwr *DynamoDB) indexManifestLayers(manifestDigest godigest.Digest, manifestBlob []byte) error {
+	if dwr.LayerIndexTablename == "" {
+		return nil
+	}
+
+	for _, layerDigest := range common.GetManifestLayers(manifestBlob) {
+		_, err := dwr.Client.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
+			ExpressionAttributeNames: map[string]string{
+				"#M": "Manifests",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":Manifest": &types.AttributeValueMemberSS{Value: []string{manifestDigest.String()}},
+			},
+			Key: map[string]types.AttributeValue{
+				"LayerDigest": &types.AttributeValueMemberS{
+					Value: layerDigest.String(),
+				},
+			},
+			TableName:        aws.String(dwr.LayerIndexTablename),
+			UpdateExpression: aws.String("ADD #M :Manifest"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (dwr *DynamoDB) GetManifestsWithLayer(layerDigest godigest.Digest) ([]godigest.Digest, error) {
+	if dwr.LayerIndexTablename == "" {
+		return nil, zerr.ErrLayerIndexDisabled
+	}
+
+	resp, err := dwr.Client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String(dwr.LayerIndexTablename),
+		Key: map[string]types.AttributeValue{
+			"LayerDigest": &types.AttributeValueMemberS{Value: layerDigest.String()},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifestDigests := []godigest.Digest{}
+
+	if resp.Item == nil {
+		return manifestDigests, nil
+	}
+
+	var manifests []string
+
+	err = attributevalue.Unmarshal(resp.Item["Manifests"], &manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, manifest := range manifests {
+		manifestDigests = append(manifestDigests, godigest.Digest(manifest))
+	}
+
+	return manifestDigests, nil
 }
 
 func (dwr *DynamoDB) GetManifestData(manifestDigest godigest.Digest) (mTypes.ManifestData, error) {
@@ -261,6 +357,17 @@ func (dwr *DynamoDB) GetRepoStars(repo string) (int, error) {
 	return repoMeta.Stars, nil
 }
 
+func (dwr *DynamoDB) SetRepoReadme(repo string, readme *mTypes.RepoReadme) error {
+	repoMeta, err := dwr.GetRepoMeta(repo)
+	if err != nil {
+		return err
+	}
+
+	repoMeta.Readme = readme
+
+	return dwr.SetRepoMeta(repo, repoMeta)
+}
+
 func (dwr *DynamoDB) SetIndexData(indexDigest godigest.Digest, indexData mTypes.IndexData) error {
 	indexAttributeValue, err := attributevalue.Marshal(indexData)
 	if err != nil {
@@ -526,6 +633,7 @@ func (dwr *DynamoDB) DeleteRepoTag(repo string, tag string) error {
 	}
 
 	delete(repoMeta.Tags, tag)
+	delete(repoMeta.TagStatistics, tag)
 
 	repoAttributeValue, err := attributevalue.Marshal(repoMeta)
 	if err != nil {
@@ -551,6 +659,88 @@ func (dwr *DynamoDB) DeleteRepoTag(repo string, tag string) error {
 	return err
 }
 
+func (dwr *DynamoDB) DeleteRepoMeta(repo string) error {
+	ctx := context.Background()
+
+	_, err := dwr.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(dwr.RepoMetaTablename),
+		Key: map[string]types.AttributeValue{
+			"RepoName": &types.AttributeValueMemberS{Value: repo},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	identityAttributeIterator := NewBaseDynamoAttributesIterator(
+		dwr.Client, dwr.UserDataTablename, "Identity", 0, dwr.Log,
+	)
+
+	identityAttribute, err := identityAttributeIterator.First(ctx)
+
+	for ; identityAttribute != nil; identityAttribute, err = identityAttributeIterator.Next(ctx) {
+		if err != nil {
+			return err
+		}
+
+		var userid string
+
+		if err := attributevalue.Unmarshal(identityAttribute, &userid); err != nil {
+			return err
+		}
+
+		resp, err := dwr.Client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(dwr.UserDataTablename),
+			Key: map[string]types.AttributeValue{
+				"Identity": &types.AttributeValueMemberS{Value: userid},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		var userData mTypes.UserData
+
+		if resp.Item == nil || resp.Item["UserData"] == nil {
+			continue
+		}
+
+		if err := attributevalue.Unmarshal(resp.Item["UserData"], &userData); err != nil {
+			return err
+		}
+
+		if !common.RemoveRepoFromUserData(&userData, repo) {
+			continue
+		}
+
+		userAttributeValue, err := attributevalue.Marshal(userData)
+		if err != nil {
+			return err
+		}
+
+		_, err = dwr.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			ExpressionAttributeNames: map[string]string{
+				"#UP": "UserData",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":UserData": userAttributeValue,
+			},
+			Key: map[string]types.AttributeValue{
+				"Identity": &types.AttributeValueMemberS{
+					Value: userid,
+				},
+			},
+			TableName:        aws.String(dwr.UserDataTablename),
+			UpdateExpression: aws.String("SET #UP = :UserData"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
 func (dwr *DynamoDB) GetRepoMeta(repo string) (mTypes.RepoMetadata, error) {
 	resp, err := dwr.Client.GetItem(context.TODO(), &dynamodb.GetItemInput{
 		TableName: aws.String(dwr.RepoMetaTablename),
@@ -615,24 +805,287 @@ func (dwr *DynamoDB) IncrementImageDownloads(repo string, reference string) erro
 		return err
 	}
 
-	descriptorDigest := reference
+	if err := common.AddImageDownload(&repoMeta, reference, time.Now()); err != nil {
+		return err
+	}
+
+	return dwr.SetRepoMeta(repo, repoMeta)
+}
 
-	if !common.ReferenceIsDigest(reference) {
-		// search digest for tag
-		descriptor, found := repoMeta.Tags[reference]
+// RecordDownload adds the pull to the statistics of its day and repo.
+func (dwr *DynamoDB) RecordDownload(record mTypes.DownloadRecord) error {
+	if dwr.DownloadStatsTablename == "" {
+		return nil
+	}
 
-		if !found {
-			return zerr.ErrManifestMetaNotFound
+	day := record.Timestamp.UTC().Format(mTypes.DownloadStatsDayFormat)
+	key := map[string]types.AttributeValue{
+		"Key": &types.AttributeValueMemberS{Value: day + "/" + record.Repo},
+	}
+
+	resp, err := dwr.Client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String(dwr.DownloadStatsTablename),
+		Key:       key,
+	})
+	if err != nil {
+		return err
+	}
+
+	stats := mTypes.DailyDownloads{Repo: record.Repo, Day: day}
+
+	if resp.Item != nil {
+		err = attributevalue.Unmarshal(resp.Item["DownloadStats"], &stats)
+		if err != nil {
+			return err
 		}
+	}
+
+	common.AddDownload(&stats, record)
 
-		descriptorDigest = descriptor.Digest
+	statsAttributeValue, err := attributevalue.Marshal(stats)
+	if err != nil {
+		return err
 	}
 
-	manifestStatistics := repoMeta.Statistics[descriptorDigest]
-	manifestStatistics.DownloadCount++
-	repoMeta.Statistics[descriptorDigest] = manifestStatistics
+	_, err = dwr.Client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		ExpressionAttributeNames: map[string]string{
+			"#DS": "DownloadStats",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":DownloadStats": statsAttributeValue,
+		},
+		Key:              key,
+		TableName:        aws.String(dwr.DownloadStatsTablename),
+		UpdateExpression: aws.String("SET #DS = :DownloadStats"),
+	})
 
-	return dwr.SetRepoMeta(repo, repoMeta)
+	return err
+}
+
+func (dwr *DynamoDB) GetDownloadStats(ctx context.Context, repo string, from, to time.Time,
+) ([]mTypes.DailyDownloads, error) {
+	if dwr.DownloadStatsTablename == "" {
+		return nil, zerr.ErrDownloadStatsDisabled
+	}
+
+	fromDay := from.UTC().Format(mTypes.DownloadStatsDayFormat)
+	toDay := to.UTC().Format(mTypes.DownloadStatsDayFormat)
+	foundStats := []mTypes.DailyDownloads{}
+
+	err := dwr.iterateDownloadStats(ctx, func(stats mTypes.DailyDownloads) error {
+		if stats.Day < fromDay || stats.Day > toDay || (repo != "" && stats.Repo != repo) {
+			return nil
+		}
+
+		if ok, err := reqCtx.RepoIsUserAvailable(ctx, stats.Repo); !ok || err != nil {
+			return nil //nolint: nilerr // the repos the user can't read are skipped
+		}
+
+		foundStats = append(foundStats, stats)
+
+		return nil
+	})
+
+	return foundStats, err
+}
+
+func (dwr *DynamoDB) DeleteDownloadStats(before time.Time) error {
+	if dwr.DownloadStatsTablename == "" {
+		return nil
+	}
+
+	beforeDay := before.UTC().Format(mTypes.DownloadStatsDayFormat)
+
+	return dwr.iterateDownloadStats(context.Background(), func(stats mTypes.DailyDownloads) error {
+		if stats.Day >= beforeDay {
+			return nil
+		}
+
+		_, err := dwr.Client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+			TableName: aws.String(dwr.DownloadStatsTablename),
+			Key: map[string]types.AttributeValue{
+				"Key": &types.AttributeValueMemberS{Value: stats.Day + "/" + stats.Repo},
+			},
+		})
+
+		return err
+	})
+}
+
+func (dwr *DynamoDB) RollupDownloadStats(before time.Time) error {
+	if dwr.DownloadStatsTablename == "" {
+		return nil
+	}
+
+	beforeDay := before.UTC().Format(mTypes.DownloadStatsDayFormat)
+
+	return dwr.iterateDownloadStats(context.Background(), func(stats mTypes.DailyDownloads) error {
+		if stats.Day >= beforeDay || stats.Hours == nil {
+			return nil
+		}
+
+		_, err := dwr.Client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+			ExpressionAttributeNames: map[string]string{
+				"#DS": "DownloadStats",
+				"#H":  "Hours",
+			},
+			Key: map[string]types.AttributeValue{
+				"Key": &types.AttributeValueMemberS{Value: stats.Day + "/" + stats.Repo},
+			},
+			TableName:        aws.String(dwr.DownloadStatsTablename),
+			UpdateExpression: aws.String("REMOVE #DS.#H"),
+		})
+
+		return err
+	})
+}
+
+func (dwr *DynamoDB) iterateDownloadStats(ctx context.Context, callback func(mTypes.DailyDownloads) error) error {
+	statsAttributeIterator := NewBaseDynamoAttributesIterator(
+		dwr.Client, dwr.DownloadStatsTablename, "DownloadStats", 0, dwr.Log,
+	)
+
+	statsAttribute, err := statsAttributeIterator.First(ctx)
+
+	for ; statsAttribute != nil; statsAttribute, err = statsAttributeIterator.Next(ctx) {
+		if err != nil {
+			return err
+		}
+
+		var stats mTypes.DailyDownloads
+
+		if err := attributevalue.Unmarshal(statsAttribute, &stats); err != nil {
+			return err
+		}
+
+		if err := callback(stats); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// RecordUserActivity adds the action to the activity history of its user.
+func (dwr *DynamoDB) RecordUserActivity(activity mTypes.UserActivity) error {
+	if dwr.UserActivityTablename == "" {
+		return nil
+	}
+
+	key := common.GetUserActivityKey(activity.User, activity.Timestamp)
+
+	for {
+		resp, err := dwr.Client.GetItem(context.Background(), &dynamodb.GetItemInput{
+			TableName: aws.String(dwr.UserActivityTablename),
+			Key: map[string]types.AttributeValue{
+				"Key": &types.AttributeValueMemberS{Value: key},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.Item == nil {
+			break
+		}
+
+		activity.Timestamp = activity.Timestamp.Add(time.Nanosecond)
+		key = common.GetUserActivityKey(activity.User, activity.Timestamp)
+	}
+
+	activityAttributeValue, err := attributevalue.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	_, err = dwr.Client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		ExpressionAttributeNames: map[string]string{
+			"#UA": "UserActivity",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":UserActivity": activityAttributeValue,
+		},
+		Key: map[string]types.AttributeValue{
+			"Key": &types.AttributeValueMemberS{Value: key},
+		},
+		TableName:        aws.String(dwr.UserActivityTablename),
+		UpdateExpression: aws.String("SET #UA = :UserActivity"),
+	})
+
+	return err
+}
+
+func (dwr *DynamoDB) GetUserActivity(ctx context.Context, user string, from, to time.Time,
+) ([]mTypes.UserActivity, error) {
+	if dwr.UserActivityTablename == "" {
+		return nil, zerr.ErrUserActivityDisabled
+	}
+
+	foundActivity := []mTypes.UserActivity{}
+
+	err := dwr.iterateUserActivity(ctx, func(activity mTypes.UserActivity) error {
+		if activity.User == user && !activity.Timestamp.Before(from) && !activity.Timestamp.After(to) {
+			foundActivity = append(foundActivity, activity)
+		}
+
+		return nil
+	})
+
+	// the items of a scan aren't sorted
+	sort.Slice(foundActivity, func(i, j int) bool {
+		return foundActivity[i].Timestamp.Before(foundActivity[j].Timestamp)
+	})
+
+	return foundActivity, err
+}
+
+func (dwr *DynamoDB) DeleteUserActivity(before time.Time) error {
+	if dwr.UserActivityTablename == "" {
+		return nil
+	}
+
+	return dwr.iterateUserActivity(context.Background(), func(activity mTypes.UserActivity) error {
+		if !activity.Timestamp.Before(before) {
+			return nil
+		}
+
+		_, err := dwr.Client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+			TableName: aws.String(dwr.UserActivityTablename),
+			Key: map[string]types.AttributeValue{
+				"Key": &types.AttributeValueMemberS{
+					Value: common.GetUserActivityKey(activity.User, activity.Timestamp),
+				},
+			},
+		})
+
+		return err
+	})
+}
+
+func (dwr *DynamoDB) iterateUserActivity(ctx context.Context, callback func(mTypes.UserActivity) error) error {
+	activityAttributeIterator := NewBaseDynamoAttributesIterator(
+		dwr.Client, dwr.UserActivityTablename, "UserActivity", 0, dwr.Log,
+	)
+
+	activityAttribute, err := activityAttributeIterator.First(ctx)
+
+	for ; activityAttribute != nil; activityAttribute, err = activityAttributeIterator.Next(ctx) {
+		if err != nil {
+			return err
+		}
+
+		var activity mTypes.UserActivity
+
+		if err := attributevalue.Unmarshal(activityAttribute, &activity); err != nil {
+			return err
+		}
+
+		if err := callback(activity); err != nil {
+			return err
+		}
+	}
+
+	return err
 }
 
 func (dwr *DynamoDB) UpdateSignaturesValidity(repo string, manifestDigest godigest.Digest) error {
@@ -676,8 +1129,18 @@ func (dwr *DynamoDB) UpdateSignaturesValidity(repo string, manifestDigest godige
 			layersInfo := []mTypes.LayerInfo{}
 
 			for _, layerInfo := range sigInfo.LayersInfo {
-				author, date, isTrusted, _ := imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent, layerInfo.SignatureKey,
-					manifestDigest, blob, repo)
+				var (
+					author    string
+					date      time.Time
+					isTrusted bool
+				)
+
+				if sigType == zcommon.CosignSignature && layerInfo.Certificate != "" {
+					author, isTrusted, _ = imgTrustStore.VerifyCosignKeylessSignature(layerInfo, manifestDigest)
+				} else {
+					author, date, isTrusted, _ = imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent,
+						layerInfo.SignatureKey, manifestDigest, blob, repo)
+				}
 
 				if isTrusted {
 					layerInfo.Signer = author
@@ -999,6 +1462,20 @@ func (dwr *DynamoDB) fetchIndexDataWithCheck(indexDigest string, indexDataMap ma
 
 func (dwr *DynamoDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFunc,
 ) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	return dwr.filterTags(ctx, nil, filterFunc)
+}
+
+func (dwr *DynamoDB) FilterTagsByManifests(ctx context.Context, manifestDigests []godigest.Digest,
+	filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	return dwr.filterTags(ctx, common.GetDigestSet(manifestDigests), filterFunc)
+}
+
+// filterTags only reads the data of the manifests in the set, or of every manifest if the set is nil.
+func (dwr *DynamoDB) filterTags(ctx context.Context, manifestSet map[string]bool, filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
 ) {
 	var (
 		foundRepos                = make([]mTypes.RepoMetadata, 0)
@@ -1043,6 +1520,10 @@ func (dwr *DynamoDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFun
 			case ispec.MediaTypeImageManifest:
 				manifestDigest := descriptor.Digest
 
+				if manifestSet != nil && !manifestSet[manifestDigest] {
+					continue
+				}
+
 				manifestMeta, err := dwr.fetchManifestMetaWithCheck(repoMeta.Name, manifestDigest, //nolint:contextcheck
 					manifestMetadataMap)
 				if err != nil {
@@ -1076,6 +1557,10 @@ func (dwr *DynamoDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFun
 				for _, manifest := range indexContent.Manifests {
 					manifestDigest := manifest.Digest.String()
 
+					if manifestSet != nil && !manifestSet[manifestDigest] {
+						continue
+					}
+
 					manifestMeta, err := dwr.fetchManifestMetaWithCheck(repoMeta.Name, manifestDigest, //nolint:contextcheck
 						manifestMetadataMap)
 					if err != nil {
@@ -1437,6 +1922,81 @@ func (dwr *DynamoDB) createManifestDataTable() error {
 	return dwr.waitTableToBeCreated(dwr.ManifestDataTablename)
 }
 
+func (dwr *DynamoDB) createLayerIndexTable() error {
+	_, err := dwr.Client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName: aws.String(dwr.LayerIndexTablename),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("LayerDigest"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("LayerDigest"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+
+	if err != nil && !strings.Contains(err.Error(), "Table already exists") {
+		return err
+	}
+
+	return dwr.waitTableToBeCreated(dwr.LayerIndexTablename)
+}
+
+func (dwr *DynamoDB) createDownloadStatsTable() error {
+	_, err := dwr.Client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName: aws.String(dwr.DownloadStatsTablename),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("Key"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("Key"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+
+	if err != nil && !strings.Contains(err.Error(), "Table already exists") {
+		return err
+	}
+
+	return dwr.waitTableToBeCreated(dwr.DownloadStatsTablename)
+}
+
+func (dwr *DynamoDB) createUserActivityTable() error {
+	_, err := dwr.Client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName: aws.String(dwr.UserActivityTablename),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("Key"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("Key"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+
+	if err != nil && !strings.Contains(err.Error(), "Table already exists") {
+		return err
+	}
+
+	return dwr.waitTableToBeCreated(dwr.UserActivityTablename)
+}
+
 func (dwr *DynamoDB) createIndexDataTable() error {
 	_, err := dwr.Client.CreateTable(context.Background(), &dynamodb.CreateTableInput{
 		TableName: aws.String(dwr.IndexDataTablename),
@@ -1622,6 +2182,97 @@ func (dwr *DynamoDB) GetBookmarkedRepos(ctx context.Context) ([]string, error) {
 	return userMeta.BookmarkedRepos, err
 }
 
+func (dwr *DynamoDB) SetBookmarkList(ctx context.Context, list mTypes.BookmarkList) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	list, err = common.NewBookmarkList(userAc, list)
+	if err != nil {
+		return err
+	}
+
+	userData, err := dwr.GetUserData(ctx)
+	if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+		return err
+	}
+
+	if userData.BookmarkLists == nil {
+		userData.BookmarkLists = map[string]mTypes.BookmarkList{}
+	}
+
+	userData.BookmarkLists[list.Name] = list
+
+	return dwr.SetUserData(ctx, userData)
+}
+
+func (dwr *DynamoDB) DeleteBookmarkList(ctx context.Context, name string) error {
+	userData, err := dwr.GetUserData(ctx)
+	if err != nil {
+		if errors.Is(err, zerr.ErrUserDataNotFound) {
+			return zerr.ErrBookmarkListNotFound
+		}
+
+		return err
+	}
+
+	if _, ok := userData.BookmarkLists[name]; !ok {
+		return zerr.ErrBookmarkListNotFound
+	}
+
+	delete(userData.BookmarkLists, name)
+
+	return dwr.SetUserData(ctx, userData)
+}
+
+func (dwr *DynamoDB) GetBookmarkLists(ctx context.Context) ([]mTypes.BookmarkList, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return []mTypes.BookmarkList{}, err
+	}
+
+	if userAc.IsAnonymous() {
+		return []mTypes.BookmarkList{}, nil
+	}
+
+	lists := []mTypes.BookmarkList{}
+
+	// the lists shared with the groups of the user are stored in the user data of their owners
+	userDataAttributeIterator := NewBaseDynamoAttributesIterator(
+		dwr.Client, dwr.UserDataTablename, "UserData", 0, dwr.Log,
+	)
+
+	userDataAttribute, err := userDataAttributeIterator.First(ctx)
+
+	for ; userDataAttribute != nil; userDataAttribute, err = userDataAttributeIterator.Next(ctx) {
+		if err != nil {
+			return []mTypes.BookmarkList{}, err
+		}
+
+		var userData mTypes.UserData
+
+		err := attributevalue.Unmarshal(userDataAttribute, &userData)
+		if err != nil {
+			return []mTypes.BookmarkList{}, err
+		}
+
+		for _, list := range userData.BookmarkLists {
+			lists = append(lists, list)
+		}
+	}
+
+	if err != nil {
+		return []mTypes.BookmarkList{}, err
+	}
+
+	return common.GetReadableBookmarkLists(userAc, lists), nil
+}
+
 func (dwr *DynamoDB) ToggleStarRepo(ctx context.Context, repo string) (
 	mTypes.ToggleState, error,
 ) {
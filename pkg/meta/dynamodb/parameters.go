@@ -11,6 +11,12 @@ import (
 type DBDriverParameters struct {
 	Endpoint, Region, RepoMetaTablename, ManifestDataTablename, IndexDataTablename,
 	UserDataTablename, APIKeyTablename, VersionTablename string
+	// optional, the layer index is disabled if not set
+	LayerIndexTablename string
+	// optional, the download statistics aren't recorded if not set
+	DownloadStatsTablename string
+	// optional, the activity of the users isn't recorded if not set
+	UserActivityTablename string
 }
 
 func GetDynamoClient(params DBDriverParameters) (*dynamodb.Client, error) {
@@ -1,6 +1,10 @@
 package meta
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
 	godigest "github.com/opencontainers/go-digest"
 
 	"zotregistry.io/zot/pkg/log"
@@ -166,3 +170,32 @@ func OnGetManifest(name, reference string, body []byte,
 
 	return nil
 }
+
+// downloadClientIDSize is the number of bytes of the hashes identifying the clients in the download statistics.
+const downloadClientIDSize = 8
+
+// OnDownload records the pull of an image in the download statistics. The client identities are hashed, so the
+// statistics only count the unique clients without storing who they are. Errors are only logged, they don't fail
+// the pull.
+func OnDownload(repo, reference string, digest godigest.Digest, body []byte, client string,
+	metaDB mTypes.MetaDB, log log.Logger,
+) {
+	isSignature, _, _, err := storage.CheckIsImageSignature(repo, body, reference)
+	if err != nil || isSignature {
+		return
+	}
+
+	clientHash := sha256.Sum256([]byte(client))
+
+	err = metaDB.RecordDownload(mTypes.DownloadRecord{
+		Repo:      repo,
+		Reference: reference,
+		Digest:    digest.String(),
+		Client:    hex.EncodeToString(clientHash[:downloadClientIDSize]),
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("repository", repo).Str("reference", reference).
+			Msg("failed to record the download of the image")
+	}
+}
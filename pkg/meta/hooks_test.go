@@ -33,7 +33,7 @@ func TestOnUpdateManifest(t *testing.T) {
 		log := log.NewLogger("debug", "")
 		metrics := monitoring.NewMetricsServer(false, log)
 		storeController.DefaultStore = local.NewImageStore(rootDir, true, true, 1*time.Second,
-			1*time.Second, true, true, log, metrics, nil, nil,
+			1*time.Second, true, true, false, log, metrics, nil, nil,
 		)
 
 		params := boltdb.DBParameters{
@@ -74,7 +74,7 @@ func TestOnUpdateManifest(t *testing.T) {
 		log := log.NewLogger("debug", "")
 		metrics := monitoring.NewMetricsServer(false, log)
 		storeController.DefaultStore = local.NewImageStore(rootDir, true, true, 1*time.Second,
-			1*time.Second, true, true, log, metrics, nil, nil,
+			1*time.Second, true, true, false, log, metrics, nil, nil,
 		)
 
 		metaDB := mocks.MetaDBMock{
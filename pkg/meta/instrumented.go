@@ -0,0 +1,455 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+)
+
+// NewInstrumentedMetaDB returns a MetaDB measuring the latency and counting the errors of each operation, the
+// operations taking longer than slowThreshold are also logged, unless it's 0.
+// Missing metadata isn't counted as an error, the callers expect it, e.g. for a repo pushed for the first time.
+func NewInstrumentedMetaDB(metaDB mTypes.MetaDB, metrics monitoring.MetricServer, slowThreshold time.Duration,
+	log log.Logger,
+) mTypes.MetaDB {
+	if metaDB == nil {
+		return metaDB
+	}
+
+	return &instrumentedMetaDB{MetaDB: metaDB, metrics: metrics, slowThreshold: slowThreshold, log: log}
+}
+
+type instrumentedMetaDB struct {
+	mTypes.MetaDB
+	metrics       monitoring.MetricServer
+	slowThreshold time.Duration
+	log           log.Logger
+}
+
+func (db *instrumentedMetaDB) observe(operation string, start time.Time, err error) error {
+	latency := time.Since(start)
+
+	monitoring.ObserveMetaDBLatency(db.metrics, operation, latency)
+
+	if err != nil && !isExpectedMetaDBError(err) {
+		monitoring.IncMetaDBErrors(db.metrics, operation)
+	}
+
+	if db.slowThreshold > 0 && latency >= db.slowThreshold {
+		db.log.Warn().Str("operation", operation).Dur("latency", latency).Dur("threshold", db.slowThreshold).
+			Msg("slow metaDB operation")
+	}
+
+	return err
+}
+
+func isExpectedMetaDBError(err error) bool {
+	return errors.Is(err, zerr.ErrRepoMetaNotFound) || errors.Is(err, zerr.ErrManifestMetaNotFound) ||
+		errors.Is(err, zerr.ErrManifestDataNotFound) || errors.Is(err, zerr.ErrTagMetaNotFound) ||
+		errors.Is(err, zerr.ErrUserDataNotFound) || errors.Is(err, zerr.ErrUserAPIKeyNotFound) ||
+		errors.Is(err, zerr.ErrBookmarkListNotFound) || errors.Is(err, context.Canceled)
+}
+
+func (db *instrumentedMetaDB) GetStarredRepos(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	result, err := db.MetaDB.GetStarredRepos(ctx)
+
+	return result, db.observe("GetStarredRepos", start, err)
+}
+
+func (db *instrumentedMetaDB) GetBookmarkedRepos(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	result, err := db.MetaDB.GetBookmarkedRepos(ctx)
+
+	return result, db.observe("GetBookmarkedRepos", start, err)
+}
+
+func (db *instrumentedMetaDB) ToggleStarRepo(ctx context.Context, reponame string) (mTypes.ToggleState, error) {
+	start := time.Now()
+	toggleState, err := db.MetaDB.ToggleStarRepo(ctx, reponame)
+
+	return toggleState, db.observe("ToggleStarRepo", start, err)
+}
+
+func (db *instrumentedMetaDB) ToggleBookmarkRepo(ctx context.Context, reponame string) (mTypes.ToggleState, error) {
+	start := time.Now()
+	toggleState, err := db.MetaDB.ToggleBookmarkRepo(ctx, reponame)
+
+	return toggleState, db.observe("ToggleBookmarkRepo", start, err)
+}
+
+func (db *instrumentedMetaDB) SetBookmarkList(ctx context.Context, list mTypes.BookmarkList) error {
+	start := time.Now()
+
+	return db.observe("SetBookmarkList", start, db.MetaDB.SetBookmarkList(ctx, list))
+}
+
+func (db *instrumentedMetaDB) DeleteBookmarkList(ctx context.Context, name string) error {
+	start := time.Now()
+
+	return db.observe("DeleteBookmarkList", start, db.MetaDB.DeleteBookmarkList(ctx, name))
+}
+
+func (db *instrumentedMetaDB) GetBookmarkLists(ctx context.Context) ([]mTypes.BookmarkList, error) {
+	start := time.Now()
+	lists, err := db.MetaDB.GetBookmarkLists(ctx)
+
+	return lists, db.observe("GetBookmarkLists", start, err)
+}
+
+func (db *instrumentedMetaDB) GetUserData(ctx context.Context) (mTypes.UserData, error) {
+	start := time.Now()
+	userData, err := db.MetaDB.GetUserData(ctx)
+
+	return userData, db.observe("GetUserData", start, err)
+}
+
+func (db *instrumentedMetaDB) SetUserData(ctx context.Context, userData mTypes.UserData) error {
+	start := time.Now()
+
+	return db.observe("SetUserData", start, db.MetaDB.SetUserData(ctx, userData))
+}
+
+func (db *instrumentedMetaDB) SetUserGroups(ctx context.Context, groups []string) error {
+	start := time.Now()
+
+	return db.observe("SetUserGroups", start, db.MetaDB.SetUserGroups(ctx, groups))
+}
+
+func (db *instrumentedMetaDB) GetUserGroups(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	result, err := db.MetaDB.GetUserGroups(ctx)
+
+	return result, db.observe("GetUserGroups", start, err)
+}
+
+func (db *instrumentedMetaDB) DeleteUserData(ctx context.Context) error {
+	start := time.Now()
+
+	return db.observe("DeleteUserData", start, db.MetaDB.DeleteUserData(ctx))
+}
+
+func (db *instrumentedMetaDB) GetUserAPIKeyInfo(hashedKey string) (string, error) {
+	start := time.Now()
+	identity, err := db.MetaDB.GetUserAPIKeyInfo(hashedKey)
+
+	return identity, db.observe("GetUserAPIKeyInfo", start, err)
+}
+
+func (db *instrumentedMetaDB) GetUserAPIKeys(ctx context.Context) ([]mTypes.APIKeyDetails, error) {
+	start := time.Now()
+	apiKeys, err := db.MetaDB.GetUserAPIKeys(ctx)
+
+	return apiKeys, db.observe("GetUserAPIKeys", start, err)
+}
+
+func (db *instrumentedMetaDB) AddUserAPIKey(ctx context.Context, hashedKey string, apiKeyDetails *mTypes.APIKeyDetails,
+) error {
+	start := time.Now()
+
+	return db.observe("AddUserAPIKey", start, db.MetaDB.AddUserAPIKey(ctx, hashedKey, apiKeyDetails))
+}
+
+func (db *instrumentedMetaDB) IsAPIKeyExpired(ctx context.Context, hashedKey string) (bool, error) {
+	start := time.Now()
+	expired, err := db.MetaDB.IsAPIKeyExpired(ctx, hashedKey)
+
+	return expired, db.observe("IsAPIKeyExpired", start, err)
+}
+
+func (db *instrumentedMetaDB) UpdateUserAPIKeyLastUsed(ctx context.Context, hashedKey string) error {
+	start := time.Now()
+
+	return db.observe("UpdateUserAPIKeyLastUsed", start, db.MetaDB.UpdateUserAPIKeyLastUsed(ctx, hashedKey))
+}
+
+func (db *instrumentedMetaDB) DeleteUserAPIKey(ctx context.Context, id string) error {
+	start := time.Now()
+
+	return db.observe("DeleteUserAPIKey", start, db.MetaDB.DeleteUserAPIKey(ctx, id))
+}
+
+func (db *instrumentedMetaDB) IncrementRepoStars(repo string) error {
+	start := time.Now()
+
+	return db.observe("IncrementRepoStars", start, db.MetaDB.IncrementRepoStars(repo))
+}
+
+func (db *instrumentedMetaDB) DecrementRepoStars(repo string) error {
+	start := time.Now()
+
+	return db.observe("DecrementRepoStars", start, db.MetaDB.DecrementRepoStars(repo))
+}
+
+func (db *instrumentedMetaDB) GetRepoStars(repo string) (int, error) {
+	start := time.Now()
+	stars, err := db.MetaDB.GetRepoStars(repo)
+
+	return stars, db.observe("GetRepoStars", start, err)
+}
+
+func (db *instrumentedMetaDB) SetRepoReadme(repo string, readme *mTypes.RepoReadme) error {
+	start := time.Now()
+
+	return db.observe("SetRepoReadme", start, db.MetaDB.SetRepoReadme(repo, readme))
+}
+
+func (db *instrumentedMetaDB) SetRepoReference(repo string, reference string, manifestDigest godigest.Digest,
+	mediaType string,
+) error {
+	start := time.Now()
+
+	return db.observe("SetRepoReference", start, db.MetaDB.SetRepoReference(repo, reference, manifestDigest, mediaType))
+}
+
+func (db *instrumentedMetaDB) DeleteRepoTag(repo string, tag string) error {
+	start := time.Now()
+
+	return db.observe("DeleteRepoTag", start, db.MetaDB.DeleteRepoTag(repo, tag))
+}
+
+func (db *instrumentedMetaDB) DeleteRepoMeta(repo string) error {
+	start := time.Now()
+
+	return db.observe("DeleteRepoMeta", start, db.MetaDB.DeleteRepoMeta(repo))
+}
+
+func (db *instrumentedMetaDB) GetRepoMeta(repo string) (mTypes.RepoMetadata, error) {
+	start := time.Now()
+	repoMeta, err := db.MetaDB.GetRepoMeta(repo)
+
+	return repoMeta, db.observe("GetRepoMeta", start, err)
+}
+
+func (db *instrumentedMetaDB) GetUserRepoMeta(ctx context.Context, repo string) (mTypes.RepoMetadata, error) {
+	start := time.Now()
+	repoMeta, err := db.MetaDB.GetUserRepoMeta(ctx, repo)
+
+	return repoMeta, db.observe("GetUserRepoMeta", start, err)
+}
+
+func (db *instrumentedMetaDB) SetRepoMeta(repo string, repoMeta mTypes.RepoMetadata) error {
+	start := time.Now()
+
+	return db.observe("SetRepoMeta", start, db.MetaDB.SetRepoMeta(repo, repoMeta))
+}
+
+func (db *instrumentedMetaDB) GetMultipleRepoMeta(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+) ([]mTypes.RepoMetadata, error) {
+	start := time.Now()
+	repos, err := db.MetaDB.GetMultipleRepoMeta(ctx, filter)
+
+	return repos, db.observe("GetMultipleRepoMeta", start, err)
+}
+
+func (db *instrumentedMetaDB) SetManifestData(manifestDigest godigest.Digest, md mTypes.ManifestData) error {
+	start := time.Now()
+
+	return db.observe("SetManifestData", start, db.MetaDB.SetManifestData(manifestDigest, md))
+}
+
+func (db *instrumentedMetaDB) GetManifestData(manifestDigest godigest.Digest) (mTypes.ManifestData, error) {
+	start := time.Now()
+	manifestData, err := db.MetaDB.GetManifestData(manifestDigest)
+
+	return manifestData, db.observe("GetManifestData", start, err)
+}
+
+func (db *instrumentedMetaDB) GetManifestMeta(repo string, manifestDigest godigest.Digest,
+) (mTypes.ManifestMetadata, error) {
+	start := time.Now()
+	manifestMeta, err := db.MetaDB.GetManifestMeta(repo, manifestDigest)
+
+	return manifestMeta, db.observe("GetManifestMeta", start, err)
+}
+
+func (db *instrumentedMetaDB) SetManifestMeta(repo string, manifestDigest godigest.Digest, mm mTypes.ManifestMetadata,
+) error {
+	start := time.Now()
+
+	return db.observe("SetManifestMeta", start, db.MetaDB.SetManifestMeta(repo, manifestDigest, mm))
+}
+
+func (db *instrumentedMetaDB) SetIndexData(digest godigest.Digest, indexData mTypes.IndexData) error {
+	start := time.Now()
+
+	return db.observe("SetIndexData", start, db.MetaDB.SetIndexData(digest, indexData))
+}
+
+func (db *instrumentedMetaDB) GetIndexData(indexDigest godigest.Digest) (mTypes.IndexData, error) {
+	start := time.Now()
+	indexData, err := db.MetaDB.GetIndexData(indexDigest)
+
+	return indexData, db.observe("GetIndexData", start, err)
+}
+
+func (db *instrumentedMetaDB) SetReferrer(repo string, referredDigest godigest.Digest, referrer mTypes.ReferrerInfo,
+) error {
+	start := time.Now()
+
+	return db.observe("SetReferrer", start, db.MetaDB.SetReferrer(repo, referredDigest, referrer))
+}
+
+func (db *instrumentedMetaDB) DeleteReferrer(repo string, referredDigest godigest.Digest,
+	referrerDigest godigest.Digest,
+) error {
+	start := time.Now()
+
+	return db.observe("DeleteReferrer", start, db.MetaDB.DeleteReferrer(repo, referredDigest, referrerDigest))
+}
+
+func (db *instrumentedMetaDB) GetReferrersInfo(repo string, referredDigest godigest.Digest, artifactTypes []string,
+) ([]mTypes.ReferrerInfo, error) {
+	start := time.Now()
+	referrers, err := db.MetaDB.GetReferrersInfo(repo, referredDigest, artifactTypes)
+
+	return referrers, db.observe("GetReferrersInfo", start, err)
+}
+
+func (db *instrumentedMetaDB) IncrementImageDownloads(repo string, reference string) error {
+	start := time.Now()
+
+	return db.observe("IncrementImageDownloads", start, db.MetaDB.IncrementImageDownloads(repo, reference))
+}
+
+func (db *instrumentedMetaDB) RecordDownload(record mTypes.DownloadRecord) error {
+	start := time.Now()
+
+	return db.observe("RecordDownload", start, db.MetaDB.RecordDownload(record))
+}
+
+func (db *instrumentedMetaDB) GetDownloadStats(ctx context.Context, repo string, from, to time.Time,
+) ([]mTypes.DailyDownloads, error) {
+	start := time.Now()
+	stats, err := db.MetaDB.GetDownloadStats(ctx, repo, from, to)
+
+	return stats, db.observe("GetDownloadStats", start, err)
+}
+
+func (db *instrumentedMetaDB) DeleteDownloadStats(before time.Time) error {
+	start := time.Now()
+
+	return db.observe("DeleteDownloadStats", start, db.MetaDB.DeleteDownloadStats(before))
+}
+
+func (db *instrumentedMetaDB) RollupDownloadStats(before time.Time) error {
+	start := time.Now()
+
+	return db.observe("RollupDownloadStats", start, db.MetaDB.RollupDownloadStats(before))
+}
+
+func (db *instrumentedMetaDB) RecordUserActivity(activity mTypes.UserActivity) error {
+	start := time.Now()
+
+	return db.observe("RecordUserActivity", start, db.MetaDB.RecordUserActivity(activity))
+}
+
+func (db *instrumentedMetaDB) GetUserActivity(ctx context.Context, user string, from, to time.Time,
+) ([]mTypes.UserActivity, error) {
+	start := time.Now()
+	activity, err := db.MetaDB.GetUserActivity(ctx, user, from, to)
+
+	return activity, db.observe("GetUserActivity", start, err)
+}
+
+func (db *instrumentedMetaDB) DeleteUserActivity(before time.Time) error {
+	start := time.Now()
+
+	return db.observe("DeleteUserActivity", start, db.MetaDB.DeleteUserActivity(before))
+}
+
+func (db *instrumentedMetaDB) AddManifestSignature(repo string, signedManifestDigest godigest.Digest,
+	sm mTypes.SignatureMetadata,
+) error {
+	start := time.Now()
+
+	return db.observe("AddManifestSignature", start, db.MetaDB.AddManifestSignature(repo, signedManifestDigest, sm))
+}
+
+func (db *instrumentedMetaDB) DeleteSignature(repo string, signedManifestDigest godigest.Digest,
+	sm mTypes.SignatureMetadata,
+) error {
+	start := time.Now()
+
+	return db.observe("DeleteSignature", start, db.MetaDB.DeleteSignature(repo, signedManifestDigest, sm))
+}
+
+func (db *instrumentedMetaDB) UpdateSignaturesValidity(repo string, manifestDigest godigest.Digest) error {
+	start := time.Now()
+
+	return db.observe("UpdateSignaturesValidity", start, db.MetaDB.UpdateSignaturesValidity(repo, manifestDigest))
+}
+
+func (db *instrumentedMetaDB) SearchRepos(ctx context.Context, searchText string,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	start := time.Now()
+	repos, manifestMetaMap, indexDataMap, err := db.MetaDB.SearchRepos(ctx, searchText)
+
+	return repos, manifestMetaMap, indexDataMap, db.observe("SearchRepos", start, err)
+}
+
+func (db *instrumentedMetaDB) SearchTags(ctx context.Context, searchText string,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	start := time.Now()
+	repos, manifestMetaMap, indexDataMap, err := db.MetaDB.SearchTags(ctx, searchText)
+
+	return repos, manifestMetaMap, indexDataMap, db.observe("SearchTags", start, err)
+}
+
+func (db *instrumentedMetaDB) FilterRepos(ctx context.Context, filter mTypes.FilterRepoFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	start := time.Now()
+	repos, manifestMetaMap, indexDataMap, err := db.MetaDB.FilterRepos(ctx, filter)
+
+	return repos, manifestMetaMap, indexDataMap, db.observe("FilterRepos", start, err)
+}
+
+func (db *instrumentedMetaDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	start := time.Now()
+	repos, manifestMetaMap, indexDataMap, err := db.MetaDB.FilterTags(ctx, filterFunc)
+
+	return repos, manifestMetaMap, indexDataMap, db.observe("FilterTags", start, err)
+}
+
+func (db *instrumentedMetaDB) FilterTagsByManifests(ctx context.Context, manifestDigests []godigest.Digest,
+	filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	start := time.Now()
+	repos, manifestMetaMap, indexDataMap, err := db.MetaDB.FilterTagsByManifests(ctx, manifestDigests, filterFunc)
+
+	return repos, manifestMetaMap, indexDataMap, db.observe("FilterTagsByManifests", start, err)
+}
+
+func (db *instrumentedMetaDB) GetManifestsWithLayer(layerDigest godigest.Digest) ([]godigest.Digest, error) {
+	start := time.Now()
+	digests, err := db.MetaDB.GetManifestsWithLayer(layerDigest)
+
+	return digests, db.observe("GetManifestsWithLayer", start, err)
+}
+
+func (db *instrumentedMetaDB) PatchDB() error {
+	start := time.Now()
+
+	return db.observe("PatchDB", start, db.MetaDB.PatchDB())
+}
+
+func (db *instrumentedMetaDB) Backup(ctx context.Context, writer io.Writer) error {
+	start := time.Now()
+
+	return db.observe("Backup", start, db.MetaDB.Backup(ctx, writer))
+}
+
+func (db *instrumentedMetaDB) Restore(ctx context.Context, reader io.Reader) error {
+	start := time.Now()
+
+	return db.observe("Restore", start, db.MetaDB.Restore(ctx, reader))
+}
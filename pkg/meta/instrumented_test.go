@@ -0,0 +1,88 @@
+package meta_test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta"
+	"zotregistry.io/zot/pkg/meta/boltdb"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/test/mocks"
+)
+
+func TestInstrumentedMetaDB(t *testing.T) {
+	Convey("Instrument the metaDB operations", t, func() {
+		logFile := path.Join(t.TempDir(), "zot.log")
+		logger := log.NewLogger("debug", logFile)
+		metrics := monitoring.NewMetricsServer(false, logger)
+
+		So(meta.NewInstrumentedMetaDB(nil, metrics, time.Second, logger), ShouldBeNil)
+
+		Convey("The operations are passed through", func() {
+			boltDriver, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: t.TempDir()})
+			So(err, ShouldBeNil)
+
+			boltDB, err := boltdb.New(boltDriver, logger)
+			So(err, ShouldBeNil)
+
+			metaDB := meta.NewInstrumentedMetaDB(boltDB, metrics, 0, logger)
+
+			err = metaDB.SetRepoReference("repo", "tag", godigest.FromString("manifest"), "mediaType")
+			So(err, ShouldBeNil)
+
+			repoMeta, err := metaDB.GetRepoMeta("repo")
+			So(err, ShouldBeNil)
+			So(repoMeta.Tags, ShouldContainKey, "tag")
+
+			_, err = metaDB.GetRepoMeta("missing")
+			So(errors.Is(err, zerr.ErrRepoMetaNotFound), ShouldBeTrue)
+
+			err = metaDB.DeleteRepoTag("repo", "tag")
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta("repo")
+			So(err, ShouldBeNil)
+			So(repoMeta.Tags, ShouldBeEmpty)
+
+			content, err := os.ReadFile(logFile)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldNotContainSubstring, "slow metaDB operation")
+		})
+
+		Convey("The slow operations are logged", func() {
+			mockedMetaDB := mocks.MetaDBMock{
+				GetRepoMetaFn: func(repo string) (mTypes.RepoMetadata, error) {
+					time.Sleep(20 * time.Millisecond)
+
+					return mTypes.RepoMetadata{}, zerr.ErrBadConfig
+				},
+				SetRepoMetaFn: func(repo string, repoMeta mTypes.RepoMetadata) error {
+					return nil
+				},
+			}
+
+			metaDB := meta.NewInstrumentedMetaDB(mockedMetaDB, metrics, 10*time.Millisecond, logger)
+
+			_, err := metaDB.GetRepoMeta("repo")
+			So(err, ShouldEqual, zerr.ErrBadConfig)
+
+			err = metaDB.SetRepoMeta("repo", mTypes.RepoMetadata{})
+			So(err, ShouldBeNil)
+
+			content, err := os.ReadFile(logFile)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldContainSubstring, "slow metaDB operation")
+			So(string(content), ShouldContainSubstring, `"operation":"GetRepoMeta"`)
+			So(string(content), ShouldNotContainSubstring, `"operation":"SetRepoMeta"`)
+		})
+	})
+}
@@ -1,7 +1,10 @@
 package meta
 
 import (
+	"database/sql"
+
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	goredis "github.com/go-redis/redis/v8"
 	"go.etcd.io/bbolt"
 
 	"zotregistry.io/zot/errors"
@@ -9,10 +12,67 @@ import (
 	"zotregistry.io/zot/pkg/log"
 	"zotregistry.io/zot/pkg/meta/boltdb"
 	mdynamodb "zotregistry.io/zot/pkg/meta/dynamodb"
+	"zotregistry.io/zot/pkg/meta/postgres"
+	mredis "zotregistry.io/zot/pkg/meta/redis"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/storage/cache"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
 )
 
+// NewFromConfig returns the metaDB of the database in the config, or the one of the storage config if it isn't set.
+func NewFromConfig(storageConfig config.GlobalStorageConfig, log log.Logger) (mTypes.MetaDB, error) {
+	if storageConfig.MetaDB == nil {
+		return New(storageConfig.StorageConfig, log)
+	}
+
+	if storageConfig.MetaDB.Driver == "redis" {
+		params := mredis.DBParameters{
+			URL:       storageConfig.MetaDB.URL,
+			KeyPrefix: storageConfig.MetaDB.TablePrefix,
+			PoolSize:  storageConfig.MetaDB.MaxOpenConns,
+		}
+
+		client, err := mredis.GetRedisClient(params)
+		if err != nil {
+			return nil, err
+		}
+
+		return Create("redis", client, params, log) //nolint:contextcheck
+	}
+
+	params := postgres.DBParameters{
+		URL:          storageConfig.MetaDB.URL,
+		TablePrefix:  storageConfig.MetaDB.TablePrefix,
+		MaxOpenConns: storageConfig.MetaDB.MaxOpenConns,
+	}
+
+	driver, err := postgres.GetPostgresDriver(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return Create("postgres", driver, params, log) //nolint:contextcheck
+}
+
 func New(storageConfig config.StorageConfig, log log.Logger) (mTypes.MetaDB, error) {
+	if storageConfig.RemoteCache && storageConfig.CacheDriver["name"] == storageConstants.PostgresDriverName {
+		// the metaDB shares the database of the cache
+		cacheParams := cache.GetPostgresDriverParameters(storageConfig.CacheDriver)
+
+		params := postgres.DBParameters{
+			URL:          cacheParams.URL,
+			TablePrefix:  cacheParams.TablePrefix,
+			MaxOpenConns: cacheParams.MaxOpenConns,
+		}
+
+		driver, err := postgres.GetPostgresDriver(params)
+		if err != nil {
+			return nil, err
+		}
+
+		return Create("postgres", driver, params, log) //nolint:contextcheck
+	}
+
 	if storageConfig.RemoteCache {
 		dynamoParams := getDynamoParams(storageConfig.CacheDriver, log)
 
@@ -62,6 +122,34 @@ func Create(dbtype string, dbDriver, parameters interface{}, log log.Logger, //n
 
 			return mdynamodb.New(properDriver, properParameters, log)
 		}
+	case "postgres":
+		{
+			properDriver, ok := dbDriver.(*sql.DB)
+			if !ok {
+				panic("failed type assertion")
+			}
+
+			properParameters, ok := parameters.(postgres.DBParameters)
+			if !ok {
+				panic("failed type assertion")
+			}
+
+			return postgres.New(properDriver, properParameters, log)
+		}
+	case "redis":
+		{
+			properDriver, ok := dbDriver.(goredis.UniversalClient)
+			if !ok {
+				panic("failed type assertion")
+			}
+
+			properParameters, ok := parameters.(mredis.DBParameters)
+			if !ok {
+				panic("failed type assertion")
+			}
+
+			return mredis.New(properDriver, properParameters, log)
+		}
 	default:
 		{
 			return nil, errors.ErrBadConfig
@@ -100,15 +188,23 @@ func getDynamoParams(cacheDriverConfig map[string]interface{}, log log.Logger) m
 		panic("dynamo parameters are not specified correctly, can't proceede")
 	}
 
+	// the layer index, download statistics and user activity tables are optional
+	layerIndexTablename, _ := cacheDriverConfig["layerindextablename"].(string)
+	downloadStatsTablename, _ := cacheDriverConfig["downloadstatstablename"].(string)
+	userActivityTablename, _ := cacheDriverConfig["useractivitytablename"].(string)
+
 	return mdynamodb.DBDriverParameters{
-		Endpoint:              endpoint,
-		Region:                region,
-		RepoMetaTablename:     repoMetaTablename,
-		ManifestDataTablename: manifestDataTablename,
-		IndexDataTablename:    indexDataTablename,
-		UserDataTablename:     userDataTablename,
-		APIKeyTablename:       apiKeyTablename,
-		VersionTablename:      versionTablename,
+		Endpoint:               endpoint,
+		Region:                 region,
+		RepoMetaTablename:      repoMetaTablename,
+		ManifestDataTablename:  manifestDataTablename,
+		IndexDataTablename:     indexDataTablename,
+		UserDataTablename:      userDataTablename,
+		APIKeyTablename:        apiKeyTablename,
+		VersionTablename:       versionTablename,
+		LayerIndexTablename:    layerIndexTablename,
+		DownloadStatsTablename: downloadStatsTablename,
+		UserActivityTablename:  userActivityTablename,
 	}
 }
 
@@ -4,12 +4,15 @@
 package meta_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,12 +26,16 @@ import (
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 	. "github.com/smartystreets/goconvey/convey"
 
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
 	"zotregistry.io/zot/pkg/extensions/imagetrust"
 	"zotregistry.io/zot/pkg/log"
 	"zotregistry.io/zot/pkg/meta"
 	"zotregistry.io/zot/pkg/meta/boltdb"
 	"zotregistry.io/zot/pkg/meta/common"
 	mdynamodb "zotregistry.io/zot/pkg/meta/dynamodb"
+	"zotregistry.io/zot/pkg/meta/postgres"
+	mredis "zotregistry.io/zot/pkg/meta/redis"
 	mTypes "zotregistry.io/zot/pkg/meta/types"
 	reqCtx "zotregistry.io/zot/pkg/requestcontext"
 	"zotregistry.io/zot/pkg/test"
@@ -107,17 +114,23 @@ func TestDynamoDBWrapper(t *testing.T) {
 	indexDataTablename := "IndexDataTable" + uuid.String()
 	userDataTablename := "UserDataTable" + uuid.String()
 	apiKeyTablename := "ApiKeyTable" + uuid.String()
+	layerIndexTablename := "LayerIndexTable" + uuid.String()
+	downloadStatsTablename := "DownloadStatsTable" + uuid.String()
+	userActivityTablename := "UserActivityTable" + uuid.String()
 
 	Convey("DynamoDB Wrapper", t, func() {
 		dynamoDBDriverParams := mdynamodb.DBDriverParameters{
-			Endpoint:              os.Getenv("DYNAMODBMOCK_ENDPOINT"),
-			RepoMetaTablename:     repoMetaTablename,
-			ManifestDataTablename: manifestDataTablename,
-			IndexDataTablename:    indexDataTablename,
-			VersionTablename:      versionTablename,
-			UserDataTablename:     userDataTablename,
-			APIKeyTablename:       apiKeyTablename,
-			Region:                "us-east-2",
+			Endpoint:               os.Getenv("DYNAMODBMOCK_ENDPOINT"),
+			RepoMetaTablename:      repoMetaTablename,
+			ManifestDataTablename:  manifestDataTablename,
+			IndexDataTablename:     indexDataTablename,
+			VersionTablename:       versionTablename,
+			UserDataTablename:      userDataTablename,
+			APIKeyTablename:        apiKeyTablename,
+			LayerIndexTablename:    layerIndexTablename,
+			DownloadStatsTablename: downloadStatsTablename,
+			UserActivityTablename:  userActivityTablename,
+			Region:                 "us-east-2",
 		}
 
 		t.Logf("using dynamo driver options: %v", dynamoDBDriverParams)
@@ -153,6 +166,116 @@ func TestDynamoDBWrapper(t *testing.T) {
 	})
 }
 
+func TestPostgresWrapper(t *testing.T) {
+	skipPostgres(t)
+
+	uuid, err := guuid.NewV4()
+	if err != nil {
+		panic(err)
+	}
+
+	Convey("Postgres Wrapper", t, func() {
+		params := postgres.DBParameters{
+			URL:         os.Getenv("POSTGRES_URL"),
+			TablePrefix: "zot_" + strings.ReplaceAll(uuid.String(), "-", "") + "_",
+		}
+
+		postgresDriver, err := postgres.GetPostgresDriver(params)
+		So(err, ShouldBeNil)
+
+		defer func() {
+			for _, table := range []string{
+				postgres.VersionTable, postgres.ManifestDataTable, postgres.IndexDataTable, postgres.RepoMetadataTable,
+				postgres.UserDataTable, postgres.UserAPIKeysTable, postgres.LayerIndexTable, postgres.DownloadStatsTable,
+				postgres.UserActivityTable,
+			} {
+				_, _ = postgresDriver.Exec("DROP TABLE IF EXISTS " + params.TablePrefix + table)
+			}
+
+			postgresDriver.Close()
+		}()
+
+		log := log.NewLogger("debug", "")
+
+		postgresWrapper, err := postgres.New(postgresDriver, params, log)
+		So(postgresWrapper, ShouldNotBeNil)
+		So(err, ShouldBeNil)
+
+		imgTrustStore, err := imagetrust.NewLocalImageTrustStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		postgresWrapper.SetImageTrustStore(imgTrustStore)
+
+		resetPostgresTables := func() error {
+			for _, table := range []string{postgres.RepoMetadataTable, postgres.ManifestDataTable} {
+				if _, err := postgresDriver.Exec("DELETE FROM " + params.TablePrefix + table); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		RunMetaDBTests(t, postgresWrapper, resetPostgresTables)
+	})
+}
+
+func TestRedisWrapper(t *testing.T) {
+	skipRedis(t)
+
+	uuid, err := guuid.NewV4()
+	if err != nil {
+		panic(err)
+	}
+
+	Convey("Redis Wrapper", t, func() {
+		params := mredis.DBParameters{
+			URL:       os.Getenv("REDIS_URL"),
+			KeyPrefix: "zot-" + uuid.String(),
+		}
+
+		redisClient, err := mredis.GetRedisClient(params)
+		So(err, ShouldBeNil)
+
+		ctx := context.Background()
+
+		deleteBuckets := func(buckets ...string) error {
+			for _, bucket := range buckets {
+				if err := redisClient.Del(ctx, params.KeyPrefix+":"+bucket).Err(); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		defer func() {
+			_ = deleteBuckets(mredis.VersionBucket, mredis.ManifestDataBucket, mredis.IndexDataBucket,
+				mredis.RepoMetadataBucket, mredis.UserDataBucket, mredis.UserAPIKeysBucket, mredis.LayerIndexBucket,
+				mredis.DownloadStatsBucket, mredis.UserActivityBucket)
+
+			redisClient.Close()
+		}()
+
+		log := log.NewLogger("debug", "")
+
+		redisWrapper, err := mredis.New(redisClient, params, log)
+		So(redisWrapper, ShouldNotBeNil)
+		So(err, ShouldBeNil)
+
+		imgTrustStore, err := imagetrust.NewLocalImageTrustStore(t.TempDir())
+		So(err, ShouldBeNil)
+
+		redisWrapper.SetImageTrustStore(imgTrustStore)
+
+		resetRedisBuckets := func() error {
+			return deleteBuckets(mredis.RepoMetadataBucket, mredis.ManifestDataBucket)
+		}
+
+		RunMetaDBTests(t, redisWrapper, resetRedisBuckets)
+	})
+}
+
 func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func() error) { //nolint: thelper
 	Convey("Test MetaDB Interface implementation", func() {
 		for _, prepFunc := range preparationFuncs {
@@ -694,6 +817,66 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			})
 		})
 
+		Convey("Test DeleteRepoMeta", func() {
+			var (
+				repo1          = "repo1"
+				repo2          = "repo2"
+				tag1           = "0.0.1"
+				manifestDigest = godigest.FromString("fake-manifest1")
+			)
+
+			userAc := reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user1")
+			userAc.SetGlobPatterns("read", map[string]bool{
+				repo1: true,
+				repo2: true,
+			})
+
+			ctx := userAc.DeriveContext(context.Background())
+
+			err := metaDB.SetRepoReference(repo1, tag1, manifestDigest, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetRepoReference(repo2, tag1, manifestDigest, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.ToggleStarRepo(ctx, repo1)
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.ToggleBookmarkRepo(ctx, repo1)
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetBookmarkList(ctx, mTypes.BookmarkList{Name: "list", Repos: []string{repo1, repo2}})
+			So(err, ShouldBeNil)
+
+			err = metaDB.DeleteRepoMeta(repo1)
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.GetRepoMeta(repo1)
+			So(err, ShouldNotBeNil)
+
+			repoMeta, err := metaDB.GetRepoMeta(repo2)
+			So(err, ShouldBeNil)
+			So(repoMeta.Tags[tag1].Digest, ShouldEqual, manifestDigest.String())
+
+			starred, err := metaDB.GetStarredRepos(ctx)
+			So(err, ShouldBeNil)
+			So(starred, ShouldBeEmpty)
+
+			bookmarked, err := metaDB.GetBookmarkedRepos(ctx)
+			So(err, ShouldBeNil)
+			So(bookmarked, ShouldBeEmpty)
+
+			lists, err := metaDB.GetBookmarkLists(ctx)
+			So(err, ShouldBeNil)
+			So(lists, ShouldHaveLength, 1)
+			So(lists[0].Repos, ShouldResemble, []string{repo2})
+
+			// deleting a repo without metadata is not an error
+			err = metaDB.DeleteRepoMeta("InexistentRepo")
+			So(err, ShouldBeNil)
+		})
+
 		Convey("Test GetMultipleRepoMeta", func() {
 			var (
 				repo1           = "repo1"
@@ -804,6 +987,206 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			So(err, ShouldNotBeNil)
 		})
 
+		Convey("Test SetRepoReadme", func() {
+			var (
+				repo1           = "repo1"
+				tag1            = "0.0.1"
+				manifestDigest1 = godigest.FromString("fake-manifest1")
+			)
+
+			err := metaDB.SetRepoReadme(repo1, &mTypes.RepoReadme{Content: "# repo1"})
+			So(err, ShouldEqual, zerr.ErrRepoMetaNotFound)
+
+			err = metaDB.SetRepoReference(repo1, tag1, manifestDigest1, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			lastUpdated := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			err = metaDB.SetRepoReadme(repo1, &mTypes.RepoReadme{
+				Content:     "# repo1",
+				LastUpdated: lastUpdated,
+				UpdatedBy:   "user",
+			})
+			So(err, ShouldBeNil)
+
+			repoMeta, err := metaDB.GetRepoMeta(repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.Readme, ShouldNotBeNil)
+			So(repoMeta.Readme.Content, ShouldEqual, "# repo1")
+			So(repoMeta.Readme.LastUpdated, ShouldEqual, lastUpdated)
+			So(repoMeta.Readme.UpdatedBy, ShouldEqual, "user")
+
+			// the README is kept when the tags change
+			err = metaDB.SetRepoReference(repo1, "0.0.2", manifestDigest1, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta(repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.Readme, ShouldNotBeNil)
+
+			err = metaDB.SetRepoReadme(repo1, nil)
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta(repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.Readme, ShouldBeNil)
+		})
+
+		Convey("Test download statistics", func() {
+			var (
+				repo1   = "repo1"
+				repo2   = "repo2"
+				digest1 = godigest.FromString("fake-manifest1").String()
+				digest2 = godigest.FromString("fake-manifest2").String()
+				day1    = time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+				day2    = day1.AddDate(0, 0, 1)
+				day3    = day1.AddDate(0, 0, 2)
+			)
+
+			records := []mTypes.DownloadRecord{
+				{Repo: repo1, Reference: "1.0", Digest: digest1, Client: "client1", Timestamp: day1},
+				{Repo: repo1, Reference: "1.0", Digest: digest1, Client: "client1", Timestamp: day1},
+				{Repo: repo1, Reference: digest1, Digest: digest1, Client: "client2", Timestamp: day1},
+				{Repo: repo1, Reference: "2.0", Digest: digest2, Client: "client1", Timestamp: day2},
+				{Repo: repo2, Reference: "1.0", Digest: digest2, Client: "client3", Timestamp: day3},
+			}
+
+			for _, record := range records {
+				err := metaDB.RecordDownload(record)
+				So(err, ShouldBeNil)
+			}
+
+			stats, err := metaDB.GetDownloadStats(context.Background(), repo1, day1, day1)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 1)
+			So(stats[0].Repo, ShouldEqual, repo1)
+			So(stats[0].Day, ShouldEqual, "2023-01-01")
+			So(stats[0].Digests[digest1].Count, ShouldEqual, 3)
+			So(stats[0].Digests[digest1].Clients, ShouldResemble, []string{"client1", "client2"})
+			So(stats[0].Tags["1.0"].Count, ShouldEqual, 2)
+			So(stats[0].Tags, ShouldNotContainKey, digest1)
+
+			stats, err = metaDB.GetDownloadStats(context.Background(), "", day1, day3)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 3)
+
+			// only the repos the user can read are returned
+			userAc := reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user")
+			userAc.SetGlobPatterns("read", map[string]bool{repo2: true})
+
+			ctx := userAc.DeriveContext(context.Background())
+
+			stats, err = metaDB.GetDownloadStats(ctx, "", day1, day3)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 1)
+			So(stats[0].Repo, ShouldEqual, repo2)
+
+			err = metaDB.DeleteDownloadStats(day2)
+			So(err, ShouldBeNil)
+
+			stats, err = metaDB.GetDownloadStats(context.Background(), "", day1, day3)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 2)
+
+			for _, dailyStats := range stats {
+				So(dailyStats.Day, ShouldNotEqual, "2023-01-01")
+			}
+		})
+
+		Convey("Test hourly download statistics", func() {
+			var (
+				repo1   = "repo1"
+				digest1 = godigest.FromString("fake-manifest1").String()
+				day1    = time.Date(2023, 1, 1, 10, 30, 0, 0, time.UTC)
+				day2    = day1.AddDate(0, 0, 1)
+			)
+
+			records := []mTypes.DownloadRecord{
+				{Repo: repo1, Reference: "1.0", Digest: digest1, Client: "client1", Timestamp: day1},
+				{Repo: repo1, Reference: "1.0", Digest: digest1, Client: "client1", Timestamp: day1.Add(time.Hour)},
+				{Repo: repo1, Reference: "1.0", Digest: digest1, Client: "client1", Timestamp: day2},
+			}
+
+			for _, record := range records {
+				err := metaDB.RecordDownload(record)
+				So(err, ShouldBeNil)
+			}
+
+			stats, err := metaDB.GetDownloadStats(context.Background(), repo1, day1, day1)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 1)
+			So(len(stats[0].Hours), ShouldEqual, mTypes.DownloadStatsHours)
+			So(stats[0].Hours[10], ShouldEqual, 1)
+			So(stats[0].Hours[11], ShouldEqual, 1)
+			So(stats[0].Tags["1.0"].LastPulled.Equal(day1.Add(time.Hour)), ShouldBeTrue)
+
+			// only the hours of the days before day2 are removed
+			err = metaDB.RollupDownloadStats(day2)
+			So(err, ShouldBeNil)
+
+			stats, err = metaDB.GetDownloadStats(context.Background(), repo1, day1, day2)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 2)
+			So(stats[0].Hours, ShouldBeNil)
+			So(stats[0].Digests[digest1].Count, ShouldEqual, 2)
+			So(stats[1].Hours[10], ShouldEqual, 1)
+		})
+
+		Convey("Test user activity", func() {
+			var (
+				user1 = "activity-user"
+				user2 = "activity-user/other"
+				time1 = time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+				time2 = time1.Add(time.Hour)
+				time3 = time1.Add(2 * time.Hour)
+			)
+
+			activities := []mTypes.UserActivity{
+				{User: user1, Action: mTypes.UserActivityPush, Repo: "repo1", Reference: "1.0", Timestamp: time1},
+				// recorded at the same time, both are kept
+				{User: user1, Action: mTypes.UserActivityStar, Repo: "repo1", Timestamp: time1},
+				{User: user1, Action: mTypes.UserActivityAPIKeyCreate, Details: "label", Timestamp: time2},
+				{User: user1, Action: mTypes.UserActivityDelete, Repo: "repo1", Reference: "1.0", Timestamp: time3},
+				{User: user2, Action: mTypes.UserActivityPush, Repo: "repo2", Reference: "1.0", Timestamp: time2},
+			}
+
+			for _, activity := range activities {
+				err := metaDB.RecordUserActivity(activity)
+				So(err, ShouldBeNil)
+			}
+
+			userActivity, err := metaDB.GetUserActivity(context.Background(), user1, time1, time3)
+			So(err, ShouldBeNil)
+			So(len(userActivity), ShouldEqual, 4)
+			So(userActivity[0].Action, ShouldEqual, mTypes.UserActivityPush)
+			So(userActivity[1].Action, ShouldEqual, mTypes.UserActivityStar)
+			So(userActivity[2].Details, ShouldEqual, "label")
+			So(userActivity[3].Action, ShouldEqual, mTypes.UserActivityDelete)
+
+			userActivity, err = metaDB.GetUserActivity(context.Background(), user1, time2, time2)
+			So(err, ShouldBeNil)
+			So(len(userActivity), ShouldEqual, 1)
+			So(userActivity[0].Action, ShouldEqual, mTypes.UserActivityAPIKeyCreate)
+
+			userActivity, err = metaDB.GetUserActivity(context.Background(), user2, time1, time3)
+			So(err, ShouldBeNil)
+			So(len(userActivity), ShouldEqual, 1)
+			So(userActivity[0].Repo, ShouldEqual, "repo2")
+
+			err = metaDB.DeleteUserActivity(time3)
+			So(err, ShouldBeNil)
+
+			userActivity, err = metaDB.GetUserActivity(context.Background(), user1, time1, time3)
+			So(err, ShouldBeNil)
+			So(len(userActivity), ShouldEqual, 1)
+			So(userActivity[0].Action, ShouldEqual, mTypes.UserActivityDelete)
+
+			userActivity, err = metaDB.GetUserActivity(context.Background(), user2, time1, time3)
+			So(err, ShouldBeNil)
+			So(userActivity, ShouldBeEmpty)
+		})
+
 		Convey("Test GetRepoStars", func() {
 			var (
 				repo1           = "repo1"
@@ -1215,6 +1598,108 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			So(len(repos), ShouldEqual, 0)
 		})
 
+		Convey("Test bookmark lists", func() {
+			var (
+				repo1 = "repo1"
+				repo2 = "repo2"
+				repo3 = "repo3"
+			)
+
+			userAc := reqCtx.NewUserAccessControl()
+			userAc.SetUsername("listOwner")
+			userAc.SetGlobPatterns("read", map[string]bool{
+				repo1: true,
+				repo2: true,
+			})
+
+			ownerCtx := userAc.DeriveContext(context.Background())
+
+			userAc = reqCtx.NewUserAccessControl()
+			userAc.SetUsername("listReader")
+			userAc.AddGroups([]string{"readers"})
+			userAc.SetGlobPatterns("read", map[string]bool{
+				repo1: true,
+			})
+
+			readerCtx := userAc.DeriveContext(context.Background())
+
+			userAc = reqCtx.NewUserAccessControl()
+			userAc.SetUsername("listOutsider")
+			userAc.AddGroups([]string{"others"})
+			userAc.SetGlobPatterns("read", map[string]bool{
+				repo1: true,
+				repo2: true,
+			})
+
+			outsiderCtx := userAc.DeriveContext(context.Background())
+
+			userAc = reqCtx.NewUserAccessControl()
+
+			anonymousCtx := userAc.DeriveContext(context.Background())
+
+			err := metaDB.SetBookmarkList(ownerCtx, mTypes.BookmarkList{
+				Name:       "golden",
+				Repos:      []string{repo1, repo2, repo1},
+				SharedWith: []string{"readers"},
+			})
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetBookmarkList(ownerCtx, mTypes.BookmarkList{Name: "private", Repos: []string{repo2}})
+			So(err, ShouldBeNil)
+
+			// the owner can only add the repos it can read
+			err = metaDB.SetBookmarkList(ownerCtx, mTypes.BookmarkList{Name: "forbidden", Repos: []string{repo3}})
+			So(err, ShouldEqual, zerr.ErrUserDataNotAllowed)
+
+			err = metaDB.SetBookmarkList(ownerCtx, mTypes.BookmarkList{Repos: []string{repo1}})
+			So(err, ShouldNotBeNil)
+
+			err = metaDB.SetBookmarkList(anonymousCtx, mTypes.BookmarkList{Name: "golden", Repos: []string{repo1}})
+			So(err, ShouldNotBeNil)
+
+			lists, err := metaDB.GetBookmarkLists(ownerCtx)
+			So(err, ShouldBeNil)
+			So(lists, ShouldHaveLength, 2)
+			So(lists[0].Name, ShouldEqual, "golden")
+			So(lists[0].Owner, ShouldEqual, "listOwner")
+			So(lists[0].Repos, ShouldResemble, []string{repo1, repo2})
+			So(lists[0].SharedWith, ShouldResemble, []string{"readers"})
+			So(lists[1].Name, ShouldEqual, "private")
+
+			// shared lists only contain the repos the reader can read
+			lists, err = metaDB.GetBookmarkLists(readerCtx)
+			So(err, ShouldBeNil)
+			So(lists, ShouldHaveLength, 1)
+			So(lists[0].Name, ShouldEqual, "golden")
+			So(lists[0].Owner, ShouldEqual, "listOwner")
+			So(lists[0].Repos, ShouldResemble, []string{repo1})
+
+			lists, err = metaDB.GetBookmarkLists(outsiderCtx)
+			So(err, ShouldBeNil)
+			So(lists, ShouldBeEmpty)
+
+			lists, err = metaDB.GetBookmarkLists(anonymousCtx)
+			So(err, ShouldBeNil)
+			So(lists, ShouldBeEmpty)
+
+			// shared lists can't be deleted by their readers
+			err = metaDB.DeleteBookmarkList(readerCtx, "golden")
+			So(err, ShouldEqual, zerr.ErrBookmarkListNotFound)
+
+			err = metaDB.DeleteBookmarkList(ownerCtx, "golden")
+			So(err, ShouldBeNil)
+
+			err = metaDB.DeleteBookmarkList(ownerCtx, "golden")
+			So(err, ShouldEqual, zerr.ErrBookmarkListNotFound)
+
+			lists, err = metaDB.GetBookmarkLists(readerCtx)
+			So(err, ShouldBeNil)
+			So(lists, ShouldBeEmpty)
+
+			err = metaDB.DeleteBookmarkList(ownerCtx, "private")
+			So(err, ShouldBeNil)
+		})
+
 		Convey("Test IncrementImageDownloads", func() {
 			var (
 				repo1 = "repo1"
@@ -1250,6 +1735,26 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			So(err, ShouldBeNil)
 
 			So(repoMeta.Statistics[manifestDigest.String()].DownloadCount, ShouldEqual, 2)
+			So(repoMeta.Statistics[manifestDigest.String()].LastPullTimestamp.IsZero(), ShouldBeFalse)
+			So(repoMeta.TagStatistics[tag1].DownloadCount, ShouldEqual, 2)
+			So(repoMeta.TagStatistics[tag1].LastPullTimestamp, ShouldEqual,
+				repoMeta.Statistics[manifestDigest.String()].LastPullTimestamp)
+
+			// the pulls by digest aren't counted by tag
+			err = metaDB.IncrementImageDownloads(repo1, manifestDigest.String())
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta(repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.Statistics[manifestDigest.String()].DownloadCount, ShouldEqual, 3)
+			So(repoMeta.TagStatistics[tag1].DownloadCount, ShouldEqual, 2)
+
+			err = metaDB.DeleteRepoTag(repo1, tag1)
+			So(err, ShouldBeNil)
+
+			repoMeta, err = metaDB.GetRepoMeta(repo1)
+			So(err, ShouldBeNil)
+			So(repoMeta.TagStatistics, ShouldNotContainKey, tag1)
 
 			_, err = metaDB.GetManifestMeta(repo1, "badManiestDigest")
 			So(err, ShouldNotBeNil)
@@ -2028,6 +2533,80 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			So(keys, ShouldContain, tag3)
 		})
 
+		Convey("Test layer index", func() {
+			var (
+				repo1           = "repo1"
+				layerDigest1    = godigest.FromString("layer1")
+				layerDigest2    = godigest.FromString("layer2")
+				manifestDigest1 = godigest.FromString("fake-manifest1")
+				manifestDigest2 = godigest.FromString("fake-manifest2")
+
+				emptyConfig ispec.Image
+				ctx         = context.Background()
+			)
+
+			emptyConfigBlob, err := json.Marshal(emptyConfig)
+			So(err, ShouldBeNil)
+
+			manifestBlob1, err := json.Marshal(ispec.Manifest{
+				Layers: []ispec.Descriptor{{Digest: layerDigest1}},
+			})
+			So(err, ShouldBeNil)
+
+			manifestBlob2, err := json.Marshal(ispec.Manifest{
+				Layers: []ispec.Descriptor{{Digest: layerDigest1}, {Digest: layerDigest2}},
+			})
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetRepoReference(repo1, "1.0.0", manifestDigest1, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+			err = metaDB.SetRepoReference(repo1, "2.0.0", manifestDigest2, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetManifestData(manifestDigest1, mTypes.ManifestData{
+				ManifestBlob: manifestBlob1,
+				ConfigBlob:   emptyConfigBlob,
+			})
+			So(err, ShouldBeNil)
+			err = metaDB.SetManifestData(manifestDigest2, mTypes.ManifestData{
+				ManifestBlob: manifestBlob2,
+				ConfigBlob:   emptyConfigBlob,
+			})
+			So(err, ShouldBeNil)
+
+			// indexing the same manifest twice doesn't duplicate it
+			err = metaDB.SetManifestData(manifestDigest2, mTypes.ManifestData{
+				ManifestBlob: manifestBlob2,
+				ConfigBlob:   emptyConfigBlob,
+			})
+			So(err, ShouldBeNil)
+
+			manifests, err := metaDB.GetManifestsWithLayer(layerDigest1)
+			So(err, ShouldBeNil)
+			So(manifests, ShouldHaveLength, 2)
+			So(manifests, ShouldContain, manifestDigest1)
+			So(manifests, ShouldContain, manifestDigest2)
+
+			manifests, err = metaDB.GetManifestsWithLayer(layerDigest2)
+			So(err, ShouldBeNil)
+			So(manifests, ShouldResemble, []godigest.Digest{manifestDigest2})
+
+			manifests, err = metaDB.GetManifestsWithLayer(godigest.FromString("missing-layer"))
+			So(err, ShouldBeNil)
+			So(manifests, ShouldBeEmpty)
+
+			repos, manifestMetaMap, _, err := metaDB.FilterTagsByManifests(ctx, []godigest.Digest{manifestDigest2},
+				func(repoMeta mTypes.RepoMetadata, manifestMeta mTypes.ManifestMetadata) bool {
+					return true
+				})
+			So(err, ShouldBeNil)
+			So(len(repos), ShouldEqual, 1)
+			So(repos[0].Tags, ShouldContainKey, "2.0.0")
+			So(repos[0].Tags, ShouldNotContainKey, "1.0.0")
+			So(manifestMetaMap, ShouldContainKey, manifestDigest2.String())
+			So(manifestMetaMap, ShouldNotContainKey, manifestDigest1.String())
+		})
+
 		Convey("Test FilterTags", func() {
 			var (
 				repo1                    = "repo1"
@@ -2523,6 +3102,74 @@ func RunMetaDBTests(t *testing.T, metaDB mTypes.MetaDB, preparationFuncs ...func
 			So(repoMeta.IsStarred, ShouldBeTrue)
 			So(repoMeta.Tags, ShouldContainKey, "tag")
 		})
+
+		Convey("Test Backup and Restore", func() {
+			userAc := reqCtx.NewUserAccessControl()
+			userAc.SetUsername("user1")
+			userAc.SetGlobPatterns("read", map[string]bool{
+				"repo": true,
+			})
+
+			ctx := userAc.DeriveContext(context.Background())
+
+			digest := godigest.FromString("1")
+
+			err := metaDB.SetRepoReference("repo", "tag", digest, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.ToggleStarRepo(ctx, "repo")
+			So(err, ShouldBeNil)
+
+			err = metaDB.IncrementImageDownloads("repo", "tag")
+			So(err, ShouldBeNil)
+
+			backup := bytes.Buffer{}
+
+			err = metaDB.Backup(context.Background(), &backup)
+			if errors.Is(err, zerr.ErrMetaDBBackupNotSupported) {
+				err = metaDB.Restore(context.Background(), &backup)
+				So(err, ShouldEqual, zerr.ErrMetaDBBackupNotSupported)
+
+				return
+			}
+
+			So(err, ShouldBeNil)
+
+			// the changes made after the backup are lost
+			err = metaDB.SetRepoReference("repo2", "tag", digest, ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.ToggleStarRepo(ctx, "repo")
+			So(err, ShouldBeNil)
+
+			Convey("The backup is restored", func() {
+				err = metaDB.Restore(context.Background(), bytes.NewReader(backup.Bytes()))
+				So(err, ShouldBeNil)
+
+				repoMeta, err := metaDB.GetUserRepoMeta(ctx, "repo")
+				So(err, ShouldBeNil)
+				So(repoMeta.IsStarred, ShouldBeTrue)
+				So(repoMeta.Stars, ShouldEqual, 1)
+				So(repoMeta.Statistics[digest.String()].DownloadCount, ShouldEqual, 1)
+
+				_, err = metaDB.GetRepoMeta("repo2")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("An invalid backup isn't restored", func() {
+				err = metaDB.Restore(context.Background(), strings.NewReader("not a backup"))
+				So(errors.Is(err, zerr.ErrBadMetaDBBackup), ShouldBeTrue)
+
+				// the backup is truncated after its header
+				truncated := backup.Bytes()[:len(backup.Bytes())/2]
+
+				err = metaDB.Restore(context.Background(), bytes.NewReader(truncated))
+				So(err, ShouldNotBeNil)
+
+				_, err = metaDB.GetRepoMeta("repo2")
+				So(err, ShouldBeNil)
+			})
+		})
 	})
 }
 
@@ -2663,6 +3310,48 @@ func TestCreateDynamo(t *testing.T) {
 		metaDB, err := meta.Create("random", nil, boltdb.DBParameters{RootDir: "root"}, log)
 		So(metaDB, ShouldBeNil)
 		So(err, ShouldNotBeNil)
+
+		So(func() { _, _ = meta.Create("postgres", nil, postgres.DBParameters{}, log) }, ShouldPanic)
+
+		So(func() { _, _ = meta.Create("redis", nil, mredis.DBParameters{}, log) }, ShouldPanic)
+	})
+}
+
+func TestNewFromConfig(t *testing.T) {
+	Convey("Without a metaDB config", t, func() {
+		storageConfig := config.GlobalStorageConfig{StorageConfig: config.StorageConfig{RootDirectory: t.TempDir()}}
+
+		metaDB, err := meta.NewFromConfig(storageConfig, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+		So(metaDB, ShouldHaveSameTypeAs, &boltdb.BoltDB{})
+	})
+
+	Convey("Postgres isn't reachable", t, func() {
+		storageConfig := config.GlobalStorageConfig{
+			StorageConfig: config.StorageConfig{RootDirectory: t.TempDir()},
+			MetaDB: &config.MetaDBConfig{
+				Driver: "postgres",
+				URL:    fmt.Sprintf("postgres://localhost:%s/zot?sslmode=disable&connect_timeout=1", test.GetFreePort()),
+			},
+		}
+
+		metaDB, err := meta.NewFromConfig(storageConfig, log.NewLogger("debug", ""))
+		So(err, ShouldNotBeNil)
+		So(metaDB, ShouldBeNil)
+	})
+
+	Convey("Redis isn't reachable", t, func() {
+		storageConfig := config.GlobalStorageConfig{
+			StorageConfig: config.StorageConfig{RootDirectory: t.TempDir()},
+			MetaDB: &config.MetaDBConfig{
+				Driver: "redis",
+				URL:    fmt.Sprintf("redis://localhost:%s/0?dial_timeout=1s", test.GetFreePort()),
+			},
+		}
+
+		metaDB, err := meta.NewFromConfig(storageConfig, log.NewLogger("debug", ""))
+		So(err, ShouldNotBeNil)
+		So(metaDB, ShouldBeNil)
 	})
 }
 
@@ -2689,6 +3378,22 @@ func TestCreateBoltDB(t *testing.T) {
 	})
 }
 
+func skipPostgres(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("POSTGRES_URL") == "" {
+		t.Skip("Skipping testing without a postgres server")
+	}
+}
+
+func skipRedis(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("REDIS_URL") == "" {
+		t.Skip("Skipping testing without a redis server")
+	}
+}
+
 func skipDynamo(t *testing.T) {
 	t.Helper()
 
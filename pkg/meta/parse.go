@@ -47,8 +47,8 @@ func ParseRepo(repo string, metaDB mTypes.MetaDB, storeController storage.StoreC
 
 	var lockLatency time.Time
 
-	imageStore.RLock(&lockLatency)
-	defer imageStore.RUnlock(&lockLatency)
+	imageStore.RLockRepo(repo, &lockLatency)
+	defer imageStore.RUnlockRepo(repo, &lockLatency)
 
 	indexBlob, err := imageStore.GetIndexContent(repo)
 	if err != nil {
@@ -160,12 +160,14 @@ func resetRepoMeta(repo string, metaDB mTypes.MetaDB, log log.Logger) error {
 	}
 
 	return metaDB.SetRepoMeta(repo, mTypes.RepoMetadata{
-		Name:       repoMeta.Name,
-		Tags:       map[string]mTypes.Descriptor{},
-		Statistics: repoMeta.Statistics,
-		Signatures: map[string]mTypes.ManifestSignatures{},
-		Referrers:  map[string][]mTypes.ReferrerInfo{},
-		Stars:      repoMeta.Stars,
+		Name:          repoMeta.Name,
+		Tags:          map[string]mTypes.Descriptor{},
+		Statistics:    repoMeta.Statistics,
+		TagStatistics: repoMeta.TagStatistics,
+		Signatures:    map[string]mTypes.ManifestSignatures{},
+		Referrers:     map[string][]mTypes.ReferrerInfo{},
+		Stars:         repoMeta.Stars,
+		Readme:        repoMeta.Readme,
 	})
 }
 
@@ -196,7 +198,8 @@ func getCachedBlob(repo string, descriptor ispec.Descriptor, metaDB mTypes.MetaD
 
 	descriptorBlob, err := getCachedBlobFromMetaDB(descriptor, metaDB)
 
-	if err != nil || len(descriptorBlob) == 0 {
+	// the blobs of the metaDB which drifted from the storage are read again from it
+	if err != nil || len(descriptorBlob) == 0 || int64(len(descriptorBlob)) != descriptor.Size {
 		descriptorBlob, _, _, err = imageStore.GetImageManifest(repo, digest.String())
 		if err != nil {
 			log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
@@ -254,8 +257,8 @@ func getCosignSignatureLayersInfo(
 
 	var lockLatency time.Time
 
-	imageStore.RLock(&lockLatency)
-	defer imageStore.RUnlock(&lockLatency)
+	imageStore.RLockRepo(repo, &lockLatency)
+	defer imageStore.RUnlockRepo(repo, &lockLatency)
 
 	for _, layer := range manifestContent.Layers {
 		layerContent, err := imageStore.GetBlobContent(repo, layer.Digest)
@@ -273,9 +276,12 @@ func getCosignSignatureLayersInfo(
 		}
 
 		layers = append(layers, mTypes.LayerInfo{
-			LayerDigest:  layer.Digest.String(),
-			LayerContent: layerContent,
-			SignatureKey: layerSigKey,
+			LayerDigest:      layer.Digest.String(),
+			LayerContent:     layerContent,
+			SignatureKey:     layerSigKey,
+			Certificate:      layer.Annotations[zcommon.CosignCertificateKey],
+			CertificateChain: layer.Annotations[zcommon.CosignChainKey],
+			Bundle:           layer.Annotations[zcommon.CosignBundleKey],
 		})
 	}
 
@@ -306,8 +312,8 @@ func getNotationSignatureLayersInfo(
 
 	var lockLatency time.Time
 
-	imageStore.RLock(&lockLatency)
-	defer imageStore.RUnlock(&lockLatency)
+	imageStore.RLockRepo(repo, &lockLatency)
+	defer imageStore.RUnlockRepo(repo, &lockLatency)
 
 	layerContent, err := imageStore.GetBlobContent(repo, layer)
 	if err != nil {
@@ -344,8 +350,8 @@ func NewManifestData(repoName string, manifestBlob []byte, imageStore storageTyp
 
 	var lockLatency time.Time
 
-	imageStore.RLock(&lockLatency)
-	defer imageStore.RUnlock(&lockLatency)
+	imageStore.RLockRepo(repoName, &lockLatency)
+	defer imageStore.RUnlockRepo(repoName, &lockLatency)
 
 	configBlob, err := imageStore.GetBlobContent(repoName, manifestContent.Config.Digest)
 	if err != nil {
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"zotregistry.io/zot/pkg/meta/common"
+	"zotregistry.io/zot/pkg/meta/version"
+)
+
+// Backup writes the tables as they are in a single read only transaction, the writes aren't blocked meanwhile.
+func (pdw *PostgresDB) Backup(ctx context.Context, writer io.Writer) error {
+	tables := getBackupTables()
+
+	return pdw.view(func(tx *bucketTx) error {
+		dbVersion := string(tx.Bucket(VersionTable).Get([]byte(version.DBVersionKey)))
+
+		backupWriter, err := common.NewBackupWriter(writer, dbVersion)
+		if err != nil {
+			return err
+		}
+
+		for _, bucketName := range common.GetBackupBuckets() {
+			err := tx.Bucket(tables[bucketName]).ForEach(func(key, value []byte) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				return backupWriter.Write(bucketName, key, value)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return backupWriter.Close()
+	})
+}
+
+// Restore replaces the rows of the tables in a single transaction, they are left unchanged if the backup can't
+// be read.
+func (pdw *PostgresDB) Restore(ctx context.Context, reader io.Reader) error {
+	backupReader, err := common.NewBackupReader(reader, common.GetBackupBuckets())
+	if err != nil {
+		return err
+	}
+
+	tables := getBackupTables()
+
+	err = pdw.update(func(tx *bucketTx) error {
+		for _, bucketName := range common.GetBackupBuckets() {
+			if err := tx.Bucket(tables[bucketName]).Clear(); err != nil {
+				return err
+			}
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			entry, err := backupReader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Bucket(tables[entry.Bucket]).Put([]byte(entry.Key), entry.Value); err != nil {
+				return err
+			}
+		}
+
+		// the version of the header is the one the data is patched from
+		return tx.Bucket(VersionTable).Put([]byte(version.DBVersionKey), []byte(backupReader.Header.DBVersion))
+	})
+	if err != nil {
+		return err
+	}
+
+	return pdw.PatchDB()
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import "zotregistry.io/zot/pkg/meta/common"
+
+// MetadataDB, the tables are named after their prefix and these names.
+const (
+	ManifestDataTable  = "manifest_data"
+	IndexDataTable     = "index_data"
+	RepoMetadataTable  = "repo_metadata"
+	UserDataTable      = "user_data"
+	VersionTable       = "version"
+	UserAPIKeysTable   = "user_api_keys"
+	LayerIndexTable    = "layer_index"
+	DownloadStatsTable = "download_stats"
+	UserActivityTable  = "user_activity"
+)
+
+func getTables() []string {
+	return []string{
+		VersionTable, ManifestDataTable, IndexDataTable, RepoMetadataTable, UserDataTable, UserAPIKeysTable,
+		LayerIndexTable, DownloadStatsTable, UserActivityTable,
+	}
+}
+
+// getBackupTables returns the tables of the buckets of the backups.
+func getBackupTables() map[string]string {
+	return map[string]string{
+		common.BackupVersionBucket:       VersionTable,
+		common.BackupManifestDataBucket:  ManifestDataTable,
+		common.BackupIndexDataBucket:     IndexDataTable,
+		common.BackupRepoMetadataBucket:  RepoMetadataTable,
+		common.BackupUserDataBucket:      UserDataTable,
+		common.BackupUserAPIKeysBucket:   UserAPIKeysTable,
+		common.BackupLayerIndexBucket:    LayerIndexTable,
+		common.BackupDownloadStatsBucket: DownloadStatsTable,
+		common.BackupUserActivityBucket:  UserActivityTable,
+	}
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/lib/pq"
+)
+
+// cursorPageSize is how many rows a cursor reads at once, the rows are read before iterating them because the
+// queries of a transaction can't run while the rows of another one are read.
+const cursorPageSize = 100
+
+/*
+bucketTx gives the metadb the buckets of a boltdb transaction, each bucket is a table of keys and values,
+so both implementations share the same logic. Like in boltdb, the writes are serialized and the reads see a
+snapshot of the database. The getters and the cursors don't return errors like the boltdb ones, the first error
+of their queries is returned by the transaction instead.
+*/
+type bucketTx struct {
+	sqlTx       *sql.Tx
+	tablePrefix string
+	err         error
+}
+
+type bucket struct {
+	tx    *bucketTx
+	table string
+}
+
+type keyValue struct {
+	key   []byte
+	value []byte
+}
+
+type cursor struct {
+	bucket    *bucket
+	rows      []keyValue
+	index     int
+	exhausted bool
+}
+
+// getLockID returns the id of the advisory lock serializing the writes of the instances using these tables.
+func getLockID(tablePrefix string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte("zot-metadb:" + tablePrefix))
+
+	return int64(hash.Sum64())
+}
+
+func (pdw *PostgresDB) update(fn func(tx *bucketTx) error) error {
+	return pdw.runTransaction(&sql.TxOptions{}, true, fn)
+}
+
+func (pdw *PostgresDB) view(fn func(tx *bucketTx) error) error {
+	return pdw.runTransaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, false, fn)
+}
+
+func (pdw *PostgresDB) runTransaction(options *sql.TxOptions, lock bool, fn func(tx *bucketTx) error) error {
+	ctx := context.Background()
+
+	sqlTx, err := pdw.DB.BeginTx(ctx, options)
+	if err != nil {
+		return fmt.Errorf("metadb: failed to start a transaction %w", err)
+	}
+
+	if lock {
+		// the metadata is read and written back, the writes of all the instances are serialized
+		if _, err := sqlTx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", pdw.lockID); err != nil {
+			_ = sqlTx.Rollback()
+
+			return fmt.Errorf("metadb: failed to lock the tables %w", err)
+		}
+	}
+
+	transaction := &bucketTx{sqlTx: sqlTx, tablePrefix: pdw.TablePrefix}
+
+	err = fn(transaction)
+	if transaction.err != nil {
+		err = transaction.err
+	}
+
+	if err != nil {
+		_ = sqlTx.Rollback()
+
+		return err
+	}
+
+	return sqlTx.Commit()
+}
+
+func (tx *bucketTx) setErr(err error) {
+	if tx.err == nil {
+		tx.err = fmt.Errorf("metadb: query failed %w", err)
+	}
+}
+
+func (tx *bucketTx) Bucket(name string) *bucket {
+	return &bucket{tx: tx, table: pq.QuoteIdentifier(tx.tablePrefix + name)}
+}
+
+func (tx *bucketTx) CreateBucketIfNotExists(name string) error {
+	_, err := tx.sqlTx.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (key TEXT COLLATE "C" PRIMARY KEY, `+
+		`value BYTEA NOT NULL)`, pq.QuoteIdentifier(tx.tablePrefix+name)))
+
+	return err
+}
+
+// Get returns the value of the key, nil if it's not found.
+func (buck *bucket) Get(key []byte) []byte {
+	var value []byte
+
+	err := buck.tx.sqlTx.QueryRow("SELECT value FROM "+buck.table+" WHERE key = $1", string(key)).Scan(&value)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			buck.tx.setErr(err)
+		}
+
+		return nil
+	}
+
+	return value
+}
+
+func (buck *bucket) Put(key []byte, value []byte) error {
+	_, err := buck.tx.sqlTx.Exec("INSERT INTO "+buck.table+" (key, value) VALUES ($1, $2) "+
+		"ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value", string(key), value)
+
+	return err
+}
+
+func (buck *bucket) Delete(key []byte) error {
+	_, err := buck.tx.sqlTx.Exec("DELETE FROM "+buck.table+" WHERE key = $1", string(key))
+
+	return err
+}
+
+// Clear deletes all the keys of the bucket.
+func (buck *bucket) Clear() error {
+	_, err := buck.tx.sqlTx.Exec("DELETE FROM " + buck.table)
+
+	return err
+}
+
+func (buck *bucket) ForEach(fn func(key, value []byte) error) error {
+	cursor := buck.Cursor()
+
+	for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Cursor iterates the keys in byte order.
+func (buck *bucket) Cursor() *cursor {
+	return &cursor{bucket: buck}
+}
+
+func (cur *cursor) First() ([]byte, []byte) {
+	return cur.Seek([]byte{})
+}
+
+// Seek moves the cursor to the first key which isn't before the given one.
+func (cur *cursor) Seek(seek []byte) ([]byte, []byte) {
+	cur.load(">=", seek)
+
+	return cur.current()
+}
+
+func (cur *cursor) Next() ([]byte, []byte) {
+	cur.index++
+
+	if cur.index >= len(cur.rows) && !cur.exhausted {
+		cur.load(">", cur.rows[len(cur.rows)-1].key)
+	}
+
+	return cur.current()
+}
+
+func (cur *cursor) current() ([]byte, []byte) {
+	if cur.index >= len(cur.rows) {
+		return nil, nil
+	}
+
+	return cur.rows[cur.index].key, cur.rows[cur.index].value
+}
+
+// load reads the next page of rows after the key.
+func (cur *cursor) load(operator string, key []byte) {
+	cur.rows = []keyValue{}
+	cur.index = 0
+	cur.exhausted = true
+
+	query := fmt.Sprintf("SELECT key, value FROM %s WHERE key %s $1 ORDER BY key LIMIT %d", cur.bucket.table, operator,
+		cursorPageSize)
+
+	rows, err := cur.bucket.tx.sqlTx.Query(query, string(key))
+	if err != nil {
+		cur.bucket.tx.setErr(err)
+
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rowKey string
+			value  []byte
+		)
+
+		if err := rows.Scan(&rowKey, &value); err != nil {
+			cur.bucket.tx.setErr(err)
+
+			return
+		}
+
+		cur.rows = append(cur.rows, keyValue{key: []byte(rowKey), value: value})
+	}
+
+	if err := rows.Err(); err != nil {
+		cur.bucket.tx.setErr(err)
+
+		return
+	}
+
+	cur.exhausted = len(cur.rows) < cursorPageSize
+}
@@ -0,0 +1,370 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+// DefaultReconcileInterval is how often the metadata is reconciled with the storage if the config doesn't tell.
+const DefaultReconcileInterval = 24 * time.Hour
+
+// the kinds of drift of the metadata of a repo from its contents in the storage.
+const (
+	// the repo is in the storage but not in the metaDB
+	DriftMissingRepo = "missing_repo"
+	// the repo is in the metaDB but not in the storage anymore
+	DriftStaleRepo = "stale_repo"
+	// manifests or indexes of the storage aren't in the metaDB
+	DriftManifests = "manifests"
+	// the manifests or indexes of the metaDB don't have the size of the ones in the storage
+	DriftSizes = "sizes"
+	// the tags of the metaDB aren't the ones of the storage
+	DriftTags = "tags"
+	// the referrers of the metaDB aren't the ones of the storage
+	DriftReferrers = "referrers"
+	// the signatures of the metaDB aren't the ones of the storage
+	DriftSignatures = "signatures"
+)
+
+// CheckRepoDrift compares the metadata of the repo with its contents in the storage, it returns the kinds of
+// drift found, none if they match.
+func CheckRepoDrift(repo string, metaDB mTypes.MetaDB, storeController storage.StoreController, log log.Logger,
+) ([]string, error) {
+	imageStore := storeController.GetImageStore(repo)
+
+	var lockLatency time.Time
+
+	imageStore.RLockRepo(repo, &lockLatency)
+	defer imageStore.RUnlockRepo(repo, &lockLatency)
+
+	repoMeta, err := metaDB.GetRepoMeta(repo)
+	if err != nil && !errors.Is(err, zerr.ErrRepoMetaNotFound) {
+		return nil, err
+	}
+
+	repoMetaFound := err == nil
+
+	indexBlob, err := imageStore.GetIndexContent(repo)
+	if err != nil {
+		if errors.Is(err, zerr.ErrRepoNotFound) && repoMetaFound {
+			return []string{DriftStaleRepo}, nil
+		}
+
+		if errors.Is(err, zerr.ErrRepoNotFound) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	var index ispec.Index
+
+	if err := json.Unmarshal(indexBlob, &index); err != nil {
+		return nil, err
+	}
+
+	if !repoMetaFound {
+		// the repos without images don't have metadata
+		if len(index.Manifests) > 0 {
+			return []string{DriftMissingRepo}, nil
+		}
+
+		return []string{}, nil
+	}
+
+	drift := map[string]bool{}
+	tags := map[string]bool{}
+	referrers := map[string]bool{}
+	signatures := map[string]bool{}
+
+	for _, descriptor := range index.Manifests {
+		tag := descriptor.Annotations[ispec.AnnotationRefName]
+
+		descriptorBlob, kind, err := getDriftedBlob(repo, descriptor, metaDB, imageStore)
+		if err != nil {
+			log.Error().Err(err).Str("repository", repo).Str("digest", descriptor.Digest.String()).
+				Msg("reconcile: failed to get blob for image")
+
+			return nil, err
+		}
+
+		isSignature, signatureType, signedManifestDigest, err := storage.CheckIsImageSignature(repo,
+			descriptorBlob, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		// the manifests of the signatures aren't stored in the metaDB
+		if kind != "" && !isSignature {
+			drift[kind] = true
+		}
+
+		if isSignature {
+			signatures[descriptor.Digest.String()] = true
+
+			if !hasSignature(repoMeta, signedManifestDigest, signatureType, descriptor.Digest) {
+				drift[DriftSignatures] = true
+			}
+
+			continue
+		}
+
+		if tag != "" {
+			tags[tag] = true
+
+			if repoMeta.Tags[tag].Digest != descriptor.Digest.String() {
+				drift[DriftTags] = true
+			}
+		}
+
+		referredDigest, _, hasSubject, err := GetReferredInfo(descriptorBlob, descriptor.Digest.String(),
+			descriptor.MediaType)
+		if hasSubject && err == nil {
+			referrers[descriptor.Digest.String()] = true
+
+			if !hasReferrer(repoMeta, referredDigest, descriptor.Digest) {
+				drift[DriftReferrers] = true
+			}
+		}
+	}
+
+	for tag := range repoMeta.Tags {
+		if !tags[tag] {
+			drift[DriftTags] = true
+		}
+	}
+
+	for _, subjectReferrers := range repoMeta.Referrers {
+		for _, referrer := range subjectReferrers {
+			if !referrers[referrer.Digest] {
+				drift[DriftReferrers] = true
+			}
+		}
+	}
+
+	for _, manifestSignatures := range repoMeta.Signatures {
+		for _, signatureInfos := range manifestSignatures {
+			for _, signatureInfo := range signatureInfos {
+				if !signatures[signatureInfo.SignatureManifestDigest] {
+					drift[DriftSignatures] = true
+				}
+			}
+		}
+	}
+
+	kinds := make([]string, 0, len(drift))
+
+	for kind := range drift {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	return kinds, nil
+}
+
+// getDriftedBlob returns the blob of the descriptor, from the metaDB if it has it with the size of the storage,
+// and the kind of drift of the blob of the metaDB if it doesn't.
+func getDriftedBlob(repo string, descriptor ispec.Descriptor, metaDB mTypes.MetaDB,
+	imageStore storageTypes.ImageStore,
+) ([]byte, string, error) {
+	kind := ""
+
+	switch descriptor.MediaType {
+	case ispec.MediaTypeImageManifest, ispec.MediaTypeImageIndex:
+		descriptorBlob, err := getCachedBlobFromMetaDB(descriptor, metaDB)
+
+		switch {
+		case err != nil && !errors.Is(err, zerr.ErrManifestDataNotFound) &&
+			!errors.Is(err, zerr.ErrIndexDataNotFount):
+			return nil, "", err
+		case err != nil || len(descriptorBlob) == 0:
+			kind = DriftManifests
+		case int64(len(descriptorBlob)) != descriptor.Size:
+			kind = DriftSizes
+		default:
+			return descriptorBlob, "", nil
+		}
+	}
+
+	descriptorBlob, _, _, err := imageStore.GetImageManifest(repo, descriptor.Digest.String())
+
+	return descriptorBlob, kind, err
+}
+
+func hasSignature(repoMeta mTypes.RepoMetadata, signedManifestDigest godigest.Digest, signatureType string,
+	signatureDigest godigest.Digest,
+) bool {
+	for _, signatureInfo := range repoMeta.Signatures[signedManifestDigest.String()][signatureType] {
+		if signatureInfo.SignatureManifestDigest == signatureDigest.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasReferrer(repoMeta mTypes.RepoMetadata, referredDigest, referrerDigest godigest.Digest) bool {
+	for _, referrer := range repoMeta.Referrers[referredDigest.String()] {
+		if referrer.Digest == referrerDigest.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReconcileRepo repairs the drift of the metadata of the repo from the storage, unless it's a dry run, and
+// returns the kinds of drift found. The stale repos are removed from the metaDB, the others are parsed again
+// from the storage, keeping their statistics, stars and readme.
+func ReconcileRepo(repo string, metaDB mTypes.MetaDB, storeController storage.StoreController, dryRun bool,
+	log log.Logger,
+) ([]string, error) {
+	kinds, err := CheckRepoDrift(repo, metaDB, storeController, log)
+	if err != nil || len(kinds) == 0 {
+		return kinds, err
+	}
+
+	log.Warn().Str("repository", repo).Strs("drift", kinds).Bool("dryRun", dryRun).
+		Msg("reconcile: the metadata of the repo drifted from the storage")
+
+	if dryRun {
+		return kinds, nil
+	}
+
+	if kinds[0] == DriftStaleRepo {
+		return kinds, metaDB.DeleteRepoMeta(repo)
+	}
+
+	return kinds, ParseRepo(repo, metaDB, storeController, log)
+}
+
+// NewReconcileTaskGenerator returns a generator of a task reconciling each repo of the storage and of the
+// metaDB, the drift found is counted by the zot_metadb_drift_total metric.
+func NewReconcileTaskGenerator(metaDB mTypes.MetaDB, storeController storage.StoreController, dryRun bool,
+	metrics monitoring.MetricServer, log log.Logger,
+) scheduler.TaskGenerator {
+	return &reconcileTaskGenerator{
+		metaDB:          metaDB,
+		storeController: storeController,
+		dryRun:          dryRun,
+		metrics:         metrics,
+		log:             log,
+	}
+}
+
+type reconcileTaskGenerator struct {
+	metaDB          mTypes.MetaDB
+	storeController storage.StoreController
+	dryRun          bool
+	metrics         monitoring.MetricServer
+	log             log.Logger
+	// repos are the repos left to reconcile, listed when the generator starts
+	repos   []string
+	started bool
+	done    bool
+}
+
+func (gen *reconcileTaskGenerator) Next() (scheduler.Task, error) {
+	if !gen.started {
+		repos, err := gen.listRepos()
+		if err != nil {
+			gen.done = true
+
+			return nil, err
+		}
+
+		gen.repos = repos
+		gen.started = true
+	}
+
+	if len(gen.repos) == 0 {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	repo := gen.repos[0]
+	gen.repos = gen.repos[1:]
+
+	return &reconcileTask{generator: gen, repo: repo}, nil
+}
+
+// listRepos returns the repos of the storage and of the metaDB.
+func (gen *reconcileTaskGenerator) listRepos() ([]string, error) {
+	storageRepos, err := getAllRepos(gen.storeController)
+	if err != nil {
+		return nil, err
+	}
+
+	metaDBRepos, err := gen.metaDB.GetMultipleRepoMeta(context.Background(),
+		func(repoMeta mTypes.RepoMetadata) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	repoSet := map[string]bool{}
+
+	for _, repo := range storageRepos {
+		repoSet[repo] = true
+	}
+
+	for _, repoMeta := range metaDBRepos {
+		repoSet[repoMeta.Name] = true
+	}
+
+	repos := make([]string, 0, len(repoSet))
+
+	for repo := range repoSet {
+		repos = append(repos, repo)
+	}
+
+	sort.Strings(repos)
+
+	return repos, nil
+}
+
+func (gen *reconcileTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *reconcileTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *reconcileTaskGenerator) Reset() {
+	gen.repos = nil
+	gen.started = false
+	gen.done = false
+}
+
+type reconcileTask struct {
+	generator *reconcileTaskGenerator
+	repo      string
+}
+
+func (task *reconcileTask) DoWork(ctx context.Context) error {
+	gen := task.generator
+
+	kinds, err := ReconcileRepo(task.repo, gen.metaDB, gen.storeController, gen.dryRun, gen.log)
+
+	for _, kind := range kinds {
+		monitoring.IncMetaDBDrift(gen.metrics, kind)
+	}
+
+	return err
+}
@@ -0,0 +1,198 @@
+package meta_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta"
+	"zotregistry.io/zot/pkg/meta/boltdb"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/storage"
+	"zotregistry.io/zot/pkg/storage/local"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+	"zotregistry.io/zot/pkg/test/mocks"
+)
+
+func TestReconcileRepo(t *testing.T) {
+	Convey("Reconcile the metaDB with the storage", t, func() {
+		rootDir := t.TempDir()
+		logger := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, logger)
+
+		boltDB, err := boltdb.GetBoltDriver(boltdb.DBParameters{RootDir: rootDir})
+		So(err, ShouldBeNil)
+
+		metaDB, err := boltdb.New(boltDB, logger)
+		So(err, ShouldBeNil)
+
+		imageStore := local.NewImageStore(rootDir, false, false, 0, 0, false, false, false, logger, metrics, nil, nil)
+		storeController := storage.StoreController{DefaultStore: imageStore}
+
+		image := CreateRandomImage()
+		err = test.WriteImageToFileSystem(image, repo, "tag1", storeController)
+		So(err, ShouldBeNil)
+
+		err = test.WriteImageToFileSystem(CreateRandomImage(), repo, "tag2", storeController)
+		So(err, ShouldBeNil)
+
+		signatureTag, err := test.GetCosignSignatureTagForManifest(image.Manifest)
+		So(err, ShouldBeNil)
+
+		err = test.WriteImageToFileSystem(CreateRandomImage(), repo, signatureTag, storeController)
+		So(err, ShouldBeNil)
+
+		err = meta.ParseStorage(metaDB, storeController, logger)
+		So(err, ShouldBeNil)
+
+		err = metaDB.IncrementRepoStars(repo)
+		So(err, ShouldBeNil)
+
+		kinds, err := meta.CheckRepoDrift(repo, metaDB, storeController, logger)
+		So(err, ShouldBeNil)
+		So(kinds, ShouldBeEmpty)
+
+		Convey("The changes of the storage are applied to the metaDB", func() {
+			err = imageStore.DeleteImageManifest(repo, "tag2", false)
+			So(err, ShouldBeNil)
+
+			err = test.WriteImageToFileSystem(CreateRandomImage(), repo, "tag3", storeController)
+			So(err, ShouldBeNil)
+
+			referrer := CreateImageWith().RandomLayers(1, 10).DefaultConfig().
+				ArtifactType("application/test").Subject(image.DescriptorRef()).Build()
+			err = test.WriteImageToFileSystem(referrer, repo, referrer.DigestStr(), storeController)
+			So(err, ShouldBeNil)
+
+			kinds, err := meta.ReconcileRepo(repo, metaDB, storeController, true, logger)
+			So(err, ShouldBeNil)
+			So(kinds, ShouldResemble, []string{meta.DriftManifests, meta.DriftReferrers, meta.DriftTags})
+
+			// nothing is repaired by a dry run
+			kinds, err = meta.CheckRepoDrift(repo, metaDB, storeController, logger)
+			So(err, ShouldBeNil)
+			So(kinds, ShouldNotBeEmpty)
+
+			_, err = meta.ReconcileRepo(repo, metaDB, storeController, false, logger)
+			So(err, ShouldBeNil)
+
+			kinds, err = meta.CheckRepoDrift(repo, metaDB, storeController, logger)
+			So(err, ShouldBeNil)
+			So(kinds, ShouldBeEmpty)
+
+			repoMeta, err := metaDB.GetRepoMeta(repo)
+			So(err, ShouldBeNil)
+			So(repoMeta.Tags, ShouldContainKey, "tag1")
+			So(repoMeta.Tags, ShouldContainKey, "tag3")
+			So(repoMeta.Tags, ShouldNotContainKey, "tag2")
+			So(repoMeta.Referrers[image.DigestStr()], ShouldHaveLength, 1)
+			So(repoMeta.Stars, ShouldEqual, 1)
+		})
+
+		Convey("The changes of the metaDB are reverted", func() {
+			err = metaDB.SetRepoReference(repo, "tag4", image.Digest(), ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			repoMeta, err := metaDB.GetRepoMeta(repo)
+			So(err, ShouldBeNil)
+			So(repoMeta.Signatures, ShouldContainKey, image.DigestStr())
+
+			delete(repoMeta.Signatures, image.DigestStr())
+			err = metaDB.SetRepoMeta(repo, repoMeta)
+			So(err, ShouldBeNil)
+
+			manifestData, err := metaDB.GetManifestData(image.Digest())
+			So(err, ShouldBeNil)
+
+			manifestData.ManifestBlob = []byte("{}")
+			err = metaDB.SetManifestData(image.Digest(), manifestData)
+			So(err, ShouldBeNil)
+
+			kinds, err := meta.ReconcileRepo(repo, metaDB, storeController, false, logger)
+			So(err, ShouldBeNil)
+			So(kinds, ShouldResemble, []string{meta.DriftSignatures, meta.DriftSizes, meta.DriftTags})
+
+			kinds, err = meta.CheckRepoDrift(repo, metaDB, storeController, logger)
+			So(err, ShouldBeNil)
+			So(kinds, ShouldBeEmpty)
+		})
+
+		Convey("The repos missing from the metaDB or the storage are reconciled", func() {
+			err = test.WriteImageToFileSystem(CreateRandomImage(), "new", "tag", storeController)
+			So(err, ShouldBeNil)
+
+			err = metaDB.SetRepoReference("gone", "tag", image.Digest(), ispec.MediaTypeImageManifest)
+			So(err, ShouldBeNil)
+
+			kinds, err := meta.CheckRepoDrift("new", metaDB, storeController, logger)
+			So(err, ShouldBeNil)
+			So(kinds, ShouldResemble, []string{meta.DriftMissingRepo})
+
+			kinds, err = meta.CheckRepoDrift("gone", metaDB, storeController, logger)
+			So(err, ShouldBeNil)
+			So(kinds, ShouldResemble, []string{meta.DriftStaleRepo})
+
+			// the task generator goes through the repos of both the storage and the metaDB
+			generator := meta.NewReconcileTaskGenerator(metaDB, storeController, false, metrics, logger)
+
+			for !generator.IsDone() {
+				task, err := generator.Next()
+				So(err, ShouldBeNil)
+
+				if task != nil {
+					So(task.DoWork(context.Background()), ShouldBeNil)
+				}
+			}
+
+			_, err = metaDB.GetRepoMeta("new")
+			So(err, ShouldBeNil)
+
+			_, err = metaDB.GetRepoMeta("gone")
+			So(errors.Is(err, zerr.ErrRepoMetaNotFound), ShouldBeTrue)
+
+			for _, repo := range []string{repo, "new", "gone"} {
+				kinds, err := meta.CheckRepoDrift(repo, metaDB, storeController, logger)
+				So(err, ShouldBeNil)
+				So(kinds, ShouldBeEmpty)
+			}
+
+			// the repos are listed again on the next run
+			generator.Reset()
+			So(generator.IsDone(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Reconcile errors", t, func() {
+		logger := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, logger)
+		imageStore := mocks.MockedImageStore{}
+		storeController := storage.StoreController{DefaultStore: imageStore}
+
+		metaDB := mocks.MetaDBMock{
+			GetRepoMetaFn: func(repo string) (mTypes.RepoMetadata, error) {
+				return mTypes.RepoMetadata{}, ErrTestError
+			},
+		}
+
+		_, err := meta.ReconcileRepo(repo, metaDB, storeController, false, logger)
+		So(err, ShouldNotBeNil)
+
+		metaDB.GetMultipleRepoMetaFn = func(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+		) ([]mTypes.RepoMetadata, error) {
+			return nil, ErrTestError
+		}
+
+		generator := meta.NewReconcileTaskGenerator(metaDB, storeController, false, metrics, logger)
+
+		_, err = generator.Next()
+		So(err, ShouldNotBeNil)
+		So(generator.IsDone(), ShouldBeTrue)
+	})
+}
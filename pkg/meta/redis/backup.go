@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"zotregistry.io/zot/pkg/meta/common"
+	"zotregistry.io/zot/pkg/meta/version"
+)
+
+// Backup reads the hashes while holding the lock of the writes, so they are consistent, and writes them once the
+// lock is released, the lock isn't held while the backup is sent.
+func (rdw *RedisDB) Backup(ctx context.Context, writer io.Writer) error {
+	buckets := getBackupBuckets()
+	entries := []common.BackupEntry{}
+
+	var dbVersion string
+
+	err := rdw.update(func(tx *bucketTx) error {
+		dbVersion = string(tx.Bucket(VersionBucket).Get([]byte(version.DBVersionKey)))
+
+		for _, bucketName := range common.GetBackupBuckets() {
+			err := tx.Bucket(buckets[bucketName]).ForEach(func(key, value []byte) error {
+				entries = append(entries, common.BackupEntry{Bucket: bucketName, Key: string(key), Value: value})
+
+				return ctx.Err()
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	backupWriter, err := common.NewBackupWriter(writer, dbVersion)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := backupWriter.Write(entry.Bucket, []byte(entry.Key), entry.Value); err != nil {
+			return err
+		}
+	}
+
+	return backupWriter.Close()
+}
+
+// Restore replaces the hashes at once when the transaction is committed, they are left unchanged if the backup
+// can't be read.
+func (rdw *RedisDB) Restore(ctx context.Context, reader io.Reader) error {
+	backupReader, err := common.NewBackupReader(reader, common.GetBackupBuckets())
+	if err != nil {
+		return err
+	}
+
+	buckets := getBackupBuckets()
+
+	err = rdw.update(func(tx *bucketTx) error {
+		for _, bucketName := range common.GetBackupBuckets() {
+			if err := tx.Bucket(buckets[bucketName]).Clear(); err != nil {
+				return err
+			}
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			entry, err := backupReader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Bucket(buckets[entry.Bucket]).Put([]byte(entry.Key), entry.Value); err != nil {
+				return err
+			}
+		}
+
+		// the version of the header is the one the data is patched from
+		return tx.Bucket(VersionBucket).Put([]byte(version.DBVersionKey), []byte(backupReader.Header.DBVersion))
+	})
+	if err != nil {
+		return err
+	}
+
+	return rdw.PatchDB()
+}
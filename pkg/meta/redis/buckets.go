@@ -0,0 +1,38 @@
+package redis
+
+import "zotregistry.io/zot/pkg/meta/common"
+
+// MetadataDB, each bucket is a hash named after the key prefix and the bucket.
+const (
+	ManifestDataBucket  = "manifest_data"
+	IndexDataBucket     = "index_data"
+	RepoMetadataBucket  = "repo_metadata"
+	UserDataBucket      = "user_data"
+	VersionBucket       = "version"
+	UserAPIKeysBucket   = "user_api_keys"
+	LayerIndexBucket    = "layer_index"
+	DownloadStatsBucket = "download_stats"
+	UserActivityBucket  = "user_activity"
+)
+
+func getBuckets() []string {
+	return []string{
+		VersionBucket, ManifestDataBucket, IndexDataBucket, RepoMetadataBucket, UserDataBucket, UserAPIKeysBucket,
+		LayerIndexBucket, DownloadStatsBucket, UserActivityBucket,
+	}
+}
+
+// getBackupBuckets returns the buckets of the backups with the names of their hashes.
+func getBackupBuckets() map[string]string {
+	return map[string]string{
+		common.BackupVersionBucket:       VersionBucket,
+		common.BackupManifestDataBucket:  ManifestDataBucket,
+		common.BackupIndexDataBucket:     IndexDataBucket,
+		common.BackupRepoMetadataBucket:  RepoMetadataBucket,
+		common.BackupUserDataBucket:      UserDataBucket,
+		common.BackupUserAPIKeysBucket:   UserAPIKeysBucket,
+		common.BackupLayerIndexBucket:    LayerIndexBucket,
+		common.BackupDownloadStatsBucket: DownloadStatsBucket,
+		common.BackupUserActivityBucket:  UserActivityBucket,
+	}
+}
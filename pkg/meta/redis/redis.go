@@ -0,0 +1,2432 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/constants"
+	zcommon "zotregistry.io/zot/pkg/common"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta/common"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/meta/version"
+	reqCtx "zotregistry.io/zot/pkg/requestcontext"
+)
+
+// RedisDB stores the metadata in redis, which can be shared by several zot instances.
+type RedisDB struct {
+	Client        goredis.UniversalClient
+	KeyPrefix     string
+	Patches       []func(client goredis.UniversalClient, keyPrefix string) error
+	imgTrustStore mTypes.ImageTrustStore
+	Log           log.Logger
+}
+
+func New(client goredis.UniversalClient, params DBParameters, log log.Logger) (*RedisDB, error) {
+	keyPrefix := params.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = DefaultKeyPrefix
+	}
+
+	rdw := &RedisDB{
+		Client:        client,
+		KeyPrefix:     keyPrefix,
+		Patches:       version.GetRedisPatches(),
+		imgTrustStore: nil,
+		Log:           log,
+	}
+
+	err := rdw.update(func(transaction *bucketTx) error {
+		return transaction.Bucket(VersionBucket).Put([]byte(version.DBVersionKey), []byte(version.CurrentVersion))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rdw, nil
+}
+
+func (rdw *RedisDB) ImageTrustStore() mTypes.ImageTrustStore {
+	return rdw.imgTrustStore
+}
+
+func (rdw *RedisDB) SetImageTrustStore(imgTrustStore mTypes.ImageTrustStore) {
+	rdw.imgTrustStore = imgTrustStore
+}
+
+func (rdw *RedisDB) SetManifestData(manifestDigest godigest.Digest, manifestData mTypes.ManifestData) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(ManifestDataBucket)
+
+		mdBlob, err := json.Marshal(manifestData)
+		if err != nil {
+			return fmt.Errorf("metadb: error while calculating blob for manifest with digest %s %w", manifestDigest, err)
+		}
+
+		err = buck.Put([]byte(manifestDigest), mdBlob)
+		if err != nil {
+			return fmt.Errorf("metadb: error while setting manifest data with for digest %s %w", manifestDigest, err)
+		}
+
+		return indexManifestLayers(tx.Bucket(LayerIndexBucket), manifestDigest, manifestData.ManifestBlob)
+	})
+
+	return err
+}
+
+// indexManifestLayers adds the manifest to the layer index entries of its layers.
+func indexManifestLayers(layerIndexBuck *bucket, manifestDigest godigest.Digest, manifestBlob []byte) error {
+	for _, layerDigest := range common.GetManifestLayers(manifestBlob) {
+		manifestDigests := []string{}
+
+		if manifestDigestsBlob := layerIndexBuck.Get([]byte(layerDigest)); len(manifestDigestsBlob) > 0 {
+			err := json.Unmarshal(manifestDigestsBlob, &manifestDigests)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling layer index for digest %s %w", layerDigest, err)
+			}
+		}
+
+		if zcommon.Contains(manifestDigests, manifestDigest.String()) {
+			continue
+		}
+
+		manifestDigests = append(manifestDigests, manifestDigest.String())
+
+		manifestDigestsBlob, err := json.Marshal(manifestDigests)
+		if err != nil {
+			return err
+		}
+
+		err = layerIndexBuck.Put([]byte(layerDigest), manifestDigestsBlob)
+		if err != nil {
+			return fmt.Errorf("metadb: error while setting layer index for digest %s %w", layerDigest, err)
+		}
+	}
+
+	return nil
+}
+
+func (rdw *RedisDB) GetManifestsWithLayer(layerDigest godigest.Digest) ([]godigest.Digest, error) {
+	manifestDigests := []godigest.Digest{}
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(LayerIndexBucket)
+
+		manifestDigestsBlob := buck.Get([]byte(layerDigest))
+		if len(manifestDigestsBlob) == 0 {
+			return nil
+		}
+
+		err := json.Unmarshal(manifestDigestsBlob, &manifestDigests)
+		if err != nil {
+			return fmt.Errorf("metadb: error while unmashaling layer index for digest %s %w", layerDigest, err)
+		}
+
+		return nil
+	})
+
+	return manifestDigests, err
+}
+
+func (rdw *RedisDB) GetManifestData(manifestDigest godigest.Digest) (mTypes.ManifestData, error) {
+	var manifestData mTypes.ManifestData
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(ManifestDataBucket)
+
+		mdBlob := buck.Get([]byte(manifestDigest))
+
+		if len(mdBlob) == 0 {
+			return zerr.ErrManifestDataNotFound
+		}
+
+		err := json.Unmarshal(mdBlob, &manifestData)
+		if err != nil {
+			return fmt.Errorf("metadb: error while unmashaling manifest meta for digest %s %w", manifestDigest, err)
+		}
+
+		return nil
+	})
+
+	return manifestData, err
+}
+
+func (rdw *RedisDB) SetManifestMeta(repo string, manifestDigest godigest.Digest, manifestMeta mTypes.ManifestMetadata,
+) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		dataBuck := tx.Bucket(ManifestDataBucket)
+		repoBuck := tx.Bucket(RepoMetadataBucket)
+
+		repoMeta := mTypes.RepoMetadata{
+			Name:       repo,
+			Tags:       map[string]mTypes.Descriptor{},
+			Statistics: map[string]mTypes.DescriptorStatistics{},
+			Signatures: map[string]mTypes.ManifestSignatures{},
+			Referrers:  map[string][]mTypes.ReferrerInfo{},
+		}
+
+		repoMetaBlob := repoBuck.Get([]byte(repo))
+		if len(repoMetaBlob) > 0 {
+			err := json.Unmarshal(repoMetaBlob, &repoMeta)
+			if err != nil {
+				return err
+			}
+		}
+
+		mdBlob, err := json.Marshal(mTypes.ManifestData{
+			ManifestBlob: manifestMeta.ManifestBlob,
+			ConfigBlob:   manifestMeta.ConfigBlob,
+		})
+		if err != nil {
+			return fmt.Errorf("metadb: error while calculating blob for manifest with digest %s %w", manifestDigest, err)
+		}
+
+		err = dataBuck.Put([]byte(manifestDigest), mdBlob)
+		if err != nil {
+			return fmt.Errorf("metadb: error while setting manifest meta with for digest %s %w", manifestDigest, err)
+		}
+
+		updatedRepoMeta := common.UpdateManifestMeta(repoMeta, manifestDigest, manifestMeta)
+
+		updatedRepoMetaBlob, err := json.Marshal(updatedRepoMeta)
+		if err != nil {
+			return fmt.Errorf("metadb: error while calculating blob for updated repo meta '%s' %w", repo, err)
+		}
+
+		return repoBuck.Put([]byte(repo), updatedRepoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetManifestMeta(repo string, manifestDigest godigest.Digest) (mTypes.ManifestMetadata, error) {
+	var manifestMetadata mTypes.ManifestMetadata
+
+	err := rdw.view(func(tx *bucketTx) error {
+		dataBuck := tx.Bucket(ManifestDataBucket)
+		repoBuck := tx.Bucket(RepoMetadataBucket)
+
+		mdBlob := dataBuck.Get([]byte(manifestDigest))
+
+		if len(mdBlob) == 0 {
+			return zerr.ErrManifestMetaNotFound
+		}
+
+		var manifestData mTypes.ManifestData
+
+		err := json.Unmarshal(mdBlob, &manifestData)
+		if err != nil {
+			return fmt.Errorf("metadb: error while unmashaling manifest meta for digest %s %w", manifestDigest, err)
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		repoMetaBlob := repoBuck.Get([]byte(repo))
+		if len(repoMetaBlob) > 0 {
+			err = json.Unmarshal(repoMetaBlob, &repoMeta)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling manifest meta for digest %s %w", manifestDigest, err)
+			}
+		}
+
+		manifestMetadata.ManifestBlob = manifestData.ManifestBlob
+		manifestMetadata.ConfigBlob = manifestData.ConfigBlob
+		manifestMetadata.DownloadCount = repoMeta.Statistics[manifestDigest.String()].DownloadCount
+
+		manifestMetadata.Signatures = mTypes.ManifestSignatures{}
+		if repoMeta.Signatures[manifestDigest.String()] != nil {
+			manifestMetadata.Signatures = repoMeta.Signatures[manifestDigest.String()]
+		}
+
+		return nil
+	})
+
+	return manifestMetadata, err
+}
+
+func (rdw *RedisDB) SetIndexData(indexDigest godigest.Digest, indexMetadata mTypes.IndexData) error {
+	// we make the assumption that the oci layout is consistent and all manifests refferenced inside the
+	// index are present
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(IndexDataBucket)
+
+		imBlob, err := json.Marshal(indexMetadata)
+		if err != nil {
+			return fmt.Errorf("metadb: error while calculating blob for manifest with digest %s %w", indexDigest, err)
+		}
+
+		err = buck.Put([]byte(indexDigest), imBlob)
+		if err != nil {
+			return fmt.Errorf("metadb: error while setting manifest meta with for digest %s %w", indexDigest, err)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetIndexData(indexDigest godigest.Digest) (mTypes.IndexData, error) {
+	var indexMetadata mTypes.IndexData
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(IndexDataBucket)
+
+		mmBlob := buck.Get([]byte(indexDigest))
+
+		if len(mmBlob) == 0 {
+			return zerr.ErrManifestMetaNotFound
+		}
+
+		err := json.Unmarshal(mmBlob, &indexMetadata)
+		if err != nil {
+			return fmt.Errorf("metadb: error while unmashaling manifest meta for digest %s %w", indexDigest, err)
+		}
+
+		return nil
+	})
+
+	return indexMetadata, err
+}
+
+func (rdw RedisDB) SetReferrer(repo string, referredDigest godigest.Digest, referrer mTypes.ReferrerInfo) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+
+		// object not found
+		if len(repoMetaBlob) == 0 {
+			var err error
+
+			// create a new object
+			repoMeta := mTypes.RepoMetadata{
+				Name:       repo,
+				Tags:       map[string]mTypes.Descriptor{},
+				Statistics: map[string]mTypes.DescriptorStatistics{},
+				Signatures: map[string]mTypes.ManifestSignatures{},
+				Referrers: map[string][]mTypes.ReferrerInfo{
+					referredDigest.String(): {
+						referrer,
+					},
+				},
+			}
+
+			repoMetaBlob, err = json.Marshal(repoMeta)
+			if err != nil {
+				return err
+			}
+
+			return buck.Put([]byte(repo), repoMetaBlob)
+		}
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		referrers := repoMeta.Referrers[referredDigest.String()]
+
+		for i := range referrers {
+			if referrers[i].Digest == referrer.Digest {
+				return nil
+			}
+		}
+
+		referrers = append(referrers, referrer)
+
+		repoMeta.Referrers[referredDigest.String()] = referrers
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw RedisDB) DeleteReferrer(repo string, referredDigest godigest.Digest,
+	referrerDigest godigest.Digest,
+) error {
+	return rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+
+		if len(repoMetaBlob) == 0 {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		referrers := repoMeta.Referrers[referredDigest.String()]
+
+		for i := range referrers {
+			if referrers[i].Digest == referrerDigest.String() {
+				referrers = append(referrers[:i], referrers[i+1:]...)
+
+				break
+			}
+		}
+
+		repoMeta.Referrers[referredDigest.String()] = referrers
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+}
+
+func (rdw RedisDB) GetReferrersInfo(repo string, referredDigest godigest.Digest, artifactTypes []string,
+) ([]mTypes.ReferrerInfo, error) {
+	referrersInfoResult := []mTypes.ReferrerInfo{}
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+		if len(repoMetaBlob) == 0 {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		referrersInfo := repoMeta.Referrers[referredDigest.String()]
+
+		for i := range referrersInfo {
+			if !common.MatchesArtifactTypes(referrersInfo[i].ArtifactType, artifactTypes) {
+				continue
+			}
+
+			referrersInfoResult = append(referrersInfoResult, referrersInfo[i])
+		}
+
+		return nil
+	})
+
+	return referrersInfoResult, err
+}
+
+func (rdw *RedisDB) SetRepoReference(repo string, reference string, manifestDigest godigest.Digest,
+	mediaType string,
+) error {
+	if err := common.ValidateRepoReferenceInput(repo, reference, manifestDigest); err != nil {
+		return err
+	}
+
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+
+		repoMeta := mTypes.RepoMetadata{
+			Name:       repo,
+			Tags:       map[string]mTypes.Descriptor{},
+			Statistics: map[string]mTypes.DescriptorStatistics{},
+			Signatures: map[string]mTypes.ManifestSignatures{},
+			Referrers:  map[string][]mTypes.ReferrerInfo{},
+		}
+
+		// object not found
+		if len(repoMetaBlob) > 0 {
+			err := json.Unmarshal(repoMetaBlob, &repoMeta)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !common.ReferenceIsDigest(reference) {
+			repoMeta.Tags[reference] = mTypes.Descriptor{
+				Digest:    manifestDigest.String(),
+				MediaType: mediaType,
+			}
+		}
+
+		if _, ok := repoMeta.Statistics[manifestDigest.String()]; !ok {
+			repoMeta.Statistics[manifestDigest.String()] = mTypes.DescriptorStatistics{DownloadCount: 0}
+		}
+
+		if _, ok := repoMeta.Signatures[manifestDigest.String()]; !ok {
+			repoMeta.Signatures[manifestDigest.String()] = mTypes.ManifestSignatures{}
+		}
+
+		if _, ok := repoMeta.Referrers[manifestDigest.String()]; !ok {
+			repoMeta.Referrers[manifestDigest.String()] = []mTypes.ReferrerInfo{}
+		}
+
+		repoMetaBlob, err := json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetRepoMeta(repo string) (mTypes.RepoMetadata, error) {
+	var repoMeta mTypes.RepoMetadata
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+
+		// object not found
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		// object found
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return repoMeta, err
+}
+
+func (rdw *RedisDB) GetUserRepoMeta(ctx context.Context, repo string) (mTypes.RepoMetadata, error) {
+	var repoMeta mTypes.RepoMetadata
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+		userBookmarks := getUserBookmarks(ctx, tx)
+		userStars := getUserStars(ctx, tx)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+
+		// object not found
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		// object found
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		repoMeta.IsBookmarked = zcommon.Contains(userBookmarks, repo)
+		repoMeta.IsStarred = zcommon.Contains(userStars, repo)
+
+		return nil
+	})
+
+	return repoMeta, err
+}
+
+func (rdw *RedisDB) SetRepoMeta(repo string, repoMeta mTypes.RepoMetadata) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMeta.Name = repo
+
+		repoMetaBlob, err := json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) DeleteRepoTag(repo string, tag string) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+
+		// object not found
+		if repoMetaBlob == nil {
+			return nil
+		}
+
+		// object found
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		delete(repoMeta.Tags, tag)
+		delete(repoMeta.TagStatistics, tag)
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) DeleteRepoMeta(repo string) error {
+	return rdw.update(func(tx *bucketTx) error {
+		repoBuck := tx.Bucket(RepoMetadataBucket)
+
+		if err := repoBuck.Delete([]byte(repo)); err != nil {
+			return err
+		}
+
+		userBuck := tx.Bucket(UserDataBucket)
+		changedUserData := map[string]mTypes.UserData{}
+
+		err := userBuck.ForEach(func(userid, userDataBlob []byte) error {
+			var userData mTypes.UserData
+
+			if err := json.Unmarshal(userDataBlob, &userData); err != nil {
+				return err
+			}
+
+			if common.RemoveRepoFromUserData(&userData, repo) {
+				changedUserData[string(userid)] = userData
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// the bucket can't be modified while iterating it
+		for userid, userData := range changedUserData {
+			if err := rdw.setUserData(userid, tx, userData); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (rdw *RedisDB) IncrementRepoStars(repo string) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		repoMeta.Stars++
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) DecrementRepoStars(repo string) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		if repoMeta.Stars > 0 {
+			repoMeta.Stars--
+		}
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetRepoStars(repo string) (int, error) {
+	stars := 0
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		buck.Get([]byte(repo))
+		repoMetaBlob := buck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		stars = repoMeta.Stars
+
+		return nil
+	})
+
+	return stars, err
+}
+
+func (rdw *RedisDB) SetRepoReadme(repo string, readme *mTypes.RepoReadme) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		repoMeta.Readme = readme
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetMultipleRepoMeta(ctx context.Context, filter func(repoMeta mTypes.RepoMetadata) bool,
+) ([]mTypes.RepoMetadata, error) {
+	foundRepos := []mTypes.RepoMetadata{}
+
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		cursor := buck.Cursor()
+
+		for repoName, repoMetaBlob := cursor.First(); repoName != nil; repoName, repoMetaBlob = cursor.Next() {
+			if ok, err := reqCtx.RepoIsUserAvailable(ctx, string(repoName)); !ok || err != nil {
+				continue
+			}
+
+			repoMeta := mTypes.RepoMetadata{}
+
+			err := json.Unmarshal(repoMetaBlob, &repoMeta)
+			if err != nil {
+				return err
+			}
+
+			if filter(repoMeta) {
+				foundRepos = append(foundRepos, repoMeta)
+			}
+		}
+
+		return nil
+	})
+
+	return foundRepos, err
+}
+
+func (rdw *RedisDB) IncrementImageDownloads(repo string, reference string) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrManifestMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		if err := common.AddImageDownload(&repoMeta, reference, time.Now()); err != nil {
+			return err
+		}
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+// RecordDownload adds the pull to the statistics of its day and repo.
+func (rdw *RedisDB) RecordDownload(record mTypes.DownloadRecord) error {
+	day := record.Timestamp.UTC().Format(mTypes.DownloadStatsDayFormat)
+	key := []byte(day + "/" + record.Repo)
+
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(DownloadStatsBucket)
+
+		stats := mTypes.DailyDownloads{Repo: record.Repo, Day: day}
+
+		if statsBlob := buck.Get(key); len(statsBlob) > 0 {
+			err := json.Unmarshal(statsBlob, &stats)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling download stats %s %w", key, err)
+			}
+		}
+
+		common.AddDownload(&stats, record)
+
+		statsBlob, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put(key, statsBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetDownloadStats(ctx context.Context, repo string, from, to time.Time,
+) ([]mTypes.DailyDownloads, error) {
+	fromDay := from.UTC().Format(mTypes.DownloadStatsDayFormat)
+	toDay := to.UTC().Format(mTypes.DownloadStatsDayFormat)
+	foundStats := []mTypes.DailyDownloads{}
+
+	err := rdw.view(func(tx *bucketTx) error {
+		cursor := tx.Bucket(DownloadStatsBucket).Cursor()
+
+		for key, statsBlob := cursor.Seek([]byte(fromDay)); key != nil; key, statsBlob = cursor.Next() {
+			day, statsRepo, _ := strings.Cut(string(key), "/")
+			if day > toDay {
+				break
+			}
+
+			if repo != "" && statsRepo != repo {
+				continue
+			}
+
+			if ok, err := reqCtx.RepoIsUserAvailable(ctx, statsRepo); !ok || err != nil {
+				continue
+			}
+
+			var stats mTypes.DailyDownloads
+
+			err := json.Unmarshal(statsBlob, &stats)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling download stats %s %w", key, err)
+			}
+
+			foundStats = append(foundStats, stats)
+		}
+
+		return nil
+	})
+
+	return foundStats, err
+}
+
+func (rdw *RedisDB) DeleteDownloadStats(before time.Time) error {
+	beforeDay := before.UTC().Format(mTypes.DownloadStatsDayFormat)
+
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(DownloadStatsBucket)
+
+		// the keys are collected first, deleting while iterating skips keys
+		oldKeys := [][]byte{}
+
+		cursor := buck.Cursor()
+
+		for key, _ := cursor.First(); key != nil && string(key) < beforeDay; key, _ = cursor.Next() {
+			oldKeys = append(oldKeys, key)
+		}
+
+		for _, key := range oldKeys {
+			if err := buck.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) RollupDownloadStats(before time.Time) error {
+	beforeDay := before.UTC().Format(mTypes.DownloadStatsDayFormat)
+
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(DownloadStatsBucket)
+
+		// the stats are collected first, writing while iterating skips keys
+		rolledUpStats := map[string][]byte{}
+
+		cursor := buck.Cursor()
+
+		for key, statsBlob := cursor.First(); key != nil && string(key) < beforeDay; key, statsBlob = cursor.Next() {
+			var stats mTypes.DailyDownloads
+
+			if err := json.Unmarshal(statsBlob, &stats); err != nil {
+				return fmt.Errorf("metadb: error while unmashaling download stats %s %w", key, err)
+			}
+
+			if stats.Hours == nil {
+				continue
+			}
+
+			stats.Hours = nil
+
+			statsBlob, err := json.Marshal(stats)
+			if err != nil {
+				return err
+			}
+
+			rolledUpStats[string(key)] = statsBlob
+		}
+
+		for key, statsBlob := range rolledUpStats {
+			if err := buck.Put([]byte(key), statsBlob); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// RecordUserActivity adds the action to the activity history of its user.
+func (rdw *RedisDB) RecordUserActivity(activity mTypes.UserActivity) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(UserActivityBucket)
+
+		key := common.GetUserActivityKey(activity.User, activity.Timestamp)
+
+		for buck.Get([]byte(key)) != nil {
+			activity.Timestamp = activity.Timestamp.Add(time.Nanosecond)
+			key = common.GetUserActivityKey(activity.User, activity.Timestamp)
+		}
+
+		activityBlob, err := json.Marshal(activity)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(key), activityBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetUserActivity(ctx context.Context, user string, from, to time.Time,
+) ([]mTypes.UserActivity, error) {
+	prefix := common.GetUserActivityPrefix(user)
+	fromKey := common.GetUserActivityKey(user, from)
+	toKey := common.GetUserActivityKey(user, to)
+	foundActivity := []mTypes.UserActivity{}
+
+	err := rdw.view(func(tx *bucketTx) error {
+		cursor := tx.Bucket(UserActivityBucket).Cursor()
+
+		for key, activityBlob := cursor.Seek([]byte(fromKey)); key != nil; key, activityBlob = cursor.Next() {
+			if !strings.HasPrefix(string(key), prefix) || string(key) > toKey {
+				break
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var activity mTypes.UserActivity
+
+			err := json.Unmarshal(activityBlob, &activity)
+			if err != nil {
+				return fmt.Errorf("metadb: error while unmashaling user activity %s %w", key, err)
+			}
+
+			foundActivity = append(foundActivity, activity)
+		}
+
+		return nil
+	})
+
+	return foundActivity, err
+}
+
+func (rdw *RedisDB) DeleteUserActivity(before time.Time) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(UserActivityBucket)
+
+		// the keys are collected first, deleting while iterating skips keys
+		oldKeys := [][]byte{}
+
+		err := buck.ForEach(func(key, _ []byte) error {
+			if common.IsUserActivityKeyBefore(string(key), before) {
+				oldKeys = append(oldKeys, key)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range oldKeys {
+			if err := buck.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) UpdateSignaturesValidity(repo string, manifestDigest godigest.Digest) error {
+	err := rdw.update(func(transaction *bucketTx) error {
+		imgTrustStore := rdw.ImageTrustStore()
+
+		if imgTrustStore == nil {
+			return nil
+		}
+
+		// get ManifestData of signed manifest
+		manifestBuck := transaction.Bucket(ManifestDataBucket)
+		mdBlob := manifestBuck.Get([]byte(manifestDigest))
+
+		var blob []byte
+
+		if len(mdBlob) != 0 {
+			var manifestData mTypes.ManifestData
+
+			err := json.Unmarshal(mdBlob, &manifestData)
+			if err != nil {
+				return fmt.Errorf("metadb: %w error while unmashaling manifest meta for digest %s", err, manifestDigest)
+			}
+
+			blob = manifestData.ManifestBlob
+		} else {
+			var indexData mTypes.IndexData
+
+			indexBuck := transaction.Bucket(IndexDataBucket)
+			idBlob := indexBuck.Get([]byte(manifestDigest))
+
+			if len(idBlob) == 0 {
+				// manifest meta not found, updating signatures with details about validity and author will not be performed
+				return nil
+			}
+
+			err := json.Unmarshal(idBlob, &indexData)
+			if err != nil {
+				return fmt.Errorf("metadb: %w error while unmashaling index meta for digest %s", err, manifestDigest)
+			}
+
+			blob = indexData.IndexBlob
+		}
+
+		// update signatures with details about validity and author
+		repoBuck := transaction.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := repoBuck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		manifestSignatures := mTypes.ManifestSignatures{}
+		for sigType, sigs := range repoMeta.Signatures[manifestDigest.String()] {
+			signaturesInfo := []mTypes.SignatureInfo{}
+
+			for _, sigInfo := range sigs {
+				layersInfo := []mTypes.LayerInfo{}
+
+				for _, layerInfo := range sigInfo.LayersInfo {
+					var (
+						author    string
+						date      time.Time
+						isTrusted bool
+					)
+
+					if sigType == zcommon.CosignSignature && layerInfo.Certificate != "" {
+						author, isTrusted, _ = imgTrustStore.VerifyCosignKeylessSignature(layerInfo, manifestDigest)
+					} else {
+						author, date, isTrusted, _ = imgTrustStore.VerifySignature(sigType, layerInfo.LayerContent,
+							layerInfo.SignatureKey, manifestDigest, blob, repo)
+					}
+
+					if isTrusted {
+						layerInfo.Signer = author
+					}
+
+					if !date.IsZero() {
+						layerInfo.Signer = author
+						layerInfo.Date = date
+					}
+
+					layersInfo = append(layersInfo, layerInfo)
+				}
+
+				signaturesInfo = append(signaturesInfo, mTypes.SignatureInfo{
+					SignatureManifestDigest: sigInfo.SignatureManifestDigest,
+					LayersInfo:              layersInfo,
+				})
+			}
+
+			manifestSignatures[sigType] = signaturesInfo
+		}
+
+		repoMeta.Signatures[manifestDigest.String()] = manifestSignatures
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return repoBuck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) AddManifestSignature(repo string, signedManifestDigest godigest.Digest,
+	sygMeta mTypes.SignatureMetadata,
+) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+
+		if len(repoMetaBlob) == 0 {
+			var err error
+			// create a new object
+			repoMeta := mTypes.RepoMetadata{
+				Name: repo,
+				Tags: map[string]mTypes.Descriptor{},
+				Signatures: map[string]mTypes.ManifestSignatures{
+					signedManifestDigest.String(): {
+						sygMeta.SignatureType: []mTypes.SignatureInfo{
+							{
+								SignatureManifestDigest: sygMeta.SignatureDigest,
+								LayersInfo:              sygMeta.LayersInfo,
+							},
+						},
+					},
+				},
+				Statistics: map[string]mTypes.DescriptorStatistics{},
+				Referrers:  map[string][]mTypes.ReferrerInfo{},
+			}
+
+			repoMetaBlob, err = json.Marshal(repoMeta)
+			if err != nil {
+				return err
+			}
+
+			return buck.Put([]byte(repo), repoMetaBlob)
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		var (
+			manifestSignatures mTypes.ManifestSignatures
+			found              bool
+		)
+
+		if manifestSignatures, found = repoMeta.Signatures[signedManifestDigest.String()]; !found {
+			manifestSignatures = mTypes.ManifestSignatures{}
+		}
+
+		signatureSlice := manifestSignatures[sygMeta.SignatureType]
+		if !common.SignatureAlreadyExists(signatureSlice, sygMeta) {
+			if sygMeta.SignatureType == zcommon.NotationSignature {
+				signatureSlice = append(signatureSlice, mTypes.SignatureInfo{
+					SignatureManifestDigest: sygMeta.SignatureDigest,
+					LayersInfo:              sygMeta.LayersInfo,
+				})
+			} else if sygMeta.SignatureType == zcommon.CosignSignature {
+				signatureSlice = []mTypes.SignatureInfo{{
+					SignatureManifestDigest: sygMeta.SignatureDigest,
+					LayersInfo:              sygMeta.LayersInfo,
+				}}
+			}
+		}
+
+		manifestSignatures[sygMeta.SignatureType] = signatureSlice
+
+		repoMeta.Signatures[signedManifestDigest.String()] = manifestSignatures
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) DeleteSignature(repo string, signedManifestDigest godigest.Digest,
+	sigMeta mTypes.SignatureMetadata,
+) error {
+	err := rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := buck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrManifestMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		sigType := sigMeta.SignatureType
+
+		var (
+			manifestSignatures mTypes.ManifestSignatures
+			found              bool
+		)
+
+		if manifestSignatures, found = repoMeta.Signatures[signedManifestDigest.String()]; !found {
+			return zerr.ErrManifestMetaNotFound
+		}
+
+		signatureSlice := manifestSignatures[sigType]
+
+		newSignatureSlice := make([]mTypes.SignatureInfo, 0, len(signatureSlice)-1)
+
+		for _, sigDigest := range signatureSlice {
+			if sigDigest.SignatureManifestDigest != sigMeta.SignatureDigest {
+				newSignatureSlice = append(newSignatureSlice, sigDigest)
+			}
+		}
+
+		manifestSignatures[sigType] = newSignatureSlice
+
+		repoMeta.Signatures[signedManifestDigest.String()] = manifestSignatures
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		return buck.Put([]byte(repo), repoMetaBlob)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) SearchRepos(ctx context.Context, searchText string,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	var (
+		foundRepos          = make([]mTypes.RepoMetadata, 0)
+		manifestMetadataMap = make(map[string]mTypes.ManifestMetadata)
+		indexDataMap        = make(map[string]mTypes.IndexData)
+	)
+
+	err := rdw.view(func(transaction *bucketTx) error {
+		var (
+			repoBuck      = transaction.Bucket(RepoMetadataBucket)
+			indexBuck     = transaction.Bucket(IndexDataBucket)
+			manifestBuck  = transaction.Bucket(ManifestDataBucket)
+			userBookmarks = getUserBookmarks(ctx, transaction)
+			userStars     = getUserStars(ctx, transaction)
+		)
+
+		cursor := repoBuck.Cursor()
+
+		for repoName, repoMetaBlob := cursor.First(); repoName != nil; repoName, repoMetaBlob = cursor.Next() {
+			if ok, err := reqCtx.RepoIsUserAvailable(ctx, string(repoName)); !ok || err != nil {
+				continue
+			}
+
+			var repoMeta mTypes.RepoMetadata
+
+			err := json.Unmarshal(repoMetaBlob, &repoMeta)
+			if err != nil {
+				return err
+			}
+
+			rank := common.RankRepoName(searchText, repoMeta.Name)
+			if rank == -1 {
+				continue
+			}
+
+			repoMeta.IsBookmarked = zcommon.Contains(userBookmarks, repoMeta.Name)
+			repoMeta.IsStarred = zcommon.Contains(userStars, repoMeta.Name)
+			repoMeta.Rank = rank
+
+			for tag, descriptor := range repoMeta.Tags {
+				switch descriptor.MediaType {
+				case ispec.MediaTypeImageManifest:
+					manifestDigest := descriptor.Digest
+
+					manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest,
+						manifestMetadataMap, manifestBuck)
+					if err != nil {
+						return fmt.Errorf("metadb: error fetching manifest meta for manifest with digest %s %w",
+							manifestDigest, err)
+					}
+
+					manifestMetadataMap[descriptor.Digest] = manifestMeta
+				case ispec.MediaTypeImageIndex:
+					indexDigest := descriptor.Digest
+
+					indexData, err := fetchIndexDataWithCheck(indexDigest, indexDataMap, indexBuck)
+					if err != nil {
+						return fmt.Errorf("metadb: error fetching index data for index with digest %s %w",
+							indexDigest, err)
+					}
+
+					var indexContent ispec.Index
+
+					err = json.Unmarshal(indexData.IndexBlob, &indexContent)
+					if err != nil {
+						return fmt.Errorf("metadb: error while unmashaling index content for %s:%s %w",
+							repoName, tag, err)
+					}
+
+					for _, manifest := range indexContent.Manifests {
+						manifestDigest := manifest.Digest
+
+						manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest.String(),
+							manifestMetadataMap, manifestBuck)
+						if err != nil {
+							return err
+						}
+
+						manifestMetadataMap[manifest.Digest.String()] = manifestMeta
+					}
+
+					indexDataMap[indexDigest] = indexData
+				default:
+					rdw.Log.Error().Str("mediaType", descriptor.MediaType).Msg("Unsupported media type")
+
+					continue
+				}
+			}
+
+			foundRepos = append(foundRepos, repoMeta)
+		}
+
+		return nil
+	})
+
+	return foundRepos, manifestMetadataMap, indexDataMap, err
+}
+
+func fetchManifestMetaWithCheck(repoMeta mTypes.RepoMetadata, manifestDigest string,
+	manifestMetadataMap map[string]mTypes.ManifestMetadata, manifestBuck *bucket,
+) (mTypes.ManifestMetadata, error) {
+	manifestMeta, manifestDownloaded := manifestMetadataMap[manifestDigest]
+
+	if !manifestDownloaded {
+		var manifestData mTypes.ManifestData
+
+		manifestDataBlob := manifestBuck.Get([]byte(manifestDigest))
+		if manifestDataBlob == nil {
+			return mTypes.ManifestMetadata{}, zerr.ErrManifestMetaNotFound
+		}
+
+		err := json.Unmarshal(manifestDataBlob, &manifestData)
+		if err != nil {
+			return mTypes.ManifestMetadata{}, fmt.Errorf("metadb: error while unmarshaling manifest metadata for digest %s %w",
+				manifestDigest, err)
+		}
+
+		manifestMeta = newManifestMetadata(manifestDigest, repoMeta, manifestData)
+	}
+
+	return manifestMeta, nil
+}
+
+func fetchIndexDataWithCheck(indexDigest string, indexDataMap map[string]mTypes.IndexData,
+	indexBuck *bucket,
+) (mTypes.IndexData, error) {
+	var (
+		indexData mTypes.IndexData
+		err       error
+	)
+
+	indexData, indexExists := indexDataMap[indexDigest]
+
+	if !indexExists {
+		indexDataBlob := indexBuck.Get([]byte(indexDigest))
+		if indexDataBlob == nil {
+			return mTypes.IndexData{}, zerr.ErrIndexDataNotFount
+		}
+
+		err := json.Unmarshal(indexDataBlob, &indexData)
+		if err != nil {
+			return mTypes.IndexData{},
+				fmt.Errorf("metadb: error while unmashaling index data for digest %s %w", indexDigest, err)
+		}
+	}
+
+	return indexData, err
+}
+
+func newManifestMetadata(manifestDigest string, repoMeta mTypes.RepoMetadata,
+	manifestData mTypes.ManifestData,
+) mTypes.ManifestMetadata {
+	manifestMeta := mTypes.ManifestMetadata{
+		ManifestBlob: manifestData.ManifestBlob,
+		ConfigBlob:   manifestData.ConfigBlob,
+	}
+
+	manifestMeta.DownloadCount = repoMeta.Statistics[manifestDigest].DownloadCount
+
+	manifestMeta.Signatures = mTypes.ManifestSignatures{}
+	if repoMeta.Signatures[manifestDigest] != nil {
+		manifestMeta.Signatures = repoMeta.Signatures[manifestDigest]
+	}
+
+	return manifestMeta
+}
+
+func (rdw *RedisDB) FilterTags(ctx context.Context, filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	return rdw.filterTags(ctx, nil, filterFunc)
+}
+
+func (rdw *RedisDB) FilterTagsByManifests(ctx context.Context, manifestDigests []godigest.Digest,
+	filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	return rdw.filterTags(ctx, common.GetDigestSet(manifestDigests), filterFunc)
+}
+
+// filterTags only reads the data of the manifests in the set, or of every manifest if the set is nil.
+func (rdw *RedisDB) filterTags(ctx context.Context, manifestSet map[string]bool, filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	var (
+		foundRepos          = make([]mTypes.RepoMetadata, 0)
+		manifestMetadataMap = make(map[string]mTypes.ManifestMetadata)
+		indexDataMap        = make(map[string]mTypes.IndexData)
+	)
+
+	err := rdw.view(func(transaction *bucketTx) error {
+		var (
+			repoBuck      = transaction.Bucket(RepoMetadataBucket)
+			indexBuck     = transaction.Bucket(IndexDataBucket)
+			manifestBuck  = transaction.Bucket(ManifestDataBucket)
+			cursor        = repoBuck.Cursor()
+			userBookmarks = getUserBookmarks(ctx, transaction)
+			userStars     = getUserStars(ctx, transaction)
+		)
+
+		repoName, repoMetaBlob := cursor.First()
+
+		for ; repoName != nil; repoName, repoMetaBlob = cursor.Next() {
+			if ok, err := reqCtx.RepoIsUserAvailable(ctx, string(repoName)); !ok || err != nil {
+				continue
+			}
+
+			repoMeta := mTypes.RepoMetadata{}
+
+			err := json.Unmarshal(repoMetaBlob, &repoMeta)
+			if err != nil {
+				return err
+			}
+
+			repoMeta.IsBookmarked = zcommon.Contains(userBookmarks, repoMeta.Name)
+			repoMeta.IsStarred = zcommon.Contains(userStars, repoMeta.Name)
+
+			matchedTags := make(map[string]mTypes.Descriptor)
+			// take all manifestsMeta
+			for tag, descriptor := range repoMeta.Tags {
+				switch descriptor.MediaType {
+				case ispec.MediaTypeImageManifest:
+					manifestDigest := descriptor.Digest
+
+					if manifestSet != nil && !manifestSet[manifestDigest] {
+						continue
+					}
+
+					manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest, manifestMetadataMap, manifestBuck)
+					if err != nil {
+						return fmt.Errorf("metadb: error while unmashaling manifest metadata for digest %s %w", manifestDigest, err)
+					}
+
+					if filterFunc(repoMeta, manifestMeta) {
+						matchedTags[tag] = descriptor
+						manifestMetadataMap[manifestDigest] = manifestMeta
+					}
+				case ispec.MediaTypeImageIndex:
+					indexDigest := descriptor.Digest
+
+					indexData, err := fetchIndexDataWithCheck(indexDigest, indexDataMap, indexBuck)
+					if err != nil {
+						return fmt.Errorf("metadb: error while getting index data for digest %s %w", indexDigest, err)
+					}
+
+					var indexContent ispec.Index
+
+					err = json.Unmarshal(indexData.IndexBlob, &indexContent)
+					if err != nil {
+						return fmt.Errorf("metadb: error while unmashaling index content for digest %s %w", indexDigest, err)
+					}
+
+					matchedManifests := []ispec.Descriptor{}
+
+					for _, manifest := range indexContent.Manifests {
+						manifestDigest := manifest.Digest.String()
+
+						if manifestSet != nil && !manifestSet[manifestDigest] {
+							continue
+						}
+
+						manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest, manifestMetadataMap, manifestBuck)
+						if err != nil {
+							return fmt.Errorf("metadb: error while getting manifest data for digest %s %w", manifestDigest, err)
+						}
+
+						if filterFunc(repoMeta, manifestMeta) {
+							matchedManifests = append(matchedManifests, manifest)
+							manifestMetadataMap[manifestDigest] = manifestMeta
+						}
+					}
+
+					if len(matchedManifests) > 0 {
+						indexContent.Manifests = matchedManifests
+
+						indexBlob, err := json.Marshal(indexContent)
+						if err != nil {
+							return err
+						}
+
+						indexData.IndexBlob = indexBlob
+
+						indexDataMap[indexDigest] = indexData
+						matchedTags[tag] = descriptor
+					}
+				default:
+					rdw.Log.Error().Str("mediaType", descriptor.MediaType).Msg("Unsupported media type")
+
+					continue
+				}
+			}
+
+			if len(matchedTags) == 0 {
+				continue
+			}
+
+			repoMeta.Tags = matchedTags
+
+			foundRepos = append(foundRepos, repoMeta)
+		}
+
+		return nil
+	})
+
+	return foundRepos, manifestMetadataMap, indexDataMap, err
+}
+
+func (rdw *RedisDB) FilterRepos(ctx context.Context, filter mTypes.FilterRepoFunc) (
+	[]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error,
+) {
+	foundRepos := make([]mTypes.RepoMetadata, 0)
+
+	err := rdw.view(func(tx *bucketTx) error {
+		var (
+			buck          = tx.Bucket(RepoMetadataBucket)
+			cursor        = buck.Cursor()
+			userBookmarks = getUserBookmarks(ctx, tx)
+			userStars     = getUserStars(ctx, tx)
+		)
+
+		for repoName, repoMetaBlob := cursor.First(); repoName != nil; repoName, repoMetaBlob = cursor.Next() {
+			if ok, err := reqCtx.RepoIsUserAvailable(ctx, string(repoName)); !ok || err != nil {
+				continue
+			}
+
+			repoMeta := mTypes.RepoMetadata{}
+
+			err := json.Unmarshal(repoMetaBlob, &repoMeta)
+			if err != nil {
+				return err
+			}
+
+			repoMeta.IsBookmarked = zcommon.Contains(userBookmarks, repoMeta.Name)
+			repoMeta.IsStarred = zcommon.Contains(userStars, repoMeta.Name)
+
+			if filter(repoMeta) {
+				foundRepos = append(foundRepos, repoMeta)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return []mTypes.RepoMetadata{}, map[string]mTypes.ManifestMetadata{}, map[string]mTypes.IndexData{}, err
+	}
+
+	foundManifestMetadataMap, foundIndexDataMap, err := common.FetchDataForRepos(rdw, foundRepos)
+
+	return foundRepos, foundManifestMetadataMap, foundIndexDataMap, err
+}
+
+func (rdw *RedisDB) SearchTags(ctx context.Context, searchText string,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	var (
+		foundRepos          = make([]mTypes.RepoMetadata, 0)
+		manifestMetadataMap = make(map[string]mTypes.ManifestMetadata)
+		indexDataMap        = make(map[string]mTypes.IndexData)
+	)
+
+	searchedRepo, searchedTag, err := common.GetRepoTag(searchText)
+	if err != nil {
+		return []mTypes.RepoMetadata{}, map[string]mTypes.ManifestMetadata{}, map[string]mTypes.IndexData{},
+			fmt.Errorf("metadb: error while parsing search text, invalid format %w", err)
+	}
+
+	err = rdw.view(func(transaction *bucketTx) error {
+		var (
+			repoBuck      = transaction.Bucket(RepoMetadataBucket)
+			indexBuck     = transaction.Bucket(IndexDataBucket)
+			manifestBuck  = transaction.Bucket(ManifestDataBucket)
+			userBookmarks = getUserBookmarks(ctx, transaction)
+			userStars     = getUserStars(ctx, transaction)
+		)
+
+		repoName, repoMetaBlob := repoBuck.Cursor().Seek([]byte(searchedRepo))
+
+		if string(repoName) != searchedRepo {
+			return nil
+		}
+
+		if ok, err := reqCtx.RepoIsUserAvailable(ctx, string(repoName)); !ok || err != nil {
+			return err
+		}
+
+		repoMeta := mTypes.RepoMetadata{}
+
+		err := json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		repoMeta.IsBookmarked = zcommon.Contains(userBookmarks, repoMeta.Name)
+		repoMeta.IsStarred = zcommon.Contains(userStars, repoMeta.Name)
+
+		matchedTags := make(map[string]mTypes.Descriptor)
+
+		for tag, descriptor := range repoMeta.Tags {
+			if !strings.HasPrefix(tag, searchedTag) {
+				continue
+			}
+
+			matchedTags[tag] = descriptor
+
+			switch descriptor.MediaType {
+			case ispec.MediaTypeImageManifest:
+				manifestDigest := descriptor.Digest
+
+				manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest, manifestMetadataMap, manifestBuck)
+				if err != nil {
+					return fmt.Errorf("metadb: error fetching manifest meta for manifest with digest %s %w",
+						manifestDigest, err)
+				}
+
+				manifestMetadataMap[descriptor.Digest] = manifestMeta
+			case ispec.MediaTypeImageIndex:
+				indexDigest := descriptor.Digest
+
+				indexData, err := fetchIndexDataWithCheck(indexDigest, indexDataMap, indexBuck)
+				if err != nil {
+					return fmt.Errorf("metadb: error fetching index data for index with digest %s %w",
+						indexDigest, err)
+				}
+
+				var indexContent ispec.Index
+
+				err = json.Unmarshal(indexData.IndexBlob, &indexContent)
+				if err != nil {
+					return fmt.Errorf("metadb: error collecting filter data for index with digest %s %w",
+						indexDigest, err)
+				}
+
+				for _, manifest := range indexContent.Manifests {
+					manifestDigest := manifest.Digest.String()
+
+					manifestMeta, err := fetchManifestMetaWithCheck(repoMeta, manifestDigest, manifestMetadataMap, manifestBuck)
+					if err != nil {
+						return fmt.Errorf("metadb: error fetching from db manifest meta for manifest with digest %s %w",
+							manifestDigest, err)
+					}
+
+					manifestMetadataMap[manifestDigest] = manifestMeta
+				}
+
+				indexDataMap[indexDigest] = indexData
+			default:
+				rdw.Log.Error().Str("mediaType", descriptor.MediaType).Msg("Unsupported media type")
+
+				continue
+			}
+		}
+
+		if len(matchedTags) == 0 {
+			return nil
+		}
+
+		repoMeta.Tags = matchedTags
+
+		foundRepos = append(foundRepos, repoMeta)
+
+		return nil
+	})
+
+	return foundRepos, manifestMetadataMap, indexDataMap, err
+}
+
+func (rdw *RedisDB) ToggleStarRepo(ctx context.Context, repo string) (mTypes.ToggleState, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return mTypes.NotChanged, err
+	}
+
+	if userAc.IsAnonymous() || !userAc.Can(constants.ReadPermission, repo) {
+		return mTypes.NotChanged, zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	var res mTypes.ToggleState
+
+	if err := rdw.update(func(tx *bucketTx) error { //nolint:varnamelen
+		var userData mTypes.UserData
+
+		err := rdw.getUserData(userid, tx, &userData)
+		if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+			return err
+		}
+
+		isRepoStarred := zcommon.Contains(userData.StarredRepos, repo)
+
+		if isRepoStarred {
+			res = mTypes.Removed
+			userData.StarredRepos = zcommon.RemoveFrom(userData.StarredRepos, repo)
+		} else {
+			res = mTypes.Added
+			userData.StarredRepos = append(userData.StarredRepos, repo)
+		}
+
+		err = rdw.setUserData(userid, tx, userData)
+		if err != nil {
+			return err
+		}
+
+		repoBuck := tx.Bucket(RepoMetadataBucket)
+
+		repoMetaBlob := repoBuck.Get([]byte(repo))
+		if repoMetaBlob == nil {
+			return zerr.ErrRepoMetaNotFound
+		}
+
+		var repoMeta mTypes.RepoMetadata
+
+		err = json.Unmarshal(repoMetaBlob, &repoMeta)
+		if err != nil {
+			return err
+		}
+
+		switch res {
+		case mTypes.Added:
+			repoMeta.Stars++
+		case mTypes.Removed:
+			repoMeta.Stars--
+		}
+
+		repoMetaBlob, err = json.Marshal(repoMeta)
+		if err != nil {
+			return err
+		}
+
+		err = repoBuck.Put([]byte(repo), repoMetaBlob)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return mTypes.NotChanged, err
+	}
+
+	return res, nil
+}
+
+func (rdw *RedisDB) GetStarredRepos(ctx context.Context) ([]string, error) {
+	userData, err := rdw.GetUserData(ctx)
+	if errors.Is(err, zerr.ErrUserDataNotFound) || errors.Is(err, zerr.ErrUserDataNotAllowed) {
+		return []string{}, nil
+	}
+
+	return userData.StarredRepos, err
+}
+
+func (rdw *RedisDB) ToggleBookmarkRepo(ctx context.Context, repo string) (mTypes.ToggleState, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return mTypes.NotChanged, err
+	}
+
+	if userAc.IsAnonymous() || !userAc.Can(constants.ReadPermission, repo) {
+		return mTypes.NotChanged, zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	var res mTypes.ToggleState
+
+	if err := rdw.update(func(transaction *bucketTx) error { //nolint:dupl
+		var userData mTypes.UserData
+
+		err := rdw.getUserData(userid, transaction, &userData)
+		if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+			return err
+		}
+
+		isRepoBookmarked := zcommon.Contains(userData.BookmarkedRepos, repo)
+
+		if isRepoBookmarked {
+			res = mTypes.Removed
+			userData.BookmarkedRepos = zcommon.RemoveFrom(userData.BookmarkedRepos, repo)
+		} else {
+			res = mTypes.Added
+			userData.BookmarkedRepos = append(userData.BookmarkedRepos, repo)
+		}
+
+		return rdw.setUserData(userid, transaction, userData)
+	}); err != nil {
+		return mTypes.NotChanged, err
+	}
+
+	return res, nil
+}
+
+func (rdw *RedisDB) GetBookmarkedRepos(ctx context.Context) ([]string, error) {
+	userData, err := rdw.GetUserData(ctx)
+	if errors.Is(err, zerr.ErrUserDataNotFound) || errors.Is(err, zerr.ErrUserDataNotAllowed) {
+		return []string{}, nil
+	}
+
+	return userData.BookmarkedRepos, err
+}
+
+func (rdw *RedisDB) SetBookmarkList(ctx context.Context, list mTypes.BookmarkList) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	list, err = common.NewBookmarkList(userAc, list)
+	if err != nil {
+		return err
+	}
+
+	userid := userAc.GetUsername()
+
+	return rdw.update(func(transaction *bucketTx) error {
+		var userData mTypes.UserData
+
+		err := rdw.getUserData(userid, transaction, &userData)
+		if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+			return err
+		}
+
+		if userData.BookmarkLists == nil {
+			userData.BookmarkLists = map[string]mTypes.BookmarkList{}
+		}
+
+		userData.BookmarkLists[list.Name] = list
+
+		return rdw.setUserData(userid, transaction, userData)
+	})
+}
+
+func (rdw *RedisDB) DeleteBookmarkList(ctx context.Context, name string) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	return rdw.update(func(transaction *bucketTx) error {
+		var userData mTypes.UserData
+
+		err := rdw.getUserData(userid, transaction, &userData)
+		if err != nil {
+			if errors.Is(err, zerr.ErrUserDataNotFound) {
+				return zerr.ErrBookmarkListNotFound
+			}
+
+			return err
+		}
+
+		if _, ok := userData.BookmarkLists[name]; !ok {
+			return zerr.ErrBookmarkListNotFound
+		}
+
+		delete(userData.BookmarkLists, name)
+
+		return rdw.setUserData(userid, transaction, userData)
+	})
+}
+
+func (rdw *RedisDB) GetBookmarkLists(ctx context.Context) ([]mTypes.BookmarkList, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return []mTypes.BookmarkList{}, err
+	}
+
+	if userAc.IsAnonymous() {
+		return []mTypes.BookmarkList{}, nil
+	}
+
+	lists := []mTypes.BookmarkList{}
+
+	// the lists shared with the groups of the user are stored in the user data of their owners
+	err = rdw.view(func(transaction *bucketTx) error {
+		buck := transaction.Bucket(UserDataBucket)
+		return buck.ForEach(func(userid, userDataBlob []byte) error {
+			var userData mTypes.UserData
+
+			if err := json.Unmarshal(userDataBlob, &userData); err != nil {
+				return err
+			}
+
+			for _, list := range userData.BookmarkLists {
+				lists = append(lists, list)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return []mTypes.BookmarkList{}, err
+	}
+
+	return common.GetReadableBookmarkLists(userAc, lists), nil
+}
+
+func (rdw *RedisDB) PatchDB() error {
+	var DBVersion string
+
+	err := rdw.view(func(tx *bucketTx) error {
+		versionBuck := tx.Bucket(VersionBucket)
+		DBVersion = string(versionBuck.Get([]byte(version.DBVersionKey)))
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("patching the database failed, can't read db version %w", err)
+	}
+
+	if version.GetVersionIndex(DBVersion) == -1 {
+		return fmt.Errorf("DB has broken format, no version found %w", err)
+	}
+
+	for patchIndex, patch := range rdw.Patches {
+		if patchIndex < version.GetVersionIndex(DBVersion) {
+			continue
+		}
+
+		err := patch(rdw.Client, rdw.KeyPrefix)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getUserStars(ctx context.Context, transaction *bucketTx) []string {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return []string{}
+	}
+
+	var (
+		userData mTypes.UserData
+		userid   = userAc.GetUsername()
+		userdb   = transaction.Bucket(UserDataBucket)
+	)
+
+	if userid == "" {
+		return []string{}
+	}
+
+	mdata := userdb.Get([]byte(userid))
+	if mdata == nil {
+		return []string{}
+	}
+
+	if err := json.Unmarshal(mdata, &userData); err != nil {
+		return []string{}
+	}
+
+	return userData.StarredRepos
+}
+
+func getUserBookmarks(ctx context.Context, transaction *bucketTx) []string {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return []string{}
+	}
+
+	var (
+		userData mTypes.UserData
+		userid   = userAc.GetUsername()
+		userdb   = transaction.Bucket(UserDataBucket)
+	)
+
+	if userid == "" {
+		return []string{}
+	}
+
+	mdata := userdb.Get([]byte(userid))
+	if mdata == nil {
+		return []string{}
+	}
+
+	if err := json.Unmarshal(mdata, &userData); err != nil {
+		return []string{}
+	}
+
+	return userData.BookmarkedRepos
+}
+
+func (rdw *RedisDB) SetUserGroups(ctx context.Context, groups []string) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.update(func(tx *bucketTx) error { //nolint:varnamelen
+		var userData mTypes.UserData
+
+		err := rdw.getUserData(userid, tx, &userData)
+		if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+			return err
+		}
+
+		userData.Groups = append(userData.Groups, groups...)
+
+		err = rdw.setUserData(userid, tx, userData)
+
+		return err
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetUserGroups(ctx context.Context) ([]string, error) {
+	userData, err := rdw.GetUserData(ctx)
+
+	return userData.Groups, err
+}
+
+func (rdw *RedisDB) UpdateUserAPIKeyLastUsed(ctx context.Context, hashedKey string) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.update(func(tx *bucketTx) error { //nolint:varnamelen
+		var userData mTypes.UserData
+
+		err := rdw.getUserData(userid, tx, &userData)
+		if err != nil {
+			return err
+		}
+
+		apiKeyDetails := userData.APIKeys[hashedKey]
+		apiKeyDetails.LastUsed = time.Now()
+
+		userData.APIKeys[hashedKey] = apiKeyDetails
+
+		err = rdw.setUserData(userid, tx, userData)
+
+		return err
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) IsAPIKeyExpired(ctx context.Context, hashedKey string) (bool, error) {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if userAc.IsAnonymous() {
+		return false, zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	var isExpired bool
+
+	err = rdw.update(func(tx *bucketTx) error { //nolint:varnamelen
+		var userData mTypes.UserData
+
+		err := rdw.getUserData(userid, tx, &userData)
+		if err != nil {
+			return err
+		}
+
+		apiKeyDetails := userData.APIKeys[hashedKey]
+		if apiKeyDetails.IsExpired {
+			isExpired = true
+
+			return nil
+		}
+
+		// if expiresAt is not nil value
+		if !apiKeyDetails.ExpirationDate.Equal(time.Time{}) && time.Now().After(apiKeyDetails.ExpirationDate) {
+			isExpired = true
+			apiKeyDetails.IsExpired = true
+		}
+
+		userData.APIKeys[hashedKey] = apiKeyDetails
+
+		err = rdw.setUserData(userid, tx, userData)
+
+		return err
+	})
+
+	return isExpired, err
+}
+
+func (rdw *RedisDB) GetUserAPIKeys(ctx context.Context) ([]mTypes.APIKeyDetails, error) {
+	apiKeys := make([]mTypes.APIKeyDetails, 0)
+
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if userAc.IsAnonymous() {
+		return nil, zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.update(func(transaction *bucketTx) error {
+		var userData mTypes.UserData
+
+		err = rdw.getUserData(userid, transaction, &userData)
+		if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+			return err
+		}
+
+		for hashedKey, apiKeyDetails := range userData.APIKeys {
+			// if expiresAt is not nil value
+			if !apiKeyDetails.ExpirationDate.Equal(time.Time{}) && time.Now().After(apiKeyDetails.ExpirationDate) {
+				apiKeyDetails.IsExpired = true
+			}
+
+			userData.APIKeys[hashedKey] = apiKeyDetails
+
+			err = rdw.setUserData(userid, transaction, userData)
+			if err != nil {
+				return err
+			}
+
+			apiKeys = append(apiKeys, apiKeyDetails)
+		}
+
+		return nil
+	})
+
+	return apiKeys, err
+}
+
+func (rdw *RedisDB) AddUserAPIKey(ctx context.Context, hashedKey string, apiKeyDetails *mTypes.APIKeyDetails) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.update(func(transaction *bucketTx) error {
+		var userData mTypes.UserData
+
+		apiKeysbuck := transaction.Bucket(UserAPIKeysBucket)
+		err := apiKeysbuck.Put([]byte(hashedKey), []byte(userid))
+		if err != nil {
+			return fmt.Errorf("metaDB: error while setting userData for identity %s %w", userid, err)
+		}
+
+		err = rdw.getUserData(userid, transaction, &userData)
+		if err != nil && !errors.Is(err, zerr.ErrUserDataNotFound) {
+			return err
+		}
+
+		if userData.APIKeys == nil {
+			userData.APIKeys = make(map[string]mTypes.APIKeyDetails)
+		}
+
+		userData.APIKeys[hashedKey] = *apiKeyDetails
+
+		err = rdw.setUserData(userid, transaction, userData)
+
+		return err
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) DeleteUserAPIKey(ctx context.Context, keyID string) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.update(func(transaction *bucketTx) error {
+		var userData mTypes.UserData
+
+		apiKeysbuck := transaction.Bucket(UserAPIKeysBucket)
+		err := rdw.getUserData(userid, transaction, &userData)
+		if err != nil {
+			return err
+		}
+
+		for hash, apiKeyDetails := range userData.APIKeys {
+			if apiKeyDetails.UUID == keyID {
+				delete(userData.APIKeys, hash)
+
+				err := apiKeysbuck.Delete([]byte(hash))
+				if err != nil {
+					return fmt.Errorf("userDB: error while deleting userAPIKey entry for hash %s %w", hash, err)
+				}
+			}
+		}
+
+		return rdw.setUserData(userid, transaction, userData)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) GetUserAPIKeyInfo(hashedKey string) (string, error) {
+	var userid string
+	err := rdw.view(func(tx *bucketTx) error {
+		buck := tx.Bucket(UserAPIKeysBucket)
+		uiBlob := buck.Get([]byte(hashedKey))
+		if len(uiBlob) == 0 {
+			return zerr.ErrUserAPIKeyNotFound
+		}
+
+		userid = string(uiBlob)
+
+		return nil
+	})
+
+	return userid, err
+}
+
+func (rdw *RedisDB) GetUserData(ctx context.Context) (mTypes.UserData, error) {
+	var userData mTypes.UserData
+
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return userData, err
+	}
+
+	if userAc.IsAnonymous() {
+		return userData, zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.view(func(tx *bucketTx) error {
+		return rdw.getUserData(userid, tx, &userData)
+	})
+
+	return userData, err
+}
+
+func (rdw *RedisDB) getUserData(userid string, transaction *bucketTx, res *mTypes.UserData) error {
+	buck := transaction.Bucket(UserDataBucket)
+	upBlob := buck.Get([]byte(userid))
+
+	if len(upBlob) == 0 {
+		return zerr.ErrUserDataNotFound
+	}
+
+	err := json.Unmarshal(upBlob, res)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rdw *RedisDB) SetUserData(ctx context.Context, userData mTypes.UserData) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.update(func(tx *bucketTx) error {
+		return rdw.setUserData(userid, tx, userData)
+	})
+
+	return err
+}
+
+func (rdw *RedisDB) setUserData(userid string, transaction *bucketTx, userData mTypes.UserData) error {
+	buck := transaction.Bucket(UserDataBucket)
+	upBlob, err := json.Marshal(userData)
+	if err != nil {
+		return err
+	}
+
+	err = buck.Put([]byte(userid), upBlob)
+	if err != nil {
+		return fmt.Errorf("metaDB: error while setting userData for identity %s %w", userid, err)
+	}
+
+	return nil
+}
+
+func (rdw *RedisDB) DeleteUserData(ctx context.Context) error {
+	userAc, err := reqCtx.UserAcFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userAc.IsAnonymous() {
+		return zerr.ErrUserDataNotAllowed
+	}
+
+	userid := userAc.GetUsername()
+
+	err = rdw.update(func(tx *bucketTx) error {
+		buck := tx.Bucket(UserDataBucket)
+		err := buck.Delete([]byte(userid))
+		if err != nil {
+			return fmt.Errorf("metaDB: error while deleting userData for identity %s %w", userid, err)
+		}
+
+		return nil
+	})
+
+	return err
+}
@@ -0,0 +1,437 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	zerr "zotregistry.io/zot/errors"
+)
+
+const (
+	// cursorPageSize is how many values a cursor reads at once.
+	cursorPageSize = 100
+	// lockTTL is how long a transaction can hold the lock, the writes of a transaction which held it longer fail.
+	lockTTL = 30 * time.Second
+	// lockTimeout is how long a transaction waits for the lock.
+	lockTimeout    = time.Minute
+	lockRetryDelay = 10 * time.Millisecond
+)
+
+// commitScriptSource applies the writes of a transaction and releases its lock if the transaction still holds
+// it. KEYS are the lock and the buckets, ARGV are the lock token and the writes, as groups of the index of their
+// bucket in KEYS, the operation, the key and the value, the key and the value of the clear operations are empty.
+const commitScriptSource = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+
+local index = 2
+while index <= #ARGV do
+	local bucket = KEYS[tonumber(ARGV[index])]
+
+	if ARGV[index + 1] == 'set' then
+		redis.call('HSET', bucket, ARGV[index + 2], ARGV[index + 3])
+	elseif ARGV[index + 1] == 'clear' then
+		redis.call('DEL', bucket)
+	else
+		redis.call('HDEL', bucket, ARGV[index + 2])
+	end
+
+	index = index + 4
+end
+
+redis.call('DEL', KEYS[1])
+
+return 1
+`
+
+// releaseScriptSource releases the lock if the transaction still holds it.
+const releaseScriptSource = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+
+return 0
+`
+
+var (
+	commitScript  = goredis.NewScript(commitScriptSource)  //nolint:gochecknoglobals
+	releaseScript = goredis.NewScript(releaseScriptSource) //nolint:gochecknoglobals
+)
+
+/*
+bucketTx gives the metadb the buckets of a boltdb transaction, each bucket is a hash of keys and values, so
+both implementations share the same logic. The writes of all the instances are serialized by a lock, they are
+kept by the transaction and applied at once by a script when it's done. The reads don't wait for the lock, they
+see the latest writes. The getters and the cursors don't return errors like the boltdb ones, the first error of
+their commands is returned by the transaction instead.
+*/
+type bucketTx struct {
+	ctx       context.Context //nolint:containedctx // the transaction only lives during the call
+	client    goredis.UniversalClient
+	keyPrefix string
+	// lockToken identifies the lock held by the transaction, it's empty for the read only transactions
+	lockToken string
+	writes    []write
+	// pending are the last writes of the keys of each bucket, read before the hashes
+	pending map[string]map[string]write
+	// cleared are the buckets whose hashes are deleted by the transaction, only their pending keys are read
+	cleared map[string]bool
+	err     error
+}
+
+type write struct {
+	bucket string
+	key    string
+	value  []byte
+	delete bool
+	clear  bool
+}
+
+type bucket struct {
+	tx   *bucketTx
+	name string
+}
+
+type keyValue struct {
+	key   []byte
+	value []byte
+}
+
+type cursor struct {
+	bucket *bucket
+	keys   []string
+	// next is the index in keys of the first key of the next page
+	next  int
+	rows  []keyValue
+	index int
+}
+
+func (rdw *RedisDB) update(fn func(tx *bucketTx) error) error {
+	ctx := context.Background()
+
+	lockToken, err := rdw.lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	transaction := rdw.newBucketTx(ctx, lockToken)
+
+	err = fn(transaction)
+	if transaction.err != nil {
+		err = transaction.err
+	}
+
+	if err != nil {
+		_ = releaseScript.Run(ctx, rdw.Client, []string{rdw.lockKey()}, lockToken).Err()
+
+		return err
+	}
+
+	return transaction.commit()
+}
+
+func (rdw *RedisDB) view(fn func(tx *bucketTx) error) error {
+	transaction := rdw.newBucketTx(context.Background(), "")
+
+	err := fn(transaction)
+	if transaction.err != nil {
+		err = transaction.err
+	}
+
+	return err
+}
+
+func (rdw *RedisDB) newBucketTx(ctx context.Context, lockToken string) *bucketTx {
+	return &bucketTx{
+		ctx:       ctx,
+		client:    rdw.Client,
+		keyPrefix: rdw.KeyPrefix,
+		lockToken: lockToken,
+		pending:   map[string]map[string]write{},
+		cleared:   map[string]bool{},
+	}
+}
+
+func (rdw *RedisDB) lockKey() string {
+	return rdw.KeyPrefix + ":lock"
+}
+
+// lock waits for the lock of the writes and returns its token.
+func (rdw *RedisDB) lock(ctx context.Context) (string, error) {
+	const tokenLength = 16
+
+	tokenBytes := make([]byte, tokenLength)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+
+	lockToken := hex.EncodeToString(tokenBytes)
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		locked, err := rdw.Client.SetNX(ctx, rdw.lockKey(), lockToken, lockTTL).Result()
+		if err != nil {
+			return "", fmt.Errorf("metadb: failed to lock the keys %w", err)
+		}
+
+		if locked {
+			return lockToken, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("metadb: timed out waiting for the lock of the keys %w", zerr.ErrMetaDBLockTimeout)
+		}
+
+		time.Sleep(lockRetryDelay)
+	}
+}
+
+func (tx *bucketTx) commit() error {
+	buckets := getBuckets()
+	keys := []string{tx.keyPrefix + ":lock"}
+	bucketIndexes := map[string]int{}
+
+	for i, name := range buckets {
+		keys = append(keys, tx.bucketKey(name))
+		// the index in KEYS of the scripts, which start at 1
+		bucketIndexes[name] = i + 2
+	}
+
+	args := []interface{}{tx.lockToken}
+
+	for _, write := range tx.writes {
+		operation := "set"
+		if write.delete {
+			operation = "del"
+		}
+
+		if write.clear {
+			operation = "clear"
+		}
+
+		args = append(args, strconv.Itoa(bucketIndexes[write.bucket]), operation, write.key, write.value)
+	}
+
+	committed, err := commitScript.Run(tx.ctx, tx.client, keys, args...).Int()
+	if err != nil {
+		return fmt.Errorf("metadb: failed to commit the writes %w", err)
+	}
+
+	if committed == 0 {
+		return fmt.Errorf("metadb: the lock expired before the writes were committed %w", zerr.ErrMetaDBLockTimeout)
+	}
+
+	return nil
+}
+
+func (tx *bucketTx) setErr(err error) {
+	if tx.err == nil {
+		tx.err = fmt.Errorf("metadb: command failed %w", err)
+	}
+}
+
+func (tx *bucketTx) bucketKey(name string) string {
+	return tx.keyPrefix + ":" + name
+}
+
+func (tx *bucketTx) Bucket(name string) *bucket {
+	return &bucket{tx: tx, name: name}
+}
+
+func (buck *bucket) getPending(key string) (write, bool) {
+	write, found := buck.tx.pending[buck.name][key]
+
+	return write, found
+}
+
+func (buck *bucket) addWrite(newWrite write) error {
+	if buck.tx.lockToken == "" {
+		return zerr.ErrReadOnlyTransaction
+	}
+
+	if buck.tx.pending[buck.name] == nil {
+		buck.tx.pending[buck.name] = map[string]write{}
+	}
+
+	buck.tx.writes = append(buck.tx.writes, newWrite)
+
+	if newWrite.clear {
+		buck.tx.pending[buck.name] = map[string]write{}
+		buck.tx.cleared[buck.name] = true
+	} else {
+		buck.tx.pending[buck.name][newWrite.key] = newWrite
+	}
+
+	return nil
+}
+
+// Get returns the value of the key, nil if it's not found.
+func (buck *bucket) Get(key []byte) []byte {
+	if write, found := buck.getPending(string(key)); found {
+		if write.delete {
+			return nil
+		}
+
+		return write.value
+	}
+
+	if buck.tx.cleared[buck.name] {
+		return nil
+	}
+
+	value, err := buck.tx.client.HGet(buck.tx.ctx, buck.tx.bucketKey(buck.name), string(key)).Bytes()
+	if err != nil {
+		if !errors.Is(err, goredis.Nil) {
+			buck.tx.setErr(err)
+		}
+
+		return nil
+	}
+
+	return value
+}
+
+func (buck *bucket) Put(key []byte, value []byte) error {
+	return buck.addWrite(write{bucket: buck.name, key: string(key), value: value})
+}
+
+func (buck *bucket) Delete(key []byte) error {
+	return buck.addWrite(write{bucket: buck.name, key: string(key), delete: true})
+}
+
+// Clear deletes all the keys of the bucket.
+func (buck *bucket) Clear() error {
+	return buck.addWrite(write{bucket: buck.name, clear: true})
+}
+
+func (buck *bucket) ForEach(fn func(key, value []byte) error) error {
+	cursor := buck.Cursor()
+
+	for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Cursor iterates the keys in byte order.
+func (buck *bucket) Cursor() *cursor {
+	return &cursor{bucket: buck}
+}
+
+func (cur *cursor) First() ([]byte, []byte) {
+	return cur.Seek([]byte{})
+}
+
+// Seek moves the cursor to the first key which isn't before the given one.
+func (cur *cursor) Seek(seek []byte) ([]byte, []byte) {
+	cur.keys = cur.bucket.keys()
+	cur.next = sort.SearchStrings(cur.keys, string(seek))
+	cur.load()
+
+	return cur.current()
+}
+
+func (cur *cursor) Next() ([]byte, []byte) {
+	cur.index++
+
+	if cur.index >= len(cur.rows) {
+		cur.load()
+	}
+
+	return cur.current()
+}
+
+func (cur *cursor) current() ([]byte, []byte) {
+	if cur.index >= len(cur.rows) {
+		return nil, nil
+	}
+
+	return cur.rows[cur.index].key, cur.rows[cur.index].value
+}
+
+// keys returns the sorted keys of the bucket, with the ones written by the transaction.
+func (buck *bucket) keys() []string {
+	keys := []string{}
+
+	if !buck.tx.cleared[buck.name] {
+		var err error
+
+		keys, err = buck.tx.client.HKeys(buck.tx.ctx, buck.tx.bucketKey(buck.name)).Result()
+		if err != nil {
+			buck.tx.setErr(err)
+
+			return []string{}
+		}
+	}
+
+	keySet := map[string]bool{}
+
+	for _, key := range keys {
+		keySet[key] = true
+	}
+
+	for key, write := range buck.tx.pending[buck.name] {
+		keySet[key] = !write.delete
+	}
+
+	keys = make([]string, 0, len(keySet))
+
+	for key, found := range keySet {
+		if found {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// load reads the values of the next page of keys, the keys deleted since they were listed are skipped.
+func (cur *cursor) load() {
+	cur.rows = []keyValue{}
+	cur.index = 0
+
+	for len(cur.rows) == 0 && cur.next < len(cur.keys) {
+		end := cur.next + cursorPageSize
+		if end > len(cur.keys) {
+			end = len(cur.keys)
+		}
+
+		pageKeys := cur.keys[cur.next:end]
+		cur.next = end
+
+		values, err := cur.bucket.tx.client.HMGet(cur.bucket.tx.ctx, cur.bucket.tx.bucketKey(cur.bucket.name),
+			pageKeys...).Result()
+		if err != nil {
+			cur.bucket.tx.setErr(err)
+			cur.next = len(cur.keys)
+
+			return
+		}
+
+		for i, key := range pageKeys {
+			if write, found := cur.bucket.getPending(key); found {
+				cur.rows = append(cur.rows, keyValue{key: []byte(key), value: write.value})
+
+				continue
+			}
+
+			if value, ok := values[i].(string); ok {
+				cur.rows = append(cur.rows, keyValue{key: []byte(key), value: []byte(value)})
+			}
+		}
+	}
+}
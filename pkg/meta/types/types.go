@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"io"
 	"time"
 
 	godigest "github.com/opencontainers/go-digest"
@@ -42,12 +43,19 @@ type MetaDB interface { //nolint:interfacebloat
 	// GetRepoStars returns the total number of stars a repo has
 	GetRepoStars(repo string) (int, error)
 
+	// SetRepoReadme sets the markdown README of a repo, a nil README removes it
+	SetRepoReadme(repo string, readme *RepoReadme) error
+
 	// SetRepoReference sets the reference of a manifest in the tag list of a repo
 	SetRepoReference(repo string, reference string, manifestDigest godigest.Digest, mediaType string) error
 
 	// DeleteRepoTag delets the tag from the tag list of a repo
 	DeleteRepoTag(repo string, tag string) error
 
+	// DeleteRepoMeta deletes the metadata of a repo and removes it from the stars, bookmarks and bookmark lists
+	// of the users
+	DeleteRepoMeta(repo string) error
+
 	// GetRepoMeta returns RepoMetadata of a repo from the database
 	GetRepoMeta(repo string) (RepoMetadata, error)
 
@@ -95,6 +103,33 @@ type MetaDB interface { //nolint:interfacebloat
 	// IncrementManifestDownloads adds 1 to the download count of a manifest
 	IncrementImageDownloads(repo string, reference string) error
 
+	// RecordDownload adds a pull of an image to the daily download statistics of its repo. The statistics are
+	// stored by day and repo, under keys starting with the day, so the ones of a range of days are read, rolled
+	// up and deleted together
+	RecordDownload(record DownloadRecord) error
+
+	// GetDownloadStats returns the daily download statistics between the days of 'from' and 'to', for a repo
+	// or for all the repos the user can read if 'repo' is empty
+	GetDownloadStats(ctx context.Context, repo string, from, to time.Time) ([]DailyDownloads, error)
+
+	// DeleteDownloadStats deletes the daily download statistics of the days before the day of 'before'
+	DeleteDownloadStats(before time.Time) error
+
+	// RollupDownloadStats removes the hourly download statistics of the days before the day of 'before', only
+	// their daily totals are kept
+	RollupDownloadStats(before time.Time) error
+
+	// RecordUserActivity adds an action to the activity history of its user. The actions are stored under keys
+	// made of the user and the time, so the ones of a user between two times are read in order. An action with
+	// the time of an already stored one of the same user is moved 1ns later, so both are kept
+	RecordUserActivity(activity UserActivity) error
+
+	// GetUserActivity returns the actions of a user between 'from' and 'to', the oldest first
+	GetUserActivity(ctx context.Context, user string, from, to time.Time) ([]UserActivity, error)
+
+	// DeleteUserActivity deletes the actions of all the users before 'before'
+	DeleteUserActivity(before time.Time) error
+
 	// AddManifestSignature adds signature metadata to a given manifest in the database
 	AddManifestSignature(repo string, signedManifestDigest godigest.Digest, sm SignatureMetadata) error
 
@@ -120,8 +155,24 @@ type MetaDB interface { //nolint:interfacebloat
 	FilterTags(ctx context.Context, filterFunc FilterFunc) (
 		[]RepoMetadata, map[string]ManifestMetadata, map[string]IndexData, error)
 
+	// FilterTagsByManifests filters for images given a filter function, like FilterTags, but only the given
+	// manifests are considered, the data of the other manifests is not read
+	FilterTagsByManifests(ctx context.Context, manifestDigests []godigest.Digest, filterFunc FilterFunc) (
+		[]RepoMetadata, map[string]ManifestMetadata, map[string]IndexData, error)
+
+	// GetManifestsWithLayer returns the digests of the manifests having the given layer, from the layer index
+	// updated when manifest data is set. Manifest data is never deleted, so neither are the index entries, the
+	// callers look the returned manifests up in the tags of the repos to skip the ones no longer referenced
+	GetManifestsWithLayer(layerDigest godigest.Digest) ([]godigest.Digest, error)
+
 	PatchDB() error
 
+	// Backup writes a consistent snapshot of the metadata, in a format any metaDB but DynamoDB can restore
+	Backup(ctx context.Context, writer io.Writer) error
+
+	// Restore replaces all the metadata with a snapshot written by Backup, then patches it to the current version
+	Restore(ctx context.Context, reader io.Reader) error
+
 	ImageTrustStore() ImageTrustStore
 
 	SetImageTrustStore(imgTrustStore ImageTrustStore)
@@ -140,6 +191,15 @@ type UserDB interface { //nolint:interfacebloat
 	// ToggleBookmarkRepo adds/removes bookmarks on repos
 	ToggleBookmarkRepo(ctx context.Context, reponame string) (ToggleState, error)
 
+	// SetBookmarkList creates or replaces a bookmark list of the current user
+	SetBookmarkList(ctx context.Context, list BookmarkList) error
+
+	// DeleteBookmarkList deletes a bookmark list of the current user
+	DeleteBookmarkList(ctx context.Context, name string) error
+
+	// GetBookmarkLists returns the bookmark lists of the current user and the ones shared with its groups
+	GetBookmarkLists(ctx context.Context) ([]BookmarkList, error)
+
 	// UserDB profile/api key CRUD
 	GetUserData(ctx context.Context) (UserData, error)
 
@@ -169,6 +229,10 @@ type ImageTrustStore interface {
 		signatureType string, rawSignature []byte, sigKey string, manifestDigest godigest.Digest, manifestContent []byte,
 		repo string,
 	) (string, time.Time, bool, error)
+
+	// VerifyCosignKeylessSignature verifies a cosign signature made with a short-lived certificate,
+	// it returns the identity of the certificate.
+	VerifyCosignKeylessSignature(layerInfo LayerInfo, manifestDigest godigest.Digest) (string, bool, error)
 }
 
 type ManifestMetadata struct {
@@ -201,8 +265,42 @@ type Descriptor struct {
 	MediaType string
 }
 
+// DownloadStatsDayFormat is the format of the days of the download statistics, in UTC.
+const DownloadStatsDayFormat = "2006-01-02"
+
+// DownloadStatsHours is the number of hourly counts of the days of the download statistics.
+const DownloadStatsHours = 24
+
+// DownloadRecord is a pull of an image manifest.
+type DownloadRecord struct {
+	Repo      string
+	Reference string // the tag or digest used to pull the image
+	Digest    string
+	Client    string // an opaque identity of the client, used to count unique clients
+	Timestamp time.Time
+}
+
+// DailyDownloads are the pulls of the images of a repo during a day.
+type DailyDownloads struct {
+	Repo string
+	Day  string
+	// the pulls of each manifest, by tag or by digest
+	Digests map[string]ReferenceDownloads
+	// the pulls by tag
+	Tags map[string]ReferenceDownloads
+	// the pulls of each hour of the day in UTC, nil once the day is rolled up
+	Hours []int
+}
+
+type ReferenceDownloads struct {
+	Count      int
+	Clients    []string
+	LastPulled time.Time
+}
+
 type DescriptorStatistics struct {
-	DownloadCount int
+	DownloadCount     int
+	LastPullTimestamp time.Time
 }
 
 type ManifestSignatures map[string][]SignatureInfo
@@ -212,14 +310,25 @@ type RepoMetadata struct {
 	Tags map[string]Descriptor
 
 	Statistics map[string]DescriptorStatistics
-	Signatures map[string]ManifestSignatures
-	Referrers  map[string][]ReferrerInfo
+	// the statistics of the pulls by tag, the ones of the manifests are in Statistics
+	TagStatistics map[string]DescriptorStatistics
+	Signatures    map[string]ManifestSignatures
+	Referrers     map[string][]ReferrerInfo
 
 	IsStarred    bool
 	IsBookmarked bool
 	Rank         int
 
 	Stars int
+
+	Readme *RepoReadme
+}
+
+// RepoReadme is the markdown documentation of a repo, set by the users and not part of the images.
+type RepoReadme struct {
+	Content     string
+	LastUpdated time.Time
+	UpdatedBy   string
 }
 
 type LayerInfo struct {
@@ -228,6 +337,10 @@ type LayerInfo struct {
 	SignatureKey string
 	Signer       string
 	Date         time.Time
+	// the certificate, its chain and the Rekor bundle of the cosign keyless signatures
+	Certificate      string
+	CertificateChain string
+	Bundle           string
 }
 
 type SignatureInfo struct {
@@ -246,6 +359,15 @@ type UserData struct {
 	BookmarkedRepos []string
 	Groups          []string
 	APIKeys         map[string]APIKeyDetails
+	BookmarkLists   map[string]BookmarkList
+}
+
+// BookmarkList is a named list of repos, shared read-only with the users of some groups.
+type BookmarkList struct {
+	Name       string
+	Owner      string
+	Repos      []string
+	SharedWith []string // groups whose users can read the list
 }
 
 type Filter struct {
@@ -266,6 +388,26 @@ type FilterData struct {
 	IsBookmarked  bool
 }
 
+// the actions of the activity history of the users.
+const (
+	UserActivityPush         = "push"
+	UserActivityDelete       = "delete"
+	UserActivityStar         = "star"
+	UserActivityUnstar       = "unstar"
+	UserActivityAPIKeyCreate = "apikey.create"
+	UserActivityAPIKeyRevoke = "apikey.revoke"
+)
+
+// UserActivity is an action of an authenticated user.
+type UserActivity struct {
+	User      string    `json:"user"`
+	Action    string    `json:"action"`
+	Repo      string    `json:"repo,omitempty"`
+	Reference string    `json:"reference,omitempty"` // the tag or digest pushed or deleted
+	Details   string    `json:"details,omitempty"`   // e.g. the label of the api key
+	Timestamp time.Time `json:"timestamp"`
+}
+
 type APIKeyDetails struct {
 	CreatedAt      time.Time `json:"createdAt"`
 	ExpirationDate time.Time `json:"expirationDate"`
@@ -1,7 +1,10 @@
 package version
 
 import (
+	"database/sql"
+
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	goredis "github.com/go-redis/redis/v8"
 	"go.etcd.io/bbolt"
 )
 
@@ -12,3 +15,11 @@ func GetBoltDBPatches() []func(DB *bbolt.DB) error {
 func GetDynamoDBPatches() []func(client *dynamodb.Client, tableNames map[string]string) error {
 	return []func(client *dynamodb.Client, tableNames map[string]string) error{}
 }
+
+func GetPostgresPatches() []func(DB *sql.DB, tablePrefix string) error {
+	return []func(DB *sql.DB, tablePrefix string) error{}
+}
+
+func GetRedisPatches() []func(client goredis.UniversalClient, keyPrefix string) error {
+	return []func(client goredis.UniversalClient, keyPrefix string) error{}
+}
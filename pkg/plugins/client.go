@@ -0,0 +1,213 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+const (
+	DefaultStartTimeout = 10 * time.Second
+	// how long the plugin has to stop after being interrupted before it's killed.
+	stopTimeout = 5 * time.Second
+	// core protocol version|protocol version|network|address|protocol.
+	handshakeParts = 5
+)
+
+// Client starts a plugin process and calls it, it implements the Plugin interface.
+type Client struct {
+	name   string
+	cmd    *exec.Cmd
+	conn   *grpc.ClientConn
+	exited chan struct{}
+	log    log.Logger
+}
+
+// NewClient starts the plugin and connects to it once it completes the handshake.
+func NewClient(config extconf.PluginConfig, log log.Logger) (*Client, error) {
+	startTimeout := config.StartTimeout
+	if startTimeout <= 0 {
+		startTimeout = DefaultStartTimeout
+	}
+
+	cmd := exec.Command(config.Path, config.Args...) //nolint: gosec // the plugins are configured by the admin
+
+	cmd.Env = append(os.Environ(), MagicCookieKey+"="+MagicCookieValue)
+	for name, value := range config.Env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+
+	stdout, stdoutWriter := io.Pipe()
+	stderr, stderrWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		name:   config.Name,
+		cmd:    cmd,
+		exited: make(chan struct{}),
+		log:    log,
+	}
+
+	go func() {
+		_ = cmd.Wait()
+
+		stdoutWriter.Close()
+		stderrWriter.Close()
+		close(client.exited)
+	}()
+
+	go client.logOutput(bufio.NewScanner(stderr))
+
+	handshake := make(chan string, 1)
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		if scanner.Scan() {
+			handshake <- scanner.Text()
+		}
+
+		client.logOutput(scanner)
+	}()
+
+	var line string
+
+	select {
+	case line = <-handshake:
+	case <-client.exited:
+		return nil, fmt.Errorf("%w: %s exited before completing the handshake", zerr.ErrPluginExited, config.Name)
+	case <-time.After(startTimeout):
+		client.kill()
+
+		return nil, fmt.Errorf("%w: %s didn't complete the handshake in %s", zerr.ErrPluginStartTimeout,
+			config.Name, startTimeout)
+	}
+
+	address, err := parseHandshake(line)
+	if err != nil {
+		client.kill()
+
+		return nil, err
+	}
+
+	client.conn, err = grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})))
+	if err != nil {
+		client.kill()
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// parseHandshake returns the address of the gRPC server of the plugin.
+func parseHandshake(line string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != handshakeParts {
+		return "", fmt.Errorf("%w: %q", zerr.ErrPluginHandshake, line)
+	}
+
+	if parts[0] != CoreProtocolVersion || parts[1] != ProtocolVersion {
+		return "", fmt.Errorf("%w: unsupported protocol version %s|%s, expected %s|%s", zerr.ErrPluginHandshake,
+			parts[0], parts[1], CoreProtocolVersion, ProtocolVersion)
+	}
+
+	if parts[4] != "grpc" {
+		return "", fmt.Errorf("%w: unsupported protocol %s, expected grpc", zerr.ErrPluginHandshake, parts[4])
+	}
+
+	switch parts[2] {
+	case "tcp":
+		return parts[3], nil
+	case "unix":
+		return "unix://" + parts[3], nil
+	default:
+		return "", fmt.Errorf("%w: unsupported network %s", zerr.ErrPluginHandshake, parts[2])
+	}
+}
+
+func (client *Client) logOutput(scanner *bufio.Scanner) {
+	for scanner.Scan() {
+		client.log.Info().Str("plugin", client.name).Str("output", scanner.Text()).Msg("plugins: plugin output")
+	}
+}
+
+func (client *Client) Name() string {
+	return client.name
+}
+
+func (client *Client) Register(ctx context.Context) (*Registration, error) {
+	registration := &Registration{}
+
+	if err := client.conn.Invoke(ctx, fullMethodName("Register"), &Empty{}, registration); err != nil {
+		return nil, err
+	}
+
+	return registration, nil
+}
+
+func (client *Client) HandleHTTP(ctx context.Context, request *HTTPRequest) (*HTTPResponse, error) {
+	response := &HTTPResponse{}
+
+	if err := client.conn.Invoke(ctx, fullMethodName("HandleHTTP"), request, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (client *Client) OnImagePushed(ctx context.Context, event *ImageEvent) error {
+	return client.conn.Invoke(ctx, fullMethodName("OnImagePushed"), event, &Empty{})
+}
+
+func (client *Client) OnImageDeleted(ctx context.Context, event *ImageEvent) error {
+	return client.conn.Invoke(ctx, fullMethodName("OnImageDeleted"), event, &Empty{})
+}
+
+func (client *Client) RunTask(ctx context.Context, request *TaskRequest) error {
+	return client.conn.Invoke(ctx, fullMethodName("RunTask"), request, &Empty{})
+}
+
+// Close interrupts the plugin and kills it if it doesn't stop in time.
+func (client *Client) Close() {
+	if client.conn != nil {
+		client.conn.Close()
+	}
+
+	if err := client.cmd.Process.Signal(os.Interrupt); err != nil {
+		client.kill()
+
+		return
+	}
+
+	select {
+	case <-client.exited:
+	case <-time.After(stopTimeout):
+		client.log.Warn().Str("plugin", client.name).Msg("plugins: plugin didn't stop in time, killing it")
+
+		client.kill()
+	}
+}
+
+func (client *Client) kill() {
+	_ = client.cmd.Process.Kill()
+
+	<-client.exited
+}
@@ -0,0 +1,151 @@
+package plugins_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/plugins"
+)
+
+const helperModeEnv = "ZOT_TEST_PLUGIN_MODE"
+
+var errTaskFailed = errors.New("task failed")
+
+type echoPlugin struct {
+	plugins.UnimplementedPlugin
+}
+
+func (echoPlugin) Register(ctx context.Context) (*plugins.Registration, error) {
+	return &plugins.Registration{
+		Routes: []plugins.Route{{Path: "/echo", Methods: []string{http.MethodPost}}},
+		Hooks:  []string{plugins.PushHook},
+		Tasks:  []plugins.Task{{Name: "report", Interval: "1h"}},
+	}, nil
+}
+
+func (echoPlugin) HandleHTTP(ctx context.Context, request *plugins.HTTPRequest) (*plugins.HTTPResponse, error) {
+	return &plugins.HTTPResponse{
+		Status:  http.StatusCreated,
+		Headers: map[string][]string{"X-User": {request.User}},
+		Body:    []byte(request.Method + " " + request.Path + "?" + request.Query + " " + string(request.Body)),
+	}, nil
+}
+
+func (echoPlugin) OnImagePushed(ctx context.Context, event *plugins.ImageEvent) error {
+	return nil
+}
+
+func (echoPlugin) RunTask(ctx context.Context, request *plugins.TaskRequest) error {
+	return fmt.Errorf("%w: %s", errTaskFailed, request.Name)
+}
+
+// TestHelperPlugin is the plugin process started by the tests, it's skipped when the test binary isn't a plugin.
+func TestHelperPlugin(t *testing.T) {
+	switch os.Getenv(helperModeEnv) {
+	case "serve":
+		if err := plugins.Serve(echoPlugin{}); err != nil {
+			os.Exit(1)
+		}
+	case "storage":
+		if err := plugins.ServeStorageDriver(newStorageDriver); err != nil {
+			os.Exit(1)
+		}
+	case "badHandshake":
+		fmt.Fprintln(os.Stdout, "1|2|tcp|127.0.0.1:1|grpc")
+		time.Sleep(time.Minute)
+	case "exit":
+		fmt.Fprintln(os.Stderr, "unable to start")
+	case "hang":
+		time.Sleep(time.Minute)
+	default:
+		return
+	}
+
+	os.Exit(0)
+}
+
+func getHelperConfig(mode string) extconf.PluginConfig {
+	return extconf.PluginConfig{
+		Name:         "test",
+		Path:         os.Args[0],
+		Args:         []string{"-test.run=^TestHelperPlugin$"},
+		Env:          map[string]string{helperModeEnv: mode},
+		StartTimeout: 10 * time.Second,
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	logger := log.NewLogger("debug", "")
+
+	Convey("A plugin serves the requests of zot", t, func() {
+		client, err := plugins.NewClient(getHelperConfig("serve"), logger)
+		So(err, ShouldBeNil)
+		So(client.Name(), ShouldEqual, "test")
+
+		defer client.Close()
+
+		ctx := context.Background()
+
+		registration, err := client.Register(ctx)
+		So(err, ShouldBeNil)
+		So(registration.Routes, ShouldHaveLength, 1)
+		So(registration.Routes[0].Path, ShouldEqual, "/echo")
+		So(registration.Hooks, ShouldResemble, []string{plugins.PushHook})
+		So(registration.Tasks, ShouldResemble, []plugins.Task{{Name: "report", Interval: "1h"}})
+
+		response, err := client.HandleHTTP(ctx, &plugins.HTTPRequest{
+			Method: http.MethodPost, Path: "/echo", Query: "a=b", Body: []byte("body"), User: "alice",
+		})
+		So(err, ShouldBeNil)
+		So(response.Status, ShouldEqual, http.StatusCreated)
+		So(response.Headers["X-User"], ShouldResemble, []string{"alice"})
+		So(string(response.Body), ShouldEqual, "POST /echo?a=b body")
+
+		err = client.OnImagePushed(ctx, &plugins.ImageEvent{Repo: "repo", Reference: "tag"})
+		So(err, ShouldBeNil)
+
+		err = client.OnImageDeleted(ctx, &plugins.ImageEvent{Repo: "repo", Reference: "tag"})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, zerr.ErrPluginNotImplemented.Error())
+
+		err = client.RunTask(ctx, &plugins.TaskRequest{Name: "report"})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "task failed: report")
+	})
+
+	Convey("Plugins failing to start", t, func() {
+		_, err := plugins.NewClient(getHelperConfig("badHandshake"), logger)
+		So(errors.Is(err, zerr.ErrPluginHandshake), ShouldBeTrue)
+
+		_, err = plugins.NewClient(getHelperConfig("exit"), logger)
+		So(errors.Is(err, zerr.ErrPluginExited), ShouldBeTrue)
+
+		config := getHelperConfig("hang")
+		config.StartTimeout = time.Second
+
+		_, err = plugins.NewClient(config, logger)
+		So(errors.Is(err, zerr.ErrPluginStartTimeout), ShouldBeTrue)
+
+		config.Path = "/nonexistent/plugin"
+
+		_, err = plugins.NewClient(config, logger)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Plugins can only be launched by zot", t, func() {
+		So(os.Getenv(plugins.MagicCookieKey), ShouldBeEmpty)
+
+		err := plugins.Serve(echoPlugin{})
+		So(errors.Is(err, zerr.ErrPluginNotLaunchedByZot), ShouldBeTrue)
+	})
+}
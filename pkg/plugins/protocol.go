@@ -0,0 +1,164 @@
+/*
+Package plugins defines the protocol of the zot plugins, out-of-tree extensions running as separate processes,
+e.g. custom scanners or billing hooks, which don't require building zot from source.
+
+zot starts every configured plugin and the plugin prints, as its first line on stdout, the address of the gRPC server
+it listens on, in the handshake format of hashicorp/go-plugin:
+
+	1|1|tcp|127.0.0.1:41234|grpc
+
+zot then calls Register to know which routes, hooks and scheduled tasks the plugin implements.
+The messages of the zot.plugin.v1.Plugin service are encoded as JSON, so that plugins can be written in any language
+without the protobuf definitions, plugins written in Go only have to implement the Plugin interface and call Serve.
+*/
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// MagicCookieKey and MagicCookieValue are set in the environment of the plugins, a basic check that
+	// the plugin is launched by zot and not executed directly.
+	MagicCookieKey   = "ZOT_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "a3f1c5e0d4b2e8f79c61b0d2e5a4c3f8"
+
+	// CoreProtocolVersion is the version of the handshake, ProtocolVersion the version of the plugin service.
+	CoreProtocolVersion = "1"
+	ProtocolVersion     = "1"
+
+	ServiceName = "zot.plugin.v1.Plugin"
+	codecName   = "json"
+)
+
+// the hooks a plugin can register, called after the images are pushed or deleted.
+const (
+	PushHook   = "push"
+	DeleteHook = "delete"
+)
+
+type Empty struct{}
+
+// Registration lists the extension points implemented by a plugin.
+type Registration struct {
+	Routes []Route  `json:"routes,omitempty"`
+	Hooks  []string `json:"hooks,omitempty"`
+	Tasks  []Task   `json:"tasks,omitempty"`
+}
+
+// Route is served under /v2/_zot/ext/plugins/<plugin name>, the path can have gorilla/mux variables, e.g. "/scans/{id}".
+type Route struct {
+	Path      string   `json:"path"`
+	Methods   []string `json:"methods,omitempty"` // GET by default
+	AdminOnly bool     `json:"adminOnly,omitempty"`
+}
+
+// Task is run periodically by the zot scheduler.
+type Task struct {
+	Name     string `json:"name"`
+	Interval string `json:"interval"` // Go duration, e.g. "1h"
+}
+
+// HTTPRequest is a request to one of the routes of the plugin, without the credentials of the user.
+type HTTPRequest struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"` // relative to the prefix of the plugin
+	Query   string              `json:"query,omitempty"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    []byte              `json:"body,omitempty"`
+	User    string              `json:"user,omitempty"` // empty for anonymous requests
+	Groups  []string            `json:"groups,omitempty"`
+}
+
+type HTTPResponse struct {
+	Status  int                 `json:"status"` // 200 by default
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    []byte              `json:"body,omitempty"`
+}
+
+// ImageEvent is passed to the push and delete hooks.
+type ImageEvent struct {
+	Repo      string `json:"repo"`
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType"`
+	Manifest  []byte `json:"manifest,omitempty"` // only for pushes
+	User      string `json:"user,omitempty"`
+}
+
+type TaskRequest struct {
+	Name string `json:"name"`
+}
+
+// jsonCodec replaces the protobuf codec of gRPC.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func fullMethodName(method string) string {
+	return "/" + ServiceName + "/" + method
+}
+
+var serviceDesc = grpc.ServiceDesc{ //nolint: gochecknoglobals
+	ServiceName: ServiceName,
+	HandlerType: (*Plugin)(nil),
+	Methods: []grpc.MethodDesc{
+		newMethodDesc("Register", func(ctx context.Context, plugin Plugin, _ *Empty) (interface{}, error) {
+			return plugin.Register(ctx)
+		}),
+		newMethodDesc("HandleHTTP", func(ctx context.Context, plugin Plugin, request *HTTPRequest) (interface{}, error) {
+			return plugin.HandleHTTP(ctx, request)
+		}),
+		newMethodDesc("OnImagePushed", func(ctx context.Context, plugin Plugin, event *ImageEvent) (interface{}, error) {
+			return &Empty{}, plugin.OnImagePushed(ctx, event)
+		}),
+		newMethodDesc("OnImageDeleted", func(ctx context.Context, plugin Plugin, event *ImageEvent) (interface{}, error) {
+			return &Empty{}, plugin.OnImageDeleted(ctx, event)
+		}),
+		newMethodDesc("RunTask", func(ctx context.Context, plugin Plugin, request *TaskRequest) (interface{}, error) {
+			return &Empty{}, plugin.RunTask(ctx, request)
+		}),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "zot/plugin/v1",
+}
+
+func newMethodDesc[Request any](method string,
+	call func(ctx context.Context, plugin Plugin, request *Request) (interface{}, error),
+) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			request := new(Request)
+
+			if err := dec(request); err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return call(ctx, srv.(Plugin), request) //nolint: forcetypeassert
+			}
+
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethodName(method)}
+
+			return interceptor(ctx, request, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(ctx, srv.(Plugin), req.(*Request)) //nolint: forcetypeassert
+			})
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	zerr "zotregistry.io/zot/errors"
+)
+
+// Plugin is implemented by the plugins, the errors returned to zot are logged.
+type Plugin interface {
+	// Register returns the routes, hooks and tasks of the plugin, it's called once after the plugin is started.
+	Register(ctx context.Context) (*Registration, error)
+	HandleHTTP(ctx context.Context, request *HTTPRequest) (*HTTPResponse, error)
+	OnImagePushed(ctx context.Context, event *ImageEvent) error
+	OnImageDeleted(ctx context.Context, event *ImageEvent) error
+	RunTask(ctx context.Context, request *TaskRequest) error
+}
+
+// UnimplementedPlugin can be embedded by the plugins implementing only some of the extension points.
+type UnimplementedPlugin struct{}
+
+func (UnimplementedPlugin) Register(ctx context.Context) (*Registration, error) {
+	return &Registration{}, nil
+}
+
+func (UnimplementedPlugin) HandleHTTP(ctx context.Context, request *HTTPRequest) (*HTTPResponse, error) {
+	return nil, zerr.ErrPluginNotImplemented
+}
+
+func (UnimplementedPlugin) OnImagePushed(ctx context.Context, event *ImageEvent) error {
+	return zerr.ErrPluginNotImplemented
+}
+
+func (UnimplementedPlugin) OnImageDeleted(ctx context.Context, event *ImageEvent) error {
+	return zerr.ErrPluginNotImplemented
+}
+
+func (UnimplementedPlugin) RunTask(ctx context.Context, request *TaskRequest) error {
+	return zerr.ErrPluginNotImplemented
+}
+
+// Serve serves the plugin until zot stops it, it's called by the main function of the plugins.
+func Serve(plugin Plugin) error {
+	return serve(&serviceDesc, plugin)
+}
+
+// serve starts the gRPC server of a plugin and prints the handshake.
+func serve(desc *grpc.ServiceDesc, impl interface{}, options ...grpc.ServerOption) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return zerr.ErrPluginNotLaunchedByZot
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(append(options, grpc.ForceServerCodec(jsonCodec{}))...)
+	server.RegisterService(desc, impl)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+
+	go func() {
+		if _, ok := <-signals; ok {
+			server.GracefulStop()
+		}
+	}()
+
+	// the handshake tells zot where to connect, the rest of the output of the plugin is logged
+	fmt.Fprintf(os.Stdout, "%s|%s|%s|%s|grpc\n", CoreProtocolVersion, ProtocolVersion,
+		listener.Addr().Network(), listener.Addr().String())
+
+	return server.Serve(listener)
+}
@@ -0,0 +1,443 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	zerr "zotregistry.io/zot/errors"
+)
+
+const (
+	// StorageServiceName is the service of the storage driver plugins, which store the blobs and the metadata
+	// of the repositories in an out-of-tree backend, e.g. Ceph RADOS or Swift.
+	StorageServiceName = "zot.storage.v1.Driver"
+
+	// the blobs and the walked directories are larger than the default limit of gRPC.
+	maxStorageMessageSize = math.MaxInt32
+	// the size of the chunks of the blobs streamed by the plugin.
+	readChunkSize = 1024 * 1024
+)
+
+// StorageDriverFactory creates the storage driver of a plugin from the parameters of the zot storage driver config.
+type StorageDriverFactory func(parameters map[string]interface{}) (storagedriver.StorageDriver, error)
+
+// StorageConfigureRequest has the parameters of the storage driver, e.g. the address of the backend and
+// the credentials, it's sent once after the plugin is started.
+type StorageConfigureRequest struct {
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type PathRequest struct {
+	Path string `json:"path"`
+}
+
+type ContentRequest struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content,omitempty"`
+}
+
+type ContentResponse struct {
+	Content []byte `json:"content,omitempty"`
+}
+
+// ReaderRequest streams the content of a file from the offset, in chunks.
+type ReaderRequest struct {
+	Path   string `json:"path"`
+	Offset int64  `json:"offset,omitempty"`
+}
+
+// WriterRequest opens a writer, its content is written with Write and committed, cancelled or closed
+// with the id of the writer.
+type WriterRequest struct {
+	Path   string `json:"path"`
+	Append bool   `json:"append,omitempty"`
+}
+
+type WriteRequest struct {
+	ID      string `json:"id"`
+	Content []byte `json:"content,omitempty"`
+}
+
+type WriterResponse struct {
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+}
+
+type FileInfo struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	IsDir   bool      `json:"isDir,omitempty"`
+}
+
+type ListResponse struct {
+	Paths []string `json:"paths,omitempty"`
+}
+
+type MoveRequest struct {
+	SourcePath string `json:"sourcePath"`
+	DestPath   string `json:"destPath"`
+}
+
+// ServeStorageDriver serves the storage driver created by the factory until zot stops it, it's called by
+// the main function of the storage driver plugins.
+func ServeStorageDriver(factory StorageDriverFactory) error {
+	return serve(&storageServiceDesc, &storageServer{factory: factory, writers: map[string]storagedriver.FileWriter{}},
+		grpc.MaxRecvMsgSize(maxStorageMessageSize), grpc.MaxSendMsgSize(maxStorageMessageSize))
+}
+
+// storageServer calls the storage driver of the plugin, the errors of the driver are returned with
+// the gRPC status codes known by the client.
+type storageServer struct {
+	factory StorageDriverFactory
+
+	driverLock sync.RWMutex
+	driver     storagedriver.StorageDriver
+
+	writersLock sync.Mutex
+	writers     map[string]storagedriver.FileWriter
+	lastWriter  int
+}
+
+func (server *storageServer) configure(parameters map[string]interface{}) error {
+	driver, err := server.factory(parameters)
+	if err != nil {
+		return err
+	}
+
+	server.driverLock.Lock()
+	defer server.driverLock.Unlock()
+
+	server.driver = driver
+
+	return nil
+}
+
+func (server *storageServer) getDriver() (storagedriver.StorageDriver, error) {
+	server.driverLock.RLock()
+	defer server.driverLock.RUnlock()
+
+	if server.driver == nil {
+		return nil, status.Error(codes.FailedPrecondition, zerr.ErrPluginNotConfigured.Error())
+	}
+
+	return server.driver, nil
+}
+
+func (server *storageServer) addWriter(writer storagedriver.FileWriter) string {
+	server.writersLock.Lock()
+	defer server.writersLock.Unlock()
+
+	server.lastWriter++
+	id := strconv.Itoa(server.lastWriter)
+	server.writers[id] = writer
+
+	return id
+}
+
+func (server *storageServer) getWriter(id string, remove bool) (storagedriver.FileWriter, error) {
+	server.writersLock.Lock()
+	defer server.writersLock.Unlock()
+
+	writer, ok := server.writers[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s: writer %s", zerr.ErrPluginWriterNotFound, id)
+	}
+
+	if remove {
+		delete(server.writers, id)
+	}
+
+	return writer, nil
+}
+
+// storageStatus converts the errors of the storage driver to the status codes known by the client.
+func storageStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		pathNotFoundErr  storagedriver.PathNotFoundError
+		invalidPathErr   storagedriver.InvalidPathError
+		invalidOffsetErr storagedriver.InvalidOffsetError
+	)
+
+	switch {
+	case errors.As(err, &pathNotFoundErr):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &invalidPathErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &invalidOffsetErr):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
+
+var storageServiceDesc = grpc.ServiceDesc{ //nolint: gochecknoglobals
+	ServiceName: StorageServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		newStorageMethodDesc("Configure", func(ctx context.Context, server *storageServer,
+			request *StorageConfigureRequest,
+		) (interface{}, error) {
+			return &Empty{}, server.configure(request.Parameters)
+		}),
+		newStorageMethodDesc("GetContent", func(ctx context.Context, server *storageServer,
+			request *PathRequest,
+		) (interface{}, error) {
+			driver, err := server.getDriver()
+			if err != nil {
+				return nil, err
+			}
+
+			content, err := driver.GetContent(ctx, request.Path)
+
+			return &ContentResponse{Content: content}, err
+		}),
+		newStorageMethodDesc("PutContent", func(ctx context.Context, server *storageServer,
+			request *ContentRequest,
+		) (interface{}, error) {
+			driver, err := server.getDriver()
+			if err != nil {
+				return nil, err
+			}
+
+			return &Empty{}, driver.PutContent(ctx, request.Path, request.Content)
+		}),
+		newStorageMethodDesc("Writer", func(ctx context.Context, server *storageServer,
+			request *WriterRequest,
+		) (interface{}, error) {
+			driver, err := server.getDriver()
+			if err != nil {
+				return nil, err
+			}
+
+			// the writer outlives the request
+			writer, err := driver.Writer(context.Background(), request.Path, request.Append) //nolint: contextcheck
+			if err != nil {
+				return nil, err
+			}
+
+			return &WriterResponse{ID: server.addWriter(writer), Size: writer.Size()}, nil
+		}),
+		newStorageMethodDesc("Write", func(ctx context.Context, server *storageServer,
+			request *WriteRequest,
+		) (interface{}, error) {
+			writer, err := server.getWriter(request.ID, false)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = writer.Write(request.Content)
+
+			return &WriterResponse{ID: request.ID, Size: writer.Size()}, err
+		}),
+		newStorageMethodDesc("Commit", func(ctx context.Context, server *storageServer,
+			request *WriterResponse,
+		) (interface{}, error) {
+			writer, err := server.getWriter(request.ID, false)
+			if err != nil {
+				return nil, err
+			}
+
+			return &WriterResponse{ID: request.ID, Size: writer.Size()}, writer.Commit()
+		}),
+		newStorageMethodDesc("Cancel", func(ctx context.Context, server *storageServer,
+			request *WriterResponse,
+		) (interface{}, error) {
+			writer, err := server.getWriter(request.ID, false)
+			if err != nil {
+				return nil, err
+			}
+
+			return &WriterResponse{ID: request.ID, Size: writer.Size()}, writer.Cancel()
+		}),
+		newStorageMethodDesc("Close", func(ctx context.Context, server *storageServer,
+			request *WriterResponse,
+		) (interface{}, error) {
+			writer, err := server.getWriter(request.ID, true)
+			if err != nil {
+				return nil, err
+			}
+
+			return &WriterResponse{ID: request.ID, Size: writer.Size()}, writer.Close()
+		}),
+		newStorageMethodDesc("Stat", func(ctx context.Context, server *storageServer,
+			request *PathRequest,
+		) (interface{}, error) {
+			driver, err := server.getDriver()
+			if err != nil {
+				return nil, err
+			}
+
+			fileInfo, err := driver.Stat(ctx, request.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			return newFileInfo(fileInfo), nil
+		}),
+		newStorageMethodDesc("List", func(ctx context.Context, server *storageServer,
+			request *PathRequest,
+		) (interface{}, error) {
+			driver, err := server.getDriver()
+			if err != nil {
+				return nil, err
+			}
+
+			paths, err := driver.List(ctx, request.Path)
+
+			return &ListResponse{Paths: paths}, err
+		}),
+		newStorageMethodDesc("Move", func(ctx context.Context, server *storageServer,
+			request *MoveRequest,
+		) (interface{}, error) {
+			driver, err := server.getDriver()
+			if err != nil {
+				return nil, err
+			}
+
+			return &Empty{}, driver.Move(ctx, request.SourcePath, request.DestPath)
+		}),
+		newStorageMethodDesc("Delete", func(ctx context.Context, server *storageServer,
+			request *PathRequest,
+		) (interface{}, error) {
+			driver, err := server.getDriver()
+			if err != nil {
+				return nil, err
+			}
+
+			return &Empty{}, driver.Delete(ctx, request.Path)
+		}),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			// Reader streams the content of the file, the stream ends at the end of the file.
+			StreamName:    "Reader",
+			ServerStreams: true,
+			Handler: newStorageStreamHandler(func(stream grpc.ServerStream, driver storagedriver.StorageDriver,
+				request *ReaderRequest,
+			) error {
+				reader, err := driver.Reader(stream.Context(), request.Path, request.Offset)
+				if err != nil {
+					return err
+				}
+
+				defer reader.Close()
+
+				buf := make([]byte, readChunkSize)
+
+				for {
+					n, err := io.ReadFull(reader, buf)
+					if n > 0 {
+						if err := stream.SendMsg(&ContentResponse{Content: buf[:n]}); err != nil {
+							return err
+						}
+					}
+
+					if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+						return nil
+					}
+
+					if err != nil {
+						return err
+					}
+				}
+			}),
+		},
+		{
+			// Walk streams the files and the directories under the path, in the order of the walk of the driver.
+			StreamName:    "Walk",
+			ServerStreams: true,
+			Handler: newStorageStreamHandler(func(stream grpc.ServerStream, driver storagedriver.StorageDriver,
+				request *PathRequest,
+			) error {
+				return driver.Walk(stream.Context(), request.Path, func(fileInfo storagedriver.FileInfo) error {
+					return stream.SendMsg(newFileInfo(fileInfo))
+				})
+			}),
+		},
+	},
+	Metadata: "zot/storage/v1",
+}
+
+func newFileInfo(fileInfo storagedriver.FileInfo) *FileInfo {
+	return &FileInfo{
+		Path:    fileInfo.Path(),
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime(),
+		IsDir:   fileInfo.IsDir(),
+	}
+}
+
+func newStorageMethodDesc[Request any](method string,
+	call func(ctx context.Context, server *storageServer, request *Request) (interface{}, error),
+) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			request := new(Request)
+
+			if err := dec(request); err != nil {
+				return nil, err
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				response, err := call(ctx, srv.(*storageServer), req.(*Request)) //nolint: forcetypeassert
+
+				return response, storageStatus(err)
+			}
+
+			if interceptor == nil {
+				return handler(ctx, request)
+			}
+
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: storageMethodName(method)}
+
+			return interceptor(ctx, request, info, handler)
+		},
+	}
+}
+
+func newStorageStreamHandler[Request any](call func(stream grpc.ServerStream, driver storagedriver.StorageDriver,
+	request *Request) error,
+) grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		driver, err := srv.(*storageServer).getDriver() //nolint: forcetypeassert
+		if err != nil {
+			return err
+		}
+
+		request := new(Request)
+
+		if err := stream.RecvMsg(request); err != nil {
+			return err
+		}
+
+		return storageStatus(call(stream, driver, request))
+	}
+}
+
+func storageMethodName(method string) string {
+	return "/" + StorageServiceName + "/" + method
+}
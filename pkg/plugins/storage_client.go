@@ -0,0 +1,396 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	extconf "zotregistry.io/zot/pkg/extensions/config"
+	"zotregistry.io/zot/pkg/log"
+)
+
+// the content written to the plugin is sent in chunks of this size.
+const writeChunkSize = 4 * 1024 * 1024
+
+// the storage driver plugins started by zot, they're stopped when zot exits.
+var (
+	storageDriversLock sync.Mutex             //nolint: gochecknoglobals
+	storageDrivers     []*StorageDriverClient //nolint: gochecknoglobals
+)
+
+// StorageDriverClient starts a storage driver plugin and calls it, it implements the storage driver interface of
+// docker/distribution, the image store uses it like the s3 and azure drivers.
+type StorageDriverClient struct {
+	client *Client
+}
+
+// NewStorageDriverClient starts the plugin and configures its storage driver with the parameters.
+func NewStorageDriverClient(config extconf.PluginConfig, parameters map[string]interface{}, log log.Logger,
+) (*StorageDriverClient, error) {
+	client, err := NewClient(config, log)
+	if err != nil {
+		return nil, err
+	}
+
+	driver := &StorageDriverClient{client: client}
+
+	startTimeout := config.StartTimeout
+	if startTimeout <= 0 {
+		startTimeout = DefaultStartTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	if err := driver.invoke(ctx, "Configure", &StorageConfigureRequest{Parameters: parameters}, &Empty{}); err != nil {
+		client.Close()
+
+		return nil, err
+	}
+
+	storageDriversLock.Lock()
+	defer storageDriversLock.Unlock()
+
+	storageDrivers = append(storageDrivers, driver)
+
+	return driver, nil
+}
+
+// StopStorageDrivers stops the storage driver plugins started by zot, it's called when zot exits.
+func StopStorageDrivers() {
+	storageDriversLock.Lock()
+	defer storageDriversLock.Unlock()
+
+	for _, driver := range storageDrivers {
+		driver.client.Close()
+	}
+
+	storageDrivers = nil
+}
+
+func (driver *StorageDriverClient) invoke(ctx context.Context, method string, request, response interface{}) error {
+	return driver.client.conn.Invoke(ctx, storageMethodName(method), request, response,
+		grpc.MaxCallRecvMsgSize(maxStorageMessageSize), grpc.MaxCallSendMsgSize(maxStorageMessageSize))
+}
+
+// newStream sends the request of a server streaming method, the stream is read until io.EOF.
+func (driver *StorageDriverClient) newStream(ctx context.Context, desc *grpc.StreamDesc, request interface{},
+) (grpc.ClientStream, error) {
+	stream, err := driver.client.conn.NewStream(ctx, desc, storageMethodName(desc.StreamName),
+		grpc.MaxCallRecvMsgSize(maxStorageMessageSize), grpc.MaxCallSendMsgSize(maxStorageMessageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.SendMsg(request); err != nil {
+		return nil, err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// driverError converts the status codes returned by the plugin to the errors of the storage drivers.
+func (driver *StorageDriverClient) driverError(err error, path string, offset int64) error {
+	if err == nil {
+		return nil
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return storagedriver.PathNotFoundError{Path: path, DriverName: driver.Name()}
+	case codes.InvalidArgument:
+		return storagedriver.InvalidPathError{Path: path, DriverName: driver.Name()}
+	case codes.OutOfRange:
+		return storagedriver.InvalidOffsetError{Path: path, Offset: offset, DriverName: driver.Name()}
+	default:
+		return storagedriver.Error{DriverName: driver.Name(), Enclosed: err}
+	}
+}
+
+// Name returns the name of the plugin.
+func (driver *StorageDriverClient) Name() string {
+	return driver.client.Name()
+}
+
+func (driver *StorageDriverClient) GetContent(ctx context.Context, path string) ([]byte, error) {
+	response := &ContentResponse{}
+
+	if err := driver.invoke(ctx, "GetContent", &PathRequest{Path: path}, response); err != nil {
+		return nil, driver.driverError(err, path, 0)
+	}
+
+	return response.Content, nil
+}
+
+func (driver *StorageDriverClient) PutContent(ctx context.Context, path string, content []byte) error {
+	err := driver.invoke(ctx, "PutContent", &ContentRequest{Path: path, Content: content}, &Empty{})
+
+	return driver.driverError(err, path, 0)
+}
+
+func (driver *StorageDriverClient) Reader(ctx context.Context, path string, offset int64) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := driver.newStream(ctx, &storageServiceDesc.Streams[0], &ReaderRequest{Path: path, Offset: offset})
+	if err != nil {
+		cancel()
+
+		return nil, driver.driverError(err, path, offset)
+	}
+
+	reader := &storageReader{driver: driver, stream: stream, cancel: cancel, path: path, offset: offset}
+
+	// the errors of the driver, e.g. a missing file, are returned before the first chunk
+	if err := reader.next(); err != nil && !errors.Is(err, io.EOF) {
+		cancel()
+
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func (driver *StorageDriverClient) Writer(ctx context.Context, path string, append bool, //nolint: predeclared
+) (storagedriver.FileWriter, error) {
+	response := &WriterResponse{}
+
+	if err := driver.invoke(ctx, "Writer", &WriterRequest{Path: path, Append: append}, response); err != nil {
+		return nil, driver.driverError(err, path, 0)
+	}
+
+	return &storageWriter{driver: driver, id: response.ID, path: path, size: response.Size}, nil
+}
+
+func (driver *StorageDriverClient) Stat(ctx context.Context, path string) (storagedriver.FileInfo, error) {
+	fileInfo := &FileInfo{}
+
+	if err := driver.invoke(ctx, "Stat", &PathRequest{Path: path}, fileInfo); err != nil {
+		return nil, driver.driverError(err, path, 0)
+	}
+
+	return newDriverFileInfo(fileInfo), nil
+}
+
+func (driver *StorageDriverClient) List(ctx context.Context, path string) ([]string, error) {
+	response := &ListResponse{}
+
+	if err := driver.invoke(ctx, "List", &PathRequest{Path: path}, response); err != nil {
+		return nil, driver.driverError(err, path, 0)
+	}
+
+	return response.Paths, nil
+}
+
+func (driver *StorageDriverClient) Move(ctx context.Context, sourcePath string, destPath string) error {
+	err := driver.invoke(ctx, "Move", &MoveRequest{SourcePath: sourcePath, DestPath: destPath}, &Empty{})
+
+	return driver.driverError(err, sourcePath, 0)
+}
+
+func (driver *StorageDriverClient) Delete(ctx context.Context, path string) error {
+	err := driver.invoke(ctx, "Delete", &PathRequest{Path: path}, &Empty{})
+
+	return driver.driverError(err, path, 0)
+}
+
+func (driver *StorageDriverClient) URLFor(ctx context.Context, path string, options map[string]interface{},
+) (string, error) {
+	return "", storagedriver.ErrUnsupportedMethod{DriverName: driver.Name()}
+}
+
+// Walk calls walkFn for the files and the directories streamed by the plugin, the entries skipped by walkFn
+// returning storagedriver.ErrSkipDir are still streamed but not passed to walkFn.
+func (driver *StorageDriverClient) Walk(ctx context.Context, from string, walkFn storagedriver.WalkFn) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := driver.newStream(ctx, &storageServiceDesc.Streams[1], &PathRequest{Path: from})
+	if err != nil {
+		return driver.driverError(err, from, 0)
+	}
+
+	var skipped string
+
+	for {
+		fileInfo := &FileInfo{}
+
+		if err := stream.RecvMsg(fileInfo); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return driver.driverError(err, from, 0)
+		}
+
+		if skipped != "" && strings.HasPrefix(fileInfo.Path, skipped) {
+			continue
+		}
+
+		err := walkFn(newDriverFileInfo(fileInfo))
+		if !errors.Is(err, storagedriver.ErrSkipDir) {
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// a directory isn't entered, a file skips the rest of its directory
+		if fileInfo.IsDir {
+			skipped = fileInfo.Path + "/"
+
+			continue
+		}
+
+		parent := path.Dir(fileInfo.Path)
+		if parent == path.Clean(from) {
+			return nil
+		}
+
+		skipped = parent + "/"
+	}
+}
+
+func newDriverFileInfo(fileInfo *FileInfo) storagedriver.FileInfo {
+	return storagedriver.FileInfoInternal{FileInfoFields: storagedriver.FileInfoFields{
+		Path:    fileInfo.Path,
+		Size:    fileInfo.Size,
+		ModTime: fileInfo.ModTime,
+		IsDir:   fileInfo.IsDir,
+	}}
+}
+
+// storageReader reads the chunks streamed by the plugin, closing it stops the stream.
+type storageReader struct {
+	driver *StorageDriverClient
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+	path   string
+	offset int64
+	chunk  []byte
+}
+
+func (reader *storageReader) next() error {
+	response := &ContentResponse{}
+
+	if err := reader.stream.RecvMsg(response); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+
+		return reader.driver.driverError(err, reader.path, reader.offset)
+	}
+
+	reader.chunk = response.Content
+
+	return nil
+}
+
+func (reader *storageReader) Read(buf []byte) (int, error) {
+	for len(reader.chunk) == 0 {
+		if err := reader.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(buf, reader.chunk)
+	reader.chunk = reader.chunk[n:]
+
+	return n, nil
+}
+
+func (reader *storageReader) Close() error {
+	reader.cancel()
+
+	return nil
+}
+
+// storageWriter buffers the content written to the plugin, it's sent in chunks.
+type storageWriter struct {
+	driver *StorageDriverClient
+	id     string
+	path   string
+	size   int64
+	buf    []byte
+}
+
+func (writer *storageWriter) call(method string) error {
+	response := &WriterResponse{}
+
+	if err := writer.driver.invoke(context.Background(), method, &WriterResponse{ID: writer.id}, response); err != nil {
+		return writer.driver.driverError(err, writer.path, 0)
+	}
+
+	writer.size = response.Size
+
+	return nil
+}
+
+func (writer *storageWriter) flush() error {
+	if len(writer.buf) == 0 {
+		return nil
+	}
+
+	response := &WriterResponse{}
+
+	err := writer.driver.invoke(context.Background(), "Write", &WriteRequest{ID: writer.id, Content: writer.buf},
+		response)
+	if err != nil {
+		return writer.driver.driverError(err, writer.path, 0)
+	}
+
+	writer.size = response.Size
+	writer.buf = writer.buf[:0]
+
+	return nil
+}
+
+func (writer *storageWriter) Write(content []byte) (int, error) {
+	writer.buf = append(writer.buf, content...)
+
+	if len(writer.buf) >= writeChunkSize {
+		if err := writer.flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(content), nil
+}
+
+func (writer *storageWriter) Size() int64 {
+	return writer.size + int64(len(writer.buf))
+}
+
+func (writer *storageWriter) Commit() error {
+	if err := writer.flush(); err != nil {
+		return err
+	}
+
+	return writer.call("Commit")
+}
+
+func (writer *storageWriter) Cancel() error {
+	writer.buf = nil
+
+	return writer.call("Cancel")
+}
+
+func (writer *storageWriter) Close() error {
+	if err := writer.flush(); err != nil {
+		return err
+	}
+
+	return writer.call("Close")
+}
@@ -0,0 +1,202 @@
+package plugins_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	"github.com/docker/distribution/registry/storage/driver/inmemory"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/plugins"
+)
+
+var errMissingParameter = errors.New("missing parameter")
+
+// newStorageDriver is the factory of the storage driver served by the helper plugin.
+func newStorageDriver(parameters map[string]interface{}) (storagedriver.StorageDriver, error) {
+	if parameters["bucket"] != "zot" {
+		return nil, errMissingParameter
+	}
+
+	return inmemory.New(), nil
+}
+
+func TestStorageDriverPlugin(t *testing.T) {
+	logger := log.NewLogger("debug", "")
+
+	Convey("A plugin serves a storage driver", t, func() {
+		driver, err := plugins.NewStorageDriverClient(getHelperConfig("storage"),
+			map[string]interface{}{"bucket": "zot"}, logger)
+		So(err, ShouldBeNil)
+		So(driver.Name(), ShouldEqual, "test")
+
+		defer plugins.StopStorageDrivers()
+
+		ctx := context.Background()
+
+		err = driver.PutContent(ctx, "/repo/index.json", []byte("index"))
+		So(err, ShouldBeNil)
+
+		content, err := driver.GetContent(ctx, "/repo/index.json")
+		So(err, ShouldBeNil)
+		So(string(content), ShouldEqual, "index")
+
+		_, err = driver.GetContent(ctx, "/repo/missing")
+		So(errors.As(err, &storagedriver.PathNotFoundError{}), ShouldBeTrue)
+
+		// the blobs are written in chunks and appended
+		blob := bytes.Repeat([]byte("0123456789abcdef"), 512*1024)
+
+		writer, err := driver.Writer(ctx, "/repo/blobs/blob", false)
+		So(err, ShouldBeNil)
+
+		_, err = io.Copy(writer, bytes.NewReader(blob[:len(blob)/2]))
+		So(err, ShouldBeNil)
+		So(writer.Size(), ShouldEqual, len(blob)/2)
+		So(writer.Close(), ShouldBeNil)
+
+		writer, err = driver.Writer(ctx, "/repo/blobs/blob", true)
+		So(err, ShouldBeNil)
+		So(writer.Size(), ShouldEqual, len(blob)/2)
+
+		_, err = io.Copy(writer, bytes.NewReader(blob[len(blob)/2:]))
+		So(err, ShouldBeNil)
+		So(writer.Commit(), ShouldBeNil)
+		So(writer.Size(), ShouldEqual, len(blob))
+		So(writer.Close(), ShouldBeNil)
+
+		// the writers are released once closed
+		So(writer.Close(), ShouldNotBeNil)
+
+		writer, err = driver.Writer(ctx, "/repo/blobs/cancelled", false)
+		So(err, ShouldBeNil)
+
+		_, err = writer.Write([]byte("cancelled"))
+		So(err, ShouldBeNil)
+		So(writer.Cancel(), ShouldBeNil)
+
+		_, err = driver.Stat(ctx, "/repo/blobs/cancelled")
+		So(errors.As(err, &storagedriver.PathNotFoundError{}), ShouldBeTrue)
+
+		reader, err := driver.Reader(ctx, "/repo/blobs/blob", 16)
+		So(err, ShouldBeNil)
+
+		content, err = io.ReadAll(reader)
+		So(err, ShouldBeNil)
+		So(bytes.Equal(content, blob[16:]), ShouldBeTrue)
+		So(reader.Close(), ShouldBeNil)
+
+		// the stream is stopped before its end
+		reader, err = driver.Reader(ctx, "/repo/blobs/blob", 0)
+		So(err, ShouldBeNil)
+
+		buf := make([]byte, 10)
+		_, err = io.ReadFull(reader, buf)
+		So(err, ShouldBeNil)
+		So(string(buf), ShouldEqual, "0123456789")
+		So(reader.Close(), ShouldBeNil)
+
+		_, err = driver.Reader(ctx, "/repo/blobs/missing", 0)
+		So(errors.As(err, &storagedriver.PathNotFoundError{}), ShouldBeTrue)
+
+		_, err = driver.Reader(ctx, "/repo/blobs/blob", -1)
+		So(errors.As(err, &storagedriver.InvalidOffsetError{}), ShouldBeTrue)
+
+		fileInfo, err := driver.Stat(ctx, "/repo/blobs/blob")
+		So(err, ShouldBeNil)
+		So(fileInfo.Path(), ShouldEqual, "/repo/blobs/blob")
+		So(fileInfo.Size(), ShouldEqual, len(blob))
+		So(fileInfo.IsDir(), ShouldBeFalse)
+
+		fileInfo, err = driver.Stat(ctx, "/repo")
+		So(err, ShouldBeNil)
+		So(fileInfo.IsDir(), ShouldBeTrue)
+
+		_, err = driver.Stat(ctx, "/missing")
+		So(errors.As(err, &storagedriver.PathNotFoundError{}), ShouldBeTrue)
+
+		paths, err := driver.List(ctx, "/repo")
+		So(err, ShouldBeNil)
+		So(paths, ShouldResemble, []string{"/repo/blobs", "/repo/index.json"})
+
+		err = driver.Move(ctx, "/repo/blobs/blob", "/repo/blobs/moved")
+		So(err, ShouldBeNil)
+
+		err = driver.Move(ctx, "/repo/blobs/blob", "/repo/blobs/moved")
+		So(errors.As(err, &storagedriver.PathNotFoundError{}), ShouldBeTrue)
+
+		err = driver.PutContent(ctx, "/other/index.json", []byte("index"))
+		So(err, ShouldBeNil)
+
+		var walked []string
+
+		err = driver.Walk(ctx, "/", func(fileInfo storagedriver.FileInfo) error {
+			walked = append(walked, fileInfo.Path())
+
+			if fileInfo.Path() == "/other" {
+				return storagedriver.ErrSkipDir
+			}
+
+			return nil
+		})
+		So(err, ShouldBeNil)
+		So(walked, ShouldResemble, []string{"/other", "/repo", "/repo/blobs", "/repo/blobs/moved", "/repo/index.json"})
+
+		// a file skipping its directory
+		walked = nil
+
+		err = driver.Walk(ctx, "/", func(fileInfo storagedriver.FileInfo) error {
+			walked = append(walked, fileInfo.Path())
+
+			if strings.HasPrefix(fileInfo.Path(), "/repo/blobs/") {
+				return storagedriver.ErrSkipDir
+			}
+
+			return nil
+		})
+		So(err, ShouldBeNil)
+		So(walked, ShouldResemble, []string{"/other", "/other/index.json", "/repo", "/repo/blobs",
+			"/repo/blobs/moved", "/repo/index.json"})
+
+		// the errors of the walk function stop the walk
+		err = driver.Walk(ctx, "/", func(fileInfo storagedriver.FileInfo) error {
+			return io.EOF
+		})
+		So(err, ShouldEqual, io.EOF)
+
+		err = driver.Walk(ctx, "/missing", func(fileInfo storagedriver.FileInfo) error {
+			return nil
+		})
+		So(errors.As(err, &storagedriver.PathNotFoundError{}), ShouldBeTrue)
+
+		err = driver.Delete(ctx, "/repo")
+		So(err, ShouldBeNil)
+
+		err = driver.Delete(ctx, "/repo")
+		So(errors.As(err, &storagedriver.PathNotFoundError{}), ShouldBeTrue)
+
+		_, err = driver.URLFor(ctx, "/other/index.json", nil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Storage driver plugins failing to start", t, func() {
+		_, err := plugins.NewStorageDriverClient(getHelperConfig("storage"), map[string]interface{}{}, logger)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, errMissingParameter.Error())
+
+		_, err = plugins.NewStorageDriverClient(getHelperConfig("exit"), map[string]interface{}{}, logger)
+		So(errors.Is(err, zerr.ErrPluginExited), ShouldBeTrue)
+	})
+
+	Convey("Storage driver plugins can only be launched by zot", t, func() {
+		err := plugins.ServeStorageDriver(newStorageDriver)
+		So(errors.Is(err, zerr.ErrPluginNotLaunchedByZot), ShouldBeTrue)
+	})
+}
@@ -148,6 +148,15 @@ func (uac *UserAccessControl) SetGlobPatterns(action string, patterns map[string
 	uac.authzInfo.globPatterns[action] = patterns
 }
 
+// GetGlobPatterns returns the repository glob patterns resolved for 'action', nil if authz is not set up.
+func (uac *UserAccessControl) GetGlobPatterns(action string) map[string]bool {
+	if uac.authzInfo == nil {
+		return nil
+	}
+
+	return uac.authzInfo.globPatterns[action]
+}
+
 /*
 Can returns whether or not the user/anonymous who made the request has 'action' permission on 'repository'.
 */
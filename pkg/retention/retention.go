@@ -0,0 +1,582 @@
+// Package retention deletes the tags which aren't kept by the retention policies of their repos, the untagged
+// manifests and their blobs are then removed by the garbage collection.
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta"
+	mcommon "zotregistry.io/zot/pkg/meta/common"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/storage"
+	storageCommon "zotregistry.io/zot/pkg/storage/common"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	DefaultInterval = 24 * time.Hour
+	// PoliciesFile stores the policies set through the mgmt extension, in the root directory of the storage.
+	PoliciesFile = "retention.json"
+)
+
+// Notifier publishes the deletions of the tags, e.g. the events extension.
+type Notifier interface {
+	ImageDeleted(repo, reference, digest, mediaType, user string)
+}
+
+// Candidate is a tag which isn't kept by the retention policies.
+type Candidate struct {
+	Repo      string `json:"repo"`
+	Tag       string `json:"tag"`
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType"`
+}
+
+type compiledPolicy struct {
+	repos            []string
+	keepLastN        int
+	keepTags         []*regexp.Regexp
+	keepPulledWithin time.Duration
+	deleteOlderThan  time.Duration
+}
+
+/*
+Manager applies the retention policies to the repos of every image store. The policies come from the config,
+unless they were set through the mgmt extension, in which case they are read from the policies file so they
+survive the restarts.
+
+The most recently pushed tags are the last ones of the repo index, a tag pushed again moves to the end of it.
+The signatures are never deleted by the policies, the garbage collection removes them with their images.
+The last pulls of the tags are read from the metaDB, without it the policies don't keep the recently pulled tags.
+*/
+type Manager struct {
+	config          *config.RetentionConfig
+	policiesPath    string
+	policies        []config.RetentionPolicy
+	storeController storage.StoreController
+	metaDB          mTypes.MetaDB
+	notifier        Notifier
+	lock            *sync.RWMutex
+	applyLock       *sync.Mutex
+	log             log.Logger
+}
+
+// NewManager returns the manager of the retention policies, the metaDB and the notifier are optional.
+func NewManager(conf *config.Config, storeController storage.StoreController, metaDB mTypes.MetaDB,
+	notifier Notifier, log log.Logger,
+) *Manager {
+	retentionConfig := conf.Storage.Retention
+	if retentionConfig == nil {
+		retentionConfig = &config.RetentionConfig{}
+	}
+
+	manager := &Manager{
+		config:          retentionConfig,
+		policiesPath:    path.Join(conf.Storage.RootDirectory, PoliciesFile),
+		policies:        retentionConfig.Policies,
+		storeController: storeController,
+		metaDB:          metaDB,
+		notifier:        notifier,
+		lock:            &sync.RWMutex{},
+		applyLock:       &sync.Mutex{},
+		log:             log,
+	}
+
+	policies, err := manager.loadPolicies()
+	if err != nil {
+		log.Error().Err(err).Str("path", manager.policiesPath).
+			Msg("retention: unable to load the stored policies, using the policies of the config")
+	} else if policies != nil {
+		manager.policies = policies
+	}
+
+	return manager
+}
+
+// Validate checks the repo patterns and the tag regular expressions of the policies compile.
+func Validate(policies []config.RetentionPolicy) error {
+	_, err := compilePolicies(policies)
+
+	return err
+}
+
+func compilePolicies(policies []config.RetentionPolicy) ([]compiledPolicy, error) {
+	compiled := make([]compiledPolicy, 0, len(policies))
+
+	for idx, policy := range policies {
+		if len(policy.Repos) == 0 {
+			return nil, fmt.Errorf("%w: policy %d doesn't match any repo", zerr.ErrBadRetentionPolicy, idx)
+		}
+
+		if policy.KeepLastN < 0 {
+			return nil, fmt.Errorf("%w: policy %d keeps a negative number of tags", zerr.ErrBadRetentionPolicy, idx)
+		}
+
+		if policy.KeepPulledWithinDays < 0 {
+			return nil, fmt.Errorf("%w: policy %d keeps the tags pulled within a negative number of days",
+				zerr.ErrBadRetentionPolicy, idx)
+		}
+
+		if policy.DeleteOlderThanDays < 0 {
+			return nil, fmt.Errorf("%w: policy %d deletes the tags older than a negative number of days",
+				zerr.ErrBadRetentionPolicy, idx)
+		}
+
+		for _, pattern := range policy.Repos {
+			if !glob.ValidatePattern(pattern) {
+				return nil, fmt.Errorf("%w: policy %d has an invalid repo pattern %q", zerr.ErrBadRetentionPolicy,
+					idx, pattern)
+			}
+		}
+
+		keepTags := make([]*regexp.Regexp, 0, len(policy.KeepTags))
+
+		for _, expr := range policy.KeepTags {
+			tagRegex, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("%w: policy %d has an invalid tag regex %q", zerr.ErrBadRetentionPolicy,
+					idx, expr)
+			}
+
+			keepTags = append(keepTags, tagRegex)
+		}
+
+		compiled = append(compiled, compiledPolicy{
+			repos:            policy.Repos,
+			keepLastN:        policy.KeepLastN,
+			keepTags:         keepTags,
+			keepPulledWithin: time.Duration(policy.KeepPulledWithinDays) * 24 * time.Hour,
+			deleteOlderThan:  time.Duration(policy.DeleteOlderThanDays) * 24 * time.Hour,
+		})
+	}
+
+	return compiled, nil
+}
+
+// Policies returns the policies currently applied.
+func (manager *Manager) Policies() []config.RetentionPolicy {
+	manager.lock.RLock()
+	defer manager.lock.RUnlock()
+
+	policies := make([]config.RetentionPolicy, len(manager.policies))
+	copy(policies, manager.policies)
+
+	return policies
+}
+
+// SetPolicies validates the policies, then stores them so they replace the policies of the config.
+func (manager *Manager) SetPolicies(policies []config.RetentionPolicy) error {
+	if err := Validate(policies); err != nil {
+		return err
+	}
+
+	if policies == nil {
+		policies = []config.RetentionPolicy{}
+	}
+
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if err := manager.storePolicies(policies); err != nil {
+		return err
+	}
+
+	manager.policies = policies
+
+	manager.log.Info().Interface("policies", policies).Msg("retention: policies updated")
+
+	return nil
+}
+
+// Preview returns the tags the policies would delete, without deleting them.
+func (manager *Manager) Preview(ctx context.Context, policies []config.RetentionPolicy) ([]Candidate, error) {
+	compiled, err := compilePolicies(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.getCandidates(ctx, compiled)
+}
+
+// Apply deletes the tags which aren't kept by the current policies, they are only logged in dry run mode.
+func (manager *Manager) Apply(ctx context.Context) ([]Candidate, error) {
+	manager.applyLock.Lock()
+	defer manager.applyLock.Unlock()
+
+	policies := manager.Policies()
+	if len(policies) == 0 {
+		return []Candidate{}, nil
+	}
+
+	compiled, err := compilePolicies(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := manager.getCandidates(ctx, compiled)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]Candidate, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		if manager.config.DryRun {
+			manager.log.Info().Str("repository", candidate.Repo).Str("tag", candidate.Tag).
+				Str("digest", candidate.Digest).Msg("retention: dry run, the tag would be deleted")
+
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		if err := manager.deleteTag(candidate); err != nil {
+			manager.log.Error().Err(err).Str("repository", candidate.Repo).Str("tag", candidate.Tag).
+				Msg("retention: unable to delete the tag")
+
+			continue
+		}
+
+		deleted = append(deleted, candidate)
+	}
+
+	return deleted, nil
+}
+
+// RunPeriodically applies the policies at the configured interval.
+func (manager *Manager) RunPeriodically(sch *scheduler.Scheduler) {
+	interval := manager.config.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	manager.log.Info().Dur("interval", interval).Msg("retention: submitting the retention policies task")
+
+	sch.SubmitSharedGenerator("retention", &taskGenerator{manager: manager}, interval, scheduler.LowPriority)
+}
+
+func (manager *Manager) getCandidates(ctx context.Context, policies []compiledPolicy) ([]Candidate, error) {
+	candidates := []Candidate{}
+
+	if len(policies) == 0 {
+		return candidates, nil
+	}
+
+	for _, imgStore := range manager.getImageStores() {
+		repos, err := imgStore.GetRepositories()
+		if err != nil {
+			manager.log.Error().Err(err).Str("rootDir", imgStore.RootDir()).Msg("retention: unable to list the repos")
+
+			return nil, err
+		}
+
+		for _, repo := range repos {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			policy := matchPolicy(policies, repo)
+			if policy == nil || policy.keepLastN == 0 && policy.deleteOlderThan == 0 {
+				continue
+			}
+
+			index, err := storageCommon.GetIndex(imgStore, repo, manager.log)
+			if err != nil {
+				manager.log.Error().Err(err).Str("repository", repo).Msg("retention: unable to read the repo index")
+
+				continue
+			}
+
+			recentlyPulled, err := manager.getRecentlyPulledTags(repo, policy)
+			if err != nil {
+				manager.log.Error().Err(err).Str("repository", repo).Msg("retention: unable to read the last pulls")
+
+				continue
+			}
+
+			oldTags := manager.getOldTags(imgStore, repo, index, policy)
+
+			candidates = append(candidates, getRepoCandidates(repo, index, policy, recentlyPulled, oldTags)...)
+		}
+	}
+
+	return candidates, nil
+}
+
+// getRecentlyPulledTags returns the tags of the repo whose image was pulled, by the tag or by its digest, within
+// the period kept by the policy.
+func (manager *Manager) getRecentlyPulledTags(repo string, policy *compiledPolicy) (map[string]bool, error) {
+	recentlyPulled := map[string]bool{}
+
+	if policy.keepPulledWithin == 0 || manager.metaDB == nil {
+		return recentlyPulled, nil
+	}
+
+	repoMeta, err := manager.metaDB.GetRepoMeta(repo)
+	if err != nil {
+		if errors.Is(err, zerr.ErrRepoMetaNotFound) {
+			return recentlyPulled, nil
+		}
+
+		return nil, err
+	}
+
+	pulledSince := time.Now().Add(-policy.keepPulledWithin)
+
+	for tag, descriptor := range repoMeta.Tags {
+		if mcommon.GetImageLastPull(repoMeta, tag, descriptor.Digest).After(pulledSince) {
+			recentlyPulled[tag] = true
+		}
+	}
+
+	return recentlyPulled, nil
+}
+
+// getOldTags returns the tags of the repo pushed before the period kept by the policy. The push of a tag is dated
+// by the manifest blob, so a tag pushed again with a manifest already in the repo keeps the date of the manifest.
+func (manager *Manager) getOldTags(imgStore storageTypes.ImageStore, repo string, index ispec.Index,
+	policy *compiledPolicy,
+) map[string]bool {
+	oldTags := map[string]bool{}
+
+	if policy.deleteOlderThan == 0 {
+		return oldTags
+	}
+
+	pushedBefore := time.Now().Add(-policy.deleteOlderThan)
+
+	for _, desc := range index.Manifests {
+		tag := desc.Annotations[ispec.AnnotationRefName]
+		if tag == "" {
+			continue
+		}
+
+		ok, _, modTime, err := imgStore.StatBlob(repo, desc.Digest)
+		if err != nil || !ok {
+			manager.log.Error().Err(err).Str("repository", repo).Str("tag", tag).
+				Msg("retention: unable to get the push time of the tag")
+
+			continue
+		}
+
+		if modTime.Before(pushedBefore) {
+			oldTags[tag] = true
+		}
+	}
+
+	return oldTags
+}
+
+// getImageStores returns the default image store then the sub stores sorted by their route, the read-only image
+// stores are skipped.
+func (manager *Manager) getImageStores() []storageTypes.ImageStore {
+	imgStores := []storageTypes.ImageStore{}
+
+	if !manager.storeController.DefaultStore.IsReadOnly() {
+		imgStores = append(imgStores, manager.storeController.DefaultStore)
+	}
+
+	routes := make([]string, 0, len(manager.storeController.SubStore))
+	for route := range manager.storeController.SubStore {
+		routes = append(routes, route)
+	}
+
+	sort.Strings(routes)
+
+	for _, route := range routes {
+		if !manager.storeController.SubStore[route].IsReadOnly() {
+			imgStores = append(imgStores, manager.storeController.SubStore[route])
+		}
+	}
+
+	return imgStores
+}
+
+// matchPolicy returns the first policy matching the repo, nil if none matches.
+func matchPolicy(policies []compiledPolicy, repo string) *compiledPolicy {
+	for idx := range policies {
+		for _, pattern := range policies[idx].repos {
+			if matched, _ := glob.Match(pattern, repo); matched {
+				return &policies[idx]
+			}
+		}
+	}
+
+	return nil
+}
+
+// getRepoCandidates returns the tags of the repo which aren't kept by the policy, the oldest first.
+func getRepoCandidates(repo string, index ispec.Index, policy *compiledPolicy,
+	recentlyPulled, oldTags map[string]bool,
+) []Candidate {
+	candidates := []Candidate{}
+	kept := 0
+
+	for idx := len(index.Manifests) - 1; idx >= 0; idx-- {
+		desc := index.Manifests[idx]
+
+		tag := desc.Annotations[ispec.AnnotationRefName]
+		if tag == "" || storageCommon.IsSignature(desc) || isTagExempt(policy, tag) || recentlyPulled[tag] {
+			continue
+		}
+
+		if !oldTags[tag] && (policy.keepLastN == 0 || kept < policy.keepLastN) {
+			kept++
+
+			continue
+		}
+
+		candidates = append([]Candidate{{
+			Repo:      repo,
+			Tag:       tag,
+			Digest:    desc.Digest.String(),
+			MediaType: desc.MediaType,
+		}}, candidates...)
+	}
+
+	return candidates
+}
+
+func isTagExempt(policy *compiledPolicy, tag string) bool {
+	for _, tagRegex := range policy.keepTags {
+		if tagRegex.MatchString(tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (manager *Manager) deleteTag(candidate Candidate) error {
+	imgStore := manager.storeController.GetImageStore(candidate.Repo)
+
+	manifestBlob, digest, mediaType, err := imgStore.GetImageManifest(candidate.Repo, candidate.Tag)
+	if err != nil {
+		return err
+	}
+
+	// the tag was pushed again since the candidates were listed
+	if digest.String() != candidate.Digest {
+		return zerr.ErrManifestConflict
+	}
+
+	if err := imgStore.DeleteImageManifest(candidate.Repo, candidate.Tag, false); err != nil {
+		return err
+	}
+
+	if manager.metaDB != nil {
+		if err := meta.OnDeleteManifest(candidate.Repo, candidate.Tag, mediaType, digest, manifestBlob,
+			manager.storeController, manager.metaDB, manager.log); err != nil {
+			return err
+		}
+	}
+
+	if manager.notifier != nil {
+		manager.notifier.ImageDeleted(candidate.Repo, candidate.Tag, candidate.Digest, mediaType, "")
+	}
+
+	manager.log.Info().Str("repository", candidate.Repo).Str("tag", candidate.Tag).Str("digest", candidate.Digest).
+		Msg("retention: tag deleted")
+
+	return nil
+}
+
+// loadPolicies returns the stored policies, nil if they were never set through the mgmt extension.
+func (manager *Manager) loadPolicies() ([]config.RetentionPolicy, error) {
+	content, err := os.ReadFile(manager.policiesPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	policies := []config.RetentionPolicy{}
+
+	if err := json.Unmarshal(content, &policies); err != nil {
+		return nil, err
+	}
+
+	if err := Validate(policies); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (manager *Manager) storePolicies(policies []config.RetentionPolicy) error {
+	content, err := json.MarshalIndent(policies, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// written to a temporary file first so the policies are never read half written
+	tmpPath := manager.policiesPath + ".tmp"
+
+	if err := os.WriteFile(tmpPath, content, storageConstants.DefaultFilePerms); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, manager.policiesPath)
+}
+
+type taskGenerator struct {
+	manager   *Manager
+	generated bool
+	done      bool
+}
+
+func (gen *taskGenerator) Next() (scheduler.Task, error) {
+	if gen.generated {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.generated = true
+
+	return &task{manager: gen.manager}, nil
+}
+
+func (gen *taskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *taskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *taskGenerator) Reset() {
+	gen.generated = false
+	gen.done = false
+}
+
+type task struct {
+	manager *Manager
+}
+
+func (task *task) DoWork(ctx context.Context) error {
+	_, err := task.manager.Apply(ctx)
+
+	return err
+}
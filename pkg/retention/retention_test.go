@@ -0,0 +1,309 @@
+package retention_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/retention"
+	"zotregistry.io/zot/pkg/storage"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	"zotregistry.io/zot/pkg/storage/local"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+	"zotregistry.io/zot/pkg/test/mocks"
+)
+
+var ErrTestError = errors.New("test error")
+
+type notifierMock struct {
+	deleted []string
+}
+
+func (notifier *notifierMock) ImageDeleted(repo, reference, digest, mediaType, user string) {
+	notifier.deleted = append(notifier.deleted, repo+":"+reference)
+}
+
+func TestValidate(t *testing.T) {
+	Convey("Validate the retention policies", t, func() {
+		err := retention.Validate(nil)
+		So(err, ShouldBeNil)
+
+		err = retention.Validate([]config.RetentionPolicy{
+			{Repos: []string{"infra/**"}, KeepLastN: 10, KeepTags: []string{"^v[0-9]+$"}},
+			{Repos: []string{"**"}},
+		})
+		So(err, ShouldBeNil)
+
+		for _, policy := range []config.RetentionPolicy{
+			{KeepLastN: 5},
+			{Repos: []string{"**"}, KeepLastN: -1},
+			{Repos: []string{"["}, KeepLastN: 5},
+			{Repos: []string{"**"}, KeepLastN: 5, KeepTags: []string{"("}},
+			{Repos: []string{"**"}, KeepLastN: 5, KeepPulledWithinDays: -1},
+			{Repos: []string{"**"}, DeleteOlderThanDays: -1},
+		} {
+			err = retention.Validate([]config.RetentionPolicy{policy})
+			So(errors.Is(err, zerr.ErrBadRetentionPolicy), ShouldBeTrue)
+		}
+	})
+}
+
+func TestRetentionPolicies(t *testing.T) {
+	Convey("Apply the retention policies", t, func() {
+		rootDir := t.TempDir()
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		for _, tag := range []string{"1.0", "latest", "2.0", "3.0"} {
+			err := test.WriteImageToFileSystem(CreateRandomImage(), "app", tag, storeController)
+			So(err, ShouldBeNil)
+		}
+
+		for _, tag := range []string{"1.0", "2.0"} {
+			err := test.WriteImageToFileSystem(CreateRandomImage(), "infra/db", tag, storeController)
+			So(err, ShouldBeNil)
+		}
+
+		conf := config.New()
+		conf.Storage.RootDirectory = rootDir
+		conf.Storage.Retention = &config.RetentionConfig{
+			Policies: []config.RetentionPolicy{
+				// the policy of a specific repo overrides the broader one listed after it
+				{Repos: []string{"infra/**"}},
+				{Repos: []string{"**"}, KeepLastN: 1, KeepTags: []string{"^latest$"}},
+			},
+		}
+
+		notifier := &notifierMock{}
+		manager := retention.NewManager(conf, storeController, nil, notifier, log)
+
+		candidates, err := manager.Preview(context.Background(), manager.Policies())
+		So(err, ShouldBeNil)
+		So(len(candidates), ShouldEqual, 2)
+		So(candidates[0].Repo, ShouldEqual, "app")
+		So(candidates[0].Tag, ShouldEqual, "1.0")
+		So(candidates[1].Tag, ShouldEqual, "2.0")
+
+		// the preview doesn't delete anything
+		tags, err := imgStore.GetImageTags("app")
+		So(err, ShouldBeNil)
+		So(tags, ShouldHaveLength, 4)
+
+		candidates, err = manager.Preview(context.Background(), []config.RetentionPolicy{
+			{Repos: []string{"**"}, KeepLastN: 1},
+		})
+		So(err, ShouldBeNil)
+		So(len(candidates), ShouldEqual, 4)
+
+		_, err = manager.Preview(context.Background(), []config.RetentionPolicy{{KeepLastN: 1}})
+		So(errors.Is(err, zerr.ErrBadRetentionPolicy), ShouldBeTrue)
+
+		Convey("The tags are deleted", func() {
+			deleted, err := manager.Apply(context.Background())
+			So(err, ShouldBeNil)
+			So(len(deleted), ShouldEqual, 2)
+			So(notifier.deleted, ShouldResemble, []string{"app:1.0", "app:2.0"})
+
+			tags, err := imgStore.GetImageTags("app")
+			So(err, ShouldBeNil)
+			So(tags, ShouldResemble, []string{"latest", "3.0"})
+
+			tags, err = imgStore.GetImageTags("infra/db")
+			So(err, ShouldBeNil)
+			So(tags, ShouldHaveLength, 2)
+
+			deleted, err = manager.Apply(context.Background())
+			So(err, ShouldBeNil)
+			So(deleted, ShouldBeEmpty)
+		})
+
+		Convey("The tags are only logged in dry run mode", func() {
+			conf.Storage.Retention.DryRun = true
+
+			manager := retention.NewManager(conf, storeController, nil, notifier, log)
+
+			deleted, err := manager.Apply(context.Background())
+			So(err, ShouldBeNil)
+			So(deleted, ShouldBeEmpty)
+			So(notifier.deleted, ShouldBeEmpty)
+
+			tags, err := imgStore.GetImageTags("app")
+			So(err, ShouldBeNil)
+			So(tags, ShouldHaveLength, 4)
+		})
+
+		Convey("The read-only image stores are skipped", func() {
+			readOnlyStore := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
+				storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, true, log, metrics, nil, nil)
+
+			manager := retention.NewManager(conf, storage.StoreController{DefaultStore: readOnlyStore}, nil,
+				notifier, log)
+
+			candidates, err := manager.Preview(context.Background(), manager.Policies())
+			So(err, ShouldBeNil)
+			So(candidates, ShouldBeEmpty)
+
+			deleted, err := manager.Apply(context.Background())
+			So(err, ShouldBeNil)
+			So(deleted, ShouldBeEmpty)
+			So(notifier.deleted, ShouldBeEmpty)
+
+			tags, err := imgStore.GetImageTags("app")
+			So(err, ShouldBeNil)
+			So(tags, ShouldHaveLength, 4)
+		})
+
+		Convey("The policies which are set replace the ones of the config", func() {
+			policies := []config.RetentionPolicy{{Repos: []string{"infra/**"}, KeepLastN: 1}}
+
+			err := manager.SetPolicies([]config.RetentionPolicy{{Repos: []string{"["}}})
+			So(errors.Is(err, zerr.ErrBadRetentionPolicy), ShouldBeTrue)
+			So(manager.Policies(), ShouldResemble, conf.Storage.Retention.Policies)
+
+			err = manager.SetPolicies(policies)
+			So(err, ShouldBeNil)
+			So(manager.Policies(), ShouldResemble, policies)
+
+			// they are loaded after a restart
+			manager = retention.NewManager(conf, storeController, nil, nil, log)
+			So(manager.Policies(), ShouldResemble, policies)
+
+			deleted, err := manager.Apply(context.Background())
+			So(err, ShouldBeNil)
+			So(len(deleted), ShouldEqual, 1)
+			So(deleted[0].Repo, ShouldEqual, "infra/db")
+			So(deleted[0].Tag, ShouldEqual, "1.0")
+
+			// the policies of the config are used if the stored ones can't be read
+			err = os.WriteFile(path.Join(rootDir, retention.PoliciesFile), []byte("{"), 0o600)
+			So(err, ShouldBeNil)
+
+			manager = retention.NewManager(conf, storeController, nil, nil, log)
+			So(manager.Policies(), ShouldResemble, conf.Storage.Retention.Policies)
+		})
+
+		Convey("The old tags are deleted", func() {
+			setPushTime := func(repo, tag string, pushTime time.Time) {
+				_, digest, _, err := imgStore.GetImageManifest(repo, tag)
+				So(err, ShouldBeNil)
+
+				err = os.Chtimes(imgStore.BlobPath(repo, digest), pushTime, pushTime)
+				So(err, ShouldBeNil)
+			}
+
+			oldPush := time.Now().AddDate(0, 0, -40)
+
+			setPushTime("app", "3.0", oldPush)
+			setPushTime("app", "latest", oldPush)
+			setPushTime("infra/db", "1.0", oldPush)
+
+			candidates, err := manager.Preview(context.Background(), []config.RetentionPolicy{
+				{Repos: []string{"infra/**"}, DeleteOlderThanDays: 30},
+				{Repos: []string{"**"}, KeepLastN: 1, KeepTags: []string{"^latest$"}, DeleteOlderThanDays: 30},
+			})
+			So(err, ShouldBeNil)
+			So(candidates, ShouldHaveLength, 3)
+
+			tags := []string{}
+			for _, candidate := range candidates {
+				tags = append(tags, candidate.Repo+":"+candidate.Tag)
+			}
+
+			// the last tag of app is old so the one before it is kept instead, the tags kept by their regular
+			// expressions are still kept
+			So(tags, ShouldResemble, []string{"app:1.0", "app:3.0", "infra/db:1.0"})
+
+			candidates, err = manager.Preview(context.Background(), []config.RetentionPolicy{
+				{Repos: []string{"**"}, DeleteOlderThanDays: 60},
+			})
+			So(err, ShouldBeNil)
+			So(candidates, ShouldBeEmpty)
+		})
+
+		Convey("The recently pulled tags are kept", func() {
+			lastDigestPull := time.Now().AddDate(0, 0, -10)
+
+			metaDB := mocks.MetaDBMock{
+				GetRepoMetaFn: func(repo string) (mTypes.RepoMetadata, error) {
+					if repo != "app" {
+						return mTypes.RepoMetadata{}, zerr.ErrRepoMetaNotFound
+					}
+
+					return mTypes.RepoMetadata{
+						Name: repo,
+						Tags: map[string]mTypes.Descriptor{
+							"1.0":    {Digest: "sha256:1.0"},
+							"2.0":    {Digest: "sha256:2.0"},
+							"latest": {Digest: "sha256:latest"},
+						},
+						Statistics: map[string]mTypes.DescriptorStatistics{
+							"sha256:latest": {DownloadCount: 1, LastPullTimestamp: lastDigestPull},
+						},
+						TagStatistics: map[string]mTypes.DescriptorStatistics{
+							"1.0": {DownloadCount: 1, LastPullTimestamp: time.Now().Add(-time.Hour)},
+							"2.0": {DownloadCount: 1, LastPullTimestamp: time.Now().AddDate(0, 0, -10)},
+						},
+					}, nil
+				},
+			}
+
+			policies := []config.RetentionPolicy{{Repos: []string{"**"}, KeepLastN: 1, KeepPulledWithinDays: 7}}
+
+			manager := retention.NewManager(conf, storeController, metaDB, nil, log)
+
+			candidates, err := manager.Preview(context.Background(), policies)
+			So(err, ShouldBeNil)
+			So(len(candidates), ShouldEqual, 3)
+			So(candidates[0].Tag, ShouldEqual, "latest")
+			So(candidates[1].Tag, ShouldEqual, "2.0")
+			So(candidates[2].Repo, ShouldEqual, "infra/db")
+
+			// the tags whose image was pulled by digest are kept too
+			lastDigestPull = time.Now().Add(-time.Hour)
+
+			candidates, err = manager.Preview(context.Background(), policies)
+			So(err, ShouldBeNil)
+			So(len(candidates), ShouldEqual, 2)
+			So(candidates[0].Tag, ShouldEqual, "2.0")
+			So(candidates[1].Repo, ShouldEqual, "infra/db")
+
+			// the repo is skipped if its last pulls can't be read
+			metaDB.GetRepoMetaFn = func(repo string) (mTypes.RepoMetadata, error) {
+				return mTypes.RepoMetadata{}, ErrTestError
+			}
+
+			manager = retention.NewManager(conf, storeController, metaDB, nil, log)
+
+			candidates, err = manager.Preview(context.Background(), policies)
+			So(err, ShouldBeNil)
+			So(candidates, ShouldBeEmpty)
+		})
+
+		Convey("The policies aren't applied after the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := manager.Apply(ctx)
+			So(err, ShouldNotBeNil)
+
+			tags, err := imgStore.GetImageTags("app")
+			So(err, ShouldBeNil)
+			So(tags, ShouldHaveLength, 4)
+		})
+	})
+}
@@ -60,6 +60,12 @@ const (
 	numWorkersMultiplier = 4
 )
 
+// TaskOwner tells whether this zot instance runs the shared tasks of the name, e.g. the members of a cluster
+// share the storage and each of its periodic tasks is run by one of them.
+type TaskOwner interface {
+	OwnsTask(name string) bool
+}
+
 type Scheduler struct {
 	tasksQLow         chan Task
 	tasksQMedium      chan Task
@@ -69,6 +75,7 @@ type Scheduler struct {
 	generatorsLock    *sync.Mutex
 	log               log.Logger
 	stopCh            chan struct{}
+	taskOwner         TaskOwner
 	RateLimit         time.Duration
 	NumWorkers        int
 }
@@ -289,6 +296,8 @@ type TaskGenerator interface {
 }
 
 type generator struct {
+	name          string
+	owner         TaskOwner
 	interval      time.Duration
 	lastRun       time.Time
 	done          bool
@@ -356,6 +365,11 @@ func (gen *generator) getState() state {
 		return waiting
 	}
 
+	// the shared tasks are generated by their owner only
+	if gen.owner != nil && !gen.owner.OwnsTask(gen.name) {
+		return waiting
+	}
+
 	return ready
 }
 
@@ -375,6 +389,36 @@ func (scheduler *Scheduler) SubmitGenerator(taskGenerator TaskGenerator, interva
 	heap.Push(&scheduler.generators, newGenerator)
 }
 
+// SetTaskOwner sets the owner of the shared generators submitted afterwards.
+func (scheduler *Scheduler) SetTaskOwner(owner TaskOwner) {
+	scheduler.generatorsLock.Lock()
+	defer scheduler.generatorsLock.Unlock()
+
+	scheduler.taskOwner = owner
+}
+
+// SubmitSharedGenerator submits a generator of tasks shared by the zot instances, they're only generated
+// by the instance owning the tasks of the name. Without an owner it's the same as SubmitGenerator.
+func (scheduler *Scheduler) SubmitSharedGenerator(name string, taskGenerator TaskGenerator, interval time.Duration,
+	priority Priority,
+) {
+	scheduler.generatorsLock.Lock()
+	defer scheduler.generatorsLock.Unlock()
+
+	newGenerator := &generator{
+		name:          name,
+		owner:         scheduler.taskOwner,
+		interval:      interval,
+		done:          false,
+		priority:      priority,
+		taskGenerator: taskGenerator,
+		remainingTask: nil,
+	}
+
+	// add generator to the generators priority queue
+	heap.Push(&scheduler.generators, newGenerator)
+}
+
 func getNumWorkers(cfg *config.Config) int {
 	if cfg.Scheduler != nil && cfg.Scheduler.NumWorkers != 0 {
 		return cfg.Scheduler.NumWorkers
@@ -242,6 +242,53 @@ func TestScheduler(t *testing.T) {
 	})
 }
 
+type taskOwner struct {
+	owned string
+}
+
+func (owner taskOwner) OwnsTask(name string) bool {
+	return name == owner.owned
+}
+
+func TestSharedGenerators(t *testing.T) {
+	Convey("Only the shared generators owned by the instance generate tasks", t, func() {
+		logFile, err := os.CreateTemp("", "zot-log*.txt")
+		So(err, ShouldBeNil)
+
+		defer os.Remove(logFile.Name()) // clean up
+
+		logger := log.NewLogger("debug", logFile.Name())
+		cfg := config.New()
+		// enough workers for the tasks of all the generators to be generated before throttling
+		cfg.Scheduler = &config.SchedulerConfig{NumWorkers: 10}
+		sch := scheduler.NewScheduler(cfg, logger)
+
+		// submitted before the owner is set, it's generated by each instance
+		genL := &generator{log: logger, priority: "local"}
+		sch.SubmitSharedGenerator("local", genL, time.Duration(0), scheduler.LowPriority)
+
+		sch.SetTaskOwner(taskOwner{owned: "owned"})
+
+		genO := &generator{log: logger, priority: "owned"}
+		sch.SubmitSharedGenerator("owned", genO, time.Duration(0), scheduler.MediumPriority)
+
+		genN := &generator{log: logger, priority: "not owned"}
+		sch.SubmitSharedGenerator("not owned", genN, time.Duration(0), scheduler.HighPriority)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sch.RunScheduler(ctx)
+
+		time.Sleep(2 * time.Second)
+		cancel()
+
+		data, err := os.ReadFile(logFile.Name())
+		So(err, ShouldBeNil)
+		So(string(data), ShouldContainSubstring, "executing local task")
+		So(string(data), ShouldContainSubstring, "executing owned task")
+		So(string(data), ShouldNotContainSubstring, "executing not owned task")
+	})
+}
+
 func TestGetNumWorkers(t *testing.T) {
 	Convey("Test setting the number of workers - default value", t, func() {
 		sch := scheduler.NewScheduler(config.New(), log.NewLogger("debug", "logFile"))
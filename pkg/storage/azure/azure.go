@@ -0,0 +1,42 @@
+package azure
+
+import (
+	"time"
+
+	// Add azure support.
+	"github.com/docker/distribution/registry/storage/driver"
+	// Load azure driver.
+	_ "github.com/docker/distribution/registry/storage/driver/azure"
+
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	zlog "zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage/cache"
+	common "zotregistry.io/zot/pkg/storage/common"
+	"zotregistry.io/zot/pkg/storage/imagestore"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+// NewImageStore returns a new image store backed by azure blob storage.
+// see https://github.com/distribution/distribution/blob/main/docs/content/storage-drivers/azure.md
+// Use the last argument to properly set a cache database, or it will default to boltDB local storage.
+func NewImageStore(rootDir string, cacheDir string, gc bool, gcReferrers bool, gcDelay time.Duration,
+	untaggedImageRetentionDelay time.Duration, dedupe, commit, readOnly bool, log zlog.Logger,
+	metrics monitoring.MetricServer, linter common.Lint, store driver.StorageDriver, cacheDriver cache.Cache,
+) storageTypes.ImageStore {
+	return imagestore.NewImageStore(
+		rootDir,
+		cacheDir,
+		gc,
+		gcReferrers,
+		gcDelay,
+		untaggedImageRetentionDelay,
+		dedupe,
+		commit,
+		readOnly,
+		log,
+		metrics,
+		linter,
+		New(store),
+		cacheDriver,
+	)
+}
@@ -0,0 +1,159 @@
+package azure_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/docker/distribution/registry/storage/driver"
+	"github.com/docker/distribution/registry/storage/driver/factory"
+	guuid "github.com/gofrs/uuid"
+	"github.com/rs/zerolog"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage"
+	"zotregistry.io/zot/pkg/storage/azure"
+	"zotregistry.io/zot/pkg/storage/cache"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func skipIt(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("AZURE_STORAGE_ACCOUNT") == "" || os.Getenv("AZURE_STORAGE_KEY") == "" {
+		t.Skip("Skipping testing without an Azure storage account")
+	}
+}
+
+func storageDriverParams(rootDir string) map[string]interface{} {
+	container := os.Getenv("AZURE_STORAGE_CONTAINER")
+	if container == "" {
+		container = "zot-storage-test"
+	}
+
+	return map[string]interface{}{
+		"name":          storageConstants.AzureStorageDriverName,
+		"rootdirectory": rootDir,
+		"accountname":   os.Getenv("AZURE_STORAGE_ACCOUNT"),
+		"accountkey":    os.Getenv("AZURE_STORAGE_KEY"),
+		"container":     container,
+	}
+}
+
+func createObjectsStore(rootDir string, cacheDir string) (driver.StorageDriver, storageTypes.ImageStore) {
+	store, err := factory.Create(storageConstants.AzureStorageDriverName, storageDriverParams(rootDir))
+	if err != nil {
+		panic(err)
+	}
+
+	log := log.Logger{Logger: zerolog.New(os.Stdout)}
+	metrics := monitoring.NewMetricsServer(false, log)
+
+	cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+		RootDir:     cacheDir,
+		Name:        "cache",
+		UseRelPaths: false,
+	}, log)
+
+	imgStore := azure.NewImageStore(rootDir, cacheDir, true, true, storageConstants.DefaultGCDelay,
+		storageConstants.DefaultUntaggedImgeRetentionDelay, true, false, false, log, metrics, nil, store, cacheDriver)
+
+	return store, imgStore
+}
+
+func TestAzureDriver(t *testing.T) {
+	Convey("Azure storage driver", t, func() {
+		So(azure.New(nil).Name(), ShouldEqual, storageConstants.AzureStorageDriverName)
+		So(storage.IsSupportedStorageDriver(storageConstants.AzureStorageDriverName), ShouldBeTrue)
+
+		Convey("Missing credentials are rejected", func() {
+			conf := config.New()
+			conf.Storage.RootDirectory = t.TempDir()
+			conf.Storage.StorageDriver = map[string]interface{}{
+				"name":          storageConstants.AzureStorageDriverName,
+				"rootdirectory": "/zot",
+				"container":     "zot-storage",
+			}
+
+			log := log.NewLogger("debug", "")
+			metrics := monitoring.NewMetricsServer(false, log)
+
+			_, err := storage.New(conf, nil, metrics, log)
+			So(err, ShouldNotBeNil)
+
+			conf.Storage.StorageDriver = nil
+			conf.Storage.SubPaths = map[string]config.StorageConfig{
+				"/a": {
+					RootDirectory: t.TempDir(),
+					StorageDriver: map[string]interface{}{
+						"name":        storageConstants.AzureStorageDriverName,
+						"accountname": "zot",
+					},
+				},
+			}
+
+			_, err = storage.New(conf, nil, metrics, log)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestAzureImageStore(t *testing.T) {
+	skipIt(t)
+
+	Convey("Push and pull images stored in Azure blob storage", t, func() {
+		uuid, err := guuid.NewV4()
+		So(err, ShouldBeNil)
+
+		testDir := path.Join("/oci-repo-test", uuid.String())
+
+		store, imgStore := createObjectsStore(testDir, t.TempDir())
+		defer func() { _ = store.Delete(context.Background(), testDir) }()
+
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		image := CreateRandomImage()
+
+		err = test.WriteImageToFileSystem(image, "first", "1.0", storeController)
+		So(err, ShouldBeNil)
+
+		err = test.WriteImageToFileSystem(image, "second", "1.0", storeController)
+		So(err, ShouldBeNil)
+
+		repos, err := imgStore.GetRepositories()
+		So(err, ShouldBeNil)
+		So(repos, ShouldResemble, []string{"first", "second"})
+
+		_, manifestDigest, _, err := imgStore.GetImageManifest("second", "1.0")
+		So(err, ShouldBeNil)
+		So(manifestDigest, ShouldEqual, image.ManifestDescriptor.Digest)
+
+		layerDigest := image.Manifest.Layers[0].Digest
+
+		// the blob of the second repository is deduped to an empty link
+		fileInfo, err := store.Stat(context.Background(), imgStore.BlobPath("second", layerDigest))
+		So(err, ShouldBeNil)
+		So(fileInfo.Size(), ShouldEqual, 0)
+
+		blob, err := imgStore.GetBlobContent("second", layerDigest)
+		So(err, ShouldBeNil)
+		So(blob, ShouldResemble, image.Layers[0])
+
+		err = imgStore.DeleteImageManifest("first", "1.0", false)
+		So(err, ShouldBeNil)
+
+		err = imgStore.DeleteBlob("first", layerDigest)
+		So(err, ShouldBeNil)
+
+		blob, err = imgStore.GetBlobContent("second", layerDigest)
+		So(err, ShouldBeNil)
+		So(blob, ShouldResemble, image.Layers[0])
+	})
+}
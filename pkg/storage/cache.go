@@ -34,6 +34,12 @@ func CreateCacheDatabaseDriver(storageConfig config.StorageConfig, log zlog.Logg
 			return nil
 		}
 
+		if name == constants.PostgresDriverName {
+			driver, _ := Create("postgres", cache.GetPostgresDriverParameters(storageConfig.CacheDriver), log)
+
+			return driver
+		}
+
 		if name != constants.DynamoDBDriverName {
 			log.Warn().Str("driver", name).Msg("remote cache driver unsupported!")
 
@@ -64,6 +70,10 @@ func Create(dbtype string, parameters interface{}, log zlog.Logger) (cache.Cache
 		{
 			return cache.NewDynamoDBCache(parameters, log), nil
 		}
+	case "postgres":
+		{
+			return cache.NewPostgresCache(parameters, log), nil
+		}
 	default:
 		{
 			return nil, errors.ErrBadConfig
@@ -190,6 +190,73 @@ func (d *BoltDBDriver) GetBlob(digest godigest.Digest) (string, error) {
 	return blobPath.String(), nil
 }
 
+func (d *BoltDBDriver) GetAllBlobs(digest godigest.Digest) ([]string, error) {
+	blobPaths := []string{}
+
+	if err := d.db.View(func(tx *bbolt.Tx) error {
+		root := tx.Bucket([]byte(constants.BlobsCache))
+		if root == nil {
+			// this is a serious failure
+			err := errors.ErrCacheRootBucket
+			d.log.Error().Err(err).Msg("unable to access root bucket")
+
+			return err
+		}
+
+		bucket := root.Bucket([]byte(digest.String()))
+		if bucket == nil {
+			return errors.ErrCacheMiss
+		}
+
+		originBlob := d.getOne(bucket.Bucket([]byte(constants.OriginalBucket)))
+		if originBlob != nil {
+			blobPaths = append(blobPaths, string(originBlob))
+		}
+
+		deduped := bucket.Bucket([]byte(constants.DuplicatesBucket))
+		if deduped == nil {
+			return nil
+		}
+
+		return deduped.ForEach(func(key, _ []byte) error {
+			if string(key) != string(originBlob) {
+				blobPaths = append(blobPaths, string(key))
+			}
+
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return blobPaths, nil
+}
+
+func (d *BoltDBDriver) GetAllDigests() ([]godigest.Digest, error) {
+	digests := []godigest.Digest{}
+
+	if err := d.db.View(func(tx *bbolt.Tx) error {
+		root := tx.Bucket([]byte(constants.BlobsCache))
+		if root == nil {
+			// this is a serious failure
+			err := errors.ErrCacheRootBucket
+			d.log.Error().Err(err).Msg("unable to access root bucket")
+
+			return err
+		}
+
+		return root.ForEach(func(key, _ []byte) error {
+			digests = append(digests, godigest.Digest(key))
+
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return digests, nil
+}
+
 func (d *BoltDBDriver) HasBlob(digest godigest.Digest, blob string) bool {
 	if err := d.db.View(func(tx *bbolt.Tx) error {
 		root := tx.Bucket([]byte(constants.BlobsCache))
@@ -321,3 +388,17 @@ func (d *BoltDBDriver) DeleteBlob(digest godigest.Digest, path string) error {
 
 	return nil
 }
+
+func (d *BoltDBDriver) Ping() error {
+	return d.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(constants.BlobsCache)) == nil {
+			return errors.ErrCacheRootBucket
+		}
+
+		return nil
+	})
+}
+
+func (d *BoltDBDriver) Close() error {
+	return d.db.Close()
+}
@@ -4,6 +4,7 @@ import (
 	"path"
 	"testing"
 
+	godigest "github.com/opencontainers/go-digest"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"zotregistry.io/zot/errors"
@@ -62,3 +63,76 @@ func TestBoltDBCache(t *testing.T) {
 		So(err, ShouldEqual, errors.ErrEmptyValue)
 	})
 }
+
+func TestBoltDBCacheGetAllBlobs(t *testing.T) {
+	Convey("List the blobs of the cache", t, func() {
+		dir := t.TempDir()
+
+		log := log.NewLogger("debug", "")
+
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{dir, "cache_test", true}, log)
+		So(cacheDriver, ShouldNotBeNil)
+
+		digests, err := cacheDriver.GetAllDigests()
+		So(err, ShouldBeNil)
+		So(digests, ShouldBeEmpty)
+
+		_, err = cacheDriver.GetAllBlobs("key")
+		So(err, ShouldEqual, errors.ErrCacheMiss)
+
+		err = cacheDriver.PutBlob("key", path.Join(dir, "second"))
+		So(err, ShouldBeNil)
+
+		err = cacheDriver.PutBlob("key", path.Join(dir, "first"))
+		So(err, ShouldBeNil)
+
+		err = cacheDriver.PutBlob("other", path.Join(dir, "other"))
+		So(err, ShouldBeNil)
+
+		digests, err = cacheDriver.GetAllDigests()
+		So(err, ShouldBeNil)
+		So(digests, ShouldHaveLength, 2)
+		So(digests, ShouldContain, godigest.Digest("key"))
+		So(digests, ShouldContain, godigest.Digest("other"))
+
+		// the original blob is first
+		blobPaths, err := cacheDriver.GetAllBlobs("key")
+		So(err, ShouldBeNil)
+		So(blobPaths, ShouldResemble, []string{"second", "first"})
+
+		err = cacheDriver.DeleteBlob("key", path.Join(dir, "second"))
+		So(err, ShouldBeNil)
+
+		blobPaths, err = cacheDriver.GetAllBlobs("key")
+		So(err, ShouldBeNil)
+		So(blobPaths, ShouldResemble, []string{"first"})
+	})
+}
+
+func TestBoltDBCachePingAndClose(t *testing.T) {
+	Convey("Check the cache and close it", t, func() {
+		dir := t.TempDir()
+
+		log := log.NewLogger("debug", "")
+
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{dir, "cache_test", true}, log)
+		So(cacheDriver, ShouldNotBeNil)
+
+		err := cacheDriver.Ping()
+		So(err, ShouldBeNil)
+
+		err = cacheDriver.Close()
+		So(err, ShouldBeNil)
+
+		err = cacheDriver.Ping()
+		So(err, ShouldNotBeNil)
+
+		// closing again is a no-op, the subpaths can share their image store
+		err = cacheDriver.Close()
+		So(err, ShouldBeNil)
+
+		// the database isn't locked anymore
+		cacheDriver, _ = storage.Create("boltdb", cache.BoltDBDriverParameters{dir, "cache_test", true}, log)
+		So(cacheDriver, ShouldNotBeNil)
+	})
+}
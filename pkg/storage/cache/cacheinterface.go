@@ -20,6 +20,18 @@ type Cache interface {
 	// Delete a blob from the cachedb.
 	DeleteBlob(digest godigest.Digest, path string) error
 
+	// Retrieves all the paths of the blob matching provided digest, the one with the content first.
+	GetAllBlobs(digest godigest.Digest) ([]string, error)
+
+	// Retrieves the digests of all the blobs in cachedb.
+	GetAllDigests() ([]godigest.Digest, error)
+
 	// UsesRelativePaths returns if cache is storing blobs relative to cache rootDir
 	UsesRelativePaths() bool
+
+	// Checks if the cachedb can be reached.
+	Ping() error
+
+	// Releases the resources of the cachedb, it can't be used after.
+	Close() error
 }
@@ -136,6 +136,61 @@ func (d *DynamoDBDriver) GetBlob(digest godigest.Digest) (string, error) {
 	return out.BlobPath[0], nil
 }
 
+// Returns all the paths of the blob, the first one has its content.
+func (d *DynamoDBDriver) GetAllBlobs(digest godigest.Digest) ([]string, error) {
+	resp, err := d.client.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"Digest": &types.AttributeValueMemberS{Value: digest.String()},
+		},
+	})
+	if err != nil {
+		d.log.Error().Err(err).Str("tableName", d.tableName).Msg("failed to get blob")
+
+		return nil, err
+	}
+
+	out := Blob{}
+
+	if resp.Item == nil {
+		return nil, zerr.ErrCacheMiss
+	}
+
+	_ = attributevalue.UnmarshalMap(resp.Item, &out)
+
+	return out.BlobPath, nil
+}
+
+func (d *DynamoDBDriver) GetAllDigests() ([]godigest.Digest, error) {
+	digests := []godigest.Digest{}
+
+	paginator := dynamodb.NewScanPaginator(d.client, &dynamodb.ScanInput{
+		TableName:            aws.String(d.tableName),
+		ProjectionExpression: aws.String("Digest"),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			d.log.Error().Err(err).Str("tableName", d.tableName).Msg("failed to scan blobs")
+
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			out := Blob{}
+
+			if err := attributevalue.UnmarshalMap(item, &out); err != nil {
+				return nil, err
+			}
+
+			digests = append(digests, godigest.Digest(out.Digest))
+		}
+	}
+
+	return digests, nil
+}
+
 func (d *DynamoDBDriver) PutBlob(digest godigest.Digest, path string) error {
 	if path == "" {
 		d.log.Error().Err(zerr.ErrEmptyValue).Str("digest", digest.String()).Msg("empty path provided")
@@ -226,3 +281,16 @@ func (d *DynamoDBDriver) DeleteBlob(digest godigest.Digest, path string) error {
 
 	return nil
 }
+
+func (d *DynamoDBDriver) Ping() error {
+	_, err := d.client.DescribeTable(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(d.tableName),
+	})
+
+	return err
+}
+
+// The dynamodb client doesn't keep any connection open.
+func (d *DynamoDBDriver) Close() error {
+	return nil
+}
@@ -0,0 +1,153 @@
+package cache_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	godigest "github.com/opencontainers/go-digest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage"
+	"zotregistry.io/zot/pkg/storage/cache"
+)
+
+func skipPostgres(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("POSTGRES_URL") == "" {
+		t.Skip("Skipping testing without a postgres server")
+	}
+}
+
+func TestPostgresCacheParameters(t *testing.T) {
+	Convey("Parse the postgres cacheDriver config", t, func() {
+		params := cache.GetPostgresDriverParameters(map[string]interface{}{
+			"name":         "postgres",
+			"url":          "postgres://localhost:5432/zot",
+			"tableprefix":  "registry_",
+			"maxopenconns": float64(10),
+		})
+		So(params, ShouldResemble, cache.PostgresDriverParameters{
+			URL:          "postgres://localhost:5432/zot",
+			TablePrefix:  "registry_",
+			MaxOpenConns: 10,
+		})
+
+		params = cache.GetPostgresDriverParameters(map[string]interface{}{"maxopenconns": 5})
+		So(params, ShouldResemble, cache.PostgresDriverParameters{MaxOpenConns: 5})
+	})
+
+	Convey("Bad params and an unreachable database", t, func() {
+		log := log.NewLogger("debug", "")
+
+		So(func() {
+			_ = cache.NewPostgresCache("bad params", log)
+		}, ShouldPanic)
+
+		cacheDriver := cache.NewPostgresCache(cache.PostgresDriverParameters{
+			URL: "postgres://localhost:1/zot?sslmode=disable&connect_timeout=1",
+		}, log)
+		So(cacheDriver, ShouldBeNil)
+	})
+}
+
+func TestPostgresCache(t *testing.T) {
+	skipPostgres(t)
+
+	Convey("Test the postgres cache", t, func() {
+		log := log.NewLogger("debug", "")
+		dir := t.TempDir()
+
+		tablePrefix := fmt.Sprintf("zot_test_%d_", time.Now().UnixNano())
+
+		cacheDriver, err := storage.Create("postgres", cache.PostgresDriverParameters{
+			URL:         os.Getenv("POSTGRES_URL"),
+			TablePrefix: tablePrefix,
+		}, log)
+		So(err, ShouldBeNil)
+		So(cacheDriver, ShouldNotBeNil)
+
+		defer func() {
+			postgresDB, err := sql.Open("postgres", os.Getenv("POSTGRES_URL"))
+			So(err, ShouldBeNil)
+
+			defer postgresDB.Close()
+
+			_, err = postgresDB.Exec("DROP TABLE IF EXISTS " + tablePrefix + "blobs")
+			So(err, ShouldBeNil)
+		}()
+
+		So(cacheDriver.Name(), ShouldEqual, "postgres")
+		So(cacheDriver.UsesRelativePaths(), ShouldBeFalse)
+
+		digest := godigest.FromString("blob")
+
+		_, err = cacheDriver.GetBlob(digest)
+		So(err, ShouldEqual, zerr.ErrCacheMiss)
+
+		_, err = cacheDriver.GetAllBlobs(digest)
+		So(err, ShouldEqual, zerr.ErrCacheMiss)
+
+		err = cacheDriver.PutBlob(digest, "")
+		So(err, ShouldEqual, zerr.ErrEmptyValue)
+
+		err = cacheDriver.PutBlob(digest, path.Join(dir, "first"))
+		So(err, ShouldBeNil)
+
+		err = cacheDriver.PutBlob(digest, path.Join(dir, "second"))
+		So(err, ShouldBeNil)
+
+		// putting a path again doesn't change the original blob
+		err = cacheDriver.PutBlob(digest, path.Join(dir, "first"))
+		So(err, ShouldBeNil)
+
+		blobPath, err := cacheDriver.GetBlob(digest)
+		So(err, ShouldBeNil)
+		So(blobPath, ShouldEqual, path.Join(dir, "first"))
+
+		blobPaths, err := cacheDriver.GetAllBlobs(digest)
+		So(err, ShouldBeNil)
+		So(blobPaths, ShouldResemble, []string{path.Join(dir, "first"), path.Join(dir, "second")})
+
+		So(cacheDriver.HasBlob(digest, path.Join(dir, "second")), ShouldBeTrue)
+		So(cacheDriver.HasBlob(digest, path.Join(dir, "third")), ShouldBeFalse)
+
+		digests, err := cacheDriver.GetAllDigests()
+		So(err, ShouldBeNil)
+		So(digests, ShouldResemble, []godigest.Digest{digest})
+
+		// the next path becomes the original one
+		err = cacheDriver.DeleteBlob(digest, path.Join(dir, "first"))
+		So(err, ShouldBeNil)
+
+		blobPath, err = cacheDriver.GetBlob(digest)
+		So(err, ShouldBeNil)
+		So(blobPath, ShouldEqual, path.Join(dir, "second"))
+
+		err = cacheDriver.DeleteBlob(digest, path.Join(dir, "second"))
+		So(err, ShouldBeNil)
+
+		_, err = cacheDriver.GetBlob(digest)
+		So(err, ShouldEqual, zerr.ErrCacheMiss)
+
+		digests, err = cacheDriver.GetAllDigests()
+		So(err, ShouldBeNil)
+		So(digests, ShouldBeEmpty)
+
+		err = cacheDriver.Ping()
+		So(err, ShouldBeNil)
+
+		err = cacheDriver.Close()
+		So(err, ShouldBeNil)
+
+		err = cacheDriver.Ping()
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,174 @@
+package chunking
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+const (
+	DefaultAverageChunkSize = 1 << 20 // 1MiB
+	MinAverageChunkSize     = 1 << 12 // 4KiB
+	MaxAverageChunkSize     = 1 << 26 // 64MiB
+
+	// the chunks are between a quarter and eight times the average size.
+	minSizeDivisor = 4
+	maxSizeFactor  = 8
+	// the cut points are harder to find before the average size and easier after, the chunk sizes are closer
+	// to the average (normalized chunking).
+	normalizationBits = 2
+)
+
+// gear maps the bytes to random values for the rolling hash, the chunks must be cut at the same points by all the
+// zot versions for the chunks of the blobs to be shared, so the values are generated with a fixed seed.
+var gear = func() [256]uint64 { //nolint:gochecknoglobals
+	var table [256]uint64
+
+	// splitmix64
+	state := uint64(0x7a6f742d63686e6b) //nolint:gomnd
+
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		value := state
+		value = (value ^ (value >> 30)) * 0xbf58476d1ce4e5b9 //nolint:gomnd
+		value = (value ^ (value >> 27)) * 0x94d049bb133111eb //nolint:gomnd
+		table[i] = value ^ (value >> 31)                     //nolint:gomnd
+	}
+
+	return table
+}()
+
+// Chunker splits a stream in chunks with content-defined chunking: the chunks are cut where the rolling hash of
+// the last bytes matches a mask, so inserting or removing bytes only changes the chunks around them and the
+// streams differing slightly share most of their chunks.
+type Chunker struct {
+	reader  io.Reader
+	buf     []byte
+	start   int
+	end     int
+	eof     bool
+	minSize int
+	avgSize int
+	maxSize int
+	maskS   uint64
+	maskL   uint64
+}
+
+// NewChunker returns a chunker of the reader, the average size is rounded down to a power of two and clamped to
+// the supported range.
+func NewChunker(reader io.Reader, averageSize int) *Chunker {
+	averageSize = NormalizeAverageChunkSize(averageSize)
+	avgBits := bits.Len(uint(averageSize)) - 1
+
+	return &Chunker{
+		reader:  reader,
+		buf:     make([]byte, 2*maxSizeFactor*averageSize), //nolint:gomnd
+		minSize: averageSize / minSizeDivisor,
+		avgSize: averageSize,
+		maxSize: maxSizeFactor * averageSize,
+		maskS:   1<<(avgBits+normalizationBits) - 1,
+		maskL:   1<<(avgBits-normalizationBits) - 1,
+	}
+}
+
+// NormalizeAverageChunkSize returns the average chunk size used by the chunker for the given one.
+func NormalizeAverageChunkSize(averageSize int) int {
+	if averageSize <= 0 {
+		return DefaultAverageChunkSize
+	}
+
+	if averageSize < MinAverageChunkSize {
+		return MinAverageChunkSize
+	}
+
+	if averageSize > MaxAverageChunkSize {
+		return MaxAverageChunkSize
+	}
+
+	return 1 << (bits.Len(uint(averageSize)) - 1)
+}
+
+// Next returns the next chunk, valid until the next call, or io.EOF after the last one.
+func (c *Chunker) Next() ([]byte, error) {
+	if err := c.fill(); err != nil {
+		return nil, err
+	}
+
+	if c.start == c.end {
+		return nil, io.EOF
+	}
+
+	size := c.cut(c.buf[c.start:c.end])
+	chunk := c.buf[c.start : c.start+size]
+	c.start += size
+
+	return chunk, nil
+}
+
+// fill reads until the buffer holds a chunk of the maximum size or the end of the stream.
+func (c *Chunker) fill() error {
+	if c.eof || c.end-c.start >= c.maxSize {
+		return nil
+	}
+
+	// the remaining bytes are moved to the start of the buffer
+	c.end = copy(c.buf, c.buf[c.start:c.end])
+	c.start = 0
+
+	for c.end < len(c.buf) {
+		n, err := c.reader.Read(c.buf[c.end:])
+		c.end += n
+
+		if errors.Is(err, io.EOF) {
+			c.eof = true
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if c.end >= c.maxSize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (c *Chunker) cut(data []byte) int {
+	size := len(data)
+	if size <= c.minSize {
+		return size
+	}
+
+	if size > c.maxSize {
+		size = c.maxSize
+	}
+
+	normalSize := c.avgSize
+	if normalSize > size {
+		normalSize = size
+	}
+
+	var hash uint64
+
+	index := c.minSize
+
+	for ; index < normalSize; index++ {
+		hash = (hash << 1) + gear[data[index]]
+		if hash&c.maskS == 0 {
+			return index + 1
+		}
+	}
+
+	for ; index < size; index++ {
+		hash = (hash << 1) + gear[data[index]]
+		if hash&c.maskL == 0 {
+			return index + 1
+		}
+	}
+
+	return size
+}
@@ -0,0 +1,312 @@
+package chunking_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	godigest "github.com/opencontainers/go-digest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage/chunking"
+	"zotregistry.io/zot/pkg/storage/local"
+)
+
+const averageChunkSize = 4096
+
+func randomContent(seed int64, size int) []byte {
+	content := make([]byte, size)
+	rand.New(rand.NewSource(seed)).Read(content) //nolint:gosec
+
+	return content
+}
+
+func chunkDigests(content []byte) []godigest.Digest {
+	chunker := chunking.NewChunker(bytes.NewReader(content), averageChunkSize)
+	digests := []godigest.Digest{}
+
+	for {
+		chunk, err := chunker.Next()
+		if err == io.EOF { //nolint:errorlint
+			return digests
+		}
+
+		So(err, ShouldBeNil)
+		So(len(chunk), ShouldBeLessThanOrEqualTo, 8*averageChunkSize)
+
+		digests = append(digests, godigest.FromBytes(chunk))
+	}
+}
+
+func countFiles(dir string) int {
+	count := 0
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			count += countFiles(path.Join(dir, entry.Name()))
+		} else {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestChunker(t *testing.T) {
+	Convey("Split the content with content-defined chunking", t, func() {
+		content := randomContent(1, 256*1024)
+
+		chunker := chunking.NewChunker(bytes.NewReader(content), averageChunkSize)
+		reassembled := []byte{}
+		chunks := 0
+
+		for {
+			chunk, err := chunker.Next()
+			if err == io.EOF { //nolint:errorlint
+				break
+			}
+
+			So(err, ShouldBeNil)
+
+			reassembled = append(reassembled, chunk...)
+			chunks++
+		}
+
+		So(reassembled, ShouldResemble, content)
+		So(chunks, ShouldBeGreaterThan, 256*1024/(8*averageChunkSize))
+
+		// the chunks are cut at the same points
+		So(chunkDigests(content), ShouldResemble, chunkDigests(content))
+
+		Convey("Inserting bytes only changes the chunks around them", func() {
+			digests := chunkDigests(content)
+
+			modified := append([]byte("inserted bytes"), content...)
+			modifiedDigests := chunkDigests(modified)
+
+			shared := 0
+
+			for _, digest := range modifiedDigests {
+				for _, other := range digests {
+					if digest == other {
+						shared++
+
+						break
+					}
+				}
+			}
+
+			So(shared, ShouldBeGreaterThanOrEqualTo, len(digests)-2)
+		})
+
+		Convey("The average chunk size is normalized", func() {
+			So(chunking.NormalizeAverageChunkSize(0), ShouldEqual, chunking.DefaultAverageChunkSize)
+			So(chunking.NormalizeAverageChunkSize(1), ShouldEqual, chunking.MinAverageChunkSize)
+			So(chunking.NormalizeAverageChunkSize(1<<30), ShouldEqual, chunking.MaxAverageChunkSize)
+			So(chunking.NormalizeAverageChunkSize(3<<20), ShouldEqual, 2<<20)
+		})
+
+		Convey("An empty stream has no chunks", func() {
+			_, err := chunking.NewChunker(bytes.NewReader([]byte{}), averageChunkSize).Next()
+			So(err, ShouldEqual, io.EOF)
+		})
+	})
+}
+
+func TestDriver(t *testing.T) {
+	Convey("Store the blobs in chunks", t, func() {
+		rootDir := t.TempDir()
+		driver := chunking.NewDriver(local.New(true), rootDir, chunking.Parameters{
+			MinBlobSize:      64 * 1024,
+			AverageChunkSize: averageChunkSize,
+		}, log.NewLogger("debug", ""))
+
+		content := randomContent(2, 256*1024)
+		digest := godigest.FromBytes(content)
+		blobPath := path.Join(rootDir, "repo", "blobs", "sha256", digest.Encoded())
+		uploadPath := path.Join(rootDir, "repo", ".uploads", "upload")
+
+		_, err := driver.WriteFile(uploadPath, content)
+		So(err, ShouldBeNil)
+
+		// the uploads are stored whole
+		fileInfo, err := os.Stat(uploadPath)
+		So(err, ShouldBeNil)
+		So(fileInfo.Size(), ShouldEqual, len(content))
+
+		err = driver.Move(uploadPath, blobPath)
+		So(err, ShouldBeNil)
+
+		_, err = os.Stat(uploadPath)
+		So(os.IsNotExist(err), ShouldBeTrue)
+
+		// the recipe is stored at the path of the blob
+		fileInfo, err = os.Stat(blobPath)
+		So(err, ShouldBeNil)
+		So(fileInfo.Size(), ShouldBeLessThan, len(content))
+
+		chunks := countFiles(path.Join(rootDir, chunking.ChunksDir))
+		So(chunks, ShouldBeGreaterThan, 1)
+
+		driverInfo, err := driver.Stat(blobPath)
+		So(err, ShouldBeNil)
+		So(driverInfo.Size(), ShouldEqual, len(content))
+
+		buf, err := driver.ReadFile(blobPath)
+		So(err, ShouldBeNil)
+		So(buf, ShouldResemble, content)
+
+		for _, offset := range []int64{0, 1, 100 * 1024, int64(len(content)) - 1, int64(len(content))} {
+			reader, err := driver.Reader(blobPath, offset)
+			So(err, ShouldBeNil)
+
+			buf, err := io.ReadAll(reader)
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, content[offset:])
+
+			So(reader.Close(), ShouldBeNil)
+		}
+
+		Convey("The blobs differing slightly share their chunks", func() {
+			modified := append([]byte("prefix"), content...)
+			modifiedDigest := godigest.FromBytes(modified)
+			modifiedPath := path.Join(rootDir, "other", "blobs", "sha256", modifiedDigest.Encoded())
+
+			_, err := driver.WriteFile(modifiedPath, modified)
+			So(err, ShouldBeNil)
+
+			buf, err := driver.ReadFile(modifiedPath)
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, modified)
+
+			So(countFiles(path.Join(rootDir, chunking.ChunksDir)), ShouldBeLessThanOrEqualTo, chunks+2)
+
+			// whatever the digest algorithm of the blob
+			sha512Path := path.Join(rootDir, "repo", "blobs", "sha512",
+				godigest.SHA512.FromBytes(content).Encoded())
+
+			_, err = driver.WriteFile(sha512Path, content)
+			So(err, ShouldBeNil)
+
+			So(countFiles(path.Join(rootDir, chunking.ChunksDir)), ShouldBeLessThanOrEqualTo, chunks+2)
+		})
+
+		Convey("The small blobs are stored whole, unless they look like recipes", func() {
+			small := []byte("small blob")
+			smallPath := path.Join(rootDir, "repo", "blobs", "sha256", godigest.FromBytes(small).Encoded())
+
+			_, err := driver.WriteFile(smallPath, small)
+			So(err, ShouldBeNil)
+
+			buf, err := os.ReadFile(smallPath)
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, small)
+
+			fake := []byte("zot.chunked-blob.v1\n" + `{"size":1,"chunks":[]}`)
+			fakePath := path.Join(rootDir, "repo", "blobs", "sha256", godigest.FromBytes(fake).Encoded())
+
+			err = os.WriteFile(uploadPath, fake, 0o600)
+			So(err, ShouldBeNil)
+
+			err = driver.Move(uploadPath, fakePath)
+			So(err, ShouldBeNil)
+
+			buf, err = driver.ReadFile(fakePath)
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, fake)
+
+			fileInfo, err := driver.Stat(fakePath)
+			So(err, ShouldBeNil)
+			So(fileInfo.Size(), ShouldEqual, len(fake))
+		})
+
+		Convey("The recipes are moved as is", func() {
+			otherPath := path.Join(rootDir, "other", "blobs", "sha256", digest.Encoded())
+
+			err := driver.Move(blobPath, otherPath)
+			So(err, ShouldBeNil)
+
+			buf, err := driver.ReadFile(otherPath)
+			So(err, ShouldBeNil)
+			So(buf, ShouldResemble, content)
+
+			So(countFiles(path.Join(rootDir, chunking.ChunksDir)), ShouldEqual, chunks)
+		})
+
+		Convey("The walks skip the chunks", func() {
+			walked := []string{}
+
+			err := driver.Walk(rootDir, func(fileInfo storagedriver.FileInfo) error {
+				walked = append(walked, fileInfo.Path())
+
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(walked, ShouldContain, blobPath)
+
+			for _, walkedPath := range walked {
+				So(strings.HasPrefix(walkedPath, path.Join(rootDir, chunking.ChunksDir)), ShouldBeFalse)
+			}
+		})
+
+		Convey("The unreferenced chunks are removed", func() {
+			removed, err := driver.CollectGarbage(0)
+			So(err, ShouldBeNil)
+			So(removed, ShouldEqual, 0)
+
+			err = driver.Delete(blobPath)
+			So(err, ShouldBeNil)
+
+			// the chunks written recently are kept
+			removed, err = driver.CollectGarbage(time.Hour)
+			So(err, ShouldBeNil)
+			So(removed, ShouldEqual, 0)
+
+			removed, err = driver.CollectGarbage(0)
+			So(err, ShouldBeNil)
+			So(removed, ShouldEqual, chunks)
+			So(countFiles(path.Join(rootDir, chunking.ChunksDir)), ShouldEqual, 0)
+
+			// the content of the blob is gone
+			_, err = driver.ReadFile(blobPath)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("A missing chunk can't be read", func() {
+			err := os.RemoveAll(path.Join(rootDir, chunking.ChunksDir))
+			So(err, ShouldBeNil)
+
+			reader, err := driver.Reader(blobPath, 0)
+			So(err, ShouldBeNil)
+
+			_, err = io.ReadAll(reader)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("An invalid recipe can't be read", func() {
+			err := os.WriteFile(blobPath, []byte("zot.chunked-blob.v1\n{"), 0o600)
+			So(err, ShouldBeNil)
+
+			_, err = driver.Stat(blobPath)
+			So(err, ShouldNotBeNil)
+
+			_, err = driver.Reader(blobPath, 0)
+			So(err, ShouldNotBeNil)
+
+			_, err = driver.CollectGarbage(0)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,479 @@
+package chunking
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	guuid "github.com/gofrs/uuid"
+	godigest "github.com/opencontainers/go-digest"
+
+	zerr "zotregistry.io/zot/errors"
+	zlog "zotregistry.io/zot/pkg/log"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	// ChunksDir is the directory of the chunks in the root directory of the image store.
+	ChunksDir = "_chunks"
+
+	DefaultMinBlobSize = 8 << 20 // 8MiB
+
+	// the files at the paths of the blobs starting with it are recipes, the blobs starting with it are always
+	// chunked so their content isn't mistaken for a recipe.
+	recipeMagic = "zot.chunked-blob.v1\n"
+)
+
+// Recipe lists the chunks of a chunked blob in order, it's stored at the path of the blob after recipeMagic.
+type Recipe struct {
+	Size   int64   `json:"size"`
+	Chunks []Chunk `json:"chunks"`
+}
+
+type Chunk struct {
+	Digest godigest.Digest `json:"digest"`
+	Size   int64           `json:"size"`
+}
+
+type Parameters struct {
+	MinBlobSize      int64 // the blobs smaller than this are stored whole, default is 8MiB
+	AverageChunkSize int   // default is 1MiB
+}
+
+/*
+Driver splits the blobs moved or written to their paths in chunks stored once in the chunks directory, whatever
+the digest algorithm of the blobs, and writes their recipes at the paths of the blobs instead of their content.
+The recipes are read back transparently: the stat of a chunked blob has the size of its content and its readers
+reassemble its chunks. The other files, e.g. the manifests, the indexes and the uploads, are stored as is.
+
+The chunks aren't removed with the blobs, CollectGarbage removes the chunks which aren't referenced by any recipe.
+*/
+type Driver struct {
+	storageTypes.Driver
+	rootDir          string
+	chunksDir        string
+	minBlobSize      int64
+	averageChunkSize int
+	// the chunks are written under the read lock and collected under the write lock
+	gcLock *sync.RWMutex
+	log    zlog.Logger
+}
+
+func NewDriver(storeDriver storageTypes.Driver, rootDir string, params Parameters, log zlog.Logger) *Driver {
+	minBlobSize := params.MinBlobSize
+	if minBlobSize <= 0 {
+		minBlobSize = DefaultMinBlobSize
+	}
+
+	return &Driver{
+		Driver:           storeDriver,
+		rootDir:          rootDir,
+		chunksDir:        path.Join(rootDir, ChunksDir),
+		minBlobSize:      minBlobSize,
+		averageChunkSize: NormalizeAverageChunkSize(params.AverageChunkSize),
+		gcLock:           &sync.RWMutex{},
+		log:              log,
+	}
+}
+
+func (d *Driver) Stat(filePath string) (storagedriver.FileInfo, error) {
+	fileInfo, err := d.Driver.Stat(filePath)
+	if err != nil || fileInfo.IsDir() || fileInfo.Size() < int64(len(recipeMagic)) || !d.isBlobPath(filePath) {
+		return fileInfo, err
+	}
+
+	recipe, err := d.getRecipe(filePath)
+	if err != nil || recipe == nil {
+		return fileInfo, err
+	}
+
+	return storagedriver.FileInfoInternal{FileInfoFields: storagedriver.FileInfoFields{
+		Path:    fileInfo.Path(),
+		Size:    recipe.Size,
+		ModTime: fileInfo.ModTime(),
+		IsDir:   false,
+	}}, nil
+}
+
+func (d *Driver) Reader(filePath string, offset int64) (io.ReadCloser, error) {
+	if !d.isBlobPath(filePath) {
+		return d.Driver.Reader(filePath, offset)
+	}
+
+	recipe, err := d.getRecipe(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if recipe == nil {
+		return d.Driver.Reader(filePath, offset)
+	}
+
+	return d.newRecipeReader(recipe, offset), nil
+}
+
+func (d *Driver) ReadFile(filePath string) ([]byte, error) {
+	if !d.isBlobPath(filePath) {
+		return d.Driver.ReadFile(filePath)
+	}
+
+	reader, err := d.Reader(filePath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func (d *Driver) WriteFile(filePath string, content []byte) (int, error) {
+	if !d.isBlobPath(filePath) ||
+		(int64(len(content)) < d.minBlobSize && !bytes.HasPrefix(content, []byte(recipeMagic))) {
+		return d.Driver.WriteFile(filePath, content)
+	}
+
+	if err := d.chunkBlob(bytes.NewReader(content), filePath); err != nil {
+		return -1, err
+	}
+
+	return len(content), nil
+}
+
+func (d *Driver) Move(sourcePath string, destPath string) error {
+	if !d.isBlobPath(destPath) {
+		return d.Driver.Move(sourcePath, destPath)
+	}
+
+	fileInfo, err := d.Driver.Stat(sourcePath)
+	if err != nil || fileInfo.IsDir() {
+		return d.Driver.Move(sourcePath, destPath)
+	}
+
+	hasMagic, err := d.hasRecipeMagic(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	// the recipes are moved as is
+	if hasMagic && d.isBlobPath(sourcePath) {
+		return d.Driver.Move(sourcePath, destPath)
+	}
+
+	if fileInfo.Size() < d.minBlobSize && !hasMagic {
+		return d.Driver.Move(sourcePath, destPath)
+	}
+
+	reader, err := d.Driver.Reader(sourcePath, 0)
+	if err != nil {
+		return err
+	}
+
+	err = d.chunkBlob(reader, destPath)
+	reader.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return d.Driver.Delete(sourcePath)
+}
+
+// Walk doesn't walk the chunks directory, the sizes of the chunked blobs are those of their recipes.
+func (d *Driver) Walk(walkPath string, walkFn storagedriver.WalkFn) error {
+	return d.Driver.Walk(walkPath, func(fileInfo storagedriver.FileInfo) error {
+		if fileInfo.IsDir() && fileInfo.Path() == d.chunksDir {
+			return storagedriver.ErrSkipDir
+		}
+
+		return walkFn(fileInfo)
+	})
+}
+
+/*
+CollectGarbage removes the chunks which aren't referenced by the recipes of the blobs, the chunks written in the
+last delay are kept as their recipes may not be written yet by another zot instance sharing the storage.
+It returns the number of chunks removed.
+*/
+func (d *Driver) CollectGarbage(delay time.Duration) (int, error) {
+	d.gcLock.Lock()
+	defer d.gcLock.Unlock()
+
+	referenced := map[string]bool{}
+
+	err := d.Driver.Walk(d.rootDir, func(fileInfo storagedriver.FileInfo) error {
+		if fileInfo.IsDir() {
+			if fileInfo.Path() == d.chunksDir {
+				return storagedriver.ErrSkipDir
+			}
+
+			return nil
+		}
+
+		if fileInfo.Size() < int64(len(recipeMagic)) || !d.isBlobPath(fileInfo.Path()) {
+			return nil
+		}
+
+		recipe, err := d.getRecipe(fileInfo.Path())
+		if err != nil {
+			// the blob was removed since it was listed
+			if errors.As(err, &storagedriver.PathNotFoundError{}) {
+				return nil
+			}
+
+			return err
+		}
+
+		if recipe != nil {
+			for _, chunk := range recipe.Chunks {
+				referenced[d.chunkPath(chunk.Digest)] = true
+			}
+		}
+
+		return nil
+	})
+	if err != nil && !errors.As(err, &storagedriver.PathNotFoundError{}) {
+		return 0, err
+	}
+
+	removed := 0
+
+	err = d.Driver.Walk(d.chunksDir, func(fileInfo storagedriver.FileInfo) error {
+		if fileInfo.IsDir() || referenced[fileInfo.Path()] || time.Since(fileInfo.ModTime()) < delay {
+			return nil
+		}
+
+		if err := d.Driver.Delete(fileInfo.Path()); err != nil &&
+			!errors.As(err, &storagedriver.PathNotFoundError{}) {
+			return err
+		}
+
+		removed++
+
+		return nil
+	})
+	if err != nil && !errors.As(err, &storagedriver.PathNotFoundError{}) {
+		return removed, err
+	}
+
+	if removed > 0 {
+		d.log.Info().Int("chunks", removed).Str("rootDir", d.rootDir).Msg("chunk dedupe: removed unreferenced chunks")
+	}
+
+	return removed, nil
+}
+
+// isBlobPath tells if the path is the one of a blob of a repository, e.g. <root>/<repo>/blobs/sha256/<hex>.
+func (d *Driver) isBlobPath(filePath string) bool {
+	rel, err := filepath.Rel(d.rootDir, filePath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	last := len(parts) - 1
+
+	//nolint:gomnd
+	if len(parts) < 4 || parts[last-2] != "blobs" {
+		return false
+	}
+
+	return godigest.NewDigestFromEncoded(godigest.Algorithm(parts[last-1]), parts[last]).Validate() == nil
+}
+
+func (d *Driver) chunkPath(digest godigest.Digest) string {
+	return path.Join(d.chunksDir, digest.Algorithm().String(), digest.Encoded())
+}
+
+func (d *Driver) hasRecipeMagic(filePath string) (bool, error) {
+	reader, err := d.Driver.Reader(filePath, 0)
+	if err != nil {
+		return false, err
+	}
+
+	defer reader.Close()
+
+	magic := make([]byte, len(recipeMagic))
+
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return string(magic) == recipeMagic, nil
+}
+
+// getRecipe returns the recipe at the path of the blob, or nil if the blob isn't chunked.
+func (d *Driver) getRecipe(filePath string) (*Recipe, error) {
+	reader, err := d.Driver.Reader(filePath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	bufReader := bufio.NewReader(reader)
+
+	magic, err := bufReader.Peek(len(recipeMagic))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if string(magic) != recipeMagic {
+		return nil, nil
+	}
+
+	if _, err := bufReader.Discard(len(recipeMagic)); err != nil {
+		return nil, err
+	}
+
+	var recipe Recipe
+
+	if err := json.NewDecoder(bufReader).Decode(&recipe); err != nil {
+		d.log.Error().Err(err).Str("blob", filePath).Msg("chunk dedupe: invalid recipe")
+
+		return nil, fmt.Errorf("%w: %s: %w", zerr.ErrBadChunkRecipe, filePath, err)
+	}
+
+	return &recipe, nil
+}
+
+// chunkBlob stores the chunks of the content and then its recipe at the path of the blob.
+func (d *Driver) chunkBlob(reader io.Reader, blobPath string) error {
+	d.gcLock.RLock()
+	defer d.gcLock.RUnlock()
+
+	recipe := Recipe{Chunks: []Chunk{}}
+	chunker := NewChunker(reader, d.averageChunkSize)
+
+	for {
+		chunk, err := chunker.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// the chunks have the same digest whatever the digest algorithm of the blob
+		digest := godigest.FromBytes(chunk)
+
+		if err := d.putChunk(digest, chunk); err != nil {
+			return err
+		}
+
+		recipe.Chunks = append(recipe.Chunks, Chunk{Digest: digest, Size: int64(len(chunk))})
+		recipe.Size += int64(len(chunk))
+	}
+
+	buf, err := json.Marshal(recipe)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Driver.WriteFile(blobPath, append([]byte(recipeMagic), buf...))
+
+	return err
+}
+
+// putChunk writes the chunk if it isn't stored yet, the readers never see a partially written chunk.
+func (d *Driver) putChunk(digest godigest.Digest, chunk []byte) error {
+	chunkPath := d.chunkPath(digest)
+
+	if _, err := d.Driver.Stat(chunkPath); err == nil {
+		return nil
+	}
+
+	uuid, err := guuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := chunkPath + "." + uuid.String()
+
+	if _, err := d.Driver.WriteFile(tmpPath, chunk); err != nil {
+		d.log.Error().Err(err).Str("chunk", chunkPath).Msg("chunk dedupe: failed to write chunk")
+
+		return err
+	}
+
+	return d.Driver.Move(tmpPath, chunkPath)
+}
+
+// recipeReader reads the chunks of a recipe in order, starting at an offset of the blob.
+type recipeReader struct {
+	driver  *Driver
+	chunks  []Chunk
+	offset  int64 // in the first chunk
+	current io.ReadCloser
+}
+
+func (d *Driver) newRecipeReader(recipe *Recipe, offset int64) *recipeReader {
+	chunks := recipe.Chunks
+
+	for len(chunks) > 0 && offset >= chunks[0].Size {
+		offset -= chunks[0].Size
+		chunks = chunks[1:]
+	}
+
+	return &recipeReader{driver: d, chunks: chunks, offset: offset}
+}
+
+func (reader *recipeReader) Read(buf []byte) (int, error) {
+	for {
+		if reader.current == nil {
+			if len(reader.chunks) == 0 {
+				return 0, io.EOF
+			}
+
+			chunkReader, err := reader.driver.Driver.Reader(reader.driver.chunkPath(reader.chunks[0].Digest),
+				reader.offset)
+			if err != nil {
+				return 0, err
+			}
+
+			reader.current = chunkReader
+			reader.chunks = reader.chunks[1:]
+			reader.offset = 0
+		}
+
+		n, err := reader.current.Read(buf)
+		if errors.Is(err, io.EOF) {
+			reader.current.Close()
+			reader.current = nil
+
+			if n == 0 {
+				continue
+			}
+
+			return n, nil
+		}
+
+		return n, err
+	}
+}
+
+func (reader *recipeReader) Close() error {
+	if reader.current == nil {
+		return nil
+	}
+
+	return reader.current.Close()
+}
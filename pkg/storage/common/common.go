@@ -157,11 +157,14 @@ func ValidateManifest(imgStore storageTypes.ImageStore, repo, reference, mediaTy
 	return "", nil
 }
 
+// GetAndValidateRequestDigest returns the digest of the body, with the algorithm of the reference if it's a digest.
 func GetAndValidateRequestDigest(body []byte, digestStr string, log zlog.Logger) (godigest.Digest, error) {
 	bodyDigest := godigest.FromBytes(body)
 
 	d, err := godigest.Parse(digestStr)
 	if err == nil {
+		bodyDigest = d.Algorithm().FromBytes(body)
+
 		if d.String() != bodyDigest.String() {
 			log.Error().Str("actual", bodyDigest.String()).Str("expected", d.String()).
 				Msg("manifest digest is not valid")
@@ -420,7 +423,7 @@ func PruneImageManifestsFromIndex(imgStore storageTypes.ImageStore, repo string,
 	inUse := map[string]uint{}
 
 	for _, manifest := range imgIndex.Manifests {
-		inUse[manifest.Digest.Encoded()]++
+		inUse[manifest.Digest.String()]++
 	}
 
 	for _, otherIndex := range otherImgIndexes {
@@ -430,9 +433,9 @@ func PruneImageManifestsFromIndex(imgStore storageTypes.ImageStore, repo string,
 		}
 
 		for _, omanifest := range oindex.Manifests {
-			_, ok := inUse[omanifest.Digest.Encoded()]
+			_, ok := inUse[omanifest.Digest.String()]
 			if ok {
-				inUse[omanifest.Digest.Encoded()]++
+				inUse[omanifest.Digest.String()]++
 			}
 		}
 	}
@@ -455,7 +458,7 @@ func PruneImageManifestsFromIndex(imgStore storageTypes.ImageStore, repo string,
 			continue
 		}
 
-		count, ok := inUse[outManifest.Digest.Encoded()]
+		count, ok := inUse[outManifest.Digest.String()]
 		if !ok {
 			prunedManifests = append(prunedManifests, outManifest)
 
@@ -630,6 +633,61 @@ func AddImageIndexBlobsToReferences(imgStore storageTypes.ImageStore,
 	return nil
 }
 
+// GetManifestReferences returns the digests the manifest keeps from being garbage collected: the config and layers
+// of an image, the manifests of an index, the blobs of an ORAS artifact and their subject.
+func GetManifestReferences(mediaType string, body []byte) ([]godigest.Digest, error) {
+	refs := []godigest.Digest{}
+
+	switch mediaType {
+	case ispec.MediaTypeImageManifest:
+		var manifest ispec.Manifest
+
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, manifest.Config.Digest)
+
+		for _, layer := range manifest.Layers {
+			refs = append(refs, layer.Digest)
+		}
+
+		if manifest.Subject != nil {
+			refs = append(refs, manifest.Subject.Digest)
+		}
+	case ispec.MediaTypeImageIndex:
+		var index ispec.Index
+
+		if err := json.Unmarshal(body, &index); err != nil {
+			return nil, err
+		}
+
+		for _, manifest := range index.Manifests {
+			refs = append(refs, manifest.Digest)
+		}
+
+		if index.Subject != nil {
+			refs = append(refs, index.Subject.Digest)
+		}
+	case oras.MediaTypeArtifactManifest:
+		var manifest oras.Manifest
+
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			return nil, err
+		}
+
+		for _, blob := range manifest.Blobs {
+			refs = append(refs, blob.Digest)
+		}
+
+		if manifest.Subject != nil {
+			refs = append(refs, manifest.Subject.Digest)
+		}
+	}
+
+	return refs, nil
+}
+
 func AddIndexBlobToReferences(imgStore storageTypes.ImageStore,
 	repo string, index ispec.Index, refBlobs map[string]bool, log zlog.Logger,
 ) error {
@@ -682,8 +740,8 @@ func ApplyLinter(imgStore storageTypes.ImageStore, linter Lint, repo string, des
 ) (bool, error) {
 	pass := true
 
-	// we'll skip anything that's not a image manifest
-	if descriptor.MediaType != ispec.MediaTypeImageManifest {
+	// we'll skip anything that's not a image manifest or index
+	if descriptor.MediaType != ispec.MediaTypeImageManifest && descriptor.MediaType != ispec.MediaTypeImageIndex {
 		return pass, nil
 	}
 
@@ -702,13 +760,38 @@ func ApplyLinter(imgStore storageTypes.ImageStore, linter Lint, repo string, des
 	return pass, nil
 }
 
+// IsCosignTag tells whether the tag is named after the digest of a manifest like the cosign tags, e.g.
+// sha256-<encoded>.sig or sha512-<encoded>.sbom.
+func IsCosignTag(tag string) bool {
+	algorithm, _, found := strings.Cut(tag, "-")
+
+	return found && godigest.Algorithm(algorithm).Available()
+}
+
+// GetCosignSignedDigest parses the digest of the manifest signed by a cosign signature from its tag, e.g.
+// sha512-<encoded>.sig, the tags which don't name a valid digest aren't cosign signatures.
+func GetCosignSignedDigest(tag string) (godigest.Digest, bool) {
+	if !IsCosignTag(tag) || !strings.HasSuffix(tag, "."+cosignSignatureTagSuffix) {
+		return "", false
+	}
+
+	algorithm, encoded, _ := strings.Cut(strings.TrimSuffix(tag, "."+cosignSignatureTagSuffix), "-")
+
+	digest := godigest.NewDigestFromEncoded(godigest.Algorithm(algorithm), encoded)
+	if err := digest.Validate(); err != nil {
+		return "", false
+	}
+
+	return digest, true
+}
+
 func IsSignature(descriptor ispec.Descriptor) bool {
 	tag := descriptor.Annotations[ispec.AnnotationRefName]
 
 	switch descriptor.MediaType {
 	case ispec.MediaTypeImageManifest:
 		// is cosgin signature
-		if strings.HasPrefix(tag, "sha256-") && strings.HasSuffix(tag, cosignSignatureTagSuffix) {
+		if IsCosignTag(tag) && strings.HasSuffix(tag, cosignSignatureTagSuffix) {
 			return true
 		}
 
@@ -1047,13 +1130,16 @@ func (dt *dedupeTask) DoWork(ctx context.Context) error {
 	GCTaskGenerator takes all repositories found in the storage.imagestore
 
 and it will execute garbage collection for each repository by creating a task
-for each repository and pushing it to the task scheduler.
+for each repository and pushing it to the task scheduler, then repair the deduped blobs
+of all the repositories.
 */
 type GCTaskGenerator struct {
 	ImgStore storageTypes.ImageStore
 	lastRepo string
 	nextRun  time.Time
 	done     bool
+	// dedupeGC is set once the deduped blobs were repaired
+	dedupeGC bool
 	rand     *rand.Rand
 }
 
@@ -1078,6 +1164,12 @@ func (gen *GCTaskGenerator) Next() (scheduler.Task, error) {
 	}
 
 	if repo == "" {
+		if !gen.dedupeGC {
+			gen.dedupeGC = true
+
+			return NewDedupeGCTask(gen.ImgStore), nil
+		}
+
 		gen.done = true
 
 		return nil, nil
@@ -1099,6 +1191,7 @@ func (gen *GCTaskGenerator) IsReady() bool {
 func (gen *GCTaskGenerator) Reset() {
 	gen.lastRepo = ""
 	gen.done = false
+	gen.dedupeGC = false
 	gen.nextRun = time.Time{}
 }
 
@@ -1116,3 +1209,111 @@ func (gct *gcTask) DoWork(ctx context.Context) error {
 	// run task
 	return gct.imgStore.RunGCRepo(gct.repo)
 }
+
+/*
+	DedupeGCTaskGenerator repairs the deduped blobs of all the repositories
+
+found in the storage.imagestore with a single task, e.g. after their garbage collection.
+*/
+type DedupeGCTaskGenerator struct {
+	ImgStore  storageTypes.ImageStore
+	generated bool
+	done      bool
+}
+
+func (gen *DedupeGCTaskGenerator) Next() (scheduler.Task, error) {
+	if gen.generated {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.generated = true
+
+	return NewDedupeGCTask(gen.ImgStore), nil
+}
+
+func (gen *DedupeGCTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *DedupeGCTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *DedupeGCTaskGenerator) Reset() {
+	gen.generated = false
+	gen.done = false
+}
+
+type dedupeGCTask struct {
+	imgStore storageTypes.ImageStore
+}
+
+func NewDedupeGCTask(imgStore storageTypes.ImageStore) *dedupeGCTask {
+	return &dedupeGCTask{imgStore}
+}
+
+func (dgt *dedupeGCTask) DoWork(ctx context.Context) error {
+	return dgt.imgStore.RunGCDedupedBlobs()
+}
+
+/*
+	UploadExpiryTaskGenerator takes all repositories found in the storage.imagestore
+
+and it will expire the blob uploads not written for longer than the TTL by creating a task
+for each repository and pushing it to the task scheduler.
+*/
+type UploadExpiryTaskGenerator struct {
+	ImgStore storageTypes.ImageStore
+	TTL      time.Duration
+	lastRepo string
+	done     bool
+}
+
+func (gen *UploadExpiryTaskGenerator) Next() (scheduler.Task, error) {
+	repo, err := gen.ImgStore.GetNextRepository(gen.lastRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if repo == "" {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.lastRepo = repo
+
+	return NewUploadExpiryTask(gen.ImgStore, repo, gen.TTL), nil
+}
+
+func (gen *UploadExpiryTaskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *UploadExpiryTaskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *UploadExpiryTaskGenerator) Reset() {
+	gen.lastRepo = ""
+	gen.done = false
+}
+
+type uploadExpiryTask struct {
+	imgStore storageTypes.ImageStore
+	repo     string
+	ttl      time.Duration
+}
+
+func NewUploadExpiryTask(imgStore storageTypes.ImageStore, repo string, ttl time.Duration,
+) *uploadExpiryTask {
+	return &uploadExpiryTask{imgStore, repo, ttl}
+}
+
+func (uet *uploadExpiryTask) DoWork(ctx context.Context) error {
+	_, err := uet.imgStore.ExpireBlobUploads(uet.repo, uet.ttl)
+
+	return err
+}
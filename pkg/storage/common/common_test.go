@@ -2,11 +2,13 @@ package storage_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	godigest "github.com/opencontainers/go-digest"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -17,6 +19,7 @@ import (
 	zerr "zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage"
 	"zotregistry.io/zot/pkg/storage/cache"
 	common "zotregistry.io/zot/pkg/storage/common"
@@ -39,7 +42,7 @@ func TestValidateManifest(t *testing.T) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		content := []byte("this is a blob")
 		digest := godigest.FromBytes(content)
@@ -158,7 +161,7 @@ func TestGetReferrersErrors(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, false, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, true, false, log, metrics, nil, cacheDriver)
 
 		artifactType := "application/vnd.example.icecream.v1"
 		validDigest := godigest.FromBytes([]byte("blob"))
@@ -434,6 +437,144 @@ func TestIsSignature(t *testing.T) {
 	})
 }
 
+func TestGetCosignSignedDigest(t *testing.T) {
+	Convey("Parse the signed digest from the cosign signature tags", t, func() {
+		for _, algorithm := range []godigest.Algorithm{godigest.SHA256, godigest.SHA512} {
+			signedDigest := algorithm.FromString("image")
+
+			digest, ok := common.GetCosignSignedDigest(algorithm.String() + "-" + signedDigest.Encoded() + ".sig")
+			So(ok, ShouldBeTrue)
+			So(digest, ShouldEqual, signedDigest)
+
+			// the other cosign tags aren't signatures
+			_, ok = common.GetCosignSignedDigest(algorithm.String() + "-" + signedDigest.Encoded() + ".sbom")
+			So(ok, ShouldBeFalse)
+		}
+
+		for _, tag := range []string{"latest", "sha256-", "sha256-1234.sig", "sha256-not-a-hex-digest-of-the-right-size.sig",
+			"md5-" + godigest.FromString("image").Encoded() + ".sig"} {
+			_, ok := common.GetCosignSignedDigest(tag)
+			So(ok, ShouldBeFalse)
+		}
+	})
+}
+
+func TestUploadExpiryTaskGenerator(t *testing.T) {
+	Convey("The stale blob uploads of each repo are expired", t, func() {
+		expired := map[string]time.Duration{}
+
+		imgStore := mocks.MockedImageStore{
+			GetNextRepositoryFn: func(repo string) (string, error) {
+				switch repo {
+				case "":
+					return "alpine", nil
+				case "alpine":
+					return "busybox", nil
+				default:
+					return "", nil
+				}
+			},
+			ExpireBlobUploadsFn: func(repo string, ttl time.Duration) (int, error) {
+				expired[repo] = ttl
+
+				return 1, nil
+			},
+		}
+
+		generator := &common.UploadExpiryTaskGenerator{ImgStore: imgStore, TTL: time.Hour}
+		So(generator.IsReady(), ShouldBeTrue)
+
+		for !generator.IsDone() {
+			task, err := generator.Next()
+			So(err, ShouldBeNil)
+
+			if task != nil {
+				So(task.DoWork(context.Background()), ShouldBeNil)
+			}
+		}
+
+		So(expired, ShouldResemble, map[string]time.Duration{"alpine": time.Hour, "busybox": time.Hour})
+
+		generator.Reset()
+		So(generator.IsDone(), ShouldBeFalse)
+
+		imgStore.GetNextRepositoryFn = func(repo string) (string, error) {
+			return "", zerr.ErrRepoNotFound
+		}
+
+		generator.ImgStore = imgStore
+
+		_, err := generator.Next()
+		So(err, ShouldEqual, zerr.ErrRepoNotFound)
+	})
+}
+
+func TestGCTaskGenerator(t *testing.T) {
+	Convey("The deduped blobs are repaired after the garbage collection of each repo", t, func() {
+		collected := []string{}
+
+		imgStore := mocks.MockedImageStore{
+			GetNextRepositoryFn: func(repo string) (string, error) {
+				switch repo {
+				case "":
+					return "alpine", nil
+				case "alpine":
+					return "busybox", nil
+				default:
+					return "", nil
+				}
+			},
+			RunGCRepoFn: func(repo string) error {
+				collected = append(collected, repo)
+
+				return nil
+			},
+			RunGCDedupedBlobsFn: func() error {
+				collected = append(collected, "dedupe")
+
+				return nil
+			},
+		}
+
+		runGenerator := func(generator scheduler.TaskGenerator) {
+			for !generator.IsDone() {
+				task, err := generator.Next()
+				So(err, ShouldBeNil)
+
+				if task != nil {
+					So(task.DoWork(context.Background()), ShouldBeNil)
+				}
+			}
+		}
+
+		generator := &common.GCTaskGenerator{ImgStore: imgStore}
+		runGenerator(generator)
+
+		So(collected, ShouldResemble, []string{"alpine", "busybox", "dedupe"})
+
+		generator.Reset()
+		So(generator.IsDone(), ShouldBeFalse)
+
+		collected = []string{}
+		runGenerator(generator)
+
+		So(collected, ShouldResemble, []string{"alpine", "busybox", "dedupe"})
+
+		Convey("The deduped blobs are repaired once", func() {
+			dedupeGenerator := &common.DedupeGCTaskGenerator{ImgStore: imgStore}
+			So(dedupeGenerator.IsReady(), ShouldBeTrue)
+
+			collected = []string{}
+			runGenerator(dedupeGenerator)
+
+			So(collected, ShouldResemble, []string{"dedupe"})
+
+			dedupeGenerator.Reset()
+			So(dedupeGenerator.IsDone(), ShouldBeFalse)
+		})
+	})
+}
+
 func TestGarbageCollectManifestErrors(t *testing.T) {
 	Convey("Make imagestore and upload manifest", t, func(c C) {
 		dir := t.TempDir()
@@ -448,7 +589,7 @@ func TestGarbageCollectManifestErrors(t *testing.T) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		Convey("trigger repo not found in GetReferencedBlobs()", func() {
 			err := common.AddRepoBlobsToReferences(imgStore, repoName, map[string]bool{}, log)
@@ -535,7 +676,7 @@ func TestGarbageCollectIndexErrors(t *testing.T) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		content := []byte("this is a blob")
 		bdgst := godigest.FromBytes(content)
@@ -19,9 +19,13 @@ const (
 	DBCacheLockCheckTimeout           = 10 * time.Second
 	BoltdbName                        = "cache"
 	DynamoDBDriverName                = "dynamodb"
+	PostgresDriverName                = "postgres"
 	DefaultGCDelay                    = 1 * time.Hour
 	DefaultUntaggedImgeRetentionDelay = 24 * time.Hour
 	DefaultGCInterval                 = 1 * time.Hour
+	DefaultUploadExpiryInterval       = 1 * time.Hour
 	S3StorageDriverName               = "s3"
+	AzureStorageDriverName            = "azure"
+	PluginStorageDriverName           = "plugin"
 	LocalStorageDriverName            = "local"
 )
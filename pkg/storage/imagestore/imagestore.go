@@ -1,8 +1,7 @@
 package imagestore
 
 import (
-	"bytes"
-	"crypto/sha256"
+	_ "crypto/sha512" // the blobs can be addressed by sha512 digests
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,6 +27,7 @@ import (
 	zreg "zotregistry.io/zot/pkg/regexp"
 	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage/cache"
+	"zotregistry.io/zot/pkg/storage/chunking"
 	common "zotregistry.io/zot/pkg/storage/common"
 	storageConstants "zotregistry.io/zot/pkg/storage/constants"
 	storageTypes "zotregistry.io/zot/pkg/storage/types"
@@ -43,33 +43,65 @@ const (
 type ImageStore struct {
 	rootDir        string
 	storeDriver    storageTypes.Driver
-	lock           *sync.RWMutex
+	lock           *ImageStoreLock
 	log            zlog.Logger
 	metrics        monitoring.MetricServer
 	cache          cache.Cache
 	dedupe         bool
 	linter         common.Lint
 	commit         bool
+	readOnly       bool
 	gc             bool
 	gcReferrers    bool
 	gcDelay        time.Duration
 	retentionDelay time.Duration
+	// the blobs deduped across repositories aren't covered by the repository locks
+	dedupeLock *sync.RWMutex
+	quota      storageTypes.QuotaEnforcer
+	tagPolicy  storageTypes.TagPolicy
+	refCounter storageTypes.ReferenceCounter
+	// splits the blobs in chunks, it's also the store driver
+	chunkDriver *chunking.Driver
 }
 
 func (is *ImageStore) RootDir() string {
 	return is.rootDir
 }
 
+// IsReadOnly tells if the pushes and deletes of the clients are rejected, the store is still written by zot, e.g.
+// by sync, but nothing is removed by the garbage collection or the retention policies.
+func (is *ImageStore) IsReadOnly() bool {
+	return is.readOnly
+}
+
 func (is *ImageStore) DirExists(d string) bool {
 	return is.storeDriver.DirExists(d)
 }
 
+// Ping checks if the cache database of the image store can be reached.
+func (is *ImageStore) Ping() error {
+	if is.cache == nil {
+		return nil
+	}
+
+	return is.cache.Ping()
+}
+
+// Close releases the cache database of the image store.
+func (is *ImageStore) Close() error {
+	if is.cache == nil {
+		return nil
+	}
+
+	return is.cache.Close()
+}
+
 // NewImageStore returns a new image store backed by cloud storages.
 // see https://github.com/docker/docker.github.io/tree/master/registry/storage-drivers
 // Use the last argument to properly set a cache database, or it will default to boltDB local storage.
 func NewImageStore(rootDir string, cacheDir string, gc bool, gcReferrers bool, gcDelay time.Duration,
-	untaggedImageRetentionDelay time.Duration, dedupe, commit bool, log zlog.Logger, metrics monitoring.MetricServer,
-	linter common.Lint, storeDriver storageTypes.Driver, cacheDriver cache.Cache,
+	untaggedImageRetentionDelay time.Duration, dedupe, commit, readOnly bool, log zlog.Logger,
+	metrics monitoring.MetricServer, linter common.Lint, storeDriver storageTypes.Driver, cacheDriver cache.Cache,
 ) storageTypes.ImageStore {
 	if err := storeDriver.EnsureDir(rootDir); err != nil {
 		log.Error().Err(err).Str("rootDir", rootDir).Msg("unable to create root dir")
@@ -80,12 +112,14 @@ func NewImageStore(rootDir string, cacheDir string, gc bool, gcReferrers bool, g
 	imgStore := &ImageStore{
 		rootDir:        rootDir,
 		storeDriver:    storeDriver,
-		lock:           &sync.RWMutex{},
+		lock:           NewImageStoreLock(),
+		dedupeLock:     &sync.RWMutex{},
 		log:            log,
 		metrics:        metrics,
 		dedupe:         dedupe,
 		linter:         linter,
 		commit:         commit,
+		readOnly:       readOnly,
 		gc:             gc,
 		gcReferrers:    gcReferrers,
 		gcDelay:        gcDelay,
@@ -96,38 +130,212 @@ func NewImageStore(rootDir string, cacheDir string, gc bool, gcReferrers bool, g
 	return imgStore
 }
 
-// RLock read-lock.
+// RLock read-lock of the whole store.
 func (is *ImageStore) RLock(lockStart *time.Time) {
 	*lockStart = time.Now()
 
 	is.lock.RLock()
 }
 
-// RUnlock read-unlock.
+// RUnlock read-unlock of the whole store.
 func (is *ImageStore) RUnlock(lockStart *time.Time) {
 	is.lock.RUnlock()
 
-	lockEnd := time.Now()
-	// includes time spent in acquiring and holding a lock
-	latency := lockEnd.Sub(*lockStart)
-	monitoring.ObserveStorageLockLatency(is.metrics, latency, is.RootDir(), storageConstants.RLOCK) // histogram
+	is.observeLockLatency(lockStart, storageConstants.RLOCK)
 }
 
-// Lock write-lock.
+// Lock write-lock of the whole store.
 func (is *ImageStore) Lock(lockStart *time.Time) {
 	*lockStart = time.Now()
 
 	is.lock.Lock()
 }
 
-// Unlock write-unlock.
+// Unlock write-unlock of the whole store.
 func (is *ImageStore) Unlock(lockStart *time.Time) {
 	is.lock.Unlock()
 
+	is.observeLockLatency(lockStart, storageConstants.RWLOCK)
+}
+
+// RLockRepo read-lock of the repository.
+func (is *ImageStore) RLockRepo(repo string, lockStart *time.Time) {
+	*lockStart = time.Now()
+
+	is.lock.RLockRepo(repo)
+}
+
+// RUnlockRepo read-unlock of the repository.
+func (is *ImageStore) RUnlockRepo(repo string, lockStart *time.Time) {
+	is.lock.RUnlockRepo(repo)
+
+	is.observeLockLatency(lockStart, storageConstants.RLOCK)
+}
+
+// LockRepo write-lock of the repository, the repository isn't locked if it returns an error.
+func (is *ImageStore) LockRepo(repo string, lockStart *time.Time) error {
+	*lockStart = time.Now()
+
+	return is.lock.LockRepo(repo)
+}
+
+// lockDedupe write-lock of the deduped blobs, shared by the repositories of the store and of the other zot
+// instances sharing the storage.
+func (is *ImageStore) lockDedupe() error {
+	is.dedupeLock.Lock()
+
+	if err := is.lock.LockDedupe(); err != nil {
+		is.dedupeLock.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// unlockDedupe write-unlock of the deduped blobs.
+func (is *ImageStore) unlockDedupe() {
+	is.lock.UnlockDedupe()
+	is.dedupeLock.Unlock()
+}
+
+// SetRepoLocker shares the write locks of the repositories with the other zot instances sharing the storage.
+func (is *ImageStore) SetRepoLocker(locker storageTypes.RepoLocker) {
+	is.lock.SetRepoLocker(locker)
+}
+
+// SetQuotaEnforcer denies the manifests and blobs pushed over the quotas of the repositories, and keeps their usage
+// up to date. It's set before the image store is used.
+func (is *ImageStore) SetQuotaEnforcer(enforcer storageTypes.QuotaEnforcer) {
+	is.quota = enforcer
+}
+
+func (is *ImageStore) SetTagPolicy(policy storageTypes.TagPolicy) {
+	is.tagPolicy = policy
+}
+
+// SetReferenceCounter counts the references to the blobs as the manifests and blobs are pushed and deleted, the
+// garbage collection then only checks the blobs which aren't referenced. It's set before the image store is used.
+func (is *ImageStore) SetReferenceCounter(counter storageTypes.ReferenceCounter) {
+	is.refCounter = counter
+}
+
+// SetChunkDedupe splits the blobs larger than minBlobSize in chunks with content-defined chunking, the chunks are
+// stored once in the root directory and the blobs are reassembled when they're read. It's set before the image
+// store is used.
+func (is *ImageStore) SetChunkDedupe(minBlobSize int64, averageChunkSize int) {
+	is.chunkDriver = chunking.NewDriver(is.storeDriver, is.rootDir, chunking.Parameters{
+		MinBlobSize:      minBlobSize,
+		AverageChunkSize: averageChunkSize,
+	}, is.log)
+	is.storeDriver = is.chunkDriver
+}
+
+// addManifestReferences counts the references of the manifest, the repo is no longer tracked if they can't be
+// counted and its next garbage collection reads all its manifests again.
+func (is *ImageStore) addManifestReferences(repo string, digest godigest.Digest, mediaType string, body []byte) {
+	if is.refCounter == nil {
+		return
+	}
+
+	refs, err := common.GetManifestReferences(mediaType, body)
+	if err == nil {
+		err = is.refCounter.AddManifest(repo, digest, refs)
+	}
+
+	if err != nil {
+		is.untrackReferences(repo, err)
+	}
+}
+
+func (is *ImageStore) addBlobReference(repo string, digest godigest.Digest) {
+	if is.refCounter == nil {
+		return
+	}
+
+	if err := is.refCounter.AddBlob(repo, digest); err != nil {
+		is.untrackReferences(repo, err)
+	}
+}
+
+func (is *ImageStore) removeBlobReferences(repo string, digest godigest.Digest) {
+	if is.refCounter == nil {
+		return
+	}
+
+	if err := is.refCounter.RemoveBlob(repo, digest); err != nil {
+		is.untrackReferences(repo, err)
+	}
+}
+
+// forgetReferences removes the references of a removed repo.
+func (is *ImageStore) forgetReferences(repo string) {
+	if is.refCounter == nil {
+		return
+	}
+
+	if err := is.refCounter.RemoveRepo(repo); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Msg("unable to remove the references of the repo")
+	}
+}
+
+func (is *ImageStore) untrackReferences(repo string, err error) {
+	is.log.Error().Err(err).Str("repository", repo).
+		Msg("unable to count the references of the repo, they're counted again by its next gc")
+
+	is.forgetReferences(repo)
+}
+
+func (is *ImageStore) isTagMutable(repo, tag string) bool {
+	return is.tagPolicy == nil || is.tagPolicy.IsTagMutable(repo, tag)
+}
+
+func (is *ImageStore) checkQuota(repo string, size int64) error {
+	if is.quota == nil || size <= 0 {
+		return nil
+	}
+
+	return is.quota.CheckQuota(repo, size)
+}
+
+func (is *ImageStore) updateUsage(repo string, delta int64) {
+	if is.quota != nil && delta != 0 {
+		is.quota.UpdateUsage(repo, delta)
+	}
+}
+
+// blobUsage returns the size of a blob counted in the usage of the repo, 0 if the repo doesn't have the blob or
+// the quotas aren't enforced.
+func (is *ImageStore) blobUsage(repo string, digest godigest.Digest) int64 {
+	if is.quota == nil {
+		return 0
+	}
+
+	if _, err := is.storeDriver.Stat(is.BlobPath(repo, digest)); err != nil {
+		return 0
+	}
+
+	// the deduped blobs have the size of their original
+	_, size, _, err := is.StatBlob(repo, digest)
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
+
+// UnlockRepo write-unlock of the repository.
+func (is *ImageStore) UnlockRepo(repo string, lockStart *time.Time) {
+	is.lock.UnlockRepo(repo)
+
+	is.observeLockLatency(lockStart, storageConstants.RWLOCK)
+}
+
+func (is *ImageStore) observeLockLatency(lockStart *time.Time, lockType string) {
 	lockEnd := time.Now()
 	// includes time spent in acquiring and holding a lock
 	latency := lockEnd.Sub(*lockStart)
-	monitoring.ObserveStorageLockLatency(is.metrics, latency, is.RootDir(), storageConstants.RWLOCK) // histogram
+	monitoring.ObserveStorageLockLatency(is.metrics, latency, is.RootDir(), lockType) // histogram
 }
 
 func (is *ImageStore) initRepo(name string) error {
@@ -206,8 +414,10 @@ func (is *ImageStore) initRepo(name string) error {
 func (is *ImageStore) InitRepo(name string) error {
 	var lockLatency time.Time
 
-	is.Lock(&lockLatency)
-	defer is.Unlock(&lockLatency)
+	if err := is.LockRepo(name, &lockLatency); err != nil {
+		return err
+	}
+	defer is.UnlockRepo(name, &lockLatency)
 
 	return is.initRepo(name)
 }
@@ -402,8 +612,8 @@ func (is *ImageStore) GetImageTags(repo string) ([]string, error) {
 		return nil, zerr.ErrRepoNotFound
 	}
 
-	is.RLock(&lockLatency)
-	defer is.RUnlock(&lockLatency)
+	is.RLockRepo(repo, &lockLatency)
+	defer is.RUnlockRepo(repo, &lockLatency)
 
 	index, err := common.GetIndex(is, repo, is.log)
 	if err != nil {
@@ -424,9 +634,9 @@ func (is *ImageStore) GetImageManifest(repo, reference string) ([]byte, godigest
 
 	var err error
 
-	is.RLock(&lockLatency)
+	is.RLockRepo(repo, &lockLatency)
 	defer func() {
-		is.RUnlock(&lockLatency)
+		is.RUnlockRepo(repo, &lockLatency)
 
 		if err == nil {
 			monitoring.IncDownloadCounter(is.metrics, repo)
@@ -476,9 +686,11 @@ func (is *ImageStore) PutImageManifest(repo, reference, mediaType string, //noli
 
 	var err error
 
-	is.Lock(&lockLatency)
+	if err = is.LockRepo(repo, &lockLatency); err != nil {
+		return "", "", err
+	}
 	defer func() {
-		is.Unlock(&lockLatency)
+		is.UnlockRepo(repo, &lockLatency)
 
 		if err == nil {
 			monitoring.SetStorageUsage(is.metrics, is.rootDir, repo)
@@ -557,6 +769,24 @@ func (is *ImageStore) PutImageManifest(repo, reference, mediaType string, //noli
 		return desc.Digest, subjectDigest, nil
 	}
 
+	// the tag was pushed before with another manifest
+	if oldDgst != "" && !is.isTagMutable(repo, reference) {
+		err = zerr.NewError(zerr.ErrImmutableTag).AddDetail("reference", reference).
+			AddDetail("digest", oldDgst.String())
+
+		is.log.Error().Err(err).Str("repository", repo).Str("reference", reference).
+			Str("digest", oldDgst.String()).Msg("unable to move the immutable tag to another manifest")
+
+		return "", "", err
+	}
+
+	// a manifest already in the repo, e.g. tagged again, doesn't change its usage
+	manifestUsage := desc.Size - is.blobUsage(repo, mDigest)
+
+	if err = is.checkQuota(repo, manifestUsage); err != nil {
+		return "", "", err
+	}
+
 	// write manifest to "blobs"
 	dir := path.Join(is.rootDir, repo, "blobs", mDigest.Algorithm().String())
 	manifestPath := path.Join(dir, mDigest.Encoded())
@@ -595,12 +825,25 @@ func (is *ImageStore) PutImageManifest(repo, reference, mediaType string, //noli
 		return "", "", err
 	}
 
+	// the other zot instances sharing the storage may write in the repo if its lock was lost
+	if err = is.lock.CheckRepo(repo); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Str("reference", reference).Msg("unable to write the index")
+
+		return "", "", err
+	}
+
+	// the references are counted before the manifest is in the index, so its blobs are never unreferenced while
+	// it is, if the index isn't written the manifest is an unreferenced blob and its references are removed with it
+	is.addManifestReferences(repo, mDigest, mediaType, body)
+
 	if _, err = is.storeDriver.WriteFile(indexPath, buf); err != nil {
 		is.log.Error().Err(err).Str("file", manifestPath).Msg("unable to write")
 
 		return "", "", err
 	}
 
+	is.updateUsage(repo, manifestUsage)
+
 	return desc.Digest, subjectDigest, nil
 }
 
@@ -615,9 +858,11 @@ func (is *ImageStore) DeleteImageManifest(repo, reference string, detectCollisio
 
 	var err error
 
-	is.Lock(&lockLatency)
+	if err = is.LockRepo(repo, &lockLatency); err != nil {
+		return err
+	}
 	defer func() {
-		is.Unlock(&lockLatency)
+		is.UnlockRepo(repo, &lockLatency)
 
 		if err == nil {
 			monitoring.SetStorageUsage(is.metrics, is.rootDir, repo)
@@ -633,33 +878,27 @@ func (is *ImageStore) DeleteImageManifest(repo, reference string, detectCollisio
 }
 
 func (is *ImageStore) deleteImageManifest(repo, reference string, detectCollisions bool) error {
+	_, _, err := is.removeImageManifest(repo, reference, detectCollisions)
+
+	return err
+}
+
+// removeImageManifest removes the manifest from the index of the repo, it returns its descriptor and if its blob
+// was removed too, it's kept if the manifest is still in the index, e.g. with another tag.
+func (is *ImageStore) removeImageManifest(repo, reference string, detectCollisions bool,
+) (ispec.Descriptor, bool, error) {
 	index, err := common.GetIndex(is, repo, is.log)
 	if err != nil {
-		return err
+		return ispec.Descriptor{}, false, err
 	}
 
-	manifestDesc, err := common.RemoveManifestDescByReference(&index, reference, detectCollisions)
+	manifestDesc, toDelete, err := is.pruneImageManifest(&index, repo, reference, detectCollisions)
 	if err != nil {
-		return err
-	}
-
-	/* check if manifest is referenced in image indexes, do not allow index images manipulations
-	(ie. remove manifest being part of an image index)	*/
-	if manifestDesc.MediaType == ispec.MediaTypeImageManifest {
-		for _, mDesc := range index.Manifests {
-			if mDesc.MediaType == ispec.MediaTypeImageIndex {
-				if ok, _ := common.IsBlobReferencedInImageIndex(is, repo, manifestDesc.Digest, ispec.Index{
-					Manifests: []ispec.Descriptor{mDesc},
-				}, is.log); ok {
-					return zerr.ErrManifestReferenced
-				}
-			}
-		}
+		return ispec.Descriptor{}, false, err
 	}
 
-	err = common.UpdateIndexWithPrunedImageManifests(is, &index, repo, manifestDesc, manifestDesc.Digest, is.log)
-	if err != nil {
-		return err
+	if err := is.lock.CheckRepo(repo); err != nil {
+		return ispec.Descriptor{}, false, err
 	}
 
 	// now update "index.json"
@@ -668,37 +907,66 @@ func (is *ImageStore) deleteImageManifest(repo, reference string, detectCollisio
 
 	buf, err := json.Marshal(index)
 	if err != nil {
-		return err
+		return ispec.Descriptor{}, false, err
 	}
 
 	if _, err := is.storeDriver.WriteFile(file, buf); err != nil {
 		is.log.Debug().Str("deleting reference", reference).Msg("")
 
-		return err
+		return ispec.Descriptor{}, false, err
 	}
 
-	// Delete blob only when blob digest not present in manifest entry.
-	// e.g. 1.0.1 & 1.0.2 have same blob digest so if we delete 1.0.1, blob should not be removed.
-	toDelete := true
+	if toDelete {
+		p := path.Join(dir, "blobs", manifestDesc.Digest.Algorithm().String(), manifestDesc.Digest.Encoded())
 
-	for _, manifest := range index.Manifests {
-		if manifestDesc.Digest.String() == manifest.Digest.String() {
-			toDelete = false
+		err = is.storeDriver.Delete(p)
+		if err != nil {
+			return ispec.Descriptor{}, false, err
+		}
 
-			break
+		is.updateUsage(repo, -manifestDesc.Size)
+		is.removeBlobReferences(repo, manifestDesc.Digest)
+	}
+
+	return manifestDesc, toDelete, nil
+}
+
+// pruneImageManifest removes the manifest from the index, it returns its descriptor and if its blob can be removed.
+func (is *ImageStore) pruneImageManifest(index *ispec.Index, repo, reference string, detectCollisions bool,
+) (ispec.Descriptor, bool, error) {
+	manifestDesc, err := common.RemoveManifestDescByReference(index, reference, detectCollisions)
+	if err != nil {
+		return ispec.Descriptor{}, false, err
+	}
+
+	/* check if manifest is referenced in image indexes, do not allow index images manipulations
+	(ie. remove manifest being part of an image index)	*/
+	if manifestDesc.MediaType == ispec.MediaTypeImageManifest {
+		for _, mDesc := range index.Manifests {
+			if mDesc.MediaType == ispec.MediaTypeImageIndex {
+				if ok, _ := common.IsBlobReferencedInImageIndex(is, repo, manifestDesc.Digest, ispec.Index{
+					Manifests: []ispec.Descriptor{mDesc},
+				}, is.log); ok {
+					return ispec.Descriptor{}, false, zerr.ErrManifestReferenced
+				}
+			}
 		}
 	}
 
-	if toDelete {
-		p := path.Join(dir, "blobs", manifestDesc.Digest.Algorithm().String(), manifestDesc.Digest.Encoded())
+	err = common.UpdateIndexWithPrunedImageManifests(is, index, repo, manifestDesc, manifestDesc.Digest, is.log)
+	if err != nil {
+		return ispec.Descriptor{}, false, err
+	}
 
-		err = is.storeDriver.Delete(p)
-		if err != nil {
-			return err
+	// Delete blob only when blob digest not present in manifest entry.
+	// e.g. 1.0.1 & 1.0.2 have same blob digest so if we delete 1.0.1, blob should not be removed.
+	for _, manifest := range index.Manifests {
+		if manifestDesc.Digest.String() == manifest.Digest.String() {
+			return manifestDesc, false, nil
 		}
 	}
 
-	return nil
+	return manifestDesc, true, nil
 }
 
 // BlobUploadPath returns the upload path for a blob in this store.
@@ -783,6 +1051,13 @@ func (is *ImageStore) PutBlobChunkStreamed(repo, uuid string, body io.Reader) (i
 		return -1, err
 	}
 
+	// the size of the blob is only known once it's finished, the uploads already over the quota are denied
+	if err := is.checkQuota(repo, file.Size()); err != nil {
+		file.Close()
+
+		return -1, err
+	}
+
 	var n int64 //nolint: varnamelen
 
 	defer func() {
@@ -825,6 +1100,10 @@ func (is *ImageStore) PutBlobChunk(repo, uuid string, from, to int64,
 		return -1, zerr.ErrBadUploadRange
 	}
 
+	if err := is.checkQuota(repo, to+1); err != nil {
+		return -1, err
+	}
+
 	n, err := io.Copy(file, body)
 
 	return n, err
@@ -885,7 +1164,7 @@ func (is *ImageStore) FinishBlobUpload(repo, uuid string, body io.Reader, dstDig
 
 	defer fileReader.Close()
 
-	srcDigest, err := godigest.FromReader(fileReader)
+	srcDigest, err := dstDigest.Algorithm().FromReader(fileReader)
 	if err != nil {
 		is.log.Error().Err(err).Str("blob", src).Msg("failed to open blob")
 
@@ -903,7 +1182,7 @@ func (is *ImageStore) FinishBlobUpload(repo, uuid string, body io.Reader, dstDig
 
 	err = is.storeDriver.EnsureDir(dir)
 	if err != nil {
-		is.log.Error().Err(err).Msg("error creating blobs/sha256 dir")
+		is.log.Error().Err(err).Str("dir", dir).Msg("error creating blobs dir")
 
 		return err
 	}
@@ -912,11 +1191,24 @@ func (is *ImageStore) FinishBlobUpload(repo, uuid string, body io.Reader, dstDig
 
 	var lockLatency time.Time
 
-	is.Lock(&lockLatency)
-	defer is.Unlock(&lockLatency)
+	if err := is.LockRepo(repo, &lockLatency); err != nil {
+		return err
+	}
+	defer is.UnlockRepo(repo, &lockLatency)
+
+	usage, err := is.checkBlobQuota(repo, src, dstDigest)
+	if err != nil {
+		return err
+	}
+
+	if is.dedupe && is.cache != nil {
+		if err := is.lockDedupe(); err != nil {
+			return err
+		}
 
-	if is.dedupe && fmt.Sprintf("%v", is.cache) != fmt.Sprintf("%v", nil) {
 		err = is.DedupeBlob(src, dstDigest, dst)
+		is.unlockDedupe()
+
 		if err := inject.Error(err); err != nil {
 			is.log.Error().Err(err).Str("src", src).Str("dstDigest", dstDigest.String()).
 				Str("dst", dst).Msg("unable to dedupe blob")
@@ -932,10 +1224,14 @@ func (is *ImageStore) FinishBlobUpload(repo, uuid string, body io.Reader, dstDig
 		}
 	}
 
+	is.updateUsage(repo, usage)
+	is.addBlobReference(repo, dstDigest)
+
 	return nil
 }
 
-// FullBlobUpload handles a full blob upload, and no partial session is created.
+// FullBlobUpload handles a full blob upload, and no partial session is created. The blob is streamed
+// to the storage driver, so its size isn't bounded by the memory.
 func (is *ImageStore) FullBlobUpload(repo string, body io.Reader, dstDigest godigest.Digest) (string, int64, error) {
 	if err := dstDigest.Validate(); err != nil {
 		return "", -1, err
@@ -952,31 +1248,40 @@ func (is *ImageStore) FullBlobUpload(repo string, body io.Reader, dstDigest godi
 
 	uuid := u.String()
 	src := is.BlobUploadPath(repo, uuid)
-	digester := sha256.New()
-	buf := new(bytes.Buffer)
+	digester := dstDigest.Algorithm().Digester()
 
-	_, err = buf.ReadFrom(body)
+	blobFile, err := is.storeDriver.Writer(src, false)
 	if err != nil {
-		is.log.Error().Err(err).Msg("failed to read blob")
+		is.log.Error().Err(err).Msg("failed to create blob")
 
 		return "", -1, err
 	}
 
-	nbytes, err := is.storeDriver.WriteFile(src, buf.Bytes())
+	// the blob is streamed to the storage and digested on the way, it is never held in memory
+	nbytes, err := io.Copy(blobFile, io.TeeReader(body, digester.Hash()))
 	if err != nil {
 		is.log.Error().Err(err).Msg("failed to write blob")
 
+		_ = blobFile.Cancel()
+
 		return "", -1, err
 	}
 
-	_, err = digester.Write(buf.Bytes())
-	if err != nil {
-		is.log.Error().Err(err).Msg("digester failed to write")
+	if err := blobFile.Commit(); err != nil {
+		is.log.Error().Err(err).Msg("failed to commit blob")
+
+		_ = blobFile.Cancel()
+
+		return "", -1, err
+	}
+
+	if err := blobFile.Close(); err != nil {
+		is.log.Error().Err(err).Msg("failed to close blob")
 
 		return "", -1, err
 	}
 
-	srcDigest := godigest.NewDigestFromEncoded(godigest.SHA256, fmt.Sprintf("%x", digester.Sum(nil)))
+	srcDigest := digester.Digest()
 	if srcDigest != dstDigest {
 		is.log.Error().Str("srcDigest", srcDigest.String()).
 			Str("dstDigest", dstDigest.String()).Msg("actual digest not equal to expected digest")
@@ -989,13 +1294,27 @@ func (is *ImageStore) FullBlobUpload(repo string, body io.Reader, dstDigest godi
 
 	var lockLatency time.Time
 
-	is.Lock(&lockLatency)
-	defer is.Unlock(&lockLatency)
+	if err := is.LockRepo(repo, &lockLatency); err != nil {
+		return "", -1, err
+	}
+	defer is.UnlockRepo(repo, &lockLatency)
+
+	usage, err := is.checkBlobQuota(repo, src, dstDigest)
+	if err != nil {
+		return "", -1, err
+	}
 
 	dst := is.BlobPath(repo, dstDigest)
 
-	if is.dedupe && fmt.Sprintf("%v", is.cache) != fmt.Sprintf("%v", nil) {
-		if err := is.DedupeBlob(src, dstDigest, dst); err != nil {
+	if is.dedupe && is.cache != nil {
+		if err := is.lockDedupe(); err != nil {
+			return "", -1, err
+		}
+
+		err := is.DedupeBlob(src, dstDigest, dst)
+		is.unlockDedupe()
+
+		if err != nil {
 			is.log.Error().Err(err).Str("src", src).Str("dstDigest", dstDigest.String()).
 				Str("dst", dst).Msg("unable to dedupe blob")
 
@@ -1010,7 +1329,70 @@ func (is *ImageStore) FullBlobUpload(repo string, body io.Reader, dstDigest godi
 		}
 	}
 
-	return uuid, int64(nbytes), nil
+	is.updateUsage(repo, usage)
+	is.addBlobReference(repo, dstDigest)
+
+	return uuid, nbytes, nil
+}
+
+// checkBlobQuota checks that the blob uploaded to src fits in the quota of the repo, otherwise the upload is
+// removed. It returns the usage added to the repo by the blob, nothing if the repo already has it.
+func (is *ImageStore) checkBlobQuota(repo, src string, digest godigest.Digest) (int64, error) {
+	if is.quota == nil {
+		return 0, nil
+	}
+
+	binfo, err := is.storeDriver.Stat(src)
+	if err != nil {
+		is.log.Error().Err(err).Str("blob", src).Msg("failed to stat blob")
+
+		return 0, err
+	}
+
+	usage := binfo.Size() - is.blobUsage(repo, digest)
+
+	if err := is.checkQuota(repo, usage); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
+			Msg("blob denied by the quota")
+
+		_ = is.storeDriver.Delete(src)
+
+		return 0, err
+	}
+
+	return usage, nil
+}
+
+// checkLinkedBlobQuota checks that the blob linked from another repo fits in the quota of the repo before it's
+// linked. It returns the usage added to the repo by the blob, nothing if the repo already has it.
+func (is *ImageStore) checkLinkedBlobQuota(repo string, digest godigest.Digest, dstRecord string) (int64, error) {
+	if is.quota == nil {
+		return 0, nil
+	}
+
+	binfo, err := is.storeDriver.Stat(dstRecord)
+	if err != nil {
+		is.log.Error().Err(err).Str("blob", dstRecord).Msg("failed to stat blob")
+
+		return 0, zerr.ErrBlobNotFound
+	}
+
+	// the caller holds the dedupe lock, so the size of the copy of the repo (an empty file if it's deduped) isn't
+	// looked up with StatBlob, it has the content of the original anyway
+	usage := binfo.Size()
+
+	if _, err := is.storeDriver.Stat(is.BlobPath(repo, digest)); err == nil {
+		usage = 0
+	}
+
+	if err := is.checkQuota(repo, usage); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
+			Msg("linked blob denied by the quota")
+
+		return 0, err
+	}
+
+	return usage, nil
 }
 
 func (is *ImageStore) DedupeBlob(src string, dstDigest godigest.Digest, dst string) error {
@@ -1115,6 +1497,68 @@ func (is *ImageStore) DeleteBlobUpload(repo, uuid string) error {
 	return nil
 }
 
+// ExpireBlobUploads deletes the blob uploads of a repo which weren't written for longer than the ttl, e.g. the
+// uploads abandoned by their clients. It returns the number of expired uploads.
+func (is *ImageStore) ExpireBlobUploads(repo string, ttl time.Duration) (int, error) {
+	uploadDir := path.Join(is.rootDir, repo, storageConstants.BlobUploadDir)
+
+	// the files of each upload, the remote storages keep the sessions of the uploads next to them
+	uploadFiles := map[string][]string{}
+	lastWrites := map[string]time.Time{}
+
+	err := is.storeDriver.Walk(uploadDir, func(fileInfo driver.FileInfo) error {
+		if fileInfo.IsDir() {
+			return nil
+		}
+
+		uuid := path.Base(fileInfo.Path())
+
+		uploadFiles[uuid] = append(uploadFiles[uuid], fileInfo.Path())
+
+		if fileInfo.ModTime().After(lastWrites[uuid]) {
+			lastWrites[uuid] = fileInfo.ModTime()
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.As(err, &driver.PathNotFoundError{}) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	var expired int
+
+	for uuid, lastWrite := range lastWrites {
+		if time.Since(lastWrite) < ttl {
+			continue
+		}
+
+		// the multipart uploads of the remote storages are cancelled
+		if err := is.DeleteBlobUpload(repo, uuid); err != nil && !errors.Is(err, zerr.ErrUploadNotFound) {
+			return expired, err
+		}
+
+		// the files left by the upload, e.g. its session
+		for _, file := range uploadFiles[uuid] {
+			if err := is.storeDriver.Delete(file); err != nil && !errors.As(err, &driver.PathNotFoundError{}) {
+				is.log.Error().Err(err).Str("file", file).Msg("failed to delete blob upload file")
+
+				return expired, err
+			}
+		}
+
+		is.log.Info().Str("repository", repo).Str("uuid", uuid).Time("lastWrite", lastWrite).
+			Msg("expired stale blob upload")
+
+		expired++
+	}
+
+	return expired, nil
+}
+
 // BlobPath returns the repository path of a blob.
 func (is *ImageStore) BlobPath(repo string, digest godigest.Digest) string {
 	return path.Join(is.rootDir, repo, "blobs", digest.Algorithm().String(), digest.Encoded())
@@ -1134,12 +1578,14 @@ func (is *ImageStore) CheckBlob(repo string, digest godigest.Digest) (bool, int6
 
 	blobPath := is.BlobPath(repo, digest)
 
-	if is.dedupe && fmt.Sprintf("%v", is.cache) != fmt.Sprintf("%v", nil) {
-		is.Lock(&lockLatency)
-		defer is.Unlock(&lockLatency)
+	if is.dedupe && is.cache != nil {
+		if err := is.LockRepo(repo, &lockLatency); err != nil {
+			return false, -1, err
+		}
+		defer is.UnlockRepo(repo, &lockLatency)
 	} else {
-		is.RLock(&lockLatency)
-		defer is.RUnlock(&lockLatency)
+		is.RLockRepo(repo, &lockLatency)
+		defer is.RUnlockRepo(repo, &lockLatency)
 	}
 
 	binfo, err := is.storeDriver.Stat(blobPath)
@@ -1150,6 +1596,11 @@ func (is *ImageStore) CheckBlob(repo string, digest godigest.Digest) (bool, int6
 	}
 	// otherwise is a 'deduped' blob (empty file)
 
+	if err := is.lockDedupe(); err != nil {
+		return false, -1, err
+	}
+	defer is.unlockDedupe()
+
 	// Check blobs in cache
 	dstRecord, err := is.checkCacheBlob(digest)
 	if err != nil {
@@ -1158,11 +1609,20 @@ func (is *ImageStore) CheckBlob(repo string, digest godigest.Digest) (bool, int6
 		return false, -1, zerr.ErrBlobNotFound
 	}
 
+	// the blobs linked from the other repos are counted in the usage of the repo
+	usage, err := is.checkLinkedBlobQuota(repo, digest, dstRecord)
+	if err != nil {
+		return false, -1, err
+	}
+
 	blobSize, err := is.copyBlob(repo, blobPath, dstRecord)
 	if err != nil {
 		return false, -1, zerr.ErrBlobNotFound
 	}
 
+	is.updateUsage(repo, usage)
+	is.addBlobReference(repo, digest)
+
 	// put deduped blob in cache
 	if err := is.cache.PutBlob(digest, blobPath); err != nil {
 		is.log.Error().Err(err).Str("blobPath", blobPath).Msg("dedupe: unable to insert blob record")
@@ -1195,6 +1655,8 @@ func (is *ImageStore) StatBlob(repo string, digest godigest.Digest) (bool, int64
 	}
 
 	// then it's a 'deduped' blob
+	is.dedupeLock.RLock()
+	defer is.dedupeLock.RUnlock()
 
 	// Check blobs in cache
 	dstRecord, err := is.checkCacheBlob(digest)
@@ -1219,7 +1681,7 @@ func (is *ImageStore) checkCacheBlob(digest godigest.Digest) (string, error) {
 		return "", err
 	}
 
-	if fmt.Sprintf("%v", is.cache) == fmt.Sprintf("%v", nil) {
+	if is.cache == nil {
 		return "", zerr.ErrBlobNotFound
 	}
 
@@ -1287,8 +1749,8 @@ func (is *ImageStore) GetBlobPartial(repo string, digest godigest.Digest, mediaT
 
 	blobPath := is.BlobPath(repo, digest)
 
-	is.RLock(&lockLatency)
-	defer is.RUnlock(&lockLatency)
+	is.RLockRepo(repo, &lockLatency)
+	defer is.RUnlockRepo(repo, &lockLatency)
 
 	binfo, err := is.storeDriver.Stat(blobPath)
 	if err != nil {
@@ -1299,6 +1761,9 @@ func (is *ImageStore) GetBlobPartial(repo string, digest godigest.Digest, mediaT
 
 	// is a deduped blob
 	if binfo.Size() == 0 {
+		is.dedupeLock.RLock()
+		defer is.dedupeLock.RUnlock()
+
 		// Check blobs in cache
 		blobPath, err = is.checkCacheBlob(digest)
 		if err != nil {
@@ -1350,8 +1815,8 @@ func (is *ImageStore) GetBlob(repo string, digest godigest.Digest, mediaType str
 
 	blobPath := is.BlobPath(repo, digest)
 
-	is.RLock(&lockLatency)
-	defer is.RUnlock(&lockLatency)
+	is.RLockRepo(repo, &lockLatency)
+	defer is.RUnlockRepo(repo, &lockLatency)
 
 	binfo, err := is.storeDriver.Stat(blobPath)
 	if err != nil {
@@ -1369,6 +1834,9 @@ func (is *ImageStore) GetBlob(repo string, digest godigest.Digest, mediaType str
 
 	// is a 'deduped' blob?
 	if binfo.Size() == 0 {
+		is.dedupeLock.RLock()
+		defer is.dedupeLock.RUnlock()
+
 		// Check blobs in cache
 		dstRecord, err := is.checkCacheBlob(digest)
 		if err != nil {
@@ -1422,6 +1890,9 @@ func (is *ImageStore) GetBlobContent(repo string, digest godigest.Digest) ([]byt
 
 	// is a 'deduped' blob?
 	if binfo.Size() == 0 {
+		is.dedupeLock.RLock()
+		defer is.dedupeLock.RUnlock()
+
 		// Check blobs in cache
 		dstRecord, err := is.checkCacheBlob(digest)
 		if err != nil {
@@ -1447,8 +1918,8 @@ func (is *ImageStore) GetReferrers(repo string, gdigest godigest.Digest, artifac
 ) (ispec.Index, error) {
 	var lockLatency time.Time
 
-	is.RLock(&lockLatency)
-	defer is.RUnlock(&lockLatency)
+	is.RLockRepo(repo, &lockLatency)
+	defer is.RUnlockRepo(repo, &lockLatency)
 
 	return common.GetReferrers(is, repo, gdigest, artifactTypes, is.log)
 }
@@ -1457,8 +1928,8 @@ func (is *ImageStore) GetOrasReferrers(repo string, gdigest godigest.Digest, art
 ) ([]artifactspec.Descriptor, error) {
 	var lockLatency time.Time
 
-	is.RLock(&lockLatency)
-	defer is.RUnlock(&lockLatency)
+	is.RLockRepo(repo, &lockLatency)
+	defer is.RUnlockRepo(repo, &lockLatency)
 
 	return common.GetOrasReferrers(is, repo, gdigest, artifactType, is.log)
 }
@@ -1469,7 +1940,7 @@ func (is *ImageStore) GetIndexContent(repo string) ([]byte, error) {
 
 	buf, err := is.storeDriver.ReadFile(path.Join(dir, "index.json"))
 	if err != nil {
-		if errors.Is(err, driver.PathNotFoundError{}) {
+		if errors.As(err, &driver.PathNotFoundError{}) {
 			is.log.Error().Err(err).Str("dir", dir).Msg("index.json doesn't exist")
 
 			return []byte{}, zerr.ErrRepoNotFound
@@ -1491,8 +1962,10 @@ func (is *ImageStore) DeleteBlob(repo string, digest godigest.Digest) error {
 		return err
 	}
 
-	is.Lock(&lockLatency)
-	defer is.Unlock(&lockLatency)
+	if err := is.LockRepo(repo, &lockLatency); err != nil {
+		return err
+	}
+	defer is.UnlockRepo(repo, &lockLatency)
 
 	return is.deleteBlob(repo, digest)
 }
@@ -1512,7 +1985,31 @@ func (is *ImageStore) deleteBlob(repo string, digest godigest.Digest) error {
 		return zerr.ErrBlobReferenced
 	}
 
-	if fmt.Sprintf("%v", is.cache) != fmt.Sprintf("%v", nil) {
+	if err := is.lock.CheckRepo(repo); err != nil {
+		return err
+	}
+
+	usage := is.blobUsage(repo, digest)
+
+	if err := is.removeBlob(digest, blobPath); err != nil {
+		return err
+	}
+
+	is.updateUsage(repo, -usage)
+	is.removeBlobReferences(repo, digest)
+
+	return nil
+}
+
+// removeBlob removes the blob and its dedupe cache entry, if it holds the content of deduped blobs the content
+// is moved to one of them.
+func (is *ImageStore) removeBlob(digest godigest.Digest, blobPath string) error {
+	if is.cache != nil {
+		if err := is.lockDedupe(); err != nil {
+			return err
+		}
+		defer is.unlockDedupe()
+
 		dstRecord, err := is.cache.GetBlob(digest)
 		if err != nil && !errors.Is(err, zerr.ErrCacheMiss) {
 			is.log.Error().Err(err).Str("blobPath", dstRecord).Msg("dedupe: unable to lookup blob record")
@@ -1573,31 +2070,136 @@ func (is *ImageStore) deleteBlob(repo string, digest godigest.Digest) error {
 	return nil
 }
 
-func (is *ImageStore) garbageCollect(repo string) error {
-	if is.gcReferrers {
-		is.log.Info().Msg("gc: manifests with missing referrers")
+// DeleteRepo removes the repository with all its manifests and blobs, the blobs deduped in other
+// repositories keep their content.
+func (is *ImageStore) DeleteRepo(repo string) error {
+	var lockLatency time.Time
 
-		// gc all manifests that have a missing subject, stop when no gc happened in a full loop over index.json.
-		stop := false
-		for !stop {
-			// because we gc manifests in the loop, need to get latest index.json content
-			index, err := common.GetIndex(is, repo, is.log)
-			if err != nil {
-				return err
-			}
+	dir := path.Join(is.rootDir, repo)
+	if !is.DirExists(dir) {
+		return zerr.ErrRepoNotFound
+	}
 
-			gced, err := is.garbageCollectIndexReferrers(repo, index, index)
-			if err != nil {
-				return err
-			}
+	if err := is.LockRepo(repo, &lockLatency); err != nil {
+		return err
+	}
+	defer is.UnlockRepo(repo, &lockLatency)
+
+	if err := is.lock.CheckRepo(repo); err != nil {
+		return err
+	}
+
+	blobs, err := is.GetAllBlobs(repo)
+	if err != nil && !errors.As(err, &driver.PathNotFoundError{}) {
+		is.log.Error().Err(err).Str("repository", repo).Msg("unable to list the blobs of the repo")
+
+		return err
+	}
+
+	for _, digest := range blobs {
+		usage := is.blobUsage(repo, digest)
+
+		if err := is.removeBlob(digest, is.BlobPath(repo, digest)); err != nil {
+			is.log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
+				Msg("unable to remove the blob of the repo")
 
-			/* if we delete any manifest then loop again and gc manifests with
-			a subject pointing to the last ones which were gc'ed. */
-			stop = !gced
+			return err
 		}
+
+		is.updateUsage(repo, -usage)
 	}
 
-	index, err := common.GetIndex(is, repo, is.log)
+	if err := is.storeDriver.Delete(dir); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Msg("unable to remove the repo")
+
+		return err
+	}
+
+	is.forgetReferences(repo)
+
+	return nil
+}
+
+// gcRun is a garbage collection of a repo, the manifests and blobs it removes are added to its report. The manifests
+// of a dry run are only removed from a copy of the index of the repo, and its blobs aren't removed.
+type gcRun struct {
+	dryRun bool
+	// index is the copy of the index of a dry run
+	index *ispec.Index
+	// removed are the manifests removed by a dry run, their blobs are no longer in the repo
+	removed map[godigest.Digest]bool
+	report  *storageTypes.GCReport
+}
+
+func newGCRun(repo string, dryRun bool) *gcRun {
+	return &gcRun{
+		dryRun:  dryRun,
+		removed: map[godigest.Digest]bool{},
+		report: &storageTypes.GCReport{
+			Repo:      repo,
+			Manifests: []storageTypes.GCReportItem{},
+			Blobs:     []storageTypes.GCReportItem{},
+		},
+	}
+}
+
+// getIndex returns the index of the repo, without the manifests already removed by a dry run.
+func (run *gcRun) getIndex(imgStore *ImageStore, repo string) (ispec.Index, error) {
+	if run.index == nil {
+		index, err := common.GetIndex(imgStore, repo, imgStore.log)
+		if err != nil || !run.dryRun {
+			return index, err
+		}
+
+		run.index = &index
+	}
+
+	index := *run.index
+	index.Manifests = append([]ispec.Descriptor{}, run.index.Manifests...)
+
+	return index, nil
+}
+
+func (run *gcRun) addManifest(desc ispec.Descriptor) {
+	run.removed[desc.Digest] = true
+	run.report.Manifests = append(run.report.Manifests, storageTypes.GCReportItem{Digest: desc.Digest, Size: desc.Size})
+	run.report.BytesFreed += desc.Size
+}
+
+func (run *gcRun) addBlob(digest godigest.Digest, size int64) {
+	run.report.Blobs = append(run.report.Blobs, storageTypes.GCReportItem{Digest: digest, Size: size})
+	run.report.BytesFreed += size
+}
+
+// recordGCMetrics counts the manifests or blobs removed by the garbage collection of the repo, and their size, the
+// removals are counted even if the garbage collection fails afterwards.
+func (is *ImageStore) recordGCMetrics(repo string, run *gcRun, removed []storageTypes.GCReportItem,
+	incReaped func(ms monitoring.MetricServer, repo string, count int),
+) {
+	if run.dryRun || len(removed) == 0 {
+		return
+	}
+
+	var size int64
+
+	for _, item := range removed {
+		size += item.Size
+	}
+
+	incReaped(is.metrics, repo, len(removed))
+	monitoring.IncGCReclaimedBytes(is.metrics, repo, size)
+}
+
+func (is *ImageStore) garbageCollect(repo string, run *gcRun) error {
+	if is.gcReferrers {
+		is.log.Info().Msg("gc: manifests with missing referrers")
+
+		if err := is.garbageCollectReferrers(repo, run); err != nil {
+			return err
+		}
+	}
+
+	index, err := run.getIndex(is, repo)
 	if err != nil {
 		return err
 	}
@@ -1605,13 +2207,13 @@ func (is *ImageStore) garbageCollect(repo string) error {
 	is.log.Info().Msg("gc: manifests without tags")
 
 	// apply image retention policy
-	if err := is.garbageCollectUntaggedManifests(index, repo); err != nil {
+	if err := is.garbageCollectUntaggedManifests(index, repo, run); err != nil {
 		return err
 	}
 
 	is.log.Info().Msg("gc: blobs")
 
-	if err := is.garbageCollectBlobs(is, repo, is.gcDelay, is.log); err != nil {
+	if err := is.garbageCollectBlobs(is, repo, is.gcDelay, is.log, run); err != nil {
 		return err
 	}
 
@@ -1624,7 +2226,37 @@ garbageCollectIndexReferrers will gc all referrers with a missing subject recurs
 rootIndex is indexJson, need to pass it down to garbageCollectReferrer()
 rootIndex is the place we look for referrers.
 */
+// garbageCollectReferrers removes the manifests whose subject is missing, until no manifest is removed.
+func (is *ImageStore) garbageCollectReferrers(repo string, run *gcRun) error {
+	previous := len(run.report.Manifests)
+	defer func() {
+		is.recordGCMetrics(repo, run, run.report.Manifests[previous:], monitoring.IncGCManifestsReaped)
+	}()
+
+	// gc all manifests that have a missing subject, stop when no gc happened in a full loop over index.json.
+	stop := false
+	for !stop {
+		// because we gc manifests in the loop, need to get latest index.json content
+		index, err := run.getIndex(is, repo)
+		if err != nil {
+			return err
+		}
+
+		gced, err := is.garbageCollectIndexReferrers(repo, index, index, run)
+		if err != nil {
+			return err
+		}
+
+		/* if we delete any manifest then loop again and gc manifests with
+		a subject pointing to the last ones which were gc'ed. */
+		stop = !gced
+	}
+
+	return nil
+}
+
 func (is *ImageStore) garbageCollectIndexReferrers(repo string, rootIndex ispec.Index, index ispec.Index,
+	run *gcRun,
 ) (bool, error) {
 	var count int
 
@@ -1641,7 +2273,7 @@ func (is *ImageStore) garbageCollectIndexReferrers(repo string, rootIndex ispec.
 				return false, err
 			}
 
-			gced, err := is.garbageCollectReferrer(repo, rootIndex, desc, indexImage.Subject)
+			gced, err := is.garbageCollectReferrer(repo, rootIndex, desc, indexImage.Subject, run)
 			if err != nil {
 				return false, err
 			}
@@ -1653,7 +2285,7 @@ func (is *ImageStore) garbageCollectIndexReferrers(repo string, rootIndex ispec.
 				return true, nil
 			}
 
-			if gced, err = is.garbageCollectIndexReferrers(repo, rootIndex, indexImage); err != nil {
+			if gced, err = is.garbageCollectIndexReferrers(repo, rootIndex, indexImage, run); err != nil {
 				return false, err
 			}
 
@@ -1670,7 +2302,7 @@ func (is *ImageStore) garbageCollectIndexReferrers(repo string, rootIndex ispec.
 				return false, err
 			}
 
-			gced, err := is.garbageCollectReferrer(repo, rootIndex, desc, image.Subject)
+			gced, err := is.garbageCollectReferrer(repo, rootIndex, desc, image.Subject, run)
 			if err != nil {
 				return false, err
 			}
@@ -1685,7 +2317,7 @@ func (is *ImageStore) garbageCollectIndexReferrers(repo string, rootIndex ispec.
 }
 
 func (is *ImageStore) garbageCollectReferrer(repo string, index ispec.Index, manifestDesc ispec.Descriptor,
-	subject *ispec.Descriptor,
+	subject *ispec.Descriptor, run *gcRun,
 ) (bool, error) {
 	var gced bool
 
@@ -1694,7 +2326,7 @@ func (is *ImageStore) garbageCollectReferrer(repo string, index ispec.Index, man
 	if subject != nil {
 		// try to find subject in index.json
 		if ok := isManifestReferencedInIndex(index, subject.Digest); !ok {
-			gced, err = garbageCollectManifest(is, repo, manifestDesc.Digest, is.gcDelay)
+			gced, err = garbageCollectManifest(is, repo, manifestDesc.Digest, is.gcDelay, run)
 			if err != nil {
 				return false, err
 			}
@@ -1703,10 +2335,10 @@ func (is *ImageStore) garbageCollectReferrer(repo string, index ispec.Index, man
 
 	tag, ok := manifestDesc.Annotations[ispec.AnnotationRefName]
 	if ok {
-		if strings.HasPrefix(tag, "sha256-") && (strings.HasSuffix(tag, cosignSignatureTagSuffix) ||
+		if common.IsCosignTag(tag) && (strings.HasSuffix(tag, cosignSignatureTagSuffix) ||
 			strings.HasSuffix(tag, SBOMTagSuffix)) {
 			if ok := isManifestReferencedInIndex(index, getSubjectFromCosignTag(tag)); !ok {
-				gced, err = garbageCollectManifest(is, repo, manifestDesc.Digest, is.gcDelay)
+				gced, err = garbageCollectManifest(is, repo, manifestDesc.Digest, is.gcDelay, run)
 				if err != nil {
 					return false, err
 				}
@@ -1717,7 +2349,12 @@ func (is *ImageStore) garbageCollectReferrer(repo string, index ispec.Index, man
 	return gced, err
 }
 
-func (is *ImageStore) garbageCollectUntaggedManifests(index ispec.Index, repo string) error {
+func (is *ImageStore) garbageCollectUntaggedManifests(index ispec.Index, repo string, run *gcRun) error {
+	previous := len(run.report.Manifests)
+	defer func() {
+		is.recordGCMetrics(repo, run, run.report.Manifests[previous:], monitoring.IncGCManifestsReaped)
+	}()
+
 	referencedByImageIndex := make([]string, 0)
 
 	if err := identifyManifestsReferencedInIndex(is, index, repo, &referencedByImageIndex); err != nil {
@@ -1735,7 +2372,7 @@ func (is *ImageStore) garbageCollectUntaggedManifests(index ispec.Index, repo st
 		if desc.MediaType == ispec.MediaTypeImageManifest || desc.MediaType == ispec.MediaTypeImageIndex {
 			_, ok := desc.Annotations[ispec.AnnotationRefName]
 			if !ok {
-				_, err := garbageCollectManifest(is, repo, desc.Digest, is.retentionDelay)
+				_, err := garbageCollectManifest(is, repo, desc.Digest, is.retentionDelay, run)
 				if err != nil {
 					return err
 				}
@@ -1790,6 +2427,7 @@ func identifyManifestsReferencedInIndex(imgStore *ImageStore, index ispec.Index,
 }
 
 func garbageCollectManifest(imgStore *ImageStore, repo string, digest godigest.Digest, delay time.Duration,
+	run *gcRun,
 ) (bool, error) {
 	canGC, err := isBlobOlderThan(imgStore, repo, digest, delay, imgStore.log)
 	if err != nil {
@@ -1803,7 +2441,25 @@ func garbageCollectManifest(imgStore *ImageStore, repo string, digest godigest.D
 		imgStore.log.Info().Str("repository", repo).Str("digest", digest.String()).
 			Msg("gc: removing unreferenced manifest")
 
-		if err := imgStore.deleteImageManifest(repo, digest.String(), true); err != nil {
+		var (
+			manifestDesc ispec.Descriptor
+			removed      bool
+		)
+
+		if run.dryRun {
+			var index ispec.Index
+
+			if index, err = run.getIndex(imgStore, repo); err == nil {
+				if manifestDesc, removed, err = imgStore.pruneImageManifest(&index, repo, digest.String(),
+					true); err == nil {
+					run.index = &index
+				}
+			}
+		} else {
+			manifestDesc, removed, err = imgStore.removeImageManifest(repo, digest.String(), true)
+		}
+
+		if err != nil {
 			if errors.Is(err, zerr.ErrManifestConflict) {
 				imgStore.log.Info().Str("repository", repo).Str("digest", digest.String()).
 					Msg("gc: skipping removing manifest due to conflict")
@@ -1814,6 +2470,10 @@ func garbageCollectManifest(imgStore *ImageStore, repo string, digest godigest.D
 			return false, err
 		}
 
+		if removed {
+			run.addManifest(manifestDesc)
+		}
+
 		return true, nil
 	}
 
@@ -1821,11 +2481,39 @@ func garbageCollectManifest(imgStore *ImageStore, repo string, digest godigest.D
 }
 
 func (is *ImageStore) garbageCollectBlobs(imgStore *ImageStore, repo string,
-	delay time.Duration, log zlog.Logger,
+	delay time.Duration, log zlog.Logger, run *gcRun,
 ) error {
+	previous := len(run.report.Blobs)
+	defer func() {
+		is.recordGCMetrics(repo, run, run.report.Blobs[previous:], monitoring.IncGCBlobsReaped)
+	}()
+
+	// a dry run doesn't change the references, it reads all the manifests
+	if is.refCounter != nil && !run.dryRun {
+		tracked, err := is.refCounter.IsTracked(repo)
+		if err != nil {
+			log.Error().Err(err).Str("repository", repo).Msg("unable to get the references of the repo")
+		}
+
+		if tracked {
+			return is.garbageCollectUnreferencedBlobs(repo, delay, log, run)
+		}
+	}
+
 	refBlobs := map[string]bool{}
 
-	err := common.AddRepoBlobsToReferences(imgStore, repo, refBlobs, log)
+	var err error
+
+	if run.dryRun {
+		var index ispec.Index
+
+		if index, err = run.getIndex(imgStore, repo); err == nil {
+			err = common.AddIndexBlobToReferences(imgStore, repo, index, refBlobs, log)
+		}
+	} else {
+		err = common.AddRepoBlobsToReferences(imgStore, repo, refBlobs, log)
+	}
+
 	if err != nil {
 		log.Error().Err(err).Str("repository", repo).Msg("unable to get referenced blobs in repo")
 
@@ -1834,7 +2522,7 @@ func (is *ImageStore) garbageCollectBlobs(imgStore *ImageStore, repo string,
 
 	allBlobs, err := imgStore.GetAllBlobs(repo)
 	if err != nil {
-		// /blobs/sha256/ may be empty in the case of s3, no need to return err, we want to skip
+		// /blobs/ may be empty in the case of s3, no need to return err, we want to skip
 		if errors.As(err, &driver.PathNotFoundError{}) {
 			return nil
 		}
@@ -1846,8 +2534,9 @@ func (is *ImageStore) garbageCollectBlobs(imgStore *ImageStore, repo string,
 
 	reaped := 0
 
-	for _, blob := range allBlobs {
-		digest := godigest.NewDigestFromEncoded(godigest.SHA256, blob)
+	for _, digest := range allBlobs {
+		blob := digest.String()
+
 		if err = digest.Validate(); err != nil {
 			log.Error().Err(err).Str("repository", repo).Str("digest", blob).Msg("unable to parse digest")
 
@@ -1855,6 +2544,13 @@ func (is *ImageStore) garbageCollectBlobs(imgStore *ImageStore, repo string,
 		}
 
 		if _, ok := refBlobs[digest.String()]; !ok {
+			// the manifests removed by a dry run are already in its report
+			if run.removed[digest] {
+				reaped++
+
+				continue
+			}
+
 			ok, err := isBlobOlderThan(imgStore, repo, digest, delay, log)
 			if err != nil {
 				log.Error().Err(err).Str("repository", repo).Str("digest", blob).Msg("unable to determine GC delay")
@@ -1866,6 +2562,17 @@ func (is *ImageStore) garbageCollectBlobs(imgStore *ImageStore, repo string,
 				continue
 			}
 
+			// the deduped blobs have the size of their content
+			_, size, _, _ := imgStore.StatBlob(repo, digest)
+
+			if run.dryRun {
+				run.addBlob(digest, size)
+
+				reaped++
+
+				continue
+			}
+
 			if err := imgStore.deleteBlob(repo, digest); err != nil {
 				if errors.Is(err, zerr.ErrBlobReferenced) {
 					if err := imgStore.deleteImageManifest(repo, digest.String(), true); err != nil {
@@ -1886,12 +2593,20 @@ func (is *ImageStore) garbageCollectBlobs(imgStore *ImageStore, repo string,
 
 			log.Info().Str("repository", repo).Str("digest", blob).Msg("garbage collected blob")
 
+			run.addBlob(digest, size)
+
 			reaped++
 		}
 	}
 
 	// if we cleaned all blobs let's also remove the repo so that it won't be returned by catalog
 	if reaped == len(allBlobs) {
+		run.report.RepoRemoved = true
+
+		if run.dryRun {
+			return nil
+		}
+
 		log.Info().Str("repository", repo).Msg("garbage collected all blobs, cleaning repo...")
 
 		if err := is.storeDriver.Delete(path.Join(is.rootDir, repo)); err != nil {
@@ -1899,58 +2614,363 @@ func (is *ImageStore) garbageCollectBlobs(imgStore *ImageStore, repo string,
 
 			return err
 		}
+
+		is.forgetReferences(repo)
+	}
+
+	if run.dryRun {
+		return nil
 	}
 
 	log.Info().Str("repository", repo).Int("count", reaped).Msg("garbage collected blobs")
 
+	if !run.report.RepoRemoved {
+		is.trackReferences(repo)
+	}
+
 	return nil
 }
 
-func (is *ImageStore) gcRepo(repo string) error {
-	var lockLatency time.Time
+// trackReferences counts the references of all the manifests of the repo, its next garbage collections only check
+// the blobs which aren't referenced.
+func (is *ImageStore) trackReferences(repo string) {
+	if is.refCounter == nil {
+		return
+	}
 
-	is.Lock(&lockLatency)
-	err := is.garbageCollect(repo)
-	is.Unlock(&lockLatency)
+	index, err := common.GetIndex(is, repo, is.log)
+	if err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Msg("unable to count the references of the repo")
+
+		return
+	}
+
+	manifests := map[godigest.Digest][]godigest.Digest{}
 
+	if err := is.addIndexReferences(repo, index, manifests); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Msg("unable to count the references of the repo")
+
+		return
+	}
+
+	blobs, err := is.GetAllBlobs(repo)
 	if err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Msg("unable to count the references of the repo")
+
+		return
+	}
+
+	if err := is.refCounter.Track(repo, manifests, blobs); err != nil {
+		is.log.Error().Err(err).Str("repository", repo).Msg("unable to store the references of the repo")
+
+		return
+	}
+
+	is.log.Info().Str("repository", repo).Int("manifests", len(manifests)).Int("blobs", len(blobs)).
+		Msg("gc: the references of the repo are counted")
+}
+
+// addIndexReferences adds the references of the manifests of the index, and of the manifests of its image indexes.
+func (is *ImageStore) addIndexReferences(repo string, index ispec.Index,
+	manifests map[godigest.Digest][]godigest.Digest,
+) error {
+	for _, desc := range index.Manifests {
+		if _, ok := manifests[desc.Digest]; ok {
+			continue
+		}
+
+		switch desc.MediaType {
+		case ispec.MediaTypeImageIndex, ispec.MediaTypeImageManifest, artifactspec.MediaTypeArtifactManifest:
+		default:
+			continue
+		}
+
+		buf, err := is.GetBlobContent(repo, desc.Digest)
+		if err != nil {
+			return err
+		}
+
+		refs, err := common.GetManifestReferences(desc.MediaType, buf)
+		if err != nil {
+			return err
+		}
+
+		manifests[desc.Digest] = refs
+
+		if desc.MediaType == ispec.MediaTypeImageIndex {
+			var indexImage ispec.Index
+
+			if err := json.Unmarshal(buf, &indexImage); err != nil {
+				return err
+			}
+
+			if err := is.addIndexReferences(repo, indexImage, manifests); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// countIndexManifests counts the references of the manifests of the index which aren't counted, e.g. when zot
+// stopped between the write of the index and the count of a pushed manifest.
+func (is *ImageStore) countIndexManifests(repo string, index ispec.Index) error {
+	uncounted := ispec.Index{}
+
+	for _, desc := range index.Manifests {
+		counted, err := is.refCounter.HasManifest(repo, desc.Digest)
+		if err != nil {
+			return err
+		}
+
+		if !counted {
+			uncounted.Manifests = append(uncounted.Manifests, desc)
+		}
+	}
+
+	if len(uncounted.Manifests) == 0 {
+		return nil
+	}
+
+	manifests := map[godigest.Digest][]godigest.Digest{}
+
+	if err := is.addIndexReferences(repo, uncounted, manifests); err != nil {
 		return err
 	}
 
+	for manifest, refs := range manifests {
+		if err := is.refCounter.AddManifest(repo, manifest, refs); err != nil {
+			return err
+		}
+	}
+
+	is.log.Warn().Str("repository", repo).Int("manifests", len(manifests)).
+		Msg("gc: counted the references of the manifests missing from the counts")
+
 	return nil
 }
 
-func (is *ImageStore) GetAllBlobs(repo string) ([]string, error) {
-	dir := path.Join(is.rootDir, repo, "blobs", "sha256")
+// garbageCollectUnreferencedBlobs removes the blobs no manifest references, and which aren't in the index of the
+// repo, using the counted references instead of reading all the manifests. The blobs of the removed manifests are
+// checked in the next pass.
+func (is *ImageStore) garbageCollectUnreferencedBlobs(repo string, delay time.Duration, log zlog.Logger,
+	run *gcRun,
+) error {
+	index, err := common.GetIndex(is, repo, log)
+	if err != nil {
+		return err
+	}
 
-	files, err := is.storeDriver.List(dir)
+	inIndex := map[godigest.Digest]bool{}
+	for _, desc := range index.Manifests {
+		inIndex[desc.Digest] = true
+	}
+
+	if err := is.countIndexManifests(repo, index); err != nil {
+		log.Error().Err(err).Str("repository", repo).Msg("unable to count the references of the repo")
+
+		return err
+	}
+
+	reaped := 0
+
+	for {
+		candidates, err := is.refCounter.Unreferenced(repo)
+		if err != nil {
+			log.Error().Err(err).Str("repository", repo).Msg("unable to get the unreferenced blobs of the repo")
+
+			return err
+		}
+
+		removed := 0
+
+		for _, digest := range candidates {
+			if inIndex[digest] {
+				continue
+			}
+
+			blobPath := is.BlobPath(repo, digest)
+
+			// the blob may not be in the repo, e.g. a subject which was never pushed
+			if digest.Validate() != nil || !is.isBlobInRepo(blobPath) {
+				is.removeBlobReferences(repo, digest)
+
+				continue
+			}
+
+			ok, err := isBlobOlderThan(is, repo, digest, delay, log)
+			if err != nil {
+				log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
+					Msg("unable to determine GC delay")
+
+				return err
+			}
+
+			if !ok {
+				continue
+			}
+
+			if err := is.lock.CheckRepo(repo); err != nil {
+				return err
+			}
+
+			// the deduped blobs have the size of their content
+			_, size, _, _ := is.StatBlob(repo, digest)
+			usage := is.blobUsage(repo, digest)
+
+			if err := is.removeBlob(digest, blobPath); err != nil {
+				log.Error().Err(err).Str("repository", repo).Str("digest", digest.String()).
+					Msg("unable to delete blob")
+
+				return err
+			}
+
+			is.updateUsage(repo, -usage)
+			is.removeBlobReferences(repo, digest)
+
+			log.Info().Str("repository", repo).Str("digest", digest.String()).Msg("garbage collected blob")
+
+			run.addBlob(digest, size)
+
+			removed++
+		}
+
+		if removed == 0 {
+			break
+		}
+
+		reaped += removed
+	}
+
+	// if we cleaned all blobs let's also remove the repo so that it won't be returned by catalog
+	if len(index.Manifests) == 0 {
+		blobs, err := is.GetAllBlobs(repo)
+		if err == nil && len(blobs) == 0 {
+			log.Info().Str("repository", repo).Msg("garbage collected all blobs, cleaning repo...")
+
+			if err := is.storeDriver.Delete(path.Join(is.rootDir, repo)); err != nil {
+				log.Error().Err(err).Str("repository", repo).Msg("unable to delete repo")
+
+				return err
+			}
+
+			is.forgetReferences(repo)
+
+			run.report.RepoRemoved = true
+		}
+	}
+
+	log.Info().Str("repository", repo).Int("count", reaped).Msg("garbage collected blobs")
+
+	return nil
+}
+
+func (is *ImageStore) isBlobInRepo(blobPath string) bool {
+	_, err := is.storeDriver.Stat(blobPath)
+
+	return err == nil
+}
+
+func (is *ImageStore) gcRepo(repo string, run *gcRun) error {
+	var lockLatency time.Time
+
+	// a dry run only reads the repo
+	if run.dryRun {
+		is.RLockRepo(repo, &lockLatency)
+		defer is.RUnlockRepo(repo, &lockLatency)
+	} else {
+		if err := is.LockRepo(repo, &lockLatency); err != nil {
+			return err
+		}
+		defer is.UnlockRepo(repo, &lockLatency)
+	}
+
+	return is.garbageCollect(repo, run)
+}
+
+// GetAllBlobs returns the digests of the blobs of the repo, the blobs of each digest algorithm are under
+// blobs/<algorithm>, the directories of the algorithms which aren't supported are skipped.
+func (is *ImageStore) GetAllBlobs(repo string) ([]godigest.Digest, error) {
+	dir := path.Join(is.rootDir, repo, "blobs")
+
+	algorithmDirs, err := is.storeDriver.List(dir)
 	if err != nil {
-		return []string{}, err
+		return []godigest.Digest{}, err
 	}
 
-	ret := []string{}
+	ret := []godigest.Digest{}
 
-	for _, file := range files {
-		ret = append(ret, filepath.Base(file))
+	for _, algorithmDir := range algorithmDirs {
+		algorithm := godigest.Algorithm(filepath.Base(algorithmDir))
+		if !algorithm.Available() {
+			continue
+		}
+
+		files, err := is.storeDriver.List(algorithmDir)
+		if err != nil {
+			return []godigest.Digest{}, err
+		}
+
+		for _, file := range files {
+			ret = append(ret, godigest.NewDigestFromEncoded(algorithm, filepath.Base(file)))
+		}
 	}
 
 	return ret, nil
 }
 
 func (is *ImageStore) RunGCRepo(repo string) error {
+	_, err := is.RunGCRepoReport(repo, false)
+
+	return err
+}
+
+// RunGCRepoReport garbage collects the repo and returns the manifests and blobs it removed, a dry run doesn't
+// remove anything and returns those which would be removed.
+func (is *ImageStore) RunGCRepoReport(repo string, dryRun bool) (storageTypes.GCReport, error) {
+	run := newGCRun(repo, dryRun)
+
+	if is.readOnly {
+		is.log.Info().Msg(fmt.Sprintf("skipping GC of the read-only store for %s", path.Join(is.RootDir(), repo)))
+
+		return *run.report, nil
+	}
+
+	if dryRun {
+		is.log.Info().Msg(fmt.Sprintf("executing GC dry run for %s", path.Join(is.RootDir(), repo)))
+
+		if err := is.gcRepo(repo, run); err != nil {
+			is.log.Error().Err(err).Msg(fmt.Sprintf("error while running GC dry run for %s",
+				path.Join(is.RootDir(), repo)))
+
+			return storageTypes.GCReport{}, err
+		}
+
+		return *run.report, nil
+	}
+
 	is.log.Info().Msg(fmt.Sprintf("executing GC of orphaned blobs for %s", path.Join(is.RootDir(), repo)))
 
-	if err := is.gcRepo(repo); err != nil {
+	start := time.Now()
+
+	err := is.gcRepo(repo, run)
+
+	monitoring.ObserveGCDuration(is.metrics, repo, time.Since(start))
+
+	if err != nil {
 		errMessage := fmt.Sprintf("error while running GC for %s", path.Join(is.RootDir(), repo))
 		is.log.Error().Err(err).Msg(errMessage)
 		is.log.Info().Msg(fmt.Sprintf("GC unsuccessfully completed for %s", path.Join(is.RootDir(), repo)))
 
-		return err
+		return *run.report, err
 	}
 
+	monitoring.SetGCLastSuccess(is.metrics, repo, time.Now())
+
 	is.log.Info().Msg(fmt.Sprintf("GC successfully completed for %s", path.Join(is.RootDir(), repo)))
 
-	return nil
+	return *run.report, nil
 }
 
 func (is *ImageStore) RunGCPeriodically(interval time.Duration, sch *scheduler.Scheduler) {
@@ -1983,7 +3003,10 @@ func (is *ImageStore) GetNextDigestWithBlobPaths(lastDigests []godigest.Digest)
 			return nil
 		}
 
-		blobDigest := godigest.NewDigestFromEncoded("sha256", path.Base(fileInfo.Path()))
+		// the blobs are under blobs/<algorithm>
+		algorithm := godigest.Algorithm(path.Base(path.Dir(fileInfo.Path())))
+
+		blobDigest := godigest.NewDigestFromEncoded(algorithm, path.Base(fileInfo.Path()))
 		if err := blobDigest.Validate(); err != nil { //nolint: nilerr
 			return nil //nolint: nilerr // ignore files which are not blobs
 		}
@@ -2056,7 +3079,7 @@ func (is *ImageStore) getOriginalBlob(digest godigest.Digest, duplicateBlobs []s
 }
 
 func (is *ImageStore) dedupeBlobs(digest godigest.Digest, duplicateBlobs []string) error {
-	if fmt.Sprintf("%v", is.cache) == fmt.Sprintf("%v", nil) {
+	if is.cache == nil {
 		is.log.Error().Err(zerr.ErrDedupeRebuild).Msg("no cache driver found, can not dedupe blobs")
 
 		return zerr.ErrDedupeRebuild
@@ -2183,6 +3206,176 @@ func (is *ImageStore) RunDedupeForDigest(digest godigest.Digest, dedupe bool, du
 	return is.restoreDedupedBlobs(digest, duplicateBlobs)
 }
 
+/*
+RunGCDedupedBlobs repairs the blobs recorded in the dedupe cache, whose copies in the other repos point at the
+content of the original blob:
+  - the records of the blobs removed from the storage, e.g. with their repo, are removed
+  - if the original blob has no content, it's moved back to it from a copy still having it
+  - if no blob has the content anymore, their records and empty copies are removed, so the clients push them again.
+
+With chunk dedupe, the chunks which aren't referenced by the blobs anymore are removed first.
+*/
+func (is *ImageStore) RunGCDedupedBlobs() error {
+	if is.readOnly {
+		return nil
+	}
+
+	if is.chunkDriver != nil {
+		if _, err := is.chunkDriver.CollectGarbage(is.gcDelay); err != nil {
+			is.log.Error().Err(err).Msg("chunk dedupe: unable to remove the unreferenced chunks")
+
+			return err
+		}
+	}
+
+	if is.cache == nil {
+		return nil
+	}
+
+	digests, err := is.cache.GetAllDigests()
+	if err != nil {
+		is.log.Error().Err(err).Msg("dedupe gc: unable to list the blobs of the cache")
+
+		return err
+	}
+
+	repaired := 0
+
+	for _, digest := range digests {
+		ok, err := is.gcDedupedBlob(digest)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			repaired++
+		}
+	}
+
+	is.log.Info().Int("repaired", repaired).Msg("dedupe gc: finished")
+
+	return nil
+}
+
+// gcDedupedBlob repairs the blobs of the digest, it returns true if any of them was repaired.
+func (is *ImageStore) gcDedupedBlob(digest godigest.Digest) (bool, error) {
+	var lockLatency time.Time
+
+	is.Lock(&lockLatency)
+	defer is.Unlock(&lockLatency)
+
+	blobPaths, err := is.cache.GetAllBlobs(digest)
+	if err != nil {
+		// the blobs were removed since the cache was listed
+		if errors.Is(err, zerr.ErrCacheMiss) {
+			return false, nil
+		}
+
+		is.log.Error().Err(err).Str("digest", digest.String()).Msg("dedupe gc: unable to get the blobs")
+
+		return false, err
+	}
+
+	repaired := false
+	sizes := map[string]int64{}
+	present := []string{}
+
+	for _, blobPath := range blobPaths {
+		if is.cache.UsesRelativePaths() {
+			blobPath = path.Join(is.rootDir, blobPath)
+		}
+
+		binfo, err := is.storeDriver.Stat(blobPath)
+		if err != nil {
+			if !errors.As(err, &driver.PathNotFoundError{}) {
+				is.log.Error().Err(err).Str("blobPath", blobPath).Msg("dedupe gc: unable to stat blob")
+
+				return repaired, err
+			}
+
+			if err := is.cache.DeleteBlob(digest, blobPath); err != nil {
+				is.log.Error().Err(err).Str("blobPath", blobPath).Msg("dedupe gc: unable to remove blob record")
+
+				return repaired, err
+			}
+
+			is.log.Info().Str("digest", digest.String()).Str("blobPath", blobPath).
+				Msg("dedupe gc: removed the record of a missing blob")
+
+			repaired = true
+
+			continue
+		}
+
+		sizes[blobPath] = binfo.Size()
+		present = append(present, blobPath)
+	}
+
+	// the blobs without content don't need one
+	if len(present) == 0 || digest == godigest.FromBytes([]byte{}) {
+		return repaired, nil
+	}
+
+	// the next original blob was picked by the cache when the missing one was removed
+	originBlob, err := is.cache.GetBlob(digest)
+	if err != nil {
+		is.log.Error().Err(err).Str("digest", digest.String()).Msg("dedupe gc: unable to lookup blob record")
+
+		return repaired, err
+	}
+
+	if is.cache.UsesRelativePaths() {
+		originBlob = path.Join(is.rootDir, originBlob)
+	}
+
+	if sizes[originBlob] > 0 {
+		return repaired, nil
+	}
+
+	for _, blobPath := range present {
+		if sizes[blobPath] == 0 {
+			continue
+		}
+
+		if err := is.storeDriver.Move(blobPath, originBlob); err != nil {
+			is.log.Error().Err(err).Str("src", blobPath).Str("dst", originBlob).
+				Msg("dedupe gc: unable to move the content to the original blob")
+
+			return repaired, err
+		}
+
+		if _, err := is.storeDriver.WriteFile(blobPath, []byte{}); err != nil {
+			is.log.Error().Err(err).Str("blobPath", blobPath).Msg("dedupe gc: unable to dedupe blob")
+
+			return true, err
+		}
+
+		is.log.Info().Str("digest", digest.String()).Str("src", blobPath).Str("dst", originBlob).
+			Msg("dedupe gc: moved the content to the original blob")
+
+		return true, nil
+	}
+
+	is.log.Error().Str("digest", digest.String()).Strs("blobPaths", present).
+		Msg("dedupe gc: the content of the blobs is lost, removing them")
+
+	for _, blobPath := range present {
+		if err := is.cache.DeleteBlob(digest, blobPath); err != nil {
+			is.log.Error().Err(err).Str("blobPath", blobPath).Msg("dedupe gc: unable to remove blob record")
+
+			return true, err
+		}
+
+		if err := is.storeDriver.Delete(blobPath); err != nil {
+			is.log.Error().Err(err).Str("blobPath", blobPath).Msg("dedupe gc: unable to remove blob")
+
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
 func (is *ImageStore) RunDedupeBlobs(interval time.Duration, sch *scheduler.Scheduler) {
 	generator := &common.DedupeTaskGenerator{
 		ImgStore: is,
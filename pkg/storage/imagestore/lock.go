@@ -0,0 +1,121 @@
+package imagestore
+
+import (
+	"sync"
+
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+// ImageStoreLock serializes the accesses to an image store, each repository has its own lock so the writes in
+// one repository don't block the others.
+type ImageStoreLock struct {
+	// the locks of the repositories, created on first use
+	repoLocks sync.Map
+	// the lock of the whole store, the repository locks hold it for reading
+	globalLock sync.RWMutex
+	// locks the repositories written by the other zot instances sharing the storage, if any
+	repoLocker storageTypes.RepoLocker
+}
+
+func NewImageStoreLock() *ImageStoreLock {
+	return &ImageStoreLock{}
+}
+
+// RLock read-locks the whole store, the writes in the repositories aren't blocked.
+func (sl *ImageStoreLock) RLock() {
+	sl.globalLock.RLock()
+}
+
+// RUnlock read-unlocks the whole store.
+func (sl *ImageStoreLock) RUnlock() {
+	sl.globalLock.RUnlock()
+}
+
+// Lock write-locks the whole store, all the repositories are blocked.
+func (sl *ImageStoreLock) Lock() {
+	sl.globalLock.Lock()
+}
+
+// Unlock write-unlocks the whole store.
+func (sl *ImageStoreLock) Unlock() {
+	sl.globalLock.Unlock()
+}
+
+// RLockRepo read-locks the repository.
+func (sl *ImageStoreLock) RLockRepo(repo string) {
+	sl.globalLock.RLock()
+	sl.repoLock(repo).RLock()
+}
+
+// RUnlockRepo read-unlocks the repository.
+func (sl *ImageStoreLock) RUnlockRepo(repo string) {
+	sl.repoLock(repo).RUnlock()
+	sl.globalLock.RUnlock()
+}
+
+// SetRepoLocker shares the write locks of the repositories with the other zot instances, it's set before the
+// store is used.
+func (sl *ImageStoreLock) SetRepoLocker(locker storageTypes.RepoLocker) {
+	sl.repoLocker = locker
+}
+
+// LockRepo write-locks the repository, and for the other zot instances sharing the storage if any. The repository
+// isn't locked if the other instances can't be reached.
+func (sl *ImageStoreLock) LockRepo(repo string) error {
+	sl.globalLock.RLock()
+	sl.repoLock(repo).Lock()
+
+	if sl.repoLocker != nil {
+		if err := sl.repoLocker.LockRepo(repo); err != nil {
+			sl.repoLock(repo).Unlock()
+			sl.globalLock.RUnlock()
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnlockRepo write-unlocks the repository.
+func (sl *ImageStoreLock) UnlockRepo(repo string) {
+	if sl.repoLocker != nil {
+		sl.repoLocker.UnlockRepo(repo)
+	}
+
+	sl.repoLock(repo).Unlock()
+	sl.globalLock.RUnlock()
+}
+
+// CheckRepo returns an error if the other zot instances may write in the write-locked repository, e.g. if the
+// lock couldn't be renewed.
+func (sl *ImageStoreLock) CheckRepo(repo string) error {
+	if sl.repoLocker == nil {
+		return nil
+	}
+
+	return sl.repoLocker.CheckRepo(repo)
+}
+
+// LockDedupe locks the deduped blobs for the other zot instances sharing the storage if any, the repository is
+// write-locked.
+func (sl *ImageStoreLock) LockDedupe() error {
+	if sl.repoLocker == nil {
+		return nil
+	}
+
+	return sl.repoLocker.LockDedupe()
+}
+
+// UnlockDedupe unlocks the deduped blobs.
+func (sl *ImageStoreLock) UnlockDedupe() {
+	if sl.repoLocker != nil {
+		sl.repoLocker.UnlockDedupe()
+	}
+}
+
+func (sl *ImageStoreLock) repoLock(repo string) *sync.RWMutex {
+	lock, _ := sl.repoLocks.LoadOrStore(repo, &sync.RWMutex{})
+
+	return lock.(*sync.RWMutex) //nolint:forcetypeassert // only RWMutexes are stored
+}
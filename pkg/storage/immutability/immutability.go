@@ -0,0 +1,52 @@
+// Package immutability keeps the tags of the repos from being moved to other manifests once pushed.
+package immutability
+
+import (
+	glob "github.com/bmatcuk/doublestar/v4"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/storage"
+)
+
+// Policy tells which tags can be pushed again with other manifests, following the first policy matching the repo.
+type Policy struct {
+	config *config.TagImmutabilityConfig
+}
+
+func NewPolicy(conf *config.TagImmutabilityConfig) *Policy {
+	return &Policy{config: conf}
+}
+
+// SetTagPolicy enforces the policy in the image stores.
+func (policy *Policy) SetTagPolicy(storeController storage.StoreController) {
+	if storeController.DefaultStore != nil {
+		storeController.DefaultStore.SetTagPolicy(policy)
+	}
+
+	for _, imgStore := range storeController.SubStore {
+		imgStore.SetTagPolicy(policy)
+	}
+}
+
+// IsTagMutable returns false if the repo matches a policy and the tag doesn't match its mutable tags.
+func (policy *Policy) IsTagMutable(repo, tag string) bool {
+	for _, repoPolicy := range policy.config.Policies {
+		if !matchAny(repoPolicy.Repos, repo) {
+			continue
+		}
+
+		return matchAny(repoPolicy.MutableTags, tag)
+	}
+
+	return true
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := glob.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package immutability_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	"zotregistry.io/zot/pkg/storage/immutability"
+	"zotregistry.io/zot/pkg/storage/local"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func TestTagImmutability(t *testing.T) {
+	Convey("Immutable tags can't be moved to other manifests", t, func() {
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(t.TempDir(), false, false, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		policy := immutability.NewPolicy(&config.TagImmutabilityConfig{
+			Policies: []config.TagImmutabilityPolicy{
+				{Repos: []string{"infra/**"}},
+				{Repos: []string{"**"}, MutableTags: []string{"latest", "dev-*"}},
+			},
+		})
+		policy.SetTagPolicy(storeController)
+
+		So(policy.IsTagMutable("infra/proxy", "latest"), ShouldBeFalse)
+		So(policy.IsTagMutable("app", "1.0"), ShouldBeFalse)
+		So(policy.IsTagMutable("app", "latest"), ShouldBeTrue)
+		So(policy.IsTagMutable("app", "dev-123"), ShouldBeTrue)
+		So(immutability.NewPolicy(&config.TagImmutabilityConfig{}).IsTagMutable("app", "1.0"), ShouldBeTrue)
+
+		pushManifest := func(image Image, tag string) error {
+			_, _, err := imgStore.PutImageManifest("app", tag, ispec.MediaTypeImageManifest,
+				image.ManifestDescriptor.Data)
+
+			return err
+		}
+
+		image := CreateRandomImage()
+
+		for _, tag := range []string{"1.0", "latest", "dev-1"} {
+			err := test.WriteImageToFileSystem(image, "app", tag, storeController)
+			So(err, ShouldBeNil)
+		}
+
+		// the blobs of the other image are pushed with another tag
+		otherImage := CreateRandomImage()
+
+		err := test.WriteImageToFileSystem(otherImage, "app", "2.0", storeController)
+		So(err, ShouldBeNil)
+
+		err = pushManifest(otherImage, "1.0")
+		So(errors.Is(err, zerr.ErrImmutableTag), ShouldBeTrue)
+
+		_, digest, _, err := imgStore.GetImageManifest("app", "1.0")
+		So(err, ShouldBeNil)
+		So(digest, ShouldEqual, image.ManifestDescriptor.Digest)
+
+		// the same manifest can be pushed again
+		err = pushManifest(image, "1.0")
+		So(err, ShouldBeNil)
+
+		for _, tag := range []string{"latest", "dev-1"} {
+			err = pushManifest(otherImage, tag)
+			So(err, ShouldBeNil)
+
+			_, digest, _, err = imgStore.GetImageManifest("app", tag)
+			So(err, ShouldBeNil)
+			So(digest, ShouldEqual, otherImage.ManifestDescriptor.Digest)
+		}
+
+		// the immutable tags can still be deleted then pushed again
+		err = imgStore.DeleteImageManifest("app", "1.0", false)
+		So(err, ShouldBeNil)
+
+		err = pushManifest(otherImage, "1.0")
+		So(err, ShouldBeNil)
+
+		manifest := otherImage.Manifest
+		manifest.Annotations = map[string]string{"key": "value"}
+
+		body, err := json.Marshal(manifest)
+		So(err, ShouldBeNil)
+
+		_, _, err = imgStore.PutImageManifest("app", "2.0", ispec.MediaTypeImageManifest, body)
+		So(errors.Is(err, zerr.ErrImmutableTag), ShouldBeTrue)
+	})
+}
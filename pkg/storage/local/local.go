@@ -14,7 +14,7 @@ import (
 // NewImageStore returns a new image store backed by a file storage.
 // Use the last argument to properly set a cache database, or it will default to boltDB local storage.
 func NewImageStore(rootDir string, gc bool, gcReferrers bool, gcDelay time.Duration,
-	untaggedImageRetentionDelay time.Duration, dedupe, commit bool,
+	untaggedImageRetentionDelay time.Duration, dedupe, commit, readOnly bool,
 	log zlog.Logger, metrics monitoring.MetricServer, linter common.Lint, cacheDriver cache.Cache,
 ) storageTypes.ImageStore {
 	return imagestore.NewImageStore(
@@ -26,6 +26,7 @@ func NewImageStore(rootDir string, gc bool, gcReferrers bool, gcDelay time.Durat
 		untaggedImageRetentionDelay,
 		dedupe,
 		commit,
+		readOnly,
 		log,
 		metrics,
 		linter,
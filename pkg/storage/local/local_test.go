@@ -16,6 +16,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	godigest "github.com/opencontainers/go-digest"
@@ -33,8 +34,10 @@ import (
 	"zotregistry.io/zot/pkg/scheduler"
 	"zotregistry.io/zot/pkg/storage"
 	"zotregistry.io/zot/pkg/storage/cache"
+	"zotregistry.io/zot/pkg/storage/chunking"
 	storageConstants "zotregistry.io/zot/pkg/storage/constants"
 	"zotregistry.io/zot/pkg/storage/local"
+	"zotregistry.io/zot/pkg/storage/refcount"
 	storageTypes "zotregistry.io/zot/pkg/storage/types"
 	"zotregistry.io/zot/pkg/test"
 	. "zotregistry.io/zot/pkg/test/image-utils"
@@ -70,7 +73,7 @@ func TestStorageFSAPIs(t *testing.T) {
 	}, log)
 
 	imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 	Convey("Repo layout", t, func(c C) {
 		Convey("Bad image manifest", func() {
@@ -208,7 +211,7 @@ func TestGetOrasReferrers(t *testing.T) {
 	}, log)
 
 	imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 	Convey("Get referrers", t, func(c C) {
 		err := test.WriteImageToFileSystem(CreateDefaultVulnerableImage(), "zot-test", "0.0.1", storage.StoreController{
@@ -268,7 +271,7 @@ func FuzzNewBlobUpload(f *testing.F) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		_, err := imgStore.NewBlobUpload(data)
 		if err != nil {
@@ -294,7 +297,7 @@ func FuzzPutBlobChunk(f *testing.F) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		repoName := data
 		uuid, err := imgStore.NewBlobUpload(repoName)
@@ -328,7 +331,7 @@ func FuzzPutBlobChunkStreamed(f *testing.F) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		repoName := data
 
@@ -361,7 +364,7 @@ func FuzzGetBlobUpload(f *testing.F) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil,
 			cacheDriver)
 
 		_, err := imgStore.GetBlobUpload(data1, data2)
@@ -388,7 +391,7 @@ func FuzzTestPutGetImageManifest(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		cblob, cdigest := test.GetRandomImageConfig()
 
@@ -440,7 +443,7 @@ func FuzzTestPutDeleteImageManifest(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		cblob, cdigest := test.GetRandomImageConfig()
 
@@ -499,7 +502,7 @@ func FuzzTestDeleteImageManifest(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		digest, _, err := newRandomBlobForFuzz(data)
 		if err != nil {
@@ -535,7 +538,7 @@ func FuzzInitRepo(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		err := imgStore.InitRepo(data)
 		if err != nil {
 			if isKnownErr(err) {
@@ -560,7 +563,7 @@ func FuzzInitValidateRepo(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		err := imgStore.InitRepo(data)
 		if err != nil {
 			if isKnownErr(err) {
@@ -592,7 +595,7 @@ func FuzzGetImageTags(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		_, err := imgStore.GetImageTags(data)
 		if err != nil {
 			if errors.Is(err, zerr.ErrRepoNotFound) || isKnownErr(err) {
@@ -617,7 +620,7 @@ func FuzzBlobUploadPath(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		_ = imgStore.BlobUploadPath(repo, uuid)
 	})
@@ -637,7 +640,7 @@ func FuzzBlobUploadInfo(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		repo := data
 
 		_, err := imgStore.BlobUploadInfo(repo, uuid)
@@ -663,7 +666,7 @@ func FuzzTestGetImageManifest(f *testing.F) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		repoName := data
 
@@ -692,7 +695,7 @@ func FuzzFinishBlobUpload(f *testing.F) {
 			UseRelPaths: true,
 		}, log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		repoName := data
 
@@ -742,7 +745,7 @@ func FuzzFullBlobUpload(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		ldigest, lblob, err := newRandomBlobForFuzz(data)
 		if err != nil {
@@ -775,7 +778,7 @@ func TestStorageCacheErrors(t *testing.T) {
 
 		imgStore := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
 			storageConstants.DefaultUntaggedImgeRetentionDelay,
-			true, true, log, metrics, nil, &mocks.CacheMock{
+			true, true, false, log, metrics, nil, &mocks.CacheMock{
 				PutBlobFn: func(digest godigest.Digest, path string) error {
 					if strings.Contains(path, dedupedRepo) {
 						return errCache
@@ -817,7 +820,7 @@ func FuzzDedupeBlob(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		blobDigest := godigest.FromString(data)
 
@@ -859,7 +862,7 @@ func FuzzDeleteBlobUpload(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		uuid, err := imgStore.NewBlobUpload(repoName)
 		if err != nil {
@@ -891,7 +894,7 @@ func FuzzBlobPath(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		digest := godigest.FromString(data)
 
 		_ = imgStore.BlobPath(repoName, digest)
@@ -913,7 +916,7 @@ func FuzzCheckBlob(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		digest := godigest.FromString(data)
 
 		_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader([]byte(data)), digest)
@@ -945,7 +948,7 @@ func FuzzGetBlob(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		digest := godigest.FromString(data)
 
 		_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader([]byte(data)), digest)
@@ -984,7 +987,7 @@ func FuzzDeleteBlob(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		digest := godigest.FromString(data)
 
 		_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader([]byte(data)), digest)
@@ -1020,7 +1023,7 @@ func FuzzGetIndexContent(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		digest := godigest.FromString(data)
 
 		_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader([]byte(data)), digest)
@@ -1056,7 +1059,7 @@ func FuzzGetBlobContent(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 		digest := godigest.FromString(data)
 
 		_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader([]byte(data)), digest)
@@ -1092,7 +1095,7 @@ func FuzzGetOrasReferrers(f *testing.F) {
 		}, *log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		storageCtlr := storage.StoreController{DefaultStore: imgStore}
 		err := test.WriteImageToFileSystem(CreateDefaultVulnerableImage(), "zot-test", "0.0.1", storageCtlr)
@@ -1154,7 +1157,7 @@ func FuzzRunGCRepo(f *testing.F) {
 			UseRelPaths: true,
 		}, *log)
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, *log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, *log, metrics, nil, cacheDriver)
 
 		if err := imgStore.RunGCRepo(data); err != nil {
 			t.Error(err)
@@ -1194,10 +1197,10 @@ func TestDedupeLinks(t *testing.T) {
 
 			if testCase.dedupe {
 				imgStore = local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-					storageConstants.DefaultUntaggedImgeRetentionDelay, testCase.dedupe, true, log, metrics, nil, cacheDriver)
+					storageConstants.DefaultUntaggedImgeRetentionDelay, testCase.dedupe, true, false, log, metrics, nil, cacheDriver)
 			} else {
 				imgStore = local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-					storageConstants.DefaultUntaggedImgeRetentionDelay, testCase.dedupe, true, log, metrics, nil, nil)
+					storageConstants.DefaultUntaggedImgeRetentionDelay, testCase.dedupe, true, false, log, metrics, nil, nil)
 			}
 
 			// manifest1
@@ -1347,7 +1350,7 @@ func TestDedupeLinks(t *testing.T) {
 				Convey("test RunDedupeForDigest directly, trigger stat error on original blob", func() {
 					// rebuild with dedupe true
 					imgStore := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-						storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+						storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 					duplicateBlobs := []string{
 						path.Join(dir, "dedupe1", "blobs", "sha256", blobDigest1),
@@ -1367,7 +1370,7 @@ func TestDedupeLinks(t *testing.T) {
 						taskScheduler, cancel := runAndGetScheduler()
 						// rebuild with dedupe true
 						imgStore := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 						imgStore.RunDedupeBlobs(time.Duration(0), taskScheduler)
 						sleepValue := i * 50
@@ -1380,7 +1383,7 @@ func TestDedupeLinks(t *testing.T) {
 
 					// rebuild with dedupe true
 					imgStore := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-						storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+						storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 					imgStore.RunDedupeBlobs(time.Duration(0), taskScheduler)
 
 					// wait until rebuild finishes
@@ -1400,7 +1403,7 @@ func TestDedupeLinks(t *testing.T) {
 					taskScheduler, cancel := runAndGetScheduler()
 
 					imgStore := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
-						storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, nil)
+						storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, nil)
 
 					// rebuild with dedupe true
 					imgStore.RunDedupeBlobs(time.Duration(0), taskScheduler)
@@ -1424,7 +1427,7 @@ func TestDedupeLinks(t *testing.T) {
 
 					imgStore := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
 						storageConstants.DefaultUntaggedImgeRetentionDelay,
-						true, true, log, metrics, nil, &mocks.CacheMock{
+						true, true, false, log, metrics, nil, &mocks.CacheMock{
 							HasBlobFn: func(digest godigest.Digest, path string) bool {
 								return false
 							},
@@ -1454,7 +1457,7 @@ func TestDedupeLinks(t *testing.T) {
 
 					imgStore := local.NewImageStore(dir, false, false, storageConstants.DefaultGCDelay,
 						storageConstants.DefaultUntaggedImgeRetentionDelay,
-						true, true, log, metrics, nil, &mocks.CacheMock{
+						true, true, false, log, metrics, nil, &mocks.CacheMock{
 							HasBlobFn: func(digest godigest.Digest, path string) bool {
 								return false
 							},
@@ -1550,7 +1553,7 @@ func TestDedupe(t *testing.T) {
 			}, log)
 
 			il := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 			So(il.DedupeBlob("", "", ""), ShouldNotBeNil)
 		})
@@ -1572,7 +1575,7 @@ func TestNegativeCases(t *testing.T) {
 
 		So(local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
 			storageConstants.DefaultUntaggedImgeRetentionDelay, true,
-			true, log, metrics, nil, cacheDriver), ShouldNotBeNil)
+			true, false, log, metrics, nil, cacheDriver), ShouldNotBeNil)
 		if os.Geteuid() != 0 {
 			cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
 				RootDir:     "/deadBEEF",
@@ -1581,7 +1584,7 @@ func TestNegativeCases(t *testing.T) {
 			}, log)
 			So(local.NewImageStore("/deadBEEF", true, true, storageConstants.DefaultGCDelay,
 				storageConstants.DefaultUntaggedImgeRetentionDelay, true,
-				true, log, metrics, nil, cacheDriver), ShouldBeNil)
+				true, false, log, metrics, nil, cacheDriver), ShouldBeNil)
 		}
 	})
 
@@ -1597,7 +1600,7 @@ func TestNegativeCases(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		err := os.Chmod(dir, 0o000) // remove all perms
 		if err != nil {
@@ -1648,7 +1651,7 @@ func TestNegativeCases(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		So(imgStore, ShouldNotBeNil)
 		So(imgStore.InitRepo("test"), ShouldBeNil)
@@ -1761,7 +1764,7 @@ func TestNegativeCases(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		So(imgStore, ShouldNotBeNil)
 		So(imgStore.InitRepo("test"), ShouldBeNil)
@@ -1787,7 +1790,7 @@ func TestNegativeCases(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		So(imgStore, ShouldNotBeNil)
 		So(imgStore.InitRepo("test"), ShouldBeNil)
@@ -1835,7 +1838,7 @@ func TestNegativeCases(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		So(imgStore, ShouldNotBeNil)
 		So(imgStore.InitRepo("test"), ShouldBeNil)
@@ -2007,7 +2010,7 @@ func TestInjectWriteFile(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, false, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, false, false, log, metrics, nil, cacheDriver)
 
 		Convey("Failure path not reached", func() {
 			err := imgStore.InitRepo("repo1")
@@ -2034,7 +2037,7 @@ func TestGarbageCollectForImageStore(t *testing.T) {
 			}, log)
 
 			imgStore := local.NewImageStore(dir, true, true, 1*time.Second, storageConstants.DefaultUntaggedImgeRetentionDelay,
-				true, true, log, metrics, nil, cacheDriver)
+				true, true, false, log, metrics, nil, cacheDriver)
 			repoName := "gc-all-repos-short"
 
 			image := CreateDefaultVulnerableImage()
@@ -2074,7 +2077,7 @@ func TestGarbageCollectForImageStore(t *testing.T) {
 			}, log)
 
 			imgStore := local.NewImageStore(dir, true, true, 1*time.Second, storageConstants.DefaultUntaggedImgeRetentionDelay,
-				true, true, log, metrics, nil, cacheDriver)
+				true, true, false, log, metrics, nil, cacheDriver)
 			repoName := "gc-all-repos-short"
 
 			image := CreateDefaultVulnerableImage()
@@ -2110,7 +2113,7 @@ func TestGarbageCollectForImageStore(t *testing.T) {
 				UseRelPaths: true,
 			}, log)
 			imgStore := local.NewImageStore(dir, true, true, 1*time.Second, storageConstants.DefaultUntaggedImgeRetentionDelay,
-				true, true, log, metrics, nil, cacheDriver)
+				true, true, false, log, metrics, nil, cacheDriver)
 			repoName := "gc-sig"
 
 			storeController := storage.StoreController{DefaultStore: imgStore}
@@ -2171,7 +2174,7 @@ func TestGarbageCollectImageUnknownManifest(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, 1*time.Second,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		storeController := storage.StoreController{
 			DefaultStore: imgStore,
@@ -2344,7 +2347,7 @@ func TestGarbageCollectErrors(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, 500*time.Millisecond,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 		repoName := "gc-index"
 
 		// create a blob/layer
@@ -2581,7 +2584,7 @@ func TestInitRepo(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		err := os.Mkdir(path.Join(dir, "test-dir"), 0o000)
 		So(err, ShouldBeNil)
@@ -2591,6 +2594,165 @@ func TestInitRepo(t *testing.T) {
 	})
 }
 
+// repoLocker locks the repos for the other zot instances sharing the storage, unless it returns an error.
+type repoLocker struct {
+	lockErr  error
+	checkErr error
+}
+
+func (locker *repoLocker) LockRepo(repo string) error {
+	return locker.lockErr
+}
+
+func (locker *repoLocker) UnlockRepo(repo string) {}
+
+func (locker *repoLocker) CheckRepo(repo string) error {
+	return locker.checkErr
+}
+
+func (locker *repoLocker) LockDedupe() error {
+	return locker.lockErr
+}
+
+func (locker *repoLocker) UnlockDedupe() {}
+
+func TestRepoLocks(t *testing.T) {
+	Convey("Lock a repository without blocking the others", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, nil)
+
+		So(imgStore.InitRepo("repo1"), ShouldBeNil)
+		So(imgStore.InitRepo("repo2"), ShouldBeNil)
+
+		var lockLatency time.Time
+
+		So(imgStore.LockRepo("repo1", &lockLatency), ShouldBeNil)
+
+		// the other repositories can be read and written
+		_, err := imgStore.GetImageTags("repo2")
+		So(err, ShouldBeNil)
+
+		_, _, err = imgStore.FullBlobUpload("repo2", bytes.NewReader([]byte("blob")),
+			godigest.FromBytes([]byte("blob")))
+		So(err, ShouldBeNil)
+
+		// the locked repository waits
+		done := make(chan struct{})
+
+		go func() {
+			_, _ = imgStore.GetImageTags("repo1")
+
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("repo1 read while locked")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		imgStore.UnlockRepo("repo1", &lockLatency)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("repo1 not read after unlock")
+		}
+	})
+
+	Convey("Lock the whole store", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, nil)
+
+		So(imgStore.InitRepo("repo1"), ShouldBeNil)
+
+		var lockLatency time.Time
+
+		imgStore.Lock(&lockLatency)
+
+		done := make(chan struct{})
+
+		go func() {
+			_, _ = imgStore.GetImageTags("repo1")
+
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("repo1 read while the store is locked")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		imgStore.Unlock(&lockLatency)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("repo1 not read after unlock")
+		}
+	})
+
+	Convey("Deny the writes if the other instances sharing the storage can't be locked", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, nil)
+
+		image := CreateRandomImage()
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		So(test.WriteImageToFileSystem(image, "repo1", "1.0", storeController), ShouldBeNil)
+
+		locker := &repoLocker{lockErr: zerr.ErrClusterLeaseUnavailable}
+		imgStore.SetRepoLocker(locker)
+
+		_, _, err := imgStore.FullBlobUpload("repo1", bytes.NewReader([]byte("blob")),
+			godigest.FromBytes([]byte("blob")))
+		So(errors.Is(err, zerr.ErrClusterLeaseUnavailable), ShouldBeTrue)
+
+		err = imgStore.DeleteImageManifest("repo1", "1.0", false)
+		So(errors.Is(err, zerr.ErrClusterLeaseUnavailable), ShouldBeTrue)
+
+		// the repo isn't left locked
+		_, err = imgStore.GetImageTags("repo1")
+		So(err, ShouldBeNil)
+
+		// the writes in progress are aborted if the lock is lost
+		locker.lockErr = nil
+		locker.checkErr = zerr.ErrClusterLeaseLost
+
+		_, _, err = imgStore.PutImageManifest("repo1", "2.0", ispec.MediaTypeImageManifest,
+			image.ManifestDescriptor.Data)
+		So(errors.Is(err, zerr.ErrClusterLeaseLost), ShouldBeTrue)
+
+		err = imgStore.DeleteImageManifest("repo1", "1.0", false)
+		So(errors.Is(err, zerr.ErrClusterLeaseLost), ShouldBeTrue)
+
+		tags, err := imgStore.GetImageTags("repo1")
+		So(err, ShouldBeNil)
+		So(tags, ShouldResemble, []string{"1.0"})
+
+		locker.checkErr = nil
+
+		err = imgStore.DeleteImageManifest("repo1", "1.0", false)
+		So(err, ShouldBeNil)
+	})
+}
+
 func TestValidateRepo(t *testing.T) {
 	Convey("Get error when unable to read directory", t, func() {
 		dir := t.TempDir()
@@ -2604,7 +2766,7 @@ func TestValidateRepo(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		err := os.Mkdir(path.Join(dir, "test-dir"), 0o000)
 		So(err, ShouldBeNil)
@@ -2625,7 +2787,7 @@ func TestValidateRepo(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		_, err := imgStore.ValidateRepo(".")
 		So(err, ShouldNotBeNil)
@@ -2671,7 +2833,7 @@ func TestGetRepositories(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver,
 		)
 
 		// Create valid directory with permissions
@@ -2768,7 +2930,7 @@ func TestGetRepositories(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver,
 		)
 
 		// Root dir does not contain repos
@@ -2817,7 +2979,7 @@ func TestGetRepositories(t *testing.T) {
 
 		imgStore := local.NewImageStore(rootDir, true, true, storageConstants.DefaultGCDelay,
 			storageConstants.DefaultUntaggedImgeRetentionDelay,
-			true, true, log, metrics, nil, cacheDriver,
+			true, true, false, log, metrics, nil, cacheDriver,
 		)
 
 		// Root dir does not contain repos
@@ -2861,7 +3023,7 @@ func TestGetNextRepository(t *testing.T) {
 	}, log)
 
 	imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver,
+		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver,
 	)
 	firstRepoName := "repo1"
 	secondRepoName := "repo2"
@@ -2916,7 +3078,7 @@ func TestPutBlobChunkStreamed(t *testing.T) {
 		}, log)
 
 		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 		uuid, err := imgStore.NewBlobUpload("test")
 		So(err, ShouldBeNil)
@@ -2931,6 +3093,712 @@ func TestPutBlobChunkStreamed(t *testing.T) {
 	})
 }
 
+func TestExpireBlobUploads(t *testing.T) {
+	Convey("Stale blob uploads are expired", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
+
+		// the repo doesn't have uploads yet
+		expired, err := imgStore.ExpireBlobUploads(repoName, time.Hour)
+		So(err, ShouldBeNil)
+		So(expired, ShouldEqual, 0)
+
+		stale, err := imgStore.NewBlobUpload(repoName)
+		So(err, ShouldBeNil)
+
+		_, err = imgStore.PutBlobChunkStreamed(repoName, stale, bytes.NewReader([]byte("stale")))
+		So(err, ShouldBeNil)
+
+		lastWrite := time.Now().Add(-2 * time.Hour)
+		err = os.Chtimes(imgStore.BlobUploadPath(repoName, stale), lastWrite, lastWrite)
+		So(err, ShouldBeNil)
+
+		active, err := imgStore.NewBlobUpload(repoName)
+		So(err, ShouldBeNil)
+
+		expired, err = imgStore.ExpireBlobUploads(repoName, time.Hour)
+		So(err, ShouldBeNil)
+		So(expired, ShouldEqual, 1)
+
+		_, err = imgStore.GetBlobUpload(repoName, stale)
+		So(err, ShouldEqual, zerr.ErrUploadNotFound)
+
+		_, err = imgStore.GetBlobUpload(repoName, active)
+		So(err, ShouldBeNil)
+
+		expired, err = imgStore.ExpireBlobUploads(repoName, 0)
+		So(err, ShouldBeNil)
+		So(expired, ShouldEqual, 1)
+
+		uploads, err := os.ReadDir(path.Join(dir, repoName, storageConstants.BlobUploadDir))
+		So(err, ShouldBeNil)
+		So(uploads, ShouldBeEmpty)
+	})
+}
+
+func TestGCDryRun(t *testing.T) {
+	Convey("A GC dry run reports what would be removed without removing it", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, 1*time.Second, 1*time.Second, true, true, false, log,
+			metrics, nil, cacheDriver)
+
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		tagged := CreateRandomImage()
+		err := test.WriteImageToFileSystem(tagged, repoName, tag, storeController)
+		So(err, ShouldBeNil)
+
+		untagged := CreateRandomImage()
+		err = test.WriteImageToFileSystem(untagged, repoName, untagged.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		orphan := []byte("orphan blob")
+		orphanDigest := godigest.FromBytes(orphan)
+		_, _, err = imgStore.FullBlobUpload(repoName, bytes.NewReader(orphan), orphanDigest)
+		So(err, ShouldBeNil)
+
+		// sleep so the untagged manifest and the orphan blob can be GC'ed
+		time.Sleep(1 * time.Second)
+
+		report, err := imgStore.RunGCRepoReport(repoName, true)
+		So(err, ShouldBeNil)
+		So(report.Repo, ShouldEqual, repoName)
+		So(report.RepoRemoved, ShouldBeFalse)
+		So(report.Manifests, ShouldResemble, []storageTypes.GCReportItem{
+			{Digest: untagged.Digest(), Size: untagged.Descriptor().Size},
+		})
+
+		blobs := map[godigest.Digest]int64{}
+		for _, blob := range report.Blobs {
+			blobs[blob.Digest] = blob.Size
+		}
+
+		So(blobs, ShouldContainKey, orphanDigest)
+		So(blobs[orphanDigest], ShouldEqual, len(orphan))
+		So(blobs, ShouldContainKey, untagged.ConfigDescriptor.Digest)
+		So(blobs, ShouldNotContainKey, tagged.ConfigDescriptor.Digest)
+
+		var bytesFreed int64
+		for _, item := range append(report.Manifests, report.Blobs...) {
+			bytesFreed += item.Size
+		}
+
+		So(report.BytesFreed, ShouldEqual, bytesFreed)
+
+		// nothing was removed
+		_, _, _, err = imgStore.GetImageManifest(repoName, untagged.DigestStr())
+		So(err, ShouldBeNil)
+
+		ok, _, err := imgStore.CheckBlob(repoName, orphanDigest)
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeTrue)
+
+		// the GC removes what the dry run reported
+		gcReport, err := imgStore.RunGCRepoReport(repoName, false)
+		So(err, ShouldBeNil)
+		So(gcReport, ShouldResemble, report)
+
+		_, _, _, err = imgStore.GetImageManifest(repoName, untagged.DigestStr())
+		So(err, ShouldNotBeNil)
+
+		_, _, _, err = imgStore.GetImageManifest(repoName, tag)
+		So(err, ShouldBeNil)
+
+		report, err = imgStore.RunGCRepoReport(repoName, true)
+		So(err, ShouldBeNil)
+		So(report.IsEmpty(), ShouldBeTrue)
+
+		Convey("The repos without images are reported as removed", func() {
+			err := imgStore.DeleteImageManifest(repoName, tag, false)
+			So(err, ShouldBeNil)
+
+			time.Sleep(1 * time.Second)
+
+			report, err := imgStore.RunGCRepoReport(repoName, true)
+			So(err, ShouldBeNil)
+			So(report.RepoRemoved, ShouldBeTrue)
+			So(report.Blobs, ShouldNotBeEmpty)
+
+			So(imgStore.DirExists(path.Join(dir, repoName)), ShouldBeTrue)
+		})
+	})
+
+	Convey("The read-only stores aren't garbage collected", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(dir, true, true, 1*time.Second, 1*time.Second, false, true, true, log,
+			metrics, nil, nil)
+
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		untagged := CreateRandomImage()
+		err := test.WriteImageToFileSystem(untagged, repoName, untagged.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		time.Sleep(1 * time.Second)
+
+		report, err := imgStore.RunGCRepoReport(repoName, false)
+		So(err, ShouldBeNil)
+		So(report.IsEmpty(), ShouldBeTrue)
+
+		_, _, _, err = imgStore.GetImageManifest(repoName, untagged.DigestStr())
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestIncrementalGC(t *testing.T) {
+	Convey("The GC only checks the unreferenced blobs of the tracked repos", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, 1*time.Second, 1*time.Second, true, true, false, log,
+			metrics, nil, cacheDriver)
+
+		counter, err := refcount.NewCounter(dir, log)
+		So(err, ShouldBeNil)
+
+		defer counter.Close()
+
+		storeController := storage.StoreController{DefaultStore: imgStore}
+		counter.SetReferenceCounter(storeController)
+
+		tagged := CreateRandomImage()
+		err = test.WriteImageToFileSystem(tagged, repoName, tag, storeController)
+		So(err, ShouldBeNil)
+
+		untagged := CreateRandomImage()
+		err = test.WriteImageToFileSystem(untagged, repoName, untagged.DigestStr(), storeController)
+		So(err, ShouldBeNil)
+
+		tracked, err := counter.IsTracked(repoName)
+		So(err, ShouldBeNil)
+		So(tracked, ShouldBeFalse)
+
+		time.Sleep(1 * time.Second)
+
+		// the first GC reads all the manifests and tracks the repo
+		report, err := imgStore.RunGCRepoReport(repoName, false)
+		So(err, ShouldBeNil)
+		So(report.Manifests, ShouldHaveLength, 1)
+		So(report.Manifests[0].Digest, ShouldEqual, untagged.Digest())
+
+		tracked, err = counter.IsTracked(repoName)
+		So(err, ShouldBeNil)
+		So(tracked, ShouldBeTrue)
+
+		unreferenced, err := counter.Unreferenced(repoName)
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldResemble, []godigest.Digest{tagged.Digest()})
+
+		// the pushes and the deletes are counted
+		deleted := CreateRandomImage()
+		err = test.WriteImageToFileSystem(deleted, repoName, "deleted", storeController)
+		So(err, ShouldBeNil)
+
+		err = imgStore.DeleteImageManifest(repoName, "deleted", false)
+		So(err, ShouldBeNil)
+
+		orphan := []byte("orphan blob")
+		orphanDigest := godigest.FromBytes(orphan)
+		_, _, err = imgStore.FullBlobUpload(repoName, bytes.NewReader(orphan), orphanDigest)
+		So(err, ShouldBeNil)
+
+		unreferenced, err = counter.Unreferenced(repoName)
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldContain, orphanDigest)
+		So(unreferenced, ShouldContain, deleted.ConfigDescriptor.Digest)
+		So(unreferenced, ShouldNotContain, tagged.ConfigDescriptor.Digest)
+
+		time.Sleep(1 * time.Second)
+
+		report, err = imgStore.RunGCRepoReport(repoName, false)
+		So(err, ShouldBeNil)
+		So(report.Manifests, ShouldBeEmpty)
+
+		blobs := []godigest.Digest{}
+		for _, blob := range report.Blobs {
+			blobs = append(blobs, blob.Digest)
+		}
+
+		So(blobs, ShouldContain, orphanDigest)
+		So(blobs, ShouldContain, deleted.ConfigDescriptor.Digest)
+
+		for _, layer := range deleted.Manifest.Layers {
+			So(blobs, ShouldContain, layer.Digest)
+		}
+
+		ok, _, err := imgStore.CheckBlob(repoName, orphanDigest)
+		So(err, ShouldNotBeNil)
+		So(ok, ShouldBeFalse)
+
+		_, _, _, err = imgStore.GetImageManifest(repoName, tag)
+		So(err, ShouldBeNil)
+
+		for _, layer := range tagged.Manifest.Layers {
+			ok, _, err := imgStore.CheckBlob(repoName, layer.Digest)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+		}
+
+		unreferenced, err = counter.Unreferenced(repoName)
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldResemble, []godigest.Digest{tagged.Digest()})
+
+		Convey("The images whose references weren't counted aren't collected", func() {
+			image := CreateRandomImage()
+			err := test.WriteImageToFileSystem(image, repoName, "uncounted", storeController)
+			So(err, ShouldBeNil)
+
+			// as if zot stopped after writing the index, before counting the references of the manifest
+			err = counter.RemoveBlob(repoName, image.Digest())
+			So(err, ShouldBeNil)
+
+			counted, err := counter.HasManifest(repoName, image.Digest())
+			So(err, ShouldBeNil)
+			So(counted, ShouldBeFalse)
+
+			time.Sleep(1 * time.Second)
+
+			report, err := imgStore.RunGCRepoReport(repoName, false)
+			So(err, ShouldBeNil)
+			So(report.Blobs, ShouldBeEmpty)
+
+			for _, layer := range image.Manifest.Layers {
+				ok, _, err := imgStore.CheckBlob(repoName, layer.Digest)
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+			}
+
+			counted, err = counter.HasManifest(repoName, image.Digest())
+			So(err, ShouldBeNil)
+			So(counted, ShouldBeTrue)
+		})
+
+		Convey("The repos without images are removed and forgotten", func() {
+			err := imgStore.DeleteImageManifest(repoName, tag, false)
+			So(err, ShouldBeNil)
+
+			time.Sleep(1 * time.Second)
+
+			report, err := imgStore.RunGCRepoReport(repoName, false)
+			So(err, ShouldBeNil)
+			So(report.RepoRemoved, ShouldBeTrue)
+
+			So(imgStore.DirExists(path.Join(dir, repoName)), ShouldBeFalse)
+
+			tracked, err := counter.IsTracked(repoName)
+			So(err, ShouldBeNil)
+			So(tracked, ShouldBeFalse)
+		})
+
+		Convey("The deleted repos are forgotten", func() {
+			err := imgStore.DeleteImageManifest(repoName, tag, false)
+			So(err, ShouldBeNil)
+
+			err = imgStore.DeleteRepo(repoName)
+			So(err, ShouldBeNil)
+
+			tracked, err := counter.IsTracked(repoName)
+			So(err, ShouldBeNil)
+			So(tracked, ShouldBeFalse)
+		})
+	})
+}
+
+func TestGCDedupedBlobs(t *testing.T) {
+	Convey("The GC repairs the deduped blobs of the other repos", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
+
+		content := []byte("deduped blob")
+		digest := godigest.FromBytes(content)
+
+		for _, repo := range []string{"original", "copy"} {
+			_, _, err := imgStore.FullBlobUpload(repo, bytes.NewReader(content), digest)
+			So(err, ShouldBeNil)
+		}
+
+		originalBlob := imgStore.BlobPath("original", digest)
+		copyBlob := imgStore.BlobPath("copy", digest)
+
+		// the cache has the paths relative to the root directory
+		originalRecord := strings.TrimPrefix(originalBlob, dir+"/")
+		copyRecord := strings.TrimPrefix(copyBlob, dir+"/")
+
+		// nothing to repair
+		err := imgStore.RunGCDedupedBlobs()
+		So(err, ShouldBeNil)
+
+		blobPaths, err := cacheDriver.GetAllBlobs(digest)
+		So(err, ShouldBeNil)
+		So(blobPaths, ShouldResemble, []string{originalRecord, copyRecord})
+
+		Convey("The records of the removed repos are removed", func() {
+			err := os.RemoveAll(path.Join(dir, "original"))
+			So(err, ShouldBeNil)
+
+			err = imgStore.RunGCDedupedBlobs()
+			So(err, ShouldBeNil)
+
+			blobPaths, err := cacheDriver.GetAllBlobs(digest)
+			So(err, ShouldBeNil)
+			So(blobPaths, ShouldResemble, []string{copyRecord})
+
+			blob, err := imgStore.GetBlobContent("copy", digest)
+			So(err, ShouldBeNil)
+			So(blob, ShouldResemble, content)
+		})
+
+		Convey("The content is moved back to the original blob", func() {
+			// the copy has the content, like a moved s3 blob
+			err := os.Remove(originalBlob)
+			So(err, ShouldBeNil)
+
+			err = os.WriteFile(originalBlob, []byte{}, 0o600)
+			So(err, ShouldBeNil)
+
+			err = imgStore.RunGCDedupedBlobs()
+			So(err, ShouldBeNil)
+
+			blob, err := os.ReadFile(originalBlob)
+			So(err, ShouldBeNil)
+			So(blob, ShouldResemble, content)
+
+			blob, err = os.ReadFile(copyBlob)
+			So(err, ShouldBeNil)
+			So(blob, ShouldBeEmpty)
+
+			blobPaths, err := cacheDriver.GetAllBlobs(digest)
+			So(err, ShouldBeNil)
+			So(blobPaths, ShouldResemble, []string{originalRecord, copyRecord})
+		})
+
+		Convey("The blobs whose content is lost are removed", func() {
+			for _, blobPath := range []string{originalBlob, copyBlob} {
+				err := os.Remove(blobPath)
+				So(err, ShouldBeNil)
+
+				err = os.WriteFile(blobPath, []byte{}, 0o600)
+				So(err, ShouldBeNil)
+			}
+
+			err = imgStore.RunGCDedupedBlobs()
+			So(err, ShouldBeNil)
+
+			_, err = cacheDriver.GetBlob(digest)
+			So(err, ShouldEqual, zerr.ErrCacheMiss)
+
+			for _, repo := range []string{"original", "copy"} {
+				ok, _, err := imgStore.CheckBlob(repo, digest)
+				So(err, ShouldNotBeNil)
+				So(ok, ShouldBeFalse)
+			}
+
+			// the blob can be pushed again
+			_, _, err = imgStore.FullBlobUpload("copy", bytes.NewReader(content), digest)
+			So(err, ShouldBeNil)
+
+			blob, err := imgStore.GetBlobContent("copy", digest)
+			So(err, ShouldBeNil)
+			So(blob, ShouldResemble, content)
+		})
+
+		Convey("The errors of the cache are returned", func() {
+			imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil,
+				&mocks.CacheMock{
+					GetAllDigestsFn: func() ([]godigest.Digest, error) {
+						return nil, errCache
+					},
+				})
+
+			err := imgStore.RunGCDedupedBlobs()
+			So(err, ShouldEqual, errCache)
+
+			imgStore = local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil,
+				&mocks.CacheMock{
+					GetAllDigestsFn: func() ([]godigest.Digest, error) {
+						return []godigest.Digest{digest}, nil
+					},
+					GetAllBlobsFn: func(digest godigest.Digest) ([]string, error) {
+						return nil, errCache
+					},
+				})
+
+			err = imgStore.RunGCDedupedBlobs()
+			So(err, ShouldEqual, errCache)
+		})
+	})
+}
+
+func TestFullBlobUploadStreamed(t *testing.T) {
+	Convey("Stream a full blob upload", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
+
+		content := make([]byte, 8*1024*1024)
+		_, err := rand.Read(content)
+		So(err, ShouldBeNil)
+
+		digest := godigest.FromBytes(content)
+
+		Convey("The blob is written and digested", func() {
+			// hide the bytes.Reader so the blob can only be read sequentially
+			body := struct{ io.Reader }{bytes.NewReader(content)}
+
+			_, size, err := imgStore.FullBlobUpload(repoName, body, digest)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+
+			blob, err := imgStore.GetBlobContent(repoName, digest)
+			So(err, ShouldBeNil)
+			So(bytes.Equal(blob, content), ShouldBeTrue)
+		})
+
+		Convey("A failing body cancels the upload", func() {
+			body := io.MultiReader(bytes.NewReader(content[:1024*1024]), iotest.ErrReader(errCache))
+
+			_, _, err := imgStore.FullBlobUpload(repoName, body, digest)
+			So(err, ShouldEqual, errCache)
+
+			uploads, err := os.ReadDir(path.Join(dir, repoName, storageConstants.BlobUploadDir))
+			So(err, ShouldBeNil)
+			So(uploads, ShouldBeEmpty)
+
+			ok, _, err := imgStore.CheckBlob(repoName, digest)
+			So(err, ShouldNotBeNil)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("A wrong digest is rejected", func() {
+			_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader(content), godigest.FromString("wrong"))
+			So(err, ShouldEqual, zerr.ErrBadBlobDigest)
+		})
+	})
+}
+
+func TestSha512Blobs(t *testing.T) {
+	Convey("Blobs addressed by sha512 digests", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, 1*time.Second,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
+
+		content := []byte("sha512 addressed blob")
+		digest := godigest.SHA512.FromBytes(content)
+
+		_, size, err := imgStore.FullBlobUpload(repoName, bytes.NewReader(content), digest)
+		So(err, ShouldBeNil)
+		So(size, ShouldEqual, len(content))
+
+		_, err = os.Stat(path.Join(dir, repoName, "blobs", "sha512", digest.Encoded()))
+		So(err, ShouldBeNil)
+
+		blob, err := imgStore.GetBlobContent(repoName, digest)
+		So(err, ShouldBeNil)
+		So(blob, ShouldResemble, content)
+
+		Convey("Chunked uploads are digested with sha512", func() {
+			upload, err := imgStore.NewBlobUpload("dedupe")
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunkStreamed("dedupe", upload, bytes.NewReader(content))
+			So(err, ShouldBeNil)
+
+			err = imgStore.FinishBlobUpload("dedupe", upload, bytes.NewReader([]byte{}), digest)
+			So(err, ShouldBeNil)
+
+			// the blob is deduped with the first one
+			fi1, err := os.Stat(path.Join(dir, repoName, "blobs", "sha512", digest.Encoded()))
+			So(err, ShouldBeNil)
+			fi2, err := os.Stat(path.Join(dir, "dedupe", "blobs", "sha512", digest.Encoded()))
+			So(err, ShouldBeNil)
+			So(os.SameFile(fi1, fi2), ShouldBeTrue)
+
+			upload, err = imgStore.NewBlobUpload("dedupe")
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunkStreamed("dedupe", upload, bytes.NewReader(content))
+			So(err, ShouldBeNil)
+
+			err = imgStore.FinishBlobUpload("dedupe", upload, bytes.NewReader([]byte{}),
+				godigest.SHA512.FromString("wrong"))
+			So(err, ShouldEqual, zerr.ErrBadBlobDigest)
+		})
+
+		Convey("Blobs of all the algorithms are listed", func() {
+			sha256Content := []byte("sha256 addressed blob")
+			sha256Digest := godigest.FromBytes(sha256Content)
+
+			_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader(sha256Content), sha256Digest)
+			So(err, ShouldBeNil)
+
+			blobs, err := imgStore.GetAllBlobs(repoName)
+			So(err, ShouldBeNil)
+			So(blobs, ShouldContain, digest)
+			So(blobs, ShouldContain, sha256Digest)
+			So(blobs, ShouldHaveLength, 2)
+
+			nextDigest, blobPaths, err := imgStore.GetNextDigestWithBlobPaths([]godigest.Digest{sha256Digest})
+			So(err, ShouldBeNil)
+			So(nextDigest, ShouldEqual, digest)
+			So(blobPaths, ShouldResemble, []string{path.Join(dir, repoName, "blobs", "sha512", digest.Encoded())})
+		})
+
+		Convey("Unreferenced blobs are garbage collected", func() {
+			time.Sleep(1 * time.Second)
+
+			err := imgStore.RunGCRepo(repoName)
+			So(err, ShouldBeNil)
+
+			ok, _, err := imgStore.CheckBlob(repoName, digest)
+			So(err, ShouldNotBeNil)
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func TestChunkDedupe(t *testing.T) {
+	Convey("Large blobs are stored in chunks", t, func() {
+		dir := t.TempDir()
+
+		log := log.Logger{Logger: zerolog.New(os.Stdout)}
+		metrics := monitoring.NewMetricsServer(false, log)
+		cacheDriver, _ := storage.Create("boltdb", cache.BoltDBDriverParameters{
+			RootDir:     dir,
+			Name:        "cache",
+			UseRelPaths: true,
+		}, log)
+
+		imgStore := local.NewImageStore(dir, true, true, 1*time.Second,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
+		imgStore.SetChunkDedupe(64*1024, 4096)
+
+		content := make([]byte, 256*1024)
+		_, err := rand.Read(content)
+		So(err, ShouldBeNil)
+
+		digest := godigest.FromBytes(content)
+
+		_, size, err := imgStore.FullBlobUpload(repoName, bytes.NewReader(content), digest)
+		So(err, ShouldBeNil)
+		So(size, ShouldEqual, len(content))
+
+		// only the recipe of the blob is stored in the repo
+		fileInfo, err := os.Stat(path.Join(dir, repoName, "blobs", "sha256", digest.Encoded()))
+		So(err, ShouldBeNil)
+		So(fileInfo.Size(), ShouldBeLessThan, len(content))
+
+		_, err = os.Stat(path.Join(dir, chunking.ChunksDir))
+		So(err, ShouldBeNil)
+
+		ok, blobSize, err := imgStore.CheckBlob(repoName, digest)
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeTrue)
+		So(blobSize, ShouldEqual, len(content))
+
+		reader, blobSize, err := imgStore.GetBlob(repoName, digest, ispec.MediaTypeImageLayer)
+		So(err, ShouldBeNil)
+		So(blobSize, ShouldEqual, len(content))
+
+		buf, err := io.ReadAll(reader)
+		So(err, ShouldBeNil)
+		So(buf, ShouldResemble, content)
+		reader.Close()
+
+		reader, _, _, err = imgStore.GetBlobPartial(repoName, digest, ispec.MediaTypeImageLayer, 1000, 1999)
+		So(err, ShouldBeNil)
+
+		buf, err = io.ReadAll(reader)
+		So(err, ShouldBeNil)
+		So(buf, ShouldResemble, content[1000:2000])
+		reader.Close()
+
+		Convey("The same content with another digest algorithm shares the chunks", func() {
+			sha512Digest := godigest.SHA512.FromBytes(content)
+
+			_, _, err := imgStore.FullBlobUpload(repoName, bytes.NewReader(content), sha512Digest)
+			So(err, ShouldBeNil)
+
+			blob, err := imgStore.GetBlobContent(repoName, sha512Digest)
+			So(err, ShouldBeNil)
+			So(blob, ShouldResemble, content)
+		})
+
+		Convey("The chunks of the removed blobs are garbage collected", func() {
+			err := imgStore.DeleteBlob(repoName, digest)
+			So(err, ShouldBeNil)
+
+			time.Sleep(1 * time.Second)
+
+			err = imgStore.RunGCDedupedBlobs()
+			So(err, ShouldBeNil)
+
+			entries, err := os.ReadDir(path.Join(dir, chunking.ChunksDir, "sha256"))
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+		})
+	})
+}
+
 func TestPullRange(t *testing.T) {
 	Convey("Repo layout", t, func(c C) {
 		dir := t.TempDir()
@@ -2946,7 +3814,7 @@ func TestPullRange(t *testing.T) {
 			}, log)
 
 			imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+				storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 			repoName := "pull-range"
 
 			upload, err := imgStore.NewBlobUpload(repoName)
@@ -2995,7 +3863,7 @@ func TestStorageDriverErr(t *testing.T) {
 	}, log)
 
 	imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 	Convey("Init repo", t, func() {
 		err := imgStore.InitRepo(repoName)
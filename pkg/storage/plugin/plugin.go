@@ -0,0 +1,38 @@
+package plugin
+
+import (
+	"time"
+
+	"github.com/docker/distribution/registry/storage/driver"
+
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	zlog "zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage/cache"
+	common "zotregistry.io/zot/pkg/storage/common"
+	"zotregistry.io/zot/pkg/storage/imagestore"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+// NewImageStore returns a new image store backed by the storage driver of a plugin, see pkg/plugins.
+// Use the last argument to properly set a cache database, or it will default to boltDB local storage.
+func NewImageStore(rootDir string, cacheDir string, gc bool, gcReferrers bool, gcDelay time.Duration,
+	untaggedImageRetentionDelay time.Duration, dedupe, commit, readOnly bool, log zlog.Logger,
+	metrics monitoring.MetricServer, linter common.Lint, store driver.StorageDriver, cacheDriver cache.Cache,
+) storageTypes.ImageStore {
+	return imagestore.NewImageStore(
+		rootDir,
+		cacheDir,
+		gc,
+		gcReferrers,
+		gcDelay,
+		untaggedImageRetentionDelay,
+		dedupe,
+		commit,
+		readOnly,
+		log,
+		metrics,
+		linter,
+		New(store),
+		cacheDriver,
+	)
+}
@@ -0,0 +1,153 @@
+package plugin_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	"github.com/docker/distribution/registry/storage/driver/inmemory"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/plugins"
+	"zotregistry.io/zot/pkg/storage"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+const helperModeEnv = "ZOT_TEST_STORAGE_PLUGIN"
+
+var errMissingParameter = errors.New("missing parameter")
+
+// TestHelperStorageDriver is the storage driver plugin started by the tests, it's skipped when
+// the test binary isn't a plugin.
+func TestHelperStorageDriver(t *testing.T) {
+	if os.Getenv(helperModeEnv) == "" {
+		return
+	}
+
+	err := plugins.ServeStorageDriver(func(parameters map[string]interface{}) (storagedriver.StorageDriver, error) {
+		if parameters["bucket"] != "zot" {
+			return nil, errMissingParameter
+		}
+
+		return inmemory.New(), nil
+	})
+	if err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func getStorageDriverConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"name":          storageConstants.PluginStorageDriverName,
+		"rootdirectory": "/zot",
+		"bucket":        "zot",
+		"plugin": map[string]interface{}{
+			"name":         "inmemory",
+			"path":         os.Args[0],
+			"args":         []string{"-test.run=^TestHelperStorageDriver$"},
+			"env":          map[string]string{helperModeEnv: "1"},
+			"starttimeout": "10s",
+		},
+	}
+}
+
+func TestPluginImageStore(t *testing.T) {
+	log := log.NewLogger("debug", "")
+	metrics := monitoring.NewMetricsServer(false, log)
+
+	Convey("Images are stored by the storage driver of a plugin", t, func() {
+		conf := config.New()
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.RemoteCache = false
+		conf.Storage.StorageDriver = getStorageDriverConfig()
+
+		storeController, err := storage.New(conf, nil, metrics, log)
+		So(err, ShouldBeNil)
+
+		defer plugins.StopStorageDrivers()
+
+		imgStore := storeController.DefaultStore
+		So(imgStore.RootDir(), ShouldEqual, "/zot")
+
+		image := CreateRandomImage()
+
+		err = test.WriteImageToFileSystem(image, "first", "1.0", storeController)
+		So(err, ShouldBeNil)
+
+		err = test.WriteImageToFileSystem(image, "second", "1.0", storeController)
+		So(err, ShouldBeNil)
+
+		repos, err := imgStore.GetRepositories()
+		So(err, ShouldBeNil)
+		So(repos, ShouldResemble, []string{"first", "second"})
+
+		_, manifestDigest, _, err := imgStore.GetImageManifest("second", "1.0")
+		So(err, ShouldBeNil)
+		So(manifestDigest, ShouldEqual, image.ManifestDescriptor.Digest)
+
+		layerDigest := image.Manifest.Layers[0].Digest
+
+		blob, err := imgStore.GetBlobContent("second", layerDigest)
+		So(err, ShouldBeNil)
+		So(blob, ShouldResemble, image.Layers[0])
+
+		// the blobs are deduped like in the other remote storages
+		err = imgStore.DeleteImageManifest("first", "1.0", false)
+		So(err, ShouldBeNil)
+
+		err = imgStore.DeleteBlob("first", layerDigest)
+		So(err, ShouldBeNil)
+
+		blob, err = imgStore.GetBlobContent("second", layerDigest)
+		So(err, ShouldBeNil)
+		So(blob, ShouldResemble, image.Layers[0])
+
+		_, err = imgStore.GetBlobContent("first", layerDigest)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Invalid storage driver plugins", t, func() {
+		conf := config.New()
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.RemoteCache = false
+
+		// the parameters aren't accepted by the plugin
+		conf.Storage.StorageDriver = getStorageDriverConfig()
+		delete(conf.Storage.StorageDriver, "bucket")
+
+		_, err := storage.New(conf, nil, metrics, log)
+		So(err, ShouldNotBeNil)
+
+		conf.Storage.StorageDriver = getStorageDriverConfig()
+		delete(conf.Storage.StorageDriver, "plugin")
+
+		_, err = storage.New(conf, nil, metrics, log)
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+
+		conf.Storage.StorageDriver = getStorageDriverConfig()
+		conf.Storage.StorageDriver["plugin"] = map[string]interface{}{"path": os.Args[0], "starttimeout": "invalid"}
+
+		_, err = storage.New(conf, nil, metrics, log)
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+
+		conf.Storage.StorageDriver = nil
+		conf.Storage.SubPaths = map[string]config.StorageConfig{
+			"/a": {
+				RootDirectory: t.TempDir(),
+				StorageDriver: map[string]interface{}{"name": storageConstants.PluginStorageDriverName},
+			},
+		}
+
+		_, err = storage.New(conf, nil, metrics, log)
+		So(errors.Is(err, zerr.ErrBadConfig), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,372 @@
+// Package refcount keeps the count of the references to the blobs of the repos, so the garbage collection only
+// checks the blobs which aren't referenced.
+package refcount
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path"
+
+	godigest "github.com/opencontainers/go-digest"
+	"go.etcd.io/bbolt"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+)
+
+const (
+	// DBName is the name of the database in the root directory of the storage.
+	DBName = "refcount"
+
+	reposBucket        = "repos"
+	countsBucket       = "counts"
+	manifestsBucket    = "manifests"
+	unreferencedBucket = "unreferenced"
+)
+
+/*
+Counter keeps the references of the repos in a boltdb database, for each repo:
+  - the number of manifests referencing each blob
+  - the references of each manifest, removed with the manifest
+  - the blobs referenced by no manifest, the candidates of the garbage collection
+
+The manifests of the index of a repo are usually not referenced, they're kept by the garbage collection as long as
+they're in the index.
+
+The references of a repo are only counted once it's tracked, the first garbage collection of the repo reads all
+its manifests and tracks it. The counts are kept up to date by the image stores as long as no other zot instance
+writes in the storage.
+*/
+type Counter struct {
+	db  *bbolt.DB
+	log log.Logger
+}
+
+// NewCounter opens the database of the references in the root directory, shared by the image stores of the
+// subpaths as the names of their repos are unique.
+func NewCounter(rootDir string, log log.Logger) (*Counter, error) {
+	if err := os.MkdirAll(rootDir, storageConstants.DefaultDirPerms); err != nil {
+		log.Error().Err(err).Str("rootDir", rootDir).Msg("refcount: unable to create the directory of the db")
+
+		return nil, err
+	}
+
+	dbPath := path.Join(rootDir, DBName+storageConstants.DBExtensionName)
+
+	db, err := bbolt.Open(dbPath, storageConstants.DefaultFilePerms, &bbolt.Options{
+		Timeout:      storageConstants.DBCacheLockCheckTimeout,
+		FreelistType: bbolt.FreelistArrayType,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("dbPath", dbPath).Msg("refcount: unable to open the db")
+
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(reposBucket))
+
+		return err
+	}); err != nil {
+		log.Error().Err(err).Str("dbPath", dbPath).Msg("refcount: unable to create the root bucket")
+
+		db.Close()
+
+		return nil, err
+	}
+
+	return &Counter{db: db, log: log}, nil
+}
+
+// SetReferenceCounter counts the references in the image stores.
+func (counter *Counter) SetReferenceCounter(storeController storage.StoreController) {
+	if storeController.DefaultStore != nil {
+		storeController.DefaultStore.SetReferenceCounter(counter)
+	}
+
+	for _, imgStore := range storeController.SubStore {
+		imgStore.SetReferenceCounter(counter)
+	}
+}
+
+// Close closes the database.
+func (counter *Counter) Close() error {
+	return counter.db.Close()
+}
+
+// IsTracked tells if the references of the repo are counted.
+func (counter *Counter) IsTracked(repo string) (bool, error) {
+	tracked := false
+
+	err := counter.db.View(func(tx *bbolt.Tx) error {
+		tracked = getRepoBucket(tx, repo) != nil
+
+		return nil
+	})
+
+	return tracked, err
+}
+
+// Track replaces the references of the repo by those of the manifests, the blobs they don't reference are
+// unreferenced.
+func (counter *Counter) Track(repo string, manifests map[godigest.Digest][]godigest.Digest,
+	blobs []godigest.Digest,
+) error {
+	return counter.db.Update(func(tx *bbolt.Tx) error {
+		root, err := getRootBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		if root.Bucket([]byte(repo)) != nil {
+			if err := root.DeleteBucket([]byte(repo)); err != nil {
+				return err
+			}
+		}
+
+		repoBucket, err := root.CreateBucket([]byte(repo))
+		if err != nil {
+			return err
+		}
+
+		for _, name := range []string{countsBucket, manifestsBucket, unreferencedBucket} {
+			if _, err := repoBucket.CreateBucket([]byte(name)); err != nil {
+				return err
+			}
+		}
+
+		for manifest, refs := range manifests {
+			if err := addManifest(repoBucket, manifest, refs); err != nil {
+				return err
+			}
+		}
+
+		for _, digest := range blobs {
+			if err := addBlob(repoBucket, digest); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// AddManifest counts the references of the manifest, once, if the repo is tracked.
+func (counter *Counter) AddManifest(repo string, manifest godigest.Digest, refs []godigest.Digest) error {
+	return counter.db.Update(func(tx *bbolt.Tx) error {
+		repoBucket := getRepoBucket(tx, repo)
+		if repoBucket == nil {
+			return nil
+		}
+
+		return addManifest(repoBucket, manifest, refs)
+	})
+}
+
+// HasManifest tells if the references of the manifest are counted, false if the repo isn't tracked.
+func (counter *Counter) HasManifest(repo string, manifest godigest.Digest) (bool, error) {
+	counted := false
+
+	err := counter.db.View(func(tx *bbolt.Tx) error {
+		repoBucket := getRepoBucket(tx, repo)
+		if repoBucket == nil {
+			return nil
+		}
+
+		counted = repoBucket.Bucket([]byte(manifestsBucket)).Get([]byte(manifest)) != nil
+
+		return nil
+	})
+
+	return counted, err
+}
+
+// AddBlob records the blob as unreferenced if no manifest references it, if the repo is tracked.
+func (counter *Counter) AddBlob(repo string, digest godigest.Digest) error {
+	return counter.db.Update(func(tx *bbolt.Tx) error {
+		repoBucket := getRepoBucket(tx, repo)
+		if repoBucket == nil {
+			return nil
+		}
+
+		return addBlob(repoBucket, digest)
+	})
+}
+
+// RemoveBlob forgets the blob, if it's a manifest its references are removed and the blobs no other manifest
+// references become unreferenced.
+func (counter *Counter) RemoveBlob(repo string, digest godigest.Digest) error {
+	return counter.db.Update(func(tx *bbolt.Tx) error {
+		repoBucket := getRepoBucket(tx, repo)
+		if repoBucket == nil {
+			return nil
+		}
+
+		if err := repoBucket.Bucket([]byte(unreferencedBucket)).Delete([]byte(digest)); err != nil {
+			return err
+		}
+
+		manifests := repoBucket.Bucket([]byte(manifestsBucket))
+
+		buf := manifests.Get([]byte(digest))
+		if buf == nil {
+			return nil
+		}
+
+		var refs []godigest.Digest
+
+		if err := json.Unmarshal(buf, &refs); err != nil {
+			return err
+		}
+
+		counts := repoBucket.Bucket([]byte(countsBucket))
+		unreferenced := repoBucket.Bucket([]byte(unreferencedBucket))
+
+		for _, ref := range refs {
+			count := getCount(counts, ref)
+			if count > 1 {
+				if err := putCount(counts, ref, count-1); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := counts.Delete([]byte(ref)); err != nil {
+				return err
+			}
+
+			// the blob may not be in the repo, e.g. a subject which was never pushed
+			if err := unreferenced.Put([]byte(ref), []byte{}); err != nil {
+				return err
+			}
+		}
+
+		return manifests.Delete([]byte(digest))
+	})
+}
+
+// Unreferenced returns the blobs of the repo which aren't referenced, and the manifests of its index.
+func (counter *Counter) Unreferenced(repo string) ([]godigest.Digest, error) {
+	digests := []godigest.Digest{}
+
+	err := counter.db.View(func(tx *bbolt.Tx) error {
+		repoBucket := getRepoBucket(tx, repo)
+		if repoBucket == nil {
+			return zerr.ErrRepoNotFound
+		}
+
+		return repoBucket.Bucket([]byte(unreferencedBucket)).ForEach(func(key, _ []byte) error {
+			digests = append(digests, godigest.Digest(key))
+
+			return nil
+		})
+	})
+
+	return digests, err
+}
+
+// RemoveRepo forgets the references of the repo, it's tracked again by its next garbage collection.
+func (counter *Counter) RemoveRepo(repo string) error {
+	return counter.db.Update(func(tx *bbolt.Tx) error {
+		root, err := getRootBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		if root.Bucket([]byte(repo)) == nil {
+			return nil
+		}
+
+		return root.DeleteBucket([]byte(repo))
+	})
+}
+
+func getRootBucket(tx *bbolt.Tx) (*bbolt.Bucket, error) {
+	root := tx.Bucket([]byte(reposBucket))
+	if root == nil {
+		return nil, zerr.ErrCacheRootBucket
+	}
+
+	return root, nil
+}
+
+// getRepoBucket returns the bucket of the repo, nil if it isn't tracked. The bucket is created with all the
+// references of the repo, in the same transaction.
+func getRepoBucket(tx *bbolt.Tx, repo string) *bbolt.Bucket {
+	root, err := getRootBucket(tx)
+	if err != nil {
+		return nil
+	}
+
+	return root.Bucket([]byte(repo))
+}
+
+func addManifest(repoBucket *bbolt.Bucket, manifest godigest.Digest, refs []godigest.Digest) error {
+	manifests := repoBucket.Bucket([]byte(manifestsBucket))
+	if manifests.Get([]byte(manifest)) != nil {
+		return nil
+	}
+
+	// a blob referenced twice by the manifest is counted once
+	uniqueRefs := []godigest.Digest{}
+	seen := map[godigest.Digest]bool{}
+
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			uniqueRefs = append(uniqueRefs, ref)
+		}
+	}
+
+	buf, err := json.Marshal(uniqueRefs)
+	if err != nil {
+		return err
+	}
+
+	if err := manifests.Put([]byte(manifest), buf); err != nil {
+		return err
+	}
+
+	counts := repoBucket.Bucket([]byte(countsBucket))
+	unreferenced := repoBucket.Bucket([]byte(unreferencedBucket))
+
+	for _, ref := range uniqueRefs {
+		if err := putCount(counts, ref, getCount(counts, ref)+1); err != nil {
+			return err
+		}
+
+		if err := unreferenced.Delete([]byte(ref)); err != nil {
+			return err
+		}
+	}
+
+	return addBlob(repoBucket, manifest)
+}
+
+func addBlob(repoBucket *bbolt.Bucket, digest godigest.Digest) error {
+	if getCount(repoBucket.Bucket([]byte(countsBucket)), digest) > 0 {
+		return nil
+	}
+
+	return repoBucket.Bucket([]byte(unreferencedBucket)).Put([]byte(digest), []byte{})
+}
+
+func getCount(counts *bbolt.Bucket, digest godigest.Digest) uint64 {
+	buf := counts.Get([]byte(digest))
+	if len(buf) != 8 { //nolint:gomnd // uint64
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(buf)
+}
+
+func putCount(counts *bbolt.Bucket, digest godigest.Digest, count uint64) error {
+	buf := make([]byte, 8) //nolint:gomnd // uint64
+	binary.BigEndian.PutUint64(buf, count)
+
+	return counts.Put([]byte(digest), buf)
+}
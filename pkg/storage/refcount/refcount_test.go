@@ -0,0 +1,155 @@
+package refcount_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage/refcount"
+)
+
+func TestCounter(t *testing.T) {
+	Convey("Count the references of the manifests to the blobs", t, func() {
+		rootDir := t.TempDir()
+
+		counter, err := refcount.NewCounter(rootDir, log.NewLogger("debug", ""))
+		So(err, ShouldBeNil)
+
+		defer counter.Close()
+
+		_, err = os.Stat(path.Join(rootDir, "refcount.db"))
+		So(err, ShouldBeNil)
+
+		config := godigest.FromString("config")
+		layer := godigest.FromString("layer")
+		sharedLayer := godigest.FromString("shared layer")
+		image := godigest.FromString("image")
+		otherImage := godigest.FromString("other image")
+		orphan := godigest.FromString("orphan")
+
+		// the repos aren't tracked until all their references are counted
+		tracked, err := counter.IsTracked("app")
+		So(err, ShouldBeNil)
+		So(tracked, ShouldBeFalse)
+
+		err = counter.AddBlob("app", orphan)
+		So(err, ShouldBeNil)
+
+		_, err = counter.Unreferenced("app")
+		So(err, ShouldNotBeNil)
+
+		err = counter.Track("app", map[godigest.Digest][]godigest.Digest{
+			image: {config, layer, sharedLayer, layer},
+		}, []godigest.Digest{config, layer, sharedLayer, image, orphan})
+		So(err, ShouldBeNil)
+
+		tracked, err = counter.IsTracked("app")
+		So(err, ShouldBeNil)
+		So(tracked, ShouldBeTrue)
+
+		// the manifests of the index aren't referenced
+		unreferenced, err := counter.Unreferenced("app")
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldHaveLength, 2)
+		So(unreferenced, ShouldContain, image)
+		So(unreferenced, ShouldContain, orphan)
+
+		err = counter.AddBlob("app", sharedLayer)
+		So(err, ShouldBeNil)
+
+		err = counter.AddManifest("app", otherImage, []godigest.Digest{config, sharedLayer})
+		So(err, ShouldBeNil)
+
+		// the references of a manifest are only counted once
+		err = counter.AddManifest("app", otherImage, []godigest.Digest{config, sharedLayer})
+		So(err, ShouldBeNil)
+
+		unreferenced, err = counter.Unreferenced("app")
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldHaveLength, 3)
+		So(unreferenced, ShouldContain, otherImage)
+
+		// the blobs still referenced by the other image stay referenced
+		err = counter.RemoveBlob("app", image)
+		So(err, ShouldBeNil)
+
+		unreferenced, err = counter.Unreferenced("app")
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldHaveLength, 3)
+		So(unreferenced, ShouldContain, layer)
+		So(unreferenced, ShouldNotContain, image)
+		So(unreferenced, ShouldNotContain, sharedLayer)
+
+		err = counter.RemoveBlob("app", otherImage)
+		So(err, ShouldBeNil)
+
+		unreferenced, err = counter.Unreferenced("app")
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldHaveLength, 4)
+		So(unreferenced, ShouldContain, config)
+		So(unreferenced, ShouldContain, sharedLayer)
+
+		for _, digest := range unreferenced {
+			err = counter.RemoveBlob("app", digest)
+			So(err, ShouldBeNil)
+		}
+
+		unreferenced, err = counter.Unreferenced("app")
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldBeEmpty)
+
+		// tracking a repo again replaces its references
+		err = counter.Track("app", map[godigest.Digest][]godigest.Digest{}, []godigest.Digest{orphan})
+		So(err, ShouldBeNil)
+
+		unreferenced, err = counter.Unreferenced("app")
+		So(err, ShouldBeNil)
+		So(unreferenced, ShouldResemble, []godigest.Digest{orphan})
+
+		err = counter.RemoveRepo("app")
+		So(err, ShouldBeNil)
+
+		tracked, err = counter.IsTracked("app")
+		So(err, ShouldBeNil)
+		So(tracked, ShouldBeFalse)
+
+		err = counter.RemoveRepo("app")
+		So(err, ShouldBeNil)
+
+		Convey("The references are kept when zot restarts", func() {
+			err := counter.Track("app", map[godigest.Digest][]godigest.Digest{image: {layer}},
+				[]godigest.Digest{image, layer})
+			So(err, ShouldBeNil)
+
+			err = counter.Close()
+			So(err, ShouldBeNil)
+
+			counter, err = refcount.NewCounter(rootDir, log.NewLogger("debug", ""))
+			So(err, ShouldBeNil)
+
+			unreferenced, err := counter.Unreferenced("app")
+			So(err, ShouldBeNil)
+			So(unreferenced, ShouldResemble, []godigest.Digest{image})
+		})
+	})
+
+	Convey("The database can't be created", t, func() {
+		rootDir := t.TempDir()
+
+		err := os.Mkdir(path.Join(rootDir, "refcount.db"), 0o755)
+		So(err, ShouldBeNil)
+
+		_, err = refcount.NewCounter(rootDir, log.NewLogger("debug", ""))
+		So(err, ShouldNotBeNil)
+
+		err = os.WriteFile(path.Join(rootDir, "file"), []byte{}, 0o600)
+		So(err, ShouldBeNil)
+
+		_, err = refcount.NewCounter(path.Join(rootDir, "file", "dir"), log.NewLogger("debug", ""))
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -52,6 +52,11 @@ func (driver *Driver) Stat(path string) (driver.FileInfo, error) {
 }
 
 func (driver *Driver) Writer(filepath string, append bool) (driver.FileWriter, error) { //nolint:predeclared
+	// the blob uploads keep a session to be resumed after a restart
+	if isBlobUpload(filepath) {
+		return driver.uploadWriter(filepath, append)
+	}
+
 	return driver.store.Writer(context.Background(), filepath, append)
 }
 
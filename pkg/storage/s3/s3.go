@@ -20,8 +20,8 @@ import (
 // see https://github.com/docker/docker.github.io/tree/master/registry/storage-drivers
 // Use the last argument to properly set a cache database, or it will default to boltDB local storage.
 func NewImageStore(rootDir string, cacheDir string, gc bool, gcReferrers bool, gcDelay time.Duration,
-	untaggedImageRetentionDelay time.Duration, dedupe, commit bool, log zlog.Logger, metrics monitoring.MetricServer,
-	linter common.Lint, store driver.StorageDriver, cacheDriver cache.Cache,
+	untaggedImageRetentionDelay time.Duration, dedupe, commit, readOnly bool, log zlog.Logger,
+	metrics monitoring.MetricServer, linter common.Lint, store driver.StorageDriver, cacheDriver cache.Cache,
 ) storageTypes.ImageStore {
 	return imagestore.NewImageStore(
 		rootDir,
@@ -32,6 +32,7 @@ func NewImageStore(rootDir string, cacheDir string, gc bool, gcReferrers bool, g
 		untaggedImageRetentionDelay,
 		dedupe,
 		commit,
+		readOnly,
 		log,
 		metrics,
 		linter,
@@ -12,6 +12,7 @@ import (
 	"path"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/docker/distribution/registry/storage/driver"
@@ -87,7 +88,7 @@ func createMockStorage(rootDir string, cacheDir string, dedupe bool, store drive
 	}
 
 	il := s3.NewImageStore(rootDir, cacheDir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, log, metrics, nil, store, cacheDriver,
+		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, false, log, metrics, nil, store, cacheDriver,
 	)
 
 	return il
@@ -100,7 +101,7 @@ func createMockStorageWithMockCache(rootDir string, dedupe bool, store driver.St
 	metrics := monitoring.NewMetricsServer(false, log)
 
 	il := s3.NewImageStore(rootDir, "", true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, log, metrics, nil, store, cacheDriver,
+		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, false, log, metrics, nil, store, cacheDriver,
 	)
 
 	return il
@@ -162,7 +163,7 @@ func createObjectsStore(rootDir string, cacheDir string, dedupe bool) (
 	}
 
 	il := s3.NewImageStore(rootDir, cacheDir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, log, metrics, nil, store, cacheDriver)
+		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, false, log, metrics, nil, store, cacheDriver)
 
 	return store, il, err
 }
@@ -197,7 +198,7 @@ func createObjectsStoreDynamo(rootDir string, cacheDir string, dedupe bool, tabl
 	}
 
 	il := s3.NewImageStore(rootDir, cacheDir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, log, metrics, nil, store, cacheDriver)
+		storageConstants.DefaultUntaggedImgeRetentionDelay, dedupe, false, false, log, metrics, nil, store, cacheDriver)
 
 	return store, il, err
 }
@@ -213,9 +214,10 @@ func runAndGetScheduler() (*scheduler.Scheduler, context.CancelFunc) {
 }
 
 type FileInfoMock struct {
-	IsDirFn func() bool
-	SizeFn  func() int64
-	PathFn  func() string
+	IsDirFn   func() bool
+	SizeFn    func() int64
+	PathFn    func() string
+	ModTimeFn func() time.Time
 }
 
 func (f *FileInfoMock) Path() string {
@@ -235,6 +237,10 @@ func (f *FileInfoMock) Size() int64 {
 }
 
 func (f *FileInfoMock) ModTime() time.Time {
+	if f != nil && f.ModTimeFn != nil {
+		return f.ModTimeFn()
+	}
+
 	return time.Now()
 }
 
@@ -247,6 +253,7 @@ func (f *FileInfoMock) IsDir() bool {
 }
 
 type FileWriterMock struct {
+	SizeFn   func() int64
 	WriteFn  func([]byte) (int, error)
 	CancelFn func() error
 	CommitFn func() error
@@ -254,6 +261,10 @@ type FileWriterMock struct {
 }
 
 func (f *FileWriterMock) Size() int64 {
+	if f != nil && f.SizeFn != nil {
+		return f.SizeFn()
+	}
+
 	return int64(fileWriterSize)
 }
 
@@ -466,6 +477,285 @@ func TestStorageDriverStatFunction(t *testing.T) {
 	})
 }
 
+func TestS3BlobUploadSession(t *testing.T) {
+	tdir := t.TempDir()
+	testDir := "/oci-repo-test"
+
+	uploadID := "upload"
+	uploadPath := path.Join(testDir, testImage, storageConstants.BlobUploadDir, uploadID)
+	sessionPath := path.Join(testDir, testImage, storageConstants.BlobUploadDir, ".sessions", uploadID)
+
+	// the content completed by the s3 driver before the restart, it has the size of FileWriterMock
+	content := []byte("test-content")
+
+	Convey("Blob upload sessions are kept next to the uploads", t, func() {
+		sessions := map[string][]byte{}
+
+		storeDriver := &StorageDriverMock{
+			PutContentFn: func(ctx context.Context, path string, content []byte) error {
+				sessions[path] = content
+
+				return nil
+			},
+			DeleteFn: func(ctx context.Context, path string) error {
+				delete(sessions, path)
+
+				return nil
+			},
+		}
+
+		imgStore := createMockStorage(testDir, tdir, false, storeDriver)
+
+		err := imgStore.DeleteBlobUpload(testImage, uploadID)
+		So(err, ShouldBeNil)
+		So(sessions, ShouldBeEmpty)
+
+		// the session isn't written if the size of the upload didn't change
+		size, err := imgStore.GetBlobUpload(testImage, uploadID)
+		So(err, ShouldBeNil)
+		So(size, ShouldEqual, fileWriterSize)
+		So(sessions, ShouldBeEmpty)
+
+		storeDriver.WriterFn = func(ctx context.Context, path string, isAppend bool) (driver.FileWriter, error) {
+			size := 0
+
+			return &FileWriterMock{
+				SizeFn: func() int64 { return int64(size) },
+				WriteFn: func(b []byte) (int, error) {
+					size += len(b)
+
+					return len(b), nil
+				},
+			}, nil
+		}
+
+		_, err = imgStore.PutBlobChunkStreamed(testImage, uploadID, bytes.NewReader(content))
+		So(err, ShouldBeNil)
+		So(string(sessions[sessionPath]), ShouldEqual, `{"size":12}`)
+
+		err = imgStore.FinishBlobUpload(testImage, uploadID, bytes.NewReader([]byte{}),
+			godigest.FromBytes([]byte{}))
+		So(err, ShouldBeNil)
+		So(sessions, ShouldBeEmpty)
+	})
+
+	Convey("Blob uploads are resumed after a restart", t, func() {
+		var (
+			cancelled bool
+			resumed   bytes.Buffer
+		)
+
+		session := []byte(`{"size":12}`)
+
+		storeDriver := &StorageDriverMock{
+			GetContentFn: func(ctx context.Context, path string) ([]byte, error) {
+				if path == sessionPath {
+					return session, nil
+				}
+
+				return nil, driver.PathNotFoundError{Path: path}
+			},
+			StatFn: func(ctx context.Context, path string) (driver.FileInfo, error) {
+				return &FileInfoMock{
+					IsDirFn: func() bool { return path != uploadPath },
+					SizeFn:  func() int64 { return int64(len(content)) },
+				}, nil
+			},
+			ReaderFn: func(ctx context.Context, path string, offset int64) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(content)), nil
+			},
+			WriterFn: func(ctx context.Context, path string, isAppend bool) (driver.FileWriter, error) {
+				if isAppend {
+					return nil, driver.PathNotFoundError{Path: path}
+				}
+
+				resumed.Reset()
+
+				return &FileWriterMock{WriteFn: resumed.Write}, nil
+			},
+		}
+
+		imgStore := createMockStorage(testDir, tdir, false, storeDriver)
+
+		Convey("The multipart upload is missing", func() {
+			size, err := imgStore.GetBlobUpload(testImage, uploadID)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+			So(resumed.Bytes(), ShouldResemble, content)
+
+			_, err = imgStore.PutBlobChunk(testImage, uploadID, size, size, bytes.NewReader([]byte{}))
+			So(err, ShouldBeNil)
+		})
+
+		Convey("The multipart upload is empty", func() {
+			storeDriver.WriterFn = func(ctx context.Context, path string, isAppend bool) (driver.FileWriter, error) {
+				if isAppend {
+					return &FileWriterMock{
+						SizeFn:   func() int64 { return 0 },
+						CancelFn: func() error { cancelled = true; return nil },
+					}, nil
+				}
+
+				return &FileWriterMock{WriteFn: resumed.Write}, nil
+			}
+
+			size, err := imgStore.BlobUploadInfo(testImage, uploadID)
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(content))
+			So(cancelled, ShouldBeTrue)
+			So(resumed.Bytes(), ShouldResemble, content)
+
+			// the empty multipart upload can't be cancelled
+			storeDriver.WriterFn = func(ctx context.Context, path string, isAppend bool) (driver.FileWriter, error) {
+				return &FileWriterMock{
+					SizeFn:   func() int64 { return 0 },
+					CancelFn: func() error { return errS3 },
+				}, nil
+			}
+
+			_, err = imgStore.BlobUploadInfo(testImage, uploadID)
+			So(err, ShouldEqual, errS3)
+		})
+
+		Convey("The content can't be copied to the new multipart upload", func() {
+			storeDriver.ReaderFn = func(ctx context.Context, path string, offset int64) (io.ReadCloser, error) {
+				return io.NopCloser(iotest.ErrReader(errS3)), nil
+			}
+
+			_, err := imgStore.GetBlobUpload(testImage, uploadID)
+			So(err, ShouldEqual, errS3)
+
+			storeDriver.WriterFn = func(ctx context.Context, path string, isAppend bool) (driver.FileWriter, error) {
+				if isAppend {
+					return nil, driver.PathNotFoundError{Path: path}
+				}
+
+				return nil, errS3
+			}
+
+			_, err = imgStore.GetBlobUpload(testImage, uploadID)
+			So(err, ShouldEqual, errS3)
+		})
+
+		Convey("The upload can't be resumed", func() {
+			// the completed object doesn't have the size of the session
+			session = []byte(`{"size":100}`)
+
+			_, err := imgStore.GetBlobUpload(testImage, uploadID)
+			So(err, ShouldEqual, zerr.ErrUploadNotFound)
+
+			// the completed object can't be read
+			session = []byte(`{"size":12}`)
+			storeDriver.ReaderFn = func(ctx context.Context, path string, offset int64) (io.ReadCloser, error) {
+				return nil, errS3
+			}
+
+			_, err = imgStore.GetBlobUpload(testImage, uploadID)
+			So(err, ShouldEqual, zerr.ErrUploadNotFound)
+
+			// there's no session
+			session = []byte("invalid")
+
+			_, err = imgStore.GetBlobUpload(testImage, uploadID)
+			So(err, ShouldEqual, zerr.ErrUploadNotFound)
+
+			err = imgStore.DeleteBlobUpload(testImage, uploadID)
+			So(err, ShouldEqual, zerr.ErrUploadNotFound)
+		})
+	})
+
+	Convey("Stale blob uploads are expired with their sessions", t, func() {
+		var (
+			cancelled bool
+			deleted   []string
+		)
+
+		lastWrite := time.Now().Add(-2 * time.Hour)
+
+		storeDriver := &StorageDriverMock{
+			WalkFn: func(ctx context.Context, path string, walkFn driver.WalkFn) error {
+				// the object completed by the s3 driver and the session of the upload
+				for _, file := range []string{uploadPath, sessionPath} {
+					err := walkFn(&FileInfoMock{
+						IsDirFn:   func() bool { return false },
+						PathFn:    func() string { return file },
+						ModTimeFn: func() time.Time { return lastWrite },
+					})
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+			WriterFn: func(ctx context.Context, path string, isAppend bool) (driver.FileWriter, error) {
+				return &FileWriterMock{CancelFn: func() error {
+					cancelled = true
+
+					return nil
+				}}, nil
+			},
+			DeleteFn: func(ctx context.Context, path string) error {
+				deleted = append(deleted, path)
+
+				return nil
+			},
+		}
+
+		imgStore := createMockStorage(testDir, tdir, false, storeDriver)
+
+		expired, err := imgStore.ExpireBlobUploads(testImage, time.Hour)
+		So(err, ShouldBeNil)
+		So(expired, ShouldEqual, 1)
+		So(cancelled, ShouldBeTrue)
+		So(deleted, ShouldContain, uploadPath)
+		So(deleted, ShouldContain, sessionPath)
+
+		// the upload was written since
+		lastWrite = time.Now()
+
+		expired, err = imgStore.ExpireBlobUploads(testImage, time.Hour)
+		So(err, ShouldBeNil)
+		So(expired, ShouldEqual, 0)
+
+		lastWrite = time.Now().Add(-2 * time.Hour)
+
+		storeDriver.DeleteFn = func(ctx context.Context, path string) error {
+			if path == sessionPath {
+				return driver.PathNotFoundError{Path: path}
+			}
+
+			return errS3
+		}
+
+		_, err = imgStore.ExpireBlobUploads(testImage, time.Hour)
+		So(err, ShouldEqual, errS3)
+
+		storeDriver.WriterFn = func(ctx context.Context, path string, isAppend bool) (driver.FileWriter, error) {
+			return nil, errS3
+		}
+
+		_, err = imgStore.ExpireBlobUploads(testImage, time.Hour)
+		So(err, ShouldEqual, errS3)
+
+		storeDriver.WalkFn = func(ctx context.Context, path string, walkFn driver.WalkFn) error {
+			return errS3
+		}
+
+		_, err = imgStore.ExpireBlobUploads(testImage, time.Hour)
+		So(err, ShouldEqual, errS3)
+
+		// there are no uploads
+		storeDriver.WalkFn = func(ctx context.Context, path string, walkFn driver.WalkFn) error {
+			return driver.PathNotFoundError{Path: path}
+		}
+
+		expired, err = imgStore.ExpireBlobUploads(testImage, time.Hour)
+		So(err, ShouldBeNil)
+		So(expired, ShouldEqual, 0)
+	})
+}
+
 func TestGetOrasAndOCIReferrers(t *testing.T) {
 	skipIt(t)
 
@@ -0,0 +1,170 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+)
+
+// the sessions of the blob uploads are kept in this directory of the uploads directory.
+const uploadSessionsDir = ".sessions"
+
+// uploadSession is the state of a blob upload kept next to it. The parts of an upload are kept by its s3 multipart
+// upload, but the s3 driver completes the multipart upload and starts a new one when the last part is smaller than
+// the minimum part size, the uploaded content is lost if zot stops in between. The size of the session tells if the
+// multipart upload found after a restart is missing content.
+type uploadSession struct {
+	// Size is the number of bytes uploaded when the writer of the upload was last closed.
+	Size int64 `json:"size"`
+}
+
+// uploadWriter writes a blob upload and keeps its session up to date.
+type uploadWriter struct {
+	storagedriver.FileWriter
+	driver *Driver
+	path   string
+	// the size of the upload in its session, -1 if it's a new upload.
+	size int64
+	done bool
+}
+
+func isBlobUpload(filepath string) bool {
+	return path.Base(path.Dir(filepath)) == storageConstants.BlobUploadDir
+}
+
+func uploadSessionPath(filepath string) string {
+	return path.Join(path.Dir(filepath), uploadSessionsDir, path.Base(filepath))
+}
+
+func (driver *Driver) getUploadSession(filepath string) (uploadSession, bool) {
+	var session uploadSession
+
+	content, err := driver.store.GetContent(context.Background(), uploadSessionPath(filepath))
+	if err != nil {
+		return session, false
+	}
+
+	if err := json.Unmarshal(content, &session); err != nil {
+		return session, false
+	}
+
+	return session, true
+}
+
+// the sessions are best effort, without them the uploads are still resumed from their multipart uploads.
+func (driver *Driver) putUploadSession(filepath string, session uploadSession) {
+	content, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+
+	_ = driver.store.PutContent(context.Background(), uploadSessionPath(filepath), content)
+}
+
+func (driver *Driver) deleteUploadSession(filepath string) {
+	_ = driver.store.Delete(context.Background(), uploadSessionPath(filepath))
+}
+
+// uploadWriter opens the writer of a blob upload, an upload whose multipart upload is missing or empty while its
+// session isn't is resumed from the object completed by the s3 driver.
+func (driver *Driver) uploadWriter(filepath string, append bool, //nolint:predeclared
+) (storagedriver.FileWriter, error) {
+	writer, err := driver.store.Writer(context.Background(), filepath, append)
+
+	if append && (errors.As(err, &storagedriver.PathNotFoundError{}) || err == nil && writer.Size() == 0) {
+		writer, err = driver.resumeUpload(filepath, writer, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	size := int64(-1)
+	if append {
+		size = writer.Size()
+	}
+
+	return &uploadWriter{FileWriter: writer, driver: driver, path: filepath, size: size}, nil
+}
+
+// resumeUpload starts a new multipart upload with the content of the object completed by the s3 driver if it has
+// the size of the session, otherwise the writer opened for the upload and its error are returned.
+func (driver *Driver) resumeUpload(filepath string, writer storagedriver.FileWriter, err error,
+) (storagedriver.FileWriter, error) {
+	session, ok := driver.getUploadSession(filepath)
+	if !ok || session.Size == 0 {
+		return writer, err
+	}
+
+	fileInfo, statErr := driver.store.Stat(context.Background(), filepath)
+	if statErr != nil || fileInfo.IsDir() || fileInfo.Size() != session.Size {
+		return writer, err
+	}
+
+	reader, readErr := driver.store.Reader(context.Background(), filepath, 0)
+	if readErr != nil {
+		return writer, err
+	}
+
+	defer reader.Close()
+
+	// the empty multipart upload started before the restart is replaced
+	if writer != nil {
+		if err := writer.Cancel(); err != nil {
+			return nil, err
+		}
+	}
+
+	resumed, err := driver.store.Writer(context.Background(), filepath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(resumed, reader); err != nil {
+		_ = resumed.Cancel()
+
+		return nil, err
+	}
+
+	return resumed, nil
+}
+
+func (writer *uploadWriter) Close() error {
+	if err := writer.FileWriter.Close(); err != nil {
+		return err
+	}
+
+	if !writer.done && writer.Size() != writer.size {
+		writer.driver.putUploadSession(writer.path, uploadSession{Size: writer.Size()})
+	}
+
+	return nil
+}
+
+func (writer *uploadWriter) Commit() error {
+	if err := writer.FileWriter.Commit(); err != nil {
+		return err
+	}
+
+	writer.done = true
+	writer.driver.deleteUploadSession(writer.path)
+
+	return nil
+}
+
+func (writer *uploadWriter) Cancel() error {
+	if err := writer.FileWriter.Cancel(); err != nil {
+		return err
+	}
+
+	writer.done = true
+	writer.driver.deleteUploadSession(writer.path)
+
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/opencontainers/umoci/oci/casext"
 
 	"zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/common"
 	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
 
@@ -33,12 +34,27 @@ const (
 )
 
 type ScrubImageResult struct {
-	ImageName string `json:"imageName"`
-	Tag       string `json:"tag"`
-	Status    string `json:"status"`
-	Error     string `json:"error"`
+	ImageName    string `json:"imageName"`
+	Tag          string `json:"tag"`
+	Status       string `json:"status"`
+	Error        string `json:"error"`
+	AffectedBlob string `json:"affectedBlob,omitempty"` // the first corrupted layer found
+	RepairStatus string `json:"repairStatus,omitempty"` // "repaired" or "failed" if a repair was attempted
+	RepairSource string `json:"repairSource,omitempty"` // where the valid copies of the blobs were taken from
+	RepairError  string `json:"repairError,omitempty"`
 }
 
+const (
+	ScrubStatusOK       = "ok"
+	ScrubStatusAffected = "affected"
+
+	RepairStatusRepaired = "repaired"
+	RepairStatusFailed   = "failed"
+)
+
+// BlobRepairer replaces a corrupted blob of a repo with a valid copy, it returns where the copy was taken from.
+type BlobRepairer func(ctx context.Context, repo string, digest godigest.Digest) (string, error)
+
 type ScrubResults struct {
 	ScrubResults []ScrubImageResult `json:"scrubResults"`
 }
@@ -86,6 +102,17 @@ func CheckImageStoreBlobsIntegrity(ctx context.Context, imgStore storageTypes.Im
 }
 
 func CheckRepo(ctx context.Context, imageName string, imgStore storageTypes.ImageStore) ([]ScrubImageResult, error) {
+	return CheckAndRepairRepo(ctx, imageName, imgStore, nil)
+}
+
+/*
+CheckAndRepairRepo checks the integrity of the images of a repo, then, if repairBlob is not nil, it replaces
+the corrupted blobs of the affected images one at a time, until they're ok or a blob can't be repaired.
+The store is not locked while repairing, repairBlob locks it to replace the blobs.
+*/
+func CheckAndRepairRepo(ctx context.Context, imageName string, imgStore storageTypes.ImageStore,
+	repairBlob BlobRepairer,
+) ([]ScrubImageResult, error) {
 	results := []ScrubImageResult{}
 
 	if ctx.Err() != nil {
@@ -106,17 +133,37 @@ func CheckRepo(ctx context.Context, imageName string, imgStore storageTypes.Imag
 
 	var lockLatency time.Time
 
-	imgStore.RLock(&lockLatency)
-	defer imgStore.RUnlock(&lockLatency)
+	imgStore.RLockRepo(imageName, &lockLatency)
+	results, manifests, err := checkImages(ctx, imageName, oci, dir)
+	imgStore.RUnlockRepo(imageName, &lockLatency)
+
+	if err != nil || repairBlob == nil {
+		return results, err
+	}
+
+	for idx := range results {
+		if results[idx].Status == ScrubStatusAffected && results[idx].AffectedBlob != "" {
+			results[idx] = repairImage(ctx, imgStore, oci, manifests[idx], dir, results[idx], repairBlob)
+		}
+	}
+
+	return results, nil
+}
+
+// checkImages returns the results of the images of a repo, and their manifests, empty for the unreadable indexes.
+func checkImages(ctx context.Context, imageName string, oci casext.Engine, dir string,
+) ([]ScrubImageResult, []ispec.Descriptor, error) {
+	results := []ScrubImageResult{}
+	manifests := []ispec.Descriptor{}
 
 	buf, err := os.ReadFile(path.Join(dir, "index.json"))
 	if err != nil {
-		return results, err
+		return results, manifests, err
 	}
 
 	var index ispec.Index
 	if err := json.Unmarshal(buf, &index); err != nil {
-		return results, errors.ErrRepoNotFound
+		return results, manifests, errors.ErrRepoNotFound
 	}
 
 	listOfManifests := []ispec.Descriptor{}
@@ -128,6 +175,7 @@ func CheckRepo(ctx context.Context, imageName string, imgStore storageTypes.Imag
 				tagName := manifest.Annotations[ispec.AnnotationRefName]
 				imgRes := getResult(imageName, tagName, errors.ErrBadBlobDigest)
 				results = append(results, imgRes)
+				manifests = append(manifests, ispec.Descriptor{})
 
 				continue
 			}
@@ -137,6 +185,7 @@ func CheckRepo(ctx context.Context, imageName string, imgStore storageTypes.Imag
 				tagName := manifest.Annotations[ispec.AnnotationRefName]
 				imgRes := getResult(imageName, tagName, errors.ErrBadBlobDigest)
 				results = append(results, imgRes)
+				manifests = append(manifests, ispec.Descriptor{})
 
 				continue
 			}
@@ -151,9 +200,58 @@ func CheckRepo(ctx context.Context, imageName string, imgStore storageTypes.Imag
 		tag := m.Annotations[ispec.AnnotationRefName]
 		imageResult := CheckIntegrity(ctx, imageName, tag, oci, m, dir)
 		results = append(results, imageResult)
+		manifests = append(manifests, m)
 	}
 
-	return results, nil
+	return results, manifests, nil
+}
+
+// repairImage repairs the corrupted blobs of an affected image, and records the outcome in its result.
+func repairImage(ctx context.Context, imgStore storageTypes.ImageStore, oci casext.Engine,
+	manifest ispec.Descriptor, dir string, result ScrubImageResult, repairBlob BlobRepairer,
+) ScrubImageResult {
+	var lockLatency time.Time
+
+	repaired := map[string]bool{}
+	sources := []string{}
+
+	for result.Status == ScrubStatusAffected && result.AffectedBlob != "" {
+		// a blob which is still corrupted after being replaced can't be repaired
+		if repaired[result.AffectedBlob] {
+			result.RepairError = errors.ErrBadBlobDigest.Error()
+
+			break
+		}
+
+		source, err := repairBlob(ctx, result.ImageName, godigest.Digest(result.AffectedBlob))
+		if err != nil {
+			result.RepairError = err.Error()
+
+			break
+		}
+
+		repaired[result.AffectedBlob] = true
+
+		if !common.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+
+		repo := result.ImageName
+
+		imgStore.RLockRepo(repo, &lockLatency)
+		result = CheckIntegrity(ctx, repo, result.Tag, oci, manifest, dir)
+		imgStore.RUnlockRepo(repo, &lockLatency)
+	}
+
+	result.RepairSource = strings.Join(sources, ",")
+
+	if result.Status == ScrubStatusOK {
+		result.RepairStatus = RepairStatusRepaired
+	} else {
+		result.RepairStatus = RepairStatusFailed
+	}
+
+	return result
 }
 
 func CheckIntegrity(ctx context.Context, imageName, tagName string, oci casext.Engine, manifest ispec.Descriptor, dir string) ScrubImageResult { //nolint: lll
@@ -189,6 +287,7 @@ func CheckLayers(ctx context.Context, imageName, tagName, dir string, manifest i
 		_, err = os.Stat(layerPath)
 		if err != nil {
 			imageRes = getResult(imageName, tagName, errors.ErrBlobNotFound)
+			imageRes.AffectedBlob = layer.Digest.String()
 
 			break
 		}
@@ -196,21 +295,24 @@ func CheckLayers(ctx context.Context, imageName, tagName, dir string, manifest i
 		layerFh, err := os.Open(layerPath)
 		if err != nil {
 			imageRes = getResult(imageName, tagName, errors.ErrBlobNotFound)
+			imageRes.AffectedBlob = layer.Digest.String()
 
 			break
 		}
 
-		computedDigest, err := godigest.FromReader(layerFh)
+		computedDigest, err := layer.Digest.Algorithm().FromReader(layerFh)
 		layerFh.Close()
 
 		if err != nil {
 			imageRes = getResult(imageName, tagName, errors.ErrBadBlobDigest)
+			imageRes.AffectedBlob = layer.Digest.String()
 
 			break
 		}
 
 		if computedDigest != layer.Digest {
 			imageRes = getResult(imageName, tagName, errors.ErrBadBlobDigest)
+			imageRes.AffectedBlob = layer.Digest.String()
 
 			break
 		}
@@ -227,10 +329,10 @@ func getResult(imageName, tag string, err error) ScrubImageResult {
 	var errField string
 
 	if err != nil {
-		status = "affected"
+		status = ScrubStatusAffected
 		errField = err.Error()
 	} else {
-		status = "ok"
+		status = ScrubStatusOK
 		errField = ""
 	}
 
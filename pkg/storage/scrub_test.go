@@ -41,7 +41,7 @@ func TestCheckAllBlobsIntegrity(t *testing.T) {
 		UseRelPaths: true,
 	}, log)
 	imgStore := local.NewImageStore(dir, true, true, storageConstants.DefaultGCDelay,
-		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, cacheDriver)
+		storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, cacheDriver)
 
 	Convey("Scrub only one repo", t, func(c C) {
 		// initialize repo
@@ -3,21 +3,26 @@ package storage
 import (
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strings"
 
+	"github.com/docker/distribution/registry/storage/driver"
 	"github.com/docker/distribution/registry/storage/driver/factory"
+	"github.com/mitchellh/mapstructure"
 	godigest "github.com/opencontainers/go-digest"
 	ispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"zotregistry.io/zot/errors"
 	"zotregistry.io/zot/pkg/api/config"
 	zcommon "zotregistry.io/zot/pkg/common"
+	extconf "zotregistry.io/zot/pkg/extensions/config"
 	"zotregistry.io/zot/pkg/extensions/monitoring"
 	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/plugins"
+	"zotregistry.io/zot/pkg/storage/azure"
 	common "zotregistry.io/zot/pkg/storage/common"
 	"zotregistry.io/zot/pkg/storage/constants"
 	"zotregistry.io/zot/pkg/storage/local"
+	"zotregistry.io/zot/pkg/storage/plugin"
 	"zotregistry.io/zot/pkg/storage/s3"
 	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
@@ -53,19 +58,20 @@ func New(config *config.Config, linter common.Lint, metrics monitoring.MetricSer
 		rootDir := config.Storage.RootDirectory
 		defaultStore = local.NewImageStore(rootDir,
 			config.Storage.GC, config.Storage.GCReferrers, config.Storage.GCDelay, config.Storage.UntaggedImageRetentionDelay,
-			config.Storage.Dedupe, config.Storage.Commit, log, metrics, linter,
+			config.Storage.Dedupe, config.Storage.Commit, config.Storage.ReadOnly, log, metrics, linter,
 			CreateCacheDatabaseDriver(config.Storage.StorageConfig, log),
 		)
 	} else {
 		storeName := fmt.Sprintf("%v", config.Storage.StorageDriver["name"])
-		if storeName != constants.S3StorageDriverName {
+		if !IsSupportedStorageDriver(storeName) {
 			log.Fatal().Err(errors.ErrBadConfig).Str("storageDriver", storeName).
 				Msg("unsupported storage driver")
 		}
 		// Init a Storager from connection string.
-		store, err := factory.Create(storeName, config.Storage.StorageDriver)
+		store, err := createStorageDriver(storeName, config.Storage.StorageDriver, log)
 		if err != nil {
-			log.Error().Err(err).Str("rootDir", config.Storage.RootDirectory).Msg("unable to create s3 service")
+			log.Error().Err(err).Str("rootDir", config.Storage.RootDirectory).Str("storageDriver", storeName).
+				Msg("unable to create storage driver service")
 
 			return storeController, err
 		}
@@ -78,14 +84,13 @@ func New(config *config.Config, linter common.Lint, metrics monitoring.MetricSer
 		}
 
 		// false positive lint - linter does not implement Lint method
-		//nolint: typecheck,contextcheck
-		defaultStore = s3.NewImageStore(rootDir, config.Storage.RootDirectory,
-			config.Storage.GC, config.Storage.GCReferrers, config.Storage.GCDelay,
-			config.Storage.UntaggedImageRetentionDelay, config.Storage.Dedupe,
-			config.Storage.Commit, log, metrics, linter, store,
-			CreateCacheDatabaseDriver(config.Storage.StorageConfig, log))
+		//nolint: contextcheck
+		defaultStore = newObjectImageStore(storeName, rootDir, config.Storage.StorageConfig, store,
+			linter, metrics, log)
 	}
 
+	setChunkDedupe(defaultStore, config.Storage.StorageConfig)
+
 	storeController.DefaultStore = defaultStore
 
 	if config.Storage.SubPaths != nil {
@@ -157,23 +162,26 @@ func getSubStore(cfg *config.Config, subPaths map[string]config.StorageConfig,
 				imgStoreMap[storageConfig.RootDirectory] = local.NewImageStore(rootDir,
 					storageConfig.GC, storageConfig.GCReferrers, storageConfig.GCDelay,
 					storageConfig.UntaggedImageRetentionDelay, storageConfig.Dedupe,
-					storageConfig.Commit, log, metrics, linter,
+					storageConfig.Commit, storageConfig.ReadOnly, log, metrics, linter,
 					CreateCacheDatabaseDriver(storageConfig, log),
 				)
 
+				setChunkDedupe(imgStoreMap[storageConfig.RootDirectory], storageConfig)
+
 				subImageStore[route] = imgStoreMap[storageConfig.RootDirectory]
 			}
 		} else {
 			storeName := fmt.Sprintf("%v", storageConfig.StorageDriver["name"])
-			if storeName != constants.S3StorageDriverName {
+			if !IsSupportedStorageDriver(storeName) {
 				log.Fatal().Err(errors.ErrBadConfig).Str("storageDriver", storeName).
 					Msg("unsupported storage driver")
 			}
 
 			// Init a Storager from connection string.
-			store, err := factory.Create(storeName, storageConfig.StorageDriver)
+			store, err := createStorageDriver(storeName, storageConfig.StorageDriver, log)
 			if err != nil {
-				log.Error().Err(err).Str("rootDir", storageConfig.RootDirectory).Msg("Unable to create s3 service")
+				log.Error().Err(err).Str("rootDir", storageConfig.RootDirectory).Str("storageDriver", storeName).
+					Msg("unable to create storage driver service")
 
 				return nil, err
 			}
@@ -185,20 +193,107 @@ func getSubStore(cfg *config.Config, subPaths map[string]config.StorageConfig,
 				rootDir = fmt.Sprintf("%v", cfg.Storage.StorageDriver["rootdirectory"])
 			}
 
-			// false positive lint - linter does not implement Lint method
-			//nolint: typecheck
-			subImageStore[route] = s3.NewImageStore(rootDir, storageConfig.RootDirectory,
-				storageConfig.GC, storageConfig.GCReferrers, storageConfig.GCDelay,
-				storageConfig.UntaggedImageRetentionDelay, storageConfig.Dedupe,
-				storageConfig.Commit, log, metrics, linter, store,
-				CreateCacheDatabaseDriver(storageConfig, log),
-			)
+			subImageStore[route] = newObjectImageStore(storeName, rootDir, storageConfig, store, linter, metrics, log)
+			setChunkDedupe(subImageStore[route], storageConfig)
 		}
 	}
 
 	return subImageStore, nil
 }
 
+// IsSupportedStorageDriver returns true if the storage driver can back an image store.
+func IsSupportedStorageDriver(storeName string) bool {
+	return storeName == constants.S3StorageDriverName || storeName == constants.AzureStorageDriverName ||
+		storeName == constants.PluginStorageDriverName
+}
+
+// GetStoragePluginConfig returns the plugin serving the storage driver, it's configured under the "plugin" key
+// of the storage driver parameters.
+func GetStoragePluginConfig(parameters map[string]interface{}) (extconf.PluginConfig, error) {
+	pluginConfig := extconf.PluginConfig{}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     &pluginConfig,
+	})
+	if err != nil {
+		return pluginConfig, err
+	}
+
+	if err := decoder.Decode(parameters["plugin"]); err != nil {
+		return pluginConfig, fmt.Errorf("%w: %w", errors.ErrBadConfig, err)
+	}
+
+	if pluginConfig.Path == "" {
+		return pluginConfig, fmt.Errorf("%w: the path of the storage driver plugin is required", errors.ErrBadConfig)
+	}
+
+	if pluginConfig.StartTimeout < 0 {
+		return pluginConfig, fmt.Errorf("%w: the start timeout of the storage driver plugin can't be negative",
+			errors.ErrBadConfig)
+	}
+
+	if pluginConfig.Name == "" {
+		pluginConfig.Name = constants.PluginStorageDriverName
+	}
+
+	return pluginConfig, nil
+}
+
+// createStorageDriver creates the storage driver from its parameters, the plugins are started and receive
+// the parameters other than the name of the driver and the plugin config.
+func createStorageDriver(storeName string, parameters map[string]interface{}, log log.Logger,
+) (driver.StorageDriver, error) {
+	if storeName != constants.PluginStorageDriverName {
+		return factory.Create(storeName, parameters)
+	}
+
+	pluginConfig, err := GetStoragePluginConfig(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginParameters := map[string]interface{}{}
+
+	for key, value := range parameters {
+		if key != "name" && key != "plugin" {
+			pluginParameters[key] = value
+		}
+	}
+
+	return plugins.NewStorageDriverClient(pluginConfig, pluginParameters, log)
+}
+
+// newObjectImageStore returns the image store backed by a storage driver, storageConfig.RootDirectory is used
+// for caching blobs locally and rootDir is the actual root directory in the storage driver.
+func newObjectImageStore(storeName, rootDir string, storageConfig config.StorageConfig, store driver.StorageDriver,
+	linter common.Lint, metrics monitoring.MetricServer, log log.Logger,
+) storageTypes.ImageStore {
+	cacheDriver := CreateCacheDatabaseDriver(storageConfig, log)
+
+	switch storeName {
+	case constants.AzureStorageDriverName:
+		return azure.NewImageStore(rootDir, storageConfig.RootDirectory, storageConfig.GC, storageConfig.GCReferrers,
+			storageConfig.GCDelay, storageConfig.UntaggedImageRetentionDelay, storageConfig.Dedupe,
+			storageConfig.Commit, storageConfig.ReadOnly, log, metrics, linter, store, cacheDriver)
+	case constants.PluginStorageDriverName:
+		return plugin.NewImageStore(rootDir, storageConfig.RootDirectory, storageConfig.GC, storageConfig.GCReferrers,
+			storageConfig.GCDelay, storageConfig.UntaggedImageRetentionDelay, storageConfig.Dedupe,
+			storageConfig.Commit, storageConfig.ReadOnly, log, metrics, linter, store, cacheDriver)
+	}
+
+	return s3.NewImageStore(rootDir, storageConfig.RootDirectory, storageConfig.GC, storageConfig.GCReferrers,
+		storageConfig.GCDelay, storageConfig.UntaggedImageRetentionDelay, storageConfig.Dedupe,
+		storageConfig.Commit, storageConfig.ReadOnly, log, metrics, linter, store, cacheDriver)
+}
+
+// setChunkDedupe splits the blobs of the image store in chunks if it's enabled in its config.
+func setChunkDedupe(imgStore storageTypes.ImageStore, storageConfig config.StorageConfig) {
+	if storageConfig.ChunkDedupe != nil {
+		imgStore.SetChunkDedupe(storageConfig.ChunkDedupe.MinBlobSize, storageConfig.ChunkDedupe.AverageChunkSize)
+	}
+}
+
 func compareImageStore(root1, root2 string) bool {
 	isSameFile, err := config.SameFile(root1, root2)
 	// This error is path error that means either of root directory doesn't exist, in that case do string match
@@ -236,16 +331,7 @@ func CheckIsImageSignature(repoName string, manifestBlob []byte, reference strin
 	}
 
 	// check cosign
-	cosignTagRule := regexp.MustCompile(`sha256\-.+\.sig`)
-
-	if tag := reference; cosignTagRule.MatchString(reference) {
-		prefixLen := len("sha256-")
-		digestLen := 64
-		signedImageManifestDigestEncoded := tag[prefixLen : prefixLen+digestLen]
-
-		signedImageManifestDigest := godigest.NewDigestFromEncoded(godigest.SHA256,
-			signedImageManifestDigestEncoded)
-
+	if signedImageManifestDigest, ok := common.GetCosignSignedDigest(reference); ok {
 		return true, CosignType, signedImageManifestDigest, nil
 	}
 
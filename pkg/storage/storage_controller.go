@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -45,3 +46,43 @@ func (sc StoreController) GetImageStore(name string) storageTypes.ImageStore {
 
 	return sc.DefaultStore
 }
+
+// getImageStores returns the default image store and the ones of the subpaths, the subpaths sharing a root
+// directory share their image store, it's closed again without error.
+func (sc StoreController) getImageStores() []storageTypes.ImageStore {
+	imgStores := []storageTypes.ImageStore{}
+
+	if sc.DefaultStore != nil {
+		imgStores = append(imgStores, sc.DefaultStore)
+	}
+
+	for _, subStore := range sc.SubStore {
+		imgStores = append(imgStores, subStore)
+	}
+
+	return imgStores
+}
+
+// Ping checks if the cache databases of all the image stores can be reached.
+func (sc StoreController) Ping() error {
+	for _, imgStore := range sc.getImageStores() {
+		if err := imgStore.Ping(); err != nil {
+			return fmt.Errorf("image store %s: %w", imgStore.RootDir(), err)
+		}
+	}
+
+	return nil
+}
+
+// Close releases the cache databases of all the image stores.
+func (sc StoreController) Close() error {
+	var errs []error
+
+	for _, imgStore := range sc.getImageStores() {
+		if err := imgStore.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("image store %s: %w", imgStore.RootDir(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
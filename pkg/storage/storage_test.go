@@ -94,7 +94,7 @@ func createObjectsStore(rootDir string, cacheDir string, gcDelay time.Duration,
 	}, log)
 
 	il := s3.NewImageStore(rootDir, cacheDir, true, true, gcDelay, imageRetention,
-		true, false, log, metrics, nil, store, cacheDriver,
+		true, false, false, log, metrics, nil, store, cacheDriver,
 	)
 
 	return store, il, err
@@ -149,7 +149,7 @@ func TestStorageAPIs(t *testing.T) {
 				driver := local.New(true)
 
 				imgStore = imagestore.NewImageStore(dir, dir, true, true, storageConstants.DefaultGCDelay,
-					storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, driver, cacheDriver)
+					storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, driver, cacheDriver)
 			}
 
 			Convey("Repo layout", t, func(c C) {
@@ -769,7 +769,7 @@ func TestMandatoryAnnotations(t *testing.T) {
 					storageConstants.DefaultUntaggedImgeRetentionDelay)
 				driver := s3.New(store)
 				imgStore = imagestore.NewImageStore(testDir, tdir, true, true, storageConstants.DefaultGCDelay,
-					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics,
+					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics,
 					&mocks.MockedLint{
 						LintFn: func(repo string, manifestDigest godigest.Digest, imageStore storageTypes.ImageStore) (bool, error) {
 							return false, nil
@@ -787,7 +787,7 @@ func TestMandatoryAnnotations(t *testing.T) {
 				driver := local.New(true)
 				imgStore = imagestore.NewImageStore(tdir, tdir, true, true, storageConstants.DefaultGCDelay,
 					storageConstants.DefaultUntaggedImgeRetentionDelay, true,
-					true, log, metrics, &mocks.MockedLint{
+					true, false, log, metrics, &mocks.MockedLint{
 						LintFn: func(repo string, manifestDigest godigest.Digest, imageStore storageTypes.ImageStore) (bool, error) {
 							return false, nil
 						},
@@ -840,7 +840,7 @@ func TestMandatoryAnnotations(t *testing.T) {
 					if testcase.storageType == storageConstants.S3StorageDriverName {
 						driver := s3.New(store)
 						imgStore = imagestore.NewImageStore(testDir, tdir, true, true, storageConstants.DefaultGCDelay,
-							storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics,
+							storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics,
 							&mocks.MockedLint{
 								LintFn: func(repo string, manifestDigest godigest.Digest, imageStore storageTypes.ImageStore) (bool, error) {
 									//nolint: goerr113
@@ -856,7 +856,7 @@ func TestMandatoryAnnotations(t *testing.T) {
 						driver := local.New(true)
 						imgStore = imagestore.NewImageStore(tdir, tdir, true, true, storageConstants.DefaultGCDelay,
 							storageConstants.DefaultUntaggedImgeRetentionDelay, true,
-							true, log, metrics, &mocks.MockedLint{
+							true, false, log, metrics, &mocks.MockedLint{
 								LintFn: func(repo string, manifestDigest godigest.Digest, imageStore storageTypes.ImageStore) (bool, error) {
 									//nolint: goerr113
 									return false, errors.New("linter error")
@@ -908,7 +908,7 @@ func TestDeleteBlobsInUse(t *testing.T) {
 				driver := local.New(true)
 				imgStore = imagestore.NewImageStore(tdir, tdir, true, true, storageConstants.DefaultGCDelay,
 					storageConstants.DefaultUntaggedImgeRetentionDelay, true,
-					true, log, metrics, nil, driver, cacheDriver)
+					true, false, log, metrics, nil, driver, cacheDriver)
 			}
 
 			Convey("Setup manifest", t, func() {
@@ -1218,13 +1218,13 @@ func TestStorageHandler(t *testing.T) {
 
 				// Create ImageStore
 				firstStore = imagestore.NewImageStore(firstRootDir, firstRootDir, false, false, storageConstants.DefaultGCDelay,
-					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, driver, nil)
+					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, driver, nil)
 
 				secondStore = imagestore.NewImageStore(secondRootDir, secondRootDir, false, false, storageConstants.DefaultGCDelay,
-					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, driver, nil)
+					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, driver, nil)
 
 				thirdStore = imagestore.NewImageStore(thirdRootDir, thirdRootDir, false, false, storageConstants.DefaultGCDelay,
-					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, log, metrics, nil, driver, nil)
+					storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, driver, nil)
 			}
 
 			Convey("Test storage handler", t, func() {
@@ -1255,6 +1255,34 @@ func TestStorageHandler(t *testing.T) {
 	}
 }
 
+func TestCheckIsImageSignature(t *testing.T) {
+	Convey("Check the cosign signatures by their tags", t, func() {
+		manifestBlob, err := json.Marshal(ispec.Manifest{})
+		So(err, ShouldBeNil)
+
+		for _, algorithm := range []godigest.Algorithm{godigest.SHA256, godigest.SHA512} {
+			signedDigest := algorithm.FromString("image")
+
+			isSignature, signatureType, digest, err := storage.CheckIsImageSignature("repo", manifestBlob,
+				algorithm.String()+"-"+signedDigest.Encoded()+".sig")
+			So(err, ShouldBeNil)
+			So(isSignature, ShouldBeTrue)
+			So(signatureType, ShouldEqual, storage.CosignType)
+			So(digest, ShouldEqual, signedDigest)
+		}
+
+		// the digest isn't sliced out of a tag which doesn't name a valid digest
+		isSignature, _, _, err := storage.CheckIsImageSignature("repo", manifestBlob,
+			"sha256-zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz.sig")
+		So(err, ShouldBeNil)
+		So(isSignature, ShouldBeFalse)
+
+		isSignature, _, _, err = storage.CheckIsImageSignature("repo", manifestBlob, "sha256-short.sig")
+		So(err, ShouldBeNil)
+		So(isSignature, ShouldBeFalse)
+	})
+}
+
 func TestRoutePrefix(t *testing.T) {
 	Convey("Test route prefix", t, func() {
 		routePrefix := storage.GetRoutePrefix("test:latest")
@@ -1305,7 +1333,7 @@ func TestGarbageCollectImageManifest(t *testing.T) {
 						driver := local.New(true)
 
 						imgStore = imagestore.NewImageStore(dir, dir, true, true, storageConstants.DefaultGCDelay,
-							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, driver, cacheDriver)
+							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, driver, cacheDriver)
 					}
 
 					repoName := "gc-long"
@@ -1464,7 +1492,7 @@ func TestGarbageCollectImageManifest(t *testing.T) {
 
 						imgStore = imagestore.NewImageStore(dir, dir, true, true, gcDelay,
 							storageConstants.DefaultUntaggedImgeRetentionDelay, true,
-							true, log, metrics, nil, driver, cacheDriver)
+							true, false, log, metrics, nil, driver, cacheDriver)
 					}
 
 					// upload orphan blob
@@ -1764,7 +1792,7 @@ func TestGarbageCollectImageManifest(t *testing.T) {
 						driver := local.New(true)
 
 						imgStore = imagestore.NewImageStore(dir, dir, true, true, gcDelay,
-							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, driver, cacheDriver)
+							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, driver, cacheDriver)
 					}
 
 					// first upload an image to the first repo and wait for GC timeout
@@ -1996,7 +2024,7 @@ func TestGarbageCollectImageIndex(t *testing.T) {
 						driver := local.New(true)
 
 						imgStore = imagestore.NewImageStore(dir, dir, true, true, storageConstants.DefaultGCDelay,
-							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, log, metrics, nil, driver, cacheDriver)
+							storageConstants.DefaultUntaggedImgeRetentionDelay, true, true, false, log, metrics, nil, driver, cacheDriver)
 					}
 
 					repoName := "gc-long"
@@ -2121,7 +2149,7 @@ func TestGarbageCollectImageIndex(t *testing.T) {
 						driver := local.New(true)
 
 						imgStore = imagestore.NewImageStore(dir, dir, true, true, gcDelay,
-							imageRetentionDelay, true, true, log, metrics, nil, driver, cacheDriver)
+							imageRetentionDelay, true, true, false, log, metrics, nil, driver, cacheDriver)
 					}
 
 					// upload orphan blob
@@ -2432,7 +2460,7 @@ func TestGarbageCollectChainedImageIndexes(t *testing.T) {
 					driver := local.New(true)
 
 					imgStore = imagestore.NewImageStore(dir, dir, true, true, gcDelay,
-						imageRetentionDelay, true, true, log, metrics, nil, driver, cacheDriver)
+						imageRetentionDelay, true, true, false, log, metrics, nil, driver, cacheDriver)
 				}
 
 				// upload orphan blob
@@ -0,0 +1,336 @@
+package transcode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/klauspost/compress/zstd"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/meta"
+	mTypes "zotregistry.io/zot/pkg/meta/types"
+	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	// AnnotationCompression is set on the copies of the pushed manifests with the transcoded layers, their subject
+	// is the pushed manifest.
+	AnnotationCompression = "io.zotregistry.transcode.compression"
+	CompressionZstd       = "zstd"
+
+	DefaultZstdLevel = 3
+	// the layers shared by the images, e.g. their base image, are only transcoded once.
+	transcodedLayersCacheSize = 1000
+)
+
+// Transcoder stores a copy of the pushed images with their gzip layers transcoded to zstd, the copy is a referrer
+// of the pushed manifest so it's removed by the gc with it.
+type Transcoder struct {
+	config          *config.Config
+	storeController storage.StoreController
+	metaDB          mTypes.MetaDB
+	taskScheduler   atomic.Pointer[scheduler.Scheduler]
+	// the zstd layers of the gzip layers already transcoded, by the digest of the gzip layer
+	transcodedLayers *lru.Cache[godigest.Digest, ispec.Descriptor]
+	log              log.Logger
+}
+
+func NewTranscoder(config *config.Config, storeController storage.StoreController, metaDB mTypes.MetaDB,
+	log log.Logger,
+) *Transcoder {
+	transcodedLayers, _ := lru.New[godigest.Digest, ispec.Descriptor](transcodedLayersCacheSize)
+
+	return &Transcoder{
+		config:           config,
+		storeController:  storeController,
+		metaDB:           metaDB,
+		transcodedLayers: transcodedLayers,
+		log:              log,
+	}
+}
+
+// SetTaskScheduler sets the scheduler running the transcoding of the pushed images.
+func (transcoder *Transcoder) SetTaskScheduler(taskScheduler *scheduler.Scheduler) {
+	if transcoder == nil {
+		return
+	}
+
+	transcoder.taskScheduler.Store(taskScheduler)
+}
+
+// getConfig returns the transcoding config of the store of the repo, nil if it doesn't transcode the layers.
+func (transcoder *Transcoder) getConfig(repo string) *config.ZstdTranscodeConfig {
+	if subPath, ok := transcoder.config.Storage.SubPaths[storage.GetRoutePrefix(repo)]; ok {
+		return subPath.ZstdTranscode
+	}
+
+	return transcoder.config.Storage.ZstdTranscode
+}
+
+// OnManifestPushed transcodes the gzip layers of a pushed image in the background.
+func (transcoder *Transcoder) OnManifestPushed(repo string, subject ispec.Descriptor, body []byte) {
+	if transcoder == nil || transcoder.getConfig(repo) == nil || !hasGzipLayers(subject.MediaType, body) {
+		return
+	}
+
+	task := &transcodeTask{transcoder: transcoder, repo: repo, subject: subject, body: body}
+
+	if taskScheduler := transcoder.taskScheduler.Load(); taskScheduler != nil {
+		taskScheduler.SubmitTask(task, scheduler.LowPriority)
+
+		return
+	}
+
+	go func() {
+		_ = task.DoWork(context.Background())
+	}()
+}
+
+type transcodeTask struct {
+	transcoder *Transcoder
+	repo       string
+	subject    ispec.Descriptor
+	body       []byte
+}
+
+func (task *transcodeTask) DoWork(ctx context.Context) error {
+	_, err := task.transcoder.TranscodeManifest(task.repo, task.subject, task.body)
+	if err != nil {
+		task.transcoder.log.Error().Err(err).Str("repository", task.repo).
+			Str("digest", task.subject.Digest.String()).Msg("transcode: couldn't transcode the layers of the image")
+	}
+
+	return err
+}
+
+// hasGzipLayers returns true for the image manifests having gzip layers, the artifacts and the referrers, such as
+// our own copies of the images, aren't transcoded.
+func hasGzipLayers(mediaType string, body []byte) bool {
+	if mediaType != ispec.MediaTypeImageManifest {
+		return false
+	}
+
+	var manifest ispec.Manifest
+
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return false
+	}
+
+	if manifest.Subject != nil || manifest.ArtifactType != "" || manifest.Config.MediaType != ispec.MediaTypeImageConfig {
+		return false
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == ispec.MediaTypeImageLayerGzip {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TranscodeManifest stores the copy of an image manifest with its gzip layers transcoded to zstd, and returns its
+// digest. Nothing is stored for the images without gzip layers to transcode.
+func (transcoder *Transcoder) TranscodeManifest(repo string, subject ispec.Descriptor, body []byte,
+) (godigest.Digest, error) {
+	transcodeConfig := transcoder.getConfig(repo)
+	if transcodeConfig == nil || !hasGzipLayers(subject.MediaType, body) {
+		return "", nil
+	}
+
+	imgStore := transcoder.storeController.GetImageStore(repo)
+
+	// the image was already transcoded, e.g. pushed again with another tag
+	if _, digest, ok := transcoder.GetVariant(repo, subject.Digest); ok {
+		return digest, nil
+	}
+
+	var manifest ispec.Manifest
+
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return "", err
+	}
+
+	level := transcodeConfig.Level
+	if level == 0 {
+		level = DefaultZstdLevel
+	}
+
+	layers := make([]ispec.Descriptor, 0, len(manifest.Layers))
+	transcoded := 0
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != ispec.MediaTypeImageLayerGzip || layer.Size < transcodeConfig.MinLayerSize {
+			layers = append(layers, layer)
+
+			continue
+		}
+
+		zstdLayer, err := transcoder.transcodeLayer(imgStore, repo, layer, level)
+		if err != nil {
+			return "", err
+		}
+
+		layers = append(layers, zstdLayer)
+		transcoded++
+	}
+
+	if transcoded == 0 {
+		return "", nil
+	}
+
+	annotations := map[string]string{}
+	for key, value := range manifest.Annotations {
+		annotations[key] = value
+	}
+
+	annotations[AnnotationCompression] = CompressionZstd
+
+	manifest.Layers = layers
+	manifest.Annotations = annotations
+	manifest.Subject = &subject
+
+	manifestBlob, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	manifestDigest := godigest.FromBytes(manifestBlob)
+
+	_, _, err = imgStore.PutImageManifest(repo, manifestDigest.String(), ispec.MediaTypeImageManifest, manifestBlob)
+	if err != nil {
+		return "", err
+	}
+
+	if transcoder.metaDB != nil {
+		err = meta.OnUpdateManifest(repo, manifestDigest.String(), ispec.MediaTypeImageManifest, manifestDigest,
+			manifestBlob, transcoder.storeController, transcoder.metaDB, transcoder.log)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	transcoder.log.Info().Str("repository", repo).Str("subject", subject.Digest.String()).
+		Str("digest", manifestDigest.String()).Int("layers", transcoded).Msg("transcode: stored zstd image")
+
+	return manifestDigest, nil
+}
+
+// transcodeLayer stores the zstd layer of a gzip layer, streamed to the image store while it's compressed.
+func (transcoder *Transcoder) transcodeLayer(imgStore storageTypes.ImageStore, repo string,
+	layer ispec.Descriptor, level int,
+) (ispec.Descriptor, error) {
+	if zstdLayer, ok := transcoder.transcodedLayers.Get(layer.Digest); ok {
+		if found, _, err := imgStore.CheckBlob(repo, zstdLayer.Digest); err == nil && found {
+			zstdLayer.Annotations = layer.Annotations
+
+			return zstdLayer, nil
+		}
+	}
+
+	blob, _, err := imgStore.GetBlob(repo, layer.Digest, layer.MediaType)
+	if err != nil {
+		return ispec.Descriptor{}, err
+	}
+	defer blob.Close()
+
+	gzipReader, err := gzip.NewReader(blob)
+	if err != nil {
+		return ispec.Descriptor{}, err
+	}
+	defer gzipReader.Close()
+
+	uuid, err := imgStore.NewBlobUpload(repo)
+	if err != nil {
+		return ispec.Descriptor{}, err
+	}
+
+	digester := layer.Digest.Algorithm().Digester()
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		// a single goroutine compresses the same layer in the same zstd layer, shared by the repos with dedupe
+		encoder, err := zstd.NewWriter(io.MultiWriter(digester.Hash(), pipeWriter),
+			zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			pipeWriter.CloseWithError(err)
+
+			return
+		}
+
+		_, err = io.Copy(encoder, gzipReader)
+		if closeErr := encoder.Close(); err == nil {
+			err = closeErr
+		}
+
+		pipeWriter.CloseWithError(err)
+	}()
+
+	size, err := imgStore.PutBlobChunkStreamed(repo, uuid, pipeReader)
+	if err != nil {
+		pipeReader.CloseWithError(err)
+		_ = imgStore.DeleteBlobUpload(repo, uuid)
+
+		return ispec.Descriptor{}, err
+	}
+
+	zstdLayer := ispec.Descriptor{
+		MediaType: ispec.MediaTypeImageLayerZstd,
+		Digest:    digester.Digest(),
+		Size:      size,
+	}
+
+	if err := imgStore.FinishBlobUpload(repo, uuid, bytes.NewReader([]byte{}), zstdLayer.Digest); err != nil {
+		_ = imgStore.DeleteBlobUpload(repo, uuid)
+
+		return ispec.Descriptor{}, err
+	}
+
+	transcoder.transcodedLayers.Add(layer.Digest, zstdLayer)
+
+	zstdLayer.Annotations = layer.Annotations
+
+	return zstdLayer, nil
+}
+
+// GetVariant returns the copy of the manifest with the zstd layers, if the image was transcoded.
+func (transcoder *Transcoder) GetVariant(repo string, digest godigest.Digest) ([]byte, godigest.Digest, bool) {
+	if transcoder == nil {
+		return nil, "", false
+	}
+
+	imgStore := transcoder.storeController.GetImageStore(repo)
+
+	referrers, err := imgStore.GetReferrers(repo, digest, nil)
+	if err != nil {
+		return nil, "", false
+	}
+
+	for _, referrer := range referrers.Manifests {
+		if referrer.Annotations[AnnotationCompression] != CompressionZstd {
+			continue
+		}
+
+		content, variantDigest, _, err := imgStore.GetImageManifest(repo, referrer.Digest.String())
+		if err != nil {
+			transcoder.log.Error().Err(err).Str("repository", repo).Str("digest", referrer.Digest.String()).
+				Msg("transcode: couldn't read zstd image")
+
+			return nil, "", false
+		}
+
+		return content, variantDigest, true
+	}
+
+	return nil, "", false
+}
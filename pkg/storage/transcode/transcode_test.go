@@ -0,0 +1,339 @@
+package transcode_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/resty.v1"
+
+	"zotregistry.io/zot/pkg/api"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/api/constants"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	"zotregistry.io/zot/pkg/storage/local"
+	"zotregistry.io/zot/pkg/storage/transcode"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+func getTar(files map[string]string) []byte {
+	var buf bytes.Buffer
+
+	tarWriter := tar.NewWriter(&buf)
+
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		So(err, ShouldBeNil)
+
+		_, err = tarWriter.Write([]byte(content))
+		So(err, ShouldBeNil)
+	}
+
+	So(tarWriter.Close(), ShouldBeNil)
+
+	return buf.Bytes()
+}
+
+func getTarGzLayer(tarBlob []byte) Layer {
+	var buf bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buf)
+
+	_, err := gzipWriter.Write(tarBlob)
+	So(err, ShouldBeNil)
+	So(gzipWriter.Close(), ShouldBeNil)
+
+	return Layer{
+		Blob:      buf.Bytes(),
+		MediaType: ispec.MediaTypeImageLayerGzip,
+		Digest:    godigest.FromBytes(buf.Bytes()),
+	}
+}
+
+func decompressZstd(blob []byte) []byte {
+	decoder, err := zstd.NewReader(bytes.NewReader(blob))
+	So(err, ShouldBeNil)
+	defer decoder.Close()
+
+	content, err := io.ReadAll(decoder)
+	So(err, ShouldBeNil)
+
+	return content
+}
+
+func getManifest(baseURL, repo, reference string, acceptZstd bool) (*resty.Response, ispec.Manifest) {
+	var manifest ispec.Manifest
+
+	request := resty.R()
+	if acceptZstd {
+		request.SetHeader("Accept", ispec.MediaTypeImageManifest+", "+ispec.MediaTypeImageLayerZstd)
+	}
+
+	resp, err := request.Get(baseURL + "/v2/" + repo + "/manifests/" + reference)
+	So(err, ShouldBeNil)
+	So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+
+	err = json.Unmarshal(resp.Body(), &manifest)
+	So(err, ShouldBeNil)
+
+	return resp, manifest
+}
+
+func TestTranscodeOnPush(t *testing.T) {
+	Convey("Pull the zstd copy of a pushed image", t, func() {
+		port := test.GetFreePort()
+		baseURL := test.GetBaseURL(port)
+
+		conf := config.New()
+		conf.HTTP.Port = port
+		conf.Storage.RootDirectory = t.TempDir()
+		conf.Storage.ZstdTranscode = &config.ZstdTranscodeConfig{}
+
+		ctlr := api.NewController(conf)
+		cm := test.NewControllerManager(ctlr)
+		cm.StartAndWait(port)
+		defer cm.StopServer()
+
+		tarBlob := getTar(map[string]string{"etc/hostname": "zot\n"})
+		image := CreateImageWith().Layers([]Layer{getTarGzLayer(tarBlob)}).DefaultConfig().Build()
+
+		err := UploadImage(image, baseURL, "repo", "tag")
+		So(err, ShouldBeNil)
+
+		// the image is transcoded in the background after being pushed, the scheduler runs the tasks every 5 seconds
+		var resp *resty.Response
+
+		var manifest ispec.Manifest
+
+		for i := 0; i < 100; i++ {
+			resp, manifest = getManifest(baseURL, "repo", "tag", true)
+			if resp.Header().Get("Docker-Content-Digest") != image.DigestStr() {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		variantDigest := godigest.FromBytes(resp.Body())
+		So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, variantDigest.String())
+		So(manifest.Subject.Digest, ShouldEqual, image.Digest())
+		So(manifest.Annotations[transcode.AnnotationCompression], ShouldEqual, transcode.CompressionZstd)
+		So(manifest.Config, ShouldResemble, image.Manifest.Config)
+		So(manifest.Layers, ShouldHaveLength, 1)
+		So(manifest.Layers[0].MediaType, ShouldEqual, ispec.MediaTypeImageLayerZstd)
+
+		blobResp, err := resty.R().Get(baseURL + "/v2/repo/blobs/" + manifest.Layers[0].Digest.String())
+		So(err, ShouldBeNil)
+		So(blobResp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(len(blobResp.Body()), ShouldEqual, manifest.Layers[0].Size)
+		So(decompressZstd(blobResp.Body()), ShouldResemble, tarBlob)
+
+		// the tags are resolved to the zstd copy too
+		headResp, err := resty.R().SetHeader("Accept", ispec.MediaTypeImageLayerZstd).
+			Head(baseURL + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(headResp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(headResp.Header().Get("Docker-Content-Digest"), ShouldEqual, variantDigest.String())
+
+		// the clients which don't accept zstd layers pull the pushed image
+		resp, manifest = getManifest(baseURL, "repo", "tag", false)
+		So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, image.DigestStr())
+		So(manifest.Layers[0].MediaType, ShouldEqual, ispec.MediaTypeImageLayerGzip)
+
+		// unless they ask for the copy with the query parameter
+		resp, err = resty.R().SetQueryParam(constants.CompressionQueryParam, transcode.CompressionZstd).
+			Get(baseURL + "/v2/repo/manifests/tag")
+		So(err, ShouldBeNil)
+		So(resp.StatusCode(), ShouldEqual, http.StatusOK)
+		So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, variantDigest.String())
+
+		// the images pulled by digest are never changed
+		resp, _ = getManifest(baseURL, "repo", image.DigestStr(), true)
+		So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, image.DigestStr())
+
+		resp, _ = getManifest(baseURL, "repo", variantDigest.String(), false)
+		So(resp.Header().Get("Docker-Content-Digest"), ShouldEqual, variantDigest.String())
+	})
+}
+
+func TestTranscodeManifest(t *testing.T) {
+	Convey("Transcode the gzip layers of an image", t, func() {
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(t.TempDir(), false, false, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		conf := config.New()
+		conf.Storage.ZstdTranscode = &config.ZstdTranscodeConfig{Level: 19}
+
+		transcoder := transcode.NewTranscoder(conf, storeController, nil, log)
+
+		tarBlob := getTar(map[string]string{"etc/hostname": "zot\n"})
+		gzipLayer := getTarGzLayer(tarBlob)
+		plainLayer := Layer{
+			Blob:      tarBlob,
+			MediaType: ispec.MediaTypeImageLayer,
+			Digest:    godigest.FromBytes(tarBlob),
+		}
+
+		image := CreateImageWith().Layers([]Layer{gzipLayer, plainLayer}).DefaultConfig().Build()
+
+		err := test.WriteImageToFileSystem(image, "repo", "tag", storeController)
+		So(err, ShouldBeNil)
+
+		manifestBlob, err := json.Marshal(image.Manifest)
+		So(err, ShouldBeNil)
+
+		digest, err := transcoder.TranscodeManifest("repo", image.Descriptor(), manifestBlob)
+		So(err, ShouldBeNil)
+		So(digest, ShouldNotBeEmpty)
+
+		variant, variantDigest, ok := transcoder.GetVariant("repo", image.Digest())
+		So(ok, ShouldBeTrue)
+		So(variantDigest, ShouldEqual, digest)
+
+		var manifest ispec.Manifest
+
+		err = json.Unmarshal(variant, &manifest)
+		So(err, ShouldBeNil)
+		So(manifest.Layers[0].MediaType, ShouldEqual, ispec.MediaTypeImageLayerZstd)
+		// only the gzip layers are transcoded
+		So(manifest.Layers[1], ShouldResemble, image.Manifest.Layers[1])
+
+		zstdBlob, err := imgStore.GetBlobContent("repo", manifest.Layers[0].Digest)
+		So(err, ShouldBeNil)
+		So(decompressZstd(zstdBlob), ShouldResemble, tarBlob)
+
+		Convey("An image is transcoded once", func() {
+			again, err := transcoder.TranscodeManifest("repo", image.Descriptor(), manifestBlob)
+			So(err, ShouldBeNil)
+			So(again, ShouldEqual, digest)
+
+			// the layers shared with other images are transcoded once too
+			otherImage := CreateImageWith().Layers([]Layer{gzipLayer}).RandomConfig().Build()
+
+			err = test.WriteImageToFileSystem(otherImage, "repo", "other", storeController)
+			So(err, ShouldBeNil)
+
+			otherBlob, err := json.Marshal(otherImage.Manifest)
+			So(err, ShouldBeNil)
+
+			_, err = transcoder.TranscodeManifest("repo", otherImage.Descriptor(), otherBlob)
+			So(err, ShouldBeNil)
+
+			otherVariant, _, ok := transcoder.GetVariant("repo", otherImage.Digest())
+			So(ok, ShouldBeTrue)
+
+			var otherManifest ispec.Manifest
+
+			err = json.Unmarshal(otherVariant, &otherManifest)
+			So(err, ShouldBeNil)
+			So(otherManifest.Layers[0], ShouldResemble, manifest.Layers[0])
+		})
+
+		Convey("The small layers, artifacts and referrers aren't transcoded", func() {
+			conf.Storage.ZstdTranscode.MinLayerSize = 1 << 20
+
+			otherImage := CreateImageWith().Layers([]Layer{gzipLayer}).RandomConfig().Build()
+
+			err := test.WriteImageToFileSystem(otherImage, "repo", "small", storeController)
+			So(err, ShouldBeNil)
+
+			otherBlob, err := json.Marshal(otherImage.Manifest)
+			So(err, ShouldBeNil)
+
+			digest, err := transcoder.TranscodeManifest("repo", otherImage.Descriptor(), otherBlob)
+			So(err, ShouldBeNil)
+			So(digest, ShouldBeEmpty)
+
+			_, _, ok := transcoder.GetVariant("repo", otherImage.Digest())
+			So(ok, ShouldBeFalse)
+
+			conf.Storage.ZstdTranscode.MinLayerSize = 0
+
+			artifact := CreateImageWith().Layers([]Layer{gzipLayer}).RandomConfig().
+				ArtifactType("application/vnd.test").Build()
+
+			artifactBlob, err := json.Marshal(artifact.Manifest)
+			So(err, ShouldBeNil)
+
+			digest, err = transcoder.TranscodeManifest("repo", artifact.Descriptor(), artifactBlob)
+			So(err, ShouldBeNil)
+			So(digest, ShouldBeEmpty)
+
+			digest, err = transcoder.TranscodeManifest("repo", ispec.Descriptor{
+				MediaType: ispec.MediaTypeImageManifest, Digest: digest,
+			}, variant)
+			So(err, ShouldBeNil)
+			So(digest, ShouldBeEmpty)
+
+			digest, err = transcoder.TranscodeManifest("repo", ispec.Descriptor{
+				MediaType: ispec.MediaTypeImageIndex,
+			}, []byte("{}"))
+			So(err, ShouldBeNil)
+			So(digest, ShouldBeEmpty)
+		})
+
+		Convey("The stores without the config don't transcode", func() {
+			conf.Storage.SubPaths = map[string]config.StorageConfig{"/a": {}}
+
+			digest, err := transcoder.TranscodeManifest("a/repo", image.Descriptor(), manifestBlob)
+			So(err, ShouldBeNil)
+			So(digest, ShouldBeEmpty)
+		})
+
+		Convey("The layers which aren't gzip archives can't be transcoded", func() {
+			badLayer := Layer{
+				Blob:      []byte("not gzip"),
+				MediaType: ispec.MediaTypeImageLayerGzip,
+				Digest:    godigest.FromString("not gzip"),
+			}
+			badImage := CreateImageWith().Layers([]Layer{badLayer}).RandomConfig().Build()
+
+			err := test.WriteImageToFileSystem(badImage, "repo", "bad", storeController)
+			So(err, ShouldBeNil)
+
+			badBlob, err := json.Marshal(badImage.Manifest)
+			So(err, ShouldBeNil)
+
+			_, err = transcoder.TranscodeManifest("repo", badImage.Descriptor(), badBlob)
+			So(err, ShouldNotBeNil)
+
+			truncatedLayer := Layer{
+				Blob:      gzipLayer.Blob[:len(gzipLayer.Blob)-10],
+				MediaType: ispec.MediaTypeImageLayerGzip,
+				Digest:    godigest.FromBytes(gzipLayer.Blob[:len(gzipLayer.Blob)-10]),
+			}
+			truncatedImage := CreateImageWith().Layers([]Layer{truncatedLayer}).RandomConfig().Build()
+
+			err = test.WriteImageToFileSystem(truncatedImage, "repo", "truncated", storeController)
+			So(err, ShouldBeNil)
+
+			truncatedBlob, err := json.Marshal(truncatedImage.Manifest)
+			So(err, ShouldBeNil)
+
+			_, err = transcoder.TranscodeManifest("repo", truncatedImage.Descriptor(), truncatedBlob)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
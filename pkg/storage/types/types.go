@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"io"
 	"time"
 
@@ -15,10 +16,20 @@ import (
 type ImageStore interface { //nolint:interfacebloat
 	DirExists(d string) bool
 	RootDir() string
+	IsReadOnly() bool
 	RLock(*time.Time)
 	RUnlock(*time.Time)
 	Lock(*time.Time)
 	Unlock(*time.Time)
+	RLockRepo(repo string, lockStart *time.Time)
+	RUnlockRepo(repo string, lockStart *time.Time)
+	LockRepo(repo string, lockStart *time.Time) error
+	UnlockRepo(repo string, lockStart *time.Time)
+	SetRepoLocker(locker RepoLocker)
+	SetQuotaEnforcer(enforcer QuotaEnforcer)
+	SetTagPolicy(policy TagPolicy)
+	SetReferenceCounter(counter ReferenceCounter)
+	SetChunkDedupe(minBlobSize int64, averageChunkSize int)
 	InitRepo(name string) error
 	ValidateRepo(name string) (bool, error)
 	GetRepositories() ([]string, error)
@@ -37,6 +48,7 @@ type ImageStore interface { //nolint:interfacebloat
 	FullBlobUpload(repo string, body io.Reader, digest godigest.Digest) (string, int64, error)
 	DedupeBlob(src string, dstDigest godigest.Digest, dst string) error
 	DeleteBlobUpload(repo, uuid string) error
+	ExpireBlobUploads(repo string, ttl time.Duration) (int, error)
 	BlobPath(repo string, digest godigest.Digest) string
 	CheckBlob(repo string, digest godigest.Digest) (bool, int64, error)
 	StatBlob(repo string, digest godigest.Digest) (bool, int64, time.Time, error)
@@ -44,16 +56,91 @@ type ImageStore interface { //nolint:interfacebloat
 	GetBlobPartial(repo string, digest godigest.Digest, mediaType string, from, to int64,
 	) (io.ReadCloser, int64, int64, error)
 	DeleteBlob(repo string, digest godigest.Digest) error
+	DeleteRepo(repo string) error
 	GetIndexContent(repo string) ([]byte, error)
 	GetBlobContent(repo string, digest godigest.Digest) ([]byte, error)
 	GetReferrers(repo string, digest godigest.Digest, artifactTypes []string) (ispec.Index, error)
 	GetOrasReferrers(repo string, digest godigest.Digest, artifactType string) ([]artifactspec.Descriptor, error)
 	RunGCRepo(repo string) error
+	RunGCRepoReport(repo string, dryRun bool) (GCReport, error)
 	RunGCPeriodically(interval time.Duration, sch *scheduler.Scheduler)
 	RunDedupeBlobs(interval time.Duration, sch *scheduler.Scheduler)
 	RunDedupeForDigest(digest godigest.Digest, dedupe bool, duplicateBlobs []string) error
+	RunGCDedupedBlobs() error
 	GetNextDigestWithBlobPaths(lastDigests []godigest.Digest) (godigest.Digest, []string, error)
-	GetAllBlobs(repo string) ([]string, error)
+	GetAllBlobs(repo string) ([]godigest.Digest, error)
+	Ping() error
+	Close() error
+}
+
+// RepoLocker locks the repositories across the zot instances sharing the storage, e.g. the members of a cluster.
+type RepoLocker interface {
+	// LockRepo takes the lock of the repository, the write is denied if it returns an error.
+	LockRepo(repo string) error
+	UnlockRepo(repo string)
+	// CheckRepo returns an error if the lock of the repository was lost while it was held, the write is aborted.
+	CheckRepo(repo string) error
+	// LockDedupe takes the lock of the deduped blobs, shared by all the repositories, while the repository is locked.
+	LockDedupe() error
+	UnlockDedupe()
+}
+
+// QuotaEnforcer tracks the size of the repositories, the image stores deny the writes going over their quotas.
+type QuotaEnforcer interface {
+	// CheckQuota returns an error wrapping zerr.ErrQuotaExceeded if the repository can't grow by size bytes.
+	CheckQuota(repo string, size int64) error
+	// UpdateUsage changes the size of the repository by delta bytes, negative when its content is removed.
+	UpdateUsage(repo string, delta int64)
+}
+
+// TagPolicy tells the image stores which tags can be pushed again with other manifests.
+type TagPolicy interface {
+	IsTagMutable(repo, tag string) bool
+}
+
+// ReferenceCounter keeps the count of the references of the manifests to the blobs of the repositories, the garbage
+// collection then only checks the blobs which aren't referenced instead of reading all the manifests.
+type ReferenceCounter interface {
+	// IsTracked tells if the references of the repository are counted, they are once Track is called.
+	IsTracked(repo string) (bool, error)
+	// Track replaces the references of the repository by those of its manifests, the other blobs are unreferenced.
+	Track(repo string, manifests map[godigest.Digest][]godigest.Digest, blobs []godigest.Digest) error
+	// AddManifest counts the references of a manifest added to the repository, only once for each manifest.
+	AddManifest(repo string, manifest godigest.Digest, refs []godigest.Digest) error
+	// HasManifest tells if the references of a manifest of the repository are counted.
+	HasManifest(repo string, manifest godigest.Digest) (bool, error)
+	// AddBlob adds a blob to the repository, it's unreferenced until a manifest references it.
+	AddBlob(repo string, digest godigest.Digest) error
+	// RemoveBlob removes a blob from the repository, with the references of the manifest it holds.
+	RemoveBlob(repo string, digest godigest.Digest) error
+	// Unreferenced returns the blobs of the repository which aren't referenced by its manifests, the manifests of
+	// its index included.
+	Unreferenced(repo string) ([]godigest.Digest, error)
+	// RemoveRepo forgets the references of the repository.
+	RemoveRepo(repo string) error
+}
+
+// GCReport lists the manifests and blobs removed by a garbage collection of a repository, or which would be
+// removed by a dry run.
+type GCReport struct {
+	Repo      string         `json:"repo"`
+	Manifests []GCReportItem `json:"manifests"`
+	Blobs     []GCReportItem `json:"blobs"`
+	// BytesFreed is the size of the manifests and blobs, the deduped blobs are counted with the size of their content.
+	BytesFreed int64 `json:"bytesFreed"`
+	// RepoRemoved is set when all the blobs of the repository are removed, with the repository.
+	RepoRemoved bool `json:"repoRemoved"`
+}
+
+// IsEmpty tells if the garbage collection didn't remove anything.
+func (report GCReport) IsEmpty() bool {
+	return len(report.Manifests) == 0 && len(report.Blobs) == 0 && !report.RepoRemoved
+}
+
+// GCReportItem is a manifest or a blob removed by a garbage collection.
+type GCReportItem struct {
+	Digest godigest.Digest `json:"digest"`
+	Size   int64           `json:"size"`
 }
 
 type Driver interface { //nolint:interfacebloat
@@ -72,3 +159,8 @@ type Driver interface { //nolint:interfacebloat
 	SameFile(path1, path2 string) bool
 	Link(src, dest string) error
 }
+
+// BlobSource returns blobs from outside of the image stores, e.g. from the upstream registries of the synced repos.
+type BlobSource interface {
+	GetBlob(ctx context.Context, repo string, digest godigest.Digest) (io.ReadCloser, error)
+}
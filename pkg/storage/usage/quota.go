@@ -0,0 +1,158 @@
+package usage
+
+import (
+	"strconv"
+	"sync"
+
+	glob "github.com/bmatcuk/doublestar/v4"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+/*
+QuotaEnforcer denies the pushes which would get a repo over its quota, or all the repos over the max size of the
+storage. The usage is computed from the blobs of the repos once when zot starts, then kept up to date by the image
+stores as the blobs and manifests are pushed and deleted.
+
+The usage is tracked by each zot instance, the instances sharing a storage don't see the pushes of each other
+until they restart, and the concurrent pushes are checked separately, so a quota can be exceeded by a little.
+*/
+type QuotaEnforcer struct {
+	config     *config.QuotasConfig
+	repoUsage  map[string]int64
+	totalUsage int64
+	lock       *sync.Mutex
+	log        log.Logger
+}
+
+// NewQuotaEnforcer returns an enforcer of the quotas with the current usage of the repos of the image stores.
+func NewQuotaEnforcer(conf *config.QuotasConfig, storeController storage.StoreController, log log.Logger,
+) *QuotaEnforcer {
+	enforcer := &QuotaEnforcer{
+		config:    conf,
+		repoUsage: map[string]int64{},
+		lock:      &sync.Mutex{},
+		log:       log,
+	}
+
+	imgStores := []storageTypes.ImageStore{storeController.DefaultStore}
+
+	for _, imgStore := range storeController.SubStore {
+		imgStores = append(imgStores, imgStore)
+	}
+
+	for _, imgStore := range imgStores {
+		if imgStore != nil {
+			enforcer.computeUsage(imgStore)
+		}
+	}
+
+	return enforcer
+}
+
+// SetQuotaEnforcer enforces the quotas in the image stores.
+func (enforcer *QuotaEnforcer) SetQuotaEnforcer(storeController storage.StoreController) {
+	if storeController.DefaultStore != nil {
+		storeController.DefaultStore.SetQuotaEnforcer(enforcer)
+	}
+
+	for _, imgStore := range storeController.SubStore {
+		imgStore.SetQuotaEnforcer(enforcer)
+	}
+}
+
+func (enforcer *QuotaEnforcer) computeUsage(imgStore storageTypes.ImageStore) {
+	repos, err := imgStore.GetRepositories()
+	if err != nil {
+		enforcer.log.Error().Err(err).Str("rootDir", imgStore.RootDir()).Msg("usage: unable to list the repos")
+
+		return
+	}
+
+	for _, repo := range repos {
+		blobs, err := imgStore.GetAllBlobs(repo)
+		if err != nil {
+			enforcer.log.Error().Err(err).Str("repository", repo).Msg("usage: unable to list the blobs of the repo")
+
+			continue
+		}
+
+		var size int64
+
+		for _, digest := range blobs {
+			if ok, blobSize, _, err := imgStore.StatBlob(repo, digest); err == nil && ok {
+				size += blobSize
+			}
+		}
+
+		enforcer.repoUsage[repo] += size
+		enforcer.totalUsage += size
+	}
+}
+
+// CheckQuota returns zerr.ErrQuotaExceeded if adding size bytes to the repo would get it over a quota.
+func (enforcer *QuotaEnforcer) CheckQuota(repo string, size int64) error {
+	enforcer.lock.Lock()
+	defer enforcer.lock.Unlock()
+
+	if quota := getRepoQuota(enforcer.config.Repos, repo); quota > 0 && enforcer.repoUsage[repo]+size > quota {
+		return zerr.NewError(zerr.ErrQuotaExceeded).AddDetail("repository", repo).
+			AddDetail("quota", strconv.FormatInt(quota, 10)).
+			AddDetail("usage", strconv.FormatInt(enforcer.repoUsage[repo], 10))
+	}
+
+	if maxSize := enforcer.config.MaxSize; maxSize > 0 && enforcer.totalUsage+size > maxSize {
+		return zerr.NewError(zerr.ErrQuotaExceeded).AddDetail("quota", strconv.FormatInt(maxSize, 10)).
+			AddDetail("usage", strconv.FormatInt(enforcer.totalUsage, 10))
+	}
+
+	return nil
+}
+
+// UpdateUsage adds delta bytes, negative if the repo got smaller, to the usage of the repo.
+func (enforcer *QuotaEnforcer) UpdateUsage(repo string, delta int64) {
+	enforcer.lock.Lock()
+	defer enforcer.lock.Unlock()
+
+	usage := enforcer.repoUsage[repo] + delta
+	if usage < 0 {
+		delta -= usage
+		usage = 0
+	}
+
+	if usage == 0 {
+		delete(enforcer.repoUsage, repo)
+	} else {
+		enforcer.repoUsage[repo] = usage
+	}
+
+	enforcer.totalUsage += delta
+	if enforcer.totalUsage < 0 {
+		enforcer.totalUsage = 0
+	}
+}
+
+// Usage returns the usage of the repo, and of all the repos.
+func (enforcer *QuotaEnforcer) Usage(repo string) (int64, int64) {
+	enforcer.lock.Lock()
+	defer enforcer.lock.Unlock()
+
+	return enforcer.repoUsage[repo], enforcer.totalUsage
+}
+
+// getRepoQuota returns the max size of the first quota matching the repo, 0 if none matches.
+func getRepoQuota(quotas []config.RepoQuota, repo string) int64 {
+	for _, quota := range quotas {
+		for _, pattern := range quota.Repos {
+			if matched, _ := glob.Match(pattern, repo); matched {
+				return quota.MaxSize
+			}
+		}
+	}
+
+	return 0
+}
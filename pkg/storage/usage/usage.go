@@ -0,0 +1,270 @@
+// Package usage checks the usage of the local storage, so the operators are warned before the repos get over
+// their quotas or the storage runs out of space, and enforces the quotas on the pushes.
+package usage
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/scheduler"
+	"zotregistry.io/zot/pkg/storage"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
+)
+
+const (
+	DefaultInterval = 5 * time.Minute
+	percent         = 100
+)
+
+// DefaultThresholds are the percentages of the repo quotas the alerts are sent at if not configured.
+func DefaultThresholds() []int {
+	return []int{80, 95} //nolint: gomnd
+}
+
+// Notifier publishes the usage alerts, e.g. the events extension.
+type Notifier interface {
+	RepoQuotaThresholdCrossed(repo string, size, quota int64, threshold int)
+	StorageFreeSpaceLow(rootDir string, free, minFree int64)
+}
+
+/*
+Checker compares the size of the repos to their quotas, and the free space of the storage root directories
+to the minimum free space. An alert is sent when a repo crosses a threshold upwards, or when the free space
+drops below the minimum, so that the alerts aren't repeated at every check. They are sent again if the usage
+goes down then up again.
+
+Only the local storage is checked, the remote storage drivers don't report their free space.
+*/
+type Checker struct {
+	config          *config.UsageAlertsConfig
+	thresholds      []int
+	imgStores       []storageTypes.ImageStore
+	metrics         monitoring.MetricServer
+	notifier        Notifier
+	repoThresholds  map[string]int // the highest threshold crossed by each repo
+	lowSpaceStorage map[string]bool
+	lock            *sync.Mutex
+	log             log.Logger
+}
+
+// NewChecker returns a checker of the local image stores, the notifier is optional.
+func NewChecker(conf *config.Config, storeController storage.StoreController, metrics monitoring.MetricServer,
+	notifier Notifier, log log.Logger,
+) *Checker {
+	imgStores := []storageTypes.ImageStore{}
+
+	if conf.Storage.StorageDriver == nil {
+		imgStores = append(imgStores, storeController.DefaultStore)
+	}
+
+	for route, subPath := range conf.Storage.SubPaths {
+		if subPath.StorageDriver == nil && storeController.SubStore[route] != nil {
+			imgStores = append(imgStores, storeController.SubStore[route])
+		}
+	}
+
+	thresholds := conf.Storage.UsageAlerts.Thresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultThresholds()
+	}
+
+	return &Checker{
+		config:          conf.Storage.UsageAlerts,
+		thresholds:      thresholds,
+		imgStores:       imgStores,
+		metrics:         metrics,
+		notifier:        notifier,
+		repoThresholds:  map[string]int{},
+		lowSpaceStorage: map[string]bool{},
+		lock:            &sync.Mutex{},
+		log:             log,
+	}
+}
+
+// RunPeriodically checks the usage at the configured interval.
+func (checker *Checker) RunPeriodically(sch *scheduler.Scheduler) {
+	interval := checker.config.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	checker.log.Info().Dur("interval", interval).Msg("usage: submitting storage usage checks")
+
+	sch.SubmitSharedGenerator("usage-alerts", &taskGenerator{checker: checker}, interval, scheduler.LowPriority)
+}
+
+// Check checks the usage of every local image store once.
+func (checker *Checker) Check(ctx context.Context) error {
+	checker.lock.Lock()
+	defer checker.lock.Unlock()
+
+	for _, imgStore := range checker.imgStores {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		checker.checkRepos(imgStore)
+		checker.checkFreeSpace(imgStore.RootDir())
+	}
+
+	return nil
+}
+
+func (checker *Checker) checkRepos(imgStore storageTypes.ImageStore) {
+	if len(checker.config.Quotas) == 0 {
+		return
+	}
+
+	repos, err := imgStore.GetRepositories()
+	if err != nil {
+		checker.log.Error().Err(err).Str("rootDir", imgStore.RootDir()).Msg("usage: unable to list the repos")
+
+		return
+	}
+
+	for _, repo := range repos {
+		quota := getRepoQuota(checker.config.Quotas, repo)
+		if quota == 0 {
+			continue
+		}
+
+		size, err := getDirSize(path.Join(imgStore.RootDir(), repo))
+		if err != nil {
+			checker.log.Error().Err(err).Str("repository", repo).Msg("usage: unable to get the size of the repo")
+
+			continue
+		}
+
+		monitoring.SetRepoQuotaUsage(checker.metrics, repo, float64(size)/float64(quota))
+
+		crossed := 0
+
+		for _, threshold := range checker.thresholds {
+			if size*percent >= quota*int64(threshold) && threshold > crossed {
+				crossed = threshold
+			}
+		}
+
+		if crossed > checker.repoThresholds[repo] {
+			checker.log.Warn().Str("repository", repo).Int64("size", size).Int64("quota", quota).
+				Int("threshold", crossed).Msg("usage: repo crossed a threshold of its quota")
+
+			if checker.notifier != nil {
+				checker.notifier.RepoQuotaThresholdCrossed(repo, size, quota, crossed)
+			}
+		}
+
+		checker.repoThresholds[repo] = crossed
+	}
+}
+
+func (checker *Checker) checkFreeSpace(rootDir string) {
+	free, err := getFreeSpace(rootDir)
+	if err != nil {
+		checker.log.Error().Err(err).Str("rootDir", rootDir).Msg("usage: unable to get the free space of the storage")
+
+		return
+	}
+
+	monitoring.SetStorageFreeBytes(checker.metrics, rootDir, free)
+
+	if checker.config.MinFreeSpace == 0 {
+		return
+	}
+
+	isLow := free < checker.config.MinFreeSpace
+
+	if isLow && !checker.lowSpaceStorage[rootDir] {
+		checker.log.Warn().Str("rootDir", rootDir).Int64("free", free).Int64("minFree", checker.config.MinFreeSpace).
+			Msg("usage: free space of the storage dropped below the minimum")
+
+		if checker.notifier != nil {
+			checker.notifier.StorageFreeSpaceLow(rootDir, free, checker.config.MinFreeSpace)
+		}
+	}
+
+	checker.lowSpaceStorage[rootDir] = isLow
+}
+
+func getDirSize(dir string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+// getFreeSpace returns the space available to unprivileged users on the file system of the directory.
+func getFreeSpace(dir string) (int64, error) {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+
+	return int64(stat.Bavail) * int64(stat.Bsize), nil //nolint: unconvert // the field types depend on the OS
+}
+
+type taskGenerator struct {
+	checker   *Checker
+	generated bool
+	done      bool
+}
+
+func (gen *taskGenerator) Next() (scheduler.Task, error) {
+	if gen.generated {
+		gen.done = true
+
+		return nil, nil
+	}
+
+	gen.generated = true
+
+	return &task{checker: gen.checker}, nil
+}
+
+func (gen *taskGenerator) IsDone() bool {
+	return gen.done
+}
+
+func (gen *taskGenerator) IsReady() bool {
+	return true
+}
+
+func (gen *taskGenerator) Reset() {
+	gen.generated = false
+	gen.done = false
+}
+
+type task struct {
+	checker *Checker
+}
+
+func (task *task) DoWork(ctx context.Context) error {
+	return task.checker.Check(ctx)
+}
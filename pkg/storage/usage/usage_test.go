@@ -0,0 +1,317 @@
+package usage_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ispec "github.com/opencontainers/image-spec/specs-go/v1"
+	. "github.com/smartystreets/goconvey/convey"
+
+	zerr "zotregistry.io/zot/errors"
+	"zotregistry.io/zot/pkg/api/config"
+	"zotregistry.io/zot/pkg/extensions/monitoring"
+	"zotregistry.io/zot/pkg/log"
+	"zotregistry.io/zot/pkg/storage"
+	"zotregistry.io/zot/pkg/storage/cache"
+	storageConstants "zotregistry.io/zot/pkg/storage/constants"
+	"zotregistry.io/zot/pkg/storage/local"
+	"zotregistry.io/zot/pkg/storage/usage"
+	"zotregistry.io/zot/pkg/test"
+	. "zotregistry.io/zot/pkg/test/image-utils"
+)
+
+type quotaAlert struct {
+	repo      string
+	threshold int
+}
+
+type notifierMock struct {
+	quotaAlerts     []quotaAlert
+	freeSpaceAlerts []string
+}
+
+func (notifier *notifierMock) RepoQuotaThresholdCrossed(repo string, size, quota int64, threshold int) {
+	notifier.quotaAlerts = append(notifier.quotaAlerts, quotaAlert{repo, threshold})
+}
+
+func (notifier *notifierMock) StorageFreeSpaceLow(rootDir string, free, minFree int64) {
+	notifier.freeSpaceAlerts = append(notifier.freeSpaceAlerts, rootDir)
+}
+
+func TestUsageAlerts(t *testing.T) {
+	Convey("Alerts are sent when the usage crosses the thresholds", t, func() {
+		rootDir := t.TempDir()
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
+
+		for _, repo := range []string{"big", "small", "other"} {
+			err := imgStore.InitRepo(repo)
+			So(err, ShouldBeNil)
+		}
+
+		conf := config.New()
+		conf.Storage.RootDirectory = rootDir
+		conf.Storage.UsageAlerts = &config.UsageAlertsConfig{
+			Quotas: []config.RepoQuota{
+				{Repos: []string{"big", "small"}, MaxSize: 10000},
+			},
+			MinFreeSpace: math.MaxInt64,
+		}
+
+		notifier := &notifierMock{}
+		checker := usage.NewChecker(conf, storage.StoreController{DefaultStore: imgStore}, metrics, notifier, log)
+
+		writeFile := func(size int) {
+			err := os.WriteFile(path.Join(rootDir, "big", "data"), make([]byte, size), 0o600)
+			So(err, ShouldBeNil)
+		}
+
+		err := checker.Check(context.Background())
+		So(err, ShouldBeNil)
+		So(notifier.quotaAlerts, ShouldBeEmpty)
+		So(notifier.freeSpaceAlerts, ShouldResemble, []string{rootDir})
+
+		writeFile(8500)
+
+		err = checker.Check(context.Background())
+		So(err, ShouldBeNil)
+		So(notifier.quotaAlerts, ShouldResemble, []quotaAlert{{"big", 80}})
+
+		// the alerts aren't repeated while the usage stays over the threshold
+		err = checker.Check(context.Background())
+		So(err, ShouldBeNil)
+		So(notifier.quotaAlerts, ShouldResemble, []quotaAlert{{"big", 80}})
+		So(notifier.freeSpaceAlerts, ShouldResemble, []string{rootDir})
+
+		writeFile(9600)
+
+		err = checker.Check(context.Background())
+		So(err, ShouldBeNil)
+		So(notifier.quotaAlerts, ShouldResemble, []quotaAlert{{"big", 80}, {"big", 95}})
+
+		// the alerts are sent again when the usage goes down then up
+		writeFile(0)
+
+		err = checker.Check(context.Background())
+		So(err, ShouldBeNil)
+
+		writeFile(8500)
+
+		err = checker.Check(context.Background())
+		So(err, ShouldBeNil)
+		So(notifier.quotaAlerts, ShouldResemble, []quotaAlert{{"big", 80}, {"big", 95}, {"big", 80}})
+
+		err = checker.Check(context.Background())
+		So(err, ShouldBeNil)
+		So(notifier.freeSpaceAlerts, ShouldResemble, []string{rootDir})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = checker.Check(ctx)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestQuotaEnforcer(t *testing.T) {
+	Convey("Pushes over the quotas are denied", t, func() {
+		rootDir := t.TempDir()
+		log := log.NewLogger("debug", "")
+		metrics := monitoring.NewMetricsServer(false, log)
+
+		imgStore := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
+			storageConstants.DefaultUntaggedImgeRetentionDelay, false, false, false, log, metrics, nil, nil)
+		storeController := storage.StoreController{DefaultStore: imgStore}
+
+		err := test.WriteImageToFileSystem(CreateRandomImage(), "existing", "1.0", storeController)
+		So(err, ShouldBeNil)
+
+		err = imgStore.InitRepo("limited")
+		So(err, ShouldBeNil)
+
+		newBlob := func(size int) ([]byte, godigest.Digest) {
+			blob := make([]byte, size)
+			_, err := rand.Read(blob)
+			So(err, ShouldBeNil)
+
+			return blob, godigest.FromBytes(blob)
+		}
+
+		conf := &config.QuotasConfig{
+			Repos: []config.RepoQuota{{Repos: []string{"limited"}, MaxSize: 1000}},
+		}
+
+		enforcer := usage.NewQuotaEnforcer(conf, storeController, log)
+		enforcer.SetQuotaEnforcer(storeController)
+
+		// the usage of the repos pushed before the start is computed
+		existingUsage, totalUsage := enforcer.Usage("existing")
+		So(existingUsage, ShouldBeGreaterThan, 0)
+		So(totalUsage, ShouldEqual, existingUsage)
+
+		Convey("Blobs over the quota of the repo", func() {
+			blob, digest := newBlob(600)
+
+			_, _, err := imgStore.FullBlobUpload("limited", bytes.NewReader(blob), digest)
+			So(err, ShouldBeNil)
+
+			repoUsage, _ := enforcer.Usage("limited")
+			So(repoUsage, ShouldEqual, 600)
+
+			// the blobs already in the repo don't count twice
+			_, _, err = imgStore.FullBlobUpload("limited", bytes.NewReader(blob), digest)
+			So(err, ShouldBeNil)
+
+			repoUsage, _ = enforcer.Usage("limited")
+			So(repoUsage, ShouldEqual, 600)
+
+			deniedBlob, deniedDigest := newBlob(600)
+
+			_, _, err = imgStore.FullBlobUpload("limited", bytes.NewReader(deniedBlob), deniedDigest)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			uploads, err := os.ReadDir(path.Join(rootDir, "limited", storageConstants.BlobUploadDir))
+			So(err, ShouldBeNil)
+			So(uploads, ShouldBeEmpty)
+
+			uuid, err := imgStore.NewBlobUpload("limited")
+			So(err, ShouldBeNil)
+
+			_, err = imgStore.PutBlobChunk("limited", uuid, 0, 599, bytes.NewReader(deniedBlob))
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			// the usage goes down when the blobs are deleted
+			err = imgStore.DeleteBlob("limited", digest)
+			So(err, ShouldBeNil)
+
+			repoUsage, _ = enforcer.Usage("limited")
+			So(repoUsage, ShouldEqual, 0)
+
+			_, _, err = imgStore.FullBlobUpload("limited", bytes.NewReader(deniedBlob), deniedDigest)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Manifests over the quota of the repo", func() {
+			image := CreateRandomImage()
+
+			err := test.WriteImageToFileSystem(image, "limited", "1.0", storeController)
+			So(err, ShouldBeNil)
+
+			repoUsage, _ := enforcer.Usage("limited")
+			So(repoUsage, ShouldBeGreaterThan, image.ManifestDescriptor.Size)
+
+			// fill the quota of the repo
+			enforcer.UpdateUsage("limited", conf.Repos[0].MaxSize-repoUsage)
+
+			manifest := image.Manifest
+			manifest.Annotations = map[string]string{"key": "value"}
+
+			manifestBlob, err := json.Marshal(manifest)
+			So(err, ShouldBeNil)
+
+			_, _, err = imgStore.PutImageManifest("limited", "2.0", ispec.MediaTypeImageManifest, manifestBlob)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			// the manifests already in the repo are only tagged
+			_, _, err = imgStore.PutImageManifest("limited", "1.1", ispec.MediaTypeImageManifest,
+				image.ManifestDescriptor.Data)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Blobs mounted from the other repos over the quota of the repo", func() {
+			cacheDriver, err := storage.Create("boltdb", cache.BoltDBDriverParameters{
+				RootDir:     rootDir,
+				Name:        "cache",
+				UseRelPaths: true,
+			}, log)
+			So(err, ShouldBeNil)
+
+			imgStore := local.NewImageStore(rootDir, false, false, storageConstants.DefaultGCDelay,
+				storageConstants.DefaultUntaggedImgeRetentionDelay, true, false, false, log, metrics, nil, cacheDriver)
+			storeController := storage.StoreController{DefaultStore: imgStore}
+			enforcer.SetQuotaEnforcer(storeController)
+
+			blob, digest := newBlob(600)
+
+			_, _, err = imgStore.FullBlobUpload("existing", bytes.NewReader(blob), digest)
+			So(err, ShouldBeNil)
+
+			// fill the quota of the repo
+			repoUsage, _ := enforcer.Usage("limited")
+			enforcer.UpdateUsage("limited", conf.Repos[0].MaxSize-repoUsage)
+
+			_, _, err = imgStore.CheckBlob("limited", digest)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			_, err = os.Stat(imgStore.BlobPath("limited", digest))
+			So(os.IsNotExist(err), ShouldBeTrue)
+
+			repoUsage, _ = enforcer.Usage("limited")
+			So(repoUsage, ShouldEqual, conf.Repos[0].MaxSize)
+
+			// the repos under their quotas mount the blob
+			ok, size, err := imgStore.CheckBlob("other", digest)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(size, ShouldEqual, 600)
+
+			repoUsage, _ = enforcer.Usage("other")
+			So(repoUsage, ShouldEqual, 600)
+
+			// the deduped blobs of the repo, empty files on some storages, are linked again without new usage
+			blobPath := imgStore.BlobPath("other", digest)
+			So(os.Remove(blobPath), ShouldBeNil)
+			So(os.WriteFile(blobPath, []byte{}, 0o600), ShouldBeNil)
+
+			checked := make(chan error, 1)
+
+			go func() {
+				_, _, err := imgStore.CheckBlob("other", digest)
+				checked <- err
+			}()
+
+			select {
+			case err = <-checked:
+				So(err, ShouldBeNil)
+			case <-time.After(10 * time.Second):
+				So("CheckBlob didn't return", ShouldBeEmpty)
+			}
+
+			repoUsage, _ = enforcer.Usage("other")
+			So(repoUsage, ShouldEqual, 600)
+		})
+
+		Convey("Blobs over the max size of the storage", func() {
+			conf.MaxSize = totalUsage + 1000
+
+			blob, digest := newBlob(600)
+
+			_, _, err := imgStore.FullBlobUpload("other", bytes.NewReader(blob), digest)
+			So(err, ShouldBeNil)
+
+			blob, digest = newBlob(600)
+
+			_, _, err = imgStore.FullBlobUpload("other", bytes.NewReader(blob), digest)
+			So(errors.Is(err, zerr.ErrQuotaExceeded), ShouldBeTrue)
+
+			// the usage doesn't go below 0
+			enforcer.UpdateUsage("other", -10000)
+
+			repoUsage, newTotalUsage := enforcer.Usage("other")
+			So(repoUsage, ShouldEqual, 0)
+			So(newTotalUsage, ShouldEqual, totalUsage)
+		})
+	})
+}
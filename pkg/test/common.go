@@ -17,6 +17,7 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
@@ -1522,6 +1523,115 @@ func GetIndexBlobWithManifests(manifestDigests []godigest.Digest) ([]byte, error
 }
 
 func MockOIDCRun() (*mockoidc.MockOIDC, error) {
+	return mockOIDCRun()
+}
+
+// MockOIDCRunWithDeviceFlow starts a mock openID provider which also supports the device authorization flow,
+// the device is authorized for the default mock user on the second poll for the token.
+func MockOIDCRunWithDeviceFlow() (*mockoidc.MockOIDC, error) {
+	var mockServer *mockoidc.MockOIDC
+
+	var lock sync.Mutex
+
+	polls := map[string]int{}
+
+	deviceMiddleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, req *http.Request) {
+			switch {
+			case req.URL.Path == mockoidc.DiscoveryEndpoint:
+				recorder := httptest.NewRecorder()
+				next.ServeHTTP(recorder, req)
+
+				discovery := map[string]interface{}{}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &discovery); err != nil {
+					http.Error(response, err.Error(), http.StatusInternalServerError)
+
+					return
+				}
+
+				// the device codes are handled by POST requests on the authorization endpoint
+				discovery["device_authorization_endpoint"] = mockServer.AuthorizationEndpoint()
+
+				response.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(response).Encode(discovery)
+			case req.URL.Path == mockoidc.AuthorizationEndpoint && req.Method == http.MethodPost:
+				if err := req.ParseForm(); err != nil || req.Form.Get("client_id") != mockServer.ClientID {
+					http.Error(response, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+
+					return
+				}
+
+				deviceCode := fmt.Sprintf("device-%d", time.Now().UnixNano())
+
+				response.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(response).Encode(map[string]interface{}{
+					"device_code":      deviceCode,
+					"user_code":        "ZOT-1234",
+					"verification_uri": mockServer.Issuer() + "/device",
+					"expires_in":       60, //nolint: gomnd
+					"interval":         1,
+				})
+			case req.URL.Path == mockoidc.TokenEndpoint:
+				if err := req.ParseForm(); err != nil {
+					http.Error(response, err.Error(), http.StatusBadRequest)
+
+					return
+				}
+
+				if req.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" {
+					next.ServeHTTP(response, req)
+
+					return
+				}
+
+				deviceCode := req.Form.Get("device_code")
+
+				lock.Lock()
+				polls[deviceCode]++
+				poll := polls[deviceCode]
+				lock.Unlock()
+
+				if !strings.HasPrefix(deviceCode, "device-") {
+					response.Header().Set("Content-Type", "application/json")
+					response.WriteHeader(http.StatusBadRequest)
+					_, _ = response.Write([]byte(`{"error":"invalid_grant"}`))
+
+					return
+				}
+
+				if poll == 1 {
+					response.Header().Set("Content-Type", "application/json")
+					response.WriteHeader(http.StatusBadRequest)
+					_, _ = response.Write([]byte(`{"error":"authorization_pending"}`))
+
+					return
+				}
+
+				// the authorized device is exchanged like an authorization code
+				session, err := mockServer.SessionStore.NewSession("openid email groups", "",
+					mockServer.UserQueue.Pop(), "", "")
+				if err != nil {
+					http.Error(response, err.Error(), http.StatusInternalServerError)
+
+					return
+				}
+
+				req.Form.Set("grant_type", "authorization_code")
+				req.Form.Set("code", session.SessionID)
+
+				next.ServeHTTP(response, req)
+			default:
+				next.ServeHTTP(response, req)
+			}
+		})
+	}
+
+	mockServer, err := mockOIDCRun(deviceMiddleware)
+
+	return mockServer, err
+}
+
+func mockOIDCRun(middlewares ...func(http.Handler) http.Handler) (*mockoidc.MockOIDC, error) {
 	// Create a fresh RSA Private Key for token signing
 	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048) //nolint: gomnd
 
@@ -1550,6 +1660,12 @@ func MockOIDCRun() (*mockoidc.MockOIDC, error) {
 	if err != nil {
 		return mockServer, err
 	}
+
+	for _, middleware := range middlewares {
+		if err := mockServer.AddMiddleware(middleware); err != nil {
+			return mockServer, err
+		}
+	}
 	// tlsConfig can be nil if you want HTTP
 	return mockServer, mockServer.Start(listener, nil)
 }
@@ -1574,7 +1690,7 @@ func CustomRedirectPolicy(noOfRedirect int) resty.RedirectPolicy {
 }
 
 func GetDefaultImageStore(rootDir string, log zLog.Logger) stypes.ImageStore {
-	return local.NewImageStore(rootDir, false, false, time.Hour, time.Hour, false, false, log,
+	return local.NewImageStore(rootDir, false, false, time.Hour, time.Hour, false, false, false, log,
 		monitoring.NewMetricsServer(false, log),
 		mocks.MockedLint{
 			LintFn: func(repo string, manifestDigest godigest.Digest, imageStore stypes.ImageStore) (bool, error) {
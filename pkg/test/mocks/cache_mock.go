@@ -18,7 +18,17 @@ type CacheMock struct {
 	// Delete a blob from the cachedb.
 	DeleteBlobFn func(digest godigest.Digest, path string) error
 
+	// Retrieves all the paths of the blob matching provided digest.
+	GetAllBlobsFn func(digest godigest.Digest) ([]string, error)
+
+	// Retrieves the digests of all the blobs in cachedb.
+	GetAllDigestsFn func() ([]godigest.Digest, error)
+
 	UsesRelativePathsFn func() bool
+
+	PingFn func() error
+
+	CloseFn func() error
 }
 
 func (cacheMock CacheMock) UsesRelativePaths() bool {
@@ -68,3 +78,35 @@ func (cacheMock CacheMock) DeleteBlob(digest godigest.Digest, path string) error
 
 	return nil
 }
+
+func (cacheMock CacheMock) GetAllBlobs(digest godigest.Digest) ([]string, error) {
+	if cacheMock.GetAllBlobsFn != nil {
+		return cacheMock.GetAllBlobsFn(digest)
+	}
+
+	return []string{}, nil
+}
+
+func (cacheMock CacheMock) GetAllDigests() ([]godigest.Digest, error) {
+	if cacheMock.GetAllDigestsFn != nil {
+		return cacheMock.GetAllDigestsFn()
+	}
+
+	return []godigest.Digest{}, nil
+}
+
+func (cacheMock CacheMock) Ping() error {
+	if cacheMock.PingFn != nil {
+		return cacheMock.PingFn()
+	}
+
+	return nil
+}
+
+func (cacheMock CacheMock) Close() error {
+	if cacheMock.CloseFn != nil {
+		return cacheMock.CloseFn()
+	}
+
+	return nil
+}
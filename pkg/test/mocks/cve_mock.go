@@ -8,14 +8,19 @@ import (
 type CveInfoMock struct {
 	GetImageListForCVEFn       func(repo, cveID string) ([]cvemodel.TagInfo, error)
 	GetImageListWithCVEFixedFn func(repo, cveID string) ([]cvemodel.TagInfo, error)
-	GetCVEListForImageFn       func(repo string, reference string, searchedCVE string, pageInput cvemodel.PageInput,
+	GetCVEListForImageFn       func(repo string, reference string, searchedCVE string, fixStatus cvemodel.FixStatus,
+		pageInput cvemodel.PageInput,
 	) ([]cvemodel.CVE, common.PageInfo, error)
 	GetCVESummaryForImageFn func(repo string, reference string,
 	) (cvemodel.ImageCVESummary, error)
 	GetCVESummaryForImageMediaFn func(repo string, digest, mediaType string,
 	) (cvemodel.ImageCVESummary, error)
-	CompareSeveritiesFn func(severity1, severity2 string) int
-	UpdateDBFn          func() error
+	GetLicensesForImageFn    func(repo, reference string) ([]cvemodel.License, error)
+	GetImageListForLicenseFn func(repo string, licenses []string) ([]cvemodel.TagInfo, error)
+	ScanImageLicensesFn      func(repo, reference string) error
+	RescanImageFn            func(repo, reference string) error
+	CompareSeveritiesFn      func(severity1, severity2 string) int
+	UpdateDBFn               func() error
 }
 
 func (cveInfo CveInfoMock) GetImageListForCVE(repo, cveID string) ([]cvemodel.TagInfo, error) {
@@ -35,14 +40,14 @@ func (cveInfo CveInfoMock) GetImageListWithCVEFixed(repo, cveID string) ([]cvemo
 }
 
 func (cveInfo CveInfoMock) GetCVEListForImage(repo string, reference string,
-	searchedCVE string, pageInput cvemodel.PageInput,
+	searchedCVE string, fixStatus cvemodel.FixStatus, pageInput cvemodel.PageInput,
 ) (
 	[]cvemodel.CVE,
 	common.PageInfo,
 	error,
 ) {
 	if cveInfo.GetCVEListForImageFn != nil {
-		return cveInfo.GetCVEListForImageFn(repo, reference, searchedCVE, pageInput)
+		return cveInfo.GetCVEListForImageFn(repo, reference, searchedCVE, fixStatus, pageInput)
 	}
 
 	return []cvemodel.CVE{}, common.PageInfo{}, nil
@@ -66,6 +71,38 @@ func (cveInfo CveInfoMock) GetCVESummaryForImageMedia(repo, digest, mediaType st
 	return cvemodel.ImageCVESummary{}, nil
 }
 
+func (cveInfo CveInfoMock) GetLicensesForImage(repo, reference string) ([]cvemodel.License, error) {
+	if cveInfo.GetLicensesForImageFn != nil {
+		return cveInfo.GetLicensesForImageFn(repo, reference)
+	}
+
+	return []cvemodel.License{}, nil
+}
+
+func (cveInfo CveInfoMock) GetImageListForLicense(repo string, licenses []string) ([]cvemodel.TagInfo, error) {
+	if cveInfo.GetImageListForLicenseFn != nil {
+		return cveInfo.GetImageListForLicenseFn(repo, licenses)
+	}
+
+	return []cvemodel.TagInfo{}, nil
+}
+
+func (cveInfo CveInfoMock) ScanImageLicenses(repo, reference string) error {
+	if cveInfo.ScanImageLicensesFn != nil {
+		return cveInfo.ScanImageLicensesFn(repo, reference)
+	}
+
+	return nil
+}
+
+func (cveInfo CveInfoMock) RescanImage(repo, reference string) error {
+	if cveInfo.RescanImageFn != nil {
+		return cveInfo.RescanImageFn(repo, reference)
+	}
+
+	return nil
+}
+
 func (cveInfo CveInfoMock) CompareSeverities(severity1, severity2 string) int {
 	if cveInfo.CompareSeveritiesFn != nil {
 		return cveInfo.CompareSeveritiesFn(severity1, severity2)
@@ -86,6 +123,9 @@ type CveScannerMock struct {
 	IsImageFormatScannableFn func(repo string, reference string) (bool, error)
 	IsImageMediaScannableFn  func(repo string, digest, mediaType string) (bool, error)
 	ScanImageFn              func(image string) (map[string]cvemodel.CVE, error)
+	RescanImageFn            func(image string) (map[string]cvemodel.CVE, error)
+	ScanImageLicensesFn      func(image string) ([]cvemodel.License, error)
+	GetCachedImageLicensesFn func(image string) ([]cvemodel.License, error)
 	CompareSeveritiesFn      func(severity1, severity2 string) int
 	UpdateDBFn               func() error
 }
@@ -114,6 +154,30 @@ func (scanner CveScannerMock) ScanImage(image string) (map[string]cvemodel.CVE,
 	return map[string]cvemodel.CVE{}, nil
 }
 
+func (scanner CveScannerMock) RescanImage(image string) (map[string]cvemodel.CVE, error) {
+	if scanner.RescanImageFn != nil {
+		return scanner.RescanImageFn(image)
+	}
+
+	return map[string]cvemodel.CVE{}, nil
+}
+
+func (scanner CveScannerMock) ScanImageLicenses(image string) ([]cvemodel.License, error) {
+	if scanner.ScanImageLicensesFn != nil {
+		return scanner.ScanImageLicensesFn(image)
+	}
+
+	return []cvemodel.License{}, nil
+}
+
+func (scanner CveScannerMock) GetCachedImageLicenses(image string) ([]cvemodel.License, error) {
+	if scanner.GetCachedImageLicensesFn != nil {
+		return scanner.GetCachedImageLicensesFn(image)
+	}
+
+	return []cvemodel.License{}, nil
+}
+
 func (scanner CveScannerMock) CompareSeverities(severity1, severity2 string) int {
 	if scanner.CompareSeveritiesFn != nil {
 		return scanner.CompareSeveritiesFn(severity1, severity2)
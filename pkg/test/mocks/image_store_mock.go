@@ -9,11 +9,13 @@ import (
 	artifactspec "github.com/oras-project/artifacts-spec/specs-go/v1"
 
 	"zotregistry.io/zot/pkg/scheduler"
+	storageTypes "zotregistry.io/zot/pkg/storage/types"
 )
 
 type MockedImageStore struct {
 	DirExistsFn         func(d string) bool
 	RootDirFn           func() string
+	IsReadOnlyFn        func() bool
 	InitRepoFn          func(name string) error
 	ValidateRepoFn      func(name string) (bool, error)
 	GetRepositoriesFn   func() ([]string, error)
@@ -33,6 +35,7 @@ type MockedImageStore struct {
 	FullBlobUploadFn       func(repo string, body io.Reader, digest godigest.Digest) (string, int64, error)
 	DedupeBlobFn           func(src string, dstDigest godigest.Digest, dst string) error
 	DeleteBlobUploadFn     func(repo string, uuid string) error
+	ExpireBlobUploadsFn    func(repo string, ttl time.Duration) (int, error)
 	BlobPathFn             func(repo string, digest godigest.Digest) string
 	CheckBlobFn            func(repo string, digest godigest.Digest) (bool, int64, error)
 	StatBlobFn             func(repo string, digest godigest.Digest) (bool, int64, time.Time, error)
@@ -40,6 +43,7 @@ type MockedImageStore struct {
 	) (io.ReadCloser, int64, int64, error)
 	GetBlobFn          func(repo string, digest godigest.Digest, mediaType string) (io.ReadCloser, int64, error)
 	DeleteBlobFn       func(repo string, digest godigest.Digest) error
+	DeleteRepoFn       func(repo string) error
 	GetIndexContentFn  func(repo string) ([]byte, error)
 	GetBlobContentFn   func(repo string, digest godigest.Digest) ([]byte, error)
 	GetReferrersFn     func(repo string, digest godigest.Digest, artifactTypes []string) (ispec.Index, error)
@@ -47,11 +51,15 @@ type MockedImageStore struct {
 	) ([]artifactspec.Descriptor, error)
 	URLForPathFn                 func(path string) (string, error)
 	RunGCRepoFn                  func(repo string) error
+	RunGCRepoReportFn            func(repo string, dryRun bool) (storageTypes.GCReport, error)
 	RunGCPeriodicallyFn          func(interval time.Duration, sch *scheduler.Scheduler)
 	RunDedupeBlobsFn             func(interval time.Duration, sch *scheduler.Scheduler)
 	RunDedupeForDigestFn         func(digest godigest.Digest, dedupe bool, duplicateBlobs []string) error
+	RunGCDedupedBlobsFn          func() error
 	GetNextDigestWithBlobPathsFn func(lastDigests []godigest.Digest) (godigest.Digest, []string, error)
-	GetAllBlobsFn                func(repo string) ([]string, error)
+	GetAllBlobsFn                func(repo string) ([]godigest.Digest, error)
+	PingFn                       func() error
+	CloseFn                      func() error
 }
 
 func (is MockedImageStore) Lock(t *time.Time) {
@@ -66,6 +74,34 @@ func (is MockedImageStore) RUnlock(t *time.Time) {
 func (is MockedImageStore) RLock(t *time.Time) {
 }
 
+func (is MockedImageStore) LockRepo(repo string, t *time.Time) error {
+	return nil
+}
+
+func (is MockedImageStore) UnlockRepo(repo string, t *time.Time) {
+}
+
+func (is MockedImageStore) RUnlockRepo(repo string, t *time.Time) {
+}
+
+func (is MockedImageStore) SetRepoLocker(locker storageTypes.RepoLocker) {
+}
+
+func (is MockedImageStore) SetQuotaEnforcer(enforcer storageTypes.QuotaEnforcer) {
+}
+
+func (is MockedImageStore) SetTagPolicy(policy storageTypes.TagPolicy) {
+}
+
+func (is MockedImageStore) SetReferenceCounter(counter storageTypes.ReferenceCounter) {
+}
+
+func (is MockedImageStore) SetChunkDedupe(minBlobSize int64, averageChunkSize int) {
+}
+
+func (is MockedImageStore) RLockRepo(repo string, t *time.Time) {
+}
+
 func (is MockedImageStore) DirExists(d string) bool {
 	if is.DirExistsFn != nil {
 		return is.DirExistsFn(d)
@@ -82,6 +118,14 @@ func (is MockedImageStore) RootDir() string {
 	return ""
 }
 
+func (is MockedImageStore) IsReadOnly() bool {
+	if is.IsReadOnlyFn != nil {
+		return is.IsReadOnlyFn()
+	}
+
+	return false
+}
+
 func (is MockedImageStore) InitRepo(name string) error {
 	if is.InitRepoFn != nil {
 		return is.InitRepoFn(name)
@@ -143,12 +187,12 @@ func (is MockedImageStore) GetImageTags(name string) ([]string, error) {
 	return []string{}, nil
 }
 
-func (is MockedImageStore) GetAllBlobs(repo string) ([]string, error) {
+func (is MockedImageStore) GetAllBlobs(repo string) ([]godigest.Digest, error) {
 	if is.GetAllBlobsFn != nil {
 		return is.GetAllBlobsFn(repo)
 	}
 
-	return []string{}, nil
+	return []godigest.Digest{}, nil
 }
 
 func (is MockedImageStore) DeleteImageManifest(name string, reference string, detectCollision bool) error {
@@ -245,6 +289,14 @@ func (is MockedImageStore) DeleteBlob(repo string, digest godigest.Digest) error
 	return nil
 }
 
+func (is MockedImageStore) DeleteRepo(repo string) error {
+	if is.DeleteRepoFn != nil {
+		return is.DeleteRepoFn(repo)
+	}
+
+	return nil
+}
+
 func (is MockedImageStore) BlobPath(repo string, digest godigest.Digest) string {
 	if is.BlobPathFn != nil {
 		return is.BlobPathFn(repo, digest)
@@ -295,6 +347,14 @@ func (is MockedImageStore) DeleteBlobUpload(repo string, uuid string) error {
 	return nil
 }
 
+func (is MockedImageStore) ExpireBlobUploads(repo string, ttl time.Duration) (int, error) {
+	if is.ExpireBlobUploadsFn != nil {
+		return is.ExpireBlobUploadsFn(repo, ttl)
+	}
+
+	return 0, nil
+}
+
 func (is MockedImageStore) GetIndexContent(repo string) ([]byte, error) {
 	if is.GetIndexContentFn != nil {
 		return is.GetIndexContentFn(repo)
@@ -350,6 +410,15 @@ func (is MockedImageStore) RunGCRepo(repo string) error {
 	return nil
 }
 
+func (is MockedImageStore) RunGCRepoReport(repo string, dryRun bool) (storageTypes.GCReport, error) {
+	if is.RunGCRepoReportFn != nil {
+		return is.RunGCRepoReportFn(repo, dryRun)
+	}
+
+	return storageTypes.GCReport{Repo: repo, Manifests: []storageTypes.GCReportItem{},
+		Blobs: []storageTypes.GCReportItem{}}, nil
+}
+
 func (is MockedImageStore) RunGCPeriodically(interval time.Duration, sch *scheduler.Scheduler) {
 	if is.RunGCPeriodicallyFn != nil {
 		is.RunGCPeriodicallyFn(interval, sch)
@@ -362,6 +431,14 @@ func (is MockedImageStore) RunDedupeBlobs(interval time.Duration, sch *scheduler
 	}
 }
 
+func (is MockedImageStore) RunGCDedupedBlobs() error {
+	if is.RunGCDedupedBlobsFn != nil {
+		return is.RunGCDedupedBlobsFn()
+	}
+
+	return nil
+}
+
 func (is MockedImageStore) RunDedupeForDigest(digest godigest.Digest, dedupe bool, duplicateBlobs []string) error {
 	if is.RunDedupeForDigestFn != nil {
 		return is.RunDedupeForDigestFn(digest, dedupe, duplicateBlobs)
@@ -378,3 +455,19 @@ func (is MockedImageStore) GetNextDigestWithBlobPaths(lastDigests []godigest.Dig
 
 	return "", []string{}, nil
 }
+
+func (is MockedImageStore) Ping() error {
+	if is.PingFn != nil {
+		return is.PingFn()
+	}
+
+	return nil
+}
+
+func (is MockedImageStore) Close() error {
+	if is.CloseFn != nil {
+		return is.CloseFn()
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"context"
+	"io"
+	"time"
 
 	godigest "github.com/opencontainers/go-digest"
 
@@ -17,12 +19,16 @@ type MetaDBMock struct {
 
 	GetRepoStarsFn func(repo string) (int, error)
 
+	SetRepoReadmeFn func(repo string, readme *mTypes.RepoReadme) error
+
 	SetRepoLogoFn func(repo string, logoPath string) error
 
 	SetRepoReferenceFn func(repo string, Reference string, manifestDigest godigest.Digest, mediaType string) error
 
 	DeleteRepoTagFn func(repo string, tag string) error
 
+	DeleteRepoMetaFn func(repo string) error
+
 	GetRepoMetaFn func(repo string) (mTypes.RepoMetadata, error)
 
 	GetUserRepoMetaFn func(ctx context.Context, repo string) (mTypes.RepoMetadata, error)
@@ -55,6 +61,24 @@ type MetaDBMock struct {
 
 	IncrementImageDownloadsFn func(repo string, reference string) error
 
+	RecordDownloadFn func(record mTypes.DownloadRecord) error
+
+	GetDownloadStatsFn func(ctx context.Context, repo string, from, to time.Time) ([]mTypes.DailyDownloads, error)
+
+	DeleteDownloadStatsFn func(before time.Time) error
+
+	RollupDownloadStatsFn func(before time.Time) error
+
+	RecordUserActivityFn func(activity mTypes.UserActivity) error
+
+	GetUserActivityFn func(ctx context.Context, user string, from, to time.Time) ([]mTypes.UserActivity, error)
+
+	DeleteUserActivityFn func(before time.Time) error
+
+	BackupFn func(ctx context.Context, writer io.Writer) error
+
+	RestoreFn func(ctx context.Context, reader io.Reader) error
+
 	UpdateSignaturesValidityFn func(repo string, manifestDigest godigest.Digest) error
 
 	AddManifestSignatureFn func(repo string, signedManifestDigest godigest.Digest, sm mTypes.SignatureMetadata) error
@@ -75,6 +99,11 @@ type MetaDBMock struct {
 	FilterTagsFn func(ctx context.Context, filterFunc mTypes.FilterFunc) (
 		[]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error)
 
+	FilterTagsByManifestsFn func(ctx context.Context, manifestDigests []godigest.Digest, filterFunc mTypes.FilterFunc) (
+		[]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error)
+
+	GetManifestsWithLayerFn func(layerDigest godigest.Digest) ([]godigest.Digest, error)
+
 	GetStarredReposFn func(ctx context.Context) ([]string, error)
 
 	GetBookmarkedReposFn func(ctx context.Context) ([]string, error)
@@ -83,6 +112,12 @@ type MetaDBMock struct {
 
 	ToggleBookmarkRepoFn func(ctx context.Context, repo string) (mTypes.ToggleState, error)
 
+	SetBookmarkListFn func(ctx context.Context, list mTypes.BookmarkList) error
+
+	DeleteBookmarkListFn func(ctx context.Context, name string) error
+
+	GetBookmarkListsFn func(ctx context.Context) ([]mTypes.BookmarkList, error)
+
 	GetUserDataFn func(ctx context.Context) (mTypes.UserData, error)
 
 	SetUserDataFn func(ctx context.Context, userProfile mTypes.UserData) error
@@ -158,6 +193,14 @@ func (sdm MetaDBMock) GetRepoStars(repo string) (int, error) {
 	return 0, nil
 }
 
+func (sdm MetaDBMock) SetRepoReadme(repo string, readme *mTypes.RepoReadme) error {
+	if sdm.SetRepoReadmeFn != nil {
+		return sdm.SetRepoReadmeFn(repo, readme)
+	}
+
+	return nil
+}
+
 func (sdm MetaDBMock) SetRepoReference(repo string, reference string, manifestDigest godigest.Digest,
 	mediaType string,
 ) error {
@@ -176,6 +219,14 @@ func (sdm MetaDBMock) DeleteRepoTag(repo string, tag string) error {
 	return nil
 }
 
+func (sdm MetaDBMock) DeleteRepoMeta(repo string) error {
+	if sdm.DeleteRepoMetaFn != nil {
+		return sdm.DeleteRepoMetaFn(repo)
+	}
+
+	return nil
+}
+
 func (sdm MetaDBMock) GetRepoMeta(repo string) (mTypes.RepoMetadata, error) {
 	if sdm.GetRepoMetaFn != nil {
 		return sdm.GetRepoMetaFn(repo)
@@ -249,6 +300,80 @@ func (sdm MetaDBMock) IncrementImageDownloads(repo string, reference string) err
 	return nil
 }
 
+func (sdm MetaDBMock) RecordDownload(record mTypes.DownloadRecord) error {
+	if sdm.RecordDownloadFn != nil {
+		return sdm.RecordDownloadFn(record)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) GetDownloadStats(ctx context.Context, repo string, from, to time.Time,
+) ([]mTypes.DailyDownloads, error) {
+	if sdm.GetDownloadStatsFn != nil {
+		return sdm.GetDownloadStatsFn(ctx, repo, from, to)
+	}
+
+	return []mTypes.DailyDownloads{}, nil
+}
+
+func (sdm MetaDBMock) DeleteDownloadStats(before time.Time) error {
+	if sdm.DeleteDownloadStatsFn != nil {
+		return sdm.DeleteDownloadStatsFn(before)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) RollupDownloadStats(before time.Time) error {
+	if sdm.RollupDownloadStatsFn != nil {
+		return sdm.RollupDownloadStatsFn(before)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) RecordUserActivity(activity mTypes.UserActivity) error {
+	if sdm.RecordUserActivityFn != nil {
+		return sdm.RecordUserActivityFn(activity)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) GetUserActivity(ctx context.Context, user string, from, to time.Time,
+) ([]mTypes.UserActivity, error) {
+	if sdm.GetUserActivityFn != nil {
+		return sdm.GetUserActivityFn(ctx, user, from, to)
+	}
+
+	return []mTypes.UserActivity{}, nil
+}
+
+func (sdm MetaDBMock) DeleteUserActivity(before time.Time) error {
+	if sdm.DeleteUserActivityFn != nil {
+		return sdm.DeleteUserActivityFn(before)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) Backup(ctx context.Context, writer io.Writer) error {
+	if sdm.BackupFn != nil {
+		return sdm.BackupFn(ctx, writer)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) Restore(ctx context.Context, reader io.Reader) error {
+	if sdm.RestoreFn != nil {
+		return sdm.RestoreFn(ctx, reader)
+	}
+
+	return nil
+}
+
 func (sdm MetaDBMock) UpdateSignaturesValidity(repo string, manifestDigest godigest.Digest) error {
 	if sdm.UpdateSignaturesValidityFn != nil {
 		return sdm.UpdateSignaturesValidityFn(repo, manifestDigest)
@@ -317,6 +442,25 @@ func (sdm MetaDBMock) FilterTags(ctx context.Context, filterFunc mTypes.FilterFu
 		map[string]mTypes.IndexData{}, nil
 }
 
+// FilterTagsByManifests defaults to FilterTags, ignoring the manifests.
+func (sdm MetaDBMock) FilterTagsByManifests(ctx context.Context, manifestDigests []godigest.Digest,
+	filterFunc mTypes.FilterFunc,
+) ([]mTypes.RepoMetadata, map[string]mTypes.ManifestMetadata, map[string]mTypes.IndexData, error) {
+	if sdm.FilterTagsByManifestsFn != nil {
+		return sdm.FilterTagsByManifestsFn(ctx, manifestDigests, filterFunc)
+	}
+
+	return sdm.FilterTags(ctx, filterFunc)
+}
+
+func (sdm MetaDBMock) GetManifestsWithLayer(layerDigest godigest.Digest) ([]godigest.Digest, error) {
+	if sdm.GetManifestsWithLayerFn != nil {
+		return sdm.GetManifestsWithLayerFn(layerDigest)
+	}
+
+	return []godigest.Digest{}, nil
+}
+
 func (sdm MetaDBMock) SetIndexData(digest godigest.Digest, indexData mTypes.IndexData) error {
 	if sdm.SetIndexDataFn != nil {
 		return sdm.SetIndexDataFn(digest, indexData)
@@ -385,6 +529,30 @@ func (sdm MetaDBMock) GetBookmarkedRepos(ctx context.Context) ([]string, error)
 	return []string{}, nil
 }
 
+func (sdm MetaDBMock) SetBookmarkList(ctx context.Context, list mTypes.BookmarkList) error {
+	if sdm.SetBookmarkListFn != nil {
+		return sdm.SetBookmarkListFn(ctx, list)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) DeleteBookmarkList(ctx context.Context, name string) error {
+	if sdm.DeleteBookmarkListFn != nil {
+		return sdm.DeleteBookmarkListFn(ctx, name)
+	}
+
+	return nil
+}
+
+func (sdm MetaDBMock) GetBookmarkLists(ctx context.Context) ([]mTypes.BookmarkList, error) {
+	if sdm.GetBookmarkListsFn != nil {
+		return sdm.GetBookmarkListsFn(ctx)
+	}
+
+	return []mTypes.BookmarkList{}, nil
+}
+
 func (sdm MetaDBMock) ToggleStarRepo(ctx context.Context, repo string) (mTypes.ToggleState, error) {
 	if sdm.ToggleStarRepoFn != nil {
 		return sdm.ToggleStarRepoFn(ctx, repo)